@@ -67,6 +67,11 @@ type ConnectionArgs struct {
 
 	// Bearer token for authenticating as a bearer identity.
 	BearerToken string
+
+	// Send the requests over HTTP/3 (QUIC) when the server advertises an HTTP/3 listener, falling back to HTTPS
+	// if it can't be reached. The events are then streamed over HTTP/3 while the other websockets, such as those
+	// of exec and console, always use HTTPS. Requests sent over HTTP/3 don't go through the TransportWrapper.
+	HTTP3 bool
 }
 
 // ConnectLXD lets you connect to a remote LXD daemon over HTTPs.
@@ -456,6 +461,13 @@ func httpsLXD(ctx context.Context, requestURL string, args *ConnectionArgs) (Ins
 		httpClient.Jar = args.CookieJar
 	}
 
+	if args.HTTP3 {
+		httpClient.Transport, err = newHTTP3Transport(httpClient.Transport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	server.http = httpClient
 	if args.AuthType == api.AuthenticationMethodOIDC {
 		server.setupOIDCClient(args.OIDCTokens)
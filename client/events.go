@@ -1,9 +1,11 @@
 package lxd
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"slices"
 	"sync"
 	"time"
@@ -100,7 +102,7 @@ type eventListenerManager struct {
 	ctx context.Context
 
 	// eventConns contains event listener connections associated to a project name (or empty for all projects).
-	eventConns map[string]*websocket.Conn
+	eventConns map[string]eventConn
 
 	// eventConnsLock controls write access to the eventConns.
 	eventConnsLock sync.Mutex
@@ -114,13 +116,13 @@ type eventListenerManager struct {
 func newEventListenerManager(ctx context.Context) *eventListenerManager {
 	return &eventListenerManager{
 		ctx:            ctx,
-		eventConns:     make(map[string]*websocket.Conn),
+		eventConns:     make(map[string]eventConn),
 		eventListeners: make(map[string][]*EventListener),
 	}
 }
 
 // getEvents connects to the LXD monitoring interface.
-func (m *eventListenerManager) getEvents(ctxConnected context.Context, websocketHook func() (*websocket.Conn, error), project string) (*EventListener, error) {
+func (m *eventListenerManager) getEvents(ctxConnected context.Context, connHook func() (eventConn, error), project string) (*EventListener, error) {
 	// If a specific project is not provided, listen to all projects.
 	allProjects := project == ""
 
@@ -173,14 +175,14 @@ func (m *eventListenerManager) getEvents(ctxConnected context.Context, websocket
 		return &listener, nil
 	}
 
-	// Connect to the websocket.
-	wsConn, err := websocketHook()
+	// Connect to the event API.
+	conn, err := connHook()
 	if err != nil {
 		return nil, err
 	}
 
 	m.eventConnsLock.Lock()
-	m.eventConns[listener.projectName] = wsConn // Save for others to use.
+	m.eventConns[listener.projectName] = conn // Save for others to use.
 	m.eventConnsLock.Unlock()
 
 	// Initialize the event listener list if we were able to connect to the events websocket.
@@ -221,7 +223,7 @@ func (m *eventListenerManager) getEvents(ctxConnected context.Context, websocket
 	// Spawn the listener
 	go func() {
 		for {
-			_, data, err := wsConn.ReadMessage()
+			data, err := conn.ReadMessage()
 			if err != nil {
 				// Prevent anything else from interacting with the listeners
 				m.eventListenersLock.Lock()
@@ -281,15 +283,20 @@ func (m *eventListenerManager) SendEvent(event api.Event) error {
 	m.eventConnsLock.Lock()
 	defer m.eventConnsLock.Unlock()
 
-	// Find an available event listener connection.
+	// Find an available event listener connection, preferring the websockets as the event streams can't send.
 	// It doesn't matter which project the event listener connection is using, as this only affects which
 	// events are received from the server, not which events we can send to it.
-	var eventConn *websocket.Conn
-	for _, eventConn = range m.eventConns {
-		break
+	var conn eventConn
+	for _, c := range m.eventConns {
+		conn = c
+
+		_, isStream := c.(*streamEventConn)
+		if !isStream {
+			break
+		}
 	}
 
-	if eventConn == nil {
+	if conn == nil {
 		return errors.New("No available event listener connection")
 	}
 
@@ -298,6 +305,83 @@ func (m *eventListenerManager) SendEvent(event api.Event) error {
 		deadline = time.Now().Add(5 * time.Second)
 	}
 
-	_ = eventConn.SetWriteDeadline(deadline)
-	return eventConn.WriteJSON(event)
+	return conn.WriteEvent(event, deadline)
+}
+
+// eventConn is a connection to the event API.
+type eventConn interface {
+	// ReadMessage returns the next serialized event.
+	ReadMessage() ([]byte, error)
+
+	// WriteEvent sends an event to the server.
+	WriteEvent(event api.Event, deadline time.Time) error
+
+	Close() error
+}
+
+// websocketEventConn is an event API connection over a websocket.
+type websocketEventConn struct {
+	*websocket.Conn
+}
+
+// ReadMessage returns the next message of the websocket.
+func (c *websocketEventConn) ReadMessage() ([]byte, error) {
+	_, data, err := c.Conn.ReadMessage()
+	return data, err
+}
+
+// WriteEvent sends the event as a websocket message.
+func (c *websocketEventConn) WriteEvent(event api.Event, deadline time.Time) error {
+	_ = c.SetWriteDeadline(deadline)
+	return c.WriteJSON(event)
+}
+
+// streamEventConn is an event API connection over HTTP/3, where the server streams the events in the response
+// body as one JSON object per line as websockets aren't available.
+type streamEventConn struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	cancel context.CancelFunc
+}
+
+// newStreamEventConn returns an event API connection reading the events from the given response body.
+func newStreamEventConn(body io.ReadCloser, cancel context.CancelFunc) *streamEventConn {
+	return &streamEventConn{
+		body:   body,
+		reader: bufio.NewReader(body),
+		cancel: cancel,
+	}
+}
+
+// ReadMessage returns the next line of the stream, skipping the empty ones.
+func (c *streamEventConn) ReadMessage() ([]byte, error) {
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			line = line[:len(line)-1]
+		}
+
+		if len(line) > 0 && (err == nil || errors.Is(err, io.EOF)) {
+			return line, nil
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return nil, err
+		}
+	}
+}
+
+// WriteEvent fails as the event stream is read-only.
+func (c *streamEventConn) WriteEvent(event api.Event, deadline time.Time) error {
+	return errors.New("Sending events isn't supported over HTTP/3")
+}
+
+// Close stops the request and closes the stream.
+func (c *streamEventConn) Close() error {
+	c.cancel()
+	return c.body.Close()
 }
@@ -0,0 +1,167 @@
+package lxd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// errHTTP3Unavailable is returned when the HTTP/3 listener of the server can't be reached, in which case the
+// request wasn't sent.
+var errHTTP3Unavailable = errors.New("HTTP/3 listener unavailable")
+
+// http3Transport sends the requests over HTTP/3 (QUIC) once the server advertised its HTTP/3 listener in the
+// Alt-Svc header of an HTTPS response, and falls back to HTTPS if that listener can't be reached.
+//
+// Requests that upgrade the connection, such as websockets, always use HTTPS. The event API is instead read as a
+// stream over HTTP/3 when available, see eventsConnect.
+type http3Transport struct {
+	https     http.RoundTripper
+	transport *http.Transport
+	h3        *http3.Transport
+
+	// UDP port of the HTTP/3 listener advertised by the server, or 0 if none.
+	port atomic.Int64
+
+	// Whether HTTP/3 failed and shouldn't be tried again.
+	disabled atomic.Bool
+}
+
+// newHTTP3Transport returns a transport trying HTTP/3 before the given HTTPS transport.
+func newHTTP3Transport(https http.RoundTripper) (*http3Transport, error) {
+	var transport *http.Transport
+	switch t := https.(type) {
+	case *http.Transport:
+		transport = t
+	case HTTPTransporter:
+		transport = t.Transport()
+	default:
+		return nil, fmt.Errorf("Unexpected http.Transport type, %T", https)
+	}
+
+	t := &http3Transport{
+		https:     https,
+		transport: transport,
+	}
+
+	t.h3 = &http3.Transport{
+		TLSClientConfig: transport.TLSClientConfig,
+		QUICConfig: &quic.Config{
+			HandshakeIdleTimeout: 5 * time.Second,
+		},
+		Dial: t.dial,
+	}
+
+	return t, nil
+}
+
+// dial connects to the HTTP/3 listener advertised by the server at the given address.
+func (t *http3Transport) dial(ctx context.Context, addr string, tlsConfig *tls.Config, config *quic.Config) (*quic.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errHTTP3Unavailable, err)
+	}
+
+	addr = net.JoinHostPort(host, strconv.FormatInt(t.port.Load(), 10))
+
+	// Wait for the end of the handshake, so that a server not speaking HTTP/3 is detected before the request is sent.
+	conn, err := quic.DialAddr(ctx, addr, tlsConfig, config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errHTTP3Unavailable, err)
+	}
+
+	return conn, nil
+}
+
+// useHTTP3 returns whether the request can be sent over HTTP/3.
+func (t *http3Transport) useHTTP3(req *http.Request) bool {
+	if t.disabled.Load() || t.port.Load() == 0 {
+		return false
+	}
+
+	if req.URL.Scheme != "https" || req.Header.Get("Upgrade") != "" {
+		return false
+	}
+
+	// QUIC can't go through HTTP proxies.
+	if t.transport.Proxy != nil {
+		proxyURL, err := t.transport.Proxy(req)
+		if err != nil || proxyURL != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RoundTrip sends the request over HTTP/3 if possible, and over HTTPS otherwise.
+func (t *http3Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.useHTTP3(req) {
+		resp, err := t.h3.RoundTrip(req)
+		if !errors.Is(err, errHTTP3Unavailable) {
+			return resp, err
+		}
+
+		logger.Debug("Failed connecting over HTTP/3, falling back to HTTPS", logger.Ctx{"url": req.URL.String(), "err": err})
+		t.disabled.Store(true)
+	}
+
+	resp, err := t.https.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	port := http3AltSvcPort(resp.Header)
+	if port > 0 {
+		t.port.Store(int64(port))
+	}
+
+	return resp, nil
+}
+
+// Transport returns the underlying HTTPS transport.
+func (t *http3Transport) Transport() *http.Transport {
+	return t.transport
+}
+
+// http3AltSvcPort returns the UDP port of the HTTP/3 listener advertised in the Alt-Svc header, or 0 if there's
+// none. Only alternative services on the same host are considered.
+func http3AltSvcPort(header http.Header) int {
+	for _, value := range header.Values("Alt-Svc") {
+		for _, alternative := range strings.Split(value, ",") {
+			protocol, authority, found := strings.Cut(strings.TrimSpace(alternative), "=")
+			if !found || protocol != http3.NextProtoH3 {
+				continue
+			}
+
+			authority, _, _ = strings.Cut(authority, ";")
+			authority = strings.Trim(strings.TrimSpace(authority), `"`)
+
+			host, port, err := net.SplitHostPort(authority)
+			if err != nil || host != "" {
+				continue
+			}
+
+			portNumber, err := strconv.Atoi(port)
+			if err != nil || portNumber <= 0 || portNumber > 65535 {
+				continue
+			}
+
+			return portNumber
+		}
+	}
+
+	return 0
+}
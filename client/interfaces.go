@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -73,6 +74,8 @@ type InstanceServer interface {
 	ImageServer
 
 	// Server functions
+	ExecuteBatch(batch api.BatchPost) (result *api.Batch, err error)
+	GetHealth() (health *api.Health, err error)
 	GetMetadataConfiguration() (metadataConfiguration *api.MetadataConfiguration, err error)
 	GetMetrics() (metrics string, err error)
 	GetServer() (server *api.Server, ETag string, err error)
@@ -167,6 +170,7 @@ type InstanceServer interface {
 	GetInstancesFullAllProjectsWithFilter(instanceType api.InstanceType, filters []string) (instances []api.InstanceFull, err error)
 	GetInstance(name string) (instance *api.Instance, ETag string, err error)
 	GetInstanceFull(name string) (instance *api.InstanceFull, ETag string, err error)
+	GetInstancesAutostart() (autostart *api.InstancesAutostart, err error)
 	CreateInstance(instance api.InstancesPost) (op Operation, err error)
 	CreateInstanceFromImage(source ImageServer, image api.Image, req api.InstancesPost) (op RemoteOperation, err error)
 	CopyInstance(source InstanceServer, instance api.Instance, args *InstanceCopyArgs) (op RemoteOperation, err error)
@@ -179,6 +183,22 @@ type InstanceServer interface {
 	RebuildInstanceFromImage(source ImageServer, image api.Image, instanceName string, req api.InstanceRebuildPost) (op RemoteOperation, err error)
 	GetInstanceUEFIVars(name string) (instanceUEFI *api.InstanceUEFIVars, ETag string, err error)
 	UpdateInstanceUEFIVars(name string, instanceUEFI api.InstanceUEFIVars, ETag string) (err error)
+	UpdateInstanceUEFIVarsPartial(name string, instanceUEFI api.InstanceUEFIVars, restart bool) (err error)
+	ResetInstanceUEFIVars(name string, restart bool) (err error)
+	GetInstanceAttestation(name string, nonce []byte) (attestation *api.InstanceAttestation, err error)
+	CreateInstanceCheckpoint(name string, checkpoint api.InstanceCheckpointPost) (op Operation, err error)
+	GetInstanceCheckpoint(name string) (content io.ReadCloser, err error)
+	UpdateInstanceCheckpoint(name string, content io.Reader) (err error)
+	DeleteInstanceCheckpoint(name string) (err error)
+	RescueInstance(name string, rescue api.InstanceRescuePost) (op Operation, err error)
+	UnrescueInstance(name string) (op Operation, err error)
+	GetInstanceOCIFile(name string, tag string, writer io.Writer) (err error)
+	PushInstanceOCI(name string, req api.InstanceExportPost) (op Operation, err error)
+	SetupInstanceWindows(name string, setup api.InstanceWindowsSetupPost) (err error)
+	DetachInstanceWindowsSetup(name string) (err error)
+	GetInstanceTPMState(name string, device string) (content io.ReadCloser, err error)
+	UpdateInstanceTPMState(name string, device string, content io.Reader) (err error)
+	ResetInstanceTPMState(name string, device string) (err error)
 
 	ExecInstance(instanceName string, exec api.InstanceExecPost, args *InstanceExecArgs) (op Operation, err error)
 	ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (op Operation, err error)
@@ -190,9 +210,13 @@ type InstanceServer interface {
 	GetInstanceFile(instanceName string, path string) (content io.ReadCloser, resp *InstanceFileResponse, err error)
 	CreateInstanceFile(instanceName string, path string, args InstanceFileArgs) (err error)
 	DeleteInstanceFile(instanceName string, path string) (err error)
+	GetInstanceFileTar(instanceName string, path string, args *InstanceFileTarArgs) (content io.ReadCloser, err error)
+	CreateInstanceFileTar(instanceName string, path string, content io.Reader, args *InstanceFileTarArgs) (err error)
 
 	GetInstanceFileSFTPConn(instanceName string) (net.Conn, error)
 	GetInstanceFileSFTP(instanceName string) (*sftp.Client, error)
+	GetInstanceDiskFileSFTPConn(instanceName string, writable bool) (net.Conn, error)
+	GetInstanceDiskFileSFTP(instanceName string, writable bool) (*sftp.Client, error)
 
 	GetInstanceSnapshotNames(instanceName string) (names []string, err error)
 	GetInstanceSnapshots(instanceName string) (snapshots []api.InstanceSnapshot, err error)
@@ -214,6 +238,7 @@ type InstanceServer interface {
 	CreateInstanceFromBackup(args InstanceBackupArgs) (op Operation, err error)
 
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
+	GetInstanceStateHistory(name string, period time.Duration) (history *api.InstanceStateHistory, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
 
 	GetInstanceLogfiles(name string) (logfiles []string, err error)
@@ -231,6 +256,12 @@ type InstanceServer interface {
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsAllProjects() (listener *EventListener, err error)
+	GetEventsSince(since uint64) (listener *EventListener, err error)
+	GetEventsAllProjectsSince(since uint64) (listener *EventListener, err error)
+	GetEventsWithSelector(selector EventSelector) (listener *EventListener, err error)
+	GetEventsAllProjectsWithSelector(selector EventSelector) (listener *EventListener, err error)
+	GetEventsHistory(since time.Time, types []string) (events []api.Event, err error)
+	GetEventsHistoryAllProjects(since time.Time, types []string) (events []api.Event, err error)
 	SendEvent(event api.Event) error
 
 	// Image functions
@@ -239,6 +270,7 @@ type InstanceServer interface {
 	UpdateImage(fingerprint string, image api.ImagePut, ETag string) (err error)
 	DeleteImage(fingerprint string) (op Operation, err error)
 	RefreshImage(fingerprint string) (op Operation, err error)
+	GetImageLocations(fingerprint string) (locations *api.ImageLocations, err error)
 	CreateImageSecret(fingerprint string) (op Operation, err error)
 	CreateImageAlias(alias api.ImageAliasesPost) (err error)
 	UpdateImageAlias(name string, alias api.ImageAliasesEntryPut, ETag string) (err error)
@@ -323,6 +355,16 @@ type InstanceServer interface {
 	GetOperationWebsocket(uuid string, secret string) (conn *websocket.Conn, err error)
 	DeleteOperation(uuid string) (err error)
 
+	// Instance template functions
+	GetInstanceTemplateNames() (names []string, err error)
+	GetInstanceTemplates() (templates []api.InstanceTemplate, err error)
+	GetInstanceTemplate(name string) (template *api.InstanceTemplate, ETag string, err error)
+	CreateInstanceTemplate(template api.InstanceTemplatesPost) (err error)
+	UpdateInstanceTemplate(name string, template api.InstanceTemplatePut, ETag string) (err error)
+	RenameInstanceTemplate(name string, template api.InstanceTemplatePost) (err error)
+	DeleteInstanceTemplate(name string) (err error)
+	CreateInstanceFromTemplate(name string, req api.InstanceTemplateInstancesPost) (op Operation, err error)
+
 	// Profile functions
 	GetProfilesAllProjects() (profiles []api.Profile, err error)
 	GetProfileNames() (names []string, err error)
@@ -338,10 +380,16 @@ type InstanceServer interface {
 	GetProjects() (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
+	UpdateProjectState(name string, state api.ProjectStatePut) (op Operation, err error)
 	CreateProject(project api.ProjectsPost) (err error)
 	UpdateProject(name string, project api.ProjectPut, ETag string) (err error)
 	RenameProject(name string, project api.ProjectPost) (op Operation, err error)
 	DeleteProject(name string) (err error)
+	GetProjectExport(name string) (content io.ReadCloser, err error)
+	ImportProject(export api.ProjectExport) (err error)
+	GetProjectShares(name string) (shares []api.ProjectShare, ETag string, err error)
+	GetProjectReceivedShares(name string) (shares []api.ProjectShare, err error)
+	UpdateProjectShares(name string, shares api.ProjectSharesPut, ETag string) (err error)
 
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
@@ -351,6 +399,8 @@ type InstanceServer interface {
 	CreateStoragePool(pool api.StoragePoolsPost) (err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (err error)
 	DeleteStoragePool(name string) (err error)
+	ScanStoragePool(name string) (scan *api.StoragePoolScan, err error)
+	RecoverStoragePool(name string, req api.StoragePoolRecoverPost) (op Operation, err error)
 
 	// Storage bucket functions ("storage_buckets" API extension)
 	GetStoragePoolBucketNames(poolName string) ([]string, error)
@@ -423,8 +473,13 @@ type InstanceServer interface {
 	RenameClusterMember(name string, member api.ClusterMemberPost) (err error)
 	CreateClusterMember(member api.ClusterMembersPost) (op Operation, err error)
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
+	RotateClusterCertificate(certs api.ClusterCertificateRotatePost) (op Operation, err error)
+	GetClusterBackup() (content io.ReadCloser, err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	SetClusterMemberMaintenance(name string, state api.ClusterMemberStatePut) (op Operation, err error)
+	GetClusterDrift() (drift *api.ClusterDrift, err error)
+	CreateClusterDatabaseMaintenance(maintenance api.ClusterDatabaseMaintenancePost) (op Operation, err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -433,6 +488,10 @@ type InstanceServer interface {
 	UpdateClusterGroup(name string, group api.ClusterGroupPut, ETag string) error
 	GetClusterGroup(name string) (*api.ClusterGroup, string, error)
 
+	// Replication functions ("instance_replication" API extension)
+	GetReplicationInstances() (instances []api.ReplicationInstance, err error)
+	PromoteReplicationInstances(promote api.ReplicationPromotePost) (op Operation, err error)
+
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
 	GetWarnings() (warnings []api.Warning, err error)
@@ -448,12 +507,20 @@ type InstanceServer interface {
 	UpdateAuthGroup(groupName string, groupPut api.AuthGroupPut, ETag string) error
 	RenameAuthGroup(groupName string, groupPost api.AuthGroupPost) error
 	DeleteAuthGroup(groupName string) error
+	GetAuthRoleNames() (roleNames []string, err error)
+	GetAuthRoles() (roles []api.AuthRole, err error)
+	GetAuthRole(roleName string) (role *api.AuthRole, ETag string, err error)
+	CreateAuthRole(rolesPost api.AuthRolesPost) error
+	UpdateAuthRole(roleName string, rolePut api.AuthRolePut, ETag string) error
+	RenameAuthRole(roleName string, rolePost api.AuthRolePost) error
+	DeleteAuthRole(roleName string) error
 	GetIdentityAuthenticationMethodsIdentifiers() (authMethodsIdentifiers map[string][]string, err error)
 	GetIdentityIdentifiersByAuthenticationMethod(authenticationMethod string) (identifiers []string, err error)
 	GetIdentities() (identities []api.Identity, err error)
 	GetIdentitiesByAuthenticationMethod(authenticationMethod string) (identities []api.Identity, err error)
 	GetIdentity(authenticationMethod string, nameOrIdentifier string) (identity *api.Identity, ETag string, err error)
 	GetCurrentIdentityInfo() (identityInfo *api.IdentityInfo, ETag string, err error)
+	UpdateCurrentIdentity(identityCurrentPut api.IdentityCurrentPut, ETag string) error
 	UpdateIdentity(authenticationMethod string, nameOrIdentifier string, identityPut api.IdentityPut, ETag string) error
 	DeleteIdentity(authenticationMethod string, nameOrIdentifier string) error
 	CreateIdentityTLS(identitiesTLSPost api.IdentitiesTLSPost) error
@@ -548,6 +615,9 @@ type BackupFileRequest struct {
 
 	// A canceler that can be used to interrupt some part of the image download request
 	Canceler *cancel.HTTPRequestCanceller
+
+	// Offset to resume a previously interrupted download from (requires the "backup_export_resume" extension)
+	Offset int64
 }
 
 // The BackupFileResponse struct is used as the response for backup downloads.
@@ -709,6 +779,10 @@ type InstanceCopyArgs struct {
 	// API extension: override_snapshot_profiles_on_copy
 	// If set, snapshots of the instance copy receive profiles of the target instance
 	OverrideSnapshotProfiles bool
+
+	// API extension: instance_copy_regenerate_identity
+	// Identity items to regenerate in the instance copy
+	RegenerateIdentity []string
 }
 
 // The InstanceSnapshotCopyArgs struct is used to pass additional options during instance copy.
@@ -740,6 +814,8 @@ type InstanceConsoleArgs struct {
 // The InstanceConsoleLogArgs struct is used to pass additional options during a
 // instance console log request.
 type InstanceConsoleLogArgs struct {
+	// Whether to use the persistent console history (requires the console_history API extension)
+	History bool
 }
 
 // The InstanceExecArgs struct is used to pass additional options during instance exec.
@@ -787,6 +863,18 @@ type InstanceFileArgs struct {
 	WriteMode string
 }
 
+// The InstanceFileTarArgs struct is used to select the files of a directory tree transferred as a tar stream.
+type InstanceFileTarArgs struct {
+	// Patterns of the files to transfer (all files if empty)
+	Include []string
+
+	// Patterns of the files and directories not to transfer
+	Exclude []string
+
+	// Access the files of a stopped virtual machine through its root disk (ro or rw)
+	Disk string
+}
+
 // The InstanceFileResponse struct is used as part of the response for a instance file download.
 type InstanceFileResponse struct {
 	// User id that owns the file
@@ -816,3 +904,17 @@ type GetPermissionsArgs struct {
 	// level permissions will not be returned.
 	ProjectName string
 }
+
+// EventSelector is used in the calls to GetEventsWithSelector to restrict the lifecycle and operation events
+// received. Other event types aren't affected, and empty fields don't restrict the events.
+type EventSelector struct {
+	// EntityTypes are the types of the entities the events must relate to, for example "instance".
+	EntityTypes []string
+
+	// EntityNames are the names of the entities the events must relate to.
+	EntityNames []string
+
+	// Actions are the actions of the lifecycle events, for example "instance-started".
+	// Operation events have no action and aren't restricted.
+	Actions []string
+}
@@ -119,6 +119,115 @@ func (r *ProtocolLXD) DeleteAuthGroup(groupName string) error {
 	return nil
 }
 
+// GetAuthRoleNames returns a slice of all role names.
+func (r *ProtocolLXD) GetAuthRoleNames() ([]string, error) {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	baseURL := "auth/roles"
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetAuthRole returns a single role by its name.
+func (r *ProtocolLXD) GetAuthRole(roleName string) (*api.AuthRole, string, error) {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return nil, "", err
+	}
+
+	role := api.AuthRole{}
+	etag, err := r.queryStruct(http.MethodGet, api.NewURL().Path("auth", "roles", roleName).String(), nil, "", &role)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &role, etag, nil
+}
+
+// GetAuthRoles returns a list of all roles.
+func (r *ProtocolLXD) GetAuthRoles() ([]api.AuthRole, error) {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []api.AuthRole
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("auth", "roles").WithQuery("recursion", "1").String(), nil, "", &roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// CreateAuthRole creates a new role.
+func (r *ProtocolLXD) CreateAuthRole(role api.AuthRolesPost) error {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, api.NewURL().Path("auth", "roles").String(), role, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthRole replaces the editable fields of the role with the given name.
+func (r *ProtocolLXD) UpdateAuthRole(roleName string, rolePut api.AuthRolePut, ETag string) error {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPut, api.NewURL().Path("auth", "roles", roleName).String(), rolePut, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameAuthRole renames the role with the given name.
+func (r *ProtocolLXD) RenameAuthRole(roleName string, rolePost api.AuthRolePost) error {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, api.NewURL().Path("auth", "roles", roleName).String(), rolePost, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthRole deletes the role with the given name.
+func (r *ProtocolLXD) DeleteAuthRole(roleName string) error {
+	err := r.CheckExtension("auth_roles")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodDelete, api.NewURL().Path("auth", "roles", roleName).String(), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetIdentityAuthenticationMethodsIdentifiers returns a map of authentication method to list of identifiers (e.g. certificate fingerprint, email address)
 // for all identities.
 func (r *ProtocolLXD) GetIdentityAuthenticationMethodsIdentifiers() (map[string][]string, error) {
@@ -247,6 +356,21 @@ func (r *ProtocolLXD) GetCurrentIdentityInfo() (*api.IdentityInfo, string, error
 	return &identityInfo, etag, nil
 }
 
+// UpdateCurrentIdentity updates the display name or the TLS certificate of the requestor, or revokes its sessions.
+func (r *ProtocolLXD) UpdateCurrentIdentity(identityCurrentPut api.IdentityCurrentPut, ETag string) error {
+	err := r.CheckExtension("auth_identity_current_patch")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPatch, api.NewURL().Path("auth", "identities", "current").String(), identityCurrentPut, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateIdentity replaces the editable fields of an identity with the given input.
 func (r *ProtocolLXD) UpdateIdentity(authenticationMethod string, nameOrIdentifer string, identityPut api.IdentityPut, ETag string) error {
 	err := r.CheckExtension("access_management")
@@ -1,6 +1,7 @@
 package lxd
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/canonical/lxd/shared/api"
@@ -174,6 +175,13 @@ func (r *ProtocolLXD) CreateClusterMember(member api.ClusterMembersPost) (Operat
 		return nil, err
 	}
 
+	if member.Preseed != nil {
+		err := r.CheckExtension("clustering_join_preseed")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	u := api.NewURL().Path("cluster", "members")
 	op, _, err := r.queryOperation(http.MethodPost, u.String(), member, "", true)
 	if err != nil {
@@ -199,6 +207,58 @@ func (r *ProtocolLXD) UpdateClusterCertificate(certs api.ClusterCertificatePut,
 	return nil
 }
 
+// RotateClusterCertificate replaces the cluster certificate on every member of the cluster in one operation, rolling
+// back on failure.
+func (r *ProtocolLXD) RotateClusterCertificate(certs api.ClusterCertificateRotatePost) (Operation, error) {
+	err := r.CheckExtension("clustering_certificate_rotation")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("cluster", "certificate", "rotate").String(), certs, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// GetClusterBackup returns a compressed tarball holding dumps of the cluster databases and the certificates of the
+// cluster member, for disaster recovery.
+func (r *ProtocolLXD) GetClusterBackup() (io.ReadCloser, error) {
+	err := r.CheckExtension("cluster_backup")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	requestURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0/cluster/backup")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
 // GetClusterMemberState gets state information about a cluster member.
 func (r *ProtocolLXD) GetClusterMemberState(name string) (*api.ClusterMemberState, string, error) {
 	err := r.CheckExtension("cluster_member_state")
@@ -239,6 +299,53 @@ func (r *ProtocolLXD) UpdateClusterMemberState(name string, state api.ClusterMem
 	return op, nil
 }
 
+// SetClusterMemberMaintenance puts a cluster member in or out of maintenance mode.
+func (r *ProtocolLXD) SetClusterMemberMaintenance(name string, state api.ClusterMemberStatePut) (Operation, error) {
+	err := r.CheckExtension("clustering_maintenance")
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path("cluster", "members", name, "state")
+	op, _, err := r.queryOperation(http.MethodPut, u.String(), state, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// GetClusterDrift returns the inconsistencies between the effective state of the cluster members.
+func (r *ProtocolLXD) GetClusterDrift() (*api.ClusterDrift, error) {
+	err := r.CheckExtension("clustering_drift")
+	if err != nil {
+		return nil, err
+	}
+
+	drift := api.ClusterDrift{}
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("cluster", "drift").String(), nil, "", &drift)
+	if err != nil {
+		return nil, err
+	}
+
+	return &drift, nil
+}
+
+// CreateClusterDatabaseMaintenance runs maintenance tasks on the cluster database.
+func (r *ProtocolLXD) CreateClusterDatabaseMaintenance(maintenance api.ClusterDatabaseMaintenancePost) (Operation, error) {
+	err := r.CheckExtension("clustering_database_maintenance")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("cluster", "database", "maintenance").String(), maintenance, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolLXD) GetClusterGroups() ([]api.ClusterGroup, error) {
 	err := r.CheckExtension("clustering_groups")
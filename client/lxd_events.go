@@ -1,13 +1,69 @@
 package lxd
 
 import (
+	"context"
 	"errors"
-
-	"github.com/gorilla/websocket"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
 )
 
+// eventsConnect connects to the event API at the given URL. The events are streamed in the response when the
+// connection uses HTTP/3, as websockets aren't available over it, and received over a websocket otherwise.
+func (r *ProtocolLXD) eventsConnect(url string) (eventConn, error) {
+	transport, ok := r.http.Transport.(*http3Transport)
+	if ok {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.httpBaseURL.Scheme+"://"+r.httpBaseURL.Host+"/1.0"+url, nil)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		if transport.useHTTP3(req) {
+			conn, err := r.eventsStream(req, cancel)
+			if err == nil {
+				return conn, nil
+			}
+
+			logger.Debug("Failed streaming events over HTTP/3, falling back to websocket", logger.Ctx{"url": req.URL.String(), "err": err})
+		} else {
+			cancel()
+		}
+	}
+
+	wsConn, err := r.websocket(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &websocketEventConn{Conn: wsConn}, nil
+}
+
+// eventsStream sends the event API request and returns the event stream of its response if it was answered over
+// HTTP/3. The cancel function stops the request.
+func (r *ProtocolLXD) eventsStream(req *http.Request, cancel context.CancelFunc) (eventConn, error) {
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if resp.ProtoMajor < 3 || resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("Unexpected %s response with status %q", resp.Proto, resp.Status)
+	}
+
+	return newStreamEventConn(resp.Body, cancel), nil
+}
+
 // getEvents connects to the LXD monitoring interface.
 func (r *ProtocolLXD) getEvents(allProjects bool) (*EventListener, error) {
 	// Resolve the project name.
@@ -25,9 +81,9 @@ func (r *ProtocolLXD) getEvents(allProjects bool) (*EventListener, error) {
 		project = connInfo.Project
 	}
 
-	// Wrap websocket connection in a function to allow the manager to
+	// Wrap the connection in a function to allow the manager to
 	// establish a new connection.
-	getWebsocket := func() (*websocket.Conn, error) {
+	getConn := func() (eventConn, error) {
 		// Resolve LXD events URL.
 		var url string
 		var err error
@@ -41,10 +97,10 @@ func (r *ProtocolLXD) getEvents(allProjects bool) (*EventListener, error) {
 			return nil, err
 		}
 
-		return r.websocket(url)
+		return r.eventsConnect(url)
 	}
 
-	return r.eventListenerManager.getEvents(r.ctxConnected, getWebsocket, project)
+	return r.eventListenerManager.getEvents(r.ctxConnected, getConn, project)
 }
 
 // GetEvents gets the events for the project defined on the client.
@@ -57,6 +113,139 @@ func (r *ProtocolLXD) GetEventsAllProjects() (*EventListener, error) {
 	return r.getEvents(true)
 }
 
+// getDedicatedEvents connects to the LXD monitoring interface with the query parameters of the given URL.
+// A dedicated connection is used so that other listeners don't receive the events specific to this one.
+func (r *ProtocolLXD) getDedicatedEvents(allProjects bool, u *api.URL) (*EventListener, error) {
+	connInfo, err := r.GetConnectionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	if connInfo.Project == "" {
+		return nil, errors.New("Unexpected empty project in connection info")
+	}
+
+	project := ""
+	if !allProjects {
+		project = connInfo.Project
+	}
+
+	if allProjects {
+		u = u.WithQuery("all-projects", "true")
+	}
+
+	getConn := func() (eventConn, error) {
+		url, err := r.setQueryAttributes(u.String())
+		if err != nil {
+			return nil, err
+		}
+
+		return r.eventsConnect(url)
+	}
+
+	return newEventListenerManager(r.ctx).getEvents(r.ctxConnected, getConn, project)
+}
+
+// getEventsSince connects to the LXD monitoring interface, replaying the retained events following the given
+// sequence number.
+func (r *ProtocolLXD) getEventsSince(allProjects bool, since uint64) (*EventListener, error) {
+	err := r.CheckExtension("event_sequence")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getDedicatedEvents(allProjects, api.NewURL().Path("events").WithQuery("since", strconv.FormatUint(since, 10)))
+}
+
+// GetEventsSince gets the events for the project defined on the client, starting with the retained events
+// following the given sequence number.
+func (r *ProtocolLXD) GetEventsSince(since uint64) (*EventListener, error) {
+	return r.getEventsSince(false, since)
+}
+
+// GetEventsAllProjectsSince gets events for all projects, starting with the retained events following the given
+// sequence number.
+func (r *ProtocolLXD) GetEventsAllProjectsSince(since uint64) (*EventListener, error) {
+	return r.getEventsSince(true, since)
+}
+
+// getEventsWithSelector connects to the LXD monitoring interface, only receiving the lifecycle and operation events
+// selected by the selector.
+func (r *ProtocolLXD) getEventsWithSelector(allProjects bool, selector EventSelector) (*EventListener, error) {
+	err := r.CheckExtension("event_selectors")
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path("events")
+	if len(selector.EntityTypes) > 0 {
+		u = u.WithQuery("entity-type", strings.Join(selector.EntityTypes, ","))
+	}
+
+	if len(selector.EntityNames) > 0 {
+		u = u.WithQuery("entity-name", strings.Join(selector.EntityNames, ","))
+	}
+
+	if len(selector.Actions) > 0 {
+		u = u.WithQuery("action", strings.Join(selector.Actions, ","))
+	}
+
+	return r.getDedicatedEvents(allProjects, u)
+}
+
+// GetEventsWithSelector gets the events for the project defined on the client, only receiving the lifecycle and
+// operation events selected by the selector.
+func (r *ProtocolLXD) GetEventsWithSelector(selector EventSelector) (*EventListener, error) {
+	return r.getEventsWithSelector(false, selector)
+}
+
+// GetEventsAllProjectsWithSelector gets events for all projects, only receiving the lifecycle and operation events
+// selected by the selector.
+func (r *ProtocolLXD) GetEventsAllProjectsWithSelector(selector EventSelector) (*EventListener, error) {
+	return r.getEventsWithSelector(true, selector)
+}
+
+// getEventsHistory returns the persisted events of the given types that occurred at or after the given time.
+func (r *ProtocolLXD) getEventsHistory(allProjects bool, since time.Time, types []string) ([]api.Event, error) {
+	err := r.CheckExtension("events_history")
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path("events", "history")
+	if !since.IsZero() {
+		u = u.WithQuery("since", since.UTC().Format(time.RFC3339))
+	}
+
+	if len(types) > 0 {
+		u = u.WithQuery("type", strings.Join(types, ","))
+	}
+
+	if allProjects {
+		u = u.WithQuery("all-projects", "true")
+	}
+
+	events := []api.Event{}
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetEventsHistory returns the persisted events of the given types for the project defined on the client, that
+// occurred at or after the given time. All the allowed types are returned if none are given.
+func (r *ProtocolLXD) GetEventsHistory(since time.Time, types []string) ([]api.Event, error) {
+	return r.getEventsHistory(false, since, types)
+}
+
+// GetEventsHistoryAllProjects returns the persisted events of the given types for all projects, that occurred at
+// or after the given time. All the allowed types are returned if none are given.
+func (r *ProtocolLXD) GetEventsHistoryAllProjects(since time.Time, types []string) ([]api.Event, error) {
+	return r.getEventsHistory(true, since, types)
+}
+
 // SendEvent send an event to the server via the client's event listener connection.
 func (r *ProtocolLXD) SendEvent(event api.Event) error {
 	return r.eventListenerManager.SendEvent(event)
@@ -12,7 +12,6 @@ import (
 	"net/url"
 	"os"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
@@ -261,15 +260,7 @@ func lxdDownloadImage(fingerprint string, uri string, userAgent string, do func(
 			},
 		}
 
-		if response.ContentLength > 0 {
-			reader.Tracker.Handler = func(percent int64, speed int64) {
-				req.ProgressHandler(ioprogress.ProgressData{Text: strconv.FormatInt(percent, 10) + "% (" + units.GetByteSizeString(speed, 2) + "/s)"})
-			}
-		} else {
-			reader.Tracker.Handler = func(received int64, speed int64) {
-				req.ProgressHandler(ioprogress.ProgressData{Text: units.GetByteSizeString(received, 2) + " (" + units.GetByteSizeString(speed, 2) + "/s)"})
-			}
-		}
+		reader.Tracker.DataHandler = req.ProgressHandler
 
 		body = reader
 	}
@@ -992,6 +983,22 @@ func (r *ProtocolLXD) RefreshImage(fingerprint string) (Operation, error) {
 	return op, nil
 }
 
+// GetImageLocations returns the cluster members holding a copy of the image.
+func (r *ProtocolLXD) GetImageLocations(fingerprint string) (*api.ImageLocations, error) {
+	err := r.CheckExtension("clustering_groups_image_replication")
+	if err != nil {
+		return nil, err
+	}
+
+	locations := api.ImageLocations{}
+	_, err = r.queryStruct(http.MethodGet, "/images/"+url.PathEscape(fingerprint)+"/locations", nil, "", &locations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &locations, nil
+}
+
 // CreateImageSecret requests that LXD issues a temporary image secret.
 func (r *ProtocolLXD) CreateImageSecret(fingerprint string) (Operation, error) {
 	// Send the request
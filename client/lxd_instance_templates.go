@@ -0,0 +1,145 @@
+package lxd
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Instance template handling functions
+
+// GetInstanceTemplateNames returns a list of available instance template names.
+func (r *ProtocolLXD) GetInstanceTemplateNames() ([]string, error) {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/instance-templates"
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetInstanceTemplates returns a list of available InstanceTemplate structs.
+func (r *ProtocolLXD) GetInstanceTemplates() ([]api.InstanceTemplate, error) {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []api.InstanceTemplate{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, "/instance-templates?recursion=1", nil, "", &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetInstanceTemplate returns an InstanceTemplate entry for the provided name.
+func (r *ProtocolLXD) GetInstanceTemplate(name string) (*api.InstanceTemplate, string, error) {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return nil, "", err
+	}
+
+	template := api.InstanceTemplate{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct(http.MethodGet, "/instance-templates/"+url.PathEscape(name), nil, "", &template)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &template, etag, nil
+}
+
+// CreateInstanceTemplate defines a new instance template.
+func (r *ProtocolLXD) CreateInstanceTemplate(template api.InstanceTemplatesPost) error {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPost, "/instance-templates", template, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateInstanceTemplate updates the instance template to match the provided InstanceTemplatePut struct.
+func (r *ProtocolLXD) UpdateInstanceTemplate(name string, template api.InstanceTemplatePut, ETag string) error {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPut, "/instance-templates/"+url.PathEscape(name), template, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameInstanceTemplate renames an existing instance template entry.
+func (r *ProtocolLXD) RenameInstanceTemplate(name string, template api.InstanceTemplatePost) error {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPost, "/instance-templates/"+url.PathEscape(name), template, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteInstanceTemplate deletes an instance template.
+func (r *ProtocolLXD) DeleteInstanceTemplate(name string) error {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodDelete, "/instance-templates/"+url.PathEscape(name), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateInstanceFromTemplate requests the creation of a new instance from an instance template.
+func (r *ProtocolLXD) CreateInstanceFromTemplate(name string, req api.InstanceTemplateInstancesPost) (Operation, error) {
+	err := r.CheckExtension("instance_templates")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, "/instance-templates/"+url.PathEscape(name)+"/instances", req, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
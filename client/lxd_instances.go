@@ -2,7 +2,9 @@ package lxd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +16,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -221,6 +224,13 @@ func (r *ProtocolLXD) UpdateInstances(state api.InstancesPut, ETag string) (Oper
 
 // rebuildInstance initiates a rebuild of a given instance on the LXD Protocol server and returns the corresponding operation or an error.
 func (r *ProtocolLXD) rebuildInstance(instanceName string, instance api.InstanceRebuildPost) (Operation, error) {
+	if instance.Reset || instance.Snapshot {
+		err := r.CheckExtension("instance_rebuild_preserve")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
 	if err != nil {
 		return nil, err
@@ -450,85 +460,578 @@ func (r *ProtocolLXD) GetInstancesFullAllProjectsWithFilter(instanceType api.Ins
 	v.Set("all-projects", "true")
 	v.Set("filter", parseFilters(filters))
 
-	err = r.CheckExtension("container_full")
+	err = r.CheckExtension("container_full")
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("instance_all_projects")
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, path+"?"+v.Encode(), nil, "", &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// GetInstance returns the instance entry for the provided name.
+func (r *ProtocolLXD) GetInstance(name string) (*api.Instance, string, error) {
+	instance := api.Instance{}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct(http.MethodGet, path+"/"+url.PathEscape(name), nil, "", &instance)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &instance, etag, nil
+}
+
+// GetInstanceUEFIVars returns the instance UEFI variables list for the provided name.
+func (r *ProtocolLXD) GetInstanceUEFIVars(name string) (*api.InstanceUEFIVars, string, error) {
+	instanceUEFI := api.InstanceUEFIVars{}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = r.CheckExtension("instances_uefi_vars")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct(http.MethodGet, path+"/"+url.PathEscape(name)+"/uefi-vars", nil, "", &instanceUEFI)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &instanceUEFI, etag, nil
+}
+
+// UpdateInstanceUEFIVars updates the instance's UEFI variables.
+func (r *ProtocolLXD) UpdateInstanceUEFIVars(name string, instanceUEFI api.InstanceUEFIVars, ETag string) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instances_uefi_vars")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPut, path+"/"+url.PathEscape(name)+"/uefi-vars", instanceUEFI, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateInstanceUEFIVarsPartial sets or, when their data is empty, removes the given UEFI variables of the instance.
+// If restart is true, a running instance is restarted to apply the change.
+func (r *ProtocolLXD) UpdateInstanceUEFIVarsPartial(name string, instanceUEFI api.InstanceUEFIVars, restart bool) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instance_uefi_vars_management")
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{}
+	if restart {
+		values.Set("restart", "1")
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPatch, path+"/"+url.PathEscape(name)+"/uefi-vars?"+values.Encode(), instanceUEFI, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResetInstanceUEFIVars resets the instance's UEFI variables to the firmware defaults.
+// If restart is true, a running instance is restarted to apply the change.
+func (r *ProtocolLXD) ResetInstanceUEFIVars(name string, restart bool) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instance_uefi_vars_management")
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{}
+	if restart {
+		values.Set("restart", "1")
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodDelete, path+"/"+url.PathEscape(name)+"/uefi-vars?"+values.Encode(), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateInstanceCheckpoint saves the runtime state of a running container, optionally stopping it.
+func (r *ProtocolLXD) CreateInstanceCheckpoint(name string, checkpoint api.InstanceCheckpointPost) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("container_checkpoint_export")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(name)+"/checkpoint", checkpoint, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// GetInstanceCheckpoint returns the checkpoint of a container as a compressed tarball.
+func (r *ProtocolLXD) GetInstanceCheckpoint(name string) (io.ReadCloser, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("container_checkpoint_export")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	url := r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(name) + "/checkpoint"
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// UpdateInstanceCheckpoint replaces the checkpoint of a stopped container with the provided tarball.
+func (r *ProtocolLXD) UpdateInstanceCheckpoint(name string, content io.Reader) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("container_checkpoint_export")
+	if err != nil {
+		return err
+	}
+
+	// Prepare the HTTP request
+	url := r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(name) + "/checkpoint"
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, content)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+
+	// Check the return value for a cleaner error
+	_, _, err = lxdParseResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteInstanceCheckpoint removes the checkpoint of a container.
+func (r *ProtocolLXD) DeleteInstanceCheckpoint(name string) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("container_checkpoint_export")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodDelete, path+"/"+url.PathEscape(name)+"/checkpoint", nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RescueInstance restarts the virtual machine from the given rescue media.
+func (r *ProtocolLXD) RescueInstance(name string, rescue api.InstanceRescuePost) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("instance_rescue")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(name)+"/rescue", rescue, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UnrescueInstance detaches the rescue media from the virtual machine.
+func (r *ProtocolLXD) UnrescueInstance(name string) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("instance_rescue")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodDelete, path+"/"+url.PathEscape(name)+"/rescue", nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// GetInstanceOCIFile exports the stopped container as an OCI image layout tarball written to the writer.
+func (r *ProtocolLXD) GetInstanceOCIFile(name string, tag string, writer io.Writer) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instance_export_oci")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(api.InstanceExportPost{Tag: tag})
+	if err != nil {
+		return err
+	}
+
+	requestURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(name) + "/export?format=oci")
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("Unexpected response status %d", resp.StatusCode)
+	}
+
+	_, err = io.Copy(writer, resp.Body)
+	return err
+}
+
+// PushInstanceOCI exports the stopped container as an OCI image and pushes it to the registry reference set as
+// target in the request.
+func (r *ProtocolLXD) PushInstanceOCI(name string, req api.InstanceExportPost) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("instance_export_oci")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Target == "" {
+		return nil, errors.New("A registry reference must be provided as target")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(name)+"/export?format=oci", req, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// SetupInstanceWindows attaches the Windows setup and VirtIO drivers media to the virtual machine.
+func (r *ProtocolLXD) SetupInstanceWindows(name string, setup api.InstanceWindowsSetupPost) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instance_windows_setup")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPost, path+"/"+url.PathEscape(name)+"/windows-setup", setup, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DetachInstanceWindowsSetup detaches the Windows setup and VirtIO drivers media from the virtual machine.
+func (r *ProtocolLXD) DetachInstanceWindowsSetup(name string) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeVM)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instance_windows_setup")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodDelete, path+"/"+url.PathEscape(name)+"/windows-setup", nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetInstanceTPMState returns the state of the instance's TPM device as a compressed tarball.
+func (r *ProtocolLXD) GetInstanceTPMState(name string, device string) (io.ReadCloser, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("instance_tpm_state")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	url := r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(name) + "/tpm/" + url.PathEscape(device)
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// UpdateInstanceTPMState replaces the state of the TPM device of a stopped instance with the provided tarball.
+func (r *ProtocolLXD) UpdateInstanceTPMState(name string, device string, content io.Reader) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	err = r.CheckExtension("instance_tpm_state")
+	if err != nil {
+		return err
+	}
+
+	// Prepare the HTTP request
+	url := r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(name) + "/tpm/" + url.PathEscape(device)
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, content)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	err = r.CheckExtension("instance_all_projects")
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Fetch the raw value
-	_, err = r.queryStruct(http.MethodGet, path+"?"+v.Encode(), nil, "", &instances)
+	// Check the return value for a cleaner error
+	_, _, err = lxdParseResponse(resp)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return instances, nil
+	return nil
 }
 
-// GetInstance returns the instance entry for the provided name.
-func (r *ProtocolLXD) GetInstance(name string) (*api.Instance, string, error) {
-	instance := api.Instance{}
-
+// ResetInstanceTPMState removes the state of the TPM device of a stopped instance.
+func (r *ProtocolLXD) ResetInstanceTPMState(name string, device string) error {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
 
-	// Fetch the raw value
-	etag, err := r.queryStruct(http.MethodGet, path+"/"+url.PathEscape(name), nil, "", &instance)
+	err = r.CheckExtension("instance_tpm_state")
 	if err != nil {
-		return nil, "", err
+		return err
 	}
 
-	return &instance, etag, nil
+	// Send the request
+	_, _, err = r.query(http.MethodDelete, path+"/"+url.PathEscape(name)+"/tpm/"+url.PathEscape(device), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
-// GetInstanceUEFIVars returns the instance UEFI variables list for the provided name.
-func (r *ProtocolLXD) GetInstanceUEFIVars(name string) (*api.InstanceUEFIVars, string, error) {
-	instanceUEFI := api.InstanceUEFIVars{}
+// GetInstanceAttestation returns the confidential computing attestation information of the instance.
+// When a nonce is provided, an attestation report including it is generated within the guest.
+func (r *ProtocolLXD) GetInstanceAttestation(name string, nonce []byte) (*api.InstanceAttestation, error) {
+	attestation := api.InstanceAttestation{}
 
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	err = r.CheckExtension("instances_uefi_vars")
+	err = r.CheckExtension("instance_confidential_computing")
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+
+	uri := path + "/" + url.PathEscape(name) + "/attestation"
+	if len(nonce) > 0 {
+		uri += "?nonce=" + hex.EncodeToString(nonce)
 	}
 
 	// Fetch the raw value
-	etag, err := r.queryStruct(http.MethodGet, path+"/"+url.PathEscape(name)+"/uefi-vars", nil, "", &instanceUEFI)
+	_, err = r.queryStruct(http.MethodGet, uri, nil, "", &attestation)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	return &instanceUEFI, etag, nil
+	return &attestation, nil
 }
 
-// UpdateInstanceUEFIVars updates the instance's UEFI variables.
-func (r *ProtocolLXD) UpdateInstanceUEFIVars(name string, instanceUEFI api.InstanceUEFIVars, ETag string) error {
-	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+// GetInstancesAutostart returns the last instance startup sequence of the server.
+func (r *ProtocolLXD) GetInstancesAutostart() (*api.InstancesAutostart, error) {
+	err := r.CheckExtension("instances_autostart_parallel")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = r.CheckExtension("instances_uefi_vars")
-	if err != nil {
-		return err
-	}
+	autostart := api.InstancesAutostart{}
 
-	// Send the request
-	_, _, err = r.query(http.MethodPut, path+"/"+url.PathEscape(name)+"/uefi-vars", instanceUEFI, ETag)
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, "/autostart", nil, "", &autostart)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &autostart, nil
 }
 
 // GetInstanceFull returns the instance entry for the provided name along with snapshot information.
@@ -891,6 +1394,12 @@ func (r *ProtocolLXD) CopyInstance(source InstanceServer, instance api.Instance,
 			}
 		}
 
+		if len(args.RegenerateIdentity) > 0 {
+			if !r.HasExtension("instance_copy_regenerate_identity") {
+				return nil, errors.New("The target server is missing the required \"instance_copy_regenerate_identity\" API extension")
+			}
+		}
+
 		// Allow overriding the target name
 		if args.Name != "" {
 			req.Name = args.Name
@@ -905,6 +1414,7 @@ func (r *ProtocolLXD) CopyInstance(source InstanceServer, instance api.Instance,
 		req.Source.AllowInconsistent = args.AllowInconsistent
 
 		req.Source.OverrideSnapshotProfiles = args.OverrideSnapshotProfiles
+		req.Source.RegenerateIdentity = args.RegenerateIdentity
 	}
 
 	if req.Source.Live {
@@ -1244,6 +1754,20 @@ func (r *ProtocolLXD) MigrateInstance(name string, instance api.InstancePost) (O
 		}
 	}
 
+	if len(instance.MigrationConfig) > 0 {
+		err := r.CheckExtension("instance_migration_tunables")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if instance.Remote != nil {
+		err := r.CheckExtension("instance_remote_migration")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Quick check.
 	if !instance.Migration {
 		return nil, errors.New("Can't ask for a rename through MigrateInstance")
@@ -1724,6 +2248,102 @@ func (r *ProtocolLXD) CreateInstanceFile(instanceName string, filePath string, a
 	return nil
 }
 
+// instanceFileTarURL returns the URL to transfer the tree rooted at the path in the instance as a tar stream.
+func (r *ProtocolLXD) instanceFileTarURL(instanceName string, filePath string, args *InstanceFileTarArgs) (string, error) {
+	err := r.CheckExtension("instance_files_tar")
+	if err != nil {
+		return "", err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("path", filePath)
+	values.Set("format", "tar")
+
+	if args != nil {
+		if args.Disk != "" {
+			err := r.CheckExtension("instance_files_disk")
+			if err != nil {
+				return "", err
+			}
+
+			values.Set("disk", args.Disk)
+		}
+
+		for _, pattern := range args.Include {
+			values.Add("include", pattern)
+		}
+
+		for _, pattern := range args.Exclude {
+			values.Add("exclude", pattern)
+		}
+	}
+
+	return r.setQueryAttributes(r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(instanceName) + "/files?" + values.Encode())
+}
+
+// GetInstanceFileTar retrieves the tree rooted at the provided path in the instance as a tar stream.
+func (r *ProtocolLXD) GetInstanceFileTar(instanceName string, filePath string, args *InstanceFileTarArgs) (io.ReadCloser, error) {
+	requestURL, err := r.instanceFileTarURL(instanceName, filePath, args)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// CreateInstanceFileTar extracts the tar stream into the directory at the provided path in the instance.
+func (r *ProtocolLXD) CreateInstanceFileTar(instanceName string, filePath string, content io.Reader, args *InstanceFileTarArgs) error {
+	requestURL, err := r.instanceFileTarURL(instanceName, filePath, args)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, requestURL, content)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-tar")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+
+	// Check the return value for a cleaner error
+	_, _, err = lxdParseResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteInstanceFile deletes a file in the instance.
 func (r *ProtocolLXD) DeleteInstanceFile(instanceName string, filePath string) error {
 	err := r.CheckExtension("file_delete")
@@ -1860,6 +2480,53 @@ func (r *ProtocolLXD) GetInstanceFileSFTP(instanceName string) (*sftp.Client, er
 	return client, nil
 }
 
+// GetInstanceDiskFileSFTPConn returns a connection to the SFTP server of a stopped virtual machine, operating on
+// its root disk mounted on the host (read-only unless writable is set).
+func (r *ProtocolLXD) GetInstanceDiskFileSFTPConn(instanceName string, writable bool) (net.Conn, error) {
+	err := r.CheckExtension("instance_files_disk")
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := api.NewURL()
+	apiURL.URL = r.httpBaseURL // Preload the URL with the client base URL.
+	apiURL.Path("1.0", "instances", instanceName, "sftp")
+
+	disk := "ro"
+	if writable {
+		disk = "rw"
+	}
+
+	apiURL.WithQuery("disk", disk)
+	r.setURLQueryAttributes(&apiURL.URL)
+
+	return r.rawSFTPConn(&apiURL.URL)
+}
+
+// GetInstanceDiskFileSFTP returns an SFTP connection to a stopped virtual machine, operating on its root disk
+// mounted on the host (read-only unless writable is set).
+func (r *ProtocolLXD) GetInstanceDiskFileSFTP(instanceName string, writable bool) (*sftp.Client, error) {
+	conn, err := r.GetInstanceDiskFileSFTPConn(instanceName, writable)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get a SFTP client.
+	client, err := sftp.NewClientPipe(conn, conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	go func() {
+		// Wait for the client to be done before closing the connection.
+		_ = client.Wait()
+		_ = conn.Close()
+	}()
+
+	return client, nil
+}
+
 // GetInstanceSnapshotNames returns a list of snapshot names for the instance.
 func (r *ProtocolLXD) GetInstanceSnapshotNames(instanceName string) ([]string, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2343,6 +3010,35 @@ func (r *ProtocolLXD) GetInstanceState(name string) (*api.InstanceState, string,
 	return &state, etag, nil
 }
 
+// GetInstanceStateHistory returns the resource usage history of the instance over the given period.
+// A zero period returns the history of the last 24 hours.
+func (r *ProtocolLXD) GetInstanceStateHistory(name string, period time.Duration) (*api.InstanceStateHistory, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("instance_state_history")
+	if err != nil {
+		return nil, err
+	}
+
+	uri := path + "/" + url.PathEscape(name) + "/state/history"
+	if period > 0 {
+		uri += "?period=" + url.QueryEscape(period.String())
+	}
+
+	history := api.InstanceStateHistory{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, uri, nil, "", &history)
+	if err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
 // UpdateInstanceState updates the instance to match the requested state.
 func (r *ProtocolLXD) UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2880,6 +3576,15 @@ func (r *ProtocolLXD) GetInstanceConsoleLog(instanceName string, args *InstanceC
 	// Prepare the HTTP request
 	url := r.httpBaseURL.String() + "/1.0" + path + "/" + url.PathEscape(instanceName) + "/console"
 
+	if args != nil && args.History {
+		err = r.CheckExtension("console_history")
+		if err != nil {
+			return nil, err
+		}
+
+		url += "?history=1"
+	}
+
 	url, err = r.setQueryAttributes(url)
 	if err != nil {
 		return nil, err
@@ -2919,8 +3624,19 @@ func (r *ProtocolLXD) DeleteInstanceConsoleLog(instanceName string, args *Instan
 		return err
 	}
 
+	uri := path + "/" + url.PathEscape(instanceName) + "/console"
+
+	if args != nil && args.History {
+		err = r.CheckExtension("console_history")
+		if err != nil {
+			return err
+		}
+
+		uri += "?history=1"
+	}
+
 	// Send the request
-	_, _, err = r.query(http.MethodDelete, path+"/"+url.PathEscape(instanceName)+"/console", nil, "")
+	_, _, err = r.query(http.MethodDelete, uri, nil, "")
 	if err != nil {
 		return err
 	}
@@ -3078,53 +3794,113 @@ func (r *ProtocolLXD) GetInstanceBackupFile(instanceName string, name string, re
 		uri += "?project=" + url.QueryEscape(r.project)
 	}
 
-	// Prepare the download request
-	request, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
-	}
+	return r.getBackupFile(uri, req)
+}
 
-	if r.httpUserAgent != "" {
-		request.Header.Set("User-Agent", r.httpUserAgent)
+// getBackupFile downloads the backup file at the given URI into the request's writer.
+// When the server supports ranged backup downloads, an interrupted transfer is resumed from the
+// last received byte rather than restarted.
+func (r *ProtocolLXD) getBackupFile(uri string, req *BackupFileRequest) (*BackupFileResponse, error) {
+	resumable := r.HasExtension("backup_export_resume")
+	if req.Offset > 0 && !resumable {
+		return nil, errors.New(`The server is missing the required "backup_export_resume" API extension`)
 	}
 
-	// Start the request
-	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
-	if err != nil {
-		return nil, err
-	}
+	offset := req.Offset
+	length := int64(-1)
+	lastModified := ""
+	retries := 0
 
-	defer func() { _ = response.Body.Close() }()
-	defer close(doneCh)
+	for {
+		// Prepare the download request
+		request, err := http.NewRequest(http.MethodGet, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.httpUserAgent != "" {
+			request.Header.Set("User-Agent", r.httpUserAgent)
+		}
+
+		if offset > 0 {
+			request.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+
+			// Make sure the file didn't change under us between attempts.
+			if lastModified != "" {
+				request.Header.Set("If-Range", lastModified)
+			}
+		}
 
-	if response.StatusCode != http.StatusOK {
-		_, _, err := lxdParseResponse(response)
+		// Start the request
+		response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
 		if err != nil {
 			return nil, err
 		}
-	}
 
-	// Handle the data
-	body := response.Body
-	if req.ProgressHandler != nil {
-		body = &ioprogress.ProgressReader{
-			ReadCloser: response.Body,
-			Tracker: &ioprogress.ProgressTracker{
-				Length: response.ContentLength,
-				Handler: func(percent int64, speed int64) {
-					req.ProgressHandler(ioprogress.ProgressData{Text: strconv.FormatInt(percent, 10) + "% (" + units.GetByteSizeString(speed, 2) + "/s)"})
-				},
-			},
+		written, err := func() (int64, error) {
+			defer func() { _ = response.Body.Close() }()
+			defer close(doneCh)
+
+			switch response.StatusCode {
+			case http.StatusPartialContent:
+			case http.StatusOK:
+				// The server sent the whole file, start over.
+				offset = 0
+			default:
+				_, _, err := lxdParseResponse(response)
+				if err != nil {
+					return 0, err
+				}
+
+				return 0, fmt.Errorf("Unexpected response status %d", response.StatusCode)
+			}
+
+			lastModified = response.Header.Get("Last-Modified")
+			if response.ContentLength >= 0 {
+				length = offset + response.ContentLength
+			}
+
+			_, err := req.BackupFile.Seek(offset, io.SeekStart)
+			if err != nil {
+				return 0, err
+			}
+
+			// Handle the data
+			body := response.Body
+			if req.ProgressHandler != nil {
+				body = &ioprogress.ProgressReader{
+					ReadCloser: response.Body,
+					Tracker: &ioprogress.ProgressTracker{
+						Length: response.ContentLength,
+						Handler: func(percent int64, speed int64) {
+							req.ProgressHandler(ioprogress.ProgressData{Text: strconv.FormatInt(percent, 10) + "% (" + units.GetByteSizeString(speed, 2) + "/s)"})
+						},
+					},
+				}
+			}
+
+			return io.Copy(req.BackupFile, body)
+		}()
+
+		offset += written
+		if err == nil {
+			break
+		}
+
+		// Only retry transfers which were interrupted part way through.
+		if !resumable || written == 0 || retries >= 5 || errors.Is(err, context.Canceled) {
+			return nil, err
 		}
+
+		retries++
 	}
 
-	size, err := io.Copy(req.BackupFile, body)
-	if err != nil {
-		return nil, err
+	if length >= 0 && offset != length {
+		return nil, fmt.Errorf("Backup file is incomplete (received %d out of %d bytes)", offset, length)
 	}
 
 	resp := BackupFileResponse{}
-	resp.Size = size
+	resp.Size = offset
 
 	return &resp, nil
 }
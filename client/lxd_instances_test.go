@@ -0,0 +1,181 @@
+package lxd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// backupTestServer serves a backup file, dropping the connection after sending the first dropAfter bytes of
+// each of the first drops requests. If replace is set, the file is replaced whenever the connection is dropped.
+type backupTestServer struct {
+	data      []byte
+	modified  time.Time
+	dropAfter int
+	drops     int
+	replace   bool
+
+	ranges []string
+}
+
+func (s *backupTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+
+	if s.drops > 0 {
+		s.drops--
+
+		start := 0
+		rangeStart, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes=")
+		if ok {
+			start, _ = strconv.Atoi(strings.TrimSuffix(rangeStart, "-"))
+		}
+
+		end := min(start+s.dropAfter, len(s.data))
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(s.data)-start))
+		w.Header().Set("Last-Modified", s.modified.UTC().Format(http.TimeFormat))
+		if start > 0 {
+			w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(s.data)-1)+"/"+strconv.Itoa(len(s.data)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+
+		_, _ = w.Write(s.data[start:end])
+		w.(http.Flusher).Flush()
+
+		// Drop the connection part way through the body.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+
+		if s.replace {
+			s.modified = s.modified.Add(time.Hour)
+		}
+
+		return
+	}
+
+	http.ServeContent(w, r, "backup.tar.gz", s.modified, bytes.NewReader(s.data))
+}
+
+func Test_getBackupFile(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+
+	tests := []struct {
+		name       string
+		extensions []string
+		drops      int
+		dropAfter  int
+		replace    bool
+		offset     int64
+		wantErr    bool
+		wantRanges []string
+	}{
+		{
+			name:       "Uninterrupted download",
+			extensions: []string{"backup_export_resume"},
+			wantRanges: []string{""},
+		},
+		{
+			name:       "Resumed after interruptions",
+			extensions: []string{"backup_export_resume"},
+			drops:      2,
+			dropAfter:  30000,
+			wantRanges: []string{"", "bytes=30000-", "bytes=60000-"},
+		},
+		{
+			name:       "Restarted when the file changed",
+			extensions: []string{"backup_export_resume"},
+			drops:      1,
+			dropAfter:  30000,
+			replace:    true,
+			wantRanges: []string{"", "bytes=30000-"},
+		},
+		{
+			name:       "Resumed from the given offset",
+			extensions: []string{"backup_export_resume"},
+			offset:     50000,
+			wantRanges: []string{"bytes=50000-"},
+		},
+		{
+			name:       "Too many interruptions",
+			extensions: []string{"backup_export_resume"},
+			drops:      10,
+			dropAfter:  10000,
+			wantErr:    true,
+			wantRanges: []string{"", "bytes=10000-", "bytes=20000-", "bytes=30000-", "bytes=40000-", "bytes=50000-"},
+		},
+		{
+			name:       "Interrupted without resume support",
+			drops:      1,
+			dropAfter:  30000,
+			wantErr:    true,
+			wantRanges: []string{""},
+		},
+		{
+			name:    "Offset without resume support",
+			offset:  50000,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backupServer := &backupTestServer{data: data, modified: time.Now(), dropAfter: tt.dropAfter, drops: tt.drops, replace: tt.replace}
+			server := httptest.NewServer(backupServer)
+			defer server.Close()
+
+			r := &ProtocolLXD{http: server.Client(), server: &api.Server{ServerUntrusted: api.ServerUntrusted{APIExtensions: tt.extensions}}}
+
+			f, err := os.Create(filepath.Join(t.TempDir(), "backup.tar.gz"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() { _ = f.Close() }()
+
+			if tt.offset > 0 {
+				_, err = f.Write(data[:tt.offset])
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			resp, err := r.getBackupFile(server.URL+"/1.0/instances/c1/backups/b1/export", &BackupFileRequest{BackupFile: f, Offset: tt.offset})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("getBackupFile() succeeded, want an error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("getBackupFile() error = %v", err)
+				}
+
+				if resp.Size != int64(len(data)) {
+					t.Errorf("getBackupFile() size = %d, want %d", resp.Size, len(data))
+				}
+
+				got, err := os.ReadFile(f.Name())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(got, data) {
+					t.Error("Downloaded backup doesn't match the original")
+				}
+			}
+
+			if strings.Join(backupServer.ranges, ",") != strings.Join(tt.wantRanges, ",") {
+				t.Errorf("Range headers = %q, want %q", backupServer.ranges, tt.wantRanges)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package lxd
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -82,6 +84,22 @@ func (r *ProtocolLXD) GetProjectState(name string) (*api.ProjectState, error) {
 	return &projectState, nil
 }
 
+// UpdateProjectState disables or enables a project.
+func (r *ProtocolLXD) UpdateProjectState(name string, state api.ProjectStatePut) (Operation, error) {
+	err := r.CheckExtension("projects_disable")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPut, "/projects/"+url.PathEscape(name)+"/state", state, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // CreateProject defines a new container project.
 func (r *ProtocolLXD) CreateProject(project api.ProjectsPost) error {
 	err := r.CheckExtension("projects")
@@ -145,3 +163,186 @@ func (r *ProtocolLXD) DeleteProject(name string) error {
 
 	return nil
 }
+
+// GetProjectExport returns a compressed tarball holding the configuration of the project and of the entities it owns.
+func (r *ProtocolLXD) GetProjectExport(name string) (io.ReadCloser, error) {
+	err := r.CheckExtension("project_export")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	requestURL, err := r.setQueryAttributes(r.httpBaseURL.String() + "/1.0/projects/" + url.PathEscape(name) + "/export")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// ImportProject re-creates a project along with its profiles, networks, network ACLs and network zones
+// from a project export.
+// Entities are created one by one, so if an error occurs the entities created until then are left in place.
+func (r *ProtocolLXD) ImportProject(export api.ProjectExport) error {
+	err := r.CheckExtension("project_export")
+	if err != nil {
+		return err
+	}
+
+	err = r.CreateProject(export.Project)
+	if err != nil {
+		return fmt.Errorf("Failed creating project %q: %w", export.Project.Name, err)
+	}
+
+	projectServer := r.UseProject(export.Project.Name)
+
+	// Create the ACLs without their rules first as the rules can reference other ACLs.
+	for _, acl := range export.NetworkACLs {
+		err = projectServer.CreateNetworkACL(api.NetworkACLsPost{
+			NetworkACLPost: acl.NetworkACLPost,
+			NetworkACLPut: api.NetworkACLPut{
+				Description: acl.Description,
+				Config:      acl.Config,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed creating network ACL %q: %w", acl.Name, err)
+		}
+	}
+
+	for _, acl := range export.NetworkACLs {
+		err = projectServer.UpdateNetworkACL(acl.Name, acl.NetworkACLPut, "")
+		if err != nil {
+			return fmt.Errorf("Failed updating network ACL %q: %w", acl.Name, err)
+		}
+	}
+
+	for _, zone := range export.NetworkZones {
+		err = projectServer.CreateNetworkZone(zone.NetworkZonesPost)
+		if err != nil {
+			return fmt.Errorf("Failed creating network zone %q: %w", zone.Name, err)
+		}
+
+		for _, record := range zone.Records {
+			err = projectServer.CreateNetworkZoneRecord(zone.Name, record)
+			if err != nil {
+				return fmt.Errorf("Failed creating record %q of network zone %q: %w", record.Name, zone.Name, err)
+			}
+		}
+	}
+
+	// When clustered, networks other than OVN ones need to be defined on each member first.
+	var members []string
+	if r.IsClustered() {
+		members, err = r.GetClusterMemberNames()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, network := range export.Networks {
+		if network.Type != "ovn" {
+			for _, member := range members {
+				err = projectServer.UseTarget(member).CreateNetwork(api.NetworksPost{Name: network.Name, Type: network.Type})
+				if err != nil {
+					return fmt.Errorf("Failed defining network %q on member %q: %w", network.Name, member, err)
+				}
+			}
+		}
+
+		err = projectServer.CreateNetwork(network)
+		if err != nil {
+			return fmt.Errorf("Failed creating network %q: %w", network.Name, err)
+		}
+	}
+
+	// Profiles go last as their devices can reference the networks.
+	for _, profile := range export.Profiles {
+		if profile.Name == "default" {
+			err = projectServer.UpdateProfile(profile.Name, profile.ProfilePut, "")
+			if err != nil {
+				return fmt.Errorf("Failed updating profile %q: %w", profile.Name, err)
+			}
+
+			continue
+		}
+
+		err = projectServer.CreateProfile(profile)
+		if err != nil {
+			return fmt.Errorf("Failed creating profile %q: %w", profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetProjectShares returns the shares of the entities of the project with other projects.
+func (r *ProtocolLXD) GetProjectShares(name string) ([]api.ProjectShare, string, error) {
+	err := r.CheckExtension("projects_shares")
+	if err != nil {
+		return nil, "", err
+	}
+
+	shares := []api.ProjectShare{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct(http.MethodGet, "/projects/"+url.PathEscape(name)+"/shares", nil, "", &shares)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return shares, etag, nil
+}
+
+// GetProjectReceivedShares returns the shares of the entities of other projects with the project.
+func (r *ProtocolLXD) GetProjectReceivedShares(name string) ([]api.ProjectShare, error) {
+	err := r.CheckExtension("projects_shares")
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []api.ProjectShare{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, "/projects/"+url.PathEscape(name)+"/shares?received=true", nil, "", &shares)
+	if err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// UpdateProjectShares replaces the shares of the entities of the project with other projects.
+func (r *ProtocolLXD) UpdateProjectShares(name string, shares api.ProjectSharesPut, ETag string) error {
+	err := r.CheckExtension("projects_shares")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPut, "/projects/"+url.PathEscape(name)+"/shares", shares, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package lxd
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Replication handling functions
+
+// GetReplicationInstances returns the replication state of the replicated instances and standby replicas.
+func (r *ProtocolLXD) GetReplicationInstances() ([]api.ReplicationInstance, error) {
+	err := r.CheckExtension("instance_replication")
+	if err != nil {
+		return nil, err
+	}
+
+	instances := []api.ReplicationInstance{}
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("replication").String(), nil, "", &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// PromoteReplicationInstances promotes the standby replicas into regular instances.
+func (r *ProtocolLXD) PromoteReplicationInstances(promote api.ReplicationPromotePost) (Operation, error) {
+	err := r.CheckExtension("instance_replication")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation(http.MethodPost, api.NewURL().Path("replication", "promote").String(), promote, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -159,6 +159,41 @@ func (r *ProtocolLXD) GetMetrics() (string, error) {
 	return string(content), nil
 }
 
+// GetHealth returns the health of the server. The status of each subsystem is only included if the client is
+// allowed to view the server resources.
+func (r *ProtocolLXD) GetHealth() (*api.Health, error) {
+	err := r.CheckExtension("health")
+	if err != nil {
+		return nil, err
+	}
+
+	health := api.Health{}
+
+	_, err = r.queryStruct(http.MethodGet, "/health", nil, "", &health)
+	if err != nil {
+		return nil, err
+	}
+
+	return &health, nil
+}
+
+// ExecuteBatch executes a batch of API requests and returns the result of each of them.
+func (r *ProtocolLXD) ExecuteBatch(batch api.BatchPost) (*api.Batch, error) {
+	err := r.CheckExtension("batch")
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.Batch{}
+
+	_, err = r.queryStruct(http.MethodPost, "/batch", batch, "", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // GetMetadataConfiguration returns metadata configuration for a server.
 func (r *ProtocolLXD) GetMetadataConfiguration() (*api.MetadataConfiguration, error) {
 	// Check that the server supports it.
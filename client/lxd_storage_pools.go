@@ -137,3 +137,37 @@ func (r *ProtocolLXD) GetStoragePoolResources(name string) (*api.ResourcesStorag
 
 	return &res, nil
 }
+
+// ScanStoragePool looks for volumes on the storage pool that are missing from the database.
+func (r *ProtocolLXD) ScanStoragePool(name string) (*api.StoragePoolScan, error) {
+	err := r.CheckExtension("storage_pool_recover")
+	if err != nil {
+		return nil, err
+	}
+
+	scan := api.StoragePoolScan{}
+
+	// Send the request
+	_, err = r.queryStruct(http.MethodPost, "/storage-pools/"+url.PathEscape(name)+"/scan", nil, "", &scan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scan, nil
+}
+
+// RecoverStoragePool re-creates the database records of unknown volumes found on the storage pool.
+func (r *ProtocolLXD) RecoverStoragePool(name string, req api.StoragePoolRecoverPost) (Operation, error) {
+	err := r.CheckExtension("storage_pool_recover")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, "/storage-pools/"+url.PathEscape(name)+"/recover", req, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -3,17 +3,12 @@ package lxd
 import (
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
-	"github.com/canonical/lxd/shared/cancel"
-	"github.com/canonical/lxd/shared/ioprogress"
-	"github.com/canonical/lxd/shared/units"
 )
 
 // Storage volumes handling function
@@ -1014,55 +1009,7 @@ func (r *ProtocolLXD) GetStoragePoolVolumeBackupFile(pool string, volName string
 		uri += "?project=" + url.QueryEscape(r.project)
 	}
 
-	// Prepare the download request
-	request, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if r.httpUserAgent != "" {
-		request.Header.Set("User-Agent", r.httpUserAgent)
-	}
-
-	// Start the request
-	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
-	if err != nil {
-		return nil, err
-	}
-
-	defer func() { _ = response.Body.Close() }()
-	defer close(doneCh)
-
-	if response.StatusCode != http.StatusOK {
-		_, _, err := lxdParseResponse(response)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// Handle the data
-	body := response.Body
-	if req.ProgressHandler != nil {
-		body = &ioprogress.ProgressReader{
-			ReadCloser: response.Body,
-			Tracker: &ioprogress.ProgressTracker{
-				Length: response.ContentLength,
-				Handler: func(percent int64, speed int64) {
-					req.ProgressHandler(ioprogress.ProgressData{Text: strconv.FormatInt(percent, 10) + "% (" + units.GetByteSizeString(speed, 2) + "/s)"})
-				},
-			},
-		}
-	}
-
-	size, err := io.Copy(req.BackupFile, body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := BackupFileResponse{}
-	resp.Size = size
-
-	return &resp, nil
+	return r.getBackupFile(uri, req)
 }
 
 func (r *ProtocolLXD) createStoragePoolVolumeFromFile(pool string, args StoragePoolVolumeBackupArgs, fileType string) (Operation, error) {
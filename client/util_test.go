@@ -2,9 +2,14 @@ package lxd
 
 import (
 	"errors"
+	"io"
+	"net/http"
 	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
 )
 
 func Test_setQueryParam(t *testing.T) {
@@ -212,3 +217,96 @@ func Test_openBrowser(t *testing.T) {
 		})
 	}
 }
+
+func Test_http3AltSvcPort(t *testing.T) {
+	tests := []struct {
+		name   string
+		altSvc []string
+		want   int
+	}{
+		{
+			name:   "no header",
+			altSvc: nil,
+			want:   0,
+		},
+		{
+			name:   "same host",
+			altSvc: []string{`h3=":8443"; ma=2592000`},
+			want:   8443,
+		},
+		{
+			name:   "other protocols first",
+			altSvc: []string{`h2=":443", h3=":8444"; ma=60`},
+			want:   8444,
+		},
+		{
+			name:   "multiple headers",
+			altSvc: []string{`h2=":443"`, `h3=":8445"`},
+			want:   8445,
+		},
+		{
+			name:   "other host",
+			altSvc: []string{`h3="example.com:8443"`},
+			want:   0,
+		},
+		{
+			name:   "invalid port",
+			altSvc: []string{`h3=":foo"`, `h3=":70000"`},
+			want:   0,
+		},
+		{
+			name:   "clear",
+			altSvc: []string{`clear`},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, value := range tt.altSvc {
+				header.Add("Alt-Svc", value)
+			}
+
+			got := http3AltSvcPort(header)
+			if got != tt.want {
+				t.Errorf("http3AltSvcPort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_streamEventConn(t *testing.T) {
+	cancelled := false
+	body := io.NopCloser(strings.NewReader("{\"type\":\"logging\"}\n\n{\"type\":\"lifecycle\"}\n"))
+	conn := newStreamEventConn(body, func() { cancelled = true })
+
+	var got []string
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("ReadMessage() error = %v, want %v", err, io.ErrUnexpectedEOF)
+			}
+
+			break
+		}
+
+		got = append(got, string(data))
+	}
+
+	want := []string{`{"type":"logging"}`, `{"type":"lifecycle"}`}
+	if !slices.Equal(got, want) {
+		t.Errorf("ReadMessage() = %v, want %v", got, want)
+	}
+
+	err := conn.WriteEvent(api.Event{Type: "logging"}, time.Now())
+	if err == nil {
+		t.Error("WriteEvent() succeeded on an event stream")
+	}
+
+	_ = conn.Close()
+	if !cancelled {
+		t.Error("Close() didn't stop the request")
+	}
+}
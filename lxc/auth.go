@@ -10,7 +10,9 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -49,6 +51,9 @@ func (c *cmdAuth) command() *cobra.Command {
 	identityProviderGroupCmd := cmdIdentityProviderGroup{global: c.global}
 	cmd.AddCommand(identityProviderGroupCmd.command())
 
+	roleCmd := cmdRole{global: c.global}
+	cmd.AddCommand(roleCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -87,6 +92,9 @@ func (c *cmdGroup) command() *cobra.Command {
 	permissionCmd := cmdGroupPermission{global: c.global}
 	cmd.AddCommand(permissionCmd.command())
 
+	roleCmd := cmdGroupRole{global: c.global}
+	cmd.AddCommand(roleCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -221,7 +229,7 @@ func (c *cmdGroupEdit) helpTemplate() string {
 		`### This is a YAML representation of the group.
 ### Any line starting with a '# will be ignored.
 ###
-### NOTE: All group information is shown but only the description and permissions can be modified.
+### NOTE: All group information is shown but only the description, permissions and roles can be modified.
 ###
 ### name: my-first-group
 ### description: My first group.
@@ -229,6 +237,10 @@ func (c *cmdGroupEdit) helpTemplate() string {
 ### - entity_type: project
 ###   url: /1.0/projects/default
 ###   entitlement: can_view
+### roles:
+### - role: network-operator
+###   entity_type: network
+###   url: /1.0/networks/lxdbr0?project=default
 ### identities:
 ###   oidc:
 ###   - jane.doe@example.com
@@ -512,6 +524,8 @@ func (c *cmdGroupPermission) command() *cobra.Command {
 
 type cmdGroupPermissionAdd struct {
 	global *cmdGlobal
+
+	flagExpiry string
 }
 
 func (c *cmdGroupPermissionAdd) command() *cobra.Command {
@@ -520,6 +534,7 @@ func (c *cmdGroupPermissionAdd) command() *cobra.Command {
 	cmd.Short = i18n.G("Add permissions to groups")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Add permissions to groups`))
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", i18n.G(`Permission expiration as a space separated list of durations in the form (\d)+(S|M|H|d|w|m|y)`)+"``")
 
 	cmd.RunE = c.run
 
@@ -555,8 +570,17 @@ func (c *cmdGroupPermissionAdd) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagExpiry != "" {
+		expiryDate, err := shared.GetExpiry(time.Now(), c.flagExpiry)
+		if err != nil {
+			return err
+		}
+
+		permission.ExpiresAt = &expiryDate
+	}
+
 	added := false
-	if !slices.Contains(group.Permissions, *permission) {
+	if !slices.ContainsFunc(group.Permissions, func(existingPermission api.Permission) bool { return samePermission(*permission, existingPermission) }) {
 		group.Permissions = append(group.Permissions, *permission)
 		added = true
 	}
@@ -621,7 +645,7 @@ func (c *cmdGroupPermissionRemove) run(cmd *cobra.Command, args []string) error
 	permissions := make([]api.Permission, 0, len(group.Permissions)-1)
 	removed := false
 	for _, existingPermission := range group.Permissions {
-		if *permission == existingPermission {
+		if samePermission(*permission, existingPermission) {
 			removed = true
 			continue
 		}
@@ -637,6 +661,164 @@ func (c *cmdGroupPermissionRemove) run(cmd *cobra.Command, args []string) error
 	return resource.server.UpdateAuthGroup(resource.name, group.Writable(), eTag)
 }
 
+type cmdGroupRole struct {
+	global *cmdGlobal
+}
+
+func (c *cmdGroupRole) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("role")
+	cmd.Short = i18n.G("Manage the roles granted to groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the roles granted to groups`))
+
+	groupRoleAddCmd := cmdGroupRoleAdd{global: c.global}
+	cmd.AddCommand(groupRoleAddCmd.command())
+
+	groupRoleRemoveCmd := cmdGroupRoleRemove{global: c.global}
+	cmd.AddCommand(groupRoleRemoveCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+type cmdGroupRoleAdd struct {
+	global *cmdGlobal
+}
+
+func (c *cmdGroupRoleAdd) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<group> <entity_type> [<entity_name>] <role> [<key>=<value>...]"))
+	cmd.Short = i18n.G("Grant roles to groups on entities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Grant roles to groups on entities
+
+The group is given the entitlements of the role that apply to the type of the entity.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc auth group role add operators network lxdbr0 network-operator project=default
+    Grant the network-operator role to the operators group on network lxdbr0 in project default`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdGroupRoleAdd) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	group, eTag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	roleGrant, err := parseRoleGrantArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(group.Roles, *roleGrant) {
+		return fmt.Errorf("Group %q already has role %q on entity %q", resource.name, roleGrant.Role, roleGrant.EntityReference)
+	}
+
+	group.Roles = append(group.Roles, *roleGrant)
+	return resource.server.UpdateAuthGroup(resource.name, group.Writable(), eTag)
+}
+
+type cmdGroupRoleRemove struct {
+	global *cmdGlobal
+}
+
+func (c *cmdGroupRoleRemove) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<group> <entity_type> [<entity_name>] <role> [<key>=<value>...]"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Remove roles from groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Remove roles from groups`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdGroupRoleRemove) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing group name"))
+	}
+
+	group, eTag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	roleGrant, err := parseRoleGrantArgs(args)
+	if err != nil {
+		return err
+	}
+
+	i := slices.Index(group.Roles, *roleGrant)
+	if i < 0 {
+		return fmt.Errorf("Group %q does not have role %q on entity %q", resource.name, roleGrant.Role, roleGrant.EntityReference)
+	}
+
+	group.Roles = slices.Delete(group.Roles, i, i+1)
+	return resource.server.UpdateAuthGroup(resource.name, group.Writable(), eTag)
+}
+
+// parseRoleGrantArgs parses the `<entity_type> [<entity_name>] <role> [<key>=<value>...]` arguments of
+// `lxc auth group role add/remove`. They follow those of `lxc auth group permission add/remove`, with the role in
+// place of the entitlement.
+func parseRoleGrantArgs(args []string) (*api.RoleGrant, error) {
+	permission, err := parsePermissionArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RoleGrant{
+		Role:            permission.Entitlement,
+		EntityType:      permission.EntityType,
+		EntityReference: permission.EntityReference,
+	}, nil
+}
+
+// samePermission returns whether the two permissions grant the same entitlement on the same entity, regardless of
+// their expiry dates.
+func samePermission(a api.Permission, b api.Permission) bool {
+	return a.EntityType == b.EntityType && a.EntityReference == b.EntityReference && a.Entitlement == b.Entitlement
+}
+
 // parsePermissionArgs parses the `<entity_type> [<entity_name>] <entitlement> [<key>=<value>...]` arguments of
 // `lxc auth group permission add/remove` and returns an api.Permission that can be appended/removed from the list of
 // permissions belonging to a group.
@@ -805,20 +987,30 @@ func (c *cmdIdentity) command() *cobra.Command {
 }
 
 type cmdIdentityCreate struct {
-	global     *cmdGlobal
-	identity   *cmdIdentity
-	flagGroups []string
+	global          *cmdGlobal
+	identity        *cmdIdentity
+	flagGroups      []string
+	flagProject     string
+	flagPermissions []string
 }
 
 func (c *cmdIdentityCreate) command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("create", i18n.G("[<remote>:]<type>/<name> [<path to PEM encoded certificate>] [[--group <group_name>]]"))
+	cmd.Use = usage("create", i18n.G("[<remote>:]<type>/<name> [<path to PEM encoded certificate>] [[--group <group_name>]] [--project <project> [--permission \"<entity_type> [<entity_name>] <entitlement> [<key>=<value>...]\"]]"))
 	cmd.Short = i18n.G("Create an identity")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Create a TLS identity`))
+		`Create a TLS identity
+
+Identities created with --project are confined to the project and are only given the permissions passed with
+--permission. Project administrators can create such identities with a subset of their own permissions.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc auth identity create tls/ci --project foo --permission "project foo can_view" --permission "instance c1 can_exec project=foo"
+   Create a TLS identity confined to project "foo", which can view the project and exec into instance "c1".`))
 
 	cmd.RunE = c.run
 	cmd.Flags().StringSliceVarP(&c.flagGroups, "group", "g", []string{}, "Groups to add to the identity")
+	cmd.Flags().StringVar(&c.flagProject, "project", "", i18n.G("Project to confine the identity to")+"``")
+	cmd.Flags().StringArrayVar(&c.flagPermissions, "permission", nil, i18n.G("Permission to give to an identity confined to a project")+"``")
 
 	return cmd
 }
@@ -856,6 +1048,29 @@ func (c *cmdIdentityCreate) run(cmd *cobra.Command, args []string) error {
 	return fmt.Errorf("Cannot create identities of type %q", idType)
 }
 
+// parseDelegationFlags returns the project and permissions given with the --project and --permission flags.
+func (c *cmdIdentityCreate) parseDelegationFlags() (string, []api.Permission, error) {
+	if c.flagProject == "" {
+		if len(c.flagPermissions) > 0 {
+			return "", nil, errors.New("Permissions can only be given to identities confined to a project, use --project")
+		}
+
+		return "", nil, nil
+	}
+
+	permissions := make([]api.Permission, 0, len(c.flagPermissions))
+	for _, flagPermission := range c.flagPermissions {
+		permission, err := parsePermissionArgs(append([]string{""}, strings.Fields(flagPermission)...))
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid permission %q: %w", flagPermission, err)
+		}
+
+		permissions = append(permissions, *permission)
+	}
+
+	return c.flagProject, permissions, nil
+}
+
 // createTLSIdentity is called via `lxc auth identity create tls/<name>`.
 // It accepts the remote name, the name of the identity, and a path to a PEM encoded TLS certificate.
 // These parameters, in addition to contents of stdin, are used to compose an [api.IdentitiesTLSPost] request body.
@@ -891,6 +1106,16 @@ func (c *cmdIdentityCreate) createTLSIdentity(remote string, name string, certFi
 		}
 	}
 
+	projectName, permissions, err := c.parseDelegationFlags()
+	if err != nil {
+		return err
+	}
+
+	if projectName != "" {
+		stdinData.Project = projectName
+		stdinData.Permissions = permissions
+	}
+
 	// If the certificate argument is provided, read it and add it to the stdin data.
 	if certFilePath != "" {
 		pemEncodedX509Cert, err := os.ReadFile(certFilePath)
@@ -992,6 +1217,16 @@ func (c *cmdIdentityCreate) createBearerIdentity(remoteName string, identityName
 		}
 	}
 
+	projectName, permissions, err := c.parseDelegationFlags()
+	if err != nil {
+		return err
+	}
+
+	if projectName != "" {
+		stdinData.Project = projectName
+		stdinData.Permissions = permissions
+	}
+
 	err = client.CreateIdentityBearer(stdinData)
 	if err != nil {
 		return err
@@ -1406,6 +1641,8 @@ func (c *cmdIdentityGroup) command() *cobra.Command {
 type cmdIdentityGroupAdd struct {
 	global   *cmdGlobal
 	identity *cmdIdentity
+
+	flagExpiry string
 }
 
 func (c *cmdIdentityGroupAdd) command() *cobra.Command {
@@ -1414,6 +1651,7 @@ func (c *cmdIdentityGroupAdd) command() *cobra.Command {
 	cmd.Short = i18n.G("Add a group to an identity")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Add a group to an identity`))
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", i18n.G(`Membership expiration as a space separated list of durations in the form (\d)+(S|M|H|d|w|m|y)`)+"``")
 
 	cmd.RunE = c.run
 
@@ -1452,6 +1690,19 @@ func (c *cmdIdentityGroupAdd) run(cmd *cobra.Command, args []string) error {
 
 	identity.Groups = append(identity.Groups, args[1])
 
+	if c.flagExpiry != "" {
+		expiryDate, err := shared.GetExpiry(time.Now(), c.flagExpiry)
+		if err != nil {
+			return err
+		}
+
+		if identity.GroupExpiries == nil {
+			identity.GroupExpiries = map[string]time.Time{}
+		}
+
+		identity.GroupExpiries[args[1]] = expiryDate
+	}
+
 	return server.UpdateIdentity(method, name, identity.Writable(), eTag)
 }
 
@@ -1506,6 +1757,8 @@ func (c *cmdIdentityGroupRemove) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Identity %q is not a member of group %q", name, args[1])
 	}
 
+	delete(identity.GroupExpiries, args[1])
+
 	return server.UpdateIdentity(method, name, identity.Writable(), eTag)
 }
 
@@ -2416,3 +2669,557 @@ func (c *cmdIdentityProviderGroupGroupRemove) run(cmd *cobra.Command, args []str
 	idpGroup.Groups = groups
 	return resource.server.UpdateIdentityProviderGroup(resource.name, idpGroup.Writable(), eTag)
 }
+
+type cmdRole struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRole) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("role")
+	cmd.Short = i18n.G("Manage roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage roles
+
+Roles are named sets of entitlements that can be granted to groups on entities.`))
+
+	roleCreateCmd := cmdRoleCreate{global: c.global}
+	cmd.AddCommand(roleCreateCmd.command())
+
+	roleDeleteCmd := cmdRoleDelete{global: c.global}
+	cmd.AddCommand(roleDeleteCmd.command())
+
+	roleEditCmd := cmdRoleEdit{global: c.global}
+	cmd.AddCommand(roleEditCmd.command())
+
+	roleShowCmd := cmdRoleShow{global: c.global}
+	cmd.AddCommand(roleShowCmd.command())
+
+	roleListCmd := cmdRoleList{global: c.global}
+	cmd.AddCommand(roleListCmd.command())
+
+	roleRenameCmd := cmdRoleRename{global: c.global}
+	cmd.AddCommand(roleRenameCmd.command())
+
+	roleEntitlementCmd := cmdRoleEntitlement{global: c.global}
+	cmd.AddCommand(roleEntitlementCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdRoleCreate struct {
+	global          *cmdGlobal
+	flagDescription string
+}
+
+func (c *cmdRoleCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<role>"))
+	cmd.Short = i18n.G("Create roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create roles`))
+	cmd.Flags().StringVarP(&c.flagDescription, "description", "d", "", i18n.G("Role description")+"``")
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleCreate) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	// Create the role
+	role := api.AuthRolesPost{}
+	role.Name = resource.name
+	role.Description = c.flagDescription
+
+	err = resource.server.CreateAuthRole(role)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Role %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdRoleDelete struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<role>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete roles`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	// Delete the role
+	err = resource.server.DeleteAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Role %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdRoleEdit struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<role>"))
+	cmd.Short = i18n.G("Edit roles as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit roles as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc auth role edit <role> < role.yaml
+   Update a role using the content of role.yaml`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the role.
+### Any line starting with a '# will be ignored.
+###
+### NOTE: All role information is shown but only the description and entitlements can be modified.
+###
+### name: network-operator
+### description: Manages networks without deleting them.
+### entitlements:
+### - entity_type: network
+###   entitlement: can_view
+### - entity_type: network
+###   entitlement: can_edit
+### used_by:
+### - /1.0/auth/groups/operators
+`)
+}
+
+func (c *cmdRoleEdit) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthRolePut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateAuthRole(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	role, etag, err := resource.server.GetAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&role)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := shared.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.AuthRolePut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateAuthRole(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Could not parse role: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = shared.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+type cmdRoleList struct {
+	global     *cmdGlobal
+	flagFormat string
+}
+
+func (c *cmdRoleList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List roles`))
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	return cmd
+}
+
+func (c *cmdRoleList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List roles
+	roles, err := resource.server.GetAuthRoles()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, role := range roles {
+		data = append(data, []string{role.Name, role.Description, strconv.Itoa(len(role.Entitlements)), strconv.Itoa(len(role.UsedBy))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("ENTITLEMENTS"),
+		i18n.G("USED BY"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, roles)
+}
+
+// Rename.
+type cmdRoleRename struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<role> <new_name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename roles`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleRename) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	// Rename the role
+	err = resource.server.RenameAuthRole(resource.name, api.AuthRolePost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Role %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdRoleShow struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<role>"))
+	cmd.Short = i18n.G("Show role configurations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show role configurations`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleShow) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	// Show the role
+	role, _, err := resource.server.GetAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+type cmdRoleEntitlement struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleEntitlement) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("entitlement")
+	cmd.Short = i18n.G("Manage role entitlements")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage role entitlements`))
+
+	roleEntitlementAddCmd := cmdRoleEntitlementAdd{global: c.global}
+	cmd.AddCommand(roleEntitlementAddCmd.command())
+
+	roleEntitlementRemoveCmd := cmdRoleEntitlementRemove{global: c.global}
+	cmd.AddCommand(roleEntitlementRemoveCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+type cmdRoleEntitlementAdd struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleEntitlementAdd) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<role> <entity_type> <entitlement>"))
+	cmd.Short = i18n.G("Add entitlements to roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Add entitlements to roles`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleEntitlementAdd) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	role, eTag, err := resource.server.GetAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	entitlement := api.AuthRoleEntitlement{EntityType: args[1], Entitlement: args[2]}
+	if slices.Contains(role.Entitlements, entitlement) {
+		return fmt.Errorf("Role %q already has entitlement %q on entities of type %q", resource.name, entitlement.Entitlement, entitlement.EntityType)
+	}
+
+	role.Entitlements = append(role.Entitlements, entitlement)
+	return resource.server.UpdateAuthRole(resource.name, role.Writable(), eTag)
+}
+
+type cmdRoleEntitlementRemove struct {
+	global *cmdGlobal
+}
+
+func (c *cmdRoleEntitlementRemove) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<role> <entity_type> <entitlement>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Remove entitlements from roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Remove entitlements from roles`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdRoleEntitlementRemove) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing role name"))
+	}
+
+	role, eTag, err := resource.server.GetAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	entitlement := api.AuthRoleEntitlement{EntityType: args[1], Entitlement: args[2]}
+	i := slices.Index(role.Entitlements, entitlement)
+	if i < 0 {
+		return fmt.Errorf("Role %q does not have entitlement %q on entities of type %q", resource.name, entitlement.Entitlement, entitlement.EntityType)
+	}
+
+	role.Entitlements = slices.Delete(role.Entitlements, i, i+1)
+	return resource.server.UpdateAuthRole(resource.name, role.Writable(), eTag)
+}
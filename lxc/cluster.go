@@ -185,7 +185,16 @@ func (c *cmdClusterList) run(cmd *cobra.Command, args []string) error {
 			rolesDelimiter = ","
 		}
 
-		line := []string{member.ServerName, member.URL, strings.Join(roles, rolesDelimiter), member.Architecture, member.FailureDomain, member.Description, strings.ToUpper(member.Status), member.Message}
+		// Report members that the scheduler skips because of their overcommit policy.
+		message := member.Message
+		if member.Status == "Online" && clusterMemberHasOvercommitPolicy(member.Config) {
+			state, _, err := resource.server.GetClusterMemberState(member.ServerName)
+			if err == nil && state.Scheduler != nil && state.Scheduler.Saturated != "" {
+				message = fmt.Sprintf(i18n.G("Saturated: %s"), state.Scheduler.Saturated)
+			}
+		}
+
+		line := []string{member.ServerName, member.URL, strings.Join(roles, rolesDelimiter), member.Architecture, member.FailureDomain, member.Description, strings.ToUpper(member.Status), message}
 		data = append(data, line)
 	}
 
@@ -205,6 +214,17 @@ func (c *cmdClusterList) run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(c.flagFormat, header, data, members)
 }
 
+// clusterMemberHasOvercommitPolicy returns whether the cluster member configuration sets an overcommit policy.
+func clusterMemberHasOvercommitPolicy(config map[string]string) bool {
+	for key := range config {
+		if strings.HasPrefix(key, "scheduler.overcommit.") || strings.HasPrefix(key, "scheduler.reserved.") {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Show.
 type cmdClusterShow struct {
 	global  *cmdGlobal
@@ -889,15 +909,20 @@ type cmdClusterAdd struct {
 	global  *cmdGlobal
 	cluster *cmdCluster
 
-	flagName string
+	flagName    string
+	flagPreseed string
 }
 
 func (c *cmdClusterAdd) command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = usage("add", i18n.G("[[<remote>:]<member>]"))
 	cmd.Short = i18n.G("Request a join token for adding a cluster member")
-	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Request a join token for adding a cluster member`))
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Request a join token for adding a cluster member
+
+The join token can embed member configuration (member_config) and roles (roles) read from a YAML file,
+which the new member applies automatically when joining.`))
 	cmd.Flags().StringVar(&c.flagName, "name", "", i18n.G("Cluster member name (alternative to passing it as an argument)")+"``")
+	cmd.Flags().StringVar(&c.flagPreseed, "preseed", "", i18n.G("YAML file with the configuration to embed in the join token")+"``")
 
 	cmd.RunE = c.run
 
@@ -948,6 +973,19 @@ func (c *cmdClusterAdd) run(cmd *cobra.Command, args []string) error {
 		ServerName: resource.name,
 	}
 
+	if c.flagPreseed != "" {
+		content, err := os.ReadFile(c.flagPreseed)
+		if err != nil {
+			return err
+		}
+
+		member.Preseed = &api.ClusterMemberJoinPreseed{}
+		err = yaml.Unmarshal(content, member.Preseed)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed parsing preseed file: %w"), err)
+		}
+	}
+
 	op, err := resource.server.CreateClusterMember(member)
 	if err != nil {
 		return err
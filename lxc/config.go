@@ -1016,6 +1016,10 @@ func (c *cmdConfigUefi) command() *cobra.Command {
 	configUefiEditCmd := cmdConfigUefiEdit{global: c.global, configUefi: c}
 	cmd.AddCommand(configUefiEditCmd.command())
 
+	// Reset
+	configUefiResetCmd := cmdConfigUefiReset{global: c.global, configUefi: c}
+	cmd.AddCommand(configUefiResetCmd.command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
@@ -1253,6 +1257,52 @@ func (c *cmdConfigUefiShow) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Reset.
+type cmdConfigUefiReset struct {
+	global     *cmdGlobal
+	configUefi *cmdConfigUefi
+
+	flagRestart bool
+}
+
+// command creates a Cobra command to reset virtual machine instance UEFI variables to the firmware defaults.
+func (c *cmdConfigUefiReset) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("reset", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Reset UEFI variables for instance")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Reset UEFI variables for instance
+
+The UEFI variables (NVRAM) of the instance are replaced with the firmware defaults.`))
+
+	cmd.Flags().BoolVar(&c.flagRestart, "restart", false, i18n.G("Restart the instance if it is running"))
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+// run resets the UEFI variables of the instance.
+func (c *cmdConfigUefiReset) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New(i18n.G("Instance name must be specified"))
+	}
+
+	return resource.server.ResetInstanceUEFIVars(resource.name, c.flagRestart)
+}
+
 // Edit.
 type cmdConfigUefiEdit struct {
 	global     *cmdGlobal
@@ -31,6 +31,7 @@ type cmdConsole struct {
 	global *cmdGlobal
 
 	flagShowLog bool
+	flagHistory bool
 	flagType    string
 }
 
@@ -46,6 +47,7 @@ as well as retrieve past log entries from it.`))
 
 	cmd.RunE = c.run
 	cmd.Flags().BoolVar(&c.flagShowLog, "show-log", false, i18n.G("Retrieve the container's console log"))
+	cmd.Flags().BoolVar(&c.flagHistory, "history", false, i18n.G("Retrieve the instance's console history (including previous boots)"))
 	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -129,12 +131,12 @@ func (c *cmdConsole) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Show the current log if requested
-	if c.flagShowLog {
+	if c.flagShowLog || c.flagHistory {
 		if c.flagType != "console" {
-			return errors.New(i18n.G("The --show-log flag is only supported for by 'console' output type"))
+			return errors.New(i18n.G("The --show-log and --history flags are only supported for by 'console' output type"))
 		}
 
-		console := &lxd.InstanceConsoleLogArgs{}
+		console := &lxd.InstanceConsoleLogArgs{History: c.flagHistory}
 		log, err := d.GetInstanceConsoleLog(name, console)
 		if err != nil {
 			return err
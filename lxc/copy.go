@@ -33,6 +33,7 @@ type cmdCopy struct {
 	flagTargetProject     string
 	flagRefresh           bool
 	flagAllowInconsistent bool
+	flagRegenerate        []string
 }
 
 func (c *cmdCopy) command() *cobra.Command {
@@ -65,6 +66,7 @@ The pull transfer mode is the default as it is compatible with all LXD versions.
 	cmd.Flags().BoolVar(&c.flagNoProfiles, "no-profiles", false, i18n.G("Create the instance with no profiles applied"))
 	cmd.Flags().BoolVar(&c.flagRefresh, "refresh", false, i18n.G("Perform an incremental copy"))
 	cmd.Flags().BoolVar(&c.flagAllowInconsistent, "allow-inconsistent", false, i18n.G("Ignore copy errors for volatile files"))
+	cmd.Flags().StringSliceVar(&c.flagRegenerate, "regenerate-identity", nil, i18n.G("Identity items to regenerate in the new instance (hwaddr, machine-id, ssh-host-keys or hostname)")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -181,6 +183,10 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			return errors.New(i18n.G("--refresh can only be used with instances"))
 		}
 
+		if len(c.flagRegenerate) > 0 {
+			return errors.New(i18n.G("--regenerate-identity can only be used with instances"))
+		}
+
 		// Copy of a snapshot into a new instance
 		srcFields := strings.SplitN(sourceName, shared.SnapshotDelimiter, 2)
 		entry, _, err := source.GetInstanceSnapshot(srcFields[0], srcFields[1])
@@ -272,12 +278,13 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 	} else {
 		// Prepare the instance creation request
 		args := lxd.InstanceCopyArgs{
-			Name:              destName,
-			Live:              stateful,
-			InstanceOnly:      instanceOnly,
-			Mode:              mode,
-			Refresh:           c.flagRefresh,
-			AllowInconsistent: c.flagAllowInconsistent,
+			Name:               destName,
+			Live:               stateful,
+			InstanceOnly:       instanceOnly,
+			Mode:               mode,
+			Refresh:            c.flagRefresh,
+			AllowInconsistent:  c.flagAllowInconsistent,
+			RegenerateIdentity: c.flagRegenerate,
 		}
 
 		// Copy of an instance into a new instance
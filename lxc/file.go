@@ -1202,6 +1202,7 @@ type cmdFileMount struct {
 	flagListen   string
 	flagAuthNone bool
 	flagAuthUser string
+	flagDisk     string
 }
 
 func (c *cmdFileMount) command() *cobra.Command {
@@ -1212,12 +1213,16 @@ func (c *cmdFileMount) command() *cobra.Command {
 		`Mount files from instances`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`lxc file mount foo/root fooroot
-   To mount /root from the instance foo onto the local fooroot directory.`))
+   To mount /root from the instance foo onto the local fooroot directory.
+
+lxc file mount vm1/etc vm1etc --disk=ro
+   To mount /etc from the root disk of the stopped virtual machine vm1 read-only onto the local vm1etc directory.`))
 
 	cmd.RunE = c.run
 	cmd.Flags().StringVar(&c.flagListen, "listen", "", i18n.G("Setup SSH SFTP listener on address:port instead of mounting"))
 	cmd.Flags().BoolVar(&c.flagAuthNone, "no-auth", false, i18n.G("Disable authentication when using SSH SFTP listener"))
 	cmd.Flags().StringVar(&c.flagAuthUser, "auth-user", "", i18n.G("Set authentication user when using SSH SFTP listener"))
+	cmd.Flags().StringVar(&c.flagDisk, "disk", "", i18n.G("Access the files of a stopped virtual machine through its root disk (ro or rw)")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -1241,6 +1246,10 @@ func (c *cmdFileMount) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagDisk != "" && c.flagDisk != "ro" && c.flagDisk != "rw" {
+		return fmt.Errorf(i18n.G("Invalid disk access mode %q (must be ro or rw)"), c.flagDisk)
+	}
+
 	// Parse remote.
 	resources, err := c.global.ParseServers(args[0])
 	if err != nil {
@@ -1302,9 +1311,18 @@ func (c *cmdFileMount) run(cmd *cobra.Command, args []string) error {
 	return c.sshSFTPServer(cmd.Context(), instName, resource)
 }
 
+// sftpConn returns a connection to the instance's SFTP server, operating on its root disk if requested.
+func (c *cmdFileMount) sftpConn(server lxd.InstanceServer, instName string) (net.Conn, error) {
+	if c.flagDisk == "" {
+		return server.GetInstanceFileSFTPConn(instName)
+	}
+
+	return server.GetInstanceDiskFileSFTPConn(instName, c.flagDisk == "rw")
+}
+
 // sshfsMount mounts the instance's filesystem using sshfs by piping the instance's SFTP connection to sshfs.
 func (c *cmdFileMount) sshfsMount(ctx context.Context, resource remoteResource, instName string, instPath string, sshfsPath string, targetPath string) error {
-	sftpConn, err := resource.server.GetInstanceFileSFTPConn(instName)
+	sftpConn, err := c.sftpConn(resource.server, instName)
 	if err != nil {
 		return fmt.Errorf(i18n.G("Failed connecting to instance SFTP: %w"), err)
 	}
@@ -1540,7 +1558,7 @@ func (c *cmdFileMount) sshSFTPServer(ctx context.Context, instName string, resou
 					defer func() { _ = channel.Close() }()
 
 					// Connect to the instance's SFTP server.
-					sftpConn, err := resource.server.GetInstanceFileSFTPConn(instName)
+					sftpConn, err := c.sftpConn(resource.server, instName)
 					if err != nil {
 						fmt.Fprintf(os.Stderr, i18n.G("Failed connecting to instance SFTP for client %q: %v")+"\n", nConn.RemoteAddr(), err)
 						return
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -20,6 +22,7 @@ type cmdImport struct {
 
 	flagStorage string
 	flagDevice  []string
+	flagPart    []string
 }
 
 func (c *cmdImport) command() *cobra.Command {
@@ -30,11 +33,15 @@ func (c *cmdImport) command() *cobra.Command {
 		`Import backups of instances including their snapshots.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`lxc import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`))
+    Create a new instance using backup0.tar.gz as the source.
+
+lxc import backup0.tar.gz.000 --part backup0.tar.gz.001 --part backup0.tar.gz.002
+    Create a new instance from a backup that was split into multiple parts.`))
 
 	cmd.RunE = c.run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
 	cmd.Flags().StringArrayVarP(&c.flagDevice, "device", "d", nil, i18n.G("New key/value to apply to a specific device")+"``")
+	cmd.Flags().StringArrayVar(&c.flagPart, "part", nil, i18n.G("Additional backup file part to append, in order (can be specified multiple times)")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 1 {
@@ -106,6 +113,30 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Assemble split backups by reading the parts back to back.
+	size := fstat.Size()
+	readers := []io.Reader{file}
+	for _, part := range c.flagPart {
+		if srcFile == "-" {
+			return errors.New(i18n.G("Backup parts can't be combined with reading from stdin"))
+		}
+
+		partFile, err := os.Open(shared.HostPathFollow(part))
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = partFile.Close() }()
+
+		partStat, err := partFile.Stat()
+		if err != nil {
+			return err
+		}
+
+		size += partStat.Size()
+		readers = append(readers, partFile)
+	}
+
 	progress := cli.ProgressRenderer{
 		Format: i18n.G("Importing instance: %s"),
 		Quiet:  c.global.flagQuiet,
@@ -118,9 +149,9 @@ func (c *cmdImport) run(cmd *cobra.Command, args []string) error {
 
 	createArgs := lxd.InstanceBackupArgs{
 		BackupFile: &ioprogress.ProgressReader{
-			ReadCloser: file,
+			ReadCloser: io.NopCloser(io.MultiReader(readers...)),
 			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
+				Length: size,
 				Handler: func(percent int64, speed int64) {
 					progress.UpdateProgress(ioprogress.ProgressData{Text: strconv.FormatInt(percent, 10) + "% (" + units.GetByteSizeString(speed, 2) + "/s)"})
 				},
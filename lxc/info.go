@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -413,6 +414,23 @@ func (c *cmdInfo) remoteInfo(d lxd.InstanceServer) error {
 			}
 		}
 
+		// vGPU utilization
+		if len(resources.GPU.MdevProfiles) > 0 {
+			fmt.Print("\n" + i18n.G("vGPU profiles:") + "\n")
+
+			keys := make([]string, 0, len(resources.GPU.MdevProfiles))
+			for k := range resources.GPU.MdevProfiles {
+				keys = append(keys, k)
+			}
+
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				v := resources.GPU.MdevProfiles[k]
+				fmt.Println("  - " + fmt.Sprintf(i18n.G("%s (%s): %d used, %d available, %d total"), k, v.Name, v.Used, v.Available, v.Total))
+			}
+		}
+
 		// Network interfaces
 		if len(resources.Network.Cards) == 1 {
 			fmt.Print("\n" + i18n.G("NIC:") + "\n")
@@ -493,6 +511,10 @@ func (c *cmdInfo) instanceInfo(d lxd.InstanceServer, name string, showLog bool)
 		fmt.Printf(i18n.G("PID: %d")+"\n", inst.State.Pid)
 	}
 
+	if inst.State.AutoRestarts != 0 {
+		fmt.Printf(i18n.G("Automatic restarts: %d")+"\n", inst.State.AutoRestarts)
+	}
+
 	if shared.TimeIsSet(inst.CreatedAt) {
 		fmt.Printf(i18n.G("Created: %s")+"\n", inst.CreatedAt.Local().Format(layout))
 	}
@@ -539,6 +561,19 @@ func (c *cmdInfo) instanceInfo(d lxd.InstanceServer, name string, showLog bool)
 			cpuInfo += fmt.Sprintf("    %s: %v\n", i18n.G("CPU usage (in seconds)"), inst.State.CPU.Usage/1000000000)
 		}
 
+		if inst.State.CPU.VCPUs != 0 {
+			cpuInfo += fmt.Sprintf("    %s: %d/%d\n", i18n.G("vCPUs (current/max)"), inst.State.CPU.VCPUs, inst.State.CPU.VCPUsMax)
+		}
+
+		if len(inst.State.CPU.NUMANodes) > 0 {
+			numaNodes := make([]string, 0, len(inst.State.CPU.NUMANodes))
+			for _, node := range inst.State.CPU.NUMANodes {
+				numaNodes = append(numaNodes, strconv.FormatUint(node, 10))
+			}
+
+			cpuInfo += fmt.Sprintf("    %s: %s\n", i18n.G("NUMA nodes"), strings.Join(numaNodes, ", "))
+		}
+
 		if cpuInfo != "" {
 			fmt.Printf("  %s\n", i18n.G("CPU usage:"))
 			fmt.Print(cpuInfo)
@@ -281,6 +281,10 @@ For help with any of those, simply call them with --help.`))
 	stopCmd := cmdStop{global: &globalCmd}
 	app.AddCommand(stopCmd.command())
 
+	// template sub-command
+	templateCmd := cmdTemplate{global: &globalCmd}
+	app.AddCommand(templateCmd.command())
+
 	// version sub-command
 	versionCmd := cmdVersion{global: &globalCmd}
 	app.AddCommand(versionCmd.command())
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
@@ -41,14 +43,30 @@ func (c *cmdProject) command() *cobra.Command {
 	projectDeleteCmd := cmdProjectDelete{global: c.global, project: c}
 	cmd.AddCommand(projectDeleteCmd.command())
 
+	// Disable
+	projectDisableCmd := cmdProjectState{global: c.global, project: c, action: "disable"}
+	cmd.AddCommand(projectDisableCmd.command())
+
 	// Edit
 	projectEditCmd := cmdProjectEdit{global: c.global, project: c}
 	cmd.AddCommand(projectEditCmd.command())
 
+	// Enable
+	projectEnableCmd := cmdProjectState{global: c.global, project: c, action: "enable"}
+	cmd.AddCommand(projectEnableCmd.command())
+
+	// Export
+	projectExportCmd := cmdProjectExport{global: c.global, project: c}
+	cmd.AddCommand(projectExportCmd.command())
+
 	// Get
 	projectGetCmd := cmdProjectGet{global: c.global, project: c}
 	cmd.AddCommand(projectGetCmd.command())
 
+	// Import
+	projectImportCmd := cmdProjectImport{global: c.global, project: c}
+	cmd.AddCommand(projectImportCmd.command())
+
 	// List
 	projectListCmd := cmdProjectList{global: c.global, project: c}
 	cmd.AddCommand(projectListCmd.command())
@@ -57,6 +75,10 @@ func (c *cmdProject) command() *cobra.Command {
 	projectRenameCmd := cmdProjectRename{global: c.global, project: c}
 	cmd.AddCommand(projectRenameCmd.command())
 
+	// Share
+	projectShareCmd := cmdProjectShare{global: c.global, project: c}
+	cmd.AddCommand(projectShareCmd.command())
+
 	// Set
 	projectSetCmd := cmdProjectSet{global: c.global, project: c}
 	cmd.AddCommand(projectSetCmd.command())
@@ -650,6 +672,87 @@ func (c *cmdProjectRename) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Disable and enable.
+type cmdProjectState struct {
+	global  *cmdGlobal
+	project *cmdProject
+	action  string
+
+	flagReason string
+}
+
+func (c *cmdProjectState) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage(c.action, i18n.G("[<remote>:]<project>"))
+
+	if c.action == "disable" {
+		cmd.Short = i18n.G("Disable projects")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Disable projects
+
+Disabling a project forcefully stops all its instances and rejects any further change in it, while preserving its data.`))
+		cmd.Example = cli.FormatSection("", i18n.G(
+			`lxc project disable foo --reason "Tenant offboarded"`))
+	} else {
+		cmd.Short = i18n.G("Enable projects")
+		cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+			`Enable disabled projects`))
+	}
+
+	cmd.Flags().StringVar(&c.flagReason, "reason", "", i18n.G("Reason for the change, recorded along with it")+"``")
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("project", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectState) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing project name"))
+	}
+
+	op, err := resource.server.UpdateProjectState(resource.name, api.ProjectStatePut{Action: c.action, Reason: c.flagReason})
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		if c.action == "disable" {
+			fmt.Printf(i18n.G("Project %s disabled")+"\n", resource.name)
+		} else {
+			fmt.Printf(i18n.G("Project %s enabled")+"\n", resource.name)
+		}
+	}
+
+	return nil
+}
+
 // Set.
 type cmdProjectSet struct {
 	global  *cmdGlobal
@@ -1003,3 +1106,230 @@ func (c *cmdProjectInfo) run(cmd *cobra.Command, args []string) error {
 
 	return cli.RenderTable(c.flagFormat, header, data, projectState)
 }
+
+// Export.
+type cmdProjectExport struct {
+	global  *cmdGlobal
+	project *cmdProject
+}
+
+func (c *cmdProjectExport) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("export", i18n.G("[<remote>:]<project> [<path>]"))
+	cmd.Short = i18n.G("Export project configuration")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Export project configuration
+
+The archive holds the configuration of the project along with its profiles,
+networks, network ACLs and network zones. No instance or volume data is included.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc project export foo
+    Export the configuration of project "foo" to project-foo.tar.gz.`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("project", toComplete)
+		}
+
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectExport) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing project name"))
+	}
+
+	targetPath := "project-" + resource.name + ".tar.gz"
+	if len(args) > 1 {
+		targetPath = args[1]
+	}
+
+	content, err := resource.server.GetProjectExport(resource.name)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = content.Close() }()
+
+	target, err := os.Create(shared.HostPathFollow(targetPath))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(target, content)
+	if err != nil {
+		_ = target.Close()
+		_ = os.Remove(targetPath)
+		return fmt.Errorf(i18n.G("Failed writing project export: %w"), err)
+	}
+
+	err = target.Close()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project %s exported to %s")+"\n", resource.name, targetPath)
+	}
+
+	return nil
+}
+
+// Import.
+type cmdProjectImport struct {
+	global  *cmdGlobal
+	project *cmdProject
+}
+
+func (c *cmdProjectImport) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("import", i18n.G("[<remote>:] <path> [<project>]"))
+	cmd.Short = i18n.G("Import project configuration")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Import project configuration
+
+Re-create a project along with its profiles, networks, network ACLs and network zones
+from an archive produced by "lxc project export".`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc project import project-foo.tar.gz
+    Re-create project "foo" from project-foo.tar.gz.
+
+lxc project import other: project-foo.tar.gz bar
+    Re-create project "foo" as project "bar" on remote "other".`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		files, directive := c.global.cmpLocalFiles(toComplete, []string{".tar.gz"})
+		if len(args) == 0 {
+			remotes, _ := c.global.cmpRemotes(toComplete, ":", false, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+			return append(files, remotes...), directive
+		}
+
+		return files, directive
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectImport) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 3)
+	if exit {
+		return err
+	}
+
+	srcFilePosition := 0
+
+	// Parse remote (identify 1st argument is remote by looking for a colon at the end).
+	remote := ""
+	if len(args) > 1 && strings.HasSuffix(args[0], ":") {
+		remote = args[0]
+		srcFilePosition = 1
+	}
+
+	if len(args) <= srcFilePosition {
+		return errors.New(i18n.G("Missing archive path"))
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	export, err := c.readExport(args[srcFilePosition])
+	if err != nil {
+		return err
+	}
+
+	// Override the project name if requested.
+	if len(args) > srcFilePosition+1 {
+		export.Project.Name = args[srcFilePosition+1]
+	}
+
+	err = resource.server.ImportProject(*export)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project %s imported")+"\n", export.Project.Name)
+	}
+
+	return nil
+}
+
+// readExport reads the index of a project export archive.
+func (c *cmdProjectImport) readExport(path string) (*api.ProjectExport, error) {
+	file, err := os.Open(shared.HostPathFollow(path))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Invalid project export archive: %w"), err)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("Invalid project export archive: %w"), err)
+		}
+
+		if hdr.Name != "index.yaml" {
+			continue
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		export := api.ProjectExport{}
+		err = yaml.Unmarshal(content, &export)
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("Invalid project export archive: %w"), err)
+		}
+
+		return &export, nil
+	}
+
+	return nil, errors.New(i18n.G("Invalid project export archive: index.yaml is missing"))
+}
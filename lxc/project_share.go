@@ -0,0 +1,308 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/i18n"
+)
+
+type cmdProjectShare struct {
+	global  *cmdGlobal
+	project *cmdProject
+}
+
+func (c *cmdProjectShare) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("share")
+	cmd.Short = i18n.G("Manage the entities shared with other projects")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the entities shared with other projects
+
+Custom storage volumes, networks and images can be shared with other projects,
+either for reading only or for attaching them to instances.`))
+
+	// Add
+	projectShareAddCmd := cmdProjectShareAdd{global: c.global, projectShare: c}
+	cmd.AddCommand(projectShareAddCmd.command())
+
+	// List
+	projectShareListCmd := cmdProjectShareList{global: c.global, projectShare: c}
+	cmd.AddCommand(projectShareListCmd.command())
+
+	// Remove
+	projectShareRemoveCmd := cmdProjectShareRemove{global: c.global, projectShare: c}
+	cmd.AddCommand(projectShareRemoveCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// parseShareArgs returns the share described by the arguments of "lxc project share add/remove", without its access.
+func (c *cmdProjectShare) parseShareArgs(resource remoteResource, args []string) (*api.ProjectShare, error) {
+	entityType := entity.Type(args[1])
+	entityName := args[2]
+
+	kv := make(map[string]string)
+	for _, arg := range args[4:] {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, errors.New(i18n.G("Supplementary arguments must be of the form <key>=<value>"))
+		}
+
+		kv[k] = v
+	}
+
+	var pathArgs []string
+	switch entityType {
+	case entity.TypeStorageVolume:
+		if kv["pool"] == "" {
+			return nil, errors.New(i18n.G("Storage volumes require a supplementary storage pool argument `pool=<pool_name>`"))
+		}
+
+		pathArgs = []string{kv["pool"], "custom", entityName}
+	case entity.TypeNetwork:
+		pathArgs = []string{entityName}
+	case entity.TypeImage:
+		// Resolve image aliases and fingerprint prefixes to the full fingerprint.
+		imageCmd := cmdImage{global: c.global}
+		image, _, err := imageCmd.dereferenceAlias(resource.server.UseProject(resource.name), "", entityName)
+		if err != nil {
+			return nil, err
+		}
+
+		pathArgs = []string{image.Fingerprint}
+	default:
+		return nil, fmt.Errorf(i18n.G("Entities of type %q can't be shared"), entityType)
+	}
+
+	entityURL, err := entityType.URL(resource.name, kv["location"], pathArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ProjectShare{
+		EntityType:      string(entityType),
+		EntityReference: entityURL.String(),
+		Project:         args[3],
+	}, nil
+}
+
+// Add.
+type cmdProjectShareAdd struct {
+	global       *cmdGlobal
+	projectShare *cmdProjectShare
+
+	flagAccess string
+}
+
+func (c *cmdProjectShareAdd) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<project> <entity_type> <entity_name> <target_project> [<key>=<value>...]"))
+	cmd.Short = i18n.G("Share entities with other projects")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Share entities with other projects
+
+The entity type is one of storage_volume, network or image.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc project share add p1 storage_volume data p2 pool=default --access=attach
+    Allows instances of project p2 to attach the custom storage volume "data" of project p1.
+
+lxc project share add p1 image ubuntu p2
+    Allows project p2 to view the image with alias "ubuntu" of project p1.`))
+	cmd.Flags().StringVar(&c.flagAccess, "access", api.ProjectShareAccessReadOnly, i18n.G("Access granted to the project (read-only or attach)")+"``")
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectShareAdd) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 4, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing project name"))
+	}
+
+	share, err := c.projectShare.parseShareArgs(resource, args)
+	if err != nil {
+		return err
+	}
+
+	share.Access = c.flagAccess
+
+	shares, etag, err := resource.server.GetProjectShares(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Replace the access of an existing share.
+	shares = slices.DeleteFunc(shares, func(s api.ProjectShare) bool {
+		return s.EntityReference == share.EntityReference && s.Project == share.Project
+	})
+
+	shares = append(shares, *share)
+
+	return resource.server.UpdateProjectShares(resource.name, api.ProjectSharesPut{Shares: shares}, etag)
+}
+
+// List.
+type cmdProjectShareList struct {
+	global       *cmdGlobal
+	projectShare *cmdProjectShare
+
+	flagFormat   string
+	flagReceived bool
+}
+
+func (c *cmdProjectShareList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]<project>"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the entities shared with other projects")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the entities shared with other projects`))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+	cmd.Flags().BoolVar(&c.flagReceived, "received", false, i18n.G("List the entities of other projects shared with the project"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("project", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectShareList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing project name"))
+	}
+
+	var shares []api.ProjectShare
+	if c.flagReceived {
+		shares, err = resource.server.GetProjectReceivedShares(resource.name)
+	} else {
+		shares, _, err = resource.server.GetProjectShares(resource.name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Render the table
+	data := [][]string{}
+	for _, share := range shares {
+		data = append(data, []string{share.EntityType, share.EntityReference, share.Project, share.Access})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("ENTITY TYPE"),
+		i18n.G("ENTITY"),
+		i18n.G("PROJECT"),
+		i18n.G("ACCESS"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, shares)
+}
+
+// Remove.
+type cmdProjectShareRemove struct {
+	global       *cmdGlobal
+	projectShare *cmdProjectShare
+}
+
+func (c *cmdProjectShareRemove) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<project> <entity_type> <entity_name> <target_project> [<key>=<value>...]"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Stop sharing entities with other projects")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Stop sharing entities with other projects`))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdProjectShareRemove) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 4, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing project name"))
+	}
+
+	share, err := c.projectShare.parseShareArgs(resource, args)
+	if err != nil {
+		return err
+	}
+
+	shares, etag, err := resource.server.GetProjectShares(resource.name)
+	if err != nil {
+		return err
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(shares), func(s api.ProjectShare) bool {
+		return s.EntityReference == share.EntityReference && s.Project == share.Project
+	})
+
+	if len(remaining) == len(shares) {
+		return fmt.Errorf(i18n.G("The %s %q isn't shared with project %q"), share.EntityType, args[2], share.Project)
+	}
+
+	return resource.server.UpdateProjectShares(resource.name, api.ProjectSharesPut{Shares: remaining}, etag)
+}
@@ -16,9 +16,13 @@ import (
 
 // Rebuild.
 type cmdRebuild struct {
-	global    *cmdGlobal
-	flagEmpty bool
-	flagForce bool
+	global                 *cmdGlobal
+	flagEmpty              bool
+	flagForce              bool
+	flagReset              bool
+	flagPreserveDevices    []string
+	flagPreserveUserConfig bool
+	flagSnapshot           bool
 }
 
 func (c *cmdRebuild) command() *cobra.Command {
@@ -31,6 +35,10 @@ func (c *cmdRebuild) command() *cobra.Command {
 	cmd.RunE = c.run
 	cmd.Flags().BoolVar(&c.flagEmpty, "empty", false, i18n.G("Rebuild as an empty instance"))
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("If an instance is running, stop it and then rebuild it"))
+	cmd.Flags().BoolVar(&c.flagReset, "reset", false, i18n.G("Reset the instance configuration and devices"))
+	cmd.Flags().StringArrayVar(&c.flagPreserveDevices, "preserve-device", nil, i18n.G("Disk device to keep when resetting the instance")+"``")
+	cmd.Flags().BoolVar(&c.flagPreserveUserConfig, "preserve-user-config", false, i18n.G("Keep the user.* configuration keys when resetting the instance"))
+	cmd.Flags().BoolVar(&c.flagSnapshot, "snapshot", false, i18n.G("Snapshot the instance before rebuilding it and restore it if the rebuild fails"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 1 {
@@ -124,7 +132,11 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 
 	// Base request
 	req := api.InstanceRebuildPost{
-		Source: api.InstanceSource{},
+		Source:             api.InstanceSource{},
+		Reset:              c.flagReset,
+		PreserveDevices:    c.flagPreserveDevices,
+		PreserveUserConfig: c.flagPreserveUserConfig,
+		Snapshot:           c.flagSnapshot,
 	}
 
 	if !c.flagEmpty {
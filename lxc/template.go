@@ -0,0 +1,654 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/i18n"
+	"github.com/canonical/lxd/shared/termios"
+)
+
+type cmdTemplate struct {
+	global *cmdGlobal
+}
+
+func (c *cmdTemplate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("template")
+	cmd.Short = i18n.G("Manage instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage instance templates`))
+
+	// Create
+	templateCreateCmd := cmdTemplateCreate{global: c.global, template: c}
+	cmd.AddCommand(templateCreateCmd.command())
+
+	// Delete
+	templateDeleteCmd := cmdTemplateDelete{global: c.global, template: c}
+	cmd.AddCommand(templateDeleteCmd.command())
+
+	// Edit
+	templateEditCmd := cmdTemplateEdit{global: c.global, template: c}
+	cmd.AddCommand(templateEditCmd.command())
+
+	// Launch
+	templateLaunchCmd := cmdTemplateLaunch{global: c.global, template: c}
+	cmd.AddCommand(templateLaunchCmd.command())
+
+	// List
+	templateListCmd := cmdTemplateList{global: c.global, template: c}
+	cmd.AddCommand(templateListCmd.command())
+
+	// Rename
+	templateRenameCmd := cmdTemplateRename{global: c.global, template: c}
+	cmd.AddCommand(templateRenameCmd.command())
+
+	// Show
+	templateShowCmd := cmdTemplateShow{global: c.global, template: c}
+	cmd.AddCommand(templateShowCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdTemplateCreate struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+}
+
+func (c *cmdTemplateCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Create instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create instance templates`))
+	cmd.Example = cli.FormatSection("", i18n.G(`lxc template create t1 < template.yaml
+    Create an instance template with the definition from template.yaml`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateCreate) run(cmd *cobra.Command, args []string) error {
+	var stdinData api.InstanceTemplatePut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance template name"))
+	}
+
+	// Create the instance template
+	template := api.InstanceTemplatesPost{}
+	template.Name = resource.name
+	template.InstanceTemplatePut = stdinData
+
+	err = resource.server.CreateInstanceTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance template %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdTemplateDelete struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+}
+
+func (c *cmdTemplateDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<template>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete instance templates`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance_template", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance template name"))
+	}
+
+	// Delete the instance template
+	err = resource.server.DeleteInstanceTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance template %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdTemplateEdit struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+}
+
+func (c *cmdTemplateEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Edit instance templates as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit instance templates as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc template edit <template> < template.yaml
+    Update an instance template using the content of template.yaml`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance_template", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the instance template.
+### Any line starting with a '# will be ignored.
+###
+### An instance template holds the definition of new instances. Values can
+### refer to the template parameters as {{ parameter }}, and to the name of
+### the new instance as {{ name }}.
+###
+### An example would look like:
+### name: web
+### parameters:
+###   memory:
+###     description: Memory limit
+###     default: 2GiB
+### type: container
+### source:
+###   type: image
+###   alias: ubuntu/24.04
+### profiles:
+### - default
+### config:
+###   limits.memory: "{{ memory }}"
+###
+### Note that the name is shown but cannot be changed`)
+}
+
+func (c *cmdTemplateEdit) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance template name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.InstanceTemplatePut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateInstanceTemplate(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	template, etag, err := resource.server.GetInstanceTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&template)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := shared.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.InstanceTemplatePut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateInstanceTemplate(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = shared.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Launch.
+type cmdTemplateLaunch struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+
+	flagNoStart bool
+	flagTarget  string
+}
+
+func (c *cmdTemplateLaunch) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("launch", i18n.G("[<remote>:]<template> <instance> [<key>=<value>...]"))
+	cmd.Short = i18n.G("Create and start instances from instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create and start instances from instance templates
+
+The template parameters are set as key/value pairs.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`lxc template launch web web01 memory=4GiB
+    Create and start the instance "web01" from the "web" template with the "memory" parameter set to 4GiB`))
+
+	cmd.Flags().BoolVar(&c.flagNoStart, "no-start", false, i18n.G("Don't start the instance after creating it"))
+	cmd.Flags().StringVar(&c.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance_template", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateLaunch) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance template name"))
+	}
+
+	req := api.InstanceTemplateInstancesPost{
+		Name:       args[1],
+		Parameters: map[string]string{},
+		Start:      !c.flagNoStart,
+	}
+
+	for _, arg := range args[2:] {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf(i18n.G("Bad key=value pair: %q"), arg)
+		}
+
+		req.Parameters[key] = value
+	}
+
+	server := resource.server
+	if c.flagTarget != "" {
+		server = server.UseTarget(c.flagTarget)
+	}
+
+	if !c.global.flagQuiet {
+		if req.Start {
+			fmt.Printf(i18n.G("Launching %s")+"\n", req.Name)
+		} else {
+			fmt.Printf(i18n.G("Creating %s")+"\n", req.Name)
+		}
+	}
+
+	op, err := server.CreateInstanceFromTemplate(resource.name, req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Retrieving image: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	return nil
+}
+
+// List.
+type cmdTemplateList struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+
+	flagFormat string
+}
+
+func (c *cmdTemplateList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List instance templates`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List instance templates
+	templates, err := resource.server.GetInstanceTemplates()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, template := range templates {
+		parameters := make([]string, 0, len(template.Parameters))
+		for name := range template.Parameters {
+			parameters = append(parameters, name)
+		}
+
+		sort.Strings(parameters)
+
+		data = append(data, []string{template.Name, template.Description, template.Type, strings.Join(parameters, "\n")})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("TYPE"),
+		i18n.G("PARAMETERS"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, templates)
+}
+
+// Rename.
+type cmdTemplateRename struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+}
+
+func (c *cmdTemplateRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<template> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename instance templates`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance_template", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateRename) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance template name"))
+	}
+
+	// Rename the instance template
+	err = resource.server.RenameInstanceTemplate(resource.name, api.InstanceTemplatePost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance template %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdTemplateShow struct {
+	global   *cmdGlobal
+	template *cmdTemplate
+}
+
+func (c *cmdTemplateShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Show instance templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show instance templates`))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance_template", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdTemplateShow) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing instance template name"))
+	}
+
+	// Show the instance template
+	template, _, err := resource.server.GetInstanceTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&template)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
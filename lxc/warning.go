@@ -254,6 +254,8 @@ func (c *cmdWarningList) parseColumns(clustered bool) ([]warningColumn, error) {
 type cmdWarningAcknowledge struct {
 	global  *cmdGlobal
 	warning *cmdWarning
+
+	flagComment string
 }
 
 func (c *cmdWarningAcknowledge) command() *cobra.Command {
@@ -264,6 +266,8 @@ func (c *cmdWarningAcknowledge) command() *cobra.Command {
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Acknowledge warning`))
 
+	cmd.Flags().StringVar(&c.flagComment, "comment", "", i18n.G("Comment recorded with the acknowledgement")+"``")
+
 	cmd.RunE = c.run
 
 	return cmd
@@ -287,7 +291,11 @@ func (c *cmdWarningAcknowledge) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	warning := api.WarningPut{Status: "acknowledged"}
+	if c.flagComment != "" && !remoteServer.HasExtension("warnings_acknowledgement") {
+		return errors.New(i18n.G("The server doesn't support acknowledgement comments"))
+	}
+
+	warning := api.WarningPut{Status: "acknowledged", Comment: c.flagComment}
 
 	return remoteServer.UpdateWarning(UUID, warning, "")
 }
@@ -27,6 +27,7 @@ var api10Cmd = APIEndpoint{
 
 var api10 = []APIEndpoint{
 	api10Cmd,
+	attestationCmd,
 	execCmd,
 	eventsCmd,
 	metricsCmd,
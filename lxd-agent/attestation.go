@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// tsmReportPath is the configfs directory used to request attestation reports from the confidential computing
+// firmware (SEV-SNP or TDX) of the guest.
+const tsmReportPath = "/sys/kernel/config/tsm/report"
+
+// tsmReportDataSize is the size of the user data included in the attestation reports.
+const tsmReportDataSize = 64
+
+var attestationCmd = APIEndpoint{
+	Path: "attestation",
+
+	Get: APIEndpointAction{Handler: attestationGet},
+}
+
+func attestationGet(d *Daemon, r *http.Request) response.Response {
+	nonce, err := hex.DecodeString(r.FormValue("nonce"))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid nonce: %w", err))
+	}
+
+	if len(nonce) > tsmReportDataSize {
+		return response.BadRequest(fmt.Errorf("Nonce can't be longer than %d bytes", tsmReportDataSize))
+	}
+
+	provider, report, err := tsmReport(nonce)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, api.InstanceAttestation{
+		Provider: provider,
+		Report:   base64.StdEncoding.EncodeToString(report),
+	})
+}
+
+// tsmReport requests an attestation report including the given data through the configfs TSM interface.
+func tsmReport(data []byte) (string, []byte, error) {
+	_, err := os.Stat(tsmReportPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil, api.StatusErrorf(http.StatusNotImplemented, "Attestation reports aren't supported by the guest kernel")
+		}
+
+		return "", nil, err
+	}
+
+	reportDir, err := os.MkdirTemp(tsmReportPath, "lxd-agent-")
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed creating attestation report request: %w", err)
+	}
+
+	defer func() { _ = os.Remove(reportDir) }()
+
+	// The report data always has a fixed size, pad the nonce with zeroes.
+	inblob := make([]byte, tsmReportDataSize)
+	copy(inblob, data)
+
+	err = os.WriteFile(filepath.Join(reportDir, "inblob"), inblob, 0600)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed writing attestation report data: %w", err)
+	}
+
+	provider, err := os.ReadFile(filepath.Join(reportDir, "provider"))
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed reading attestation report provider: %w", err)
+	}
+
+	report, err := os.ReadFile(filepath.Join(reportDir, "outblob"))
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed generating attestation report: %w", err)
+	}
+
+	return strings.TrimSpace(string(provider)), report, nil
+}
@@ -84,7 +84,7 @@ func eventsSocket(d *Daemon, r *http.Request, w http.ResponseWriter) error {
 	}
 
 	// As we don't know which project we are in, subscribe to events from all projects.
-	listener, err := d.events.AddListener("", true, nil, listenerConnection, strings.Split(typeStr, ","), nil, nil, nil)
+	listener, err := d.events.AddListener("", true, nil, events.Selector{}, listenerConnection, strings.Split(typeStr, ","), nil, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -118,7 +118,13 @@ func eventsPost(d *Daemon, r *http.Request) response.Response {
 }
 
 func eventsProcess(event api.Event) {
-	// We currently only need to react to device events.
+	// Online any hotplugged vCPUs when the CPU limit changes.
+	if event.Type == "config" {
+		eventsProcessConfig(event)
+		return
+	}
+
+	// Otherwise we only need to react to device events.
 	if event.Type != "device" {
 		return
 	}
@@ -239,3 +245,63 @@ func eventsProcess(event api.Event) {
 		}
 	}
 }
+
+func eventsProcessConfig(event api.Event) {
+	type configEvent struct {
+		Key string `json:"key"`
+	}
+
+	e := configEvent{}
+	err := json.Unmarshal(event.Metadata, &e)
+	if err != nil {
+		return
+	}
+
+	if e.Key != "limits.cpu" {
+		return
+	}
+
+	// Hotplugged vCPUs may take a little while to show up.
+	for range 5 {
+		count, err := onlineCPUs()
+		if err != nil {
+			logger.Error("Failed to online hotplugged vCPUs", logger.Ctx{"err": err})
+			return
+		}
+
+		if count > 0 {
+			logger.Info("Onlined hotplugged vCPUs", logger.Ctx{"count": count})
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// onlineCPUs brings all offline CPUs online and returns how many were onlined.
+func onlineCPUs() (int, error) {
+	paths, err := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/online")
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return count, err
+		}
+
+		if strings.TrimSpace(string(content)) != "0" {
+			continue
+		}
+
+		err = os.WriteFile(path, []byte("1"), 0644)
+		if err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/instance/clone"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// identityRegenerate regenerates the identity of a copied instance as requested by LXD.
+// It returns whether the guest needs to be rebooted for the new identity to be fully applied.
+func identityRegenerate(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	config := clone.IdentityConfig{}
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		return false, fmt.Errorf("Could not parse %s: %w", path, err)
+	}
+
+	logger.Info("Regenerating instance identity", logger.Ctx{"items": config.Items})
+
+	err = clone.RegenerateIdentity("/", config.Hostname, config.Items, 0, 0)
+	if err != nil {
+		return false, err
+	}
+
+	if slices.Contains(config.Items, api.InstanceIdentityHostname) && shared.PathExists("/proc/sys/kernel/hostname") {
+		err = os.WriteFile("/proc/sys/kernel/hostname", []byte(config.Hostname), 0)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// The SSH server may already be running with the previous host keys.
+	if slices.Contains(config.Items, api.InstanceIdentitySSHHostKeys) {
+		_, _ = shared.RunCommandContext(context.TODO(), "systemctl", "try-restart", "ssh.service", "sshd.service")
+	}
+
+	// The running system keeps using the previous machine ID until the next boot.
+	return slices.Contains(config.Items, api.InstanceIdentityMachineID), nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
 
+	"github.com/canonical/lxd/lxd/instance/clone"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/lxd/util"
@@ -92,6 +93,13 @@ func (c *cmdAgent) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Regenerate the identity of a copied instance.
+	// Failures are only logged so that the agent remains available to investigate them.
+	needReboot, err := identityRegenerate(clone.IdentityConfigFile)
+	if err != nil {
+		logger.Warn("Failed regenerating instance identity", logger.Ctx{"err": err})
+	}
+
 	// Run cloud-init.
 	if shared.PathExists("/etc/cloud") && slices.Contains(files, "/var/lib/cloud/seed/nocloud-net/meta-data") {
 		logger.Info("Seeding cloud-init")
@@ -105,6 +113,10 @@ func (c *cmdAgent) Run(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		needReboot = true
+	}
+
+	if needReboot {
 		logger.Info("Rebooting")
 		_, _ = shared.RunCommandContext(context.TODO(), "reboot")
 
@@ -134,6 +146,9 @@ func (c *cmdAgent) Run(cmd *cobra.Command, args []string) error {
 	// Mount shares from host.
 	c.mountHostShares()
 
+	// Write the secrets passed by the host.
+	installSecrets()
+
 	d := newDaemon(c.global.flagLogDebug, c.global.flagLogVerbose)
 
 	err = d.init()
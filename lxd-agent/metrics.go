@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -381,9 +383,49 @@ func getMemoryMetrics() (metrics.MemoryMetrics, error) {
 		out.RSSBytes = out.MemTotalBytes - out.MemAvailableBytes
 	}
 
+	// Pressure stall information isn't available on all kernels.
+	out.PressureSomeAvg10, out.PressureFullAvg10, err = getMemoryPressure()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Debug("Failed to get memory pressure", logger.Ctx{"err": err})
+	}
+
 	return out, nil
 }
 
+// getMemoryPressure returns the "some" and "full" 10 seconds averages from /proc/pressure/memory.
+func getMemoryPressure() (some float64, full float64, err error) {
+	content, err := os.ReadFile("/proc/pressure/memory")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for line := range strings.SplitSeq(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		avg10, ok := strings.CutPrefix(fields[1], "avg10=")
+		if !ok {
+			return 0, 0, fmt.Errorf("Invalid /proc/pressure/memory content: %q", line)
+		}
+
+		value, err := strconv.ParseFloat(avg10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Failed to parse %q: %w", avg10, err)
+		}
+
+		switch fields[0] {
+		case "some":
+			some = value
+		case "full":
+			full = value
+		}
+	}
+
+	return some, full, nil
+}
+
 func getNetworkMetrics() (map[string]metrics.NetworkMetrics, error) {
 	out := map[string]metrics.NetworkMetrics{}
 
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// installSecrets checks for the existence of files under SecretConfigDir in the config share.
+// Each file is named <device>.json and contains the secret value along with its path, mode and ownership.
+// The config files are removed once the secrets are written so that they don't linger in the agent directory.
+func installSecrets() {
+	secretDirEntries, err := os.ReadDir(deviceConfig.SecretConfigDir)
+	if err != nil {
+		// Abort if configuration folder does not exist (nothing to do), otherwise log and return.
+		if os.IsNotExist(err) {
+			return
+		}
+
+		logger.Error("Could not read secret configuration directory", logger.Ctx{"err": err})
+		return
+	}
+
+	for _, f := range secretDirEntries {
+		secretFile := filepath.Join(deviceConfig.SecretConfigDir, f.Name())
+
+		err := installSecret(secretFile)
+		if err != nil {
+			logger.Error("Failed installing secret", logger.Ctx{"file": secretFile, "err": err})
+		}
+
+		_ = os.Remove(secretFile)
+	}
+}
+
+// installSecret writes the secret described by the given config file at its target path.
+func installSecret(secretFile string) error {
+	secretBytes, err := os.ReadFile(secretFile)
+	if err != nil {
+		return err
+	}
+
+	var conf deviceConfig.SecretConfig
+	err = json.Unmarshal(secretBytes, &conf)
+	if err != nil {
+		return err
+	}
+
+	l := logger.AddContext(logger.Ctx{"device": conf.DeviceName, "path": conf.Path})
+
+	if !strings.HasPrefix(conf.Path, "/") || strings.Contains(conf.Path, "..") {
+		l.Error("Invalid secret path")
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(conf.Path), 0755)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that the secret is never exposed with the wrong permissions.
+	tmpPath := conf.Path + ".lxd-tmp"
+
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(conf.Data)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	err = f.Chown(conf.UID, conf.GID)
+	if err == nil {
+		err = f.Chmod(os.FileMode(conf.Mode).Perm())
+	}
+
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	err = os.Rename(tmpPath, conf.Path)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	l.Info("Installed secret")
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/scriptlet"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// admitRequest evaluates the server and project admission scriptlets against a request creating or updating an
+// entity. The server scriptlet runs first, followed by the one of the project. Either can reject the request, or
+// change the given spec, which must be a pointer to the request body.
+func admitRequest(ctx context.Context, s *state.State, projectName string, entityType entity.Type, operation string, name string, spec any) error {
+	requestor, err := request.GetRequestor(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Notifications and requests forwarded by other cluster members after admission have already been admitted on
+	// the member that received the request, and their spec may already have been changed by the scriptlets.
+	// Requests forwarded before admission are admitted here.
+	if requestor.IsClusterNotification() || requestor.IsAdmitted() {
+		return nil
+	}
+
+	var projectConfig map[string]string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectConfig, err = cluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading config of project %q: %w", projectName, err)
+	}
+
+	req := scriptlet.AdmissionRequest{
+		Operation:  operation,
+		EntityType: string(entityType),
+		Project:    projectName,
+		Name:       name,
+		Username:   requestor.CallerUsername(),
+		Protocol:   requestor.CallerProtocol(),
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": projectName, "entityType": entityType, "name": name, "operation": operation})
+
+	scriptlets := []struct {
+		name string
+		src  string
+	}{
+		{name: "server", src: s.GlobalConfig.AdmissionScriptlet()},
+		{name: "project", src: projectConfig["admission.scriptlet"]},
+	}
+
+	for _, sl := range scriptlets {
+		if sl.src == "" {
+			continue
+		}
+
+		err = scriptlet.AdmissionRun(ctx, l, sl.name, sl.src, req, spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	requestor.SetAdmitted()
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/lxd/alerts"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// setupAlerts starts tracking the alerts of this member and recording the instance restarts needed to evaluate
+// the alerting rules.
+func (d *Daemon) setupAlerts() {
+	d.alerts = alerts.NewManager(func(projectName string, alert api.EventAlert) {
+		_ = d.events.Send(projectName, api.EventTypeAlert, alert)
+		d.alertsWarning(alert)
+	})
+
+	// The firing alerts aren't persisted, so resolve the warnings raised before the restart. They are raised
+	// again if the condition is still detected.
+	err := warnings.ResolveWarningsByLocalNodeAndType(d.db.Cluster, warningtype.StoragePoolAlmostFull)
+	if err != nil {
+		logger.Warn("Failed resolving storage pool usage warnings", logger.Ctx{"err": err})
+	}
+
+	d.internalListener.AddHandler("alerts", func(event api.Event) {
+		// The instances of the other members are handled by the members themselves.
+		if event.Location != d.serverName {
+			return
+		}
+
+		d.alerts.HandleEvent(event)
+	})
+}
+
+// alertsWarning raises the warning matching an alert when it starts firing and resolves it once the alert stops
+// firing, so that the warning clears by itself when the condition isn't detected anymore.
+func (d *Daemon) alertsWarning(alert api.EventAlert) {
+	if alert.Rule != alerts.RuleStoragePoolUsage {
+		return
+	}
+
+	u, err := url.Parse(alert.Source)
+	if err != nil {
+		return
+	}
+
+	_, _, _, pathArgs, err := entity.ParseURL(*u)
+	if err != nil || len(pathArgs) == 0 {
+		return
+	}
+
+	var poolID int64
+	err = d.db.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err = tx.GetStoragePoolID(ctx, pathArgs[0])
+		if err != nil {
+			return err
+		}
+
+		if alert.Status != api.AlertStatusFiring {
+			return nil
+		}
+
+		return tx.UpsertWarningLocalNode(ctx, "", entity.TypeStoragePool, int(poolID), warningtype.StoragePoolAlmostFull, alert.Description)
+	})
+	if err != nil {
+		logger.Warn("Failed updating storage pool usage warning", logger.Ctx{"pool": pathArgs[0], "err": err})
+		return
+	}
+
+	if alert.Status != api.AlertStatusFiring {
+		err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(d.db.Cluster, "", warningtype.StoragePoolAlmostFull, entity.TypeStoragePool, int(poolID))
+		if err != nil {
+			logger.Warn("Failed resolving storage pool usage warning", logger.Ctx{"pool": pathArgs[0], "err": err})
+		}
+	}
+}
+
+// alertsTask evaluates the built-in alerting rules.
+// The storage pool and instance rules are evaluated by each member for its own pools and instances, while the
+// cluster member rule and the rule for remote storage pools are evaluated by the leader.
+func alertsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		storagePoolUsage, clusterMemberOffline, instanceRestarts := s.GlobalConfig.Alerts()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Warn("Failed getting cluster leader for alerts", logger.Ctx{"err": err})
+			return
+		}
+
+		firing, err := alertsStoragePoolUsage(ctx, s, storagePoolUsage, leaderInfo.Leader)
+		if err != nil {
+			logger.Warn("Failed evaluating storage pool alerts", logger.Ctx{"err": err})
+		} else {
+			d.alerts.Update(alerts.RuleStoragePoolUsage, firing)
+		}
+
+		if leaderInfo.Leader && clusterMemberOffline {
+			firing, err := alertsClusterMemberOffline(ctx, s)
+			if err != nil {
+				logger.Warn("Failed evaluating cluster member alerts", logger.Ctx{"err": err})
+			} else {
+				d.alerts.Update(alerts.RuleClusterMemberOffline, firing)
+			}
+		} else if leaderInfo.Leader {
+			d.alerts.Update(alerts.RuleClusterMemberOffline, nil)
+		} else {
+			// The new leader takes over the alerts.
+			d.alerts.Forget(alerts.RuleClusterMemberOffline)
+		}
+
+		firing, err = alertsInstanceRestartLoop(ctx, s, d.alerts.InstanceRestarts(), instanceRestarts)
+		if err != nil {
+			logger.Warn("Failed evaluating instance alerts", logger.Ctx{"err": err})
+		} else {
+			d.alerts.Update(alerts.RuleInstanceRestartLoop, firing)
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+// alertsStoragePoolUsage returns an alert for each storage pool whose usage reached the threshold.
+// Remote storage pools are only evaluated on the leader, as they are shared by all members.
+func alertsStoragePoolUsage(ctx context.Context, s *state.State, threshold int64, isLeader bool) ([]alerts.Alert, error) {
+	if threshold <= 0 {
+		return nil, nil
+	}
+
+	var poolNames []string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	firing := []alerts.Alert{}
+	for _, poolName := range poolNames {
+		if !storagePools.IsAvailable(poolName) {
+			continue
+		}
+
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+		}
+
+		remote := pool.Driver().Info().Remote
+		if remote && !isLeader {
+			continue
+		}
+
+		res, err := pool.GetResources()
+		if err != nil {
+			logger.Warn("Failed getting storage pool resources", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		if res == nil || res.Space.Total == 0 {
+			continue
+		}
+
+		usage := float64(res.Space.Used) * 100 / float64(res.Space.Total)
+		if usage < float64(threshold) {
+			continue
+		}
+
+		source := entity.StoragePoolURL(poolName)
+		description := fmt.Sprintf("Storage pool %q is %.0f%% full", poolName, usage)
+		if s.ServerClustered && !remote {
+			source = source.Target(s.ServerName)
+			description = fmt.Sprintf("Storage pool %q is %.0f%% full on %q", poolName, usage, s.ServerName)
+		}
+
+		firing = append(firing, alerts.Alert{
+			Source:      source.String(),
+			Description: description,
+			Value:       usage,
+			Threshold:   float64(threshold),
+		})
+	}
+
+	return firing, nil
+}
+
+// alertsClusterMemberOffline returns an alert for each offline cluster member.
+func alertsClusterMemberOffline(ctx context.Context, s *state.State) ([]alerts.Alert, error) {
+	if !s.ServerClustered {
+		return nil, nil
+	}
+
+	var members []db.NodeInfo
+	var offlineThreshold time.Duration
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		offlineThreshold, err = tx.GetNodeOfflineThreshold(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	firing := []alerts.Alert{}
+	for _, member := range members {
+		if !member.IsOffline(offlineThreshold) {
+			continue
+		}
+
+		offlineSeconds := time.Since(member.Heartbeat).Seconds()
+
+		firing = append(firing, alerts.Alert{
+			Source:      api.NewURL().Path(version.APIVersion, "cluster", "members", member.Name).String(),
+			Description: fmt.Sprintf("Cluster member %q is offline", member.Name),
+			Value:       offlineSeconds,
+			Threshold:   offlineThreshold.Seconds(),
+		})
+	}
+
+	return firing, nil
+}
+
+// alertsInstanceRestartLoop returns an alert for each instance which restarted at least the threshold number of
+// times within the restart window. The threshold can be overridden per project.
+func alertsInstanceRestartLoop(ctx context.Context, s *state.State, restarts map[string]alerts.InstanceRestarts, threshold int64) ([]alerts.Alert, error) {
+	if len(restarts) == 0 {
+		return nil, nil
+	}
+
+	var projectsConfig map[string]map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectsConfig, err = dbCluster.GetAllProjectsConfig(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	firing := []alerts.Alert{}
+	for source, instRestarts := range restarts {
+		projectThreshold := threshold
+		if projectsConfig[instRestarts.Project]["alerts.instance_restarts"] != "" {
+			projectThreshold, err = strconv.ParseInt(projectsConfig[instRestarts.Project]["alerts.instance_restarts"], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid restart alert threshold for project %q: %w", instRestarts.Project, err)
+			}
+		}
+
+		if projectThreshold <= 0 || int64(instRestarts.Count) < projectThreshold {
+			continue
+		}
+
+		firing = append(firing, alerts.Alert{
+			Project:     instRestarts.Project,
+			Source:      source,
+			Description: fmt.Sprintf("Instance %q restarted %d times in the last %s", instRestarts.Name, instRestarts.Count, alerts.RestartWindow),
+			Value:       float64(instRestarts.Count),
+			Threshold:   float64(projectThreshold),
+		})
+	}
+
+	return firing, nil
+}
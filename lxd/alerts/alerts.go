@@ -0,0 +1,200 @@
+package alerts
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Alerting rules.
+const (
+	// RuleStoragePoolUsage fires when a storage pool is fuller than the configured percentage.
+	RuleStoragePoolUsage = "storage-pool-usage"
+
+	// RuleClusterMemberOffline fires when a cluster member is offline.
+	RuleClusterMemberOffline = "cluster-member-offline"
+
+	// RuleInstanceRestartLoop fires when an instance restarted more than the configured number of times within
+	// the restart window.
+	RuleInstanceRestartLoop = "instance-restart-loop"
+)
+
+// RestartWindow is the period over which instance restarts are counted.
+const RestartWindow = 10 * time.Minute
+
+// Alert is a condition detected by an alerting rule.
+type Alert struct {
+	// Project is the project of the entity the alert relates to, or empty if the entity isn't project specific.
+	Project string
+
+	// Source is the URL of the entity the alert relates to.
+	Source string
+
+	Description string
+	Value       float64
+	Threshold   float64
+}
+
+// firingAlert is an alert which is currently firing.
+type firingAlert struct {
+	Alert
+
+	since time.Time
+}
+
+// Manager tracks the alerts which are firing and sends an event each time an alert starts or stops firing.
+type Manager struct {
+	send func(projectName string, alert api.EventAlert)
+
+	mu       sync.Mutex
+	firing   map[string]map[string]*firingAlert // Keyed by rule and source.
+	restarts map[string]*instanceRestarts       // Keyed by instance URL.
+}
+
+// instanceRestarts records the recent restarts of an instance.
+type instanceRestarts struct {
+	project string
+	name    string
+	times   []time.Time
+}
+
+// NewManager returns a manager sending the alert events with the given function.
+func NewManager(send func(projectName string, alert api.EventAlert)) *Manager {
+	return &Manager{
+		send:     send,
+		firing:   map[string]map[string]*firingAlert{},
+		restarts: map[string]*instanceRestarts{},
+	}
+}
+
+// Update records the alerts currently detected by a rule. An event is sent for each alert that wasn't firing yet,
+// and for each previously firing alert that isn't detected anymore.
+func (m *Manager) Update(rule string, alerts []Alert) {
+	m.mu.Lock()
+
+	previous := m.firing[rule]
+	current := make(map[string]*firingAlert, len(alerts))
+	events := []alertEvent{}
+
+	for _, alert := range alerts {
+		firing, ok := previous[alert.Source]
+		if ok {
+			// Keep the time at which the alert started firing, but update the details.
+			firing.Alert = alert
+			current[alert.Source] = firing
+			continue
+		}
+
+		firing = &firingAlert{Alert: alert, since: time.Now()}
+		current[alert.Source] = firing
+		events = append(events, firing.event(rule, api.AlertStatusFiring))
+	}
+
+	for _, source := range slices.Sorted(maps.Keys(previous)) {
+		_, ok := current[source]
+		if !ok {
+			events = append(events, previous[source].event(rule, api.AlertStatusResolved))
+		}
+	}
+
+	m.firing[rule] = current
+	m.mu.Unlock()
+
+	for _, event := range events {
+		m.send(event.Project, event.EventAlert)
+	}
+}
+
+// Forget drops the alerts of a rule without sending any event, for example when the rule is evaluated by another
+// cluster member.
+func (m *Manager) Forget(rule string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.firing, rule)
+}
+
+// alertEvent is an alert event along with the project it belongs to.
+type alertEvent struct {
+	api.EventAlert
+
+	Project string
+}
+
+func (a *firingAlert) event(rule string, status string) alertEvent {
+	return alertEvent{
+		Project: a.Project,
+		EventAlert: api.EventAlert{
+			Rule:        rule,
+			Status:      status,
+			Source:      a.Source,
+			Description: a.Description,
+			Value:       a.Value,
+			Threshold:   a.Threshold,
+			FiringSince: a.since,
+		},
+	}
+}
+
+// HandleEvent records the instance restarts from the lifecycle events.
+//
+// Warn: This must not log, as it is called for logging events too.
+func (m *Manager) HandleEvent(event api.Event) {
+	if event.Type != api.EventTypeLifecycle {
+		return
+	}
+
+	lifecycle := api.EventLifecycle{}
+	err := json.Unmarshal(event.Metadata, &lifecycle)
+	if err != nil {
+		return
+	}
+
+	if lifecycle.Action != api.EventLifecycleInstanceRestarted && lifecycle.Action != api.EventLifecycleInstanceAutoRestarted {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	restarts, ok := m.restarts[lifecycle.Source]
+	if !ok {
+		restarts = &instanceRestarts{project: event.Project, name: lifecycle.Name}
+		m.restarts[lifecycle.Source] = restarts
+	}
+
+	restarts.times = append(restarts.times, event.Timestamp)
+}
+
+// InstanceRestarts returns the number of restarts of each instance within the restart window, keyed by the URL of
+// the instance. Older restarts are forgotten.
+func (m *Manager) InstanceRestarts() map[string]InstanceRestarts {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	since := time.Now().Add(-RestartWindow)
+	out := make(map[string]InstanceRestarts, len(m.restarts))
+
+	for source, restarts := range m.restarts {
+		restarts.times = slices.DeleteFunc(restarts.times, func(t time.Time) bool { return t.Before(since) })
+		if len(restarts.times) == 0 {
+			delete(m.restarts, source)
+			continue
+		}
+
+		out[source] = InstanceRestarts{Project: restarts.project, Name: restarts.name, Count: len(restarts.times)}
+	}
+
+	return out
+}
+
+// InstanceRestarts is the number of recent restarts of an instance.
+type InstanceRestarts struct {
+	Project string
+	Name    string
+	Count   int
+}
@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestManager_Update(t *testing.T) {
+	var sent []api.EventAlert
+	m := NewManager(func(projectName string, alert api.EventAlert) {
+		sent = append(sent, alert)
+	})
+
+	pool1 := Alert{Source: "/1.0/storage-pools/pool1", Value: 95, Threshold: 90}
+	pool2 := Alert{Source: "/1.0/storage-pools/pool2", Value: 91, Threshold: 90}
+
+	// New alerts fire.
+	m.Update(RuleStoragePoolUsage, []Alert{pool1, pool2})
+	assert.Len(t, sent, 2)
+	assert.Equal(t, api.AlertStatusFiring, sent[0].Status)
+	assert.Equal(t, RuleStoragePoolUsage, sent[0].Rule)
+
+	// Alerts which are still firing don't send events again.
+	sent = nil
+	pool1.Value = 97
+	m.Update(RuleStoragePoolUsage, []Alert{pool1, pool2})
+	assert.Empty(t, sent)
+
+	// Alerts which aren't detected anymore are resolved.
+	m.Update(RuleStoragePoolUsage, []Alert{pool1})
+	assert.Len(t, sent, 1)
+	assert.Equal(t, api.AlertStatusResolved, sent[0].Status)
+	assert.Equal(t, pool2.Source, sent[0].Source)
+
+	// Forgotten alerts aren't resolved.
+	sent = nil
+	m.Forget(RuleStoragePoolUsage)
+	m.Update(RuleStoragePoolUsage, nil)
+	assert.Empty(t, sent)
+}
+
+func TestManager_InstanceRestarts(t *testing.T) {
+	m := NewManager(func(projectName string, alert api.EventAlert) {})
+
+	restart := func(action string, at time.Time) {
+		metadata, _ := json.Marshal(api.EventLifecycle{Action: action, Source: "/1.0/instances/c1", Name: "c1"})
+		m.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Project: "default", Timestamp: at, Metadata: metadata})
+	}
+
+	restart(api.EventLifecycleInstanceRestarted, time.Now().Add(-2*RestartWindow))
+	restart(api.EventLifecycleInstanceRestarted, time.Now())
+	restart(api.EventLifecycleInstanceAutoRestarted, time.Now())
+	restart(api.EventLifecycleInstanceStarted, time.Now())
+
+	restarts := m.InstanceRestarts()
+	assert.Equal(t, map[string]InstanceRestarts{"/1.0/instances/c1": {Project: "default", Name: "c1", Count: 2}}, restarts)
+}
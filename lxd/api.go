@@ -412,6 +412,11 @@ func (s *lxdHTTPServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 		// Set CORS headers, unless this is an internal request.
 		setCORSHeaders(rw, req, s.d.State().GlobalConfig)
+
+		// Advertise the HTTP/3 listener to the clients of the HTTPS one.
+		if req.TLS != nil && req.ProtoMajor < 3 {
+			s.d.endpoints.QUICHeaders(rw.Header())
+		}
 	}
 
 	// OPTIONS request don't need any further processing
@@ -27,6 +27,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/node"
+	"github.com/canonical/lxd/lxd/notifications"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
@@ -51,6 +52,7 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	batchCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -60,6 +62,10 @@ var api10 = []APIEndpoint{
 	clusterNodeStateCmd,
 	clusterNodesCmd,
 	clusterCertificateCmd,
+	clusterCertificateRotateCmd,
+	clusterBackupCmd,
+	clusterDriftCmd,
+	clusterDatabaseMaintenanceCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
 	instanceBackupsCmd,
@@ -69,22 +75,42 @@ var api10 = []APIEndpoint{
 	instanceFileCmd,
 	instanceExecOutputCmd,
 	instanceExecOutputsCmd,
+	instanceSessionCmd,
+	instanceSessionsCmd,
 	instanceLogCmd,
 	instanceLogsCmd,
 	instanceMetadataCmd,
 	instanceMetadataTemplatesCmd,
 	instancesCmd,
+	instancesAutostartCmd,
 	instanceRebuildCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
 	instanceSnapshotsCmd,
 	instanceStateCmd,
+	instanceStateHistoryCmd,
+	instanceAvailabilityCmd,
+	instanceTemplateCmd,
+	instanceTemplatesCmd,
+	instanceTemplateInstancesCmd,
 	instanceUEFIVarsCmd,
+	instanceTPMCmd,
+	instanceAttestationCmd,
+	instanceCheckpointCmd,
+	instanceRescueCmd,
+	instanceExportCmd,
+	instanceWindowsSetupCmd,
+	debugProfileCmd,
+	debugProfilesCmd,
+	debugRuntimeCmd,
 	eventsCmd,
+	eventsHistoryCmd,
+	healthCmd,
 	imageAliasCmd,
 	imageAliasesCmd,
 	imageCmd,
 	imageExportCmd,
+	imageLocationsCmd,
 	imageRefreshCmd,
 	imagesCmd,
 	imageSecretCmd,
@@ -116,8 +142,15 @@ var api10 = []APIEndpoint{
 	projectCmd,
 	projectsCmd,
 	projectStateCmd,
+	projectUsageCmd,
+	projectSharesCmd,
+	projectExportCmd,
+	replicationCmd,
+	replicationPromoteCmd,
 	storagePoolCmd,
+	storagePoolRecoverCmd,
 	storagePoolResourcesCmd,
+	storagePoolScanCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
@@ -146,6 +179,8 @@ var api10 = []APIEndpoint{
 	bearerIdentityTokenCmd,
 	authGroupsCmd,
 	authGroupCmd,
+	authRolesCmd,
+	authRoleCmd,
 	identityProviderGroupsCmd,
 	identityProviderGroupCmd,
 	permissionsCmd,
@@ -980,6 +1015,13 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	bgpChanged := false
 	dnsChanged := false
 	lokiChanged := false
+	tracingChanged := false
+	webhookChanged := false
+	smtpChanged := false
+	fileSinkChanged := false
+	kafkaSinkChanged := false
+	mqttSinkChanged := false
+	syslogSinkChanged := false
 	acmeDomainChanged := false
 	acmeCAURLChanged := false
 	oidcChanged := false
@@ -1015,6 +1057,13 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 				d.taskPruneImages.Reset()
 			}
 
+		case "instances.state.history.interval":
+			fallthrough
+		case "instances.state.history.retention":
+			if !s.OS.MockMode {
+				d.taskInstanceStateHistory.Reset()
+			}
+
 		case "core.bgp_asn":
 			bgpChanged = true
 		case "loki.api.url":
@@ -1033,6 +1082,28 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			fallthrough
 		case "loki.types":
 			lokiChanged = true
+		case "core.tracing.endpoint", "core.tracing.insecure", "core.tracing.sampling":
+			tracingChanged = true
+		case "webhook.url", "webhook.secret", "webhook.types", "webhook.projects":
+			webhookChanged = true
+		case "smtp.address", "smtp.username", "smtp.password", "smtp.sender":
+			smtpChanged = true
+		case "sinks.file.path", "sinks.file.max_size", "sinks.file.max_files", "sinks.file.types", "sinks.file.projects":
+			fileSinkChanged = true
+		case "sinks.kafka.url", "sinks.kafka.topic", "sinks.kafka.types", "sinks.kafka.projects":
+			kafkaSinkChanged = true
+		case "sinks.mqtt.url", "sinks.mqtt.username", "sinks.mqtt.password", "sinks.mqtt.topic", "sinks.mqtt.types", "sinks.mqtt.projects":
+			mqttSinkChanged = true
+		case "sinks.syslog.address", "sinks.syslog.protocol", "sinks.syslog.types", "sinks.syslog.projects":
+			syslogSinkChanged = true
+		case "core.events_history_retention":
+			d.setupEventsHistory(newClusterConfig.EventsHistoryRetention())
+		case "core.slow_request_threshold", "core.slow_transaction_threshold":
+			d.setupSlowThresholds(newClusterConfig.SlowThresholds())
+		case "core.max_request_size", "core.max_upload_size", "core.request_size_overrides":
+			d.setupRequestSizeLimits(newClusterConfig.RequestSizeLimits())
+		case "core.require_etag":
+			d.setupRequireEtag(newClusterConfig.RequireEtag())
 		case "acme.ca_url":
 			acmeCAURLChanged = true
 		case "acme.domain":
@@ -1104,6 +1175,14 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	value, ok = nodeChanged["core.https_quic_address"]
+	if ok {
+		err := s.Endpoints.QUICUpdateAddress(value)
+		if err != nil {
+			return err
+		}
+	}
+
 	value, ok = nodeChanged["core.storage_buckets_address"]
 	if ok {
 		err := s.Endpoints.StorageBucketsUpdateAddress(value, s.Endpoints.NetworkCert())
@@ -1175,13 +1254,59 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	if lokiChanged {
 		lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := newClusterConfig.LokiServer()
 
-		if lokiURL == "" || lokiLoglevel == "" || len(lokiTypes) == 0 {
-			d.internalListener.RemoveHandler("loki")
-		} else {
-			err := d.setupLoki(lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes)
-			if err != nil {
-				return err
-			}
+		err := d.setupLoki(lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tracingChanged {
+		err := d.setupTracing(newClusterConfig.Tracing())
+		if err != nil {
+			return err
+		}
+	}
+
+	if webhookChanged {
+		err := d.setupWebhook(newClusterConfig.Webhook())
+		if err != nil {
+			return err
+		}
+	}
+
+	if smtpChanged {
+		address, username, password, sender := newClusterConfig.SMTP()
+		err := d.notifications.SetSMTP(notifications.SMTP{Address: address, Username: username, Password: password, Sender: sender})
+		if err != nil {
+			return err
+		}
+	}
+
+	if fileSinkChanged {
+		err := d.setupFileSink(newClusterConfig.FileSink())
+		if err != nil {
+			return err
+		}
+	}
+
+	if kafkaSinkChanged {
+		err := d.setupKafkaSink(newClusterConfig.KafkaSink())
+		if err != nil {
+			return err
+		}
+	}
+
+	if mqttSinkChanged {
+		err := d.setupMQTTSink(newClusterConfig.MQTTSink())
+		if err != nil {
+			return err
+		}
+	}
+
+	if syslogSinkChanged {
+		err := d.setupSyslogSink(newClusterConfig.SyslogSink())
+		if err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// batchMaxRequests is the maximum number of requests in a batch.
+const batchMaxRequests = 100
+
+// batchRedactedPaths are the API paths of the entities whose state, as returned by GET, omits write-only fields such
+// as the secrets of projects. Their changes can't be reverted by putting back their previous state.
+var batchRedactedPaths = []string{"/1.0/projects/"}
+
+var batchCmd = APIEndpoint{
+	Path:        "batch",
+	MetricsType: entity.TypeServer,
+
+	// Each request of the batch is authorized on its own.
+	Post: APIEndpointAction{Handler: batchPost, AccessHandler: allowAuthenticated},
+}
+
+// batchResponse is the response of a request of a batch.
+type batchResponse struct {
+	statusCode int
+	etag       string
+	location   string
+	resp       *api.Response
+}
+
+// swagger:operation POST /1.0/batch server batch_post
+//
+//	Execute a batch of requests
+//
+//	Executes an ordered list of API requests on behalf of the caller and returns the result of each of them.
+//	Each request is authorized and handled as if it was sent on its own, including the maximum size of its body.
+//
+//	In "best-effort" mode (default), all the requests are executed, regardless of the failure of any of them,
+//	and background operations are returned without waiting for them.
+//
+//	In "all-or-nothing" mode, only configuration changes (PUT and PATCH) are allowed. The state of each entity is
+//	retrieved before changing it and used as its ETag, background operations are waited for, and the batch stops at
+//	the first failure, after which the changes of the previous requests are reverted in reverse order. The requests
+//	whose changes couldn't be reverted have their revert error set. Projects can't be changed in this mode, as their
+//	secrets aren't part of their retrieved state.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: batch
+//	    description: Batch of requests
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BatchPost"
+//	responses:
+//	  "200":
+//	    description: Batch results
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Batch"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func batchPost(d *Daemon, r *http.Request) response.Response {
+	req := api.BatchPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Mode == "" {
+		req.Mode = api.BatchModeBestEffort
+	}
+
+	if !slices.Contains([]string{api.BatchModeBestEffort, api.BatchModeAllOrNothing}, req.Mode) {
+		return response.BadRequest(fmt.Errorf("Invalid batch mode %q", req.Mode))
+	}
+
+	if len(req.Requests) == 0 {
+		return response.BadRequest(errors.New("No requests in batch"))
+	}
+
+	if len(req.Requests) > batchMaxRequests {
+		return response.BadRequest(fmt.Errorf("Too many requests in batch, the maximum is %d", batchMaxRequests))
+	}
+
+	for i, batchReq := range req.Requests {
+		err := batchValidateRequest(batchReq, req.Mode)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid request %d: %w", i, err))
+		}
+
+		err = batchLimitRequestSize(batchReq, r)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Invalid request %d: %w", i, err))
+		}
+	}
+
+	// The requests are handled by the server that received the batch, so that they go through authentication,
+	// authorization and forwarding as usual.
+	server, ok := r.Context().Value(http.ServerContextKey).(*http.Server)
+	if !ok {
+		return response.InternalError(errors.New("Failed getting API server of request"))
+	}
+
+	batch := api.Batch{Success: true, Results: make([]api.BatchResult, len(req.Requests))}
+
+	// The previous state of the entities changed by the requests, for reverting them in all-or-nothing mode.
+	previous := make([]json.RawMessage, 0, len(req.Requests))
+
+	for i, batchReq := range req.Requests {
+		etag := batchReq.ETag
+
+		if req.Mode == api.BatchModeAllOrNothing {
+			current, err := batchQuery(server, r, http.MethodGet, batchReq.URL, "", nil)
+			if err != nil {
+				batch.Results[i] = batchResultFromError(err)
+				batch.Success = false
+				break
+			}
+
+			if etag == "" {
+				etag = current.etag
+			}
+
+			previous = append(previous, current.resp.Metadata)
+		}
+
+		resp, err := batchQuery(server, r, batchReq.Method, batchReq.URL, etag, batchReq.Body)
+		if err == nil && req.Mode == api.BatchModeAllOrNothing && resp.resp.Type == api.AsyncResponse {
+			err = batchWaitOperation(server, r, resp.resp.Operation)
+		}
+
+		if err != nil {
+			batch.Results[i] = batchResultFromError(err)
+			batch.Success = false
+
+			if req.Mode == api.BatchModeAllOrNothing {
+				break
+			}
+
+			continue
+		}
+
+		batch.Results[i] = api.BatchResult{
+			StatusCode: resp.statusCode,
+			Location:   resp.location,
+			Metadata:   resp.resp.Metadata,
+		}
+
+		if resp.resp.Type == api.AsyncResponse {
+			batch.Results[i].Operation = resp.resp.Operation
+		}
+	}
+
+	if batch.Success || req.Mode != api.BatchModeAllOrNothing {
+		return response.SyncResponse(true, batch)
+	}
+
+	// Revert the successful requests of the failed all-or-nothing batch in reverse order.
+	for i := len(previous) - 1; i >= 0; i-- {
+		if batch.Results[i].Error != "" {
+			continue
+		}
+
+		batchReq := req.Requests[i]
+
+		err := batchRevert(server, r, batchReq.URL, previous[i])
+		if err != nil {
+			logger.Warn("Failed reverting batch request", logger.Ctx{"method": batchReq.Method, "url": batchReq.URL, "err": err})
+			batch.Results[i].RevertError = err.Error()
+			continue
+		}
+
+		batch.Results[i].Reverted = true
+	}
+
+	return response.SyncResponse(true, batch)
+}
+
+// batchValidateRequest checks that the request can be part of a batch in the given mode.
+func batchValidateRequest(batchReq api.BatchRequest, mode string) error {
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	if mode == api.BatchModeAllOrNothing {
+		// Only configuration changes can be reverted.
+		methods = []string{http.MethodPut, http.MethodPatch}
+	}
+
+	if !slices.Contains(methods, batchReq.Method) {
+		return fmt.Errorf("Method %q isn't allowed in %q mode", batchReq.Method, mode)
+	}
+
+	u, err := url.Parse(batchReq.URL)
+	if err != nil {
+		return fmt.Errorf("Invalid URL %q: %w", batchReq.URL, err)
+	}
+
+	if u.Scheme != "" || u.Host != "" || !strings.HasPrefix(u.Path, "/1.0/") {
+		return fmt.Errorf("URL %q isn't a path of the API", batchReq.URL)
+	}
+
+	// Nested batches and streams of events can't be part of a batch.
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "/1.0/batch" || path == "/1.0/events" || strings.HasSuffix(path, "/websocket") {
+		return fmt.Errorf("URL %q isn't allowed in a batch", batchReq.URL)
+	}
+
+	// Changes to entities with write-only fields can't be reverted.
+	if mode == api.BatchModeAllOrNothing {
+		for _, prefix := range batchRedactedPaths {
+			if strings.HasPrefix(path, prefix) {
+				return fmt.Errorf("URL %q isn't allowed in %q mode as its state can't be fully retrieved", batchReq.URL, mode)
+			}
+		}
+	}
+
+	return nil
+}
+
+// batchLimitRequestSize checks that the body of a request of a batch doesn't exceed the maximum request size of its
+// endpoint, as if it was sent on its own.
+// It returns an [api.StatusError] with [http.StatusRequestEntityTooLarge] if the body is too large.
+func batchLimitRequestSize(batchReq api.BatchRequest, r *http.Request) error {
+	limits := apiRequestSizeLimits.Load()
+	if limits == nil || batchReq.Body == nil {
+		return nil
+	}
+
+	u, err := url.Parse(batchReq.URL)
+	if err != nil {
+		return err
+	}
+
+	limit := limits.limit(strings.Trim(strings.TrimPrefix(u.Path, "/1.0"), "/"), false)
+	if limit <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(batchReq.Body)
+	if err != nil {
+		return err
+	}
+
+	if int64(len(data)) > limit {
+		metrics.TrackRequestTooLarge(entity.TypeServer)
+		logger.Warn("Rejected too large API request in batch", logger.Ctx{"method": batchReq.Method, "url": batchReq.URL, "ip": r.RemoteAddr, "limit": limit})
+
+		return api.StatusErrorf(http.StatusRequestEntityTooLarge, "Request body exceeds the maximum size of %s", units.GetByteSizeStringIEC(limit, 2))
+	}
+
+	return nil
+}
+
+// batchQuery handles an API request of a batch as the caller of the batch request, and returns its response.
+// It returns an [api.StatusError] if the request failed.
+func batchQuery(server *http.Server, r *http.Request, method string, target string, etag string, body any) (*batchResponse, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the authentication details of the caller.
+	req.Host = r.Host
+	req.RemoteAddr = r.RemoteAddr
+	req.TLS = r.TLS
+	req.Header = r.Header.Clone()
+	req.Header.Del("Content-Length")
+	req.Header.Del("If-Match")
+	req.Header.Set("Content-Type", "application/json")
+
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	capture := response.NewResponseCapture(req)
+	server.Handler.ServeHTTP(capture, req)
+
+	resp, etag, err := capture.ToAPIResponse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchResponse{
+		statusCode: capture.StatusCode(),
+		etag:       etag,
+		location:   capture.Header().Get("Location"),
+		resp:       resp,
+	}, nil
+}
+
+// batchRevert restores the previous state of the entity changed by a request of a batch.
+func batchRevert(server *http.Server, r *http.Request, target string, previous json.RawMessage) error {
+	current, err := batchQuery(server, r, http.MethodGet, target, "", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := batchQuery(server, r, http.MethodPut, target, current.etag, previous)
+	if err != nil {
+		return err
+	}
+
+	if resp.resp.Type == api.AsyncResponse {
+		return batchWaitOperation(server, r, resp.resp.Operation)
+	}
+
+	return nil
+}
+
+// batchWaitOperation waits for the background operation started by a request of a batch, and returns an error if
+// the operation failed.
+func batchWaitOperation(server *http.Server, r *http.Request, operationURL string) error {
+	resp, err := batchQuery(server, r, http.MethodGet, operationURL+"/wait", "", nil)
+	if err != nil {
+		return err
+	}
+
+	op, err := resp.resp.MetadataAsOperation()
+	if err != nil {
+		return err
+	}
+
+	if op.StatusCode != api.Success {
+		return fmt.Errorf("Operation %s failed: %s", op.ID, op.Err)
+	}
+
+	return nil
+}
+
+// batchResultFromError returns the result of a request of a batch that failed with the given error.
+func batchResultFromError(err error) api.BatchResult {
+	statusCode, found := api.StatusErrorMatch(err)
+	if !found {
+		statusCode = http.StatusInternalServerError
+	}
+
+	return api.BatchResult{StatusCode: statusCode, Error: err.Error()}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// batchTestServer is an API server handling the configuration of profiles, used as the target of batch requests.
+type batchTestServer struct {
+	mu       sync.Mutex
+	profiles map[string]map[string]string
+
+	// failures holds the "<method> <profile>" requests that fail.
+	failures map[string]bool
+
+	// onGet is called after the state of a profile was returned.
+	onGet func(name string)
+}
+
+func (s *batchTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutPrefix(r.URL.Path, "/1.0/profiles/")
+	if !ok {
+		_ = response.NotFound(nil).Render(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	config, ok := s.profiles[name]
+	state := api.ProfilePut{Config: maps.Clone(config)}
+	s.mu.Unlock()
+
+	if !ok {
+		_ = response.NotFound(nil).Render(w, r)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		_ = response.SyncResponseETag(true, state, state).Render(w, r)
+
+		if s.onGet != nil {
+			s.onGet(name)
+		}
+
+		return
+	}
+
+	err := util.EtagCheck(r, state)
+	if err != nil {
+		_ = response.SmartError(err).Render(w, r)
+		return
+	}
+
+	if s.failures[r.Method+" "+name] {
+		_ = response.BadRequest(errors.New("Invalid configuration")).Render(w, r)
+		return
+	}
+
+	req := api.ProfilePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		_ = response.BadRequest(err).Render(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	if r.Method == http.MethodPatch {
+		maps.Copy(s.profiles[name], req.Config)
+	} else {
+		s.profiles[name] = req.Config
+	}
+
+	s.mu.Unlock()
+
+	_ = response.EmptySyncResponse.Render(w, r)
+}
+
+// run sends the batch and returns the status code of the response and the batch results.
+func (s *batchTestServer) run(t *testing.T, batchReq api.BatchPost) (int, api.Batch) {
+	data, err := json.Marshal(batchReq)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: s}
+	r := httptest.NewRequest(http.MethodPost, "/1.0/batch", bytes.NewReader(data))
+	r = r.WithContext(context.WithValue(r.Context(), http.ServerContextKey, server))
+
+	w := httptest.NewRecorder()
+	err = batchPost(nil, r).Render(w, r)
+	require.NoError(t, err)
+
+	resp := api.Response{}
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.NoError(t, err)
+
+	batch := api.Batch{}
+	if resp.Type == api.SyncResponse {
+		err = resp.MetadataAsStruct(&batch)
+		require.NoError(t, err)
+	}
+
+	return w.Code, batch
+}
+
+func newBatchTestServer() *batchTestServer {
+	return &batchTestServer{
+		profiles: map[string]map[string]string{
+			"a": {"limits.cpu": "1"},
+			"b": {"limits.cpu": "1"},
+			"c": {"limits.cpu": "1"},
+		},
+		failures: map[string]bool{},
+	}
+}
+
+func batchTestPatch(name string) api.BatchRequest {
+	return api.BatchRequest{
+		Method: http.MethodPatch,
+		URL:    "/1.0/profiles/" + name,
+		Body:   api.ProfilePut{Config: map[string]string{"limits.cpu": "2"}},
+	}
+}
+
+func Test_batchPost(t *testing.T) {
+	unchanged := map[string]string{"limits.cpu": "1"}
+	changed := map[string]string{"limits.cpu": "2"}
+
+	t.Run("Best effort mode continues after a failure", func(t *testing.T) {
+		s := newBatchTestServer()
+		s.failures["PATCH b"] = true
+
+		code, batch := s.run(t, api.BatchPost{Requests: []api.BatchRequest{batchTestPatch("a"), batchTestPatch("b"), batchTestPatch("c")}})
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, batch.Success)
+		assert.Equal(t, http.StatusOK, batch.Results[0].StatusCode)
+		assert.Equal(t, http.StatusBadRequest, batch.Results[1].StatusCode)
+		assert.Equal(t, http.StatusOK, batch.Results[2].StatusCode)
+		assert.Equal(t, changed, s.profiles["a"])
+		assert.Equal(t, unchanged, s.profiles["b"])
+		assert.Equal(t, changed, s.profiles["c"])
+	})
+
+	t.Run("All-or-nothing mode applies all the requests", func(t *testing.T) {
+		s := newBatchTestServer()
+
+		code, batch := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), batchTestPatch("b")}})
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, batch.Success)
+		assert.Equal(t, changed, s.profiles["a"])
+		assert.Equal(t, changed, s.profiles["b"])
+	})
+
+	t.Run("All-or-nothing mode reverts the previous requests", func(t *testing.T) {
+		s := newBatchTestServer()
+		s.failures["PATCH c"] = true
+
+		code, batch := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), batchTestPatch("b"), batchTestPatch("c")}})
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, batch.Success)
+
+		for i := range 2 {
+			assert.Equal(t, http.StatusOK, batch.Results[i].StatusCode)
+			assert.True(t, batch.Results[i].Reverted)
+			assert.Empty(t, batch.Results[i].RevertError)
+		}
+
+		assert.Equal(t, http.StatusBadRequest, batch.Results[2].StatusCode)
+		assert.NotEmpty(t, batch.Results[2].Error)
+		assert.False(t, batch.Results[2].Reverted)
+		assert.Equal(t, unchanged, s.profiles["a"])
+		assert.Equal(t, unchanged, s.profiles["b"])
+		assert.Equal(t, unchanged, s.profiles["c"])
+	})
+
+	t.Run("All-or-nothing mode stops at the first failure", func(t *testing.T) {
+		s := newBatchTestServer()
+		s.failures["PATCH b"] = true
+
+		code, batch := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), batchTestPatch("b"), batchTestPatch("c")}})
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, batch.Success)
+		assert.True(t, batch.Results[0].Reverted)
+		assert.Equal(t, http.StatusBadRequest, batch.Results[1].StatusCode)
+
+		// The request following the failure isn't executed.
+		assert.Equal(t, 0, batch.Results[2].StatusCode)
+		assert.Equal(t, unchanged, s.profiles["c"])
+	})
+
+	t.Run("All-or-nothing mode reports failed reverts", func(t *testing.T) {
+		s := newBatchTestServer()
+		s.failures["PUT a"] = true
+		s.failures["PATCH c"] = true
+
+		code, batch := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), batchTestPatch("b"), batchTestPatch("c")}})
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, batch.Success)
+
+		// The changes of the first request are still applied and the failed revert is reported.
+		assert.False(t, batch.Results[0].Reverted)
+		assert.NotEmpty(t, batch.Results[0].RevertError)
+		assert.Empty(t, batch.Results[0].Error)
+		assert.Equal(t, changed, s.profiles["a"])
+
+		// The other requests are still reverted.
+		assert.True(t, batch.Results[1].Reverted)
+		assert.Equal(t, unchanged, s.profiles["b"])
+	})
+
+	t.Run("All-or-nothing mode fails if the entity changes after being retrieved", func(t *testing.T) {
+		s := newBatchTestServer()
+		s.onGet = func(name string) {
+			if name != "b" {
+				return
+			}
+
+			s.mu.Lock()
+			s.profiles["b"] = map[string]string{"limits.cpu": "4"}
+			s.mu.Unlock()
+		}
+
+		code, batch := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), batchTestPatch("b")}})
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, batch.Success)
+		assert.True(t, batch.Results[0].Reverted)
+		assert.Equal(t, http.StatusPreconditionFailed, batch.Results[1].StatusCode)
+		assert.Equal(t, unchanged, s.profiles["a"])
+
+		// The concurrent change is kept.
+		assert.Equal(t, map[string]string{"limits.cpu": "4"}, s.profiles["b"])
+	})
+
+	t.Run("All-or-nothing mode rejects batches with other methods", func(t *testing.T) {
+		s := newBatchTestServer()
+
+		post := api.BatchRequest{Method: http.MethodPost, URL: "/1.0/profiles", Body: api.ProfilesPost{Name: "d"}}
+		code, _ := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), post}})
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		// Nothing is executed.
+		assert.Equal(t, unchanged, s.profiles["a"])
+	})
+
+	t.Run("All-or-nothing mode rejects batches changing projects", func(t *testing.T) {
+		s := newBatchTestServer()
+
+		project := api.BatchRequest{Method: http.MethodPatch, URL: "/1.0/projects/default", Body: api.ProjectPut{Config: map[string]string{"features.images": "false"}}}
+		code, _ := s.run(t, api.BatchPost{Mode: api.BatchModeAllOrNothing, Requests: []api.BatchRequest{batchTestPatch("a"), project}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, unchanged, s.profiles["a"])
+	})
+}
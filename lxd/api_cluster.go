@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"slices"
 	"sort"
@@ -56,7 +57,7 @@ import (
 	"github.com/canonical/lxd/shared/version"
 )
 
-type evacuateStopFunc func(inst instance.Instance) error
+type evacuateStopFunc func(inst instance.Instance, stateful bool) error
 type evacuateMigrateFunc func(ctx context.Context, s *state.State, inst instance.Instance, targetMemberInfo *db.NodeInfo, live bool, startInstance bool, metadata map[string]any, op *operations.Operation) error
 
 type evacuateOpts struct {
@@ -69,8 +70,14 @@ type evacuateOpts struct {
 	stopInstance    evacuateStopFunc
 	migrateInstance evacuateMigrateFunc
 	op              *operations.Operation
+	policies        map[string]string // Evacuation policies of specific instances keyed by "<project>/<instance>".
+	report          evacuateReportFunc
 }
 
+// evacuateReportFunc is called with the outcome ("migrated", "stopped", "skipped" or "failed") of the evacuation
+// of each instance.
+type evacuateReportFunc func(inst instance.Instance, policy string, status string, target string, err error)
+
 var targetGroupPrefix = "@"
 
 var clusterCmd = APIEndpoint{
@@ -106,6 +113,7 @@ var clusterNodeStateCmd = APIEndpoint{
 
 	Get:  APIEndpointAction{Handler: clusterNodeStateGet, AccessHandler: allowAuthenticated},
 	Post: APIEndpointAction{Handler: clusterNodeStatePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+	Put:  APIEndpointAction{Handler: clusterNodeStatePut, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
 }
 
 var clusterCertificateCmd = APIEndpoint{
@@ -506,6 +514,12 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 		return response.BadRequest(errors.New("No server address provided for this member"))
 	}
 
+	for _, role := range req.Roles {
+		if !slices.Contains(slices.Collect(maps.Values(db.ClusterRoles)), db.ClusterRole(role)) {
+			return response.BadRequest(fmt.Errorf("Invalid cluster member role %q", role))
+		}
+	}
+
 	localHTTPSAddress := s.LocalConfig.HTTPSAddress()
 
 	var config *node.Config
@@ -803,6 +817,24 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 				return fmt.Errorf("Failed to add new member to the default cluster group: %w", err)
 			}
 
+			// Apply the roles requested for the new member.
+			if len(req.Roles) > 0 {
+				member, err := tx.GetNodeByName(ctx, req.ServerName)
+				if err != nil {
+					return fmt.Errorf("Failed to get new member: %w", err)
+				}
+
+				roles := make([]db.ClusterRole, 0, len(req.Roles))
+				for _, role := range req.Roles {
+					roles = append(roles, db.ClusterRole(role))
+				}
+
+				err = tx.UpdateNodeRoles(member.ID, roles)
+				if err != nil {
+					return fmt.Errorf("Failed to set roles of new member: %w", err)
+				}
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -1365,6 +1397,20 @@ func clusterNodesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Join token name cannot be %q", req.ServerName))
 	}
 
+	if req.Preseed != nil {
+		for _, role := range req.Preseed.Roles {
+			if !slices.Contains(slices.Collect(maps.Values(db.ClusterRoles)), db.ClusterRole(role)) {
+				return response.BadRequest(fmt.Errorf("Invalid cluster member role %q", role))
+			}
+		}
+
+		for _, key := range req.Preseed.MemberConfig {
+			if !slices.Contains([]string{"storage-pool", "network"}, key.Entity) {
+				return response.BadRequest(fmt.Errorf("Invalid member configuration entity %q", key.Entity))
+			}
+		}
+	}
+
 	expiry, err := shared.GetExpiry(time.Now(), s.GlobalConfig.ClusterJoinTokenExpiry())
 	if err != nil {
 		return response.BadRequest(err)
@@ -1459,6 +1505,10 @@ func clusterNodesPost(d *Daemon, r *http.Request) response.Response {
 		"expiresAt":   expiry,
 	}
 
+	if req.Preseed != nil {
+		meta["preseed"] = req.Preseed
+	}
+
 	resources := map[string][]api.URL{}
 	resources["cluster"] = []api.URL{}
 
@@ -1806,6 +1856,18 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 			}
 		}
 
+		// Witness members can't host any instance.
+		if slices.Contains(newRoles, db.ClusterRoleWitness) && !slices.Contains(nodeInfo.Roles, db.ClusterRoleWitness) {
+			instances, err := dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Node: &nodeInfo.Name})
+			if err != nil {
+				return fmt.Errorf("Failed to get instances: %w", err)
+			}
+
+			if len(instances) > 0 {
+				return api.StatusErrorf(http.StatusBadRequest, "The %q role can't be added to a cluster member with instances", db.ClusterRoleWitness)
+			}
+		}
+
 		// Update the roles.
 		err = tx.UpdateNodeRoles(nodeInfo.ID, newRoles)
 		if err != nil {
@@ -1880,6 +1942,53 @@ func clusterValidateConfig(config map[string]string) error {
 		//  defaultdesc: `all`
 		//  shortdesc: Controls how instances are scheduled to run on this member
 		"scheduler.instance": validate.Optional(validate.IsOneOf("all", "group", "manual")),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.overcommit.cpu)
+		// Ratio of CPUs that can be allocated to instances to the logical CPUs of the member (minus {config:option}`cluster-cluster:scheduler.reserved.cpu`), for example `4.0`.
+		// Virtual machines without `limits.cpu` count as one CPU and containers without `limits.cpu` aren't counted.
+		// See {ref}`clustering-instance-placement-overcommit` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: unlimited
+		//  shortdesc: CPU overcommit ratio used when placing instances
+		"scheduler.overcommit.cpu": validate.Optional(cluster.ValidateOvercommitRatio),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.overcommit.memory)
+		// Ratio of memory that can be allocated to instances to the total memory of the member (minus {config:option}`cluster-cluster:scheduler.reserved.memory`), for example `1.5`.
+		// Virtual machines without `limits.memory` count as 1 GiB and containers without `limits.memory` aren't counted.
+		// See {ref}`clustering-instance-placement-overcommit` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: unlimited
+		//  shortdesc: Memory overcommit ratio used when placing instances
+		"scheduler.overcommit.memory": validate.Optional(cluster.ValidateOvercommitRatio),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.reserved.cpu)
+		// Number of logical CPUs of the member reserved for the host, which aren't considered when applying {config:option}`cluster-cluster:scheduler.overcommit.cpu`.
+		// ---
+		//  type: integer
+		//  defaultdesc: `0`
+		//  shortdesc: CPUs reserved for the host when placing instances
+		"scheduler.reserved.cpu": validate.Optional(validate.IsUint32),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.reserved.memory)
+		// Memory headroom of the member reserved for the host, for example `8GiB`.
+		// No instance is placed on the member while its available memory is below this value, and it isn't considered when applying {config:option}`cluster-cluster:scheduler.overcommit.memory`.
+		// ---
+		//  type: string
+		//  defaultdesc: `0`
+		//  shortdesc: Memory headroom reserved for the host when placing instances
+		"scheduler.reserved.memory": validate.Optional(validate.IsSize),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.rebalance)
+		// Set this option to `false` to exclude the member from the workload rebalancer.
+		// Instances are then neither moved away from nor onto this member.
+		// See {ref}`cluster-rebalance` for more information.
+		// ---
+		//  type: bool
+		//  defaultdesc: `true`
+		//  shortdesc: Whether the workload rebalancer considers this member
+		"scheduler.rebalance": validate.Optional(validate.IsBool),
 	}
 
 	for k, v := range config {
@@ -2320,13 +2429,18 @@ func updateClusterCertificate(ctx context.Context, s *state.State, gateway *clus
 
 	newClusterCertFilename := shared.VarPath(acme.ClusterCertFilename)
 
-	requestor, err := request.GetRequestor(r.Context())
-	if err != nil {
-		return err
+	notification := false
+	if r != nil {
+		requestor, err := request.GetRequestor(r.Context())
+		if err != nil {
+			return err
+		}
+
+		notification = requestor.IsClusterNotification()
 	}
 
 	// First node forwards request to all other cluster nodes
-	if r == nil || !requestor.IsClusterNotification() {
+	if !notification {
 		var err error
 
 		revert.Add(func() {
@@ -2389,7 +2503,7 @@ func updateClusterCertificate(ctx context.Context, s *state.State, gateway *clus
 				continue
 			}
 
-			client, err = cluster.Connect(r.Context(), member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+			client, err = cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
 			if err != nil {
 				return err
 			}
@@ -2402,7 +2516,7 @@ func updateClusterCertificate(ctx context.Context, s *state.State, gateway *clus
 			// When reverting the certificate, we need to connect to the cluster members using the
 			// new certificate otherwise we'll get a bad certificate error.
 			revert.Add(func() {
-				client, err := cluster.Connect(r.Context(), member.Address, newCertInfo, s.ServerCert(), true)
+				client, err := cluster.Connect(context.Background(), member.Address, newCertInfo, s.ServerCert(), true)
 				if err != nil {
 					logger.Error("Failed to connect to cluster member", logger.Ctx{"address": member.Address, "err": err})
 					return
@@ -2416,7 +2530,7 @@ func updateClusterCertificate(ctx context.Context, s *state.State, gateway *clus
 		}
 	}
 
-	err = util.WriteCert(s.OS.VarDir, "cluster", []byte(req.ClusterCertificate), []byte(req.ClusterCertificateKey), nil)
+	err := util.WriteCert(s.OS.VarDir, "cluster", []byte(req.ClusterCertificate), []byte(req.ClusterCertificateKey), nil)
 	if err != nil {
 		return err
 	}
@@ -3022,6 +3136,8 @@ func clusterNodeStateGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	memberState.Maintenance = clusterMaintenanceRender()
+
 	return response.SyncResponse(true, memberState)
 }
 
@@ -3083,29 +3199,29 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 
 	switch req.Action {
 	case "evacuate":
-		stopFunc := func(inst instance.Instance) error {
-			l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+		stopFunc, migrateFunc := evacuateClusterMemberFuncs(r)
 
-			// Get the shutdown timeout for the instance.
-			timeout := inst.ExpandedConfig()["boot.host_shutdown_timeout"]
-			val, err := strconv.Atoi(timeout)
-			if err != nil {
-				val = evacuateHostShutdownDefaultTimeout
-			}
+		return evacuateClusterMember(s, d.gateway, r, req.Mode, stopFunc, migrateFunc)
+	case "restore":
+		return restoreClusterMember(d, r, req.Mode)
+	}
 
-			// Start with a clean shutdown.
-			err = inst.Shutdown(time.Duration(val) * time.Second)
-			if err != nil {
-				l.Warn("Failed shutting down instance, forcing stop", logger.Ctx{"err": err})
+	return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+}
 
-				// Fallback to forced stop.
-				err = inst.Stop(false)
-				if err != nil && !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
-					return fmt.Errorf("Failed to stop instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
-				}
+// evacuateClusterMemberFuncs returns the functions used to stop and migrate the instances when evacuating the
+// cluster member.
+func evacuateClusterMemberFuncs(r *http.Request) (evacuateStopFunc, evacuateMigrateFunc) {
+	stopFunc := func(inst instance.Instance, stateful bool) error {
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		if stateful {
+			// Keep the runtime state of the instance so it can be resumed when restored.
+			err := inst.Stop(true)
+			if err != nil {
+				return fmt.Errorf("Failed to statefully stop instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
 			}
 
-			// Mark the instance as RUNNING in volatile so its state can be properly restored.
 			err = inst.VolatileSet(map[string]string{"volatile.last_state.power": instance.PowerStateRunning})
 			if err != nil {
 				l.Warn("Failed to set instance state to RUNNING", logger.Ctx{"err": err})
@@ -3114,54 +3230,77 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 			return nil
 		}
 
-		migrateFunc := func(ctx context.Context, s *state.State, inst instance.Instance, targetMemberInfo *db.NodeInfo, live bool, startInstance bool, metadata map[string]any, op *operations.Operation) error {
-			// Migrate the instance.
-			req := api.InstancePost{
-				Name: inst.Name(),
-				Live: live,
-			}
-
-			err := migrateInstance(r.Context(), s, inst, targetMemberInfo.Name, req, op)
-			if err != nil {
-				return fmt.Errorf("Failed to migrate instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
-			}
+		// Get the shutdown timeout for the instance.
+		timeout := inst.ExpandedConfig()["boot.host_shutdown_timeout"]
+		val, err := strconv.Atoi(timeout)
+		if err != nil {
+			val = evacuateHostShutdownDefaultTimeout
+		}
 
-			if !startInstance || live {
-				return nil
-			}
+		// Start with a clean shutdown.
+		err = inst.Shutdown(time.Duration(val) * time.Second)
+		if err != nil {
+			l.Warn("Failed shutting down instance, forcing stop", logger.Ctx{"err": err})
 
-			// Start it back up on target.
-			dest, err := cluster.Connect(r.Context(), targetMemberInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
-			if err != nil {
-				return fmt.Errorf("Failed to connect to destination %q for instance %q in project %q: %w", targetMemberInfo.Address, inst.Name(), inst.Project().Name, err)
+			// Fallback to forced stop.
+			err = inst.Stop(false)
+			if err != nil && !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+				return fmt.Errorf("Failed to stop instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
 			}
+		}
 
-			dest = dest.UseProject(inst.Project().Name)
+		// Mark the instance as RUNNING in volatile so its state can be properly restored.
+		err = inst.VolatileSet(map[string]string{"volatile.last_state.power": instance.PowerStateRunning})
+		if err != nil {
+			l.Warn("Failed to set instance state to RUNNING", logger.Ctx{"err": err})
+		}
 
-			if metadata != nil && op != nil {
-				metadata["evacuation_progress"] = fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name)
-				_ = op.UpdateMetadata(metadata)
-			}
+		return nil
+	}
 
-			startOp, err := dest.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start"}, "")
-			if err != nil {
-				return err
-			}
+	migrateFunc := func(ctx context.Context, s *state.State, inst instance.Instance, targetMemberInfo *db.NodeInfo, live bool, startInstance bool, metadata map[string]any, op *operations.Operation) error {
+		// Migrate the instance.
+		req := api.InstancePost{
+			Name: inst.Name(),
+			Live: live,
+		}
 
-			err = startOp.Wait()
-			if err != nil {
-				return err
-			}
+		err := migrateInstance(r.Context(), s, inst, targetMemberInfo.Name, req, op)
+		if err != nil {
+			return fmt.Errorf("Failed to migrate instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+		}
 
+		if !startInstance || live {
 			return nil
 		}
 
-		return evacuateClusterMember(s, d.gateway, r, req.Mode, stopFunc, migrateFunc)
-	case "restore":
-		return restoreClusterMember(d, r, req.Mode)
+		// Start it back up on target.
+		dest, err := cluster.Connect(r.Context(), targetMemberInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		if err != nil {
+			return fmt.Errorf("Failed to connect to destination %q for instance %q in project %q: %w", targetMemberInfo.Address, inst.Name(), inst.Project().Name, err)
+		}
+
+		dest = dest.UseProject(inst.Project().Name)
+
+		if metadata != nil && op != nil {
+			metadata["evacuation_progress"] = fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name)
+			_ = op.UpdateMetadata(metadata)
+		}
+
+		startOp, err := dest.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start"}, "")
+		if err != nil {
+			return err
+		}
+
+		err = startOp.Wait()
+		if err != nil {
+			return err
+		}
+
+		return nil
 	}
 
-	return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+	return stopFunc, migrateFunc
 }
 
 func internalClusterHeal(d *Daemon, r *http.Request) response.Response {
@@ -3355,28 +3494,35 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 
 	metadata := make(map[string]any)
 
+	report := opts.report
+	if report == nil {
+		report = func(inst instance.Instance, policy string, status string, target string, err error) {}
+	}
+
 	for _, inst := range opts.instances {
 		instProject := inst.Project()
 		l := logger.AddContext(logger.Ctx{"project": instProject.Name, "instance": inst.Name()})
 
-		// Check if migratable.
-		migrate, live := inst.CanMigrate()
-
-		// Apply overrides.
-		if opts.mode != "" {
-			switch opts.mode {
-			case "stop":
-				migrate = false
-				live = false
-			case "migrate":
-				migrate = true
-				live = false
-			case "live-migrate":
-				migrate = true
-				live = true
-			default:
-				return fmt.Errorf("Invalid mode: %q", opts.mode)
-			}
+		// Apply the evacuation policy, falling back to checking if migratable.
+		policy := evacuatePolicy(inst, opts)
+
+		var migrate, live, stateful bool
+		switch policy {
+		case "auto":
+			migrate, live = inst.CanMigrate()
+		case "stop":
+		case "stateful-stop":
+			stateful = true
+		case "migrate":
+			migrate = true
+		case "live-migrate":
+			migrate = true
+			live = true
+		case "skip":
+			report(inst, policy, "skipped", "", nil)
+			continue
+		default:
+			return fmt.Errorf("Invalid mode: %q", policy)
 		}
 
 		// Stop the instance if needed.
@@ -3385,14 +3531,16 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 			metadata["evacuation_progress"] = fmt.Sprintf("Stopping %q in project %q", inst.Name(), instProject.Name)
 			_ = opts.op.UpdateMetadata(metadata)
 
-			err := opts.stopInstance(inst)
+			err := opts.stopInstance(inst, stateful && !migrate)
 			if err != nil {
+				report(inst, policy, "failed", "", err)
 				return err
 			}
 		}
 
 		// If not migratable, the instance is just stopped.
 		if !migrate {
+			report(inst, policy, "stopped", "", nil)
 			continue
 		}
 
@@ -3411,9 +3559,32 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 				return err
 			}
 
+			groupConstraints, err := clusterGroupConstraints(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			candidateMembers, err = instanceGroupConstraintCandidates(groupConstraints, []int{inst.Architecture()}, inst.Type(), candidateMembers)
+			if err != nil {
+				return err
+			}
+
+			candidateMembers, err = instancePlacementCandidates(ctx, tx, instProject.Name, inst.Name(), inst.ExpandedConfig(), inst.Labels(), candidateMembers)
+			if err != nil {
+				return err
+			}
+
 			return nil
 		})
 		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				// Skip migration if the placement rules exclude all the members.
+				l.Warn("No migration target available for instance", logger.Ctx{"err": err})
+				report(inst, policy, "stopped", "", err)
+				continue
+			}
+
+			report(inst, policy, "failed", "", err)
 			return err
 		}
 
@@ -3422,8 +3593,12 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 			if api.StatusErrorCheck(err, http.StatusNotFound) {
 				// Skip migration if no target is available
 				l.Warn("No migration target available for instance")
+				report(inst, policy, "stopped", "", err)
 				continue
 			}
+
+			report(inst, policy, "failed", "", err)
+			return err
 		}
 
 		// Start migrating the instance.
@@ -3438,13 +3613,37 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 		start := isRunning || instanceShouldAutoStart(inst)
 		err = opts.migrateInstance(ctx, opts.s, inst, targetMemberInfo, live, start, metadata, opts.op)
 		if err != nil {
+			report(inst, policy, "failed", targetMemberInfo.Name, err)
 			return err
 		}
+
+		report(inst, policy, "migrated", targetMemberInfo.Name, nil)
 	}
 
 	return nil
 }
 
+// evacuatePolicy returns the evacuation policy of the instance. The policy set for the instance in the request
+// takes precedence over the mode of the request, which takes precedence over the cluster.evacuate setting of the
+// instance.
+func evacuatePolicy(inst instance.Instance, opts evacuateOpts) string {
+	policy, ok := opts.policies[inst.Project().Name+"/"+inst.Name()]
+	if ok && policy != "" {
+		return policy
+	}
+
+	if opts.mode != "" {
+		return opts.mode
+	}
+
+	policy = inst.ExpandedConfig()["cluster.evacuate"]
+	if policy == "" {
+		return "auto"
+	}
+
+	return policy
+}
+
 func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Response {
 	s := d.State()
 
@@ -3541,11 +3740,11 @@ func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Resp
 					continue
 				}
 
-				// Start the instance.
+				// Start the instance, resuming it if statefully stopped during evacuation.
 				metadata["evacuation_progress"] = fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name)
 				_ = op.UpdateMetadata(metadata)
 
-				err = inst.Start(false)
+				err = inst.Start(inst.IsStateful())
 				if err != nil {
 					return fmt.Errorf("Failed to start instance %q: %w", inst.Name(), err)
 				}
@@ -3655,6 +3854,7 @@ func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Resp
 					Description:  inst.Description(),
 					Devices:      inst.LocalDevices(),
 					Ephemeral:    inst.IsEphemeral(),
+					Labels:       inst.Labels(),
 					Profiles:     inst.Profiles(),
 					Project:      inst.Project().Name,
 					ExpiryDate:   inst.ExpiryDate(),
@@ -3743,6 +3943,11 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = clusterGroupValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		obj := dbCluster.ClusterGroup{
 			Name:        req.Name,
@@ -3750,7 +3955,7 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 			Nodes:       req.Members,
 		}
 
-		_, err := dbCluster.CreateClusterGroup(ctx, tx.Tx(), obj)
+		groupID, err := dbCluster.CreateClusterGroup(ctx, tx.Tx(), obj)
 		if err != nil {
 			if api.StatusErrorCheck(err, http.StatusConflict) {
 				return api.StatusErrorf(http.StatusConflict, "Cluster group %q already exists", req.Name)
@@ -3759,6 +3964,11 @@ func clusterGroupsPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = dbCluster.UpdateClusterGroupConfig(ctx, tx.Tx(), int(groupID), req.Config)
+		if err != nil {
+			return err
+		}
+
 		for _, node := range obj.Nodes {
 			err = tx.AddNodeToClusterGroup(ctx, obj.Name, node)
 			if err != nil {
@@ -4130,12 +4340,38 @@ func clusterGroupPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = clusterGroupValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		group, err := dbCluster.GetClusterGroup(ctx, tx.Tx(), name)
 		if err != nil {
 			return err
 		}
 
+		// Validate the ETag.
+		nodeClusterGroups, err := dbCluster.GetNodeClusterGroups(ctx, tx.Tx(), dbCluster.NodeClusterGroupFilter{GroupID: &group.ID})
+		if err != nil {
+			return err
+		}
+
+		group.Nodes = make([]string, 0, len(nodeClusterGroups))
+		for _, node := range nodeClusterGroups {
+			group.Nodes = append(group.Nodes, node.Node)
+		}
+
+		apiGroup, err := group.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		err = util.EtagCheck(r, apiGroup.Writable())
+		if err != nil {
+			return err
+		}
+
 		members, err := tx.GetClusterGroupNodes(ctx, name)
 		if err != nil {
 			return err
@@ -4167,6 +4403,11 @@ func clusterGroupPut(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = dbCluster.UpdateClusterGroupConfig(ctx, tx.Tx(), group.ID, req.Config)
+		if err != nil {
+			return err
+		}
+
 		// skipMembers is a list of members which already belong to the group.
 		skipMembers := []string{}
 
@@ -4281,8 +4522,7 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 	req := clusterGroup.Writable()
 
 	// Validate the ETag.
-	etag := []any{clusterGroup.Description, clusterGroup.Members}
-	err = util.EtagCheck(r, etag)
+	err = util.EtagCheck(r, clusterGroup.Writable())
 	if err != nil {
 		return response.PreconditionFailed(err)
 	}
@@ -4297,6 +4537,11 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 		req.Members = clusterGroup.Members
 	}
 
+	err = clusterGroupValidateConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		obj := dbCluster.ClusterGroup{
 			Name:        dbClusterGroup.Name,
@@ -4308,6 +4553,11 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = dbCluster.UpdateClusterGroupConfig(ctx, tx.Tx(), dbClusterGroup.ID, req.Config)
+		if err != nil {
+			return err
+		}
+
 		groupID, err := dbCluster.GetClusterGroupID(ctx, tx.Tx(), obj.Name)
 		if err != nil {
 			return err
@@ -4480,6 +4730,73 @@ func clusterGroupValidateName(name string) error {
 	return nil
 }
 
+// clusterGroupValidateConfig validates the configuration keys/values for cluster groups.
+func clusterGroupValidateConfig(config map[string]string) error {
+	clusterGroupConfigKeys := map[string]func(value string) error{
+		// lxdmeta:generate(entities=cluster; group=group; key=images.replication)
+		// Possible values are `lazy` and `eager`.
+		// With `eager`, the images are copied to all members of the cluster group. With `lazy`, the images are only copied to a member of the cluster group when first used, on top of {config:option}`cluster-group:images.minimal_replica`.
+		// See {ref}`cluster-groups-image-replication` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `lazy`
+		//  shortdesc: How images are replicated to the members of the cluster group
+		"images.replication": validate.Optional(validate.IsOneOf("lazy", "eager")),
+
+		// lxdmeta:generate(entities=cluster; group=group; key=images.minimal_replica)
+		// Minimum number of members of the cluster group holding a copy of each image.
+		// ---
+		//  type: integer
+		//  defaultdesc: `0`
+		//  shortdesc: Minimum number of image copies in the cluster group
+		"images.minimal_replica": validate.Optional(validate.IsUint32),
+
+		// lxdmeta:generate(entities=cluster; group=group; key=instances.architectures)
+		// Comma-separated list of architectures (for example, `x86_64,aarch64`).
+		// Only instances of these architectures are placed on the members of the cluster group, and the image variant used to create an instance targeting the cluster group is picked among them.
+		// See {ref}`cluster-groups-constraints` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Architectures of the instances allowed on the members of the cluster group
+		"instances.architectures": validate.Optional(validate.IsListOf(func(value string) error {
+			_, err := osarch.ArchitectureId(value)
+			return err
+		})),
+
+		// lxdmeta:generate(entities=cluster; group=group; key=instances.types)
+		// Comma-separated list of instance types (`container` and `virtual-machine`).
+		// Only instances of these types are placed on the members of the cluster group.
+		// See {ref}`cluster-groups-constraints` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Types of the instances allowed on the members of the cluster group
+		"instances.types": validate.Optional(validate.IsListOf(validate.IsOneOf(instancetype.Container.String(), instancetype.VM.String()))),
+	}
+
+	for k, v := range config {
+		// lxdmeta:generate(entities=cluster; group=group; key=user.*)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Free form user key/value storage
+		if strings.HasPrefix(k, "user.") {
+			continue
+		}
+
+		validator, ok := clusterGroupConfigKeys[k]
+		if !ok {
+			return fmt.Errorf("Invalid cluster group configuration key %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return fmt.Errorf("Invalid cluster group configuration key %q value", k)
+		}
+	}
+
+	return nil
+}
+
 func evacuateClusterSelectTarget(ctx context.Context, s *state.State, candidateMembers []db.NodeInfo) (*db.NodeInfo, error) {
 	var targetMemberInfo *db.NodeInfo
 	var err error
@@ -4582,7 +4899,39 @@ func autoHealClusterTask(stateFunc func() *state.State) (task.Func, task.Schedul
 	return f, task.Every(time.Minute)
 }
 
-func autoHealCluster(_ context.Context, s *state.State, offlineMembers []db.NodeInfo) error {
+// autoHealFenceTimeout is how long the fence hook can run for before being killed.
+const autoHealFenceTimeout = 5 * time.Minute
+
+// autoHealFenceMember runs the configured fence hook for the offline cluster member, if any.
+func autoHealFenceMember(ctx context.Context, s *state.State, member db.NodeInfo) error {
+	hookName := s.GlobalConfig.ClusterHealingFenceHook()
+	if hookName == "" {
+		return nil
+	}
+
+	path, err := util.HookPath(hookName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, autoHealFenceTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), "LXD_MEMBER_NAME="+member.Name, "LXD_MEMBER_ADDRESS="+member.Address)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed fencing cluster member %q with hook %q: %w (%s)", member.Name, hookName, err, strings.TrimSpace(string(out)))
+	}
+
+	logger.Info("Fenced offline cluster member", logger.Ctx{"member": member.Name, "hook": hookName})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberFenced.Event(member.Name, nil, map[string]any{"address": member.Address}))
+
+	return nil
+}
+
+func autoHealCluster(ctx context.Context, s *state.State, offlineMembers []db.NodeInfo) error {
 	logger.Info("Healing cluster instances")
 
 	dest, err := cluster.Connect(context.Background(), s.LocalConfig.ClusterAddress(), s.Endpoints.NetworkCert(), s.ServerCert(), true)
@@ -4591,11 +4940,19 @@ func autoHealCluster(_ context.Context, s *state.State, offlineMembers []db.Node
 	}
 
 	for _, member := range offlineMembers {
+		// Make sure the member can't access the shared storage anymore before starting its instances elsewhere.
+		err = autoHealFenceMember(ctx, s, member)
+		if err != nil {
+			return err
+		}
+
 		logger.Info("Healing cluster member instances", logger.Ctx{"member": member.Name})
 		_, _, err = dest.RawQuery(http.MethodPost, "/internal/cluster/heal/"+member.Name, nil, "")
 		if err != nil {
 			return fmt.Errorf("Failed evacuating cluster member %q: %w", member.Name, err)
 		}
+
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberHealed.Event(member.Name, nil, nil))
 	}
 
 	logger.Info("Done healing cluster instances")
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var clusterBackupCmd = APIEndpoint{
+	Path:        "cluster/backup",
+	MetricsType: entity.TypeClusterMember,
+
+	Get: APIEndpointAction{Handler: clusterBackupGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterBackupFiles are the files of the LXD directory included in a cluster backup, if they exist.
+var clusterBackupFiles = []string{"cluster.crt", "cluster.key", "server.crt", "server.key", "server.ca"}
+
+// swagger:operation GET /1.0/cluster/backup cluster cluster_backup_get
+//
+//	Back up the cluster configuration
+//
+//	Download a compressed tarball holding a consistent dump of the global database, a dump of the local
+//	database and the certificates of the cluster member. No instance or volume data is included.
+//
+//	The `index.yaml` file of the tarball holds a ClusterBackup. The `database/patch.global.sql` file
+//	replaces the content of the global database when loaded by a LXD server of the same version.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	responses:
+//	  "200":
+//	    description: Raw file data
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterBackupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	index := api.ClusterBackup{
+		CreatedAt:     time.Now().UTC(),
+		ServerName:    s.ServerName,
+		ServerVersion: version.Version,
+		SchemaVersion: dbCluster.SchemaVersion,
+		APIExtensions: version.APIExtensionsCount(),
+		Members:       map[string]string{},
+	}
+
+	// Dump the global database within a single transaction so that the backup is consistent.
+	var globalDump, globalPatch string
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		for _, member := range members {
+			index.Members[member.Name] = member.Address
+		}
+
+		globalDump, err = query.Dump(ctx, tx.Tx(), false)
+		if err != nil {
+			return fmt.Errorf("Failed dumping global database: %w", err)
+		}
+
+		// The schema table is left out so that the patch only applies to a database of the same version.
+		globalPatch, err = query.DumpReplace(ctx, tx.Tx(), []string{"schema"})
+		if err != nil {
+			return fmt.Errorf("Failed dumping global database: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var localDump string
+	err = query.Transaction(r.Context(), s.DB.Node.DB(), func(ctx context.Context, tx *sql.Tx) error {
+		localDump, err = query.Dump(ctx, tx, false)
+		if err != nil {
+			return fmt.Errorf("Failed dumping local database: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	indexBytes, err := yaml.Marshal(index)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	entries := map[string][]byte{
+		"index.yaml":                indexBytes,
+		"database/global.sql":       []byte(globalDump),
+		"database/patch.global.sql": []byte(globalPatch),
+		"database/local.sql":        []byte(localDump),
+	}
+
+	entryNames := []string{"index.yaml", "database/global.sql", "database/patch.global.sql", "database/local.sql"}
+	for _, name := range clusterBackupFiles {
+		content, err := os.ReadFile(shared.VarPath(name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return response.InternalError(err)
+		}
+
+		entries[name] = content
+		entryNames = append(entryNames, name)
+	}
+
+	// Wrap everything into a compressed tarball.
+	buf := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	for _, name := range entryNames {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(entries[name])),
+			ModTime: index.CreatedAt,
+		})
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		_, err = tarWriter.Write(entries[name])
+		if err != nil {
+			return response.InternalError(err)
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	filename := "cluster-backup-" + index.CreatedAt.Format("2006-01-02T150405Z") + ".tar.gz"
+	files := []response.FileResponseEntry{
+		{
+			Identifier:   filename,
+			Filename:     filename,
+			File:         bytes.NewReader(buf.Bytes()),
+			FileSize:     int64(buf.Len()),
+			FileModified: index.CreatedAt,
+		},
+	}
+
+	return response.FileResponse(files, nil)
+}
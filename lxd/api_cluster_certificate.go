@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var clusterCertificateRotateCmd = APIEndpoint{
+	Path:        "cluster/certificate/rotate",
+	MetricsType: entity.TypeClusterMember,
+
+	Post: APIEndpointAction{Handler: clusterCertificateRotatePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterCertificateVerify checks that all the online cluster members serve the expected cluster certificate.
+func clusterCertificateVerify(ctx context.Context, d *Daemon, cert *shared.CertInfo) error {
+	s := d.State()
+
+	var members []db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	localClusterAddress := s.LocalConfig.ClusterAddress()
+	for _, member := range members {
+		if member.Address == localClusterAddress {
+			if s.Endpoints.NetworkCert().Fingerprint() != cert.Fingerprint() {
+				return fmt.Errorf("Cluster member %q doesn't use the new cluster certificate", member.Name)
+			}
+
+			continue
+		}
+
+		if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			continue
+		}
+
+		// Connecting with the new certificate as the trusted server certificate fails if the member doesn't use it.
+		client, err := cluster.Connect(ctx, member.Address, cert, s.ServerCert(), true)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to cluster member %q: %w", member.Name, err)
+		}
+
+		server, _, err := client.GetServer()
+		if err != nil {
+			return fmt.Errorf("Failed verifying cluster certificate of cluster member %q: %w", member.Name, err)
+		}
+
+		if server.Environment.CertificateFingerprint != cert.Fingerprint() {
+			return fmt.Errorf("Cluster member %q doesn't use the new cluster certificate", member.Name)
+		}
+	}
+
+	return nil
+}
+
+// clusterRotateAuthSecrets rotates the cluster-wide secrets used to derive the OIDC cookie encryption keys, and
+// notifies all cluster members to reload them. The previous secret is kept, so that existing sessions are re-issued
+// with the new secret on their next request instead of being logged out.
+func clusterRotateAuthSecrets(ctx context.Context, d *Daemon) error {
+	s := d.State()
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		secrets, err := dbCluster.GetCoreAuthSecrets(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		_, err = secrets.Rotate(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	d.clearCoreAuthSecrets()
+
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	return notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		_, _, err := client.RawQuery(http.MethodPost, "/internal/auth-secrets-refresh", nil, "")
+		if err != nil {
+			return fmt.Errorf("Failed to notify cluster member %q of rotated auth secrets: %w", member.Name, err)
+		}
+
+		return nil
+	})
+}
+
+// swagger:operation POST /1.0/cluster/certificate/rotate cluster clustering_rotate_cert
+//
+//	Rotate the certificate for the cluster
+//
+//	Distributes a new cluster certificate to all cluster members, switches them over and verifies that they all
+//	use it, rolling back to the previous certificate on failure. The authentication secrets derived from it
+//	are then rotated. A new certificate is generated if none is provided.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: cluster
+//	    description: Cluster certificate rotation request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterCertificateRotatePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterCertificateRotatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	req := api.ClusterCertificateRotatePost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if (req.ClusterCertificate == "") != (req.ClusterCertificateKey == "") {
+		return response.BadRequest(errors.New("Both the cluster certificate and its key must be provided"))
+	}
+
+	// Generate a new certificate if none is provided.
+	if req.ClusterCertificate == "" {
+		certBytes, keyBytes, err := shared.GenerateMemCert(false, shared.CertOptions{AddHosts: true})
+		if err != nil {
+			return response.InternalError(fmt.Errorf("Failed generating cluster certificate: %w", err))
+		}
+
+		req.ClusterCertificate = string(certBytes)
+		req.ClusterCertificateKey = string(keyBytes)
+	}
+
+	newCert, err := shared.KeyPairFromRaw([]byte(req.ClusterCertificate), []byte(req.ClusterCertificateKey))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid cluster certificate: %w", err))
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+
+	run := func(op *operations.Operation) error {
+		ctx := context.TODO()
+		metadata := map[string]any{}
+
+		progress := func(message string) {
+			metadata["rotation_progress"] = message
+			_ = op.UpdateMetadata(metadata)
+		}
+
+		oldCertBytes, err := os.ReadFile(shared.VarPath("cluster.crt"))
+		if err != nil {
+			return err
+		}
+
+		oldKeyBytes, err := os.ReadFile(shared.VarPath("cluster.key"))
+		if err != nil {
+			return err
+		}
+
+		oldReq := api.ClusterCertificatePut{
+			ClusterCertificate:    string(oldCertBytes),
+			ClusterCertificateKey: string(oldKeyBytes),
+		}
+
+		// Distribute the new certificate and switch all members over to it. The members which were already
+		// switched are reverted if this fails.
+		progress("Distributing new cluster certificate")
+		err = updateClusterCertificate(ctx, s, d.gateway, nil, api.ClusterCertificatePut{
+			ClusterCertificate:    req.ClusterCertificate,
+			ClusterCertificateKey: req.ClusterCertificateKey,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed distributing new cluster certificate: %w", err)
+		}
+
+		progress("Verifying new cluster certificate")
+		err = clusterCertificateVerify(ctx, d, newCert)
+		if err != nil {
+			progress("Rolling back cluster certificate")
+			rollbackErr := updateClusterCertificate(ctx, s, d.gateway, nil, oldReq)
+			if rollbackErr != nil {
+				logger.Error("Failed rolling back cluster certificate", logger.Ctx{"err": rollbackErr})
+			}
+
+			return fmt.Errorf("Failed verifying new cluster certificate: %w", err)
+		}
+
+		progress("Rotating authentication secrets")
+		err = clusterRotateAuthSecrets(ctx, d)
+		if err != nil {
+			return fmt.Errorf("Failed rotating authentication secrets: %w", err)
+		}
+
+		delete(metadata, "rotation_progress")
+		metadata["fingerprint"] = newCert.Fingerprint()
+		_ = op.UpdateMetadata(metadata)
+
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterCertificateUpdated.Event("certificate", requestor, map[string]any{"fingerprint": newCert.Fingerprint()}))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, "", operations.OperationClassTask, operationtype.ClusterCertificateRotate, nil, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var clusterDatabaseMaintenanceCmd = APIEndpoint{
+	Path:        "cluster/database/maintenance",
+	MetricsType: entity.TypeClusterMember,
+
+	Post: APIEndpointAction{Handler: clusterDatabaseMaintenancePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterDatabaseMaintenanceTasks are the supported cluster database maintenance tasks.
+var clusterDatabaseMaintenanceTasks = []string{"integrity-check", "vacuum", "report"}
+
+// clusterDatabasePages retrieves the page size, page count and free page count of the cluster database.
+func clusterDatabasePages(ctx context.Context, tx *sql.Tx, result *api.ClusterDatabaseMaintenance) error {
+	pragmas := map[string]*int64{
+		"page_size":      &result.PageSize,
+		"page_count":     &result.PageCount,
+		"freelist_count": &result.FreePages,
+	}
+
+	for pragma, value := range pragmas {
+		err := tx.QueryRowContext(ctx, "PRAGMA "+pragma).Scan(value)
+		if err != nil {
+			return fmt.Errorf("Failed getting %q of cluster database: %w", pragma, err)
+		}
+	}
+
+	result.Size = result.PageSize * result.PageCount
+
+	return nil
+}
+
+// swagger:operation POST /1.0/cluster/database/maintenance cluster cluster_database_maintenance_post
+//
+//	Run maintenance tasks on the cluster database
+//
+//	Runs integrity checks, vacuum and size reporting on the cluster database as a background operation.
+//	The results are returned in the "maintenance" field of the operation metadata.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: maintenance
+//	    description: Maintenance tasks
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterDatabaseMaintenancePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterDatabaseMaintenancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ClusterDatabaseMaintenancePost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if len(req.Tasks) == 0 {
+		req.Tasks = []string{"report"}
+	}
+
+	for _, task := range req.Tasks {
+		if !slices.Contains(clusterDatabaseMaintenanceTasks, task) {
+			return response.BadRequest(fmt.Errorf("Invalid cluster database maintenance task %q", task))
+		}
+	}
+
+	run := func(op *operations.Operation) error {
+		ctx := context.TODO()
+		result := api.ClusterDatabaseMaintenance{}
+		metadata := map[string]any{}
+
+		progress := func(message string) {
+			metadata["maintenance_progress"] = message
+			_ = op.UpdateMetadata(metadata)
+		}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return clusterDatabasePages(ctx, tx.Tx(), &result)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, task := range req.Tasks {
+			switch task {
+			case "integrity-check":
+				progress("Checking cluster database integrity")
+
+				err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+					var err error
+					result.Integrity, err = query.SelectStrings(ctx, tx.Tx(), "PRAGMA integrity_check")
+					return err
+				})
+				if err != nil {
+					return fmt.Errorf("Failed checking cluster database integrity: %w", err)
+				}
+
+				if !slices.Equal(result.Integrity, []string{"ok"}) {
+					logger.Warn("Cluster database integrity check found problems", logger.Ctx{"problems": result.Integrity})
+				}
+
+			case "vacuum":
+				progress("Vacuuming cluster database")
+
+				sizeBefore := result.Size
+
+				// VACUUM can't run within a transaction.
+				_, err = s.DB.Cluster.DB().ExecContext(ctx, "VACUUM")
+				if err != nil {
+					return fmt.Errorf("Failed vacuuming cluster database: %w", err)
+				}
+
+				err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+					return clusterDatabasePages(ctx, tx.Tx(), &result)
+				})
+				if err != nil {
+					return err
+				}
+
+				result.SizeReclaimed = max(sizeBefore-result.Size, 0)
+
+			case "report":
+				progress("Reporting cluster database usage")
+
+				err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+					counts, err := query.CountAll(ctx, tx.Tx())
+					if err != nil {
+						return fmt.Errorf("Failed counting cluster database rows: %w", err)
+					}
+
+					result.Tables = make(map[string]int64, len(counts))
+					for table, count := range counts {
+						result.Tables[table] = int64(count)
+					}
+
+					return clusterDatabasePages(ctx, tx.Tx(), &result)
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		delete(metadata, "maintenance_progress")
+		metadata["maintenance"] = result
+		_ = op.UpdateMetadata(metadata)
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, "", operations.OperationClassTask, operationtype.ClusterDatabaseMaintenance, nil, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var clusterDriftCmd = APIEndpoint{
+	Path:        "cluster/drift",
+	MetricsType: entity.TypeClusterMember,
+
+	Get: APIEndpointAction{Handler: clusterDriftGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanView)},
+}
+
+// clusterDriftMemberState retrieves the effective state of the cluster member, including the local status of the
+// managed networks on it.
+func clusterDriftMemberState(ctx context.Context, s *state.State, member db.NodeInfo) (*cluster.MemberDriftState, error) {
+	client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	client = client.UseTarget(member.Name)
+
+	server, _, err := client.GetServer()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting server environment: %w", err)
+	}
+
+	networks, err := client.GetNetworksAllProjects()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting networks: %w", err)
+	}
+
+	state := &cluster.MemberDriftState{Environment: server.Environment}
+	for _, network := range networks {
+		if !network.Managed {
+			continue
+		}
+
+		// Only a targeted request returns the status of the network on the member.
+		memberNetwork, _, err := client.UseProject(network.Project).GetNetwork(network.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting network %q in project %q: %w", network.Name, network.Project, err)
+		}
+
+		memberNetwork.Project = network.Project
+		state.Networks = append(state.Networks, *memberNetwork)
+	}
+
+	return state, nil
+}
+
+// swagger:operation GET /1.0/cluster/drift cluster cluster_drift_get
+//
+//	Get the cluster configuration drift
+//
+//	Compares the effective state of the cluster members (kernel and LXC features, instance and storage driver
+//	versions and network availability) and reports the inconsistencies that could break instance migration or
+//	scheduling between them.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Cluster drift
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterDrift"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterDriftGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	var members []db.NodeInfo
+	var offlineThreshold time.Duration
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		offlineThreshold, err = tx.GetNodeOfflineThreshold(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster offline threshold: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	drift := api.ClusterDrift{
+		Members:            []string{},
+		UnreachableMembers: []string{},
+	}
+
+	states := make(map[string]cluster.MemberDriftState, len(members))
+	for _, member := range members {
+		if member.IsOffline(offlineThreshold) {
+			drift.UnreachableMembers = append(drift.UnreachableMembers, member.Name)
+			continue
+		}
+
+		state, err := clusterDriftMemberState(r.Context(), s, member)
+		if err != nil {
+			logger.Warn("Failed getting cluster member state for drift report", logger.Ctx{"member": member.Name, "err": err})
+			drift.UnreachableMembers = append(drift.UnreachableMembers, member.Name)
+			continue
+		}
+
+		states[member.Name] = *state
+		drift.Members = append(drift.Members, member.Name)
+	}
+
+	drift.Inconsistencies = cluster.Drift(states)
+
+	return response.SyncResponse(true, drift)
+}
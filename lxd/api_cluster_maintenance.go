@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// clusterMaintenancePolicies are the valid evacuation policies of the maintenance mode.
+var clusterMaintenancePolicies = []string{"auto", "stop", "stateful-stop", "migrate", "live-migrate", "skip"}
+
+// clusterMaintenanceLast is the progress of the last maintenance drain of the local cluster member.
+var clusterMaintenanceLast *api.ClusterMemberMaintenance
+var clusterMaintenanceLastMu sync.Mutex
+
+// clusterMaintenanceRender returns a copy of the progress of the last maintenance drain, or nil if none.
+func clusterMaintenanceRender() *api.ClusterMemberMaintenance {
+	clusterMaintenanceLastMu.Lock()
+	defer clusterMaintenanceLastMu.Unlock()
+
+	if clusterMaintenanceLast == nil {
+		return nil
+	}
+
+	out := *clusterMaintenanceLast
+	out.Instances = slices.Clone(clusterMaintenanceLast.Instances)
+
+	return &out
+}
+
+// clusterMaintenanceStart records the start of a maintenance drain of the given instances.
+// It fails if a drain is already in progress.
+func clusterMaintenanceStart(instances []instance.Instance, policies func(inst instance.Instance) string) error {
+	clusterMaintenanceLastMu.Lock()
+	defer clusterMaintenanceLastMu.Unlock()
+
+	if clusterMaintenanceLast != nil && clusterMaintenanceLast.Status == "draining" {
+		return api.StatusErrorf(http.StatusConflict, "The cluster member is already being drained")
+	}
+
+	clusterMaintenanceLast = &api.ClusterMemberMaintenance{
+		Status:    "draining",
+		StartedAt: time.Now(),
+		Instances: make([]api.ClusterMemberMaintenanceInstance, 0, len(instances)),
+	}
+
+	for _, inst := range instances {
+		clusterMaintenanceLast.Instances = append(clusterMaintenanceLast.Instances, api.ClusterMemberMaintenanceInstance{
+			Project: inst.Project().Name,
+			Name:    inst.Name(),
+			Policy:  policies(inst),
+			Status:  "pending",
+		})
+	}
+
+	return nil
+}
+
+// clusterMaintenanceReport records the outcome of the evacuation of an instance.
+func clusterMaintenanceReport(inst instance.Instance, policy string, status string, target string, err error) {
+	clusterMaintenanceLastMu.Lock()
+	defer clusterMaintenanceLastMu.Unlock()
+
+	if clusterMaintenanceLast == nil {
+		return
+	}
+
+	for i, entry := range clusterMaintenanceLast.Instances {
+		if entry.Project != inst.Project().Name || entry.Name != inst.Name() {
+			continue
+		}
+
+		entry.Policy = policy
+		entry.Status = status
+		entry.Target = target
+		entry.Error = ""
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		clusterMaintenanceLast.Instances[i] = entry
+		return
+	}
+}
+
+// clusterMaintenanceFinish records the completion of the maintenance drain, or its failure if err is not nil.
+func clusterMaintenanceFinish(err error) {
+	clusterMaintenanceLastMu.Lock()
+	defer clusterMaintenanceLastMu.Unlock()
+
+	if clusterMaintenanceLast == nil {
+		return
+	}
+
+	clusterMaintenanceLast.FinishedAt = time.Now()
+	clusterMaintenanceLast.Status = "drained"
+	if err != nil {
+		clusterMaintenanceLast.Status = "failed"
+		clusterMaintenanceLast.Error = err.Error()
+	}
+}
+
+// clusterMaintenanceClear forgets the last maintenance drain once the cluster member leaves maintenance mode.
+func clusterMaintenanceClear() {
+	clusterMaintenanceLastMu.Lock()
+	defer clusterMaintenanceLastMu.Unlock()
+
+	clusterMaintenanceLast = nil
+}
+
+// swagger:operation PUT /1.0/cluster/members/{name}/state cluster cluster_member_state_put
+//
+//	Put a cluster member in or out of maintenance mode
+//
+//	Puts a cluster member in maintenance mode, blocking new instance placements on it and draining its instances
+//	according to their evacuation policy, or takes it out of maintenance mode, restoring its instances.
+//
+//	A failed drain can be resumed by putting the cluster member in maintenance mode again.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: cluster
+//	    description: Cluster member maintenance mode
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterMemberStatePut"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterNodeStatePut(d *Daemon, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s := d.State()
+
+	// Forward request
+	resp := forwardedResponseToNode(r.Context(), s, name)
+	if resp != nil {
+		return resp
+	}
+
+	// Parse the request
+	req := api.ClusterMemberStatePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Validate the policies.
+	if !req.Maintenance {
+		if req.Policy != "" && req.Policy != "skip" {
+			return response.BadRequest(fmt.Errorf("Invalid policy %q when leaving maintenance mode", req.Policy))
+		}
+
+		if len(req.InstancePolicies) > 0 {
+			return response.BadRequest(errors.New("Instance policies can only be set when entering maintenance mode"))
+		}
+	} else {
+		if req.Policy != "" && !slices.Contains(clusterMaintenancePolicies, req.Policy) {
+			return response.BadRequest(fmt.Errorf("Invalid policy %q", req.Policy))
+		}
+
+		for key, policy := range req.InstancePolicies {
+			projectName, instName, ok := strings.Cut(key, "/")
+			if !ok || projectName == "" || instName == "" {
+				return response.BadRequest(fmt.Errorf("Invalid instance %q, must be in the form <project>/<instance>", key))
+			}
+
+			if !slices.Contains(clusterMaintenancePolicies, policy) {
+				return response.BadRequest(fmt.Errorf("Invalid policy %q for instance %q", policy, key))
+			}
+		}
+	}
+
+	// Run some pre-checks before draining or restoring the cluster member.
+	// It's important that those checks runs on the cluster member itself.
+	if s.NetworkReady.Err() == nil {
+		return response.BadRequest(fmt.Errorf("Cannot change maintenance mode of %q because some networks aren't started yet", d.serverName))
+	} else if s.StorageReady.Err() == nil {
+		return response.BadRequest(fmt.Errorf("Cannot change maintenance mode of %q because some storage pools aren't started yet", d.serverName))
+	}
+
+	if !req.Maintenance {
+		resp := restoreClusterMember(d, r, req.Policy)
+		clusterMaintenanceClear()
+
+		return resp
+	}
+
+	stopFunc, migrateFunc := evacuateClusterMemberFuncs(r)
+
+	return drainClusterMember(s, d.gateway, r, name, req, stopFunc, migrateFunc)
+}
+
+// drainClusterMember puts the cluster member in maintenance mode and evacuates its instances in a background
+// operation. The cluster member stays evacuated if the drain fails, so that it can be resumed.
+func drainClusterMember(s *state.State, gateway *cluster.Gateway, r *http.Request, name string, req api.ClusterMemberStatePut, stopInstance evacuateStopFunc, migrateInstance evacuateMigrateFunc) response.Response {
+	run := func(op *operations.Operation) error {
+		// Set node status to EVACUATED, unless resuming a previous drain.
+		err := s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			node, err := tx.GetNodeByName(ctx, name)
+			if err != nil {
+				return fmt.Errorf("Failed to get cluster member by name: %w", err)
+			}
+
+			switch node.State {
+			case db.ClusterMemberStatePending:
+				return errors.New("Cannot drain a pending cluster member")
+			case db.ClusterMemberStateEvacuated:
+				return nil
+			}
+
+			err = tx.UpdateNodeStatus(node.ID, db.ClusterMemberStateEvacuated)
+			if err != nil {
+				return fmt.Errorf("Failed to update cluster member status: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Retrieve the instances still on the node now that no new instance can be placed on it.
+		var dbInstances []dbCluster.Instance
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			dbInstances, err = dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Node: &name})
+			if err != nil {
+				return fmt.Errorf("Failed to get instances: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		instances := make([]instance.Instance, 0, len(dbInstances))
+		for _, dbInst := range dbInstances {
+			inst, err := instance.LoadByProjectAndName(s, dbInst.Project, dbInst.Name)
+			if err != nil {
+				return fmt.Errorf("Failed to load instance: %w", err)
+			}
+
+			instances = append(instances, inst)
+		}
+
+		opts := evacuateOpts{
+			s:               s,
+			gateway:         gateway,
+			r:               r,
+			instances:       instances,
+			mode:            req.Policy,
+			srcMemberName:   name,
+			stopInstance:    stopInstance,
+			migrateInstance: migrateInstance,
+			op:              op,
+			policies:        req.InstancePolicies,
+			report:          clusterMaintenanceReport,
+		}
+
+		err = clusterMaintenanceStart(instances, func(inst instance.Instance) string { return evacuatePolicy(inst, opts) })
+		if err != nil {
+			return err
+		}
+
+		err = evacuateInstances(context.Background(), opts)
+		if err != nil {
+			clusterMaintenanceFinish(err)
+			return err
+		}
+
+		// Evacuate networks too.
+		networkStop(s, true)
+
+		clusterMaintenanceFinish(nil)
+		return nil
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, "", operations.OperationClassTask, operationtype.ClusterMemberEvacuate, nil, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
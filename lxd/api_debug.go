@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// debugCPUProfile is the name of the CPU profile, which isn't one of the runtime/pprof profiles.
+const debugCPUProfile = "cpu"
+
+// debugCPUProfileDefaultSeconds is the default duration of CPU profiles.
+const debugCPUProfileDefaultSeconds = 30
+
+// debugCPUProfileMaxSeconds is the maximum duration of CPU profiles.
+const debugCPUProfileMaxSeconds = 600
+
+var debugRuntimeCmd = APIEndpoint{
+	Path:        "debug/runtime",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: debugRuntimeGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementAdmin)},
+}
+
+var debugProfilesCmd = APIEndpoint{
+	Path:        "debug/pprof",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: debugProfilesGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementAdmin)},
+}
+
+var debugProfileCmd = APIEndpoint{
+	Path:        "debug/pprof/{name}",
+	MetricsType: entity.TypeServer,
+
+	Get:  APIEndpointAction{Handler: debugProfileGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementAdmin)},
+	Post: APIEndpointAction{Handler: debugProfilePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementAdmin)},
+}
+
+// swagger:operation GET /1.0/debug/runtime server debug_runtime_get
+//
+//	Get the runtime diagnostics
+//
+//	Returns the Go runtime information and memory allocation statistics of the daemon.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "200":
+//	    description: Runtime diagnostics
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/DebugRuntime"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func debugRuntimeGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	info := api.DebugRuntime{
+		GoVersion:  runtime.Version(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		CgoCalls:   runtime.NumCgoCall(),
+		Memory: api.DebugMemory{
+			Alloc:        ms.Alloc,
+			TotalAlloc:   ms.TotalAlloc,
+			Sys:          ms.Sys,
+			HeapInuse:    ms.HeapInuse,
+			HeapIdle:     ms.HeapIdle,
+			HeapReleased: ms.HeapReleased,
+			HeapObjects:  ms.HeapObjects,
+			StackInuse:   ms.StackInuse,
+			Mallocs:      ms.Mallocs,
+			Frees:        ms.Frees,
+			GCCycles:     ms.NumGC,
+			GCPauseTotal: ms.PauseTotalNs,
+		},
+	}
+
+	if ms.LastGC > 0 {
+		info.Memory.LastGC = time.Unix(0, int64(ms.LastGC))
+	}
+
+	return response.SyncResponse(true, info)
+}
+
+// swagger:operation GET /1.0/debug/pprof server debug_pprof_get
+//
+//	Get the profiles
+//
+//	Returns a list of the profiles which can be collected from the daemon (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/debug/pprof/cpu",
+//	              "/1.0/debug/pprof/goroutine",
+//	              "/1.0/debug/pprof/heap"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func debugProfilesGet(d *Daemon, r *http.Request) response.Response {
+	names := debugProfileNames()
+
+	urls := make([]string, 0, len(names))
+	for _, name := range names {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "debug", "pprof", name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation GET /1.0/debug/pprof/{name} server debug_pprof_profile_get
+//
+//	Get a profile
+//
+//	Collects a profile of the daemon and returns it in the `pprof` format, or as text if `debug` is set.
+//	CPU profiles are collected for the given number of seconds.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	  - text/plain
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: query
+//	    name: seconds
+//	    description: Duration of the CPU profile (defaults to 30)
+//	    type: integer
+//	    example: 30
+//	  - in: query
+//	    name: debug
+//	    description: Text format level, for example 2 for a full goroutine dump (not supported for CPU profiles)
+//	    type: integer
+//	    example: 2
+//	responses:
+//	  "200":
+//	    description: Raw profile
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "409":
+//	    description: A CPU profile is already being collected
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func debugProfileGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	name, seconds, debug, err := debugProfileParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var buf bytes.Buffer
+	err = debugWriteProfile(r.Context(), &buf, name, seconds, debug)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		if debug > 0 {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", "attachment; filename="+debugProfileFilename(name, debug, time.Now()))
+		}
+
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
+
+// swagger:operation POST /1.0/debug/pprof/{name} server debug_pprof_profile_post
+//
+//	Write a profile to the log directory
+//
+//	Collects a profile of the daemon and writes it to the log directory of the daemon, for example to be
+//	included in a support bundle.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: query
+//	    name: seconds
+//	    description: Duration of the CPU profile (defaults to 30)
+//	    type: integer
+//	    example: 30
+//	  - in: query
+//	    name: debug
+//	    description: Text format level, for example 2 for a full goroutine dump (not supported for CPU profiles)
+//	    type: integer
+//	    example: 2
+//	responses:
+//	  "200":
+//	    description: Written profile
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/DebugProfile"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "409":
+//	    description: A CPU profile is already being collected
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func debugProfilePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	name, seconds, debug, err := debugProfileParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var buf bytes.Buffer
+	err = debugWriteProfile(r.Context(), &buf, name, seconds, debug)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	path := shared.LogPath(debugProfileFilename(name, debug, time.Now()))
+	err = os.WriteFile(path, buf.Bytes(), 0600)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Failed writing profile %q: %w", name, err))
+	}
+
+	logger.Info("Wrote debug profile", logger.Ctx{"name": name, "path": path})
+
+	return response.SyncResponse(true, api.DebugProfile{Name: name, Path: path})
+}
+
+// debugProfileNames returns the sorted names of the profiles which can be collected.
+func debugProfileNames() []string {
+	names := []string{debugCPUProfile}
+	for _, profile := range pprof.Profiles() {
+		names = append(names, profile.Name())
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// debugProfileParams returns the name of the profile, the duration of CPU profiles and the text format level
+// requested.
+func debugProfileParams(r *http.Request) (name string, seconds int, debug int, err error) {
+	name, err = url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	if !slices.Contains(debugProfileNames(), name) {
+		return "", 0, 0, api.StatusErrorf(http.StatusNotFound, "Profile %q not found", name)
+	}
+
+	seconds = debugCPUProfileDefaultSeconds
+	secondsStr := request.QueryParam(r, "seconds")
+	if secondsStr != "" {
+		seconds, err = strconv.Atoi(secondsStr)
+		if err != nil || seconds <= 0 || seconds > debugCPUProfileMaxSeconds {
+			return "", 0, 0, api.StatusErrorf(http.StatusBadRequest, "Invalid profile duration %q, must be between 1 and %d seconds", secondsStr, debugCPUProfileMaxSeconds)
+		}
+	}
+
+	debugStr := request.QueryParam(r, "debug")
+	if debugStr != "" {
+		debug, err = strconv.Atoi(debugStr)
+		if err != nil || debug < 0 {
+			return "", 0, 0, api.StatusErrorf(http.StatusBadRequest, "Invalid profile debug level %q", debugStr)
+		}
+
+		if debug > 0 && name == debugCPUProfile {
+			return "", 0, 0, api.StatusErrorf(http.StatusBadRequest, "CPU profiles are only available in the pprof format")
+		}
+	}
+
+	return name, seconds, debug, nil
+}
+
+// debugWriteProfile writes the named profile to the buffer.
+// CPU profiles are collected for the given number of seconds, or until the context is cancelled.
+func debugWriteProfile(ctx context.Context, buf *bytes.Buffer, name string, seconds int, debug int) error {
+	if name != debugCPUProfile {
+		return pprof.Lookup(name).WriteTo(buf, debug)
+	}
+
+	err := pprof.StartCPUProfile(buf)
+	if err != nil {
+		return api.StatusErrorf(http.StatusConflict, "Failed starting CPU profile: %v", err)
+	}
+
+	select {
+	case <-time.After(time.Duration(seconds) * time.Second):
+	case <-ctx.Done():
+	}
+
+	pprof.StopCPUProfile()
+
+	if ctx.Err() != nil {
+		return errors.New("CPU profile interrupted")
+	}
+
+	return nil
+}
+
+// debugProfileFilename returns the name of the file to store the profile in.
+func debugProfileFilename(name string, debug int, now time.Time) string {
+	ext := "pb.gz"
+	if debug > 0 {
+		ext = "txt"
+	}
+
+	return fmt.Sprintf("pprof-%s-%s.%s", name, now.UTC().Format("20060102T150405Z"), ext)
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/network"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// healthCheckTimeout is the maximum time spent on each health check.
+const healthCheckTimeout = 5 * time.Second
+
+// healthMaxTimeSkew is the maximum clock difference between cluster members before the time check is degraded.
+const healthMaxTimeSkew = 5 * time.Second
+
+var healthCmd = APIEndpoint{
+	Path:        "health",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: healthGet, AllowUntrusted: true},
+}
+
+// swagger:operation GET /1.0/health server health_get
+//
+//	Get the server health
+//
+//	Returns the health of the server, suitable for load balancer health probes and monitoring.
+//	Untrusted callers only get the overall status, based on the availability of the database.
+//	Callers allowed to view the server resources also get the status of each subsystem.
+//	The status code is 503 if the overall status is "error".
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Server health
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Health"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+//	  "503":
+//	    description: The server is unhealthy
+//	    schema:
+//	      $ref: "#/definitions/Health"
+func healthGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Only callers allowed to view the server resources get the status of each subsystem.
+	detailed := false
+	if requestor.IsTrusted() {
+		err := s.Authorizer.CheckPermission(r.Context(), entity.ServerURL(), auth.EntitlementCanViewResources)
+		if err == nil {
+			detailed = true
+		} else if !auth.IsDeniedError(err) {
+			return response.SmartError(err)
+		}
+	}
+
+	databaseCheck, onlineMembers := healthCheckDatabase(r.Context(), s)
+	checks := []api.HealthCheck{databaseCheck}
+
+	// The other checks need the database.
+	if detailed && databaseCheck.Status != api.HealthStatusError {
+		checks = append(checks, healthCheckStorage(r.Context(), s), healthCheckNetwork(r.Context(), s))
+
+		if s.ServerClustered {
+			checks = append(checks,
+				healthCheckEvents(onlineMembers),
+				healthCheckImages(r.Context(), s, onlineMembers),
+				healthCheckTime(r.Context(), s, onlineMembers),
+			)
+		}
+	}
+
+	health := api.Health{Status: api.HealthStatusOK}
+	for _, check := range checks {
+		if check.Status == api.HealthStatusError || (check.Status == api.HealthStatusDegraded && health.Status == api.HealthStatusOK) {
+			health.Status = check.Status
+		}
+	}
+
+	if detailed {
+		health.Checks = checks
+	}
+
+	code := http.StatusOK
+	if health.Status == api.HealthStatusError {
+		code = http.StatusServiceUnavailable
+	}
+
+	return response.SyncResponseCode(true, code, health)
+}
+
+// healthCheckDatabase checks that the cluster database can be queried, which requires a quorum of database members
+// when clustered. It also returns the online cluster members.
+func healthCheckDatabase(ctx context.Context, s *state.State) (api.HealthCheck, []db.NodeInfo) {
+	check := api.HealthCheck{Name: "database", Status: api.HealthStatusOK}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var members []db.NodeInfo
+	var offlineThreshold time.Duration
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		offlineThreshold, err = tx.GetNodeOfflineThreshold(ctx)
+
+		return err
+	})
+	if err != nil {
+		check.Status = api.HealthStatusError
+		check.Message = fmt.Sprintf("Failed querying the database: %v", err)
+		return check, nil
+	}
+
+	if !s.ServerClustered {
+		check.Message = "Database available"
+		return check, nil
+	}
+
+	onlineMembers := make([]db.NodeInfo, 0, len(members))
+	for _, member := range members {
+		if !member.IsOffline(offlineThreshold) {
+			onlineMembers = append(onlineMembers, member)
+		}
+	}
+
+	if len(onlineMembers) < len(members) {
+		check.Status = api.HealthStatusDegraded
+	}
+
+	check.Message = fmt.Sprintf("%d of %d cluster members online", len(onlineMembers), len(members))
+
+	return check, onlineMembers
+}
+
+// healthCheckStorage checks that the storage pools are available on this member.
+func healthCheckStorage(ctx context.Context, s *state.State) api.HealthCheck {
+	check := api.HealthCheck{Name: "storage", Status: api.HealthStatusOK}
+
+	var poolNames []string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolNames, err = tx.GetStoragePoolNames(ctx)
+
+		return err
+	})
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		check.Status = api.HealthStatusError
+		check.Message = fmt.Sprintf("Failed getting storage pools: %v", err)
+		return check
+	}
+
+	var unavailable []string
+	for _, poolName := range poolNames {
+		if !storagePools.IsAvailable(poolName) {
+			unavailable = append(unavailable, poolName)
+		}
+	}
+
+	if len(unavailable) > 0 {
+		check.Status = api.HealthStatusError
+		check.Message = fmt.Sprintf("Unavailable storage pools: %v", unavailable)
+		return check
+	}
+
+	check.Message = fmt.Sprintf("%d storage pools available", len(poolNames))
+
+	return check
+}
+
+// healthCheckNetwork checks that the managed networks are available on this member.
+func healthCheckNetwork(ctx context.Context, s *state.State) api.HealthCheck {
+	check := api.HealthCheck{Name: "network", Status: api.HealthStatusOK}
+
+	var projectNetworks map[string]map[int64]api.Network
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		projectNetworks, err = tx.GetCreatedNetworks(ctx)
+
+		return err
+	})
+	if err != nil {
+		check.Status = api.HealthStatusError
+		check.Message = fmt.Sprintf("Failed getting networks: %v", err)
+		return check
+	}
+
+	count := 0
+	var unavailable []string
+	for projectName, networks := range projectNetworks {
+		for _, n := range networks {
+			count++
+
+			if !network.IsAvailable(projectName, n.Name) {
+				unavailable = append(unavailable, projectName+"/"+n.Name)
+			}
+		}
+	}
+
+	if len(unavailable) > 0 {
+		check.Status = api.HealthStatusError
+		check.Message = fmt.Sprintf("Unavailable networks: %v", unavailable)
+		return check
+	}
+
+	check.Message = fmt.Sprintf("%d networks available", count)
+
+	return check
+}
+
+// healthCheckEvents checks that this member is connected to the members it pulls events from.
+func healthCheckEvents(onlineMembers []db.NodeInfo) api.HealthCheck {
+	check := api.HealthCheck{Name: "events", Status: api.HealthStatusOK}
+
+	mode, listeners := cluster.EventListenersStatus()
+
+	active := 0
+	for _, isActive := range listeners {
+		if isActive {
+			active++
+		}
+	}
+
+	if active < len(listeners) || (len(listeners) == 0 && len(onlineMembers) > 1) {
+		check.Status = api.HealthStatusDegraded
+	}
+
+	check.Message = fmt.Sprintf("%d of %d event listeners active (%s)", active, len(listeners), mode)
+
+	return check
+}
+
+// healthCheckImages checks that the images are replicated on enough cluster members.
+func healthCheckImages(ctx context.Context, s *state.State, onlineMembers []db.NodeInfo) api.HealthCheck {
+	check := api.HealthCheck{Name: "images", Status: api.HealthStatusOK}
+
+	// -1 means that the images are replicated on all members.
+	desired := s.GlobalConfig.ImagesMinimalReplica()
+	if desired == -1 || desired > int64(len(onlineMembers)) {
+		desired = int64(len(onlineMembers))
+	}
+
+	underReplicated := 0
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		images, err := tx.GetImages(ctx)
+		if err != nil {
+			return err
+		}
+
+		for fingerprint := range images {
+			addresses, err := tx.GetNodesWithImage(ctx, fingerprint)
+			if err != nil {
+				return err
+			}
+
+			if int64(len(addresses)) < desired {
+				underReplicated++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		check.Status = api.HealthStatusError
+		check.Message = fmt.Sprintf("Failed getting images: %v", err)
+		return check
+	}
+
+	if underReplicated > 0 {
+		check.Status = api.HealthStatusDegraded
+		check.Message = fmt.Sprintf("%d images replicated on less than %d cluster members", underReplicated, desired)
+		return check
+	}
+
+	check.Message = "Images replicated"
+
+	return check
+}
+
+// healthCheckTime checks that the clocks of the online cluster members are in sync with the clock of this member,
+// using the Date header of their responses.
+func healthCheckTime(ctx context.Context, s *state.State, onlineMembers []db.NodeInfo) api.HealthCheck {
+	check := api.HealthCheck{Name: "time", Status: api.HealthStatusOK}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	localAddress := s.Endpoints.NetworkAddress()
+
+	var mu sync.Mutex
+	var maxSkew time.Duration
+	var maxSkewMember string
+	var unreachable []string
+
+	wg := sync.WaitGroup{}
+	for _, member := range onlineMembers {
+		if member.Address == localAddress {
+			continue
+		}
+
+		wg.Add(1)
+		go func(member db.NodeInfo) {
+			defer wg.Done()
+
+			skew, err := healthMemberTimeSkew(ctx, s, member)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				unreachable = append(unreachable, member.Name)
+				return
+			}
+
+			if skew < 0 {
+				skew = -skew
+			}
+
+			if skew > maxSkew {
+				maxSkew = skew
+				maxSkewMember = member.Name
+			}
+		}(member)
+	}
+
+	wg.Wait()
+
+	if maxSkew > healthMaxTimeSkew {
+		check.Status = api.HealthStatusDegraded
+		check.Message = fmt.Sprintf("Clock of cluster member %q is %s off", maxSkewMember, maxSkew.Round(time.Second))
+	} else {
+		check.Message = "Clocks in sync"
+	}
+
+	if len(unreachable) > 0 {
+		check.Status = api.HealthStatusDegraded
+		check.Message += fmt.Sprintf(", failed checking cluster members %v", unreachable)
+	}
+
+	return check
+}
+
+// healthMemberTimeSkew returns the difference between the clock of the member and the clock of this member.
+// The precision is limited to a second by the Date header.
+func healthMemberTimeSkew(ctx context.Context, s *state.State, member db.NodeInfo) (time.Duration, error) {
+	client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return 0, err
+	}
+
+	httpClient, err := client.GetHTTPClient()
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := client.GetConnectionInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, info.URL+"/", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	end := time.Now()
+	_ = resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("Invalid Date header: %w", err)
+	}
+
+	// Compare with the middle of the request to account for the network latency.
+	return date.Sub(start.Add(end.Sub(start) / 2)), nil
+}
@@ -65,6 +65,7 @@ var apiInternal = []APIEndpoint{
 	internalSQLCmd,
 	internalWarningCreateCmd,
 	internalIdentityCacheRefreshCmd,
+	internalAuthSecretsRefreshCmd,
 	internalPruneTokenCmd,
 	internalOperationWaitCmd,
 }
@@ -164,6 +165,12 @@ var internalIdentityCacheRefreshCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: internalIdentityCacheRefresh, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
 }
 
+var internalAuthSecretsRefreshCmd = APIEndpoint{
+	Path: "auth-secrets-refresh",
+
+	Post: APIEndpointAction{Handler: internalAuthSecretsRefresh, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
 type internalImageOptimizePost struct {
 	Image   api.Image `json:"image"    yaml:"image"`
 	Pool    string    `json:"pool"     yaml:"pool"`
@@ -1170,3 +1177,9 @@ func internalIdentityCacheRefresh(d *Daemon, _ *http.Request) response.Response
 	d.State().UpdateIdentityCache()
 	return response.EmptySyncResponse
 }
+
+func internalAuthSecretsRefresh(d *Daemon, _ *http.Request) response.Response {
+	logger.Debug("Received auth secrets update notification - clearing in-memory secrets")
+	d.clearCoreAuthSecrets()
+	return response.EmptySyncResponse
+}
@@ -59,6 +59,8 @@ type internalRecoverValidateVolume struct {
 	SnapshotCount int    `json:"snapshotCount" yaml:"snapshotCount"` // Count of snapshots found for volume.
 	Project       string `json:"project" yaml:"project"`             // Project the volume belongs to.
 	Pool          string `json:"pool" yaml:"pool"`                   // Pool the volume belongs to.
+
+	Config *backupConfig.Config `json:"-" yaml:"-"` // Backup config the volume was discovered from.
 }
 
 // internalRecoverValidateResult returns the result of the validation scan.
@@ -166,8 +168,43 @@ func appendUnknownVolumeConfig(originalPoolName string, projectName string, volu
 	return nil
 }
 
+// internalRecoverVolumeInfo returns the scan result entry for an unknown volume discovered on the given pool.
+func internalRecoverVolumeInfo(poolName string, projectName string, poolVol *backupConfig.Config) (internalRecoverValidateVolume, error) {
+	vol := internalRecoverValidateVolume{
+		// The volume's pool name is the one where it originates from.
+		Pool:    poolName,
+		Project: projectName,
+		Config:  poolVol,
+	}
+
+	// Build display fields for scan results.
+	if poolVol.Instance != nil {
+		vol.Type = poolVol.Instance.Type
+		vol.Name = poolVol.Instance.Name
+		vol.SnapshotCount = len(poolVol.Snapshots)
+	} else if poolVol.Bucket != nil {
+		vol.Type = "bucket"
+		vol.Name = poolVol.Bucket.Name
+	} else {
+		vol.Type = "volume"
+
+		customVol, err := poolVol.CustomVolume()
+		if err != nil {
+			return internalRecoverValidateVolume{}, fmt.Errorf("Failed getting the custom volume: %w", err)
+		}
+
+		// In case of custom volumes those could be discovered from the instance's backup config inside another pool.
+		vol.Pool = customVol.Pool
+		vol.Name = customVol.Name
+		vol.SnapshotCount = len(customVol.Snapshots)
+	}
+
+	return vol, nil
+}
+
 // internalRecoverScan provides the discovery and import functionality for both recovery validate and import steps.
-func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.StoragePoolsPost, validateOnly bool) response.Response {
+// If a selector function is provided, only the unknown volumes it returns true for are considered.
+func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.StoragePoolsPost, validateOnly bool, selector func(vol internalRecoverValidateVolume) bool) (*internalRecoverValidateResult, error) {
 	var err error
 	var projects map[string]*api.Project
 	var projectProfiles map[string][]*api.Profile
@@ -233,7 +270,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 		return nil
 	})
 	if err != nil {
-		return response.SmartError(fmt.Errorf("Failed getting validate dependency check info: %w", err))
+		return nil, fmt.Errorf("Failed getting validate dependency check info: %w", err)
 	}
 
 	res := internalRecoverValidateResult{}
@@ -261,13 +298,13 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 		pool, err := storagePools.LoadByName(s, p.Name)
 		if err != nil {
 			if !response.IsNotFoundError(err) {
-				return response.SmartError(fmt.Errorf("Failed loading existing pool %q: %w", p.Name, err))
+				return nil, fmt.Errorf("Failed loading existing pool %q: %w", p.Name, err)
 			}
 
 			// If the pool DB record doesn't exist, and we are clustered, then don't proceed
 			// any further as we do not support pool DB record recovery when clustered.
 			if s.ServerClustered {
-				return response.BadRequest(errors.New("Storage pool recovery not supported when clustered"))
+				return nil, api.NewStatusError(http.StatusBadRequest, "Storage pool recovery not supported when clustered")
 			}
 
 			// If pool doesn't exist in DB, initialise a temporary pool with the supplied info.
@@ -281,18 +318,18 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 
 			pool, err = storagePools.NewTemporary(s, &poolInfo)
 			if err != nil {
-				return response.SmartError(fmt.Errorf("Failed to initialise unknown pool %q: %w", p.Name, err))
+				return nil, fmt.Errorf("Failed to initialise unknown pool %q: %w", p.Name, err)
 			}
 
 			// Populate configuration with default values.
 			err := pool.Driver().FillConfig()
 			if err != nil {
-				return response.SmartError(fmt.Errorf("Failed to evaluate the default configuration values for unknown pool %q: %w", p.Name, err))
+				return nil, fmt.Errorf("Failed to evaluate the default configuration values for unknown pool %q: %w", p.Name, err)
 			}
 
 			err = pool.Driver().Validate(poolInfo.Config)
 			if err != nil {
-				return response.SmartError(fmt.Errorf("Failed config validation for unknown pool %q: %w", p.Name, err))
+				return nil, fmt.Errorf("Failed config validation for unknown pool %q: %w", p.Name, err)
 			}
 		}
 
@@ -302,7 +339,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 		// Try to mount the pool.
 		ourMount, err := pool.Mount()
 		if err != nil {
-			return response.SmartError(fmt.Errorf("Failed mounting pool %q: %w", pool.Name(), err))
+			return nil, fmt.Errorf("Failed mounting pool %q: %w", pool.Name(), err)
 		}
 
 		// Unmount pool when done if not existing in DB after function has finished.
@@ -329,7 +366,26 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				continue // Ignore unsupported storage drivers.
 			}
 
-			return response.SmartError(fmt.Errorf("Failed checking volumes on pool %q: %w", pool.Name(), err))
+			return nil, fmt.Errorf("Failed checking volumes on pool %q: %w", pool.Name(), err)
+		}
+
+		// Drop any unknown volumes that weren't selected so they neither get imported nor checked for dependencies.
+		if selector != nil {
+			for projectName, volConfigs := range poolProjectVols {
+				selected := make([]*backupConfig.Config, 0, len(volConfigs))
+				for _, volConfig := range volConfigs {
+					vol, err := internalRecoverVolumeInfo(p.Name, projectName, volConfig)
+					if err != nil {
+						return nil, err
+					}
+
+					if selector(vol) {
+						selected = append(selected, volConfig)
+					}
+				}
+
+				poolProjectVols[projectName] = selected
+			}
 		}
 
 		// Iterate over the list of returned unknown volumes and store them for consumption after validation scan to avoid needing to reprocess.
@@ -339,7 +395,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 			for _, volConfig := range volConfigs {
 				err = appendUnknownVolumeConfig(p.Name, projectName, volConfig, poolsProjectVols)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed to add unknown volume to the list: %w", err))
+					return nil, fmt.Errorf("Failed to add unknown volume to the list: %w", err)
 				}
 			}
 		}
@@ -412,47 +468,17 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 		for poolName, poolProjectVols := range poolsProjectVols {
 			for projectName, poolVols := range poolProjectVols {
 				for _, poolVol := range poolVols {
-					var displayType, displayName string
-					var displaySnapshotCount int
-
-					// The volume's pool name is the one where it originates from.
-					displayPoolName := poolName
-
-					// Build display fields for scan results.
-					if poolVol.Instance != nil {
-						displayType = poolVol.Instance.Type
-						displayName = poolVol.Instance.Name
-						displaySnapshotCount = len(poolVol.Snapshots)
-					} else if poolVol.Bucket != nil {
-						displayType = "bucket"
-						displayName = poolVol.Bucket.Name
-						displaySnapshotCount = 0
-					} else {
-						displayType = "volume"
-
-						customVol, err := poolVol.CustomVolume()
-						if err != nil {
-							return response.SmartError(fmt.Errorf("Failed getting the custom volume: %w", err))
-						}
-
-						// In case of custom volumes those could be discovered from the instance's backup config inside another pool.
-						displayPoolName = customVol.Pool
-						displayName = customVol.Name
-						displaySnapshotCount = len(customVol.Snapshots)
+					vol, err := internalRecoverVolumeInfo(poolName, projectName, poolVol)
+					if err != nil {
+						return nil, err
 					}
 
-					res.UnknownVolumes = append(res.UnknownVolumes, internalRecoverValidateVolume{
-						Pool:          displayPoolName,
-						Project:       projectName,
-						Type:          displayType,
-						Name:          displayName,
-						SnapshotCount: displaySnapshotCount,
-					})
+					res.UnknownVolumes = append(res.UnknownVolumes, vol)
 				}
 			}
 		}
 
-		return response.SyncResponse(true, &res)
+		return &res, nil
 	}
 
 	// If in import mode and no dependency errors, then re-create missing DB records.
@@ -493,7 +519,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				logger.Info("Creating storage pool DB record from instance config", logger.Ctx{"name": rootVolPool.Name, "description": rootVolPool.Description, "driver": rootVolPool.Driver, "config": rootVolPool.Config})
 				poolID, err = dbStoragePoolCreateAndUpdateCache(ctx, s, rootVolPool.Name, rootVolPool.Description, rootVolPool.Driver, rootVolPool.Config)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed creating storage pool %q database entry: %w", pool.Name(), err))
+					return nil, fmt.Errorf("Failed creating storage pool %q database entry: %w", pool.Name(), err)
 				}
 			} else {
 				// Create storage pool DB record from config supplied by user if not
@@ -503,7 +529,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				logger.Info("Creating storage pool DB record from user config", logger.Ctx{"name": pool.Name(), "driver": poolDriverName, "config": poolDriverConfig})
 				poolID, err = dbStoragePoolCreateAndUpdateCache(ctx, s, pool.Name(), "", poolDriverName, poolDriverConfig)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed creating storage pool %q database entry: %w", pool.Name(), err))
+					return nil, fmt.Errorf("Failed creating storage pool %q database entry: %w", pool.Name(), err)
 				}
 			}
 
@@ -517,14 +543,14 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				return tx.StoragePoolNodeCreated(poolID)
 			})
 			if err != nil {
-				return response.SmartError(fmt.Errorf("Failed marking storage pool %q local status as created: %w", pool.Name(), err))
+				return nil, fmt.Errorf("Failed marking storage pool %q local status as created: %w", pool.Name(), err)
 			}
 
 			logger.Debug("Marked storage pool local status as created", logger.Ctx{"pool": pool.Name()})
 
 			newPool, err := storagePools.LoadByName(s, pool.Name())
 			if err != nil {
-				return response.SmartError(fmt.Errorf("Failed loading created storage pool %q: %w", pool.Name(), err))
+				return nil, fmt.Errorf("Failed loading created storage pool %q: %w", pool.Name(), err)
 			}
 
 			// Record this newly created pool so that defer doesn't unmount on return.
@@ -539,7 +565,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 
 			if projectInfo == nil {
 				// Shouldn't happen as we validated this above, but be sure for safety.
-				return response.SmartError(fmt.Errorf("Project %q not found", projectName))
+				return nil, fmt.Errorf("Project %q not found", projectName)
 			}
 
 			customStorageProjectName := project.StorageVolumeProjectFromRecord(projectInfo, dbCluster.StoragePoolVolumeTypeCustom)
@@ -550,18 +576,18 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				if poolVol.Instance != nil || poolVol.Bucket != nil {
 					continue // Skip instance volumes and buckets.
 				} else if poolVol.Instance == nil && len(poolVol.Volumes) == 0 {
-					return response.SmartError(errors.New("Volume is neither instance nor custom volume"))
+					return nil, errors.New("Volume is neither instance nor custom volume")
 				}
 
 				rootVol, err := poolVol.RootVolume()
 				if err != nil {
-					response.SmartError(fmt.Errorf("Failed getting the root volume: %w", err))
+					return nil, fmt.Errorf("Failed getting the root volume: %w", err)
 				}
 
 				// Import custom volume and any snapshots.
 				cleanup, err := pool.ImportCustomVolume(customStorageProjectName, poolVol, nil)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed importing custom volume %q in project %q: %w", rootVol.Name, projectName, err))
+					return nil, fmt.Errorf("Failed importing custom volume %q in project %q: %w", rootVol.Name, projectName, err)
 				}
 
 				revert.Add(cleanup)
@@ -577,7 +603,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				// Import bucket.
 				cleanup, err := pool.ImportBucket(projectName, poolVol, nil)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed importing bucket %q in project %q: %w", poolVol.Bucket.Name, projectName, err))
+					return nil, fmt.Errorf("Failed importing bucket %q in project %q: %w", poolVol.Bucket.Name, projectName, err)
 				}
 
 				revert.Add(cleanup)
@@ -592,7 +618,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 
 			if projectInfo == nil {
 				// Shouldn't happen as we validated this above, but be sure for safety.
-				return response.SmartError(fmt.Errorf("Project %q not found", projectName))
+				return nil, fmt.Errorf("Project %q not found", projectName)
 			}
 
 			profileProjectName := project.ProfileProjectFromRecord(projectInfo)
@@ -615,7 +641,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 
 				inst, cleanup, err := internalRecoverImportInstance(s, pool, projectName, poolVol, profiles)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed creating instance %q record in project %q: %w", poolVol.Instance.Name, projectName, err))
+					return nil, fmt.Errorf("Failed creating instance %q record in project %q: %w", poolVol.Instance.Name, projectName, err)
 				}
 
 				revert.Add(cleanup)
@@ -633,7 +659,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 
 					cleanup, err := internalRecoverImportInstanceSnapshot(s, pool, projectName, poolVol, poolInstSnap, profiles)
 					if err != nil {
-						return response.SmartError(fmt.Errorf("Failed creating instance %q snapshot %q record in project %q: %w", poolVol.Instance.Name, poolInstSnap.Name, projectName, err))
+						return nil, fmt.Errorf("Failed creating instance %q snapshot %q record in project %q: %w", poolVol.Instance.Name, poolInstSnap.Name, projectName, err)
 					}
 
 					revert.Add(cleanup)
@@ -642,7 +668,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				// Recreate instance mount path and symlinks (must come after snapshot recovery).
 				cleanup, err = pool.ImportInstance(inst, poolVol, nil)
 				if err != nil {
-					return response.SmartError(fmt.Errorf("Failed importing instance %q in project %q: %w", poolVol.Instance.Name, projectName, err))
+					return nil, fmt.Errorf("Failed importing instance %q in project %q: %w", poolVol.Instance.Name, projectName, err)
 				}
 
 				revert.Add(cleanup)
@@ -653,7 +679,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 				if err == nil {
 					err = pool.SetInstanceQuota(inst, rootConfig["size"], rootConfig["size.state"], nil)
 					if err != nil {
-						return response.SmartError(fmt.Errorf("Failed reinitializing root disk quota %q for instance %q in project %q: %w", rootConfig["size"], poolVol.Instance.Name, projectName, err))
+						return nil, fmt.Errorf("Failed reinitializing root disk quota %q for instance %q in project %q: %w", rootConfig["size"], poolVol.Instance.Name, projectName, err)
 					}
 				}
 			}
@@ -661,7 +687,7 @@ func internalRecoverScan(ctx context.Context, s *state.State, userPools []api.St
 	}
 
 	revert.Success()
-	return response.EmptySyncResponse
+	return nil, nil
 }
 
 // internalRecoverImportInstance recreates the database records for an instance and returns the new instance.
@@ -772,7 +798,12 @@ func internalRecoverValidate(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
-	return internalRecoverScan(r.Context(), d.State(), req.Pools, true)
+	res, err := internalRecoverScan(r.Context(), d.State(), req.Pools, true, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, res)
 }
 
 // internalRecoverImport performs the pool volume recovery.
@@ -784,5 +815,15 @@ func internalRecoverImport(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
-	return internalRecoverScan(r.Context(), d.State(), req.Pools, false)
+	res, err := internalRecoverScan(r.Context(), d.State(), req.Pools, false, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Dependency errors prevented the import, let the caller know about them.
+	if res != nil {
+		return response.SyncResponse(true, res)
+	}
+
+	return response.EmptySyncResponse
 }
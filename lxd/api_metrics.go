@@ -4,26 +4,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"net"
 	"net/http"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/warningtype"
 	"github.com/canonical/lxd/lxd/instance"
 	instanceDrivers "github.com/canonical/lxd/lxd/instance/drivers"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/ip"
 	"github.com/canonical/lxd/lxd/locking"
 	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/network"
+	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
@@ -112,6 +121,8 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	metricSet := metrics.NewMetricSet(nil)
 
 	var projectNames []string
+	var poolNames []string
+	var networks map[string]map[int64]api.Network
 	var intMetrics *metrics.MetricSet
 	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Figure out the projects to retrieve.
@@ -130,6 +141,17 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		var err error
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed loading storage pools: %w", err)
+		}
+
+		networks, err = tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed loading networks: %w", err)
+		}
+
 		// Register internal metrics.
 		intMetrics = internalMetrics(ctx, s, tx)
 		return nil
@@ -138,6 +160,10 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Storage pool metrics are gathered outside of the transaction as they query the storage drivers.
+	intMetrics.Merge(storagePoolMetrics(s, poolNames))
+	intMetrics.Merge(conntrackMetrics(networks))
+
 	// invalidProjectFilters returns project filters which are either not in cache or have expired.
 	invalidProjectFilters := func(projectNames []string) []dbCluster.InstanceFilter {
 		metricsCacheLock.Lock()
@@ -380,7 +406,7 @@ func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSe
 	return response.SyncResponsePlain(true, compress, metricSet.String())
 }
 
-// clusterMemberWarnings returns the list of unresolved and unacknowledged warnings related to this cluster member.
+// clusterMemberWarnings returns the list of unresolved warnings related to this cluster member.
 // If this member is the leader, also include nodeless warnings.
 // This way we include them while avoiding counting them redundantly across cluster members.
 func clusterMemberWarnings(ctx context.Context, s *state.State, tx *db.ClusterTx) ([]dbCluster.Warning, error) {
@@ -395,8 +421,8 @@ func clusterMemberWarnings(ctx context.Context, s *state.State, tx *db.ClusterTx
 	emptyNode := ""
 
 	for status := range warningtype.Statuses {
-		// Do not include resolved warnings that are resolved but not yet pruned neither those that were acknowledged.
-		if status != warningtype.StatusResolved && status != warningtype.StatusAcknowledged {
+		// Do not include resolved warnings that are resolved but not yet pruned.
+		if status != warningtype.StatusResolved {
 			filters = append(filters, dbCluster.WarningFilter{Node: &s.ServerName, Status: &status})
 			if leaderInfo.Leader {
 				// Count the nodeless warnings as belonging to the leader node.
@@ -416,18 +442,49 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 	if err != nil {
 		logger.Warn("Failed to get warnings", logger.Ctx{"err": err})
 	} else {
+		type warningKey struct {
+			typeCode warningtype.Type
+			status   warningtype.Status
+		}
+
+		active := 0
+		counts := map[warningKey]int{}
+		for _, w := range warnings {
+			counts[warningKey{typeCode: w.TypeCode, status: w.Status}]++
+
+			// Acknowledged warnings aren't active.
+			if w.Status != warningtype.StatusAcknowledged {
+				active++
+			}
+		}
+
 		// Total number of warnings
-		out.AddSamples(metrics.WarningsTotal, metrics.Sample{Value: float64(len(warnings))})
+		out.AddSamples(metrics.WarningsTotal, metrics.Sample{Value: float64(active)})
+
+		for key, count := range counts {
+			labels := map[string]string{
+				"type":     warningtype.TypeNames[key.typeCode],
+				"severity": warningtype.Severities[key.typeCode.Severity()],
+				"status":   warningtype.Statuses[key.status],
+			}
+
+			out.AddSamples(metrics.Warnings, metrics.Sample{Labels: labels, Value: float64(count)})
+		}
 	}
 
 	// Create local variable to get a pointer.
 	nodeID := tx.GetNodeID()
-	operations, err := dbCluster.GetOperations(ctx, tx.Tx(), dbCluster.OperationFilter{NodeID: &nodeID})
+	dbOperations, err := dbCluster.GetOperations(ctx, tx.Tx(), dbCluster.OperationFilter{NodeID: &nodeID})
 	if err != nil {
 		logger.Warn("Failed to get operations", logger.Ctx{"err": err})
 	} else {
 		// Total number of operations
-		out.AddSamples(metrics.OperationsTotal, metrics.Sample{Value: float64(len(operations))})
+		out.AddSamples(metrics.OperationsTotal, metrics.Sample{Value: float64(len(dbOperations))})
+	}
+
+	// Operations waiting for the operation limits
+	for _, depth := range operations.QueueDepths() {
+		out.AddSamples(metrics.OperationsQueued, metrics.Sample{Labels: map[string]string{"queue": depth.Queue, "project": depth.Project}, Value: float64(depth.Waiting)})
 	}
 
 	// API request metrics
@@ -449,8 +506,59 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 				},
 			)
 		}
+
+		out.AddSamples(
+			metrics.APISlowRequestsTotal,
+			metrics.Sample{
+				Labels: map[string]string{"entity_type": entityType.String()},
+				Value:  float64(metrics.GetSlowRequests(entityType)),
+			},
+		)
+
+		out.AddSamples(
+			metrics.APIRequestsTooLargeTotal,
+			metrics.Sample{
+				Labels: map[string]string{"entity_type": entityType.String()},
+				Value:  float64(metrics.GetRequestsTooLarge(entityType)),
+			},
+		)
 	}
 
+	// Slow database transactions
+	for database, count := range db.SlowTransactions() {
+		out.AddSamples(metrics.DBSlowTransactionsTotal, metrics.Sample{Labels: map[string]string{"database": database}, Value: float64(count)})
+	}
+
+	// Dqlite and raft health
+	if s.ServerClustered {
+		raftMetrics, err := cluster.GetRaftMetrics(ctx, s.DB.Node)
+		if err != nil {
+			logger.Warn("Failed to get raft metrics", logger.Ctx{"err": err})
+		} else {
+			out.AddSamples(metrics.ClusterRaftLeaderChangesTotal, metrics.Sample{Value: float64(raftMetrics.LeaderChanges)})
+
+			if raftMetrics.ApplyLatency > 0 {
+				out.AddSamples(metrics.ClusterRaftApplyLatencySeconds, metrics.Sample{Value: raftMetrics.ApplyLatency.Seconds()})
+			}
+
+			if raftMetrics.SnapshotAge > 0 {
+				out.AddSamples(metrics.ClusterRaftSnapshotAgeSeconds, metrics.Sample{Value: raftMetrics.SnapshotAge.Seconds()})
+			}
+
+			for _, failures := range raftMetrics.DialFailures {
+				out.AddSamples(metrics.ClusterDialFailuresTotal, metrics.Sample{Labels: map[string]string{"type": failures.Type, "address": failures.Address}, Value: float64(failures.Count)})
+			}
+
+			for member, role := range raftMetrics.Roles {
+				out.AddSamples(metrics.ClusterRaftRole, metrics.Sample{Labels: map[string]string{"member": member, "role": role}, Value: 1})
+			}
+		}
+	}
+
+	// API request and operation durations
+	out.AddSamples(metrics.APIRequestDurationSeconds, metrics.GetRequestDurations()...)
+	out.AddSamples(metrics.OperationDurationSeconds, metrics.GetOperationDurations()...)
+
 	// Daemon uptime
 	out.AddSamples(metrics.UptimeSeconds, metrics.Sample{Value: time.Since(s.StartTime).Seconds()})
 
@@ -487,3 +595,165 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 
 	return out
 }
+
+// storagePoolMetrics returns the capacity, latency and error metrics of the storage pools available on this member.
+func storagePoolMetrics(s *state.State, poolNames []string) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	for _, poolName := range poolNames {
+		if !storagePools.IsAvailable(poolName) {
+			continue
+		}
+
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		labels := func() map[string]string {
+			return map[string]string{"pool": poolName, "driver": pool.Driver().Info().Name}
+		}
+
+		res, err := pool.GetResources()
+		if err != nil {
+			logger.Warn("Failed getting storage pool resources", logger.Ctx{"pool": poolName, "err": err})
+		} else if res != nil {
+			out.AddSamples(metrics.StoragePoolSizeBytes, metrics.Sample{Labels: labels(), Value: float64(res.Space.Total)})
+			out.AddSamples(metrics.StoragePoolUsedBytes, metrics.Sample{Labels: labels(), Value: float64(res.Space.Used)})
+		}
+
+		stats, err := pool.GetIOStats()
+		if err != nil {
+			if !errors.Is(err, storageDrivers.ErrNotSupported) {
+				logger.Warn("Failed getting storage pool I/O statistics", logger.Ctx{"pool": poolName, "err": err})
+			}
+
+			continue
+		}
+
+		if stats == nil {
+			continue
+		}
+
+		out.AddSamples(metrics.StoragePoolReadLatencySeconds, latencyHistogramSamples(labels, stats.ReadLatency)...)
+		out.AddSamples(metrics.StoragePoolWriteLatencySeconds, latencyHistogramSamples(labels, stats.WriteLatency)...)
+
+		for _, errorType := range slices.Sorted(maps.Keys(stats.Errors)) {
+			errorLabels := labels()
+			errorLabels["type"] = errorType
+			out.AddSamples(metrics.StoragePoolIOErrorsTotal, metrics.Sample{Labels: errorLabels, Value: float64(stats.Errors[errorType])})
+		}
+	}
+
+	return out
+}
+
+// conntrackMetrics returns the size and drop counters of the conntrack table, and the number of conntrack entries
+// and NAT translations of each bridge network available on this member.
+func conntrackMetrics(networks map[string]map[int64]api.Network) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	stats, err := ip.GetConntrackStats()
+	if err != nil {
+		// Conntrack isn't loaded until the first firewall rule using it is added.
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("Failed getting conntrack statistics", logger.Ctx{"err": err})
+		}
+
+		return out
+	}
+
+	out.AddSamples(metrics.ConntrackEntries, metrics.Sample{Value: float64(stats.Entries)})
+	out.AddSamples(metrics.ConntrackEntriesLimit, metrics.Sample{Value: float64(stats.Limit)})
+
+	for _, reason := range slices.Sorted(maps.Keys(stats.Drops)) {
+		out.AddSamples(metrics.ConntrackDropsTotal, metrics.Sample{Labels: map[string]string{"reason": reason}, Value: float64(stats.Drops[reason])})
+	}
+
+	type bridgeSubnets struct {
+		project string
+		name    string
+		subnets []*net.IPNet
+	}
+
+	bridges := []bridgeSubnets{}
+	for projectName, projectNetworks := range networks {
+		for _, n := range projectNetworks {
+			if n.Type != "bridge" || !network.IsAvailable(projectName, n.Name) {
+				continue
+			}
+
+			bridge := bridgeSubnets{project: projectName, name: n.Name}
+			for _, key := range []string{"ipv4.address", "ipv6.address"} {
+				_, subnet, err := net.ParseCIDR(n.Config[key])
+				if err == nil {
+					bridge.subnets = append(bridge.subnets, subnet)
+				}
+			}
+
+			if len(bridge.subnets) > 0 {
+				bridges = append(bridges, bridge)
+			}
+		}
+	}
+
+	if len(bridges) == 0 {
+		return out
+	}
+
+	flows, err := ip.ConntrackFlows()
+	if err != nil {
+		logger.Warn("Failed getting conntrack entries", logger.Ctx{"err": err})
+		return out
+	}
+
+	for _, bridge := range bridges {
+		var entries, translations int
+		for _, flow := range flows {
+			// The instances are either the source of the connection or, for forwarded ports, the destination
+			// of the translated connection.
+			inNetwork := slices.ContainsFunc(bridge.subnets, func(subnet *net.IPNet) bool {
+				return subnet.Contains(flow.OriginalSrc) || subnet.Contains(flow.OriginalDst) || subnet.Contains(flow.ReplySrc)
+			})
+
+			if !inNetwork {
+				continue
+			}
+
+			entries++
+			if flow.IsNAT() {
+				translations++
+			}
+		}
+
+		labels := map[string]string{"network": bridge.name, "project": bridge.project}
+		out.AddSamples(metrics.NetworkConntrackEntries, metrics.Sample{Labels: labels, Value: float64(entries)})
+		out.AddSamples(metrics.NetworkNATTranslations, metrics.Sample{Labels: labels, Value: float64(translations)})
+	}
+
+	return out
+}
+
+// latencyHistogramSamples returns the bucket, sum and count samples of a storage pool latency histogram.
+func latencyHistogramSamples(labels func() map[string]string, histogram storageDrivers.LatencyHistogram) []metrics.Sample {
+	samples := make([]metrics.Sample, 0, len(histogram.Buckets)+3)
+	for _, bucket := range histogram.Buckets {
+		bucketLabels := labels()
+		bucketLabels["le"] = strconv.FormatFloat(bucket.UpperBound, 'g', -1, 64)
+		samples = append(samples, metrics.Sample{Suffix: "_bucket", Labels: bucketLabels, Value: float64(bucket.Count)})
+	}
+
+	bucketLabels := labels()
+	bucketLabels["le"] = "+Inf"
+	samples = append(samples, metrics.Sample{Suffix: "_bucket", Labels: bucketLabels, Value: float64(histogram.Count)})
+
+	// Some drivers only know the distribution of the latencies and not their sum.
+	if histogram.Sum >= 0 {
+		samples = append(samples, metrics.Sample{Suffix: "_sum", Labels: labels(), Value: histogram.Sum})
+	}
+
+	samples = append(samples, metrics.Sample{Suffix: "_count", Labels: labels(), Value: float64(histogram.Count)})
+
+	return samples
+}
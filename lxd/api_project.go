@@ -7,11 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -22,14 +25,18 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/network"
 	"github.com/canonical/lxd/lxd/node"
+	"github.com/canonical/lxd/lxd/notifications"
 	"github.com/canonical/lxd/lxd/operations"
 	projecthelpers "github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/scriptlet"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
@@ -41,6 +48,9 @@ import (
 	"github.com/canonical/lxd/shared/version"
 )
 
+// projectDeleteProtectionMaxUnlock is how long the deletion protection of a project can be lifted for at once.
+const projectDeleteProtectionMaxUnlock = 24 * time.Hour
+
 var projectsCmd = APIEndpoint{
 	Path:        "projects",
 	MetricsType: entity.TypeProject,
@@ -65,6 +75,7 @@ var projectStateCmd = APIEndpoint{
 	MetricsType: entity.TypeProject,
 
 	Get: APIEndpointAction{Handler: projectStateGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView, "name")},
+	Put: APIEndpointAction{Handler: projectStatePut, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
 }
 
 // swagger:operation GET /1.0/projects projects projects_get
@@ -210,6 +221,11 @@ func projectsGet(d *Daemon, r *http.Request) response.Response {
 
 	for _, apiProject := range apiProjects {
 		apiProject.UsedBy = projecthelpers.FilterUsedBy(r.Context(), s.Authorizer, apiProject.UsedBy)
+
+		err = projectRedactSecrets(r.Context(), s, apiProject)
+		if err != nil {
+			return response.SmartError(err)
+		}
 	}
 
 	if len(withEntitlements) > 0 {
@@ -228,6 +244,30 @@ func projectsGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, apiProjects)
 }
 
+// projectRedactSecrets clears the values of the project secrets unless the caller can edit the project.
+func projectRedactSecrets(ctx context.Context, s *state.State, project *api.Project) error {
+	// Only allow identities that can edit the project to view the secrets as they are only meant for instances.
+	err := s.Authorizer.CheckPermission(ctx, entity.ProjectURL(project.Name), auth.EntitlementCanEdit)
+	if err == nil {
+		return nil
+	} else if !auth.IsDeniedError(err) {
+		return err
+	}
+
+	config := make(map[string]string, len(project.Config))
+	for key, value := range project.Config {
+		if strings.HasPrefix(key, "secret.") {
+			value = ""
+		}
+
+		config[key] = value
+	}
+
+	project.Config = config
+
+	return nil
+}
+
 // projectUsedBy returns a list of URLs for all instances, images, profiles,
 // storage volumes, networks, and acls that use this project.
 func projectUsedBy(ctx context.Context, tx *db.ClusterTx, project *dbCluster.Project) ([]string, error) {
@@ -364,6 +404,13 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Unable to create project config for project %q: %w", project.Name, err)
 		}
 
+		if project.Config["parent"] != "" {
+			err = limits.AllowProjectUpdate(ctx, s.GlobalConfig, tx, project.Name, project.Config, slices.Collect(maps.Keys(project.Config)))
+			if err != nil {
+				return err
+			}
+		}
+
 		if shared.IsTrue(project.Config["features.profiles"]) {
 			err = projectCreateDefaultProfile(ctx, tx, project.Name, project.StoragePool, project.Network)
 			if err != nil {
@@ -378,6 +425,11 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		err = projectValidateDefaultProfiles(ctx, tx, &api.Project{Name: project.Name, Config: project.Config})
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -394,6 +446,28 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponseLocation(true, nil, lc.Source)
 }
 
+// projectValidateDefaultProfiles checks that the profiles set in "instances.default_profiles" exist, so that a
+// typo is reported when the project is changed rather than when creating an instance.
+func projectValidateDefaultProfiles(ctx context.Context, tx *db.ClusterTx, p *api.Project) error {
+	if p.Config["instances.default_profiles"] == "" {
+		return nil
+	}
+
+	profileProject := projecthelpers.ProfileProjectFromRecord(p)
+	for _, profileName := range projecthelpers.DefaultProfilesFromRecord(p, nil) {
+		_, err := dbCluster.GetProfileID(ctx, tx.Tx(), profileProject, profileName)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return api.StatusErrorf(http.StatusBadRequest, "Profile %q listed in %q doesn't exist in project %q", profileName, "instances.default_profiles", profileProject)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Create the default profile of a project.
 func projectCreateDefaultProfile(ctx context.Context, tx *db.ClusterTx, project string, storagePool string, network string) error {
 	// Create a default profile
@@ -517,6 +591,11 @@ func projectGet(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = projectRedactSecrets(r.Context(), s, project)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	etag := []any{
 		project.Description,
 		project.Config,
@@ -799,6 +878,19 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		return response.BadRequest(err)
 	}
 
+	// Only allow lifting the deletion protection for a limited amount of time.
+	var unlockedUntil time.Time
+	if slices.Contains(configChanged, "security.protection.delete.unlocked_until") && req.Config["security.protection.delete.unlocked_until"] != "" {
+		unlockedUntil, _ = time.Parse(time.RFC3339, req.Config["security.protection.delete.unlocked_until"])
+		if !unlockedUntil.After(time.Now()) {
+			return response.BadRequest(errors.New(`"security.protection.delete.unlocked_until" must be in the future`))
+		}
+
+		if unlockedUntil.After(time.Now().Add(projectDeleteProtectionMaxUnlock)) {
+			return response.BadRequest(fmt.Errorf(`"security.protection.delete.unlocked_until" must be within the next %s`, projectDeleteProtectionMaxUnlock))
+		}
+	}
+
 	// Update the database entry.
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		err := limits.AllowProjectUpdate(ctx, s.GlobalConfig, tx, project.Name, req.Config, configChanged)
@@ -833,6 +925,11 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 			}
 		}
 
+		err = projectValidateDefaultProfiles(ctx, tx, &api.Project{Name: project.Name, Config: req.Config})
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -840,6 +937,11 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		return response.SmartError(err)
 	}
 
+	if !unlockedUntil.IsZero() {
+		requestor := request.CreateRequestor(ctx)
+		s.Events.SendLifecycle(project.Name, lifecycle.ProjectDeleteProtectionUnlocked.Event(project.Name, requestor, logger.Ctx{"until": unlockedUntil.UTC().Format(time.RFC3339)}))
+	}
+
 	return response.EmptySyncResponse
 }
 
@@ -978,6 +1080,15 @@ func projectPost(d *Daemon, r *http.Request) response.Response {
 				return errors.New("Only empty projects can be renamed")
 			}
 
+			children, err := limits.ChildProjects(ctx, tx, name)
+			if err != nil {
+				return err
+			}
+
+			if len(children) > 0 {
+				return errors.New("Only projects without child projects can be renamed")
+			}
+
 			err = projecthelpers.ValidName(req.Name)
 			if err != nil {
 				return err
@@ -1139,6 +1250,15 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			return errors.New("Only empty projects can be removed")
 		}
 
+		children, err := limits.ChildProjects(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		if len(children) > 0 {
+			return fmt.Errorf("Project %q can't be removed while it has child projects", name)
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1230,6 +1350,27 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 
 		state.Resources = result
 
+		state.Status, err = dbCluster.GetProjectStatus(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		changes, err := dbCluster.GetProjectStatusChanges(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		state.StatusChanges = make([]api.ProjectStatusChange, 0, len(changes))
+		for _, change := range changes {
+			state.StatusChanges = append(state.StatusChanges, api.ProjectStatusChange{
+				Status:    change.Status,
+				Reason:    change.Reason,
+				Requestor: change.Requestor,
+				Protocol:  change.Protocol,
+				Date:      change.Date,
+			})
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1239,6 +1380,225 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, &state)
 }
 
+// swagger:operation PUT /1.0/projects/{name}/state projects project_state_put
+//
+//	Disable or enable the project
+//
+//	Disables the project, forcefully stopping all its instances and rejecting any further change in it, or enables
+//	it again. The data of the project is preserved. The change is recorded along with its requestor and reason.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: state
+//	    description: Requested project state
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectStatePut"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectStatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ProjectStatePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var status string
+	switch req.Action {
+	case "disable":
+		status = api.ProjectStatusDisabled
+	case "enable":
+		status = api.ProjectStatusActive
+	default:
+		return response.BadRequest(fmt.Errorf("Invalid action %q", req.Action))
+	}
+
+	if name == api.ProjectDefaultName && status == api.ProjectStatusDisabled {
+		return response.BadRequest(errors.New("The default project can't be disabled"))
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+
+	var changed bool
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		current, err := dbCluster.GetProjectStatus(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		// Disabling a disabled project only stops its instances again.
+		if current == status {
+			return nil
+		}
+
+		changed = true
+
+		return dbCluster.CreateProjectStatusChange(ctx, tx.Tx(), name, dbCluster.ProjectStatusChange{
+			Status:    status,
+			Reason:    req.Reason,
+			Requestor: requestor.Username,
+			Protocol:  requestor.Protocol,
+			Date:      time.Now(),
+		})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if changed {
+		action := lifecycle.ProjectEnabled
+		if status == api.ProjectStatusDisabled {
+			action = lifecycle.ProjectDisabled
+		}
+
+		s.Events.SendLifecycle(name, action.Event(name, requestor, logger.Ctx{"reason": req.Reason}))
+	}
+
+	run := func(op *operations.Operation) error {
+		if status == api.ProjectStatusActive {
+			return nil
+		}
+
+		err := projectStopInstances(s, op, name)
+		if err != nil {
+			return err
+		}
+
+		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+		if err != nil {
+			return err
+		}
+
+		return notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			op, err := client.UseProject(name).UpdateInstances(api.InstancesPut{State: &api.InstanceStatePut{Action: string(instancetype.Stop), Force: true}}, "")
+			if err != nil {
+				return err
+			}
+
+			return op.Wait()
+		})
+	}
+
+	resources := map[string][]api.URL{}
+	resources["projects"] = []api.URL{*api.NewURL().Path(version.APIVersion, "projects", name)}
+
+	op, err := operations.OperationCreate(r.Context(), s, name, operations.OperationClassTask, operationtype.ProjectStateUpdate, resources, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// projectStopInstances forcefully stops the running instances of the project on this member.
+func projectStopInstances(s *state.State, op *operations.Operation, projectName string) error {
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return err
+	}
+
+	failures := map[string]error{}
+	failuresLock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for _, inst := range instances {
+		if inst.Project().Name != projectName || !inst.IsRunning() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(inst instance.Instance) {
+			defer wg.Done()
+
+			inst.SetOperation(op)
+			err := inst.Stop(false)
+			if err != nil {
+				failuresLock.Lock()
+				failures[inst.Name()] = err
+				failuresLock.Unlock()
+			}
+		}(inst)
+	}
+
+	wg.Wait()
+
+	return coalesceErrors(true, failures)
+}
+
+// projectDisabledCheck rejects the requests changing anything in a disabled project, apart from enabling or deleting
+// the project and cancelling its operations. The requests forwarded by other cluster members were already checked by
+// the member which received them.
+func projectDisabledCheck(s *state.State, r *http.Request, path string) response.Response {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return nil
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err == nil && (requestor.IsClusterNotification() || requestor.IsForwarded()) {
+		return nil
+	}
+
+	projectName := request.ProjectParam(r)
+	if path == "projects/{name}" || strings.HasPrefix(path, "projects/{name}/") {
+		if path == "projects/{name}/state" || r.Method == http.MethodDelete {
+			return nil
+		}
+
+		projectName, err = url.PathUnescape(mux.Vars(r)["name"])
+		if err != nil {
+			return nil
+		}
+	} else if path == "operations/{id}" && r.Method == http.MethodDelete {
+		return nil
+	}
+
+	// The default project can't be disabled.
+	if projectName == api.ProjectDefaultName {
+		return nil
+	}
+
+	var status string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		status, err = dbCluster.GetProjectStatus(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if status == api.ProjectStatusDisabled {
+		return response.Forbidden(fmt.Errorf("Project %q is disabled", projectName))
+	}
+
+	return nil
+}
+
 // Check if a project is empty.
 func projectIsEmpty(ctx context.Context, project *dbCluster.Project, tx *db.ClusterTx) (bool, error) {
 	usedBy, err := projectUsedBy(ctx, tx, project)
@@ -1273,6 +1633,22 @@ func isEitherAllowOrBlockOrManaged(value string) error {
 func projectValidateConfig(s *state.State, config map[string]string, defaultNetwork string) error {
 	// Validate the project configuration.
 	projectConfigKeys := map[string]func(value string) error{
+		// lxdmeta:generate(entities=project; group=specific; key=admission.scriptlet)
+		// The Starlark scriptlet must define an `admit(request)` function. It is evaluated on every request creating or
+		// updating an instance, storage volume or network in the project, after the server-wide
+		// {config:option}`server-miscellaneous:admission.scriptlet`.
+		// ---
+		//  type: string
+		//  shortdesc: Admission scriptlet for create and update requests in the project
+		"admission.scriptlet": validate.Optional(scriptlet.AdmissionValidate),
+		// lxdmeta:generate(entities=project; group=specific; key=alerts.instance_restarts)
+		// The alert fires when an instance of the project restarts at least this number of times within 10 minutes.
+		// Set to `0` to disable the alert for the project.
+		// ---
+		//  type: integer
+		//  defaultdesc: value of {config:option}`server-alerts:alerts.instance_restarts`
+		//  shortdesc: Number of restarts after which to alert on an instance restart loop
+		"alerts.instance_restarts": validate.Optional(validate.IsUint32),
 		// lxdmeta:generate(entities=project; group=specific; key=backups.compression_algorithm)
 		// Specify which compression algorithm to use for backups in this project.
 		// Possible values are `bzip2`, `gzip`, `lzma`, `xz`, or `none`.
@@ -1328,6 +1704,14 @@ func projectValidateConfig(s *state.State, config map[string]string, defaultNetw
 		//  initialvaluedesc: `false`
 		//  shortdesc: Whether to use a separate set of network zones for the project
 		"features.networks.zones": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=project; group=specific; key=images.allowed_servers)
+		// Specify a comma-separated list of the image server URLs that the images of the project can be downloaded from.
+		// An entry also allows the URLs below it.
+		// ---
+		//  type: string
+		//  defaultdesc: value of {config:option}`server-images:images.allowed_servers`
+		//  shortdesc: Image servers that the images of the project can be downloaded from
+		"images.allowed_servers": validate.Optional(validate.IsListOf(validate.IsRequestURL)),
 		// lxdmeta:generate(entities=project; group=specific; key=images.auto_update_cached)
 		//
 		// ---
@@ -1359,6 +1743,85 @@ func projectValidateConfig(s *state.State, config map[string]string, defaultNetw
 		//  type: integer
 		//  shortdesc: When an unused cached remote image is flushed in the project
 		"images.remote_cache_expiry": validate.Optional(validate.IsInt64),
+		// lxdmeta:generate(entities=project; group=specific; key=instances.default_profiles)
+		// Specify a comma-separated list of profiles to apply, in order, to the new instances of the project that don't specify any profiles.
+		// This also takes precedence over the profiles of the image the instance is created from.
+		// The profiles are those of the project if {config:option}`project-features:features.profiles` is enabled, and otherwise those of the `default` project.
+		// They must exist when the option is set.
+		// ---
+		//  type: string
+		//  defaultdesc: `default`
+		//  shortdesc: Profiles to apply to new instances
+		"instances.default_profiles": validate.Optional(validate.IsListOf(validate.IsNotEmpty)),
+		// lxdmeta:generate(entities=project; group=specific; key=security.protection.delete)
+		// When enabled, the instances and custom storage volumes of the project can't be deleted.
+		// Instances that set {config:option}`instance-security:security.protection.delete` themselves keep their own value.
+		// See {ref}`project-delete-protection` for how to temporarily lift the protection.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to protect the instances and volumes of the project from deletion
+		"security.protection.delete": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=project; group=specific; key=security.protection.delete.unlocked_until)
+		// Specify a date in RFC3339 format until which {config:option}`project-specific:security.protection.delete` is lifted.
+		// The date must be within the next 24 hours when set.
+		// ---
+		//  type: string
+		//  shortdesc: Date until which the deletion protection of the project is lifted
+		"security.protection.delete.unlocked_until": validate.Optional(func(value string) error {
+			_, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return errors.New("Invalid date, expected RFC3339 format")
+			}
+
+			return nil
+		}),
+		// lxdmeta:generate(entities=project; group=specific; key=sessions.recording)
+		// Specify a comma-separated list of the session types to record for the instances of the project.
+		// Possible values are `exec` and `console`.
+		//
+		// Recordings are stored in the asciicast format alongside the instance logs.
+		// See {ref}`instances-access-recording` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Which instance sessions to record
+		"sessions.recording": validate.Optional(validate.IsListOf(validate.IsOneOf("exec", "console"))),
+		// lxdmeta:generate(entities=project; group=notifications; key=notifications.email.to)
+		// Specify a comma-separated list of email addresses to send the notifications of the project to.
+		// Emails are sent through the SMTP server configured in {config:option}`server-notifications:smtp.address`.
+		// ---
+		//  type: string
+		//  shortdesc: Email addresses to notify
+		"notifications.email.to": validate.Optional(validate.IsListOf(validate.IsEmailAddress)),
+		// lxdmeta:generate(entities=project; group=notifications; key=notifications.types)
+		// Specify a comma-separated list of the notification types to send.
+		// Possible values are `lifecycle`, `operation`, `alert` and `warning`.
+		// ---
+		//  type: string
+		//  defaultdesc: `lifecycle,alert,warning`
+		//  shortdesc: Types of notifications to send
+		"notifications.types": validate.Optional(validate.IsListOf(validate.IsOneOf(api.EventTypeLifecycle, api.EventTypeOperation, api.EventTypeAlert, notifications.TypeWarning))),
+		// lxdmeta:generate(entities=project; group=notifications; key=notifications.webhook.secret)
+		// The secret is used to sign the requests sent to the webhooks of the project.
+		// ---
+		//  type: string
+		//  shortdesc: Secret to sign the webhook requests with
+		"notifications.webhook.secret": validate.IsAny,
+		// lxdmeta:generate(entities=project; group=notifications; key=notifications.webhook.url)
+		// Specify a comma-separated list of URLs to send the notifications of the project to.
+		// Only the events and warnings of the project are sent.
+		// ---
+		//  type: string
+		//  shortdesc: Webhooks to notify
+		"notifications.webhook.url": validate.Optional(validate.IsListOf(validate.IsRequestURL)),
+		// lxdmeta:generate(entities=project; group=specific; key=parent)
+		// Specify the name of the parent project to nest the project under it.
+		// The limits of the project can't exceed the remaining quota of the parent, and the usage of the project counts towards the limits of all its ancestors.
+		// See {ref}`project-hierarchy` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Parent project
+		"parent": validate.Optional(projecthelpers.ValidName),
 		// lxdmeta:generate(entities=project; group=limits; key=limits.instances)
 		//
 		// ---
@@ -1383,6 +1846,22 @@ func projectValidateConfig(s *state.State, config map[string]string, defaultNetw
 		//  type: string
 		//  shortdesc: Usage limit for the host's memory for the project
 		"limits.memory": validate.Optional(validate.IsSize),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.operations.concurrent)
+		// The limit applies on each cluster member to the background operations requested through the API, such as image imports, instance creations or backups.
+		// Additional operations wait in the `Pending` state until a running operation of the project completes.
+		// Operations started by LXD itself aren't limited.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of operations running at the same time in the project
+		"limits.operations.concurrent": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.operations.queued)
+		// This value is the maximum number of operations waiting for {config:option}`project-limits:limits.operations.concurrent` on each cluster member.
+		// Additional operations are rejected with a `429 Too Many Requests` error.
+		// If unset, the number of waiting operations isn't limited.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of operations waiting to run in the project
+		"limits.operations.queued": validate.Optional(validate.IsUint32),
 		// lxdmeta:generate(entities=project; group=limits; key=limits.processes)
 		// This value is the maximum value for the sum of the individual {config:option}`instance-resource-limits:limits.processes` configurations set on the instances of the project.
 		// ---
@@ -1735,6 +2214,15 @@ func projectValidateConfig(s *state.State, config map[string]string, defaultNetw
 			continue
 		}
 
+		// lxdmeta:generate(entities=project; group=specific; key=secret.*)
+		// The values are made available to instances through `secret` devices.
+		// ---
+		//  type: string
+		//  shortdesc: Secret values that can be injected into instances
+		if strings.HasPrefix(key, "secret.") {
+			continue
+		}
+
 		// Then validate.
 		validator, ok := projectConfigKeys[key]
 		if !ok {
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	projecthelpers "github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var projectExportCmd = APIEndpoint{
+	Path:        "projects/{name}/export",
+	MetricsType: entity.TypeProject,
+
+	Get: APIEndpointAction{Handler: projectExportGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanEdit, "name")},
+}
+
+// swagger:operation GET /1.0/projects/{name}/export projects project_export_get
+//
+//	Export the project configuration
+//
+//	Download a compressed tarball holding the configuration of the project and of its profiles,
+//	networks, network ACLs and network zones. No instance or volume data is included.
+//
+//	The `index.yaml` file of the tarball holds a ProjectExport.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	responses:
+//	  "200":
+//	    description: Raw file data
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectExportGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var export *api.ProjectExport
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		export, err = projectExport(ctx, tx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	index, err := yaml.Marshal(export)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// Wrap the index into a compressed tarball.
+	modified := time.Now()
+	buf := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    "index.yaml",
+		Mode:    0600,
+		Size:    int64(len(index)),
+		ModTime: modified,
+	})
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	_, err = tarWriter.Write(index)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	filename := "project-" + name + ".tar.gz"
+	files := []response.FileResponseEntry{
+		{
+			Identifier:   filename,
+			Filename:     filename,
+			File:         bytes.NewReader(buf.Bytes()),
+			FileSize:     int64(buf.Len()),
+			FileModified: modified,
+		},
+	}
+
+	return response.FileResponse(files, nil)
+}
+
+// projectExport gathers the configuration of a project and of the entities it owns.
+// Entities that the project shares with the default project aren't included.
+func projectExport(ctx context.Context, tx *db.ClusterTx, projectName string) (*api.ProjectExport, error) {
+	dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := dbProject.ToAPI(ctx, tx.Tx())
+	if err != nil {
+		return nil, err
+	}
+
+	export := &api.ProjectExport{
+		Project: api.ProjectsPost{
+			Name: project.Name,
+			ProjectPut: api.ProjectPut{
+				Description: project.Description,
+				Config:      project.Config,
+			},
+		},
+		Profiles:     []api.ProfilesPost{},
+		Networks:     []api.NetworksPost{},
+		NetworkACLs:  []api.NetworkACLsPost{},
+		NetworkZones: []api.ProjectExportNetworkZone{},
+	}
+
+	// Profiles.
+	if projecthelpers.ProfileProjectFromRecord(project) == projectName {
+		profiles, err := dbCluster.GetProfiles(ctx, tx.Tx(), dbCluster.ProfileFilter{Project: &projectName})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading profiles: %w", err)
+		}
+
+		for _, profile := range profiles {
+			apiProfile, err := profile.ToAPI(ctx, tx.Tx(), nil, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			export.Profiles = append(export.Profiles, api.ProfilesPost{
+				Name:       apiProfile.Name,
+				ProfilePut: apiProfile.Writable(),
+			})
+		}
+	}
+
+	if projecthelpers.NetworkProjectFromRecord(project) == projectName {
+		// Networks.
+		networks, err := tx.GetCreatedNetworksByProject(ctx, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading networks: %w", err)
+		}
+
+		for _, network := range networks {
+			// Member specific and volatile keys don't apply to another deployment.
+			config := make(map[string]string, len(network.Config))
+			for key, value := range network.Config {
+				if slices.Contains(db.NodeSpecificNetworkConfig, key) || strings.HasPrefix(key, "volatile.") {
+					continue
+				}
+
+				config[key] = value
+			}
+
+			export.Networks = append(export.Networks, api.NetworksPost{
+				Name: network.Name,
+				Type: network.Type,
+				NetworkPut: api.NetworkPut{
+					Description: network.Description,
+					Config:      config,
+				},
+			})
+		}
+
+		sort.Slice(export.Networks, func(i, j int) bool { return export.Networks[i].Name < export.Networks[j].Name })
+
+		// Network ACLs.
+		aclNames, err := tx.GetNetworkACLs(ctx, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network ACLs: %w", err)
+		}
+
+		for _, aclName := range aclNames {
+			_, acl, err := tx.GetNetworkACL(ctx, projectName, aclName)
+			if err != nil {
+				return nil, err
+			}
+
+			export.NetworkACLs = append(export.NetworkACLs, api.NetworkACLsPost{
+				NetworkACLPost: api.NetworkACLPost{Name: acl.Name},
+				NetworkACLPut:  acl.Writable(),
+			})
+		}
+	}
+
+	// Network zones.
+	if projecthelpers.NetworkZoneProjectFromRecord(project) == projectName {
+		zoneNames, err := tx.GetNetworkZonesByProject(ctx, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network zones: %w", err)
+		}
+
+		for _, zoneName := range zoneNames {
+			zoneID, zone, err := tx.GetNetworkZoneByProject(ctx, projectName, zoneName)
+			if err != nil {
+				return nil, err
+			}
+
+			exportZone := api.ProjectExportNetworkZone{
+				NetworkZonesPost: api.NetworkZonesPost{
+					Name:           zone.Name,
+					NetworkZonePut: zone.Writable(),
+				},
+				Records: []api.NetworkZoneRecordsPost{},
+			}
+
+			recordNames, err := tx.GetNetworkZoneRecordNames(ctx, zoneID)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, recordName := range recordNames {
+				_, record, err := tx.GetNetworkZoneRecord(ctx, zoneID, recordName)
+				if err != nil {
+					return nil, err
+				}
+
+				exportZone.Records = append(exportZone.Records, api.NetworkZoneRecordsPost{
+					Name:                 record.Name,
+					NetworkZoneRecordPut: record.Writable(),
+				})
+			}
+
+			export.NetworkZones = append(export.NetworkZones, exportZone)
+		}
+	}
+
+	return export, nil
+}
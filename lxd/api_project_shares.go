@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var projectSharesCmd = APIEndpoint{
+	Path:        "projects/{name}/shares",
+	MetricsType: entity.TypeProject,
+
+	Get: APIEndpointAction{Handler: projectSharesGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView, "name")},
+	Put: APIEndpointAction{Handler: projectSharesPut, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanEdit, "name")},
+}
+
+// swagger:operation GET /1.0/projects/{name}/shares projects project_shares_get
+//
+//	Get the project shares
+//
+//	Returns the shares of the images, networks and storage volumes of the project with other projects.
+//	When `received` is set, returns the entities of other projects that are shared with the project instead.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: received
+//	    description: Whether to return the entities shared with the project
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: Project shares
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: Project shares
+//	          items:
+//	            $ref: "#/definitions/ProjectShare"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectSharesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	received := shared.IsTrue(request.QueryParam(r, "received"))
+
+	var shares []api.ProjectShare
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		if received {
+			shares, err = getReceivedProjectShares(ctx, tx.Tx(), name)
+			return err
+		}
+
+		shares, err = getProjectShares(ctx, tx.Tx(), name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, shares, shares)
+}
+
+// getProjectShares returns the shares of the entities of the given project.
+func getProjectShares(ctx context.Context, tx *sql.Tx, projectName string) ([]api.ProjectShare, error) {
+	dbShares, entityURLs, err := dbCluster.GetProjectShares(ctx, tx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]api.ProjectShare, 0, len(dbShares))
+	for _, dbShare := range dbShares {
+		shares = append(shares, dbShare.ToAPI(entityURLs[entity.Type(dbShare.EntityType)][dbShare.EntityID]))
+	}
+
+	return shares, nil
+}
+
+// getReceivedProjectShares returns the shares of the entities of other projects with the given project.
+func getReceivedProjectShares(ctx context.Context, tx *sql.Tx, projectName string) ([]api.ProjectShare, error) {
+	dbShares, err := dbCluster.GetReceivedProjectShares(ctx, tx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]api.ProjectShare, 0, len(dbShares))
+	for _, dbShare := range dbShares {
+		u, err := dbCluster.GetEntityURL(ctx, tx, entity.Type(dbShare.EntityType), dbShare.EntityID)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, dbShare.ToAPI(u))
+	}
+
+	return shares, nil
+}
+
+// swagger:operation PUT /1.0/projects/{name}/shares projects project_shares_put
+//
+//	Update the project shares
+//
+//	Replaces the shares of the images, networks and storage volumes of the project with other projects.
+//	Only custom storage volumes can be shared.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: shares
+//	    description: Project shares
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectSharesPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectSharesPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ProjectSharesPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		current, err := getProjectShares(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		err = util.EtagCheck(r, current)
+		if err != nil {
+			return err
+		}
+
+		shares, err := projectSharesFromAPI(ctx, tx.Tx(), name, req.Shares)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.SetProjectShares(ctx, tx.Tx(), name, shares)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+	s.Events.SendLifecycle(name, lifecycle.ProjectUpdated.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// projectSharesFromAPI validates the given shares of the entities of the given project and converts them to their
+// database representation.
+func projectSharesFromAPI(ctx context.Context, tx *sql.Tx, projectName string, apiShares []api.ProjectShare) ([]dbCluster.ProjectShare, error) {
+	shares := make([]dbCluster.ProjectShare, 0, len(apiShares))
+	for _, apiShare := range apiShares {
+		entityType := entity.Type(apiShare.EntityType)
+		if !slices.Contains(dbCluster.ProjectShareEntityTypes, entityType) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Entities of type %q can't be shared", apiShare.EntityType)
+		}
+
+		if apiShare.Access != api.ProjectShareAccessReadOnly && apiShare.Access != api.ProjectShareAccessAttach {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid access %q for share of %q", apiShare.Access, apiShare.EntityReference)
+		}
+
+		u, err := url.Parse(apiShare.EntityReference)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Failed to parse entity reference %q: %w", apiShare.EntityReference, err)
+		}
+
+		referenceEntityType, referenceProjectName, _, pathArgs, err := entity.ParseURL(*u)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Failed to parse entity reference %q: %w", apiShare.EntityReference, err)
+		}
+
+		if referenceEntityType != entityType {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Entity type %q does not correspond to entity reference %q", apiShare.EntityType, apiShare.EntityReference)
+		}
+
+		if referenceProjectName != projectName {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Entity %q isn't part of project %q", apiShare.EntityReference, projectName)
+		}
+
+		if entityType == entity.TypeStorageVolume && (len(pathArgs) < 2 || pathArgs[1] != dbCluster.StoragePoolVolumeTypeNameCustom) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Only custom storage volumes can be shared")
+		}
+
+		if apiShare.Project == projectName {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Entity %q can't be shared with its own project", apiShare.EntityReference)
+		}
+
+		entityRef, err := dbCluster.GetEntityReferenceFromURL(ctx, tx, &api.URL{URL: *u})
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Failed to find entity %q: %w", apiShare.EntityReference, err)
+		}
+
+		projectID, err := dbCluster.GetProjectID(ctx, tx, apiShare.Project)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Failed to find project %q: %w", apiShare.Project, err)
+		}
+
+		for _, share := range shares {
+			if share.EntityType == entityRef.EntityType && share.EntityID == entityRef.EntityID && share.ProjectID == int(projectID) {
+				return nil, api.StatusErrorf(http.StatusBadRequest, "Entity %q is shared with project %q more than once", apiShare.EntityReference, apiShare.Project)
+			}
+		}
+
+		shares = append(shares, dbCluster.ProjectShare{
+			EntityType: entityRef.EntityType,
+			EntityID:   entityRef.EntityID,
+			ProjectID:  int(projectID),
+			Access:     apiShare.Access,
+		})
+	}
+
+	return shares, nil
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/resources"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var projectUsageCmd = APIEndpoint{
+	Path:        "projects/{name}/usage",
+	MetricsType: entity.TypeProject,
+
+	Get: APIEndpointAction{Handler: projectUsageGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView, "name")},
+}
+
+// projectUsageDefaultRange is the time range covered by the usage when no start is given.
+const projectUsageDefaultRange = 30 * 24 * time.Hour
+
+// swagger:operation GET /1.0/projects/{name}/usage projects project_usage_get
+//
+//	Get the project usage
+//
+//	Returns the API calls, instance hours, storage and network usage of the project, aggregated per hour, day or
+//	month (UTC), as recorded when `core.projects_usage_retention` is set.
+//	Only the periods with recorded usage are returned.
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - text/csv
+//	parameters:
+//	  - in: query
+//	    name: period
+//	    description: Aggregation period ("hour", "day" or "month", defaults to "day")
+//	    type: string
+//	    example: month
+//	  - in: query
+//	    name: since
+//	    description: Start of the time range (RFC3339, defaults to 30 days ago)
+//	    type: string
+//	    example: 2024-01-01T00:00:00Z
+//	  - in: query
+//	    name: until
+//	    description: End of the time range (RFC3339, defaults to now)
+//	    type: string
+//	    example: 2024-02-01T00:00:00Z
+//	  - in: query
+//	    name: format
+//	    description: Response format ("json" or "csv", defaults to "json")
+//	    type: string
+//	    example: csv
+//	responses:
+//	  "200":
+//	    description: Project usage
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: Usage per period
+//	          items:
+//	            $ref: "#/definitions/ProjectUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	period := request.QueryParam(r, "period")
+	if period == "" {
+		period = "day"
+	}
+
+	if period != "hour" && period != "day" && period != "month" {
+		return response.BadRequest(fmt.Errorf("Invalid period %q", period))
+	}
+
+	format := request.QueryParam(r, "format")
+	if format != "" && format != "json" && format != "csv" {
+		return response.BadRequest(fmt.Errorf("Invalid format %q", format))
+	}
+
+	until := time.Now()
+	if request.QueryParam(r, "until") != "" {
+		until, err = time.Parse(time.RFC3339, request.QueryParam(r, "until"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid end time: %w", err))
+		}
+	}
+
+	since := until.Add(-projectUsageDefaultRange)
+	if request.QueryParam(r, "since") != "" {
+		since, err = time.Parse(time.RFC3339, request.QueryParam(r, "since"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid start time: %w", err))
+		}
+	}
+
+	if !since.Before(until) {
+		return response.BadRequest(errors.New("The start time must be before the end time"))
+	}
+
+	var hourly []dbCluster.ProjectUsage
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		hourly, err = dbCluster.GetProjectUsage(ctx, tx.Tx(), name, projectUsagePeriodStart(since, period), until)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	usage := projectUsageAggregate(hourly, period)
+
+	if format != "csv" {
+		return response.SyncResponse(true, usage)
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename="+name+"-usage.csv")
+
+		out := csv.NewWriter(w)
+
+		err := out.Write([]string{"start", "end", "api_calls", "instance_hours", "storage_gb_hours", "network_bytes"})
+		if err != nil {
+			return err
+		}
+
+		for _, u := range usage {
+			err := out.Write([]string{
+				u.Start.Format(time.RFC3339),
+				u.End.Format(time.RFC3339),
+				strconv.FormatInt(u.APICalls, 10),
+				strconv.FormatFloat(u.InstanceHours, 'f', -1, 64),
+				strconv.FormatFloat(u.StorageGBHours, 'f', -1, 64),
+				strconv.FormatInt(u.NetworkBytes, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		out.Flush()
+
+		return out.Error()
+	})
+}
+
+// projectUsagePeriodStart returns the start of the period, in UTC, containing the given time.
+func projectUsagePeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+
+	switch period {
+	case "hour":
+		return t.Truncate(time.Hour)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// projectUsageAggregate sums the hourly usage, ordered from the oldest to the newest hour, per period.
+func projectUsageAggregate(hourly []dbCluster.ProjectUsage, period string) []api.ProjectUsage {
+	usage := []api.ProjectUsage{}
+
+	for _, u := range hourly {
+		start := projectUsagePeriodStart(u.PeriodStart, period)
+		if len(usage) == 0 || !usage[len(usage)-1].Start.Equal(start) {
+			end := start.Add(time.Hour)
+			switch period {
+			case "day":
+				end = start.AddDate(0, 0, 1)
+			case "month":
+				end = start.AddDate(0, 1, 0)
+			}
+
+			usage = append(usage, api.ProjectUsage{Start: start, End: end})
+		}
+
+		last := &usage[len(usage)-1]
+		last.APICalls += u.APICalls
+		last.InstanceHours += float64(u.InstanceSeconds) / 3600
+		last.StorageGBHours += float64(u.StorageByteSeconds) / 1e9 / 3600
+		last.NetworkBytes += u.NetworkBytes
+	}
+
+	return usage
+}
+
+// projectsUsageInterval is how often the usage of the projects is recorded.
+const projectsUsageInterval = 5 * time.Minute
+
+// projectsUsageTask records the usage of the projects on this member, and adds it to the hourly usage in the
+// database. The API calls, instance time and network traffic are recorded by each member for its own instances,
+// while the storage, which is shared by all members, is recorded by the leader.
+func projectsUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	// Network counters of the host interfaces of the instances at the previous run, to record the traffic in
+	// between.
+	lastCounters := map[string]int64{}
+
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Always take the request counts, so that they don't accumulate while the accounting is disabled.
+		requests := metrics.TakeProjectRequests()
+
+		retention := s.GlobalConfig.ProjectsUsageRetention()
+		if retention <= 0 {
+			clear(lastCounters)
+			return
+		}
+
+		usage := map[string]*dbCluster.ProjectUsage{}
+		projectUsage := func(projectName string) *dbCluster.ProjectUsage {
+			u, ok := usage[projectName]
+			if !ok {
+				u = &dbCluster.ProjectUsage{Project: projectName, PeriodStart: time.Now().UTC().Truncate(time.Hour)}
+				usage[projectName] = u
+			}
+
+			return u
+		}
+
+		for projectName, count := range requests {
+			projectUsage(projectName).APICalls += count
+		}
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Warn("Failed loading instances for projects usage", logger.Ctx{"err": err})
+		}
+
+		counters := map[string]int64{}
+		for _, inst := range instances {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			u := projectUsage(inst.Project().Name)
+			u.InstanceSeconds += int64(projectsUsageInterval.Seconds())
+
+			for devName, devConfig := range inst.ExpandedDevices() {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				hostName := inst.LocalConfig()["volatile."+devName+".host_name"]
+				if hostName == "" {
+					continue
+				}
+
+				nicCounters, err := resources.GetNetworkCounters(hostName)
+				if err != nil {
+					continue
+				}
+
+				total := nicCounters.BytesReceived + nicCounters.BytesSent
+				counters[hostName] = total
+
+				// Counters are reset when the interface is recreated.
+				previous, ok := lastCounters[hostName]
+				if ok && total >= previous {
+					u.NetworkBytes += total - previous
+				}
+			}
+		}
+
+		lastCounters = counters
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Warn("Failed getting cluster leader for projects usage", logger.Ctx{"err": err})
+			return
+		}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			if leaderInfo.Leader {
+				projectNames, err := dbCluster.GetProjectNames(ctx, tx.Tx())
+				if err != nil {
+					return err
+				}
+
+				for _, projectName := range projectNames {
+					allocations, err := limits.GetCurrentAllocations(ctx, s.GlobalConfig.Dump(), tx, projectName)
+					if err != nil {
+						return err
+					}
+
+					disk := allocations["disk"].Usage
+					if disk > 0 {
+						projectUsage(projectName).StorageByteSeconds += disk * int64(projectsUsageInterval.Seconds())
+					}
+				}
+
+				err = dbCluster.DeleteProjectsUsageBefore(ctx, tx.Tx(), time.Now().Add(-retention))
+				if err != nil {
+					return err
+				}
+			}
+
+			projectsUsage := make([]dbCluster.ProjectUsage, 0, len(usage))
+			for _, u := range usage {
+				projectsUsage = append(projectsUsage, *u)
+			}
+
+			return dbCluster.AddProjectsUsage(ctx, tx.Tx(), tx.GetNodeID(), projectsUsage)
+		})
+		if err != nil {
+			logger.Warn("Failed recording projects usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(projectsUsageInterval)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_projectUsagePeriodStart(t *testing.T) {
+	now := time.Date(2026, 10, 17, 13, 45, 10, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	assert.Equal(t, time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC), projectUsagePeriodStart(now, "hour"))
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), projectUsagePeriodStart(now, "day"))
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), projectUsagePeriodStart(now, "month"))
+}
+
+func Test_projectUsageAggregate(t *testing.T) {
+	hourly := []dbCluster.ProjectUsage{
+		{PeriodStart: time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC), APICalls: 1, InstanceSeconds: 3600, StorageByteSeconds: 2e9 * 3600, NetworkBytes: 10},
+		{PeriodStart: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC), APICalls: 2, InstanceSeconds: 1800, NetworkBytes: 20},
+		{PeriodStart: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC), APICalls: 3, InstanceSeconds: 1800, StorageByteSeconds: 1e9 * 3600},
+		{PeriodStart: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), APICalls: 4},
+	}
+
+	assert.Equal(t, []api.ProjectUsage{
+		{Start: time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), APICalls: 1, InstanceHours: 1, StorageGBHours: 2, NetworkBytes: 10},
+		{Start: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC), APICalls: 2, InstanceHours: 0.5, NetworkBytes: 20},
+		{Start: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), APICalls: 3, InstanceHours: 0.5, StorageGBHours: 1},
+		{Start: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 2, 1, 0, 0, 0, time.UTC), APICalls: 4},
+	}, projectUsageAggregate(hourly, "hour"))
+
+	assert.Equal(t, []api.ProjectUsage{
+		{Start: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), APICalls: 1, InstanceHours: 1, StorageGBHours: 2, NetworkBytes: 10},
+		{Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), APICalls: 5, InstanceHours: 1, StorageGBHours: 1, NetworkBytes: 20},
+		{Start: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), APICalls: 4},
+	}, projectUsageAggregate(hourly, "day"))
+
+	assert.Equal(t, []api.ProjectUsage{
+		{Start: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), APICalls: 1, InstanceHours: 1, StorageGBHours: 2, NetworkBytes: 10},
+		{Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), APICalls: 9, InstanceHours: 1, StorageGBHours: 1, NetworkBytes: 20},
+	}, projectUsageAggregate(hourly, "month"))
+
+	assert.Empty(t, projectUsageAggregate(nil, "day"))
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// requestSizeLimits are the maximum sizes of the API request bodies. A zero size means that there is no limit.
+type requestSizeLimits struct {
+	request   int64
+	upload    int64
+	overrides map[string]int64
+}
+
+// apiRequestSizeLimits holds the current requestSizeLimits.
+var apiRequestSizeLimits atomic.Pointer[requestSizeLimits]
+
+// setupRequestSizeLimits sets the maximum sizes of the API request bodies and uploads, along with their overrides
+// keyed by API path relative to `/1.0/`.
+func (d *Daemon) setupRequestSizeLimits(request int64, upload int64, overrides map[string]int64) {
+	apiRequestSizeLimits.Store(&requestSizeLimits{request: request, upload: upload, overrides: overrides})
+}
+
+// limit returns the maximum size of the body of a request to the given path, relative to `/1.0/`.
+func (l *requestSizeLimits) limit(path string, upload bool) int64 {
+	// The most specific override matching whole path segments wins.
+	matched := -1
+	var limit int64
+	for prefix, size := range l.overrides {
+		if len(prefix) <= matched || (path != prefix && !strings.HasPrefix(path, prefix+"/")) {
+			continue
+		}
+
+		matched = len(prefix)
+		limit = size
+	}
+
+	if matched >= 0 {
+		return limit
+	}
+
+	if upload {
+		return l.upload
+	}
+
+	return l.request
+}
+
+// limitRequestBody enforces the maximum size of the body of an API request. JSON bodies are read in full so that
+// handlers never see a truncated body, while uploads are streamed and fail once they exceed the limit.
+// It returns a non-nil response if the request must be rejected.
+func limitRequestBody(r *http.Request, entityType entity.Type) response.Response {
+	limits := apiRequestSizeLimits.Load()
+	if limits == nil || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	// Bodies of any other type than JSON are binary uploads, which not all endpoints declare in their content types.
+	contentType := shared.SplitNTrimSpace(r.Header.Get("Content-Type"), ";", 2, false)[0]
+	upload := contentType != "" && contentType != "application/json"
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/1.0"), "/")
+	limit := limits.limit(path, upload)
+	if limit <= 0 {
+		return nil
+	}
+
+	tooLarge := func() error {
+		metrics.TrackRequestTooLarge(entityType)
+		logger.Warn("Rejected too large API request", logger.Ctx{"method": r.Method, "url": r.URL.RequestURI(), "ip": r.RemoteAddr, "limit": limit})
+
+		return api.StatusErrorf(http.StatusRequestEntityTooLarge, "Request body exceeds the maximum size of %s", units.GetByteSizeStringIEC(limit, 2))
+	}
+
+	if r.ContentLength > limit {
+		return response.SmartError(tooLarge())
+	}
+
+	if upload {
+		r.Body = &limitedBody{ReadCloser: r.Body, remaining: limit, tooLarge: tooLarge}
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	_, err := io.Copy(buf, io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Failed reading request body: %w", err))
+	}
+
+	_ = r.Body.Close()
+
+	if int64(buf.Len()) > limit {
+		return response.SmartError(tooLarge())
+	}
+
+	r.Body = shared.BytesReadCloser{Buf: buf}
+
+	return nil
+}
+
+// limitedBody is a request body which fails once more than the remaining bytes are read from it.
+type limitedBody struct {
+	io.ReadCloser
+
+	remaining int64
+	tooLarge  func() error
+	err       error
+}
+
+// Read reads from the body, failing with a 413 error once the limit is exceeded.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	// Read one byte past the limit to detect bodies exceeding it.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		b.err = b.tooLarge()
+		return int(b.remaining), b.err
+	}
+
+	b.remaining -= int64(n)
+
+	return n, err
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func Test_requestSizeLimits_limit(t *testing.T) {
+	limits := &requestSizeLimits{
+		request: 100,
+		upload:  1000,
+		overrides: map[string]int64{
+			"images":         5000,
+			"images/aliases": 200,
+			"instances":      0,
+		},
+	}
+
+	tests := []struct {
+		path     string
+		upload   bool
+		expected int64
+	}{
+		{path: "networks", expected: 100},
+		{path: "networks", upload: true, expected: 1000},
+		{path: "images", expected: 5000},
+		{path: "images", upload: true, expected: 5000},
+		{path: "images/abcdef/export", expected: 5000},
+		{path: "images/aliases/foo", expected: 200},
+		{path: "imagesfoo", expected: 100},
+		{path: "instances/c1/files", upload: true, expected: 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, limits.limit(tt.path, tt.upload), "path %q (upload %v)", tt.path, tt.upload)
+	}
+}
+
+func Test_limitRequestBody(t *testing.T) {
+	apiRequestSizeLimits.Store(&requestSizeLimits{request: 10, upload: 20, overrides: map[string]int64{"instances": 0}})
+	defer apiRequestSizeLimits.Store(nil)
+
+	newRequest := func(path string, contentType string, body string, knownLength bool) *http.Request {
+		var reader io.Reader = strings.NewReader(body)
+		if !knownLength {
+			reader = io.MultiReader(reader)
+		}
+
+		r := httptest.NewRequest(http.MethodPost, path, reader)
+		r.Header.Set("Content-Type", contentType)
+		if !knownLength {
+			r.ContentLength = -1
+		}
+
+		return r
+	}
+
+	// JSON bodies within the limit are passed on in full.
+	r := newRequest("/1.0/networks", "application/json", `{"a":"b"}`, false)
+	assert.Nil(t, limitRequestBody(r, entity.TypeNetwork))
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"b"}`, string(body))
+
+	// JSON bodies exceeding the limit are rejected, whether their length is announced or not.
+	for _, knownLength := range []bool{true, false} {
+		r = newRequest("/1.0/networks", "application/json", `{"a":"bcdef"}`, knownLength)
+		resp := limitRequestBody(r, entity.TypeNetwork)
+		require.NotNil(t, resp)
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, resp.Render(rec, r))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	}
+
+	// Uploads are streamed and fail once they exceed their own limit.
+	r = newRequest("/1.0/images", "application/octet-stream", strings.Repeat("x", 20), false)
+	assert.Nil(t, limitRequestBody(r, entity.TypeImage))
+	body, err = io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Len(t, body, 20)
+
+	r = newRequest("/1.0/images", "application/octet-stream", strings.Repeat("x", 30), false)
+	assert.Nil(t, limitRequestBody(r, entity.TypeImage))
+	body, err = io.ReadAll(r.Body)
+	assert.Len(t, body, 20)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusRequestEntityTooLarge))
+
+	// Uploads announcing a length above the limit are rejected upfront.
+	r = newRequest("/1.0/images", "application/octet-stream", strings.Repeat("x", 30), true)
+	assert.NotNil(t, limitRequestBody(r, entity.TypeImage))
+
+	// Overrides disable the limit.
+	r = newRequest("/1.0/instances", "application/json", strings.Repeat("x", 100), true)
+	assert.Nil(t, limitRequestBody(r, entity.TypeInstance))
+	body, err = io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Len(t, body, 100)
+
+	// Requests without a body are left alone.
+	r = httptest.NewRequest(http.MethodGet, "/1.0/networks", nil)
+	assert.Nil(t, limitRequestBody(r, entity.TypeNetwork))
+
+	// Nothing is enforced until the limits are set.
+	apiRequestSizeLimits.Store(nil)
+	r = newRequest("/1.0/networks", "application/json", strings.Repeat("x", 100), true)
+	assert.Nil(t, limitRequestBody(r, entity.TypeNetwork))
+}
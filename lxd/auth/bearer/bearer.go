@@ -91,11 +91,26 @@ func Authenticate(token string, subject string, identityCache *identity.Cache) (
 	}
 
 	// Verify the token.
-	_, err = parser.Parse(token, keyFunc)
+	t, err := parser.Parse(token, keyFunc)
 	if err != nil {
 		return nil, api.StatusErrorf(http.StatusForbidden, "Token is not valid: %w", err)
 	}
 
+	// Reject the tokens issued before the identity revoked its sessions.
+	issuedAt, err := t.Claims.GetIssuedAt()
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Token is not valid: %w", err)
+	}
+
+	var issuedAtTime time.Time
+	if issuedAt != nil {
+		issuedAtTime = issuedAt.Time
+	}
+
+	if entry.SessionRevoked(issuedAtTime) {
+		return nil, api.NewStatusError(http.StatusForbidden, "Token was revoked")
+	}
+
 	return &request.RequestorArgs{
 		Trusted:  true,
 		Protocol: api.AuthenticationMethodBearer,
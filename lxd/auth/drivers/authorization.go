@@ -29,8 +29,12 @@ type authorizer interface {
 type Opts struct {
 	config           map[string]any
 	openfgaDatastore storage.OpenFGADatastore
+	sharedProjects   SharedProjectsFunc
 }
 
+// SharedProjectsFunc returns a map of entity URL to the names of the projects that the entity is shared with.
+type SharedProjectsFunc func(ctx context.Context) (map[string][]string, error)
+
 // WithConfig can be passed into LoadAuthorizer to pass in driver specific configuration.
 func WithConfig(c map[string]any) func(*Opts) {
 	return func(o *Opts) {
@@ -45,6 +49,14 @@ func WithOpenFGADatastore(store storage.OpenFGADatastore) func(*Opts) {
 	}
 }
 
+// WithSharedProjects can be passed into LoadAuthorizer so that restricted TLS clients can view the entities that are
+// shared with their projects.
+func WithSharedProjects(f SharedProjectsFunc) func(*Opts) {
+	return func(o *Opts) {
+		o.sharedProjects = f
+	}
+}
+
 // LoadAuthorizer instantiates, configures, and initialises an Authorizer.
 func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, certificateCache *identity.Cache, options ...func(opts *Opts)) (auth.Authorizer, error) {
 	opts := &Opts{}
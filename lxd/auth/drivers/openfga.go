@@ -193,7 +193,7 @@ func (e *embeddedOpenFGA) checkPermission(ctx context.Context, entityURL *api.UR
 	}
 
 	// Combine the users LXD groups with any mappings that have come from the IDP.
-	groups := id.Groups
+	groups := id.ActiveGroups()
 	for _, idpGroup := range requestor.CallerIdentityProviderGroups() {
 		lxdGroups, err := e.identityCache.GetIdentityProviderGroupMapping(idpGroup)
 		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
@@ -409,7 +409,7 @@ func (e *embeddedOpenFGA) getPermissionChecker(ctx context.Context, entitlement
 	}
 
 	// Combine the users LXD groups with any mappings that have come from the IDP.
-	groups := id.Groups
+	groups := id.ActiveGroups()
 	for _, idpGroup := range requestor.CallerIdentityProviderGroups() {
 		lxdGroups, err := e.identityCache.GetIdentityProviderGroupMapping(idpGroup)
 		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
@@ -28,9 +28,12 @@ func init() {
 
 type tls struct {
 	commonAuthorizer
+
+	sharedProjects SharedProjectsFunc
 }
 
 func (t *tls) load(ctx context.Context, identityCache *identity.Cache, opts Opts) error {
+	t.sharedProjects = opts.sharedProjects
 	return nil
 }
 
@@ -85,11 +88,21 @@ func (t *tls) CheckPermission(ctx context.Context, entityURL *api.URL, entitleme
 	}
 
 	// Check project level permissions against the certificates project list.
-	if !slices.Contains(id.Projects, projectName) {
-		return api.StatusErrorf(http.StatusForbidden, "User does not have permission for project %q", projectName)
+	if slices.Contains(id.Projects, projectName) {
+		return nil
 	}
 
-	return nil
+	// Entities shared with one of the projects of the certificate can be viewed.
+	sharedWith, err := t.getSharedProjects(ctx, entitlement, entityType)
+	if err != nil {
+		return err
+	}
+
+	if t.isSharedWith(sharedWith, entityURL, id) {
+		return nil
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "User does not have permission for project %q", projectName)
 }
 
 // CheckPermissionWithoutEffectiveProject calls CheckPermission. This is because the TLS auth driver does not need to consider
@@ -140,6 +153,14 @@ func (t *tls) GetPermissionChecker(ctx context.Context, entitlement auth.Entitle
 		return nil, fmt.Errorf("Failed to check project specificity of entity type %q: %w", entityType, err)
 	}
 
+	var sharedWith map[string][]string
+	if projectSpecific {
+		sharedWith, err = t.getSharedProjects(ctx, entitlement, entityType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Filter objects by project.
 	return func(entityURL *api.URL) bool {
 		eType, project, _, pathArguments, err := entity.ParseURL(entityURL.URL)
@@ -159,8 +180,9 @@ func (t *tls) GetPermissionChecker(ctx context.Context, entitlement auth.Entitle
 			return t.allowProjectUnspecificEntityType(entitlement, entityType, id, project, pathArguments)
 		}
 
-		// Otherwise, check if the project is in the list of allowed projects for the entity.
-		return slices.Contains(id.Projects, project)
+		// Otherwise, check if the project is in the list of allowed projects for the entity, or if the entity is
+		// shared with one of them.
+		return slices.Contains(id.Projects, project) || t.isSharedWith(sharedWith, entityURL, id)
 	}, nil
 }
 
@@ -170,6 +192,32 @@ func (t *tls) GetPermissionCheckerWithoutEffectiveProject(ctx context.Context, e
 	return t.GetPermissionChecker(ctx, entitlement, entityType)
 }
 
+// getSharedProjects returns a map of entity URL to the projects that the entity is shared with, if the given
+// entitlement can be granted through a share. Shares only grant the can_view entitlement.
+func (t *tls) getSharedProjects(ctx context.Context, entitlement auth.Entitlement, entityType entity.Type) (map[string][]string, error) {
+	if t.sharedProjects == nil || entitlement != auth.EntitlementCanView || !slices.Contains([]entity.Type{entity.TypeImage, entity.TypeNetwork, entity.TypeStorageVolume}, entityType) {
+		return nil, nil
+	}
+
+	sharedWith, err := t.sharedProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get shared entities: %w", err)
+	}
+
+	return sharedWith, nil
+}
+
+// isSharedWith returns true if the entity is shared with any of the projects of the identity.
+func (t *tls) isSharedWith(sharedWith map[string][]string, entityURL *api.URL, id *identity.CacheEntry) bool {
+	for _, projectName := range sharedWith[entityURL.String()] {
+		if slices.Contains(id.Projects, projectName) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t *tls) allowProjectUnspecificEntityType(entitlement auth.Entitlement, entityType entity.Type, id *identity.CacheEntry, projectName string, pathArguments []string) bool {
 	switch entityType {
 	case entity.TypeServer:
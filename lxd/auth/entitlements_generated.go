@@ -31,7 +31,7 @@ const (
 	// EntitlementCanViewPermissions is the "can_view_permissions" entitlement. It applies to the following entities: entity.TypeServer.
 	EntitlementCanViewPermissions Entitlement = "can_view_permissions"
 
-	// EntitlementCanCreateIdentities is the "can_create_identities" entitlement. It applies to the following entities: entity.TypeServer.
+	// EntitlementCanCreateIdentities is the "can_create_identities" entitlement. It applies to the following entities: entity.TypeProject, entity.TypeServer.
 	EntitlementCanCreateIdentities Entitlement = "can_create_identities"
 
 	// EntitlementCanViewIdentities is the "can_view_identities" entitlement. It applies to the following entities: entity.TypeServer.
@@ -399,6 +399,8 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanEdit,
 		// Grants permission to delete the project.
 		EntitlementCanDelete,
+		// Grants permission to create identities confined to the project, with a subset of the entitlements of the caller.
+		EntitlementCanCreateIdentities,
 		// Grants permission to create, view, edit, and delete all images belonging to the project.
 		EntitlementImageManager,
 		// Grants permission to create images.
@@ -125,6 +125,10 @@ func (o *Verifier) authenticateAccessToken(ctx context.Context, accessToken stri
 
 	id, err := o.identityCache.GetByOIDCSubject(claims.Subject)
 	if err == nil {
+		if id.SessionRevoked(claims.GetIssuedAt()) {
+			return nil, AuthError{Err: errors.New("Provided OIDC token was revoked")}
+		}
+
 		return &AuthenticationResult{
 			IdentityType:           api.IdentityTypeOIDCClient,
 			Email:                  id.Identifier,
@@ -163,6 +167,11 @@ func (o *Verifier) authenticateIDToken(w http.ResponseWriter, r *http.Request) (
 		// Try to verify the ID token.
 		claims, err = rp.VerifyIDToken[*oidc.IDTokenClaims](r.Context(), idToken, o.relyingParty.IDTokenVerifier())
 		if err == nil {
+			err = o.checkSessionRevoked(w, r, claims.Subject)
+			if err != nil {
+				return nil, err
+			}
+
 			if startNewSession {
 				err = o.startSession(r.Context(), w, idToken, refreshToken)
 				if err != nil {
@@ -196,6 +205,12 @@ func (o *Verifier) authenticateIDToken(w http.ResponseWriter, r *http.Request) (
 		return nil, AuthError{Err: fmt.Errorf("Failed to verify refreshed ID token: %w", err)}
 	}
 
+	// Don't extend a revoked session.
+	err = o.checkSessionRevoked(w, r, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
 	err = o.startSession(r.Context(), w, idToken, tokens.RefreshToken)
 	if err != nil {
 		return nil, AuthError{Err: fmt.Errorf("Failed to create new session with refreshed token: %w", err)}
@@ -204,6 +219,34 @@ func (o *Verifier) authenticateIDToken(w http.ResponseWriter, r *http.Request) (
 	return o.getResultFromClaims(claims, claims.Claims)
 }
 
+// checkSessionRevoked returns an error if the identity with the given subject revoked its sessions after the session of
+// the request was started. The session cookies are then deleted to force the user to log in again.
+func (o *Verifier) checkSessionRevoked(w http.ResponseWriter, r *http.Request, subject string) error {
+	id, err := o.identityCache.GetByOIDCSubject(subject)
+	if err != nil {
+		// Identities are only added to the cache on their first login, so there is nothing to revoke yet.
+		return nil
+	}
+
+	sessionIDCookie, err := r.Cookie(cookieNameSessionID)
+	if err != nil {
+		return AuthError{Err: fmt.Errorf("Failed to get session ID cookie from request: %w", err)}
+	}
+
+	sessionID, err := uuid.Parse(sessionIDCookie.Value)
+	if err != nil {
+		return AuthError{Err: fmt.Errorf("Invalid session ID cookie: %w", err)}
+	}
+
+	sessionStartedAtSeconds, sessionStartedAtNanoseconds := sessionID.Time().UnixTime()
+	if id.SessionRevoked(time.Unix(sessionStartedAtSeconds, sessionStartedAtNanoseconds)) {
+		_ = o.setCookies(w, nil, uuid.UUID{}, "", "", true)
+		return AuthError{Err: errors.New("Session was revoked")}
+	}
+
+	return nil
+}
+
 // getResultFromClaims gets an AuthenticationResult from the given rp.SubjectGetter and claim map.
 // It returns an error if any required values are not present or are invalid.
 func (o *Verifier) getResultFromClaims(sg rp.SubjectGetter, claims map[string]any) (*AuthenticationResult, error) {
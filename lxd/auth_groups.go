@@ -21,9 +21,11 @@ import (
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
 )
 
 var authGroupsCmd = APIEndpoint{
@@ -190,6 +192,7 @@ func getAuthGroups(d *Daemon, r *http.Request) response.Response {
 	var authGroupPermissions []dbCluster.Permission
 	groupsIdentities := make(map[int][]dbCluster.Identity)
 	groupsIdentityProviderGroups := make(map[int][]dbCluster.IdentityProviderGroup)
+	groupsRoleGrants := make(map[int][]api.RoleGrant)
 	entityURLs := make(map[entity.Type]map[int]*api.URL)
 	err = d.db.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		allGroups, err := dbCluster.GetAuthGroups(ctx, tx.Tx())
@@ -231,6 +234,18 @@ func getAuthGroups(d *Daemon, r *http.Request) response.Response {
 			if err != nil {
 				return err
 			}
+
+			groupsRoles, err := dbCluster.GetAllAuthGroupRoles(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			for groupID, groupRoles := range groupsRoles {
+				groupsRoleGrants[groupID], err = dbCluster.AuthGroupRolesToAPI(ctx, tx.Tx(), groupRoles)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -257,11 +272,7 @@ func getAuthGroups(d *Daemon, r *http.Request) response.Response {
 			if ok {
 				apiPermissions = make([]api.Permission, 0, len(permissions))
 				for _, permission := range permissions {
-					apiPermissions = append(apiPermissions, api.Permission{
-						EntityType:      string(permission.EntityType),
-						EntityReference: entityURLs[entity.Type(permission.EntityType)][permission.EntityID].String(),
-						Entitlement:     string(permission.Entitlement),
-					})
+					apiPermissions = append(apiPermissions, permission.ToAPI(entityURLs[entity.Type(permission.EntityType)][permission.EntityID]))
 				}
 			}
 
@@ -286,6 +297,7 @@ func getAuthGroups(d *Daemon, r *http.Request) response.Response {
 				Permissions:            apiPermissions,
 				Identities:             apiIdentities,
 				IdentityProviderGroups: idpGroups,
+				Roles:                  groupsRoleGrants[group.ID],
 			}
 
 			apiGroups = append(apiGroups, group)
@@ -354,6 +366,11 @@ func createAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = validateRoleGrants(group.Roles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
@@ -376,6 +393,11 @@ func createAuthGroup(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = upsertRoleGrants(ctx, tx.Tx(), int(groupID), group.Roles)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -518,6 +540,11 @@ func updateAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = validateRoleGrants(groupPut.Roles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
@@ -561,6 +588,11 @@ func updateAuthGroup(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		err = upsertRoleGrants(ctx, tx.Tx(), group.ID, groupPut.Roles)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -617,6 +649,11 @@ func patchAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = validateRoleGrants(groupPut.Roles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
@@ -669,6 +706,18 @@ func patchAuthGroup(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		roleGrants := apiGroup.Roles
+		for _, roleGrant := range groupPut.Roles {
+			if !slices.Contains(roleGrants, roleGrant) {
+				roleGrants = append(roleGrants, roleGrant)
+			}
+		}
+
+		err = upsertRoleGrants(ctx, tx.Tx(), group.ID, roleGrants)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -827,7 +876,8 @@ func deleteAuthGroup(d *Daemon, r *http.Request) response.Response {
 }
 
 // validatePermissions checks that a) the entity type exists, b) the entitlement exists, c) then entity type matches the
-// entity reference (URL), and d) that the entitlement is valid for the entity type.
+// entity reference (URL), d) that the entitlement is valid for the entity type, and e) that the expiry date, if any,
+// is in the future.
 func validatePermissions(permissions []api.Permission) error {
 	for _, permission := range permissions {
 		entityType := entity.Type(permission.EntityType)
@@ -854,6 +904,10 @@ func validatePermissions(permissions []api.Permission) error {
 		if err != nil {
 			return api.StatusErrorf(http.StatusBadRequest, "Failed to validate group permission with entity reference %q and entitlement %q: %w", permission.EntityReference, permission.Entitlement, err)
 		}
+
+		if permission.ExpiresAt != nil && !permission.ExpiresAt.After(time.Now()) {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to validate group permission with entity reference %q and entitlement %q: Expiry date must be in the future", permission.EntityReference, permission.Entitlement)
+		}
 	}
 
 	return nil
@@ -889,11 +943,17 @@ func upsertPermissions(ctx context.Context, tx *sql.Tx, groupID int, permissions
 			return api.StatusErrorf(http.StatusBadRequest, "Missing entity ID for permission with URL %q", permission.EntityReference)
 		}
 
-		authGroupPermissions = append(authGroupPermissions, dbCluster.Permission{
+		authGroupPermission := dbCluster.Permission{
 			Entitlement: entitlement,
 			EntityType:  entityType,
 			EntityID:    entityRef.EntityID,
-		})
+		}
+
+		if permission.ExpiresAt != nil {
+			authGroupPermission.ExpiryDate = sql.NullTime{Time: *permission.ExpiresAt, Valid: true}
+		}
+
+		authGroupPermissions = append(authGroupPermissions, authGroupPermission)
 	}
 
 	err = dbCluster.SetAuthGroupPermissions(ctx, tx, groupID, authGroupPermissions)
@@ -903,3 +963,61 @@ func upsertPermissions(ctx context.Context, tx *sql.Tx, groupID int, permissions
 
 	return nil
 }
+
+// pruneExpiredAuthGrantsTask removes the group memberships and permissions whose expiry date has passed. They're
+// already ignored by the authorizer once expired, this only cleans them up.
+func pruneExpiredAuthGrantsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Warn("Failed getting cluster leader for pruning expired auth grants", logger.Ctx{"err": err})
+			return
+		}
+
+		if !leaderInfo.Leader {
+			return
+		}
+
+		var memberships int64
+		var permissions int64
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			memberships, permissions, err = dbCluster.DeleteExpiredAuthGroupGrants(ctx, tx.Tx())
+			return err
+		})
+		if err != nil {
+			logger.Warn("Failed pruning expired auth grants", logger.Ctx{"err": err})
+			return
+		}
+
+		if memberships == 0 && permissions == 0 {
+			return
+		}
+
+		logger.Info("Pruned expired auth grants", logger.Ctx{"memberships": memberships, "permissions": permissions})
+
+		if memberships == 0 {
+			return
+		}
+
+		// Refresh the identity caches so that they no longer list the expired memberships.
+		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+		if err != nil {
+			logger.Warn("Failed creating notifier for identity cache refresh", logger.Ctx{"err": err})
+			return
+		}
+
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			_, _, err := client.RawQuery(http.MethodPost, "/internal/identity-cache-refresh", nil, "")
+			return err
+		})
+		if err != nil {
+			logger.Warn("Failed notifying identity cache refresh", logger.Ctx{"err": err})
+		}
+
+		s.UpdateIdentityCache()
+	}
+
+	return f, task.Every(time.Hour)
+}
@@ -0,0 +1,773 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var authRolesCmd = APIEndpoint{
+	Name:        "auth_roles",
+	Path:        "auth/roles",
+	MetricsType: entity.TypeIdentity,
+	Get: APIEndpointAction{
+		Handler:       getAuthRoles,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewGroups),
+	},
+	Post: APIEndpointAction{
+		Handler:       createAuthRole,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanCreateGroups),
+	},
+}
+
+var authRoleCmd = APIEndpoint{
+	Name:        "auth_role",
+	Path:        "auth/roles/{roleName}",
+	MetricsType: entity.TypeIdentity,
+	Get: APIEndpointAction{
+		Handler:       getAuthRole,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewGroups),
+	},
+	Put: APIEndpointAction{
+		Handler:       updateAuthRole,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEditGroups),
+	},
+	Post: APIEndpointAction{
+		Handler:       renameAuthRole,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEditGroups),
+	},
+	Delete: APIEndpointAction{
+		Handler:       deleteAuthRole,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanDeleteGroups),
+	},
+	Patch: APIEndpointAction{
+		Handler:       patchAuthRole,
+		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEditGroups),
+	},
+}
+
+// authRoleURL returns the URL of the role with the given name.
+func authRoleURL(roleName string) *api.URL {
+	return api.NewURL().Path(version.APIVersion, "auth", "roles", roleName)
+}
+
+func validateRoleName(name string) error {
+	if name == "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Role name cannot be empty")
+	}
+
+	if strings.Contains(name, "/") {
+		return api.StatusErrorf(http.StatusBadRequest, "Role name cannot contain a forward slash")
+	}
+
+	if strings.Contains(name, ":") {
+		return api.StatusErrorf(http.StatusBadRequest, "Role name cannot contain a colon")
+	}
+
+	return nil
+}
+
+// validateRoleEntitlements checks that the entity type of each entitlement exists and that the entitlement is valid
+// for the entity type.
+func validateRoleEntitlements(entitlements []api.AuthRoleEntitlement) error {
+	for _, e := range entitlements {
+		entityType := entity.Type(e.EntityType)
+		err := entityType.Validate()
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to validate entity type for role entitlement %q: %w", e.Entitlement, err)
+		}
+
+		err = auth.ValidateEntitlement(entityType, auth.Entitlement(e.Entitlement))
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to validate role entitlement %q on entity type %q: %w", e.Entitlement, e.EntityType, err)
+		}
+	}
+
+	return nil
+}
+
+// upsertRoleEntitlements sets the given entitlements against the role with the given ID.
+func upsertRoleEntitlements(ctx context.Context, tx *sql.Tx, roleID int, entitlements []api.AuthRoleEntitlement) error {
+	roleEntitlements := make([]dbCluster.AuthRoleEntitlement, 0, len(entitlements))
+	for _, e := range entitlements {
+		roleEntitlement := dbCluster.AuthRoleEntitlement{
+			EntityType:  dbCluster.EntityType(e.EntityType),
+			Entitlement: auth.Entitlement(e.Entitlement),
+		}
+
+		if !slices.Contains(roleEntitlements, roleEntitlement) {
+			roleEntitlements = append(roleEntitlements, roleEntitlement)
+		}
+	}
+
+	err := dbCluster.SetAuthRoleEntitlements(ctx, tx, roleID, roleEntitlements)
+	if err != nil {
+		return fmt.Errorf("Failed to set role entitlements: %w", err)
+	}
+
+	return nil
+}
+
+// validateRoleGrantedEntityTypes checks that the given entitlements of a role still include entitlements on each of
+// the entity types that the role is granted on, so that updating the role doesn't leave grants without effect.
+func validateRoleGrantedEntityTypes(roleName string, entitlements []api.AuthRoleEntitlement, grantedEntityTypes []dbCluster.EntityType) error {
+	for _, entityType := range grantedEntityTypes {
+		hasEntitlements := slices.ContainsFunc(entitlements, func(e api.AuthRoleEntitlement) bool {
+			return dbCluster.EntityType(e.EntityType) == entityType
+		})
+
+		if !hasEntitlements {
+			return api.StatusErrorf(http.StatusBadRequest, "Authorization role %q is granted on entities of type %q and must keep entitlements on them", roleName, entityType)
+		}
+	}
+
+	return nil
+}
+
+// validateRoleGrants checks that a) the role name is set, b) the entity type exists, and c) the entity type matches
+// the entity reference (URL).
+func validateRoleGrants(roleGrants []api.RoleGrant) error {
+	for _, roleGrant := range roleGrants {
+		if roleGrant.Role == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Role name cannot be empty for role grant with entity reference %q", roleGrant.EntityReference)
+		}
+
+		entityType := entity.Type(roleGrant.EntityType)
+		err := entityType.Validate()
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to validate entity type for role %q with entity reference %q: %w", roleGrant.Role, roleGrant.EntityReference, err)
+		}
+
+		u, err := url.Parse(roleGrant.EntityReference)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to parse role %q with entity reference %q: %w", roleGrant.Role, roleGrant.EntityReference, err)
+		}
+
+		referenceEntityType, _, _, _, err := entity.ParseURL(*u)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to parse role %q with entity reference %q: %w", roleGrant.Role, roleGrant.EntityReference, err)
+		}
+
+		if entityType != referenceEntityType {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to parse role %q with entity reference %q: Entity type does not correspond to entity reference", roleGrant.Role, roleGrant.EntityReference)
+		}
+	}
+
+	return nil
+}
+
+// upsertRoleGrants converts the given slice of api.RoleGrant into a slice of cluster.AuthGroupRole by resolving the
+// role names and the URLs of each grant to IDs. Then sets those roles against the group with the given ID. The roles
+// must give entitlements on the entity type they are granted on.
+func upsertRoleGrants(ctx context.Context, tx *sql.Tx, groupID int, roleGrants []api.RoleGrant) error {
+	entityReferences := make(map[*api.URL]*dbCluster.EntityRef, len(roleGrants))
+	roleGrantToURL := make(map[api.RoleGrant]*api.URL, len(roleGrants))
+	for _, roleGrant := range roleGrants {
+		u, err := url.Parse(roleGrant.EntityReference)
+		if err != nil {
+			return fmt.Errorf("Failed to parse role entity reference: %w", err)
+		}
+
+		apiURL := &api.URL{URL: *u}
+		entityReferences[apiURL] = &dbCluster.EntityRef{}
+		roleGrantToURL[roleGrant] = apiURL
+	}
+
+	err := dbCluster.PopulateEntityReferencesFromURLs(ctx, tx, entityReferences)
+	if err != nil {
+		return err
+	}
+
+	roleEntitlements, err := dbCluster.GetAllAuthRoleEntitlements(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	roleIDs := make(map[string]int)
+	groupRoles := make([]dbCluster.AuthGroupRole, 0, len(roleGrants))
+	for roleGrant, apiURL := range roleGrantToURL {
+		roleID, ok := roleIDs[roleGrant.Role]
+		if !ok {
+			role, err := dbCluster.GetAuthRole(ctx, tx, roleGrant.Role)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return api.StatusErrorf(http.StatusBadRequest, "Authorization role %q not found", roleGrant.Role)
+				}
+
+				return err
+			}
+
+			roleID = role.ID
+			roleIDs[roleGrant.Role] = roleID
+		}
+
+		entityType := dbCluster.EntityType(roleGrant.EntityType)
+		hasEntitlements := slices.ContainsFunc(roleEntitlements[roleID], func(e dbCluster.AuthRoleEntitlement) bool {
+			return e.EntityType == entityType
+		})
+
+		if !hasEntitlements {
+			return api.StatusErrorf(http.StatusBadRequest, "Authorization role %q has no entitlements on entities of type %q", roleGrant.Role, roleGrant.EntityType)
+		}
+
+		entityRef, ok := entityReferences[apiURL]
+		if !ok {
+			return api.StatusErrorf(http.StatusBadRequest, "Missing entity ID for role %q with URL %q", roleGrant.Role, roleGrant.EntityReference)
+		}
+
+		groupRoles = append(groupRoles, dbCluster.AuthGroupRole{
+			RoleID:     roleID,
+			EntityType: entityType,
+			EntityID:   entityRef.EntityID,
+		})
+	}
+
+	err = dbCluster.SetAuthGroupRoles(ctx, tx, groupID, groupRoles)
+	if err != nil {
+		return fmt.Errorf("Failed to set group roles: %w", err)
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/auth/roles auth_roles auth_roles_get
+//
+//	Get the roles
+//
+//	Returns a list of authorization roles (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/roles/foo",
+//	              "/1.0/auth/roles/bar"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/roles?recursion=1 auth_roles auth_roles_get_recursion1
+//
+//	Get the roles
+//
+//	Returns a list of authorization roles.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of auth roles
+//	          items:
+//	            $ref: "#/definitions/AuthRole"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func getAuthRoles(d *Daemon, r *http.Request) response.Response {
+	recursion := util.IsRecursionRequest(r)
+	s := d.State()
+
+	var apiRoles []api.AuthRole
+	var roleURLs []string
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		roles, err := dbCluster.GetAuthRoles(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if !recursion {
+			roleURLs = make([]string, 0, len(roles))
+			for _, role := range roles {
+				roleURLs = append(roleURLs, authRoleURL(role.Name).String())
+			}
+
+			return nil
+		}
+
+		apiRoles = make([]api.AuthRole, 0, len(roles))
+		for _, role := range roles {
+			apiRole, err := role.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			apiRoles = append(apiRoles, *apiRole)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, apiRoles)
+	}
+
+	return response.SyncResponse(true, roleURLs)
+}
+
+// swagger:operation POST /1.0/auth/roles auth_roles auth_roles_post
+//
+//	Create a new authorization role
+//
+//	Creates a new authorization role.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Role request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthRolesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func createAuthRole(d *Daemon, r *http.Request) response.Response {
+	var role api.AuthRolesPost
+	err := json.NewDecoder(r.Body).Decode(&role)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid request body: %w", err))
+	}
+
+	err = validateRoleName(role.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = validateRoleEntitlements(role.Entitlements)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	s := d.State()
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		roleID, err := dbCluster.CreateAuthRole(ctx, tx.Tx(), dbCluster.AuthRole{
+			Name:        role.Name,
+			Description: role.Description,
+		})
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusConflict) {
+				return api.StatusErrorf(http.StatusConflict, "Authorization role %q already exists", role.Name)
+			}
+
+			return err
+		}
+
+		return upsertRoleEntitlements(ctx, tx.Tx(), int(roleID), role.Entitlements)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Send a lifecycle event for the role creation
+	lc := lifecycle.AuthRoleCreated.Event(role.Name, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle("", lc)
+
+	return response.SyncResponseLocation(true, nil, authRoleURL(role.Name).String())
+}
+
+// swagger:operation GET /1.0/auth/roles/{roleName} auth_roles auth_role_get
+//
+//	Get the authorization role
+//
+//	Gets a specific authorization role.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthRole"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func getAuthRole(d *Daemon, r *http.Request) response.Response {
+	roleName, err := url.PathUnescape(mux.Vars(r)["roleName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var apiRole *api.AuthRole
+	s := d.State()
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		role, err := dbCluster.GetAuthRole(ctx, tx.Tx(), roleName)
+		if err != nil {
+			return err
+		}
+
+		apiRole, err = role.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, *apiRole, *apiRole)
+}
+
+// swagger:operation PUT /1.0/auth/roles/{roleName} auth_roles auth_role_put
+//
+//	Update the authorization role
+//
+//	Replaces the editable fields of an authorization role. The role must keep entitlements on each type of entity
+//	that it is granted on.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Update request
+//	    schema:
+//	      $ref: "#/definitions/AuthRolePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func updateAuthRole(d *Daemon, r *http.Request) response.Response {
+	return modifyAuthRole(d, r, false)
+}
+
+// swagger:operation PATCH /1.0/auth/roles/{roleName} auth_roles auth_role_patch
+//
+//	Partially update the authorization role
+//
+//	Updates the editable fields of an authorization role. The given entitlements are added to those of the role.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Update request
+//	    schema:
+//	      $ref: "#/definitions/AuthRolePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func patchAuthRole(d *Daemon, r *http.Request) response.Response {
+	return modifyAuthRole(d, r, true)
+}
+
+// modifyAuthRole replaces the editable fields of the role, or merges them into the existing ones when patching.
+func modifyAuthRole(d *Daemon, r *http.Request, patch bool) response.Response {
+	roleName, err := url.PathUnescape(mux.Vars(r)["roleName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var rolePut api.AuthRolePut
+	err = json.NewDecoder(r.Body).Decode(&rolePut)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid request body: %w", err))
+	}
+
+	err = validateRoleEntitlements(rolePut.Entitlements)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	s := d.State()
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		role, err := dbCluster.GetAuthRole(ctx, tx.Tx(), roleName)
+		if err != nil {
+			return err
+		}
+
+		apiRole, err := role.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		err = util.EtagCheck(r, *apiRole)
+		if err != nil {
+			return err
+		}
+
+		if patch {
+			if rolePut.Description == "" {
+				rolePut.Description = apiRole.Description
+			}
+
+			rolePut.Entitlements = append(apiRole.Entitlements, rolePut.Entitlements...)
+		}
+
+		grantedEntityTypes, err := dbCluster.GetAuthRoleGrantedEntityTypes(ctx, tx.Tx(), role.ID)
+		if err != nil {
+			return err
+		}
+
+		err = validateRoleGrantedEntityTypes(roleName, rolePut.Entitlements, grantedEntityTypes)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateAuthRole(ctx, tx.Tx(), roleName, dbCluster.AuthRole{
+			Name:        roleName,
+			Description: rolePut.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		return upsertRoleEntitlements(ctx, tx.Tx(), role.ID, rolePut.Entitlements)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Send a lifecycle event for the role update
+	lc := lifecycle.AuthRoleUpdated.Event(roleName, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle("", lc)
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/auth/roles/{roleName} auth_roles auth_role_post
+//
+//	Rename the authorization role
+//
+//	Renames the authorization role
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Update request
+//	    schema:
+//	      $ref: "#/definitions/AuthRolePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func renameAuthRole(d *Daemon, r *http.Request) response.Response {
+	roleName, err := url.PathUnescape(mux.Vars(r)["roleName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var rolePost api.AuthRolePost
+	err = json.NewDecoder(r.Body).Decode(&rolePost)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid request body: %w", err))
+	}
+
+	err = validateRoleName(rolePost.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	s := d.State()
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.RenameAuthRole(ctx, tx.Tx(), roleName, rolePost.Name)
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return response.Conflict(fmt.Errorf("Authorization role %q already exists", rolePost.Name))
+		}
+
+		return response.SmartError(err)
+	}
+
+	// Send a lifecycle event for the role rename
+	lc := lifecycle.AuthRoleRenamed.Event(rolePost.Name, request.CreateRequestor(r.Context()), map[string]any{"old_name": roleName})
+	s.Events.SendLifecycle("", lc)
+
+	return response.SyncResponseLocation(true, nil, authRoleURL(rolePost.Name).String())
+}
+
+// swagger:operation DELETE /1.0/auth/roles/{roleName} auth_roles auth_role_delete
+//
+//	Delete the authorization role
+//
+//	Deletes the authorization role. The role must not be granted to any group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func deleteAuthRole(d *Daemon, r *http.Request) response.Response {
+	roleName, err := url.PathUnescape(mux.Vars(r)["roleName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	s := d.State()
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		role, err := dbCluster.GetAuthRole(ctx, tx.Tx(), roleName)
+		if err != nil {
+			return err
+		}
+
+		groupNames, err := dbCluster.GetAuthGroupNamesByAuthRoleID(ctx, tx.Tx(), role.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(groupNames) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Authorization role %q is still granted to groups %s", roleName, strings.Join(groupNames, ", "))
+		}
+
+		return dbCluster.DeleteAuthRole(ctx, tx.Tx(), roleName)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Send a lifecycle event for the role deletion
+	lc := lifecycle.AuthRoleDeleted.Event(roleName, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle("", lc)
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func Test_validateRoleGrantedEntityTypes(t *testing.T) {
+	entitlements := []api.AuthRoleEntitlement{
+		{EntityType: string(entity.TypeProject), Entitlement: "can_view"},
+		{EntityType: string(entity.TypeInstance), Entitlement: "can_exec"},
+	}
+
+	tests := []struct {
+		name               string
+		entitlements       []api.AuthRoleEntitlement
+		grantedEntityTypes []dbCluster.EntityType
+		wantErr            bool
+	}{
+		{
+			name:         "Role not granted",
+			entitlements: nil,
+		},
+		{
+			name:               "Entitlements on all granted entity types",
+			entitlements:       entitlements,
+			grantedEntityTypes: []dbCluster.EntityType{dbCluster.EntityType(entity.TypeProject), dbCluster.EntityType(entity.TypeInstance)},
+		},
+		{
+			name:               "No entitlements left on a granted entity type",
+			entitlements:       entitlements[:1],
+			grantedEntityTypes: []dbCluster.EntityType{dbCluster.EntityType(entity.TypeProject), dbCluster.EntityType(entity.TypeInstance)},
+			wantErr:            true,
+		},
+		{
+			name:               "No entitlements left",
+			entitlements:       nil,
+			grantedEntityTypes: []dbCluster.EntityType{dbCluster.EntityType(entity.TypeProject)},
+			wantErr:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRoleGrantedEntityTypes("operator", tt.entitlements, tt.grantedEntityTypes)
+			if tt.wantErr {
+				assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -145,10 +145,11 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	// Create the tarball.
 	tarPipeReader, tarPipeWriter := io.Pipe()
 	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
-	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, idmap)
+	// The tarball stops being written once the cancellation of the operation is requested.
+	tarWriter := instancewriter.NewInstanceTarWriter(op.CheckpointWriter(tarPipeWriter), idmap)
 
 	// Setup tar writer go routine, with optional compression.
-	tarWriterRes := make(chan error)
+	tarWriterRes := make(chan error, 1)
 	var compressErr error
 
 	backupProgressWriter := &ioprogress.ProgressWriter{
@@ -161,6 +162,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 				progressText := fmt.Sprintf("%s (%s/s)", units.GetByteSizeString(value, 2), units.GetByteSizeString(speed, 2))
 				meta["create_backup_progress"] = progressText
+				meta[api.OperationProgressMetadataKey] = op.RecordProgress("create_backup", ioprogress.ProgressData{TransferredBytes: value, Speed: speed})
 				_ = op.UpdateMetadata(meta)
 			},
 		},
@@ -226,6 +228,12 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Last chance to discard the backup if the operation was cancelled.
+	err = op.Checkpoint()
+	if err != nil {
+		return err
+	}
+
 	revert.Success()
 	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
 
@@ -394,7 +402,7 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 	return nil
 }
 
-func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, version uint32) error {
+func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, version uint32, op *operations.Operation) error {
 	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name})
 	l.Debug("Volume backup started")
 	defer l.Debug("Volume backup finished")
@@ -476,10 +484,11 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 	// Create the tarball.
 	tarPipeReader, tarPipeWriter := io.Pipe()
 	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
-	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, nil)
+	// The tarball stops being written once the cancellation of the operation is requested.
+	tarWriter := instancewriter.NewInstanceTarWriter(op.CheckpointWriter(tarPipeWriter), nil)
 
 	// Setup tar writer go routine, with optional compression.
-	tarWriterRes := make(chan error)
+	tarWriterRes := make(chan error, 1)
 	var compressErr error
 
 	go func(resCh chan<- error) {
@@ -540,6 +549,12 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Last chance to discard the backup if the operation was cancelled.
+	err = op.Checkpoint()
+	if err != nil {
+		return err
+	}
+
 	revert.Success()
 	return nil
 }
@@ -39,6 +39,7 @@ func ConfigToInstanceDBArgs(state *state.State, c *config.Config, projectName st
 		Description:  c.Instance.Description,
 		Devices:      deviceConfig.NewDevices(c.Instance.Devices),
 		Ephemeral:    c.Instance.Ephemeral,
+		Labels:       c.Instance.Labels,
 		LastUsedDate: c.Instance.LastUsedAt,
 		Name:         c.Instance.Name,
 		Stateful:     c.Instance.Stateful,
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
@@ -15,7 +16,11 @@ import (
 
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/scriptlet"
+	"github.com/canonical/lxd/lxd/sink"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -46,6 +51,11 @@ func (c *Config) ClusterUUID() string {
 	return c.m.GetString("volatile.uuid")
 }
 
+// AdmissionScriptlet returns the server-wide admission scriptlet.
+func (c *Config) AdmissionScriptlet() string {
+	return c.m.GetString("admission.scriptlet")
+}
+
 // BackupsCompressionAlgorithm returns the compression algorithm to use for backups.
 func (c *Config) BackupsCompressionAlgorithm() string {
 	return c.m.GetString("backups.compression_algorithm")
@@ -171,6 +181,12 @@ func (c *Config) ImagesCompressionAlgorithm() string {
 	return c.m.GetString("images.compression_algorithm")
 }
 
+// ImagesAllowedServers returns the image server URLs that images can be downloaded from. An empty list means that
+// any server is allowed.
+func (c *Config) ImagesAllowedServers() []string {
+	return shared.SplitNTrimSpace(c.m.GetString("images.allowed_servers"), ",", -1, true)
+}
+
 // ImagesAutoUpdateCached returns whether or not to auto update cached images.
 func (c *Config) ImagesAutoUpdateCached() bool {
 	return c.m.GetBool("images.auto_update_cached")
@@ -196,6 +212,15 @@ func (c *Config) InstancesMigrationStateful() bool {
 	return c.m.GetBool("instances.migration.stateful")
 }
 
+// InstancesStateHistory returns the interval between resource usage samples of the instances and how long to keep
+// them for. A zero interval means that the history is disabled.
+func (c *Config) InstancesStateHistory() (interval time.Duration, retention time.Duration) {
+	interval = time.Duration(c.m.GetInt64("instances.state.history.interval")) * time.Minute
+	retention = time.Duration(c.m.GetInt64("instances.state.history.retention")) * time.Hour
+
+	return interval, retention
+}
+
 // LokiServer returns all the Loki settings needed to connect to a server.
 func (c *Config) LokiServer() (apiURL string, authUsername string, authPassword string, apiCACert string, instance string, logLevel string, labels []string, types []string) {
 	if c.m.GetString("loki.types") != "" {
@@ -209,6 +234,128 @@ func (c *Config) LokiServer() (apiURL string, authUsername string, authPassword
 	return c.m.GetString("loki.api.url"), c.m.GetString("loki.auth.username"), c.m.GetString("loki.auth.password"), c.m.GetString("loki.api.ca_cert"), c.m.GetString("loki.instance"), c.m.GetString("loki.loglevel"), labels, types
 }
 
+// Webhook returns all the settings needed to send events to webhooks.
+func (c *Config) Webhook() (urls []string, secret string, types []string, projects []string) {
+	if c.m.GetString("webhook.url") != "" {
+		urls = strings.Split(c.m.GetString("webhook.url"), ",")
+	}
+
+	if c.m.GetString("webhook.types") != "" {
+		types = strings.Split(c.m.GetString("webhook.types"), ",")
+	}
+
+	if c.m.GetString("webhook.projects") != "" {
+		projects = strings.Split(c.m.GetString("webhook.projects"), ",")
+	}
+
+	return urls, c.m.GetString("webhook.secret"), types, projects
+}
+
+// FileSink returns the settings of the sink writing events to a local file.
+func (c *Config) FileSink() (path string, maxSize int64, maxFiles int64, filter sink.Filter) {
+	return c.m.GetString("sinks.file.path"), c.m.GetInt64("sinks.file.max_size"), c.m.GetInt64("sinks.file.max_files"), c.sinkFilter("file")
+}
+
+// KafkaSink returns the settings of the sink producing events to a Kafka topic.
+func (c *Config) KafkaSink() (proxyURL string, topic string, filter sink.Filter) {
+	return c.m.GetString("sinks.kafka.url"), c.m.GetString("sinks.kafka.topic"), c.sinkFilter("kafka")
+}
+
+// MQTTSink returns the settings of the sink publishing events to an MQTT broker.
+func (c *Config) MQTTSink() (brokerURL string, username string, password string, topic string, filter sink.Filter) {
+	return c.m.GetString("sinks.mqtt.url"), c.m.GetString("sinks.mqtt.username"), c.m.GetString("sinks.mqtt.password"), c.m.GetString("sinks.mqtt.topic"), c.sinkFilter("mqtt")
+}
+
+// SyslogSink returns the settings of the sink sending events to a remote syslog server.
+func (c *Config) SyslogSink() (protocol string, address string, filter sink.Filter) {
+	return c.m.GetString("sinks.syslog.protocol"), c.m.GetString("sinks.syslog.address"), c.sinkFilter("syslog")
+}
+
+// sinkFilter returns the filter of the events forwarded to the given sink.
+func (c *Config) sinkFilter(name string) sink.Filter {
+	filter := sink.Filter{}
+
+	types := c.m.GetString("sinks." + name + ".types")
+	if types != "" {
+		filter.Types = strings.Split(types, ",")
+	}
+
+	projects := c.m.GetString("sinks." + name + ".projects")
+	if projects != "" {
+		filter.Projects = strings.Split(projects, ",")
+	}
+
+	return filter
+}
+
+// Alerts returns the thresholds of the built-in alerting rules. A zero threshold means that the rule is disabled.
+func (c *Config) Alerts() (storagePoolUsage int64, clusterMemberOffline bool, instanceRestarts int64) {
+	return c.m.GetInt64("alerts.storage_pool_usage"), c.m.GetBool("alerts.cluster_member_offline"), c.m.GetInt64("alerts.instance_restarts")
+}
+
+// SlowThresholds returns the durations above which API requests and database transactions are logged as slow.
+// A zero threshold means that the logging is disabled.
+func (c *Config) SlowThresholds() (request time.Duration, transaction time.Duration) {
+	return time.Duration(c.m.GetInt64("core.slow_request_threshold")) * time.Millisecond, time.Duration(c.m.GetInt64("core.slow_transaction_threshold")) * time.Millisecond
+}
+
+// RequestSizeLimits returns the maximum sizes of the API request bodies and uploads, along with the overrides of
+// both keyed by API path relative to `/1.0/`. A zero size means that there is no limit.
+func (c *Config) RequestSizeLimits() (request int64, upload int64, overrides map[string]int64) {
+	request, _ = units.ParseByteSizeString(c.m.GetString("core.max_request_size"))
+	upload, _ = units.ParseByteSizeString(c.m.GetString("core.max_upload_size"))
+
+	overrides = map[string]int64{}
+	value := c.m.GetString("core.request_size_overrides")
+	if value != "" {
+		for _, override := range strings.Split(value, ",") {
+			path, size, _ := strings.Cut(override, "=")
+			overrides[strings.Trim(strings.TrimSpace(path), "/")], _ = units.ParseByteSizeString(strings.TrimSpace(size))
+		}
+	}
+
+	return request, upload, overrides
+}
+
+// RequireEtag returns whether API requests that update an entity must include an `If-Match` header.
+func (c *Config) RequireEtag() bool {
+	return c.m.GetBool("core.require_etag")
+}
+
+// OperationLimits returns the maximum number of running operations of the given category (`image_imports`,
+// `backups` or `migrations`) on each cluster member, and of operations of each project waiting for them.
+// A zero concurrent value means that there is no limit, and a negative queued value means that the queue isn't limited.
+func (c *Config) OperationLimits(category string) (concurrent int64, queued int64) {
+	queued = -1
+	value := c.m.GetString("core.max_queued_operations")
+	if value != "" {
+		queued, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	return c.m.GetInt64("core.max_concurrent_" + category), queued
+}
+
+// SMTP returns all the settings needed to send notification emails.
+func (c *Config) SMTP() (address string, username string, password string, sender string) {
+	return c.m.GetString("smtp.address"), c.m.GetString("smtp.username"), c.m.GetString("smtp.password"), c.m.GetString("smtp.sender")
+}
+
+// EventsHistoryRetention returns how long to keep the history of events for. A zero retention means that the
+// history is disabled.
+func (c *Config) EventsHistoryRetention() time.Duration {
+	return time.Duration(c.m.GetInt64("core.events_history_retention")) * time.Hour
+}
+
+// ProjectsUsageRetention returns how long to keep the usage accounting of the projects for.
+func (c *Config) ProjectsUsageRetention() time.Duration {
+	return time.Duration(c.m.GetInt64("core.projects_usage_retention")) * 24 * time.Hour
+}
+
+// Tracing returns the OpenTelemetry trace export settings.
+func (c *Config) Tracing() (endpoint string, insecure bool, sampling int64) {
+	return c.m.GetString("core.tracing.endpoint"), c.m.GetBool("core.tracing.insecure"), c.m.GetInt64("core.tracing.sampling")
+}
+
 // ACME returns all ACME settings needed for certificate renewal.
 func (c *Config) ACME() (domain string, email string, caURL string, agreeTOS bool) {
 	return c.m.GetString("acme.domain"), c.m.GetString("acme.email"), c.m.GetString("acme.ca_url"), c.m.GetBool("acme.agree_tos")
@@ -234,6 +381,28 @@ func (c *Config) OIDCServer() (issuer string, clientID string, clientSecret stri
 	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.client.secret"), strings.Fields(c.m.GetString("oidc.scopes")), c.m.GetString("oidc.audience"), c.m.GetString("oidc.groups.claim")
 }
 
+// ReplicationTarget returns the address and certificate of the standby cluster that instances are replicated to.
+func (c *Config) ReplicationTarget() (address string, certificate string) {
+	return c.m.GetString("replication.target.address"), c.m.GetString("replication.target.certificate")
+}
+
+// ClusterHealingFenceHook returns the name of the hook used to fence offline cluster members before healing them.
+func (c *Config) ClusterHealingFenceHook() string {
+	return c.m.GetString("cluster.healing_fence_hook")
+}
+
+// ClusterPartitionFenceHook returns the name of the hook used by a cluster member to fence itself when isolated from
+// the rest of the cluster.
+func (c *Config) ClusterPartitionFenceHook() string {
+	return c.m.GetString("cluster.partition_fence_hook")
+}
+
+// ClusterRebalance returns the interval of the workload rebalancer (0 if disabled), the load difference (in percent)
+// triggering it and the maximum number of instance moves per hour.
+func (c *Config) ClusterRebalance() (time.Duration, int64, int64) {
+	return time.Duration(c.m.GetInt64("cluster.rebalance.interval")) * time.Minute, c.m.GetInt64("cluster.rebalance.threshold"), c.m.GetInt64("cluster.rebalance.max_moves")
+}
+
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
 // number of seconds after which an offline node will be evacuated automatically. If the config key
 // is set but its value is lower than cluster.offline_threshold it returns
@@ -327,6 +496,46 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Agree to ACME terms of service
 	"acme.agree_tos": {Type: config.Bool, Default: "false"},
 
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=admission.scriptlet)
+	// The Starlark scriptlet must define an `admit(request)` function. It is evaluated on every request creating or
+	// updating an instance, storage volume or network, and can reject the request or change its specification.
+	// See {ref}`authorization-admission` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Admission scriptlet for create and update requests
+	"admission.scriptlet": {Validator: validate.Optional(scriptlet.AdmissionValidate)},
+
+	// lxdmeta:generate(entities=server; group=alerts; key=alerts.cluster_member_offline)
+	// The alert fires when a cluster member doesn't respond to heartbeats for longer than {config:option}`server-cluster:cluster.offline_threshold`.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `true`
+	//  shortdesc: Whether to alert when a cluster member is offline
+	"alerts.cluster_member_offline": {Type: config.Bool, Default: "true"},
+
+	// lxdmeta:generate(entities=server; group=alerts; key=alerts.instance_restarts)
+	// The alert fires when an instance restarts (either on request or automatically) at least this number of times within 10 minutes.
+	// Set to `0` to disable the alert.
+	// This can be overridden per project with {config:option}`project-specific:alerts.instance_restarts`.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `5`
+	//  shortdesc: Number of restarts after which to alert on an instance restart loop
+	"alerts.instance_restarts": {Type: config.Int64, Default: "5", Validator: validate.Optional(validate.IsUint32)},
+
+	// lxdmeta:generate(entities=server; group=alerts; key=alerts.storage_pool_usage)
+	// The alert fires when the used space of a storage pool reaches this percentage of its size.
+	// Set to `0` to disable the alert.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `90`
+	//  shortdesc: Storage pool usage percentage above which to alert
+	"alerts.storage_pool_usage": {Type: config.Int64, Default: "90", Validator: validate.IsInRange(0, 100)},
+
 	// lxdmeta:generate(entities=server; group=miscellaneous; key=backups.compression_algorithm)
 	// Possible values are `bzip2`, `gzip`, `lzma`, `xz`, or `none`.
 	// ---
@@ -364,6 +573,62 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Threshold when to evacuate an offline cluster member
 	"cluster.healing_threshold": {Type: config.Int64, Default: "0"},
 
+	// lxdmeta:generate(entities=server; group=cluster; key=cluster.healing_fence_hook)
+	// Specify the name of an executable in the `hooks` directory of LXD (for example, `/var/snap/lxd/common/lxd/hooks/`).
+	// Before healing an offline cluster member, the cluster leader runs this hook to fence the member, for example through its power management controller.
+	// The hook receives the name and address of the member in the `LXD_MEMBER_NAME` and `LXD_MEMBER_ADDRESS` environment variables.
+	// If the hook fails, the member isn't healed.
+	//
+	// The hook must be installed on all cluster members, owned by `root` and not writable by other users.
+	// See {ref}`cluster-automatic-evacuation` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Hook to fence offline cluster members before healing them
+	"cluster.healing_fence_hook": {Validator: validate.Optional(instancetype.ValidHookName)},
+
+	// lxdmeta:generate(entities=server; group=cluster; key=cluster.partition_fence_hook)
+	// Specify the name of an executable in the `hooks` directory of LXD (for example, `/var/snap/lxd/common/lxd/hooks/`).
+	// When a cluster member hasn't been in contact with the rest of the cluster for longer than {config:option}`server-cluster:cluster.offline_threshold`, it runs this hook to fence itself, for example by stopping its instances on shared storage.
+	// The hook receives the name and address of the member in the `LXD_MEMBER_NAME` and `LXD_MEMBER_ADDRESS` environment variables, and the time of the last heartbeat in the `LXD_LAST_HEARTBEAT` environment variable.
+	//
+	// The hook must be installed on all cluster members, owned by `root` and not writable by other users.
+	// See {ref}`cluster-partition` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Hook run by a cluster member to fence itself when isolated
+	"cluster.partition_fence_hook": {Validator: validate.Optional(instancetype.ValidHookName)},
+
+	// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.interval)
+	// Specify the number of minutes between two evaluations of the load of the cluster members by the workload rebalancer.
+	// To disable the workload rebalancer, set this option to `0`.
+	// See {ref}`cluster-rebalance` for more information.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Interval of the workload rebalancer in minutes
+	"cluster.rebalance.interval": {Type: config.Int64, Default: "0", Validator: validate.IsInRange(0, 1440)},
+
+	// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.threshold)
+	// Specify the difference of load (in percent of the logical CPUs) between the most and the least loaded cluster members above which the workload rebalancer moves instances.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `20`
+	//  shortdesc: Load difference triggering the workload rebalancer
+	"cluster.rebalance.threshold": {Type: config.Int64, Default: "20", Validator: validate.IsInRange(1, 100)},
+
+	// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.max_moves)
+	// Specify the maximum number of instances that the workload rebalancer moves per hour.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `2`
+	//  shortdesc: Maximum number of instance moves per hour
+	"cluster.rebalance.max_moves": {Type: config.Int64, Default: "2", Validator: validate.IsInRange(1, 100)},
+
 	// lxdmeta:generate(entities=server; group=cluster; key=cluster.join_token_expiry)
 	//
 	// ---
@@ -496,6 +761,28 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How long to wait before shutdown
 	"core.shutdown_timeout": {Type: config.Int64, Default: "5"},
 
+	// lxdmeta:generate(entities=server; group=core; key=core.slow_request_threshold)
+	// When set, each cluster member logs a warning for the API requests that take longer than this number of milliseconds, with the requested URL, project and caller.
+	// Such requests are also counted in the `lxd_api_slow_requests_total` metric.
+	// Set it to `0` to disable the logging of slow requests.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Duration (in milliseconds) above which API requests are logged as slow
+	"core.slow_request_threshold": {Type: config.Int64, Default: "0", Validator: validate.IsInRange(0, 3600000)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.slow_transaction_threshold)
+	// When set, each cluster member logs a warning for the database transactions that take longer than this number of milliseconds, with the function that started the transaction and the caller of the API request, if any.
+	// Such transactions are also counted in the `lxd_db_slow_transactions_total` metric.
+	// Set it to `0` to disable the logging of slow transactions.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Duration (in milliseconds) above which database transactions are logged as slow
+	"core.slow_transaction_threshold": {Type: config.Int64, Default: "0", Validator: validate.IsInRange(0, 3600000)},
+
 	// lxdmeta:generate(entities=server; group=core; key=core.trust_ca_certificates)
 	//
 	// ---
@@ -505,6 +792,154 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to automatically trust clients signed by the CA
 	"core.trust_ca_certificates": {Type: config.Bool, Default: "false"},
 
+	// lxdmeta:generate(entities=server; group=core; key=core.events_history_retention)
+	// When set, each cluster member persists its lifecycle and operation events to the database.
+	// The history is available through the `/1.0/events/history` endpoint.
+	// Set it to `0` to disable the history.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: How long (in hours) to keep the history of events for
+	"core.events_history_retention": {Type: config.Int64, Default: "0", Validator: validate.IsInRange(0, 8760)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.projects_usage_retention)
+	// When set, each cluster member records the API calls, instance hours, storage and network usage of the
+	// projects every five minutes, and aggregates them per hour in the database.
+	// The usage is available through the `/1.0/projects/<name>/usage` endpoint.
+	// Set it to `0` to disable the usage accounting.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: How long (in days) to keep the usage accounting of projects for
+	"core.projects_usage_retention": {Type: config.Int64, Default: "0", Validator: validate.IsInRange(0, 3650)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.tracing.endpoint)
+	// Specify the name or IP and port of an OpenTelemetry collector accepting OTLP over gRPC,
+	// for example `tempo.example.com:4317`.
+	// Spans are exported for API requests, database transactions, storage operations and migrations.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address of the OTLP trace collector
+	"core.tracing.endpoint": {Validator: validate.Optional(validate.IsListenAddress(true, false, true))},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.tracing.insecure)
+	// Set this option to `true` to connect to the trace collector without TLS.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to export traces without TLS
+	"core.tracing.insecure": {Type: config.Bool, Default: "false"},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.tracing.sampling)
+	// Specify the percentage of traces started by LXD that are exported.
+	// Requests that are part of a trace sampled by the caller are always exported.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `100`
+	//  shortdesc: Percentage of traces to export
+	"core.tracing.sampling": {Type: config.Int64, Default: "100", Validator: validate.IsInRange(0, 100)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.max_request_size)
+	// Specify the maximum size of the JSON body of an API request, for example `10MiB`.
+	// Larger requests are rejected with a `413 Request Entity Too Large` error and counted in the `lxd_api_requests_too_large_total` metric.
+	// Set it to `0` to disable the limit.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `10MiB`
+	//  shortdesc: Maximum size of API request bodies
+	"core.max_request_size": {Default: "10MiB", Validator: validate.IsSize},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.max_upload_size)
+	// Specify the maximum size of the binary uploads streamed to the API, such as image, backup and file uploads.
+	// Any request body whose content type isn't `application/json` is considered an upload. Unlike JSON bodies, uploads aren't buffered in memory.
+	// Set it to `0` or leave it empty to disable the limit.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Maximum size of API uploads
+	"core.max_upload_size": {Validator: validate.Optional(validate.IsSize)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.request_size_overrides)
+	// Specify a comma-separated list of `<path>=<size>` pairs to override {config:option}`server-core:core.max_request_size` and {config:option}`server-core:core.max_upload_size` for some API endpoints.
+	// The path is relative to `/1.0/` and applies to all the endpoints below it, for example `images=20GiB` applies to `/1.0/images` and `/1.0/images/<fingerprint>`.
+	// The most specific path takes precedence, and a size of `0` disables the limit.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Per-endpoint maximum sizes of API requests
+	"core.request_size_overrides": {Validator: validate.Optional(validate.IsListOf(func(value string) error {
+		path, size, ok := strings.Cut(value, "=")
+		if !ok || strings.Trim(path, "/") == "" {
+			return fmt.Errorf("Invalid override %q, expected <path>=<size>", value)
+		}
+
+		return validate.IsSize(size)
+	}))},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.max_concurrent_image_imports)
+	// Specify the maximum number of image downloads and imports that run at the same time on each cluster member.
+	// Further ones are queued, and started in turn for each project.
+	// Only the operations requested through the API are limited, not those started by LXD itself such as image auto-updates.
+	// Set it to `0` to disable the limit.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum number of concurrent image imports
+	"core.max_concurrent_image_imports": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.max_concurrent_backups)
+	// Specify the maximum number of instance and custom volume backups, backup restores and instance exports that run at the same time on each cluster member.
+	// Further ones are queued, and started in turn for each project.
+	// Only the operations requested through the API are limited.
+	// Set it to `0` to disable the limit.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum number of concurrent backups
+	"core.max_concurrent_backups": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.max_concurrent_migrations)
+	// Specify the maximum number of instance migrations and replications, and custom volume copies, moves and migrations that run at the same time on each cluster member.
+	// Further ones are queued, and started in turn for each project.
+	// Only the migrations and copies running as background tasks are limited.
+	// The source side of a migration to another server or cluster member, which waits for the target to connect to its websockets, isn't limited, as queueing it would make the target time out.
+	// Set it to `0` to disable the limit.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum number of concurrent migrations
+	"core.max_concurrent_migrations": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.max_queued_operations)
+	// Specify the maximum number of operations of each project that can wait for {config:option}`server-core:core.max_concurrent_image_imports`, {config:option}`server-core:core.max_concurrent_backups` or {config:option}`server-core:core.max_concurrent_migrations`.
+	// Further operations fail with a `429 Too Many Requests` error.
+	// If not set, the number of waiting operations isn't limited.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  shortdesc: Maximum number of queued expensive operations per project
+	"core.max_queued_operations": {Validator: validate.Optional(validate.IsUint32)},
+
+	// lxdmeta:generate(entities=server; group=core; key=core.require_etag)
+	// If enabled, API requests that update an entity (`PUT` and `PATCH`) must include an `If-Match` header with the ETag of the entity.
+	// Requests without it are rejected with a `428 Precondition Required` error, so that concurrent changes can't be silently overwritten.
+	// Requests between cluster members are exempt.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether updates require an `If-Match` header
+	"core.require_etag": {Type: config.Bool, Default: "false"},
+
 	// lxdmeta:generate(entities=server; group=core; key=core.auth_secret_expiry)
 	// The secret is used for various cryptographic purposes, such as cookie encryption.
 	// When a given secret is older than the configured expiry, a new secret is generated.
@@ -534,6 +969,17 @@ var ConfigSchema = config.Schema{
 		return nil
 	}},
 
+	// lxdmeta:generate(entities=server; group=images; key=images.allowed_servers)
+	// Specify a comma-separated list of the image server URLs that images can be downloaded from, for example `https://cloud-images.ubuntu.com/releases`.
+	// An entry also allows the URLs below it.
+	// This applies to new instances, image copies and imports from a URL, and image auto-updates.
+	// If unset, images can be downloaded from any server.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Image servers that images can be downloaded from
+	"images.allowed_servers": {Validator: validate.Optional(validate.IsListOf(validate.IsRequestURL))},
+
 	// lxdmeta:generate(entities=server; group=images; key=images.auto_update_cached)
 	//
 	// ---
@@ -599,6 +1045,27 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to set `migration.stateful` to `true` for the instances
 	"instances.migration.stateful": {Type: config.Bool, Default: "false"},
 
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.state.history.interval)
+	// When set, each cluster member periodically samples the resource usage (CPU, memory, root disk and network)
+	// of its running instances and keeps the samples in memory.
+	// The history is available through the `/1.0/instances/{name}/state/history` endpoint.
+	// Set it to `0` to disable the history.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Interval (in minutes) between instance resource usage samples
+	"instances.state.history.interval": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 60))},
+
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.state.history.retention)
+	// The history is kept in memory and lost when LXD restarts or when the instance moves to another cluster member.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `24`
+	//  shortdesc: How long (in hours) to keep instance resource usage samples for
+	"instances.state.history.retention": {Type: config.Int64, Default: "24", Validator: validate.Optional(validate.IsInRange(1, 168))},
+
 	// TODO: Remove after sunset period
 	// lxdmeta:generate(entities=server; group=miscellaneous; key=user.instances.placement.scriptlet)
 	// Stores the migrated value from the deprecated `instances.placement.scriptlet` configuration key. LXD ignores this key; changing it has no effect. It exists only to preserve previously stored data and may be removed in a future release.
@@ -802,6 +1269,289 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OVN SSL client key
 	"network.ovn.client_key": {Default: ""},
 
+	// lxdmeta:generate(entities=server; group=replication; key=replication.target.address)
+	// Specify the address and port of the standby cluster, for example `192.0.2.10:8443`.
+	// Instances with {config:option}`instance-replication:replication.schedule` set are replicated to it.
+	// See {ref}`disaster-recovery-standby` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address of the standby cluster
+	"replication.target.address": {Validator: validate.Optional(validate.IsListenAddress(true, false, false))},
+
+	// lxdmeta:generate(entities=server; group=replication; key=replication.target.certificate)
+	// The standby cluster must trust the cluster certificate of this cluster.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Certificate of the standby cluster
+	"replication.target.certificate": {Validator: validate.Optional(validate.IsX509Certificate)},
+
+	// lxdmeta:generate(entities=server; group=notifications; key=smtp.address)
+	// Specify the host and port of the SMTP server, for example `mail.example.com:587`.
+	// It is used to send the notifications of the projects that set {config:option}`project-notifications:notifications.email.to`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address of the SMTP server used to send notification emails
+	"smtp.address": {Validator: validate.Optional(validate.IsListenAddress(true, false, true))},
+
+	// lxdmeta:generate(entities=server; group=notifications; key=smtp.password)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Password used to authenticate with the SMTP server
+	"smtp.password": {},
+
+	// lxdmeta:generate(entities=server; group=notifications; key=smtp.sender)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address the notification emails are sent from
+	"smtp.sender": {Validator: validate.Optional(validate.IsEmailAddress)},
+
+	// lxdmeta:generate(entities=server; group=notifications; key=smtp.username)
+	// If set, the SMTP server is authenticated with using the `PLAIN` mechanism.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: User name used to authenticate with the SMTP server
+	"smtp.username": {},
+
+	// lxdmeta:generate(entities=server; group=webhook; key=webhook.projects)
+	// Specify a comma-separated list of projects to send the events of.
+	// If empty, the events of all projects and the events that are not project specific are sent.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Projects to send the events of to the webhooks
+	"webhook.projects": {},
+
+	// lxdmeta:generate(entities=server; group=webhook; key=webhook.secret)
+	// When set, each request is signed with an HMAC-SHA256 of its body using this secret.
+	// The signature is sent in the `X-LXD-Signature` header, in the `sha256=<hex digest>` format.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Secret used to sign the webhook requests
+	"webhook.secret": {},
+
+	// lxdmeta:generate(entities=server; group=webhook; key=webhook.types)
+	// Specify a comma-separated list of events to send to the webhooks.
+	// The events can be any combination of `alert`, `lifecycle` and `operation`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle`
+	//  shortdesc: Events to send to the webhooks
+	"webhook.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("alert", "lifecycle", "operation"))), Default: "lifecycle"},
+
+	// lxdmeta:generate(entities=server; group=webhook; key=webhook.url)
+	// Specify a comma-separated list of HTTP or HTTPS URLs, for example `https://hooks.example.com/lxd`.
+	// Each event is sent as a JSON `POST` request to every URL.
+	// Failed requests are retried with an exponential backoff.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URLs to send the events to
+	"webhook.url": {Validator: validate.Optional(validate.IsListOf(validate.IsRequestURL))},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.file.max_files)
+	// When the file is rotated, the previous files are renamed with a numeric suffix, for example `events.log.1`.
+	// Set it to `0` to not keep any rotated file.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `5`
+	//  shortdesc: Number of rotated event files to keep
+	"sinks.file.max_files": {Type: config.Int64, Default: "5", Validator: validate.IsInRange(0, 100)},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.file.max_size)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `100`
+	//  shortdesc: Size (in MiB) at which the event file is rotated
+	"sinks.file.max_size": {Type: config.Int64, Default: "100", Validator: validate.IsInRange(1, 1024*1024)},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.file.path)
+	// Specify the absolute path of the file that each cluster member writes its events to, as JSON lines.
+	// The parent directory must exist.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Path of the file to write events to
+	"sinks.file.path": {Validator: validate.Optional(validate.IsAbsFilePath)},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.file.projects)
+	// Specify a comma-separated list of projects to forward the events of.
+	// If empty, the events of all projects and the events that are not project specific are forwarded.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Projects to forward the events of to the file
+	"sinks.file.projects": {},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.file.types)
+	// Specify a comma-separated list of events to forward to the file.
+	// The events can be any combination of `alert`, `lifecycle`, `logging`, `operation` and `ovn`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle`
+	//  shortdesc: Events to forward to the file
+	"sinks.file.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("alert", "lifecycle", "logging", "operation", "ovn"))), Default: "lifecycle"},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.kafka.topic)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lxd`
+	//  shortdesc: Kafka topic to produce events to
+	"sinks.kafka.topic": {Default: "lxd"},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.kafka.projects)
+	// Specify a comma-separated list of projects to forward the events of.
+	// If empty, the events of all projects and the events that are not project specific are forwarded.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Projects to forward the events of to the Kafka topic
+	"sinks.kafka.projects": {},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.kafka.types)
+	// Specify a comma-separated list of events to forward to the Kafka topic.
+	// The events can be any combination of `alert`, `lifecycle`, `logging`, `operation` and `ovn`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle`
+	//  shortdesc: Events to forward to the Kafka topic
+	"sinks.kafka.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("alert", "lifecycle", "logging", "operation", "ovn"))), Default: "lifecycle"},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.kafka.url)
+	// Specify the URL of a Kafka REST proxy, for example `http://kafka-rest.example.com:8082`.
+	// The events are produced as JSON records keyed by cluster member name.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL of the Kafka REST proxy
+	"sinks.kafka.url": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.mqtt.password)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Password used to authenticate with the MQTT broker
+	"sinks.mqtt.password": {},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.mqtt.projects)
+	// Specify a comma-separated list of projects to forward the events of.
+	// If empty, the events of all projects and the events that are not project specific are forwarded.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Projects to forward the events of to the MQTT broker
+	"sinks.mqtt.projects": {},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.mqtt.topic)
+	// The events are published to the `<topic>/<project>/<event type>` topic, or to `<topic>/<event type>` for events that are not project specific.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lxd`
+	//  shortdesc: Prefix of the MQTT topics to publish events to
+	"sinks.mqtt.topic": {Default: "lxd", Validator: func(value string) error {
+		if value == "" || strings.ContainsAny(value, "+#") {
+			return errors.New("The MQTT topic must not be empty or contain wildcards")
+		}
+
+		return nil
+	}},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.mqtt.types)
+	// Specify a comma-separated list of events to forward to the MQTT broker.
+	// The events can be any combination of `alert`, `lifecycle`, `logging`, `operation` and `ovn`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle`
+	//  shortdesc: Events to forward to the MQTT broker
+	"sinks.mqtt.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("alert", "lifecycle", "logging", "operation", "ovn"))), Default: "lifecycle"},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.mqtt.url)
+	// Specify the URL of the MQTT broker, for example `mqtt://broker.example.com:1883`, or `mqtts://broker.example.com:8883` to connect over TLS.
+	// The events are published as JSON messages with QoS 0.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL of the MQTT broker
+	"sinks.mqtt.url": {Validator: validate.Optional(func(value string) error {
+		u, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+
+		if u.Scheme != "mqtt" && u.Scheme != "mqtts" {
+			return errors.New("The MQTT broker URL scheme must be mqtt or mqtts")
+		}
+
+		if u.Hostname() == "" {
+			return errors.New("The MQTT broker URL must include a host")
+		}
+
+		return nil
+	})},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.mqtt.username)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: User name used to authenticate with the MQTT broker
+	"sinks.mqtt.username": {},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.syslog.address)
+	// Specify the name or IP and port of the syslog server, for example `syslog.example.com:514`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address of the remote syslog server
+	"sinks.syslog.address": {Validator: validate.Optional(validate.IsListenAddress(true, false, true))},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.syslog.projects)
+	// Specify a comma-separated list of projects to forward the events of.
+	// If empty, the events of all projects and the events that are not project specific are forwarded.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Projects to forward the events of to the syslog server
+	"sinks.syslog.projects": {},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.syslog.protocol)
+	// Possible values are `udp` and `tcp`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `udp`
+	//  shortdesc: Protocol used to connect to the syslog server
+	"sinks.syslog.protocol": {Default: "udp", Validator: validate.IsOneOf("udp", "tcp")},
+
+	// lxdmeta:generate(entities=server; group=sinks; key=sinks.syslog.types)
+	// Specify a comma-separated list of events to forward to the syslog server.
+	// The events can be any combination of `alert`, `lifecycle`, `logging`, `operation` and `ovn`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle`
+	//  shortdesc: Events to forward to the syslog server
+	"sinks.syslog.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("alert", "lifecycle", "logging", "operation", "ovn"))), Default: "lifecycle"},
+
 	// lxdmeta:generate(entities=server; group=miscellaneous; key=volatile.uuid)
 	// This UUID is used as a stable identifier for the cluster. It cannot be changed.
 	// ---
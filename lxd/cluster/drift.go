@@ -0,0 +1,167 @@
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// MemberDriftState is the effective state of a cluster member compared by Drift.
+type MemberDriftState struct {
+	Environment api.ServerEnvironment
+	Networks    []api.Network
+}
+
+// Drift compares the effective state of the cluster members, keyed by member name, and returns the
+// inconsistencies that could break instance migration or scheduling between them.
+func Drift(members map[string]MemberDriftState) []api.ClusterDriftInconsistency {
+	inconsistencies := []api.ClusterDriftInconsistency{}
+
+	// compare records an inconsistency if the values of the item aren't the same on all members.
+	compare := func(category string, name string, impact string, value func(state MemberDriftState) string) {
+		values := make(map[string]string, len(members))
+		for memberName, state := range members {
+			values[memberName] = value(state)
+		}
+
+		if !driftValuesDiffer(values) {
+			return
+		}
+
+		inconsistencies = append(inconsistencies, api.ClusterDriftInconsistency{
+			Category: category,
+			Name:     name,
+			Impact:   impact,
+			Values:   values,
+		})
+	}
+
+	// compareVersions records an inconsistency if a driver is missing on some members, which breaks scheduling,
+	// or if its version differs between members, which can break migration.
+	compareVersions := func(category string, versions func(state MemberDriftState) map[string]string) {
+		names := map[string]struct{}{}
+		for _, state := range members {
+			for name := range versions(state) {
+				names[name] = struct{}{}
+			}
+		}
+
+		for name := range names {
+			impact := "migration"
+			for _, state := range members {
+				_, ok := versions(state)[name]
+				if !ok {
+					impact = "scheduling"
+					break
+				}
+			}
+
+			compare(category, name, impact, func(state MemberDriftState) string { return versions(state)[name] })
+		}
+	}
+
+	// compareFeatures records an inconsistency for each feature not having the same value on all members.
+	compareFeatures := func(category string, features func(state MemberDriftState) map[string]string) {
+		names := map[string]struct{}{}
+		for _, state := range members {
+			for name := range features(state) {
+				names[name] = struct{}{}
+			}
+		}
+
+		for name := range names {
+			compare(category, name, "migration", func(state MemberDriftState) string { return features(state)[name] })
+		}
+	}
+
+	compare("kernel", "kernel_version", "migration", func(state MemberDriftState) string { return state.Environment.KernelVersion })
+	compare("kernel", "kernel_architecture", "migration", func(state MemberDriftState) string { return state.Environment.KernelArchitecture })
+
+	compareFeatures("kernel_feature", func(state MemberDriftState) map[string]string { return state.Environment.KernelFeatures })
+	compareFeatures("lxc_feature", func(state MemberDriftState) map[string]string { return state.Environment.LXCFeatures })
+
+	compareVersions("instance_driver", func(state MemberDriftState) map[string]string {
+		return driftInstanceDrivers(state.Environment)
+	})
+
+	compareVersions("storage_driver", func(state MemberDriftState) map[string]string {
+		versions := make(map[string]string, len(state.Environment.StorageSupportedDrivers))
+		for _, driver := range state.Environment.StorageSupportedDrivers {
+			versions[driver.Name] = driver.Version
+		}
+
+		return versions
+	})
+
+	// Managed networks must be available on all members for instances using them to be placed anywhere.
+	networks := map[string]struct{}{}
+	for _, state := range members {
+		for _, network := range state.Networks {
+			if network.Managed {
+				networks[network.Project+"/"+network.Name] = struct{}{}
+			}
+		}
+	}
+
+	for name := range networks {
+		compare("network", name, "scheduling", func(state MemberDriftState) string {
+			for _, network := range state.Networks {
+				if network.Managed && network.Project+"/"+network.Name == name {
+					return network.Status
+				}
+			}
+
+			return ""
+		})
+	}
+
+	sort.Slice(inconsistencies, func(i int, j int) bool {
+		if inconsistencies[i].Category != inconsistencies[j].Category {
+			return inconsistencies[i].Category < inconsistencies[j].Category
+		}
+
+		return inconsistencies[i].Name < inconsistencies[j].Name
+	})
+
+	return inconsistencies
+}
+
+// driftValuesDiffer returns true if the values aren't all the same.
+func driftValuesDiffer(values map[string]string) bool {
+	first := true
+	var reference string
+	for _, value := range values {
+		if first {
+			reference = value
+			first = false
+			continue
+		}
+
+		if value != reference {
+			return true
+		}
+	}
+
+	return false
+}
+
+// driftInstanceDrivers returns the versions of the instance drivers of the environment, keyed by driver name.
+func driftInstanceDrivers(env api.ServerEnvironment) map[string]string {
+	versions := map[string]string{}
+	if env.Driver == "" {
+		return versions
+	}
+
+	names := strings.Split(env.Driver, " | ")
+	driverVersions := strings.Split(env.DriverVersion, " | ")
+	for i, name := range names {
+		if i < len(driverVersions) {
+			versions[name] = driverVersions[i]
+		} else {
+			versions[name] = ""
+		}
+	}
+
+	return versions
+}
@@ -0,0 +1,74 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestDrift(t *testing.T) {
+	member := func(kernel string, qemu string, network string) cluster.MemberDriftState {
+		env := api.ServerEnvironment{
+			KernelVersion:      kernel,
+			KernelArchitecture: "x86_64",
+			KernelFeatures:     map[string]string{"idmapped_mounts": "true"},
+			Driver:             "lxc",
+			DriverVersion:      "6.0.0",
+			StorageSupportedDrivers: []api.ServerStorageDriverInfo{
+				{Name: "zfs", Version: "2.2.2"},
+			},
+		}
+
+		if qemu != "" {
+			env.Driver += " | qemu"
+			env.DriverVersion += " | " + qemu
+		}
+
+		return cluster.MemberDriftState{
+			Environment: env,
+			Networks: []api.Network{
+				{Name: "lxdbr0", Project: "default", Managed: true, Status: network},
+				{Name: "eth0", Managed: false},
+			},
+		}
+	}
+
+	// Identical members.
+	inconsistencies := cluster.Drift(map[string]cluster.MemberDriftState{
+		"lxd01": member("6.8.0", "8.2.2", api.NetworkStatusCreated),
+		"lxd02": member("6.8.0", "8.2.2", api.NetworkStatusCreated),
+	})
+
+	assert.Empty(t, inconsistencies)
+
+	// Drifted members.
+	inconsistencies = cluster.Drift(map[string]cluster.MemberDriftState{
+		"lxd01": member("6.8.0", "8.2.2", api.NetworkStatusCreated),
+		"lxd02": member("6.8.0", "9.0.0", api.NetworkStatusCreated),
+		"lxd03": member("6.11.0", "", api.NetworkStatusUnavailable),
+	})
+
+	assert.Equal(t, []api.ClusterDriftInconsistency{
+		{
+			Category: "instance_driver",
+			Name:     "qemu",
+			Impact:   "scheduling",
+			Values:   map[string]string{"lxd01": "8.2.2", "lxd02": "9.0.0", "lxd03": ""},
+		},
+		{
+			Category: "kernel",
+			Name:     "kernel_version",
+			Impact:   "migration",
+			Values:   map[string]string{"lxd01": "6.8.0", "lxd02": "6.8.0", "lxd03": "6.11.0"},
+		},
+		{
+			Category: "network",
+			Name:     "default/lxdbr0",
+			Impact:   "scheduling",
+			Values:   map[string]string{"lxd01": api.NetworkStatusCreated, "lxd02": api.NetworkStatusCreated, "lxd03": api.NetworkStatusUnavailable},
+		},
+	}, inconsistencies)
+}
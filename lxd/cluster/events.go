@@ -127,6 +127,20 @@ func ServerEventMode() EventMode {
 	return eventMode
 }
 
+// EventListenersStatus returns the event distribution mode that this local server is operating in, and whether the
+// listener pulling events from each member address is active.
+func EventListenersStatus() (EventMode, map[string]bool) {
+	listenersLock.Lock()
+	defer listenersLock.Unlock()
+
+	status := make(map[string]bool, len(listeners))
+	for address, listener := range listeners {
+		status[address] = listener.IsActive()
+	}
+
+	return eventMode, status
+}
+
 // RoleInSlice returns whether or not the rule is within the roles list.
 func RoleInSlice(role db.ClusterRole, roles []db.ClusterRole) bool {
 	return slices.Contains(roles, role)
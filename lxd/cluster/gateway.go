@@ -381,6 +381,7 @@ func (g *Gateway) DialFunc() client.DialFunc {
 
 		conn, err := dqliteNetworkDial(ctx, "dqlite", address, g)
 		if err != nil {
+			observeDialFailure(ctx, "dqlite", address)
 			return nil, err
 		}
 
@@ -404,6 +405,7 @@ func (g *Gateway) raftDial() client.DialFunc {
 
 		conn, err := dqliteNetworkDial(ctx, "raft", nodeAddress, g)
 		if err != nil {
+			observeDialFailure(ctx, "raft", nodeAddress)
 			return nil, err
 		}
 
@@ -656,6 +658,7 @@ func (g *Gateway) LeaderAddress() (string, error) {
 
 			if leader != nil && leader.Address != "" {
 				_ = client.Close()
+				observeLeader(leader.Address)
 				return leader.Address, nil
 			}
 
@@ -716,6 +719,7 @@ func (g *Gateway) LeaderAddress() (string, error) {
 			continue
 		}
 
+		observeLeader(leader)
 		return leader, nil
 	}
 
@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/osarch"
+)
+
+// GroupConstraints represents the constraints on the instances placed on the members of a cluster group.
+type GroupConstraints struct {
+	// Group is the name of the cluster group.
+	Group string
+
+	// Architectures are the architectures of the instances allowed on the members of the group (nil if any).
+	Architectures []int
+
+	// InstanceTypes are the types of the instances allowed on the members of the group (nil if any).
+	InstanceTypes []instancetype.Type
+
+	// Members are the names of the members of the group.
+	Members []string
+}
+
+// GroupConstraintsFromConfig returns the instance constraints defined by the cluster group configuration.
+// It returns nil if the cluster group doesn't define any constraint.
+func GroupConstraintsFromConfig(group string, config map[string]string, members []string) (*GroupConstraints, error) {
+	constraints := &GroupConstraints{
+		Group:   group,
+		Members: members,
+	}
+
+	for _, name := range shared.SplitNTrimSpace(config["instances.architectures"], ",", -1, true) {
+		architecture, err := osarch.ArchitectureId(name)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid architecture %q in cluster group %q: %w", name, group, err)
+		}
+
+		constraints.Architectures = append(constraints.Architectures, architecture)
+	}
+
+	for _, name := range shared.SplitNTrimSpace(config["instances.types"], ",", -1, true) {
+		instanceType, err := instancetype.New(name)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid instance type %q in cluster group %q: %w", name, group, err)
+		}
+
+		constraints.InstanceTypes = append(constraints.InstanceTypes, instanceType)
+	}
+
+	if constraints.Architectures == nil && constraints.InstanceTypes == nil {
+		return nil, nil
+	}
+
+	return constraints, nil
+}
+
+// AllowedArchitectures returns the given architectures which are allowed on the members of the group, keeping their
+// order. If no architecture is given, the architectures allowed by the group are returned.
+func (c *GroupConstraints) AllowedArchitectures(architectures []int) []int {
+	if c.Architectures == nil {
+		return architectures
+	}
+
+	if len(architectures) == 0 {
+		return c.Architectures
+	}
+
+	allowed := []int{}
+	for _, architecture := range architectures {
+		if slices.Contains(c.Architectures, architecture) {
+			allowed = append(allowed, architecture)
+		}
+	}
+
+	return allowed
+}
+
+// Check returns an error if an instance of one of the given architectures (any if empty) and of the given type
+// can't be placed on the members of the group.
+func (c *GroupConstraints) Check(architectures []int, instanceType instancetype.Type) error {
+	if c.InstanceTypes != nil && instanceType != instancetype.Any && !slices.Contains(c.InstanceTypes, instanceType) {
+		names := make([]string, 0, len(c.InstanceTypes))
+		for _, allowedType := range c.InstanceTypes {
+			names = append(names, allowedType.String())
+		}
+
+		return fmt.Errorf("Cluster group %q only allows instances of type %s", c.Group, strings.Join(names, ", "))
+	}
+
+	if c.Architectures != nil && len(architectures) > 0 && len(c.AllowedArchitectures(architectures)) == 0 {
+		names := make([]string, 0, len(c.Architectures))
+		for _, architecture := range c.Architectures {
+			name, _ := osarch.ArchitectureName(architecture)
+			names = append(names, name)
+		}
+
+		return fmt.Errorf("Cluster group %q only allows instances of architecture %s", c.Group, strings.Join(names, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/osarch"
+)
+
+func TestGroupConstraintsFromConfig(t *testing.T) {
+	constraints, err := cluster.GroupConstraintsFromConfig("g1", map[string]string{"images.replication": "eager"}, []string{"n1"})
+	require.NoError(t, err)
+	assert.Nil(t, constraints)
+
+	constraints, err = cluster.GroupConstraintsFromConfig("g1", map[string]string{"instances.architectures": "aarch64", "instances.types": "container"}, []string{"n1"})
+	require.NoError(t, err)
+	assert.Equal(t, &cluster.GroupConstraints{
+		Group:         "g1",
+		Architectures: []int{osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN},
+		InstanceTypes: []instancetype.Type{instancetype.Container},
+		Members:       []string{"n1"},
+	}, constraints)
+
+	_, err = cluster.GroupConstraintsFromConfig("g1", map[string]string{"instances.architectures": "foo"}, []string{"n1"})
+	assert.Error(t, err)
+}
+
+func TestGroupConstraintsCheck(t *testing.T) {
+	amd64 := osarch.ARCH_64BIT_INTEL_X86
+	arm64 := osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN
+
+	constraints := &cluster.GroupConstraints{
+		Group:         "arm",
+		Architectures: []int{arm64},
+		InstanceTypes: []instancetype.Type{instancetype.Container},
+	}
+
+	assert.NoError(t, constraints.Check([]int{amd64, arm64}, instancetype.Container))
+	assert.NoError(t, constraints.Check(nil, instancetype.Any))
+	assert.Error(t, constraints.Check([]int{amd64}, instancetype.Container))
+	assert.Error(t, constraints.Check([]int{arm64}, instancetype.VM))
+
+	// The image is picked among the architectures allowed by the group.
+	assert.Equal(t, []int{arm64}, constraints.AllowedArchitectures([]int{amd64, arm64}))
+	assert.Equal(t, []int{arm64}, constraints.AllowedArchitectures(nil))
+	assert.Equal(t, []int{}, constraints.AllowedArchitectures([]int{amd64}))
+}
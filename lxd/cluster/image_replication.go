@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"slices"
+	"strconv"
+)
+
+// ImageReplicationPolicy represents the image replication policy of a cluster group.
+type ImageReplicationPolicy struct {
+	// Eager is true if the images are copied to all the members of the group.
+	Eager bool
+
+	// MinimalReplica is the minimum number of members of the group holding a copy of each image.
+	MinimalReplica int
+
+	// Members are the names of the members of the group.
+	Members []string
+}
+
+// ImageReplicationPolicyFromConfig returns the image replication policy defined by the cluster group configuration.
+// It returns nil if the cluster group doesn't define any policy.
+func ImageReplicationPolicyFromConfig(config map[string]string, members []string) *ImageReplicationPolicy {
+	policy := &ImageReplicationPolicy{
+		Eager:   config["images.replication"] == "eager",
+		Members: members,
+	}
+
+	if config["images.minimal_replica"] != "" {
+		minimalReplica, err := strconv.Atoi(config["images.minimal_replica"])
+		if err == nil {
+			policy.MinimalReplica = minimalReplica
+		}
+	}
+
+	if !policy.Eager && policy.MinimalReplica <= 0 {
+		return nil
+	}
+
+	return policy
+}
+
+// ImageReplicationTargets returns the names of the cluster members that must receive a copy of an image held by the
+// holders in order to satisfy the replication policies. Only the available members are considered as targets and
+// they are picked in order when only some of them are needed.
+func ImageReplicationTargets(policies []ImageReplicationPolicy, holders []string, available []string) []string {
+	targets := []string{}
+
+	for _, policy := range policies {
+		held := 0
+		candidates := []string{}
+		for _, member := range policy.Members {
+			if slices.Contains(holders, member) || slices.Contains(targets, member) {
+				held++
+				continue
+			}
+
+			if slices.Contains(available, member) {
+				candidates = append(candidates, member)
+			}
+		}
+
+		count := len(candidates)
+		if !policy.Eager {
+			count = min(max(policy.MinimalReplica-held, 0), len(candidates))
+		}
+
+		targets = append(targets, candidates[:count]...)
+	}
+
+	return targets
+}
@@ -0,0 +1,43 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/cluster"
+)
+
+func TestImageReplicationPolicyFromConfig(t *testing.T) {
+	assert.Nil(t, cluster.ImageReplicationPolicyFromConfig(map[string]string{}, []string{"n1"}))
+	assert.Nil(t, cluster.ImageReplicationPolicyFromConfig(map[string]string{"images.replication": "lazy"}, []string{"n1"}))
+
+	policy := cluster.ImageReplicationPolicyFromConfig(map[string]string{"images.minimal_replica": "2"}, []string{"n1", "n2"})
+	assert.Equal(t, &cluster.ImageReplicationPolicy{MinimalReplica: 2, Members: []string{"n1", "n2"}}, policy)
+
+	policy = cluster.ImageReplicationPolicyFromConfig(map[string]string{"images.replication": "eager"}, []string{"n1"})
+	assert.Equal(t, &cluster.ImageReplicationPolicy{Eager: true, Members: []string{"n1"}}, policy)
+}
+
+func TestImageReplicationTargets(t *testing.T) {
+	available := []string{"n1", "n2", "n3", "n4", "n5"}
+
+	// Eager replication to all the available members of the group.
+	policies := []cluster.ImageReplicationPolicy{{Eager: true, Members: []string{"n1", "n2", "n3", "n6"}}}
+	assert.Equal(t, []string{"n2", "n3"}, cluster.ImageReplicationTargets(policies, []string{"n1"}, available))
+
+	// Minimal replica count, already partially satisfied.
+	policies = []cluster.ImageReplicationPolicy{{MinimalReplica: 3, Members: []string{"n1", "n2", "n3", "n4"}}}
+	assert.Equal(t, []string{"n2", "n3"}, cluster.ImageReplicationTargets(policies, []string{"n1", "n5"}, available))
+
+	// Minimal replica count already satisfied.
+	assert.Equal(t, []string{}, cluster.ImageReplicationTargets(policies, []string{"n1", "n2", "n4"}, available))
+
+	// Overlapping groups don't copy the image twice.
+	policies = []cluster.ImageReplicationPolicy{
+		{Eager: true, Members: []string{"n2", "n3"}},
+		{MinimalReplica: 2, Members: []string{"n3", "n4", "n5"}},
+	}
+
+	assert.Equal(t, []string{"n2", "n3", "n4"}, cluster.ImageReplicationTargets(policies, []string{"n1"}, available))
+}
@@ -197,5 +197,10 @@ func MemberState(ctx context.Context, s *state.State) (*api.ClusterMemberState,
 		}
 	}
 
+	memberState.Scheduler, err = MemberScheduler(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting scheduler state: %w", err)
+	}
+
 	return &memberState, nil
 }
@@ -1079,9 +1079,26 @@ func Handover(state *state.State, gateway *Gateway, address string) (string, []d
 	return "", nil, nil
 }
 
+// raftNodeMetadata returns the metadata used to pick the database role of an online member in the given failure
+// domain. Witness members have a lower weight, which makes them preferred when a voter is promoted and kept when one
+// is demoted. This is only a preference: the failure domains take precedence, and a witness doesn't replace an
+// existing voter.
+func raftNodeMetadata(failureDomain uint64, witness bool) *client.NodeMetadata {
+	var weight uint64 = 1
+	if witness {
+		weight = 0
+	}
+
+	return &client.NodeMetadata{
+		FailureDomain: failureDomain,
+		Weight:        weight,
+	}
+}
+
 // Build an app.RolesChanges object feeded with the current cluster state.
 func newRolesChanges(state *state.State, gateway *Gateway, nodes []db.RaftNode, unavailableMembers []string) (*app.RolesChanges, error) {
 	var domains map[string]uint64
+	witnesses := map[string]bool{}
 	err := state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
@@ -1090,6 +1107,17 @@ func newRolesChanges(state *state.State, gateway *Gateway, nodes []db.RaftNode,
 			return fmt.Errorf("Load failure domains: %w", err)
 		}
 
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Load cluster members: %w", err)
+		}
+
+		for _, member := range members {
+			if slices.Contains(member.Roles, db.ClusterRoleWitness) {
+				witnesses[member.Address] = true
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1100,9 +1128,7 @@ func newRolesChanges(state *state.State, gateway *Gateway, nodes []db.RaftNode,
 
 	for _, node := range nodes {
 		if !slices.Contains(unavailableMembers, node.Address) && HasConnectivity(gateway.networkCert, gateway.state().ServerCert(), node.Address) {
-			cluster[node.NodeInfo] = &client.NodeMetadata{
-				FailureDomain: domains[node.Address],
-			}
+			cluster[node.NodeInfo] = raftNodeMetadata(domains[node.Address], witnesses[node.Address])
 		} else {
 			cluster[node.NodeInfo] = nil
 		}
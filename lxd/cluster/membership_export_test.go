@@ -0,0 +1,4 @@
+package cluster
+
+// RaftNodeMetadata returns the metadata used to pick the database role of an online member.
+var RaftNodeMetadata = raftNodeMetadata
@@ -0,0 +1,176 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+)
+
+// DialFailures is the number of failed connections to a cluster member.
+type DialFailures struct {
+	// Type is the type of connection, either "dqlite" or "raft".
+	Type string
+
+	// Address is the address of the cluster member.
+	Address string
+
+	Count int64
+}
+
+// RaftMetrics is the dqlite and raft health of this cluster member.
+type RaftMetrics struct {
+	// LeaderChanges is the number of times this member saw the raft leader change.
+	LeaderChanges int64
+
+	// ApplyLatency is the time it took the leader to commit its last heartbeat round to the global database.
+	// It's zero if this member hasn't been the leader.
+	ApplyLatency time.Duration
+
+	// SnapshotAge is the age of the most recent raft snapshot. It's zero if this member has no snapshot.
+	SnapshotAge time.Duration
+
+	DialFailures []DialFailures
+
+	// Roles is the raft role of each database member, keyed by member name.
+	Roles map[string]string
+}
+
+type dialTarget struct {
+	kind    string
+	address string
+}
+
+// raftStats records the raft events observed by this member.
+var raftStats = struct {
+	mu            sync.Mutex
+	leaderAddress string
+	leaderChanges int64
+	applyLatency  time.Duration
+	dialFailures  map[dialTarget]int64
+}{
+	dialFailures: map[dialTarget]int64{},
+}
+
+// observeLeader records the current raft leader and counts a change if it differs from the previous one.
+func observeLeader(address string) {
+	raftStats.mu.Lock()
+	defer raftStats.mu.Unlock()
+
+	if raftStats.leaderAddress != "" && raftStats.leaderAddress != address {
+		raftStats.leaderChanges++
+	}
+
+	raftStats.leaderAddress = address
+}
+
+// observeApplyLatency records the time it took to commit a write to the global database.
+func observeApplyLatency(duration time.Duration) {
+	raftStats.mu.Lock()
+	defer raftStats.mu.Unlock()
+
+	raftStats.applyLatency = duration
+}
+
+// observeDialFailure counts a failed dqlite or raft connection to the given address.
+// Connections aborted because the context was cancelled aren't counted.
+func observeDialFailure(ctx context.Context, kind string, address string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	raftStats.mu.Lock()
+	defer raftStats.mu.Unlock()
+
+	raftStats.dialFailures[dialTarget{kind: kind, address: address}]++
+}
+
+// GetRaftMetrics returns the dqlite and raft health of this cluster member.
+func GetRaftMetrics(ctx context.Context, node *db.Node) (*RaftMetrics, error) {
+	out := &RaftMetrics{Roles: map[string]string{}}
+
+	raftStats.mu.Lock()
+	out.LeaderChanges = raftStats.leaderChanges
+	out.ApplyLatency = raftStats.applyLatency
+
+	for target, count := range raftStats.dialFailures {
+		out.DialFailures = append(out.DialFailures, DialFailures{Type: target.kind, Address: target.address, Count: count})
+	}
+
+	raftStats.mu.Unlock()
+
+	sort.Slice(out.DialFailures, func(i, j int) bool {
+		if out.DialFailures[i].Type != out.DialFailures[j].Type {
+			return out.DialFailures[i].Type < out.DialFailures[j].Type
+		}
+
+		return out.DialFailures[i].Address < out.DialFailures[j].Address
+	})
+
+	var raftNodes []db.RaftNode
+	err := node.Transaction(ctx, func(ctx context.Context, tx *db.NodeTx) error {
+		var err error
+
+		raftNodes, err = tx.GetRaftNodes(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raftNode := range raftNodes {
+		name := raftNode.Name
+		if name == "" {
+			name = raftNode.Address
+		}
+
+		out.Roles[name] = raftNode.Role.String()
+	}
+
+	out.SnapshotAge, err = raftSnapshotAge(node.DqliteDir())
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// raftSnapshotAge returns the age of the most recent raft snapshot in the given dqlite directory.
+func raftSnapshotAge(dir string) (time.Duration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	var latest time.Time
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "snapshot-") || filepath.Ext(entry.Name()) == ".meta" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	if latest.IsZero() {
+		return 0, nil
+	}
+
+	return time.Since(latest), nil
+}
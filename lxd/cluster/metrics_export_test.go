@@ -0,0 +1,15 @@
+package cluster
+
+import (
+	"context"
+)
+
+// ObserveLeader records the current raft leader.
+func ObserveLeader(address string) {
+	observeLeader(address)
+}
+
+// ObserveDialFailure counts a failed dqlite or raft connection to the given address.
+func ObserveDialFailure(ctx context.Context, kind string, address string) {
+	observeDialFailure(ctx, kind, address)
+}
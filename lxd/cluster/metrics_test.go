@@ -0,0 +1,71 @@
+package cluster_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/go-dqlite/v3/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+)
+
+func TestGetRaftMetrics(t *testing.T) {
+	node, cleanup := db.NewTestNode(t)
+	defer cleanup()
+
+	raftNodes := []db.RaftNode{
+		{NodeInfo: client.NodeInfo{ID: 1, Address: "10.1.1.1:8443", Role: db.RaftVoter}, Name: "node1"},
+		{NodeInfo: client.NodeInfo{ID: 2, Address: "10.1.1.2:8443", Role: db.RaftStandBy}, Name: "node2"},
+		{NodeInfo: client.NodeInfo{ID: 3, Address: "10.1.1.3:8443", Role: db.RaftSpare}},
+	}
+
+	err := node.Transaction(context.Background(), func(ctx context.Context, tx *db.NodeTx) error {
+		return tx.ReplaceRaftNodes(raftNodes)
+	})
+	require.NoError(t, err)
+
+	before, err := cluster.GetRaftMetrics(context.Background(), node)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"node1": "voter", "node2": "stand-by", "10.1.1.3:8443": "spare"}, before.Roles)
+	assert.Zero(t, before.SnapshotAge)
+
+	// Leader changes are counted, but not the first leader seen nor the same leader seen again.
+	cluster.ObserveLeader("10.1.1.1:8443")
+	cluster.ObserveLeader("10.1.1.1:8443")
+	cluster.ObserveLeader("10.1.1.2:8443")
+	cluster.ObserveLeader("10.1.1.1:8443")
+
+	// Connections aborted by the caller aren't counted as failures.
+	ctx, cancel := context.WithCancel(context.Background())
+	cluster.ObserveDialFailure(ctx, "raft", "10.1.1.9:8443")
+	cluster.ObserveDialFailure(ctx, "raft", "10.1.1.9:8443")
+	cluster.ObserveDialFailure(ctx, "dqlite", "10.1.1.9:8443")
+	cancel()
+	cluster.ObserveDialFailure(ctx, "dqlite", "10.1.1.9:8443")
+
+	// Only the most recent snapshot is considered, and its metadata file is ignored.
+	dqliteDir := node.DqliteDir()
+	require.NoError(t, os.MkdirAll(dqliteDir, 0700))
+
+	for name, age := range map[string]time.Duration{"snapshot-1-100-1000": time.Hour, "snapshot-1-200-2000": time.Minute, "snapshot-1-200-2000.meta": 0} {
+		path := filepath.Join(dqliteDir, name)
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		require.NoError(t, os.Chtimes(path, time.Now().Add(-age), time.Now().Add(-age)))
+	}
+
+	after, err := cluster.GetRaftMetrics(context.Background(), node)
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, after.LeaderChanges-before.LeaderChanges, int64(2))
+	assert.Contains(t, after.DialFailures, cluster.DialFailures{Type: "raft", Address: "10.1.1.9:8443", Count: 2})
+	assert.Contains(t, after.DialFailures, cluster.DialFailures{Type: "dqlite", Address: "10.1.1.9:8443", Count: 1})
+	assert.GreaterOrEqual(t, after.SnapshotAge, time.Minute)
+	assert.Less(t, after.SnapshotAge, time.Hour)
+}
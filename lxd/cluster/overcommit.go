@@ -0,0 +1,220 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/resources"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// Resources allocated to virtual machines without limits, matching the QEMU driver defaults.
+const (
+	overcommitVMDefaultCPUs   = 1
+	overcommitVMDefaultMemory = 1024 * 1024 * 1024
+)
+
+// ValidateOvercommitRatio validates an overcommit ratio, a positive decimal number.
+func ValidateOvercommitRatio(value string) error {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid overcommit ratio %q: %w", value, err)
+	}
+
+	if ratio <= 0 || math.IsInf(ratio, 0) {
+		return fmt.Errorf("Invalid overcommit ratio %q, must be a positive number", value)
+	}
+
+	return nil
+}
+
+// HasOvercommitPolicy returns whether the cluster member configuration sets an overcommit policy.
+func HasOvercommitPolicy(config map[string]string) bool {
+	for key := range config {
+		if strings.HasPrefix(key, "scheduler.overcommit.") || strings.HasPrefix(key, "scheduler.reserved.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InstanceAllocation returns the number of CPUs and the memory (in bytes) allocated to an instance with the given
+// expanded configuration, on a cluster member with the given total memory.
+func InstanceAllocation(config map[string]string, instanceType instancetype.Type, memoryTotal uint64) (uint64, uint64, error) {
+	var cpus uint64
+	var memory uint64
+
+	if instanceType == instancetype.VM {
+		cpus = overcommitVMDefaultCPUs
+		memory = overcommitVMDefaultMemory
+	}
+
+	limitsCPU := config["limits.cpu"]
+	if limitsCPU != "" {
+		count, err := strconv.ParseUint(limitsCPU, 10, 64)
+		if err != nil {
+			pins, err := resources.ParseCpuset(limitsCPU)
+			if err != nil {
+				return 0, 0, fmt.Errorf("Failed parsing limits.cpu: %w", err)
+			}
+
+			count = uint64(len(pins))
+		}
+
+		cpus = count
+	}
+
+	limitsMemory := config["limits.memory"]
+	if limitsMemory != "" {
+		percent, ok := strings.CutSuffix(limitsMemory, "%")
+		if ok {
+			value, err := strconv.ParseFloat(percent, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("Failed parsing limits.memory: %w", err)
+			}
+
+			memory = uint64(float64(memoryTotal) * value / 100)
+		} else {
+			value, err := units.ParseByteSizeString(limitsMemory)
+			if err != nil {
+				return 0, 0, fmt.Errorf("Failed parsing limits.memory: %w", err)
+			}
+
+			memory = uint64(value)
+		}
+	}
+
+	return cpus, memory, nil
+}
+
+// MemberScheduler computes the resource allocation of the local cluster member against its overcommit policy.
+// It returns nil if the cluster member has no overcommit policy.
+func MemberScheduler(ctx context.Context, s *state.State) (*api.ClusterMemberScheduler, error) {
+	var member db.NodeInfo
+	var instances []db.InstanceArgs
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		member, err = tx.GetNodeByName(ctx, s.ServerName)
+		if err != nil {
+			return fmt.Errorf("Failed loading cluster member: %w", err)
+		}
+
+		if !HasOvercommitPolicy(member.Config) {
+			return nil
+		}
+
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, _ api.Project) error {
+			instances = append(instances, inst)
+			return nil
+		}, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !HasOvercommitPolicy(member.Config) {
+		return nil, nil
+	}
+
+	memory, err := resources.GetMemory()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting memory resources: %w", err)
+	}
+
+	scheduler := &api.ClusterMemberScheduler{
+		MemoryTotal:     memory.Total,
+		MemoryAvailable: memory.Total - memory.Used,
+	}
+
+	for _, inst := range instances {
+		config := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), inst.Config, inst.Profiles)
+
+		cpus, memoryAllocated, err := InstanceAllocation(config, inst.Type, memory.Total)
+		if err != nil {
+			return nil, fmt.Errorf("Failed computing allocation of instance %q in project %q: %w", inst.Name, inst.Project, err)
+		}
+
+		scheduler.AllocatedCPUs += cpus
+		scheduler.AllocatedMemory += memoryAllocated
+	}
+
+	var reservedCPUs uint64
+	if member.Config["scheduler.reserved.cpu"] != "" {
+		reservedCPUs, err = strconv.ParseUint(member.Config["scheduler.reserved.cpu"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing scheduler.reserved.cpu: %w", err)
+		}
+	}
+
+	if member.Config["scheduler.reserved.memory"] != "" {
+		reserved, err := units.ParseByteSizeString(member.Config["scheduler.reserved.memory"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing scheduler.reserved.memory: %w", err)
+		}
+
+		scheduler.MemoryReserved = uint64(reserved)
+	}
+
+	if member.Config["scheduler.overcommit.cpu"] != "" {
+		ratio, err := strconv.ParseFloat(member.Config["scheduler.overcommit.cpu"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing scheduler.overcommit.cpu: %w", err)
+		}
+
+		cpus := uint64(runtime.NumCPU())
+		scheduler.CPUsLimit = uint64(float64(max(cpus, reservedCPUs)-reservedCPUs) * ratio)
+	}
+
+	if member.Config["scheduler.overcommit.memory"] != "" {
+		ratio, err := strconv.ParseFloat(member.Config["scheduler.overcommit.memory"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing scheduler.overcommit.memory: %w", err)
+		}
+
+		scheduler.MemoryLimit = uint64(float64(max(memory.Total, scheduler.MemoryReserved)-scheduler.MemoryReserved) * ratio)
+	}
+
+	// Consider the member saturated when it can't accept any additional CPU or memory.
+	err = SchedulerFits(scheduler, 1, 1)
+	if err != nil {
+		scheduler.Saturated = err.Error()
+	}
+
+	return scheduler, nil
+}
+
+// SchedulerFits checks whether an instance allocated the given number of CPUs and memory can be placed on the
+// cluster member without exceeding its overcommit policy.
+func SchedulerFits(scheduler *api.ClusterMemberScheduler, cpus uint64, memory uint64) error {
+	if scheduler == nil {
+		return nil
+	}
+
+	if scheduler.MemoryReserved > 0 && scheduler.MemoryAvailable < scheduler.MemoryReserved+memory {
+		return errors.New("Available memory is below the reserved headroom")
+	}
+
+	if scheduler.CPUsLimit > 0 && scheduler.AllocatedCPUs+cpus > scheduler.CPUsLimit {
+		return errors.New("CPU overcommit limit reached")
+	}
+
+	if scheduler.MemoryLimit > 0 && scheduler.AllocatedMemory+memory > scheduler.MemoryLimit {
+		return errors.New("Memory overcommit limit reached")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestInstanceAllocation(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	cases := []struct {
+		config       map[string]string
+		instanceType instancetype.Type
+		cpus         uint64
+		memory       uint64
+	}{
+		{map[string]string{}, instancetype.Container, 0, 0},
+		{map[string]string{}, instancetype.VM, 1, gib},
+		{map[string]string{"limits.cpu": "4", "limits.memory": "2GiB"}, instancetype.VM, 4, 2 * gib},
+		{map[string]string{"limits.cpu": "0-3,8", "limits.memory": "25%"}, instancetype.Container, 5, 4 * gib},
+	}
+
+	for _, c := range cases {
+		cpus, memory, err := cluster.InstanceAllocation(c.config, c.instanceType, 16*gib)
+		require.NoError(t, err)
+		assert.Equal(t, c.cpus, cpus)
+		assert.Equal(t, c.memory, memory)
+	}
+
+	_, _, err := cluster.InstanceAllocation(map[string]string{"limits.memory": "lots"}, instancetype.Container, 16*gib)
+	assert.Error(t, err)
+}
+
+func TestSchedulerFits(t *testing.T) {
+	assert.NoError(t, cluster.SchedulerFits(nil, 64, 1024))
+
+	scheduler := &api.ClusterMemberScheduler{
+		AllocatedCPUs:   6,
+		CPUsLimit:       8,
+		AllocatedMemory: 900,
+		MemoryLimit:     1000,
+		MemoryAvailable: 500,
+		MemoryReserved:  200,
+	}
+
+	assert.NoError(t, cluster.SchedulerFits(scheduler, 2, 100))
+	assert.EqualError(t, cluster.SchedulerFits(scheduler, 3, 100), "CPU overcommit limit reached")
+	assert.EqualError(t, cluster.SchedulerFits(scheduler, 2, 101), "Memory overcommit limit reached")
+	assert.EqualError(t, cluster.SchedulerFits(scheduler, 2, 301), "Available memory is below the reserved headroom")
+}
+
+func TestValidateOvercommitRatio(t *testing.T) {
+	assert.NoError(t, cluster.ValidateOvercommitRatio("1.5"))
+	assert.Error(t, cluster.ValidateOvercommitRatio("0"))
+	assert.Error(t, cluster.ValidateOvercommitRatio("-1"))
+	assert.Error(t, cluster.ValidateOvercommitRatio("abc"))
+}
@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"slices"
+	"sort"
+)
+
+// RebalanceMember represents a cluster member considered by the workload rebalancer.
+type RebalanceMember struct {
+	// Name of the cluster member.
+	Name string
+
+	// Load of the cluster member, as the load average in percent of its logical CPUs.
+	Load float64
+
+	// LogicalCPUs is the number of logical CPUs of the cluster member.
+	LogicalCPUs uint64
+}
+
+// RebalanceInstance represents an instance that the workload rebalancer can move.
+type RebalanceInstance struct {
+	// Project of the instance.
+	Project string
+
+	// Name of the instance.
+	Name string
+
+	// Location is the name of the cluster member running the instance.
+	Location string
+
+	// CPUs is the number of CPUs of the instance, used to estimate its load.
+	CPUs uint64
+
+	// Targets are the names of the cluster members the instance can be moved to.
+	Targets []string
+}
+
+// RebalanceMove represents an instance move planned by the workload rebalancer.
+type RebalanceMove struct {
+	Instance RebalanceInstance
+	Source   string
+	Target   string
+}
+
+// rebalanceInstanceLoad returns the estimated load of the instance on the cluster member, assuming it keeps all its
+// CPUs busy.
+func rebalanceInstanceLoad(inst RebalanceInstance, member RebalanceMember) float64 {
+	return float64(inst.CPUs) * 100 / float64(member.LogicalCPUs)
+}
+
+// RebalancePlan returns the instance moves evening out the load of the cluster members, until the load difference
+// between the most and the least loaded members is below the threshold or the maximum number of moves is reached.
+// Only moves that don't make the target member more loaded than the source member are planned.
+func RebalancePlan(members []RebalanceMember, instances []RebalanceInstance, threshold float64, maxMoves int) []RebalanceMove {
+	moves := []RebalanceMove{}
+	members = slices.Clone(members)
+	instances = slices.Clone(instances)
+
+	for len(moves) < maxMoves && len(members) > 1 {
+		sort.SliceStable(members, func(i, j int) bool { return members[i].Load > members[j].Load })
+
+		source := &members[0]
+		if source.LogicalCPUs == 0 || source.Load-members[len(members)-1].Load < threshold {
+			break
+		}
+
+		// Pick the largest instance of the most loaded member that can be moved to a less loaded member
+		// without overshooting, preferring the least loaded targets.
+		bestInstance := -1
+		var bestTarget *RebalanceMember
+		for i, inst := range instances {
+			if inst.Location != source.Name || (bestInstance >= 0 && inst.CPUs <= instances[bestInstance].CPUs) {
+				continue
+			}
+
+			for j := len(members) - 1; j > 0; j-- {
+				target := &members[j]
+				if target.LogicalCPUs == 0 || !slices.Contains(inst.Targets, target.Name) {
+					continue
+				}
+
+				if target.Load+rebalanceInstanceLoad(inst, *target) > source.Load-rebalanceInstanceLoad(inst, *source) {
+					continue
+				}
+
+				bestInstance = i
+				bestTarget = target
+				break
+			}
+		}
+
+		if bestInstance < 0 {
+			break
+		}
+
+		inst := instances[bestInstance]
+		moves = append(moves, RebalanceMove{Instance: inst, Source: source.Name, Target: bestTarget.Name})
+
+		source.Load -= rebalanceInstanceLoad(inst, *source)
+		bestTarget.Load += rebalanceInstanceLoad(inst, *bestTarget)
+		instances = slices.Delete(instances, bestInstance, bestInstance+1)
+	}
+
+	return moves
+}
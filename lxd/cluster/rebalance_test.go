@@ -0,0 +1,43 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/cluster"
+)
+
+func TestRebalancePlan(t *testing.T) {
+	members := []cluster.RebalanceMember{
+		{Name: "n1", Load: 90, LogicalCPUs: 10},
+		{Name: "n2", Load: 10, LogicalCPUs: 10},
+		{Name: "n3", Load: 50, LogicalCPUs: 10},
+	}
+
+	instances := []cluster.RebalanceInstance{
+		{Name: "small", Location: "n1", CPUs: 1, Targets: []string{"n2", "n3"}},
+		{Name: "large", Location: "n1", CPUs: 2, Targets: []string{"n2", "n3"}},
+		{Name: "pinned", Location: "n1", CPUs: 4},
+		{Name: "other", Location: "n3", CPUs: 1, Targets: []string{"n1", "n2"}},
+	}
+
+	// The largest movable instance goes to the least loaded member first.
+	moves := cluster.RebalancePlan(members, instances, 20, 1)
+	assert.Len(t, moves, 1)
+	assert.Equal(t, "large", moves[0].Instance.Name)
+	assert.Equal(t, "n1", moves[0].Source)
+	assert.Equal(t, "n2", moves[0].Target)
+
+	// Moves stop once the most loaded member has no movable instance left.
+	moves = cluster.RebalancePlan(members, instances, 20, 10)
+	names := []string{}
+	for _, move := range moves {
+		names = append(names, move.Instance.Name+"->"+move.Target)
+	}
+
+	assert.Equal(t, []string{"large->n2", "small->n2"}, names)
+
+	// Nothing to do below the threshold.
+	assert.Empty(t, cluster.RebalancePlan(members, instances, 90, 10))
+}
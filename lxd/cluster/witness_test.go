@@ -0,0 +1,64 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/canonical/go-dqlite/v3/app"
+	"github.com/canonical/go-dqlite/v3/client"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/cluster"
+)
+
+func TestWitnessVoterPreference(t *testing.T) {
+	// A missing voter is preferably replaced by a witness.
+	changes := &app.RolesChanges{
+		Config: app.RolesConfig{Voters: 3, StandBys: 2},
+		State: map[client.NodeInfo]*client.NodeMetadata{
+			{ID: 1, Address: "10.0.0.1:8443", Role: client.Voter}:   cluster.RaftNodeMetadata(0, false),
+			{ID: 2, Address: "10.0.0.2:8443", Role: client.Voter}:   cluster.RaftNodeMetadata(0, false),
+			{ID: 3, Address: "10.0.0.3:8443", Role: client.StandBy}: cluster.RaftNodeMetadata(0, false),
+			{ID: 4, Address: "10.0.0.4:8443", Role: client.Spare}:   cluster.RaftNodeMetadata(0, true),
+		},
+	}
+
+	role, candidates := changes.Adjust(1)
+	assert.Equal(t, client.Voter, role)
+	assert.Equal(t, "10.0.0.4:8443", candidates[0].Address)
+
+	// The failure domains take precedence: a member of a failure domain without voters is promoted first.
+	changes.State = map[client.NodeInfo]*client.NodeMetadata{
+		{ID: 1, Address: "10.0.0.1:8443", Role: client.Voter}:   cluster.RaftNodeMetadata(1, false),
+		{ID: 2, Address: "10.0.0.2:8443", Role: client.Voter}:   cluster.RaftNodeMetadata(1, false),
+		{ID: 3, Address: "10.0.0.3:8443", Role: client.StandBy}: cluster.RaftNodeMetadata(2, false),
+		{ID: 4, Address: "10.0.0.4:8443", Role: client.Spare}:   cluster.RaftNodeMetadata(1, true),
+	}
+
+	role, candidates = changes.Adjust(1)
+	assert.Equal(t, client.Voter, role)
+	assert.Equal(t, "10.0.0.3:8443", candidates[0].Address)
+
+	// A witness in its own failure domain becomes a voter even when there are enough voters already.
+	changes.State = map[client.NodeInfo]*client.NodeMetadata{
+		{ID: 1, Address: "10.0.0.1:8443", Role: client.Voter}: cluster.RaftNodeMetadata(1, false),
+		{ID: 2, Address: "10.0.0.2:8443", Role: client.Voter}: cluster.RaftNodeMetadata(1, false),
+		{ID: 3, Address: "10.0.0.3:8443", Role: client.Voter}: cluster.RaftNodeMetadata(2, false),
+		{ID: 4, Address: "10.0.0.4:8443", Role: client.Spare}: cluster.RaftNodeMetadata(3, true),
+	}
+
+	role, candidates = changes.Adjust(1)
+	assert.Equal(t, client.Voter, role)
+	assert.Equal(t, "10.0.0.4:8443", candidates[0].Address)
+
+	// Once promoted, a regular member of the failure domain with several voters is demoted rather than the witness.
+	changes.State = map[client.NodeInfo]*client.NodeMetadata{
+		{ID: 1, Address: "10.0.0.1:8443", Role: client.Voter}: cluster.RaftNodeMetadata(1, false),
+		{ID: 2, Address: "10.0.0.2:8443", Role: client.Voter}: cluster.RaftNodeMetadata(1, false),
+		{ID: 3, Address: "10.0.0.3:8443", Role: client.Voter}: cluster.RaftNodeMetadata(2, false),
+		{ID: 4, Address: "10.0.0.4:8443", Role: client.Voter}: cluster.RaftNodeMetadata(3, true),
+	}
+
+	role, candidates = changes.Adjust(3)
+	assert.Equal(t, client.Spare, role)
+	assert.Contains(t, []string{"10.0.0.1:8443", "10.0.0.2:8443"}, candidates[0].Address)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// clusterContactRefresh records that this member is in contact with the rest of the cluster, either because it
+// received a heartbeat from the leader or because it completed a heartbeat round as the leader. If this member
+// was isolated, a warning is recorded now that the cluster database is reachable again.
+func (d *Daemon) clusterContactRefresh() {
+	now := time.Now()
+
+	d.partitionMu.Lock()
+	d.lastHeartbeat = now
+	partitionedSince := d.partitionedSince
+	fenced := d.partitionFenced
+	d.partitionedSince = time.Time{}
+	d.partitionFenced = false
+	d.partitionMu.Unlock()
+
+	if partitionedSince.IsZero() {
+		return
+	}
+
+	logger.Warn("Cluster partition resolved", logger.Ctx{"partitionedSince": partitionedSince, "fenced": fenced})
+
+	// Record the warning asynchronously so that the heartbeat response isn't delayed.
+	go func() {
+		if d.db.Cluster == nil {
+			return
+		}
+
+		err := d.db.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, "", "", -1, warningtype.ClusterPartition, fmt.Sprintf("partitionedSince: %s, resolvedAt: %s, fenced: %t", partitionedSince.UTC(), now.UTC(), fenced))
+		})
+		if err != nil {
+			logger.Warn("Failed to create cluster partition warning", logger.Ctx{"err": err})
+		}
+	}()
+}
+
+// clusterPartitionTask detects when this member has been out of contact with the rest of the cluster for longer
+// than the offline threshold. At that point the other members consider it offline and may start its instances
+// elsewhere, so the configured partition fence hook is run to make sure it stops accessing the shared storage.
+func clusterPartitionTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		if !s.ServerClustered {
+			return
+		}
+
+		d.partitionMu.Lock()
+		lastHeartbeat := d.lastHeartbeat
+
+		// Skip if no contact has been made yet, if still in contact or if the partition was already detected.
+		if lastHeartbeat.IsZero() || time.Since(lastHeartbeat) < s.GlobalConfig.OfflineThreshold() || !d.partitionedSince.IsZero() {
+			d.partitionMu.Unlock()
+			return
+		}
+
+		d.partitionedSince = time.Now()
+		d.partitionMu.Unlock()
+
+		logger.Warn("Cluster member isolated from the rest of the cluster", logger.Ctx{"lastHeartbeat": lastHeartbeat})
+
+		hookName := s.GlobalConfig.ClusterPartitionFenceHook()
+		fenced := false
+		if hookName != "" {
+			err := clusterPartitionFence(ctx, s.ServerName, s.LocalConfig.ClusterAddress(), hookName, lastHeartbeat)
+			if err != nil {
+				logger.Error("Failed fencing isolated cluster member", logger.Ctx{"err": err})
+			} else {
+				fenced = true
+
+				// Only record the fencing if the partition wasn't resolved in the meantime.
+				d.partitionMu.Lock()
+				d.partitionFenced = !d.partitionedSince.IsZero()
+				d.partitionMu.Unlock()
+			}
+		}
+
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberPartitioned.Event(s.ServerName, nil, map[string]any{"last_heartbeat": lastHeartbeat.UTC(), "fenced": fenced}))
+	}
+
+	return f, task.Every(10 * time.Second)
+}
+
+// clusterPartitionFence runs the partition fence hook for the isolated local cluster member.
+func clusterPartitionFence(ctx context.Context, memberName string, memberAddress string, hookName string, lastHeartbeat time.Time) error {
+	path, err := util.HookPath(hookName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, autoHealFenceTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), "LXD_MEMBER_NAME="+memberName, "LXD_MEMBER_ADDRESS="+memberAddress, "LXD_LAST_HEARTBEAT="+lastHeartbeat.UTC().Format(time.RFC3339))
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed fencing isolated cluster member %q with hook %q: %w (%s)", memberName, hookName, err, strings.TrimSpace(string(out)))
+	}
+
+	logger.Info("Fenced isolated cluster member", logger.Ctx{"member": memberName, "hook": hookName})
+
+	return nil
+}
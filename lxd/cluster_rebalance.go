@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+func autoRebalanceClusterTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	var lastRun time.Time
+	var moves []time.Time
+
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		interval, threshold, maxMoves := s.GlobalConfig.ClusterRebalance()
+		if interval == 0 {
+			return // Skip rebalancing if it's disabled.
+		}
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed to determine cluster leader", logger.Ctx{"err": err})
+			return
+		}
+
+		if !leaderInfo.Clustered || !leaderInfo.Leader {
+			return // Skip rebalancing if not cluster leader.
+		}
+
+		if time.Since(lastRun) < interval {
+			return
+		}
+
+		lastRun = time.Now()
+
+		// Only keep the moves done during the last hour.
+		moves = slices.DeleteFunc(moves, func(move time.Time) bool { return time.Since(move) > time.Hour })
+		remaining := int(maxMoves) - len(moves)
+		if remaining <= 0 {
+			return // Skip rebalancing if the maximum number of moves per hour is reached.
+		}
+
+		plan, members, err := autoRebalanceClusterPlan(ctx, s, float64(threshold), remaining)
+		if err != nil {
+			logger.Error("Failed evaluating cluster members load", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(plan) == 0 {
+			return // Skip rebalancing if the load is even enough.
+		}
+
+		opRun := func(op *operations.Operation) error {
+			done, err := autoRebalanceCluster(ctx, s, op, plan, members)
+			for range done {
+				moves = append(moves, time.Now())
+			}
+
+			if err != nil {
+				logger.Error("Failed rebalancing cluster instances", logger.Ctx{"err": err})
+				return err
+			}
+
+			return nil
+		}
+
+		op, err := operations.OperationCreate(context.Background(), s, "", operations.OperationClassTask, operationtype.ClusterRebalance, nil, nil, opRun, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating cluster instances rebalance operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting cluster instances rebalance operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed rebalancing cluster instances", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+// autoRebalanceClusterPlan evaluates the load of the cluster members considered by the workload rebalancer and
+// returns the instance moves evening it out, along with the cluster members by name.
+func autoRebalanceClusterPlan(ctx context.Context, s *state.State, threshold float64, maxMoves int) ([]cluster.RebalanceMove, map[string]db.NodeInfo, error) {
+	var members []db.NodeInfo
+	var instances []cluster.RebalanceInstance
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		// Skip the members excluded from the workload rebalancer, which are neither sources nor targets.
+		for _, member := range allMembers {
+			if shared.IsFalse(member.Config["scheduler.rebalance"]) || slices.Contains(member.Roles, db.ClusterRoleWitness) {
+				continue
+			}
+
+			if member.State != db.ClusterMemberStateCreated || member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+				continue
+			}
+
+			members = append(members, member)
+		}
+
+		if len(members) < 2 {
+			return nil
+		}
+
+		memberNames := make([]string, 0, len(members))
+		for _, member := range members {
+			memberNames = append(memberNames, member.Name)
+		}
+
+		groupConstraints, err := clusterGroupConstraints(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		// Only running virtual machines supporting live migration are moved.
+		instanceType := instancetype.VM
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			if !slices.Contains(memberNames, dbInst.Node) {
+				return nil
+			}
+
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			if inst.LocalConfig()["volatile.last_state.power"] != instance.PowerStateRunning || shared.IsFalseOrEmpty(inst.ExpandedConfig()["migration.stateful"]) {
+				return nil
+			}
+
+			cpus, _, err := cluster.InstanceAllocation(inst.ExpandedConfig(), inst.Type(), 0)
+			if err != nil {
+				return err
+			}
+
+			candidateMembers, err := tx.GetCandidateMembers(ctx, members, []int{inst.Architecture()}, "", limits.GetRestrictedClusterGroups(&p), s.GlobalConfig.OfflineThreshold())
+			if err != nil {
+				return err
+			}
+
+			candidateMembers, err = instanceGroupConstraintCandidates(groupConstraints, []int{inst.Architecture()}, inst.Type(), candidateMembers)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return nil // Skip instances which the cluster group constraints keep in place.
+				}
+
+				return err
+			}
+
+			candidateMembers, err = instancePlacementCandidates(ctx, tx, p.Name, inst.Name(), inst.ExpandedConfig(), inst.Labels(), candidateMembers)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return nil // Skip instances which the placement rules keep in place.
+				}
+
+				return err
+			}
+
+			rebalanceInst := cluster.RebalanceInstance{
+				Project:  p.Name,
+				Name:     inst.Name(),
+				Location: dbInst.Node,
+				CPUs:     cpus,
+			}
+
+			for _, candidate := range candidateMembers {
+				if candidate.Name != dbInst.Node {
+					rebalanceInst.Targets = append(rebalanceInst.Targets, candidate.Name)
+				}
+			}
+
+			instances = append(instances, rebalanceInst)
+
+			return nil
+		}, dbCluster.InstanceFilter{Type: &instanceType})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(members) < 2 || len(instances) == 0 {
+		return nil, nil, nil
+	}
+
+	// Evaluate the member load from the same state as the one provided to the placement scriptlet.
+	memberStates, err := cluster.ClusterState(s, s.Endpoints.NetworkCert(), members...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting cluster member states: %w", err)
+	}
+
+	rebalanceMembers := make([]cluster.RebalanceMember, 0, len(members))
+	membersByName := make(map[string]db.NodeInfo, len(members))
+	for _, member := range members {
+		memberState, ok := memberStates[member.Name]
+		if !ok || memberState.SysInfo.LogicalCPUs == 0 || len(memberState.SysInfo.LoadAverages) == 0 {
+			continue
+		}
+
+		rebalanceMembers = append(rebalanceMembers, cluster.RebalanceMember{
+			Name:        member.Name,
+			Load:        memberState.SysInfo.LoadAverages[0] * 100 / float64(memberState.SysInfo.LogicalCPUs),
+			LogicalCPUs: memberState.SysInfo.LogicalCPUs,
+		})
+
+		membersByName[member.Name] = member
+	}
+
+	return cluster.RebalancePlan(rebalanceMembers, instances, threshold, maxMoves), membersByName, nil
+}
+
+// autoRebalanceCluster live-migrates the instances according to the planned moves and returns the moves which were
+// done.
+func autoRebalanceCluster(ctx context.Context, s *state.State, op *operations.Operation, moves []cluster.RebalanceMove, members map[string]db.NodeInfo) ([]cluster.RebalanceMove, error) {
+	logger.Info("Rebalancing cluster instances")
+
+	done := []cluster.RebalanceMove{}
+	metadata := make(map[string]any)
+	for _, move := range moves {
+		l := logger.AddContext(logger.Ctx{"project": move.Instance.Project, "instance": move.Instance.Name, "source": move.Source, "target": move.Target})
+
+		metadata["rebalance_progress"] = fmt.Sprintf("Migrating %q in project %q from %q to %q", move.Instance.Name, move.Instance.Project, move.Source, move.Target)
+		_ = op.UpdateMetadata(metadata)
+
+		dest, err := cluster.Connect(ctx, members[move.Target].Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		if err != nil {
+			return done, err
+		}
+
+		dest = dest.UseProject(move.Instance.Project)
+		dest = dest.UseTarget(move.Target)
+
+		migrateOp, err := dest.MigrateInstance(move.Instance.Name, api.InstancePost{Migration: true, Live: true})
+		if err == nil {
+			err = migrateOp.Wait()
+		}
+
+		if err != nil {
+			// Keep going with the other moves, the instance is still running on the source member.
+			l.Warn("Failed moving instance for rebalancing", logger.Ctx{"err": err})
+			continue
+		}
+
+		l.Info("Moved instance for rebalancing")
+		done = append(done, move)
+		s.Events.SendLifecycle(move.Instance.Project, lifecycle.ClusterMemberRebalanced.Event(move.Source, nil, map[string]any{"instance": move.Instance.Name, "project": move.Instance.Project, "target": move.Target}))
+	}
+
+	logger.Info("Done rebalancing cluster instances")
+
+	return done, nil
+}
@@ -20,16 +20,19 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dqliteClient "github.com/canonical/go-dqlite/v3/client"
 	"github.com/canonical/go-dqlite/v3/driver"
 	"github.com/gorilla/mux"
 	liblxc "github.com/lxc/go-lxc"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sys/unix"
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/acme"
+	"github.com/canonical/lxd/lxd/alerts"
 	"github.com/canonical/lxd/lxd/apparmor"
 	"github.com/canonical/lxd/lxd/auth"
 	authDrivers "github.com/canonical/lxd/lxd/auth/drivers"
@@ -54,27 +57,32 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	instanceDrivers "github.com/canonical/lxd/lxd/instance/drivers"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/instance/statehistory"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/loki"
 	"github.com/canonical/lxd/lxd/maas"
 	"github.com/canonical/lxd/lxd/metrics"
 	networkZone "github.com/canonical/lxd/lxd/network/zone"
 	"github.com/canonical/lxd/lxd/node"
+	"github.com/canonical/lxd/lxd/notifications"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/rsync"
 	"github.com/canonical/lxd/lxd/seccomp"
+	"github.com/canonical/lxd/lxd/sink"
 	"github.com/canonical/lxd/lxd/state"
 	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/lxd/storage/s3/miniod"
 	"github.com/canonical/lxd/lxd/sys"
 	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/tracing"
 	"github.com/canonical/lxd/lxd/ubuntupro"
 	"github.com/canonical/lxd/lxd/ucred"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/lxd/webhook"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/cancel"
@@ -107,8 +115,15 @@ type Daemon struct {
 	clusterTasks *task.Group
 
 	// Indexes of tasks that need to be reset when their execution interval changes
-	taskPruneImages      *task.Task
-	taskClusterHeartbeat *task.Task
+	taskPruneImages          *task.Task
+	taskClusterHeartbeat     *task.Task
+	taskInstanceStateHistory *task.Task
+
+	// Resource usage history of the local instances
+	instanceStateHistory *statehistory.Store
+
+	// Lifecycle and operation events of this member waiting to be persisted
+	eventsHistoryQueue chan api.Event
 
 	// Stores startup time of daemon
 	startTime time.Time
@@ -150,6 +165,12 @@ type Daemon struct {
 	// Keep track of skews.
 	timeSkew bool
 
+	// Keep track of cluster partitions.
+	partitionMu      sync.Mutex
+	lastHeartbeat    time.Time // Last time this member was in contact with the rest of the cluster.
+	partitionedSince time.Time // Time at which this member was detected as isolated, zero if it isn't.
+	partitionFenced  bool      // Whether the partition fence hook ran successfully.
+
 	// Configuration.
 	globalConfig   *clusterConfig.Config
 	localConfig    *node.Config
@@ -159,7 +180,15 @@ type Daemon struct {
 	serverName      string
 	serverClustered bool
 
-	lokiClient *loki.Client
+	// Event sinks, by name.
+	sinks   map[string]sink.Sink
+	sinksMu sync.Mutex
+
+	// Built-in alerting rules.
+	alerts *alerts.Manager
+
+	// Notifications of the projects.
+	notifications *notifications.Router
 
 	// HTTP-01 challenge provider for ACME
 	http01Provider acme.HTTP01Provider
@@ -204,6 +233,10 @@ func newDaemon(config *DaemonConfig, os *sys.OS) *Daemon {
 		waitStorageReady: cancel.New(),
 		shutdownCtx:      shutdownCtx,
 		shutdownDoneCh:   make(chan error),
+
+		instanceStateHistory: statehistory.NewStore(),
+		eventsHistoryQueue:   make(chan api.Event, eventsHistoryQueueSize),
+		sinks:                map[string]sink.Sink{},
 	}
 
 	d.serverCert = func() *shared.CertInfo { return d.serverCertInt }
@@ -760,6 +793,15 @@ func (d *Daemon) getCoreAuthSecrets(ctx context.Context) (dbCluster.AuthSecrets,
 	return slices.Clone(d.internalSecrets), nil
 }
 
+// clearCoreAuthSecrets clears the in-memory copy of the cluster-wide secrets, so that they are loaded again from the
+// database on next use.
+func (d *Daemon) clearCoreAuthSecrets() {
+	d.internalSecretsMu.Lock()
+	defer d.internalSecretsMu.Unlock()
+
+	d.internalSecrets = nil
+}
+
 // State creates a new State instance linked to our internal db and os.
 func (d *Daemon) State() *state.State {
 	// If the daemon is shutting down, the context will be cancelled.
@@ -864,6 +906,10 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 
 		w.Header().Set("Content-Type", "application/json")
 
+		// Start the span of the request, continuing the trace of the caller if any.
+		span := tracing.StartRequest(r, r.Method+" "+uri, attribute.String("http.request.method", r.Method), attribute.String("http.route", uri))
+		defer span.End()
+
 		if r.RemoteAddr != "@" || version != "internal" {
 			// Block public API requests until we're done with basic
 			// initialization tasks, such setting up the cluster database.
@@ -920,6 +966,11 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			logCtx["username"] = requestor.Username
 		}
 
+		reqRequestor, err := request.GetRequestor(r.Context())
+		if err == nil {
+			logCtx["trace"] = reqRequestor.TraceID()
+		}
+
 		untrustedOk := (r.Method == "GET" && c.Get.AllowUntrusted) || (r.Method == "POST" && c.Post.AllowUntrusted)
 		if requestor.Trusted {
 			logger.Debug("Handling API request", logCtx)
@@ -1010,6 +1061,14 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				}
 			}
 
+			// Enforce the maximum size of the request body on the main API.
+			if version == "1.0" {
+				resp := limitRequestBody(r, c.MetricsType)
+				if resp != nil {
+					return resp
+				}
+			}
+
 			// All APIEndpointActions should have an access handler or should allow untrusted requests.
 			if action.AccessHandler == nil && !action.AllowUntrusted {
 				return response.InternalError(fmt.Errorf("Access handler not defined for %s %s", r.Method, r.URL.RequestURI()))
@@ -1028,6 +1087,19 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				}
 			}
 
+			// Require an ETag on the updates of entities from clients if configured.
+			if version == "1.0" && requestor.Protocol != request.ProtocolCluster && (r.Method == http.MethodPut || r.Method == http.MethodPatch) && apiRequireEtag.Load() {
+				request.SetContextValue(r, request.CtxEtagRequired, true)
+			}
+
+			// Reject the changes to disabled projects.
+			if version == "1.0" {
+				resp := projectDisabledCheck(d.State(), r, c.Path)
+				if resp != nil {
+					return resp
+				}
+			}
+
 			return action.Handler(d, r)
 		}
 
@@ -1103,6 +1175,21 @@ func setupSharedMounts() error {
 	return nil
 }
 
+// sharedProjects returns a map of entity URL to the names of the projects that the entity is shared with.
+func (d *Daemon) sharedProjects(ctx context.Context) (map[string][]string, error) {
+	var sharedWith map[string][]string
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		sharedWith, err = dbCluster.GetSharedProjectsByEntityURL(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sharedWith, nil
+}
+
 // Init starts daemon process.
 func (d *Daemon) Init() error {
 	d.startTime = time.Now()
@@ -1110,11 +1197,43 @@ func (d *Daemon) Init() error {
 	return d.init()
 }
 
+// setSink replaces the event sink with the given name, stopping the existing one if any. A nil sink removes it.
+// With localOnly, the sink only receives the events of this member, as the other members deliver their own.
+func (d *Daemon) setSink(name string, s sink.Sink, localOnly bool) {
+	d.sinksMu.Lock()
+	defer d.sinksMu.Unlock()
+
+	d.internalListener.RemoveHandler(name)
+
+	existing, ok := d.sinks[name]
+	if ok {
+		existing.Stop()
+		delete(d.sinks, name)
+	}
+
+	if s == nil {
+		return
+	}
+
+	d.sinks[name] = s
+	if !localOnly {
+		d.internalListener.AddHandler(name, s.HandleEvent)
+		return
+	}
+
+	d.internalListener.AddHandler(name, func(event api.Event) {
+		// The events of the other members are forwarded by the members themselves.
+		if event.Location != d.serverName {
+			return
+		}
+
+		s.HandleEvent(event)
+	})
+}
+
 func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, instanceName string, logLevel string, labels []string, types []string) error {
 	// Stop any existing loki client.
-	if d.lokiClient != nil {
-		d.lokiClient.Stop()
-	}
+	d.setSink("loki", nil, false)
 
 	// Check basic requirements for starting a new client.
 	if URL == "" || logLevel == "" || len(types) == 0 {
@@ -1144,17 +1263,139 @@ func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, i
 	}
 
 	// Start a new client.
-	d.lokiClient, err = loki.NewClient(d.shutdownCtx, u, cert, key, caCert, instanceName, location, logLevel, labels, types)
+	client, err := loki.NewClient(d.shutdownCtx, u, cert, key, caCert, instanceName, location, logLevel, labels, types)
 	if err != nil {
 		return err
 	}
 
 	// Attach the new client to the log handler.
-	d.internalListener.AddHandler("loki", d.lokiClient.HandleEvent)
+	d.setSink("loki", client, false)
+
+	return nil
+}
+
+// setupWebhook (re)configures the delivery of the events of this member to webhooks.
+func (d *Daemon) setupWebhook(urls []string, secret string, types []string, projects []string) error {
+	// Stop any existing webhook client.
+	d.setSink("webhook", nil, false)
+
+	// Check basic requirements for starting a new client.
+	if len(urls) == 0 || len(types) == 0 {
+		return nil
+	}
+
+	client, err := webhook.NewClient(urls, secret, types, projects)
+	if err != nil {
+		return err
+	}
+
+	d.setSink("webhook", client, true)
+
+	return nil
+}
+
+// setupFileSink (re)configures the sink writing the events of this member to a local file.
+func (d *Daemon) setupFileSink(path string, maxSize int64, maxFiles int64, filter sink.Filter) error {
+	d.setSink("file", nil, false)
+
+	if path == "" || len(filter.Types) == 0 {
+		return nil
+	}
+
+	s, err := sink.NewFile(path, maxSize*1024*1024, int(maxFiles), filter)
+	if err != nil {
+		return err
+	}
+
+	d.setSink("file", s, true)
+
+	return nil
+}
+
+// setupKafkaSink (re)configures the sink producing the events of this member to a Kafka topic.
+func (d *Daemon) setupKafkaSink(proxyURL string, topic string, filter sink.Filter) error {
+	d.setSink("kafka", nil, false)
+
+	if proxyURL == "" || len(filter.Types) == 0 {
+		return nil
+	}
+
+	s, err := sink.NewKafka(proxyURL, topic, filter)
+	if err != nil {
+		return err
+	}
+
+	d.setSink("kafka", s, true)
+
+	return nil
+}
+
+// setupMQTTSink (re)configures the sink publishing the events of this member to an MQTT broker.
+func (d *Daemon) setupMQTTSink(brokerURL string, username string, password string, topic string, filter sink.Filter) error {
+	d.setSink("mqtt", nil, false)
+
+	if brokerURL == "" || len(filter.Types) == 0 {
+		return nil
+	}
+
+	s, err := sink.NewMQTT(brokerURL, username, password, topic, "lxd-"+d.serverName, filter)
+	if err != nil {
+		return err
+	}
+
+	d.setSink("mqtt", s, true)
 
 	return nil
 }
 
+// setupSyslogSink (re)configures the sink sending the events of this member to a remote syslog server.
+func (d *Daemon) setupSyslogSink(protocol string, address string, filter sink.Filter) error {
+	d.setSink("syslog", nil, false)
+
+	if address == "" || len(filter.Types) == 0 {
+		return nil
+	}
+
+	s, err := sink.NewSyslog(protocol, address, filter)
+	if err != nil {
+		return err
+	}
+
+	d.setSink("syslog", s, true)
+
+	return nil
+}
+
+// setupSlowThresholds sets the durations above which API requests and database transactions are logged as slow.
+func (d *Daemon) setupSlowThresholds(request time.Duration, transaction time.Duration) {
+	metrics.SetSlowRequestThreshold(request)
+	db.SetSlowTransactionThreshold(transaction)
+}
+
+// apiRequireEtag is whether the updates of entities from clients require an `If-Match` header.
+var apiRequireEtag atomic.Bool
+
+// setupRequireEtag sets whether the updates of entities from clients require an `If-Match` header.
+func (d *Daemon) setupRequireEtag(required bool) {
+	apiRequireEtag.Store(required)
+}
+
+// setupTracing (re)configures the export of spans to an OpenTelemetry collector.
+func (d *Daemon) setupTracing(endpoint string, insecure bool, sampling int64) error {
+	// Handle standalone systems.
+	instanceName := d.serverName
+	if !d.serverClustered {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+
+		instanceName = hostname
+	}
+
+	return tracing.Setup(endpoint, insecure, sampling, instanceName)
+}
+
 func (d *Daemon) init() error {
 	d.startStopLock.Lock()
 	defer d.startStopLock.Unlock()
@@ -1627,7 +1868,7 @@ func (d *Daemon) init() error {
 
 	// Load the embedded OpenFGA authorizer. This cannot be loaded until after the cluster database is initialised,
 	// so the TLS authorizer must be loaded first to set up clustering.
-	d.authorizer, err = authDrivers.LoadAuthorizer(d.shutdownCtx, authDrivers.DriverEmbeddedOpenFGA, logger.Log, d.identityCache, authDrivers.WithOpenFGADatastore(openfga.NewOpenFGAStore(d.db.Cluster)))
+	d.authorizer, err = authDrivers.LoadAuthorizer(d.shutdownCtx, authDrivers.DriverEmbeddedOpenFGA, logger.Log, d.identityCache, authDrivers.WithOpenFGADatastore(openfga.NewOpenFGAStore(d.db.Cluster)), authDrivers.WithSharedProjects(d.sharedProjects))
 	if err != nil {
 		return err
 	}
@@ -1766,6 +2007,17 @@ func (d *Daemon) init() error {
 	maasAPIURL, maasAPIKey = d.globalConfig.MAASController()
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := d.globalConfig.LokiServer()
+	tracingEndpoint, tracingInsecure, tracingSampling := d.globalConfig.Tracing()
+	webhookURLs, webhookSecret, webhookTypes, webhookProjects := d.globalConfig.Webhook()
+	smtpAddress, smtpUsername, smtpPassword, smtpSender := d.globalConfig.SMTP()
+	fileSinkPath, fileSinkMaxSize, fileSinkMaxFiles, fileSinkFilter := d.globalConfig.FileSink()
+	kafkaSinkURL, kafkaSinkTopic, kafkaSinkFilter := d.globalConfig.KafkaSink()
+	mqttSinkURL, mqttSinkUsername, mqttSinkPassword, mqttSinkTopic, mqttSinkFilter := d.globalConfig.MQTTSink()
+	syslogSinkProtocol, syslogSinkAddress, syslogSinkFilter := d.globalConfig.SyslogSink()
+	eventsHistoryRetention := d.globalConfig.EventsHistoryRetention()
+	slowRequestThreshold, slowTransactionThreshold := d.globalConfig.SlowThresholds()
+	maxRequestSize, maxUploadSize, requestSizeOverrides := d.globalConfig.RequestSizeLimits()
+	requireEtag := d.globalConfig.RequireEtag()
 	oidcIssuer, oidcClientID, oidcClientSecret, oidcScopes, oidcAudience, oidcGroupsClaim := d.globalConfig.OIDCServer()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 
@@ -1780,6 +2032,76 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup webhooks.
+	if len(webhookURLs) > 0 {
+		err = d.setupWebhook(webhookURLs, webhookSecret, webhookTypes, webhookProjects)
+		if err != nil {
+			logger.Warn("Failed to setup webhooks", logger.Ctx{"err": err})
+		}
+	}
+
+	// Setup event sinks.
+	if fileSinkPath != "" {
+		err = d.setupFileSink(fileSinkPath, fileSinkMaxSize, fileSinkMaxFiles, fileSinkFilter)
+		if err != nil {
+			logger.Warn("Failed to setup file event sink", logger.Ctx{"err": err})
+		}
+	}
+
+	if kafkaSinkURL != "" {
+		err = d.setupKafkaSink(kafkaSinkURL, kafkaSinkTopic, kafkaSinkFilter)
+		if err != nil {
+			logger.Warn("Failed to setup Kafka event sink", logger.Ctx{"err": err})
+		}
+	}
+
+	if mqttSinkURL != "" {
+		err = d.setupMQTTSink(mqttSinkURL, mqttSinkUsername, mqttSinkPassword, mqttSinkTopic, mqttSinkFilter)
+		if err != nil {
+			logger.Warn("Failed to setup MQTT event sink", logger.Ctx{"err": err})
+		}
+	}
+
+	if syslogSinkAddress != "" {
+		err = d.setupSyslogSink(syslogSinkProtocol, syslogSinkAddress, syslogSinkFilter)
+		if err != nil {
+			logger.Warn("Failed to setup syslog event sink", logger.Ctx{"err": err})
+		}
+	}
+
+	// Setup the events history.
+	go d.eventsHistoryWriter()
+	d.setupEventsHistory(eventsHistoryRetention)
+
+	// Setup the alerting rules.
+	d.setupAlerts()
+
+	// Setup the notifications of the projects.
+	d.setupNotifications(notifications.SMTP{Address: smtpAddress, Username: smtpUsername, Password: smtpPassword, Sender: smtpSender})
+
+	// Setup the recording of the instances availability.
+	d.setupInstanceAvailability()
+
+	// Setup the recording of the identities creating and modifying entities.
+	d.setupEntityOwnership()
+
+	// Setup the logging of slow requests and transactions.
+	d.setupSlowThresholds(slowRequestThreshold, slowTransactionThreshold)
+
+	// Setup the maximum sizes of the API requests.
+	d.setupRequestSizeLimits(maxRequestSize, maxUploadSize, requestSizeOverrides)
+
+	// Setup whether updates require an ETag.
+	d.setupRequireEtag(requireEtag)
+
+	// Setup OpenTelemetry trace export.
+	if tracingEndpoint != "" {
+		err = d.setupTracing(tracingEndpoint, tracingInsecure, tracingSampling)
+		if err != nil {
+			logger.Warn("Failed to setup tracing", logger.Ctx{"err": err})
+		}
+	}
+
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
 		if err != nil {
@@ -1879,6 +2201,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	quicAddress := d.localConfig.HTTPSQUICAddress()
+	if quicAddress != "" {
+		err = d.endpoints.UpQUIC(quicAddress)
+		if err != nil {
+			return err
+		}
+	}
+
 	storageBucketsAddress := d.localConfig.StorageBucketsAddress()
 	if storageBucketsAddress != "" {
 		err = d.endpoints.UpStorageBuckets(storageBucketsAddress)
@@ -2047,6 +2377,9 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d.State))
 
+		// Replicate instances to the standby cluster (minutely check of configurable cron expression)
+		d.tasks.Add(replicationTask(d.State))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d.State))
 
@@ -2055,6 +2388,30 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d.State))
+
+		// Remove expired group memberships and permissions (hourly)
+		d.tasks.Add(pruneExpiredAuthGrantsTask(d))
+
+		// Sample instance resource usage (configurable)
+		d.taskInstanceStateHistory = d.tasks.Add(instanceStateHistoryTask(d))
+
+		// Prune the events history (hourly)
+		d.tasks.Add(pruneEventsHistoryTask(d))
+
+		// Record the usage of the projects (every 5 minutes)
+		d.tasks.Add(projectsUsageTask(d))
+
+		// Record the status of the instances and prune their availability history (hourly)
+		d.tasks.Add(instanceAvailabilityTask(d))
+
+		// Resize the memory balloon of VMs using the automatic policy (every 15s)
+		d.tasks.Add(instanceMemoryBalloonTask(d.State))
+
+		// Evaluate the alerting rules (minutely)
+		d.tasks.Add(alertsTask(d))
+
+		// Send the notifications of the new project warnings (minutely)
+		d.tasks.Add(notificationsWarningsTask(d))
 	}
 
 	// Start all background tasks
@@ -2096,6 +2453,12 @@ func (d *Daemon) startClusterTasks() {
 	// Perform automatic evacuation for offline cluster members
 	d.clusterTasks.Add(autoHealClusterTask(d.State))
 
+	// Move instances away from overloaded cluster members
+	d.clusterTasks.Add(autoRebalanceClusterTask(d.State))
+
+	// Fence this member if it gets isolated from the rest of the cluster
+	d.clusterTasks.Add(clusterPartitionTask(d))
+
 	// Start all background tasks
 	d.clusterTasks.Start(d.shutdownCtx)
 }
@@ -2292,6 +2655,9 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 		trackError(d.endpoints.Down(), "Shutdown endpoints")
 	}
 
+	// Flush the pending spans.
+	tracing.Stop()
+
 	if shouldUnmount {
 		logger.Info("Unmounting temporary filesystems")
 
@@ -2506,6 +2872,9 @@ func (d *Daemon) heartbeatHandler(w http.ResponseWriter, r *http.Request, isLead
 	localClusterAddress := s.LocalConfig.ClusterAddress()
 
 	if hbData.FullStateList {
+		// A full state heartbeat means that the leader, which holds quorum, can reach this member.
+		d.clusterContactRefresh()
+
 		// If there is an ongoing heartbeat round (and by implication this is the leader), then this could
 		// be a problem because it could be broadcasting the stale member state information which in turn
 		// could lead to incorrect decisions being made. So calling heartbeatRestart will request any
@@ -2537,6 +2906,11 @@ func (d *Daemon) heartbeatHandler(w http.ResponseWriter, r *http.Request, isLead
 func (d *Daemon) nodeRefreshTask(heartbeatData *cluster.APIHeartbeat, isLeader bool, unavailableMembers []string) {
 	s := d.State()
 
+	// Being called at the end of a leader heartbeat round means that the cluster database is available.
+	if isLeader && unavailableMembers != nil {
+		d.clusterContactRefresh()
+	}
+
 	// Don't process the heartbeat until we're fully online.
 	if d.db.Cluster == nil || d.db.Cluster.GetNodeID() == 0 {
 		return
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/canonical/lxd/client"
@@ -29,7 +30,6 @@ import (
 	"github.com/canonical/lxd/shared/cancel"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
-	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -52,6 +52,44 @@ type ImageDownloadArgs struct {
 	UserRequested     bool
 }
 
+// imageServerAllowed checks that the images of the project can be downloaded from the server, as set by
+// images.allowed_servers in the project or in the server configuration.
+func imageServerAllowed(ctx context.Context, s *state.State, projectName string, server string) error {
+	var projectConfig map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		projectConfig, err = cluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading config of project %q: %w", projectName, err)
+	}
+
+	allowedServers := s.GlobalConfig.ImagesAllowedServers()
+	if projectConfig["images.allowed_servers"] != "" {
+		allowedServers = shared.SplitNTrimSpace(projectConfig["images.allowed_servers"], ",", -1, true)
+	}
+
+	if len(allowedServers) == 0 || imageServerMatches(server, allowedServers) {
+		return nil
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "Image server %q isn't allowed in project %q", strings.TrimSuffix(server, "/"), projectName)
+}
+
+// imageServerMatches returns whether the server URL is one of the allowed server URLs or is below one of them.
+func imageServerMatches(server string, allowedServers []string) bool {
+	server = strings.TrimSuffix(server, "/")
+	for _, allowed := range allowedServers {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if server == allowed || strings.HasPrefix(server, allowed+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
 // imageOperationLock acquires a lock for operating on an image and returns the unlock function.
 func imageOperationLock(fingerprint string) (locking.UnlockFunc, error) {
 	l := logger.AddContext(logger.Ctx{"fingerprint": fingerprint})
@@ -75,6 +113,11 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		protocol = "lxd"
 	}
 
+	err = imageServerAllowed(ctx, s, args.ProjectName, args.Server)
+	if err != nil {
+		return nil, err
+	}
+
 	// Copy so that local modifications aren't propagated to args.
 	alias := args.Alias
 
@@ -364,6 +407,7 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 
 		if meta["download_progress"] != progress.Text {
 			meta["download_progress"] = progress.Text
+			meta[api.OperationProgressMetadataKey] = op.RecordProgress("download", progress)
 			_ = op.UpdateMetadata(meta)
 		}
 	}
@@ -515,10 +559,8 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		body := &ioprogress.ProgressReader{
 			ReadCloser: raw.Body,
 			Tracker: &ioprogress.ProgressTracker{
-				Length: raw.ContentLength,
-				Handler: func(percent int64, speed int64) {
-					progress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
-				},
+				Length:      raw.ContentLength,
+				DataHandler: progress,
 			},
 		}
 
@@ -580,6 +622,12 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		info.AutoUpdate = args.AutoUpdate
 	}
 
+	// Last chance to wipe the downloaded files if the operation was cancelled.
+	err = op.Checkpoint()
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry
 		return tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
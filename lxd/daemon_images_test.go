@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_imageServerMatches(t *testing.T) {
+	allowed := []string{"https://cloud-images.ubuntu.com/releases/", "https://images.example.com"}
+
+	assert.True(t, imageServerMatches("https://cloud-images.ubuntu.com/releases", allowed))
+	assert.True(t, imageServerMatches("https://cloud-images.ubuntu.com/releases/", allowed))
+	assert.True(t, imageServerMatches("https://images.example.com/streams/v1/index.json", allowed))
+
+	// Only the URLs below an entry are allowed, not the ones sharing a prefix.
+	assert.False(t, imageServerMatches("https://cloud-images.ubuntu.com/releases-old", allowed))
+	assert.False(t, imageServerMatches("https://cloud-images.ubuntu.com", allowed))
+	assert.False(t, imageServerMatches("https://images.example.com.evil.org", allowed))
+	assert.False(t, imageServerMatches("http://images.example.com", allowed))
+	assert.False(t, imageServerMatches("https://images.example.com", nil))
+}
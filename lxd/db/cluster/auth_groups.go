@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db/query"
@@ -78,15 +79,23 @@ func (g *AuthGroup) ToAPI(ctx context.Context, tx *sql.Tx, canViewIdentity auth.
 			return nil, fmt.Errorf("Entity URL missing for permission with entity type %q and entity ID `%d`", p.EntityType, p.EntityID)
 		}
 
-		apiPermissions = append(apiPermissions, api.Permission{
-			EntityType:      string(p.EntityType),
-			EntityReference: u.String(),
-			Entitlement:     string(p.Entitlement),
-		})
+		apiPermissions = append(apiPermissions, p.ToAPI(u))
 	}
 
 	group.Permissions = apiPermissions
 
+	groupRoles, err := GetAuthGroupRoles(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groupRoles) > 0 {
+		group.Roles, err = AuthGroupRolesToAPI(ctx, tx, groupRoles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	identities, err := GetIdentitiesByAuthGroupID(ctx, tx, g.ID)
 	if err != nil {
 		return nil, err
@@ -114,13 +123,15 @@ func (g *AuthGroup) ToAPI(ctx context.Context, tx *sql.Tx, canViewIdentity auth.
 	return group, nil
 }
 
-// GetIdentitiesByAuthGroupID returns the identities that are members of the group with the given ID.
+// GetIdentitiesByAuthGroupID returns the identities that are members of the group with the given ID, ignoring expired
+// memberships.
 func GetIdentitiesByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int) ([]Identity, error) {
 	stmt := `
 SELECT identities.id, identities.auth_method, identities.type, identities.identifier, identities.name, identities.metadata
 FROM identities
 JOIN identities_auth_groups ON identities.id = identities_auth_groups.identity_id
-WHERE identities_auth_groups.auth_group_id = ?`
+WHERE identities_auth_groups.auth_group_id = ?
+AND (identities_auth_groups.expiry_date IS NULL OR identities_auth_groups.expiry_date > ?)`
 
 	var result []Identity
 	dest := func(scan func(dest ...any) error) error {
@@ -135,7 +146,7 @@ WHERE identities_auth_groups.auth_group_id = ?`
 		return nil
 	}
 
-	err := query.Scan(ctx, tx, stmt, dest, groupID)
+	err := query.Scan(ctx, tx, stmt, dest, groupID, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get identities for the group with ID `%d`: %w", groupID, err)
 	}
@@ -148,7 +159,8 @@ func GetAllIdentitiesByAuthGroupIDs(ctx context.Context, tx *sql.Tx) (map[int][]
 	stmt := `
 SELECT identities_auth_groups.auth_group_id, identities.id, identities.auth_method, identities.type, identities.identifier, identities.name, identities.metadata
 FROM identities
-JOIN identities_auth_groups ON identities.id = identities_auth_groups.identity_id`
+JOIN identities_auth_groups ON identities.id = identities_auth_groups.identity_id
+WHERE identities_auth_groups.expiry_date IS NULL OR identities_auth_groups.expiry_date > ?`
 
 	result := make(map[int][]Identity)
 	dest := func(scan func(dest ...any) error) error {
@@ -164,7 +176,7 @@ JOIN identities_auth_groups ON identities.id = identities_auth_groups.identity_i
 		return nil
 	}
 
-	err := query.Scan(ctx, tx, stmt, dest)
+	err := query.Scan(ctx, tx, stmt, dest, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get identities for all groups: %w", err)
 	}
@@ -230,12 +242,16 @@ JOIN auth_groups_identity_provider_groups ON identity_provider_groups.id = auth_
 	return result, nil
 }
 
-// GetPermissionsByAuthGroupID returns the permissions that belong to the group with the given ID.
+// GetPermissionsByAuthGroupID returns the unexpired permissions that belong to the group with the given ID.
 func GetPermissionsByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int) ([]Permission, error) {
+	stmt := `
+SELECT id, auth_group_id, entitlement, entity_type, entity_id, expiry_date FROM auth_groups_permissions
+WHERE auth_group_id = ? AND (expiry_date IS NULL OR expiry_date > ?)`
+
 	var result []Permission
 	dest := func(scan func(dest ...any) error) error {
 		p := Permission{}
-		err := scan(&p.ID, &p.GroupID, &p.Entitlement, &p.EntityType, &p.EntityID)
+		err := scan(&p.ID, &p.GroupID, &p.Entitlement, &p.EntityType, &p.EntityID, &p.ExpiryDate)
 		if err != nil {
 			return err
 		}
@@ -244,7 +260,7 @@ func GetPermissionsByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int) (
 		return nil
 	}
 
-	err := query.Scan(ctx, tx, `SELECT id, auth_group_id, entitlement, entity_type, entity_id FROM auth_groups_permissions WHERE auth_group_id = ?`, dest, groupID)
+	err := query.Scan(ctx, tx, stmt, dest, groupID, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get permissions for the group with ID `%d`: %w", groupID, err)
 	}
@@ -252,14 +268,16 @@ func GetPermissionsByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int) (
 	return result, nil
 }
 
-// GetPermissions returns a map of group ID to the permissions that belong to the auth group with that ID.
+// GetPermissions returns the unexpired permissions of all auth groups.
 func GetPermissions(ctx context.Context, tx *sql.Tx) ([]Permission, error) {
-	stmt := `SELECT id, auth_group_id, entitlement, entity_type, entity_id FROM auth_groups_permissions`
+	stmt := `
+SELECT id, auth_group_id, entitlement, entity_type, entity_id, expiry_date FROM auth_groups_permissions
+WHERE expiry_date IS NULL OR expiry_date > ?`
 
 	var result []Permission
 	dest := func(scan func(dest ...any) error) error {
 		p := Permission{}
-		err := scan(&p.ID, &p.GroupID, &p.Entitlement, &p.EntityType, &p.EntityID)
+		err := scan(&p.ID, &p.GroupID, &p.Entitlement, &p.EntityType, &p.EntityID, &p.ExpiryDate)
 		if err != nil {
 			return err
 		}
@@ -268,7 +286,7 @@ func GetPermissions(ctx context.Context, tx *sql.Tx) ([]Permission, error) {
 		return nil
 	}
 
-	err := query.Scan(ctx, tx, stmt, dest)
+	err := query.Scan(ctx, tx, stmt, dest, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get permissions for all groups: %w", err)
 	}
@@ -289,7 +307,7 @@ func SetAuthGroupPermissions(ctx context.Context, tx *sql.Tx, groupID int, authG
 	}
 
 	for _, permission := range authGroupPermissions {
-		_, err := tx.ExecContext(ctx, `INSERT INTO auth_groups_permissions (auth_group_id, entity_type, entity_id, entitlement) VALUES (?, ?, ?, ?);`, groupID, permission.EntityType, permission.EntityID, permission.Entitlement)
+		_, err := tx.ExecContext(ctx, `INSERT INTO auth_groups_permissions (auth_group_id, entity_type, entity_id, entitlement, expiry_date) VALUES (?, ?, ?, ?, ?);`, groupID, permission.EntityType, permission.EntityID, permission.Entitlement, permission.ExpiryDate)
 		if err != nil {
 			return fmt.Errorf("Failed to write group permissions: %w", err)
 		}
@@ -297,3 +315,31 @@ func SetAuthGroupPermissions(ctx context.Context, tx *sql.Tx, groupID int, authG
 
 	return nil
 }
+
+// DeleteExpiredAuthGroupGrants deletes the group memberships and permissions whose expiry date has passed.
+// It returns the number of deleted memberships and permissions.
+func DeleteExpiredAuthGroupGrants(ctx context.Context, tx *sql.Tx) (int64, int64, error) {
+	now := time.Now()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM identities_auth_groups WHERE expiry_date <= ?`, now)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed to delete expired group memberships: %w", err)
+	}
+
+	memberships, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	res, err = tx.ExecContext(ctx, `DELETE FROM auth_groups_permissions WHERE expiry_date <= ?`, now)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed to delete expired group permissions: %w", err)
+	}
+
+	permissions, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return memberships, permissions, nil
+}
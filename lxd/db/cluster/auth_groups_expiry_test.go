@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthGroupGrantsExpiry(t *testing.T) {
+	db := newDB(t)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	_, err := db.Exec(`
+INSERT INTO identities (id, auth_method, type, identifier, name, metadata) VALUES (1, 1, 1, 'id1', 'id1', '{}');
+INSERT INTO auth_groups (id, name, description) VALUES (1, 'permanent', ''), (2, 'temporary', ''), (3, 'expired', '');
+INSERT INTO identities_auth_groups (identity_id, auth_group_id, expiry_date) VALUES (1, 1, NULL), (1, 2, ?), (1, 3, ?);
+INSERT INTO auth_groups_permissions (auth_group_id, entity_type, entity_id, entitlement, expiry_date) VALUES (1, 1, 1, 'can_view', NULL), (1, 1, 1, 'can_edit', ?), (1, 1, 1, 'admin', ?);
+`, future, past, future, past)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+
+	// Expired memberships and permissions are ignored.
+	groups, err := GetAuthGroupsByIdentityID(ctx, tx, 1)
+	require.NoError(t, err)
+	groupNames := []string{}
+	for _, group := range groups {
+		groupNames = append(groupNames, group.Name)
+	}
+
+	assert.ElementsMatch(t, []string{"permanent", "temporary"}, groupNames)
+
+	identities, err := GetIdentitiesByAuthGroupID(ctx, tx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+
+	permissions, err := GetPermissionsByAuthGroupID(ctx, tx, 1)
+	require.NoError(t, err)
+	require.Len(t, permissions, 2)
+	for _, permission := range permissions {
+		if permission.Entitlement == "can_edit" {
+			assert.True(t, permission.ExpiryDate.Valid)
+			assert.WithinDuration(t, future, permission.ExpiryDate.Time, time.Second)
+		} else {
+			assert.EqualValues(t, "can_view", permission.Entitlement)
+			assert.False(t, permission.ExpiryDate.Valid)
+		}
+	}
+
+	distinct, err := GetDistinctPermissionsByGroupNames(ctx, tx, []string{"permanent"})
+	require.NoError(t, err)
+	assert.Len(t, distinct, 2)
+
+	// Only the unexpired memberships with an expiry date are listed.
+	expiries, err := GetAuthGroupExpiriesByIdentityID(ctx, tx, 1)
+	require.NoError(t, err)
+	require.Len(t, expiries, 1)
+	assert.WithinDuration(t, future, expiries["temporary"], time.Second)
+
+	// Expiry dates can only be set on existing memberships.
+	err = SetIdentityAuthGroupExpiries(ctx, tx, 1, map[string]time.Time{"permanent": future.Add(time.Hour)})
+	require.NoError(t, err)
+	err = SetIdentityAuthGroupExpiries(ctx, tx, 1, map[string]time.Time{"missing": future})
+	assert.Error(t, err)
+
+	allExpiries, err := GetAllAuthGroupExpiriesByIdentityIDs(ctx, tx)
+	require.NoError(t, err)
+	assert.Len(t, allExpiries[1], 2)
+
+	// Pruning deletes the expired grants only.
+	memberships, deletedPermissions, err := DeleteExpiredAuthGroupGrants(ctx, tx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), memberships)
+	assert.Equal(t, int64(1), deletedPermissions)
+
+	var count int
+	err = tx.QueryRow(`SELECT count(*) FROM identities_auth_groups`).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	err = tx.QueryRow(`SELECT count(*) FROM auth_groups_permissions`).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
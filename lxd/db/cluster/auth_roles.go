@@ -0,0 +1,318 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t auth_roles.mapper.go
+//go:generate mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//go:generate mapper stmt -e auth_role objects table=auth_roles
+//go:generate mapper stmt -e auth_role objects-by-ID table=auth_roles
+//go:generate mapper stmt -e auth_role objects-by-Name table=auth_roles
+//go:generate mapper stmt -e auth_role id table=auth_roles
+//go:generate mapper stmt -e auth_role create table=auth_roles
+//go:generate mapper stmt -e auth_role delete-by-Name table=auth_roles
+//go:generate mapper stmt -e auth_role update table=auth_roles
+//go:generate mapper stmt -e auth_role rename table=auth_roles
+//
+//go:generate mapper method -i -e auth_role GetMany
+//go:generate mapper method -i -e auth_role GetOne
+//go:generate mapper method -i -e auth_role ID
+//go:generate mapper method -i -e auth_role Exists
+//go:generate mapper method -i -e auth_role Create
+//go:generate mapper method -i -e auth_role DeleteOne-by-Name
+//go:generate mapper method -i -e auth_role Update
+//go:generate mapper method -i -e auth_role Rename
+//go:generate goimports -w auth_roles.mapper.go
+//go:generate goimports -w auth_roles.interface.mapper.go
+
+// AuthRole is the database representation of an api.AuthRole.
+type AuthRole struct {
+	ID          int
+	Name        string `db:"primary=true"`
+	Description string
+}
+
+// AuthRoleFilter contains fields upon which an AuthRole can be filtered.
+type AuthRoleFilter struct {
+	ID   *int
+	Name *string
+}
+
+// AuthRoleEntitlement is the database representation of an api.AuthRoleEntitlement.
+type AuthRoleEntitlement struct {
+	RoleID      int
+	EntityType  EntityType
+	Entitlement auth.Entitlement
+}
+
+// ToAPI converts the AuthRoleEntitlement to an api.AuthRoleEntitlement.
+func (e AuthRoleEntitlement) ToAPI() api.AuthRoleEntitlement {
+	return api.AuthRoleEntitlement{
+		EntityType:  string(e.EntityType),
+		Entitlement: string(e.Entitlement),
+	}
+}
+
+// AuthGroupRole is the database representation of an api.RoleGrant.
+type AuthGroupRole struct {
+	GroupID    int
+	RoleID     int
+	RoleName   string
+	EntityType EntityType
+	EntityID   int
+}
+
+// ToAPI converts the AuthRole to an api.AuthRole, making extra database queries as necessary.
+func (r *AuthRole) ToAPI(ctx context.Context, tx *sql.Tx) (*api.AuthRole, error) {
+	role := &api.AuthRole{
+		Name:        r.Name,
+		Description: r.Description,
+	}
+
+	entitlements, err := GetAuthRoleEntitlements(ctx, tx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Entitlements = make([]api.AuthRoleEntitlement, 0, len(entitlements))
+	for _, e := range entitlements {
+		role.Entitlements = append(role.Entitlements, e.ToAPI())
+	}
+
+	groupNames, err := GetAuthGroupNamesByAuthRoleID(ctx, tx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	role.UsedBy = make([]string, 0, len(groupNames))
+	for _, groupName := range groupNames {
+		role.UsedBy = append(role.UsedBy, entity.AuthGroupURL(groupName).String())
+	}
+
+	return role, nil
+}
+
+// GetAuthRoleEntitlements returns the entitlements of the role with the given ID.
+func GetAuthRoleEntitlements(ctx context.Context, tx *sql.Tx, roleID int) ([]AuthRoleEntitlement, error) {
+	entitlements, err := getAuthRoleEntitlements(ctx, tx, &roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entitlements[roleID], nil
+}
+
+// GetAllAuthRoleEntitlements returns a map of role IDs to the entitlements of the role with that ID.
+func GetAllAuthRoleEntitlements(ctx context.Context, tx *sql.Tx) (map[int][]AuthRoleEntitlement, error) {
+	return getAuthRoleEntitlements(ctx, tx, nil)
+}
+
+func getAuthRoleEntitlements(ctx context.Context, tx *sql.Tx, roleID *int) (map[int][]AuthRoleEntitlement, error) {
+	stmt := `SELECT auth_role_id, entity_type, entitlement FROM auth_roles_entitlements`
+
+	var args []any
+	if roleID != nil {
+		stmt += ` WHERE auth_role_id = ?`
+		args = append(args, *roleID)
+	}
+
+	stmt += ` ORDER BY auth_role_id, entity_type, entitlement`
+
+	result := make(map[int][]AuthRoleEntitlement)
+	dest := func(scan func(dest ...any) error) error {
+		e := AuthRoleEntitlement{}
+		err := scan(&e.RoleID, &e.EntityType, &e.Entitlement)
+		if err != nil {
+			return err
+		}
+
+		result[e.RoleID] = append(result[e.RoleID], e)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get role entitlements: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetAuthRoleEntitlements replaces the entitlements of the role with the given ID.
+func SetAuthRoleEntitlements(ctx context.Context, tx *sql.Tx, roleID int, entitlements []AuthRoleEntitlement) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM auth_roles_entitlements WHERE auth_role_id = ?`, roleID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete existing entitlements for role with ID `%d`: %w", roleID, err)
+	}
+
+	for _, e := range entitlements {
+		_, err := tx.ExecContext(ctx, `INSERT INTO auth_roles_entitlements (auth_role_id, entity_type, entitlement) VALUES (?, ?, ?)`, roleID, e.EntityType, e.Entitlement)
+		if err != nil {
+			return fmt.Errorf("Failed to write role entitlements: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetAuthGroupNamesByAuthRoleID returns the names of the groups that the role with the given ID is granted to.
+func GetAuthGroupNamesByAuthRoleID(ctx context.Context, tx *sql.Tx, roleID int) ([]string, error) {
+	stmt := `
+SELECT DISTINCT auth_groups.name
+FROM auth_groups
+JOIN auth_groups_roles ON auth_groups.id = auth_groups_roles.auth_group_id
+WHERE auth_groups_roles.auth_role_id = ?
+ORDER BY auth_groups.name`
+
+	groupNames, err := query.SelectStrings(ctx, tx, stmt, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get groups for the role with ID `%d`: %w", roleID, err)
+	}
+
+	return groupNames, nil
+}
+
+// GetAuthRoleGrantedEntityTypes returns the entity types of the entities on which the role with the given ID is
+// granted to groups.
+func GetAuthRoleGrantedEntityTypes(ctx context.Context, tx *sql.Tx, roleID int) ([]EntityType, error) {
+	stmt := `SELECT DISTINCT entity_type FROM auth_groups_roles WHERE auth_role_id = ? ORDER BY entity_type`
+
+	var entityTypes []EntityType
+	dest := func(scan func(dest ...any) error) error {
+		var entityType EntityType
+		err := scan(&entityType)
+		if err != nil {
+			return err
+		}
+
+		entityTypes = append(entityTypes, entityType)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, stmt, dest, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get granted entity types for the role with ID `%d`: %w", roleID, err)
+	}
+
+	return entityTypes, nil
+}
+
+// GetAuthGroupRoles returns the roles granted to the group with the given ID.
+func GetAuthGroupRoles(ctx context.Context, tx *sql.Tx, groupID int) ([]AuthGroupRole, error) {
+	groupRoles, err := getAuthGroupRoles(ctx, tx, &groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupRoles[groupID], nil
+}
+
+// GetAllAuthGroupRoles returns a map of group IDs to the roles granted to the group with that ID.
+func GetAllAuthGroupRoles(ctx context.Context, tx *sql.Tx) (map[int][]AuthGroupRole, error) {
+	return getAuthGroupRoles(ctx, tx, nil)
+}
+
+func getAuthGroupRoles(ctx context.Context, tx *sql.Tx, groupID *int) (map[int][]AuthGroupRole, error) {
+	stmt := `
+SELECT auth_groups_roles.auth_group_id, auth_roles.id, auth_roles.name, auth_groups_roles.entity_type, auth_groups_roles.entity_id
+FROM auth_groups_roles
+JOIN auth_roles ON auth_roles.id = auth_groups_roles.auth_role_id`
+
+	var args []any
+	if groupID != nil {
+		stmt += `
+WHERE auth_groups_roles.auth_group_id = ?`
+		args = append(args, *groupID)
+	}
+
+	stmt += `
+ORDER BY auth_roles.name, auth_groups_roles.entity_type, auth_groups_roles.entity_id`
+
+	result := make(map[int][]AuthGroupRole)
+	dest := func(scan func(dest ...any) error) error {
+		r := AuthGroupRole{}
+		err := scan(&r.GroupID, &r.RoleID, &r.RoleName, &r.EntityType, &r.EntityID)
+		if err != nil {
+			return err
+		}
+
+		result[r.GroupID] = append(result[r.GroupID], r)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get group roles: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetAuthGroupRoles replaces the roles granted to the group with the given ID.
+func SetAuthGroupRoles(ctx context.Context, tx *sql.Tx, groupID int, groupRoles []AuthGroupRole) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM auth_groups_roles WHERE auth_group_id = ?`, groupID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete existing roles for group with ID `%d`: %w", groupID, err)
+	}
+
+	for _, r := range groupRoles {
+		_, err := tx.ExecContext(ctx, `INSERT INTO auth_groups_roles (auth_group_id, auth_role_id, entity_type, entity_id) VALUES (?, ?, ?, ?)`, groupID, r.RoleID, r.EntityType, r.EntityID)
+		if err != nil {
+			return fmt.Errorf("Failed to write group roles: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AuthGroupRolesToAPI converts the given roles granted to a group to a slice of api.RoleGrant, resolving the URLs of
+// the entities they are granted on. Grants on entities that no longer exist are skipped.
+func AuthGroupRolesToAPI(ctx context.Context, tx *sql.Tx, groupRoles []AuthGroupRole) ([]api.RoleGrant, error) {
+	type entityKey struct {
+		entityType EntityType
+		entityID   int
+	}
+
+	entityURLs := make(map[entityKey]*api.URL)
+	roleGrants := make([]api.RoleGrant, 0, len(groupRoles))
+	for _, r := range groupRoles {
+		key := entityKey{entityType: r.EntityType, entityID: r.EntityID}
+		u, ok := entityURLs[key]
+		if !ok {
+			var err error
+			u, err = GetEntityURL(ctx, tx, entity.Type(r.EntityType), r.EntityID)
+			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return nil, err
+			}
+
+			entityURLs[key] = u
+		}
+
+		if u == nil {
+			continue
+		}
+
+		roleGrants = append(roleGrants, api.RoleGrant{
+			Role:            r.RoleName,
+			EntityType:      string(r.EntityType),
+			EntityReference: u.String(),
+		})
+	}
+
+	return roleGrants, nil
+}
@@ -0,0 +1,389 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+// The code below was generated by lxd-generate - DO NOT EDIT!
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+var _ = api.ServerEnvironment{}
+
+var authRoleObjects = RegisterStmt(`
+SELECT auth_roles.id, auth_roles.name, auth_roles.description
+  FROM auth_roles
+  ORDER BY auth_roles.name
+`)
+
+var authRoleObjectsByID = RegisterStmt(`
+SELECT auth_roles.id, auth_roles.name, auth_roles.description
+  FROM auth_roles
+  WHERE ( auth_roles.id = ? )
+  ORDER BY auth_roles.name
+`)
+
+var authRoleObjectsByName = RegisterStmt(`
+SELECT auth_roles.id, auth_roles.name, auth_roles.description
+  FROM auth_roles
+  WHERE ( auth_roles.name = ? )
+  ORDER BY auth_roles.name
+`)
+
+var authRoleID = RegisterStmt(`
+SELECT auth_roles.id FROM auth_roles
+  WHERE auth_roles.name = ?
+`)
+
+var authRoleCreate = RegisterStmt(`
+INSERT INTO auth_roles (name, description)
+  VALUES (?, ?)
+`)
+
+var authRoleDeleteByName = RegisterStmt(`
+DELETE FROM auth_roles WHERE name = ?
+`)
+
+var authRoleUpdate = RegisterStmt(`
+UPDATE auth_roles
+  SET name = ?, description = ?
+ WHERE id = ?
+`)
+
+var authRoleRename = RegisterStmt(`
+UPDATE auth_roles SET name = ? WHERE name = ?
+`)
+
+// authRoleColumns returns a string of column names to be used with a SELECT statement for the entity.
+// Use this function when building statements to retrieve database entries matching the AuthRole entity.
+func authRoleColumns() string {
+	return "auths_roles.id, auths_roles.name, auths_roles.description"
+}
+
+// getAuthRoles can be used to run handwritten sql.Stmts to return a slice of objects.
+func getAuthRoles(ctx context.Context, stmt *sql.Stmt, args ...any) ([]AuthRole, error) {
+	objects := make([]AuthRole, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		a := AuthRole{}
+		err := scan(&a.ID, &a.Name, &a.Description)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, a)
+
+		return nil
+	}
+
+	err := query.SelectObjects(ctx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"auths_roles\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// getAuthRolesRaw can be used to run handwritten query strings to return a slice of objects.
+func getAuthRolesRaw(ctx context.Context, tx *sql.Tx, sql string, args ...any) ([]AuthRole, error) {
+	objects := make([]AuthRole, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		a := AuthRole{}
+		err := scan(&a.ID, &a.Name, &a.Description)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, a)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, sql, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"auths_roles\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetAuthRoles returns all available auth_roles.
+// generator: auth_role GetMany
+func GetAuthRoles(ctx context.Context, tx *sql.Tx, filters ...AuthRoleFilter) ([]AuthRole, error) {
+	var err error
+
+	// Result slice.
+	objects := make([]AuthRole, 0)
+
+	// Pick the prepared statement and arguments to use based on active criteria.
+	var sqlStmt *sql.Stmt
+	args := []any{}
+	queryParts := [2]string{}
+
+	if len(filters) == 0 {
+		sqlStmt, err = Stmt(tx, authRoleObjects)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get \"authRoleObjects\" prepared statement: %w", err)
+		}
+	}
+
+	for i, filter := range filters {
+		if filter.Name != nil && filter.ID == nil {
+			args = append(args, []any{filter.Name}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(tx, authRoleObjectsByName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"authRoleObjectsByName\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(authRoleObjectsByName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"authRoleObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.ID != nil && filter.Name == nil {
+			args = append(args, []any{filter.ID}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(tx, authRoleObjectsByID)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"authRoleObjectsByID\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(authRoleObjectsByID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"authRoleObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.ID == nil && filter.Name == nil {
+			return nil, errors.New("Cannot filter on empty AuthRoleFilter")
+		} else {
+			return nil, errors.New("No statement exists for the given Filter")
+		}
+	}
+
+	// Select.
+	if sqlStmt != nil {
+		objects, err = getAuthRoles(ctx, sqlStmt, args...)
+	} else {
+		queryStr := strings.Join(queryParts[:], "ORDER BY")
+		objects, err = getAuthRolesRaw(ctx, tx, queryStr, args...)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"auths_roles\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetAuthRole returns the auth_role with the given key.
+// generator: auth_role GetOne
+func GetAuthRole(ctx context.Context, tx *sql.Tx, name string) (*AuthRole, error) {
+	filter := AuthRoleFilter{}
+	filter.Name = &name
+
+	objects, err := GetAuthRoles(ctx, tx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"auths_roles\" table: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return nil, api.StatusErrorf(http.StatusNotFound, "AuthRole not found")
+	case 1:
+		return &objects[0], nil
+	default:
+		return nil, errors.New("More than one \"auths_roles\" entry matches")
+	}
+}
+
+// GetAuthRoleID return the ID of the auth_role with the given key.
+// generator: auth_role ID
+func GetAuthRoleID(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	stmt, err := Stmt(tx, authRoleID)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"authRoleID\" prepared statement: %w", err)
+	}
+
+	row := stmt.QueryRowContext(ctx, name)
+	var id int64
+	err = row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "AuthRole not found")
+		}
+
+		return -1, fmt.Errorf("Failed to get \"auths_roles\" ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// AuthRoleExists checks if a auth_role with the given key exists.
+// generator: auth_role Exists
+func AuthRoleExists(ctx context.Context, tx *sql.Tx, name string) (bool, error) {
+	_, err := GetAuthRoleID(ctx, tx, name)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateAuthRole adds a new auth_role to the database.
+// generator: auth_role Create
+func CreateAuthRole(ctx context.Context, tx *sql.Tx, object AuthRole) (int64, error) {
+	args := make([]any, 2)
+
+	// Populate the statement arguments.
+	args[0] = object.Name
+	args[1] = object.Description
+
+	// Prepared statement to use.
+	stmt, err := Stmt(tx, authRoleCreate)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"authRoleCreate\" prepared statement: %w", err)
+	}
+
+	// Execute the statement.
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return -1, api.NewStatusError(http.StatusConflict, "This \"auths_roles\" entry already exists")
+		}
+
+		return -1, fmt.Errorf("Failed to create \"auths_roles\" entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to fetch \"auths_roles\" entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteAuthRole deletes the auth_role matching the given key parameters.
+// generator: auth_role DeleteOne-by-Name
+func DeleteAuthRole(ctx context.Context, tx *sql.Tx, name string) error {
+	stmt, err := Stmt(tx, authRoleDeleteByName)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"authRoleDeleteByName\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, name)
+	if err != nil {
+		return fmt.Errorf("Delete \"auths_roles\": %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "AuthRole not found")
+	} else if n > 1 {
+		return fmt.Errorf("Query deleted %d AuthRole rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// UpdateAuthRole updates the auth_role matching the given key parameters.
+// generator: auth_role Update
+func UpdateAuthRole(ctx context.Context, tx *sql.Tx, name string, object AuthRole) error {
+	id, err := GetAuthRoleID(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := Stmt(tx, authRoleUpdate)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"authRoleUpdate\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, object.Name, object.Description, id)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return api.NewStatusError(http.StatusConflict, "A \"auths_roles\" entry already exists with these properties")
+		}
+
+		return fmt.Errorf("Update \"auths_roles\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// RenameAuthRole renames the auth_role matching the given key parameters.
+// generator: auth_role Rename
+func RenameAuthRole(ctx context.Context, tx *sql.Tx, name string, to string) error {
+	stmt, err := Stmt(tx, authRoleRename)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"authRoleRename\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, to, name)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return api.NewStatusError(http.StatusConflict, "A \"auths_roles\" entry already exists with this name")
+		}
+
+		return fmt.Errorf("Rename AuthRole failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows failed: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query affected %d rows instead of 1", n)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func TestAuthGroupsEffectivePermissions(t *testing.T) {
+	db := newDB(t)
+
+	// The generated mapper functions use the prepared statements.
+	stmts, err := PrepareStmts(db, false)
+	require.NoError(t, err)
+
+	previousStmts := PreparedStmts
+	PreparedStmts = stmts
+	t.Cleanup(func() { PreparedStmts = previousStmts })
+
+	doTx := func(f func(ctx context.Context, tx *sql.Tx)) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+
+		f(ctx, tx)
+		require.NoError(t, tx.Commit())
+	}
+
+	projectType := EntityType(entity.TypeProject)
+	instanceType := EntityType(entity.TypeInstance)
+
+	var operatorsID, viewersID, roleID int
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		id, err := CreateAuthGroup(ctx, tx, AuthGroup{Name: "operators"})
+		require.NoError(t, err)
+		operatorsID = int(id)
+
+		id, err = CreateAuthGroup(ctx, tx, AuthGroup{Name: "viewers"})
+		require.NoError(t, err)
+		viewersID = int(id)
+
+		id, err = CreateAuthRole(ctx, tx, AuthRole{Name: "project-operator"})
+		require.NoError(t, err)
+		roleID = int(id)
+
+		err = SetAuthRoleEntitlements(ctx, tx, roleID, []AuthRoleEntitlement{
+			{EntityType: projectType, Entitlement: auth.EntitlementCanView},
+			{EntityType: projectType, Entitlement: auth.EntitlementCanEdit},
+			{EntityType: instanceType, Entitlement: auth.EntitlementCanExec},
+		})
+		require.NoError(t, err)
+
+		// The operators have a direct permission also given by the role, and a direct permission that expired.
+		err = SetAuthGroupPermissions(ctx, tx, operatorsID, []Permission{
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanView},
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanDelete, ExpiryDate: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}},
+		})
+		require.NoError(t, err)
+
+		err = SetAuthGroupRoles(ctx, tx, operatorsID, []AuthGroupRole{{RoleID: roleID, EntityType: projectType, EntityID: 1}})
+		require.NoError(t, err)
+
+		// The viewers have a direct permission that hasn't expired yet.
+		err = SetAuthGroupPermissions(ctx, tx, viewersID, []Permission{
+			{EntityType: projectType, EntityID: 2, Entitlement: auth.EntitlementCanView, ExpiryDate: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}},
+		})
+		require.NoError(t, err)
+	})
+
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		// The view contains the direct permissions, expired or not, and the entitlements of the roles on the type of
+		// the entity they are granted on. The direct permission also given by the role is only included once.
+		var count int
+		err := tx.QueryRowContext(ctx, `SELECT count(*) FROM auth_groups_effective_permissions WHERE auth_group_id = ?`, operatorsID).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		// Permissions given both directly and through a role are only returned once, and expired ones are excluded.
+		permissions, err := GetDistinctPermissionsByGroupNames(ctx, tx, []string{"operators"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []Permission{
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanView},
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanEdit},
+		}, permissions)
+
+		// Permissions shared by several groups are only returned once.
+		permissions, err = GetDistinctPermissionsByGroupNames(ctx, tx, []string{"operators", "viewers"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []Permission{
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanView},
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanEdit},
+			{EntityType: projectType, EntityID: 2, Entitlement: auth.EntitlementCanView},
+		}, permissions)
+
+		groupPermissions, err := GetGroupPermissions(ctx, tx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []Permission{
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanView},
+			{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanEdit},
+		}, groupPermissions["operators"])
+		assert.ElementsMatch(t, []Permission{
+			{EntityType: projectType, EntityID: 2, Entitlement: auth.EntitlementCanView},
+		}, groupPermissions["viewers"])
+
+		entityTypes, err := GetAuthRoleGrantedEntityTypes(ctx, tx, roleID)
+		require.NoError(t, err)
+		assert.Equal(t, []EntityType{projectType}, entityTypes)
+	})
+
+	// Removing the role from the group removes the entitlements it gave.
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		err := SetAuthGroupRoles(ctx, tx, operatorsID, nil)
+		require.NoError(t, err)
+
+		permissions, err := GetDistinctPermissionsByGroupNames(ctx, tx, []string{"operators"})
+		require.NoError(t, err)
+		assert.Equal(t, []Permission{{EntityType: projectType, EntityID: 1, Entitlement: auth.EntitlementCanView}}, permissions)
+
+		entityTypes, err := GetAuthRoleGrantedEntityTypes(ctx, tx, roleID)
+		require.NoError(t, err)
+		assert.Empty(t, entityTypes)
+	})
+}
@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"slices"
 
 	"github.com/canonical/lxd/lxd/db/query"
@@ -55,16 +56,53 @@ func (c *ClusterGroup) ToAPI(ctx context.Context, tx *sql.Tx) (*api.ClusterGroup
 		return nil, err
 	}
 
+	config, err := GetClusterGroupConfig(ctx, tx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	result := api.ClusterGroup{
 		Name:        c.Name,
 		Description: c.Description,
 		Members:     c.Nodes,
+		Config:      config,
 		UsedBy:      usedBy,
 	}
 
 	return &result, nil
 }
 
+// GetClusterGroupConfig returns the configuration of the cluster group with the given ID.
+func GetClusterGroupConfig(ctx context.Context, tx *sql.Tx, groupID int) (map[string]string, error) {
+	config, err := query.SelectConfig(ctx, tx, "cluster_groups_config", "cluster_group_id = ?", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading cluster group config: %w", err)
+	}
+
+	return config, nil
+}
+
+// UpdateClusterGroupConfig replaces the configuration of the cluster group with the given ID.
+func UpdateClusterGroupConfig(ctx context.Context, tx *sql.Tx, groupID int, config map[string]string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM cluster_groups_config WHERE cluster_group_id = ?", groupID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting cluster group config: %w", err)
+	}
+
+	for key, value := range config {
+		if value == "" {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO cluster_groups_config (cluster_group_id, key, value) VALUES (?, ?, ?)", groupID, key, value)
+		if err != nil {
+			return fmt.Errorf("Failed inserting cluster group config: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // GetClusterGroupUsedBy collates references to the cluster group with the given name.
 // This currently only returns the URLs of projects whose `restricted.cluster.groups` configuration
 // contains the cluster group.
@@ -55,6 +55,8 @@ const (
 	TypeUnixHotplug = DeviceType(9)
 	TypeTPM         = DeviceType(10)
 	TypePCI         = DeviceType(11)
+	TypeSecret      = DeviceType(12)
+	TypeWatchdog    = DeviceType(13)
 )
 
 func (t DeviceType) String() string {
@@ -83,6 +85,10 @@ func (t DeviceType) String() string {
 		return "tpm"
 	case TypePCI:
 		return "pci"
+	case TypeSecret:
+		return "secret"
+	case TypeWatchdog:
+		return "watchdog"
 	}
 
 	return ""
@@ -115,6 +121,10 @@ func NewDeviceType(t string) (DeviceType, error) {
 		return TypeTPM, nil
 	case "pci":
 		return TypePCI, nil
+	case "secret":
+		return TypeSecret, nil
+	case "watchdog":
+		return TypeWatchdog, nil
 	default:
 		return -1, fmt.Errorf("Invalid device type %q", t)
 	}
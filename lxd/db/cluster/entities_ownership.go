@@ -0,0 +1,104 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// EntityOwnership records the identities that created and last modified an entity.
+type EntityOwnership struct {
+	EntityID       int
+	CreatedBy      string
+	CreatedAt      time.Time
+	LastModifiedBy string
+	LastModifiedAt time.Time
+}
+
+// SetEntityCreated records the identity that created the entity of the given type and ID.
+// The creation is only recorded once, so that a repeated creation event doesn't override it.
+func SetEntityCreated(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityID int, createdBy string, date time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO entities_ownership (entity_type, entity_id, created_by, created_at, last_modified_by, last_modified_at)
+  VALUES (?, ?, ?, ?, ?, ?)
+  ON CONFLICT (entity_type, entity_id) DO UPDATE SET created_by = excluded.created_by, created_at = excluded.created_at
+  WHERE entities_ownership.created_at IS NULL
+`, EntityType(entityType), entityID, createdBy, date.UTC(), createdBy, date.UTC())
+	if err != nil {
+		return fmt.Errorf("Insert failed for \"entities_ownership\" table: %w", err)
+	}
+
+	return nil
+}
+
+// SetEntityModified records the identity that last modified the entity of the given type and ID.
+func SetEntityModified(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityID int, modifiedBy string, date time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO entities_ownership (entity_type, entity_id, last_modified_by, last_modified_at)
+  VALUES (?, ?, ?, ?)
+  ON CONFLICT (entity_type, entity_id) DO UPDATE SET last_modified_by = excluded.last_modified_by, last_modified_at = excluded.last_modified_at
+  WHERE entities_ownership.last_modified_at IS NULL OR entities_ownership.last_modified_at <= excluded.last_modified_at
+`, EntityType(entityType), entityID, modifiedBy, date.UTC())
+	if err != nil {
+		return fmt.Errorf("Insert failed for \"entities_ownership\" table: %w", err)
+	}
+
+	return nil
+}
+
+// GetEntityOwnerships returns a map of entity ID to the ownership of the entities of the given type.
+// If no entity IDs are given, the ownership of all the entities of the type is returned.
+func GetEntityOwnerships(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityIDs ...int) (map[int]EntityOwnership, error) {
+	stmt := `
+SELECT entity_id, created_by, created_at, last_modified_by, last_modified_at
+  FROM entities_ownership
+  WHERE entity_type = ?`
+
+	args := []any{EntityType(entityType)}
+	if len(entityIDs) > 0 {
+		stmt += " AND entity_id IN " + query.Params(len(entityIDs))
+		for _, entityID := range entityIDs {
+			args = append(args, entityID)
+		}
+	}
+
+	ownerships := make(map[int]EntityOwnership)
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		var ownership EntityOwnership
+		var createdAt sql.NullTime
+		var lastModifiedAt sql.NullTime
+
+		err := scan(&ownership.EntityID, &ownership.CreatedBy, &createdAt, &ownership.LastModifiedBy, &lastModifiedAt)
+		if err != nil {
+			return err
+		}
+
+		ownership.CreatedAt = createdAt.Time
+		ownership.LastModifiedAt = lastModifiedAt.Time
+		ownerships[ownership.EntityID] = ownership
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"entities_ownership\" table: %w", err)
+	}
+
+	return ownerships, nil
+}
+
+// GetEntityOwnership returns the ownership of the entity of the given type and ID.
+// If nothing was recorded for the entity, an empty EntityOwnership is returned.
+func GetEntityOwnership(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityID int) (EntityOwnership, error) {
+	ownerships, err := GetEntityOwnerships(ctx, tx, entityType, entityID)
+	if err != nil {
+		return EntityOwnership{}, err
+	}
+
+	return ownerships[entityID], nil
+}
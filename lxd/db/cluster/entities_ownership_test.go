@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func TestEntityOwnership(t *testing.T) {
+	db := newDB(t)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	date := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	// Nothing is recorded by default.
+	ownership, err := GetEntityOwnership(ctx, tx, entity.TypeInstance, 1)
+	require.NoError(t, err)
+	assert.Equal(t, EntityOwnership{}, ownership)
+
+	// The creation is recorded as the last modification too.
+	err = SetEntityCreated(ctx, tx, entity.TypeInstance, 1, "alice", date)
+	require.NoError(t, err)
+
+	ownership, err = GetEntityOwnership(ctx, tx, entity.TypeInstance, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ownership.EntityID)
+	assert.Equal(t, "alice", ownership.CreatedBy)
+	assert.True(t, ownership.CreatedAt.Equal(date))
+	assert.Equal(t, "alice", ownership.LastModifiedBy)
+
+	// A repeated creation doesn't override the recorded one.
+	err = SetEntityCreated(ctx, tx, entity.TypeInstance, 1, "bob", date.Add(time.Minute))
+	require.NoError(t, err)
+
+	err = SetEntityModified(ctx, tx, entity.TypeInstance, 1, "bob", date.Add(time.Hour))
+	require.NoError(t, err)
+
+	// An older modification doesn't override a newer one.
+	err = SetEntityModified(ctx, tx, entity.TypeInstance, 1, "carol", date.Add(time.Minute))
+	require.NoError(t, err)
+
+	ownership, err = GetEntityOwnership(ctx, tx, entity.TypeInstance, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", ownership.CreatedBy)
+	assert.True(t, ownership.CreatedAt.Equal(date))
+	assert.Equal(t, "bob", ownership.LastModifiedBy)
+	assert.True(t, ownership.LastModifiedAt.Equal(date.Add(time.Hour)))
+
+	// A creation recorded after a modification of the entity keeps the modification.
+	err = SetEntityModified(ctx, tx, entity.TypeProfile, 1, "bob", date.Add(time.Hour))
+	require.NoError(t, err)
+
+	err = SetEntityCreated(ctx, tx, entity.TypeProfile, 1, "alice", date)
+	require.NoError(t, err)
+
+	ownership, err = GetEntityOwnership(ctx, tx, entity.TypeProfile, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", ownership.CreatedBy)
+	assert.Equal(t, "bob", ownership.LastModifiedBy)
+
+	// The ownerships are per entity type and can be filtered by ID.
+	err = SetEntityCreated(ctx, tx, entity.TypeInstance, 2, "dave", date)
+	require.NoError(t, err)
+
+	ownerships, err := GetEntityOwnerships(ctx, tx, entity.TypeInstance)
+	require.NoError(t, err)
+	assert.Len(t, ownerships, 2)
+
+	ownerships, err = GetEntityOwnerships(ctx, tx, entity.TypeInstance, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, ownerships, 1)
+	assert.Equal(t, "dave", ownerships[2].CreatedBy)
+
+	ownerships, err = GetEntityOwnerships(ctx, tx, entity.TypeNetwork)
+	require.NoError(t, err)
+	assert.Empty(t, ownerships)
+}
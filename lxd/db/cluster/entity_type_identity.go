@@ -75,6 +75,9 @@ CREATE TRIGGER %s
 	DELETE FROM auth_groups_permissions 
 		WHERE entity_type IN (%d, %d) 
 		AND entity_id = OLD.id;
+	DELETE FROM auth_groups_roles
+		WHERE entity_type IN (%d, %d)
+		AND entity_id = OLD.id;
 	DELETE FROM warnings
 		WHERE entity_type_code IN (%d, %d)
 		AND entity_id = OLD.id;
@@ -82,7 +85,7 @@ CREATE TRIGGER %s
 		WHERE entity_type = %d
 		AND entity_id = OLD.id;
 	END
-`, name, e.code(), typeCertificate.code(), e.code(), typeCertificate.code(), e.code())
+`, name, e.code(), typeCertificate.code(), e.code(), typeCertificate.code(), e.code(), typeCertificate.code(), e.code())
 }
 
 // authMethodCaseClause returns the SQL CASE clause for auth method mapping.
@@ -83,9 +83,18 @@ CREATE TRIGGER %s
 	DELETE FROM auth_groups_permissions 
 		WHERE entity_type = %d 
 		AND entity_id = OLD.id;
+	DELETE FROM auth_groups_roles
+		WHERE entity_type = %d
+		AND entity_id = OLD.id;
 	DELETE FROM warnings
 		WHERE entity_type_code = %d
 		AND entity_id = OLD.id;
+	DELETE FROM entities_ownership
+		WHERE entity_type = %d
+		AND entity_id = OLD.id;
+	DELETE FROM projects_shares
+		WHERE entity_type = %d
+		AND entity_id = OLD.id;
 	END
-`, name, e.code(), e.code())
+`, name, e.code(), e.code(), e.code(), e.code(), e.code())
 }
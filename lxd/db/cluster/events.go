@@ -0,0 +1,116 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Event is a lifecycle or operation event persisted in the event history.
+type Event struct {
+	ID        int64
+	Type      string
+	Project   string
+	Location  string
+	Timestamp time.Time
+	Metadata  string
+}
+
+// EventFilter specifies potential query parameter fields.
+type EventFilter struct {
+	Since *time.Time
+	Types []string
+}
+
+// ToAPI converts the persisted event to an API event.
+func (e Event) ToAPI() api.Event {
+	return api.Event{
+		Type:      e.Type,
+		Timestamp: e.Timestamp,
+		Metadata:  json.RawMessage(e.Metadata),
+		Location:  e.Location,
+		Project:   e.Project,
+	}
+}
+
+// CreateEvents adds the given events to the event history.
+func CreateEvents(ctx context.Context, tx *sql.Tx, events []Event) error {
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO events (type, project, location, timestamp, metadata) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("Failed to prepare insert into \"events\" table: %w", err)
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, event := range events {
+		_, err = stmt.ExecContext(ctx, event.Type, event.Project, event.Location, event.Timestamp.UTC(), event.Metadata)
+		if err != nil {
+			return fmt.Errorf("Insert failed for \"events\" table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetEvents returns the events of the event history matching the given filter, from the oldest to the newest.
+func GetEvents(ctx context.Context, tx *sql.Tx, filter EventFilter) ([]Event, error) {
+	var where []string
+	var args []any
+
+	if filter.Since != nil {
+		where = append(where, "timestamp >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+
+	if len(filter.Types) > 0 {
+		where = append(where, "type IN "+query.Params(len(filter.Types)))
+		for _, eventType := range filter.Types {
+			args = append(args, eventType)
+		}
+	}
+
+	stmt := "SELECT id, type, project, location, timestamp, metadata FROM events"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	stmt += " ORDER BY timestamp, id"
+
+	events := []Event{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		event := Event{}
+
+		err := scan(&event.ID, &event.Type, &event.Project, &event.Location, &event.Timestamp, &event.Metadata)
+		if err != nil {
+			return err
+		}
+
+		events = append(events, event)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"events\" table: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteEventsBefore removes the events of the given location that are older than the given time from the event
+// history.
+func DeleteEventsBefore(ctx context.Context, tx *sql.Tx, location string, before time.Time) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM events WHERE location = ? AND timestamp < ?", location, before.UTC())
+	if err != nil {
+		return fmt.Errorf("Delete entries for \"events\" failed: %w", err)
+	}
+
+	return nil
+}
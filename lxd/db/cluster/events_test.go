@@ -0,0 +1,65 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestEvents(t *testing.T) {
+	db := newDB(t)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	err = CreateEvents(ctx, tx, []Event{
+		{Type: api.EventTypeLifecycle, Project: "default", Location: "n1", Timestamp: now.Add(-2 * time.Hour), Metadata: `{"action":"instance-created"}`},
+		{Type: api.EventTypeOperation, Project: "default", Location: "n2", Timestamp: now.Add(-time.Hour), Metadata: `{"id":"1"}`},
+		{Type: api.EventTypeLifecycle, Project: "p1", Location: "n2", Timestamp: now, Metadata: `{"action":"instance-started"}`},
+	})
+	require.NoError(t, err)
+
+	// All the events are returned from the oldest to the newest.
+	events, err := GetEvents(ctx, tx, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "n1", events[0].Location)
+	assert.True(t, events[0].Timestamp.Equal(now.Add(-2*time.Hour)))
+	assert.Equal(t, api.Event{
+		Type:      api.EventTypeLifecycle,
+		Timestamp: events[2].Timestamp,
+		Metadata:  []byte(`{"action":"instance-started"}`),
+		Location:  "n2",
+		Project:   "p1",
+	}, events[2].ToAPI())
+
+	// Filter by time and type.
+	since := now.Add(-time.Hour)
+	events, err = GetEvents(ctx, tx, EventFilter{Since: &since})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, api.EventTypeOperation, events[0].Type)
+
+	events, err = GetEvents(ctx, tx, EventFilter{Since: &since, Types: []string{api.EventTypeLifecycle}})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "p1", events[0].Project)
+
+	// Only the old events of the given member are pruned.
+	require.NoError(t, DeleteEventsBefore(ctx, tx, "n2", now))
+	events, err = GetEvents(ctx, tx, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "n1", events[0].Location)
+	assert.Equal(t, "p1", events[1].Project)
+}
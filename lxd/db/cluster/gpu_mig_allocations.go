@@ -0,0 +1,100 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GPUMIGAllocation is a MIG (Multi-Instance GPU) instance created on a physical GPU of a cluster member for
+// an instance's GPU device.
+type GPUMIGAllocation struct {
+	ID                int64
+	NodeID            int64
+	GPU               string
+	Profile           string
+	GPUInstanceID     int
+	ComputeInstanceID int
+	InstanceID        int
+	DeviceName        string
+}
+
+// CreateGPUMIGAllocation records a MIG instance created for an instance's GPU device.
+func CreateGPUMIGAllocation(ctx context.Context, tx *sql.Tx, allocation GPUMIGAllocation) (int64, error) {
+	result, err := tx.ExecContext(ctx, `
+INSERT INTO gpu_mig_allocations (node_id, gpu, profile, gpu_instance_id, compute_instance_id, instance_id, device_name)
+VALUES (?, ?, ?, ?, ?, ?, ?)`, allocation.NodeID, allocation.GPU, allocation.Profile, allocation.GPUInstanceID, allocation.ComputeInstanceID, allocation.InstanceID, allocation.DeviceName)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return -1, api.StatusErrorf(http.StatusConflict, "A MIG instance is already allocated for this device")
+		}
+
+		return -1, fmt.Errorf("Insert failed for \"gpu_mig_allocations\" table: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetGPUMIGAllocation returns the MIG instance allocated for the given instance's GPU device.
+func GetGPUMIGAllocation(ctx context.Context, tx *sql.Tx, instanceID int, deviceName string) (*GPUMIGAllocation, error) {
+	allocations, err := getGPUMIGAllocations(ctx, tx, "WHERE instance_id = ? AND device_name = ?", instanceID, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(allocations) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "MIG allocation not found")
+	}
+
+	return &allocations[0], nil
+}
+
+// GetNodeGPUMIGAllocations returns the MIG instances allocated on the physical GPUs of the given cluster member.
+func GetNodeGPUMIGAllocations(ctx context.Context, tx *sql.Tx, nodeID int64) ([]GPUMIGAllocation, error) {
+	return getGPUMIGAllocations(ctx, tx, "WHERE node_id = ?", nodeID)
+}
+
+// DeleteGPUMIGAllocation removes the record of the MIG instance allocated for the given instance's GPU device.
+func DeleteGPUMIGAllocation(ctx context.Context, tx *sql.Tx, instanceID int, deviceName string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM gpu_mig_allocations WHERE instance_id = ? AND device_name = ?", instanceID, deviceName)
+	if err != nil {
+		return fmt.Errorf("Delete entry for \"gpu_mig_allocations\" failed: %w", err)
+	}
+
+	return nil
+}
+
+// getGPUMIGAllocations returns the MIG allocations matching the given filter.
+func getGPUMIGAllocations(ctx context.Context, tx *sql.Tx, where string, args ...any) ([]GPUMIGAllocation, error) {
+	if where == "" {
+		return nil, errors.New("A filter is required")
+	}
+
+	allocations := []GPUMIGAllocation{}
+
+	stmt := "SELECT id, node_id, gpu, profile, gpu_instance_id, compute_instance_id, instance_id, device_name FROM gpu_mig_allocations " + where + " ORDER BY id"
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		allocation := GPUMIGAllocation{}
+
+		err := scan(&allocation.ID, &allocation.NodeID, &allocation.GPU, &allocation.Profile, &allocation.GPUInstanceID, &allocation.ComputeInstanceID, &allocation.InstanceID, &allocation.DeviceName)
+		if err != nil {
+			return err
+		}
+
+		allocations = append(allocations, allocation)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"gpu_mig_allocations\" table: %w", err)
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,68 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestGPUMIGAllocations(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`
+INSERT INTO nodes (id, name, address, schema, api_extensions, arch, description) VALUES (1, 'n1', '10.0.0.1:8443', 1, 1, 1, ''), (2, 'n2', '10.0.0.2:8443', 1, 1, 1, '');
+INSERT INTO projects (id, name, description) VALUES (1, 'default', '');
+INSERT INTO instances (id, node_id, name, architecture, type, project_id, description) VALUES
+  (1, 1, 'c1', 1, 0, 1, ''),
+  (2, 1, 'c2', 1, 0, 1, ''),
+  (3, 2, 'c3', 1, 0, 1, '');
+`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	gpu := "GPU-5b2f4c3a-0000-0000-0000-000000000000"
+
+	_, err = CreateGPUMIGAllocation(ctx, tx, GPUMIGAllocation{NodeID: 1, GPU: gpu, Profile: "1g.5gb", GPUInstanceID: 7, ComputeInstanceID: 0, InstanceID: 1, DeviceName: "gpu0"})
+	require.NoError(t, err)
+
+	_, err = CreateGPUMIGAllocation(ctx, tx, GPUMIGAllocation{NodeID: 1, GPU: gpu, Profile: "2g.10gb", GPUInstanceID: 3, ComputeInstanceID: 0, InstanceID: 2, DeviceName: "gpu0"})
+	require.NoError(t, err)
+
+	_, err = CreateGPUMIGAllocation(ctx, tx, GPUMIGAllocation{NodeID: 2, GPU: gpu, Profile: "1g.5gb", GPUInstanceID: 7, ComputeInstanceID: 0, InstanceID: 3, DeviceName: "gpu0"})
+	require.NoError(t, err)
+
+	// A device can only hold a single MIG instance.
+	_, err = CreateGPUMIGAllocation(ctx, tx, GPUMIGAllocation{NodeID: 1, GPU: gpu, Profile: "1g.5gb", GPUInstanceID: 8, ComputeInstanceID: 0, InstanceID: 1, DeviceName: "gpu0"})
+	assert.Error(t, err)
+
+	allocation, err := GetGPUMIGAllocation(ctx, tx, 2, "gpu0")
+	require.NoError(t, err)
+	assert.Equal(t, "2g.10gb", allocation.Profile)
+	assert.Equal(t, 3, allocation.GPUInstanceID)
+
+	allocations, err := GetNodeGPUMIGAllocations(ctx, tx, 1)
+	require.NoError(t, err)
+	require.Len(t, allocations, 2)
+	assert.Equal(t, 1, allocations[0].InstanceID)
+	assert.Equal(t, 2, allocations[1].InstanceID)
+
+	err = DeleteGPUMIGAllocation(ctx, tx, 1, "gpu0")
+	require.NoError(t, err)
+
+	_, err = GetGPUMIGAllocation(ctx, tx, 1, "gpu0")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound), "Unexpected error %v", err)
+
+	allocations, err = GetNodeGPUMIGAllocations(ctx, tx, 1)
+	require.NoError(t, err)
+	assert.Len(t, allocations, 1)
+}
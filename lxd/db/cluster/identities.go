@@ -334,10 +334,25 @@ func (i *Identity) ToAPI(ctx context.Context, tx *sql.Tx, canViewGroup auth.Perm
 		return nil, err
 	}
 
+	expiries, err := GetAuthGroupExpiriesByIdentityID(ctx, tx, i.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	groupNames := make([]string, 0, len(groups))
+	var groupExpiries map[string]time.Time
 	for _, group := range groups {
 		if canViewGroup(entity.AuthGroupURL(group.Name)) {
 			groupNames = append(groupNames, group.Name)
+
+			expiryDate, ok := expiries[group.Name]
+			if ok {
+				if groupExpiries == nil {
+					groupExpiries = make(map[string]time.Time)
+				}
+
+				groupExpiries[group.Name] = expiryDate
+			}
 		}
 	}
 
@@ -356,14 +371,32 @@ func (i *Identity) ToAPI(ctx context.Context, tx *sql.Tx, canViewGroup auth.Perm
 		tlsCertificate = metadata.Certificate
 	}
 
-	return &api.Identity{
+	delegation, err := GetIdentityDelegation(ctx, tx, i.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := GetIdentityProfile(ctx, tx, i.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiIdentity := &api.Identity{
 		AuthenticationMethod: string(i.AuthMethod),
 		Type:                 string(i.Type),
 		Identifier:           i.Identifier,
 		Name:                 i.Name,
+		DisplayName:          profile.DisplayName,
 		Groups:               groupNames,
+		GroupExpiries:        groupExpiries,
 		TLSCertificate:       tlsCertificate,
-	}, nil
+	}
+
+	if delegation != nil {
+		apiIdentity.Delegation = delegation.ToAPI()
+	}
+
+	return apiIdentity, nil
 }
 
 // ActivateTLSIdentity updates a TLS identity to make it valid by adding the fingerprint, PEM encoded certificate, and setting
@@ -444,13 +477,15 @@ func GetPendingTLSIdentityByTokenSecret(ctx context.Context, tx *sql.Tx, secret
 	return &identities[0], nil
 }
 
-// GetAuthGroupsByIdentityID returns a slice of groups that the identity with the given ID is a member of.
+// GetAuthGroupsByIdentityID returns a slice of groups that the identity with the given ID is a member of, ignoring
+// expired memberships.
 func GetAuthGroupsByIdentityID(ctx context.Context, tx *sql.Tx, identityID int) ([]AuthGroup, error) {
 	stmt := `
 SELECT auth_groups.id, auth_groups.name, auth_groups.description
 FROM auth_groups
 JOIN identities_auth_groups ON auth_groups.id = identities_auth_groups.auth_group_id
-WHERE identities_auth_groups.identity_id = ?`
+WHERE identities_auth_groups.identity_id = ?
+AND (identities_auth_groups.expiry_date IS NULL OR identities_auth_groups.expiry_date > ?)`
 
 	var result []AuthGroup
 	dest := func(scan func(dest ...any) error) error {
@@ -465,7 +500,7 @@ WHERE identities_auth_groups.identity_id = ?`
 		return nil
 	}
 
-	err := query.Scan(ctx, tx, stmt, dest, identityID)
+	err := query.Scan(ctx, tx, stmt, dest, identityID, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get groups for identity with ID `%d`: %w", identityID, err)
 	}
@@ -473,12 +508,14 @@ WHERE identities_auth_groups.identity_id = ?`
 	return result, nil
 }
 
-// GetAllAuthGroupsByIdentityIDs returns a map of identity ID to slice of groups the identity with that ID is a member of.
+// GetAllAuthGroupsByIdentityIDs returns a map of identity ID to slice of groups the identity with that ID is a member of,
+// ignoring expired memberships.
 func GetAllAuthGroupsByIdentityIDs(ctx context.Context, tx *sql.Tx) (map[int][]AuthGroup, error) {
 	stmt := `
 SELECT identities_auth_groups.identity_id, auth_groups.id, auth_groups.name, auth_groups.description
 FROM auth_groups
-JOIN identities_auth_groups ON auth_groups.id = identities_auth_groups.auth_group_id`
+JOIN identities_auth_groups ON auth_groups.id = identities_auth_groups.auth_group_id
+WHERE identities_auth_groups.expiry_date IS NULL OR identities_auth_groups.expiry_date > ?`
 
 	result := make(map[int][]AuthGroup)
 	dest := func(scan func(dest ...any) error) error {
@@ -494,7 +531,7 @@ JOIN identities_auth_groups ON auth_groups.id = identities_auth_groups.auth_grou
 		return nil
 	}
 
-	err := query.Scan(ctx, tx, stmt, dest)
+	err := query.Scan(ctx, tx, stmt, dest, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get identities for all groups: %w", err)
 	}
@@ -502,6 +539,63 @@ JOIN identities_auth_groups ON auth_groups.id = identities_auth_groups.auth_grou
 	return result, nil
 }
 
+// GetAuthGroupExpiriesByIdentityID returns a map of group name to expiry date of the unexpired memberships of the
+// identity with the given ID that have an expiry date.
+func GetAuthGroupExpiriesByIdentityID(ctx context.Context, tx *sql.Tx, identityID int) (map[string]time.Time, error) {
+	expiries, err := getAuthGroupExpiries(ctx, tx, &identityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return expiries[identityID], nil
+}
+
+// GetAllAuthGroupExpiriesByIdentityIDs returns a map of identity ID to map of group name to expiry date of the
+// unexpired memberships that have an expiry date.
+func GetAllAuthGroupExpiriesByIdentityIDs(ctx context.Context, tx *sql.Tx) (map[int]map[string]time.Time, error) {
+	return getAuthGroupExpiries(ctx, tx, nil)
+}
+
+func getAuthGroupExpiries(ctx context.Context, tx *sql.Tx, identityID *int) (map[int]map[string]time.Time, error) {
+	stmt := `
+SELECT identities_auth_groups.identity_id, auth_groups.name, identities_auth_groups.expiry_date
+FROM auth_groups
+JOIN identities_auth_groups ON auth_groups.id = identities_auth_groups.auth_group_id
+WHERE identities_auth_groups.expiry_date > ?`
+
+	args := []any{time.Now()}
+	if identityID != nil {
+		stmt += ` AND identities_auth_groups.identity_id = ?`
+		args = append(args, *identityID)
+	}
+
+	result := make(map[int]map[string]time.Time)
+	dest := func(scan func(dest ...any) error) error {
+		var id int
+		var groupName string
+		var expiryDate time.Time
+		err := scan(&id, &groupName, &expiryDate)
+		if err != nil {
+			return err
+		}
+
+		if result[id] == nil {
+			result[id] = make(map[string]time.Time)
+		}
+
+		result[id][groupName] = expiryDate
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get group membership expiry dates: %w", err)
+	}
+
+	return result, nil
+}
+
 // GetIdentityByNameOrIdentifier attempts to get an identity by the authentication method and identifier. If that fails
 // it will try to use the nameOrID argument as a name and will return the result only if the query matches a single Identity.
 // It will return an [api.StatusError] with [http.StatusNotFound] if none are found or [http.StatusBadRequest] if multiple are found.
@@ -590,3 +684,27 @@ WHERE auth_groups.name IN %s
 
 	return nil
 }
+
+// SetIdentityAuthGroupExpiries sets the expiry dates of the memberships of the identity with the given ID to the groups
+// with the given names. The memberships must already exist.
+func SetIdentityAuthGroupExpiries(ctx context.Context, tx *sql.Tx, identityID int, expiries map[string]time.Time) error {
+	for groupName, expiryDate := range expiries {
+		res, err := tx.ExecContext(ctx, `
+UPDATE identities_auth_groups SET expiry_date = ?
+WHERE identity_id = ? AND auth_group_id = (SELECT id FROM auth_groups WHERE name = ?)`, expiryDate, identityID, groupName)
+		if err != nil {
+			return fmt.Errorf("Failed to set expiry date of membership to group %q: %w", groupName, err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("Failed to check expiry date of membership to group %q: %w", groupName, err)
+		}
+
+		if rowsAffected != 1 {
+			return api.StatusErrorf(http.StatusBadRequest, "Identity isn't a member of group %q", groupName)
+		}
+	}
+
+	return nil
+}
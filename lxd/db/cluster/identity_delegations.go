@@ -0,0 +1,119 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// IdentityDelegation records the creation of an identity confined to a project by another identity.
+type IdentityDelegation struct {
+	IdentityID int
+
+	// ParentIdentityID is the ID of the identity that created the identity, unset once it's deleted.
+	ParentIdentityID sql.NullInt64
+
+	// Parent is the URL of the identity that created the identity, kept for auditing.
+	Parent string
+
+	Project   string
+	AuthGroup string
+	Date      time.Time
+}
+
+// ToAPI converts the IdentityDelegation to an api.IdentityDelegation.
+func (d IdentityDelegation) ToAPI() *api.IdentityDelegation {
+	return &api.IdentityDelegation{
+		Parent:    d.Parent,
+		Project:   d.Project,
+		Group:     d.AuthGroup,
+		CreatedAt: d.Date,
+	}
+}
+
+// CreateIdentityDelegation records the delegation of the permissions of the given group to the identity with the
+// given ID, confined to the given project.
+func CreateIdentityDelegation(ctx context.Context, tx *sql.Tx, delegation IdentityDelegation) error {
+	res, err := tx.ExecContext(ctx, `
+INSERT INTO identities_delegations (identity_id, parent_identity_id, parent, project_id, auth_group_id, date)
+  SELECT ?, ?, ?, projects.id, (SELECT id FROM auth_groups WHERE name = ?), ? FROM projects WHERE projects.name = ?
+`, delegation.IdentityID, delegation.ParentIdentityID, delegation.Parent, delegation.AuthGroup, delegation.Date.UTC(), delegation.Project)
+	if err != nil {
+		return fmt.Errorf("Insert failed for \"identities_delegations\" table: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Project %q not found", delegation.Project)
+	}
+
+	return nil
+}
+
+// GetIdentityDelegation returns the delegation of the identity with the given ID, or nil if the identity wasn't
+// created by delegation.
+func GetIdentityDelegation(ctx context.Context, tx *sql.Tx, identityID int) (*IdentityDelegation, error) {
+	delegations, err := getIdentityDelegations(ctx, tx, &identityID)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation, ok := delegations[identityID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &delegation, nil
+}
+
+// GetAllIdentityDelegations returns a map of identity ID to the delegation of the identity, for the identities
+// created by delegation.
+func GetAllIdentityDelegations(ctx context.Context, tx *sql.Tx) (map[int]IdentityDelegation, error) {
+	return getIdentityDelegations(ctx, tx, nil)
+}
+
+func getIdentityDelegations(ctx context.Context, tx *sql.Tx, identityID *int) (map[int]IdentityDelegation, error) {
+	stmt := `
+SELECT identities_delegations.identity_id, identities_delegations.parent_identity_id, identities_delegations.parent,
+  projects.name, coalesce(auth_groups.name, ''), identities_delegations.date
+  FROM identities_delegations
+  JOIN projects ON projects.id = identities_delegations.project_id
+  LEFT JOIN auth_groups ON auth_groups.id = identities_delegations.auth_group_id`
+
+	var args []any
+	if identityID != nil {
+		stmt += `
+  WHERE identities_delegations.identity_id = ?`
+		args = append(args, *identityID)
+	}
+
+	delegations := make(map[int]IdentityDelegation)
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		d := IdentityDelegation{}
+
+		err := scan(&d.IdentityID, &d.ParentIdentityID, &d.Parent, &d.Project, &d.AuthGroup, &d.Date)
+		if err != nil {
+			return err
+		}
+
+		delegations[d.IdentityID] = d
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"identities_delegations\" table: %w", err)
+	}
+
+	return delegations, nil
+}
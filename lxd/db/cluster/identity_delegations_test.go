@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestIdentityDelegations(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`
+PRAGMA foreign_keys = ON;
+INSERT INTO projects (id, name, description) VALUES (1, 'default', ''), (2, 'p1', '');
+INSERT INTO identities (id, auth_method, type, identifier, name, metadata) VALUES (1, 1, 1, 'admin', 'admin', '{}'), (2, 1, 1, 'ci', 'ci', '{}'), (3, 1, 1, 'other', 'other', '{}');
+INSERT INTO auth_groups (id, name, description) VALUES (1, 'delegation-1', '');
+`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	date := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	delegation := IdentityDelegation{
+		IdentityID: 2,
+		Parent:     "/1.0/auth/identities/tls/admin",
+		Project:    "p1",
+		AuthGroup:  "delegation-1",
+		Date:       date,
+	}
+
+	delegation.ParentIdentityID.Int64 = 1
+	delegation.ParentIdentityID.Valid = true
+
+	err = CreateIdentityDelegation(ctx, tx, delegation)
+	require.NoError(t, err)
+
+	// The project must exist.
+	err = CreateIdentityDelegation(ctx, tx, IdentityDelegation{IdentityID: 3, Parent: "/1.0/auth/identities/unix/root", Project: "missing", Date: date})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	// Identities that weren't created by delegation have none.
+	got, err := GetIdentityDelegation(ctx, tx, 3)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = GetIdentityDelegation(ctx, tx, 2)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.True(t, got.Date.Equal(date))
+	got.Date = date
+	assert.Equal(t, delegation, *got)
+	assert.Equal(t, &api.IdentityDelegation{Parent: "/1.0/auth/identities/tls/admin", Project: "p1", Group: "delegation-1", CreatedAt: date}, got.ToAPI())
+
+	// The parent is still recorded once the parent identity and the group are deleted.
+	_, err = tx.Exec(`DELETE FROM identities WHERE id = 1; DELETE FROM auth_groups WHERE id = 1`)
+	require.NoError(t, err)
+
+	all, err := GetAllIdentityDelegations(ctx, tx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.False(t, all[2].ParentIdentityID.Valid)
+	assert.Equal(t, "/1.0/auth/identities/tls/admin", all[2].Parent)
+	assert.Equal(t, "", all[2].AuthGroup)
+
+	// The delegation is deleted along with the identity.
+	_, err = tx.Exec(`DELETE FROM identities WHERE id = 2`)
+	require.NoError(t, err)
+
+	all, err = GetAllIdentityDelegations(ctx, tx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
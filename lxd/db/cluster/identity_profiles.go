@@ -0,0 +1,102 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+)
+
+// IdentityProfile holds the settings that an identity manages itself.
+type IdentityProfile struct {
+	IdentityID  int
+	DisplayName string
+
+	// SessionsRevokedAt is the date before which the sessions and tokens of the identity are no longer accepted.
+	SessionsRevokedAt sql.NullTime
+}
+
+// GetIdentityProfile returns the profile of the identity with the given ID. An empty profile is returned if the
+// identity never set up its profile.
+func GetIdentityProfile(ctx context.Context, tx *sql.Tx, identityID int) (*IdentityProfile, error) {
+	profiles, err := getIdentityProfiles(ctx, tx, &identityID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[identityID]
+	if !ok {
+		return &IdentityProfile{IdentityID: identityID}, nil
+	}
+
+	return &profile, nil
+}
+
+// GetAllIdentityProfiles returns a map of identity ID to the profile of the identity, for the identities that set
+// up their profile.
+func GetAllIdentityProfiles(ctx context.Context, tx *sql.Tx) (map[int]IdentityProfile, error) {
+	return getIdentityProfiles(ctx, tx, nil)
+}
+
+func getIdentityProfiles(ctx context.Context, tx *sql.Tx, identityID *int) (map[int]IdentityProfile, error) {
+	stmt := `
+SELECT identity_id, display_name, sessions_revoked_at
+  FROM identities_profiles`
+
+	var args []any
+	if identityID != nil {
+		stmt += `
+  WHERE identity_id = ?`
+		args = append(args, *identityID)
+	}
+
+	profiles := make(map[int]IdentityProfile)
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		p := IdentityProfile{}
+
+		err := scan(&p.IdentityID, &p.DisplayName, &p.SessionsRevokedAt)
+		if err != nil {
+			return err
+		}
+
+		profiles[p.IdentityID] = p
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"identities_profiles\" table: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// SetIdentityDisplayName sets the display name of the identity with the given ID.
+func SetIdentityDisplayName(ctx context.Context, tx *sql.Tx, identityID int, displayName string) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO identities_profiles (identity_id, display_name) VALUES (?, ?)
+  ON CONFLICT (identity_id) DO UPDATE SET display_name = excluded.display_name
+`, identityID, displayName)
+	if err != nil {
+		return fmt.Errorf("Failed to set display name of identity: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeIdentitySessions records that the sessions and tokens of the identity with the given ID that were started
+// before the given date are revoked.
+func RevokeIdentitySessions(ctx context.Context, tx *sql.Tx, identityID int, date time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO identities_profiles (identity_id, sessions_revoked_at) VALUES (?, ?)
+  ON CONFLICT (identity_id) DO UPDATE SET sessions_revoked_at = excluded.sessions_revoked_at
+`, identityID, date.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed to revoke sessions of identity: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityProfiles(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`INSERT INTO identities (id, auth_method, type, identifier, name, metadata) VALUES (1, 1, 1, 'id1', 'id1', '{}'), (2, 1, 1, 'id2', 'id2', '{}');`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	date := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	// Identities that never set up their profile have an empty one.
+	profile, err := GetIdentityProfile(ctx, tx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &IdentityProfile{IdentityID: 1}, profile)
+
+	err = SetIdentityDisplayName(ctx, tx, 1, "Jane")
+	require.NoError(t, err)
+
+	err = RevokeIdentitySessions(ctx, tx, 1, date)
+	require.NoError(t, err)
+
+	// Revoking the sessions keeps the display name and the other way around.
+	err = SetIdentityDisplayName(ctx, tx, 1, "Jane Doe")
+	require.NoError(t, err)
+
+	profile, err = GetIdentityProfile(ctx, tx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", profile.DisplayName)
+	assert.True(t, profile.SessionsRevokedAt.Valid)
+	assert.True(t, profile.SessionsRevokedAt.Time.Equal(date))
+
+	err = RevokeIdentitySessions(ctx, tx, 1, date.Add(time.Hour))
+	require.NoError(t, err)
+
+	err = RevokeIdentitySessions(ctx, tx, 2, date)
+	require.NoError(t, err)
+
+	profiles, err := GetAllIdentityProfiles(ctx, tx)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "Jane Doe", profiles[1].DisplayName)
+	assert.True(t, profiles[1].SessionsRevokedAt.Time.Equal(date.Add(time.Hour)))
+	assert.Equal(t, "", profiles[2].DisplayName)
+	assert.True(t, profiles[2].SessionsRevokedAt.Time.Equal(date))
+}
@@ -0,0 +1,88 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db/query"
+)
+
+// CreateInstanceLabels adds the given labels to the instance with the given ID.
+func CreateInstanceLabels(ctx context.Context, tx *sql.Tx, instanceID int64, labels map[string]string) error {
+	for key, value := range labels {
+		_, err := tx.ExecContext(ctx, "INSERT INTO instances_labels (instance_id, key, value) VALUES (?, ?, ?)", instanceID, key, value)
+		if err != nil {
+			return fmt.Errorf("Insert failed for \"instances_labels\" table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateInstanceLabels replaces the labels of the instance with the given ID.
+func UpdateInstanceLabels(ctx context.Context, tx *sql.Tx, instanceID int64, labels map[string]string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM instances_labels WHERE instance_id = ?", instanceID)
+	if err != nil {
+		return fmt.Errorf("Delete entry for \"instances_labels\" failed: %w", err)
+	}
+
+	return CreateInstanceLabels(ctx, tx, instanceID, labels)
+}
+
+// GetInstanceIDsWithLabels returns the IDs of the instances having all the given labels.
+func GetInstanceIDsWithLabels(ctx context.Context, tx *sql.Tx, labels map[string]string) ([]int, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("At least one label is required")
+	}
+
+	conds := make([]string, 0, len(labels))
+	args := make([]any, 0, len(labels)*2+1)
+	for key, value := range labels {
+		conds = append(conds, "(key = ? AND value = ?)")
+		args = append(args, key, value)
+	}
+
+	args = append(args, len(labels))
+
+	// Each label matches a different row, so the instances having them all match as many rows as there are labels.
+	stmt := fmt.Sprintf("SELECT instance_id FROM instances_labels WHERE %s GROUP BY instance_id HAVING COUNT(*) = ?", strings.Join(conds, " OR "))
+
+	return query.SelectIntegers(ctx, tx, stmt, args...)
+}
+
+// GetInstanceMembersWithLabel returns the IDs of the cluster members of the instances of the project having the
+// given label, indexed by instance name.
+func GetInstanceMembersWithLabel(ctx context.Context, tx *sql.Tx, projectName string, key string, value string) (map[string]int64, error) {
+	stmt := `
+SELECT instances.name, instances.node_id
+  FROM instances_labels
+  JOIN instances ON instances.id = instances_labels.instance_id
+  JOIN projects ON projects.id = instances.project_id
+ WHERE projects.name = ? AND instances_labels.key = ? AND instances_labels.value = ?
+`
+
+	members := map[string]int64{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		var name string
+		var memberID int64
+
+		err := scan(&name, &memberID)
+		if err != nil {
+			return err
+		}
+
+		members[name] = memberID
+
+		return nil
+	}, projectName, key, value)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching instances with label %q: %w", key, err)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInstanceMembersWithLabel(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`
+INSERT INTO nodes (id, name, address, schema, api_extensions, arch, description) VALUES (1, 'n1', '10.0.0.1:8443', 1, 1, 1, ''), (2, 'n2', '10.0.0.2:8443', 1, 1, 1, '');
+INSERT INTO projects (id, name, description) VALUES (1, 'default', ''), (2, 'p1', '');
+INSERT INTO instances (id, node_id, name, architecture, type, project_id, description) VALUES
+  (1, 1, 'web1', 1, 0, 1, ''),
+  (2, 2, 'web2', 1, 0, 1, ''),
+  (3, 2, 'db1', 1, 0, 1, ''),
+  (4, 1, 'web1', 1, 0, 2, '');
+INSERT INTO instances_labels (instance_id, key, value) VALUES
+  (1, 'app', 'web'),
+  (2, 'app', 'web'),
+  (3, 'app', 'db'),
+  (4, 'app', 'web');
+`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	members, err := GetInstanceMembersWithLabel(context.Background(), tx, "default", "app", "web")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"web1": 1, "web2": 2}, members)
+
+	members, err = GetInstanceMembersWithLabel(context.Background(), tx, "p1", "app", "web")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"web1": 1}, members)
+
+	members, err = GetInstanceMembersWithLabel(context.Background(), tx, "default", "app", "cache")
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
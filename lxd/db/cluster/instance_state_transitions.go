@@ -0,0 +1,122 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+)
+
+// InstanceStateTransition is a change of the status of an instance.
+type InstanceStateTransition struct {
+	Status    string
+	Action    string
+	Timestamp time.Time
+}
+
+// CreateInstanceStateTransition records a change of the status of the given instance.
+func CreateInstanceStateTransition(ctx context.Context, tx *sql.Tx, project string, name string, transition InstanceStateTransition) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO instances_state_transitions (instance_id, status, action, timestamp)
+  SELECT instances.id, ?, ?, ?
+    FROM instances
+    JOIN projects ON projects.id = instances.project_id
+    WHERE projects.name = ? AND instances.name = ?
+`, transition.Status, transition.Action, transition.Timestamp.UTC(), project, name)
+	if err != nil {
+		return fmt.Errorf("Insert failed for \"instances_state_transitions\" table: %w", err)
+	}
+
+	return nil
+}
+
+// GetInstanceStateTransitions returns the changes of the status of the given instance since the given time, from
+// the oldest to the newest. The last change before that time, if any, is included first so that the status of the
+// instance at the start of the range is known.
+func GetInstanceStateTransitions(ctx context.Context, tx *sql.Tx, instanceID int64, since time.Time) ([]InstanceStateTransition, error) {
+	stmt := `
+SELECT status, action, timestamp FROM instances_state_transitions
+  WHERE instance_id = ? AND timestamp >= coalesce(
+    (SELECT max(timestamp) FROM instances_state_transitions WHERE instance_id = ? AND timestamp < ?), ?)
+  ORDER BY timestamp, id
+`
+
+	transitions := []InstanceStateTransition{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		t := InstanceStateTransition{}
+
+		err := scan(&t.Status, &t.Action, &t.Timestamp)
+		if err != nil {
+			return err
+		}
+
+		transitions = append(transitions, t)
+
+		return nil
+	}, instanceID, instanceID, since.UTC(), since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"instances_state_transitions\" table: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// GetInstancesLastStatus returns the last recorded status of the instances of the given cluster member, keyed by
+// project and instance name.
+func GetInstancesLastStatus(ctx context.Context, tx *sql.Tx, nodeID int64) (map[string]map[string]string, error) {
+	stmt := `
+SELECT projects.name, instances.name, instances_state_transitions.status
+  FROM instances_state_transitions
+  JOIN instances ON instances.id = instances_state_transitions.instance_id
+  JOIN projects ON projects.id = instances.project_id
+  WHERE instances.node_id = ? AND instances_state_transitions.id = (
+    SELECT latest.id FROM instances_state_transitions AS latest
+      WHERE latest.instance_id = instances_state_transitions.instance_id
+      ORDER BY latest.timestamp DESC, latest.id DESC LIMIT 1)
+`
+
+	statuses := map[string]map[string]string{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		var project, name, status string
+
+		err := scan(&project, &name, &status)
+		if err != nil {
+			return err
+		}
+
+		if statuses[project] == nil {
+			statuses[project] = map[string]string{}
+		}
+
+		statuses[project][name] = status
+
+		return nil
+	}, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"instances_state_transitions\" table: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// DeleteInstanceStateTransitionsBefore removes the changes of status recorded before the given time, except the last
+// one of each instance which is kept as the status of the instance at that time.
+func DeleteInstanceStateTransitionsBefore(ctx context.Context, tx *sql.Tx, before time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+DELETE FROM instances_state_transitions
+  WHERE timestamp < ? AND EXISTS (
+    SELECT 1 FROM instances_state_transitions AS later
+      WHERE later.instance_id = instances_state_transitions.instance_id AND later.timestamp < ?
+        AND (later.timestamp > instances_state_transitions.timestamp
+          OR (later.timestamp = instances_state_transitions.timestamp AND later.id > instances_state_transitions.id)))
+`, before.UTC(), before.UTC())
+	if err != nil {
+		return fmt.Errorf("Delete entries for \"instances_state_transitions\" failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceStateTransitions(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`
+INSERT INTO nodes (id, name, address, schema, api_extensions, arch, description) VALUES (1, 'n1', '10.0.0.1:8443', 1, 1, 1, ''), (2, 'n2', '10.0.0.2:8443', 1, 1, 1, '');
+INSERT INTO projects (id, name, description) VALUES (1, 'default', ''), (2, 'p1', '');
+INSERT INTO instances (id, node_id, name, architecture, type, project_id, description) VALUES
+  (1, 1, 'c1', 1, 0, 1, ''),
+  (2, 2, 'c2', 1, 0, 1, ''),
+  (3, 1, 'c1', 1, 0, 2, '');
+`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, transition := range []struct {
+		project    string
+		name       string
+		transition InstanceStateTransition
+	}{
+		{"default", "c1", InstanceStateTransition{Status: "Running", Action: "instance-started", Timestamp: now.Add(-3 * time.Hour)}},
+		{"default", "c1", InstanceStateTransition{Status: "Stopped", Action: "instance-stopped", Timestamp: now.Add(-2 * time.Hour)}},
+		{"default", "c1", InstanceStateTransition{Status: "Running", Action: "instance-started", Timestamp: now.Add(-time.Hour)}},
+		{"default", "c2", InstanceStateTransition{Status: "Running", Timestamp: now.Add(-time.Hour)}},
+		{"p1", "c1", InstanceStateTransition{Status: "Frozen", Action: "instance-paused", Timestamp: now.Add(-time.Hour)}},
+		{"p1", "missing", InstanceStateTransition{Status: "Running", Timestamp: now}},
+	} {
+		require.NoError(t, CreateInstanceStateTransition(ctx, tx, transition.project, transition.name, transition.transition))
+	}
+
+	// The changes since the given time come after the last change before it.
+	transitions, err := GetInstanceStateTransitions(ctx, tx, 1, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "Stopped", transitions[0].Status)
+	assert.Equal(t, "instance-stopped", transitions[0].Action)
+	assert.True(t, transitions[0].Timestamp.Equal(now.Add(-2*time.Hour)))
+	assert.Equal(t, "Running", transitions[1].Status)
+
+	transitions, err = GetInstanceStateTransitions(ctx, tx, 1, now.Add(-4*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, transitions, 3)
+
+	// The last status of the instances of each member.
+	statuses, err := GetInstancesLastStatus(ctx, tx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"default": {"c1": "Running"}, "p1": {"c1": "Frozen"}}, statuses)
+
+	statuses, err = GetInstancesLastStatus(ctx, tx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"default": {"c2": "Running"}}, statuses)
+
+	// The last change before the given time is kept as the status of the instance at that time.
+	require.NoError(t, DeleteInstanceStateTransitionsBefore(ctx, tx, now.Add(-90*time.Minute)))
+
+	transitions, err = GetInstanceStateTransitions(ctx, tx, 1, now.Add(-4*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "Stopped", transitions[0].Status)
+	assert.Equal(t, "Running", transitions[1].Status)
+
+	transitions, err = GetInstanceStateTransitions(ctx, tx, 2, now.Add(-4*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, transitions, 1)
+}
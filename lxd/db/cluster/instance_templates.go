@@ -0,0 +1,86 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t instance_templates.mapper.go
+//go:generate mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//go:generate mapper stmt -e instance_template objects table=instance_templates
+//go:generate mapper stmt -e instance_template objects-by-Project table=instance_templates
+//go:generate mapper stmt -e instance_template objects-by-Project-and-Name table=instance_templates
+//go:generate mapper stmt -e instance_template id table=instance_templates
+//go:generate mapper stmt -e instance_template create table=instance_templates
+//go:generate mapper stmt -e instance_template rename table=instance_templates
+//go:generate mapper stmt -e instance_template update table=instance_templates
+//go:generate mapper stmt -e instance_template delete-by-Project-and-Name table=instance_templates
+//
+//go:generate mapper method -i -e instance_template ID table=instance_templates
+//go:generate mapper method -i -e instance_template GetMany table=instance_templates
+//go:generate mapper method -i -e instance_template GetOne table=instance_templates
+//go:generate mapper method -i -e instance_template Create table=instance_templates
+//go:generate mapper method -i -e instance_template Rename table=instance_templates
+//go:generate mapper method -i -e instance_template Update table=instance_templates
+//go:generate mapper method -i -e instance_template DeleteOne-by-Project-and-Name table=instance_templates
+//go:generate goimports -w instance_templates.mapper.go
+//go:generate goimports -w instance_templates.interface.mapper.go
+
+// InstanceTemplate is a value object holding db-related details about an instance template.
+// The instance definition (everything but the name and description) is stored JSON encoded.
+type InstanceTemplate struct {
+	ID          int
+	ProjectID   int    `db:"omit=create,update"`
+	Project     string `db:"primary=yes&join=projects.name"`
+	Name        string `db:"primary=yes"`
+	Description string
+	Definition  string
+}
+
+// InstanceTemplateFilter specifies potential query parameter fields.
+type InstanceTemplateFilter struct {
+	Project *string
+	Name    *string
+}
+
+// ToAPI converts the database InstanceTemplate to API type.
+func (t *InstanceTemplate) ToAPI() (*api.InstanceTemplate, error) {
+	template := api.InstanceTemplate{
+		Name:    t.Name,
+		Project: t.Project,
+	}
+
+	err := json.Unmarshal([]byte(t.Definition), &template.InstanceTemplatePut)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing definition of instance template %q: %w", t.Name, err)
+	}
+
+	template.Description = t.Description
+
+	return &template, nil
+}
+
+// InstanceTemplateFromAPI converts the API instance template fields to a database InstanceTemplate.
+func InstanceTemplateFromAPI(projectName string, name string, put api.InstanceTemplatePut) (*InstanceTemplate, error) {
+	definition := put
+	definition.Description = ""
+
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding definition of instance template %q: %w", name, err)
+	}
+
+	return &InstanceTemplate{
+		Project:     projectName,
+		Name:        name,
+		Description: put.Description,
+		Definition:  string(data),
+	}, nil
+}
@@ -0,0 +1,39 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+)
+
+// InstanceTemplateGenerated is an interface of generated methods for InstanceTemplate.
+type InstanceTemplateGenerated interface {
+	// GetInstanceTemplateID return the ID of the instance_template with the given key.
+	// generator: instance_template ID
+	GetInstanceTemplateID(ctx context.Context, tx *sql.Tx, project string, name string) (int64, error)
+
+	// GetInstanceTemplates returns all available instance_templates.
+	// generator: instance_template GetMany
+	GetInstanceTemplates(ctx context.Context, tx *sql.Tx, filters ...InstanceTemplateFilter) ([]InstanceTemplate, error)
+
+	// GetInstanceTemplate returns the instance_template with the given key.
+	// generator: instance_template GetOne
+	GetInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string) (*InstanceTemplate, error)
+
+	// CreateInstanceTemplate adds a new instance_template to the database.
+	// generator: instance_template Create
+	CreateInstanceTemplate(ctx context.Context, tx *sql.Tx, object InstanceTemplate) (int64, error)
+
+	// RenameInstanceTemplate renames the instance_template matching the given key parameters.
+	// generator: instance_template Rename
+	RenameInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string, to string) error
+
+	// UpdateInstanceTemplate updates the instance_template matching the given key parameters.
+	// generator: instance_template Update
+	UpdateInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string, object InstanceTemplate) error
+
+	// DeleteInstanceTemplate deletes the instance_template matching the given key parameters.
+	// generator: instance_template DeleteOne-by-Project-and-Name
+	DeleteInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string) error
+}
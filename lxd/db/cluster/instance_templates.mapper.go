@@ -0,0 +1,381 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+// The code below was generated by lxd-generate - DO NOT EDIT!
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+var _ = api.ServerEnvironment{}
+
+var instanceTemplateObjects = RegisterStmt(`
+SELECT instance_templates.id, instance_templates.project_id, projects.name AS project, instance_templates.name, instance_templates.description, instance_templates.definition
+  FROM instance_templates
+  JOIN projects ON instance_templates.project_id = projects.id
+  ORDER BY projects.id, instance_templates.name
+`)
+
+var instanceTemplateObjectsByProject = RegisterStmt(`
+SELECT instance_templates.id, instance_templates.project_id, projects.name AS project, instance_templates.name, instance_templates.description, instance_templates.definition
+  FROM instance_templates
+  JOIN projects ON instance_templates.project_id = projects.id
+  WHERE ( project = ? )
+  ORDER BY projects.id, instance_templates.name
+`)
+
+var instanceTemplateObjectsByProjectAndName = RegisterStmt(`
+SELECT instance_templates.id, instance_templates.project_id, projects.name AS project, instance_templates.name, instance_templates.description, instance_templates.definition
+  FROM instance_templates
+  JOIN projects ON instance_templates.project_id = projects.id
+  WHERE ( project = ? AND instance_templates.name = ? )
+  ORDER BY projects.id, instance_templates.name
+`)
+
+var instanceTemplateID = RegisterStmt(`
+SELECT instance_templates.id FROM instance_templates
+  JOIN projects ON instance_templates.project_id = projects.id
+  WHERE projects.name = ? AND instance_templates.name = ?
+`)
+
+var instanceTemplateCreate = RegisterStmt(`
+INSERT INTO instance_templates (project_id, name, description, definition)
+  VALUES ((SELECT projects.id FROM projects WHERE projects.name = ?), ?, ?, ?)
+`)
+
+var instanceTemplateRename = RegisterStmt(`
+UPDATE instance_templates SET name = ? WHERE project_id = (SELECT projects.id FROM projects WHERE projects.name = ?) AND name = ?
+`)
+
+var instanceTemplateUpdate = RegisterStmt(`
+UPDATE instance_templates
+  SET project_id = (SELECT projects.id FROM projects WHERE projects.name = ?), name = ?, description = ?, definition = ?
+ WHERE id = ?
+`)
+
+var instanceTemplateDeleteByProjectAndName = RegisterStmt(`
+DELETE FROM instance_templates WHERE project_id = (SELECT projects.id FROM projects WHERE projects.name = ?) AND name = ?
+`)
+
+// GetInstanceTemplateID return the ID of the instance_template with the given key.
+// generator: instance_template ID
+func GetInstanceTemplateID(ctx context.Context, tx *sql.Tx, project string, name string) (int64, error) {
+	stmt, err := Stmt(tx, instanceTemplateID)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"instanceTemplateID\" prepared statement: %w", err)
+	}
+
+	row := stmt.QueryRowContext(ctx, project, name)
+	var id int64
+	err = row.Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "InstanceTemplate not found")
+		}
+
+		return -1, fmt.Errorf("Failed to get \"instance_templates\" ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// instanceTemplateColumns returns a string of column names to be used with a SELECT statement for the entity.
+// Use this function when building statements to retrieve database entries matching the InstanceTemplate entity.
+func instanceTemplateColumns() string {
+	return "instance_templates.id, instance_templates.project_id, projects.name AS project, instance_templates.name, instance_templates.description, instance_templates.definition"
+}
+
+// getInstanceTemplates can be used to run handwritten sql.Stmts to return a slice of objects.
+func getInstanceTemplates(ctx context.Context, stmt *sql.Stmt, args ...any) ([]InstanceTemplate, error) {
+	objects := make([]InstanceTemplate, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		i := InstanceTemplate{}
+		err := scan(&i.ID, &i.ProjectID, &i.Project, &i.Name, &i.Description, &i.Definition)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, i)
+
+		return nil
+	}
+
+	err := query.SelectObjects(ctx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"instance_templates\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// getInstanceTemplatesRaw can be used to run handwritten query strings to return a slice of objects.
+func getInstanceTemplatesRaw(ctx context.Context, tx *sql.Tx, sql string, args ...any) ([]InstanceTemplate, error) {
+	objects := make([]InstanceTemplate, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		i := InstanceTemplate{}
+		err := scan(&i.ID, &i.ProjectID, &i.Project, &i.Name, &i.Description, &i.Definition)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, i)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, sql, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"instance_templates\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetInstanceTemplates returns all available instance_templates.
+// generator: instance_template GetMany
+func GetInstanceTemplates(ctx context.Context, tx *sql.Tx, filters ...InstanceTemplateFilter) ([]InstanceTemplate, error) {
+	var err error
+
+	// Result slice.
+	objects := make([]InstanceTemplate, 0)
+
+	// Pick the prepared statement and arguments to use based on active criteria.
+	var sqlStmt *sql.Stmt
+	args := []any{}
+	queryParts := [2]string{}
+
+	if len(filters) == 0 {
+		sqlStmt, err = Stmt(tx, instanceTemplateObjects)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get \"instanceTemplateObjects\" prepared statement: %w", err)
+		}
+	}
+
+	for i, filter := range filters {
+		if filter.Project != nil && filter.Name != nil {
+			args = append(args, []any{filter.Project, filter.Name}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(tx, instanceTemplateObjectsByProjectAndName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"instanceTemplateObjectsByProjectAndName\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(instanceTemplateObjectsByProjectAndName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"instanceTemplateObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.Project != nil && filter.Name == nil {
+			args = append(args, []any{filter.Project}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(tx, instanceTemplateObjectsByProject)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"instanceTemplateObjectsByProject\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(instanceTemplateObjectsByProject)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"instanceTemplateObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.Project == nil && filter.Name == nil {
+			return nil, errors.New("Cannot filter on empty InstanceTemplateFilter")
+		} else {
+			return nil, errors.New("No statement exists for the given Filter")
+		}
+	}
+
+	// Select.
+	if sqlStmt != nil {
+		objects, err = getInstanceTemplates(ctx, sqlStmt, args...)
+	} else {
+		queryStr := strings.Join(queryParts[:], "ORDER BY")
+		objects, err = getInstanceTemplatesRaw(ctx, tx, queryStr, args...)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"instance_templates\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetInstanceTemplate returns the instance_template with the given key.
+// generator: instance_template GetOne
+func GetInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string) (*InstanceTemplate, error) {
+	filter := InstanceTemplateFilter{}
+	filter.Project = &project
+	filter.Name = &name
+
+	objects, err := GetInstanceTemplates(ctx, tx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"instance_templates\" table: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return nil, api.StatusErrorf(http.StatusNotFound, "InstanceTemplate not found")
+	case 1:
+		return &objects[0], nil
+	default:
+		return nil, errors.New("More than one \"instance_templates\" entry matches")
+	}
+}
+
+// CreateInstanceTemplate adds a new instance_template to the database.
+// generator: instance_template Create
+func CreateInstanceTemplate(ctx context.Context, tx *sql.Tx, object InstanceTemplate) (int64, error) {
+	args := make([]any, 4)
+
+	// Populate the statement arguments.
+	args[0] = object.Project
+	args[1] = object.Name
+	args[2] = object.Description
+	args[3] = object.Definition
+
+	// Prepared statement to use.
+	stmt, err := Stmt(tx, instanceTemplateCreate)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"instanceTemplateCreate\" prepared statement: %w", err)
+	}
+
+	// Execute the statement.
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return -1, api.NewStatusError(http.StatusConflict, "This \"instance_templates\" entry already exists")
+		}
+
+		return -1, fmt.Errorf("Failed to create \"instance_templates\" entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to fetch \"instance_templates\" entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// RenameInstanceTemplate renames the instance_template matching the given key parameters.
+// generator: instance_template Rename
+func RenameInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string, to string) error {
+	stmt, err := Stmt(tx, instanceTemplateRename)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"instanceTemplateRename\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, to, project, name)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return api.NewStatusError(http.StatusConflict, "A \"instance_templates\" entry already exists with this name")
+		}
+
+		return fmt.Errorf("Rename InstanceTemplate failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows failed: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query affected %d rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// UpdateInstanceTemplate updates the instance_template matching the given key parameters.
+// generator: instance_template Update
+func UpdateInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string, object InstanceTemplate) error {
+	id, err := GetInstanceTemplateID(ctx, tx, project, name)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := Stmt(tx, instanceTemplateUpdate)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"instanceTemplateUpdate\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, object.Project, object.Name, object.Description, object.Definition, id)
+	if err != nil {
+		if query.IsConflictErr(err) {
+			return api.NewStatusError(http.StatusConflict, "A \"instance_templates\" entry already exists with these properties")
+		}
+
+		return fmt.Errorf("Update \"instance_templates\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// DeleteInstanceTemplate deletes the instance_template matching the given key parameters.
+// generator: instance_template DeleteOne-by-Project-and-Name
+func DeleteInstanceTemplate(ctx context.Context, tx *sql.Tx, project string, name string) error {
+	stmt, err := Stmt(tx, instanceTemplateDeleteByProjectAndName)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"instanceTemplateDeleteByProjectAndName\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.ExecContext(ctx, project, name)
+	if err != nil {
+		return fmt.Errorf("Delete \"instance_templates\": %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "InstanceTemplate not found")
+	} else if n > 1 {
+		return fmt.Errorf("Query deleted %d InstanceTemplate rows instead of 1", n)
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"maps"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db/query"
@@ -22,6 +23,22 @@ type Permission struct {
 	Entitlement auth.Entitlement
 	EntityType  EntityType
 	EntityID    int
+	ExpiryDate  sql.NullTime
+}
+
+// ToAPI converts the Permission to an api.Permission, given the URL of its entity.
+func (p Permission) ToAPI(entityURL *api.URL) api.Permission {
+	permission := api.Permission{
+		EntityType:      string(p.EntityType),
+		EntityReference: entityURL.String(),
+		Entitlement:     string(p.Entitlement),
+	}
+
+	if p.ExpiryDate.Valid {
+		permission.ExpiresAt = &p.ExpiryDate.Time
+	}
+
+	return permission
 }
 
 // GetPermissionEntityURLs accepts a slice of Permission as input. The input Permission slice may include permissions
@@ -113,7 +130,8 @@ func GetPermissionEntityURLs(ctx context.Context, tx *sql.Tx, permissions []Perm
 	return validPermissions, entityURLs, nil
 }
 
-// GetDistinctPermissionsByGroupNames gets all distinct permissions that the groups with the given names have been granted.
+// GetDistinctPermissionsByGroupNames gets all distinct unexpired permissions that the groups with the given names have been granted,
+// either directly or through roles.
 func GetDistinctPermissionsByGroupNames(ctx context.Context, tx *sql.Tx, groupNames []string) ([]Permission, error) {
 	if len(groupNames) == 0 {
 		return nil, nil
@@ -125,10 +143,13 @@ func GetDistinctPermissionsByGroupNames(ctx context.Context, tx *sql.Tx, groupNa
 	}
 
 	q := `
-SELECT DISTINCT auth_groups_permissions.entitlement, auth_groups_permissions.entity_type, auth_groups_permissions.entity_id
-FROM auth_groups_permissions
-JOIN auth_groups ON auth_groups_permissions.auth_group_id = auth_groups.id
-WHERE auth_groups.name IN ` + query.Params(len(groupNames))
+SELECT DISTINCT auth_groups_effective_permissions.entitlement, auth_groups_effective_permissions.entity_type, auth_groups_effective_permissions.entity_id
+FROM auth_groups_effective_permissions
+JOIN auth_groups ON auth_groups_effective_permissions.auth_group_id = auth_groups.id
+WHERE auth_groups.name IN ` + query.Params(len(groupNames)) + `
+AND (auth_groups_effective_permissions.expiry_date IS NULL OR auth_groups_effective_permissions.expiry_date > ?)`
+
+	args = append(args, time.Now())
 
 	rows, err := tx.QueryContext(ctx, q, args...)
 	if err != nil {
@@ -149,15 +170,17 @@ WHERE auth_groups.name IN ` + query.Params(len(groupNames))
 	return permissions, nil
 }
 
-// GetGroupPermissions returns a map of group name to slice of permissions. This is used by the OpenFGADatastore
-// implementation. It is pre-loaded into an openfga.RequestCache to reduce the total number of queries.
+// GetGroupPermissions returns a map of group name to slice of unexpired permissions, including those granted through
+// roles. This is used by the OpenFGADatastore implementation. It is pre-loaded into an openfga.RequestCache to reduce
+// the total number of queries.
 func GetGroupPermissions(ctx context.Context, tx *sql.Tx) (map[string][]Permission, error) {
 	q := `
-SELECT auth_groups.name, auth_groups_permissions.entity_id, auth_groups_permissions.entity_type, auth_groups_permissions.entitlement
+SELECT DISTINCT auth_groups.name, auth_groups_effective_permissions.entity_id, auth_groups_effective_permissions.entity_type, auth_groups_effective_permissions.entitlement
 FROM auth_groups
-JOIN auth_groups_permissions ON auth_groups_permissions.auth_group_id = auth_groups.id
+JOIN auth_groups_effective_permissions ON auth_groups_effective_permissions.auth_group_id = auth_groups.id
+WHERE auth_groups_effective_permissions.expiry_date IS NULL OR auth_groups_effective_permissions.expiry_date > ?
 `
-	rows, err := tx.QueryContext(ctx, q)
+	rows, err := tx.QueryContext(ctx, q, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to query group permissions: %w", err)
 	}
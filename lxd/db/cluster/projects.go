@@ -92,6 +92,11 @@ func (p *Project) ToAPI(ctx context.Context, tx *sql.Tx) (*api.Project, error) {
 		return nil, fmt.Errorf("Failed loading project config: %w", err)
 	}
 
+	apiProject.Status, err = GetProjectStatus(ctx, tx, p.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading project status: %w", err)
+	}
+
 	return apiProject, nil
 }
 
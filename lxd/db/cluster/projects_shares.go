@@ -0,0 +1,189 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// ProjectShareEntityTypes are the types of the entities that a project can share with other projects.
+var ProjectShareEntityTypes = []entity.Type{entity.TypeImage, entity.TypeNetwork, entity.TypeStorageVolume}
+
+// ProjectShare is the database representation of an api.ProjectShare.
+type ProjectShare struct {
+	ID          int
+	EntityType  EntityType
+	EntityID    int
+	ProjectID   int
+	ProjectName string
+	Access      string
+}
+
+// ToAPI converts the ProjectShare to an api.ProjectShare, given the URL of its entity.
+func (s ProjectShare) ToAPI(entityURL *api.URL) api.ProjectShare {
+	return api.ProjectShare{
+		EntityType:      string(s.EntityType),
+		EntityReference: entityURL.String(),
+		Project:         s.ProjectName,
+		Access:          s.Access,
+	}
+}
+
+// GetAllProjectShares returns all the shares of all projects.
+func GetAllProjectShares(ctx context.Context, tx *sql.Tx) ([]ProjectShare, error) {
+	return getProjectShares(ctx, tx, "", nil)
+}
+
+// GetEntityProjectShares returns the shares of the entity with the given type and ID.
+func GetEntityProjectShares(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityID int) ([]ProjectShare, error) {
+	return getProjectShares(ctx, tx, "entity_type = ? AND entity_id = ?", []any{EntityType(entityType), entityID})
+}
+
+// GetReceivedProjectShares returns the shares of entities of other projects with the given project.
+func GetReceivedProjectShares(ctx context.Context, tx *sql.Tx, projectName string) ([]ProjectShare, error) {
+	return getProjectShares(ctx, tx, "projects.name = ?", []any{projectName})
+}
+
+func getProjectShares(ctx context.Context, tx *sql.Tx, where string, args []any) ([]ProjectShare, error) {
+	stmt := `
+SELECT projects_shares.id, projects_shares.entity_type, projects_shares.entity_id, projects_shares.project_id, projects.name, projects_shares.access
+FROM projects_shares
+JOIN projects ON projects_shares.project_id = projects.id`
+
+	if where != "" {
+		stmt += `
+WHERE ` + where
+	}
+
+	stmt += `
+ORDER BY projects_shares.entity_type, projects_shares.entity_id, projects.name`
+
+	shares := []ProjectShare{}
+	dest := func(scan func(dest ...any) error) error {
+		s := ProjectShare{}
+		err := scan(&s.ID, &s.EntityType, &s.EntityID, &s.ProjectID, &s.ProjectName, &s.Access)
+		if err != nil {
+			return err
+		}
+
+		shares = append(shares, s)
+
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get project shares: %w", err)
+	}
+
+	return shares, nil
+}
+
+// GetProjectShares returns the shares of the entities of the given project, along with a map of entity type to entity
+// ID to the URL of the entities of the project that can be shared.
+func GetProjectShares(ctx context.Context, tx *sql.Tx, projectName string) ([]ProjectShare, map[entity.Type]map[int]*api.URL, error) {
+	entityURLs, err := GetEntityURLs(ctx, tx, projectName, ProjectShareEntityTypes...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allShares, err := GetAllProjectShares(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shares := make([]ProjectShare, 0, len(allShares))
+	for _, s := range allShares {
+		_, ok := entityURLs[entity.Type(s.EntityType)][s.EntityID]
+		if ok {
+			shares = append(shares, s)
+		}
+	}
+
+	return shares, entityURLs, nil
+}
+
+// SetProjectShares replaces the shares of the entities of the given project.
+func SetProjectShares(ctx context.Context, tx *sql.Tx, projectName string, shares []ProjectShare) error {
+	existing, _, err := GetProjectShares(ctx, tx, projectName)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range existing {
+		_, err := tx.ExecContext(ctx, `DELETE FROM projects_shares WHERE id = ?`, s.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to delete existing shares of project %q: %w", projectName, err)
+		}
+	}
+
+	for _, s := range shares {
+		_, err := tx.ExecContext(ctx, `INSERT INTO projects_shares (entity_type, entity_id, project_id, access) VALUES (?, ?, ?, ?)`, s.EntityType, s.EntityID, s.ProjectID, s.Access)
+		if err != nil {
+			return fmt.Errorf("Failed to write shares of project %q: %w", projectName, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckProjectShareAttach returns an error unless the entity with the given type and ID is shared with the given
+// project for attaching.
+func CheckProjectShareAttach(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityID int, projectName string) error {
+	shares, err := getProjectShares(ctx, tx, "entity_type = ? AND entity_id = ? AND projects.name = ?", []any{EntityType(entityType), entityID, projectName})
+	if err != nil {
+		return err
+	}
+
+	if len(shares) == 0 {
+		return api.StatusErrorf(http.StatusForbidden, "The %s isn't shared with project %q", entityType, projectName)
+	}
+
+	if shares[0].Access != api.ProjectShareAccessAttach {
+		return api.StatusErrorf(http.StatusForbidden, "The %s is only shared with project %q for reading", entityType, projectName)
+	}
+
+	return nil
+}
+
+// GetSharedProjectsByEntityURL returns a map of entity URL to the names of the projects that the entity is shared with.
+func GetSharedProjectsByEntityURL(ctx context.Context, tx *sql.Tx) (map[string][]string, error) {
+	shares, err := GetAllProjectShares(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedWith := make(map[string][]string)
+	for _, s := range shares {
+		u, err := GetEntityURL(ctx, tx, entity.Type(s.EntityType), s.EntityID)
+		if err != nil {
+			return nil, err
+		}
+
+		sharedWith[u.String()] = append(sharedWith[u.String()], s.ProjectName)
+	}
+
+	return sharedWith, nil
+}
+
+// GetProjectSharesUsedBy returns the URLs of the projects that the entity with the given type and ID is shared with.
+func GetProjectSharesUsedBy(ctx context.Context, tx *sql.Tx, entityType entity.Type, entityID int) ([]string, error) {
+	shares, err := GetEntityProjectShares(ctx, tx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy := make([]string, 0, len(shares))
+	for _, s := range shares {
+		usedBy = append(usedBy, entity.ProjectURL(s.ProjectName).String())
+	}
+
+	return usedBy, nil
+}
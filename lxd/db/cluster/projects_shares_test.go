@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func TestProjectShares(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`
+INSERT INTO projects (id, name, description) VALUES (1, 'default', ''), (2, 'p1', ''), (3, 'p2', ''), (4, 'p3', '');
+INSERT INTO networks (id, project_id, name, description) VALUES (1, 2, 'n1', ''), (2, 2, 'n2', ''), (3, 3, 'n3', '');
+INSERT INTO images (id, fingerprint, filename, size, architecture, upload_date, project_id) VALUES (1, 'abc', 'abc', 1, 1, '2026-10-01', 2);
+`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	networkType := EntityType(entity.TypeNetwork)
+	imageType := EntityType(entity.TypeImage)
+
+	err = SetProjectShares(ctx, tx, "p1", []ProjectShare{
+		{EntityType: networkType, EntityID: 1, ProjectID: 3, Access: api.ProjectShareAccessAttach},
+		{EntityType: networkType, EntityID: 1, ProjectID: 4, Access: api.ProjectShareAccessReadOnly},
+		{EntityType: imageType, EntityID: 1, ProjectID: 3, Access: api.ProjectShareAccessReadOnly},
+	})
+	require.NoError(t, err)
+
+	err = SetProjectShares(ctx, tx, "p2", []ProjectShare{
+		{EntityType: networkType, EntityID: 3, ProjectID: 2, Access: api.ProjectShareAccessAttach},
+	})
+	require.NoError(t, err)
+
+	// The shares are those of the entities of the project, along with the URLs of the entities that can be shared.
+	shares, entityURLs, err := GetProjectShares(ctx, tx, "p1")
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+	assert.Len(t, entityURLs[entity.TypeNetwork], 2)
+	assert.Len(t, entityURLs[entity.TypeImage], 1)
+	assert.Equal(t, api.ProjectShare{EntityType: "network", EntityReference: "/1.0/networks/n1?project=p1", Project: "p2", Access: api.ProjectShareAccessAttach}, shares[1].ToAPI(entityURLs[entity.TypeNetwork][1]))
+
+	received, err := GetReceivedProjectShares(ctx, tx, "p2")
+	require.NoError(t, err)
+	assert.Len(t, received, 2)
+
+	// Only the shares for attaching allow attaching.
+	err = CheckProjectShareAttach(ctx, tx, entity.TypeNetwork, 1, "p2")
+	assert.NoError(t, err)
+
+	err = CheckProjectShareAttach(ctx, tx, entity.TypeNetwork, 1, "p3")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+
+	err = CheckProjectShareAttach(ctx, tx, entity.TypeNetwork, 2, "p2")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+
+	err = CheckProjectShareAttach(ctx, tx, entity.TypeImage, 1, "p2")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+
+	// The projects that the entities are shared with use them.
+	usedBy, err := GetProjectSharesUsedBy(ctx, tx, entity.TypeNetwork, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1.0/projects/p2", "/1.0/projects/p3"}, usedBy)
+
+	sharedWith, err := GetSharedProjectsByEntityURL(ctx, tx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p2", "p3"}, sharedWith["/1.0/networks/n1?project=p1"])
+	assert.Equal(t, []string{"p1"}, sharedWith["/1.0/networks/n3?project=p2"])
+
+	// Setting the shares of a project replaces its shares only.
+	err = SetProjectShares(ctx, tx, "p1", []ProjectShare{
+		{EntityType: networkType, EntityID: 2, ProjectID: 3, Access: api.ProjectShareAccessReadOnly},
+	})
+	require.NoError(t, err)
+
+	shares, _, err = GetProjectShares(ctx, tx, "p1")
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	assert.Equal(t, 2, shares[0].EntityID)
+
+	shares, _, err = GetProjectShares(ctx, tx, "p2")
+	require.NoError(t, err)
+	assert.Len(t, shares, 1)
+}
@@ -0,0 +1,88 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// ProjectStatusChange is a change of the status of a project.
+type ProjectStatusChange struct {
+	Status    string
+	Reason    string
+	Requestor string
+	Protocol  string
+	Date      time.Time
+}
+
+// CreateProjectStatusChange records a change of the status of the given project.
+func CreateProjectStatusChange(ctx context.Context, tx *sql.Tx, project string, change ProjectStatusChange) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO projects_status_changes (project_id, status, reason, requestor, protocol, date)
+  SELECT projects.id, ?, ?, ?, ?, ? FROM projects WHERE projects.name = ?
+`, change.Status, change.Reason, change.Requestor, change.Protocol, change.Date.UTC(), project)
+	if err != nil {
+		return fmt.Errorf("Insert failed for \"projects_status_changes\" table: %w", err)
+	}
+
+	return nil
+}
+
+// GetProjectStatusChanges returns the changes of the status of the given project, from the newest to the oldest.
+func GetProjectStatusChanges(ctx context.Context, tx *sql.Tx, project string) ([]ProjectStatusChange, error) {
+	stmt := `
+SELECT projects_status_changes.status, reason, requestor, protocol, date
+  FROM projects_status_changes
+  JOIN projects ON projects.id = projects_status_changes.project_id
+  WHERE projects.name = ?
+  ORDER BY date DESC, projects_status_changes.id DESC
+`
+
+	changes := []ProjectStatusChange{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		c := ProjectStatusChange{}
+
+		err := scan(&c.Status, &c.Reason, &c.Requestor, &c.Protocol, &c.Date)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, c)
+
+		return nil
+	}, project)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"projects_status_changes\" table: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetProjectStatus returns the current status of the given project, which is active unless it was changed.
+func GetProjectStatus(ctx context.Context, tx *sql.Tx, project string) (string, error) {
+	stmt := `
+SELECT projects_status_changes.status
+  FROM projects_status_changes
+  JOIN projects ON projects.id = projects_status_changes.project_id
+  WHERE projects.name = ?
+  ORDER BY date DESC, projects_status_changes.id DESC
+  LIMIT 1
+`
+
+	statuses, err := query.SelectStrings(ctx, tx, stmt, project)
+	if err != nil {
+		return "", fmt.Errorf("Failed to fetch from \"projects_status_changes\" table: %w", err)
+	}
+
+	if len(statuses) == 0 {
+		return api.ProjectStatusActive, nil
+	}
+
+	return statuses[0], nil
+}
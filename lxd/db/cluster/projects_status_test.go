@@ -0,0 +1,75 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestProjectStatusChanges(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`INSERT INTO projects (id, name, description) VALUES (1, 'default', ''), (2, 'p1', '');`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	date := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	// Projects are active unless their status was changed.
+	status, err := GetProjectStatus(ctx, tx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, api.ProjectStatusActive, status)
+
+	changes, err := GetProjectStatusChanges(ctx, tx, "p1")
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	err = CreateProjectStatusChange(ctx, tx, "p1", ProjectStatusChange{Status: api.ProjectStatusDisabled, Reason: "Offboarded", Requestor: "admin", Protocol: "tls", Date: date})
+	require.NoError(t, err)
+
+	status, err = GetProjectStatus(ctx, tx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, api.ProjectStatusDisabled, status)
+
+	// The changes of other projects don't matter.
+	status, err = GetProjectStatus(ctx, tx, "default")
+	require.NoError(t, err)
+	assert.Equal(t, api.ProjectStatusActive, status)
+
+	err = CreateProjectStatusChange(ctx, tx, "p1", ProjectStatusChange{Status: api.ProjectStatusActive, Reason: "Back", Requestor: "admin", Protocol: "unix", Date: date.Add(time.Hour)})
+	require.NoError(t, err)
+
+	status, err = GetProjectStatus(ctx, tx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, api.ProjectStatusActive, status)
+
+	// The changes are returned from the newest to the oldest.
+	changes, err = GetProjectStatusChanges(ctx, tx, "p1")
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, api.ProjectStatusActive, changes[0].Status)
+	assert.Equal(t, "Back", changes[0].Reason)
+	assert.Equal(t, "unix", changes[0].Protocol)
+	assert.True(t, changes[0].Date.Equal(date.Add(time.Hour)))
+	assert.Equal(t, api.ProjectStatusDisabled, changes[1].Status)
+	assert.Equal(t, "Offboarded", changes[1].Reason)
+	assert.Equal(t, "admin", changes[1].Requestor)
+
+	// Changes made within the same second are ordered by creation.
+	err = CreateProjectStatusChange(ctx, tx, "p1", ProjectStatusChange{Status: api.ProjectStatusDisabled, Date: date.Add(time.Hour)})
+	require.NoError(t, err)
+
+	status, err = GetProjectStatus(ctx, tx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, api.ProjectStatusDisabled, status)
+}
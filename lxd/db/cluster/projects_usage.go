@@ -0,0 +1,92 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+)
+
+// ProjectUsage is the resource usage of a project on a cluster member during the hour starting at PeriodStart.
+type ProjectUsage struct {
+	Project            string
+	PeriodStart        time.Time
+	APICalls           int64
+	InstanceSeconds    int64
+	StorageByteSeconds int64
+	NetworkBytes       int64
+}
+
+// AddProjectsUsage adds the given usage to the usage recorded for the projects on the given cluster member.
+// Usage of projects which don't exist anymore is ignored.
+func AddProjectsUsage(ctx context.Context, tx *sql.Tx, nodeID int64, usage []ProjectUsage) error {
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO projects_usage (project_id, node_id, period_start, api_calls, instance_seconds, storage_byte_seconds, network_bytes)
+  SELECT projects.id, ?, ?, ?, ?, ?, ? FROM projects WHERE projects.name = ?
+  ON CONFLICT (project_id, node_id, period_start) DO UPDATE SET
+    api_calls = api_calls + excluded.api_calls,
+    instance_seconds = instance_seconds + excluded.instance_seconds,
+    storage_byte_seconds = storage_byte_seconds + excluded.storage_byte_seconds,
+    network_bytes = network_bytes + excluded.network_bytes
+`)
+	if err != nil {
+		return fmt.Errorf("Failed to prepare insert into \"projects_usage\" table: %w", err)
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, u := range usage {
+		_, err = stmt.ExecContext(ctx, nodeID, u.PeriodStart.UTC(), u.APICalls, u.InstanceSeconds, u.StorageByteSeconds, u.NetworkBytes, u.Project)
+		if err != nil {
+			return fmt.Errorf("Insert failed for \"projects_usage\" table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetProjectUsage returns the hourly usage of a project, summed over all cluster members, for the hours starting
+// within the given time range, from the oldest to the newest.
+func GetProjectUsage(ctx context.Context, tx *sql.Tx, project string, since time.Time, until time.Time) ([]ProjectUsage, error) {
+	stmt := `
+SELECT projects_usage.period_start, sum(api_calls), sum(instance_seconds), sum(storage_byte_seconds), sum(network_bytes)
+  FROM projects_usage
+  JOIN projects ON projects.id = projects_usage.project_id
+  WHERE projects.name = ? AND projects_usage.period_start >= ? AND projects_usage.period_start < ?
+  GROUP BY projects_usage.period_start
+  ORDER BY projects_usage.period_start
+`
+
+	usage := []ProjectUsage{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		u := ProjectUsage{Project: project}
+
+		err := scan(&u.PeriodStart, &u.APICalls, &u.InstanceSeconds, &u.StorageByteSeconds, &u.NetworkBytes)
+		if err != nil {
+			return err
+		}
+
+		usage = append(usage, u)
+
+		return nil
+	}, project, since.UTC(), until.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"projects_usage\" table: %w", err)
+	}
+
+	return usage, nil
+}
+
+// DeleteProjectsUsageBefore removes the usage recorded for the hours starting before the given time.
+func DeleteProjectsUsageBefore(ctx context.Context, tx *sql.Tx, before time.Time) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM projects_usage WHERE period_start < ?", before.UTC())
+	if err != nil {
+		return fmt.Errorf("Delete entries for \"projects_usage\" failed: %w", err)
+	}
+
+	return nil
+}
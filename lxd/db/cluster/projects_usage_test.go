@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectsUsage(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec(`
+INSERT INTO nodes (id, name, address, schema, api_extensions, arch, description) VALUES (1, 'n1', '10.0.0.1:8443', 1, 1, 1, ''), (2, 'n2', '10.0.0.2:8443', 1, 1, 1, '');
+INSERT INTO projects (id, name, description) VALUES (1, 'default', ''), (2, 'p1', '');
+`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	ctx := context.Background()
+	hour := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	err = AddProjectsUsage(ctx, tx, 1, []ProjectUsage{
+		{Project: "default", PeriodStart: hour, APICalls: 10, InstanceSeconds: 300, StorageByteSeconds: 1000, NetworkBytes: 100},
+		{Project: "p1", PeriodStart: hour, APICalls: 1},
+		{Project: "missing", PeriodStart: hour, APICalls: 1},
+	})
+	require.NoError(t, err)
+
+	// The usage of the same hour is added to the usage already recorded.
+	err = AddProjectsUsage(ctx, tx, 1, []ProjectUsage{
+		{Project: "default", PeriodStart: hour, APICalls: 5, InstanceSeconds: 300, NetworkBytes: 50},
+		{Project: "default", PeriodStart: hour.Add(time.Hour), APICalls: 2},
+	})
+	require.NoError(t, err)
+
+	err = AddProjectsUsage(ctx, tx, 2, []ProjectUsage{
+		{Project: "default", PeriodStart: hour, APICalls: 3, InstanceSeconds: 300},
+	})
+	require.NoError(t, err)
+
+	// The usage is summed over the cluster members.
+	usage, err := GetProjectUsage(ctx, tx, "default", hour, hour.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	assert.True(t, usage[0].PeriodStart.Equal(hour))
+	assert.Equal(t, ProjectUsage{Project: "default", PeriodStart: usage[0].PeriodStart, APICalls: 18, InstanceSeconds: 900, StorageByteSeconds: 1000, NetworkBytes: 150}, usage[0])
+	assert.True(t, usage[1].PeriodStart.Equal(hour.Add(time.Hour)))
+	assert.Equal(t, int64(2), usage[1].APICalls)
+
+	// The time range covers the hours starting within it.
+	usage, err = GetProjectUsage(ctx, tx, "default", hour.Add(time.Minute), hour.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, int64(2), usage[0].APICalls)
+
+	usage, err = GetProjectUsage(ctx, tx, "p1", hour, hour.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, int64(1), usage[0].APICalls)
+
+	usage, err = GetProjectUsage(ctx, tx, "missing", hour, hour.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	// Only the usage of the hours starting before the given time is pruned.
+	require.NoError(t, DeleteProjectsUsageBefore(ctx, tx, hour.Add(time.Hour)))
+	usage, err = GetProjectUsage(ctx, tx, "default", hour, hour.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.True(t, usage[0].PeriodStart.Equal(hour.Add(time.Hour)))
+}
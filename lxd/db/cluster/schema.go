@@ -12,6 +12,26 @@ CREATE TABLE auth_groups (
     description TEXT NOT NULL,
     UNIQUE (name)
 );
+CREATE VIEW auth_groups_effective_permissions (
+         auth_group_id,
+         entity_type,
+         entity_id,
+         entitlement,
+         expiry_date) AS
+  SELECT auth_group_id,
+         entity_type,
+         entity_id,
+         entitlement,
+         expiry_date
+    FROM auth_groups_permissions UNION
+  SELECT auth_groups_roles.auth_group_id,
+         auth_groups_roles.entity_type,
+         auth_groups_roles.entity_id,
+         auth_roles_entitlements.entitlement,
+         NULL
+    FROM auth_groups_roles
+    JOIN auth_roles_entitlements ON auth_groups_roles.auth_role_id = auth_roles_entitlements.auth_role_id
+     AND auth_groups_roles.entity_type = auth_roles_entitlements.entity_type;
 CREATE TABLE auth_groups_identity_provider_groups (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     auth_group_id INTEGER NOT NULL,
@@ -26,21 +46,87 @@ CREATE TABLE auth_groups_permissions (
     entity_type INTEGER NOT NULL,
     entity_id INTEGER NOT NULL,
     entitlement TEXT NOT NULL,
+    expiry_date DATETIME,
     FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
     UNIQUE (auth_group_id, entity_type, entitlement, entity_id)
 );
+CREATE TABLE auth_groups_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    auth_role_id INTEGER NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_role_id) REFERENCES auth_roles (id) ON DELETE CASCADE,
+    UNIQUE (auth_group_id, auth_role_id, entity_type, entity_id)
+);
+CREATE TABLE auth_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE auth_roles_entitlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_role_id INTEGER NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    FOREIGN KEY (auth_role_id) REFERENCES auth_roles (id) ON DELETE CASCADE,
+    UNIQUE (auth_role_id, entity_type, entitlement)
+);
 CREATE TABLE "cluster_groups" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
     description TEXT NOT NULL,
     UNIQUE (name)
 );
+CREATE TABLE cluster_groups_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    cluster_group_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (cluster_group_id) REFERENCES cluster_groups (id) ON DELETE CASCADE,
+    UNIQUE (cluster_group_id, key)
+);
 CREATE TABLE config (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     key TEXT NOT NULL,
     value TEXT,
     UNIQUE (key)
 );
+CREATE TABLE entities_ownership (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME,
+    last_modified_by TEXT NOT NULL DEFAULT '',
+    last_modified_at DATETIME,
+    UNIQUE (entity_type, entity_id)
+);
+CREATE TABLE events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type TEXT NOT NULL,
+    project TEXT NOT NULL,
+    location TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    metadata TEXT NOT NULL
+);
+CREATE INDEX events_timestamp_idx ON events (timestamp);
+CREATE TABLE gpu_mig_allocations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    node_id INTEGER NOT NULL,
+    gpu TEXT NOT NULL,
+    profile TEXT NOT NULL,
+    gpu_instance_id INTEGER NOT NULL,
+    compute_instance_id INTEGER NOT NULL,
+    instance_id INTEGER NOT NULL,
+    device_name TEXT NOT NULL,
+    FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE,
+    FOREIGN KEY (instance_id) REFERENCES instances (id) ON DELETE CASCADE,
+    UNIQUE (node_id, gpu, gpu_instance_id),
+    UNIQUE (instance_id, device_name)
+);
 CREATE TABLE identities (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     auth_method INTEGER NOT NULL,
@@ -58,10 +144,33 @@ CREATE TABLE identities_auth_groups (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     identity_id INTEGER NOT NULL,
     auth_group_id INTEGER NOT NULL,
+    expiry_date DATETIME,
     FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
     FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
     UNIQUE (identity_id, auth_group_id)
 );
+CREATE TABLE identities_delegations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    identity_id INTEGER NOT NULL,
+    parent_identity_id INTEGER,
+    parent TEXT NOT NULL,
+    project_id INTEGER NOT NULL,
+    auth_group_id INTEGER,
+    date DATETIME NOT NULL,
+    FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_identity_id) REFERENCES identities (id) ON DELETE SET NULL,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE SET NULL,
+    UNIQUE (identity_id)
+);
+CREATE TABLE identities_profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    identity_id INTEGER NOT NULL,
+    display_name TEXT NOT NULL DEFAULT '',
+    sessions_revoked_at DATETIME,
+    FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    UNIQUE (identity_id)
+);
 CREATE TABLE identities_projects (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     identity_id INTEGER NOT NULL,
@@ -139,6 +248,15 @@ CREATE TABLE "images_source" (
     alias TEXT NOT NULL,
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
 );
+CREATE TABLE instance_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
 CREATE TABLE "instances" (
     id INTEGER primary key AUTOINCREMENT NOT NULL,
     node_id INTEGER NOT NULL,
@@ -191,6 +309,15 @@ CREATE TABLE "instances_devices_config" (
     FOREIGN KEY (instance_device_id) REFERENCES "instances_devices" (id) ON DELETE CASCADE,
     UNIQUE (instance_device_id, key)
 );
+CREATE TABLE "instances_labels" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    instance_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
+    UNIQUE (instance_id, key)
+);
+CREATE INDEX instances_labels_key_value_idx ON instances_labels (key, value);
 CREATE INDEX instances_node_id_idx ON instances (node_id);
 CREATE TABLE "instances_profiles" (
     id INTEGER primary key AUTOINCREMENT NOT NULL,
@@ -244,6 +371,15 @@ CREATE TABLE "instances_snapshots_devices_config" (
     FOREIGN KEY (instance_snapshot_device_id) REFERENCES "instances_snapshots_devices" (id) ON DELETE CASCADE,
     UNIQUE (instance_snapshot_device_id, key)
 );
+CREATE TABLE instances_state_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    instance_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    action TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE
+);
+CREATE INDEX instances_state_transitions_instance_id_timestamp_idx ON instances_state_transitions (instance_id, timestamp);
 CREATE TABLE "networks" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     project_id INTEGER NOT NULL,
@@ -483,6 +619,41 @@ CREATE TABLE "projects_config" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
     UNIQUE (project_id, key)
 );
+CREATE TABLE projects_shares (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    access TEXT NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (entity_type, entity_id, project_id)
+);
+CREATE INDEX projects_shares_project_id_idx ON projects_shares (project_id);
+CREATE TABLE projects_status_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    requestor TEXT NOT NULL DEFAULT '',
+    protocol TEXT NOT NULL DEFAULT '',
+    date DATETIME NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE INDEX projects_status_changes_project_id_date_idx ON projects_status_changes (project_id, date);
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    period_start DATETIME NOT NULL,
+    api_calls INTEGER NOT NULL DEFAULT 0,
+    instance_seconds INTEGER NOT NULL DEFAULT 0,
+    storage_byte_seconds INTEGER NOT NULL DEFAULT 0,
+    network_bytes INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (project_id, node_id, period_start),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+CREATE INDEX projects_usage_period_start_idx ON projects_usage (period_start);
 CREATE TABLE secrets (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     entity_type INTEGER NOT NULL,
@@ -673,11 +844,13 @@ CREATE TABLE "warnings" (
 	updated_date DATETIME,
 	last_message TEXT NOT NULL,
 	count INTEGER NOT NULL,
+	acknowledged_by TEXT NOT NULL DEFAULT '',
+	acknowledged_comment TEXT NOT NULL DEFAULT '',
 	UNIQUE (uuid),
 	FOREIGN KEY (node_id) REFERENCES "nodes"(id) ON DELETE CASCADE,
 	FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (76, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (91, strftime("%s"))
 `
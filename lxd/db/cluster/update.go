@@ -117,6 +117,284 @@ var updates = map[int]schema.Update{
 	74: updateFromV73,
 	75: updateFromV74,
 	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+	84: updateFromV83,
+	85: updateFromV84,
+	86: updateFromV85,
+	87: updateFromV86,
+	88: updateFromV87,
+	89: updateFromV88,
+	90: updateFromV89,
+	91: updateFromV90,
+}
+
+func updateFromV90(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE identities_profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    identity_id INTEGER NOT NULL,
+    display_name TEXT NOT NULL DEFAULT '',
+    sessions_revoked_at DATETIME,
+    FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    UNIQUE (identity_id)
+);
+`)
+	return err
+}
+
+func updateFromV89(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE projects_shares (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    access TEXT NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (entity_type, entity_id, project_id)
+);
+CREATE INDEX projects_shares_project_id_idx ON projects_shares (project_id);
+`)
+	return err
+}
+
+func updateFromV88(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE auth_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE auth_roles_entitlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_role_id INTEGER NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    FOREIGN KEY (auth_role_id) REFERENCES auth_roles (id) ON DELETE CASCADE,
+    UNIQUE (auth_role_id, entity_type, entitlement)
+);
+CREATE TABLE auth_groups_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    auth_role_id INTEGER NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_role_id) REFERENCES auth_roles (id) ON DELETE CASCADE,
+    UNIQUE (auth_group_id, auth_role_id, entity_type, entity_id)
+);
+CREATE VIEW auth_groups_effective_permissions (
+         auth_group_id,
+         entity_type,
+         entity_id,
+         entitlement,
+         expiry_date) AS
+  SELECT auth_group_id,
+         entity_type,
+         entity_id,
+         entitlement,
+         expiry_date
+    FROM auth_groups_permissions UNION
+  SELECT auth_groups_roles.auth_group_id,
+         auth_groups_roles.entity_type,
+         auth_groups_roles.entity_id,
+         auth_roles_entitlements.entitlement,
+         NULL
+    FROM auth_groups_roles
+    JOIN auth_roles_entitlements ON auth_groups_roles.auth_role_id = auth_roles_entitlements.auth_role_id
+     AND auth_groups_roles.entity_type = auth_roles_entitlements.entity_type;
+`)
+	return err
+}
+
+func updateFromV87(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE entities_ownership (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    entity_type INTEGER NOT NULL,
+    entity_id INTEGER NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME,
+    last_modified_by TEXT NOT NULL DEFAULT '',
+    last_modified_at DATETIME,
+    UNIQUE (entity_type, entity_id)
+);
+`)
+	return err
+}
+
+func updateFromV86(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE identities_delegations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    identity_id INTEGER NOT NULL,
+    parent_identity_id INTEGER,
+    parent TEXT NOT NULL,
+    project_id INTEGER NOT NULL,
+    auth_group_id INTEGER,
+    date DATETIME NOT NULL,
+    FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_identity_id) REFERENCES identities (id) ON DELETE SET NULL,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE SET NULL,
+    UNIQUE (identity_id)
+);
+`)
+	return err
+}
+
+func updateFromV85(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+ALTER TABLE identities_auth_groups ADD COLUMN expiry_date DATETIME;
+ALTER TABLE auth_groups_permissions ADD COLUMN expiry_date DATETIME;
+`)
+	return err
+}
+
+func updateFromV84(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE projects_status_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    requestor TEXT NOT NULL DEFAULT '',
+    protocol TEXT NOT NULL DEFAULT '',
+    date DATETIME NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE INDEX projects_status_changes_project_id_date_idx ON projects_status_changes (project_id, date);
+`)
+	return err
+}
+
+func updateFromV83(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+ALTER TABLE warnings ADD COLUMN acknowledged_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE warnings ADD COLUMN acknowledged_comment TEXT NOT NULL DEFAULT '';
+`)
+	return err
+}
+
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE instances_state_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    instance_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    action TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE
+);
+CREATE INDEX instances_state_transitions_instance_id_timestamp_idx ON instances_state_transitions (instance_id, timestamp);
+`)
+	return err
+}
+
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    period_start DATETIME NOT NULL,
+    api_calls INTEGER NOT NULL DEFAULT 0,
+    instance_seconds INTEGER NOT NULL DEFAULT 0,
+    storage_byte_seconds INTEGER NOT NULL DEFAULT 0,
+    network_bytes INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (project_id, node_id, period_start),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+CREATE INDEX projects_usage_period_start_idx ON projects_usage (period_start);
+`)
+	return err
+}
+
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type TEXT NOT NULL,
+    project TEXT NOT NULL,
+    location TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    metadata TEXT NOT NULL
+);
+CREATE INDEX events_timestamp_idx ON events (timestamp);
+`)
+	return err
+}
+
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE cluster_groups_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    cluster_group_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (cluster_group_id) REFERENCES cluster_groups (id) ON DELETE CASCADE,
+    UNIQUE (cluster_group_id, key)
+);
+`)
+	return err
+}
+
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE gpu_mig_allocations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    node_id INTEGER NOT NULL,
+    gpu TEXT NOT NULL,
+    profile TEXT NOT NULL,
+    gpu_instance_id INTEGER NOT NULL,
+    compute_instance_id INTEGER NOT NULL,
+    instance_id INTEGER NOT NULL,
+    device_name TEXT NOT NULL,
+    FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE,
+    FOREIGN KEY (instance_id) REFERENCES instances (id) ON DELETE CASCADE,
+    UNIQUE (node_id, gpu, gpu_instance_id),
+    UNIQUE (instance_id, device_name)
+);
+`)
+	return err
+}
+
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE "instances_labels" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    instance_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
+    UNIQUE (instance_id, key)
+);
+CREATE INDEX instances_labels_key_value_idx ON instances_labels (key, value);
+`)
+	return err
+}
+
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE instance_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`)
+	return err
 }
 
 func updateFromV75(ctx context.Context, tx *sql.Tx) error {
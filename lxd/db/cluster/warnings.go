@@ -37,19 +37,21 @@ import (
 
 // Warning is a value object holding db-related details about a warning.
 type Warning struct {
-	ID            int
-	Node          string     `db:"coalesce=''&leftjoin=nodes.name"`
-	Project       string     `db:"coalesce=''&leftjoin=projects.name"`
-	EntityType    EntityType `db:"coalesce=-1&sql=warnings.entity_type_code"`
-	EntityID      int        `db:"coalesce=-1"`
-	UUID          string     `db:"primary=yes"`
-	TypeCode      warningtype.Type
-	Status        warningtype.Status
-	FirstSeenDate time.Time
-	LastSeenDate  time.Time
-	UpdatedDate   time.Time
-	LastMessage   string
-	Count         int
+	ID                  int
+	Node                string     `db:"coalesce=''&leftjoin=nodes.name"`
+	Project             string     `db:"coalesce=''&leftjoin=projects.name"`
+	EntityType          EntityType `db:"coalesce=-1&sql=warnings.entity_type_code"`
+	EntityID            int        `db:"coalesce=-1"`
+	UUID                string     `db:"primary=yes"`
+	TypeCode            warningtype.Type
+	Status              warningtype.Status
+	FirstSeenDate       time.Time
+	LastSeenDate        time.Time
+	UpdatedDate         time.Time
+	LastMessage         string
+	Count               int
+	AcknowledgedBy      string
+	AcknowledgedComment string
 }
 
 // WarningFilter specifies potential query parameter fields.
@@ -68,7 +70,7 @@ type WarningFilter struct {
 func (w Warning) ToAPI() api.Warning {
 	typeCode := warningtype.Type(w.TypeCode)
 
-	return api.Warning{
+	warning := api.Warning{
 		UUID:        w.UUID,
 		Location:    w.Node,
 		Project:     w.Project,
@@ -80,4 +82,12 @@ func (w Warning) ToAPI() api.Warning {
 		Severity:    warningtype.Severities[typeCode.Severity()],
 		Status:      warningtype.Statuses[warningtype.Status(w.Status)],
 	}
+
+	// The acknowledgement is kept when the warning is resolved, but not once it reoccurs.
+	if w.Status != warningtype.StatusNew {
+		warning.AcknowledgedBy = w.AcknowledgedBy
+		warning.AcknowledgedComment = w.AcknowledgedComment
+	}
+
+	return warning
 }
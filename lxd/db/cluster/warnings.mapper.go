@@ -19,7 +19,7 @@ import (
 var _ = api.ServerEnvironment{}
 
 var warningObjects = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -27,7 +27,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByUUID = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -36,7 +36,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByProject = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -45,7 +45,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByStatus = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -54,7 +54,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByNodeAndStatus = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -63,7 +63,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByNodeAndTypeCode = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -72,7 +72,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByNodeAndTypeCodeAndProject = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -81,7 +81,7 @@ SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, ''
 `)
 
 var warningObjectsByNodeAndTypeCodeAndProjectAndEntityTypeAndEntityID = RegisterStmt(`
-SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count
+SELECT warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment
   FROM warnings
   LEFT JOIN nodes ON warnings.node_id = nodes.id
   LEFT JOIN projects ON warnings.project_id = projects.id
@@ -105,7 +105,7 @@ SELECT warnings.id FROM warnings
 // warningColumns returns a string of column names to be used with a SELECT statement for the entity.
 // Use this function when building statements to retrieve database entries matching the Warning entity.
 func warningColumns() string {
-	return "warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count"
+	return "warnings.id, coalesce(nodes.name, '') AS node, coalesce(projects.name, '') AS project, coalesce(warnings.entity_type_code, -1), coalesce(warnings.entity_id, -1), warnings.uuid, warnings.type_code, warnings.status, warnings.first_seen_date, warnings.last_seen_date, warnings.updated_date, warnings.last_message, warnings.count, warnings.acknowledged_by, warnings.acknowledged_comment"
 }
 
 // getWarnings can be used to run handwritten sql.Stmts to return a slice of objects.
@@ -114,7 +114,7 @@ func getWarnings(ctx context.Context, stmt *sql.Stmt, args ...any) ([]Warning, e
 
 	dest := func(scan func(dest ...any) error) error {
 		w := Warning{}
-		err := scan(&w.ID, &w.Node, &w.Project, &w.EntityType, &w.EntityID, &w.UUID, &w.TypeCode, &w.Status, &w.FirstSeenDate, &w.LastSeenDate, &w.UpdatedDate, &w.LastMessage, &w.Count)
+		err := scan(&w.ID, &w.Node, &w.Project, &w.EntityType, &w.EntityID, &w.UUID, &w.TypeCode, &w.Status, &w.FirstSeenDate, &w.LastSeenDate, &w.UpdatedDate, &w.LastMessage, &w.Count, &w.AcknowledgedBy, &w.AcknowledgedComment)
 		if err != nil {
 			return err
 		}
@@ -138,7 +138,7 @@ func getWarningsRaw(ctx context.Context, tx *sql.Tx, sql string, args ...any) ([
 
 	dest := func(scan func(dest ...any) error) error {
 		w := Warning{}
-		err := scan(&w.ID, &w.Node, &w.Project, &w.EntityType, &w.EntityID, &w.UUID, &w.TypeCode, &w.Status, &w.FirstSeenDate, &w.LastSeenDate, &w.UpdatedDate, &w.LastMessage, &w.Count)
+		err := scan(&w.ID, &w.Node, &w.Project, &w.EntityType, &w.EntityID, &w.UUID, &w.TypeCode, &w.Status, &w.FirstSeenDate, &w.LastSeenDate, &w.UpdatedDate, &w.LastMessage, &w.Count, &w.AcknowledgedBy, &w.AcknowledgedComment)
 		if err != nil {
 			return err
 		}
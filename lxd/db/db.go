@@ -21,6 +21,7 @@ import (
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/node"
 	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/tracing"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
@@ -108,11 +109,19 @@ func (n *Node) DqliteDir() string {
 // function returns no error, all database changes are committed to the
 // node-level database, otherwise they are rolled back.
 func (n *Node) Transaction(ctx context.Context, f func(context.Context, *NodeTx) error) error {
+	ctx, span := tracing.Start(ctx, "db.local.transaction")
+	start := time.Now()
+
 	nodeTx := &NodeTx{}
-	return query.Transaction(ctx, n.db, func(ctx context.Context, tx *sql.Tx) error {
+	err := query.Transaction(ctx, n.db, func(ctx context.Context, tx *sql.Tx) error {
 		nodeTx.tx = tx
 		return f(ctx, nodeTx)
 	})
+
+	tracing.End(span, err)
+	trackSlowTransaction(ctx, slowTransactionsLocal, start, err)
+
+	return err
 }
 
 // Close the database facade.
@@ -378,11 +387,14 @@ func (c *Cluster) RunExclusive(f func(t Transactor) error) error {
 }
 
 func (c *Cluster) transaction(ctx context.Context, f func(context.Context, *ClusterTx) error) error {
+	ctx, span := tracing.Start(ctx, "db.global.transaction")
+	start := time.Now()
+
 	clusterTx := &ClusterTx{
 		nodeID: c.nodeID,
 	}
 
-	return query.Retry(ctx, func(ctx context.Context) error {
+	err := query.Retry(ctx, func(ctx context.Context) error {
 		txFunc := func(ctx context.Context, tx *sql.Tx) error {
 			clusterTx.tx = tx
 			return f(ctx, clusterTx)
@@ -399,6 +411,11 @@ func (c *Cluster) transaction(ctx context.Context, f func(context.Context, *Clus
 
 		return err
 	})
+
+	tracing.End(span, err)
+	trackSlowTransaction(ctx, slowTransactionsGlobal, start, err)
+
+	return err
 }
 
 // NodeID sets the node NodeID associated with this cluster instance. It's used for
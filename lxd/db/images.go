@@ -15,6 +15,7 @@ import (
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/query"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/osarch"
 )
@@ -50,6 +51,88 @@ SELECT fingerprint
 	return fingerprints, nil
 }
 
+// imageSortColumns are the columns images can be sorted by, keyed by their sort field. The values of the copies of
+// an image in different projects are aggregated.
+var imageSortColumns = map[string]string{
+	"fingerprint":  "images.fingerprint",
+	"filename":     "MIN(images.filename)",
+	"size":         "MAX(images.size)",
+	"created_at":   "MIN(images.creation_date)",
+	"uploaded_at":  "MIN(images.upload_date)",
+	"last_used_at": "MAX(images.last_use_date)",
+}
+
+// GetImagesFingerprintsPage returns the page of image fingerprints selected by the pagination, sorted by its sort
+// keys and then by fingerprint. The images are those of the given project as returned by GetImagesFingerprints, or
+// of all projects if allProjects is true. The images for which the optional filter returns false don't count
+// towards the page.
+func (c *ClusterTx) GetImagesFingerprintsPage(ctx context.Context, projectName string, allProjects bool, publicOnly bool, pagination *request.Pagination, filter func(fingerprint string, public bool) bool) ([]string, error) {
+	orderBy, err := paginationOrderBy(pagination, imageSortColumns, "images.fingerprint")
+	if err != nil {
+		return nil, err
+	}
+
+	var where []string
+	var args []any
+
+	if !allProjects {
+		enabled, err := cluster.ProjectHasImages(ctx, c.tx, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("Check if project has images: %w", err)
+		}
+
+		if !enabled {
+			projectName = "default"
+		}
+
+		where = append(where, "projects.name = ?")
+		args = append(args, projectName)
+	}
+
+	if publicOnly {
+		where = append(where, "images.public = 1")
+	}
+
+	q := `
+SELECT images.fingerprint, MAX(images.public)
+  FROM images
+  JOIN projects ON projects.id = images.project_id
+`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	q += " GROUP BY images.fingerprint"
+
+	type imageRow struct {
+		fingerprint string
+		public      bool
+	}
+
+	rowFunc := func(scan func(dest ...any) error) (imageRow, error) {
+		var row imageRow
+		err := scan(&row.fingerprint, &row.public)
+		return row, err
+	}
+
+	var rowFilter func(row imageRow) bool
+	if filter != nil {
+		rowFilter = func(row imageRow) bool { return filter(row.fingerprint, row.public) }
+	}
+
+	rows, err := selectPage(ctx, c.tx, q, orderBy, args, pagination, rowFunc, rowFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := make([]string, 0, len(rows))
+	for _, row := range rows {
+		fingerprints = append(fingerprints, row.fingerprint)
+	}
+
+	return fingerprints, nil
+}
+
 // CreateImageSource inserts a new image source.
 func (c *ClusterTx) CreateImageSource(ctx context.Context, id int, server string, protocol string, certificate string, alias string) error {
 	protocolInt := -1
@@ -887,6 +970,18 @@ WHERE images.fingerprint = ?
 	return c.getNodesByImageFingerprint(ctx, q, fingerprint, nil)
 }
 
+// GetNodeNamesWithImage returns the names of all the nodes (online or not) which have the image.
+func (c *ClusterTx) GetNodeNamesWithImage(ctx context.Context, fingerprint string) ([]string, error) {
+	q := `
+SELECT DISTINCT nodes.name FROM nodes
+  JOIN images_nodes ON images_nodes.node_id = nodes.id
+  JOIN images ON images_nodes.image_id = images.id
+WHERE images.fingerprint = ?
+ORDER BY nodes.name
+	`
+	return query.SelectStrings(ctx, c.tx, q, fingerprint)
+}
+
 // GetNodesWithImageAndAutoUpdate returns the addresses of online nodes which already have the image.
 func (c *ClusterTx) GetNodesWithImageAndAutoUpdate(ctx context.Context, fingerprint string, autoUpdate bool) ([]string, error) {
 	q := `
@@ -17,6 +17,7 @@ import (
 	"github.com/canonical/lxd/lxd/db/query"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/osarch"
@@ -39,6 +40,7 @@ type InstanceArgs struct {
 	Config       map[string]string
 	Description  string
 	Devices      deviceConfig.Devices
+	Labels       map[string]string
 	Ephemeral    bool
 	LastUsedDate time.Time
 	Name         string
@@ -66,6 +68,7 @@ func (i *InstanceArgs) ToAPI() (*api.Instance, error) {
 
 		Config:  i.Config,
 		Devices: i.Devices.CloneNative(),
+		Labels:  i.Labels,
 	}
 
 	rslt.Architecture, err = osarch.ArchitectureName(i.Architecture)
@@ -247,6 +250,91 @@ func (c *ClusterTx) GetInstancesByMemberAddress(ctx context.Context, offlineThre
 	return memberAddressInstances, nil
 }
 
+// instanceSortColumns are the columns instances can be sorted by, keyed by their sort field.
+var instanceSortColumns = map[string]string{
+	"project":  "projects.name",
+	"name":     "instances.name",
+	"location": "nodes.name",
+	"type":     "instances.type",
+}
+
+// GetInstancesPageByMemberAddress returns the page of instances selected by the pagination, sorted by its sort keys
+// and then by project and name, along with the instances of the page associated to each cluster member address as
+// returned by GetInstancesByMemberAddress. The instances for which the optional filter returns false don't count
+// towards the page.
+func (c *ClusterTx) GetInstancesPageByMemberAddress(ctx context.Context, offlineThreshold time.Duration, projects []string, instType instancetype.Type, pagination *request.Pagination, filter func(inst Instance) bool) ([]Instance, map[string][]Instance, error) {
+	orderBy, err := paginationOrderBy(pagination, instanceSortColumns, "projects.name", "instances.name")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args := make([]any, 0, len(projects)+1)
+	var q strings.Builder
+
+	q.WriteString(`SELECT
+		instances.id, instances.name, instances.type,
+		nodes.id, nodes.name, nodes.address, nodes.heartbeat,
+		projects.name
+	FROM instances
+	JOIN nodes ON nodes.id = instances.node_id
+	JOIN projects ON projects.id = instances.project_id
+	`)
+
+	// Project filter.
+	q.WriteString("WHERE projects.name IN " + query.Params(len(projects)))
+	for _, project := range projects {
+		args = append(args, project)
+	}
+
+	// Instance type filter.
+	if instType != instancetype.Any {
+		q.WriteString(" AND instances.type = ?")
+		args = append(args, instType)
+	}
+
+	type memberInstance struct {
+		Instance
+		memberAddress string
+	}
+
+	rowFunc := func(scan func(dest ...any) error) (memberInstance, error) {
+		var inst memberInstance
+		var memberID int64
+		var memberHeartbeat time.Time
+		err := scan(&inst.ID, &inst.Name, &inst.Type, &memberID, &inst.Location, &inst.memberAddress, &memberHeartbeat, &inst.Project)
+		if err != nil {
+			return memberInstance{}, err
+		}
+
+		if memberID == c.nodeID {
+			inst.memberAddress = ""
+		} else if nodeIsOffline(offlineThreshold, memberHeartbeat) {
+			inst.memberAddress = "0.0.0.0"
+		}
+
+		return inst, nil
+	}
+
+	var rowFilter func(inst memberInstance) bool
+	if filter != nil {
+		rowFilter = func(inst memberInstance) bool { return filter(inst.Instance) }
+	}
+
+	page, err := selectPage(ctx, c.tx, q.String(), orderBy, args, pagination, rowFunc, rowFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	instances := make([]Instance, 0, len(page))
+	memberAddressInstances := make(map[string][]Instance)
+	for _, inst := range page {
+		instances = append(instances, inst.Instance)
+		memberAddressInstances[inst.memberAddress] = append(memberAddressInstances[inst.memberAddress], inst.Instance)
+	}
+
+	return instances, memberAddressInstances, nil
+}
+
 // ErrListStop used as return value from InstanceList's instanceFunc when prematurely stopping the search.
 var ErrListStop = errors.New("search stopped")
 
@@ -399,6 +487,67 @@ func (c *ClusterTx) instanceConfigFill(ctx context.Context, snapshotsMode bool,
 	})
 }
 
+// instanceLabelsFill loads the labels for all specified instances in a single query and then updates the entries
+// in the instances map.
+func (c *ClusterTx) instanceLabelsFill(ctx context.Context, instanceArgs *map[int]InstanceArgs) error {
+	instances := *instanceArgs
+
+	if len(instances) == 0 {
+		return nil
+	}
+
+	// Don't use query parameters for the IN statement to workaround an issue in Dqlite (apparently)
+	// that means that >255 query parameters causes partial result sets. See #10705
+	// This is safe as the inputs are ints.
+	var q strings.Builder
+
+	q.WriteString(`SELECT
+		instance_id,
+		key,
+		value
+	FROM instances_labels
+	WHERE instance_id IN (`)
+
+	q.Grow(len(instances) * 2) // We know the minimum length of the separators and integers.
+
+	first := true
+	for instanceID := range instances {
+		if !first {
+			q.WriteString(",")
+		}
+
+		first = false
+
+		q.WriteString(strconv.Itoa(instanceID))
+	}
+
+	q.WriteString(`)`)
+
+	return query.Scan(ctx, c.Tx(), q.String(), func(scan func(dest ...any) error) error {
+		var instanceID int
+		var key, value string
+
+		err := scan(&instanceID, &key, &value)
+		if err != nil {
+			return err
+		}
+
+		inst, found := instances[instanceID]
+		if !found {
+			return fmt.Errorf("Failed loading instance labels, referenced instance %d not loaded", instanceID)
+		}
+
+		if inst.Labels == nil {
+			inst.Labels = make(map[string]string)
+			instances[instanceID] = inst
+		}
+
+		inst.Labels[key] = value
+
+		return nil
+	})
+}
+
 // instanceDevicesFill loads the device config for all instances specified in a single query and then updates
 // the entries in the instances map.
 func (c *ClusterTx) instanceDevicesFill(ctx context.Context, snapshotsMode bool, instanceArgs *map[int]InstanceArgs) error {
@@ -654,6 +803,14 @@ func (c *ClusterTx) InstancesToInstanceArgs(ctx context.Context, fillProfiles bo
 		return nil, fmt.Errorf("Failed loading instance devices: %w", err)
 	}
 
+	// Populate instance labels (snapshots don't have any).
+	if snapshotCount == 0 {
+		err = c.instanceLabelsFill(ctx, &instanceArgs)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading instance labels: %w", err)
+		}
+	}
+
 	// Populate instance profiles if requested.
 	if fillProfiles {
 		err = c.instanceProfilesFill(ctx, snapshotCount > 0, &instanceArgs)
@@ -665,6 +822,32 @@ func (c *ClusterTx) InstancesToInstanceArgs(ctx context.Context, fillProfiles bo
 	return instanceArgs, nil
 }
 
+// UpdateInstanceProject moves an instance, along with its snapshots, to another project.
+// The storage volumes of the instance are not moved.
+func (c *ClusterTx) UpdateInstanceProject(ctx context.Context, project string, name string, newProject string) error {
+	instanceID, err := cluster.GetInstanceID(ctx, c.tx, project, name)
+	if err != nil {
+		return fmt.Errorf("Failed to get instance's ID: %w", err)
+	}
+
+	stmt := "UPDATE instances SET project_id=(SELECT id FROM projects WHERE name=?) WHERE id=?"
+	result, err := c.tx.ExecContext(ctx, stmt, newProject, instanceID)
+	if err != nil {
+		return fmt.Errorf("Failed to update instance's project: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get rows affected by instance update: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Unexpected number of updated rows in instances table: %d", n)
+	}
+
+	return nil
+}
+
 // UpdateInstanceNode changes the name of an instance and the cluster member hosting it.
 // It's meant to be used when moving a non-running instance backed by ceph from one cluster node to another.
 func (c *ClusterTx) UpdateInstanceNode(ctx context.Context, project string, oldName string, newName string, newMemberName string, poolID int64, volumeType cluster.StoragePoolVolumeType) error {
@@ -5,6 +5,7 @@ package db_test
 import (
 	"context"
 	"database/sql"
+	"net/http"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/shared/api"
 )
 
@@ -437,6 +439,51 @@ func TestGetInstancesByMemberAddress(t *testing.T) {
 		}, result)
 }
 
+// Pages of instances are selected by the database, not counting filtered out instances.
+func TestGetInstancesPageByMemberAddress(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	nodeID1 := int64(1) // This is the default local member
+
+	nodeID2, err := tx.CreateNode("node2", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	addContainer(t, tx, nodeID2, "c4")
+	addContainer(t, tx, nodeID1, "c2")
+	addContainer(t, tx, nodeID2, "c3")
+	addContainer(t, tx, nodeID1, "c1")
+
+	offlineThreshold := time.Duration(db.DefaultOfflineThreshold) * time.Second
+
+	// Instances are sorted by project and name by default.
+	instances, memberAddressInstances, err := tx.GetInstancesPageByMemberAddress(context.Background(), offlineThreshold, []string{"default"}, instancetype.Any, &request.Pagination{Limit: 2, Offset: 1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []db.Instance{
+		{ID: 2, Project: api.ProjectDefaultName, Name: "c2", Location: "none"},
+		{ID: 3, Project: api.ProjectDefaultName, Name: "c3", Location: "node2"},
+	}, instances)
+	assert.Equal(t, map[string][]db.Instance{
+		"":            {{ID: 2, Project: api.ProjectDefaultName, Name: "c2", Location: "none"}},
+		"1.2.3.4:666": {{ID: 3, Project: api.ProjectDefaultName, Name: "c3", Location: "node2"}},
+	}, memberAddressInstances)
+
+	// Filtered out instances don't count towards the page.
+	pagination := &request.Pagination{Limit: 2, Offset: 1, Sort: []request.SortKey{{Field: "name", Descending: true}}}
+	instances, _, err = tx.GetInstancesPageByMemberAddress(context.Background(), offlineThreshold, []string{"default"}, instancetype.Any, pagination, func(inst db.Instance) bool {
+		return inst.Name != "c3"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []db.Instance{
+		{ID: 2, Project: api.ProjectDefaultName, Name: "c2", Location: "none"},
+		{ID: 4, Project: api.ProjectDefaultName, Name: "c1", Location: "none"},
+	}, instances)
+
+	// Unknown sort fields are rejected.
+	_, _, err = tx.GetInstancesPageByMemberAddress(context.Background(), offlineThreshold, []string{"default"}, instancetype.Any, &request.Pagination{Sort: []request.SortKey{{Field: "size"}}}, nil)
+	assert.Error(t, err)
+}
+
 func TestGetInstancePool(t *testing.T) {
 	dbCluster, cleanup := db.NewTestCluster(t)
 	defer cleanup()
@@ -546,6 +593,38 @@ func TestGetLocalInstancesInProject(t *testing.T) {
 	assert.Equal(t, map[string]map[string]string{"root": {"type": "disk", "x": "y"}}, cluster.DevicesToAPI(c3Devices))
 }
 
+func TestUpdateInstanceProject(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	project := cluster.Project{}
+	project.Name = "other"
+	_, err := cluster.CreateProject(ctx, tx.Tx(), project)
+	require.NoError(t, err)
+
+	addContainer(t, tx, 1, "c1")
+	addContainerConfig(t, tx, "c1", "x", "y")
+
+	err = tx.UpdateInstanceProject(ctx, "default", "c1", "other")
+	require.NoError(t, err)
+
+	_, err = cluster.GetInstance(ctx, tx.Tx(), "default", "c1")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	c1, err := cluster.GetInstance(ctx, tx.Tx(), "other", "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "other", c1.Project)
+
+	c1Config, err := cluster.GetInstanceConfig(ctx, tx.Tx(), c1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"x": "y"}, c1Config)
+
+	err = tx.UpdateInstanceProject(ctx, "default", "c1", "other")
+	assert.Error(t, err)
+}
+
 func addContainer(t *testing.T, tx *db.ClusterTx, nodeID int64, name string) {
 	stmt := `
 INSERT INTO instances(node_id, name, architecture, type, project_id, description) VALUES (?, ?, 1, ?, 1, '')
@@ -40,6 +40,10 @@ const ClusterRoleEventHub = ClusterRole("event-hub")
 // ClusterRoleOVNChassis represents a cluster member who operates as an OVN chassis.
 const ClusterRoleOVNChassis = ClusterRole("ovn-chassis")
 
+// ClusterRoleWitness represents a cluster member who only takes part in the database quorum and hosts no
+// instances or storage volumes.
+const ClusterRoleWitness = ClusterRole("witness")
+
 // ClusterRoles maps role ids into human-readable names.
 //
 // Note: the database role is currently stored directly in the raft
@@ -48,6 +52,7 @@ const ClusterRoleOVNChassis = ClusterRole("ovn-chassis")
 var ClusterRoles = map[int]ClusterRole{
 	1: ClusterRoleEventHub,
 	2: ClusterRoleOVNChassis,
+	3: ClusterRoleWitness,
 }
 
 // Numeric type codes identifying different cluster member states.
@@ -1046,6 +1051,11 @@ func (c *ClusterTx) GetCandidateMembers(ctx context.Context, allMembers []NodeIn
 			continue
 		}
 
+		// Skip witness members as they can't host instances.
+		if slices.Contains(member.Roles, ClusterRoleWitness) {
+			continue
+		}
+
 		// Skip group-only members if targeted cluster group doesn't match.
 		if member.Config["scheduler.instance"] == "group" && !slices.Contains(member.Groups, targetClusterGroup) {
 			continue
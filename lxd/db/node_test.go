@@ -332,6 +332,26 @@ INSERT INTO instances (id, node_id, name, architecture, type, project_id, descri
 	assert.Equal(t, "buzz", member.Name)
 }
 
+// Witness members are never candidates for new instances.
+func TestGetCandidateMembers_Witness(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	id, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	err = tx.UpdateNodeRoles(id, []db.ClusterRole{db.ClusterRoleWitness})
+	require.NoError(t, err)
+
+	allMembers, err := tx.GetNodes(context.Background())
+	require.NoError(t, err)
+
+	members, err := tx.GetCandidateMembers(context.Background(), allMembers, nil, "", nil, time.Duration(db.DefaultOfflineThreshold)*time.Second)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "none", members[0].Name)
+}
+
 // If there are nodes, and one of them is offline, return the name of the
 // online node, even if the offline one has more instances.
 func TestGetNodeWithLeastInstances_OfflineNode(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 	"github.com/openfga/openfga/pkg/storage"
@@ -63,7 +64,7 @@ type RequestCache struct {
 //
 // Observations:
 //   - This method is only called on Check requests.
-//   - The `Relation` field of the given key is always either `project` or `server`.
+//   - The `Relation` field of the given key is always either `project`, `server`, or `shared_project`.
 //   - The `Object` field is never a group or identity.
 //
 // Implementation:
@@ -75,6 +76,8 @@ type RequestCache struct {
 //     object in the `Object` filed. Again, OpenFGA doesn't know that this is one-to-many. Since the URL of the object contains the
 //     project name, we can parse this URL and return a tuple that relates the `Object` in the tuple to a project object via the
 //     `project` relation.
+//   - When the `Relation` field of the given key is `shared_project`, OpenFGA is asking which projects the object is
+//     shared with. We look up the shares of the object and return a tuple for each project.
 //   - For any other relations or unexpected input, return an error.
 //
 // Notes:
@@ -136,6 +139,10 @@ func (o *openfgaStore) Read(ctx context.Context, s string, key *openfgav1.TupleK
 		return nil, err
 	}
 
+	if relation == relationSharedProject {
+		return o.readSharedProjects(ctx, obj, entityType, &api.URL{URL: *u})
+	}
+
 	// We're returning a single relation between a parent and child. Set up the tuple key with the object and relation.
 	tupleKey := &openfgav1.TupleKey{
 		Object:   obj,
@@ -197,6 +204,81 @@ func (o *openfgaStore) Read(ctx context.Context, s string, key *openfgav1.TupleK
 	return storage.NewStaticTupleIterator([]*openfgav1.Tuple{{Key: tupleKey}}), nil
 }
 
+// relationSharedProject relates an entity to the projects that it is shared with.
+const relationSharedProject = "shared_project"
+
+// readSharedProjects returns the tuples relating the given object to the projects that it is shared with.
+func (o *openfgaStore) readSharedProjects(ctx context.Context, obj string, entityType entity.Type, entityURL *api.URL) (storage.TupleIterator, error) {
+	if !slices.Contains(cluster.ProjectShareEntityTypes, entityType) {
+		return nil, fmt.Errorf("Received unexpected query, entities of type %q can't be shared", entityType)
+	}
+
+	var shares []cluster.ProjectShare
+	err := o.clusterDB.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		entityRef, err := cluster.GetEntityReferenceFromURL(ctx, tx.Tx(), entityURL)
+		if err != nil {
+			return err
+		}
+
+		shares, err = cluster.GetEntityProjectShares(ctx, tx.Tx(), entityType, entityRef.EntityID)
+		return err
+	})
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return nil, fmt.Errorf("Read: Failed to get shares of entity %q: %w", entityURL.String(), err)
+	}
+
+	tuples := make([]*openfgav1.Tuple, 0, len(shares))
+	for _, share := range shares {
+		tuples = append(tuples, &openfgav1.Tuple{Key: &openfgav1.TupleKey{
+			Object:   obj,
+			Relation: relationSharedProject,
+			User:     string(entity.TypeProject) + ":" + entity.ProjectURL(share.ProjectName).String(),
+		}})
+	}
+
+	return storage.NewStaticTupleIterator(tuples), nil
+}
+
+// readStartingWithSharedProject returns the tuples relating the entities of the given type that are shared with the
+// given project to the project.
+func (o *openfgaStore) readStartingWithSharedProject(ctx context.Context, entityType entity.Type, projectName string) (storage.TupleIterator, error) {
+	if !slices.Contains(cluster.ProjectShareEntityTypes, entityType) {
+		return nil, fmt.Errorf("ReadStartingWithUser: Entities of type %q can't be shared", entityType)
+	}
+
+	var tuples []*openfgav1.Tuple
+	err := o.clusterDB.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		shares, err := cluster.GetReceivedProjectShares(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, share := range shares {
+			if entity.Type(share.EntityType) != entityType {
+				continue
+			}
+
+			u, err := cluster.GetEntityURL(ctx, tx.Tx(), entityType, share.EntityID)
+			if err != nil {
+				return err
+			}
+
+			tuples = append(tuples, &openfgav1.Tuple{Key: &openfgav1.TupleKey{
+				Object:   string(entityType) + ":" + u.String(),
+				Relation: relationSharedProject,
+				User:     string(entity.TypeProject) + ":" + entity.ProjectURL(projectName).String(),
+			}})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ReadStartingWithUser: Failed to get entities shared with project %q: %w", projectName, err)
+	}
+
+	return storage.NewStaticTupleIterator(tuples), nil
+}
+
 // ReadUserTuple reads a single tuple from the store.
 //
 // Observations:
@@ -439,12 +521,13 @@ func (o *openfgaStore) getGroupsWithEntitlementOnEntityWithURL(ctx context.Conte
 
 		// Get all groups with the permission.
 		q := `
-SELECT auth_groups.name
-FROM auth_groups_permissions
-JOIN auth_groups ON auth_groups_permissions.auth_group_id = auth_groups.id
-WHERE auth_groups_permissions.entitlement = ? AND auth_groups_permissions.entity_type = ? AND auth_groups_permissions.entity_id = ?
+SELECT DISTINCT auth_groups.name
+FROM auth_groups_effective_permissions
+JOIN auth_groups ON auth_groups_effective_permissions.auth_group_id = auth_groups.id
+WHERE auth_groups_effective_permissions.entitlement = ? AND auth_groups_effective_permissions.entity_type = ? AND auth_groups_effective_permissions.entity_id = ?
+AND (auth_groups_effective_permissions.expiry_date IS NULL OR auth_groups_effective_permissions.expiry_date > ?)
 `
-		groupNames, err = query.SelectStrings(ctx, tx.Tx(), q, entitlement, cluster.EntityType(entityType), entityRef.EntityID)
+		groupNames, err = query.SelectStrings(ctx, tx.Tx(), q, entitlement, cluster.EntityType(entityType), entityRef.EntityID, time.Now())
 		if err != nil {
 			return err
 		}
@@ -556,6 +639,15 @@ func (o *openfgaStore) ReadStartingWithUser(ctx context.Context, store string, f
 		return nil, fmt.Errorf("ReadStartingWithUser: Unexpected user entity URL %q: %w", userURL, err)
 	}
 
+	// List the entities of the given type that are shared with the project.
+	if filter.Relation == relationSharedProject {
+		if userEntityType != entity.TypeProject {
+			return nil, fmt.Errorf("ReadStartingWithUser: Relation %q is not valid for entities of type %q", filter.Relation, userEntityType)
+		}
+
+		return o.readStartingWithSharedProject(ctx, entityType, projectName)
+	}
+
 	// Our parent-child relations are always named as the entity type of the parent.
 	relationEntityType := entity.Type(filter.Relation)
 
@@ -745,13 +837,14 @@ func readStartingWithUserTuples(entityType entity.Type, entityURLs []string, ent
 func (o *openfgaStore) getEntitiesOfTypeWhereGroupHasEntitlement(ctx context.Context, entityType entity.Type, groupName string, entitlement auth.Entitlement) ([]string, error) {
 	// Construct a query to list permissions with the given entity type and entitlement for the given group.
 	q := `
-SELECT auth_groups_permissions.entity_type, auth_groups_permissions.entity_id, auth_groups_permissions.entitlement
-FROM auth_groups_permissions
-JOIN auth_groups ON auth_groups_permissions.auth_group_id = auth_groups.id
-WHERE auth_groups_permissions.entitlement = ? AND auth_groups_permissions.entity_type = ? AND auth_groups.name = ?
+SELECT DISTINCT auth_groups_effective_permissions.entity_type, auth_groups_effective_permissions.entity_id, auth_groups_effective_permissions.entitlement
+FROM auth_groups_effective_permissions
+JOIN auth_groups ON auth_groups_effective_permissions.auth_group_id = auth_groups.id
+WHERE auth_groups_effective_permissions.entitlement = ? AND auth_groups_effective_permissions.entity_type = ? AND auth_groups.name = ?
+AND (auth_groups_effective_permissions.expiry_date IS NULL OR auth_groups_effective_permissions.expiry_date > ?)
 `
 	relation := string(entitlement)
-	args := []any{relation, cluster.EntityType(entityType), groupName}
+	args := []any{relation, cluster.EntityType(entityType), groupName, time.Now()}
 
 	var entityURLs map[entity.Type]map[int]*api.URL
 	var permissions []cluster.Permission
@@ -76,6 +76,16 @@ const (
 	RenewServerCertificate
 	RemoveExpiredTokens
 	ClusterHeal
+	StoragePoolRecover
+	InstanceCheckpoint
+	InstanceRescue
+	InstanceExport
+	InstancesReplicate
+	ReplicationPromote
+	ClusterDatabaseMaintenance
+	ClusterRebalance
+	ClusterCertificateRotate
+	ProjectStateUpdate
 )
 
 // Description return a human-readable description of the operation type.
@@ -199,6 +209,26 @@ func (t Type) Description() string {
 		return "Remove expired tokens"
 	case ClusterHeal:
 		return "Healing cluster"
+	case StoragePoolRecover:
+		return "Recovering storage pool volumes"
+	case InstanceCheckpoint:
+		return "Checkpointing instance"
+	case InstanceRescue:
+		return "Rescuing instance"
+	case InstanceExport:
+		return "Exporting instance"
+	case InstancesReplicate:
+		return "Replicating instances"
+	case ReplicationPromote:
+		return "Promoting standby replicas"
+	case ClusterDatabaseMaintenance:
+		return "Running cluster database maintenance"
+	case ClusterRebalance:
+		return "Rebalancing cluster instances"
+	case ClusterCertificateRotate:
+		return "Rotating cluster certificate"
+	case ProjectStateUpdate:
+		return "Updating project state"
 	default:
 		return "Executing operation"
 	}
@@ -227,6 +257,12 @@ func (t Type) Permission() (entity.Type, auth.Entitlement) {
 		return entity.TypeInstance, auth.EntitlementCanUpdateState
 	case InstanceRestart:
 		return entity.TypeInstance, auth.EntitlementCanUpdateState
+	case InstanceCheckpoint:
+		return entity.TypeInstance, auth.EntitlementCanUpdateState
+	case InstanceRescue:
+		return entity.TypeInstance, auth.EntitlementCanEdit
+	case InstanceExport:
+		return entity.TypeInstance, auth.EntitlementCanManageBackups
 	case CommandExec:
 		return entity.TypeInstance, auth.EntitlementCanExec
 	case SnapshotCreate:
@@ -284,3 +320,18 @@ func (t Type) Permission() (entity.Type, auth.Entitlement) {
 
 	return "", ""
 }
+
+// Cancellable returns whether the operation stops at cancellation checkpoints and reverts its changes when its
+// cancellation is requested.
+func (t Type) Cancellable() bool {
+	switch t {
+	case BackupCreate, BackupRestore, CustomVolumeBackupCreate, CustomVolumeBackupRestore, InstanceExport:
+		return true
+	case ImageDownload:
+		return true
+	case InstanceMigrate, InstanceLiveMigrate, VolumeMigrate, VolumeCopy, VolumeMove:
+		return true
+	}
+
+	return false
+}
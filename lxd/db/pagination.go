@@ -0,0 +1,111 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// paginationBatchSize is the number of rows fetched at once when selecting a page of rows that are filtered after
+// being fetched.
+const paginationBatchSize = 500
+
+// paginationOrderBy returns the ORDER BY clause sorting rows by the sort keys of the pagination, followed by the
+// given default columns to break ties. The columns map each sort field to the column to sort by.
+// It returns an [api.StatusError] with [http.StatusBadRequest] if a sort key isn't one of the given fields.
+func paginationOrderBy(p *request.Pagination, columns map[string]string, defaults ...string) (string, error) {
+	orderBy := make([]string, 0, len(p.Sort)+len(defaults))
+	for _, key := range p.Sort {
+		column, ok := columns[key.Field]
+		if !ok {
+			return "", api.StatusErrorf(http.StatusBadRequest, "Invalid sort field %q", key.Field)
+		}
+
+		if key.Descending {
+			column += " DESC"
+		}
+
+		orderBy = append(orderBy, column)
+	}
+
+	orderBy = append(orderBy, defaults...)
+
+	return " ORDER BY " + strings.Join(orderBy, ", "), nil
+}
+
+// selectPage runs the given query sorted by the given ORDER BY clause and returns the rows of the page selected by
+// the pagination, each converted by the rowFunc.
+// The rows for which the optional filter returns false, such as those the caller isn't allowed to see, don't count
+// towards the offset and limit of the page. The rows are then fetched in batches until the page is complete.
+func selectPage[T any](ctx context.Context, tx *sql.Tx, stmt string, orderBy string, args []any, p *request.Pagination, rowFunc func(scan func(dest ...any) error) (T, error), filter func(row T) bool) ([]T, error) {
+	stmt += orderBy + " LIMIT ? OFFSET ?"
+
+	var page []T
+
+	// Let the database select the page if no rows are filtered out.
+	if filter == nil {
+		limit := -1
+		if p.Limit > 0 {
+			limit = p.Limit
+		}
+
+		err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+			row, err := rowFunc(scan)
+			if err != nil {
+				return err
+			}
+
+			page = append(page, row)
+
+			return nil
+		}, append(slices.Clone(args), limit, p.Offset)...)
+		if err != nil {
+			return nil, err
+		}
+
+		return page, nil
+	}
+
+	skip := p.Offset
+	complete := func() bool { return p.Limit > 0 && len(page) >= p.Limit }
+
+	for offset := 0; ; offset += paginationBatchSize {
+		rows := 0
+		err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+			rows++
+
+			row, err := rowFunc(scan)
+			if err != nil {
+				return err
+			}
+
+			if complete() || !filter(row) {
+				return nil
+			}
+
+			if skip > 0 {
+				skip--
+				return nil
+			}
+
+			page = append(page, row)
+
+			return nil
+		}, append(slices.Clone(args), paginationBatchSize, offset)...)
+		if err != nil {
+			return nil, err
+		}
+
+		if rows < paginationBatchSize || complete() {
+			return page, nil
+		}
+	}
+}
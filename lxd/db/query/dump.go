@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -58,6 +59,59 @@ func Dump(ctx context.Context, tx *sql.Tx, schemaOnly bool) (string, error) {
 	return builder.String(), nil
 }
 
+// DumpReplace returns a SQL text replacing the rows of all tables, except the excluded ones, with their current
+// rows. Unlike Dump, it neither creates the tables nor controls the transaction, so that it can be applied to an
+// existing database with the same schema, for example through a patch file loaded at startup.
+func DumpReplace(ctx context.Context, tx *sql.Tx, excludeTables []string) (string, error) {
+	entitiesSchemas, entityNames, err := getEntitiesSchemas(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+
+	tableNames := make([]string, 0, len(entityNames))
+	for _, name := range entityNames {
+		if entitiesSchemas[name][0] != "table" || slices.Contains(excludeTables, name) {
+			continue
+		}
+
+		tableNames = append(tableNames, name)
+	}
+
+	// Only check the foreign keys once all the rows are replaced.
+	var builder strings.Builder
+	builder.WriteString("PRAGMA defer_foreign_keys=ON;\n")
+
+	// Delete the existing rows, starting with the most recently created tables.
+	for i := len(tableNames) - 1; i >= 0; i-- {
+		builder.WriteString("DELETE FROM " + tableNames[i] + ";\n")
+	}
+
+	for _, tableName := range tableNames {
+		tableData, err := getTableData(ctx, tx, tableName)
+		if err != nil {
+			return "", err
+		}
+
+		for _, stmt := range tableData {
+			builder.WriteString(stmt + "\n")
+		}
+	}
+
+	// Sequences.
+	builder.WriteString("DELETE FROM sqlite_sequence;\n")
+
+	tableData, err := getTableData(ctx, tx, "sqlite_sequence")
+	if err != nil {
+		return "", fmt.Errorf("Failed to dump table sqlite_sequence: %w", err)
+	}
+
+	for _, stmt := range tableData {
+		builder.WriteString(stmt + "\n")
+	}
+
+	return builder.String(), nil
+}
+
 // getEntitiesSchemas gets all the tables, their kind, and their schema, as well as a list of entity names in their default order from
 // the sqlite_master table. The returned map values are arrays of length 2 whose first element contains the entity type and the second
 // contains it's schema.
@@ -51,6 +51,22 @@ COMMIT;
 `, dump)
 }
 
+func TestDumpReplace(t *testing.T) {
+	tx := newTxForDump(t, "local")
+	dump, err := query.DumpReplace(context.Background(), tx, []string{"schema"})
+	require.NoError(t, err)
+	assert.Equal(t, `PRAGMA defer_foreign_keys=ON;
+DELETE FROM raft_nodes;
+DELETE FROM patches;
+DELETE FROM config;
+INSERT INTO patches VALUES(1,'invalid_profile_names','2018-04-17 06:26:06+00:00');
+INSERT INTO patches VALUES(2,'leftover_profile_config','2018-04-17 06:26:06+00:00');
+DELETE FROM sqlite_sequence;
+INSERT INTO sqlite_sequence VALUES('schema',1);
+INSERT INTO sqlite_sequence VALUES('patches',2);
+`, dump)
+}
+
 func TestDumpTablePatches(t *testing.T) {
 	tx := newTxForDump(t, "local")
 
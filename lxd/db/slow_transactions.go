@@ -0,0 +1,94 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Names of the databases, as reported for slow transactions.
+const (
+	slowTransactionsLocal  = "local"
+	slowTransactionsGlobal = "global"
+)
+
+// slowTransactionThreshold is the duration above which transactions are logged and counted as slow.
+// A zero threshold disables the tracking of slow transactions.
+var slowTransactionThreshold atomic.Int64
+
+var slowTransactions = map[string]*atomic.Int64{
+	slowTransactionsLocal:  new(atomic.Int64),
+	slowTransactionsGlobal: new(atomic.Int64),
+}
+
+// SetSlowTransactionThreshold sets the duration above which transactions are logged and counted as slow.
+// A zero threshold disables the tracking of slow transactions.
+func SetSlowTransactionThreshold(threshold time.Duration) {
+	slowTransactionThreshold.Store(int64(threshold))
+}
+
+// SlowTransactions returns the number of slow transactions, keyed by database ("local" or "global").
+func SlowTransactions() map[string]int64 {
+	out := make(map[string]int64, len(slowTransactions))
+	for database, count := range slowTransactions {
+		out[database] = count.Load()
+	}
+
+	return out
+}
+
+// trackSlowTransaction logs and counts the transaction started at the given time if it exceeded the slow
+// transaction threshold. The transaction is identified by the function which started it and, if it was started
+// while handling an API request, by the caller of the request.
+func trackSlowTransaction(ctx context.Context, database string, start time.Time, err error) {
+	threshold := time.Duration(slowTransactionThreshold.Load())
+	if threshold <= 0 {
+		return
+	}
+
+	duration := time.Since(start)
+	if duration < threshold {
+		return
+	}
+
+	slowTransactions[database].Add(1)
+
+	logCtx := logger.Ctx{"database": database, "duration": duration, "function": transactionCaller()}
+	if err != nil {
+		logCtx["err"] = err
+	}
+
+	requestor, reqErr := request.GetRequestor(ctx)
+	if reqErr == nil {
+		logCtx["username"] = requestor.CallerUsername()
+		logCtx["protocol"] = requestor.CallerProtocol()
+		logCtx["trace_id"] = requestor.TraceID()
+	}
+
+	logger.Warn("Slow database transaction", logCtx)
+}
+
+// transactionCaller returns the name of the first function outside of the database packages in the call stack.
+func transactionCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/canonical/lxd/lxd/db.") && !strings.HasPrefix(frame.Function, "github.com/canonical/lxd/lxd/db/") {
+			return frame.Function
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
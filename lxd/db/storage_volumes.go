@@ -14,6 +14,7 @@ import (
 
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/version"
@@ -185,6 +186,80 @@ type StorageVolume struct {
 // Accepts filters for narrowing down the results returned. If memberSpecific is true, then the search is
 // restricted to volumes that belong to this member or belong to all members.
 func (c *ClusterTx) GetStorageVolumes(ctx context.Context, memberSpecific bool, filters ...StorageVolumeFilter) ([]*StorageVolume, error) {
+	q, args, err := c.storageVolumesQuery(memberSpecific, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []*StorageVolume
+
+	err = query.Scan(ctx, c.Tx(), q, func(scan func(dest ...any) error) error {
+		vol, err := storageVolumeScan(scan)
+		if err != nil {
+			return err
+		}
+
+		volumes = append(volumes, vol)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.storageVolumesConfigFill(ctx, volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// storageVolumeSortColumns are the columns storage volumes can be sorted by, keyed by their sort field.
+// Volume types are sorted by name.
+var storageVolumeSortColumns = map[string]string{
+	"type": fmt.Sprintf("CASE storage_volumes_all.type WHEN %d THEN '%s' WHEN %d THEN '%s' WHEN %d THEN '%s' WHEN %d THEN '%s' END",
+		cluster.StoragePoolVolumeTypeContainer, cluster.StoragePoolVolumeTypeNameContainer,
+		cluster.StoragePoolVolumeTypeImage, cluster.StoragePoolVolumeTypeNameImage,
+		cluster.StoragePoolVolumeTypeCustom, cluster.StoragePoolVolumeTypeNameCustom,
+		cluster.StoragePoolVolumeTypeVM, cluster.StoragePoolVolumeTypeNameVM),
+	"name":       "storage_volumes_all.name",
+	"project":    "projects.name",
+	"pool":       "storage_pools.name",
+	"location":   "location",
+	"created_at": "storage_volumes_all.creation_date",
+}
+
+// GetStorageVolumesPage returns the page of storage volumes selected by the pagination, sorted by its sort keys and
+// then by type and name. The volumes for which the optional filter returns false don't count towards the page, and
+// the config is only loaded for the volumes of the page.
+// Accepts the same filters as GetStorageVolumes.
+func (c *ClusterTx) GetStorageVolumesPage(ctx context.Context, memberSpecific bool, pagination *request.Pagination, filter func(vol *StorageVolume) bool, filters ...StorageVolumeFilter) ([]*StorageVolume, error) {
+	orderBy, err := paginationOrderBy(pagination, storageVolumeSortColumns, storageVolumeSortColumns["type"], "storage_volumes_all.name", "storage_volumes_all.id")
+	if err != nil {
+		return nil, err
+	}
+
+	q, args, err := c.storageVolumesQuery(memberSpecific, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := selectPage(ctx, c.Tx(), q, orderBy, args, pagination, storageVolumeScan, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.storageVolumesConfigFill(ctx, volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// storageVolumesQuery returns the query and its arguments listing the storage volumes matching the given filters.
+func (c *ClusterTx) storageVolumesQuery(memberSpecific bool, filters []StorageVolumeFilter) (string, []any, error) {
 	var q = &strings.Builder{}
 	args := []any{}
 
@@ -211,11 +286,11 @@ func (c *ClusterTx) GetStorageVolumes(ctx context.Context, memberSpecific bool,
 		for i, filter := range filters {
 			// Validate filter.
 			if filter.Name != nil && filter.Type == nil {
-				return nil, errors.New("Cannot filter by volume name if volume type not specified")
+				return "", nil, errors.New("Cannot filter by volume name if volume type not specified")
 			}
 
 			if filter.Name != nil && filter.Project == nil {
-				return nil, errors.New("Cannot filter by volume name if volume project not specified")
+				return "", nil, errors.New("Cannot filter by volume name if volume project not specified")
 			}
 
 			var qFilters []string
@@ -241,7 +316,7 @@ func (c *ClusterTx) GetStorageVolumes(ctx context.Context, memberSpecific bool,
 			}
 
 			if qFilters == nil {
-				return nil, errors.New("Invalid storage volume filter")
+				return "", nil, errors.New("Invalid storage volume filter")
 			}
 
 			if i > 0 {
@@ -264,49 +339,47 @@ func (c *ClusterTx) GetStorageVolumes(ctx context.Context, memberSpecific bool,
 		}
 	}
 
-	var err error
-	var volumes []*StorageVolume
-
-	err = query.Scan(ctx, c.Tx(), q.String(), func(scan func(dest ...any) error) error {
-		var rawVolumeType = int(-1)
-		var rawContentType = int(-1)
-		var vol StorageVolume
-
-		err := scan(&vol.Project, &vol.ID, &vol.Name, &vol.Location, &rawVolumeType, &rawContentType, &vol.Description, &vol.CreatedAt, &vol.Pool)
-		if err != nil {
-			return err
-		}
-
-		volumeType, err := cluster.StoragePoolVolumeTypeFromInt(rawVolumeType)
-		if err != nil {
-			return err
-		}
+	return q.String(), args, nil
+}
 
-		contentType, err := cluster.StoragePoolVolumeContentTypeFromInt(rawContentType)
-		if err != nil {
-			return err
-		}
+// storageVolumeScan scans a row of the query returned by storageVolumesQuery.
+func storageVolumeScan(scan func(dest ...any) error) (*StorageVolume, error) {
+	var rawVolumeType = int(-1)
+	var rawContentType = int(-1)
+	var vol StorageVolume
 
-		vol.Type = volumeType.String()
-		vol.ContentType = contentType.String()
+	err := scan(&vol.Project, &vol.ID, &vol.Name, &vol.Location, &rawVolumeType, &rawContentType, &vol.Description, &vol.CreatedAt, &vol.Pool)
+	if err != nil {
+		return nil, err
+	}
 
-		volumes = append(volumes, &vol)
+	volumeType, err := cluster.StoragePoolVolumeTypeFromInt(rawVolumeType)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
-	}, args...)
+	contentType, err := cluster.StoragePoolVolumeContentTypeFromInt(rawContentType)
 	if err != nil {
 		return nil, err
 	}
 
-	// Populate config.
+	vol.Type = volumeType.String()
+	vol.ContentType = contentType.String()
+
+	return &vol, nil
+}
+
+// storageVolumesConfigFill loads the config of the given storage volumes.
+func (c *ClusterTx) storageVolumesConfigFill(ctx context.Context, volumes []*StorageVolume) error {
+	var err error
 	for _, volume := range volumes {
 		volume.Config, err = c.storageVolumeConfigGet(ctx, volume.ID, shared.IsSnapshot(volume.Name))
 		if err != nil {
-			return nil, fmt.Errorf("Failed loading volume config for %q: %w", volume.Name, err)
+			return fmt.Errorf("Failed loading volume config for %q: %w", volume.Name, err)
 		}
 	}
 
-	return volumes, nil
+	return nil
 }
 
 // GetStoragePoolVolume returns the storage volume attached to a given storage pool.
@@ -506,6 +579,22 @@ func (c *ClusterTx) RenameStoragePoolVolume(ctx context.Context, projectName str
 	return nil
 }
 
+// UpdateStoragePoolVolumeProject moves a storage volume, along with its snapshots, to another project.
+func (c *ClusterTx) UpdateStoragePoolVolumeProject(ctx context.Context, projectName string, volumeName string, volumeType cluster.StoragePoolVolumeType, poolID int64, newProjectName string) error {
+	volume, err := c.GetStoragePoolVolume(ctx, poolID, projectName, volumeType, volumeName, true)
+	if err != nil {
+		return err
+	}
+
+	stmt := "UPDATE storage_volumes SET project_id=(SELECT id FROM projects WHERE name=?) WHERE id=?"
+	_, err = c.tx.ExecContext(ctx, stmt, newProjectName, volume.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateStoragePoolVolume creates a new storage volume attached to a given storage pool.
 func (c *ClusterTx) CreateStoragePoolVolume(ctx context.Context, projectName string, volumeName string, volumeDescription string, volumeType cluster.StoragePoolVolumeType, poolID int64, volumeConfig map[string]string, contentType cluster.StoragePoolVolumeContentType, creationDate time.Time) (int64, error) {
 	var volumeID int64
@@ -127,6 +127,27 @@ func (c *ClusterTx) UpdateWarningStatus(UUID string, status warningtype.Status)
 	return nil
 }
 
+// AcknowledgeWarning sets the status of the warning with the given UUID to acknowledged, recording who
+// acknowledged it and why.
+func (c *ClusterTx) AcknowledgeWarning(UUID string, acknowledgedBy string, comment string) error {
+	str := "UPDATE warnings SET status=?, updated_date=?, acknowledged_by=?, acknowledged_comment=? WHERE uuid=?"
+	res, err := c.tx.Exec(str, warningtype.StatusAcknowledged, time.Now(), acknowledgedBy, comment, UUID)
+	if err != nil {
+		return fmt.Errorf("Failed to acknowledge warning %q: %w", UUID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get affected rows to acknowledge warning %q: %w", UUID, err)
+	}
+
+	if rowsAffected == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Warning not found")
+	}
+
+	return nil
+}
+
 // UpdateWarningState updates the warning message and status with the given ID.
 func (c *ClusterTx) UpdateWarningState(UUID string, message string, status warningtype.Status) error {
 	str := "UPDATE warnings SET last_message=?, last_seen_date=?, updated_date=?, status = ?, count=count+1 WHERE uuid=?"
@@ -58,6 +58,10 @@ const (
 	StoragePoolUnvailable
 	// UnableToUpdateClusterCertificate represents the unable to update cluster certificate warning.
 	UnableToUpdateClusterCertificate
+	// ClusterPartition represents the cluster partition warning.
+	ClusterPartition
+	// StoragePoolAlmostFull represents a storage pool whose usage reached the alerting threshold.
+	StoragePoolAlmostFull
 )
 
 // TypeNames associates a warning code to its name.
@@ -88,6 +92,8 @@ var TypeNames = map[Type]string{
 	InstanceTypeNotOperational:             "Instance type not operational",
 	StoragePoolUnvailable:                  "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:       "Unable to update cluster certificate",
+	ClusterPartition:                       "Cluster member was isolated from the cluster",
+	StoragePoolAlmostFull:                  "Storage pool almost full",
 }
 
 // Severity returns the severity of the warning type.
@@ -145,6 +151,10 @@ func (t Type) Severity() Severity {
 		return SeverityHigh
 	case UnableToUpdateClusterCertificate:
 		return SeverityLow
+	case ClusterPartition:
+		return SeverityHigh
+	case StoragePoolAlmostFull:
+		return SeverityModerate
 	}
 
 	return SeverityLow
@@ -96,6 +96,7 @@ type RunConfig struct {
 	USBDevice        []USBDeviceItem  // USB device configuration settings.
 	TPMDevice        []RunConfigItem  // TPM device configuration settings.
 	PCIDevice        []RunConfigItem  // PCI device configuration settings.
+	WatchdogDevice   []RunConfigItem  // Watchdog device configuration settings.
 	Revert           revert.Hook      // Revert setup of device on post-setup error.
 }
 
@@ -109,3 +110,16 @@ type NICConfig struct {
 	MACAddress string `json:"mac_address"`
 	MTU        uint32 `json:"mtu"`
 }
+
+// SecretConfigDir shared constant used to indicate where secret config is stored.
+const SecretConfigDir = "secrets"
+
+// SecretConfig contains a secret file to be written inside a VM by the agent.
+type SecretConfig struct {
+	DeviceName string `json:"device_name"`
+	Path       string `json:"path"`
+	Mode       uint32 `json:"mode"`
+	UID        int    `json:"uid"`
+	GID        int    `json:"gid"`
+	Data       []byte `json:"data"`
+}
@@ -82,6 +82,10 @@ func newByType(state *state.State, projectName string, conf deviceConfig.Device)
 		dev = &tpm{}
 	case "pci":
 		dev = &pci{}
+	case "secret":
+		dev = &secret{}
+	case "watchdog":
+		dev = &watchdog{}
 	}
 
 	// Check a valid device type has been found.
@@ -9,6 +9,7 @@ import (
 
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared/logger"
 )
@@ -57,11 +58,30 @@ func usbUnregisterHandler(inst instance.Instance, deviceName string) {
 	delete(usbHandlers, key)
 }
 
+// usbEventContext returns the lifecycle event context describing the USB device of the event.
+func usbEventContext(event *USBEvent) map[string]any {
+	return map[string]any{
+		"type":      "usb",
+		"vendorid":  event.Vendor,
+		"productid": event.Product,
+		"serial":    event.Serial,
+		"busnum":    event.BusNum,
+		"devnum":    event.DevNum,
+	}
+}
+
 // USBRunHandlers executes any handlers registered for USB events.
 func USBRunHandlers(state *state.State, event *USBEvent) {
 	usbMutex.Lock()
 	defer usbMutex.Unlock()
 
+	switch event.Action {
+	case "add":
+		state.Events.SendLifecycle("", lifecycle.ResourcesDeviceAdded.Event(usbEventContext(event)))
+	case "remove":
+		state.Events.SendLifecycle("", lifecycle.ResourcesDeviceRemoved.Event(usbEventContext(event)))
+	}
+
 	for key, hook := range usbHandlers {
 		keyParts := strings.SplitN(key, "\000", 3)
 		projectName := keyParts[0]
@@ -93,6 +113,16 @@ func USBRunHandlers(state *state.State, event *USBEvent) {
 				logger.Error("USB event instance handler failed", logger.Ctx{"err": err, "project": projectName, "instance": instanceName, "device": deviceName})
 				continue
 			}
+
+			ctx := usbEventContext(event)
+			ctx["device"] = deviceName
+
+			switch event.Action {
+			case "add":
+				state.Events.SendLifecycle(projectName, lifecycle.InstanceDeviceAttached.Event(instance, ctx))
+			case "remove":
+				state.Events.SendLifecycle(projectName, lifecycle.InstanceDeviceDetached.Event(instance, ctx))
+			}
 		}
 	}
 }
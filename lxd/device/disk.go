@@ -42,6 +42,9 @@ import (
 // Special disk "source" value used for generating a VM cloud-init config ISO.
 const diskSourceCloudInit = "cloud-init:config"
 
+// Special disk "source" value used for generating a Windows VM setup ISO.
+const diskSourceWindowsSetup = "windows:setup"
+
 // DiskVirtiofsdSockMountOpt indicates the mount option prefix used to provide the virtiofsd socket path to
 // the QEMU driver.
 const DiskVirtiofsdSockMountOpt = "virtiofsdSock"
@@ -132,13 +135,14 @@ func (d *disk) isRequired(devConfig deviceConfig.Device) bool {
 }
 
 // sourceIsLocalPath returns true if the source supplied should be considered a local path on the host.
-// It returns false if the disk source is empty, a VM cloud-init config drive, or a remote ceph/cephfs path.
+// It returns false if the disk source is empty, a VM cloud-init config drive, a VM Windows setup drive or a remote
+// ceph/cephfs path.
 func (d *disk) sourceIsLocalPath(source string) bool {
 	if source == "" {
 		return false
 	}
 
-	if source == diskSourceCloudInit {
+	if source == diskSourceCloudInit || source == diskSourceWindowsSetup {
 		return false
 	}
 
@@ -171,6 +175,16 @@ func (d *disk) sourceVolumeFields() (volumeName string, volumeType storageDriver
 	return volumeName, volumeType, dbVolumeType, nil
 }
 
+// storageVolumeProject returns the project of the source storage volume. Custom volumes shared by another project
+// are referenced using "source.project".
+func (d *disk) storageVolumeProject(instProj *api.Project, dbVolumeType cluster.StoragePoolVolumeType) string {
+	if d.config["source.project"] != "" && dbVolumeType == cluster.StoragePoolVolumeTypeCustom {
+		return d.config["source.project"]
+	}
+
+	return project.StorageVolumeProjectFromRecord(instProj, dbVolumeType)
+}
+
 // Check that unshared custom storage block volumes are not added to profiles or
 // multiple instances unless they will not be accessed concurrently.
 func (d *disk) checkBlockVolSharing(instanceType instancetype.Type, projectName string, volume *api.StorageVolume) error {
@@ -278,6 +292,16 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		//  required: no
 		//  shortdesc: Type of the backing storage volume
 		"source.type": validate.Optional(validate.IsOneOf(cluster.StoragePoolVolumeTypeNameCustom, cluster.StoragePoolVolumeTypeNameVM)),
+		// lxdmeta:generate(entities=device-disk; group=device-conf; key=source.project)
+		// Project of the custom storage volume given by `source`. The volume
+		// must be shared with the project of the instance with `attach` access.
+		// See {ref}`projects-shares`.
+		// ---
+		//  type: string
+		//  defaultdesc: project of the instance
+		//  required: no
+		//  shortdesc: Project of the shared custom storage volume
+		"source.project": validate.IsAny,
 		// lxdmeta:generate(entities=device-disk; group=device-conf; key=source.snapshot)
 		// Snapshot of the volume given by `source`.
 		// ---
@@ -448,6 +472,10 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		return errors.New(`"source.type" can only be used on storage volume disk devices`)
 	}
 
+	if d.config["source.project"] != "" && (d.config["pool"] == "" || d.config["path"] == "/" || !slices.Contains([]string{"", cluster.StoragePoolVolumeTypeNameCustom}, d.config["source.type"])) {
+		return errors.New(`"source.project" can only be used on custom storage volume disk devices`)
+	}
+
 	if d.config["source"] == "" && d.config["path"] != "/" {
 		return errors.New(`Non root disk devices require the "source" property`)
 	}
@@ -552,7 +580,7 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 
 				// Derive the effective storage project name from the instance config's project.
 				instProj := instConf.Project()
-				storageProjectName = project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+				storageProjectName = d.storageVolumeProject(&instProj, dbVolumeType)
 
 				// GetStoragePoolVolume returns a volume with an empty Location field for remote drivers.
 				err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -563,6 +591,16 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 					return fmt.Errorf(`Failed loading "%s/%s" from project %q: %w`, volumeType, volumeName, storageProjectName, err)
 				}
 
+				// Volumes of other projects must be shared with the project of the instance for attaching.
+				if storageProjectName != instProj.Name && d.config["source.project"] != "" {
+					err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+						return cluster.CheckProjectShareAttach(ctx, tx.Tx(), entity.TypeStorageVolume, int(dbCustomVolume.ID), instProj.Name)
+					})
+					if err != nil {
+						return err
+					}
+				}
+
 				err = d.checkBlockVolSharing(instConf.Type(), storageProjectName, &dbCustomVolume.StorageVolume)
 				if err != nil {
 					return err
@@ -723,8 +761,8 @@ func (d *disk) validateEnvironmentSourcePath() error {
 
 // validateEnvironment checks the runtime environment for correctness.
 func (d *disk) validateEnvironment() error {
-	if d.inst.Type() != instancetype.VM && d.config["source"] == diskSourceCloudInit {
-		return fmt.Errorf("disks with source=%s are only supported by virtual machines", diskSourceCloudInit)
+	if d.inst.Type() != instancetype.VM && (d.config["source"] == diskSourceCloudInit || d.config["source"] == diskSourceWindowsSetup) {
+		return fmt.Errorf("disks with source=%s are only supported by virtual machines", d.config["source"])
 	}
 
 	err := d.validateEnvironmentSourcePath()
@@ -769,7 +807,7 @@ func (d *disk) Register() error {
 		}
 
 		instProj := d.inst.Project()
-		storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+		storageProjectName := d.storageVolumeProject(&instProj, dbVolumeType)
 
 		// Try to mount the volume that should already be mounted to reinitialise the ref counter.
 		if dbVolumeType == cluster.StoragePoolVolumeTypeVM {
@@ -914,7 +952,7 @@ func (d *disk) startContainer() (*deviceConfig.RunConfig, error) {
 			}
 
 			instProj := d.inst.Project()
-			storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+			storageProjectName := d.storageVolumeProject(&instProj, dbVolumeType)
 
 			var dbVolume *db.StorageVolume
 			err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -1100,9 +1138,17 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 		}
 
 		return &runConf, nil
-	} else if d.config["source"] == diskSourceCloudInit {
-		// This is a special virtual disk source that can be attached to a VM to provide cloud-init config.
-		isoPath, err := d.generateVMConfigDrive()
+	} else if d.config["source"] == diskSourceCloudInit || d.config["source"] == diskSourceWindowsSetup {
+		// These are special virtual disk sources that can be attached to a VM to provide cloud-init config or
+		// the Windows setup media.
+		var isoPath string
+		var err error
+		if d.config["source"] == diskSourceCloudInit {
+			isoPath, err = d.generateVMConfigDrive()
+		} else {
+			isoPath, err = d.generateVMWindowsSetupDrive()
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -1170,7 +1216,7 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 
 				// Derive the effective storage project name from the instance config's project.
 				instProj := d.inst.Project()
-				storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+				storageProjectName := d.storageVolumeProject(&instProj, dbVolumeType)
 
 				// GetStoragePoolVolume returns a volume with an empty Location field for remote drivers.
 				var dbVolume *db.StorageVolume
@@ -1694,7 +1740,7 @@ func (d *disk) mountPoolVolume() (func(), string, *storagePools.MountInfo, error
 	}
 
 	instProj := d.inst.Project()
-	storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+	storageProjectName := d.storageVolumeProject(&instProj, dbVolumeType)
 
 	if dbVolumeType == cluster.StoragePoolVolumeTypeVM {
 		diskInst, err := instance.LoadByProjectAndName(d.state, d.inst.Project().Name, volumeName)
@@ -2208,7 +2254,7 @@ func (d *disk) postStop() error {
 
 		// Only custom volumes can be attached currently.
 		instProj := d.inst.Project()
-		storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, dbVolumeType)
+		storageProjectName := d.storageVolumeProject(&instProj, dbVolumeType)
 
 		if dbVolumeType == cluster.StoragePoolVolumeTypeVM {
 			var diskInst instance.Instance
@@ -2677,6 +2723,90 @@ func (d *disk) generateVMConfigDrive() (string, error) {
 	return isoPath, nil
 }
 
+// windowsSetupAgentInstall is the PowerShell script installing the lxd-agent from the Windows setup drive.
+const windowsSetupAgentInstall = `$ErrorActionPreference = "Stop"
+$Target = Join-Path $env:ProgramFiles "LXD"
+
+if (-not (Test-Path (Join-Path $PSScriptRoot "lxd-agent.exe"))) {
+    Write-Error "lxd-agent.exe isn't available on this setup media"
+}
+
+# Copy the agent and its certificates.
+New-Item -ItemType Directory -Force -Path $Target | Out-Null
+Copy-Item -Path (Join-Path $PSScriptRoot "*") -Destination $Target -Force
+
+# Register the agent as a service started on boot.
+if (-not (Get-Service -Name "lxd-agent" -ErrorAction SilentlyContinue)) {
+    New-Service -Name "lxd-agent" -DisplayName "LXD - agent" -BinaryPathName "` + "`" + `"$Target\lxd-agent.exe` + "`" + `"" -StartupType Automatic | Out-Null
+}
+
+Start-Service -Name "lxd-agent"
+`
+
+// generateVMWindowsSetupDrive generates an ISO containing the answer file and the lxd-agent install media used when
+// installing Windows in a VM.
+// Returns the path to the ISO.
+func (d *disk) generateVMWindowsSetupDrive() (string, error) {
+	scratchDir := filepath.Join(d.inst.DevicesPath(), filesystem.PathNameEncode(d.name))
+
+	// Check we have the mkisofs tool available.
+	mkisofsPath, err := exec.LookPath("mkisofs")
+	if err != nil {
+		return "", err
+	}
+
+	// Start from a clean directory so that no stale file ends up on the drive.
+	_ = os.RemoveAll(scratchDir)
+	err = os.MkdirAll(filepath.Join(scratchDir, "lxd-agent"), 0700)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = os.RemoveAll(scratchDir) }()
+
+	// Windows setup looks for an answer file at the root of removable drives.
+	unattend := d.inst.ExpandedConfig()["windows.unattend"]
+	if unattend != "" {
+		err = os.WriteFile(filepath.Join(scratchDir, "Autounattend.xml"), []byte(unattend), 0400)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Include the agent certificates from the config share generated before the devices are started.
+	for _, name := range []string{"agent.crt", "agent.key", "server.crt"} {
+		err = shared.FileCopy(filepath.Join(d.inst.Path(), "config", name), filepath.Join(scratchDir, "lxd-agent", name))
+		if err != nil {
+			return "", fmt.Errorf("Failed copying agent certificate %q: %w", name, err)
+		}
+	}
+
+	// Include the Windows build of the agent if available on the host.
+	agentPath, err := exec.LookPath("lxd-agent.exe")
+	if err != nil {
+		d.logger.Warn("lxd-agent.exe not found, skipping its inclusion in the Windows setup drive", logger.Ctx{"err": err})
+	} else {
+		err = shared.FileCopy(agentPath, filepath.Join(scratchDir, "lxd-agent", "lxd-agent.exe"))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = os.WriteFile(filepath.Join(scratchDir, "lxd-agent", "install.ps1"), []byte(windowsSetupAgentInstall), 0400)
+	if err != nil {
+		return "", err
+	}
+
+	// The volume label allows scripts in the answer file to find the drive regardless of its letter.
+	isoPath := filepath.Join(d.inst.Path(), "windows-setup.iso")
+	_, err = shared.RunCommandContext(context.TODO(), mkisofsPath, "-joliet", "-rock", "-input-charset", "utf8", "-output-charset", "utf8", "-volid", "LXD_SETUP", "-o", isoPath, scratchDir)
+	if err != nil {
+		return "", err
+	}
+
+	return isoPath, nil
+}
+
 // cephCreds returns cluster name and user name to use for ceph disks.
 func (d *disk) cephCreds() (clusterName string, userName string) {
 	// Apply the ceph configuration.
@@ -2695,8 +2825,10 @@ func (d *disk) cephCreds() (clusterName string, userName string) {
 
 // Remove cleans up the device when it is removed from an instance.
 func (d *disk) Remove() error {
-	// Remove the config.iso file for cloud-init config drives.
-	if d.config["source"] == diskSourceCloudInit {
+	// Remove the config.iso file for cloud-init config drives and the windows-setup.iso file for Windows setup
+	// drives.
+	isoName := map[string]string{diskSourceCloudInit: "config.iso", diskSourceWindowsSetup: "windows-setup.iso"}[d.config["source"]]
+	if isoName != "" {
 		pool, err := storagePools.LoadByInstance(d.state, d.inst)
 		if err != nil {
 			return err
@@ -2709,10 +2841,10 @@ func (d *disk) Remove() error {
 
 		defer func() { _ = pool.UnmountInstance(d.inst, nil) }()
 
-		isoPath := filepath.Join(d.inst.Path(), "config.iso")
+		isoPath := filepath.Join(d.inst.Path(), isoName)
 		err = os.Remove(isoPath)
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("Failed removing %s file: %w", diskSourceCloudInit, err)
+			return fmt.Errorf("Failed removing %s file: %w", d.config["source"], err)
 		}
 	}
 
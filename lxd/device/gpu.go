@@ -113,6 +113,14 @@ func gpuValidationRules(requiredFields []string, optionalFields []string) map[st
 		//  type: string
 		//  shortdesc: Existing MIG device UUID
 		"mig.uuid": gpuValidMigUUID,
+		// lxdmeta:generate(entities=device-gpu-mig; group=device-conf; key=mig.profile)
+		// A MIG GPU instance and compute instance of this profile are created on the GPU when the instance starts
+		// and destroyed when it stops.
+		// For example: `1g.5gb`
+		// ---
+		//  type: string
+		//  shortdesc: MIG profile to create the MIG device from
+		"mig.profile": validate.IsAny,
 		// lxdmeta:generate(entities=device-gpu-mdev; group=device-conf; key=mdev)
 		// For example: `i915-GVTg_V5_4`
 		// ---
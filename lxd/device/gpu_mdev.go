@@ -15,6 +15,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/resources"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 )
@@ -65,92 +66,35 @@ func (d *gpuMdev) startVM() (*deviceConfig.RunConfig, error) {
 	revert := revert.New()
 	defer revert.Fail()
 
-	var pciAddress string
-	for _, gpu := range gpus.Cards {
-		// Skip any cards that are not selected.
-		if !gpuSelected(d.Config(), gpu) {
-			continue
-		}
-
-		if pciAddress != "" {
-			return nil, errors.New("VMs cannot match multiple GPUs per device")
-		}
-
-		pciAddress = gpu.PCIAddress
+	// Select the GPU (or virtual function) to create the vGPU on, reusing the existing one if still present.
+	pciAddress, err := gpuMdevSelect(gpus.Cards, d.config, gpuMdevParent(mdevUUID))
+	if err != nil {
+		return nil, err
+	}
 
-		// Look for the requested mdev profile on the GPU itself.
-		mdevFound := false
-		mdevAvailable := false
-		for k, v := range gpu.Mdev {
-			if d.config["mdev"] == k {
-				mdevFound = true
-				if v.Available > 0 {
-					mdevAvailable = true
-				}
+	// Create the vGPU.
+	if mdevUUID == "" || !shared.PathExists(fmt.Sprintf("/sys/bus/pci/devices/%s/%s", pciAddress, mdevUUID)) {
+		mdevUUID = uuid.New().String()
 
-				break
+		err = os.WriteFile(filepath.Join(fmt.Sprintf("/sys/bus/pci/devices/%s/mdev_supported_types/%s/create", pciAddress, d.config["mdev"])), []byte(mdevUUID), 0200)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("The requested profile %q does not exist", d.config["mdev"])
 			}
-		}
-
-		// If no mdev found on the GPU and SR-IOV is present, look on the VFs.
-		if !mdevFound && gpu.SRIOV != nil {
-			for _, vf := range gpu.SRIOV.VFs {
-				for k, v := range vf.Mdev {
-					if d.config["mdev"] == k {
-						mdevFound = true
-						if v.Available > 0 {
-							mdevAvailable = true
-
-							// Replace the PCI address with that of the VF.
-							pciAddress = vf.PCIAddress
-						}
-
-						break
-					}
-				}
 
-				if mdevAvailable {
-					break
-				}
-			}
+			return nil, fmt.Errorf("Failed to create virtual gpu %q: %w", mdevUUID, err)
 		}
 
-		if !mdevFound {
-			return nil, fmt.Errorf("Invalid mdev profile %q", d.config["mdev"])
-		}
+		revert.Add(func() {
+			path := "/sys/bus/mdev/devices/" + mdevUUID
 
-		if !mdevAvailable {
-			return nil, fmt.Errorf("No available mdev for profile %q", d.config["mdev"])
-		}
-
-		// Create the vGPU.
-		if mdevUUID == "" || !shared.PathExists(fmt.Sprintf("/sys/bus/pci/devices/%s/%s", pciAddress, mdevUUID)) {
-			mdevUUID = uuid.New().String()
-
-			err = os.WriteFile(filepath.Join(fmt.Sprintf("/sys/bus/pci/devices/%s/mdev_supported_types/%s/create", pciAddress, d.config["mdev"])), []byte(mdevUUID), 0200)
-			if err != nil {
-				if os.IsNotExist(err) {
-					return nil, fmt.Errorf("The requested profile %q does not exist", d.config["mdev"])
+			if shared.PathExists(path) {
+				err := os.WriteFile(filepath.Join(path, "remove"), []byte("1\n"), 0200)
+				if err != nil {
+					d.logger.Error("Failed to remove vgpu", logger.Ctx{"device": mdevUUID, "err": err})
 				}
-
-				return nil, fmt.Errorf("Failed to create virtual gpu %q: %w", mdevUUID, err)
 			}
-
-			revert.Add(func() {
-				path := "/sys/bus/mdev/devices/" + mdevUUID
-
-				if shared.PathExists(path) {
-					err := os.WriteFile(filepath.Join(path, "remove"), []byte("1\n"), 0200)
-					if err != nil {
-						d.logger.Error("Failed to remove vgpu", logger.Ctx{"device": mdevUUID, "err": err})
-					}
-				}
-			})
-		}
-	}
-
-	if pciAddress == "" {
-		return nil, errors.New("Failed to detect requested GPU device")
+		})
 	}
 
 	// Get PCI information about the GPU device.
@@ -260,3 +204,75 @@ func (d *gpuMdev) validateEnvironment() error {
 
 	return validatePCIDevice(d.config["pci"])
 }
+
+// gpuMdevParent returns the PCI address of the device the existing vGPU was created on (if any).
+func gpuMdevParent(mdevUUID string) string {
+	if mdevUUID == "" {
+		return ""
+	}
+
+	devicePath, err := filepath.EvalSymlinks("/sys/bus/mdev/devices/" + mdevUUID)
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(filepath.Dir(devicePath))
+}
+
+// gpuMdevSelect returns the PCI address of the GPU (or virtual function) to create the requested mdev profile on.
+// When several GPUs match the device configuration, they are treated as a pool and the one with the most
+// available instances of the profile is picked, unless the preferred address is part of the candidates.
+func gpuMdevSelect(cards []api.ResourcesGPUCard, config deviceConfig.Device, preferred string) (string, error) {
+	gpuFound := false
+	mdevFound := false
+	pciAddress := ""
+	var available uint64
+
+	for _, gpu := range cards {
+		// Skip any cards that are not selected.
+		if !gpuSelected(config, gpu) {
+			continue
+		}
+
+		gpuFound = true
+
+		// Look for the requested mdev profile on the GPU itself and, failing that, on its VFs.
+		candidates := []api.ResourcesGPUCard{gpu}
+		_, ok := gpu.Mdev[config["mdev"]]
+		if !ok && gpu.SRIOV != nil {
+			candidates = gpu.SRIOV.VFs
+		}
+
+		for _, candidate := range candidates {
+			mdev, ok := candidate.Mdev[config["mdev"]]
+			if !ok {
+				continue
+			}
+
+			mdevFound = true
+
+			if preferred != "" && candidate.PCIAddress == preferred {
+				return preferred, nil
+			}
+
+			if mdev.Available > available {
+				pciAddress = candidate.PCIAddress
+				available = mdev.Available
+			}
+		}
+	}
+
+	if !gpuFound {
+		return "", errors.New("Failed to detect requested GPU device")
+	}
+
+	if !mdevFound {
+		return "", fmt.Errorf("Invalid mdev profile %q", config["mdev"])
+	}
+
+	if pciAddress == "" {
+		return "", fmt.Errorf("No available mdev for profile %q", config["mdev"])
+	}
+
+	return pciAddress, nil
+}
@@ -1,10 +1,17 @@
 package device
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	pcidev "github.com/canonical/lxd/lxd/device/pci"
 	"github.com/canonical/lxd/lxd/instance"
@@ -12,8 +19,16 @@ import (
 	"github.com/canonical/lxd/lxd/resources"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
 )
 
+// gpuMIGMu prevents concurrent creation of MIG instances, as they are placed by the NVIDIA driver.
+var gpuMIGMu sync.Mutex
+
+// gpuMIGCreatedRegex matches the IDs of the GPU and compute instances created by `nvidia-smi mig -cgi -C`.
+var gpuMIGCreatedRegex = regexp.MustCompile(`created (GPU|compute) instance ID\s+(\d+)`)
+
 type gpuMIG struct {
 	deviceCommon
 }
@@ -37,6 +52,7 @@ func (d *gpuMIG) validateConfig(instConf instance.ConfigReader) error {
 		"mig.gi",
 		"mig.ci",
 		"mig.uuid",
+		"mig.profile",
 	}
 
 	err := d.config.Validate(gpuValidationRules(requiredFields, optionalFields))
@@ -62,14 +78,20 @@ func (d *gpuMIG) validateConfig(instConf instance.ConfigReader) error {
 		}
 	}
 
-	if d.config["mig.uuid"] != "" {
+	if d.config["mig.profile"] != "" {
+		for _, field := range []string{"mig.uuid", "mig.gi", "mig.ci"} {
+			if d.config[field] != "" {
+				return fmt.Errorf(`Cannot use %q when "mig.profile" is set`, field)
+			}
+		}
+	} else if d.config["mig.uuid"] != "" {
 		for _, field := range []string{"mig.gi", "mig.ci"} {
 			if d.config[field] != "" {
 				return fmt.Errorf(`Cannot use %q when "mig.uuid" is set`, field)
 			}
 		}
 	} else if d.config["mig.gi"] == "" || d.config["mig.ci"] == "" {
-		return errors.New(`Either "mig.uuid" or both "mig.gi" and "mig.ci" must be set`)
+		return errors.New(`Either "mig.profile", "mig.uuid" or both "mig.gi" and "mig.ci" must be set`)
 	}
 
 	return nil
@@ -85,7 +107,7 @@ func (d *gpuMIG) validateEnvironment() error {
 }
 
 // buildMIGDeviceName builds the name of the MIG device based on old/new format.
-func (d *gpuMIG) buildMIGDeviceName(gpu api.ResourcesGPUCard) string {
+func (d *gpuMIG) buildMIGDeviceName(gpu api.ResourcesGPUCard, gi string, ci string) string {
 	if d.config["mig.uuid"] != "" {
 		if strings.HasPrefix(d.config["mig.uuid"], "MIG-") {
 			return d.config["mig.uuid"]
@@ -94,7 +116,160 @@ func (d *gpuMIG) buildMIGDeviceName(gpu api.ResourcesGPUCard) string {
 		return "MIG-" + d.config["mig.uuid"]
 	}
 
-	return fmt.Sprintf("MIG-%s/%s/%s", gpu.Nvidia.UUID, d.config["mig.gi"], d.config["mig.ci"])
+	return fmt.Sprintf("MIG-%s/%s/%s", gpu.Nvidia.UUID, gi, ci)
+}
+
+// gpuMIGIdentifier returns the identifier of the GPU used with nvidia-smi.
+func gpuMIGIdentifier(gpu api.ResourcesGPUCard) string {
+	if gpu.Nvidia.UUID != "" {
+		return gpu.Nvidia.UUID
+	}
+
+	return gpu.PCIAddress
+}
+
+// gpuMIGCreate creates a MIG GPU instance of the given profile on the GPU along with a compute instance using
+// all of its resources, and returns their IDs.
+func gpuMIGCreate(gpu string, profile string) (gi int, ci int, err error) {
+	output, err := shared.RunCommandCLocale("nvidia-smi", "mig", "-i", gpu, "-cgi", profile, "-C")
+	if err != nil {
+		return -1, -1, fmt.Errorf("Failed creating MIG instance of profile %q on GPU %q: %w", profile, gpu, err)
+	}
+
+	gi = -1
+	ci = -1
+	for _, match := range gpuMIGCreatedRegex.FindAllStringSubmatch(output, -1) {
+		id, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+
+		if match[1] == "GPU" {
+			gi = id
+		} else {
+			ci = id
+		}
+	}
+
+	if gi < 0 || ci < 0 {
+		if gi >= 0 {
+			_ = gpuMIGDestroy(gpu, gi, -1)
+		}
+
+		return -1, -1, fmt.Errorf("Failed parsing the MIG instance IDs from nvidia-smi output: %q", output)
+	}
+
+	return gi, ci, nil
+}
+
+// gpuMIGDestroy destroys the MIG compute instance (if ci isn't negative) and GPU instance on the GPU.
+func gpuMIGDestroy(gpu string, gi int, ci int) error {
+	if ci >= 0 {
+		_, err := shared.RunCommandCLocale("nvidia-smi", "mig", "-i", gpu, "-gi", strconv.Itoa(gi), "-ci", strconv.Itoa(ci), "-dci")
+		if err != nil {
+			return fmt.Errorf("Failed destroying MIG compute instance %d/%d on GPU %q: %w", gi, ci, gpu, err)
+		}
+	}
+
+	_, err := shared.RunCommandCLocale("nvidia-smi", "mig", "-i", gpu, "-gi", strconv.Itoa(gi), "-dgi")
+	if err != nil {
+		return fmt.Errorf("Failed destroying MIG GPU instance %d on GPU %q: %w", gi, gpu, err)
+	}
+
+	return nil
+}
+
+// releaseMIG destroys the MIG instance allocated for the device (if any) and removes its record.
+func (d *gpuMIG) releaseMIG() error {
+	var allocation *cluster.GPUMIGAllocation
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		allocation, err = cluster.GetGPUMIGAllocation(ctx, tx.Tx(), d.inst.ID(), d.name)
+
+		return err
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	// Only destroy MIG instances created on this member, the record of others is stale.
+	if allocation.NodeID == d.state.DB.Cluster.GetNodeID() {
+		err = gpuMIGDestroy(allocation.GPU, allocation.GPUInstanceID, allocation.ComputeInstanceID)
+		if err != nil {
+			d.logger.Warn("Failed destroying MIG instance", logger.Ctx{"gpu": allocation.GPU, "gi": allocation.GPUInstanceID, "ci": allocation.ComputeInstanceID, "err": err})
+		}
+	}
+
+	return d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return cluster.DeleteGPUMIGAllocation(ctx, tx.Tx(), d.inst.ID(), d.name)
+	})
+}
+
+// allocateMIG creates a MIG instance of the configured profile on the GPU and records it.
+func (d *gpuMIG) allocateMIG(gpu api.ResourcesGPUCard) (gi string, ci string, err error) {
+	gpuMIGMu.Lock()
+	defer gpuMIGMu.Unlock()
+
+	// Clean up any leftover allocation, for example after a crash.
+	err = d.releaseMIG()
+	if err != nil {
+		return "", "", err
+	}
+
+	profile := d.config["mig.profile"]
+	if gpu.Nvidia.MIG == nil {
+		return "", "", fmt.Errorf("MIG isn't enabled on GPU %q", gpu.PCIAddress)
+	}
+
+	migProfile, ok := gpu.Nvidia.MIG.Profiles[profile]
+	if !ok {
+		return "", "", fmt.Errorf("The requested MIG profile %q does not exist on GPU %q", profile, gpu.PCIAddress)
+	}
+
+	if migProfile.Available == 0 {
+		return "", "", fmt.Errorf("No MIG instance of profile %q available on GPU %q", profile, gpu.PCIAddress)
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	gpuID := gpuMIGIdentifier(gpu)
+
+	giID, ciID, err := gpuMIGCreate(gpuID, profile)
+	if err != nil {
+		return "", "", err
+	}
+
+	revert.Add(func() { _ = gpuMIGDestroy(gpuID, giID, ciID) })
+
+	allocation := cluster.GPUMIGAllocation{
+		NodeID:            d.state.DB.Cluster.GetNodeID(),
+		GPU:               gpuID,
+		Profile:           profile,
+		GPUInstanceID:     giID,
+		ComputeInstanceID: ciID,
+		InstanceID:        d.inst.ID(),
+		DeviceName:        d.name,
+	}
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := cluster.CreateGPUMIGAllocation(ctx, tx.Tx(), allocation)
+
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("Failed recording MIG allocation: %w", err)
+	}
+
+	revert.Success()
+
+	return strconv.Itoa(giID), strconv.Itoa(ciID), nil
 }
 
 // CanHotPlug returns whether the device can be managed whilst the instance is running,.
@@ -112,6 +287,9 @@ func (d *gpuMIG) Start() (*deviceConfig.RunConfig, error) {
 
 	runConf := deviceConfig.RunConfig{}
 
+	revert := revert.New()
+	defer revert.Fail()
+
 	// Get all the GPUs.
 	gpus, err := resources.GetGPU()
 	if err != nil {
@@ -145,14 +323,27 @@ func (d *gpuMIG) Start() (*deviceConfig.RunConfig, error) {
 
 		gpuID := fields[1]
 
+		gi := d.config["mig.gi"]
+		ci := d.config["mig.ci"]
+
+		// Create the MIG instance on demand.
+		if d.config["mig.profile"] != "" {
+			gi, ci, err = d.allocateMIG(gpu)
+			if err != nil {
+				return nil, err
+			}
+
+			revert.Add(func() { _ = d.releaseMIG() })
+		}
+
 		if d.config["mig.uuid"] == "" {
-			if !shared.PathExists(fmt.Sprintf("/proc/driver/nvidia/capabilities/gpu%s/mig/gi%s/ci%s/access", gpuID, d.config["mig.gi"], d.config["mig.ci"])) {
-				return nil, fmt.Errorf("MIG device gi=%s ci=%s doesn't exist on GPU %s", d.config["mig.gi"], d.config["mig.ci"], gpuID)
+			if !shared.PathExists(fmt.Sprintf("/proc/driver/nvidia/capabilities/gpu%s/mig/gi%s/ci%s/access", gpuID, gi, ci)) {
+				return nil, fmt.Errorf("MIG device gi=%s ci=%s doesn't exist on GPU %s", gi, ci, gpuID)
 			}
 		}
 
 		runConf.GPUDevice = append(runConf.GPUDevice, []deviceConfig.RunConfigItem{
-			{Key: GPUNvidiaDeviceKey, Value: d.buildMIGDeviceName(gpu)},
+			{Key: GPUNvidiaDeviceKey, Value: d.buildMIGDeviceName(gpu, gi, ci)},
 		}...)
 	}
 
@@ -160,10 +351,28 @@ func (d *gpuMIG) Start() (*deviceConfig.RunConfig, error) {
 		return nil, errors.New("Failed to detect requested GPU device")
 	}
 
+	revert.Success()
+
 	return &runConf, nil
 }
 
 // Stop is run when the device is removed from the instance.
 func (d *gpuMIG) Stop() (*deviceConfig.RunConfig, error) {
-	return nil, nil
+	if d.config["mig.profile"] == "" {
+		return nil, nil
+	}
+
+	runConf := deviceConfig.RunConfig{
+		PostHooks: []func() error{d.postStop},
+	}
+
+	return &runConf, nil
+}
+
+// postStop is run after the device is removed from the instance.
+func (d *gpuMIG) postStop() error {
+	gpuMIGMu.Lock()
+	defer gpuMIGMu.Unlock()
+
+	return d.releaseMIG()
 }
@@ -74,6 +74,15 @@ func nicValidationRules(requiredFields []string, optionalFields []string, instCo
 		//  required: yes
 		//  shortdesc: Managed network to link the device to
 		"network": validate.IsAny,
+		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=network.project)
+		// The network must be shared with the project of the instance with `attach` access.
+		// See {ref}`projects-shares`.
+		// ---
+		//  type: string
+		//  managed: no
+		//  defaultdesc: network project of the instance
+		//  shortdesc: Project of the shared network to link the device to
+		"network.project": validate.IsAny,
 		// lxdmeta:generate(entities=device-nic-{bridged+macvlan}; group=device-conf; key=mtu)
 		//
 		// ---
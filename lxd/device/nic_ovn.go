@@ -15,6 +15,7 @@ import (
 	"github.com/mdlayher/netx/eui64"
 
 	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	pcidev "github.com/canonical/lxd/lxd/device/pci"
 	"github.com/canonical/lxd/lxd/dnsmasq/dhcpalloc"
@@ -29,6 +30,7 @@ import (
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 )
@@ -101,6 +103,7 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		"acceleration",
 		"nested",
 		"vlan",
+		"network.project",
 	}
 
 	// The NIC's network may be a non-default project, so lookup project and get network's project name.
@@ -109,6 +112,11 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		return fmt.Errorf("Failed loading network project name: %w", err)
 	}
 
+	// Networks shared by another project are referenced using "network.project".
+	if d.config["network.project"] != "" {
+		networkProjectName = d.config["network.project"]
+	}
+
 	// Lookup network settings and apply them to the device's config.
 	n, err := network.LoadByName(d.state, networkProjectName, d.config["network"])
 	if err != nil {
@@ -123,6 +131,16 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		return errors.New("Specified network must be of type ovn")
 	}
 
+	// Networks of other projects must be shared with the project of the instance for attaching.
+	if d.config["network.project"] != "" && networkProjectName != instConf.Project().Name {
+		err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return cluster.CheckProjectShareAttach(ctx, tx.Tx(), entity.TypeNetwork, int(n.ID()), instConf.Project().Name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	bannedKeys := []string{"mtu"}
 	for _, bannedKey := range bannedKeys {
 		if d.config[bannedKey] != "" {
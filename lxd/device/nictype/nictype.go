@@ -27,6 +27,11 @@ func NICType(s *state.State, deviceProjectName string, d deviceConfig.Device) (s
 				return "", fmt.Errorf("Failed to translate device project %q into network project: %w", deviceProjectName, err)
 			}
 
+			// Networks shared by another project are referenced using "network.project".
+			if d["network.project"] != "" {
+				networkProjectName = d["network.project"]
+			}
+
 			var netInfo *api.Network
 
 			err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -0,0 +1,316 @@
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// secretProjectConfigPrefix is the prefix of the project configuration keys holding the secret values.
+const secretProjectConfigPrefix = "secret."
+
+type secret struct {
+	deviceCommon
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *secret) CanMigrate() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *secret) validateConfig(instConf instance.ConfigReader) error {
+	if !instanceSupported(instConf.Type(), instancetype.Container, instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// lxdmeta:generate(entities=device-secret; group=device-conf; key=source)
+		// The value is read from the `secret.<name>` configuration key of the instance's project.
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Name of the project secret
+		"source": validate.IsAny,
+
+		// lxdmeta:generate(entities=device-secret; group=device-conf; key=path)
+		// For example: `/run/secrets/db_password`
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Path of the file inside the instance
+		"path": validate.IsAny,
+
+		// lxdmeta:generate(entities=device-secret; group=device-conf; key=uid)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `0`
+		//  shortdesc: UID of the file owner in the instance
+		"uid": unixValidUserID,
+
+		// lxdmeta:generate(entities=device-secret; group=device-conf; key=gid)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `0`
+		//  shortdesc: GID of the file owner in the instance
+		"gid": unixValidUserID,
+
+		// lxdmeta:generate(entities=device-secret; group=device-conf; key=mode)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `0400`
+		//  shortdesc: Mode of the file in the instance
+		"mode": unixValidOctalFileMode,
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	if d.config["source"] == "" {
+		return fmt.Errorf(`Missing source property for secret device %q`, d.name)
+	}
+
+	err = validate.IsAbsFilePath(d.config["path"])
+	if err != nil {
+		return fmt.Errorf("Invalid path for secret device %q: %w", d.name, err)
+	}
+
+	if d.config["path"] == "/" {
+		return fmt.Errorf(`Invalid path for secret device %q: Path cannot be "/"`, d.name)
+	}
+
+	return nil
+}
+
+// secretValue returns the value of the project secret referenced by the device.
+func (d *secret) secretValue() ([]byte, error) {
+	value, ok := d.inst.Project().Config[secretProjectConfigPrefix+d.config["source"]]
+	if !ok {
+		return nil, fmt.Errorf("Secret %q not found in project %q", d.config["source"], d.inst.Project().Name)
+	}
+
+	return []byte(value), nil
+}
+
+// fileOwnership returns the mode, UID and GID of the secret file inside the instance.
+func (d *secret) fileOwnership() (mode os.FileMode, uid int, gid int, err error) {
+	mode = 0400
+	if d.config["mode"] != "" {
+		rawMode, err := strconv.ParseUint(d.config["mode"], 8, 32)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("Invalid mode %q: %w", d.config["mode"], err)
+		}
+
+		mode = os.FileMode(rawMode).Perm()
+	}
+
+	if d.config["uid"] != "" {
+		uid, err = strconv.Atoi(d.config["uid"])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("Invalid UID %q: %w", d.config["uid"], err)
+		}
+	}
+
+	if d.config["gid"] != "" {
+		gid, err = strconv.Atoi(d.config["gid"])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("Invalid GID %q: %w", d.config["gid"], err)
+		}
+	}
+
+	return mode, uid, gid, nil
+}
+
+// Start is run when the device is added to the instance.
+func (d *secret) Start() (*deviceConfig.RunConfig, error) {
+	value, err := d.secretValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.inst.Type() == instancetype.VM {
+		return d.startVM(value)
+	}
+
+	return d.startContainer(value)
+}
+
+// hostPath returns the path of the host-side tmpfs holding the secret of a container.
+func (d *secret) hostPath() string {
+	return filepath.Join(d.inst.DevicesPath(), "secret."+filesystem.PathNameEncode(d.name))
+}
+
+// startContainer writes the secret into a dedicated tmpfs and bind mounts it into the container.
+func (d *secret) startContainer(value []byte) (*deviceConfig.RunConfig, error) {
+	mode, uid, gid, err := d.fileOwnership()
+	if err != nil {
+		return nil, err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	hostPath := d.hostPath()
+
+	err = os.MkdirAll(hostPath, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create device path %q: %w", hostPath, err)
+	}
+
+	revert.Add(func() { _ = os.RemoveAll(hostPath) })
+
+	// Keep the secret in memory so that it never hits the disk.
+	err = unix.Mount("tmpfs", hostPath, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "size=1m,mode=0700")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to mount tmpfs on %q: %w", hostPath, err)
+	}
+
+	revert.Add(func() { _ = unix.Unmount(hostPath, unix.MNT_DETACH) })
+
+	secretPath := filepath.Join(hostPath, "secret")
+
+	err = os.WriteFile(secretPath, value, mode)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write secret file %q: %w", secretPath, err)
+	}
+
+	// The UID and GID are relative to the container and get shifted along with the mount.
+	err = os.Chown(secretPath, uid, gid)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to chown secret file %q: %w", secretPath, err)
+	}
+
+	// Needed as the file creation respects the umask.
+	err = os.Chmod(secretPath, mode)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to chmod secret file %q: %w", secretPath, err)
+	}
+
+	runConf := deviceConfig.RunConfig{}
+	runConf.Mounts = append(runConf.Mounts, deviceConfig.MountEntryItem{
+		DevName:    d.name,
+		DevSource:  deviceConfig.DevSourcePath{Path: secretPath},
+		TargetPath: strings.TrimPrefix(d.config["path"], "/"),
+		FSType:     "none",
+		Opts:       []string{"bind", "ro", "create=file"},
+		OwnerShift: deviceConfig.MountOwnerShiftStatic,
+	})
+
+	// Detach the host-side tmpfs once the secret is mounted in the container.
+	runConf.PostHooks = append(runConf.PostHooks, d.postStart)
+
+	revert.Success()
+	return &runConf, nil
+}
+
+// vmConfigPath returns the path of the file passing the secret to the VM agent.
+func (d *secret) vmConfigPath() string {
+	return filepath.Join(d.inst.Path(), "config", deviceConfig.SecretConfigDir, filesystem.PathNameEncode(d.name)+".json")
+}
+
+// startVM passes the secret to the VM agent through the config drive.
+func (d *secret) startVM(value []byte) (*deviceConfig.RunConfig, error) {
+	mode, uid, gid, err := d.fileOwnership()
+	if err != nil {
+		return nil, err
+	}
+
+	secretConfig := deviceConfig.SecretConfig{
+		DeviceName: d.name,
+		Path:       d.config["path"],
+		Mode:       uint32(mode),
+		UID:        uid,
+		GID:        gid,
+		Data:       value,
+	}
+
+	secretJSON, err := json.Marshal(secretConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding secret config: %w", err)
+	}
+
+	secretFile := d.vmConfigPath()
+
+	err = os.WriteFile(secretFile, secretJSON, 0400)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing secret config file %q: %w", secretFile, err)
+	}
+
+	return &deviceConfig.RunConfig{}, nil
+}
+
+// postStart is run after the instance is started.
+func (d *secret) postStart() error {
+	// The container keeps its own reference to the tmpfs.
+	return d.clearHostPath()
+}
+
+// clearHostPath unmounts and removes the host-side tmpfs of a container secret.
+func (d *secret) clearHostPath() error {
+	hostPath := d.hostPath()
+	if !shared.PathExists(hostPath) {
+		return nil
+	}
+
+	if filesystem.IsMountPoint(hostPath) {
+		err := unix.Unmount(hostPath, unix.MNT_DETACH)
+		if err != nil {
+			return fmt.Errorf("Failed to unmount %q: %w", hostPath, err)
+		}
+	}
+
+	err := os.RemoveAll(hostPath)
+	if err != nil {
+		return fmt.Errorf("Failed to remove %q: %w", hostPath, err)
+	}
+
+	return nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *secret) Stop() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{
+		PostHooks: []func() error{d.postStop},
+	}
+
+	if d.inst.Type() == instancetype.Container {
+		// Request an unmount of the secret inside the container.
+		runConf.Mounts = append(runConf.Mounts, deviceConfig.MountEntryItem{
+			TargetPath: strings.TrimPrefix(d.config["path"], "/"),
+		})
+	}
+
+	return &runConf, nil
+}
+
+// postStop is run after the device is removed from the instance.
+func (d *secret) postStop() error {
+	if d.inst.Type() == instancetype.VM {
+		err := os.Remove(d.vmConfigPath())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed removing secret config file: %w", err)
+		}
+
+		return nil
+	}
+
+	return d.clearHostPath()
+}
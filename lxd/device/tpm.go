@@ -1,10 +1,14 @@
 package device
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,6 +22,7 @@ import (
 	"github.com/canonical/lxd/lxd/subprocess"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/validate"
 )
@@ -97,7 +102,7 @@ func (d *tpm) Start() (*deviceConfig.RunConfig, error) {
 		return nil, fmt.Errorf("Failed to validate environment: %w", err)
 	}
 
-	tpmDevPath := filepath.Join(d.inst.Path(), "tpm."+filesystem.PathNameEncode(d.name))
+	tpmDevPath := TPMStatePath(d.inst, d.name)
 
 	if !shared.PathExists(tpmDevPath) {
 		err := os.Mkdir(tpmDevPath, 0700)
@@ -115,7 +120,7 @@ func (d *tpm) Start() (*deviceConfig.RunConfig, error) {
 
 func (d *tpm) startContainer() (*deviceConfig.RunConfig, error) {
 	escapedDeviceName := filesystem.PathNameEncode(d.name)
-	tpmDevPath := filepath.Join(d.inst.Path(), "tpm."+escapedDeviceName)
+	tpmDevPath := TPMStatePath(d.inst, d.name)
 	logFileName := fmt.Sprintf("tpm.%s.log", escapedDeviceName)
 	logPath := filepath.Join(d.inst.LogPath(), logFileName)
 
@@ -214,7 +219,7 @@ func (d *tpm) startVM() (*deviceConfig.RunConfig, error) {
 	defer revert.Fail()
 
 	escapedDeviceName := filesystem.PathNameEncode(d.name)
-	tpmDevPath := filepath.Join(d.inst.Path(), "tpm."+escapedDeviceName)
+	tpmDevPath := TPMStatePath(d.inst, d.name)
 	socketPath := filepath.Join(tpmDevPath, fmt.Sprintf("swtpm-%s.sock", escapedDeviceName))
 	runConf := deviceConfig.RunConfig{
 		TPMDevice: []deviceConfig.RunConfigItem{
@@ -329,7 +334,145 @@ func (d *tpm) Stop() (*deviceConfig.RunConfig, error) {
 
 // Remove removes the TPM state file.
 func (d *tpm) Remove() error {
-	tpmDevPath := filepath.Join(d.inst.Path(), "tpm."+d.name)
+	return os.RemoveAll(TPMStatePath(d.inst, d.name))
+}
+
+// TPMStatePath returns the path of the directory holding the emulated TPM state of the instance's device.
+// The directory lives on the instance volume so that it's included in its snapshots, backups and migrations.
+func TPMStatePath(inst instance.Instance, devName string) string {
+	return filepath.Join(inst.Path(), "tpm."+filesystem.PathNameEncode(devName))
+}
+
+// TPMStateExport writes the emulated TPM state found in statePath to w as a compressed tarball.
+func TPMStateExport(statePath string, w io.Writer) error {
+	entries, err := os.ReadDir(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return api.StatusErrorf(http.StatusNotFound, "The TPM has no state")
+		}
+
+		return fmt.Errorf("Failed listing TPM state files: %w", err)
+	}
+
+	gzWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	for _, entry := range entries {
+		// The swtpm state is made of regular files in a flat directory, skip its sockets and lock files.
+		if !entry.Type().IsRegular() || strings.HasSuffix(entry.Name(), ".lock") {
+			continue
+		}
+
+		err := tpmStateExportFile(tarWriter, filepath.Join(statePath, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzWriter.Close()
+}
+
+// tpmStateExportFile adds a TPM state file to the tarball.
+func tpmStateExportFile(tarWriter *tar.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Failed opening TPM state file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.WriteHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tarWriter, f)
+	if err != nil {
+		return fmt.Errorf("Failed exporting TPM state file %q: %w", info.Name(), err)
+	}
+
+	return nil
+}
 
-	return os.RemoveAll(tpmDevPath)
+// TPMStateImport replaces the emulated TPM state in statePath with the content of the tarball read from r
+// (as written by TPMStateExport).
+func TPMStateImport(statePath string, r io.Reader) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Extract next to the current state so that it's only replaced once the import succeeded.
+	importPath := statePath + ".import"
+
+	err := os.RemoveAll(importPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Mkdir(importPath, 0700)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = os.RemoveAll(importPath) })
+
+	gzReader, err := gzip.NewReader(r)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid TPM state: %v", err)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid TPM state: %v", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !shared.IsFileName(hdr.Name) {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid TPM state file %q", hdr.Name)
+		}
+
+		f, err := os.OpenFile(filepath.Join(importPath, hdr.Name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed creating TPM state file %q: %w", hdr.Name, err)
+		}
+
+		_, err = io.Copy(f, tarReader)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("Failed importing TPM state file %q: %w", hdr.Name, err)
+		}
+	}
+
+	err = os.RemoveAll(statePath)
+	if err != nil {
+		return fmt.Errorf("Failed removing TPM state: %w", err)
+	}
+
+	err = os.Rename(importPath, statePath)
+	if err != nil {
+		return fmt.Errorf("Failed replacing TPM state: %w", err)
+	}
+
+	revert.Success()
+
+	return nil
 }
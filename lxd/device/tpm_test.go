@@ -0,0 +1,86 @@
+package device
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestTPMStateExportImport(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "tpm.vtpm")
+
+	// Exporting a TPM that was never started fails.
+	err := TPMStateExport(statePath, &bytes.Buffer{})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound), "Unexpected error %v", err)
+
+	require.NoError(t, os.Mkdir(statePath, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(statePath, "tpm2-00.permall"), []byte("state"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(statePath, ".lock"), nil, 0600))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, TPMStateExport(statePath, buf))
+
+	// Importing replaces the whole state, leaving no lock nor stale file behind.
+	require.NoError(t, os.WriteFile(filepath.Join(statePath, "stale"), []byte("stale"), 0600))
+	require.NoError(t, TPMStateImport(statePath, buf))
+
+	entries, err := os.ReadDir(statePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "tpm2-00.permall", entries[0].Name())
+
+	content, err := os.ReadFile(filepath.Join(statePath, "tpm2-00.permall"))
+	require.NoError(t, err)
+	assert.Equal(t, "state", string(content))
+	assert.NoDirExists(t, statePath+".import")
+}
+
+func TestTPMStateImportInvalid(t *testing.T) {
+	tarball := func(name string) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		gzWriter := gzip.NewWriter(buf)
+		tarWriter := tar.NewWriter(gzWriter)
+		_ = tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0600, Size: 5})
+		_, _ = tarWriter.Write([]byte("state"))
+		_ = tarWriter.Close()
+		_ = gzWriter.Close()
+
+		return buf
+	}
+
+	tests := []struct {
+		name  string
+		input *bytes.Buffer
+	}{
+		{name: "Not compressed", input: bytes.NewBufferString("state")},
+		{name: "Path traversal", input: tarball("../tpm2-00.permall")},
+		{name: "Sub directory", input: tarball("sub/tpm2-00.permall")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statePath := filepath.Join(t.TempDir(), "tpm.vtpm")
+			require.NoError(t, os.Mkdir(statePath, 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(statePath, "tpm2-00.permall"), []byte("current"), 0600))
+
+			err := TPMStateImport(statePath, tt.input)
+			assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest), "Unexpected error %v", err)
+
+			// The current state is kept.
+			content, err := os.ReadFile(filepath.Join(statePath, "tpm2-00.permall"))
+			require.NoError(t, err)
+			assert.Equal(t, "current", string(content))
+			assert.NoDirExists(t, statePath+".import")
+		})
+	}
+}
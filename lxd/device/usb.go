@@ -80,6 +80,17 @@ func (d *usb) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The device number of the USB device
 		"devnum": validate.Optional(validate.IsUint32),
 
+		// lxdmeta:generate(entities=device-unix-usb; group=device-conf; key=hotplug)
+		// When enabled, matching USB devices that are plugged into the host while the instance is running are
+		// automatically attached to it, and detached when they are unplugged.
+		// Set `serial` to keep following a specific device across reconnections (its bus and device numbers change).
+		// When disabled, only the devices present when the instance starts are attached.
+		// ---
+		//  type: bool
+		//  defaultdesc: `true`
+		//  shortdesc: Whether to attach and detach matching devices as they are plugged into the host
+		"hotplug": validate.Optional(validate.IsBool),
+
 		"uid":      unixValidUserID,
 		"gid":      unixValidUserID,
 		"mode":     unixValidOctalFileMode,
@@ -96,6 +107,11 @@ func (d *usb) validateConfig(instConf instance.ConfigReader) error {
 
 // Register is run after the device is started or when LXD starts.
 func (d *usb) Register() error {
+	// Only follow the devices plugged into the host if requested.
+	if shared.IsFalse(d.config["hotplug"]) {
+		return nil
+	}
+
 	// Extract variables needed to run the event hook so that the reference to this device
 	// struct is not needed to be kept in memory.
 	devicesPath := d.inst.DevicesPath()
@@ -0,0 +1,92 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/osarch"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+type watchdog struct {
+	deviceCommon
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *watchdog) CanMigrate() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *watchdog) validateConfig(instConf instance.ConfigReader) error {
+	if !instanceSupported(instConf.Type(), instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// lxdmeta:generate(entities=device-watchdog; group=device-conf; key=model)
+		// Possible values are `i6300esb` (PCI device) and `itco` (built into the chipset, x86_64 only).
+		// ---
+		//  type: string
+		//  defaultdesc: `i6300esb`
+		//  shortdesc: Model of the emulated watchdog
+		"model": validate.Optional(validate.IsOneOf("i6300esb", "itco")),
+
+		// lxdmeta:generate(entities=device-watchdog; group=device-conf; key=action)
+		// Possible values are `restart`, `poweroff` and `none`.
+		// An `instance-watchdog` lifecycle event is sent when the watchdog expires, regardless of the action.
+		// ---
+		//  type: string
+		//  defaultdesc: `restart`
+		//  shortdesc: Action taken when the watchdog expires
+		"action": validate.Optional(validate.IsOneOf("restart", "poweroff", "none")),
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	if d.config["model"] == "itco" && instConf.Architecture() != osarch.ARCH_64BIT_INTEL_X86 {
+		return errors.New(`The "itco" watchdog model is only supported on x86_64`)
+	}
+
+	// QEMU supports a single watchdog action per VM.
+	for devName, devConfig := range instConf.ExpandedDevices() {
+		if devName != d.name && devConfig["type"] == "watchdog" {
+			return fmt.Errorf("Only one watchdog device can be added to an instance, found %q", devName)
+		}
+	}
+
+	return nil
+}
+
+// Start is run when the device is added to the instance.
+func (d *watchdog) Start() (*deviceConfig.RunConfig, error) {
+	model := d.config["model"]
+	if model == "" {
+		model = "i6300esb"
+	}
+
+	action := d.config["action"]
+	if action == "" {
+		action = "restart"
+	}
+
+	runConf := deviceConfig.RunConfig{}
+	runConf.WatchdogDevice = []deviceConfig.RunConfigItem{
+		{Key: "devName", Value: d.name},
+		{Key: "model", Value: model},
+		{Key: "action", Value: action},
+	}
+
+	return &runConf, nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *watchdog) Stop() (*deviceConfig.RunConfig, error) {
+	return &deviceConfig.RunConfig{}, nil
+}
@@ -542,6 +542,10 @@ func deviceTaskBalance(s *state.State) {
 	for _, c := range instances {
 		conf := c.ExpandedConfig()
 		cpuNodes := conf["limits.cpu.nodes"]
+		if cpuNodes == "balanced" {
+			// Use the NUMA nodes picked when the instance started.
+			cpuNodes = conf["volatile.cpu.nodes"]
+		}
 		numaCpus, err := getNumaCPUs(numaNodeToCPU, cpuNodes)
 		if err != nil {
 			logger.Error("Error parsing numa node set", logger.Ctx{"numaNodes": cpuNodes, "err": err})
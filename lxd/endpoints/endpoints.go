@@ -1,11 +1,13 @@
 package endpoints
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	tomb "gopkg.in/tomb.v2"
@@ -166,6 +168,9 @@ type Endpoints struct {
 	cert      *shared.CertInfo      // Keypair and CA to use for TLS.
 	inherited map[kind]bool         // Store whether the listener came through socket activation
 
+	quic       atomic.Pointer[quicEndpoint] // HTTP/3 server of the REST API, if any.
+	quicConfig atomic.Pointer[tls.Config]   // TLS configuration of the HTTP/3 server.
+
 	systemdListenFDsStart int // First socket activation FD, for tests.
 }
 
@@ -399,6 +404,11 @@ func (e *Endpoints) Down() error {
 		}
 	}
 
+	err := e.quicClose()
+	if err != nil {
+		return err
+	}
+
 	if e.tomb != nil {
 		e.tomb.Kill(nil)
 		_ = e.tomb.Wait()
@@ -144,6 +144,10 @@ func (e *Endpoints) NetworkUpdateCert(cert *shared.CertInfo) {
 			listener.(*listeners.FancyTLSListener).Config(cert)
 		}
 	}
+
+	if e.quic.Load() != nil {
+		e.quicConfig.Store(util.ServerTLSConfig(cert))
+	}
 }
 
 // NetworkUpdateTrustedProxy updates the https trusted proxy used by the network endpoint.
@@ -0,0 +1,202 @@
+package endpoints
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	tomb "gopkg.in/tomb.v2"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// quicEndpoint is the HTTP/3 server serving the REST API over QUIC along with its UDP socket.
+type quicEndpoint struct {
+	server *http3.Server
+	conn   net.PacketConn
+}
+
+func quicCreateListener(address string) (net.PacketConn, error) {
+	// Listening on `udp` network with address 0.0.0.0 will end up with listening
+	// on both IPv4 and IPv6 interfaces. Pass `udp4` to make it work only on 0.0.0.0.
+	listenAddress := util.CanonicalNetworkAddress(address, shared.HTTPSDefaultPort)
+	protocol := "udp"
+
+	if strings.HasPrefix(listenAddress, "0.0.0.0") {
+		protocol = "udp4"
+	}
+
+	conn, err := net.ListenPacket(protocol, listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Bind network address: %w", err)
+	}
+
+	return conn, nil
+}
+
+// UpQUIC brings up the HTTP/3 listener of the REST API on specified address.
+func (e *Endpoints) UpQUIC(listenAddress string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	conn, err := quicCreateListener(listenAddress)
+	if err != nil {
+		return fmt.Errorf("Failed starting QUIC listener: %w", err)
+	}
+
+	e.quicServe(conn)
+
+	return nil
+}
+
+// quicServe starts an HTTP/3 server for the REST API on the given UDP socket.
+// It must be called with the lock held.
+func (e *Endpoints) quicServe(conn net.PacketConn) {
+	restServer := e.servers[network]
+
+	// The TLS configuration is looked up for each connection, so that certificate updates apply straight away.
+	e.quicConfig.Store(util.ServerTLSConfig(e.cert))
+
+	server := &http3.Server{
+		Handler: restServer.Handler,
+		TLSConfig: &tls.Config{
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return e.quicConfig.Load(), nil
+			},
+		},
+		QUICConfig: &quic.Config{
+			// Keep long-lived connections, such as event streams, alive through NAT and firewalls.
+			KeepAlivePeriod: 15 * time.Second,
+		},
+		// Close the connections without any request in progress, as they are kept alive otherwise.
+		IdleTimeout: time.Minute,
+		// Handlers can get the API server from the request context, as they do over HTTPS.
+		ConnContext: func(ctx context.Context, _ *quic.Conn) context.Context {
+			return context.WithValue(ctx, http.ServerContextKey, restServer)
+		},
+	}
+
+	e.quic.Store(&quicEndpoint{server: server, conn: conn})
+
+	logger.Info("Binding socket", logger.Ctx{"type": "REST API QUIC socket", "socket": conn.LocalAddr()})
+
+	// Defer the creation of the tomb, so Down() doesn't wait on it unless
+	// we actually have spawned at least a server.
+	if e.tomb == nil {
+		e.tomb = &tomb.Tomb{}
+	}
+
+	e.tomb.Go(func() error {
+		return server.Serve(conn)
+	})
+}
+
+// quicClose stops the HTTP/3 server and closes its UDP socket.
+// It must be called with the lock held.
+func (e *Endpoints) quicClose() error {
+	endpoint := e.quic.Swap(nil)
+	if endpoint == nil {
+		return nil
+	}
+
+	logger.Info("Closing socket", logger.Ctx{"type": "REST API QUIC socket", "socket": endpoint.conn.LocalAddr()})
+
+	// Closing the server doesn't close the socket it was serving.
+	_ = endpoint.server.Close()
+
+	return endpoint.conn.Close()
+}
+
+// QUICAddress returns the network address of the HTTP/3 endpoint, or an
+// empty string if there's no HTTP/3 endpoint.
+func (e *Endpoints) QUICAddress() string {
+	endpoint := e.quic.Load()
+	if endpoint == nil {
+		return ""
+	}
+
+	return endpoint.conn.LocalAddr().String()
+}
+
+// QUICHeaders sets the Alt-Svc header advertising the HTTP/3 endpoint to the clients of the HTTPS endpoint.
+// The header isn't set if there's no HTTP/3 endpoint.
+func (e *Endpoints) QUICHeaders(header http.Header) {
+	endpoint := e.quic.Load()
+	if endpoint == nil {
+		return
+	}
+
+	// This fails until the server is listening, in which case there's nothing to advertise yet.
+	_ = endpoint.server.SetQUICHeaders(header)
+}
+
+// QUICUpdateAddress updates the address for the HTTP/3 endpoint, shutting it down and restarting it.
+func (e *Endpoints) QUICUpdateAddress(address string) error {
+	if address != "" {
+		address = util.CanonicalNetworkAddress(address, shared.HTTPSDefaultPort)
+	}
+
+	oldAddress := e.QUICAddress()
+	if address == oldAddress {
+		return nil
+	}
+
+	logger.Info("Update QUIC address")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Close the previous socket
+	_ = e.quicClose()
+
+	// If turning off listening, we're done
+	if address == "" {
+		return nil
+	}
+
+	// Attempt to setup the new listening socket
+	getListener := func(address string) (net.PacketConn, error) {
+		var err error
+		var conn net.PacketConn
+
+		for range 10 { // Ten retries over a second seems reasonable.
+			conn, err = quicCreateListener(address)
+			if err == nil {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Cannot listen on QUIC socket: %w", err)
+		}
+
+		return conn, nil
+	}
+
+	conn, err := getListener(address)
+	if err != nil {
+		// Attempt to revert to the previous address
+		if oldAddress != "" {
+			conn, err1 := getListener(oldAddress)
+			if err1 == nil {
+				e.quicServe(conn)
+			}
+		}
+
+		return err
+	}
+
+	e.quicServe(conn)
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// entityOwnershipCreatedActions are the lifecycle actions recording the identity that created an entity.
+var entityOwnershipCreatedActions = map[string]entity.Type{
+	api.EventLifecycleInstanceCreated:      entity.TypeInstance,
+	api.EventLifecycleStorageVolumeCreated: entity.TypeStorageVolume,
+	api.EventLifecycleNetworkCreated:       entity.TypeNetwork,
+	api.EventLifecycleProfileCreated:       entity.TypeProfile,
+}
+
+// entityOwnershipModifiedActions are the lifecycle actions recording the identity that last modified an entity.
+var entityOwnershipModifiedActions = map[string]entity.Type{
+	api.EventLifecycleInstanceUpdated:      entity.TypeInstance,
+	api.EventLifecycleInstanceRenamed:      entity.TypeInstance,
+	api.EventLifecycleStorageVolumeUpdated: entity.TypeStorageVolume,
+	api.EventLifecycleStorageVolumeRenamed: entity.TypeStorageVolume,
+	api.EventLifecycleNetworkUpdated:       entity.TypeNetwork,
+	api.EventLifecycleNetworkRenamed:       entity.TypeNetwork,
+	api.EventLifecycleProfileUpdated:       entity.TypeProfile,
+	api.EventLifecycleProfileRenamed:       entity.TypeProfile,
+}
+
+// setupEntityOwnership starts recording the identities that create and modify the instances, storage volumes,
+// networks and profiles from the lifecycle events of this member.
+func (d *Daemon) setupEntityOwnership() {
+	d.internalListener.AddHandler("ownership", func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle {
+			return
+		}
+
+		// The changes made on the other members are recorded by the members themselves.
+		if event.Location != d.serverName {
+			return
+		}
+
+		lifecycle := api.EventLifecycle{}
+		err := json.Unmarshal(event.Metadata, &lifecycle)
+		if err != nil {
+			return
+		}
+
+		entityType, created := entityOwnershipCreatedActions[lifecycle.Action]
+		if !created {
+			var modified bool
+			entityType, modified = entityOwnershipModifiedActions[lifecycle.Action]
+			if !modified {
+				return
+			}
+		}
+
+		u, err := url.Parse(lifecycle.Source)
+		if err != nil {
+			return
+		}
+
+		var requestor string
+		if lifecycle.Requestor != nil {
+			requestor = lifecycle.Requestor.Username
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				ref, err := dbCluster.GetEntityReferenceFromURL(ctx, tx.Tx(), &api.URL{URL: *u})
+				if api.StatusErrorCheck(err, http.StatusNotFound) && entityType == entity.TypeStorageVolume {
+					// The volumes of local pools are only found on the member they are located on.
+					ref, err = dbCluster.GetEntityReferenceFromURL(ctx, tx.Tx(), (&api.URL{URL: *u}).Target(d.serverName))
+				}
+
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					// The entity was deleted in the meantime.
+					return nil
+				} else if err != nil {
+					return err
+				}
+
+				if created {
+					return dbCluster.SetEntityCreated(ctx, tx.Tx(), entityType, ref.EntityID, requestor, event.Timestamp)
+				}
+
+				return dbCluster.SetEntityModified(ctx, tx.Tx(), entityType, ref.EntityID, requestor, event.Timestamp)
+			})
+			if err != nil {
+				logger.Warn("Failed recording entity ownership", logger.Ctx{"url": lifecycle.Source, "action": lifecycle.Action, "err": err})
+			}
+		}()
+	})
+}
+
+// storageVolumesOwnershipFill sets the identities that created and last modified the given storage volumes.
+func storageVolumesOwnershipFill(ctx context.Context, tx *db.ClusterTx, volumes ...*db.StorageVolume) error {
+	volumeIDs := make([]int, 0, len(volumes))
+	for _, vol := range volumes {
+		// Snapshots don't record their ownership and their IDs don't refer to volumes.
+		if shared.IsSnapshot(vol.Name) {
+			continue
+		}
+
+		volumeIDs = append(volumeIDs, int(vol.ID))
+	}
+
+	if len(volumeIDs) == 0 {
+		return nil
+	}
+
+	ownerships, err := dbCluster.GetEntityOwnerships(ctx, tx.Tx(), entity.TypeStorageVolume, volumeIDs...)
+	if err != nil {
+		return err
+	}
+
+	for _, vol := range volumes {
+		ownership, ok := ownerships[int(vol.ID)]
+		if !ok || shared.IsSnapshot(vol.Name) {
+			continue
+		}
+
+		vol.CreatedBy = ownership.CreatedBy
+		vol.LastModifiedBy = ownership.LastModifiedBy
+	}
+
+	return nil
+}
+
+// instancesOwnershipFill sets the identities that created and last modified the instances, keyed by instance ID.
+func instancesOwnershipFill(ctx context.Context, tx *db.ClusterTx, instances map[int]*api.Instance) error {
+	if len(instances) == 0 {
+		return nil
+	}
+
+	ownerships, err := dbCluster.GetEntityOwnerships(ctx, tx.Tx(), entity.TypeInstance, slices.Collect(maps.Keys(instances))...)
+	if err != nil {
+		return err
+	}
+
+	for instanceID, inst := range instances {
+		ownership, ok := ownerships[instanceID]
+		if !ok {
+			continue
+		}
+
+		inst.CreatedBy = ownership.CreatedBy
+		inst.LastModifiedBy = ownership.LastModifiedBy
+	}
+
+	return nil
+}
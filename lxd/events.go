@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db"
@@ -22,7 +24,7 @@ import (
 	"github.com/canonical/lxd/shared/ws"
 )
 
-var eventTypes = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeOVN}
+var eventTypes = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeOVN, api.EventTypeAlert}
 var privilegedEventTypes = []string{api.EventTypeLogging, api.EventTypeOVN}
 
 var eventsCmd = APIEndpoint{
@@ -32,6 +34,13 @@ var eventsCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: eventsGet, AccessHandler: allowAuthenticated},
 }
 
+var eventsHistoryCmd = APIEndpoint{
+	Path:        "events/history",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: eventsHistoryGet, AccessHandler: allowAuthenticated},
+}
+
 type eventsServe struct {
 	s *state.State
 }
@@ -61,31 +70,28 @@ type requestorMetadata struct {
 	} `json:"requestor"`
 }
 
-func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error {
-	projectName, allProjects, err := request.ProjectParams(r)
+// eventsFilter returns a filter allowing the events that the caller is allowed to view, and whether the caller is
+// allowed to view the privileged event types.
+//
+// Warn: The filter must not call the default logger or send any events of its own, see [events.Server.AddListener].
+func eventsFilter(s *state.State, r *http.Request) (func(logger.Logger, api.Event) bool, bool, error) {
+	requestor, err := request.GetRequestor(r.Context())
 	if err != nil {
-		return err
-	}
-
-	if !allProjects && projectName != api.ProjectDefaultName {
-		_, err := s.DB.GetProject(context.Background(), projectName)
-		if err != nil {
-			return err
-		}
+		return nil, false, err
 	}
 
 	// Get permission checkers required for filtering
 
-	// This permission checker is for use with project specific lifecycle events.
+	// This permission checker is for use with project specific lifecycle and alert events.
 	canViewProjectLifecycleEvents, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanViewEvents, entity.TypeProject)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	// This permission checker is for use with project specific operations.
 	canViewProjectOperations, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanViewOperations, entity.TypeProject)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	// `can_view_operations` on `server` is required to view any operation event that is not project specific.
@@ -94,7 +100,7 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	if err == nil {
 		canViewServerOperations = true
 	} else if !auth.IsDeniedError(err) {
-		return err
+		return nil, false, err
 	}
 
 	// `can_view_events` on `server` is required to view any of the privileged event types, or any lifecycle event that is not project specific.
@@ -103,15 +109,91 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	if err == nil {
 		canViewServerEvents = true
 	} else if !auth.IsDeniedError(err) {
-		return err
+		return nil, false, err
 	}
 
+	filter := func(log logger.Logger, event api.Event) bool {
+		l := log.AddContext(logger.Ctx{"type": event.Type, "location": event.Location, "project": event.Project})
+
+		// Privileged events require `can_view_events` on `server.
+		if slices.Contains(privilegedEventTypes, event.Type) {
+			return canViewServerEvents
+		}
+
+		switch event.Type {
+		case api.EventTypeLifecycle:
+			// Lifecycle events that are not project specific require `can_view_events` on `server`.
+			if event.Project == "" {
+				return canViewServerEvents
+			}
+
+			// Otherwise check if the caller has `can_view_lifecycle_events` on the project.
+			if canViewProjectLifecycleEvents(entity.ProjectURL(event.Project)) {
+				return true
+			}
+
+		case api.EventTypeOperation:
+			// Operations that are not project specific require `can_view_operations` on `server`.
+			if event.Project == "" {
+				return canViewServerOperations
+			}
+
+			// Otherwise check if the caller has `can_view_operations` on the project.
+			if canViewProjectOperations(entity.ProjectURL(event.Project)) {
+				return true
+			}
+
+		case api.EventTypeAlert:
+			// Alerts that are not project specific require `can_view_events` on `server`.
+			if event.Project == "" {
+				return canViewServerEvents
+			}
+
+			// Otherwise check if the caller has `can_view_events` on the project.
+			return canViewProjectLifecycleEvents(entity.ProjectURL(event.Project))
+
+		default:
+			// We don't expect any other event types at this point
+			l.Warn("Received unexpected event type")
+			return false
+		}
+
+		// At this point the caller does not have permission to view the event via group membership or project.
+		// Check the event or operation requestor to see if they are the identity that triggered the event.
+
+		// Unmarshal the requestor from the event metadata.
+		var m requestorMetadata
+		err := json.Unmarshal(event.Metadata, &m)
+		if err != nil {
+			l.Error("Failed to unmarshal event metadata during requestor filtering")
+			return false
+		}
+
+		if m.Requestor == nil {
+			return false
+		}
+
+		// Allow the event if the same requestor is connected.
+		if m.Requestor.Username == requestor.CallerUsername() && m.Requestor.Protocol == requestor.CallerProtocol() {
+			return true
+		}
+
+		// Otherwise, filter it out.
+		return false
+	}
+
+	return filter, canViewServerEvents, nil
+}
+
+// eventsRequestTypes returns the event types requested by the caller, or all the allowed event types that the caller
+// is allowed to view if none were requested.
+func eventsRequestTypes(r *http.Request, allowedTypes []string, canViewServerEvents bool) ([]string, error) {
 	// User requested types
 	types := strings.Split(r.FormValue("type"), ",")
 	if len(types) == 1 && types[0] == "" {
 		// If no types were requested, return all event types the caller has permission to view.
 		types = []string{}
-		for _, entry := range eventTypes {
+		for _, entry := range allowedTypes {
 			if !canViewServerEvents && slices.Contains(privilegedEventTypes, entry) {
 				continue
 			}
@@ -121,16 +203,91 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	} else {
 		// Otherwise, validate the provided types.
 		for _, entry := range types {
-			if !slices.Contains(eventTypes, entry) {
-				return api.StatusErrorf(http.StatusBadRequest, "%q isn't a supported event type", entry)
+			if !slices.Contains(allowedTypes, entry) {
+				return nil, api.StatusErrorf(http.StatusBadRequest, "%q isn't a supported event type", entry)
 			}
 
 			if !canViewServerEvents && slices.Contains(privilegedEventTypes, entry) {
-				return api.StatusErrorf(http.StatusForbidden, "Forbidden")
+				return nil, api.StatusErrorf(http.StatusForbidden, "Forbidden")
 			}
 		}
 	}
 
+	return types, nil
+}
+
+// eventsRequestSelector returns the selector of the lifecycle and operation events requested by the caller through
+// the "entity-type", "entity-name" and "action" query parameters.
+func eventsRequestSelector(r *http.Request) (events.Selector, error) {
+	var selector events.Selector
+
+	split := func(value string) []string {
+		if value == "" {
+			return nil
+		}
+
+		return strings.Split(value, ",")
+	}
+
+	for _, entityTypeName := range split(r.FormValue("entity-type")) {
+		entityType := entity.Type(entityTypeName)
+		err := entityType.Validate()
+		if err != nil {
+			return events.Selector{}, api.StatusErrorf(http.StatusBadRequest, "Invalid entity type: %w", err)
+		}
+
+		selector.EntityTypes = append(selector.EntityTypes, entityType)
+	}
+
+	selector.EntityNames = split(r.FormValue("entity-name"))
+	selector.Actions = split(r.FormValue("action"))
+
+	return selector, nil
+}
+
+func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error {
+	projectName, allProjects, err := request.ProjectParams(r)
+	if err != nil {
+		return err
+	}
+
+	if !allProjects && projectName != api.ProjectDefaultName {
+		_, err := s.DB.GetProject(context.Background(), projectName)
+		if err != nil {
+			return err
+		}
+	}
+
+	filter, canViewServerEvents, err := eventsFilter(s, r)
+	if err != nil {
+		return err
+	}
+
+	types, err := eventsRequestTypes(r, eventTypes, canViewServerEvents)
+	if err != nil {
+		return err
+	}
+
+	selector, err := eventsRequestSelector(r)
+	if err != nil {
+		return err
+	}
+
+	// Events to replay to a reconnecting listener.
+	var since uint64
+	replay := r.FormValue("since") != ""
+	if replay {
+		since, err = strconv.ParseUint(r.FormValue("since"), 10, 64)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid event sequence number %q", r.FormValue("since"))
+		}
+
+		err = s.Events.CheckReplay(since)
+		if err != nil {
+			return api.StatusErrorf(http.StatusGone, "%w", err)
+		}
+	}
+
 	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
 
 	requestor, err := request.GetRequestor(r.Context())
@@ -180,84 +337,42 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 		}
 	}
 
-	filter := func(log logger.Logger, event api.Event) bool {
-		l = log.AddContext(logger.Ctx{"type": event.Type, "location": event.Location, "project": event.Project})
-
-		// Privileged events require `can_view_events` on `server.
-		if slices.Contains(privilegedEventTypes, event.Type) {
-			return canViewServerEvents
-		}
-
-		switch event.Type {
-		case api.EventTypeLifecycle:
-			// Lifecycle events that are not project specific require `can_view_events` on `server`.
-			if event.Project == "" {
-				return canViewServerEvents
-			}
-
-			// Otherwise check if the caller has `can_view_lifecycle_events` on the project.
-			if canViewProjectLifecycleEvents(entity.ProjectURL(event.Project)) {
-				return true
-			}
-
-		case api.EventTypeOperation:
-			// Operations that are not project specific require `can_view_operations` on `server`.
-			if event.Project == "" {
-				return canViewServerOperations
-			}
-
-			// Otherwise check if the caller has `can_view_operations` on the project.
-			if canViewProjectOperations(entity.ProjectURL(event.Project)) {
-				return true
-			}
-
-		default:
-			// We don't expect any other event types at this point
-			l.Warn("Received unexpected event type")
-			return false
-		}
-
-		// At this point the caller does not have permission to view the event via group membership or project.
-		// Check the event or operation requestor to see if they are the identity that triggered the event.
-
-		// Unmarshal the requestor from the event metadata.
-		var m requestorMetadata
-		err := json.Unmarshal(event.Metadata, &m)
+	// Upgrade the connection to websocket as late as possible.
+	// This is because the client will assume it's getting events as soon as the upgrade is performed.
+	var listenerConnection events.EventListenerConnection
+	if r.ProtoMajor >= 3 {
+		// Websockets aren't available over HTTP/3, so the events are streamed in the response instead.
+		listenerConnection, err = events.NewResponseStreamListenerConnection(w, r)
 		if err != nil {
-			l.Error("Failed to unmarshal event metadata during requestor filtering")
-			return false
-		}
-
-		if m.Requestor == nil {
-			return false
+			l.Warn("Failed setting up event stream", logger.Ctx{"err": err})
+			return nil
 		}
-
-		// Allow the event if the same requestor is connected.
-		if m.Requestor.Username == requestor.CallerUsername() && m.Requestor.Protocol == requestor.CallerProtocol() {
-			return true
+	} else {
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			l.Warn("Failed upgrading event connection", logger.Ctx{"err": err})
+			return nil
 		}
 
-		// Otherwise, filter it out.
-		return false
+		listenerConnection = events.NewWebsocketListenerConnection(conn)
 	}
 
-	// Upgrade the connection to websocket as late as possible.
-	// This is because the client will assume it's getting events as soon as the upgrade is performed.
-	conn, err := ws.Upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		l.Warn("Failed upgrading event connection", logger.Ctx{"err": err})
-		return nil
-	}
+	defer func() { _ = listenerConnection.Close() }() // Ensure listener below ends when this function ends.
 
-	defer func() { _ = conn.Close() }() // Ensure listener below ends when this function ends.
-
-	listenerConnection := events.NewWebsocketListenerConnection(conn)
-	listener, err := s.Events.AddListener(projectName, allProjects, filter, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	listener, err := s.Events.AddListener(projectName, allProjects, filter, selector, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
 	if err != nil {
 		l.Warn("Failed to add event listener", logger.Ctx{"err": err})
 		return nil
 	}
 
+	if replay {
+		err = s.Events.Replay(listener, since)
+		if err != nil {
+			l.Warn("Failed replaying events", logger.Ctx{"err": err})
+			return nil
+		}
+	}
+
 	listener.Wait(r.Context())
 
 	return nil
@@ -268,6 +383,7 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	Get the event stream
 //
 //	Connects to the event API using websocket.
+//	Over HTTP/3, the events are streamed in the response instead, one JSON object per line.
 //
 //	---
 //	produces:
@@ -287,15 +403,184 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	    name: all-projects
 //	    description: Retrieve instances from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Replay the retained events following this sequence number
+//	    type: integer
+//	    example: 1234
+//	  - in: query
+//	    name: entity-type
+//	    description: Only return the lifecycle and operation events related to these entity types, comma separated
+//	    type: string
+//	    example: instance,storage_volume
+//	  - in: query
+//	    name: entity-name
+//	    description: Only return the lifecycle and operation events related to entities with these names, comma separated
+//	    type: string
+//	    example: c1
+//	  - in: query
+//	    name: action
+//	    description: Only return the lifecycle events with these actions, comma separated
+//	    type: string
+//	    example: instance-started,instance-stopped
 //	responses:
 //	  "200":
 //	    description: Websocket message (JSON)
 //	    schema:
 //	      $ref: "#/definitions/Event"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
+//	  "410":
+//	    description: The events following the sequence number are no longer retained
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func eventsGet(d *Daemon, r *http.Request) response.Response {
 	return &eventsServe{s: d.State()}
 }
+
+// swagger:operation GET /1.0/events/history server events_history_get
+//
+//	Get the event history
+//
+//	Returns the persisted lifecycle and operation events, from the oldest to the newest.
+//	Events are only persisted when `core.events_history_retention` is set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: type
+//	    description: Event type(s), comma separated (valid types are operation or lifecycle)
+//	    type: string
+//	    example: lifecycle
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve events from all projects
+//	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Only return the events that occurred at or after this time (RFC3339)
+//	    type: string
+//	    example: 2026-01-01T00:00:00Z
+//	  - in: query
+//	    name: entity-type
+//	    description: Only return the lifecycle and operation events related to these entity types, comma separated
+//	    type: string
+//	    example: instance,storage_volume
+//	  - in: query
+//	    name: entity-name
+//	    description: Only return the lifecycle and operation events related to entities with these names, comma separated
+//	    type: string
+//	    example: c1
+//	  - in: query
+//	    name: action
+//	    description: Only return the lifecycle events with these actions, comma separated
+//	    type: string
+//	    example: instance-started,instance-stopped
+//	responses:
+//	  "200":
+//	    description: API events
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of events
+//	          items:
+//	            $ref: "#/definitions/Event"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func eventsHistoryGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, allProjects, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !allProjects && projectName != api.ProjectDefaultName {
+		_, err := s.DB.GetProject(r.Context(), projectName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	filter, canViewServerEvents, err := eventsFilter(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	types, err := eventsRequestTypes(r, []string{api.EventTypeOperation, api.EventTypeLifecycle}, canViewServerEvents)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	selector, err := eventsRequestSelector(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	dbFilter := cluster.EventFilter{Types: types}
+
+	if r.FormValue("since") != "" {
+		since, err := time.Parse(time.RFC3339, r.FormValue("since"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid time %q: %w", r.FormValue("since"), err))
+		}
+
+		dbFilter.Since = &since
+	}
+
+	var dbEvents []cluster.Event
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbEvents, err = cluster.GetEvents(ctx, tx.Tx(), dbFilter)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
+
+	result := make([]api.Event, 0, len(dbEvents))
+	for _, dbEvent := range dbEvents {
+		// Events which aren't project specific are returned regardless of the project, as with the event stream.
+		if dbEvent.Project != "" && !allProjects && dbEvent.Project != projectName {
+			continue
+		}
+
+		event := dbEvent.ToAPI()
+		if !selector.Match(event) || !filter(l, event) {
+			continue
+		}
+
+		result = append(result, event)
+	}
+
+	return response.SyncResponse(true, result)
+}
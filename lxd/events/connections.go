@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"sync"
 	"time"
 
@@ -44,6 +45,15 @@ type simpleListenerConnection struct {
 	lock sync.Mutex
 }
 
+type responseStreamListenerConnection struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	lock sync.Mutex
+}
+
 // NewWebsocketListenerConnection returns a new websocket listener connection.
 func NewWebsocketListenerConnection(connection *websocket.Conn) EventListenerConnection {
 	return &websockListenerConnection{
@@ -307,3 +317,88 @@ func (e *simpleListenerConnection) LocalAddr() net.Addr {
 func (e *simpleListenerConnection) RemoteAddr() net.Addr {
 	return nil
 }
+
+// NewResponseStreamListenerConnection returns a new listener connection streaming the events in the response to
+// an HTTP request. It is used for the protocols that don't support websockets, such as HTTP/3.
+func NewResponseStreamListenerConnection(w http.ResponseWriter, r *http.Request) (EventListenerConnection, error) {
+	// Send the response headers to let the client know what to expect.
+	// They are followed by the events, one JSON object per line.
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+
+	err := controller.Flush()
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending initial HTTP response: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+
+	return &responseStreamListenerConnection{
+		w:          w,
+		controller: controller,
+		ctx:        ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+// Reader for the response stream connection.
+// The client can't send anything once the response started, so this only waits for it to go away.
+func (e *responseStreamListenerConnection) Reader(ctx context.Context, recvFunc EventHandler) {
+	defer func() { _ = e.Close() }()
+
+	select {
+	case <-ctx.Done():
+	case <-e.ctx.Done():
+	}
+}
+
+// WriteJSON sends a JSON event to the response stream connection.
+func (e *responseStreamListenerConnection) WriteJSON(event any) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	// The response can't be written to once the request handler returned.
+	if e.ctx.Err() != nil {
+		return errors.New("Connection closed")
+	}
+
+	err := e.controller.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("Failed setting write deadline: %w", err)
+	}
+
+	err = json.NewEncoder(e.w).Encode(event)
+	if err != nil {
+		return fmt.Errorf("Failed sending event: %w", err)
+	}
+
+	err = e.controller.Flush()
+	if err != nil {
+		return fmt.Errorf("Failed sending event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the response stream connection.
+func (e *responseStreamListenerConnection) Close() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.cancel()
+
+	return nil
+}
+
+// LocalAddr returns nil for logging purposes.
+func (e *responseStreamListenerConnection) LocalAddr() net.Addr {
+	return nil
+}
+
+// RemoteAddr returns nil for logging purposes.
+func (e *responseStreamListenerConnection) RemoteAddr() net.Addr {
+	return nil
+}
@@ -26,6 +26,9 @@ const EventSourcePull = 1
 // EventSourcePush indicates the event was received from an event listener client connected to us.
 const EventSourcePush = 2
 
+// historySize is the number of lifecycle and operation events retained for replay.
+const historySize = 1000
+
 // InjectFunc is used to inject an event received by a listener into the local events dispatcher.
 type InjectFunc func(event api.Event, eventSource EventSource)
 
@@ -40,6 +43,12 @@ type Server struct {
 	notify    NotifyFunc
 	location  string
 
+	// sequence is the sequence number of the last dispatched event.
+	sequence uint64
+
+	// history contains the most recent lifecycle and operation events, for replay to reconnecting listeners.
+	history []historyEvent
+
 	// logger is a [logger.Logger] that can be used while an event is being processed.
 	// This is necessary because the global [logger.Logger] is configured with a hook that will send logging events to the server.
 	// If the global logger is used while the Server is locked, the Server goes into deadlock. This logger should be used instead.
@@ -76,11 +85,11 @@ func (s *Server) SetLocalLocation(location string) {
 }
 
 // AddListener creates and returns a new event listener. The filter argument must return true to include the event and
-// false to omit the event.
+// false to omit the event. The selector restricts the lifecycle and operation events by entity and action.
 //
 // Warn: The filter must not call the default logger or send any events of its own. Otherwise, the event server will
 // deadlock when it tries to broadcast the logging event.
-func (s *Server) AddListener(projectName string, allProjects bool, filter func(logger.Logger, api.Event) bool, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+func (s *Server) AddListener(projectName string, allProjects bool, filter func(logger.Logger, api.Event) bool, selector Selector, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
 	if allProjects && projectName != "" {
 		return nil, errors.New("Cannot specify project name when listening for events on all projects")
 	}
@@ -103,6 +112,7 @@ func (s *Server) AddListener(projectName string, allProjects bool, filter func(l
 		allProjects:      allProjects,
 		projectName:      projectName,
 		filter:           filter,
+		selector:         selector,
 		excludeSources:   excludeSources,
 		excludeLocations: excludeLocations,
 	}
@@ -114,6 +124,7 @@ func (s *Server) AddListener(projectName string, allProjects bool, filter func(l
 		return nil, fmt.Errorf("A listener with ID %q already exists", listener.id)
 	}
 
+	listener.startSequence = s.sequence
 	s.listeners[listener.id] = listener
 
 	go listener.start()
@@ -121,6 +132,54 @@ func (s *Server) AddListener(projectName string, allProjects bool, filter func(l
 	return listener, nil
 }
 
+// CheckReplay checks that the events dispatched after the given sequence number can still be replayed.
+func (s *Server) CheckReplay(since uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if since > s.sequence {
+		return fmt.Errorf("Event sequence %d is ahead of the current sequence %d", since, s.sequence)
+	}
+
+	// Events may only have been dropped once the history is full.
+	if len(s.history) == historySize && s.history[0].event.Sequence > since+1 {
+		return fmt.Errorf("Events after sequence %d are no longer retained", since)
+	}
+
+	return nil
+}
+
+// Replay sends the retained events dispatched after the given sequence number, and before the listener was
+// added, to the listener.
+func (s *Server) Replay(listener *Listener, since uint64) error {
+	s.lock.Lock()
+	filterLogger := s.logger.AddContext(logger.Ctx{"replay": true})
+	events := []api.Event{}
+	for _, entry := range s.history {
+		if entry.event.Sequence <= since || entry.event.Sequence > listener.startSequence {
+			continue
+		}
+
+		if !listener.accepts(filterLogger, entry.event, entry.source) {
+			continue
+		}
+
+		events = append(events, entry.event)
+	}
+
+	s.lock.Unlock()
+
+	for _, event := range events {
+		err := listener.WriteJSON(event)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("Failed replaying event %d: %w", event.Sequence, err)
+		}
+	}
+
+	return nil
+}
+
 // SendLifecycle broadcasts a lifecycle event.
 func (s *Server) SendLifecycle(projectName string, event api.EventLifecycle) {
 	_ = s.Send(projectName, api.EventTypeLifecycle, event)
@@ -170,10 +229,6 @@ func (s *Server) Inject(event api.Event, eventSource EventSource) {
 }
 
 func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
-	sourceInSlice := func(source EventSource, sources []EventSource) bool {
-		return slices.Contains(sources, source)
-	}
-
 	s.lock.Lock()
 
 	// Set the Location for local events to the local serverName if not already populated (do it here rather
@@ -182,6 +237,17 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 		event.Location = s.location
 	}
 
+	// Number the event in the sequence of this member, replacing the sequence number of forwarded events.
+	s.sequence++
+	event.Sequence = s.sequence
+
+	if event.Type == api.EventTypeLifecycle || event.Type == api.EventTypeOperation {
+		s.history = append(s.history, historyEvent{event: event, source: eventSource})
+		if len(s.history) > historySize {
+			s.history = s.history[1:]
+		}
+	}
+
 	// If a notifcation hook is present, then call it for locally produced events.
 	// This can be used to send local events to another target (such as an event-hub member).
 	if s.notify != nil && eventSource == EventSourceLocal {
@@ -191,26 +257,7 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 	filterLogger := s.logger.AddContext(logger.Ctx{"source": eventSource})
 	listeners := s.listeners
 	for _, listener := range listeners {
-		// If the event is project specific, check if the listener is requesting events from that project.
-		if event.Project != "" && !listener.allProjects && event.Project != listener.projectName {
-			continue
-		}
-
-		if sourceInSlice(eventSource, listener.excludeSources) {
-			continue
-		}
-
-		if !slices.Contains(listener.messageTypes, event.Type) {
-			continue
-		}
-
-		// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
-		if eventSource != EventSourceLocal && slices.Contains(listener.excludeLocations, event.Location) {
-			continue
-		}
-
-		// Apply any further filters.
-		if !listener.filter(filterLogger, event) {
+		if !listener.accepts(filterLogger, event, eventSource) {
 			continue
 		}
 
@@ -253,6 +300,42 @@ type Listener struct {
 	allProjects      bool
 	projectName      string
 	filter           func(logger.Logger, api.Event) bool
+	selector         Selector
 	excludeSources   []EventSource
 	excludeLocations []string
+	startSequence    uint64
+}
+
+// historyEvent is an event retained for replay along with its source.
+type historyEvent struct {
+	event  api.Event
+	source EventSource
+}
+
+// accepts returns whether the event from the given source should be delivered to the listener.
+func (l *Listener) accepts(filterLogger logger.Logger, event api.Event, eventSource EventSource) bool {
+	// If the event is project specific, check if the listener is requesting events from that project.
+	if event.Project != "" && !l.allProjects && event.Project != l.projectName {
+		return false
+	}
+
+	if slices.Contains(l.excludeSources, eventSource) {
+		return false
+	}
+
+	if !slices.Contains(l.messageTypes, event.Type) {
+		return false
+	}
+
+	// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
+	if eventSource != EventSourceLocal && slices.Contains(l.excludeLocations, event.Location) {
+		return false
+	}
+
+	if !l.selector.Match(event) {
+		return false
+	}
+
+	// Apply any further filters.
+	return l.filter(filterLogger, event)
 }
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// bufferConnection records the events written to a listener.
+type bufferConnection struct {
+	lock   sync.Mutex
+	buf    bytes.Buffer
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *bufferConnection) Read([]byte) (int, error) {
+	<-c.closed
+	return 0, io.EOF
+}
+
+func (c *bufferConnection) Write(p []byte) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.buf.Write(p)
+}
+
+func (c *bufferConnection) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+// sequences returns the sequence numbers of the events written to the connection.
+func (c *bufferConnection) sequences(t *testing.T) []uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	sequences := []uint64{}
+	decoder := json.NewDecoder(bytes.NewReader(c.buf.Bytes()))
+	for decoder.More() {
+		event := api.Event{}
+		require.NoError(t, decoder.Decode(&event))
+		sequences = append(sequences, event.Sequence)
+	}
+
+	slices.Sort(sequences)
+
+	return sequences
+}
+
+func TestServerSequence(t *testing.T) {
+	server, err := NewServer(false, false, nil)
+	require.NoError(t, err)
+
+	server.SendLifecycle("p1", api.EventLifecycle{Action: "instance-created"})
+	require.NoError(t, server.Send("p1", api.EventTypeLogging, api.EventLogging{Message: "foo"}))
+	server.SendLifecycle("p2", api.EventLifecycle{Action: "instance-created"})
+
+	// Forwarded events are numbered in the sequence of the member.
+	server.Inject(api.Event{Type: api.EventTypeLifecycle, Project: "p1", Location: "node2", Sequence: 100}, EventSourcePull)
+
+	require.Len(t, server.history, 3)
+	assert.Equal(t, []uint64{1, 3, 4}, []uint64{server.history[0].event.Sequence, server.history[1].event.Sequence, server.history[2].event.Sequence})
+
+	assert.NoError(t, server.CheckReplay(0))
+	assert.NoError(t, server.CheckReplay(4))
+	assert.Error(t, server.CheckReplay(5))
+}
+
+func TestServerReplay(t *testing.T) {
+	server, err := NewServer(false, false, nil)
+	require.NoError(t, err)
+
+	server.SendLifecycle("p1", api.EventLifecycle{Action: "instance-created"})
+	server.SendLifecycle("p1", api.EventLifecycle{Action: "instance-started"})
+	server.SendLifecycle("p2", api.EventLifecycle{Action: "instance-started"})
+	server.SendLifecycle("p1", api.EventLifecycle{Action: "instance-stopped"})
+
+	conn := &bufferConnection{closed: make(chan struct{})}
+	listener, err := server.AddListener("p1", false, nil, Selector{}, NewSimpleListenerConnection(conn), []string{api.EventTypeLifecycle}, nil, nil, nil)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// Events dispatched after the listener was added are only delivered once.
+	server.SendLifecycle("p1", api.EventLifecycle{Action: "instance-deleted"})
+
+	// Only the events of the listener project following the sequence number are replayed.
+	require.NoError(t, server.Replay(listener, 1))
+
+	expected := []uint64{2, 4, 5}
+	for i := 0; i < 100 && !slices.Equal(conn.sequences(t), expected); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, expected, conn.sequences(t))
+}
+
+func TestServerReplayRetention(t *testing.T) {
+	server, err := NewServer(false, false, nil)
+	require.NoError(t, err)
+
+	for range historySize + 5 {
+		server.SendLifecycle("p1", api.EventLifecycle{Action: "instance-updated"})
+	}
+
+	assert.Len(t, server.history, historySize)
+	assert.NoError(t, server.CheckReplay(5))
+	assert.Error(t, server.CheckReplay(4))
+}
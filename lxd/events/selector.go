@@ -0,0 +1,111 @@
+package events
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// Selector selects the lifecycle and operation events delivered to a listener by entity and lifecycle action.
+// Other event types aren't affected. Empty fields don't restrict the events.
+type Selector struct {
+	// EntityTypes are the types of the entities the events must relate to.
+	EntityTypes []entity.Type
+
+	// EntityNames are the names of the entities the events must relate to.
+	EntityNames []string
+
+	// Actions are the actions of the lifecycle events. Operation events have no action and aren't restricted.
+	Actions []string
+}
+
+// IsZero returns whether the selector doesn't restrict the events.
+func (s Selector) IsZero() bool {
+	return len(s.EntityTypes) == 0 && len(s.EntityNames) == 0 && len(s.Actions) == 0
+}
+
+// Match returns whether the event is selected.
+func (s Selector) Match(event api.Event) bool {
+	if s.IsZero() {
+		return true
+	}
+
+	switch event.Type {
+	case api.EventTypeLifecycle:
+		var lifecycle struct {
+			Action string `json:"action"`
+			Source string `json:"source"`
+			Name   string `json:"name"`
+		}
+
+		err := json.Unmarshal(event.Metadata, &lifecycle)
+		if err != nil {
+			return false
+		}
+
+		if len(s.Actions) > 0 && !slices.Contains(s.Actions, lifecycle.Action) {
+			return false
+		}
+
+		return s.matchEntity(lifecycle.Source, lifecycle.Name)
+	case api.EventTypeOperation:
+		var operation struct {
+			Resources map[string][]string `json:"resources"`
+		}
+
+		err := json.Unmarshal(event.Metadata, &operation)
+		if err != nil {
+			return false
+		}
+
+		// Operations are selected if any of the resources they affect is.
+		for _, resources := range operation.Resources {
+			for _, resource := range resources {
+				if s.matchEntity(resource, "") {
+					return true
+				}
+			}
+		}
+
+		return len(s.EntityTypes) == 0 && len(s.EntityNames) == 0
+	}
+
+	return true
+}
+
+// matchEntity returns whether the entity with the given URL is selected. The entity name defaults to the last
+// path argument of the URL.
+func (s Selector) matchEntity(rawURL string, name string) bool {
+	if len(s.EntityTypes) == 0 && len(s.EntityNames) == 0 {
+		return true
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	entityType, _, _, pathArgs, err := entity.ParseURL(*u)
+	if err != nil {
+		return false
+	}
+
+	if len(s.EntityTypes) > 0 && !slices.Contains(s.EntityTypes, entityType) {
+		return false
+	}
+
+	if len(s.EntityNames) > 0 {
+		if name == "" && len(pathArgs) > 0 {
+			name = pathArgs[len(pathArgs)-1]
+		}
+
+		if !slices.Contains(s.EntityNames, name) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+func TestSelector_Match(t *testing.T) {
+	lifecycle := func(action string, source string) api.Event {
+		metadata, _ := json.Marshal(api.EventLifecycle{Action: action, Source: source})
+		return api.Event{Type: api.EventTypeLifecycle, Metadata: metadata}
+	}
+
+	operation := func(resources map[string][]string) api.Event {
+		metadata, _ := json.Marshal(api.Operation{Resources: resources})
+		return api.Event{Type: api.EventTypeOperation, Metadata: metadata}
+	}
+
+	instanceStarted := lifecycle("instance-started", "/1.0/instances/c1")
+	volumeCreated := lifecycle("storage-volume-created", "/1.0/storage-pools/default/volumes/custom/vol1")
+	instanceOperation := operation(map[string][]string{"instances": {"/1.0/instances/c1"}})
+	logging := api.Event{Type: api.EventTypeLogging}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		event    api.Event
+		want     bool
+	}{
+		{"empty selector", Selector{}, instanceStarted, true},
+		{"entity type match", Selector{EntityTypes: []entity.Type{entity.TypeInstance}}, instanceStarted, true},
+		{"entity type mismatch", Selector{EntityTypes: []entity.Type{entity.TypeInstance}}, volumeCreated, false},
+		{"entity name match", Selector{EntityNames: []string{"vol1"}}, volumeCreated, true},
+		{"entity name mismatch", Selector{EntityNames: []string{"c2"}}, instanceStarted, false},
+		{"action match", Selector{Actions: []string{"instance-started"}}, instanceStarted, true},
+		{"action mismatch", Selector{Actions: []string{"instance-stopped"}}, instanceStarted, false},
+		{"operation resource match", Selector{EntityTypes: []entity.Type{entity.TypeInstance}, EntityNames: []string{"c1"}}, instanceOperation, true},
+		{"operation resource mismatch", Selector{EntityNames: []string{"c2"}}, instanceOperation, false},
+		{"operation without action", Selector{Actions: []string{"instance-started"}}, instanceOperation, true},
+		{"other event types", Selector{EntityNames: []string{"c2"}}, logging, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.selector.Match(test.event))
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// eventsHistoryQueueSize is the maximum number of events waiting to be persisted. Events are dropped if the
+// database can't keep up.
+const eventsHistoryQueueSize = 1000
+
+// eventsHistoryBatchSize is the maximum number of events persisted in a single transaction.
+const eventsHistoryBatchSize = 100
+
+// eventsHistoryFlushInterval is how long events can wait before being persisted.
+const eventsHistoryFlushInterval = time.Second
+
+// setupEventsHistory starts or stops persisting the lifecycle and operation events of this member, depending on
+// the configured retention.
+func (d *Daemon) setupEventsHistory(retention time.Duration) {
+	if retention <= 0 {
+		d.internalListener.RemoveHandler("history")
+		return
+	}
+
+	d.internalListener.AddHandler("history", d.eventsHistoryHandleEvent)
+}
+
+// eventsHistoryHandleEvent queues the lifecycle and operation events of this member to be persisted.
+//
+// Warn: This must not log, as it is called for logging events too.
+func (d *Daemon) eventsHistoryHandleEvent(event api.Event) {
+	if event.Type != api.EventTypeLifecycle && event.Type != api.EventTypeOperation {
+		return
+	}
+
+	// The events of the other members are persisted by the members themselves.
+	if event.Location != d.serverName {
+		return
+	}
+
+	select {
+	case d.eventsHistoryQueue <- event:
+	default:
+	}
+}
+
+// eventsHistoryWriter persists the queued events in batches until the daemon shuts down.
+func (d *Daemon) eventsHistoryWriter() {
+	ticker := time.NewTicker(eventsHistoryFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]dbCluster.Event, 0, eventsHistoryBatchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.CreateEvents(ctx, tx.Tx(), batch)
+		})
+		if err != nil {
+			logger.Warn("Failed persisting events history", logger.Ctx{"events": len(batch), "err": err})
+		}
+
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event := <-d.eventsHistoryQueue:
+			batch = append(batch, dbCluster.Event{
+				Type:      event.Type,
+				Project:   event.Project,
+				Location:  event.Location,
+				Timestamp: event.Timestamp,
+				Metadata:  string(event.Metadata),
+			})
+
+			if len(batch) >= eventsHistoryBatchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+
+		case <-d.shutdownCtx.Done():
+			flush()
+			return
+		}
+	}
+}
+
+// pruneEventsHistoryTask removes the events of this member which are older than the configured retention.
+func pruneEventsHistoryTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		retention := s.GlobalConfig.EventsHistoryRetention()
+		if retention <= 0 {
+			return
+		}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteEventsBefore(ctx, tx.Tx(), s.ServerName, time.Now().Add(-retention))
+		})
+		if err != nil {
+			logger.Warn("Failed pruning events history", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
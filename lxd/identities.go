@@ -7,6 +7,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
@@ -61,6 +62,10 @@ var currentIdentityCmd = APIEndpoint{
 		Handler:       identityGetCurrent,
 		AccessHandler: allowAuthenticated,
 	},
+	Patch: APIEndpointAction{
+		Handler:       identityPatchCurrent,
+		AccessHandler: allowAuthenticated,
+	},
 }
 
 var tlsIdentitiesCmd = APIEndpoint{
@@ -100,7 +105,7 @@ var bearerIdentitiesCmd = APIEndpoint{
 	},
 	Post: APIEndpointAction{
 		Handler:       identitiesBearerPost,
-		AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanCreateIdentities),
+		AccessHandler: allowAuthenticated,
 	},
 }
 
@@ -248,6 +253,18 @@ func identityAccessHandler(authenticationMethod string, entitlement auth.Entitle
 
 		if identityType.IsFineGrained() {
 			err = s.Authorizer.CheckPermission(r.Context(), entity.IdentityURL(authenticationMethod, id.Identifier), entitlement)
+			if auth.IsDeniedError(err) && (entitlement == auth.EntitlementCanView || entitlement == auth.EntitlementCanDelete) {
+				// The identity that created a delegated identity can view and delete it.
+				isParent, parentErr := isIdentityParent(r.Context(), s, *id)
+				if parentErr != nil {
+					return response.SmartError(parentErr)
+				}
+
+				if isParent {
+					err = nil
+				}
+			}
+
 			if err != nil {
 				return response.SmartError(err)
 			}
@@ -401,6 +418,17 @@ func identitiesBearerPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Identities of type %q cannot be created via the bearer API", req.Type))
 	}
 
+	// Project administrators can create identities confined to their project.
+	err = checkCreateIdentityPermission(r.Context(), s, req.Project)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = validateDelegation(r.Context(), s, req.Project, req.Groups, req.Permissions)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	newIdentityID := uuid.New()
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the identity.
@@ -414,6 +442,10 @@ func identitiesBearerPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		if req.Project != "" {
+			return delegateIdentity(ctx, tx.Tx(), int(id), req.Project, req.Permissions)
+		}
+
 		if len(req.Groups) > 0 {
 			return dbCluster.SetIdentityAuthGroups(ctx, tx.Tx(), int(id), req.Groups)
 		}
@@ -625,8 +657,14 @@ func createIdentityTLSUntrusted(ctx context.Context, s *state.State, peerCertifi
 
 // createIdentityTLSTrusted handles requests to create an identity when the caller is trusted.
 func createIdentityTLSTrusted(ctx context.Context, s *state.State, networkCert *shared.CertInfo, req api.IdentitiesTLSPost, notify identityNotificationFunc) response.Response {
-	// Check if the caller has permission to create identities.
-	err := s.Authorizer.CheckPermission(ctx, entity.ServerURL(), auth.EntitlementCanCreateIdentities)
+	// Check if the caller has permission to create identities. Project administrators can create identities confined
+	// to their project.
+	err := checkCreateIdentityPermission(ctx, s, req.Project)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = validateDelegation(ctx, s, req.Project, req.Groups, req.Permissions)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -678,6 +716,10 @@ func createIdentityTLSTrusted(ctx context.Context, s *state.State, networkCert *
 			}
 		}
 
+		if req.Project != "" {
+			return delegateIdentity(ctx, tx.Tx(), int(id), req.Project, req.Permissions)
+		}
+
 		if len(req.Groups) > 0 {
 			return dbCluster.SetIdentityAuthGroups(ctx, tx.Tx(), int(id), req.Groups)
 		}
@@ -785,6 +827,10 @@ func createIdentityTLSPending(ctx context.Context, s *state.State, req api.Ident
 			return err
 		}
 
+		if req.Project != "" {
+			return delegateIdentity(ctx, tx.Tx(), int(id), req.Project, req.Permissions)
+		}
+
 		if len(req.Groups) > 0 {
 			return dbCluster.SetIdentityAuthGroups(ctx, tx.Tx(), int(id), req.Groups)
 		}
@@ -1131,6 +1177,8 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 
 		var identities []dbCluster.Identity
 		var groupsByIdentityID map[int][]dbCluster.AuthGroup
+		var groupExpiriesByIdentityID map[int]map[string]time.Time
+		var delegationsByIdentityID map[int]dbCluster.IdentityDelegation
 		var apiIdentity *api.Identity
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 			// Get all identities, filter by authentication method if present.
@@ -1172,6 +1220,16 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 				if err != nil {
 					return err
 				}
+
+				groupExpiriesByIdentityID, err = dbCluster.GetAllAuthGroupExpiriesByIdentityIDs(ctx, tx.Tx())
+				if err != nil {
+					return err
+				}
+
+				delegationsByIdentityID, err = dbCluster.GetAllIdentityDelegations(ctx, tx.Tx())
+				if err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -1195,10 +1253,20 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 		if recursion {
 			// Convert the []cluster.Group in the groupsByIdentityID map to string slices of the group names.
 			groupNamesByIdentityID := make(map[int][]string, len(groupsByIdentityID))
+			groupExpiriesByVisibleGroup := make(map[int]map[string]time.Time, len(groupExpiriesByIdentityID))
 			for identityID, groups := range groupsByIdentityID {
 				for _, group := range groups {
 					if canViewGroup(entity.AuthGroupURL(group.Name)) {
 						groupNamesByIdentityID[identityID] = append(groupNamesByIdentityID[identityID], group.Name)
+
+						expiryDate, ok := groupExpiriesByIdentityID[identityID][group.Name]
+						if ok {
+							if groupExpiriesByVisibleGroup[identityID] == nil {
+								groupExpiriesByVisibleGroup[identityID] = make(map[string]time.Time)
+							}
+
+							groupExpiriesByVisibleGroup[identityID][group.Name] = expiryDate
+						}
 					}
 				}
 			}
@@ -1227,9 +1295,15 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 					Identifier:           id.Identifier,
 					Name:                 id.Name,
 					Groups:               groupNamesByIdentityID[id.ID],
+					GroupExpiries:        groupExpiriesByVisibleGroup[id.ID],
 					TLSCertificate:       certificate,
 				}
 
+				delegation, ok := delegationsByIdentityID[id.ID]
+				if ok {
+					identity.Delegation = delegation.ToAPI()
+				}
+
 				apiIdentities = append(apiIdentities, identity)
 				urlToIdentity[entity.IdentityURL(string(id.AuthMethod), id.Identifier)] = identity
 			}
@@ -1476,12 +1550,158 @@ func identityGetCurrent(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	return response.SyncResponse(true, api.IdentityInfo{
+	return response.SyncResponseETag(true, api.IdentityInfo{
 		Identity:             *apiIdentity,
 		EffectiveGroups:      effectiveGroups,
 		EffectivePermissions: effectivePermissions,
 		FineGrained:          identityType.IsFineGrained(),
+	}, apiIdentity)
+}
+
+// swagger:operation PATCH /1.0/auth/identities/current identities identity_patch_current
+//
+//	Partially update the current identity
+//
+//	Updates the display name or the TLS certificate of the requestor, or revokes its sessions and tokens.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Update request
+//	    schema:
+//	      $ref: "#/definitions/IdentityCurrentPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+//	  "501":
+//	    $ref: "#/responses/NotImplemented"
+func identityPatchCurrent(d *Daemon, r *http.Request) response.Response {
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Must be a remote API request.
+	err = identity.ValidateAuthenticationMethod(requestor.CallerProtocol())
+	if err != nil {
+		return response.BadRequest(errors.New("Current identity information must be updated via the HTTPS API"))
+	}
+
+	s := d.State()
+	var id *dbCluster.Identity
+	var apiIdentity *api.Identity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err = dbCluster.GetIdentity(ctx, tx.Tx(), dbCluster.AuthMethod(requestor.CallerProtocol()), requestor.CallerUsername())
+		if err != nil {
+			return fmt.Errorf("Failed to get current identity from database: %w", err)
+		}
+
+		// The caller is allowed to view the groups that they are a member of.
+		apiIdentity, err = id.ToAPI(ctx, tx.Tx(), func(entityURL *api.URL) bool { return true })
+		if err != nil {
+			return err
+		}
+
+		return util.EtagCheck(r, apiIdentity)
 	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Apply the request on top of the current values.
+	req := api.IdentityCurrentPut{
+		DisplayName:    apiIdentity.DisplayName,
+		TLSCertificate: apiIdentity.TLSCertificate,
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Failed to unmarshal request body: %w", err))
+	}
+
+	if len(req.DisplayName) > 255 {
+		return response.BadRequest(errors.New("Display name must not be longer than 255 characters"))
+	}
+
+	if req.RevokeSessions && id.AuthMethod == api.AuthenticationMethodTLS {
+		return response.BadRequest(errors.New("TLS identities don't have sessions to revoke"))
+	}
+
+	// Parse the certificate if it's being replaced.
+	var fingerprint string
+	var metadata string
+	if req.TLSCertificate != apiIdentity.TLSCertificate {
+		identityType, err := identity.New(string(id.Type))
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if id.AuthMethod != api.AuthenticationMethodTLS {
+			return response.BadRequest(fmt.Errorf("Cannot set a certificate for identities of type %q", id.Type))
+		}
+
+		if !identityType.IsFineGrained() {
+			return response.NotImplemented(fmt.Errorf("Identities of type %q cannot be modified via this API", id.Type))
+		}
+
+		fingerprint, metadata, err = validateIdentityCert(s.Endpoints.NetworkCert(), req.TLSCertificate)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		if req.DisplayName != apiIdentity.DisplayName {
+			err := dbCluster.SetIdentityDisplayName(ctx, tx.Tx(), id.ID, req.DisplayName)
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.RevokeSessions {
+			err := dbCluster.RevokeIdentitySessions(ctx, tx.Tx(), id.ID, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		if fingerprint != "" && fingerprint != id.Identifier {
+			return dbCluster.UpdateIdentity(ctx, tx.Tx(), id.AuthMethod, id.Identifier, dbCluster.Identity{
+				AuthMethod: id.AuthMethod,
+				Type:       id.Type,
+				Identifier: fingerprint,
+				Name:       id.Name,
+				Metadata:   metadata,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other cluster members to update their identity cache.
+	notify := newIdentityNotificationFunc(s, r, s.Endpoints.NetworkCert(), s.ServerCert())
+	_, err = notify(lifecycle.IdentityUpdated, string(id.AuthMethod), id.Identifier, true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
 }
 
 // swagger:operation PUT /1.0/auth/identities/tls/{nameOrIdentifier} identities identity_put_tls
@@ -1619,7 +1839,7 @@ func updateSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluste
 		}
 
 		// Return an error if the caller tries to update their own groups.
-		if !slices.Equal(identityPut.Groups, apiIdentity.Groups) {
+		if !slices.Equal(identityPut.Groups, apiIdentity.Groups) || !maps.EqualFunc(identityPut.GroupExpiries, apiIdentity.GroupExpiries, time.Time.Equal) {
 			return api.NewStatusError(http.StatusForbidden, "Only the certificate may be changed")
 		}
 
@@ -1653,13 +1873,33 @@ func updateSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluste
 	return response.EmptySyncResponse
 }
 
+// validateGroupExpiries checks that the expiry dates of the group memberships are in the future and only apply to
+// groups the identity is a member of.
+func validateGroupExpiries(identityPut api.IdentityPut) error {
+	for groupName, expiryDate := range identityPut.GroupExpiries {
+		if !slices.Contains(identityPut.Groups, groupName) {
+			return api.StatusErrorf(http.StatusBadRequest, "Expiry date given for group %q which the identity isn't a member of", groupName)
+		}
+
+		if !expiryDate.After(time.Now()) {
+			return api.StatusErrorf(http.StatusBadRequest, "Expiry date of the membership to group %q must be in the future", groupName)
+		}
+	}
+
+	return nil
+}
+
 // updateIdentityPrivileged is called when the caller has `can_edit` on the identity. It must account for both OIDC and TLS identities.
 func updateIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Identity, identityPut api.IdentityPut) response.Response {
+	err := validateGroupExpiries(identityPut)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Validate certificate if given (not present for OIDC or pending TLS identities).
 	var fingerprint string
 	var metadata string
 	if identityPut.TLSCertificate != "" {
-		var err error
 		fingerprint, metadata, err = validateIdentityCert(s.Endpoints.NetworkCert(), identityPut.TLSCertificate)
 		if err != nil {
 			return response.SmartError(err)
@@ -1690,6 +1930,11 @@ func updateIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Iden
 			return err
 		}
 
+		err = dbCluster.SetIdentityAuthGroupExpiries(ctx, tx.Tx(), id.ID, identityPut.GroupExpiries)
+		if err != nil {
+			return err
+		}
+
 		if identityPut.TLSCertificate == "" || fingerprint == id.Identifier {
 			return nil
 		}
@@ -1833,11 +2078,15 @@ func identityPatch(authenticationMethod string) func(d *Daemon, r *http.Request)
 
 // patchIdentityPrivileged is invoked when the caller has `can_edit` on the identity. It must handle both OIDC and TLS identities.
 func patchIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Identity, identityPut api.IdentityPut) response.Response {
+	err := validateGroupExpiries(identityPut)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Parse the certificate if given.
 	var fingerprint string
 	var metadata string
 	if identityPut.TLSCertificate != "" {
-		var err error
 		fingerprint, metadata, err = validateIdentityCert(s.Endpoints.NetworkCert(), identityPut.TLSCertificate)
 		if err != nil {
 			return response.SmartError(err)
@@ -1874,6 +2123,11 @@ func patchIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Ident
 			return err
 		}
 
+		err = dbCluster.SetIdentityAuthGroupExpiries(ctx, tx.Tx(), id.ID, identityPut.GroupExpiries)
+		if err != nil {
+			return err
+		}
+
 		// Only update the certificate if it is given. Additionally, we don't need to update it if it's the same as the
 		// existing one.
 		if identityPut.TLSCertificate != "" && fingerprint != id.Identifier {
@@ -2023,6 +2277,11 @@ func identityDelete(d *Daemon, r *http.Request) response.Response {
 
 	s := d.State()
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := deleteIdentityDelegationGroup(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
 		return dbCluster.DeleteIdentity(ctx, tx.Tx(), id.AuthMethod, id.Identifier)
 	})
 	if err != nil {
@@ -2106,8 +2365,10 @@ func updateIdentityCache(d *Daemon) {
 	var identities []dbCluster.Identity
 	projects := make(map[int][]string)
 	groups := make(map[int][]string)
+	var groupExpiries map[int]map[string]time.Time
 	idpGroupMapping := make(map[string][]string)
 	bearerIdentitySecrets := make(map[int]dbCluster.AuthSecretValue)
+	var identityProfiles map[int]dbCluster.IdentityProfile
 	var err error
 	err = s.DB.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
 		identities, err = dbCluster.GetIdentitys(ctx, tx.Tx())
@@ -2115,6 +2376,11 @@ func updateIdentityCache(d *Daemon) {
 			return err
 		}
 
+		groupExpiries, err = dbCluster.GetAllAuthGroupExpiriesByIdentityIDs(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
 		for _, identity := range identities {
 			identityProjects, err := dbCluster.GetIdentityProjects(ctx, tx.Tx(), identity.ID)
 			if err != nil {
@@ -2155,6 +2421,11 @@ func updateIdentityCache(d *Daemon) {
 			return err
 		}
 
+		identityProfiles, err = dbCluster.GetAllIdentityProfiles(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -2182,6 +2453,8 @@ func updateIdentityCache(d *Daemon) {
 			IdentityType:         string(id.Type),
 			Projects:             projects[id.ID],
 			Groups:               groups[id.ID],
+			GroupExpiries:        groupExpiries[id.ID],
+			SessionsRevokedAt:    identityProfiles[id.ID].SessionsRevokedAt.Time,
 		}
 
 		if cacheEntry.AuthenticationMethod == api.AuthenticationMethodTLS {
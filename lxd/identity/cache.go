@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/canonical/lxd/shared/api"
 )
@@ -30,6 +31,10 @@ type CacheEntry struct {
 	Projects             []string
 	Groups               []string
 
+	// GroupExpiries is a map of group name to the expiry date of the membership to the group, for the memberships
+	// that expire.
+	GroupExpiries map[string]time.Time
+
 	// Certificate is optional. It is pre-computed for identities with AuthenticationMethod api.AuthenticationMethodTLS.
 	Certificate *x509.Certificate
 
@@ -38,6 +43,31 @@ type CacheEntry struct {
 
 	// Secret is optional. It is required for identities with AuthenticationMethod set to api.AuthenticationMethodBearer
 	Secret []byte
+
+	// SessionsRevokedAt is optional. Sessions and tokens of the identity started before this date are rejected.
+	SessionsRevokedAt time.Time
+}
+
+// SessionRevoked returns whether a session or token of the identity started at the given date was revoked.
+func (e *CacheEntry) SessionRevoked(startedAt time.Time) bool {
+	return !e.SessionsRevokedAt.IsZero() && startedAt.Before(e.SessionsRevokedAt)
+}
+
+// ActiveGroups returns the groups of the identity, excluding those whose membership has expired since the cache was
+// last updated.
+func (e *CacheEntry) ActiveGroups() []string {
+	now := time.Now()
+	groups := make([]string, 0, len(e.Groups))
+	for _, group := range e.Groups {
+		expiryDate, ok := e.GroupExpiries[group]
+		if ok && !expiryDate.After(now) {
+			continue
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
 }
 
 // Get returns a single CacheEntry by its authentication method and identifier.
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// checkCreateIdentityPermission checks that the caller can create identities confined to the given project, or any
+// identity if no project is given.
+func checkCreateIdentityPermission(ctx context.Context, s *state.State, projectName string) error {
+	if projectName == "" {
+		return s.Authorizer.CheckPermission(ctx, entity.ServerURL(), auth.EntitlementCanCreateIdentities)
+	}
+
+	return s.Authorizer.CheckPermission(ctx, entity.ProjectURL(projectName), auth.EntitlementCanCreateIdentities)
+}
+
+// validateDelegation checks the permissions delegated to a new identity confined to the given project. The
+// permissions must apply to the project or to entities of the project, and the caller must hold each of them so that
+// delegating can't escalate their privileges.
+func validateDelegation(ctx context.Context, s *state.State, projectName string, groups []string, permissions []api.Permission) error {
+	if projectName == "" {
+		if len(permissions) > 0 {
+			return api.NewStatusError(http.StatusBadRequest, "Permissions can only be given to identities confined to a project")
+		}
+
+		return nil
+	}
+
+	if len(groups) > 0 {
+		return api.NewStatusError(http.StatusBadRequest, "Identities confined to a project can't be added to groups")
+	}
+
+	if len(permissions) == 0 {
+		return api.NewStatusError(http.StatusBadRequest, "At least one permission must be given to identities confined to a project")
+	}
+
+	err := validatePermissions(permissions)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		u, err := url.Parse(permission.EntityReference)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to parse permission entity reference: %w", err)
+		}
+
+		entityType, entityProject, _, pathArguments, err := entity.ParseURL(*u)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed to parse permission entity reference: %w", err)
+		}
+
+		requiresProject, err := entityType.RequiresProject()
+		if err != nil {
+			return err
+		}
+
+		if entityType == entity.TypeProject && len(pathArguments) > 0 {
+			entityProject = pathArguments[0]
+		} else if !requiresProject {
+			return api.StatusErrorf(http.StatusBadRequest, "Permissions on entities of type %q can't be given to identities confined to a project", entityType)
+		}
+
+		if entityProject != projectName {
+			return api.StatusErrorf(http.StatusBadRequest, "Entity %q isn't in project %q", permission.EntityReference, projectName)
+		}
+
+		err = s.Authorizer.CheckPermission(ctx, &api.URL{URL: *u}, auth.Entitlement(permission.Entitlement))
+		if auth.IsDeniedError(err) {
+			return api.StatusErrorf(http.StatusForbidden, "Can't delegate entitlement %q on %q without holding it", permission.Entitlement, permission.EntityReference)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delegateIdentity grants the permissions to the identity with the given ID through a dedicated group, and records
+// the caller as the parent of the identity.
+func delegateIdentity(ctx context.Context, tx *sql.Tx, identityID int, projectName string, permissions []api.Permission) error {
+	requestor, err := request.GetRequestor(ctx)
+	if err != nil {
+		return err
+	}
+
+	groupName := "delegation-" + uuid.New().String()
+	groupID, err := dbCluster.CreateAuthGroup(ctx, tx, dbCluster.AuthGroup{
+		Name:        groupName,
+		Description: fmt.Sprintf("Permissions delegated by %s in project %q", requestor.CallerUsername(), projectName),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = upsertPermissions(ctx, tx, int(groupID), permissions)
+	if err != nil {
+		return err
+	}
+
+	err = dbCluster.SetIdentityAuthGroups(ctx, tx, identityID, []string{groupName})
+	if err != nil {
+		return err
+	}
+
+	delegation := dbCluster.IdentityDelegation{
+		IdentityID: identityID,
+		Project:    projectName,
+		AuthGroup:  groupName,
+		Date:       time.Now(),
+	}
+
+	caller := requestor.CallerIdentity()
+	if caller != nil {
+		delegation.Parent = entity.IdentityURL(caller.AuthenticationMethod, caller.Identifier).String()
+
+		parentID, err := dbCluster.GetIdentityID(ctx, tx, dbCluster.AuthMethod(caller.AuthenticationMethod), caller.Identifier)
+		if err == nil {
+			delegation.ParentIdentityID = sql.NullInt64{Int64: parentID, Valid: true}
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+	} else {
+		// Callers without an identity, such as on the unix socket, are recorded by protocol and username.
+		delegation.Parent = entity.IdentityURL(requestor.CallerProtocol(), requestor.CallerUsername()).String()
+	}
+
+	return dbCluster.CreateIdentityDelegation(ctx, tx, delegation)
+}
+
+// isIdentityParent returns whether the caller created the given identity by delegation.
+func isIdentityParent(ctx context.Context, s *state.State, id dbCluster.Identity) (bool, error) {
+	requestor, err := request.GetRequestor(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	caller := requestor.CallerIdentity()
+	if caller == nil {
+		return false, nil
+	}
+
+	var delegation *dbCluster.IdentityDelegation
+	var parentID int64
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		delegation, err = dbCluster.GetIdentityDelegation(ctx, tx.Tx(), id.ID)
+		if err != nil || delegation == nil {
+			return err
+		}
+
+		parentID, err = dbCluster.GetIdentityID(ctx, tx.Tx(), dbCluster.AuthMethod(caller.AuthenticationMethod), caller.Identifier)
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil
+		}
+
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if delegation == nil || !delegation.ParentIdentityID.Valid {
+		return false, nil
+	}
+
+	return delegation.ParentIdentityID.Int64 == parentID, nil
+}
+
+// deleteIdentityDelegationGroup deletes the group holding the permissions delegated to the identity, if any. It must
+// be called before the identity is deleted.
+func deleteIdentityDelegationGroup(ctx context.Context, tx *sql.Tx, identityID int) error {
+	delegation, err := dbCluster.GetIdentityDelegation(ctx, tx, identityID)
+	if err != nil {
+		return err
+	}
+
+	if delegation == nil || delegation.AuthGroup == "" {
+		return nil
+	}
+
+	err = dbCluster.DeleteAuthGroup(ctx, tx, delegation.AuthGroup)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed deleting group of delegated identity: %w", err)
+	}
+
+	return nil
+}
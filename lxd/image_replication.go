@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var imageLocationsCmd = APIEndpoint{
+	Path:        "images/{fingerprint}/locations",
+	MetricsType: entity.TypeImage,
+
+	Get: APIEndpointAction{Handler: imageLocationsGet, AccessHandler: imageAccessHandler(auth.EntitlementCanView)},
+}
+
+// imageReplicationState returns the names of the cluster members holding a copy of the image, the names of the
+// cluster members missing a copy required by the image replication policies of the cluster groups and the addresses
+// of the cluster members by name.
+func imageReplicationState(ctx context.Context, tx *db.ClusterTx, fingerprint string) ([]string, []string, map[string]string, error) {
+	holders, err := tx.GetNodeNamesWithImage(ctx, fingerprint)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed getting cluster members with image: %w", err)
+	}
+
+	groups, err := dbCluster.GetClusterGroups(ctx, tx.Tx())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed getting cluster groups: %w", err)
+	}
+
+	policies := []cluster.ImageReplicationPolicy{}
+	for _, group := range groups {
+		config, err := dbCluster.GetClusterGroupConfig(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		members, err := tx.GetClusterGroupNodes(ctx, group.Name)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Failed getting members of cluster group %q: %w", group.Name, err)
+		}
+
+		policy := cluster.ImageReplicationPolicyFromConfig(config, members)
+		if policy != nil {
+			policies = append(policies, *policy)
+		}
+	}
+
+	offlineThreshold, err := tx.GetNodeOfflineThreshold(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	nodes, err := tx.GetNodes(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	addresses := make(map[string]string, len(nodes))
+	available := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		addresses[node.Name] = node.Address
+
+		if !node.IsOffline(offlineThreshold) {
+			available = append(available, node.Name)
+		}
+	}
+
+	return holders, cluster.ImageReplicationTargets(policies, holders, available), addresses, nil
+}
+
+// imageSyncClusterGroups copies the image to the cluster members which require it according to the image replication
+// policies of the cluster groups.
+func imageSyncClusterGroups(ctx context.Context, s *state.State, project string, fingerprint string) error {
+	var pending []string
+	var addresses map[string]string
+	var sourceAddresses []string
+	var image *api.Image
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		_, pending, addresses, err = imageReplicationState(ctx, tx, fingerprint)
+		if err != nil {
+			return err
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		sourceAddresses, err = tx.GetNodesWithImage(ctx, fingerprint)
+		if err != nil {
+			return fmt.Errorf("Failed to get nodes for the image synchronization: %w", err)
+		}
+
+		_, image, err = tx.GetImage(ctx, fingerprint, dbCluster.ImageFilter{Project: &project})
+		if err != nil {
+			return fmt.Errorf("Failed to get image: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 || len(sourceAddresses) == 0 {
+		return nil
+	}
+
+	// Pick a random member holding the image as the source.
+	source, err := cluster.Connect(ctx, sourceAddresses[rand.Intn(len(sourceAddresses))], s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to source node for image synchronization: %w", err)
+	}
+
+	source = source.UseProject(project)
+
+	for _, member := range pending {
+		logger.Info("Replicating image to cluster group member", logger.Ctx{"fingerprint": fingerprint, "project": project, "member": member})
+		err = imageCopyToMember(ctx, s, source, image, project, addresses[member])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/images/{fingerprint}/locations images image_locations_get
+//
+//	Get the image locations
+//
+//	Gets the cluster members holding a copy of the image, and those still missing a copy required by the
+//	image replication policies of the cluster groups.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Image locations
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ImageLocations"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageLocationsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	details, err := request.GetContextValue[imageDetails](r.Context(), ctxImageDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	locations := api.ImageLocations{}
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		locations.Members, locations.Pending, _, err = imageReplicationState(ctx, tx, details.image.Fingerprint)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, locations)
+}
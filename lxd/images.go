@@ -413,6 +413,7 @@ func imgPostInstanceInfo(s *state.State, req api.ImagesPost, op *operations.Oper
 				}
 
 				shared.SetProgressMetadata(metadata, "create_image_from_instance_pack", "Image pack", percent, processed, speed)
+				metadata[api.OperationProgressMetadataKey] = op.RecordProgress("create_image_from_instance_pack", ioprogress.ProgressData{Percentage: int(percent), TransferredBytes: processed, Speed: speed})
 				_ = op.UpdateMetadata(metadata)
 			},
 			Length: totalSize,
@@ -633,6 +634,11 @@ func imgPostURLInfo(ctx context.Context, s *state.State, req api.ImagesPost, op
 		return nil, errors.New("Missing URL")
 	}
 
+	err = imageServerAllowed(ctx, s, project, req.Source.URL)
+	if err != nil {
+		return nil, err
+	}
+
 	myhttp, err := util.HTTPClient("", s.Proxy)
 	if err != nil {
 		return nil, err
@@ -1376,6 +1382,12 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Failed syncing image between nodes: %w", err)
 		}
 
+		// Apply the image replication policies of the cluster groups.
+		err = imageSyncClusterGroups(s.ShutdownCtx, s, dbProject.Name, info.Fingerprint)
+		if err != nil {
+			return fmt.Errorf("Failed syncing image to cluster groups: %w", err)
+		}
+
 		s.Events.SendLifecycle(dbProject.Name, lifecycle.ImageCreated.Event(info.Fingerprint, dbProject.Name, op.EventLifecycleRequestor(), logger.Ctx{"type": info.Type}))
 
 		return nil
@@ -1515,9 +1527,7 @@ func getImageMetadata(fname string) (*api.ImageMetadata, string, error) {
 	return &result, imageType, nil
 }
 
-func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectName string, public bool, clauses *filter.ClauseSet, hasPermission auth.PermissionChecker, allProjects bool) (any, error) {
-	mustLoadObjects := recursion || (clauses != nil && len(clauses.Clauses) > 0)
-
+func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectName string, public bool, clauses *filter.ClauseSet, hasPermission auth.PermissionChecker, allProjects bool, pagination *request.Pagination) (any, error) {
 	imagesProjectsMap := map[string][]string{}
 	if allProjects {
 		var err error
@@ -1526,69 +1536,89 @@ func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectN
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		fingerprints, err := tx.GetImagesFingerprints(ctx, projectName, public)
-		if err != nil {
-			return nil, err
-		}
+	}
 
-		for _, fingerprint := range fingerprints {
-			imagesProjectsMap[fingerprint] = []string{projectName}
+	imageProjects := func(fingerprint string) []string {
+		if allProjects {
+			return imagesProjectsMap[fingerprint]
 		}
+
+		return []string{projectName}
 	}
 
-	var resultString []string
-	var resultMap []*api.Image
+	hasAccess := func(fingerprint string, imagePublic bool) bool {
+		if imagePublic {
+			return true
+		}
 
-	if recursion {
-		resultMap = make([]*api.Image, 0, len(imagesProjectsMap))
-	} else {
-		resultString = make([]string, 0, len(imagesProjectsMap))
+		for _, project := range imageProjects(fingerprint) {
+			if hasPermission(entity.ImageURL(project, fingerprint)) {
+				return true
+			}
+		}
+
+		return false
 	}
 
-	for fingerprint, projects := range imagesProjectsMap {
-		hasAccess := false
+	// Select the page of images in the database so that only those are loaded, unless the images must be loaded to
+	// be filtered. Their page is then selected once they're filtered.
+	filterLoaded := clauses != nil && len(clauses.Clauses) > 0
+	dbPagination := pagination
+	if filterLoaded {
+		dbPagination = &request.Pagination{Sort: pagination.Sort}
+	}
 
-		image, err := doImageGet(ctx, tx, projects[0], fingerprint, public)
-		if err != nil {
-			continue
-		}
+	fingerprints, err := tx.GetImagesFingerprintsPage(ctx, projectName, allProjects, public, dbPagination, hasAccess)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, project := range projects {
-			if image.Public || hasPermission(entity.ImageURL(project, fingerprint)) {
-				hasAccess = true
-				break
-			}
+	if !recursion && !filterLoaded {
+		resultString := make([]string, 0, len(fingerprints))
+		for _, fingerprint := range fingerprints {
+			resultString = append(resultString, api.NewURL().Path(version.APIVersion, "images", fingerprint).String())
 		}
 
-		if !hasAccess {
+		return resultString, nil
+	}
+
+	images := make([]*api.Image, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		projects := imageProjects(fingerprint)
+		if len(projects) == 0 {
 			continue
 		}
 
-		if !mustLoadObjects {
-			resultString = append(resultString, api.NewURL().Path(version.APIVersion, "images", fingerprint).String())
-		} else {
-			if clauses != nil && len(clauses.Clauses) > 0 {
-				match, err := filter.Match(*image, *clauses)
-				if err != nil {
-					return nil, err
-				}
+		image, err := doImageGet(ctx, tx, projects[0], fingerprint, public)
+		if err != nil {
+			continue
+		}
 
-				if !match {
-					continue
-				}
+		if filterLoaded {
+			match, err := filter.Match(*image, *clauses)
+			if err != nil {
+				return nil, err
 			}
 
-			if recursion {
-				resultMap = append(resultMap, image)
-			} else {
-				resultString = append(resultString, api.NewURL().Path(version.APIVersion, "images", image.Fingerprint).String())
+			if !match {
+				continue
 			}
 		}
+
+		images = append(images, image)
+	}
+
+	if filterLoaded {
+		images = request.Page(pagination, images)
 	}
 
 	if recursion {
-		return resultMap, nil
+		return images, nil
+	}
+
+	resultString := make([]string, 0, len(images))
+	for _, image := range images {
+		resultString = append(resultString, api.NewURL().Path(version.APIVersion, "images", image.Fingerprint).String())
 	}
 
 	return resultString, nil
@@ -1618,6 +1648,21 @@ func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectN
 //      name: all-projects
 //      description: Retrieve images from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -1676,6 +1721,26 @@ func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectN
 //      name: all-projects
 //      description: Retrieve images from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
+//    - in: query
+//      name: fields
+//      description: Comma-separated list of fields to return
+//      type: string
+//      example: fingerprint,size
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -1729,6 +1794,21 @@ func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectN
 //      name: all-projects
 //      description: Retrieve images from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -1788,6 +1868,26 @@ func doImagesGet(ctx context.Context, tx *db.ClusterTx, recursion bool, projectN
 //	    description: Retrieve images from all projects
 //	    type: boolean
 //	    example: default
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return
+//	    type: integer
+//	    example: 100
+//	  - in: query
+//	    name: offset
+//	    description: Number of entries to skip
+//	    type: integer
+//	    example: 200
+//	  - in: query
+//	    name: sort
+//	    description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//	    type: string
+//	    example: -created_at
+//	  - in: query
+//	    name: fields
+//	    description: Comma-separated list of fields to return
+//	    type: string
+//	    example: fingerprint,size
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -1884,9 +1984,14 @@ func imagesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Invalid filter: %w", err))
 	}
 
+	pagination, err := request.PaginationParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	var result any
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		result, err = doImagesGet(ctx, tx, recursion, projectName, publicOnly, clauses, canViewImage, allProjects)
+		result, err = doImagesGet(ctx, tx, recursion, projectName, publicOnly, clauses, canViewImage, allProjects, pagination)
 		if err != nil {
 			return err
 		}
@@ -1918,6 +2023,13 @@ func imagesGet(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	if recursion {
+		result, err = pagination.SelectFields(result)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	return response.SyncResponse(true, result)
 }
 
@@ -4924,6 +5036,11 @@ func autoSyncImages(ctx context.Context, s *state.State) error {
 				logger.Error("Failed to synchronize images", logger.Ctx{"err": err, "project": projectName, "fingerprint": fingerprint})
 			}
 
+			err = imageSyncClusterGroups(ctx, s, projectName, fingerprint)
+			if err != nil {
+				logger.Error("Failed to synchronize images to cluster groups", logger.Ctx{"err": err, "project": projectName, "fingerprint": fingerprint})
+			}
+
 			ch <- nil
 		}(projects[0], fingerprint)
 
@@ -5010,12 +5127,6 @@ func imageSyncBetweenNodes(ctx context.Context, s *state.State, r *http.Request,
 		return fmt.Errorf("Failed to get image: %w", err)
 	}
 
-	// Populate the copy arguments with properties from the source image.
-	args := lxd.ImageCopyArgs{
-		Type:   image.Type,
-		Public: image.Public,
-	}
-
 	// Replicate on as many nodes as needed.
 	for range int(nodeCount) {
 		var addresses []string
@@ -5038,28 +5149,39 @@ func imageSyncBetweenNodes(ctx context.Context, s *state.State, r *http.Request,
 		// Pick a random node from that slice as the target.
 		targetNodeAddress := addresses[rand.Intn(len(addresses))]
 
-		client, err := cluster.Connect(reqContext, targetNodeAddress, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+		err = imageCopyToMember(reqContext, s, source, image, project, targetNodeAddress)
 		if err != nil {
-			return fmt.Errorf("Failed to connect node for image synchronization: %w", err)
+			return err
 		}
+	}
 
-		// Select the right project.
-		client = client.UseProject(project)
+	return nil
+}
 
-		// Copy the image to the target server.
-		logger.Info("Copying image to member", logger.Ctx{"fingerprint": fingerprint, "address": targetNodeAddress, "project": project, "public": args.Public, "type": args.Type})
-		op, err := client.CopyImage(source, *image, &args)
-		if err != nil {
-			return fmt.Errorf("Failed to copy image to %q: %w", targetNodeAddress, err)
-		}
+// imageCopyToMember copies the image from the source cluster member to the cluster member with the given address.
+func imageCopyToMember(ctx context.Context, s *state.State, source lxd.InstanceServer, image *api.Image, project string, targetNodeAddress string) error {
+	// Populate the copy arguments with properties from the source image.
+	args := lxd.ImageCopyArgs{
+		Type:   image.Type,
+		Public: image.Public,
+	}
 
-		err = op.Wait()
-		if err != nil {
-			return err
-		}
+	client, err := cluster.Connect(ctx, targetNodeAddress, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect node for image synchronization: %w", err)
 	}
 
-	return nil
+	// Select the right project.
+	client = client.UseProject(project)
+
+	// Copy the image to the target server.
+	logger.Info("Copying image to member", logger.Ctx{"fingerprint": image.Fingerprint, "address": targetNodeAddress, "project": project, "public": args.Public, "type": args.Type})
+	op, err := client.CopyImage(source, *image, &args)
+	if err != nil {
+		return fmt.Errorf("Failed to copy image to %q: %w", targetNodeAddress, err)
+	}
+
+	return op.Wait()
 }
 
 func createTokenResponse(s *state.State, r *http.Request, projectName string, fingerprint string, metadata shared.Jmap) response.Response {
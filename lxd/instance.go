@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -19,13 +22,18 @@ import (
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/rsync"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/gorilla/mux"
 )
 
 // Helper functions
@@ -83,7 +91,7 @@ func instanceImageTransfer(ctx context.Context, s *state.State, localProjectName
 	return nil
 }
 
-func ensureImageIsLocallyAvailable(ctx context.Context, s *state.State, img *api.Image, projectName string) error {
+func ensureImageIsLocallyAvailable(ctx context.Context, s *state.State, img *api.Image, imageProjectName string, projectName string) error {
 	// Check if the image is available locally or it's on another member.
 	// Ensure we are the only ones operating on this image. Otherwise another instance created at the same
 	// time may also arrive at the conclusion that the image doesn't exist on this cluster member and then
@@ -109,20 +117,37 @@ func ensureImageIsLocallyAvailable(ctx context.Context, s *state.State, img *api
 
 	if memberAddress != "" {
 		// The image is available from another node, let's try to import it.
-		err = instanceImageTransfer(ctx, s, projectName, projectName, img.Fingerprint, memberAddress)
+		err = instanceImageTransfer(ctx, s, imageProjectName, imageProjectName, img.Fingerprint, memberAddress)
 		if err != nil {
 			return fmt.Errorf("Failed transferring image %q from %q: %w", img.Fingerprint, memberAddress, err)
 		}
 
 		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			// As the image record already exists in the project, just add the node ID to the image.
-			return tx.AddImageToLocalNode(ctx, projectName, img.Fingerprint)
+			return tx.AddImageToLocalNode(ctx, imageProjectName, img.Fingerprint)
 		})
 		if err != nil {
 			return fmt.Errorf("Failed adding transferred image %q record to local cluster member: %w", img.Fingerprint, err)
 		}
 	}
 
+	// Copy the image files of images shared by another project if these use different storage.
+	if imageProjectName != projectName && s.LocalConfig.StorageImagesVolume(imageProjectName) != s.LocalConfig.StorageImagesVolume(projectName) {
+		sourcePath := filepath.Join(s.ImagesStoragePath(imageProjectName), img.Fingerprint)
+		destPath := s.ImagesStoragePath(projectName)
+
+		for _, path := range []string{sourcePath, sourcePath + ".rootfs"} {
+			if !shared.PathExists(path) {
+				continue
+			}
+
+			_, err = rsync.CopyFile(path, destPath, "", false)
+			if err != nil {
+				return fmt.Errorf("Failed to copy image files from project %q: %w", imageProjectName, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -202,7 +227,7 @@ func instanceCreateFromImage(s *state.State, img *api.Image, args db.InstanceArg
 	return nil
 }
 
-func instanceRebuildFromImage(ctx context.Context, s *state.State, inst instance.Instance, img *api.Image, op *operations.Operation) error {
+func instanceRebuildFromImage(ctx context.Context, s *state.State, inst instance.Instance, img *api.Image, imageProjectName string, op *operations.Operation) error {
 	// Validate the type of the image matches the type of the instance.
 	imgType, err := instancetype.New(img.Type)
 	if err != nil {
@@ -213,7 +238,7 @@ func instanceRebuildFromImage(ctx context.Context, s *state.State, inst instance
 		return fmt.Errorf("Requested image's type %q doesn't match instance type %q", imgType, inst.Type())
 	}
 
-	err = ensureImageIsLocallyAvailable(ctx, s, img, inst.Project().Name)
+	err = ensureImageIsLocallyAvailable(ctx, s, img, imageProjectName, inst.Project().Name)
 	if err != nil {
 		return err
 	}
@@ -492,6 +517,37 @@ func instanceLoadNodeProjectAll(ctx context.Context, s *state.State, project str
 	return instances, nil
 }
 
+// instanceLoadByIDs loads the local instances with the given IDs.
+func instanceLoadByIDs(ctx context.Context, s *state.State, ids []int) ([]instance.Instance, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	filters := make([]dbCluster.InstanceFilter, 0, len(ids))
+	for _, id := range ids {
+		filters = append(filters, dbCluster.InstanceFilter{ID: &id})
+	}
+
+	var instances []instance.Instance
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			instances = append(instances, inst)
+
+			return nil
+		}, filters...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
 func autoCreateInstanceSnapshots(ctx context.Context, s *state.State, instances []instance.Instance) error {
 	// Make the snapshots.
 	for _, inst := range instances {
@@ -733,10 +789,22 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(stateFunc func() *state.Stat
 	return f, schedule
 }
 
+// sourceImageProject returns the project of the image of an instance source. Local images shared by another project
+// are referenced using the project of the source.
+func sourceImageProject(projectName string, source api.InstanceSource) string {
+	if source.Server == "" && source.Project != "" {
+		return source.Project
+	}
+
+	return projectName
+}
+
 // getSourceImageFromInstanceSource returns the image to use for an instance source.
 func getSourceImageFromInstanceSource(ctx context.Context, s *state.State, tx *db.ClusterTx, project string, source api.InstanceSource, imageRef *string, instType string) (*api.Image, error) {
+	imageProject := sourceImageProject(project, source)
+
 	// Resolve the image.
-	sourceImageRefUpdate, err := instance.ResolveImage(ctx, tx, project, source)
+	sourceImageRefUpdate, err := instance.ResolveImage(ctx, tx, imageProject, source)
 	if err != nil {
 		return nil, err
 	}
@@ -758,11 +826,19 @@ func getSourceImageFromInstanceSource(ctx context.Context, s *state.State, tx *d
 	}
 
 	// Check if image has an entry in the database.
-	_, sourceImage, err := tx.GetImageByFingerprintPrefix(ctx, sourceImageHash, dbCluster.ImageFilter{Project: &project})
+	imageID, sourceImage, err := tx.GetImageByFingerprintPrefix(ctx, sourceImageHash, dbCluster.ImageFilter{Project: &imageProject})
 	if err != nil {
 		return nil, err
 	}
 
+	// Images of other projects must be shared with the project for attaching.
+	if imageProject != project {
+		err = dbCluster.CheckProjectShareAttach(ctx, tx.Tx(), entity.TypeImage, imageID, project)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return sourceImage, nil
 }
 
@@ -774,3 +850,43 @@ func instanceOperationLock(ctx context.Context, projectName string, instanceName
 
 	return locking.Lock(ctx, "InstanceOperation_"+project.Instance(projectName, instanceName))
 }
+
+// instanceLoadLocalVM loads the local VM targeted by the request.
+// A non-nil response is returned if the request was forwarded or failed.
+func instanceLoadLocalVM(s *state.State, r *http.Request) (instance.Instance, response.Response) {
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return nil, response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if resp != nil {
+		return nil, resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.VM {
+		return nil, response.BadRequest(errors.New("This operation is only supported for VM type instances"))
+	}
+
+	return inst, nil
+}
@@ -0,0 +1,282 @@
+package clone
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// IdentityConfigFile is the name of the file in the VM config drive that requests the lxd-agent to regenerate the
+// identity of the guest.
+const IdentityConfigFile = "identity.json"
+
+// IdentityConfig represents the identity regeneration requested from the lxd-agent.
+type IdentityConfig struct {
+	Hostname string   `json:"hostname"`
+	Items    []string `json:"items"`
+}
+
+// GuestIdentityItems returns the identity items that need to be regenerated within the guest filesystem.
+func GuestIdentityItems(items []string) []string {
+	guestItems := make([]string, 0, len(items))
+	for _, item := range items {
+		if item == api.InstanceIdentityHWAddr || slices.Contains(guestItems, item) {
+			continue
+		}
+
+		guestItems = append(guestItems, item)
+	}
+
+	return guestItems
+}
+
+// RegenerateIdentity regenerates the requested identity items within the guest filesystem found at rootPath.
+// Path resolution is confined to rootPath and any new file is owned by the provided uid and gid.
+func RegenerateIdentity(rootPath string, hostname string, items []string, uid int, gid int) error {
+	root, err := os.OpenRoot(rootPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = root.Close() }()
+
+	if slices.Contains(items, api.InstanceIdentityMachineID) {
+		err = regenerateMachineID(root)
+		if err != nil {
+			return fmt.Errorf("Failed regenerating machine ID: %w", err)
+		}
+	}
+
+	if slices.Contains(items, api.InstanceIdentitySSHHostKeys) {
+		err = regenerateSSHHostKeys(root, hostname, uid, gid)
+		if err != nil {
+			return fmt.Errorf("Failed regenerating SSH host keys: %w", err)
+		}
+	}
+
+	if slices.Contains(items, api.InstanceIdentityHostname) {
+		err = regenerateHostname(root, hostname, uid, gid)
+		if err != nil {
+			return fmt.Errorf("Failed setting hostname: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readFile returns the content of the file at name, or nil if it doesn't exist.
+func readFile(root *os.Root, name string) ([]byte, error) {
+	f, err := root.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return io.ReadAll(f)
+}
+
+// writeFile replaces the content of the file at name, creating it with the provided owner and mode if needed.
+// The owner and mode of an existing file are left untouched.
+func writeFile(root *os.Root, name string, data []byte, uid int, gid int, mode os.FileMode) error {
+	_, err := root.Lstat(name)
+	create := errors.Is(err, fs.ErrNotExist)
+	if err != nil && !create {
+		return err
+	}
+
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	if create {
+		err = f.Chown(uid, gid)
+		if err != nil {
+			return err
+		}
+
+		err = f.Chmod(mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// regenerateMachineID replaces the machine ID with a new random one.
+// Images that ship without a machine ID (or with an empty one) get one generated on first boot, so those are left
+// alone.
+func regenerateMachineID(root *os.Root) error {
+	content, err := readFile(root, "etc/machine-id")
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(content)) == "" {
+		return nil
+	}
+
+	buf := make([]byte, 16)
+	_, err = rand.Read(buf)
+	if err != nil {
+		return err
+	}
+
+	machineID := []byte(hex.EncodeToString(buf) + "\n")
+
+	err = writeFile(root, "etc/machine-id", machineID, 0, 0, 0444)
+	if err != nil {
+		return err
+	}
+
+	// Older distributions keep a separate copy of the machine ID for D-Bus rather than a symlink.
+	info, err := root.Lstat("var/lib/dbus/machine-id")
+	if err == nil && info.Mode().IsRegular() {
+		err = writeFile(root, "var/lib/dbus/machine-id", machineID, 0, 0, 0444)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sshHostKeyPattern matches the names of the SSH host private keys.
+var sshHostKeyPattern = regexp.MustCompile(`^ssh_host_([a-z0-9]+)_key$`)
+
+// regenerateSSHHostKeys replaces each of the SSH host keys with a new key of the same type.
+// Keys of types that can't be generated (such as DSA) are removed.
+func regenerateSSHHostKeys(root *os.Root, hostname string, uid int, gid int) error {
+	dir, err := root.Open("etc/ssh")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	names, err := dir.Readdirnames(-1)
+	_ = dir.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		match := sshHostKeyPattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+
+		keyPath := path.Join("etc/ssh", name)
+
+		var key crypto.Signer
+		switch match[1] {
+		case "rsa":
+			key, err = rsa.GenerateKey(rand.Reader, 3072)
+		case "ecdsa":
+			key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		case "ed25519":
+			_, key, err = ed25519.GenerateKey(rand.Reader)
+		default:
+			for _, p := range []string{keyPath, keyPath + ".pub"} {
+				err = root.Remove(p)
+				if err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		comment := "root@" + hostname
+
+		block, err := ssh.MarshalPrivateKey(key, comment)
+		if err != nil {
+			return err
+		}
+
+		pub, err := ssh.NewPublicKey(key.Public())
+		if err != nil {
+			return err
+		}
+
+		err = writeFile(root, keyPath, pem.EncodeToMemory(block), uid, gid, 0600)
+		if err != nil {
+			return err
+		}
+
+		authorizedKey := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n") + " " + comment + "\n"
+		err = writeFile(root, keyPath+".pub", []byte(authorizedKey), uid, gid, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// regenerateHostname sets the hostname in /etc/hostname and replaces the previous hostname in /etc/hosts.
+func regenerateHostname(root *os.Root, hostname string, uid int, gid int) error {
+	content, err := readFile(root, "etc/hostname")
+	if err != nil {
+		return err
+	}
+
+	oldHostname := strings.TrimSpace(string(content))
+
+	err = writeFile(root, "etc/hostname", []byte(hostname+"\n"), uid, gid, 0644)
+	if err != nil {
+		return err
+	}
+
+	if oldHostname == "" || oldHostname == hostname {
+		return nil
+	}
+
+	hosts, err := readFile(root, "etc/hosts")
+	if err != nil || hosts == nil {
+		return err
+	}
+
+	// Replace the old hostname, including as the first label of a fully qualified name.
+	oldHostnamePattern := regexp.MustCompile(`(?m)(^|[ \t])` + regexp.QuoteMeta(oldHostname) + `([ \t.]|$)`)
+	newHosts := oldHostnamePattern.ReplaceAll(hosts, []byte("${1}"+hostname+"${2}"))
+
+	return writeFile(root, "etc/hosts", newHosts, uid, gid, 0644)
+}
@@ -0,0 +1,98 @@
+package clone
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestRegenerateIdentity(t *testing.T) {
+	rootPath := t.TempDir()
+
+	files := map[string]string{
+		"etc/machine-id":                   "0123456789abcdef0123456789abcdef\n",
+		"etc/hostname":                     "c1\n",
+		"etc/hosts":                        "127.0.0.1 localhost\n127.0.1.1 c1.example.com c1\n10.0.0.1 c10\n",
+		"etc/ssh/ssh_host_ed25519_key":     "old",
+		"etc/ssh/ssh_host_ed25519_key.pub": "old",
+		"etc/ssh/ssh_host_dsa_key":         "old",
+		"etc/ssh/ssh_host_dsa_key.pub":     "old",
+		"etc/ssh/sshd_config":              "PermitRootLogin no\n",
+	}
+
+	for name, content := range files {
+		err := os.MkdirAll(filepath.Join(rootPath, filepath.Dir(name)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filepath.Join(rootPath, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items := []string{api.InstanceIdentityMachineID, api.InstanceIdentitySSHHostKeys, api.InstanceIdentityHostname}
+	err := RegenerateIdentity(rootPath, "c2", items, os.Getuid(), os.Getgid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readFile := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(rootPath, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(content)
+	}
+
+	machineID := strings.TrimSpace(readFile("etc/machine-id"))
+	if len(machineID) != 32 || machineID == strings.TrimSpace(files["etc/machine-id"]) {
+		t.Errorf("Machine ID wasn't regenerated: %q", machineID)
+	}
+
+	if readFile("etc/hostname") != "c2\n" {
+		t.Errorf("Unexpected hostname %q", readFile("etc/hostname"))
+	}
+
+	expectedHosts := "127.0.0.1 localhost\n127.0.1.1 c2.example.com c2\n10.0.0.1 c10\n"
+	if readFile("etc/hosts") != expectedHosts {
+		t.Errorf("Unexpected hosts file %q, expected %q", readFile("etc/hosts"), expectedHosts)
+	}
+
+	_, err = ssh.ParsePrivateKey([]byte(readFile("etc/ssh/ssh_host_ed25519_key")))
+	if err != nil {
+		t.Errorf("Invalid SSH host private key: %v", err)
+	}
+
+	_, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(readFile("etc/ssh/ssh_host_ed25519_key.pub")))
+	if err != nil {
+		t.Errorf("Invalid SSH host public key: %v", err)
+	} else if comment != "root@c2" {
+		t.Errorf("Unexpected SSH host key comment %q", comment)
+	}
+
+	for _, name := range []string{"etc/ssh/ssh_host_dsa_key", "etc/ssh/ssh_host_dsa_key.pub"} {
+		_, err = os.Stat(filepath.Join(rootPath, name))
+		if !os.IsNotExist(err) {
+			t.Errorf("Expected %q to be removed", name)
+		}
+	}
+
+	if readFile("etc/ssh/sshd_config") != files["etc/ssh/sshd_config"] {
+		t.Error("Unrelated SSH configuration was modified")
+	}
+}
+
+func TestGuestIdentityItems(t *testing.T) {
+	items := GuestIdentityItems([]string{api.InstanceIdentityHWAddr, api.InstanceIdentityHostname, api.InstanceIdentityHostname})
+	if len(items) != 1 || items[0] != api.InstanceIdentityHostname {
+		t.Errorf("Unexpected guest identity items %v", items)
+	}
+}
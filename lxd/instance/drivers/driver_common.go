@@ -71,6 +71,7 @@ type common struct {
 	expandedDevices deviceConfig.Devices
 	expiryDate      time.Time
 	id              int
+	labels          map[string]string
 	lastUsedDate    time.Time
 	localConfig     map[string]string
 	localDevices    deviceConfig.Devices
@@ -115,6 +116,11 @@ func (d *common) Description() string {
 	return d.description
 }
 
+// Labels returns the instance's labels.
+func (d *common) Labels() map[string]string {
+	return d.labels
+}
+
 // IsEphemeral returns whether the instanc is ephemeral or not.
 func (d *common) IsEphemeral() bool {
 	return d.ephemeral
@@ -377,6 +383,11 @@ func (d *common) ConsoleBufferLogPath() string {
 	return filepath.Join(d.LogPath(), "console.log")
 }
 
+// consoleHistoryPath returns the path of the instance's persistent console history.
+func (d *common) consoleHistoryPath() string {
+	return filepath.Join(d.LogPath(), "console.history")
+}
+
 // DevicesPath returns the instance's devices path.
 func (d *common) DevicesPath() string {
 	name := project.Instance(d.project.Name, d.name)
@@ -399,6 +410,11 @@ func (d *common) ExecOutputPath() string {
 	return filepath.Join(d.Path(), "exec-output")
 }
 
+// SessionsPath returns the path of the instance's session recordings.
+func (d *common) SessionsPath() string {
+	return filepath.Join(d.LogPath(), "sessions")
+}
+
 // RootfsPath returns the instance's rootfs path.
 func (d *common) RootfsPath() string {
 	return filepath.Join(d.Path(), "rootfs")
@@ -574,6 +590,7 @@ func (d *common) restartCommon(inst instance.Instance, timeout time.Duration) er
 			Description:  inst.Description(),
 			Devices:      inst.LocalDevices(),
 			Ephemeral:    false,
+			Labels:       inst.Labels(),
 			Profiles:     inst.Profiles(),
 			Project:      inst.Project().Name,
 			Type:         inst.Type(),
@@ -660,22 +677,39 @@ func (d *common) rebuildCommon(inst instance.Instance, img *api.Image, op *opera
 		return err
 	}
 
-	err = pool.DeleteInstance(inst, op)
+	snapshots, err := inst.Snapshots()
 	if err != nil {
 		return err
 	}
 
-	// Rebuild as empty if there is no image provided.
-	if img == nil {
-		err = pool.CreateInstance(inst, nil)
+	if len(snapshots) > 0 {
+		// The root volume can't be re-created without losing the snapshots, so replace its content instead.
+		if img == nil {
+			return errors.New("Instances with snapshots can only be rebuilt from an image")
+		}
+
+		err = pool.ReimageInstance(inst, img.Fingerprint, op)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = pool.CreateInstanceFromImage(inst, img.Fingerprint, op)
+		err = pool.DeleteInstance(inst, op)
 		if err != nil {
 			return err
 		}
+
+		// Rebuild as empty if there is no image provided.
+		if img == nil {
+			err = pool.CreateInstance(inst, nil)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = pool.CreateInstanceFromImage(inst, img.Fingerprint, op)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -854,6 +888,7 @@ func (d *common) restoreCommon(inst instance.Instance, source instance.Instance)
 				Description:  d.Description(),
 				Devices:      d.LocalDevices(),
 				Ephemeral:    false,
+				Labels:       d.Labels(),
 				Profiles:     d.Profiles(),
 				Project:      d.Project().Name,
 				Type:         d.Type(),
@@ -897,6 +932,7 @@ func (d *common) restoreCommon(inst instance.Instance, source instance.Instance)
 		Description:  source.Description(),
 		Devices:      source.LocalDevices(),
 		Ephemeral:    source.IsEphemeral(),
+		Labels:       d.Labels(), // Snapshots don't have labels, keep the current ones.
 		Profiles:     source.Profiles(),
 		Project:      source.Project().Name,
 		Type:         source.Type(),
@@ -1229,6 +1265,7 @@ func (d *common) onStopOperationSetup(target string) (*operationlock.InstanceOpe
 		}
 
 		op.SetInstanceInitiated(true)
+		op.SetUnrequested(true)
 	} else {
 		d.logger.Debug("Instance operation lock inherited for stop", logger.Ctx{"action": op.Action()})
 	}
@@ -1236,6 +1273,149 @@ func (d *common) onStopOperationSetup(target string) (*operationlock.InstanceOpe
 	return op, nil
 }
 
+// autoRestart restarts the instance in the background according to its boot.autorestart policy.
+// It must be called once the instance has stopped without being requested through LXD, with failure indicating
+// whether the instance stopped unexpectedly rather than being shut down from within.
+// The restart attempts wait for the stop operation to finish and are abandoned if the instance is started by
+// other means in the meantime.
+func (d *common) autoRestart(op *operationlock.InstanceOperation, failure bool) {
+	policy := d.expandedConfig["boot.autorestart"]
+	if d.ephemeral || policy == "" || policy == "no" || (policy == "on-failure" && !failure) {
+		return
+	}
+
+	maxRetries := 10
+	if d.expandedConfig["boot.autorestart.max_retries"] != "" {
+		maxRetries, _ = strconv.Atoi(d.expandedConfig["boot.autorestart.max_retries"])
+	}
+
+	backoff := 5 * time.Second
+	if d.expandedConfig["boot.autorestart.backoff"] != "" {
+		seconds, _ := strconv.Atoi(d.expandedConfig["boot.autorestart.backoff"])
+		backoff = time.Duration(seconds) * time.Second
+	}
+
+	count, _ := strconv.Atoi(d.localConfig["volatile.autorestart.count"])
+	if count >= maxRetries {
+		d.logger.Warn("Not restarting instance, maximum number of automatic restarts reached", logger.Ctx{"maxRetries": maxRetries})
+		return
+	}
+
+	projectName := d.project.Name
+	instanceName := d.name
+
+	go func() {
+		// Wait for the stop operation to finish.
+		_ = op.Wait(context.Background())
+
+		for count < maxRetries {
+			// Double the delay with each attempt, up to 5 minutes.
+			delay := min(backoff<<min(count, 6), 5*time.Minute)
+			time.Sleep(delay)
+
+			inst, err := instance.LoadByProjectAndName(d.state, projectName, instanceName)
+			if err != nil {
+				d.logger.Warn("Failed loading instance for automatic restart", logger.Ctx{"err": err})
+				return
+			}
+
+			// Give up if the instance was started (which resets the counter) or the policy changed meanwhile.
+			current, _ := strconv.Atoi(inst.LocalConfig()["volatile.autorestart.count"])
+			if inst.IsRunning() || current != count || inst.ExpandedConfig()["boot.autorestart"] != policy {
+				return
+			}
+
+			count++
+			err = inst.VolatileSet(map[string]string{"volatile.autorestart.count": strconv.Itoa(count)})
+			if err != nil {
+				d.logger.Warn("Failed recording automatic restart", logger.Ctx{"err": err})
+				return
+			}
+
+			d.logger.Info("Restarting instance automatically", logger.Ctx{"attempt": count, "maxRetries": maxRetries})
+
+			ctx := map[string]any{
+				"attempt":     count,
+				"max_retries": maxRetries,
+			}
+
+			err = inst.Start(false)
+			if err != nil {
+				d.logger.Warn("Failed restarting instance automatically", logger.Ctx{"attempt": count, "err": err})
+				ctx["error"] = err.Error()
+			}
+
+			d.state.Events.SendLifecycle(projectName, lifecycle.InstanceAutoRestarted.Event(inst, ctx))
+
+			if err == nil {
+				return
+			}
+		}
+	}()
+}
+
+// consoleHistorySize is the maximum size of the persistent console history of an instance.
+const consoleHistorySize = 1024 * 1024
+
+// consoleHistoryRotate moves the console output of the previous boot to the persistent console history.
+// It must be called before starting the instance. Only the most recent consoleHistorySize bytes are kept.
+func (d *common) consoleHistoryRotate() error {
+	current, err := os.ReadFile(d.ConsoleBufferLogPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed reading console log: %w", err)
+	}
+
+	if len(current) == 0 {
+		return nil
+	}
+
+	history, err := os.ReadFile(d.consoleHistoryPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed reading console history: %w", err)
+	}
+
+	history = append(history, current...)
+	if len(history) > consoleHistorySize {
+		history = history[len(history)-consoleHistorySize:]
+	}
+
+	err = os.WriteFile(d.consoleHistoryPath(), history, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed writing console history: %w", err)
+	}
+
+	err = os.Truncate(d.ConsoleBufferLogPath(), 0)
+	if err != nil {
+		return fmt.Errorf("Failed truncating console log: %w", err)
+	}
+
+	return nil
+}
+
+// consoleHistory returns the persistent console history followed by the console output of the current boot.
+func (d *common) consoleHistory(current []byte) ([]byte, error) {
+	history, err := os.ReadFile(d.consoleHistoryPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Failed reading console history: %w", err)
+	}
+
+	return append(history, current...), nil
+}
+
+// ConsoleHistoryClear removes the persistent console history of the instance.
+func (d *common) ConsoleHistoryClear() error {
+	err := os.Remove(d.consoleHistoryPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed removing console history: %w", err)
+	}
+
+	return nil
+}
+
 // warningsDelete deletes any persistent warnings for the instance.
 func (d *common) warningsDelete() error {
 	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -0,0 +1,72 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// hookTimeout is how long a host-side lifecycle hook can run for before being killed.
+const hookTimeout = 5 * time.Minute
+
+// hookLogFileName is the name of the instance log file the output of the host-side lifecycle hooks is written to.
+const hookLogFileName = "hooks.log"
+
+// runLifecycleHooks runs the host-side hooks listed in the given config key (hooks.pre_start or hooks.post_stop)
+// one after the other, appending their output to the hooks log of the instance. It stops at the first failing hook.
+func (d *common) runLifecycleHooks(key string, stage string) error {
+	names := shared.SplitNTrimSpace(d.expandedConfig[key], ",", -1, true)
+	if len(names) == 0 {
+		return nil
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(d.LogPath(), hookLogFileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed opening hooks log: %w", err)
+	}
+
+	defer func() { _ = logFile.Close() }()
+
+	env := append(os.Environ(),
+		"LXD_HOOK="+stage,
+		"LXD_INSTANCE_NAME="+d.name,
+		"LXD_INSTANCE_PROJECT="+d.project.Name,
+		"LXD_INSTANCE_TYPE="+d.dbType.String(),
+		"LXD_INSTANCE_PATH="+d.Path(),
+	)
+
+	for _, name := range names {
+		path, err := util.HookPath(name)
+		if err != nil {
+			_, _ = fmt.Fprintf(logFile, "%s %s hook %q: %v\n", time.Now().Format(time.RFC3339), stage, name, err)
+			return err
+		}
+
+		d.logger.Debug("Running hook", logger.Ctx{"stage": stage, "hook": name})
+		_, _ = fmt.Fprintf(logFile, "%s %s hook %q: Running\n", time.Now().Format(time.RFC3339), stage, name)
+
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		cmd := exec.CommandContext(ctx, path, stage)
+		cmd.Env = env
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+
+		err = cmd.Run()
+		cancel()
+		if err != nil {
+			_, _ = fmt.Fprintf(logFile, "%s %s hook %q: Failed: %v\n", time.Now().Format(time.RFC3339), stage, name, err)
+			return fmt.Errorf("Failed running %s hook %q: %w", stage, name, err)
+		}
+
+		_, _ = fmt.Fprintf(logFile, "%s %s hook %q: Succeeded\n", time.Now().Format(time.RFC3339), stage, name)
+	}
+
+	return nil
+}
@@ -1,13 +1,19 @@
 package drivers
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"math"
 	"net"
@@ -47,6 +53,7 @@ import (
 	"github.com/canonical/lxd/lxd/device/nictype"
 	"github.com/canonical/lxd/lxd/idmap"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/clone"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/instance/operationlock"
 	"github.com/canonical/lxd/lxd/instancewriter"
@@ -123,6 +130,7 @@ func lxcCreate(s *state.State, args db.InstanceArgs, p api.Project) (instance.In
 			ephemeral:    args.Ephemeral,
 			expiryDate:   args.ExpiryDate,
 			id:           args.ID,
+			labels:       args.Labels,
 			lastUsedDate: args.LastUsedDate,
 			localConfig:  args.Config,
 			localDevices: args.Devices,
@@ -328,6 +336,7 @@ func lxcInstantiate(s *state.State, args db.InstanceArgs, expandedDevices device
 			ephemeral:    args.Ephemeral,
 			expiryDate:   args.ExpiryDate,
 			id:           args.ID,
+			labels:       args.Labels,
 			lastUsedDate: args.LastUsedDate,
 			localConfig:  args.Config,
 			localDevices: args.Devices,
@@ -1912,6 +1921,12 @@ func (d *lxc) startCommon() (string, []func() error, error) {
 		}
 	}
 
+	// Keep the console output of the previous boot.
+	err = d.consoleHistoryRotate()
+	if err != nil {
+		d.logger.Warn("Failed rotating console history", logger.Ctx{"err": err})
+	}
+
 	// Wait for any file operations to complete.
 	// This is to avoid having an active mount by forkfile and so all file operations
 	// from this point will use the container's namespace rather than a chroot.
@@ -1985,6 +2000,12 @@ func (d *lxc) startCommon() (string, []func() error, error) {
 		return "", nil, err
 	}
 
+	// Run the host-side pre-start hooks.
+	err = d.runLifecycleHooks("hooks.pre_start", "pre-start")
+	if err != nil {
+		return "", nil, err
+	}
+
 	volatileSet := make(map[string]string)
 
 	// Generate UUID if not present (do this before UpdateBackupFile() call).
@@ -2327,7 +2348,18 @@ func (d *lxc) Start(stateful bool) error {
 	}
 
 	if stateful {
-		return api.StatusErrorf(http.StatusBadRequest, "Stateful restore is not supported for containers")
+		if !d.stateful {
+			err = api.StatusErrorf(http.StatusBadRequest, "The instance has no checkpoint to restore")
+			op.Done(err)
+			return err
+		}
+
+		_, err = exec.LookPath("criu")
+		if err != nil {
+			err = api.StatusErrorf(http.StatusBadRequest, "Restoring checkpoints requires CRIU to be installed on the host")
+			op.Done(err)
+			return err
+		}
 	} else if d.stateful {
 		// Clear any left over state when doing stateless start.
 		err := os.RemoveAll(d.StatePath())
@@ -2355,14 +2387,28 @@ func (d *lxc) Start(stateful bool) error {
 
 	name := project.Instance(d.Project().Name, d.name)
 
-	// Start the LXC container
-	_, err = shared.RunCommandContext(
-		context.TODO(),
-		d.state.OS.ExecPath,
-		"forkstart",
-		name,
-		d.state.OS.LxcPath,
-		configPath)
+	if stateful {
+		// Restore the LXC container from its checkpoint.
+		_, err = shared.RunCommandContext(
+			context.TODO(),
+			d.state.OS.ExecPath,
+			"forkmigrate",
+			name,
+			d.state.OS.LxcPath,
+			configPath,
+			d.StatePath(),
+			"false")
+	} else {
+		// Start the LXC container
+		_, err = shared.RunCommandContext(
+			context.TODO(),
+			d.state.OS.ExecPath,
+			"forkstart",
+			name,
+			d.state.OS.LxcPath,
+			configPath)
+	}
+
 	if err != nil && !d.IsRunning() {
 		// Attempt to extract the LXC errors
 		lxcLog := ""
@@ -2398,6 +2444,14 @@ func (d *lxc) Start(stateful bool) error {
 		return err
 	}
 
+	// The checkpoint has been consumed.
+	if stateful {
+		err = d.CheckpointDelete()
+		if err != nil {
+			d.logger.Warn("Failed removing restored checkpoint", logger.Ctx{"err": err})
+		}
+	}
+
 	// Run any post start hooks.
 	err = d.runHooks(postStartHooks)
 	if err != nil {
@@ -2464,6 +2518,25 @@ func (d *lxc) onStart(_ map[string]string) error {
 		}
 	}
 
+	// Regenerate the identity of a copied instance.
+	key = "volatile.regenerate_identity"
+	if d.localConfig[key] != "" {
+		err = d.regenerateIdentity(strings.Split(d.localConfig[key], ","))
+		if err != nil {
+			_ = apparmor.InstanceUnload(d.state.OS, d)
+			return err
+		}
+
+		err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Remove the volatile key from the DB
+			return tx.DeleteInstanceConfigKey(ctx, int64(d.id), key)
+		})
+		if err != nil {
+			_ = apparmor.InstanceUnload(d.state.OS, d)
+			return err
+		}
+	}
+
 	err = d.templateApplyNow("start")
 	if err != nil {
 		_ = apparmor.InstanceUnload(d.state.OS, d)
@@ -2586,6 +2659,11 @@ func (d *lxc) validateStartup(statusCode api.StatusCode) error {
 		return errors.New("Instance is protected from being started")
 	}
 
+	// Check if instance is a standby replica.
+	if shared.IsTrue(d.localConfig["volatile.replication.standby"]) {
+		return errors.New("Instance is a standby replica and must be promoted before being started")
+	}
+
 	if shared.IsTrue(d.expandedConfig["security.delegate_bpf"]) && !d.state.OS.BPFToken {
 		return errors.New("BPF Token mechanism is not supported by your kernel. Linux kernel 6.9+ is required to start this instance, or security.delegate_bpf option must be disabled")
 	}
@@ -2970,6 +3048,12 @@ func (d *lxc) onStop(args map[string]string) error {
 			return
 		}
 
+		// Run the host-side post-stop hooks.
+		err = d.runLifecycleHooks("hooks.post_stop", "post-stop")
+		if err != nil {
+			d.logger.Error("Failed running post-stop hooks", logger.Ctx{"err": err})
+		}
+
 		// Log and emit lifecycle if not user triggered
 		if op.GetInstanceInitiated() {
 			ctxMap := logger.Ctx{
@@ -3009,6 +3093,12 @@ func (d *lxc) onStop(args map[string]string) error {
 
 		// Trigger a scheduler rebalance after DB changes made.
 		cgroup.TaskSchedulerTrigger(d.dbType, d.name, "stopped")
+
+		// Restart the container if it stopped on its own. LXC doesn't report why the container stopped so
+		// this is always considered a failure.
+		if op.GetUnrequested() {
+			d.autoRestart(op, true)
+		}
 	}(d, target, op)
 
 	return nil
@@ -3217,11 +3307,12 @@ func (d *lxc) Render(options ...func(response any) error) (state any, etag any,
 	}
 
 	// Prepare the ETag
-	etag = []any{d.architecture, d.localConfig, d.localDevices, d.ephemeral, d.profiles}
+	etag = []any{d.architecture, d.localConfig, d.localDevices, d.ephemeral, d.profiles, d.labels}
 
 	instState := api.Instance{
 		Name:            d.name,
 		Description:     d.description,
+		Labels:          d.labels,
 		Architecture:    architectureName,
 		Profiles:        profileNames,
 		Config:          d.localConfig,
@@ -3333,6 +3424,7 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 	}
 
 	status.Disk = d.diskState()
+	status.AutoRestarts, _ = strconv.ParseInt(d.localConfig["volatile.autorestart.count"], 10, 64)
 
 	d.release()
 
@@ -3344,6 +3436,326 @@ func (d *lxc) RenderState(hostInterfaces []net.Interface) (*api.InstanceState, e
 	return d.renderState(d.statusCode(), hostInterfaces)
 }
 
+// Checkpoint saves the state of the running container using CRIU so that it can be exported and restored later
+// with a stateful start. The container is stopped once its state is saved if stop is true.
+func (d *lxc) Checkpoint(stop bool) error {
+	d.logger.Debug("Checkpoint started", logger.Ctx{"stop": stop})
+	defer d.logger.Debug("Checkpoint finished", logger.Ctx{"stop": stop})
+
+	if !d.IsRunning() {
+		return api.StatusErrorf(http.StatusBadRequest, "The instance isn't running")
+	}
+
+	if stop && d.ephemeral {
+		return api.StatusErrorf(http.StatusBadRequest, "Ephemeral instances can't be stopped after a checkpoint")
+	}
+
+	_, err := exec.LookPath("criu")
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Checkpoints require CRIU to be installed on the host")
+	}
+
+	// When stopping, the stop operation is picked up by the onStop hook.
+	var op *operationlock.InstanceOperation
+	if stop {
+		op, err = operationlock.Create(d.Project().Name, d.Name(), operationlock.ActionStop, false, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	cc, err := d.initLXC(false)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	// Replace any previous checkpoint.
+	err = os.RemoveAll(d.StatePath())
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	err = os.MkdirAll(d.StatePath(), 0700)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	err = cc.Migrate(liblxc.MIGRATE_DUMP, liblxc.MigrateOptions{
+		Directory: d.StatePath(),
+		Stop:      stop,
+		Verbose:   true,
+	})
+	if err != nil {
+		_ = os.RemoveAll(d.StatePath())
+		err = fmt.Errorf("Failed creating checkpoint (see %q for details): %w", filepath.Join(d.StatePath(), "dump.log"), err)
+		op.Done(err)
+		return err
+	}
+
+	err = d.setStateful(true)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	if stop {
+		// Wait for the onStop hook to clean up.
+		err = op.Wait(context.Background())
+		if err != nil {
+			return err
+		}
+
+		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStopped.Event(d, nil))
+	}
+
+	return nil
+}
+
+// setStateful records whether the instance has saved state to restore.
+func (d *lxc) setStateful(stateful bool) error {
+	if d.stateful == stateful {
+		return nil
+	}
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateInstanceStatefulFlag(ctx, d.id, stateful)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed updating instance stateful flag: %w", err)
+	}
+
+	d.stateful = stateful
+
+	return nil
+}
+
+// checkpointSignatureName is the name of the last file of the checkpoint tarballs, holding their signature.
+const checkpointSignatureName = "lxd-checkpoint.sig"
+
+// checkpointMAC returns the MAC signing the checkpoints exported by the server. It is keyed with the private key of
+// the server certificate, so that only the checkpoints exported by the server or its cluster can be imported.
+func checkpointMAC(key []byte) hash.Hash {
+	return hmac.New(sha256.New, key)
+}
+
+// CheckpointExport writes the checkpoint of the container to w as a signed compressed tarball.
+func (d *lxc) CheckpointExport(w io.Writer) error {
+	if !d.stateful {
+		return api.StatusErrorf(http.StatusNotFound, "The instance has no checkpoint")
+	}
+
+	_, err := d.mount()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = d.unmount() }()
+
+	return checkpointWriteTarball(w, checkpointMAC(d.state.ServerCert().PrivateKey()), d.StatePath())
+}
+
+// checkpointWriteTarball writes the checkpoint files of the directory to w as a compressed tarball, signed with mac.
+func checkpointWriteTarball(w io.Writer, mac hash.Hash, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("Failed listing checkpoint files: %w", err)
+	}
+
+	gzWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	for _, entry := range entries {
+		// CRIU images are regular files in a flat directory.
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		err := checkpointExportFile(tarWriter, mac, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	signature := []byte(hex.EncodeToString(mac.Sum(nil)))
+	err = tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: checkpointSignatureName, Mode: 0600, Size: int64(len(signature))})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(signature)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzWriter.Close()
+}
+
+// checkpointExportFile adds a checkpoint file to the tarball and to its signature.
+func checkpointExportFile(tarWriter *tar.Writer, mac hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Failed opening checkpoint file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.WriteHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(mac, "%s\x00%d\x00", hdr.Name, hdr.Size)
+
+	_, err = io.Copy(io.MultiWriter(tarWriter, mac), f)
+	if err != nil {
+		return fmt.Errorf("Failed exporting checkpoint file %q: %w", info.Name(), err)
+	}
+
+	return nil
+}
+
+// CheckpointImport replaces the checkpoint of the stopped container with the one read from r (a compressed
+// tarball as produced by CheckpointExport). The container can then be restored with a stateful start.
+// Tarballs without a valid signature of the server are rejected, as their files are restored by CRIU as root.
+func (d *lxc) CheckpointImport(r io.Reader) error {
+	if d.IsRunning() {
+		return api.StatusErrorf(http.StatusBadRequest, "The instance must be stopped to import a checkpoint")
+	}
+
+	_, err := d.mount()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = d.unmount() }()
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = os.RemoveAll(d.StatePath())
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(d.StatePath(), 0700)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = os.RemoveAll(d.StatePath()) })
+
+	// The imported files are removed by the reverter if the checkpoint wasn't exported by this server or cluster.
+	err = checkpointReadTarball(r, checkpointMAC(d.state.ServerCert().PrivateKey()), d.StatePath())
+	if err != nil {
+		return err
+	}
+
+	err = d.setStateful(true)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+
+	return nil
+}
+
+// checkpointReadTarball extracts the checkpoint files of the compressed tarball read from r into the directory, and
+// checks that the tarball was signed with mac. The extracted files must be removed if an error is returned.
+func checkpointReadTarball(r io.Reader, mac hash.Hash, dir string) error {
+	gzReader, err := gzip.NewReader(r)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid checkpoint: %v", err)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+	var signature []byte
+
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid checkpoint: %v", err)
+		}
+
+		if signature != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid checkpoint: signature must be the last file")
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !shared.IsFileName(hdr.Name) {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid checkpoint file %q", hdr.Name)
+		}
+
+		if hdr.Name == checkpointSignatureName {
+			signature, err = io.ReadAll(io.LimitReader(tarReader, 1024))
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "Invalid checkpoint: %v", err)
+			}
+
+			continue
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, hdr.Name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed creating checkpoint file %q: %w", hdr.Name, err)
+		}
+
+		_, _ = fmt.Fprintf(mac, "%s\x00%d\x00", hdr.Name, hdr.Size)
+
+		_, err = io.Copy(io.MultiWriter(f, mac), tarReader)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("Failed importing checkpoint file %q: %w", hdr.Name, err)
+		}
+	}
+
+	if !hmac.Equal(signature, []byte(hex.EncodeToString(mac.Sum(nil)))) {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid checkpoint signature, only checkpoints exported by this server can be imported")
+	}
+
+	return nil
+}
+
+// CheckpointDelete removes the checkpoint of the container.
+func (d *lxc) CheckpointDelete() error {
+	if !d.IsRunning() {
+		_, err := d.mount()
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = d.unmount() }()
+	}
+
+	err := os.RemoveAll(d.StatePath())
+	if err != nil {
+		return fmt.Errorf("Failed removing checkpoint: %w", err)
+	}
+
+	return d.setStateful(false)
+}
+
 // snapshot creates a snapshot of the instance.
 func (d *lxc) snapshot(name string, expiry *time.Time) error {
 	// Wait for any file operations to complete to have a more consistent snapshot.
@@ -3491,7 +3903,7 @@ func (d *lxc) delete(force bool) error {
 		d.logger.Info("Deleting instance", ctxMap)
 	}
 
-	if !force && shared.IsTrue(d.expandedConfig["security.protection.delete"]) && !d.IsSnapshot() {
+	if !force && project.DeleteProtectedFromRecord(&d.project, d.expandedConfig) && !d.IsSnapshot() {
 		err := errors.New("Instance is protected from being deleted")
 		d.logger.Warn("Failed to delete instance", logger.Ctx{"err": err})
 		return err
@@ -3888,8 +4300,15 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 		}
 	}
 
+	// Validate the new labels.
+	err = instance.ValidLabels(args.Labels)
+	if err != nil {
+		return err
+	}
+
 	// Get a copy of the old configuration
 	oldDescription := d.Description()
+	oldLabels := d.labels
 	oldArchitecture := 0
 	err = shared.DeepCopy(&d.architecture, &oldArchitecture)
 	if err != nil {
@@ -3942,6 +4361,7 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 	defer func() {
 		if undoChanges {
 			d.description = oldDescription
+			d.labels = oldLabels
 			d.architecture = oldArchitecture
 			d.ephemeral = oldEphemeral
 			d.expandedConfig = oldExpandedConfig
@@ -3958,6 +4378,7 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 
 	// Apply the various changes
 	d.description = args.Description
+	d.labels = args.Labels
 	d.architecture = args.Architecture
 	d.ephemeral = args.Ephemeral
 	d.localConfig = args.Config
@@ -4535,6 +4956,11 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 			return err
 		}
 
+		err = cluster.UpdateInstanceLabels(ctx, tx.Tx(), int64(object.ID), d.labels)
+		if err != nil {
+			return err
+		}
+
 		devices, err := cluster.APIToDevices(d.localDevices.CloneNative())
 		if err != nil {
 			return err
@@ -5556,6 +5982,26 @@ func (d *lxc) ConversionReceive(args instance.ConversionReceiveArgs) error {
 	return nil
 }
 
+// regenerateIdentity regenerates the requested identity items in the container's root filesystem.
+func (d *lxc) regenerateIdentity(items []string) error {
+	idmapset, err := d.DiskIdmap()
+	if err != nil {
+		return fmt.Errorf("Failed to set ID map: %w", err)
+	}
+
+	rootUID := int64(0)
+	rootGID := int64(0)
+
+	// Get the right uid and gid for the container
+	if idmapset != nil {
+		rootUID, rootGID = idmapset.ShiftIntoNs(0, 0)
+	}
+
+	d.logger.Info("Regenerating instance identity", logger.Ctx{"items": items})
+
+	return clone.RegenerateIdentity(d.RootfsPath(), d.name, items, int(rootUID), int(rootGID))
+}
+
 func (d *lxc) templateApplyNow(trigger instance.TemplateTrigger) error {
 	// If there's no metadata, just return
 	fname := filepath.Join(d.Path(), "metadata.yaml")
@@ -6110,6 +6556,30 @@ func (d *lxc) ConsoleLog(opts liblxc.ConsoleLogOptions) (string, error) {
 	return string(msg), nil
 }
 
+// ConsoleHistory returns the persistent console history of the container followed by the console output of
+// the current (or last) boot.
+func (d *lxc) ConsoleHistory() ([]byte, error) {
+	if !d.IsRunning() {
+		current, err := os.ReadFile(d.ConsoleBufferLogPath())
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("Failed reading console log: %w", err)
+		}
+
+		return d.consoleHistory(current)
+	}
+
+	// Query the container's console ringbuffer.
+	current, err := d.ConsoleLog(liblxc.ConsoleLogOptions{ReadLog: true})
+	if err != nil {
+		errno, isErrno := shared.GetErrno(err)
+		if !isErrno || errno != unix.ENODATA {
+			return nil, err
+		}
+	}
+
+	return d.consoleHistory([]byte(current))
+}
+
 // Exec executes a command inside the instance.
 func (d *lxc) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	// Generate the LXC config if missing.
@@ -0,0 +1,163 @@
+package drivers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// checkpointTestTarball returns a checkpoint tarball of the given files, in order.
+func checkpointTestTarball(t *testing.T, files [][2]string) *bytes.Buffer {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	for _, file := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: file[0], Mode: 0600, Size: int64(len(file[1]))})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tarWriter.Write([]byte(file[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gzWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf
+}
+
+// checkpointTestFiles returns the files of a checkpoint tarball, in order.
+func checkpointTestFiles(t *testing.T, tarball []byte) [][2]string {
+	t.Helper()
+
+	gzReader, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files [][2]string
+	tarReader := tar.NewReader(gzReader)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, [2]string{hdr.Name, string(content)})
+	}
+
+	return files
+}
+
+func TestCheckpointTarball(t *testing.T) {
+	key := []byte("server key")
+
+	// Export a checkpoint.
+	sourceDir := t.TempDir()
+	for name, content := range map[string]string{"inventory.img": "inventory", "pages-1.img": "pages"} {
+		err := os.WriteFile(filepath.Join(sourceDir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := os.Mkdir(filepath.Join(sourceDir, "subdir"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported := &bytes.Buffer{}
+	err = checkpointWriteTarball(exported, checkpointMAC(key), sourceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the regular files are exported, followed by the signature.
+	files := checkpointTestFiles(t, exported.Bytes())
+	if len(files) != 3 || files[2][0] != checkpointSignatureName {
+		t.Fatalf("Unexpected checkpoint files %v", files)
+	}
+
+	t.Run("Checkpoint exported with the same key", func(t *testing.T) {
+		dir := t.TempDir()
+		err := checkpointReadTarball(bytes.NewReader(exported.Bytes()), checkpointMAC(key), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "pages-1.img"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != "pages" {
+			t.Errorf("Unexpected checkpoint file content %q", content)
+		}
+
+		_, err = os.Stat(filepath.Join(dir, checkpointSignatureName))
+		if !os.IsNotExist(err) {
+			t.Error("The signature was extracted")
+		}
+	})
+
+	tampered := make([][2]string, len(files))
+	copy(tampered, files)
+	tampered[1] = [2]string{tampered[1][0], "tampered"}
+
+	renamed := make([][2]string, len(files))
+	copy(renamed, files)
+	renamed[1] = [2]string{"pages-2.img", renamed[1][1]}
+
+	tests := []struct {
+		name    string
+		tarball *bytes.Buffer
+		key     []byte
+	}{
+		{name: "Checkpoint of another server", tarball: bytes.NewBuffer(exported.Bytes()), key: []byte("other server key")},
+		{name: "Tampered file content", tarball: checkpointTestTarball(t, tampered), key: key},
+		{name: "Renamed file", tarball: checkpointTestTarball(t, renamed), key: key},
+		{name: "Missing file", tarball: checkpointTestTarball(t, files[1:]), key: key},
+		{name: "Missing signature", tarball: checkpointTestTarball(t, files[:2]), key: key},
+		{name: "Signature not last", tarball: checkpointTestTarball(t, append(files[:3:3], [2]string{"extra.img", "extra"})), key: key},
+		{name: "File outside of the checkpoint", tarball: checkpointTestTarball(t, [][2]string{{"../inventory.img", "inventory"}, files[2]}), key: key},
+		{name: "Not a tarball", tarball: bytes.NewBufferString("checkpoint"), key: key},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkpointReadTarball(tt.tarball, checkpointMAC(tt.key), t.TempDir())
+			if !api.StatusErrorCheck(err, http.StatusBadRequest) {
+				t.Errorf("Expected the checkpoint to be rejected, got %v", err)
+			}
+		})
+	}
+}
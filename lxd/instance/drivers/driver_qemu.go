@@ -3,16 +3,17 @@ package drivers
 import (
 	"bufio"
 	"bytes"
-	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
@@ -37,7 +38,7 @@ import (
 	"golang.org/x/sys/unix"
 	"google.golang.org/protobuf/proto"
 
-	"github.com/canonical/lxd/client"
+	lxd "github.com/canonical/lxd/client"
 	agentAPI "github.com/canonical/lxd/lxd-agent/api"
 	"github.com/canonical/lxd/lxd/apparmor"
 	"github.com/canonical/lxd/lxd/backup/config"
@@ -50,8 +51,10 @@ import (
 	"github.com/canonical/lxd/lxd/device/filters"
 	"github.com/canonical/lxd/lxd/device/nictype"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/clone"
 	"github.com/canonical/lxd/lxd/instance/drivers/edk2"
 	"github.com/canonical/lxd/lxd/instance/drivers/qmp"
+	"github.com/canonical/lxd/lxd/instance/drivers/statestore"
 	"github.com/canonical/lxd/lxd/instance/drivers/uefi"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/instance/operationlock"
@@ -149,6 +152,7 @@ func qemuInstantiate(s *state.State, args db.InstanceArgs, expandedDevices devic
 			ephemeral:    args.Ephemeral,
 			expiryDate:   args.ExpiryDate,
 			id:           args.ID,
+			labels:       args.Labels,
 			lastUsedDate: args.LastUsedDate,
 			localConfig:  args.Config,
 			localDevices: args.Devices,
@@ -207,6 +211,7 @@ func qemuCreate(s *state.State, args db.InstanceArgs, p api.Project) (instance.I
 			ephemeral:    args.Ephemeral,
 			expiryDate:   args.ExpiryDate,
 			id:           args.ID,
+			labels:       args.Labels,
 			lastUsedDate: args.LastUsedDate,
 			localConfig:  args.Config,
 			localDevices: args.Devices,
@@ -381,7 +386,7 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 	state := d.state
 
 	return func(event string, data map[string]any) {
-		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventAgentStarted}, event) {
+		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventAgentStarted, qmp.EventVMWatchdog}, event) {
 			return // Don't bother loading the instance from DB if we aren't going to handle the event.
 		}
 
@@ -420,6 +425,10 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				return
 			}
 
+		case qmp.EventVMWatchdog:
+			d.logger.Warn("Instance watchdog expired", logger.Ctx{"action": data["action"]})
+			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceWatchdog.Event(d, map[string]any{"action": data["action"]}))
+
 		case qmp.EventVMShutdown:
 			target := "stop"
 			entry, ok := data["reason"]
@@ -433,7 +442,8 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				d.logger.Debug("Instance stopped", logger.Ctx{"target": target, "reason": data["reason"]})
 			}
 
-			err = d.onStop(target)
+			reason, _ := entry.(string)
+			err = d.onStop(target, reason)
 			if err != nil {
 				d.logger.Error("Failed to cleanly stop instance", logger.Ctx{"err": err})
 				return
@@ -583,7 +593,8 @@ func (d *qemu) pidWait(timeout time.Duration) bool {
 }
 
 // onStop is run when the instance stops.
-func (d *qemu) onStop(target string) error {
+// The reason is the one reported by QEMU (if any).
+func (d *qemu) onStop(target string, reason string) error {
 	d.logger.Debug("onStop hook started", logger.Ctx{"target": target})
 	defer d.logger.Debug("onStop hook finished", logger.Ctx{"target": target})
 
@@ -619,6 +630,7 @@ func (d *qemu) onStop(target string) error {
 
 	// Cleanup.
 	d.cleanupDevices() // Must be called before unmount.
+	d.memoryBalloonForget()
 	_ = os.Remove(d.pidFilePath())
 	_ = os.Remove(d.monitorPath())
 
@@ -637,6 +649,12 @@ func (d *qemu) onStop(target string) error {
 		return err
 	}
 
+	// Run the host-side post-stop hooks.
+	err = d.runLifecycleHooks("hooks.post_stop", "post-stop")
+	if err != nil {
+		d.logger.Error("Failed running post-stop hooks", logger.Ctx{"err": err})
+	}
+
 	// Log and emit lifecycle if not user triggered.
 	if op.GetInstanceInitiated() {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceShutdown.Event(d, nil))
@@ -660,6 +678,9 @@ func (d *qemu) onStop(target string) error {
 			op.Done(err)
 			return err
 		}
+	} else if op.GetUnrequested() {
+		// Restart the instance if it stopped on its own, a shutdown from within the guest isn't a failure.
+		d.autoRestart(op, reason != "guest-shutdown")
 	}
 
 	return nil
@@ -875,24 +896,23 @@ func (d *qemu) restoreState(monitor *qmp.Monitor) error {
 			defer func() { _ = filesystemConn.Close() }()
 		}
 
+		// Allow the source to switch the migration to post-copy mode (if supported by the host).
+		err := monitor.MigrateSetCapabilities(map[string]bool{"postcopy-ram": true})
+		if err != nil {
+			d.logger.Debug("Post-copy migration not available", logger.Ctx{"err": err})
+		}
+
 		// Receive checkpoint from QEMU process on source.
+		// A socket is used rather than a pipe to provide the return path needed by post-copy migration.
 		d.logger.Debug("Stateful migration checkpoint receive starting")
-		pipeRead, pipeWrite, err := os.Pipe()
+		socket, cleanup, err := d.migrationStateSocket(stateConn)
 		if err != nil {
 			return err
 		}
 
-		go func() {
-			_, err := io.Copy(pipeWrite, stateConn)
-			if err != nil {
-				d.logger.Warn("Failed reading from state connection", logger.Ctx{"err": err})
-			}
-
-			_ = pipeRead.Close()
-			_ = pipeWrite.Close()
-		}()
+		defer cleanup()
 
-		err = d.restoreStateHandle(context.Background(), monitor, pipeRead)
+		err = d.restoreStateHandle(context.Background(), monitor, socket)
 		if err != nil {
 			return fmt.Errorf("Failed restoring checkpoint from source: %w", err)
 		}
@@ -900,23 +920,36 @@ func (d *qemu) restoreState(monitor *qmp.Monitor) error {
 		d.logger.Debug("Stateful migration checkpoint receive finished")
 	} else {
 		statePath := d.StatePath()
-		d.logger.Debug("Stateful checkpoint restore starting", logger.Ctx{"source": statePath})
-		defer d.logger.Debug("Stateful checkpoint restore finished", logger.Ctx{"source": statePath})
 
-		stateFile, err := os.Open(statePath)
-		if err != nil {
-			return fmt.Errorf("Failed opening state file %q: %w", statePath, err)
-		}
+		var uncompressedState io.ReadCloser
+		if !shared.PathExists(statePath) && shared.PathExists(d.stateManifestPath()) {
+			// Restore the state of an incremental stateful snapshot.
+			statePath = d.stateManifestPath()
+
+			var err error
+			uncompressedState, err = statestore.Open(d.stateChunksPath(), statePath)
+			if err != nil {
+				return fmt.Errorf("Failed opening state %q: %w", statePath, err)
+			}
+		} else {
+			stateFile, err := os.Open(statePath)
+			if err != nil {
+				return fmt.Errorf("Failed opening state file %q: %w", statePath, err)
+			}
 
-		defer func() { _ = stateFile.Close() }()
+			defer func() { _ = stateFile.Close() }()
 
-		uncompressedState, err := gzip.NewReader(stateFile)
-		if err != nil {
-			return fmt.Errorf("Failed opening state gzip reader: %w", err)
+			uncompressedState, err = stateDecompressionReader(stateFile)
+			if err != nil {
+				return fmt.Errorf("Failed opening state decompression reader: %w", err)
+			}
 		}
 
 		defer func() { _ = uncompressedState.Close() }()
 
+		d.logger.Debug("Stateful checkpoint restore starting", logger.Ctx{"source": statePath})
+		defer d.logger.Debug("Stateful checkpoint restore finished", logger.Ctx{"source": statePath})
+
 		pipeRead, pipeWrite, err := os.Pipe()
 		if err != nil {
 			return err
@@ -934,13 +967,85 @@ func (d *qemu) restoreState(monitor *qmp.Monitor) error {
 
 		err = d.restoreStateHandle(context.Background(), monitor, pipeRead)
 		if err != nil {
-			return fmt.Errorf("Failed restoring state from %q: %w", stateFile.Name(), err)
+			return fmt.Errorf("Failed restoring state from %q: %w", statePath, err)
 		}
 	}
 
 	return nil
 }
 
+// migrationStateSocket returns one end of a socket pair to be handed to QEMU, with the other end being connected
+// to the migration state connection in both directions. Unlike a pipe, this allows QEMU to use a migration return
+// path. The returned cleanup function must be called once the state transfer has completed.
+func (d *qemu) migrationStateSocket(stateConn io.ReadWriteCloser) (*os.File, func(), error) {
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed creating migration state socket pair: %w", err)
+	}
+
+	remote := os.NewFile(uintptr(fds[1]), "migration-state")
+	localFile := os.NewFile(uintptr(fds[0]), "migration-state-local")
+
+	localConn, err := net.FileConn(localFile)
+	_ = localFile.Close()
+	if err != nil {
+		_ = remote.Close()
+		return nil, nil, fmt.Errorf("Failed setting up migration state socket: %w", err)
+	}
+
+	local, ok := localConn.(*net.UnixConn)
+	if !ok {
+		_ = localConn.Close()
+		_ = remote.Close()
+		return nil, nil, errors.New("Unexpected migration state socket type")
+	}
+
+	go func() {
+		_, err := io.Copy(local, stateConn)
+		if err != nil {
+			d.logger.Warn("Failed reading from state connection", logger.Ctx{"err": err})
+		}
+
+		// Signal the end of the migration stream to QEMU whilst keeping the return path open.
+		_ = local.CloseWrite()
+	}()
+
+	go func() { _, _ = io.Copy(stateConn, local) }()
+
+	cleanup := func() {
+		_ = local.Close()
+		_ = remote.Close()
+	}
+
+	return remote, cleanup, nil
+}
+
+// updateMigrationProgress reports the live migration state transfer statistics in the operation metadata.
+func (d *qemu) updateMigrationProgress(info *qmp.MigrationInfo) {
+	if d.op == nil {
+		return
+	}
+
+	meta := d.op.Metadata()
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+
+	meta["migration_state"] = map[string]any{
+		"status":            info.Status,
+		"transferred":       info.RAM.Transferred,
+		"remaining":         info.RAM.Remaining,
+		"total":             info.RAM.Total,
+		"dirty_pages_rate":  info.RAM.DirtyPagesRate,
+		"mbps":              info.RAM.Mbps,
+		"expected_downtime": info.ExpectedDowntime,
+	}
+
+	meta["container_progress"] = fmt.Sprintf("Memory: %s / %s (%s/s)", units.GetByteSizeString(info.RAM.Transferred, 2), units.GetByteSizeString(info.RAM.Total, 2), units.GetByteSizeString(int64(info.RAM.Mbps*1000*1000/8), 2))
+
+	_ = d.op.UpdateMetadata(meta)
+}
+
 // saveStateHandle dumps the current VM state to a file handle.
 // Once started, the VM is in a paused state and it's up to the caller to wait for the transfer to complete and
 // resume or kill the VM guest.
@@ -978,12 +1083,18 @@ func (d *qemu) saveState(monitor *qmp.Monitor) error {
 
 	defer func() { _ = stateFile.Close() }()
 
-	compressedState, err := gzip.NewWriterLevel(stateFile, gzip.BestSpeed)
+	written := &stateWriteCounter{w: stateFile}
+	compressedState, err := d.stateCompressionWriter(written)
 	if err != nil {
 		return err
 	}
 
-	defer func() { _ = compressedState.Close() }()
+	compressedStateClosed := false
+	defer func() {
+		if !compressedStateClosed {
+			_ = compressedState.Close()
+		}
+	}()
 
 	pipeRead, pipeWrite, err := os.Pipe()
 	if err != nil {
@@ -995,18 +1106,131 @@ func (d *qemu) saveState(monitor *qmp.Monitor) error {
 		_ = pipeWrite.Close()
 	}()
 
-	go func() { _, _ = io.Copy(compressedState, pipeRead) }()
+	copyErr := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(compressedState, pipeRead)
+		if err != nil {
+			// Unblock QEMU.
+			_, _ = io.Copy(io.Discard, pipeRead)
+		}
+
+		copyErr <- err
+	}()
 
 	err = d.saveStateHandle(monitor, pipeWrite)
 	if err != nil {
 		return fmt.Errorf("Failed initializing state save to %q: %w", stateFile.Name(), err)
 	}
 
-	err = monitor.MigrateWait("completed")
+	err = monitor.MigrateWaitProgress("completed", func(info *qmp.MigrationInfo) error {
+		d.updateStateSaveProgress(info, written.written.Load())
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("Failed saving state to %q: %w", stateFile.Name(), err)
 	}
 
+	// Signal the end of the state to the compression and wait for all of it to be written.
+	_ = pipeWrite.Close()
+
+	err = <-copyErr
+	if err != nil {
+		return fmt.Errorf("Failed writing state to %q: %w", stateFile.Name(), err)
+	}
+
+	compressedStateClosed = true
+	err = compressedState.Close()
+	if err != nil {
+		return fmt.Errorf("Failed writing state to %q: %w", stateFile.Name(), err)
+	}
+
+	d.logger.Debug("Stateful checkpoint written", logger.Ctx{"target": statePath, "compression": d.expandedConfig["migration.stateful.compression"], "size": written.written.Load()})
+
+	return nil
+}
+
+// stateChunksPath returns the path of the chunk store used by incremental stateful snapshots.
+func (d *qemu) stateChunksPath() string {
+	return filepath.Join(d.Path(), "state-chunks")
+}
+
+// stateManifestPath returns the path of the list of chunks making up the state of an incremental stateful snapshot.
+func (d *qemu) stateManifestPath() string {
+	return filepath.Join(d.Path(), "state.chunks")
+}
+
+// saveStateIncremental dumps the current VM state to the chunk store, only writing the parts of the memory that
+// changed since the previous incremental stateful snapshot.
+// Once dumped, the VM is in a paused state and it's up to the caller to resume or kill it.
+func (d *qemu) saveStateIncremental(monitor *qmp.Monitor) error {
+	manifestPath := d.stateManifestPath()
+	d.logger.Debug("Incremental stateful checkpoint starting", logger.Ctx{"target": manifestPath})
+
+	_ = os.Remove(d.StatePath())
+	_ = os.Remove(manifestPath)
+
+	pipeRead, pipeWrite, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = pipeRead.Close()
+		_ = pipeWrite.Close()
+	}()
+
+	type writeResult struct {
+		stats *statestore.Stats
+		err   error
+	}
+
+	result := make(chan writeResult, 1)
+	go func() {
+		stats, err := statestore.Write(pipeRead, d.stateChunksPath(), manifestPath)
+		if err != nil {
+			// Unblock QEMU.
+			_, _ = io.Copy(io.Discard, pipeRead)
+		}
+
+		result <- writeResult{stats: stats, err: err}
+	}()
+
+	err = d.saveStateHandle(monitor, pipeWrite)
+	if err != nil {
+		return fmt.Errorf("Failed initializing state save to %q: %w", manifestPath, err)
+	}
+
+	err = monitor.MigrateWait("completed")
+	if err != nil {
+		return fmt.Errorf("Failed saving state to %q: %w", manifestPath, err)
+	}
+
+	// Signal the end of the state to the chunk writer.
+	_ = pipeWrite.Close()
+
+	res := <-result
+	if res.err != nil {
+		return fmt.Errorf("Failed saving state to %q: %w", manifestPath, res.err)
+	}
+
+	d.logger.Debug("Incremental stateful checkpoint finished", logger.Ctx{"target": manifestPath, "size": res.stats.Size, "written": res.stats.NewSize, "chunks": res.stats.Chunks, "newChunks": res.stats.NewChunks})
+
+	return nil
+}
+
+// removeState removes the saved VM state (full or incremental).
+// The chunk store is kept as the base for the next incremental stateful snapshot.
+func (d *qemu) removeState() error {
+	err := os.Remove(d.StatePath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = os.Remove(d.stateManifestPath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	return nil
 }
 
@@ -1068,6 +1292,11 @@ func (d *qemu) validateStartup(stateful bool, statusCode api.StatusCode) error {
 		return errors.New("Instance is protected from being started")
 	}
 
+	// Check if instance is a standby replica.
+	if shared.IsTrue(d.localConfig["volatile.replication.standby"]) {
+		return errors.New("Instance is a standby replica and must be promoted before being started")
+	}
+
 	return nil
 }
 
@@ -1154,6 +1383,12 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		}
 	}
 
+	// Keep the console output of the previous boot.
+	err = d.consoleHistoryRotate()
+	if err != nil {
+		d.logger.Warn("Failed rotating console history", logger.Ctx{"err": err})
+	}
+
 	// Remove old pid file if needed.
 	if shared.PathExists(d.pidFilePath()) {
 		err = os.Remove(d.pidFilePath())
@@ -1240,6 +1475,13 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		return err
 	}
 
+	// Run the host-side pre-start hooks.
+	err = d.runLifecycleHooks("hooks.pre_start", "pre-start")
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
 	// Copy EDK2 settings firmware to nvram file if needed.
 	// This firmware file can be modified by the VM so it must be copied from the defaults.
 	if d.architectureSupportsUEFI(d.architecture) {
@@ -1457,6 +1699,13 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		}
 	}
 
+	// Pick the NUMA nodes to place the VM on.
+	err = d.balanceNUMANodes()
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
 	// Get CPU information.
 	cpuInfo, err := d.cpuTopology(d.expandedConfig["limits.cpu"])
 	if err != nil {
@@ -1531,8 +1780,8 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		qemuCmd = append(qemuCmd, "-incoming", "defer")
 	} else if d.stateful {
 		// Stateless start requested but state is present, delete it.
-		err := os.Remove(d.StatePath())
-		if err != nil && !os.IsNotExist(err) {
+		err := d.removeState()
+		if err != nil {
 			op.Done(err)
 			return err
 		}
@@ -1706,6 +1955,13 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	// onStop hook isn't triggered prematurely (as this function's reverter will clean up on failure to start).
 	monitor.SetOnDisconnectEvent(false)
 
+	// Make sure the requested memory encryption is in effect before going further.
+	err = d.validateMemoryEncryption(monitor)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
 	// We need to hotplug vCPUs now if:
 	// - architecture supports hotplug
 	// - no explicit vCPU pinning was specified (cpuInfo.vcpus == nil)
@@ -1775,6 +2031,13 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		return fmt.Errorf("Failed setting reboot action: %w", err)
 	}
 
+	// Have the guest report memory statistics through the balloon device, so that memory metrics can be
+	// collected from the host when the lxd-agent isn't available.
+	err = monitor.SetMemoryBalloonStatsInterval(qemuBalloonDeviceName, qemuBalloonStatsInterval)
+	if err != nil {
+		d.logger.Warn("Failed enabling memory balloon statistics", logger.Ctx{"err": err})
+	}
+
 	// Restore the state.
 	if stateful {
 		err = d.restoreState(monitor)
@@ -1795,7 +2058,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	// Finish handling stateful start.
 	if stateful {
 		// Cleanup state.
-		_ = os.Remove(d.StatePath())
+		_ = d.removeState()
 		d.stateful = false
 
 		err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -1912,7 +2175,28 @@ func (d *qemu) setupSEV(fdFiles *[]*os.File) (*qemuSevOpts, error) {
 		sevOpts.sessionDataFD = fmt.Sprintf("/proc/self/fd/%d", sessionDataFD)
 	}
 
-	if shared.IsTrue(d.expandedConfig["security.sev.policy.es"]) {
+	if shared.IsTrue(d.expandedConfig["security.sev.policy.snp"]) {
+		if shared.IsTrue(d.expandedConfig["security.sev.policy.es"]) {
+			return nil, errors.New("AMD SEV-ES and SEV-SNP can't be enabled at the same time (SEV-SNP guests always have an encrypted state)")
+		}
+
+		_, sevSNP := info.Features["sev-snp"]
+		if !sevSNP {
+			return nil, errors.New("AMD SEV-SNP is not supported by the host")
+		}
+
+		if sevOpts.dhCertFD != "" {
+			return nil, errors.New("AMD SEV session data isn't supported for SEV-SNP guests")
+		}
+
+		// The default policy allows SMT and requires the ABI version 0.0 or later (see chapter 4.3 of the link below).
+		// https://www.amd.com/content/dam/amd/en/documents/epyc-technical-docs/specifications/56860.pdf
+		sevOpts.snp = true
+		sevOpts.policy = "0x30000"
+		if d.expandedConfig["security.sev.snp.policy"] != "" {
+			sevOpts.policy = d.expandedConfig["security.sev.snp.policy"]
+		}
+	} else if shared.IsTrue(d.expandedConfig["security.sev.policy.es"]) {
 		_, sevES := info.Features["sev-es"]
 		if !sevES {
 			return nil, errors.New("AMD SEV-ES is not supported by the host")
@@ -1929,6 +2213,149 @@ func (d *qemu) setupSEV(fdFiles *[]*os.File) (*qemuSevOpts, error) {
 	return sevOpts, nil
 }
 
+func (d *qemu) setupTDX() (*qemuTDXOpts, error) {
+	if d.architecture != osarch.ARCH_64BIT_INTEL_X86 {
+		return nil, errors.New("Intel TDX support is only available on x86_64 systems")
+	}
+
+	if shared.IsTrue(d.expandedConfig["security.sev"]) {
+		return nil, errors.New("Intel TDX and AMD SEV can't be enabled at the same time")
+	}
+
+	if shared.IsTrue(d.expandedConfig["security.csm"]) {
+		return nil, errors.New("Intel TDX can't be enabled while CSM is turned on. Please set security.csm=false on the instance")
+	}
+
+	// Get the QEMU features to check if Intel TDX is supported.
+	info := DriverStatuses()[instancetype.VM].Info
+	_, tdxFound := info.Features["tdx"]
+	if !tdxFound {
+		return nil, errors.New("Intel TDX is not supported by the host")
+	}
+
+	return &qemuTDXOpts{
+		mrConfigID:    d.expandedConfig["security.tdx.mrconfigid"],
+		mrOwner:       d.expandedConfig["security.tdx.mrowner"],
+		mrOwnerConfig: d.expandedConfig["security.tdx.mrownerconfig"],
+	}, nil
+}
+
+// confidentialType returns the confidential computing technology enabled for the VM (if any).
+func (d *qemu) confidentialType() string {
+	if shared.IsTrue(d.expandedConfig["security.tdx"]) {
+		return "tdx"
+	}
+
+	if shared.IsTrue(d.expandedConfig["security.sev"]) {
+		if shared.IsTrue(d.expandedConfig["security.sev.policy.snp"]) {
+			return "sev-snp"
+		}
+
+		if shared.IsTrue(d.expandedConfig["security.sev.policy.es"]) {
+			return "sev-es"
+		}
+
+		return "sev"
+	}
+
+	return ""
+}
+
+// validateMemoryEncryption checks that memory encryption is active for a running VM that requested it.
+func (d *qemu) validateMemoryEncryption(monitor *qmp.Monitor) error {
+	confidentialType := d.confidentialType()
+	if confidentialType == "" || confidentialType == "tdx" {
+		// QEMU fails to start TDX guests when the trust domain can't be created.
+		return nil
+	}
+
+	info, err := monitor.QuerySEV()
+	if err != nil {
+		return err
+	}
+
+	expectedSEVType := "sev"
+	if confidentialType == "sev-snp" {
+		expectedSEVType = "sev-snp"
+	}
+
+	if !info.Enabled || (info.SEVType != "" && info.SEVType != expectedSEVType) {
+		return fmt.Errorf("Memory encryption (%s) isn't active for the instance", confidentialType)
+	}
+
+	return nil
+}
+
+// Attestation returns the confidential computing attestation information of the VM.
+// When a nonce is provided, an attestation report including it is requested from within the guest.
+func (d *qemu) Attestation(nonce []byte) (*api.InstanceAttestation, error) {
+	confidentialType := d.confidentialType()
+	if confidentialType == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Confidential computing isn't enabled for the instance")
+	}
+
+	if !d.IsRunning() {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "The instance isn't running")
+	}
+
+	attestation := &api.InstanceAttestation{Type: confidentialType}
+
+	if confidentialType != "tdx" {
+		monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := monitor.QuerySEV()
+		if err != nil {
+			return nil, err
+		}
+
+		attestation.State = info.State
+		if confidentialType == "sev-snp" {
+			attestation.Policy = fmt.Sprintf("0x%x", info.SNPPolicy)
+		} else {
+			attestation.Policy = fmt.Sprintf("0x%x", info.Policy)
+
+			// The launch measurement is only available for SEV and SEV-ES guests.
+			attestation.Measurement, err = monitor.SEVLaunchMeasure()
+			if err != nil {
+				d.logger.Debug("Failed getting SEV launch measurement", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	if len(nonce) > 0 {
+		client, err := d.getAgentClient()
+		if err != nil {
+			return nil, err
+		}
+
+		agent, err := lxd.ConnectLXDHTTP(nil, client)
+		if err != nil {
+			return nil, fmt.Errorf("Failed connecting to agent: %w", err)
+		}
+
+		defer agent.Disconnect()
+
+		report := api.InstanceAttestation{}
+		resp, _, err := agent.RawQuery(http.MethodGet, "/1.0/attestation?nonce="+hex.EncodeToString(nonce), nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting attestation report from the guest: %w", err)
+		}
+
+		err = resp.MetadataAsStruct(&report)
+		if err != nil {
+			return nil, err
+		}
+
+		attestation.Provider = report.Provider
+		attestation.Report = report.Report
+	}
+
+	return attestation, nil
+}
+
 // getAgentConnectionInfo returns the connection info the lxd-agent needs to connect to the LXD
 // server.
 func (d *qemu) getAgentConnectionInfo() (*agentAPI.API10Put, error) {
@@ -2788,6 +3215,44 @@ func (d *qemu) UEFIVarsUpdate(newUEFIVarsSet api.InstanceUEFIVars) error {
 	return nil
 }
 
+// UEFIVarsReset resets the UEFI variables of the instance to the firmware defaults.
+func (d *qemu) UEFIVarsReset() error {
+	if d.IsRunning() {
+		return errors.New("UEFI variables can only be reset for stopped VM instances")
+	}
+
+	if !d.architectureSupportsUEFI(d.architecture) {
+		return errors.New("UEFI is not supported for this instance architecture")
+	}
+
+	if shared.IsTrue(d.expandedConfig["security.csm"]) {
+		return errors.New("UEFI is disabled when CSM mode is active")
+	}
+
+	// Ensure that a VM start or update isn't in progress.
+	instOp, err := d.LockExclusive()
+	if err != nil {
+		return fmt.Errorf("Failed getting exclusive access instance: %w", err)
+	}
+
+	defer instOp.Done(err)
+
+	// setupNvram() requires instance's config volume to be mounted.
+	_, err = d.mount()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = d.unmount() }()
+
+	err = d.setupNvram()
+	if err != nil {
+		return fmt.Errorf("Failed setting up NVRAM: %w", err)
+	}
+
+	return nil
+}
+
 func (d *qemu) consolePath() string {
 	return filepath.Join(d.LogPath(), "qemu.console")
 }
@@ -3091,6 +3556,34 @@ echo "To start it now, unmount this filesystem and run: systemctl start lxd-agen
 		return err
 	}
 
+	// Request the lxd-agent to regenerate the identity of a copied instance.
+	identityConfigPath := filepath.Join(configDrivePath, clone.IdentityConfigFile)
+	_ = os.Remove(identityConfigPath)
+
+	key = "volatile.regenerate_identity"
+	if d.localConfig[key] != "" {
+		identityConfig, err := json.Marshal(clone.IdentityConfig{
+			Hostname: d.name,
+			Items:    strings.Split(d.localConfig[key], ","),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(identityConfigPath, identityConfig, 0400)
+		if err != nil {
+			return err
+		}
+
+		err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Remove the volatile key from the DB.
+			return tx.DeleteInstanceConfigKey(ctx, int64(d.id), key)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Copy the template metadata itself too.
 	metaPath := filepath.Join(d.Path(), "metadata.yaml")
 	if shared.PathExists(metaPath) {
@@ -3108,6 +3601,14 @@ echo "To start it now, unmount this filesystem and run: systemctl start lxd-agen
 		return err
 	}
 
+	// Clear SecretConfigDir to ensure that secrets of removed devices aren't passed to the agent.
+	secretConfigPath := filepath.Join(configDrivePath, deviceConfig.SecretConfigDir)
+	_ = os.RemoveAll(secretConfigPath)
+	err = os.MkdirAll(secretConfigPath, 0500)
+	if err != nil {
+		return err
+	}
+
 	// Writing the connection info the config drive allows the lxd-agent to start devlxd very
 	// early. This is important for systemd services which want or require /dev/lxd/sock.
 	connInfo, err := d.getAgentConnectionInfo()
@@ -3351,7 +3852,7 @@ func (d *qemu) generateQemuConfigFile(cpuInfo *cpuTopology, mountInfo *storagePo
 	cfg = append(cfg, qemuControlSocket(&qemuControlSocketOpts{d.monitorPath()})...)
 
 	// Console output.
-	cfg = append(cfg, qemuConsole(&qemuConsoleOpts{d.consolePath()})...)
+	cfg = append(cfg, qemuConsole(&qemuConsoleOpts{path: d.consolePath(), logPath: d.ConsoleBufferLogPath()})...)
 
 	// Setup the bus allocator.
 	bus := qemuNewBus(busName, &cfg)
@@ -3526,6 +4027,23 @@ func (d *qemu) generateQemuConfigFile(cpuInfo *cpuTopology, mountInfo *storagePo
 
 	cfg = append(cfg, qemuGPU(&gpuOpts)...)
 
+	// If user has requested Intel TDX, check if supported and add to QEMU config.
+	if shared.IsTrue(d.expandedConfig["security.tdx"]) {
+		tdxOpts, err := d.setupTDX()
+		if err != nil {
+			return "", nil, err
+		}
+
+		for i := range cfg {
+			if cfg[i].name == "machine" {
+				cfg[i].entries = append(cfg[i].entries, cfgEntry{"confidential-guest-support", "tdx0"}, cfgEntry{"kernel-irqchip", "split"})
+				break
+			}
+		}
+
+		cfg = append(cfg, qemuTDX(tdxOpts)...)
+	}
+
 	// If user has requested AMD SEV, check if supported and add to QEMU config.
 	if shared.IsTrue(d.expandedConfig["security.sev"]) {
 		sevOpts, err := d.setupSEV(fdFiles)
@@ -3642,6 +4160,16 @@ func (d *qemu) generateQemuConfigFile(cpuInfo *cpuTopology, mountInfo *storagePo
 				return "", nil, err
 			}
 		}
+
+		// Add watchdog device.
+		if len(runConf.WatchdogDevice) > 0 {
+			monHook, err := d.addWatchdogDeviceConfig(&cfg, bus.name, busAllocate, runConf.WatchdogDevice)
+			if err != nil {
+				return "", nil, err
+			}
+
+			monHooks = append(monHooks, monHook)
+		}
 	}
 
 	err = d.generateAgentMountsFile()
@@ -3699,6 +4227,15 @@ func (d *qemu) addCPUMemoryConfig(cfg *[]cfgSection, cpuInfo *cpuTopology) error
 
 			// Expose the total requested by the user already so the hotplug limit can be set higher if needed.
 			cpuOpts.cpuRequested = cpuInfo.cores
+
+			if d.expandedConfig["limits.cpu.hotplug_max"] != "" {
+				cpuMax, err := strconv.Atoi(d.expandedConfig["limits.cpu.hotplug_max"])
+				if err != nil {
+					return fmt.Errorf("Failed parsing limits.cpu.hotplug_max: %w", err)
+				}
+
+				cpuOpts.cpuMax = cpuMax
+			}
 		} else {
 			cpuOpts.cpuCount = cpuInfo.cores
 			cpuOpts.cpuCores = cpuInfo.cores
@@ -4879,6 +5416,59 @@ func (d *qemu) addTPMDeviceConfig(cfg *[]cfgSection, tpmConfig []deviceConfig.Ru
 	return nil
 }
 
+// addWatchdogDeviceConfig adds the qemu config required for adding a watchdog device.
+// It returns a monitor hook setting the action taken when the watchdog expires.
+func (d *qemu) addWatchdogDeviceConfig(cfg *[]cfgSection, busName string, busAllocate busAllocator, watchdogConfig []deviceConfig.RunConfigItem) (monitorHook, error) {
+	var devName, model, action string
+
+	for _, watchdogItem := range watchdogConfig {
+		switch watchdogItem.Key {
+		case "devName":
+			devName = watchdogItem.Value
+		case "model":
+			model = watchdogItem.Value
+		case "action":
+			action = watchdogItem.Value
+		}
+	}
+
+	watchdogOpts := qemuWatchdogOpts{
+		devName: devName,
+		model:   model,
+	}
+
+	if model != "itco" {
+		devBus, devAddr, multi, err := busAllocate(devName, false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed allocating bus for watchdog device %q: %w", devName, err)
+		}
+
+		watchdogOpts.dev = qemuDevOpts{
+			busName:       busName,
+			devBus:        devBus,
+			devAddr:       devAddr,
+			multifunction: multi,
+		}
+	}
+
+	*cfg = append(*cfg, qemuWatchdog(&watchdogOpts)...)
+
+	// A watchdog reset goes through the reboot action, which makes LXD restart the instance.
+	qemuAction := "reset"
+	switch action {
+	case "poweroff":
+		qemuAction = "poweroff"
+	case "none":
+		qemuAction = "none"
+	}
+
+	monHook := func(m *qmp.Monitor) error {
+		return m.SetAction(map[string]string{"watchdog": qemuAction})
+	}
+
+	return monHook, nil
+}
+
 func (d *qemu) addVmgenDeviceConfig(cfg *[]cfgSection, guid string) error {
 	vmgenIDOpts := qemuVmgenIDOpts{
 		guid: guid,
@@ -4991,7 +5581,7 @@ func (d *qemu) Stop(stateful bool) error {
 		}
 
 		// Wait for QEMU process to exit and perform device cleanup.
-		err = d.onStop("stop")
+		err = d.onStop("stop", "")
 		if err != nil {
 			op.Done(err)
 			return err
@@ -5116,6 +5706,8 @@ func (d *qemu) snapshot(name string, expiry *time.Time, stateful bool) error {
 	var err error
 	var monitor *qmp.Monitor
 
+	incremental := shared.IsTrue(d.expandedConfig["snapshots.stateful.incremental"])
+
 	// Deal with state.
 	if stateful {
 		// Confirm the instance has stateful migration enabled.
@@ -5135,7 +5727,14 @@ func (d *qemu) snapshot(name string, expiry *time.Time, stateful bool) error {
 		}
 
 		// Dump the state.
-		err = d.saveState(monitor)
+		if incremental {
+			err = d.saveStateIncremental(monitor)
+		} else {
+			// Drop any chunk store left over from previous incremental stateful snapshots.
+			_ = os.RemoveAll(d.stateChunksPath())
+			err = d.saveState(monitor)
+		}
+
 		if err != nil {
 			return err
 		}
@@ -5149,8 +5748,16 @@ func (d *qemu) snapshot(name string, expiry *time.Time, stateful bool) error {
 
 	// Resume the VM once the disk state has been saved.
 	if stateful {
+		// Only keep the chunks of the new state in the main volume as the base for the next incremental snapshot.
+		if incremental {
+			err = statestore.Prune(d.stateChunksPath(), d.stateManifestPath())
+			if err != nil {
+				return err
+			}
+		}
+
 		// Remove the state from the main volume.
-		err = os.Remove(d.StatePath())
+		err = d.removeState()
 		if err != nil {
 			return err
 		}
@@ -5532,8 +6139,15 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 		}
 	}
 
+	// Validate the new labels.
+	err = instance.ValidLabels(args.Labels)
+	if err != nil {
+		return err
+	}
+
 	// Get a copy of the old configuration.
 	oldDescription := d.Description()
+	oldLabels := d.labels
 	oldArchitecture := 0
 	err = shared.DeepCopy(&d.architecture, &oldArchitecture)
 	if err != nil {
@@ -5581,6 +6195,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 	// Revert local changes if update fails.
 	revert.Add(func() {
 		d.description = oldDescription
+		d.labels = oldLabels
 		d.architecture = oldArchitecture
 		d.ephemeral = oldEphemeral
 		d.expandedConfig = oldExpandedConfig
@@ -5593,6 +6208,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 
 	// Apply the various changes to local vars.
 	d.description = args.Description
+	d.labels = args.Labels
 	d.architecture = args.Architecture
 	d.ephemeral = args.Ephemeral
 	d.localConfig = args.Config
@@ -5737,6 +6353,8 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 		liveUpdateKeys := []string{
 			"cluster.evacuate",
 			"limits.memory",
+			"limits.memory.balloon",
+			"limits.memory.min",
 			"security.agent.metrics",
 			"security.csm",
 			"security.devlxd",
@@ -5822,6 +6440,23 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 						return fmt.Errorf("Failed updating memory limit: %w", err)
 					}
 				}
+			case "limits.memory.balloon":
+				if value == "auto" {
+					break
+				}
+
+				// Give the full memory limit back to the VM when the automatic policy is disabled.
+				memoryLimit := d.expandedConfig["limits.memory"]
+				if memoryLimit == "" {
+					memoryLimit = QEMUDefaultMemSize
+				}
+
+				err = d.updateMemoryLimit(memoryLimit)
+				if err != nil {
+					return fmt.Errorf("Failed resetting memory balloon: %w", err)
+				}
+
+				d.memoryBalloonForget()
 			case "security.csm":
 				// Defer rebuilding nvram until next start.
 				d.localConfig["volatile.apply_nvram"] = "true"
@@ -5909,6 +6544,11 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			return err
 		}
 
+		err = dbCluster.UpdateInstanceLabels(ctx, tx.Tx(), int64(object.ID), d.labels)
+		if err != nil {
+			return err
+		}
+
 		devices, err := dbCluster.APIToDevices(d.localDevices.CloneNative())
 		if err != nil {
 			return err
@@ -5969,6 +6609,20 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 				return err
 			}
 		}
+
+		// Let the agent online any hotplugged vCPUs.
+		if cpuLimitWasChanged {
+			msg := map[string]any{
+				"key":       "limits.cpu",
+				"old_value": oldExpandedConfig["limits.cpu"],
+				"value":     d.expandedConfig["limits.cpu"],
+			}
+
+			err = d.devlxdEventSend("config", msg)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	if userRequested {
@@ -6183,7 +6837,7 @@ func (d *qemu) delete(force bool) error {
 	}
 
 	// Check if instance is delete protected.
-	if !force && shared.IsTrue(d.expandedConfig["security.protection.delete"]) && !d.IsSnapshot() {
+	if !force && project.DeleteProtectedFromRecord(&d.project, d.expandedConfig) && !d.IsSnapshot() {
 		return errors.New("Instance is protected from being deleted")
 	}
 
@@ -6762,7 +7416,7 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) (err error) {
 				defer instanceRefClear(d)
 			}
 
-			err = d.migrateSendLive(pool, args.ClusterMoveSourceName, blockSize, filesystemConn, stateConn, volSourceArgs)
+			err = d.migrateSendLive(pool, args.ClusterMoveSourceName, blockSize, filesystemConn, stateConn, volSourceArgs, args.MigrationConfig)
 			if err != nil {
 				return err
 			}
@@ -6801,12 +7455,18 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) (err error) {
 }
 
 // migrateSendLive performs live migration send process.
-func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, volSourceArgs *migration.VolumeSourceArgs) error {
+func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, volSourceArgs *migration.VolumeSourceArgs, migrationConfig map[string]string) error {
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
 	if err != nil {
 		return err
 	}
 
+	// Apply the per-migration overrides on top of the instance's live migration configuration.
+	config := maps.Clone(d.expandedConfig)
+	maps.Copy(config, migrationConfig)
+
+	postcopy := shared.IsTrue(config["migration.postcopy"])
+
 	rootDiskName := "lxd_root"                  // Name of source disk device to sync from
 	nbdTargetDiskName := "lxd_root_nbd"         // Name of NBD disk device added to local VM to sync to.
 	rootSnapshotDiskName := "lxd_root_snapshot" // Name of snapshot disk device to use.
@@ -6822,7 +7482,10 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		// Setup migration capabilities.
 		capabilities := map[string]bool{
 			// Automatically throttle down the guest to speed up convergence of RAM migration.
-			"auto-converge": true,
+			"auto-converge": shared.IsTrueOrEmpty(config["migration.auto_converge"]),
+
+			// Allow the migration to be switched to post-copy mode once the bulk of the RAM has been sent.
+			"postcopy-ram": postcopy,
 
 			// Allow the migration to be paused after the source qemu releases the block devices but
 			// before the serialisation of the device state, to avoid a race condition between
@@ -6929,7 +7592,10 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		// Still set some options for shared storage.
 		capabilities := map[string]bool{
 			// Automatically throttle down the guest to speed up convergence of RAM migration.
-			"auto-converge": true,
+			"auto-converge": shared.IsTrueOrEmpty(config["migration.auto_converge"]),
+
+			// Allow the migration to be switched to post-copy mode once the bulk of the RAM has been sent.
+			"postcopy-ram": postcopy,
 		}
 
 		err = monitor.MigrateSetCapabilities(capabilities)
@@ -6938,6 +7604,34 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		}
 	}
 
+	// Setup migration parameters.
+	params := map[string]any{}
+	if config["migration.bandwidth"] != "" || d.state.LocalConfig.MaxMigrationBandwidth() > 0 {
+		bandwidth, err := units.ParseByteSizeString(config["migration.bandwidth"])
+		if err != nil {
+			return fmt.Errorf("Failed parsing migration bandwidth: %w", err)
+		}
+
+		// Never exceed the bandwidth limit of the cluster member.
+		params["max-bandwidth"] = migration.BandwidthLimit(bandwidth, d.state.LocalConfig.MaxMigrationBandwidth())
+	}
+
+	if config["migration.downtime_limit"] != "" {
+		downtimeLimit, err := strconv.ParseUint(config["migration.downtime_limit"], 10, 32)
+		if err != nil {
+			return fmt.Errorf("Failed parsing migration downtime limit: %w", err)
+		}
+
+		params["downtime-limit"] = downtimeLimit
+	}
+
+	if len(params) > 0 {
+		err = monitor.MigrateSetParameters(params)
+		if err != nil {
+			return fmt.Errorf("Failed setting migration parameters: %w", err)
+		}
+	}
+
 	// Perform storage transfer while instance is still running.
 	// For shared storage the storage driver will likely not do much here, but we still call it anyway for the
 	// sense checks it performs.
@@ -7069,27 +7763,61 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	d.logger.Debug("Stateful migration checkpoint send starting")
 
 	// Send checkpoint to QEMU process on target. This will pause the guest OS (if not already paused).
-	pipeRead, pipeWrite, err := os.Pipe()
-	if err != nil {
-		return err
-	}
+	var stateFile *os.File
+	if postcopy {
+		// Post-copy migration requires a return path from the target, so use a socket rather than a pipe.
+		socket, cleanup, err := d.migrationStateSocket(stateConn)
+		if err != nil {
+			return err
+		}
 
-	defer func() {
-		_ = pipeRead.Close()
-		_ = pipeWrite.Close()
-	}()
+		defer cleanup()
 
-	go func() { _, _ = io.Copy(stateConn, pipeRead) }()
+		stateFile = socket
+	} else {
+		pipeRead, pipeWrite, err := os.Pipe()
+		if err != nil {
+			return err
+		}
 
-	err = d.saveStateHandle(monitor, pipeWrite)
+		defer func() {
+			_ = pipeRead.Close()
+			_ = pipeWrite.Close()
+		}()
+
+		go func() { _, _ = io.Copy(stateConn, pipeRead) }()
+
+		stateFile = pipeWrite
+	}
+
+	err = d.saveStateHandle(monitor, stateFile)
 	if err != nil {
 		return fmt.Errorf("Failed starting state transfer to target: %w", err)
 	}
 
+	// Report the state transfer progress and switch to post-copy mode (if enabled) once the first pass over
+	// the guest's RAM has completed.
+	postcopyStarted := false
+	migrateProgress := func(info *qmp.MigrationInfo) error {
+		d.updateMigrationProgress(info)
+
+		if postcopy && !postcopyStarted && info.Status == "active" && info.RAM.DirtySyncCount > 1 {
+			err := monitor.MigrateStartPostcopy()
+			if err != nil {
+				return fmt.Errorf("Failed switching state transfer to post-copy: %w", err)
+			}
+
+			postcopyStarted = true
+			d.logger.Debug("Stateful migration checkpoint switched to post-copy")
+		}
+
+		return nil
+	}
+
 	// Non-shared storage snapshot transfer finalization.
 	if !sharedStorage {
 		// Wait until state transfer has reached pre-switchover state (the guest OS will remain paused).
-		err = monitor.MigrateWait("pre-switchover")
+		err = monitor.MigrateWaitProgress("pre-switchover", migrateProgress)
 		if err != nil {
 			return fmt.Errorf("Failed waiting for state transfer to reach pre-switchover stage: %w", err)
 		}
@@ -7115,7 +7843,7 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	}
 
 	// Wait until the migration state transfer has completed (the guest OS will remain paused).
-	err = monitor.MigrateWait("completed")
+	err = monitor.MigrateWaitProgress("completed", migrateProgress)
 	if err != nil {
 		return fmt.Errorf("Failed waiting for state transfer to reach completed stage: %w", err)
 	}
@@ -7824,6 +8552,17 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	return file, chDisconnect, nil
 }
 
+// ConsoleHistory returns the persistent console history of the instance followed by the serial console output
+// of the current (or last) boot.
+func (d *qemu) ConsoleHistory() ([]byte, error) {
+	current, err := os.ReadFile(d.ConsoleBufferLogPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Failed reading console log: %w", err)
+	}
+
+	return d.consoleHistory(current)
+}
+
 // Exec a command inside the instance.
 func (d *qemu) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	revert := revert.New()
@@ -7937,11 +8676,12 @@ func (d *qemu) Render(options ...func(response any) error) (state any, etag any,
 	}
 
 	// Prepare the ETag
-	etag = []any{d.architecture, d.localConfig, d.localDevices, d.ephemeral, d.profiles}
+	etag = []any{d.architecture, d.localConfig, d.localDevices, d.ephemeral, d.profiles, d.labels}
 
 	instState := api.Instance{
 		Name:            d.name,
 		Description:     d.description,
+		Labels:          d.labels,
 		Architecture:    d.architectureName,
 		Profiles:        profileNames,
 		Config:          d.localConfig,
@@ -8068,6 +8808,20 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 			}
 		}
 
+		// Populate the vCPU counts.
+		status.CPU.VCPUs, status.CPU.VCPUsMax, err = d.vcpuState()
+		if err != nil {
+			d.logger.Warn("Failed getting vCPU state", logger.Ctx{"err": err})
+		}
+
+		// Populate the NUMA placement.
+		numaNodes, err := resources.ParseNumaNodeSet(d.cpuNodes())
+		if err == nil {
+			for _, node := range numaNodes {
+				status.CPU.NUMANodes = append(status.CPU.NUMANodes, uint64(node))
+			}
+		}
+
 		// Populate host_name for network devices.
 		for k, m := range d.ExpandedDevices() {
 			// We only care about nics.
@@ -8097,6 +8851,7 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 	status.Pid = int64(pid)
 	status.Status = statusCode.String()
 	status.StatusCode = statusCode
+	status.AutoRestarts, _ = strconv.ParseInt(d.localConfig["volatile.autorestart.count"], 10, 64)
 	status.Disk, err = d.diskState()
 	if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
 		d.logger.Info("Unable to get disk usage", logger.Ctx{"err": err})
@@ -8543,8 +9298,9 @@ func (d *qemu) cpuTopology(limit string) (*cpuTopology, error) {
 		topology.threads = 1
 
 		// Check for NUMA node assignment without pinning
-		if d.expandedConfig["limits.cpu.nodes"] != "" {
-			numaNodeIDs, err := resources.ParseNumaNodeSet(d.expandedConfig["limits.cpu.nodes"])
+		cpuNodes := d.cpuNodes()
+		if cpuNodes != "" {
+			numaNodeIDs, err := resources.ParseNumaNodeSet(cpuNodes)
 			if err != nil {
 				return nil, fmt.Errorf("Invalid NUMA node selection: %v", err)
 			}
@@ -8563,11 +9319,10 @@ func (d *qemu) cpuTopology(limit string) (*cpuTopology, error) {
 				}
 			} else {
 				// If multiple NUMA nodes are given, distribute vCPUs evenly across specified nodes.
-				node := numaNodeIDs[0]
-				topology.nodes[uint64(node)] = []uint64{}
 				for i := uint64(0); i < uint64(nrLimit); i++ {
+					node := uint64(numaNodeIDs[i%uint64(len(numaNodeIDs))])
 					topology.vcpus[i] = i
-					topology.nodes[uint64(node)] = append(topology.nodes[uint64(node)], i)
+					topology.nodes[node] = append(topology.nodes[node], i)
 				}
 			}
 		}
@@ -8975,8 +9730,24 @@ func (d *qemu) checkFeatures(hostArch int, qemuPath string) (map[string]any, err
 				} else if strings.TrimSpace(string(sevES)) == "Y" {
 					features["sev-es"] = struct{}{}
 				}
+
+				// Check if the SEV-SNP extension is enabled.
+				sevSNP, err := os.ReadFile("/sys/module/kvm_amd/parameters/sev_snp")
+				if err != nil {
+					logger.Debug("Failed querying SEV-SNP capability during VM feature check", logger.Ctx{"err": err})
+				} else if strings.TrimSpace(string(sevSNP)) == "Y" {
+					features["sev-snp"] = struct{}{}
+				}
 			}
 		}
+
+		// Check if Intel TDX is enabled.
+		tdx, err := os.ReadFile("/sys/module/kvm_intel/parameters/tdx")
+		if err != nil && !os.IsNotExist(err) {
+			logger.Debug("Failed querying TDX capability during VM feature check", logger.Ctx{"err": err})
+		} else if strings.TrimSpace(string(tdx)) == "Y" {
+			features["tdx"] = struct{}{}
+		}
 	}
 
 	// Check if vhost-net accelerator (for NIC CPU offloading) is available.
@@ -9007,24 +9778,48 @@ func (d *qemu) Metrics(_ []net.Interface) (*metrics.MetricSet, error) {
 		return nil, ErrInstanceIsStopped
 	}
 
+	var metricSet *metrics.MetricSet
+	var err error
+
 	if d.agentMetricsEnabled() {
-		metrics, err := d.getAgentMetrics()
+		metricSet, err = d.getAgentMetrics()
 		if err != nil {
 			if !errors.Is(err, errQemuAgentOffline) {
 				d.logger.Warn("Could not get VM metrics from agent", logger.Ctx{"err": err})
 			}
-
-			// Fallback data if agent is not reachable.
-			return d.getQemuMetrics()
 		}
+	}
 
-		return metrics, nil
+	if metricSet == nil {
+		// Fallback data if agent is not reachable.
+		metricSet, err = d.getQemuMetrics()
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return d.getQemuMetrics()
+	d.addMemoryBalloonMetrics(metricSet)
+
+	return metricSet, nil
 }
 
 func (d *qemu) getAgentMetrics() (*metrics.MetricSet, error) {
+	m, err := d.queryAgentMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	// The running state is hard-coded here as if we've made it to this point, the VM is running.
+	metricSet, err := metrics.MetricSetFromAPI(m, map[string]string{"project": d.project.Name, "name": d.name, "type": instancetype.VM.String(), "state": instance.PowerStateRunning})
+	if err != nil {
+		return nil, err
+	}
+
+	return metricSet, nil
+}
+
+// queryAgentMetrics retrieves the raw metrics from the lxd-agent.
+func (d *qemu) queryAgentMetrics() (*metrics.Metrics, error) {
 	client, err := d.getAgentClient()
 	if err != nil {
 		return nil, err
@@ -9050,13 +9845,7 @@ func (d *qemu) getAgentMetrics() (*metrics.MetricSet, error) {
 		return nil, err
 	}
 
-	// The running state is hard-coded here as if we've made it to this point, the VM is running.
-	metricSet, err := metrics.MetricSetFromAPI(&m, map[string]string{"project": d.project.Name, "name": d.name, "type": instancetype.VM.String(), "state": instance.PowerStateRunning})
-	if err != nil {
-		return nil, err
-	}
-
-	return metricSet, nil
+	return &m, nil
 }
 
 func (d *qemu) getNetworkState() (map[string]api.InstanceStateNetwork, error) {
@@ -9183,9 +9972,9 @@ func (d *qemu) setCPUs(count int) error {
 
 	// More CPUs requested.
 	if count > totalReservedCPUs {
-		// Cannot allocate more CPUs than the system provides.
+		// Cannot allocate more CPUs than the maximum the VM was started with.
 		if count > len(cpus) {
-			return errors.New("Cannot allocate more CPUs than available")
+			return fmt.Errorf("Cannot allocate more than %d vCPUs to the running instance, restart it with a higher limits.cpu.hotplug_max to do so", len(cpus))
 		}
 
 		// This shouldn't trigger, but if it does, don't panic.
@@ -9284,6 +10073,32 @@ func (d *qemu) setCPUs(count int) error {
 	return nil
 }
 
+// vcpuState returns the number of vCPUs currently plugged into the running VM and the maximum number of vCPUs
+// that can be plugged into it.
+func (d *qemu) vcpuState() (int64, int64, error) {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pids, err := monitor.GetCPUs()
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed getting vCPUs: %w", err)
+	}
+
+	count := int64(len(pids))
+	if !d.architectureSupportsCPUHotplug() {
+		return count, count, nil
+	}
+
+	cpus, err := monitor.QueryHotpluggableCPUs()
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed to query hotpluggable CPUs: %w", err)
+	}
+
+	return count, int64(len(cpus)), nil
+}
+
 func (d *qemu) architectureSupportsCPUHotplug() bool {
 	// Check supported features.
 	info := DriverStatuses()[instancetype.VM].Info
@@ -0,0 +1,203 @@
+package drivers
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/instance/drivers/qmp"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+const (
+	// qemuBalloonPressureHigh is the memory pressure (percentage of stalled time) above which memory is given
+	// back to the guest.
+	qemuBalloonPressureHigh = 10.0
+
+	// qemuBalloonPressureLow is the memory pressure below which memory can be reclaimed from the guest.
+	qemuBalloonPressureLow = 1.0
+
+	// qemuBalloonStepPercent is the maximum amount of memory (as a percentage of limits.memory) that is
+	// reclaimed from the guest in a single step.
+	qemuBalloonStepPercent = 10
+
+	// qemuBalloonHeadroomPercent is the amount of memory (as a percentage of the memory in use by the guest)
+	// that is always left available to the guest.
+	qemuBalloonHeadroomPercent = 25
+
+	// qemuBalloonDeviceName is the ID of the balloon device (see qemuBalloon).
+	qemuBalloonDeviceName = "qemu_balloon"
+
+	// qemuBalloonStatsInterval is the interval in seconds at which the guest reports memory statistics through
+	// the balloon device. These are used for the memory metrics when the lxd-agent isn't available.
+	qemuBalloonStatsInterval = 10
+)
+
+// qemuBalloonState tracks the automatic memory balloon activity of a running VM.
+type qemuBalloonState struct {
+	targetBytes int64
+	resizes     map[string]uint64 // Keyed by direction ("grow" or "shrink").
+}
+
+var qemuBalloonStates = map[string]*qemuBalloonState{}
+var qemuBalloonStatesMu sync.Mutex
+
+// MemoryBalloonAdjust resizes the memory balloon of the running VM based on the memory pressure reported by the
+// lxd-agent. It does nothing unless limits.memory.balloon is set to auto.
+func (d *qemu) MemoryBalloonAdjust() error {
+	if d.expandedConfig["limits.memory.balloon"] != "auto" || !d.IsRunning() {
+		return nil
+	}
+
+	minBytes, maxBytes, err := d.memoryBalloonRange()
+	if err != nil {
+		return err
+	}
+
+	// Leave the balloon alone until the lxd-agent can report on the guest memory usage.
+	m, err := d.queryAgentMetrics()
+	if errors.Is(err, errQemuAgentOffline) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err // The VM isn't running as no monitor socket available.
+	}
+
+	curBytes, err := monitor.GetMemoryBalloonSizeBytes()
+	if err != nil {
+		return err
+	}
+
+	targetBytes := memoryBalloonTarget(curBytes, minBytes, maxBytes, m.Memory)
+	if targetBytes == curBytes {
+		d.memoryBalloonRecord(curBytes, "")
+		return nil
+	}
+
+	err = monitor.SetMemoryBalloonSizeBytes(targetBytes)
+	if err != nil {
+		return err
+	}
+
+	direction := "grow"
+	if targetBytes < curBytes {
+		direction = "shrink"
+	}
+
+	d.memoryBalloonRecord(targetBytes, direction)
+	d.logger.Debug("Resized memory balloon", logger.Ctx{"from": curBytes, "to": targetBytes, "pressure": m.Memory.PressureSomeAvg10})
+
+	return nil
+}
+
+// memoryBalloonRange returns the range within which the memory balloon can be automatically resized.
+func (d *qemu) memoryBalloonRange() (minBytes int64, maxBytes int64, err error) {
+	memoryLimit := d.expandedConfig["limits.memory"]
+	if memoryLimit == "" {
+		memoryLimit = QEMUDefaultMemSize
+	}
+
+	maxBytes, err = parseMemoryStr(memoryLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	minBytes = maxBytes / 2
+	if d.expandedConfig["limits.memory.min"] != "" {
+		minBytes, err = units.ParseByteSizeString(d.expandedConfig["limits.memory.min"])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return min(minBytes, maxBytes), maxBytes, nil
+}
+
+// memoryBalloonTarget returns the memory size that should be given to the guest.
+// Memory is given back as soon as the guest is under pressure or runs low on available memory, while it is
+// reclaimed by small steps and only when the guest is idle, always keeping some headroom on top of the memory
+// in use.
+func memoryBalloonTarget(curBytes int64, minBytes int64, maxBytes int64, mem metrics.MemoryMetrics) int64 {
+	usedBytes := max(int64(mem.MemTotalBytes)-int64(mem.MemAvailableBytes), 0)
+	wantBytes := usedBytes + usedBytes*qemuBalloonHeadroomPercent/100
+	stepBytes := maxBytes * qemuBalloonStepPercent / 100
+
+	targetBytes := curBytes
+	if mem.PressureSomeAvg10 >= qemuBalloonPressureHigh {
+		targetBytes = max(curBytes+stepBytes, wantBytes)
+	} else if wantBytes > curBytes {
+		targetBytes = wantBytes
+	} else if mem.PressureSomeAvg10 < qemuBalloonPressureLow {
+		targetBytes = max(curBytes-stepBytes, wantBytes)
+	}
+
+	// Round down to the MiB and keep within the configured range.
+	targetBytes = targetBytes / 1024 / 1024 * 1024 * 1024
+	targetBytes = min(max(targetBytes, minBytes), maxBytes)
+
+	// Ignore changes below 1% of the memory limit to avoid resizing the balloon back and forth.
+	diff := targetBytes - curBytes
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if diff < maxBytes/100 {
+		return curBytes
+	}
+
+	return targetBytes
+}
+
+// memoryBalloonRecord records the current target of the memory balloon and the direction of the last resize.
+func (d *qemu) memoryBalloonRecord(targetBytes int64, direction string) {
+	qemuBalloonStatesMu.Lock()
+	defer qemuBalloonStatesMu.Unlock()
+
+	key := project.Instance(d.project.Name, d.name)
+
+	state, ok := qemuBalloonStates[key]
+	if !ok {
+		state = &qemuBalloonState{resizes: map[string]uint64{}}
+		qemuBalloonStates[key] = state
+	}
+
+	state.targetBytes = targetBytes
+	if direction != "" {
+		state.resizes[direction]++
+	}
+}
+
+// memoryBalloonForget clears the recorded memory balloon activity of the VM.
+func (d *qemu) memoryBalloonForget() {
+	qemuBalloonStatesMu.Lock()
+	defer qemuBalloonStatesMu.Unlock()
+
+	delete(qemuBalloonStates, project.Instance(d.project.Name, d.name))
+}
+
+// addMemoryBalloonMetrics adds the automatic memory balloon metrics to the metric set.
+func (d *qemu) addMemoryBalloonMetrics(metricSet *metrics.MetricSet) {
+	if d.expandedConfig["limits.memory.balloon"] != "auto" {
+		return
+	}
+
+	qemuBalloonStatesMu.Lock()
+	defer qemuBalloonStatesMu.Unlock()
+
+	state, ok := qemuBalloonStates[project.Instance(d.project.Name, d.name)]
+	if !ok {
+		return
+	}
+
+	metricSet.AddSamples(metrics.MemoryBalloonTargetBytes, metrics.Sample{Value: float64(state.targetBytes)})
+
+	for _, direction := range []string{"grow", "shrink"} {
+		metricSet.AddSamples(metrics.MemoryBalloonResizesTotal, metrics.Sample{Labels: map[string]string{"direction": direction}, Value: float64(state.resizes[direction])})
+	}
+}
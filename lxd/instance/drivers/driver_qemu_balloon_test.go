@@ -0,0 +1,85 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/lxd/metrics"
+)
+
+func TestMemoryBalloonTarget(t *testing.T) {
+	const MiB = int64(1024 * 1024)
+	const GiB = 1024 * MiB
+
+	minBytes := 1 * GiB
+	maxBytes := 4 * GiB
+
+	tests := []struct {
+		name     string
+		curBytes int64
+		used     int64
+		pressure float64
+		expected int64
+	}{
+		{
+			name:     "Idle guest gives back a step of memory",
+			curBytes: 4 * GiB,
+			used:     1 * GiB,
+			expected: (4*GiB - maxBytes/10) / MiB * MiB,
+		},
+		{
+			name:     "Idle guest keeps some headroom",
+			curBytes: 2 * GiB,
+			used:     GiB + GiB/2,
+			expected: (GiB + GiB/2) * 125 / 100,
+		},
+		{
+			name:     "Idle guest is never shrunk below the minimum",
+			curBytes: 1*GiB + GiB/10,
+			used:     GiB / 4,
+			expected: 1 * GiB,
+		},
+		{
+			name:     "Guest running low on memory",
+			curBytes: 2 * GiB,
+			used:     2 * GiB,
+			pressure: 5,
+			expected: 2*GiB + GiB/2,
+		},
+		{
+			name:     "Guest under pressure",
+			curBytes: 2 * GiB,
+			used:     GiB,
+			pressure: 20,
+			expected: (2*GiB + maxBytes/10) / MiB * MiB,
+		},
+		{
+			name:     "Guest under pressure is never grown above the limit",
+			curBytes: 4 * GiB,
+			used:     4 * GiB,
+			pressure: 50,
+			expected: 4 * GiB,
+		},
+		{
+			name:     "Moderate pressure leaves the balloon alone",
+			curBytes: 3 * GiB,
+			used:     GiB,
+			pressure: 5,
+			expected: 3 * GiB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mem := metrics.MemoryMetrics{
+				MemTotalBytes:     uint64(test.curBytes),
+				MemAvailableBytes: uint64(max(test.curBytes-test.used, 0)),
+				PressureSomeAvg10: test.pressure,
+			}
+
+			targetBytes := memoryBalloonTarget(test.curBytes, minBytes, maxBytes, mem)
+			if targetBytes != test.expected {
+				t.Errorf("Expected a target of %d bytes, got %d", test.expected, targetBytes)
+			}
+		})
+	}
+}
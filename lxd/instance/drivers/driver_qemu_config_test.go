@@ -704,13 +704,23 @@ func TestQemuConfigTemplates(t *testing.T) {
 			opts     qemuConsoleOpts
 			expected string
 		}{{
-			qemuConsoleOpts{"/dev/shm/console-socket"},
+			qemuConsoleOpts{path: "/dev/shm/console-socket"},
 			`# Console
 			[chardev "console"]
 			backend = "socket"
 			path = "/dev/shm/console-socket"
 			server = "on"
 			wait = "off"`,
+		}, {
+			qemuConsoleOpts{path: "/dev/shm/console-socket", logPath: "/var/log/console.log"},
+			`# Console
+			[chardev "console"]
+			backend = "socket"
+			path = "/dev/shm/console-socket"
+			server = "on"
+			wait = "off"
+			logfile = "/var/log/console.log"
+			logappend = "on"`,
 		}}
 		for _, tc := range testCases {
 			runTest(tc.expected, qemuConsole(&tc.opts))
@@ -1081,6 +1091,103 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_watchdog", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuWatchdogOpts
+			expected string
+		}{{
+			qemuWatchdogOpts{
+				dev:     qemuDevOpts{"pci", "qemu_pcie2", "00.0", false},
+				devName: "myWatchdog",
+				model:   "i6300esb",
+			},
+			`# Watchdog ("myWatchdog" device)
+			[device "dev-lxd_myWatchdog"]
+			driver = "i6300esb"
+			bus = "qemu_pcie2"
+			addr = "00.0"`,
+		}, {
+			qemuWatchdogOpts{
+				devName: "myWatchdog",
+				model:   "itco",
+			},
+			`# Watchdog ("myWatchdog" device)
+			[global]
+			driver = "ICH9-LPC"
+			property = "noreboot"
+			value = "off"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuWatchdog(&tc.opts))
+		}
+	})
+
+	t.Run("qemu_sev", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuSevOpts
+			expected string
+		}{{
+			qemuSevOpts{
+				cbitpos:         51,
+				reducedPhysBits: 1,
+				policy:          "0x5",
+				dhCertFD:        "/dev/fd/3",
+				sessionDataFD:   "/dev/fd/4",
+			},
+			`# Secure Encrypted Virtualization
+			[object "sev0"]
+			qom-type = "sev-guest"
+			cbitpos = "51"
+			reduced-phys-bits = "1"
+			policy = "0x5"
+			dh-cert-file = "/dev/fd/3"
+			session-file = "/dev/fd/4"`,
+		}, {
+			qemuSevOpts{
+				cbitpos:         51,
+				reducedPhysBits: 1,
+				policy:          "0x30000",
+				snp:             true,
+			},
+			`# Secure Encrypted Virtualization
+			[object "sev0"]
+			qom-type = "sev-snp-guest"
+			cbitpos = "51"
+			reduced-phys-bits = "1"
+			policy = "0x30000"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuSEV(&tc.opts))
+		}
+	})
+
+	t.Run("qemu_tdx", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuTDXOpts
+			expected string
+		}{{
+			qemuTDXOpts{},
+			`# Trust Domain Extensions
+			[object "tdx0"]
+			qom-type = "tdx-guest"`,
+		}, {
+			qemuTDXOpts{
+				mrConfigID:    "Y29uZmln",
+				mrOwner:       "b3duZXI=",
+				mrOwnerConfig: "b3duZXJjb25maWc=",
+			},
+			`# Trust Domain Extensions
+			[object "tdx0"]
+			qom-type = "tdx-guest"
+			mrconfigid = "Y29uZmln"
+			mrowner = "b3duZXI="
+			mrownerconfig = "b3duZXJjb25maWc="`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuTDX(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_raw_cfg_override", func(t *testing.T) {
 		cfg := []cfgSection{{
 			name: "global",
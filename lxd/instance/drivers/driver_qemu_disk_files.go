@@ -0,0 +1,306 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/sys/unix"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance/operationlock"
+	"github.com/canonical/lxd/lxd/locking"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+)
+
+// diskFilesModeFile is the name of the file recording whether the root disk is mounted read-only or read-write.
+const diskFilesModeFile = "diskfile.mode"
+
+// diskFilesMountTimeout bounds how long mounting or unmounting the root disk may take, so that a hung libguestfs
+// appliance doesn't keep the instance locked.
+const diskFilesMountTimeout = 5 * time.Minute
+
+// diskFilesMaxLifetime bounds how long the root disk stays mounted, as a client holding its connection open would
+// otherwise keep the instance locked.
+const diskFilesMaxLifetime = time.Hour
+
+// diskFilesMountArgs returns the guestmount arguments mounting the guest root filesystem of the disk at the given
+// path on the target path.
+func diskFilesMountArgs(path string, target string, pidFile string, readOnly bool) []string {
+	// Mount the filesystems of the guest operating system as found in its root filesystem, and never allow devices,
+	// setuid binaries or executables from them.
+	args := []string{"--format=raw", "--add", path, "--inspector", "--pid-file", pidFile, "--options", "nodev,nosuid,noexec"}
+	if readOnly {
+		args = append(args, "--ro")
+	} else {
+		args = append(args, "--rw")
+	}
+
+	return append(args, target)
+}
+
+// diskFilesModeAllows returns whether an existing mount of the root disk with the given recorded mode can be reused
+// for the requested access.
+func diskFilesModeAllows(mode string, writable bool) bool {
+	return !writable || mode == "rw"
+}
+
+// diskFilesMountRoot mounts the guest root filesystem of the disk image or block device at the given path on the
+// target path using guestmount. The guest filesystems are untrusted, so they are parsed by the libguestfs appliance,
+// which runs in a separate virtual machine, rather than by the host kernel which only sees the resulting FUSE mount.
+func diskFilesMountRoot(path string, target string, pidFile string, readOnly bool) error {
+	_, err := exec.LookPath("guestmount")
+	if err != nil {
+		return errors.New("Accessing the disk of stopped virtual machines requires guestmount (libguestfs) to be installed on the host")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diskFilesMountTimeout)
+	defer cancel()
+
+	_, err = shared.RunCommandContext(ctx, "guestmount", diskFilesMountArgs(path, target, pidFile, readOnly)...)
+	if err != nil {
+		return fmt.Errorf("Failed mounting the root filesystem of the instance disk: %w", err)
+	}
+
+	return nil
+}
+
+// diskFilesUnmountRoot unmounts the guest root filesystem and waits for the libguestfs appliance to exit, which
+// flushes the pending writes to the disk.
+func diskFilesUnmountRoot(target string, pidFile string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), diskFilesMountTimeout)
+	defer cancel()
+
+	_, err := shared.RunCommandContext(ctx, "guestunmount", target)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(pidFile)
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return err
+	}
+
+	for range 300 {
+		if unix.Kill(pid, 0) != nil {
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return fmt.Errorf("Timed out waiting for guestmount (PID %d) to exit", pid)
+}
+
+// DiskFileSFTPConn returns a connection to a SFTP server operating on the root filesystem of the stopped VM,
+// mounted on the host from its root disk.
+func (d *qemu) DiskFileSFTPConn(writable bool) (net.Conn, error) {
+	// Lock to avoid concurrent spawning.
+	spawnUnlock, err := locking.Lock(context.TODO(), fmt.Sprint("diskfile_", d.id))
+	if err != nil {
+		return nil, err
+	}
+
+	defer spawnUnlock()
+
+	err = os.MkdirAll(d.LogPath(), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trickery to handle paths > 108 chars.
+	dirFile, err := os.Open(d.LogPath())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = dirFile.Close() }()
+
+	diskfileAddr, err := net.ResolveUnixAddr("unix", fmt.Sprintf("/proc/self/fd/%d/diskfile.sock", dirFile.Fd()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Attempt to connect on existing socket.
+	diskfilePath := filepath.Join(d.LogPath(), "diskfile.sock")
+	modePath := filepath.Join(d.LogPath(), diskFilesModeFile)
+	diskfileConn, err := net.DialUnix("unix", nil, diskfileAddr)
+	if err == nil {
+		// Found an existing server, only reuse it if it was mounted with the required access.
+		mode, _ := os.ReadFile(modePath)
+		if !diskFilesModeAllows(string(mode), writable) {
+			_ = diskfileConn.Close()
+			return nil, errors.New("The instance disk is already mounted read-only, retry once it's released")
+		}
+
+		return diskfileConn, nil
+	}
+
+	if d.IsRunning() {
+		return nil, errors.New("The instance disk can only be accessed while the instance is stopped")
+	}
+
+	// Prevent the instance from being started (or modified) while its disk is mounted on the host.
+	op, err := operationlock.Create(d.Project().Name, d.Name(), operationlock.ActionUpdate, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	revert.Add(func() { op.Done(nil) })
+
+	// Mount the instance's config volume to activate the root disk.
+	mountInfo, err := d.mount()
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() { _ = d.unmount() })
+
+	devSource, ok := mountInfo.DevSource.(deviceConfig.DevSourcePath)
+	if !ok || devSource.Path == "" {
+		return nil, errors.New("The instance root disk isn't available on the host")
+	}
+
+	rootfsPath, err := os.MkdirTemp("", "lxd_vm_disk_")
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() { _ = os.Remove(rootfsPath) })
+
+	pidPath := filepath.Join(d.LogPath(), "diskfile.pid")
+	err = diskFilesMountRoot(devSource.Path, rootfsPath, pidPath, !writable)
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		err := diskFilesUnmountRoot(rootfsPath, pidPath)
+		if err != nil {
+			d.logger.Warn("Failed unmounting instance disk", logger.Ctx{"err": err})
+		}
+
+		_ = os.Remove(pidPath)
+	})
+
+	mode := "ro"
+	if writable {
+		mode = "rw"
+	}
+
+	err = os.WriteFile(modePath, []byte(mode), 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() { _ = os.Remove(modePath) })
+
+	// Create the listener.
+	_ = os.Remove(diskfilePath)
+	diskfileListener, err := net.ListenUnix("unix", diskfileAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = diskfileListener.Close()
+		_ = os.Remove(diskfilePath)
+	})
+
+	diskfileFile, err := diskfileListener.File()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = diskfileFile.Close() }()
+
+	rootfsFile, err := os.Open(rootfsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rootfsFile.Close() }()
+
+	// Run forkfile chrooted into the guest root filesystem (no PID to attach to).
+	var stderr bytes.Buffer
+	diskfile := exec.Cmd{
+		Path:       d.state.OS.ExecPath,
+		Args:       []string{d.state.OS.ExecPath, "forkfile", "--", "3", "4", "-1", "-1"},
+		ExtraFiles: []*os.File{diskfileFile, rootfsFile},
+		Stderr:     &stderr,
+	}
+
+	err = diskfile.Start()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to run forkfile: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Release the disk once the server exits (it stops by itself after a period of inactivity).
+	cleanup := revert.Clone()
+	go func() {
+		expiry := time.AfterFunc(diskFilesMaxLifetime, func() {
+			d.logger.Warn("Stopping disk SFTP server after its maximum lifetime", logger.Ctx{"lifetime": diskFilesMaxLifetime})
+			_ = diskfile.Process.Signal(unix.SIGTERM)
+		})
+
+		exitStatus, err := shared.ExitStatus(diskfile.Wait())
+		expiry.Stop()
+
+		if !slices.Contains([]int{0, 143}, exitStatus) {
+			d.logger.Warn("Disk SFTP server stopped with error", logger.Ctx{"err": err, "exitStatus": exitStatus, "stderr": strings.TrimSpace(stderr.String())})
+		}
+
+		cleanup.Fail()
+	}()
+
+	revert.Success()
+
+	d.logger.Debug("Mounted instance disk for file access", logger.Ctx{"disk": devSource.Path, "mode": mode, "pid": diskfile.Process.Pid})
+
+	// Connect to the new server.
+	return net.DialUnix("unix", nil, diskfileAddr)
+}
+
+// DiskFileSFTP returns a SFTP client operating on the root filesystem of the stopped VM, mounted on the host from
+// its root disk (read-only unless writable is set).
+func (d *qemu) DiskFileSFTP(writable bool) (*sftp.Client, error) {
+	conn, err := d.DiskFileSFTPConn(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get a SFTP client.
+	client, err := sftp.NewClientPipe(conn, conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	go func() {
+		// Wait for the client to be done before closing the connection.
+		_ = client.Wait()
+		_ = conn.Close()
+	}()
+
+	return client, nil
+}
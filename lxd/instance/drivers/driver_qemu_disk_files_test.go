@@ -0,0 +1,60 @@
+package drivers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiskFilesMountArgs(t *testing.T) {
+	tests := []struct {
+		readOnly bool
+		access   string
+	}{
+		{readOnly: true, access: "--ro"},
+		{readOnly: false, access: "--rw"},
+	}
+
+	for _, test := range tests {
+		args := diskFilesMountArgs("/dev/zvol/default/virtual-machines/v1.block", "/tmp/lxd_vm_disk_1", "/var/log/lxd/v1/diskfile.pid", test.readOnly)
+
+		// The disk is added as raw and the target comes last.
+		if !slices.Equal(args[:3], []string{"--format=raw", "--add", "/dev/zvol/default/virtual-machines/v1.block"}) || args[len(args)-1] != "/tmp/lxd_vm_disk_1" {
+			t.Errorf("Unexpected guestmount arguments %q", args)
+		}
+
+		// Devices, setuid binaries and executables of the guest are never allowed.
+		i := slices.Index(args, "--options")
+		if i < 0 || args[i+1] != "nodev,nosuid,noexec" {
+			t.Errorf("Missing mount options in %q", args)
+		}
+
+		i = slices.Index(args, "--pid-file")
+		if i < 0 || args[i+1] != "/var/log/lxd/v1/diskfile.pid" {
+			t.Errorf("Missing PID file in %q", args)
+		}
+
+		if !slices.Contains(args, test.access) || slices.Contains(args, "--ro") == slices.Contains(args, "--rw") {
+			t.Errorf("Expected %q access only in %q", test.access, args)
+		}
+	}
+}
+
+func TestDiskFilesModeAllows(t *testing.T) {
+	tests := []struct {
+		mode     string
+		writable bool
+		allowed  bool
+	}{
+		{mode: "ro", writable: false, allowed: true},
+		{mode: "rw", writable: false, allowed: true},
+		{mode: "ro", writable: true, allowed: false},
+		{mode: "rw", writable: true, allowed: true},
+		{mode: "", writable: true, allowed: false},
+	}
+
+	for _, test := range tests {
+		if diskFilesModeAllows(test.mode, test.writable) != test.allowed {
+			t.Errorf("diskFilesModeAllows(%q, %v) = %v, want %v", test.mode, test.writable, !test.allowed, test.allowed)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance/drivers/qmp"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/units"
@@ -34,7 +35,16 @@ func (d *qemu) getQemuMetrics() (*metrics.MetricSet, error) {
 		out.CPU = cpuStats
 	}
 
-	memoryStats, err := d.getQemuMemoryMetrics()
+	memoryStats, err := d.getQemuBalloonMemoryMetrics(monitor)
+	if err != nil {
+		if !errors.Is(err, qmp.ErrMonitorNoBalloonStats) {
+			d.logger.Debug("Failed to get memory metrics from balloon device", logger.Ctx{"err": err})
+		}
+
+		// Fallback to the memory used by the QEMU process.
+		memoryStats, err = d.getQemuMemoryMetrics()
+	}
+
 	if err != nil {
 		d.logger.Warn("Failed to get memory metrics", logger.Ctx{"err": err})
 	} else {
@@ -85,8 +95,13 @@ func (d *qemu) getQemuDiskMetrics(monitor *qmp.Monitor) (map[string]metrics.Disk
 
 	out := make(map[string]metrics.DiskMetrics)
 
-	for dev, stat := range stats {
-		out[dev] = metrics.DiskMetrics{
+	for qdev, stat := range stats {
+		// Skip block devices which aren't attached to the guest.
+		if qdev == "" {
+			continue
+		}
+
+		out[qemuBlockStatsDeviceName(qdev)] = metrics.DiskMetrics{
 			ReadBytes:       uint64(stat.BytesRead),
 			ReadsCompleted:  uint64(stat.ReadsCompleted),
 			WrittenBytes:    uint64(stat.BytesWritten),
@@ -97,6 +112,54 @@ func (d *qemu) getQemuDiskMetrics(monitor *qmp.Monitor) (map[string]metrics.Disk
 	return out, nil
 }
 
+// getQemuBalloonMemoryMetrics returns the memory metrics reported by the guest through the balloon device.
+func (d *qemu) getQemuBalloonMemoryMetrics(monitor *qmp.Monitor) (metrics.MemoryMetrics, error) {
+	stats, err := monitor.GetMemoryBalloonStats(qemuBalloonDeviceName)
+	if err != nil {
+		return metrics.MemoryMetrics{}, err
+	}
+
+	return qemuBalloonMemoryMetrics(stats)
+}
+
+// qemuBalloonMemoryMetrics converts the memory statistics reported by the guest through the balloon device.
+func qemuBalloonMemoryMetrics(stats *qmp.MemoryBalloonStats) (metrics.MemoryMetrics, error) {
+	out := metrics.MemoryMetrics{}
+
+	// Older guest drivers may not report all the statistics, in which case they are set to -1.
+	if stats.TotalMemory < 0 || stats.FreeMemory < 0 {
+		return out, qmp.ErrMonitorNoBalloonStats
+	}
+
+	out.MemTotalBytes = uint64(stats.TotalMemory)
+	out.MemFreeBytes = uint64(stats.FreeMemory)
+	out.MemAvailableBytes = uint64(stats.FreeMemory)
+
+	if stats.AvailableMemory >= 0 {
+		out.MemAvailableBytes = uint64(stats.AvailableMemory)
+	}
+
+	if stats.DiskCaches >= 0 {
+		out.CachedBytes = uint64(stats.DiskCaches)
+	}
+
+	return out, nil
+}
+
+// qemuBlockStatsDeviceName returns the name of the LXD disk device from the QEMU device path reported in the
+// block statistics, such as "/machine/peripheral/dev-lxd_root/virtio-backend" or "dev-lxd_root".
+func qemuBlockStatsDeviceName(qdev string) string {
+	name := strings.TrimPrefix(qdev, "/machine/peripheral/")
+	name, _, _ = strings.Cut(name, "/")
+
+	devName, found := strings.CutPrefix(name, qemuDeviceIDPrefix)
+	if !found {
+		return name
+	}
+
+	return filesystem.PathNameDecode(devName)
+}
+
 func (d *qemu) getQemuMemoryMetrics() (metrics.MemoryMetrics, error) {
 	out := metrics.MemoryMetrics{}
 
@@ -0,0 +1,83 @@
+package drivers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/canonical/lxd/lxd/instance/drivers/qmp"
+	"github.com/canonical/lxd/lxd/metrics"
+)
+
+func TestQemuBalloonMemoryMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    qmp.MemoryBalloonStats
+		expected metrics.MemoryMetrics
+		err      error
+	}{
+		{
+			name:  "All statistics reported",
+			stats: qmp.MemoryBalloonStats{TotalMemory: 4096, FreeMemory: 1024, AvailableMemory: 2048, DiskCaches: 512, SwapIn: -1, SwapOut: -1},
+			expected: metrics.MemoryMetrics{
+				MemTotalBytes:     4096,
+				MemFreeBytes:      1024,
+				MemAvailableBytes: 2048,
+				CachedBytes:       512,
+			},
+		},
+		{
+			name:  "Available memory falls back to free memory",
+			stats: qmp.MemoryBalloonStats{TotalMemory: 4096, FreeMemory: 1024, AvailableMemory: -1, DiskCaches: -1, SwapIn: -1, SwapOut: -1},
+			expected: metrics.MemoryMetrics{
+				MemTotalBytes:     4096,
+				MemFreeBytes:      1024,
+				MemAvailableBytes: 1024,
+			},
+		},
+		{
+			name:  "Total memory not reported",
+			stats: qmp.MemoryBalloonStats{TotalMemory: -1, FreeMemory: 1024, AvailableMemory: 2048, DiskCaches: 512, SwapIn: -1, SwapOut: -1},
+			err:   qmp.ErrMonitorNoBalloonStats,
+		},
+		{
+			name:  "Free memory not reported",
+			stats: qmp.MemoryBalloonStats{TotalMemory: 4096, FreeMemory: -1, AvailableMemory: 2048, DiskCaches: 512, SwapIn: -1, SwapOut: -1},
+			err:   qmp.ErrMonitorNoBalloonStats,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := qemuBalloonMemoryMetrics(&tt.stats)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQemuBlockStatsDeviceName(t *testing.T) {
+	tests := []struct {
+		qdev     string
+		expected string
+	}{
+		{qdev: "/machine/peripheral/dev-lxd_root/virtio-backend", expected: "root"},
+		{qdev: "dev-lxd_root", expected: "root"},
+		{qdev: "/machine/peripheral/dev-lxd_data--disk/virtio-backend", expected: "data-disk"},
+		{qdev: "/machine/peripheral/dev-lxd_mnt-data/virtio-backend", expected: "mnt/data"},
+		{qdev: "/machine/peripheral/scsi0/virtio-backend", expected: "scsi0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.qdev, func(t *testing.T) {
+			got := qemuBlockStatsDeviceName(tt.qdev)
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
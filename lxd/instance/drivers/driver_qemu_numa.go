@@ -0,0 +1,238 @@
+package drivers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/resources"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// qemuNUMABalanceMu prevents VMs starting at the same time from being placed based on the same NUMA node usage.
+var qemuNUMABalanceMu sync.Mutex
+
+// qemuNUMANode represents the capacity and usage of a host NUMA node.
+type qemuNUMANode struct {
+	id        uint64
+	threads   int
+	freeBytes int64
+	vcpus     float64 // Number of vCPUs of the running VMs placed on the node.
+}
+
+// cpuNodes returns the NUMA nodes the VM is placed on, resolving the NUMA nodes picked by LXD when
+// limits.cpu.nodes is set to balanced.
+func (d *qemu) cpuNodes() string {
+	return instanceCPUNodes(d.expandedConfig)
+}
+
+// instanceCPUNodes returns the NUMA nodes an instance is placed on based on its expanded config.
+func instanceCPUNodes(config map[string]string) string {
+	if config["limits.cpu.nodes"] == "balanced" {
+		return config["volatile.cpu.nodes"]
+	}
+
+	return config["limits.cpu.nodes"]
+}
+
+// balanceNUMANodes picks the NUMA nodes of the VM when limits.cpu.nodes is set to balanced and records them in
+// volatile.cpu.nodes.
+func (d *qemu) balanceNUMANodes() error {
+	if d.expandedConfig["limits.cpu.nodes"] != "balanced" {
+		return nil
+	}
+
+	qemuNUMABalanceMu.Lock()
+	defer qemuNUMABalanceMu.Unlock()
+
+	nodes, err := d.pickNUMANodes()
+	if err != nil {
+		return fmt.Errorf("Failed picking NUMA nodes: %w", err)
+	}
+
+	nodeIDs := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		nodeIDs = append(nodeIDs, strconv.FormatUint(node, 10))
+	}
+
+	cpuNodes := strings.Join(nodeIDs, ",")
+	if cpuNodes != "" {
+		d.logger.Debug("Picked NUMA nodes", logger.Ctx{"nodes": cpuNodes})
+	}
+
+	return d.VolatileSet(map[string]string{"volatile.cpu.nodes": cpuNodes})
+}
+
+// pickNUMANodes returns the least used NUMA nodes that can fit the vCPUs and memory of the VM.
+// A single NUMA node is preferred, otherwise as few NUMA nodes as possible are used.
+// No NUMA nodes are returned when the host has a single NUMA node, when the CPUs of the VM are pinned, or when the
+// VM can't fit on the host NUMA nodes.
+func (d *qemu) pickNUMANodes() ([]uint64, error) {
+	limit := d.expandedConfig["limits.cpu"]
+	if limit == "" {
+		limit = "1"
+	}
+
+	vcpus, err := strconv.Atoi(limit)
+	if err != nil {
+		// The pinned CPUs already define the NUMA placement.
+		return nil, nil
+	}
+
+	memoryLimit := d.expandedConfig["limits.memory"]
+	if memoryLimit == "" {
+		memoryLimit = QEMUDefaultMemSize
+	}
+
+	memoryBytes, err := parseMemoryStr(memoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := d.hostNUMANodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) < 2 {
+		return nil, nil
+	}
+
+	picked := numaNodesPick(nodes, vcpus, memoryBytes)
+	if picked == nil {
+		d.logger.Warn("Not enough resources on the host NUMA nodes, skipping NUMA placement", logger.Ctx{"vcpus": vcpus, "memory": memoryBytes})
+	}
+
+	return picked, nil
+}
+
+// numaNodesPick returns the least used NUMA nodes that can fit the given vCPUs and memory, or nil if they can't fit.
+func numaNodesPick(nodes []qemuNUMANode, vcpus int, memoryBytes int64) []uint64 {
+	nodes = slices.Clone(nodes)
+
+	// Sort the NUMA nodes from the least to the most used relative to their size.
+	slices.SortStableFunc(nodes, func(a qemuNUMANode, b qemuNUMANode) int {
+		c := cmp.Compare(a.vcpus/float64(a.threads), b.vcpus/float64(b.threads))
+		if c != 0 {
+			return c
+		}
+
+		// Prefer the NUMA node with the most free memory.
+		return cmp.Compare(b.freeBytes, a.freeBytes)
+	})
+
+	for _, node := range nodes {
+		if node.threads >= vcpus && node.freeBytes >= memoryBytes {
+			return []uint64{node.id}
+		}
+	}
+
+	// Spread the VM across the least used NUMA nodes.
+	picked := []uint64{}
+	threads := 0
+	var freeBytes int64
+	for _, node := range nodes {
+		picked = append(picked, node.id)
+		threads += node.threads
+		freeBytes += node.freeBytes
+
+		if threads >= vcpus && freeBytes >= memoryBytes {
+			slices.Sort(picked)
+			return picked
+		}
+	}
+
+	return nil
+}
+
+// hostNUMANodes returns the capacity and usage of the host NUMA nodes.
+func (d *qemu) hostNUMANodes() ([]qemuNUMANode, error) {
+	cpus, err := resources.GetCPU()
+	if err != nil {
+		return nil, err
+	}
+
+	memory, err := resources.GetMemory()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[uint64]*qemuNUMANode{}
+	for _, socket := range cpus.Sockets {
+		for _, core := range socket.Cores {
+			for _, thread := range core.Threads {
+				if !thread.Online {
+					continue
+				}
+
+				node, ok := nodes[thread.NUMANode]
+				if !ok {
+					node = &qemuNUMANode{id: thread.NUMANode}
+					nodes[thread.NUMANode] = node
+				}
+
+				node.threads++
+			}
+		}
+	}
+
+	for _, memoryNode := range memory.Nodes {
+		node, ok := nodes[memoryNode.NUMANode]
+		if ok {
+			node.freeBytes = int64(memoryNode.Total - memoryNode.Used)
+		}
+	}
+
+	// Account for the vCPUs of the other running VMs.
+	vms, err := instance.LoadNodeAll(d.state, instancetype.VM)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vm := range vms {
+		if vm.ID() == d.id || !vm.IsRunning() {
+			continue
+		}
+
+		config := vm.ExpandedConfig()
+
+		vmNodes, err := resources.ParseNumaNodeSet(instanceCPUNodes(config))
+		if err != nil || len(vmNodes) == 0 {
+			continue
+		}
+
+		vmVCPUs, err := strconv.Atoi(config["limits.cpu"])
+		if err != nil {
+			vmVCPUs = 1
+		}
+
+		for _, vmNode := range vmNodes {
+			node, ok := nodes[uint64(vmNode)]
+			if ok {
+				node.vcpus += float64(vmVCPUs) / float64(len(vmNodes))
+			}
+		}
+	}
+
+	out := make([]qemuNUMANode, 0, len(nodes))
+	for _, node := range nodes {
+		// Skip memory-only and CPU-only NUMA nodes.
+		if node.threads == 0 || node.freeBytes == 0 {
+			continue
+		}
+
+		out = append(out, *node)
+	}
+
+	// Provide a stable order to pick from.
+	slices.SortFunc(out, func(a qemuNUMANode, b qemuNUMANode) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package drivers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNUMANodesPick(t *testing.T) {
+	const GiB = int64(1024 * 1024 * 1024)
+
+	nodes := []qemuNUMANode{
+		{id: 0, threads: 8, freeBytes: 16 * GiB, vcpus: 6},
+		{id: 1, threads: 8, freeBytes: 8 * GiB, vcpus: 2},
+		{id: 2, threads: 8, freeBytes: 32 * GiB, vcpus: 2},
+		{id: 3, threads: 4, freeBytes: 4 * GiB},
+	}
+
+	tests := []struct {
+		name        string
+		vcpus       int
+		memoryBytes int64
+		expected    []uint64
+	}{
+		{
+			name:        "Least used node",
+			vcpus:       2,
+			memoryBytes: 2 * GiB,
+			expected:    []uint64{3},
+		},
+		{
+			name:        "Least used node fitting the vCPUs, preferring free memory",
+			vcpus:       6,
+			memoryBytes: 4 * GiB,
+			expected:    []uint64{2},
+		},
+		{
+			name:        "Least used node fitting the memory",
+			vcpus:       2,
+			memoryBytes: 24 * GiB,
+			expected:    []uint64{2},
+		},
+		{
+			name:        "Spread across the least used nodes",
+			vcpus:       12,
+			memoryBytes: 8 * GiB,
+			expected:    []uint64{2, 3},
+		},
+		{
+			name:        "Not enough resources",
+			vcpus:       64,
+			memoryBytes: 8 * GiB,
+			expected:    nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			picked := numaNodesPick(nodes, test.vcpus, test.memoryBytes)
+			if !slices.Equal(picked, test.expected) || (picked == nil) != (test.expected == nil) {
+				t.Errorf("Expected NUMA nodes %v, got %v", test.expected, picked)
+			}
+		})
+	}
+
+	// The nodes of the caller are left untouched.
+	if nodes[0].id != 0 || nodes[3].id != 3 {
+		t.Errorf("Unexpected reordering of the NUMA nodes %v", nodes)
+	}
+}
+
+func TestInstanceCPUNodes(t *testing.T) {
+	if instanceCPUNodes(map[string]string{"limits.cpu.nodes": "0-1"}) != "0-1" {
+		t.Error("Expected the configured NUMA nodes")
+	}
+
+	if instanceCPUNodes(map[string]string{"limits.cpu.nodes": "balanced", "volatile.cpu.nodes": "2,3"}) != "2,3" {
+		t.Error("Expected the NUMA nodes picked by LXD")
+	}
+
+	if instanceCPUNodes(map[string]string{}) != "" {
+		t.Error("Expected no NUMA nodes")
+	}
+}
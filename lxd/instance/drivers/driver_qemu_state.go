@@ -0,0 +1,115 @@
+package drivers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/canonical/lxd/lxd/instance/drivers/qmp"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// Magic bytes at the start of the compressed memory state files.
+var (
+	stateGzipMagic = []byte{0x1f, 0x8b}
+	stateZstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// stateWriteCounter counts the bytes written to the memory state file.
+type stateWriteCounter struct {
+	w       io.Writer
+	written atomic.Int64
+}
+
+// Write writes p to the underlying writer and counts the bytes written.
+func (c *stateWriteCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.written.Add(int64(n))
+
+	return n, err
+}
+
+// nopWriteCloser is a writer whose Close method does nothing.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing.
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// stateCompressionWriter returns a writer compressing the memory state to w using the algorithm and level set in
+// `migration.stateful.compression` and `migration.stateful.compression_level`.
+func (d *qemu) stateCompressionWriter(w io.Writer) (io.WriteCloser, error) {
+	level := 1
+	if d.expandedConfig["migration.stateful.compression_level"] != "" {
+		var err error
+		level, err = strconv.Atoi(d.expandedConfig["migration.stateful.compression_level"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing state compression level: %w", err)
+		}
+	}
+
+	switch d.expandedConfig["migration.stateful.compression"] {
+	case "none":
+		return nopWriteCloser{w}, nil
+	case "zstd":
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	default:
+		return gzip.NewWriterLevel(w, min(level, gzip.BestCompression))
+	}
+}
+
+// stateDecompressionReader returns a reader decompressing the memory state read from r, detecting the compression
+// algorithm from the first bytes of the state.
+func stateDecompressionReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+
+	// The uncompressed state starts with the QEMU magic bytes, which don't match any of the compressed formats.
+	magic, _ := br.Peek(len(stateZstdMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, stateGzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, stateZstdMagic):
+		decoder, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	default:
+		return io.NopCloser(br), nil
+	}
+}
+
+// updateStateSaveProgress reports the progress of the memory state save in the operation metadata.
+func (d *qemu) updateStateSaveProgress(info *qmp.MigrationInfo, written int64) {
+	if d.op == nil {
+		return
+	}
+
+	meta := d.op.Metadata()
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+
+	meta["state_save"] = map[string]any{
+		"status":      info.Status,
+		"transferred": info.RAM.Transferred,
+		"remaining":   info.RAM.Remaining,
+		"total":       info.RAM.Total,
+		"written":     written,
+	}
+
+	meta["container_progress"] = fmt.Sprintf("Memory: %s / %s (%s written)", units.GetByteSizeString(info.RAM.Transferred, 2), units.GetByteSizeString(info.RAM.Total, 2), units.GetByteSizeString(written, 2))
+
+	_ = d.op.UpdateMetadata(meta)
+}
@@ -0,0 +1,80 @@
+package drivers
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestStateCompression(t *testing.T) {
+	// The uncompressed state starts with the QEMU magic bytes.
+	state := bytes.Repeat([]byte("QEVM\x00\x00\x00\x03memory state "), 1024)
+
+	tests := []struct {
+		compression string
+		level       string
+		magic       []byte
+	}{
+		{compression: "", magic: stateGzipMagic},
+		{compression: "gzip", level: "22", magic: stateGzipMagic},
+		{compression: "zstd", level: "3", magic: stateZstdMagic},
+		{compression: "none", magic: []byte("QEVM")},
+	}
+
+	for _, test := range tests {
+		d := &qemu{common: common{expandedConfig: map[string]string{
+			"migration.stateful.compression":       test.compression,
+			"migration.stateful.compression_level": test.level,
+		}}}
+
+		buf := &bytes.Buffer{}
+		w, err := d.stateCompressionWriter(buf)
+		if err != nil {
+			t.Fatalf("Failed creating %q writer: %v", test.compression, err)
+		}
+
+		_, err = w.Write(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.HasPrefix(buf.Bytes(), test.magic) {
+			t.Errorf("Unexpected %q state header %x", test.compression, buf.Bytes()[:4])
+		}
+
+		if test.compression != "none" && buf.Len() >= len(state) {
+			t.Errorf("The %q state wasn't compressed (%d bytes)", test.compression, buf.Len())
+		}
+
+		// The algorithm is detected when reading the state back.
+		r, err := stateDecompressionReader(buf)
+		if err != nil {
+			t.Fatalf("Failed creating %q reader: %v", test.compression, err)
+		}
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = r.Close()
+
+		if !bytes.Equal(content, state) {
+			t.Errorf("The %q state wasn't restored", test.compression)
+		}
+	}
+}
+
+func TestStateCompressionInvalidLevel(t *testing.T) {
+	d := &qemu{common: common{expandedConfig: map[string]string{"migration.stateful.compression_level": "fast"}}}
+
+	_, err := d.stateCompressionWriter(io.Discard)
+	if err == nil {
+		t.Error("Expected an invalid compression level to be rejected")
+	}
+}
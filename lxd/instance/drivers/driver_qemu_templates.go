@@ -345,11 +345,17 @@ type qemuSevOpts struct {
 	policy          string
 	dhCertFD        string
 	sessionDataFD   string
+	snp             bool
 }
 
 func qemuSEV(opts *qemuSevOpts) []cfgSection {
+	qomType := "sev-guest"
+	if opts.snp {
+		qomType = "sev-snp-guest"
+	}
+
 	entries := []cfgEntry{
-		{key: "qom-type", value: "sev-guest"},
+		{key: "qom-type", value: qomType},
 		{key: "cbitpos", value: strconv.Itoa(opts.cbitpos)},
 		{key: "reduced-phys-bits", value: strconv.Itoa(opts.reducedPhysBits)},
 		{key: "policy", value: opts.policy},
@@ -366,6 +372,25 @@ func qemuSEV(opts *qemuSevOpts) []cfgSection {
 	}}
 }
 
+type qemuTDXOpts struct {
+	mrConfigID    string
+	mrOwner       string
+	mrOwnerConfig string
+}
+
+func qemuTDX(opts *qemuTDXOpts) []cfgSection {
+	return []cfgSection{{
+		name:    `object "tdx0"`,
+		comment: "Trust Domain Extensions",
+		entries: []cfgEntry{
+			{key: "qom-type", value: "tdx-guest"},
+			{key: "mrconfigid", value: opts.mrConfigID},
+			{key: "mrowner", value: opts.mrOwner},
+			{key: "mrownerconfig", value: opts.mrOwnerConfig},
+		},
+	}}
+}
+
 type qemuVsockOpts struct {
 	dev     qemuDevOpts
 	vsockFD int
@@ -454,6 +479,7 @@ type qemuCPUOpts struct {
 	architecture        string
 	cpuCount            int
 	cpuRequested        int
+	cpuMax              int
 	cpuSockets          int
 	cpuCores            int
 	cpuThreads          int
@@ -523,6 +549,11 @@ func qemuCPU(opts *qemuCPUOpts, pinning bool) []cfgSection {
 			maxCPU = opts.cpuCount
 		}
 
+		// Use the configured hotplug maximum if set.
+		if opts.cpuMax > 0 {
+			maxCPU = opts.cpuMax
+		}
+
 		entries = append(entries, cfgEntry{
 			key: "maxcpus", value: strconv.Itoa(maxCPU),
 		})
@@ -612,19 +643,26 @@ func qemuControlSocket(opts *qemuControlSocketOpts) []cfgSection {
 }
 
 type qemuConsoleOpts struct {
-	path string
+	path    string
+	logPath string
 }
 
 func qemuConsole(opts *qemuConsoleOpts) []cfgSection {
+	entries := []cfgEntry{
+		{key: "backend", value: "socket"},
+		{key: "path", value: opts.path},
+		{key: "server", value: "on"},
+		{key: "wait", value: "off"},
+	}
+
+	if opts.logPath != "" {
+		entries = append(entries, cfgEntry{key: "logfile", value: opts.logPath}, cfgEntry{key: "logappend", value: "on"})
+	}
+
 	return []cfgSection{{
 		name:    `chardev "console"`,
 		comment: "Console",
-		entries: []cfgEntry{
-			{key: "backend", value: "socket"},
-			{key: "path", value: opts.path},
-			{key: "server", value: "on"},
-			{key: "wait", value: "off"},
-		},
+		entries: entries,
 	}}
 }
 
@@ -922,6 +960,41 @@ func qemuTPM(opts *qemuTPMOpts) []cfgSection {
 	}}
 }
 
+type qemuWatchdogOpts struct {
+	dev     qemuDevOpts
+	devName string
+	model   string
+}
+
+func qemuWatchdog(opts *qemuWatchdogOpts) []cfgSection {
+	comment := fmt.Sprintf(`Watchdog ("%s" device)`, opts.devName)
+
+	// The iTCO watchdog is built into the ICH9 LPC bridge and only needs to be allowed to reset the VM.
+	if opts.model == "itco" {
+		return []cfgSection{{
+			name:    "global",
+			comment: comment,
+			entries: []cfgEntry{
+				{key: "driver", value: "ICH9-LPC"},
+				{key: "property", value: "noreboot"},
+				{key: "value", value: "off"},
+			},
+		}}
+	}
+
+	deviceOpts := qemuDevEntriesOpts{
+		dev:     opts.dev,
+		pciName: "i6300esb",
+	}
+
+	return []cfgSection{{
+		// Devices use "lxd_" prefix indicating that this is a user named device.
+		name:    `device "` + qemuDeviceNameOrID(qemuDeviceIDPrefix, opts.devName, "", qemuDeviceIDMaxLength) + `"`,
+		comment: comment,
+		entries: qemuDeviceEntries(&deviceOpts),
+	}}
+}
+
 type qemuVmgenIDOpts struct {
 	guid string
 }
@@ -33,6 +33,23 @@ type AddFdInfo struct {
 	FD int `json:"fd"`
 }
 
+// MigrationRAMStats contains the RAM transfer statistics of a migration job.
+type MigrationRAMStats struct {
+	Transferred    int64   `json:"transferred"`
+	Remaining      int64   `json:"remaining"`
+	Total          int64   `json:"total"`
+	DirtyPagesRate int64   `json:"dirty-pages-rate"`
+	DirtySyncCount int64   `json:"dirty-sync-count"`
+	Mbps           float64 `json:"mbps"`
+}
+
+// MigrationInfo contains information about a migration job.
+type MigrationInfo struct {
+	Status           string            `json:"status"`
+	RAM              MigrationRAMStats `json:"ram"`
+	ExpectedDowntime int64             `json:"expected-downtime"`
+}
+
 // CPUInstanceProperties contains CPU instance properties.
 type CPUInstanceProperties struct {
 	NodeID    int `json:"node-id,omitempty"`
@@ -292,32 +309,70 @@ func (m *Monitor) Migrate(uri string) error {
 	return nil
 }
 
+// MigrateSetParameters sets the parameters used during migration (such as "max-bandwidth" or "downtime-limit").
+func (m *Monitor) MigrateSetParameters(params map[string]any) error {
+	err := m.run("migrate-set-parameters", params, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MigrateStartPostcopy switches a running migration job to post-copy mode.
+// This requires the "postcopy-ram" capability to have been enabled on both ends.
+func (m *Monitor) MigrateStartPostcopy() error {
+	return m.run("migrate-start-postcopy", nil, nil)
+}
+
+// QueryMigrate returns the status and statistics of the current migration job.
+func (m *Monitor) QueryMigrate() (*MigrationInfo, error) {
+	var resp struct {
+		Return MigrationInfo `json:"return"`
+	}
+
+	err := m.run("query-migrate", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp.Return, nil
+}
+
 // MigrateWait waits until migration job reaches the specified status.
 // Returns nil if the migraton job reaches the specified status or an error if the migration job is in the failed
 // status.
 func (m *Monitor) MigrateWait(state string) error {
+	return m.MigrateWaitProgress(state, nil)
+}
+
+// MigrateWaitProgress waits until migration job reaches the specified status, calling the progress function (if
+// not nil) with the current migration information on every poll.
+// Returns nil if the migraton job reaches the specified status or an error if the migration job is in the failed
+// status or if the progress function returns an error.
+func (m *Monitor) MigrateWaitProgress(state string, progress func(info *MigrationInfo) error) error {
 	// Wait until it completes or fails.
 	for {
-		// Prepare the response.
-		var resp struct {
-			Return struct {
-				Status string `json:"status"`
-			} `json:"return"`
-		}
-
-		err := m.run("query-migrate", nil, &resp)
+		info, err := m.QueryMigrate()
 		if err != nil {
 			return err
 		}
 
-		if resp.Return.Status == "failed" {
+		if info.Status == "failed" {
 			return errors.New("Migrate call failed")
 		}
 
-		if resp.Return.Status == state {
+		if info.Status == state {
 			return nil
 		}
 
+		if progress != nil {
+			err = progress(info)
+			if err != nil {
+				return err
+			}
+		}
+
 		time.Sleep(1 * time.Second)
 	}
 }
@@ -466,6 +521,62 @@ func (m *Monitor) SetMemoryBalloonSizeBytes(sizeBytes int64) error {
 	return m.run("balloon", args, nil)
 }
 
+// MemoryBalloonStats contains the guest memory statistics reported through the balloon device.
+// Statistics which aren't reported by the guest are set to -1.
+type MemoryBalloonStats struct {
+	TotalMemory     int64 `json:"stat-total-memory"`
+	FreeMemory      int64 `json:"stat-free-memory"`
+	AvailableMemory int64 `json:"stat-available-memory"`
+	DiskCaches      int64 `json:"stat-disk-caches"`
+	SwapIn          int64 `json:"stat-swap-in"`
+	SwapOut         int64 `json:"stat-swap-out"`
+}
+
+// SetMemoryBalloonStatsInterval sets the interval in seconds at which the guest reports memory statistics
+// through the balloon device. An interval of 0 disables the statistics.
+func (m *Monitor) SetMemoryBalloonStatsInterval(device string, seconds int) error {
+	args := map[string]any{
+		"path":     "/machine/peripheral/" + device,
+		"property": "guest-stats-polling-interval",
+		"value":    seconds,
+	}
+
+	err := m.run("qom-set", args, nil)
+	if err != nil {
+		return fmt.Errorf("Failed setting balloon statistics interval: %w", err)
+	}
+
+	return nil
+}
+
+// GetMemoryBalloonStats returns the latest guest memory statistics reported through the balloon device.
+// It returns ErrMonitorNoBalloonStats if the guest didn't report any statistics yet.
+func (m *Monitor) GetMemoryBalloonStats(device string) (*MemoryBalloonStats, error) {
+	// Prepare the response.
+	var resp struct {
+		Return struct {
+			Stats      MemoryBalloonStats `json:"stats"`
+			LastUpdate int64              `json:"last-update"`
+		} `json:"return"`
+	}
+
+	args := map[string]string{
+		"path":     "/machine/peripheral/" + device,
+		"property": "guest-stats",
+	}
+
+	err := m.run("qom-get", args, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting balloon statistics: %w", err)
+	}
+
+	if resp.Return.LastUpdate == 0 {
+		return nil, ErrMonitorNoBalloonStats
+	}
+
+	return &resp.Return.Stats, nil
+}
+
 // AddBlockDevice adds a block device.
 func (m *Monitor) AddBlockDevice(blockDev map[string]any, device map[string]any) error {
 	revert := revert.New()
@@ -771,6 +882,50 @@ func (m *Monitor) SEVCapabilities() (AMDSEVCapabilities, error) {
 	return resp.Return, nil
 }
 
+// SEVInfo represents the SEV state of a running guest.
+type SEVInfo struct {
+	Enabled   bool   `json:"enabled"`    // Whether SEV is enabled for the guest
+	State     string `json:"state"`      // SEV guest state (e.g. "running")
+	SEVType   string `json:"sev-type"`   // SEV type ("sev" or "sev-snp")
+	Policy    uint32 `json:"policy"`     // SEV (and SEV-ES) guest policy
+	SNPPolicy uint64 `json:"snp-policy"` // SEV-SNP guest policy
+	BuildID   int    `json:"build-id"`   // Firmware build ID
+	APIMajor  int    `json:"api-major"`  // Firmware API major version
+	APIMinor  int    `json:"api-minor"`  // Firmware API minor version
+}
+
+// QuerySEV returns the SEV state of the guest.
+func (m *Monitor) QuerySEV() (*SEVInfo, error) {
+	// Prepare the response
+	var resp struct {
+		Return SEVInfo `json:"return"`
+	}
+
+	err := m.run("query-sev", nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed querying SEV state: %w", err)
+	}
+
+	return &resp.Return, nil
+}
+
+// SEVLaunchMeasure returns the (base64-encoded) launch measurement of a SEV or SEV-ES guest.
+func (m *Monitor) SEVLaunchMeasure() (string, error) {
+	// Prepare the response
+	var resp struct {
+		Return struct {
+			Data string `json:"data"`
+		} `json:"return"`
+	}
+
+	err := m.run("query-sev-launch-measure", nil, &resp)
+	if err != nil {
+		return "", fmt.Errorf("Failed querying SEV launch measurement: %w", err)
+	}
+
+	return resp.Return.Data, nil
+}
+
 // NBDServerStart starts internal NBD server and returns a connection to it.
 func (m *Monitor) NBDServerStart() (net.Conn, error) {
 	var args struct {
@@ -9,3 +9,6 @@ var ErrMonitorDisconnect = errors.New("Monitor is disconnected")
 
 // ErrMonitorBadConsole is retuned when the requested console doesn't exist.
 var ErrMonitorBadConsole = errors.New("Requested console couldn't be found")
+
+// ErrMonitorNoBalloonStats is returned when the guest didn't report memory statistics through the balloon device.
+var ErrMonitorNoBalloonStats = errors.New("No memory statistics reported by the guest")
@@ -29,6 +29,9 @@ var EventVMShutdown = "SHUTDOWN"
 // EventVMShutdownReasonDisconnect is used as the reason when the shutdown event is triggered by a QMP disconnect.
 var EventVMShutdownReasonDisconnect = "disconnect"
 
+// EventVMWatchdog is the event sent when the watchdog device of the VM expires.
+var EventVMWatchdog = "WATCHDOG"
+
 // Monitor represents a QMP monitor.
 type Monitor struct {
 	path string
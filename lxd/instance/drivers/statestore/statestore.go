@@ -0,0 +1,327 @@
+package statestore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// minChunkSize is the minimum size of a chunk (except for the last one).
+	minChunkSize = 256 * 1024
+
+	// maxChunkSize is the maximum size of a chunk.
+	maxChunkSize = 4 * 1024 * 1024
+
+	// chunkMask gives an average chunk size of about 1MiB (on top of the minimum size).
+	chunkMask = (1 << 20) - 1
+)
+
+// gearTable holds the pseudo-random values used by the rolling hash.
+var gearTable [256]uint64
+
+func init() {
+	// Fill the table deterministically (splitmix64) so that chunk boundaries are stable across runs.
+	seed := uint64(0x4c58445354415445)
+	for i := range gearTable {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gearTable[i] = z ^ (z >> 31)
+	}
+}
+
+// Manifest describes a state stored as a list of chunks.
+type Manifest struct {
+	// Size of the state in bytes.
+	Size int64 `json:"size"`
+
+	// Ordered list of chunk hashes making up the state.
+	Chunks []string `json:"chunks"`
+}
+
+// Stats describes the outcome of a Write.
+type Stats struct {
+	// Total number of chunks and bytes of the state.
+	Chunks int
+	Size   int64
+
+	// Number of chunks and bytes that weren't already present in the store.
+	NewChunks int
+	NewSize   int64
+}
+
+// Write splits the state read from r into content-defined chunks, stores the chunks missing from chunksDir and
+// writes the list of chunks to manifestPath. Chunks already present (from a previous state) are reused so only
+// the parts of the state that changed get written.
+func Write(r io.Reader, chunksDir string, manifestPath string) (*Stats, error) {
+	err := os.MkdirAll(chunksDir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating state chunks directory %q: %w", chunksDir, err)
+	}
+
+	manifest := Manifest{Chunks: []string{}}
+	stats := &Stats{}
+
+	reader := bufio.NewReaderSize(r, maxChunkSize)
+	buf := make([]byte, 0, maxChunkSize)
+
+	for {
+		buf, err = nextChunk(reader, buf[:0])
+		if len(buf) > 0 {
+			sum := sha256.Sum256(buf)
+			hash := hex.EncodeToString(sum[:])
+
+			created, err := writeChunk(chunksDir, hash, buf)
+			if err != nil {
+				return nil, err
+			}
+
+			manifest.Chunks = append(manifest.Chunks, hash)
+			manifest.Size += int64(len(buf))
+
+			stats.Chunks++
+			stats.Size += int64(len(buf))
+			if created {
+				stats.NewChunks++
+				stats.NewSize += int64(len(buf))
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading state: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(manifestPath, data, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing state manifest %q: %w", manifestPath, err)
+	}
+
+	return stats, nil
+}
+
+// nextChunk reads the next content-defined chunk into buf.
+func nextChunk(r *bufio.Reader, buf []byte) ([]byte, error) {
+	var hash uint64
+
+	for len(buf) < maxChunkSize {
+		b, err := r.ReadByte()
+		if err != nil {
+			return buf, err
+		}
+
+		buf = append(buf, b)
+		hash = (hash << 1) + gearTable[b]
+
+		if len(buf) >= minChunkSize && hash&chunkMask == 0 {
+			break
+		}
+	}
+
+	return buf, nil
+}
+
+// writeChunk stores the chunk (compressed) unless already present and returns whether it was created.
+func writeChunk(chunksDir string, hash string, data []byte) (bool, error) {
+	chunkPath := filepath.Join(chunksDir, hash)
+
+	_, err := os.Stat(chunkPath)
+	if err == nil {
+		return false, nil
+	}
+
+	f, err := os.CreateTemp(chunksDir, ".tmp-")
+	if err != nil {
+		return false, fmt.Errorf("Failed creating state chunk: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	compressed, err := gzip.NewWriterLevel(f, gzip.BestSpeed)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = compressed.Write(data)
+	if err != nil {
+		return false, fmt.Errorf("Failed writing state chunk %q: %w", hash, err)
+	}
+
+	err = compressed.Close()
+	if err != nil {
+		return false, fmt.Errorf("Failed writing state chunk %q: %w", hash, err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return false, fmt.Errorf("Failed writing state chunk %q: %w", hash, err)
+	}
+
+	err = os.Rename(f.Name(), chunkPath)
+	if err != nil {
+		return false, fmt.Errorf("Failed storing state chunk %q: %w", hash, err)
+	}
+
+	return true, nil
+}
+
+// LoadManifest reads the manifest at the given path.
+func LoadManifest(manifestPath string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing state manifest %q: %w", manifestPath, err)
+	}
+
+	return &manifest, nil
+}
+
+// Open returns a reader for the state described by the manifest at manifestPath.
+// The content of each chunk is verified against its hash as it is read.
+func Open(chunksDir string, manifestPath string) (io.ReadCloser, error) {
+	manifest, err := LoadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check all the chunks are present before starting.
+	for _, hash := range manifest.Chunks {
+		_, err := os.Stat(filepath.Join(chunksDir, hash))
+		if err != nil {
+			return nil, fmt.Errorf("Missing state chunk %q: %w", hash, err)
+		}
+	}
+
+	return &reader{chunksDir: chunksDir, chunks: manifest.Chunks}, nil
+}
+
+// reader reads a state chunk by chunk.
+type reader struct {
+	chunksDir string
+	chunks    []string
+	current   []byte
+}
+
+// Read implements io.Reader.
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if len(r.chunks) == 0 {
+			return 0, io.EOF
+		}
+
+		data, err := readChunk(r.chunksDir, r.chunks[0])
+		if err != nil {
+			return 0, err
+		}
+
+		r.current = data
+		r.chunks = r.chunks[1:]
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+
+	return n, nil
+}
+
+// Close implements io.Closer.
+func (r *reader) Close() error {
+	r.chunks = nil
+	r.current = nil
+
+	return nil
+}
+
+// readChunk returns the verified content of a chunk.
+func readChunk(chunksDir string, hash string) ([]byte, error) {
+	f, err := os.Open(filepath.Join(chunksDir, hash))
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening state chunk %q: %w", hash, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	uncompressed, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening state chunk %q: %w", hash, err)
+	}
+
+	data, err := io.ReadAll(uncompressed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading state chunk %q: %w", hash, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != hash {
+		return nil, fmt.Errorf("State chunk %q is corrupted", hash)
+	}
+
+	return data, nil
+}
+
+// Prune removes the chunks from chunksDir that aren't referenced by any of the given manifests.
+func Prune(chunksDir string, manifestPaths ...string) error {
+	keep := map[string]bool{}
+	for _, manifestPath := range manifestPaths {
+		manifest, err := LoadManifest(manifestPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return err
+		}
+
+		for _, hash := range manifest.Chunks {
+			keep[hash] = true
+		}
+	}
+
+	entries, err := os.ReadDir(chunksDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed listing state chunks in %q: %w", chunksDir, err)
+	}
+
+	for _, entry := range entries {
+		if keep[entry.Name()] {
+			continue
+		}
+
+		err := os.Remove(filepath.Join(chunksDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("Failed removing state chunk %q: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package statestore
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func randomState(t *testing.T, size int) []byte {
+	t.Helper()
+
+	data := make([]byte, size)
+	_, err := rand.New(rand.NewSource(1)).Read(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func readState(t *testing.T, chunksDir string, manifestPath string) []byte {
+	t.Helper()
+
+	r, err := Open(chunksDir, manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = r.Close() }()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestWriteOpen(t *testing.T) {
+	dir := t.TempDir()
+	chunksDir := filepath.Join(dir, "chunks")
+	state := randomState(t, 12*1024*1024)
+
+	stats, err := Write(bytes.NewReader(state), chunksDir, filepath.Join(dir, "state1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Size != int64(len(state)) || stats.NewChunks != stats.Chunks {
+		t.Fatalf("Unexpected stats for initial state: %+v", stats)
+	}
+
+	if !bytes.Equal(readState(t, chunksDir, filepath.Join(dir, "state1")), state) {
+		t.Fatal("Initial state doesn't match")
+	}
+
+	// Change a small part of the state, only the affected chunks should be written.
+	modified := bytes.Clone(state)
+	copy(modified[5*1024*1024:], bytes.Repeat([]byte{0xff}, 4096))
+
+	stats, err = Write(bytes.NewReader(modified), chunksDir, filepath.Join(dir, "state2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.NewChunks == 0 || stats.NewChunks > 2 {
+		t.Fatalf("Unexpected number of new chunks for modified state: %+v", stats)
+	}
+
+	if !bytes.Equal(readState(t, chunksDir, filepath.Join(dir, "state2")), modified) {
+		t.Fatal("Modified state doesn't match")
+	}
+
+	if !bytes.Equal(readState(t, chunksDir, filepath.Join(dir, "state1")), state) {
+		t.Fatal("Initial state doesn't match after writing the modified state")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	chunksDir := filepath.Join(dir, "chunks")
+	state := randomState(t, 4*1024*1024)
+
+	_, err := Write(bytes.NewReader(state), chunksDir, filepath.Join(dir, "state1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Write(bytes.NewReader(state[:1024*1024]), chunksDir, filepath.Join(dir, "state2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Prune(chunksDir, filepath.Join(dir, "state2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(readState(t, chunksDir, filepath.Join(dir, "state2")), state[:1024*1024]) {
+		t.Fatal("Remaining state doesn't match")
+	}
+
+	_, err = Open(chunksDir, filepath.Join(dir, "state1"))
+	if err == nil {
+		t.Fatal("Expected pruned state to be incomplete")
+	}
+
+	err = os.RemoveAll(chunksDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Prune(chunksDir, filepath.Join(dir, "state2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
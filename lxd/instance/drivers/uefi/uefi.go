@@ -2,6 +2,7 @@ package uefi
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -85,6 +86,66 @@ func Validate(e api.InstanceUEFIVars) error {
 		if len(name)+len(decodedData) > 0x8400 {
 			return fmt.Errorf("Bad UEFI variable key: %q", k)
 		}
+
+		err = validateVariableData(name, strings.ToLower(guid), decodedData)
+		if err != nil {
+			return fmt.Errorf("Bad UEFI variable (key: %q) data: %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+// EFI variable vendor GUIDs.
+const (
+	// GlobalVariableGUID is the vendor GUID of the architecturally defined variables (BootOrder, PK, KEK...).
+	GlobalVariableGUID = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+
+	// ImageSecurityDatabaseGUID is the vendor GUID of the Secure Boot signature databases (db, dbx).
+	ImageSecurityDatabaseGUID = "d719b2cb-3d3a-4596-a3bc-dad00e67656f"
+)
+
+// validateVariableData checks the content of the well-known variables that are commonly edited.
+func validateVariableData(name string, guid string, data []byte) error {
+	switch {
+	case guid == GlobalVariableGUID && (name == "BootOrder" || name == "BootNext"):
+		// A list of 16-bit boot option numbers.
+		if len(data)%2 != 0 {
+			return fmt.Errorf("%s must be a list of 16-bit boot option numbers", name)
+		}
+
+	case guid == GlobalVariableGUID && (name == "PK" || name == "KEK"), guid == ImageSecurityDatabaseGUID && (name == "db" || name == "dbx"):
+		return validateSignatureLists(data)
+	}
+
+	return nil
+}
+
+// validateSignatureLists checks that data is a sequence of EFI_SIGNATURE_LIST structures as used by the Secure Boot
+// key databases (PK, KEK, db and dbx).
+func validateSignatureLists(data []byte) error {
+	// EFI_SIGNATURE_LIST header: SignatureType (GUID), SignatureListSize, SignatureHeaderSize and SignatureSize.
+	const headerSize = 16 + 4 + 4 + 4
+
+	for len(data) > 0 {
+		if len(data) < headerSize {
+			return errors.New("Truncated EFI signature list")
+		}
+
+		listSize := binary.LittleEndian.Uint32(data[16:20])
+		sigHeaderSize := binary.LittleEndian.Uint32(data[20:24])
+		sigSize := binary.LittleEndian.Uint32(data[24:28])
+
+		if uint64(listSize) > uint64(len(data)) || uint64(listSize) < headerSize+uint64(sigHeaderSize) {
+			return fmt.Errorf("Invalid EFI signature list size %d", listSize)
+		}
+
+		// Each signature holds at least the owner GUID.
+		if sigSize <= 16 || (listSize-headerSize-sigHeaderSize)%sigSize != 0 {
+			return fmt.Errorf("Invalid EFI signature size %d", sigSize)
+		}
+
+		data = data[listSize:]
 	}
 
 	return nil
@@ -0,0 +1,66 @@
+package uefi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// signatureList returns an EFI_SIGNATURE_LIST holding count signatures of the given size.
+func signatureList(count uint32, sigSize uint32) []byte {
+	listSize := 28 + count*sigSize
+	data := make([]byte, listSize)
+	binary.LittleEndian.PutUint32(data[16:20], listSize)
+	binary.LittleEndian.PutUint32(data[24:28], sigSize)
+
+	return data
+}
+
+func TestValidate(t *testing.T) {
+	list := signatureList(2, 48)
+
+	badSize := signatureList(1, 48)
+	binary.LittleEndian.PutUint32(badSize[16:20], 1024)
+
+	badSigSize := signatureList(1, 48)
+	binary.LittleEndian.PutUint32(badSigSize[24:28], 10)
+
+	tests := []struct {
+		name     string
+		key      string
+		data     []byte
+		expected string
+	}{
+		{name: "Boot order", key: "BootOrder-" + GlobalVariableGUID, data: []byte{0x01, 0x00, 0x00, 0x00}},
+		{name: "Odd boot order", key: "BootOrder-" + GlobalVariableGUID, data: []byte{0x01, 0x00, 0x00}, expected: "BootOrder must be a list of 16-bit boot option numbers"},
+		{name: "Platform key", key: "PK-" + GlobalVariableGUID, data: list},
+		{name: "Multiple signature lists", key: "db-" + ImageSecurityDatabaseGUID, data: append(signatureList(1, 48), list...)},
+		{name: "Upper case GUID", key: "KEK-8BE4DF61-93CA-11D2-AA0D-00E098032B8C", data: list},
+		{name: "Truncated signature list", key: "dbx-" + ImageSecurityDatabaseGUID, data: list[:20], expected: "Truncated EFI signature list"},
+		{name: "Invalid signature list size", key: "PK-" + GlobalVariableGUID, data: badSize, expected: "Invalid EFI signature list size 1024"},
+		{name: "Invalid signature size", key: "PK-" + GlobalVariableGUID, data: badSigSize, expected: "Invalid EFI signature size 10"},
+		{name: "Unknown variable", key: "Foo-" + GlobalVariableGUID, data: []byte{0x01}},
+		{name: "Invalid GUID", key: "BootOrder-8be4df61-93ca-11d2-aa0d-00e098032bzz", data: []byte{0x01, 0x00}, expected: "Bad UUID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := api.InstanceUEFIVars{
+				Variables: map[string]api.InstanceUEFIVariable{
+					tt.key: {Data: hex.EncodeToString(tt.data)},
+				},
+			}
+
+			err := Validate(vars)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expected)
+			}
+		})
+	}
+}
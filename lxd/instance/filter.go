@@ -1,6 +1,9 @@
 package instance
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/filter"
 )
@@ -23,3 +26,44 @@ func FilterFull(instances []*api.InstanceFull, clauses filter.ClauseSet) ([]*api
 
 	return filtered, nil
 }
+
+// labelShorthandRegexp matches the `label.<key>=<value>` filter shorthand.
+var labelShorthandRegexp = regexp.MustCompile(`^label\.([^=]+)=(.*)$`)
+
+// ParseFilter parses an instance list filter.
+// On top of the regular filter syntax, `label.<key>=<value>` can be used as a shorthand for `labels.<key> eq <value>`.
+func ParseFilter(s string) (*filter.ClauseSet, error) {
+	ops := filter.QueryOperatorSet()
+
+	parts := strings.Fields(s)
+	for i, part := range parts {
+		match := labelShorthandRegexp.FindStringSubmatch(part)
+		if match == nil {
+			continue
+		}
+
+		parts[i] = "labels." + match[1] + " " + ops.Equals + " " + match[2]
+	}
+
+	return filter.Parse(strings.Join(parts, " "), ops)
+}
+
+// LabelSelector returns the labels that instances must have to match the given clauses when these only select
+// instances by label equality (`labels.<key> eq <value>` joined with `and`). It returns nil otherwise.
+func LabelSelector(clauses filter.ClauseSet) map[string]string {
+	if len(clauses.Clauses) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(clauses.Clauses))
+	for _, clause := range clauses.Clauses {
+		key, found := strings.CutPrefix(clause.Field, "labels.")
+		if !found || clause.Not || clause.Operator != clauses.Ops.Equals || clause.PrevLogical != clauses.Ops.And {
+			return nil
+		}
+
+		labels[key] = clause.Value
+	}
+
+	return labels
+}
@@ -0,0 +1,30 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelSelector(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   map[string]string
+	}{
+		{filter: "label.env=prod", want: map[string]string{"env": "prod"}},
+		{filter: "label.env=prod and labels.tier eq web", want: map[string]string{"env": "prod", "tier": "web"}},
+		{filter: "label.env=prod or label.env=dev", want: nil},
+		{filter: "not label.env=prod", want: nil},
+		{filter: "label.env=prod and status eq Running", want: nil},
+		{filter: "labels.env ne prod", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			clauses, err := ParseFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, LabelSelector(*clauses))
+		})
+	}
+}
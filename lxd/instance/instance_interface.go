@@ -116,6 +116,8 @@ type Instance interface {
 
 	// Console - Allocate and run a console tty or a spice Unix socket.
 	Console(protocol string) (*os.File, chan error, error)
+	ConsoleHistory() ([]byte, error)
+	ConsoleHistoryClear() error
 	Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (Cmd, error)
 
 	// Status
@@ -138,6 +140,7 @@ type Instance interface {
 	Name() string
 	CloudInitID() string
 	Description() string
+	Labels() map[string]string
 	CreationDate() time.Time
 	LastUsedDate() time.Time
 
@@ -150,6 +153,7 @@ type Instance interface {
 	// Paths.
 	Path() string
 	ExecOutputPath() string
+	SessionsPath() string
 	RootfsPath() string
 	TemplatesPath() string
 	StatePath() string
@@ -189,6 +193,12 @@ type Container interface {
 	InsertSeccompUnixDevice(prefix string, m deviceConfig.Device, pid int) error
 	DevptsFd() (*os.File, error)
 	IdmappedStorage(path string, fstype string) idmap.IdmapStorageType
+
+	// Checkpoints.
+	Checkpoint(stop bool) error
+	CheckpointExport(w io.Writer) error
+	CheckpointImport(r io.Reader) error
+	CheckpointDelete() error
 }
 
 // VM interface is for VM specific functions.
@@ -202,6 +212,17 @@ type VM interface {
 	// UEFI vars handling.
 	UEFIVars() (*api.InstanceUEFIVars, error)
 	UEFIVarsUpdate(newUEFIVarsSet api.InstanceUEFIVars) error
+	UEFIVarsReset() error
+
+	// Confidential computing.
+	Attestation(nonce []byte) (*api.InstanceAttestation, error)
+
+	// File access through the root disk of the stopped VM.
+	DiskFileSFTPConn(writable bool) (net.Conn, error)
+	DiskFileSFTP(writable bool) (*sftp.Client, error)
+
+	// Memory ballooning.
+	MemoryBalloonAdjust() error
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
@@ -243,6 +264,7 @@ type MigrateSendArgs struct {
 	MigrateArgs
 
 	AllowInconsistent bool
+	MigrationConfig   map[string]string // Overrides of the instance's live migration configuration keys.
 }
 
 // MigrateReceiveArgs represent arguments for instance migration receive.
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -36,6 +37,7 @@ import (
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/osarch"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
 )
@@ -50,6 +52,37 @@ var Load func(s *state.State, args db.InstanceArgs, p api.Project) (Instance, er
 // Returns a revert fail function that can be used to undo this function if a subsequent step fails.
 var Create func(s *state.State, args db.InstanceArgs, p api.Project) (Instance, revert.Hook, error)
 
+// labelRegexp matches the valid label keys and values: up to 63 alphanumeric characters, dashes, underscores and
+// dots, starting and ending with an alphanumeric character.
+var labelRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]{0,61}[a-zA-Z0-9])?$`)
+
+// ValidLabels validates an instance's labels.
+func ValidLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if !labelRegexp.MatchString(key) {
+			return fmt.Errorf("Invalid label key %q", key)
+		}
+
+		if !labelRegexp.MatchString(value) {
+			return fmt.Errorf("Invalid value %q for label %q", value, key)
+		}
+	}
+
+	return nil
+}
+
+// MatchLabels returns true if the instance has all the labels of the selector.
+func MatchLabels(inst Instance, selector map[string]string) bool {
+	labels := inst.Labels()
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ValidConfig validates an instance's config.
 func ValidConfig(sysOS *sys.OS, config map[string]string, expanded bool, instanceType instancetype.Type) error {
 	if config == nil {
@@ -124,6 +157,56 @@ func ValidConfig(sysOS *sys.OS, config map[string]string, expanded bool, instanc
 		return errors.New(`CPU pinning specified, but pinning strategy is set to "auto"`)
 	}
 
+	// Validate the vCPU count against the hotplug maximum.
+	if expanded && config["limits.cpu.hotplug_max"] != "" {
+		cpuCount, err := strconv.Atoi(cpuLimit)
+		if err != nil && cpuLimit != "" {
+			return errors.New("limits.cpu.hotplug_max cannot be used with CPU pinning")
+		}
+
+		cpuMax, err := strconv.Atoi(config["limits.cpu.hotplug_max"])
+		if err != nil {
+			return err
+		}
+
+		if cpuCount > cpuMax {
+			return fmt.Errorf("limits.cpu (%d) cannot be higher than limits.cpu.hotplug_max (%d)", cpuCount, cpuMax)
+		}
+	}
+
+	if instanceType == instancetype.Container && config["limits.cpu.nodes"] == "balanced" {
+		return errors.New(`limits.cpu.nodes can only be set to "balanced" for virtual machines`)
+	}
+
+	// Validate the automatic memory balloon policy.
+	if expanded && config["limits.memory.balloon"] == "auto" {
+		if shared.IsTrue(config["limits.memory.hugepages"]) {
+			return errors.New("limits.memory.balloon cannot be set to auto when using huge pages")
+		}
+
+		if shared.IsFalse(config["security.agent.metrics"]) {
+			return errors.New("limits.memory.balloon cannot be set to auto when security.agent.metrics is disabled")
+		}
+	}
+
+	// Percentage based memory limits are only resolved when the instance starts.
+	memoryLimit := config["limits.memory"]
+	if expanded && config["limits.memory.min"] != "" && memoryLimit != "" && !strings.HasSuffix(memoryLimit, "%") {
+		memoryMin, err := units.ParseByteSizeString(config["limits.memory.min"])
+		if err != nil {
+			return err
+		}
+
+		memoryMax, err := units.ParseByteSizeString(memoryLimit)
+		if err != nil {
+			return err
+		}
+
+		if memoryMin > memoryMax {
+			return fmt.Errorf("limits.memory.min (%s) cannot be higher than limits.memory (%s)", config["limits.memory.min"], memoryLimit)
+		}
+	}
+
 	return nil
 }
 
@@ -742,6 +825,12 @@ func CreateInternal(s *state.State, args db.InstanceArgs, clearLogDir bool) (Ins
 		return nil, nil, nil, err
 	}
 
+	// Validate instance labels.
+	err = ValidLabels(args.Labels)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Leave validating devices to Create function call below.
 
 	// Validate architecture.
@@ -900,6 +989,11 @@ func CreateInternal(s *state.State, args db.InstanceArgs, clearLogDir bool) (Ins
 			return err
 		}
 
+		err = cluster.CreateInstanceLabels(ctx, tx.Tx(), instanceID, args.Labels)
+		if err != nil {
+			return err
+		}
+
 		profileNames := make([]string, 0, len(args.Profiles))
 		for _, profile := range args.Profiles {
 			profileNames = append(profileNames, profile.Name)
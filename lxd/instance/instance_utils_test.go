@@ -5,6 +5,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/idmap"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/sys"
 )
 
 func Test_lxcParseRawLXC(t *testing.T) {
@@ -139,6 +143,118 @@ lxc.log.file = "/dev/null"`,
 	assert.Error(t, err, "Expected error for unprivileged mapping with LXD_UNPRIVILEGED_ONLY set")
 }
 
+func Test_ValidConfigCPUHotplug(t *testing.T) {
+	sysOS := &sys.OS{IdmapSet: &idmap.IdmapSet{}}
+
+	tests := []struct {
+		name      string
+		config    map[string]string
+		expectErr bool
+	}{
+		{
+			name:   "No hotplug maximum",
+			config: map[string]string{"limits.cpu": "4"},
+		},
+		{
+			name:   "Below the hotplug maximum",
+			config: map[string]string{"limits.cpu": "2", "limits.cpu.hotplug_max": "8"},
+		},
+		{
+			name:   "At the hotplug maximum",
+			config: map[string]string{"limits.cpu": "8", "limits.cpu.hotplug_max": "8"},
+		},
+		{
+			name:   "Default CPU count",
+			config: map[string]string{"limits.cpu.hotplug_max": "8"},
+		},
+		{
+			name:      "Above the hotplug maximum",
+			config:    map[string]string{"limits.cpu": "16", "limits.cpu.hotplug_max": "8"},
+			expectErr: true,
+		},
+		{
+			name:      "CPU pinning",
+			config:    map[string]string{"limits.cpu": "0-3", "limits.cpu.hotplug_max": "8"},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid hotplug maximum",
+			config:    map[string]string{"limits.cpu": "2", "limits.cpu.hotplug_max": "many"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidConfig(sysOS, test.config, true, instancetype.VM)
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ValidConfigBalancedCPUNodes(t *testing.T) {
+	sysOS := &sys.OS{IdmapSet: &idmap.IdmapSet{}}
+
+	assert.NoError(t, ValidConfig(sysOS, map[string]string{"limits.cpu.nodes": "balanced"}, true, instancetype.VM))
+	assert.NoError(t, ValidConfig(sysOS, map[string]string{"limits.cpu.nodes": "0-1"}, true, instancetype.Container))
+	assert.Error(t, ValidConfig(sysOS, map[string]string{"limits.cpu.nodes": "balanced"}, true, instancetype.Container))
+	assert.Error(t, ValidConfig(sysOS, map[string]string{"limits.cpu.nodes": "spread"}, true, instancetype.VM))
+}
+
+func Test_ValidConfigMemoryBalloon(t *testing.T) {
+	sysOS := &sys.OS{IdmapSet: &idmap.IdmapSet{}}
+
+	tests := []struct {
+		name      string
+		config    map[string]string
+		expectErr bool
+	}{
+		{
+			name:   "Automatic balloon",
+			config: map[string]string{"limits.memory": "4GiB", "limits.memory.min": "1GiB", "limits.memory.balloon": "auto"},
+		},
+		{
+			name:   "Minimum memory with a percentage limit",
+			config: map[string]string{"limits.memory": "50%", "limits.memory.min": "1GiB"},
+		},
+		{
+			name:      "Minimum memory above the limit",
+			config:    map[string]string{"limits.memory": "1GiB", "limits.memory.min": "2GiB"},
+			expectErr: true,
+		},
+		{
+			name:      "Automatic balloon with huge pages",
+			config:    map[string]string{"limits.memory.balloon": "auto", "limits.memory.hugepages": "true"},
+			expectErr: true,
+		},
+		{
+			name:      "Automatic balloon without agent metrics",
+			config:    map[string]string{"limits.memory.balloon": "auto", "security.agent.metrics": "false"},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid balloon policy",
+			config:    map[string]string{"limits.memory.balloon": "always"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidConfig(sysOS, test.config, true, instancetype.VM)
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func Test_DeviceNextInterfaceHWAddr(t *testing.T) {
 	mac1, err := DeviceNextInterfaceHWAddr()
 	if err != nil {
@@ -76,6 +76,20 @@ func ValidSnapName(snapshotName string) error {
 	return nil
 }
 
+// ValidHookName validates the name of a host-side lifecycle hook.
+// Hooks are referenced by their file name within the hooks directory of LXD.
+func ValidHookName(hookName string) error {
+	if hookName == "" {
+		return errors.New("Invalid hook name, cannot be empty")
+	}
+
+	if strings.HasPrefix(hookName, ".") || !shared.IsFileName(hookName) {
+		return fmt.Errorf("Invalid hook name %q: Must be a file name without a leading dot", hookName)
+	}
+
+	return nil
+}
+
 // ErrNoRootDisk means there is no root disk device found.
 var ErrNoRootDisk = errors.New("No root device could be found")
 
@@ -111,6 +125,42 @@ var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.autorestart)
+	// Possible values are:
+	//
+	// - `no`: Don't restart the instance automatically.
+	// - `on-failure`: Restart the instance when it stops unexpectedly.
+	// - `always`: Restart the instance whenever it stops without being requested through LXD (including when it is shut down from within).
+	//
+	// For virtual machines, a shutdown from within the guest isn't considered a failure, but a crash of the guest or of the QEMU process is.
+	// For containers, LXD can't tell a clean shutdown from a crash, so any stop that wasn't requested through LXD is considered a failure.
+	// ---
+	//  type: string
+	//  defaultdesc: `no`
+	//  liveupdate: yes
+	//  shortdesc: Whether to restart the instance when it stops unexpectedly
+	"boot.autorestart": validate.Optional(validate.IsOneOf("no", "on-failure", "always")),
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.autorestart.backoff)
+	// The number of seconds to wait before the first automatic restart attempt.
+	// The delay doubles with each consecutive attempt, up to 300 seconds.
+	// ---
+	//  type: integer
+	//  defaultdesc: `5`
+	//  liveupdate: yes
+	//  shortdesc: Delay before restarting the instance
+	"boot.autorestart.backoff": validate.Optional(validate.IsUint32),
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.autorestart.max_retries)
+	// The maximum number of automatic restarts since the instance was last started through LXD.
+	// The counter is reset when the instance is started or restarted through the API.
+	// ---
+	//  type: integer
+	//  defaultdesc: `10`
+	//  liveupdate: yes
+	//  shortdesc: Maximum number of automatic restarts
+	"boot.autorestart.max_retries": validate.Optional(validate.IsUint32),
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.autostart)
 	// If set to `true`, the instance will always be auto-started, unless `security.protection.start` is also enabled.
 	// If set to `false`, the instance will not be started on LXD start up.
@@ -122,7 +172,7 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	"boot.autostart": validate.Optional(validate.IsBool),
 
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.autostart.delay)
-	// The number of seconds to wait after the instance started before starting the next one.
+	// The number of seconds to wait after the instance started before starting the instances with a lower {config:option}`instance-boot:boot.autostart.priority`.
 	// ---
 	//  type: integer
 	//  defaultdesc: `0`
@@ -132,6 +182,7 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.autostart.priority)
 	// The instance with the highest value is started first.
+	// Instances with the same priority are started in parallel, see {ref}`instances-autostart`.
 	// ---
 	//  type: integer
 	//  defaultdesc: `0`
@@ -139,6 +190,30 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.depends_on)
+	// Specify a comma-separated list of instances of the same project and cluster member that must be started before this instance when LXD starts.
+	// Append `:ready` to an instance name to wait for that instance to report that it is ready through the devLXD API instead of only waiting for it to be running (for example, `db:ready,cache`).
+	//
+	// Dependencies take precedence over {config:option}`instance-boot:boot.autostart.priority`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Instances to start before this instance
+	"boot.depends_on": func(value string) error {
+		_, err := ParseBootDependencies(value)
+		return err
+	},
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.depends_on.timeout)
+	// The number of seconds to wait for each dependency listed in {config:option}`instance-boot:boot.depends_on` to be running (or ready).
+	// If a dependency isn't running (or ready) in time, the instance is started anyway.
+	// ---
+	//  type: integer
+	//  defaultdesc: `60`
+	//  liveupdate: no
+	//  shortdesc: How long to wait for each dependency
+	"boot.depends_on.timeout": validate.Optional(validate.IsUint32),
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -214,6 +289,25 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  condition: If supported by image
 	//  shortdesc: Legacy version of `cloud-init.vendor-data`
 
+	// lxdmeta:generate(entities=instance; group=hooks; key=hooks.pre_start)
+	// Specify a comma-separated list of hooks to run on the host, in order, before the instance is started.
+	// If a hook fails, the instance isn't started.
+	// See {ref}`instance-options-hooks` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Host-side hooks to run before starting the instance
+	"hooks.pre_start": validate.Optional(validate.IsListOf(ValidHookName)),
+
+	// lxdmeta:generate(entities=instance; group=hooks; key=hooks.post_stop)
+	// Specify a comma-separated list of hooks to run on the host, in order, after the instance has stopped.
+	// See {ref}`instance-options-hooks` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Host-side hooks to run after the instance stopped
+	"hooks.post_stop": validate.Optional(validate.IsListOf(ValidHookName)),
+
 	// lxdmeta:generate(entities=instance; group=miscellaneous; key=cluster.evacuate)
 	// The `cluster.evacuate` provides control over how instances are handled when a cluster member is being evacuated.
 	//
@@ -224,6 +318,8 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//   - `live-migrate`: Instances are live-migrated to another node. This means the instance remains running and operational during the migration process, ensuring minimal disruption.
 	//   - `migrate`: In this mode, instances are migrated to another node in the cluster. The migration process will not be live, meaning there will be a brief downtime for the instance during the migration.
 	//   -  `stop`: Instances are not migrated. Instead, they are stopped on the current node.
+	//   - `stateful-stop`: Instances are not migrated. Instead, they are stopped statefully on the current node and resumed when the node is restored.
+	//   - `skip`: Instances are left running on the current node.
 	//
 	// See {ref}`cluster-evacuate` for more information.
 	// ---
@@ -231,7 +327,40 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  defaultdesc: `auto`
 	//  liveupdate: no
 	//  shortdesc: What to do when evacuating the instance
-	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop")),
+	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "skip")),
+
+	// lxdmeta:generate(entities=instance; group=placement; key=placement.anti_affinity)
+	// Specify a comma-separated list of label keys.
+	// The instance is never placed on a cluster member that runs another instance of the same project with the same value for any of these labels.
+	//
+	// See {ref}`clustering-instance-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Labels of the instances not to co-locate the instance with
+	"placement.anti_affinity": validate.Optional(validate.IsListOf(validate.IsNotEmpty)),
+
+	// lxdmeta:generate(entities=instance; group=placement; key=placement.preferred_group)
+	// When automatically placing the instance, the members of this cluster group are preferred over the other members.
+	// The other members are only considered if none of the members of the group are suitable.
+	//
+	// See {ref}`clustering-instance-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Cluster group to prefer when placing the instance
+	"placement.preferred_group": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=placement; key=placement.spread)
+	// Specify a label key.
+	// The instances of the same project with the same value for this label are spread across the failure domains of the cluster: the instance is placed in the failure domain running the fewest of them.
+	//
+	// See {ref}`clustering-instance-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Label of the instances to spread the instance with across failure domains
+	"placement.spread": validate.IsAny,
 
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
@@ -246,13 +375,20 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu.nodes)
 	// A comma-separated list of NUMA node IDs or ranges to place the instance CPUs on.
+	// For virtual machines, this can also be set to `balanced` to have LXD pick the NUMA nodes when the instance starts.
 	//
-	// See {ref}`instance-options-limits-cpu-container` for more information.
+	// See {ref}`instance-options-limits-cpu-container` and {ref}`instance-options-limits-cpu-vm-numa` for more information.
 	// ---
 	//  type: string
 	//  liveupdate: yes
 	//  shortdesc: Which NUMA nodes to place the instance CPUs on
-	"limits.cpu.nodes": validate.Optional(validate.IsValidCPUSet),
+	"limits.cpu.nodes": validate.Optional(func(value string) error {
+		if value == "balanced" {
+			return nil
+		}
+
+		return validate.IsValidCPUSet(value)
+	}),
 
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.disk.priority)
 	// Controls how much priority to give to the instance's I/O requests when under load.
@@ -324,6 +460,18 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Raw idmap configuration
 	"raw.idmap": validate.IsAny,
 
+	// lxdmeta:generate(entities=instance; group=replication; key=replication.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable replication.
+	//
+	// The instance is replicated to the standby cluster configured through {config:option}`server-replication:replication.target.address`.
+	// See {ref}`disaster-recovery-standby` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: yes
+	//  shortdesc: Schedule for replicating the instance to the standby cluster
+	"replication.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
 	// lxdmeta:generate(entities=instance; group=security; key=security.devlxd)
 	// See {ref}`dev-lxd` for more information.
 	// ---
@@ -431,6 +579,13 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Template hook
 	"volatile.apply_template": validate.IsAny,
 
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.autorestart.count)
+	// The number of automatic restarts of the instance since it was last started through the API (see {config:option}`instance-boot:boot.autorestart`).
+	// ---
+	//  type: integer
+	//  shortdesc: Number of automatic restarts
+	"volatile.autorestart.count": validate.Optional(validate.IsUint32),
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.base_image)
 	// The hash of the image that the instance was created from (empty if the instance was not created from an image).
 	// ---
@@ -460,6 +615,38 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	"volatile.last_state.power": validate.IsAny,
 	"volatile.last_state.ready": validate.IsBool,
 	"volatile.apply_quota":      validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.regenerate_identity)
+	// Comma-separated list of identity items (`machine-id`, `ssh-host-keys` or `hostname`) that are regenerated upon next startup.
+	// This is set when copying an instance with identity regeneration.
+	// ---
+	//  type: string
+	//  shortdesc: Identity items to regenerate
+	"volatile.regenerate_identity": validate.Optional(validate.IsListOf(validate.IsOneOf(api.InstanceIdentityMachineID, api.InstanceIdentitySSHHostKeys, api.InstanceIdentityHostname))),
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.replication.last_error)
+	// The error of the last failed replication of the instance to the standby cluster (empty if the last replication succeeded).
+	// ---
+	//  type: string
+	//  shortdesc: Error of the last replication
+	"volatile.replication.last_error": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.replication.last_sync)
+	// On the primary cluster, the time of the last successful replication of the instance to the standby cluster.
+	// On the standby cluster, the time the replica was last updated.
+	// ---
+	//  type: string
+	//  shortdesc: Time of the last replication
+	"volatile.replication.last_sync": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.replication.standby)
+	// Whether the instance is a replica received from a primary cluster.
+	// A replica cannot be started until it is promoted.
+	// ---
+	//  type: bool
+	//  shortdesc: Whether the instance is a standby replica
+	"volatile.replication.standby": validate.Optional(validate.IsBool),
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.uuid)
 	// The instance UUID is globally unique across all servers and projects.
 	// ---
@@ -1047,6 +1234,42 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to back the instance using huge pages
 	"limits.memory.hugepages": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.memory.balloon)
+	// Possible values are `manual` (the memory balloon only follows {config:option}`instance-resource-limits:limits.memory`)
+	// and `auto` (LXD adjusts the memory balloon based on the memory pressure reported by the `lxd-agent`).
+	//
+	// See {ref}`instance-options-limits-memory-balloon` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: `manual`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Memory balloon policy
+	"limits.memory.balloon": validate.Optional(validate.IsOneOf("manual", "auto")),
+
+	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.memory.min)
+	// When {config:option}`instance-resource-limits:limits.memory.balloon` is set to `auto`, LXD never shrinks the memory of the instance below this value.
+	// ---
+	//  type: string
+	//  defaultdesc: 50% of `limits.memory`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Minimum amount of memory left to the instance by the memory balloon
+	"limits.memory.min": validate.Optional(validate.IsSize),
+
+	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu.hotplug_max)
+	// This sets the maximum number of vCPUs that can be hotplugged into the running virtual machine by increasing {config:option}`instance-resource-limits:limits.cpu`.
+	// It cannot be used together with CPU pinning.
+	//
+	// See {ref}`instance-options-limits-cpu-vm` for more information.
+	// ---
+	//  type: integer
+	//  defaultdesc: Number of host CPUs, capped to 64 (unless `limits.cpu` is higher)
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Maximum number of vCPUs that can be hotplugged
+	"limits.cpu.hotplug_max": validate.Optional(validate.IsUint32),
+
 	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.cpu.pin_strategy)
 	// Specify the strategy for VM CPU auto pinning.
 	// Possible values: `none` (disables CPU auto pinning) and `auto` (enables CPU auto pinning).
@@ -1070,6 +1293,85 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to allow for stateful stop/start and snapshots
 	"migration.stateful": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=migration; key=migration.stateful.compression)
+	// Possible values are `gzip`, `zstd` and `none`.
+	// This applies to the memory state written by stateful stop and (non-incremental) stateful snapshots.
+	// ---
+	//  type: string
+	//  defaultdesc: `gzip`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Compression algorithm of the saved memory state
+	"migration.stateful.compression": validate.Optional(validate.IsOneOf("gzip", "zstd", "none")),
+
+	// lxdmeta:generate(entities=instance; group=migration; key=migration.stateful.compression_level)
+	// Higher levels produce smaller state files but take longer to write.
+	// The level ranges from 1 to 9 for `gzip` and from 1 to 22 for `zstd` (where it's mapped to the closest level supported by LXD).
+	// ---
+	//  type: integer
+	//  defaultdesc: `1`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Compression level of the saved memory state
+	"migration.stateful.compression_level": validate.Optional(validate.IsInRange(1, 22)),
+
+	// lxdmeta:generate(entities=instance; group=migration; key=migration.bandwidth)
+	// Limits the bandwidth used to transfer the memory and device state of the instance during live migration.
+	// The value is given in bytes per second, with the supported units being the same as for storage sizes (for example, `100MiB`).
+	// ---
+	//  type: string
+	//  defaultdesc: QEMU default
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Maximum bandwidth per second used for live migration
+	"migration.bandwidth": validate.Optional(validate.IsSize),
+
+	// lxdmeta:generate(entities=instance; group=migration; key=migration.auto_converge)
+	// When enabled, the guest gets throttled down during live migration if its memory is being modified faster than it can be transferred.
+	// ---
+	//  type: bool
+	//  defaultdesc: `true`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to throttle the guest to help live migration converge
+	"migration.auto_converge": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=migration; key=migration.postcopy)
+	// When enabled, the instance is switched to the target after a first pass over its memory, and the remaining memory is then transferred on demand.
+	// This guarantees that the live migration completes, but the instance is lost if the connection between the source and the target fails before the transfer has completed.
+	//
+	// Post-copy migration requires `userfaultfd` support on the target.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to use post-copy live migration
+	"migration.postcopy": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=migration; key=migration.downtime_limit)
+	// Specify the maximum tolerated downtime (in milliseconds) when switching the instance over to the target during live migration.
+	// ---
+	//  type: integer
+	//  defaultdesc: `300`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Maximum downtime in milliseconds for live migration
+	"migration.downtime_limit": validate.Optional(validate.IsUint32),
+
+	// lxdmeta:generate(entities=instance; group=snapshots; key=snapshots.stateful.incremental)
+	// When enabled, stateful snapshots only store the parts of the memory that changed since the previous stateful snapshot.
+	// The memory state is split into chunks, and the chunks of the latest state are kept in the instance volume as the base for the next snapshot.
+	//
+	// This reduces the time and space needed for stateful snapshots of virtual machines with a lot of memory, at the cost of keeping a copy of the latest memory state in the instance volume.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to store incremental memory state in stateful snapshots
+	"snapshots.stateful.incremental": validate.Optional(validate.IsBool),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// lxdmeta:generate(entities=instance; group=raw; key=raw.qemu)
@@ -1140,6 +1442,34 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether AMD SEV-ES (SEV Encrypted State) is enabled for this VM
 	"security.sev.policy.es": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=security; key=security.sev.policy.snp)
+	// Requires {config:option}`instance-security:security.sev` to be enabled.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether AMD SEV-SNP (SEV Secure Nested Paging) is enabled for this VM
+	"security.sev.policy.snp": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=security; key=security.sev.snp.policy)
+	// The guest policy passed to the AMD secure processor when launching an SEV-SNP guest, as a hexadecimal value.
+	// See the AMD SEV-SNP firmware ABI specification for the meaning of each bit.
+	// ---
+	//  type: string
+	//  defaultdesc: `0x30000`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: SEV-SNP guest policy
+	"security.sev.snp.policy": validate.Optional(func(value string) error {
+		_, err := strconv.ParseUint(value, 0, 64)
+		if err != nil || !strings.HasPrefix(value, "0x") {
+			return fmt.Errorf("Invalid SEV-SNP policy %q, must be a hexadecimal value", value)
+		}
+
+		return nil
+	}),
+
 	// lxdmeta:generate(entities=instance; group=security; key=security.sev.session.dh)
 	//
 	// ---
@@ -1160,6 +1490,43 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: The guest owner's `base64`-encoded session blob
 	"security.sev.session.data": validate.IsAny,
 
+	// lxdmeta:generate(entities=instance; group=security; key=security.tdx)
+	// Requires a host with Intel TDX enabled and a firmware supporting TDX.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether Intel TDX (Trust Domain Extensions) is enabled for this VM
+	"security.tdx": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=security; key=security.tdx.mrconfigid)
+	// The `base64`-encoded SHA384 digest included in the TDX attestation reports to identify the guest configuration.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: TDX `MRCONFIGID` of the guest
+	"security.tdx.mrconfigid": validate.Optional(isTDXDigest),
+
+	// lxdmeta:generate(entities=instance; group=security; key=security.tdx.mrowner)
+	// The `base64`-encoded SHA384 digest included in the TDX attestation reports to identify the guest owner.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: TDX `MROWNER` of the guest
+	"security.tdx.mrowner": validate.Optional(isTDXDigest),
+
+	// lxdmeta:generate(entities=instance; group=security; key=security.tdx.mrownerconfig)
+	// The `base64`-encoded SHA384 digest included in the TDX attestation reports to identify the owner-defined configuration.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: TDX `MROWNERCONFIG` of the guest
+	"security.tdx.mrownerconfig": validate.Optional(isTDXDigest),
+
 	// lxdmeta:generate(entities=instance; group=miscellaneous; key=user.*)
 	// User keys can be used in search.
 	// ---
@@ -1177,6 +1544,17 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to use the name and MTU of the default network interfaces
 	"agent.nic_config": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=miscellaneous; key=windows.unattend)
+	// The content is written as `Autounattend.xml` on the Windows setup media (a disk device with `source=windows:setup`).
+	//
+	// See {ref}`instances-windows-setup` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Answer file for Windows setup
+	"windows.unattend": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.apply_nvram)
 	//
 	// ---
@@ -1184,6 +1562,27 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to regenerate VM NVRAM the next time the instance starts
 	"volatile.apply_nvram": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.rescue.device)
+	// The name of the device holding the rescue media while the instance is in rescue mode.
+	// ---
+	//  type: string
+	//  shortdesc: Rescue media device
+	"volatile.rescue.device": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.windows_setup.devices)
+	// The comma-separated names of the devices holding the Windows setup and VirtIO drivers media.
+	// ---
+	//  type: string
+	//  shortdesc: Windows setup media devices
+	"volatile.windows_setup.devices": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.cpu.nodes)
+	// The NUMA nodes picked by LXD when {config:option}`instance-resource-limits:limits.cpu.nodes` is set to `balanced`.
+	// ---
+	//  type: string
+	//  shortdesc: NUMA nodes used as of last start
+	"volatile.cpu.nodes": validate.Optional(validate.IsValidCPUSet),
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.vsock_id)
 	//
 	// ---
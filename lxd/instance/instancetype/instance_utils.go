@@ -1,10 +1,15 @@
 package instancetype
 
 import (
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
 	"maps"
 	"strconv"
+	"strings"
 
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 )
 
@@ -58,3 +63,55 @@ func ExpandInstanceDevices(devices deviceConfig.Devices, profiles []api.Profile)
 
 	return expandedDevices
 }
+
+// BootDependency represents an instance that must be started before another one (from `boot.depends_on`).
+type BootDependency struct {
+	// Name of the instance to depend on.
+	Name string
+
+	// Whether to wait for the instance to signal that it is ready (through the devLXD API) rather than only
+	// for it to be running.
+	Ready bool
+}
+
+// ParseBootDependencies parses the value of `boot.depends_on`.
+// Entries are instance names, optionally suffixed with `:ready` to wait for the instance to be ready.
+func ParseBootDependencies(value string) ([]BootDependency, error) {
+	dependencies := []BootDependency{}
+
+	for _, entry := range shared.SplitNTrimSpace(value, ",", -1, true) {
+		name, condition, found := strings.Cut(entry, ":")
+
+		dependency := BootDependency{Name: name}
+		if found {
+			if condition != "ready" {
+				return nil, fmt.Errorf("Invalid condition %q for dependency %q", condition, name)
+			}
+
+			dependency.Ready = true
+		}
+
+		err := ValidName(name, false)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid dependency %q: %w", name, err)
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, nil
+}
+
+// isTDXDigest validates a `base64`-encoded SHA384 digest as used for the TDX measurement registers.
+func isTDXDigest(value string) error {
+	digest, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("Invalid base64 value: %w", err)
+	}
+
+	if len(digest) != sha512.Size384 {
+		return fmt.Errorf("Invalid digest length %d, must be %d bytes (SHA384)", len(digest), sha512.Size384)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package instancetype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBootDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []BootDependency
+		wantErr  bool
+	}{
+		{name: "Empty", value: "", expected: []BootDependency{}},
+		{name: "Single instance", value: "db", expected: []BootDependency{{Name: "db"}}},
+		{name: "Ready condition", value: "db:ready", expected: []BootDependency{{Name: "db", Ready: true}}},
+		{name: "Multiple instances", value: "db:ready, cache", expected: []BootDependency{{Name: "db", Ready: true}, {Name: "cache"}}},
+		{name: "Unknown condition", value: "db:running", wantErr: true},
+		{name: "Invalid instance name", value: "db_1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps, err := ParseBootDependencies(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, deps)
+		})
+	}
+}
+
+func TestConfidentialComputingConfig(t *testing.T) {
+	digest := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWYwMTIzNDU2Nzg5YWJjZGVm"
+
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: "security.sev.policy.snp", value: "true"},
+		{key: "security.sev.policy.snp", value: "maybe", wantErr: true},
+		{key: "security.sev.snp.policy", value: "0x30000"},
+		{key: "security.sev.snp.policy", value: "196608", wantErr: true},
+		{key: "security.sev.snp.policy", value: "0xzz", wantErr: true},
+		{key: "security.tdx", value: "true"},
+		{key: "security.tdx.mrconfigid", value: digest},
+		{key: "security.tdx.mrowner", value: digest},
+		{key: "security.tdx.mrownerconfig", value: digest},
+		{key: "security.tdx.mrowner", value: "not base64", wantErr: true},
+		{key: "security.tdx.mrowner", value: "c2hvcnQ=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			checker, err := ConfigKeyChecker(tt.key, VM)
+			require.NoError(t, err)
+
+			err = checker(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
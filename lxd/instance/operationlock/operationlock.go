@@ -51,6 +51,7 @@ type InstanceOperation struct {
 	instanceName      string
 	reusable          bool
 	instanceInitiated bool
+	unrequested       bool
 }
 
 // Create creates a new operation lock for an Instance if one does not already exist and returns it.
@@ -223,3 +224,23 @@ func (op *InstanceOperation) GetInstanceInitiated() bool {
 
 	return op.instanceInitiated
 }
+
+// SetUnrequested sets the marker indicating that the operation wasn't requested through LXD.
+func (op *InstanceOperation) SetUnrequested(unrequested bool) {
+	// This function can be called on a nil struct.
+	if op == nil {
+		return
+	}
+
+	op.unrequested = unrequested
+}
+
+// GetUnrequested gets the marker indicating that the operation wasn't requested through LXD.
+func (op *InstanceOperation) GetUnrequested() bool {
+	// This function can be called on a nil struct.
+	if op == nil {
+		return false
+	}
+
+	return op.unrequested
+}
@@ -0,0 +1,115 @@
+package statehistory
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Store keeps the most recent resource usage samples of instances in memory.
+type Store struct {
+	mu    sync.Mutex
+	rings map[string]*ring
+}
+
+// ring is a fixed size circular buffer of samples.
+type ring struct {
+	samples []api.InstanceStateSample
+	next    int
+	full    bool
+}
+
+// NewStore returns a new empty Store.
+func NewStore() *Store {
+	return &Store{rings: map[string]*ring{}}
+}
+
+func storeKey(projectName string, instanceName string) string {
+	return projectName + "/" + instanceName
+}
+
+// Record adds a sample for the instance, keeping at most size samples (the oldest ones are discarded first).
+func (s *Store) Record(projectName string, instanceName string, sample api.InstanceStateSample, size int) {
+	if size <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := storeKey(projectName, instanceName)
+
+	r := s.rings[key]
+	if r == nil {
+		r = &ring{samples: make([]api.InstanceStateSample, size)}
+		s.rings[key] = r
+	} else if len(r.samples) != size {
+		r.resize(size)
+	}
+
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Samples returns the samples of the instance taken since the given time, oldest first.
+func (s *Store) Samples(projectName string, instanceName string, since time.Time) []api.InstanceStateSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := []api.InstanceStateSample{}
+
+	r := s.rings[storeKey(projectName, instanceName)]
+	if r == nil {
+		return samples
+	}
+
+	for _, sample := range r.ordered() {
+		if sample.Timestamp.Before(since) {
+			continue
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+// Retain removes the history of all the instances for which keep returns false.
+func (s *Store) Retain(keep func(projectName string, instanceName string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.rings {
+		projectName, instanceName, _ := strings.Cut(key, "/")
+		if !keep(projectName, instanceName) {
+			delete(s.rings, key)
+		}
+	}
+}
+
+// ordered returns the samples of the ring, oldest first.
+func (r *ring) ordered() []api.InstanceStateSample {
+	if !r.full {
+		return append([]api.InstanceStateSample(nil), r.samples[:r.next]...)
+	}
+
+	return append(append([]api.InstanceStateSample(nil), r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// resize changes the size of the ring, keeping the most recent samples.
+func (r *ring) resize(size int) {
+	samples := r.ordered()
+	if len(samples) > size {
+		samples = samples[len(samples)-size:]
+	}
+
+	r.samples = make([]api.InstanceStateSample, size)
+	copy(r.samples, samples)
+	r.next = len(samples) % size
+	r.full = len(samples) == size
+}
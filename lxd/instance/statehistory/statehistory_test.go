@@ -0,0 +1,45 @@
+package statehistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	start := time.Now()
+
+	for i := range 5 {
+		store.Record("default", "c1", api.InstanceStateSample{Timestamp: start.Add(time.Duration(i) * time.Minute), Processes: int64(i)}, 3)
+	}
+
+	samples := store.Samples("default", "c1", start)
+	if len(samples) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(samples))
+	}
+
+	for i, sample := range samples {
+		if sample.Processes != int64(i+2) {
+			t.Fatalf("Unexpected sample %d: %d", i, sample.Processes)
+		}
+	}
+
+	samples = store.Samples("default", "c1", start.Add(4*time.Minute))
+	if len(samples) != 1 || samples[0].Processes != 4 {
+		t.Fatalf("Unexpected samples: %v", samples)
+	}
+
+	// Shrinking keeps the most recent samples.
+	store.Record("default", "c1", api.InstanceStateSample{Timestamp: start.Add(5 * time.Minute), Processes: 5}, 2)
+	samples = store.Samples("default", "c1", start)
+	if len(samples) != 2 || samples[0].Processes != 4 || samples[1].Processes != 5 {
+		t.Fatalf("Unexpected samples after resize: %v", samples)
+	}
+
+	store.Retain(func(projectName string, instanceName string) bool { return instanceName != "c1" })
+	if len(store.Samples("default", "c1", start)) != 0 {
+		t.Fatal("Expected history to be removed")
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var instanceAttestationCmd = APIEndpoint{
+	Name:        "instanceAttestation",
+	Path:        "instances/{name}/attestation",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "vmAttestation", Path: "virtual-machines/{name}/attestation"},
+	},
+
+	Get: APIEndpointAction{Handler: instanceAttestationGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanView, "name")},
+}
+
+// swagger:operation GET /1.0/instances/{name}/attestation instances instance_attestation_get
+//
+//	Get the instance's attestation information
+//
+//	Gets the confidential computing (AMD SEV or Intel TDX) attestation information for a running VM.
+//	When a nonce is provided, an attestation report including it is generated within the guest (requires the LXD agent).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: nonce
+//	    description: Hex-encoded nonce (up to 64 bytes) to include in the attestation report
+//	    type: string
+//	    example: 0123456789abcdef
+//	responses:
+//	  "200":
+//	    description: Instance attestation information
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceAttestation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceAttestationGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	nonce, err := hex.DecodeString(r.FormValue("nonce"))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid nonce: %w", err))
+	}
+
+	if len(nonce) > 64 {
+		return response.BadRequest(errors.New("Nonce can't be longer than 64 bytes"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.VM {
+		return response.BadRequest(errors.New("Attestation is supported for VM type instances only"))
+	}
+
+	attestation, err := inst.(instance.VM).Attestation(nonce)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, attestation)
+}
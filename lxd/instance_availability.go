@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var instanceAvailabilityCmd = APIEndpoint{
+	Name:        "instanceAvailability",
+	Path:        "instances/{name}/availability",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerAvailability", Path: "containers/{name}/availability"},
+		{Name: "vmAvailability", Path: "virtual-machines/{name}/availability"},
+	},
+
+	Get: APIEndpointAction{Handler: instanceAvailabilityGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanView, "name")},
+}
+
+// instanceAvailabilityRetention is how long the state changes of the instances are kept for.
+const instanceAvailabilityRetention = 90 * 24 * time.Hour
+
+// instanceAvailabilityStatuses maps the lifecycle actions changing the status of an instance to the new status.
+var instanceAvailabilityStatuses = map[string]api.StatusCode{
+	api.EventLifecycleInstanceStarted:       api.Running,
+	api.EventLifecycleInstanceRestarted:     api.Running,
+	api.EventLifecycleInstanceAutoRestarted: api.Running,
+	api.EventLifecycleInstanceResumed:       api.Running,
+	api.EventLifecycleInstanceStopped:       api.Stopped,
+	api.EventLifecycleInstanceShutdown:      api.Stopped,
+	api.EventLifecycleInstancePaused:        api.Frozen,
+}
+
+// swagger:operation GET /1.0/instances/{name}/availability instances instance_availability_get
+//
+//	Get the availability
+//
+//	Gets the uptime and downtime of the instance over the requested period, along with its last state changes.
+//	The instance is considered down while it's stopped, frozen or in error. The time before the first recorded
+//	state change of the instance is reported as untracked.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: period
+//	    description: How far back to report the availability for (defaults to 24h)
+//	    type: string
+//	    example: 720h
+//	  - in: query
+//	    name: changes
+//	    description: Maximum number of state changes to return (defaults to 10)
+//	    type: integer
+//	    example: 20
+//	responses:
+//	  "200":
+//	    description: Instance availability
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceAvailability"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceAvailabilityGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	period := 24 * time.Hour
+	if r.FormValue("period") != "" {
+		period, err = time.ParseDuration(r.FormValue("period"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid period: %w", err))
+		}
+
+		if period <= 0 {
+			return response.BadRequest(errors.New("Period must be positive"))
+		}
+	}
+
+	changes := 10
+	if r.FormValue("changes") != "" {
+		changes, err = strconv.Atoi(r.FormValue("changes"))
+		if err != nil || changes < 0 {
+			return response.BadRequest(fmt.Errorf("Invalid number of changes %q", r.FormValue("changes")))
+		}
+	}
+
+	// The state changes are recorded by the member running the instance, but they're stored in the global
+	// database so the request doesn't need to be forwarded.
+	until := time.Now()
+	since := until.Add(-period)
+
+	var transitions []dbCluster.InstanceStateTransition
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		inst, err := dbCluster.GetInstance(ctx, tx.Tx(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		if instanceType != instancetype.Any && inst.Type != instanceType {
+			return api.StatusErrorf(http.StatusNotFound, "Instance not found")
+		}
+
+		transitions, err = dbCluster.GetInstanceStateTransitions(ctx, tx.Tx(), int64(inst.ID), since)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, instanceAvailability(transitions, since, until, changes))
+}
+
+// instanceAvailability computes the availability of an instance between since and until from its state changes,
+// given from the oldest to the newest and starting with the last change before since, if any.
+func instanceAvailability(transitions []dbCluster.InstanceStateTransition, since time.Time, until time.Time, changes int) api.InstanceAvailability {
+	availability := api.InstanceAvailability{
+		Since:   since,
+		Until:   until,
+		Changes: []api.InstanceStateChange{},
+	}
+
+	var uptime, downtime, untracked time.Duration
+	account := func(status string, duration time.Duration) {
+		switch status {
+		case "":
+			untracked += duration
+		case api.Running.String():
+			uptime += duration
+		default:
+			downtime += duration
+		}
+	}
+
+	cursor := since
+	status := ""
+	for _, t := range transitions {
+		start := t.Timestamp
+		if start.Before(since) {
+			start = since
+		}
+
+		account(status, start.Sub(cursor))
+		status = t.Status
+		cursor = start
+	}
+
+	account(status, until.Sub(cursor))
+
+	availability.Uptime = int64(uptime.Seconds())
+	availability.Downtime = int64(downtime.Seconds())
+	availability.Untracked = int64(untracked.Seconds())
+
+	tracked := uptime + downtime
+	if tracked > 0 {
+		availability.UptimePercentage = float64(uptime) * 100 / float64(tracked)
+		availability.DowntimePercentage = float64(downtime) * 100 / float64(tracked)
+	}
+
+	for i := len(transitions) - 1; i >= 0 && len(availability.Changes) < changes; i-- {
+		t := transitions[i]
+		if t.Timestamp.Before(since) {
+			break
+		}
+
+		availability.Changes = append(availability.Changes, api.InstanceStateChange{
+			Timestamp: t.Timestamp,
+			Status:    t.Status,
+			Action:    t.Action,
+		})
+	}
+
+	return availability
+}
+
+// setupInstanceAvailability starts recording the state changes of the instances of this member.
+func (d *Daemon) setupInstanceAvailability() {
+	d.internalListener.AddHandler("availability", func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle {
+			return
+		}
+
+		// The instances of the other members are recorded by the members themselves.
+		if event.Location != d.serverName {
+			return
+		}
+
+		lifecycle := api.EventLifecycle{}
+		err := json.Unmarshal(event.Metadata, &lifecycle)
+		if err != nil {
+			return
+		}
+
+		status, ok := instanceAvailabilityStatuses[lifecycle.Action]
+		if !ok || lifecycle.Name == "" {
+			return
+		}
+
+		transition := dbCluster.InstanceStateTransition{
+			Status:    status.String(),
+			Action:    lifecycle.Action,
+			Timestamp: event.Timestamp,
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return dbCluster.CreateInstanceStateTransition(ctx, tx.Tx(), event.Project, lifecycle.Name, transition)
+			})
+			if err != nil {
+				logger.Warn("Failed recording instance state change", logger.Ctx{"project": event.Project, "instance": lifecycle.Name, "err": err})
+			}
+		}()
+	})
+}
+
+// instanceAvailabilityTask records the status of the local instances whose last recorded status is outdated, such
+// as after a crash of the instance or of LXD, and removes the old state changes.
+func instanceAvailabilityTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Warn("Failed loading instances for availability", logger.Ctx{"err": err})
+			return
+		}
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Warn("Failed getting cluster leader for availability", logger.Ctx{"err": err})
+			return
+		}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			recorded, err := dbCluster.GetInstancesLastStatus(ctx, tx.Tx(), tx.GetNodeID())
+			if err != nil {
+				return err
+			}
+
+			for _, inst := range instances {
+				status := instanceAvailabilityStatus(inst)
+				if status == "" || recorded[inst.Project().Name][inst.Name()] == status {
+					continue
+				}
+
+				transition := dbCluster.InstanceStateTransition{
+					Status:    status,
+					Timestamp: time.Now(),
+				}
+
+				err = dbCluster.CreateInstanceStateTransition(ctx, tx.Tx(), inst.Project().Name, inst.Name(), transition)
+				if err != nil {
+					return err
+				}
+			}
+
+			if leaderInfo.Leader {
+				return dbCluster.DeleteInstanceStateTransitionsBefore(ctx, tx.Tx(), time.Now().Add(-instanceAvailabilityRetention))
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed recording instances availability", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
+
+// instanceAvailabilityStatus returns the current status of the instance, or an empty string if the instance is
+// in a transient status such as starting or stopping.
+func instanceAvailabilityStatus(inst instance.Instance) string {
+	state := inst.State()
+
+	for _, status := range []api.StatusCode{api.Running, api.Stopped, api.Frozen, api.Error} {
+		if strings.EqualFold(state, status.String()) {
+			return status.String()
+		}
+	}
+
+	return ""
+}
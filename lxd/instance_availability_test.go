@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_instanceAvailability(t *testing.T) {
+	until := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	since := until.Add(-10 * time.Hour)
+
+	// No recorded state change.
+	availability := instanceAvailability(nil, since, until, 10)
+	assert.Equal(t, int64(0), availability.Uptime)
+	assert.Equal(t, int64(0), availability.Downtime)
+	assert.Equal(t, int64(10*3600), availability.Untracked)
+	assert.Equal(t, float64(0), availability.UptimePercentage)
+	assert.Empty(t, availability.Changes)
+
+	// The first change within the period leaves the time before it untracked.
+	transitions := []dbCluster.InstanceStateTransition{
+		{Status: "Running", Action: "instance-started", Timestamp: until.Add(-8 * time.Hour)},
+		{Status: "Stopped", Action: "instance-stopped", Timestamp: until.Add(-6 * time.Hour)},
+		{Status: "Running", Action: "instance-started", Timestamp: until.Add(-5 * time.Hour)},
+		{Status: "Frozen", Action: "instance-paused", Timestamp: until.Add(-2 * time.Hour)},
+		{Status: "Running", Action: "instance-resumed", Timestamp: until.Add(-time.Hour)},
+	}
+
+	availability = instanceAvailability(transitions, since, until, 10)
+	assert.Equal(t, since, availability.Since)
+	assert.Equal(t, until, availability.Until)
+	assert.Equal(t, int64(6*3600), availability.Uptime)
+	assert.Equal(t, int64(2*3600), availability.Downtime)
+	assert.Equal(t, int64(2*3600), availability.Untracked)
+	assert.Equal(t, float64(75), availability.UptimePercentage)
+	assert.Equal(t, float64(25), availability.DowntimePercentage)
+
+	// The changes are returned from the newest to the oldest.
+	assert.Equal(t, []api.InstanceStateChange{
+		{Timestamp: until.Add(-time.Hour), Status: "Running", Action: "instance-resumed"},
+		{Timestamp: until.Add(-2 * time.Hour), Status: "Frozen", Action: "instance-paused"},
+	}, instanceAvailability(transitions, since, until, 2).Changes)
+
+	assert.Empty(t, instanceAvailability(transitions, since, until, 0).Changes)
+
+	// The status at the start of the period is taken from the last change before it, which isn't returned.
+	transitions = []dbCluster.InstanceStateTransition{
+		{Status: "Stopped", Action: "instance-stopped", Timestamp: since.Add(-time.Hour)},
+		{Status: "Running", Action: "instance-started", Timestamp: until.Add(-time.Hour)},
+	}
+
+	availability = instanceAvailability(transitions, since, until, 10)
+	assert.Equal(t, int64(3600), availability.Uptime)
+	assert.Equal(t, int64(9*3600), availability.Downtime)
+	assert.Equal(t, int64(0), availability.Untracked)
+	assert.Equal(t, float64(10), availability.UptimePercentage)
+	assert.Equal(t, float64(90), availability.DowntimePercentage)
+	assert.Equal(t, []api.InstanceStateChange{{Timestamp: until.Add(-time.Hour), Status: "Running", Action: "instance-started"}}, availability.Changes)
+}
@@ -685,9 +685,16 @@ func instanceBackupDelete(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: header
+//	    name: Range
+//	    description: Byte range to retrieve (used to resume an interrupted download)
+//	    type: string
+//	    example: bytes=1048576-
 //	responses:
 //	  "200":
 //	    description: Raw image data
+//	  "206":
+//	    description: Partial backup data
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
 //	  "500":
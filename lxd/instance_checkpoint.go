@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var instanceCheckpointCmd = APIEndpoint{
+	Name:        "instanceCheckpoint",
+	Path:        "instances/{name}/checkpoint",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerCheckpoint", Path: "containers/{name}/checkpoint"},
+	},
+
+	Get:    APIEndpointAction{Handler: instanceCheckpointGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanManageBackups, "name")},
+	Post:   APIEndpointAction{Handler: instanceCheckpointPost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanUpdateState, "name")},
+	Put:    APIEndpointAction{Handler: instanceCheckpointPut, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanUpdateState, "name")},
+	Delete: APIEndpointAction{Handler: instanceCheckpointDelete, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanUpdateState, "name")},
+}
+
+// instanceCheckpointLoad loads the local container targeted by the request.
+// A non-nil response is returned if the request was forwarded or failed.
+func instanceCheckpointLoad(s *state.State, r *http.Request) (instance.Container, response.Response) {
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return nil, response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if resp != nil {
+		return nil, resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.Container {
+		return nil, response.BadRequest(errors.New("Checkpoints are supported for container type instances only"))
+	}
+
+	return inst.(instance.Container), nil
+}
+
+// swagger:operation GET /1.0/instances/{name}/checkpoint instances instance_checkpoint_get
+//
+//	Export the container checkpoint
+//
+//	Downloads the checkpoint (CRIU runtime state) of the container as a compressed tarball.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw checkpoint tarball
+//	     content:
+//	       application/octet-stream:
+//	         schema:
+//	           type: string
+//	           example: raw data
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceCheckpointGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	c, resp := instanceCheckpointLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	// Export to a temporary file first so that failures can still be reported to the client.
+	f, err := os.CreateTemp(s.BackupsStoragePath(c.Project().Name), backup.WorkingDirPrefix+"_checkpoint_")
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	cleanup := func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+
+	err = c.CheckpointExport(f)
+	if err != nil {
+		cleanup()
+		return response.SmartError(err)
+	}
+
+	ent := response.FileResponseEntry{
+		Path:     f.Name(),
+		Filename: "checkpoint.tar.gz",
+		Cleanup:  cleanup,
+	}
+
+	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
+}
+
+// swagger:operation POST /1.0/instances/{name}/checkpoint instances instance_checkpoint_post
+//
+//	Checkpoint the container
+//
+//	Saves the runtime state of the running container using CRIU, optionally stopping it.
+//	The checkpoint can then be exported or restored with a stateful start.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: checkpoint
+//	    description: Checkpoint request
+//	    schema:
+//	      $ref: "#/definitions/InstanceCheckpointPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceCheckpointPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	c, resp := instanceCheckpointLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.InstanceCheckpointPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	do := func(op *operations.Operation) error {
+		c.SetOperation(op)
+
+		return c.Checkpoint(req.Stop)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", c.Name())}
+	op, err := operations.OperationCreate(r.Context(), s, c.Project().Name, operations.OperationClassTask, operationtype.InstanceCheckpoint, resources, nil, do, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation PUT /1.0/instances/{name}/checkpoint instances instance_checkpoint_put
+//
+//	Import a container checkpoint
+//
+//	Replaces the checkpoint of the stopped container with the uploaded tarball (as exported by GET).
+//	The container can then be restored with a stateful start.
+//	Only the checkpoints exported by this server or its cluster are accepted, and importing them isn't
+//	allowed in restricted projects.
+//
+//	---
+//	consumes:
+//	  - application/octet-stream
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: raw_file
+//	    description: Raw checkpoint tarball
+//	    required: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceCheckpointPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	c, resp := instanceCheckpointLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	err := instanceCheckpointImportAllowed(c.Project().Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = c.CheckpointImport(r.Body)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// instanceCheckpointImportAllowed returns an error if checkpoints can't be imported in a project with the given
+// configuration. CRIU restores the imported files as root on the host, so restricted projects can only restore the
+// checkpoints taken on the server.
+func instanceCheckpointImportAllowed(projectConfig map[string]string) error {
+	if shared.IsTrue(projectConfig["restricted"]) {
+		return api.StatusErrorf(http.StatusForbidden, "Importing checkpoints isn't allowed in restricted projects")
+	}
+
+	return nil
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/checkpoint instances instance_checkpoint_delete
+//
+//	Delete the container checkpoint
+//
+//	Removes the checkpoint of the container.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceCheckpointDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	c, resp := instanceCheckpointLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	err := c.CheckpointDelete()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_instanceCheckpointImportAllowed(t *testing.T) {
+	assert.NoError(t, instanceCheckpointImportAllowed(map[string]string{}))
+	assert.NoError(t, instanceCheckpointImportAllowed(map[string]string{"restricted": "false"}))
+
+	err := instanceCheckpointImportAllowed(map[string]string{"restricted": "true"})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden), "Unexpected error %v", err)
+}
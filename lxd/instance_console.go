@@ -23,6 +23,7 @@ import (
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -216,10 +217,10 @@ func (s *consoleWs) connectVGA(r *http.Request, w http.ResponseWriter) error {
 }
 
 // Do connects to the websocket and executes the operation.
-func (s *consoleWs) Do(_ *operations.Operation) error {
+func (s *consoleWs) Do(op *operations.Operation) error {
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
-		return s.doConsole()
+		return s.doConsole(op)
 	case instance.ConsoleTypeVGA:
 		return s.doVGA()
 	default:
@@ -227,10 +228,18 @@ func (s *consoleWs) Do(_ *operations.Operation) error {
 	}
 }
 
-func (s *consoleWs) doConsole() error {
+func (s *consoleWs) doConsole(op *operations.Operation) error {
 	defer logger.Debug("Console websocket finished")
 	<-s.allConnected
 
+	// Record the session if required by the project.
+	rec, err := instanceSessionRecorder(s.instance, "console", op, s.width, s.height, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rec.Close() }()
+
 	// Get console from instance.
 	console, consoleDisconnectCh, err := s.instance.Console(s.protocol)
 	if err != nil {
@@ -298,6 +307,7 @@ func (s *consoleWs) doConsole() error {
 				}
 
 				logger.Debugf("Set window size to: %dx%d", winchWidth, winchHeight)
+				rec.Resize(winchWidth, winchHeight)
 			}
 		}
 	}()
@@ -313,7 +323,7 @@ func (s *consoleWs) doConsole() error {
 		defer l.Debug("Finished mirroring websocket to console")
 
 		l.Debug("Started mirroring websocket")
-		readDone, writeDone := ws.Mirror(conn, console)
+		readDone, writeDone := ws.Mirror(conn, rec.ReadWriteCloser(console))
 
 		<-readDone
 		l.Debug("Finished mirroring console to websocket")
@@ -554,6 +564,8 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 //	Get console log
 //
 //	Gets the console log for the instance.
+//	When `history` is set, returns the persistent console history of the instance (across restarts)
+//	followed by the console output of the current boot. This is supported for both containers and virtual machines.
 //
 //	---
 //	produces:
@@ -564,6 +576,11 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: history
+//	    description: Whether to retrieve the persistent console history
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	     description: Raw console log
@@ -613,6 +630,23 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if shared.IsTrue(r.FormValue("history")) {
+		history, err := inst.ConsoleHistory()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceConsoleRetrieved.Event(inst, map[string]any{"history": true}))
+
+		ent := response.FileResponseEntry{
+			File:         bytes.NewReader(history),
+			FileModified: time.Now(),
+			FileSize:     int64(len(history)),
+		}
+
+		return response.FileResponse([]response.FileResponseEntry{ent}, nil)
+	}
+
 	if inst.Type() != instancetype.Container {
 		return response.SmartError(errors.New("Instance is not container type"))
 	}
@@ -666,6 +700,7 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 //	Clear the console log
 //
 //	Clears the console log buffer.
+//	When `history` is set, removes the persistent console history of the instance instead.
 //
 //	---
 //	produces:
@@ -676,6 +711,11 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: history
+//	    description: Whether to remove the persistent console history
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	    $ref: "#/responses/EmptySyncResponse"
@@ -704,6 +744,10 @@ func instanceConsoleLogDelete(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if shared.IsTrue(r.FormValue("history")) {
+		return response.SmartError(inst.ConsoleHistoryClear())
+	}
+
 	if inst.Type() != instancetype.Container {
 		return response.SmartError(errors.New("Instance is not container type"))
 	}
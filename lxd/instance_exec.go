@@ -240,6 +240,18 @@ func (s *execWs) Do(op *operations.Operation) error {
 		stderr = ttys[execWSStderr]
 	}
 
+	// Record the session if required by the project.
+	rec, err := instanceSessionRecorder(s.instance, "exec", op, s.req.Width, s.req.Height, s.req.Command)
+	if err != nil {
+		for _, f := range append(ttys, ptys...) {
+			_ = f.Close()
+		}
+
+		return err
+	}
+
+	defer func() { _ = rec.Close() }()
+
 	waitAttachedChildIsDead, markAttachedChildIsDead := context.WithCancel(context.Background())
 	var wgEOF sync.WaitGroup
 
@@ -382,6 +394,8 @@ func (s *execWs) Do(op *operations.Operation) error {
 					l.Debug("Failed to set window size", logger.Ctx{"err": err, "width": winchWidth, "height": winchHeight})
 					continue
 				}
+
+				rec.Resize(winchWidth, winchHeight)
 			} else if command.Command == "signal" {
 				err := cmd.Signal(unix.Signal(command.Signal))
 				if err != nil {
@@ -412,10 +426,10 @@ func (s *execWs) Do(op *operations.Operation) error {
 			if s.instance.Type() == instancetype.Container {
 				// For containers, we are running the command via the local LXD managed PTY and so
 				// need to use the same PTY handle for both read and write.
-				readDone, writeDone = ws.Mirror(conn, shared.NewExecWrapper(waitAttachedChildIsDead, ptys[0]))
+				readDone, writeDone = ws.Mirror(conn, rec.ReadWriteCloser(shared.NewExecWrapper(waitAttachedChildIsDead, ptys[0])))
 			} else {
-				readDone = ws.MirrorRead(conn, ptys[execWSStdout])
-				writeDone = ws.MirrorWrite(conn, ttys[execWSStdin])
+				readDone = ws.MirrorRead(conn, rec.Reader(ptys[execWSStdout]))
+				writeDone = ws.MirrorWrite(conn, rec.Writer(ttys[execWSStdin]))
 			}
 
 			readErr = <-readDone
@@ -469,10 +483,10 @@ func (s *execWs) Do(op *operations.Operation) error {
 				}
 
 				if i == execWSStdin {
-					err = <-ws.MirrorWrite(conn, ttys[i])
+					err = <-ws.MirrorWrite(conn, rec.Writer(ttys[i]))
 					_ = ttys[i].Close()
 				} else {
-					err = <-ws.MirrorRead(conn, shared.NewExecWrapper(waitAttachedChildIsDead, ptys[i]))
+					err = <-ws.MirrorRead(conn, rec.Reader(shared.NewExecWrapper(waitAttachedChildIsDead, ptys[i])))
 					_ = ptys[i].Close()
 					wgEOF.Done()
 				}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/oci"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var instanceExportCmd = APIEndpoint{
+	Name:        "instanceExport",
+	Path:        "instances/{name}/export",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerExport", Path: "containers/{name}/export"},
+	},
+
+	Post: APIEndpointAction{Handler: instanceExportPost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanManageBackups, "name")},
+}
+
+// instanceExportOCITagRegex matches the valid OCI image tags.
+var instanceExportOCITagRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+// instanceExportOCI writes the root filesystem and metadata of the stopped container as an OCI image layout in a
+// new directory within buildDir, tagged with the given tag. The conversion stops once the cancellation of the
+// operation is requested, if any. The path of the layout is returned.
+func instanceExportOCI(inst instance.Instance, buildDir string, tag string, op *operations.Operation) (string, error) {
+	layoutDir, err := os.MkdirTemp(buildDir, "lxd_oci_")
+	if err != nil {
+		return "", err
+	}
+
+	// Convert the image tarball of the instance as it is being generated.
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := inst.Export(pw, nil, time.Time{}, nil)
+		_ = pw.CloseWithError(err)
+	}()
+
+	_, err = oci.WriteLayout(op.CheckpointReader(pr), layoutDir, tag)
+	_ = pr.CloseWithError(err)
+	if err != nil {
+		_ = os.RemoveAll(layoutDir)
+		return "", fmt.Errorf("Failed exporting instance as OCI image: %w", err)
+	}
+
+	return layoutDir, nil
+}
+
+// instanceExportOCIPush pushes the image of the OCI image layout to the registry reference using skopeo.
+// The registry credentials are the ones configured for skopeo on the host.
+func instanceExportOCIPush(ctx context.Context, layoutDir string, tag string, target string) error {
+	_, err := exec.LookPath("skopeo")
+	if err != nil {
+		return errors.New("Pushing OCI images requires skopeo to be installed on the host")
+	}
+
+	_, err = shared.RunCommandContext(ctx, "skopeo", "copy", "--quiet", "oci:"+layoutDir+":"+tag, "docker://"+target)
+	if err != nil {
+		return fmt.Errorf("Failed pushing OCI image to %q: %w", target, err)
+	}
+
+	return nil
+}
+
+// swagger:operation POST /1.0/instances/{name}/export instances instance_export_post
+//
+//	Export the instance as an OCI image
+//
+//	Converts the root filesystem and metadata of the stopped container into an OCI image.
+//	The OCI image layout is returned as a tarball, unless a registry reference is provided as target in which case
+//	the image is pushed to it in a background operation.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/octet-stream
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: format
+//	    description: Export format (only `oci` is supported)
+//	    type: string
+//	    example: oci
+//	  - in: body
+//	    name: export
+//	    description: Export request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/InstanceExportPost"
+//	responses:
+//	  "200":
+//	     description: Raw file data
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceExportPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	format := request.QueryParam(r, "format")
+	if format != "oci" {
+		return response.BadRequest(fmt.Errorf("Unsupported export format %q", format))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	req := api.InstanceExportPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return response.BadRequest(err)
+	}
+
+	if req.Tag == "" {
+		req.Tag = "latest"
+	}
+
+	if !instanceExportOCITagRegex.MatchString(req.Tag) {
+		return response.BadRequest(fmt.Errorf("Invalid OCI image tag %q", req.Tag))
+	}
+
+	if req.Target != "" && (strings.Contains(req.Target, "://") || strings.HasPrefix(req.Target, "-") || strings.ContainsAny(req.Target, " \t\n")) {
+		return response.BadRequest(fmt.Errorf("Invalid registry reference %q", req.Target))
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.Container {
+		return response.BadRequest(errors.New("Only containers can be exported as OCI images"))
+	}
+
+	if inst.IsRunning() {
+		return response.BadRequest(errors.New("The instance must be stopped to be exported"))
+	}
+
+	buildDir, err := os.MkdirTemp(s.ImagesStoragePath(projectName), "lxd_build_")
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	if req.Target == "" {
+		return instanceExportOCIFile(inst, buildDir, req.Tag)
+	}
+
+	do := func(op *operations.Operation) error {
+		defer func() { _ = os.RemoveAll(buildDir) }()
+
+		inst.SetOperation(op)
+
+		layoutDir, err := instanceExportOCI(inst, buildDir, req.Tag, op)
+		if err != nil {
+			return err
+		}
+
+		// The push is aborted once the cancellation of the operation is requested.
+		return instanceExportOCIPush(op.Context(), layoutDir, req.Tag, req.Target)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+	op, err := operations.OperationCreate(r.Context(), s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceExport, resources, nil, do, nil, nil)
+	if err != nil {
+		_ = os.RemoveAll(buildDir)
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// instanceExportOCIFile returns the OCI image layout of the container as a tarball.
+func instanceExportOCIFile(inst instance.Instance, buildDir string, tag string) response.Response {
+	cleanup := func() {
+		err := os.RemoveAll(buildDir)
+		if err != nil {
+			logger.Warn("Failed removing OCI export build directory", logger.Ctx{"path": buildDir, "err": err})
+		}
+	}
+
+	layoutDir, err := instanceExportOCI(inst, buildDir, tag, nil)
+	if err != nil {
+		cleanup()
+		return response.SmartError(err)
+	}
+
+	archive, err := os.Create(filepath.Join(buildDir, "oci.tar"))
+	if err != nil {
+		cleanup()
+		return response.InternalError(err)
+	}
+
+	err = oci.WriteArchive(layoutDir, archive)
+	_ = archive.Close()
+	if err != nil {
+		cleanup()
+		return response.InternalError(err)
+	}
+
+	ent := response.FileResponseEntry{
+		Identifier: "oci",
+		Filename:   inst.Name() + ".oci.tar",
+		Path:       archive.Name(),
+		Cleanup:    cleanup,
+	}
+
+	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
+}
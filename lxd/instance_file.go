@@ -28,6 +28,19 @@ import (
 	"github.com/canonical/lxd/shared/revert"
 )
 
+// instanceDiskFiles is a stopped VM whose files are accessed by mounting its root disk on the host rather than
+// through the agent.
+type instanceDiskFiles struct {
+	instance.VM
+
+	writable bool
+}
+
+// FileSFTP returns a SFTP client operating on the root disk of the VM.
+func (i *instanceDiskFiles) FileSFTP() (*sftp.Client, error) {
+	return i.DiskFileSFTP(i.writable)
+}
+
 func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 	s := d.State()
 
@@ -62,6 +75,24 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Access the files of stopped VMs through their root disk if requested.
+	disk := r.FormValue("disk")
+	if disk != "" {
+		if disk != "ro" && disk != "rw" {
+			return response.BadRequest(fmt.Errorf("Invalid disk access mode %q", disk))
+		}
+
+		if inst.Type() != instancetype.VM {
+			return response.BadRequest(errors.New("Disk file access is only supported for virtual machines"))
+		}
+
+		if inst.IsRunning() {
+			return response.BadRequest(errors.New("Disk file access is only supported for stopped virtual machines"))
+		}
+
+		inst = &instanceDiskFiles{VM: inst.(instance.VM), writable: disk == "rw"}
+	}
+
 	// Parse and cleanup the path.
 	path := r.FormValue("path")
 	if path == "" {
@@ -72,6 +103,18 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 		path = "/" + path
 	}
 
+	// Transfer whole directory trees as tar streams.
+	if r.FormValue("format") == "tar" {
+		switch r.Method {
+		case "GET":
+			return instanceFileGetTar(s, inst, path, r)
+		case "POST":
+			return instanceFilePostTar(s, inst, path, r)
+		default:
+			return response.BadRequest(fmt.Errorf("The tar format isn't supported for method %q", r.Method))
+		}
+	}
+
 	switch r.Method {
 	case "GET":
 		return instanceFileGet(s, inst, path)
@@ -92,10 +135,13 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 //
 //	Gets the file content. If it's a directory, a json list of files will be returned instead.
 //
+//	With `format=tar`, the whole tree rooted at the path is returned as a tar stream instead.
+//
 //	---
 //	produces:
 //	  - application/json
 //	  - application/octet-stream
+//	  - application/x-tar
 //	parameters:
 //	  - in: query
 //	    name: path
@@ -107,6 +153,26 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: disk
+//	    description: Access the files of a stopped virtual machine through its root disk (`ro` or `rw`)
+//	    type: string
+//	    example: ro
+//	  - in: query
+//	    name: format
+//	    description: Transfer format (`tar` to download the whole tree)
+//	    type: string
+//	    example: tar
+//	  - in: query
+//	    name: include
+//	    description: Pattern of the files to include in the tar stream (can be repeated)
+//	    type: string
+//	    example: "*.conf"
+//	  - in: query
+//	    name: exclude
+//	    description: Pattern of the files and directories to exclude from the tar stream (can be repeated)
+//	    type: string
+//	    example: "*.log"
 //	responses:
 //	  "200":
 //	     description: Raw file or directory listing
@@ -286,6 +352,11 @@ func instanceFileGet(s *state.State, inst instance.Instance, path string) respon
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: disk
+//	    description: Access the files of a stopped virtual machine through its root disk (`ro` or `rw`)
+//	    type: string
+//	    example: ro
 //	responses:
 //	  "200":
 //	     description: Raw file or directory listing
@@ -418,9 +489,12 @@ func effectiveFileOwnership(inst instance.Instance, headers *shared.LXDFileHeade
 //
 //	Creates a new file in the instance.
 //
+//	With `format=tar`, the body is a tar stream that is extracted into the directory at the path instead.
+//
 //	---
 //	consumes:
 //	  - application/octet-stream
+//	  - application/x-tar
 //	produces:
 //	  - application/json
 //	parameters:
@@ -434,6 +508,26 @@ func effectiveFileOwnership(inst instance.Instance, headers *shared.LXDFileHeade
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: disk
+//	    description: Access the files of a stopped virtual machine through its root disk (`ro` or `rw`)
+//	    type: string
+//	    example: ro
+//	  - in: query
+//	    name: format
+//	    description: Transfer format (`tar` to upload a whole tree)
+//	    type: string
+//	    example: tar
+//	  - in: query
+//	    name: include
+//	    description: Pattern of the files to extract from the tar stream (can be repeated)
+//	    type: string
+//	    example: "*.conf"
+//	  - in: query
+//	    name: exclude
+//	    description: Pattern of the files and directories to skip from the tar stream (can be repeated)
+//	    type: string
+//	    example: "*.log"
 //	  - in: body
 //	    name: raw_file
 //	    description: Raw file content
@@ -651,6 +745,11 @@ func instanceFilePost(s *state.State, inst instance.Instance, path string, r *ht
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: disk
+//	    description: Access the files of a stopped virtual machine through its root disk (`ro` or `rw`)
+//	    type: string
+//	    example: ro
 //	responses:
 //	  "200":
 //	    $ref: "#/responses/EmptySyncResponse"
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// instanceFileTarFilter selects the entries of a directory tree transferred as a tar stream.
+type instanceFileTarFilter struct {
+	include []string
+	exclude []string
+}
+
+// instanceFileTarFilterFromRequest returns the filter made of the include and exclude query parameters of the request.
+func instanceFileTarFilterFromRequest(r *http.Request) (*instanceFileTarFilter, error) {
+	filter := &instanceFileTarFilter{
+		include: r.URL.Query()["include"],
+		exclude: r.URL.Query()["exclude"],
+	}
+
+	for _, pattern := range append(append([]string{}, filter.include...), filter.exclude...) {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	return filter, nil
+}
+
+// match returns whether the pattern matches the relative path or its last element.
+func (f *instanceFileTarFilter) match(pattern string, relPath string) bool {
+	matched, _ := path.Match(pattern, relPath)
+	if matched {
+		return true
+	}
+
+	matched, _ = path.Match(pattern, path.Base(relPath))
+	return matched
+}
+
+// selected returns whether the entry at the given path (relative to the root of the transfer) should be transferred.
+// Excluded directories are skipped with their content, while the include patterns only apply to non-directories.
+func (f *instanceFileTarFilter) selected(relPath string, isDir bool) bool {
+	for _, pattern := range f.exclude {
+		if f.match(pattern, relPath) {
+			return false
+		}
+	}
+
+	if isDir || len(f.include) == 0 {
+		return true
+	}
+
+	for _, pattern := range f.include {
+		if f.match(pattern, relPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// instanceFileTarHeader returns the tar header of the instance file with the given stat.
+func instanceFileTarHeader(client *sftp.Client, filePath string, name string, stat fs.FileInfo) (*tar.Header, error) {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(stat.Mode().Perm()),
+		ModTime: stat.ModTime(),
+	}
+
+	fileStat, ok := stat.Sys().(*sftp.FileStat)
+	if ok {
+		hdr.Uid = int(fileStat.UID)
+		hdr.Gid = int(fileStat.GID)
+	}
+
+	switch {
+	case stat.Mode().IsDir():
+		hdr.Typeflag = tar.TypeDir
+		hdr.Name += "/"
+	case stat.Mode()&os.ModeSymlink == os.ModeSymlink:
+		target, err := client.ReadLink(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = target
+	case stat.Mode().IsRegular():
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = stat.Size()
+	default:
+		// Devices, sockets and pipes can't be transferred.
+		return nil, nil
+	}
+
+	return hdr, nil
+}
+
+// instanceFileTarWrite writes the tree rooted at root in the instance as a tar stream.
+// If root is not a directory, the stream contains a single entry named after it.
+func instanceFileTarWrite(client *sftp.Client, root string, filter *instanceFileTarFilter, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	walker := client.Walk(root)
+	for walker.Step() {
+		err := walker.Err()
+		if err != nil {
+			return err
+		}
+
+		stat := walker.Stat()
+
+		relPath, err := filepath.Rel(root, walker.Path())
+		if err != nil {
+			return err
+		}
+
+		if relPath == "." {
+			// Don't include the root directory itself.
+			if stat.IsDir() {
+				continue
+			}
+
+			relPath = path.Base(root)
+		}
+
+		if !filter.selected(relPath, stat.IsDir()) {
+			if stat.IsDir() {
+				walker.SkipDir()
+			}
+
+			continue
+		}
+
+		hdr, err := instanceFileTarHeader(client, walker.Path(), relPath, stat)
+		if err != nil {
+			return err
+		}
+
+		if hdr == nil {
+			continue
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = func() error {
+			file, err := client.Open(walker.Path())
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = file.Close() }()
+
+			_, err = io.CopyN(tw, file, hdr.Size)
+			return err
+		}()
+		if err != nil {
+			return fmt.Errorf("Failed transferring %q: %w", walker.Path(), err)
+		}
+	}
+
+	return tw.Close()
+}
+
+// instanceFileTarOwnership sets the ownership of the extracted entry, within the allowed range for containers.
+func instanceFileTarOwnership(client *sftp.Client, inst instance.Instance, filePath string, hdr *tar.Header) error {
+	headers := &shared.LXDFileHeaders{UID: int64(hdr.Uid), GID: int64(hdr.Gid)}
+
+	var err error
+	if inst.Type() == instancetype.Container {
+		headers.UID, headers.GID, err = effectiveFileOwnership(inst, headers, filePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return client.Chown(filePath, int(headers.UID), int(headers.GID))
+}
+
+// instanceFileTarExtract extracts the tar stream into the root directory in the instance (created if missing).
+func instanceFileTarExtract(client *sftp.Client, inst instance.Instance, root string, filter *instanceFileTarFilter, r io.Reader) error {
+	err := client.MkdirAll(root)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed reading tar stream: %w", err)
+		}
+
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if name == "." {
+			continue
+		}
+
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("Invalid entry %q in tar stream", hdr.Name)
+		}
+
+		if !filter.selected(name, hdr.Typeflag == tar.TypeDir) {
+			continue
+		}
+
+		target := path.Join(root, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = client.MkdirAll(target)
+		case tar.TypeReg:
+			err = func() error {
+				file, err := client.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+				if err != nil {
+					return err
+				}
+
+				defer func() { _ = file.Close() }()
+
+				_, err = io.Copy(file, tr)
+				return err
+			}()
+		case tar.TypeSymlink:
+			_ = client.Remove(target)
+			err = client.Symlink(hdr.Linkname, target)
+		case tar.TypeLink:
+			if !filepath.IsLocal(path.Clean(hdr.Linkname)) {
+				return fmt.Errorf("Invalid link target %q in tar stream", hdr.Linkname)
+			}
+
+			_ = client.Remove(target)
+			err = client.Link(path.Join(root, hdr.Linkname), target)
+		default:
+			// Devices, sockets and pipes can't be created.
+			logger.Debug("Skipping unsupported tar entry", logger.Ctx{"name": hdr.Name, "type": hdr.Typeflag})
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed extracting %q: %w", hdr.Name, err)
+		}
+
+		// Symlink permissions and ownership aren't meaningful (and can't be set through SFTP).
+		if hdr.Typeflag == tar.TypeSymlink || hdr.Typeflag == tar.TypeLink {
+			continue
+		}
+
+		err = client.Chmod(target, fs.FileMode(hdr.Mode).Perm())
+		if err != nil {
+			return err
+		}
+
+		err = instanceFileTarOwnership(client, inst, target, hdr)
+		if err != nil {
+			return err
+		}
+
+		err = client.Chtimes(target, hdr.ModTime, hdr.ModTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instanceFileGetTar returns the tree rooted at the given path in the instance as a tar stream.
+func instanceFileGetTar(s *state.State, inst instance.Instance, path string, r *http.Request) response.Response {
+	filter, err := instanceFileTarFilterFromRequest(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get a SFTP client.
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// Check the path exists before starting the transfer so that errors can be reported.
+	_, err = client.Lstat(path)
+	if err != nil {
+		_ = client.Close()
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceFileRetrieved.Event(inst, logger.Ctx{"path": path}))
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		defer func() { _ = client.Close() }()
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.WriteHeader(http.StatusOK)
+
+		return instanceFileTarWrite(client, path, filter, w)
+	})
+}
+
+// instanceFilePostTar extracts the tar stream of the request body into the directory at the given path in the instance.
+func instanceFilePostTar(s *state.State, inst instance.Instance, path string, r *http.Request) response.Response {
+	filter, err := instanceFileTarFilterFromRequest(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get a SFTP client.
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	stat, err := client.Stat(path)
+	if err == nil && !stat.IsDir() {
+		return response.BadRequest(errors.New("Tar streams can only be extracted into a directory"))
+	}
+
+	err = instanceFileTarExtract(client, inst, path, filter, r.Body)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceFilePushed.Event(inst, logger.Ctx{"path": path}))
+	return response.EmptySyncResponse
+}
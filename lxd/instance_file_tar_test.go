@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instanceFileTarFilterFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/1.0/instances/c1/files?path=/root&format=tar&include=*.conf&include=sub/*.txt&exclude=logs", nil)
+
+	filter, err := instanceFileTarFilterFromRequest(r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.conf", "sub/*.txt"}, filter.include)
+	assert.Equal(t, []string{"logs"}, filter.exclude)
+
+	r = httptest.NewRequest("GET", "/1.0/instances/c1/files?path=/root&format=tar&exclude=%5B", nil)
+	_, err = instanceFileTarFilterFromRequest(r)
+	assert.Error(t, err)
+}
+
+func Test_instanceFileTarFilterSelected(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   instanceFileTarFilter
+		relPath  string
+		isDir    bool
+		expected bool
+	}{
+		{
+			name:     "No filter",
+			relPath:  "sub/c.txt",
+			expected: true,
+		},
+		{
+			name:     "Included by name",
+			filter:   instanceFileTarFilter{include: []string{"*.conf"}},
+			relPath:  "sub/b.conf",
+			expected: true,
+		},
+		{
+			name:     "Included by path",
+			filter:   instanceFileTarFilter{include: []string{"sub/*.txt"}},
+			relPath:  "sub/c.txt",
+			expected: true,
+		},
+		{
+			name:     "Not included",
+			filter:   instanceFileTarFilter{include: []string{"*.conf"}},
+			relPath:  "sub/c.txt",
+			expected: false,
+		},
+		{
+			name:     "Directories ignore include patterns",
+			filter:   instanceFileTarFilter{include: []string{"*.conf"}},
+			relPath:  "sub",
+			isDir:    true,
+			expected: true,
+		},
+		{
+			name:     "Excluded directory",
+			filter:   instanceFileTarFilter{exclude: []string{"logs"}},
+			relPath:  "logs",
+			isDir:    true,
+			expected: false,
+		},
+		{
+			name:     "Exclude takes precedence",
+			filter:   instanceFileTarFilter{include: []string{"*.conf"}, exclude: []string{"b.*"}},
+			relPath:  "sub/b.conf",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.selected(tt.relPath, tt.isDir))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -59,6 +61,50 @@ import (
 //    "500":
 //      $ref: "#/responses/InternalServerError"
 
+// swagger:operation GET /1.0/instances/{name}?recursion=2 instances instance_get_recursion2
+//
+//  Get the instance
+//
+//  Gets a specific instance (full struct).
+//
+//  recursion=2 also includes the resource usage samples of the last hour
+//  (when `instances.state.history.interval` is set).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//  responses:
+//    "200":
+//      description: Instance
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            $ref: "#/definitions/InstanceFull"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
 // swagger:operation GET /1.0/instances/{name}?recursion=1 instances instance_get_recursion1
 //
 //	Get the instance
@@ -154,10 +200,29 @@ func instanceGet(d *Daemon, r *http.Request) response.Response {
 	if !recursive {
 		state, etag, err = c.Render()
 	} else {
+		var full *api.InstanceFull
+
 		hostInterfaces, _ := net.Interfaces()
-		state, etag, err = c.RenderFull(hostInterfaces)
+		full, etag, err = c.RenderFull(hostInterfaces)
+		if err == nil && r.FormValue("recursion") == "2" {
+			full.StateHistory = instanceStateRecentSamples(d, c)
+		}
+
+		state = full
+	}
+
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	apiInst, ok := state.(*api.Instance)
+	if !ok {
+		apiInst = &state.(*api.InstanceFull).Instance
 	}
 
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return instancesOwnershipFill(ctx, tx, map[int]*api.Instance{c.ID(): apiInst})
+	})
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -679,6 +679,7 @@ func validLogFileName(fname string) bool {
 		fname == "lxc.conf" ||
 		fname == "qemu.log" ||
 		fname == "qemu.conf" ||
+		fname == "hooks.log" ||
 		strings.HasPrefix(fname, "migration_") ||
 		strings.HasPrefix(fname, "snapshot_")
 }
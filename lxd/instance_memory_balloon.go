@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// instanceMemoryBalloonInterval is how often the memory balloon of the VMs using the automatic policy is resized.
+const instanceMemoryBalloonInterval = 15 * time.Second
+
+// instanceMemoryBalloonTask resizes the memory balloon of the local running VMs that have limits.memory.balloon set
+// to auto, based on the memory pressure reported by their lxd-agent.
+func instanceMemoryBalloonTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.VM)
+		if err != nil {
+			logger.Warn("Failed loading instances for memory ballooning", logger.Ctx{"err": err})
+			return
+		}
+
+		// Limit the concurrency to the number of CPU cores.
+		var wg sync.WaitGroup
+		vmCh := make(chan instance.VM)
+		for range runtime.NumCPU() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for vm := range vmCh {
+					err := vm.MemoryBalloonAdjust()
+					if err != nil {
+						logger.Warn("Failed resizing memory balloon", logger.Ctx{"project": vm.Project().Name, "instance": vm.Name(), "err": err})
+					}
+				}
+			}()
+		}
+
+		for _, inst := range instances {
+			if inst.ExpandedConfig()["limits.memory.balloon"] != "auto" || !inst.IsRunning() {
+				continue
+			}
+
+			vm, ok := inst.(instance.VM)
+			if !ok {
+				continue
+			}
+
+			select {
+			case vmCh <- vm:
+			case <-ctx.Done():
+			}
+		}
+
+		close(vmCh)
+		wg.Wait()
+	}
+
+	return f, task.Every(instanceMemoryBalloonInterval)
+}
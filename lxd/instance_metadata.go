@@ -115,7 +115,7 @@ func instanceMetadataGet(d *Daemon, r *http.Request) response.Response {
 	// If missing, just return empty result
 	metadataPath := filepath.Join(c.Path(), "metadata.yaml")
 	if !shared.PathExists(metadataPath) {
-		return response.SyncResponse(true, api.ImageMetadata{})
+		return response.SyncResponseETag(true, api.ImageMetadata{}, api.ImageMetadata{})
 	}
 
 	// Read the metadata
@@ -225,26 +225,9 @@ func instanceMetadataPatch(d *Daemon, r *http.Request) response.Response {
 	defer func() { _ = storagePools.InstanceUnmount(pool, inst, nil) }()
 
 	// Read the existing data.
-	metadataPath := filepath.Join(inst.Path(), "metadata.yaml")
-	metadata := api.ImageMetadata{}
-	if shared.PathExists(metadataPath) {
-		metadataFile, err := os.Open(metadataPath)
-		if err != nil {
-			return response.InternalError(err)
-		}
-
-		defer func() { _ = metadataFile.Close() }()
-
-		data, err := io.ReadAll(metadataFile)
-		if err != nil {
-			return response.InternalError(err)
-		}
-
-		// Parse into the API struct
-		err = yaml.Unmarshal(data, &metadata)
-		if err != nil {
-			return response.SmartError(err)
-		}
+	metadata, err := instanceMetadataRead(inst)
+	if err != nil {
+		return response.SmartError(err)
 	}
 
 	// Validate ETag
@@ -351,9 +334,44 @@ func instanceMetadataPut(d *Daemon, r *http.Request) response.Response {
 
 	defer func() { _ = storagePools.InstanceUnmount(pool, inst, nil) }()
 
+	// Validate ETag
+	currentMetadata, err := instanceMetadataRead(inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = util.EtagCheck(r, currentMetadata)
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
 	return doInstanceMetadataUpdate(s, inst, metadata, r)
 }
 
+// instanceMetadataRead returns the current metadata of the given instance, which is empty if the instance has no
+// metadata file. The instance must be mounted.
+func instanceMetadataRead(inst instance.Instance) (api.ImageMetadata, error) {
+	metadata := api.ImageMetadata{}
+
+	metadataPath := filepath.Join(inst.Path(), "metadata.yaml")
+	if !shared.PathExists(metadataPath) {
+		return metadata, nil
+	}
+
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return metadata, err
+	}
+
+	// Parse into the API struct
+	err = yaml.Unmarshal(data, &metadata)
+	if err != nil {
+		return metadata, err
+	}
+
+	return metadata, nil
+}
+
 func doInstanceMetadataUpdate(s *state.State, inst instance.Instance, metadata api.ImageMetadata, r *http.Request) response.Response {
 	// Convert YAML.
 	data, err := yaml.Marshal(metadata)
@@ -21,6 +21,7 @@ import (
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/osarch"
 )
 
@@ -192,6 +193,30 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Check if labels were passed
+	if req.Labels == nil {
+		req.Labels = c.Labels()
+	} else {
+		for k, v := range c.Labels() {
+			_, ok := req.Labels[k]
+			if !ok {
+				req.Labels[k] = v
+			}
+		}
+
+		// Once the labels are merged, remove labels whose value is empty.
+		for k, v := range req.Labels {
+			if v == "" {
+				delete(req.Labels, k)
+			}
+		}
+	}
+
+	err = admitRequest(r.Context(), s, projectName, entity.TypeInstance, "update", name, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check project limits.
 	apiProfiles := make([]api.Profile, 0, len(req.Profiles))
 	err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
@@ -232,6 +257,7 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		Description:  req.Description,
 		Devices:      deviceConfig.NewDevices(req.Devices),
 		Ephemeral:    req.Ephemeral,
+		Labels:       req.Labels,
 		Profiles:     apiProfiles,
 		Project:      projectName,
 	}
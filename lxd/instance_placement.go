@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/osarch"
+)
+
+// instancePlacementCandidates applies the placement rules set in the expanded config of an instance (`placement.*`)
+// to the candidate cluster members and returns the members the instance can be placed on.
+// If the rules exclude all the candidates, a not found error explaining why each member was excluded is returned.
+func instancePlacementCandidates(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, config map[string]string, labels map[string]string, candidates []db.NodeInfo) ([]db.NodeInfo, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	var explanation []string
+
+	// Exclude the members running instances sharing one of the anti-affinity labels.
+	for _, key := range shared.SplitNTrimSpace(config["placement.anti_affinity"], ",", -1, true) {
+		value, found := labels[key]
+		if !found {
+			continue
+		}
+
+		instanceMembers, err := dbCluster.GetInstanceMembersWithLabel(ctx, tx.Tx(), projectName, key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		memberInstances := map[int64]string{}
+		for name, memberID := range instanceMembers {
+			if name != instanceName {
+				memberInstances[memberID] = name
+			}
+		}
+
+		remaining := make([]db.NodeInfo, 0, len(candidates))
+		for _, member := range candidates {
+			name, found := memberInstances[member.ID]
+			if found {
+				explanation = append(explanation, fmt.Sprintf("Member %q runs instance %q with label %s=%s (placement.anti_affinity)", member.Name, name, key, value))
+				continue
+			}
+
+			remaining = append(remaining, member)
+		}
+
+		candidates = remaining
+	}
+
+	if len(candidates) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "No suitable cluster member could be found: %s", strings.Join(explanation, ", "))
+	}
+
+	if len(explanation) > 0 {
+		logger.Debug("Excluded cluster members from instance placement", logger.Ctx{"project": projectName, "instance": instanceName, "reasons": explanation})
+	}
+
+	// Prefer the members of the preferred cluster group if any is suitable.
+	preferredGroup := config["placement.preferred_group"]
+	if preferredGroup != "" {
+		var preferred []db.NodeInfo
+		for _, member := range candidates {
+			if slices.Contains(member.Groups, preferredGroup) {
+				preferred = append(preferred, member)
+			}
+		}
+
+		if len(preferred) > 0 {
+			candidates = preferred
+		}
+	}
+
+	// Prefer the members in the failure domains running the fewest instances sharing the spread label.
+	spreadKey := config["placement.spread"]
+	spreadValue, found := labels[spreadKey]
+	if spreadKey != "" && found && len(candidates) > 1 {
+		instanceMembers, err := dbCluster.GetInstanceMembersWithLabel(ctx, tx.Tx(), projectName, spreadKey, spreadValue)
+		if err != nil {
+			return nil, err
+		}
+
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		memberDomains, err := tx.GetNodesFailureDomains(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting failure domains: %w", err)
+		}
+
+		memberAddresses := make(map[int64]string, len(allMembers))
+		for _, member := range allMembers {
+			memberAddresses[member.ID] = member.Address
+		}
+
+		domainInstances := map[uint64]int{}
+		for name, memberID := range instanceMembers {
+			if name != instanceName {
+				domainInstances[memberDomains[memberAddresses[memberID]]]++
+			}
+		}
+
+		fewest := -1
+		for _, member := range candidates {
+			count := domainInstances[memberDomains[member.Address]]
+			if fewest == -1 || count < fewest {
+				fewest = count
+			}
+		}
+
+		spread := make([]db.NodeInfo, 0, len(candidates))
+		for _, member := range candidates {
+			if domainInstances[memberDomains[member.Address]] == fewest {
+				spread = append(spread, member)
+			}
+		}
+
+		candidates = spread
+	}
+
+	return candidates, nil
+}
+
+// clusterGroupConstraints returns the instance constraints of the cluster groups defining any.
+func clusterGroupConstraints(ctx context.Context, tx *db.ClusterTx) ([]cluster.GroupConstraints, error) {
+	groups, err := dbCluster.GetClusterGroups(ctx, tx.Tx())
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting cluster groups: %w", err)
+	}
+
+	allConstraints := []cluster.GroupConstraints{}
+	for _, group := range groups {
+		config, err := dbCluster.GetClusterGroupConfig(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		members, err := tx.GetClusterGroupNodes(ctx, group.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting members of cluster group %q: %w", group.Name, err)
+		}
+
+		constraints, err := cluster.GroupConstraintsFromConfig(group.Name, config, members)
+		if err != nil {
+			return nil, err
+		}
+
+		if constraints != nil {
+			allConstraints = append(allConstraints, *constraints)
+		}
+	}
+
+	return allConstraints, nil
+}
+
+// clusterGroupConstraintsCheck returns an error if the constraints of the cluster groups of the member don't allow
+// an instance of one of the given architectures (any if empty) and of the given type.
+func clusterGroupConstraintsCheck(allConstraints []cluster.GroupConstraints, architectures []int, instanceType instancetype.Type, member db.NodeInfo) error {
+	for _, constraints := range allConstraints {
+		if !slices.Contains(constraints.Members, member.Name) {
+			continue
+		}
+
+		// Only consider the architectures supported by the member.
+		personalities, err := osarch.ArchitecturePersonalities(member.Architecture)
+		if err != nil {
+			return err
+		}
+
+		memberArchitectures := []int{}
+		for _, architecture := range append([]int{member.Architecture}, personalities...) {
+			if len(architectures) == 0 || slices.Contains(architectures, architecture) {
+				memberArchitectures = append(memberArchitectures, architecture)
+			}
+		}
+
+		err = constraints.Check(memberArchitectures, instanceType)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instanceGroupConstraintCandidates excludes the candidate cluster members belonging to a cluster group whose
+// constraints don't allow an instance of one of the given architectures (any if empty) and of the given type.
+// If the constraints exclude all the candidates, a not found error explaining why each member was excluded is returned.
+func instanceGroupConstraintCandidates(allConstraints []cluster.GroupConstraints, architectures []int, instanceType instancetype.Type, candidates []db.NodeInfo) ([]db.NodeInfo, error) {
+	if len(candidates) == 0 || len(allConstraints) == 0 {
+		return candidates, nil
+	}
+
+	var explanation []string
+	remaining := make([]db.NodeInfo, 0, len(candidates))
+	for _, member := range candidates {
+		err := clusterGroupConstraintsCheck(allConstraints, architectures, instanceType, member)
+		if err != nil {
+			explanation = append(explanation, fmt.Sprintf("Member %q: %v", member.Name, err))
+			continue
+		}
+
+		remaining = append(remaining, member)
+	}
+
+	if len(remaining) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "No suitable cluster member could be found: %s", strings.Join(explanation, ", "))
+	}
+
+	return remaining, nil
+}
@@ -10,6 +10,7 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 
 	"github.com/gorilla/mux"
 
@@ -215,6 +216,19 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
+			groupConstraints, err := clusterGroupConstraints(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			if targetMemberInfo != nil {
+				// Check that the constraints of the cluster groups of the targeted member allow the instance.
+				err = clusterGroupConstraintsCheck(groupConstraints, []int{inst.Architecture()}, inst.Type(), *targetMemberInfo)
+				if err != nil {
+					return api.StatusErrorf(http.StatusBadRequest, "Instance can't be moved to cluster member %q: %w", targetMemberInfo.Name, err)
+				}
+			}
+
 			if targetMemberInfo == nil {
 				clusterGroupsAllowed := limits.GetRestrictedClusterGroups(targetProject)
 
@@ -222,6 +236,16 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				if err != nil {
 					return err
 				}
+
+				candidateMembers, err = instanceGroupConstraintCandidates(groupConstraints, []int{inst.Architecture()}, inst.Type(), candidateMembers)
+				if err != nil {
+					return err
+				}
+
+				candidateMembers, err = instancePlacementCandidates(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), inst.Labels(), candidateMembers)
+				if err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -230,6 +254,14 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		// Only consider the members with enough capacity within their overcommit policy.
+		if targetMemberInfo == nil {
+			candidateMembers, err = instanceOvercommitCandidateMembers(r.Context(), s, candidateMembers, inst.Type(), inst.ExpandedConfig())
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
 		// Pick the member with the least number of instances.
 		if targetMemberInfo == nil {
 			var filteredCandidateMembers []db.NodeInfo
@@ -293,7 +325,31 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Check the live migration tunables are valid.
+	err = instanceValidateMigrationConfig(req.MigrationConfig)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Re-home the instance into another project.
+	if req.TargetProject != "" {
+		if target != "" {
+			return response.BadRequest(errors.New("Moving an instance to another project can't be combined with a target member"))
+		}
+
+		return instancePostProjectMove(r, s, inst, req)
+	}
+
 	if req.Migration {
+		// Server to server instance migration to a remote cluster.
+		if req.Remote != nil {
+			if target != "" || req.Target != nil {
+				return response.BadRequest(errors.New("Migrating an instance to a remote cluster can't be combined with a target"))
+			}
+
+			return instancePostRemote(r, s, inst, req)
+		}
+
 		// Server-side instance migration.
 		if req.Pool != "" || req.Project != "" {
 			// Check if user has access to target project.
@@ -362,6 +418,8 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.InternalError(err)
 		}
 
+		ws.migrationConfig = req.MigrationConfig
+
 		resources := map[string][]api.URL{}
 		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
 
@@ -561,6 +619,7 @@ func instancePostMigration(s *state.State, inst instance.Instance, req api.Insta
 		Architecture: inst.Architecture(),
 		Description:  inst.Description(),
 		Ephemeral:    inst.IsEphemeral(),
+		Labels:       inst.Labels(),
 		Stateful:     inst.IsStateful(),
 	}
 
@@ -588,11 +647,17 @@ func instancePostMigration(s *state.State, inst instance.Instance, req api.Insta
 		return err
 	}
 
-	// Update any permissions relating to the old instance to point to the new instance before it is deleted.
-	// Warnings relating to the old instance will be deleted.
+	// Update any permissions and role grants relating to the old instance to point to the new instance before it is
+	// deleted. Warnings relating to the old instance will be deleted.
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		q := `UPDATE auth_groups_permissions SET entity_id = ? WHERE entity_type = ? AND entity_id = ?`
 		_, err = tx.Tx().ExecContext(ctx, q, targetInst.ID(), dbCluster.EntityType(entity.TypeInstance), inst.ID())
+		if err != nil {
+			return err
+		}
+
+		q = `UPDATE auth_groups_roles SET entity_id = ? WHERE entity_type = ? AND entity_id = ?`
+		_, err = tx.Tx().ExecContext(ctx, q, targetInst.ID(), dbCluster.EntityType(entity.TypeInstance), inst.ID())
 		return err
 	})
 	if err != nil {
@@ -625,7 +690,7 @@ func instancePostMigration(s *state.State, inst instance.Instance, req api.Insta
 
 // Migrate an instance to another cluster node (supports both local and remote storage).
 // Source and target members must be online.
-func instancePostClusteringMigrate(ctx context.Context, s *state.State, srcPool storagePools.Pool, srcInst instance.Instance, newInstName string, srcMember db.NodeInfo, newMember db.NodeInfo, stateful bool, allowInconsistent bool) (func(op *operations.Operation) error, error) {
+func instancePostClusteringMigrate(ctx context.Context, s *state.State, srcPool storagePools.Pool, srcInst instance.Instance, newInstName string, srcMember db.NodeInfo, newMember db.NodeInfo, stateful bool, allowInconsistent bool, migrationConfig map[string]string) (func(op *operations.Operation) error, error) {
 	srcMemberOffline := srcMember.IsOffline(s.GlobalConfig.OfflineThreshold())
 
 	// Make sure that the source member is online if we end up being called from another member after a
@@ -722,6 +787,8 @@ func instancePostClusteringMigrate(ctx context.Context, s *state.State, srcPool
 			return fmt.Errorf("Failed setting up instance migration on source: %w", err)
 		}
 
+		srcMigration.migrationConfig = migrationConfig
+
 		run := func(op *operations.Operation) error {
 			return srcMigration.Do(s, op)
 		}
@@ -978,10 +1045,29 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 		return f(op)
 	}
 
-	f, err := instancePostClusteringMigrate(ctx, s, srcPool, inst, req.Name, srcMember, newMember, req.Live, req.AllowInconsistent)
+	f, err := instancePostClusteringMigrate(ctx, s, srcPool, inst, req.Name, srcMember, newMember, req.Live, req.AllowInconsistent, req.MigrationConfig)
 	if err != nil {
 		return err
 	}
 
 	return f(op)
 }
+
+// instanceValidateMigrationConfig checks that the live migration tunables provided for a single migration only
+// contain supported keys with valid values.
+func instanceValidateMigrationConfig(config map[string]string) error {
+	tunables := []string{"migration.bandwidth", "migration.auto_converge", "migration.postcopy", "migration.downtime_limit"}
+
+	for key, value := range config {
+		if !slices.Contains(tunables, key) {
+			return fmt.Errorf("Invalid migration configuration key %q", key)
+		}
+
+		err := instancetype.InstanceConfigKeysVM[key](value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for migration configuration key %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// instanceRemoteConnect connects to the remote cluster using the given certificate as client certificate.
+// If a trust token is provided, it is first used to add the certificate to the trust store of the remote
+// cluster, after checking the identity of the remote cluster against the fingerprint in the token.
+// Returns the connection, the certificate of the remote cluster and the address used.
+func instanceRemoteConnect(ctx context.Context, s *state.State, serverCert *shared.CertInfo, remote api.InstancePostRemote) (lxd.InstanceServer, string, string, error) {
+	args := &lxd.ConnectionArgs{
+		TLSClientCert: string(serverCert.PublicKey()),
+		TLSClientKey:  string(serverCert.PrivateKey()),
+		TLSServerCert: remote.Certificate,
+		UserAgent:     version.UserAgent,
+		Proxy:         s.Proxy,
+	}
+
+	if remote.Token == "" {
+		if remote.Address == "" || remote.Certificate == "" {
+			return nil, "", "", errors.New("The address and certificate of the remote cluster are required without a trust token")
+		}
+
+		client, err := lxd.ConnectLXD("https://"+remote.Address, args)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("Failed connecting to remote cluster %q: %w", remote.Address, err)
+		}
+
+		return client, remote.Certificate, remote.Address, nil
+	}
+
+	token, err := shared.CertificateTokenDecode(remote.Token)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Invalid trust token: %w", err)
+	}
+
+	addresses := token.Addresses
+	if remote.Address != "" {
+		addresses = []string{remote.Address}
+	}
+
+	// Find a reachable address of the remote cluster presenting the certificate of the token.
+	var address string
+	var lastErr error
+	for _, addr := range addresses {
+		cert, err := shared.GetRemoteCertificate(ctx, "https://"+addr, version.UserAgent)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if shared.CertFingerprint(cert) != token.Fingerprint {
+			lastErr = fmt.Errorf("Certificate fingerprint of %q doesn't match the trust token", addr)
+			continue
+		}
+
+		address = addr
+		args.TLSServerCert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+		break
+	}
+
+	if address == "" {
+		return nil, "", "", fmt.Errorf("Failed connecting to remote cluster: %w", lastErr)
+	}
+
+	client, err := lxd.ConnectLXD("https://"+address, args)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Failed connecting to remote cluster %q: %w", address, err)
+	}
+
+	block, _ := pem.Decode(serverCert.PublicKey())
+	if block == nil {
+		return nil, "", "", errors.New("Failed to decode server certificate")
+	}
+
+	post := api.CertificatesPost{
+		Name:        token.ClientName,
+		Type:        api.CertificateTypeClient,
+		Certificate: base64.StdEncoding.EncodeToString(block.Bytes),
+		TrustToken:  remote.Token,
+	}
+
+	err = client.CreateCertificate(post)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusConflict) {
+		return nil, "", "", fmt.Errorf("Failed adding server certificate to remote cluster: %w", err)
+	}
+
+	// Reconnect now that the server is trusted by the remote cluster.
+	client, err = lxd.ConnectLXD("https://"+address, args)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Failed connecting to remote cluster %q: %w", address, err)
+	}
+
+	return client, args.TLSServerCert, address, nil
+}
+
+// instanceRemotePush creates the instance on the remote cluster using the push migration mode and sends the
+// instance volumes (and runtime state if live) to it, waiting for the remote cluster to finish creating it.
+// Cancelling the context stops waiting for the remote cluster and cancels its operation.
+func instanceRemotePush(ctx context.Context, s *state.State, op *operations.Operation, client lxd.InstanceServer, remoteCert string, address string, inst instance.Instance, instReq api.InstancesPost, migrationConfig map[string]string) error {
+	targetOp, err := client.CreateInstance(instReq)
+	if err != nil {
+		return fmt.Errorf("Failed creating instance on remote cluster: %w", err)
+	}
+
+	targetOpAPI := targetOp.Get()
+
+	targetSecrets := map[string]string{}
+	for k, v := range targetOpAPI.Metadata {
+		vStr, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		targetSecrets[k] = vStr
+	}
+
+	pushTarget := &api.InstancePostTarget{
+		Certificate: remoteCert,
+		Operation:   "https://" + address + "/" + version.APIVersion + "/operations/" + url.PathEscape(targetOpAPI.ID),
+		Websockets:  targetSecrets,
+	}
+
+	ws, err := newMigrationSource(inst, instReq.Source.Live, instReq.Source.InstanceOnly, instReq.Source.AllowInconsistent, "", pushTarget)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return err
+	}
+
+	ws.migrationConfig = migrationConfig
+
+	err = ws.Do(s, op)
+	if err != nil {
+		_ = targetOp.Cancel()
+		return fmt.Errorf("Failed sending instance to remote cluster: %w", err)
+	}
+
+	err = targetOp.WaitContext(ctx)
+	if err != nil {
+		// Stop the remote side too if the operation was cancelled.
+		if ctx.Err() != nil {
+			_ = targetOp.Cancel()
+		}
+
+		return fmt.Errorf("Failed creating instance on remote cluster: %w", err)
+	}
+
+	return nil
+}
+
+// instancePostRemoteValidate checks that the caller can migrate an instance of the given type and state, in a project
+// with the given configuration, to a remote cluster with the given request.
+// It returns an [api.StatusError] with [http.StatusForbidden] or [http.StatusBadRequest] if the migration is refused.
+func instancePostRemoteValidate(ctx context.Context, authorizer auth.Authorizer, projectConfig map[string]string, instType instancetype.Type, running bool, req api.InstancePost) error {
+	// The server authenticates against the remote cluster with its own certificate, so this is limited to server
+	// administrators and never allowed from restricted projects.
+	if shared.IsTrue(projectConfig["restricted"]) {
+		return api.StatusErrorf(http.StatusForbidden, "Migrating instances to a remote cluster isn't allowed in restricted projects")
+	}
+
+	err := authorizer.CheckPermission(ctx, entity.ServerURL(), auth.EntitlementAdmin)
+	if err != nil {
+		return err
+	}
+
+	if req.Remote.Token == "" && req.Remote.Address == "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Either a trust token or an address is required for the remote cluster")
+	}
+
+	if running && !req.Live {
+		return api.StatusErrorf(http.StatusBadRequest, "Instance must be stopped to be migrated to a remote cluster without live migration")
+	}
+
+	if running && instType == instancetype.Container {
+		return api.StatusErrorf(http.StatusBadRequest, "Live migration to a remote cluster is not supported for containers")
+	}
+
+	return nil
+}
+
+// instancePostRemote migrates the instance to a remote cluster. The instance is created on the remote cluster in
+// push mode and its volumes (and runtime state if live) are sent directly from this server, before removing the
+// local instance.
+func instancePostRemote(r *http.Request, s *state.State, inst instance.Instance, req api.InstancePost) response.Response {
+	remote := *req.Remote
+
+	err := instancePostRemoteValidate(r.Context(), s.Authorizer, inst.Project().Config, inst.Type(), inst.IsRunning(), req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	render, _, err := inst.Render()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	apiInst, ok := render.(*api.Instance)
+	if !ok {
+		return response.InternalError(errors.New("Unexpected instance representation"))
+	}
+
+	// We keep the req.ContainerOnly for backward compatibility.
+	instanceOnly := req.InstanceOnly || req.ContainerOnly //nolint:staticcheck,unused
+
+	instReq := api.InstancesPost{
+		Name:        req.Name,
+		InstancePut: apiInst.Writable(),
+		Type:        api.InstanceType(apiInst.Type),
+		Source: api.InstanceSource{
+			Type:              api.SourceTypeMigration,
+			Mode:              "push",
+			BaseImage:         apiInst.Config["volatile.base_image"],
+			Live:              req.Live && inst.IsRunning(),
+			InstanceOnly:      instanceOnly,
+			AllowInconsistent: req.AllowInconsistent,
+		},
+	}
+
+	// Apply the configuration overrides.
+	if req.Config != nil {
+		instReq.Config = req.Config
+	}
+
+	if req.Devices != nil {
+		instReq.Devices = req.Devices
+	}
+
+	if req.Profiles != nil {
+		instReq.Profiles = req.Profiles
+	}
+
+	// Create the root disk in the requested storage pool.
+	if remote.Pool != "" {
+		devices := maps.Clone(instReq.Devices)
+		if devices == nil {
+			devices = map[string]map[string]string{}
+		}
+
+		rootDevName, rootDev, err := instancetype.GetRootDiskDevice(apiInst.ExpandedDevices)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		rootDev = maps.Clone(rootDev)
+		rootDev["pool"] = remote.Pool
+		devices[rootDevName] = rootDev
+		instReq.Devices = devices
+	}
+
+	run := func(op *operations.Operation) error {
+		ctx := op.Context()
+
+		client, remoteCert, address, err := instanceRemoteConnect(ctx, s, s.ServerCert(), remote)
+		if err != nil {
+			return err
+		}
+
+		if remote.Project != "" {
+			client = client.UseProject(remote.Project)
+		}
+
+		if remote.Target != "" {
+			client = client.UseTarget(remote.Target)
+		}
+
+		err = instanceRemotePush(ctx, s, op, client, remoteCert, address, inst, instReq, req.MigrationConfig)
+		if err != nil {
+			return err
+		}
+
+		// Only remove the local instance once the remote cluster confirms it has the instance.
+		_, _, err = client.GetInstance(instReq.Name)
+		if err != nil {
+			return fmt.Errorf("Failed confirming instance on remote cluster, keeping local instance: %w", err)
+		}
+
+		// Remove the local instance now that it lives on the remote cluster.
+		if inst.IsRunning() {
+			err = inst.Stop(false)
+			if err != nil {
+				logger.Warn("Failed stopping migrated instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			}
+		}
+
+		err = inst.Delete(true)
+		if err != nil {
+			return fmt.Errorf("Failed deleting local instance after migration to remote cluster: %w", err)
+		}
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	if inst.Type() == instancetype.Container {
+		resources["containers"] = resources["instances"]
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceMigrate, resources, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
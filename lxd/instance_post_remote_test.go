@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// remoteTestAuthorizer only grants the admin entitlement on the server to administrators.
+type remoteTestAuthorizer struct {
+	auth.Authorizer
+
+	admin bool
+}
+
+func (a remoteTestAuthorizer) CheckPermission(ctx context.Context, entityURL *api.URL, entitlement auth.Entitlement) error {
+	if a.admin {
+		return nil
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "User does not have entitlement %q on entity %q", entitlement, entityURL.String())
+}
+
+func Test_instancePostRemoteValidate(t *testing.T) {
+	withAddress := api.InstancePost{Remote: &api.InstancePostRemote{Address: "10.0.0.1:8443"}}
+
+	tests := []struct {
+		name          string
+		admin         bool
+		projectConfig map[string]string
+		instType      instancetype.Type
+		running       bool
+		req           api.InstancePost
+		statusCode    int
+	}{
+		{
+			name:     "Stopped instance",
+			admin:    true,
+			instType: instancetype.Container,
+			req:      withAddress,
+		},
+		{
+			name:          "Restricted project",
+			admin:         true,
+			projectConfig: map[string]string{"restricted": "true"},
+			instType:      instancetype.Container,
+			req:           withAddress,
+			statusCode:    http.StatusForbidden,
+		},
+		{
+			name:       "Not a server administrator",
+			instType:   instancetype.Container,
+			req:        withAddress,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "No remote address or token",
+			admin:      true,
+			instType:   instancetype.Container,
+			req:        api.InstancePost{Remote: &api.InstancePostRemote{}},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Running instance without live migration",
+			admin:      true,
+			instType:   instancetype.VM,
+			running:    true,
+			req:        withAddress,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Live migration of a container",
+			admin:      true,
+			instType:   instancetype.Container,
+			running:    true,
+			req:        api.InstancePost{Live: true, Remote: withAddress.Remote},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Live migration of a virtual machine",
+			admin:    true,
+			instType: instancetype.VM,
+			running:  true,
+			req:      api.InstancePost{Live: true, Remote: withAddress.Remote},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := instancePostRemoteValidate(context.Background(), remoteTestAuthorizer{admin: tt.admin}, tt.projectConfig, tt.instType, tt.running, tt.req)
+			if tt.statusCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, api.StatusErrorCheck(err, tt.statusCode), "Unexpected error %v", err)
+		})
+	}
+}
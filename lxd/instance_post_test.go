@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_instanceValidateMigrationConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr bool
+	}{
+		{name: "No overrides", config: nil},
+		{name: "All tunables", config: map[string]string{"migration.bandwidth": "100MiB", "migration.auto_converge": "false", "migration.postcopy": "true", "migration.downtime_limit": "500"}},
+		{name: "Invalid bandwidth", config: map[string]string{"migration.bandwidth": "fast"}, wantErr: true},
+		{name: "Invalid auto-converge", config: map[string]string{"migration.auto_converge": "maybe"}, wantErr: true},
+		{name: "Invalid post-copy", config: map[string]string{"migration.postcopy": "maybe"}, wantErr: true},
+		{name: "Negative downtime limit", config: map[string]string{"migration.downtime_limit": "-1"}, wantErr: true},
+		{name: "Downtime limit out of range", config: map[string]string{"migration.downtime_limit": "4294967296"}, wantErr: true},
+		{name: "Other instance option", config: map[string]string{"migration.stateful": "true"}, wantErr: true},
+		{name: "Unknown option", config: map[string]string{"limits.cpu": "2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := instanceValidateMigrationConfig(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
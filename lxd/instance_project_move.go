@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/instance/operationlock"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// instanceProjectMovePlan describes how an instance is re-homed into another project.
+type instanceProjectMovePlan struct {
+	project         *api.Project
+	profiles        []api.Profile
+	keptProfiles    []string
+	droppedProfiles []string
+	localDevices    deviceConfig.Devices
+}
+
+// metadata returns the operation metadata reporting the remapping of the instance profiles.
+func (p *instanceProjectMovePlan) metadata() map[string]any {
+	return map[string]any{
+		"target_project":   p.project.Name,
+		"profiles_kept":    p.keptProfiles,
+		"profiles_dropped": p.droppedProfiles,
+	}
+}
+
+// instancePostProjectMove checks that the instance can be moved to the requested project and starts the move.
+func instancePostProjectMove(r *http.Request, s *state.State, inst instance.Instance, req api.InstancePost) response.Response {
+	if req.Migration || req.Name != inst.Name() || req.Pool != "" || req.Project != "" {
+		return response.BadRequest(errors.New("Moving an instance to another project can't be combined with a rename or a migration"))
+	}
+
+	if req.TargetProject == inst.Project().Name {
+		return response.BadRequest(errors.New("The instance is already in the target project"))
+	}
+
+	if inst.IsRunning() {
+		return response.BadRequest(errors.New("Instance must be stopped to be moved to another project"))
+	}
+
+	// The instance is removed from its current project and created in the target one.
+	err := s.Authorizer.CheckPermission(r.Context(), entity.InstanceURL(inst.Project().Name, inst.Name()), auth.EntitlementCanDelete)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), entity.ProjectURL(req.TargetProject), auth.EntitlementCanCreateInstances)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	plan, err := instanceProjectMovePrepare(r.Context(), s, inst, req.TargetProject)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		return instanceProjectMove(s, inst, plan, op)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	if inst.Type() == instancetype.Container {
+		resources["containers"] = resources["instances"]
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceMigrate, resources, plan.metadata(), run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// instanceProjectMovePrepare remaps the instance profiles to the target project and checks for conflicts with
+// the target project's instances, restrictions, networks and storage volumes.
+func instanceProjectMovePrepare(ctx context.Context, s *state.State, inst instance.Instance, targetProjectName string) (*instanceProjectMovePlan, error) {
+	plan := &instanceProjectMovePlan{
+		keptProfiles:    []string{},
+		droppedProfiles: []string{},
+		localDevices:    inst.LocalDevices().Clone(),
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), targetProjectName)
+		if err != nil {
+			return fmt.Errorf("Failed loading target project %q: %w", targetProjectName, err)
+		}
+
+		plan.project, err = dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		// Check that the name isn't already in use in the target project.
+		_, err = dbCluster.GetInstanceID(ctx, tx.Tx(), targetProjectName, inst.Name())
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Instance %q already exists in project %q", inst.Name(), targetProjectName)
+		} else if !response.IsNotFoundError(err) {
+			return err
+		}
+
+		// Backups are stored per project.
+		backups, err := tx.GetInstanceBackups(ctx, inst.Project().Name, inst.Name())
+		if err != nil {
+			return fmt.Errorf("Failed to fetch instance's backups: %w", err)
+		}
+
+		if len(backups) > 0 {
+			return api.StatusErrorf(http.StatusConflict, "Instance has backups")
+		}
+
+		// Remap the profiles by name, dropping the ones that don't exist in the target project.
+		profilesProjectName := targetProjectName
+		hasProfiles, err := dbCluster.ProjectHasProfiles(ctx, tx.Tx(), targetProjectName)
+		if err != nil {
+			return err
+		}
+
+		if !hasProfiles {
+			profilesProjectName = api.ProjectDefaultName
+		}
+
+		profileConfigs, err := dbCluster.GetConfig(ctx, tx.Tx(), "profile")
+		if err != nil {
+			return err
+		}
+
+		profileDevices, err := dbCluster.GetDevices(ctx, tx.Tx(), "profile")
+		if err != nil {
+			return err
+		}
+
+		for _, instProfile := range inst.Profiles() {
+			profile, err := dbCluster.GetProfile(ctx, tx.Tx(), profilesProjectName, instProfile.Name)
+			if response.IsNotFoundError(err) {
+				plan.droppedProfiles = append(plan.droppedProfiles, instProfile.Name)
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			apiProfile, err := profile.ToAPI(ctx, tx.Tx(), profileConfigs, profileDevices)
+			if err != nil {
+				return err
+			}
+
+			plan.profiles = append(plan.profiles, *apiProfile)
+			plan.keptProfiles = append(plan.keptProfiles, instProfile.Name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the current root disk device if the remapped profiles would change it, as the root volume stays
+	// in its pool.
+	_, _, err = instancetype.GetRootDiskDevice(plan.localDevices.CloneNative())
+	if errors.Is(err, instancetype.ErrNoRootDisk) {
+		rootDevKey, rootDev, err := instancetype.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+		if err != nil {
+			return nil, err
+		}
+
+		_, profileRootDev, err := instancetype.GetRootDiskDevice(instancetype.ExpandInstanceDevices(deviceConfig.Devices{}, plan.profiles).CloneNative())
+		if err != nil ||
+			profileRootDev["pool"] != rootDev["pool"] ||
+			profileRootDev["size"] != rootDev["size"] ||
+			profileRootDev["size.state"] != rootDev["size.state"] {
+			plan.localDevices[rootDevKey] = rootDev
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Check the target project's limits and restrictions.
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		req := api.InstancesPost{
+			Name: inst.Name(),
+			Type: api.InstanceType(inst.Type().String()),
+			InstancePut: api.InstancePut{
+				Config:   inst.LocalConfig(),
+				Devices:  plan.localDevices.CloneNative(),
+				Profiles: plan.keptProfiles,
+			},
+			Source: api.InstanceSource{Type: api.SourceTypeCopy},
+		}
+
+		return limits.AllowInstanceCreation(ctx, s.GlobalConfig, tx, targetProjectName, req)
+	})
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "Instance doesn't comply with the restrictions of project %q: %w", targetProjectName, err)
+	}
+
+	// Check that the networks and storage volumes referenced by the devices are available in the target project.
+	expandedDevices := instancetype.ExpandInstanceDevices(plan.localDevices, plan.profiles)
+	err = instance.ValidDevices(s, *plan.project, inst.Type(), plan.localDevices, expandedDevices)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "Instance devices conflict with project %q: %w", targetProjectName, err)
+	}
+
+	return plan, nil
+}
+
+// instanceProjectMove re-homes a stopped instance, along with its snapshots and root volume, into another project.
+func instanceProjectMove(s *state.State, inst instance.Instance, plan *instanceProjectMovePlan, op *operations.Operation) error {
+	oldProjectName := inst.Project().Name
+
+	// Prevent the instance from being started (or modified) while it's moved.
+	opLock, err := operationlock.Create(oldProjectName, inst.Name(), operationlock.ActionUpdate, false, false)
+	if err != nil {
+		return err
+	}
+
+	defer opLock.Done(nil)
+
+	if inst.IsRunning() {
+		return errors.New("Instance must be stopped to be moved to another project")
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	cleanup, err := pool.MoveInstanceProject(inst, plan.project.Name, op)
+	if err != nil {
+		return fmt.Errorf("Failed moving instance volume: %w", err)
+	}
+
+	revert.Add(cleanup)
+
+	devices, err := dbCluster.APIToDevices(plan.localDevices.CloneNative())
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.UpdateInstanceProject(ctx, oldProjectName, inst.Name(), plan.project.Name)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateInstanceDevices(ctx, tx.Tx(), int64(inst.ID()), devices)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateInstanceProfiles(ctx, tx.Tx(), inst.ID(), plan.project.Name, plan.keptProfiles)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed moving instance record: %w", err)
+	}
+
+	revert.Success()
+
+	// Move the logs along with the instance.
+	newLogPath := shared.LogPath(project.Instance(plan.project.Name, inst.Name()))
+	if shared.PathExists(inst.LogPath()) {
+		_ = os.RemoveAll(newLogPath)
+		err = os.Rename(inst.LogPath(), newLogPath)
+		if err != nil {
+			logger.Warn("Failed moving instance logs", logger.Ctx{"project": oldProjectName, "instance": inst.Name(), "err": err})
+		}
+	}
+
+	newInst, err := instance.LoadByProjectAndName(s, plan.project.Name, inst.Name())
+	if err != nil {
+		return fmt.Errorf("Failed loading moved instance: %w", err)
+	}
+
+	err = newInst.UpdateBackupFile()
+	if err != nil {
+		return fmt.Errorf("Failed updating backup file: %w", err)
+	}
+
+	s.Events.SendLifecycle(plan.project.Name, lifecycle.InstanceUpdated.Event(newInst, map[string]any{"old_project": oldProjectName}))
+
+	return nil
+}
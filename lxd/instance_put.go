@@ -24,6 +24,7 @@ import (
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/osarch"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/version"
@@ -123,6 +124,13 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if configRaw.Restore == "" {
+		err = admitRequest(r.Context(), s, projectName, entity.TypeInstance, "update", name, &configRaw)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	architecture, err := osarch.ArchitectureId(configRaw.Architecture)
 	if err != nil {
 		architecture = 0
@@ -174,6 +182,7 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 				Description:  configRaw.Description,
 				Devices:      deviceConfig.NewDevices(configRaw.Devices),
 				Ephemeral:    configRaw.Ephemeral,
+				Labels:       configRaw.Labels,
 				Profiles:     apiProfiles,
 				Project:      projectName,
 			}
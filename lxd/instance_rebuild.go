@@ -7,19 +7,25 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -28,6 +34,8 @@ import (
 //	Rebuild an instance
 //
 //	Rebuild an instance using an alternate image or as empty.
+//	The instance configuration and devices can optionally be reset (keeping selected disk devices and user.* keys)
+//	and a snapshot can be taken beforehand so that the instance is restored if the rebuild fails.
 //	---
 //	consumes:
 //	  - application/octet-stream
@@ -136,11 +144,19 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Instance must be stopped to be rebuilt"))
 	}
 
-	run := func(op *operations.Operation) error {
-		if req.Source.Type == api.SourceTypeNone {
-			return instanceRebuildFromEmpty(inst, op)
+	if !req.Reset && (len(req.PreserveDevices) > 0 || req.PreserveUserConfig) {
+		return response.BadRequest(errors.New("Devices and user configuration can only be preserved when resetting the instance"))
+	}
+
+	localDevices := inst.LocalDevices()
+	for _, devName := range req.PreserveDevices {
+		dev, found := localDevices[devName]
+		if !found || dev["type"] != "disk" {
+			return response.BadRequest(fmt.Errorf("Instance has no local disk device %q", devName))
 		}
+	}
 
+	run := func(op *operations.Operation) error {
 		if req.Source.Server != "" {
 			sourceImage, err = ensureDownloadedImageFitWithinBudget(r.Context(), s, op, *targetProject, sourceImageRef, req.Source, inst.Type().String())
 			if err != nil {
@@ -148,11 +164,50 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		if sourceImage == nil {
+		if req.Source.Type != api.SourceTypeNone && sourceImage == nil {
 			return errors.New("Image not provided for instance rebuild")
 		}
 
-		return instanceRebuildFromImage(r.Context(), s, inst, sourceImage, op)
+		revert := revert.New()
+		defer revert.Fail()
+
+		if req.Snapshot {
+			snap, err := instanceRebuildSnapshot(s, inst)
+			if err != nil {
+				return err
+			}
+
+			// Roll the instance back to its state from before the rebuild if anything fails.
+			revert.Add(func() {
+				err := inst.Restore(snap, false)
+				if err != nil {
+					logger.Error("Failed restoring instance after failed rebuild", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "snapshot": snap.Name(), "err": err})
+					return
+				}
+
+				_ = snap.Delete(true)
+			})
+		}
+
+		if req.Reset {
+			err = instanceRebuildReset(inst, req.PreserveDevices, req.PreserveUserConfig)
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.Source.Type == api.SourceTypeNone {
+			err = instanceRebuildFromEmpty(inst, op)
+		} else {
+			err = instanceRebuildFromImage(r.Context(), s, inst, sourceImage, sourceImageProject(targetProject.Name, req.Source), op)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		revert.Success()
+		return nil
 	}
 
 	resources := map[string][]api.URL{}
@@ -169,3 +224,60 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 
 	return operations.OperationResponse(op)
 }
+
+// instanceRebuildSnapshot takes a snapshot of the instance prior to rebuilding it and returns it.
+func instanceRebuildSnapshot(s *state.State, inst instance.Instance) (instance.Instance, error) {
+	snapName, err := instance.NextSnapshotName(s, inst, "snap%d")
+	if err != nil {
+		return nil, err
+	}
+
+	err = inst.Snapshot(snapName, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed snapshotting instance before rebuild: %w", err)
+	}
+
+	return instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name()+shared.SnapshotDelimiter+snapName)
+}
+
+// instanceRebuildReset clears the local configuration and devices of the instance ahead of a rebuild.
+// The volatile keys and root disk device are always kept, as well as the given disk devices and the
+// user.* keys if requested.
+func instanceRebuildReset(inst instance.Instance, preserveDevices []string, preserveUserConfig bool) error {
+	config := map[string]string{}
+	for key, value := range inst.LocalConfig() {
+		if strings.HasPrefix(key, instancetype.ConfigVolatilePrefix) || (preserveUserConfig && strings.HasPrefix(key, "user.")) {
+			config[key] = value
+		}
+	}
+
+	localDevices := inst.LocalDevices()
+	rootDiskName, _, _ := instancetype.GetRootDiskDevice(localDevices.CloneNative())
+
+	devices := deviceConfig.Devices{}
+	for devName, dev := range localDevices {
+		if devName == rootDiskName || slices.Contains(preserveDevices, devName) {
+			devices[devName] = dev
+		}
+	}
+
+	args := db.InstanceArgs{
+		Architecture: inst.Architecture(),
+		Config:       config,
+		Description:  inst.Description(),
+		Devices:      devices,
+		Ephemeral:    inst.IsEphemeral(),
+		Labels:       inst.Labels(),
+		Profiles:     inst.Profiles(),
+		Project:      inst.Project().Name,
+		Type:         inst.Type(),
+		Snapshot:     inst.IsSnapshot(),
+	}
+
+	err := inst.Update(args, true)
+	if err != nil {
+		return fmt.Errorf("Failed resetting instance configuration: %w", err)
+	}
+
+	return nil
+}
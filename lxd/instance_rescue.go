@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var instanceRescueCmd = APIEndpoint{
+	Name:        "instanceRescue",
+	Path:        "instances/{name}/rescue",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "vmRescue", Path: "virtual-machines/{name}/rescue"},
+	},
+
+	Post:   APIEndpointAction{Handler: instanceRescuePost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+	Delete: APIEndpointAction{Handler: instanceRescueDelete, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+// instanceRescueDeviceName is the name of the device holding the rescue media (suffixed if already in use).
+const instanceRescueDeviceName = "rescue"
+
+// instanceRescueApply stops the instance if running, applies the given local config and devices and then starts it
+// if requested.
+func instanceRescueApply(inst instance.Instance, config map[string]string, devices deviceConfig.Devices, start bool) error {
+	if inst.IsRunning() {
+		// The guest is likely to be broken, so don't wait for it to shut down.
+		err := inst.Stop(false)
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance: %w", err)
+		}
+	}
+
+	args := db.InstanceArgs{
+		Architecture: inst.Architecture(),
+		Config:       config,
+		Description:  inst.Description(),
+		Devices:      devices,
+		Ephemeral:    inst.IsEphemeral(),
+		Labels:       inst.Labels(),
+		Profiles:     inst.Profiles(),
+		Project:      inst.Project().Name,
+		Type:         inst.Type(),
+		Snapshot:     inst.IsSnapshot(),
+	}
+
+	err := inst.Update(args, true)
+	if err != nil {
+		return err
+	}
+
+	if !start {
+		return nil
+	}
+
+	return inst.Start(false)
+}
+
+// swagger:operation POST /1.0/instances/{name}/rescue instances instance_rescue_post
+//
+//	Boot the instance in rescue mode
+//
+//	Restarts (or starts) the virtual machine with the given custom ISO volume attached first in the boot order,
+//	so that its root disk can be repaired from the rescue system.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: rescue
+//	    description: Rescue request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceRescuePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceRescuePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	inst, resp := instanceLoadLocalVM(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.InstanceRescuePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Pool == "" || req.Volume == "" {
+		return response.BadRequest(errors.New("A storage pool and volume must be provided"))
+	}
+
+	if inst.LocalConfig()["volatile.rescue.device"] != "" {
+		return response.BadRequest(errors.New("Instance is already in rescue mode"))
+	}
+
+	do := func(op *operations.Operation) error {
+		inst.SetOperation(op)
+
+		// Boot from the rescue media before any other device.
+		devices := inst.LocalDevices().Clone()
+		bootPriority := 0
+		for _, dev := range inst.ExpandedDevices() {
+			priority, _ := strconv.Atoi(dev["boot.priority"])
+			bootPriority = max(bootPriority, priority+1)
+		}
+
+		devName := instanceRescueDeviceName
+		for i := 1; inst.ExpandedDevices()[devName] != nil; i++ {
+			devName = fmt.Sprintf("%s%d", instanceRescueDeviceName, i)
+		}
+
+		devices[devName] = deviceConfig.Device{
+			"type":          "disk",
+			"pool":          req.Pool,
+			"source":        req.Volume,
+			"boot.priority": strconv.Itoa(bootPriority),
+		}
+
+		config := maps.Clone(inst.LocalConfig())
+		config["volatile.rescue.device"] = devName
+
+		return instanceRescueApply(inst, config, devices, true)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+	op, err := operations.OperationCreate(r.Context(), s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceRescue, resources, nil, do, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/rescue instances instance_rescue_delete
+//
+//	Leave rescue mode
+//
+//	Detaches the rescue media from the virtual machine and, if it is running, restarts it from its root disk.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceRescueDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	inst, resp := instanceLoadLocalVM(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	devName := inst.LocalConfig()["volatile.rescue.device"]
+	if devName == "" {
+		return response.BadRequest(errors.New("Instance isn't in rescue mode"))
+	}
+
+	do := func(op *operations.Operation) error {
+		inst.SetOperation(op)
+
+		devices := inst.LocalDevices().Clone()
+		delete(devices, devName)
+
+		config := maps.Clone(inst.LocalConfig())
+		delete(config, "volatile.rescue.device")
+
+		// Only boot the instance back from its root disk if it was running.
+		return instanceRescueApply(inst, config, devices, inst.IsRunning())
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+	op, err := operations.OperationCreate(r.Context(), s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceRescue, resources, nil, do, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
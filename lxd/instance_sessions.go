@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/recorder"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var instanceSessionCmd = APIEndpoint{
+	Name:        "instanceSession",
+	Path:        "instances/{name}/logs/sessions/{file}",
+	MetricsType: entity.TypeInstance,
+
+	Get: APIEndpointAction{Handler: instanceSessionGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+var instanceSessionsCmd = APIEndpoint{
+	Name:        "instanceSessions",
+	Path:        "instances/{name}/logs/sessions",
+	MetricsType: entity.TypeInstance,
+
+	Get: APIEndpointAction{Handler: instanceSessionsGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+// instanceSessionRecorder starts recording a session of the given type ("exec" or "console") if the
+// `sessions.recording` configuration of the instance's project requires it. It returns a nil recorder otherwise.
+func instanceSessionRecorder(inst instance.Instance, sessionType string, op *operations.Operation, width int, height int, command []string) (*recorder.Recorder, error) {
+	recorded := shared.SplitNTrimSpace(inst.Project().Config["sessions.recording"], ",", -1, true)
+	if !slices.Contains(recorded, sessionType) {
+		return nil, nil
+	}
+
+	err := os.MkdirAll(inst.SessionsPath(), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating session recordings directory: %w", err)
+	}
+
+	requestor := op.EventLifecycleRequestor()
+	header := recorder.Header{
+		Width:   width,
+		Height:  height,
+		Command: strings.Join(command, " "),
+		Title:   fmt.Sprintf("%s session on instance %q in project %q by %q (%s)", sessionType, inst.Name(), inst.Project().Name, requestor.Username, requestor.Address),
+	}
+
+	return recorder.New(filepath.Join(inst.SessionsPath(), sessionType+"_"+op.ID()+".cast"), header)
+}
+
+// swagger:operation GET /1.0/instances/{name}/logs/sessions instances instance_sessions_get
+//
+//	Get the session recordings
+//
+//	Returns a list of exec and console session recordings (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/instances/foo/logs/sessions/exec_d0a89537-0617-4ed6-a79b-c2e88a970965.cast",
+//	              "/1.0/instances/foo/logs/sessions/console_4f3a1e2b-8c9d-4e5f-a6b7-c8d9e0f1a2b3.cast"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSessionsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instancetype.Any)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	result := []string{}
+
+	dents, err := os.ReadDir(inst.SessionsPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return response.SmartError(err)
+	}
+
+	for _, f := range dents {
+		if !validSessionFileName(f.Name()) {
+			continue
+		}
+
+		result = append(result, "/"+version.APIVersion+"/instances/"+name+"/logs/sessions/"+f.Name())
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation GET /1.0/instances/{name}/logs/sessions/{filename} instances instance_session_get
+//
+//	Get a session recording
+//
+//	Gets the session recording (in the asciicast format).
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw file
+//	     content:
+//	       application/octet-stream:
+//	         schema:
+//	           type: string
+//	           example: some-text
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSessionGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instancetype.Any)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	file, err := url.PathUnescape(mux.Vars(r)["file"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !validSessionFileName(file) {
+		return response.BadRequest(fmt.Errorf("Session recording file name %q not valid", file))
+	}
+
+	ent := response.FileResponseEntry{
+		Path:     filepath.Join(inst.SessionsPath(), file),
+		Filename: file,
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceLogRetrieved.Event(file, inst, request.CreateRequestor(r.Context()), nil))
+
+	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
+}
+
+func validSessionFileName(fName string) bool {
+	if !shared.IsFileName(fName) {
+		return false
+	}
+
+	return strings.HasSuffix(fName, ".cast") && (strings.HasPrefix(fName, "exec_") || strings.HasPrefix(fName, "console_"))
+}
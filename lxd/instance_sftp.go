@@ -33,6 +33,12 @@ import (
 //	produces:
 //	  - application/json
 //	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: disk
+//	    description: Access the files of a stopped virtual machine through its root disk (`ro` or `rw`)
+//	    type: string
+//	    example: ro
 //	responses:
 //	  "101":
 //	    description: Switching protocols to SFTP
@@ -61,6 +67,11 @@ func instanceSFTPHandler(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(api.StatusErrorf(http.StatusBadRequest, "Missing or invalid upgrade header"))
 	}
 
+	disk := r.FormValue("disk")
+	if disk != "" && disk != "ro" && disk != "rw" {
+		return response.BadRequest(fmt.Errorf("Invalid disk access mode %q", disk))
+	}
+
 	// Redirect to correct server if needed.
 	instanceType, err := urlInstanceTypeDetect(r)
 	if err != nil {
@@ -79,7 +90,12 @@ func instanceSFTPHandler(d *Daemon, r *http.Request) response.Response {
 	}
 
 	if client != nil {
-		resp.instConn, err = client.GetInstanceFileSFTPConn(instName)
+		if disk != "" {
+			resp.instConn, err = client.GetInstanceDiskFileSFTPConn(instName, disk == "rw")
+		} else {
+			resp.instConn, err = client.GetInstanceFileSFTPConn(instName)
+		}
+
 		if err != nil {
 			return response.SmartError(err)
 		}
@@ -89,7 +105,17 @@ func instanceSFTPHandler(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
-		resp.instConn, err = inst.FileSFTPConn()
+		if disk != "" {
+			vm, ok := inst.(instance.VM)
+			if !ok {
+				return response.BadRequest(errors.New("Disk file access is only supported for virtual machines"))
+			}
+
+			resp.instConn, err = vm.DiskFileSFTPConn(disk == "rw")
+		} else {
+			resp.instConn, err = inst.FileSFTPConn()
+		}
+
 		if err != nil {
 			return response.SmartError(api.StatusErrorf(http.StatusInternalServerError, "Failed getting instance SFTP connection: %w", err))
 		}
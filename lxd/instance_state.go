@@ -248,6 +248,11 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 			return inst.Unfreeze()
 		}
 
+		err := instanceAutoRestartReset(inst)
+		if err != nil {
+			return err
+		}
+
 		return inst.Start(req.Stateful)
 	case instancetype.Stop:
 		if req.Stateful {
@@ -264,6 +269,11 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 
 		return inst.Shutdown(timeout)
 	case instancetype.Restart:
+		err := instanceAutoRestartReset(inst)
+		if err != nil {
+			return err
+		}
+
 		return inst.Restart(timeout)
 	case instancetype.Freeze:
 		return inst.Freeze()
@@ -273,3 +283,12 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 
 	return fmt.Errorf("Unknown action: '%s'", req.Action)
 }
+
+// instanceAutoRestartReset resets the automatic restart counter of an instance started through the API.
+func instanceAutoRestartReset(inst instance.Instance) error {
+	if inst.LocalConfig()["volatile.autorestart.count"] == "" {
+		return nil
+	}
+
+	return inst.VolatileSet(map[string]string{"volatile.autorestart.count": ""})
+}
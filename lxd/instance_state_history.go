@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var instanceStateHistoryCmd = APIEndpoint{
+	Name:        "instanceStateHistory",
+	Path:        "instances/{name}/state/history",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerStateHistory", Path: "containers/{name}/state/history"},
+		{Name: "vmStateHistory", Path: "virtual-machines/{name}/state/history"},
+	},
+
+	Get: APIEndpointAction{Handler: instanceStateHistoryGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanView, "name")},
+}
+
+// swagger:operation GET /1.0/instances/{name}/state/history instances instance_state_history_get
+//
+//	Get the resource usage history
+//
+//	Gets the resource usage samples (CPU, memory, root disk and network) of the instance over the requested period.
+//	Samples are only collected when `instances.state.history.interval` is set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: period
+//	    description: How far back to return samples for (defaults to 24h)
+//	    type: string
+//	    example: 6h
+//	responses:
+//	  "200":
+//	    description: Resource usage history
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceStateHistory"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceStateHistoryGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	period := 24 * time.Hour
+	if r.FormValue("period") != "" {
+		period, err = time.ParseDuration(r.FormValue("period"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid period: %w", err))
+		}
+
+		if period <= 0 {
+			return response.BadRequest(errors.New("Period must be positive"))
+		}
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	interval, _ := s.GlobalConfig.InstancesStateHistory()
+
+	history := api.InstanceStateHistory{
+		Interval: int64(interval.Seconds()),
+		Samples:  d.instanceStateHistory.Samples(inst.Project().Name, inst.Name(), time.Now().Add(-period)),
+	}
+
+	return response.SyncResponse(true, history)
+}
+
+// instanceStateRecentPeriod is how far back the resource usage samples embedded in recursion=2 instance responses go.
+const instanceStateRecentPeriod = time.Hour
+
+// instanceStateRecentSamples returns the resource usage samples of the instance taken over the last
+// instanceStateRecentPeriod.
+func instanceStateRecentSamples(d *Daemon, inst instance.Instance) []api.InstanceStateSample {
+	return d.instanceStateHistory.Samples(inst.Project().Name, inst.Name(), time.Now().Add(-instanceStateRecentPeriod))
+}
+
+// instanceStateSample returns the current resource usage of a running instance.
+func instanceStateSample(inst instance.Instance, hostInterfaces []net.Interface) (*api.InstanceStateSample, error) {
+	state, err := inst.RenderState(hostInterfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := api.InstanceStateSample{
+		Timestamp:   time.Now(),
+		CPUUsage:    state.CPU.Usage,
+		MemoryUsage: state.Memory.Usage,
+		Processes:   state.Processes,
+	}
+
+	rootDiskName, _, err := instancetype.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+	if err == nil {
+		sample.DiskUsage = state.Disk[rootDiskName].Usage
+	}
+
+	for name, network := range state.Network {
+		if name == "lo" {
+			continue
+		}
+
+		sample.NetworkBytesReceived += network.Counters.BytesReceived
+		sample.NetworkBytesSent += network.Counters.BytesSent
+	}
+
+	return &sample, nil
+}
+
+// instanceStateHistoryTask samples the resource usage of the local running instances at the interval set by
+// `instances.state.history.interval`.
+func instanceStateHistoryTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		interval, retention := s.GlobalConfig.InstancesStateHistory()
+		if interval <= 0 {
+			return
+		}
+
+		size := int(retention / interval)
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Warn("Failed loading instances for resource usage history", logger.Ctx{"err": err})
+			return
+		}
+
+		// Forget about the instances that were deleted or moved away.
+		local := make(map[string]bool, len(instances))
+		for _, inst := range instances {
+			local[inst.Project().Name+"/"+inst.Name()] = true
+		}
+
+		d.instanceStateHistory.Retain(func(projectName string, instanceName string) bool {
+			return local[projectName+"/"+instanceName]
+		})
+
+		hostInterfaces, _ := net.Interfaces()
+
+		// Limit the sampling concurrency to the number of CPU cores.
+		var wg sync.WaitGroup
+		instCh := make(chan instance.Instance)
+		for range runtime.NumCPU() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for inst := range instCh {
+					sample, err := instanceStateSample(inst, hostInterfaces)
+					if err != nil {
+						logger.Debug("Failed sampling instance resource usage", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+						continue
+					}
+
+					d.instanceStateHistory.Record(inst.Project().Name, inst.Name(), *sample, size)
+				}
+			}()
+		}
+
+		for _, inst := range instances {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			select {
+			case instCh <- inst:
+			case <-ctx.Done():
+			}
+		}
+
+		close(instCh)
+		wg.Wait()
+	}
+
+	schedule := func() (time.Duration, error) {
+		interval, _ := d.State().GlobalConfig.InstancesStateHistory()
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
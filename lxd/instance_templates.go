@@ -0,0 +1,806 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/flosch/pongo2"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var instanceTemplatesCmd = APIEndpoint{
+	Path:        "instance-templates",
+	MetricsType: entity.TypeInstance,
+
+	Get:  APIEndpointAction{Handler: instanceTemplatesGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: instanceTemplatesPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances)},
+}
+
+var instanceTemplateCmd = APIEndpoint{
+	Path:        "instance-templates/{name}",
+	MetricsType: entity.TypeInstance,
+
+	Delete: APIEndpointAction{Handler: instanceTemplateDelete, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances)},
+	Get:    APIEndpointAction{Handler: instanceTemplateGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanView)},
+	Patch:  APIEndpointAction{Handler: instanceTemplatePatch, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances)},
+	Post:   APIEndpointAction{Handler: instanceTemplatePost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances)},
+	Put:    APIEndpointAction{Handler: instanceTemplatePut, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances)},
+}
+
+var instanceTemplateInstancesCmd = APIEndpoint{
+	Path:        "instance-templates/{name}/instances",
+	MetricsType: entity.TypeInstance,
+
+	Post: APIEndpointAction{Handler: instanceTemplateInstancesPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateInstances)},
+}
+
+// instanceTemplateParameterName is the pattern that template parameter names must match to be usable as template
+// variables.
+var instanceTemplateParameterName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// instanceTemplateValidateName checks the validity of an instance template name.
+func instanceTemplateValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.Contains(name, "/") {
+		return errors.New("Instance template names may not contain slashes")
+	}
+
+	if slices.Contains([]string{".", ".."}, name) {
+		return fmt.Errorf("Invalid instance template name %q", name)
+	}
+
+	return nil
+}
+
+// instanceTemplateValidate checks the validity of an instance template definition.
+// The instance configuration and devices are only validated once the template gets instantiated, as they may
+// contain parameter placeholders.
+func instanceTemplateValidate(put api.InstanceTemplatePut) error {
+	if put.Type != "" {
+		_, err := instancetype.New(put.Type)
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, param := range put.Parameters {
+		if !instanceTemplateParameterName.MatchString(name) {
+			return fmt.Errorf("Invalid parameter name %q", name)
+		}
+
+		if name == "name" {
+			return errors.New(`The "name" parameter is reserved for the name of the new instance`)
+		}
+
+		if param.Required && param.Default != "" {
+			return fmt.Errorf("Required parameter %q can't have a default value", name)
+		}
+	}
+
+	return nil
+}
+
+// instanceTemplateRender substitutes the parameters of an instance template and returns the request to create a
+// new instance from it.
+func instanceTemplateRender(template api.InstanceTemplate, name string, parameters map[string]string) (*api.InstancesPost, error) {
+	ctx := pongo2.Context{"name": name}
+
+	for key, value := range parameters {
+		_, ok := template.Parameters[key]
+		if !ok {
+			return nil, fmt.Errorf("Unknown parameter %q", key)
+		}
+
+		ctx[key] = value
+	}
+
+	for key, param := range template.Parameters {
+		_, ok := ctx[key]
+		if ok {
+			continue
+		}
+
+		if param.Required {
+			return nil, fmt.Errorf("Missing value for required parameter %q", key)
+		}
+
+		ctx[key] = param.Default
+	}
+
+	render := func(value string) (string, error) {
+		if !strings.Contains(value, "{{") && !strings.Contains(value, "{%") {
+			return value, nil
+		}
+
+		return shared.RenderTemplate(value, ctx)
+	}
+
+	var err error
+	req := api.InstancesPost{
+		Name:   name,
+		Type:   api.InstanceType(template.Type),
+		Source: template.Source,
+		InstancePut: api.InstancePut{
+			Config:   make(map[string]string, len(template.Config)),
+			Devices:  make(map[string]map[string]string, len(template.Devices)),
+			Profiles: make([]string, 0, len(template.Profiles)),
+		},
+	}
+
+	for key, value := range template.Config {
+		req.Config[key], err = render(value)
+		if err != nil {
+			return nil, fmt.Errorf("Failed rendering configuration key %q: %w", key, err)
+		}
+	}
+
+	for devName, dev := range template.Devices {
+		req.Devices[devName] = make(map[string]string, len(dev))
+		for key, value := range dev {
+			req.Devices[devName][key], err = render(value)
+			if err != nil {
+				return nil, fmt.Errorf("Failed rendering key %q of device %q: %w", key, devName, err)
+			}
+		}
+	}
+
+	for _, profile := range template.Profiles {
+		rendered, err := render(profile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed rendering profile %q: %w", profile, err)
+		}
+
+		req.Profiles = append(req.Profiles, rendered)
+	}
+
+	for _, field := range []*string{&req.Source.Alias, &req.Source.Fingerprint, &req.Source.Server, &req.Source.Source} {
+		*field, err = render(*field)
+		if err != nil {
+			return nil, fmt.Errorf("Failed rendering instance source: %w", err)
+		}
+	}
+
+	return &req, nil
+}
+
+// swagger:operation GET /1.0/instance-templates instance-templates instance_templates_get
+//
+//	Get the instance templates
+//
+//	Returns a list of instance templates (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/instance-templates/web-server",
+//	              "/1.0/instance-templates/database"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/instance-templates?recursion=1 instance-templates instance_templates_get_recursion1
+//
+//	Get the instance templates
+//
+//	Returns a list of instance templates (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of instance templates
+//	          items:
+//	            $ref: "#/definitions/InstanceTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplatesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := util.IsRecursionRequest(r)
+
+	var templates []dbCluster.InstanceTemplate
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		templates, err = dbCluster.GetInstanceTemplates(ctx, tx.Tx(), dbCluster.InstanceTemplateFilter{Project: &projectName})
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(templates))
+		for _, template := range templates {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "instance-templates", template.Name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	apiTemplates := make([]*api.InstanceTemplate, 0, len(templates))
+	for _, template := range templates {
+		apiTemplate, err := template.ToAPI()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		apiTemplates = append(apiTemplates, apiTemplate)
+	}
+
+	return response.SyncResponse(true, apiTemplates)
+}
+
+// swagger:operation POST /1.0/instance-templates instance-templates instance_templates_post
+//
+//	Add an instance template
+//
+//	Creates a new instance template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: template
+//	    description: Instance template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceTemplatesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplatesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	req := api.InstanceTemplatesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = instanceTemplateValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = instanceTemplateValidate(req.InstanceTemplatePut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	template, err := dbCluster.InstanceTemplateFromAPI(projectName, req.Name, req.InstanceTemplatePut)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.CreateInstanceTemplate(ctx, tx.Tx(), *template)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed creating instance template %q: %w", req.Name, err))
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+	lc := lifecycle.InstanceTemplateCreated.Event(req.Name, projectName, requestor, nil)
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// instanceTemplateLoad returns the instance template matching the request.
+func instanceTemplateLoad(ctx context.Context, d *Daemon, r *http.Request) (*api.InstanceTemplate, error) {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, err
+	}
+
+	var template *api.InstanceTemplate
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTemplate, err := dbCluster.GetInstanceTemplate(ctx, tx.Tx(), request.ProjectParam(r), name)
+		if err != nil {
+			return err
+		}
+
+		template, err = dbTemplate.ToAPI()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// swagger:operation GET /1.0/instance-templates/{name} instance-templates instance_template_get
+//
+//	Get the instance template
+//
+//	Gets a specific instance template.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Instance template
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplateGet(d *Daemon, r *http.Request) response.Response {
+	template, err := instanceTemplateLoad(r.Context(), d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, template, template.Writable())
+}
+
+// swagger:operation PUT /1.0/instance-templates/{name} instance-templates instance_template_put
+//
+//	Update the instance template
+//
+//	Updates the entire instance template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: template
+//	    description: Instance template definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplatePut(d *Daemon, r *http.Request) response.Response {
+	return instanceTemplateUpdate(d, r, false)
+}
+
+// swagger:operation PATCH /1.0/instance-templates/{name} instance-templates instance_template_patch
+//
+//	Partially update the instance template
+//
+//	Updates a subset of the instance template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: template
+//	    description: Instance template definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplatePatch(d *Daemon, r *http.Request) response.Response {
+	return instanceTemplateUpdate(d, r, true)
+}
+
+// instanceTemplateUpdate implements PUT and PATCH of an instance template.
+// When patching, the fields that aren't part of the request are kept and the configuration, devices and
+// parameters are merged with the existing ones.
+func instanceTemplateUpdate(d *Daemon, r *http.Request, patch bool) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	template, err := instanceTemplateLoad(r.Context(), d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = util.EtagCheck(r, template.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	req := api.InstanceTemplatePut{}
+	if patch {
+		req = template.Writable()
+
+		// Start from copies so the merged maps don't alias the current ones.
+		req.Config = maps.Clone(req.Config)
+		req.Parameters = maps.Clone(req.Parameters)
+		req.Devices = maps.Clone(req.Devices)
+	}
+
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = instanceTemplateValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	dbTemplate, err := dbCluster.InstanceTemplateFromAPI(projectName, template.Name, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.UpdateInstanceTemplate(ctx, tx.Tx(), projectName, template.Name, *dbTemplate)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceTemplateUpdated.Event(template.Name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/instance-templates/{name} instance-templates instance_template_post
+//
+//	Rename the instance template
+//
+//	Renames an existing instance template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: template
+//	    description: Instance template rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceTemplatePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.InstanceTemplatePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = instanceTemplateValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the name isn't already in use.
+		_, err := dbCluster.GetInstanceTemplate(ctx, tx.Tx(), projectName, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Name %q already in use", req.Name)
+		}
+
+		return dbCluster.RenameInstanceTemplate(ctx, tx.Tx(), projectName, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+	lc := lifecycle.InstanceTemplateRenamed.Event(req.Name, projectName, requestor, logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/instance-templates/{name} instance-templates instance_template_delete
+//
+//	Delete the instance template
+//
+//	Removes the instance template.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplateDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteInstanceTemplate(ctx, tx.Tx(), projectName, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceTemplateDeleted.Event(name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/instance-templates/{name}/instances instance-templates instance_template_instances_post
+//
+//	Create an instance from the template
+//
+//	Substitutes the provided parameters into the instance template and creates a new instance from it.
+//	This behaves like a regular instance creation request.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member or group
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: instance
+//	    description: Instance name and template parameters
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceTemplateInstancesPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTemplateInstancesPost(d *Daemon, r *http.Request) response.Response {
+	template, err := instanceTemplateLoad(r.Context(), d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.InstanceTemplateInstancesPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = instancetype.ValidName(req.Name, false)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	createReq, err := instanceTemplateRender(*template, req.Name, req.Parameters)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	createReq.Start = req.Start
+
+	// Hand the rendered request over to the regular instance creation logic.
+	body, err := json.Marshal(createReq)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	createRequest := r.Clone(r.Context())
+	createRequest.Body = io.NopCloser(bytes.NewReader(body))
+	createRequest.ContentLength = int64(len(body))
+	createRequest.Header.Set("Content-Type", "application/json")
+
+	return instancesPost(d, createRequest)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_instanceTemplateRender(t *testing.T) {
+	template := api.InstanceTemplate{
+		InstanceTemplatePut: api.InstanceTemplatePut{
+			Parameters: map[string]api.InstanceTemplateParameter{
+				"memory":  {Default: "2GiB"},
+				"image":   {Required: true},
+				"profile": {},
+			},
+			Type: "container",
+			Source: api.InstanceSource{
+				Type:  api.SourceTypeImage,
+				Alias: "{{ image }}",
+			},
+			Profiles: []string{"default", "{{ profile }}"},
+			Config: map[string]string{
+				"limits.memory": "{{ memory }}",
+				"user.hostname": "{{ name }}.example.com",
+			},
+			Devices: map[string]map[string]string{
+				"root": {"type": "disk", "path": "/", "pool": "{{ image }}-pool"},
+			},
+		},
+		Name: "web",
+	}
+
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    string
+		wantAlias  string
+		wantMemory string
+		profiles   []string
+	}{
+		{
+			name:       "Defaults",
+			parameters: map[string]string{"image": "24.04"},
+			wantAlias:  "24.04",
+			wantMemory: "2GiB",
+			profiles:   []string{"default", ""},
+		},
+		{
+			name:       "All parameters",
+			parameters: map[string]string{"image": "22.04", "memory": "4GiB", "profile": "web"},
+			wantAlias:  "22.04",
+			wantMemory: "4GiB",
+			profiles:   []string{"default", "web"},
+		},
+		{
+			name:       "Unknown parameter",
+			parameters: map[string]string{"image": "24.04", "cpu": "2"},
+			wantErr:    `Unknown parameter "cpu"`,
+		},
+		{
+			name:       "Missing required parameter",
+			parameters: map[string]string{"memory": "4GiB"},
+			wantErr:    `Missing value for required parameter "image"`,
+		},
+		{
+			name:       "Required parameter set to an empty value",
+			parameters: map[string]string{"image": ""},
+			wantAlias:  "",
+			wantMemory: "2GiB",
+			profiles:   []string{"default", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := instanceTemplateRender(template, "web01", tt.parameters)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "web01", req.Name)
+			assert.Equal(t, api.InstanceTypeContainer, req.Type)
+			assert.Equal(t, api.SourceTypeImage, req.Source.Type)
+			assert.Equal(t, tt.wantAlias, req.Source.Alias)
+			assert.Equal(t, tt.wantMemory, req.Config["limits.memory"])
+			assert.Equal(t, "web01.example.com", req.Config["user.hostname"])
+			assert.Equal(t, tt.wantAlias+"-pool", req.Devices["root"]["pool"])
+			assert.Equal(t, "/", req.Devices["root"]["path"])
+			assert.Equal(t, tt.profiles, req.Profiles)
+		})
+	}
+
+	// The template itself isn't modified.
+	assert.Equal(t, "{{ image }}", template.Source.Alias)
+	assert.Equal(t, []string{"default", "{{ profile }}"}, template.Profiles)
+
+	// Rendering errors report the profile as written in the template.
+	template.Profiles = []string{"{{ profile"}
+	_, err := instanceTemplateRender(template, "web01", map[string]string{"image": "24.04"})
+	assert.ErrorContains(t, err, `Failed rendering profile "{{ profile"`)
+}
+
+func Test_instanceTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		put     api.InstanceTemplatePut
+		wantErr bool
+	}{
+		{
+			name: "Valid parameters",
+			put: api.InstanceTemplatePut{Parameters: map[string]api.InstanceTemplateParameter{
+				"memory": {Default: "2GiB"},
+				"image":  {Required: true},
+				"suffix": {},
+			}},
+		},
+		{
+			name:    "Reserved parameter name",
+			put:     api.InstanceTemplatePut{Parameters: map[string]api.InstanceTemplateParameter{"name": {}}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid parameter name",
+			put:     api.InstanceTemplatePut{Parameters: map[string]api.InstanceTemplateParameter{"limits.memory": {}}},
+			wantErr: true,
+		},
+		{
+			name:    "Required parameter with a default value",
+			put:     api.InstanceTemplatePut{Parameters: map[string]api.InstanceTemplateParameter{"memory": {Default: "2GiB", Required: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid instance type",
+			put:     api.InstanceTemplatePut{Type: "invalid"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := instanceTemplateValidate(tt.put)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
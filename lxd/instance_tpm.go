@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/device"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var instanceTPMCmd = APIEndpoint{
+	Name:        "instanceTPM",
+	Path:        "instances/{name}/tpm/{device}",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "containerTPM", Path: "containers/{name}/tpm/{device}"},
+		{Name: "vmTPM", Path: "virtual-machines/{name}/tpm/{device}"},
+	},
+
+	Get:    APIEndpointAction{Handler: instanceTPMGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanManageBackups, "name")},
+	Put:    APIEndpointAction{Handler: instanceTPMPut, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name"), ContentTypes: []string{"application/octet-stream"}},
+	Delete: APIEndpointAction{Handler: instanceTPMDelete, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+// instanceTPMLoad loads the local instance and the name of the TPM device targeted by the request.
+// A non-nil response is returned if the request was forwarded or failed.
+func instanceTPMLoad(s *state.State, r *http.Request) (instance.Instance, string, response.Response) {
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if shared.IsSnapshot(name) {
+		return nil, "", response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	devName, err := url.PathUnescape(mux.Vars(r)["device"])
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if resp != nil {
+		return nil, "", resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	dev, ok := inst.ExpandedDevices()[devName]
+	if !ok || dev["type"] != "tpm" {
+		return nil, "", response.NotFound(fmt.Errorf("TPM device %q not found", devName))
+	}
+
+	return inst, devName, nil
+}
+
+// instanceTPMStateRun mounts the instance volume and calls f with the path of the TPM state directory.
+// The instance must be stopped so that the state isn't modified by the TPM emulator in the meantime.
+func instanceTPMStateRun(s *state.State, inst instance.Instance, devName string, f func(statePath string) error) error {
+	if inst.IsRunning() {
+		return api.StatusErrorf(http.StatusBadRequest, "The instance must be stopped to access its TPM state")
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return err
+	}
+
+	_, err = pool.MountInstance(inst, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = pool.UnmountInstance(inst, nil) }()
+
+	return f(device.TPMStatePath(inst, devName))
+}
+
+// swagger:operation GET /1.0/instances/{name}/tpm/{device} instances instance_tpm_get
+//
+//	Export the TPM state
+//
+//	Downloads the state of the emulated TPM device of the stopped instance as a compressed tarball.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw TPM state tarball
+//	     content:
+//	       application/octet-stream:
+//	         schema:
+//	           type: string
+//	           example: raw data
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTPMGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	inst, devName, resp := instanceTPMLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	// Export to a temporary file first so that failures can still be reported to the client.
+	f, err := os.CreateTemp(s.BackupsStoragePath(inst.Project().Name), backup.WorkingDirPrefix+"_tpm_")
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	cleanup := func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+
+	err = instanceTPMStateRun(s, inst, devName, func(statePath string) error {
+		return device.TPMStateExport(statePath, f)
+	})
+	if err != nil {
+		cleanup()
+		return response.SmartError(err)
+	}
+
+	ent := response.FileResponseEntry{
+		Path:     f.Name(),
+		Filename: "tpm.tar.gz",
+		Cleanup:  cleanup,
+	}
+
+	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
+}
+
+// swagger:operation PUT /1.0/instances/{name}/tpm/{device} instances instance_tpm_put
+//
+//	Import the TPM state
+//
+//	Replaces the state of the emulated TPM device of the stopped instance with the uploaded tarball (as exported by GET).
+//
+//	---
+//	consumes:
+//	  - application/octet-stream
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: raw_file
+//	    description: Raw TPM state tarball
+//	    required: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTPMPut(d *Daemon, r *http.Request) response.Response {
+	// Don't mess with instance while in setup mode.
+	<-d.waitReady.Done()
+
+	s := d.State()
+
+	inst, devName, resp := instanceTPMLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	unlock, err := instanceOperationLock(s.ShutdownCtx, inst.Project().Name, inst.Name())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer unlock()
+
+	err = instanceTPMStateRun(s, inst, devName, func(statePath string) error {
+		return device.TPMStateImport(statePath, r.Body)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/tpm/{device} instances instance_tpm_delete
+//
+//	Reset the TPM state
+//
+//	Removes the state of the emulated TPM device of the stopped instance.
+//	A blank TPM is created the next time the instance starts.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceTPMDelete(d *Daemon, r *http.Request) response.Response {
+	// Don't mess with instance while in setup mode.
+	<-d.waitReady.Done()
+
+	s := d.State()
+
+	inst, devName, resp := instanceTPMLoad(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	unlock, err := instanceOperationLock(s.ShutdownCtx, inst.Project().Name, inst.Name())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer unlock()
+
+	err = instanceTPMStateRun(s, inst, devName, func(statePath string) error {
+		err := os.RemoveAll(statePath)
+		if err != nil {
+			return fmt.Errorf("Failed removing TPM state: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -104,40 +107,9 @@ func instanceUEFIVarsGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponseETag(true, instanceUEFI, etag)
 }
 
-// swagger:operation PUT /1.0/instances/{name}/uefi-vars instances instance_uefi_vars_put
-//
-//	Set the instance's UEFI variables
-//
-//	Sets the UEFI variables for a specific VM.
-//
-//	---
-//	consumes:
-//	  - application/json
-//	produces:
-//	  - application/json
-//	parameters:
-//	  - in: query
-//	    name: project
-//	    description: Project name
-//	    type: string
-//	    example: default
-//	  - in: body
-//	    name: instanceEFI
-//	    description: UEFI variables update request
-//	    schema:
-//	      $ref: "#/definitions/InstanceUEFIVars"
-//	responses:
-//	  "200":
-//	    $ref: "#/responses/EmptySyncResponse"
-//	  "400":
-//	    $ref: "#/responses/BadRequest"
-//	  "403":
-//	    $ref: "#/responses/Forbidden"
-//	  "404":
-//	    $ref: "#/responses/NotFound"
-//	  "500":
-//	    $ref: "#/responses/InternalServerError"
-func instanceUEFIVarsPut(d *Daemon, r *http.Request) response.Response {
+// instanceUEFIVarsChange applies a change to the UEFI variables of the VM targeted by the request.
+// If the restart query parameter is set, a running VM is shut down before the change and started again afterwards.
+func instanceUEFIVarsChange(d *Daemon, r *http.Request, change func(vm instance.VM, current *api.InstanceUEFIVars) error) response.Response {
 	// Don't mess with instance while in setup mode.
 	<-d.waitReady.Done()
 
@@ -160,6 +132,8 @@ func instanceUEFIVarsPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Invalid instance name"))
 	}
 
+	restart := shared.IsTrue(r.FormValue("restart"))
+
 	// Handle requests targeted to a container on a different node
 	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
 	if err != nil {
@@ -186,8 +160,9 @@ func instanceUEFIVarsPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("UEFI variables manipulation supported for VM type instances only"))
 	}
 
-	if inst.IsRunning() {
-		return response.BadRequest(errors.New("UEFI variables editing is allowed for stopped VM instances only"))
+	wasRunning := inst.IsRunning()
+	if wasRunning && !restart {
+		return response.BadRequest(errors.New("UEFI variables editing is allowed for stopped VM instances only (or with restart enabled)"))
 	}
 
 	instanceUEFI, err := inst.(instance.VM).UEFIVars()
@@ -202,16 +177,185 @@ func instanceUEFIVarsPut(d *Daemon, r *http.Request) response.Response {
 		return response.PreconditionFailed(err)
 	}
 
+	if wasRunning {
+		timeout, err := strconv.Atoi(inst.ExpandedConfig()["boot.host_shutdown_timeout"])
+		if err != nil {
+			timeout = evacuateHostShutdownDefaultTimeout
+		}
+
+		err = inst.Shutdown(time.Duration(timeout) * time.Second)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed shutting down instance: %w", err))
+		}
+	}
+
+	err = change(inst.(instance.VM), instanceUEFI)
+	if err != nil {
+		// Bring the instance back up with its unchanged variables.
+		if wasRunning {
+			_ = inst.Start(false)
+		}
+
+		return response.SmartError(err)
+	}
+
+	if wasRunning {
+		err = inst.Start(false)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed starting instance: %w", err))
+		}
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation PUT /1.0/instances/{name}/uefi-vars instances instance_uefi_vars_put
+//
+//	Set the instance's UEFI variables
+//
+//	Sets the UEFI variables for a specific VM.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: restart
+//	    description: Whether to shut down the running VM before the change and start it again afterwards
+//	    type: boolean
+//	    example: true
+//	  - in: body
+//	    name: instanceEFI
+//	    description: UEFI variables update request
+//	    schema:
+//	      $ref: "#/definitions/InstanceUEFIVars"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceUEFIVarsPut(d *Daemon, r *http.Request) response.Response {
 	configRaw := api.InstanceUEFIVars{}
-	err = json.NewDecoder(r.Body).Decode(&configRaw)
+	err := json.NewDecoder(r.Body).Decode(&configRaw)
 	if err != nil {
 		return response.BadRequest(err)
 	}
 
-	err = inst.(instance.VM).UEFIVarsUpdate(configRaw)
+	return instanceUEFIVarsChange(d, r, func(vm instance.VM, _ *api.InstanceUEFIVars) error {
+		return vm.UEFIVarsUpdate(configRaw)
+	})
+}
+
+// swagger:operation PATCH /1.0/instances/{name}/uefi-vars instances instance_uefi_vars_patch
+//
+//	Partially update the instance's UEFI variables
+//
+//	Updates a subset of the UEFI variables for a specific VM (for example `BootOrder` or the Secure Boot `PK`, `KEK`, `db` and `dbx` keys).
+//	Variables with empty data are removed.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: restart
+//	    description: Whether to shut down the running VM before the change and start it again afterwards
+//	    type: boolean
+//	    example: true
+//	  - in: body
+//	    name: instanceEFI
+//	    description: UEFI variables update request
+//	    schema:
+//	      $ref: "#/definitions/InstanceUEFIVars"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceUEFIVarsPatch(d *Daemon, r *http.Request) response.Response {
+	configRaw := api.InstanceUEFIVars{}
+	err := json.NewDecoder(r.Body).Decode(&configRaw)
 	if err != nil {
-		return response.SmartError(err)
+		return response.BadRequest(err)
 	}
 
-	return response.EmptySyncResponse
+	return instanceUEFIVarsChange(d, r, func(vm instance.VM, current *api.InstanceUEFIVars) error {
+		if current.Variables == nil {
+			current.Variables = map[string]api.InstanceUEFIVariable{}
+		}
+
+		for key, variable := range configRaw.Variables {
+			if variable.Data == "" {
+				delete(current.Variables, key)
+				continue
+			}
+
+			current.Variables[key] = variable
+		}
+
+		return vm.UEFIVarsUpdate(*current)
+	})
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/uefi-vars instances instance_uefi_vars_delete
+//
+//	Reset the instance's UEFI variables
+//
+//	Resets the UEFI variables (NVRAM) of a specific VM to the firmware defaults.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: restart
+//	    description: Whether to shut down the running VM before the change and start it again afterwards
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceUEFIVarsDelete(d *Daemon, r *http.Request) response.Response {
+	return instanceUEFIVarsChange(d, r, func(vm instance.VM, _ *api.InstanceUEFIVars) error {
+		return vm.UEFIVarsReset()
+	})
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var instanceWindowsSetupCmd = APIEndpoint{
+	Name:        "instanceWindowsSetup",
+	Path:        "instances/{name}/windows-setup",
+	MetricsType: entity.TypeInstance,
+	Aliases: []APIEndpointAlias{
+		{Name: "vmWindowsSetup", Path: "virtual-machines/{name}/windows-setup"},
+	},
+
+	Post:   APIEndpointAction{Handler: instanceWindowsSetupPost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+	Delete: APIEndpointAction{Handler: instanceWindowsSetupDelete, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+// Names of the devices holding the setup and VirtIO drivers media (suffixed if already in use).
+const (
+	instanceWindowsSetupDeviceName   = "windows-setup"
+	instanceWindowsDriversDeviceName = "windows-drivers"
+)
+
+// instanceWindowsSetupDeviceAdd adds the device to the local devices under the first unused name derived from baseName
+// and returns that name.
+func instanceWindowsSetupDeviceAdd(inst instance.Instance, devices deviceConfig.Devices, baseName string, dev deviceConfig.Device) string {
+	devName := baseName
+	for i := 1; inst.ExpandedDevices()[devName] != nil || devices[devName] != nil; i++ {
+		devName = fmt.Sprintf("%s%d", baseName, i)
+	}
+
+	devices[devName] = dev
+
+	return devName
+}
+
+// instanceWindowsSetupApply applies the given local config and devices to the instance.
+func instanceWindowsSetupApply(inst instance.Instance, config map[string]string, devices deviceConfig.Devices) error {
+	args := db.InstanceArgs{
+		Architecture: inst.Architecture(),
+		Config:       config,
+		Description:  inst.Description(),
+		Devices:      devices,
+		Ephemeral:    inst.IsEphemeral(),
+		Labels:       inst.Labels(),
+		Profiles:     inst.Profiles(),
+		Project:      inst.Project().Name,
+		Type:         inst.Type(),
+		Snapshot:     inst.IsSnapshot(),
+	}
+
+	return inst.Update(args, true)
+}
+
+// swagger:operation POST /1.0/instances/{name}/windows-setup instances instance_windows_setup_post
+//
+//	Prepare the Windows setup media
+//
+//	Attaches the media needed to install Windows in the virtual machine from a raw image:
+//	a generated drive holding the answer file and the lxd-agent install media and, if provided,
+//	the custom ISO volume holding the VirtIO drivers.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: setup
+//	    description: Windows setup request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceWindowsSetupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceWindowsSetupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	inst, resp := instanceLoadLocalVM(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.InstanceWindowsSetupPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if (req.DriversPool == "") != (req.DriversVolume == "") {
+		return response.BadRequest(errors.New("Both the storage pool and volume of the drivers media must be provided"))
+	}
+
+	if inst.LocalConfig()["volatile.windows_setup.devices"] != "" {
+		return response.BadRequest(errors.New("Windows setup media are already attached"))
+	}
+
+	devices := inst.LocalDevices().Clone()
+	devNames := []string{instanceWindowsSetupDeviceAdd(inst, devices, instanceWindowsSetupDeviceName, deviceConfig.Device{"type": "disk", "source": "windows:setup"})}
+	if req.DriversVolume != "" {
+		devNames = append(devNames, instanceWindowsSetupDeviceAdd(inst, devices, instanceWindowsDriversDeviceName, deviceConfig.Device{"type": "disk", "pool": req.DriversPool, "source": req.DriversVolume}))
+	}
+
+	config := maps.Clone(inst.LocalConfig())
+	config["volatile.windows_setup.devices"] = strings.Join(devNames, ",")
+	if req.Unattend != "" {
+		config["windows.unattend"] = req.Unattend
+	}
+
+	err = instanceWindowsSetupApply(inst, config, devices)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/instances/{name}/windows-setup instances instance_windows_setup_delete
+//
+//	Detach the Windows setup media
+//
+//	Detaches the setup and VirtIO drivers media from the virtual machine once Windows is installed.
+//	The answer file is kept in the `windows.unattend` configuration key.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceWindowsSetupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	inst, resp := instanceLoadLocalVM(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	devNames := inst.LocalConfig()["volatile.windows_setup.devices"]
+	if devNames == "" {
+		return response.BadRequest(errors.New("No Windows setup media are attached"))
+	}
+
+	devices := inst.LocalDevices().Clone()
+	for _, devName := range strings.Split(devNames, ",") {
+		delete(devices, devName)
+	}
+
+	config := maps.Clone(inst.LocalConfig())
+	delete(config, "volatile.windows_setup.devices")
+
+	err := instanceWindowsSetupApply(inst, config, devices)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"sync"
@@ -64,8 +65,10 @@ var instanceUEFIVarsCmd = APIEndpoint{
 		{Name: "vmUEFIVars", Path: "virtual-machines/{name}/uefi-vars"},
 	},
 
-	Get: APIEndpointAction{Handler: instanceUEFIVarsGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanView, "name")},
-	Put: APIEndpointAction{Handler: instanceUEFIVarsPut, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+	Get:    APIEndpointAction{Handler: instanceUEFIVarsGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanView, "name")},
+	Put:    APIEndpointAction{Handler: instanceUEFIVarsPut, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+	Patch:  APIEndpointAction{Handler: instanceUEFIVarsPatch, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
+	Delete: APIEndpointAction{Handler: instanceUEFIVarsDelete, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanEdit, "name")},
 }
 
 var instanceRebuildCmd = APIEndpoint{
@@ -267,19 +270,140 @@ func (slice instanceAutostartList) Swap(i, j int) {
 
 var instancesStartMu sync.Mutex
 
+// instancesStartOrder sorts the instances by boot priority and then moves each instance after the instances of the
+// list it depends on (from boot.depends_on). Instances that are part of a dependency cycle keep their priority order.
+func instancesStartOrder(instances []instance.Instance) []instance.Instance {
+	sort.Sort(instanceAutostartList(instances))
+
+	local := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		local[project.Instance(inst.Project().Name, inst.Name())] = true
+	}
+
+	// Get the dependencies of each instance that are part of the list.
+	dependencies := make(map[instance.Instance][]string, len(instances))
+	for _, inst := range instances {
+		deps, _ := instancetype.ParseBootDependencies(inst.ExpandedConfig()["boot.depends_on"])
+		for _, dep := range deps {
+			key := project.Instance(inst.Project().Name, dep.Name)
+			if local[key] {
+				dependencies[inst] = append(dependencies[inst], key)
+			}
+		}
+	}
+
+	ordered := make([]instance.Instance, 0, len(instances))
+	placed := make(map[string]bool, len(instances))
+	remaining := slices.Clone(instances)
+
+	for len(remaining) > 0 {
+		// Pick the first instance (in priority order) whose dependencies have all been placed.
+		next := slices.IndexFunc(remaining, func(inst instance.Instance) bool {
+			for _, key := range dependencies[inst] {
+				if !placed[key] {
+					return false
+				}
+			}
+
+			return true
+		})
+
+		if next < 0 {
+			logger.Warn("Instance boot dependency cycle detected, ignoring dependencies", logger.Ctx{"project": remaining[0].Project().Name, "instance": remaining[0].Name()})
+			next = 0
+		}
+
+		inst := remaining[next]
+		ordered = append(ordered, inst)
+		placed[project.Instance(inst.Project().Name, inst.Name())] = true
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+
+	return ordered
+}
+
+// instanceWaitBootDependencies waits for the dependencies of the instance (from boot.depends_on) to have been
+// started and to be running (or ready), up to boot.depends_on.timeout for each. Dependencies that aren't part of the
+// startup plan or that weren't started are skipped.
+func instanceWaitBootDependencies(s *state.State, plan *instancesAutostartPlan, entry *instanceAutostartEntry) {
+	config := entry.inst.ExpandedConfig()
+
+	deps, _ := instancetype.ParseBootDependencies(config["boot.depends_on"])
+	if len(deps) == 0 {
+		return
+	}
+
+	timeout := 60 * time.Second
+	if config["boot.depends_on.timeout"] != "" {
+		timeoutInt, err := strconv.Atoi(config["boot.depends_on.timeout"])
+		if err == nil {
+			timeout = time.Duration(timeoutInt) * time.Second
+		}
+	}
+
+	instLogger := logger.AddContext(logger.Ctx{"project": entry.inst.Project().Name, "instance": entry.inst.Name()})
+
+	for _, dep := range deps {
+		depEntry := plan.entry(entry.inst.Project().Name, dep.Name)
+		if depEntry == nil {
+			instLogger.Warn("Skipping boot dependency not found on this member", logger.Ctx{"dependency": dep.Name})
+			continue
+		}
+
+		deadline := time.Now().Add(timeout)
+
+		// Wait for the start attempt of the dependency to complete.
+		select {
+		case <-depEntry.done:
+		case <-time.After(timeout):
+			instLogger.Warn("Timed out waiting for boot dependency", logger.Ctx{"dependency": dep.Name, "ready": dep.Ready, "timeout": timeout})
+			continue
+		}
+
+		status := plan.status(depEntry)
+		if status != api.InstanceAutostartStarted && status != api.InstanceAutostartRunning {
+			instLogger.Warn("Skipping boot dependency that isn't starting", logger.Ctx{"dependency": dep.Name})
+			continue
+		}
+
+		for {
+			// Reload the dependency to get its current ready state.
+			depInst, err := instance.LoadByProjectAndName(s, entry.inst.Project().Name, dep.Name)
+			if err == nil && depInst.IsRunning() && (!dep.Ready || shared.IsTrue(depInst.LocalConfig()["volatile.last_state.ready"])) {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				instLogger.Warn("Timed out waiting for boot dependency", logger.Ctx{"dependency": dep.Name, "ready": dep.Ready, "timeout": timeout})
+				break
+			}
+
+			time.Sleep(time.Second)
+		}
+	}
+}
+
 // instanceShouldAutoStart returns whether the instance should be auto-started.
 // Returns true if the conditions below are all met:
 // 1. security.protection.start is not enabled or not set.
 // 2. boot.autostart is enabled or boot.autostart is not set and instance was previously running.
+// 3. The project of the instance isn't disabled.
 func instanceShouldAutoStart(inst instance.Instance) bool {
 	config := inst.ExpandedConfig()
 	autoStart := config["boot.autostart"]
 	lastState := config["volatile.last_state.power"]
 	protectStart := config["security.protection.start"]
 
+	if inst.Project().Status == api.ProjectStatusDisabled {
+		return false
+	}
+
 	return shared.IsFalseOrEmpty(protectStart) && (shared.IsTrue(autoStart) || (autoStart == "" && lastState == instance.PowerStateRunning))
 }
 
+// instancesStart starts the instances that should be auto-started, in the order given by instancesStartOrder.
+// Up to instances.autostart.parallelism instances are started concurrently. An instance is only started once the
+// instances with a higher boot priority and the instances it depends on have been started.
 func instancesStart(s *state.State, instances []instance.Instance) {
 	// Check if the cluster is currently evacuated.
 	if s.DB.Cluster.LocalNodeIsEvacuated() {
@@ -290,74 +414,125 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	instancesStartMu.Lock()
 	defer instancesStartMu.Unlock()
 
-	// Sort based on instance boot priority.
-	sort.Sort(instanceAutostartList(instances))
+	// Sort based on instance boot priority and dependencies.
+	instances = instancesStartOrder(instances)
 
-	// Let's make up to 3 attempts to start instances.
-	maxAttempts := 3
+	plan := newInstancesAutostartPlan(instances, s.LocalConfig.InstancesAutostartParallelism())
+	instancesAutostartPlanSet(plan)
 
-	// Start the instances
-	for _, inst := range instances {
-		if !instanceShouldAutoStart(inst) {
-			continue
-		}
+	var wg sync.WaitGroup
+	entryCh := make(chan *instanceAutostartEntry)
+	for range plan.parallelism {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-		// If already running, we're done.
-		if inst.IsRunning() {
-			continue
+			for entry := range entryCh {
+				instanceAutostart(s, plan, entry)
+			}
+		}()
+	}
+
+	// Entries are handed over in start order so that the instances an entry waits for are already being handled.
+	for _, entry := range plan.entries {
+		entryCh <- entry
+	}
+
+	close(entryCh)
+	wg.Wait()
+
+	plan.finish()
+}
+
+// instanceAutostart starts the instance of the startup plan entry if it should be auto-started.
+func instanceAutostart(s *state.State, plan *instancesAutostartPlan, entry *instanceAutostartEntry) {
+	inst := entry.inst
+
+	// Release the instances waiting for this one once done.
+	defer close(entry.done)
+
+	// If already running, we're done.
+	if inst.IsRunning() {
+		plan.setStatus(entry, api.InstanceAutostartRunning, nil)
+		return
+	}
+
+	if !instanceShouldAutoStart(inst) {
+		plan.setStatus(entry, api.InstanceAutostartSkipped, nil)
+		return
+	}
+
+	// Wait for the instances with a higher priority and for the instances this one depends on.
+	plan.setStatus(entry, api.InstanceAutostartWaiting, nil)
+	for _, other := range plan.entries[:entry.index] {
+		if other.priority > entry.priority {
+			<-other.done
 		}
+	}
 
-		// Get the instance config.
-		config := inst.ExpandedConfig()
-		autoStartDelay := config["boot.autostart.delay"]
+	instanceWaitBootDependencies(s, plan, entry)
 
-		instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+	// Get the instance config.
+	config := inst.ExpandedConfig()
+	autoStartDelay := config["boot.autostart.delay"]
 
-		// Try to start the instance.
-		var attempt = 0
-		for {
-			attempt++
-			err := inst.Start(false)
-			if err != nil {
-				if api.StatusErrorCheck(err, http.StatusServiceUnavailable) {
-					break // Don't log or retry instances that are not ready to start yet.
-				}
+	instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
-				instLogger.Warn("Failed auto start instance attempt", logger.Ctx{"attempt": attempt, "maxAttempts": maxAttempts, "err": err})
+	// Let's make up to 3 attempts to start instances.
+	maxAttempts := 3
 
-				if attempt >= maxAttempts {
-					warnErr := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-						// If unable to start after 3 tries, record a warning.
-						return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, entity.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, err.Error())
-					})
-					if warnErr != nil {
-						instLogger.Warn("Failed to create instance autostart failure warning", logger.Ctx{"err": warnErr})
-					}
+	// Try to start the instance.
+	plan.setStatus(entry, api.InstanceAutostartStarting, nil)
 
-					instLogger.Error("Failed to auto start instance", logger.Ctx{"err": err})
+	var attempt = 0
+	for {
+		attempt++
+		plan.setAttempts(entry, attempt)
 
-					break
+		err := inst.Start(false)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+				plan.setStatus(entry, api.InstanceAutostartSkipped, err)
+				return // Don't log or retry instances that are not ready to start yet.
+			}
+
+			instLogger.Warn("Failed auto start instance attempt", logger.Ctx{"attempt": attempt, "maxAttempts": maxAttempts, "err": err})
+
+			if attempt >= maxAttempts {
+				warnErr := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+					// If unable to start after 3 tries, record a warning.
+					return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, entity.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, err.Error())
+				})
+				if warnErr != nil {
+					instLogger.Warn("Failed to create instance autostart failure warning", logger.Ctx{"err": warnErr})
 				}
 
-				time.Sleep(5 * time.Second)
+				instLogger.Error("Failed to auto start instance", logger.Ctx{"err": err})
+				plan.setStatus(entry, api.InstanceAutostartFailed, err)
 
-				continue
+				return
 			}
 
-			// Resolve any previous warning.
-			warnErr := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, inst.Project().Name, warningtype.InstanceAutostartFailure, entity.TypeInstance, inst.ID())
-			if warnErr != nil {
-				instLogger.Warn("Failed to resolve instance autostart failure warning", logger.Ctx{"err": warnErr})
-			}
+			time.Sleep(5 * time.Second)
 
-			// Wait the auto-start delay if set.
-			autoStartDelayInt, err := strconv.Atoi(autoStartDelay)
-			if err == nil {
-				time.Sleep(time.Duration(autoStartDelayInt) * time.Second)
-			}
+			continue
+		}
+
+		// Resolve any previous warning.
+		warnErr := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, inst.Project().Name, warningtype.InstanceAutostartFailure, entity.TypeInstance, inst.ID())
+		if warnErr != nil {
+			instLogger.Warn("Failed to resolve instance autostart failure warning", logger.Ctx{"err": warnErr})
+		}
 
-			break
+		plan.setStatus(entry, api.InstanceAutostartStarted, nil)
+
+		// Wait the auto-start delay if set, holding back the instances with a lower priority.
+		autoStartDelayInt, err := strconv.Atoi(autoStartDelay)
+		if err == nil {
+			time.Sleep(time.Duration(autoStartDelayInt) * time.Second)
 		}
+
+		return
 	}
 }
 
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var instancesAutostartCmd = APIEndpoint{
+	Path:        "autostart",
+	MetricsType: entity.TypeInstance,
+
+	Get: APIEndpointAction{Handler: instancesAutostartGet, AccessHandler: allowAuthenticated},
+}
+
+// instanceAutostartEntry is an instance of the startup plan.
+type instanceAutostartEntry struct {
+	inst     instance.Instance
+	index    int
+	priority int
+	done     chan struct{} // Closed once the instance has been handled (including the auto-start delay).
+
+	api.InstanceAutostart
+}
+
+// instancesAutostartPlan is the startup sequence of the local instances.
+type instancesAutostartPlan struct {
+	mu sync.Mutex
+
+	parallelism int
+	startedAt   time.Time
+	finishedAt  time.Time
+	entries     []*instanceAutostartEntry
+	byName      map[string]*instanceAutostartEntry
+}
+
+// instancesAutostartLast is the startup plan of the last startup sequence.
+var instancesAutostartLast *instancesAutostartPlan
+var instancesAutostartLastMu sync.Mutex
+
+// newInstancesAutostartPlan returns a startup plan for the instances, which must be in start order.
+func newInstancesAutostartPlan(instances []instance.Instance, parallelism int) *instancesAutostartPlan {
+	plan := &instancesAutostartPlan{
+		parallelism: max(parallelism, 1),
+		startedAt:   time.Now(),
+		entries:     make([]*instanceAutostartEntry, 0, len(instances)),
+		byName:      make(map[string]*instanceAutostartEntry, len(instances)),
+	}
+
+	for i, inst := range instances {
+		config := inst.ExpandedConfig()
+		priority, _ := strconv.Atoi(config["boot.autostart.priority"])
+
+		dependsOn := []string{}
+		if config["boot.depends_on"] != "" {
+			dependsOn = shared.SplitNTrimSpace(config["boot.depends_on"], ",", -1, false)
+		}
+
+		entry := &instanceAutostartEntry{
+			inst:     inst,
+			index:    i,
+			priority: priority,
+			done:     make(chan struct{}),
+			InstanceAutostart: api.InstanceAutostart{
+				Project:   inst.Project().Name,
+				Name:      inst.Name(),
+				Priority:  priority,
+				DependsOn: dependsOn,
+				Status:    api.InstanceAutostartPending,
+			},
+		}
+
+		plan.entries = append(plan.entries, entry)
+		plan.byName[inst.Project().Name+"/"+inst.Name()] = entry
+	}
+
+	return plan
+}
+
+// entry returns the entry of the instance in the plan, or nil if not part of it.
+func (p *instancesAutostartPlan) entry(projectName string, instanceName string) *instanceAutostartEntry {
+	return p.byName[projectName+"/"+instanceName]
+}
+
+// status returns the current status of the entry.
+func (p *instancesAutostartPlan) status(entry *instanceAutostartEntry) api.InstanceAutostartStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return entry.Status
+}
+
+// setStatus updates the status of the entry, recording the given error if any.
+func (p *instancesAutostartPlan) setStatus(entry *instanceAutostartEntry, status api.InstanceAutostartStatus, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch status {
+	case api.InstanceAutostartStarting:
+		entry.StartedAt = time.Now()
+	case api.InstanceAutostartStarted, api.InstanceAutostartFailed:
+		entry.FinishedAt = time.Now()
+	}
+
+	entry.Status = status
+	entry.Error = ""
+	if err != nil {
+		entry.Error = err.Error()
+	}
+}
+
+// setAttempts updates the number of start attempts of the entry.
+func (p *instancesAutostartPlan) setAttempts(entry *instanceAutostartEntry, attempts int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry.Attempts = attempts
+}
+
+// finish marks the startup sequence as completed.
+func (p *instancesAutostartPlan) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finishedAt = time.Now()
+}
+
+// render returns the API representation of the plan, only including the instances allowed by the filter.
+func (p *instancesAutostartPlan) render(filter func(entry *instanceAutostartEntry) bool) *api.InstancesAutostart {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := &api.InstancesAutostart{
+		Parallelism: p.parallelism,
+		StartedAt:   p.startedAt,
+		FinishedAt:  p.finishedAt,
+		Instances:   []api.InstanceAutostart{},
+	}
+
+	for _, entry := range p.entries {
+		if filter(entry) {
+			out.Instances = append(out.Instances, entry.InstanceAutostart)
+		}
+	}
+
+	return out
+}
+
+// instancesAutostartPlanSet records the plan as the one of the last startup sequence.
+func instancesAutostartPlanSet(plan *instancesAutostartPlan) {
+	instancesAutostartLastMu.Lock()
+	defer instancesAutostartLastMu.Unlock()
+
+	instancesAutostartLast = plan
+}
+
+// swagger:operation GET /1.0/autostart instances instances_autostart_get
+//
+//	Get the instance startup sequence
+//
+//	Shows the last instance startup sequence of the cluster member, with the instances in start order along
+//	with their start status and errors.
+//
+//	Only the instances the requestor can view are included.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "200":
+//	    description: Instance startup sequence
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstancesAutostart"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instancesAutostartGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeInstance)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	instancesAutostartLastMu.Lock()
+	plan := instancesAutostartLast
+	instancesAutostartLastMu.Unlock()
+
+	if plan == nil {
+		return response.SyncResponse(true, api.InstancesAutostart{Instances: []api.InstanceAutostart{}})
+	}
+
+	return response.SyncResponse(true, plan.render(func(entry *instanceAutostartEntry) bool {
+		return userHasPermission(entity.InstanceURL(entry.Project, entry.Name))
+	}))
+}
@@ -1,12 +1,13 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,7 +26,6 @@ import (
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
-	"github.com/canonical/lxd/shared/filter"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/version"
 )
@@ -77,6 +77,21 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //      name: all-projects
 //      description: Retrieve instances from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -name
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -135,6 +150,26 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //      name: all-projects
 //      description: Retrieve instances from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -name
+//    - in: query
+//      name: fields
+//      description: Comma-separated list of fields to return
+//      type: string
+//      example: name,status
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -174,6 +209,9 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //  latter also includes state and snapshot information allowing for a
 //  single API call to return everything needed by most clients.
 //
+//  recursion=2 also includes the resource usage samples of the last hour
+//  (when `instances.state.history.interval` is set).
+//
 //  ---
 //  produces:
 //    - application/json
@@ -192,6 +230,26 @@ func urlInstanceTypeDetect(r *http.Request) (instancetype.Type, error) {
 //      name: all-projects
 //      description: Retrieve instances from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -name
+//    - in: query
+//      name: fields
+//      description: Comma-separated list of fields to return
+//      type: string
+//      example: name,status
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -240,11 +298,17 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 
 	// Parse filter value.
 	filterStr := r.FormValue("filter")
-	clauses, err := filter.Parse(filterStr, filter.QueryOperatorSet())
+	clauses, err := instance.ParseFilter(filterStr)
 	if err != nil {
 		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
 	}
 
+	// Filters only selecting instances by label can be resolved from the database index.
+	var labelSelector map[string]string
+	if clauses != nil {
+		labelSelector = instance.LabelSelector(*clauses)
+	}
+
 	mustLoadObjects := recursion > 0 || (recursion == 0 && clauses != nil && len(clauses.Clauses) > 0)
 
 	projectName, allProjects, err := request.ProjectParams(r)
@@ -258,8 +322,27 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	// Get the list and location of all instances.
+	pagination, err := request.PaginationParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeInstance)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Select the page of instances in the database so that only those are loaded, unless the instances must be
+	// loaded to be filtered. Their page is then selected once they're filtered.
+	filterLoaded := clauses != nil && len(clauses.Clauses) > 0 && labelSelector == nil
+	dbPagination := pagination
+	if filterLoaded {
+		dbPagination = &request.Pagination{Sort: pagination.Sort}
+	}
+
+	// Get the list and location of the instances the user has access to and which match the label selector.
 	var filteredProjects []string
+	var dbInstances []db.Instance
 	var memberAddressInstances map[string][]db.Instance
 
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -276,9 +359,28 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 			filteredProjects = []string{projectName}
 		}
 
+		var labelledInstanceIDs map[int64]bool
+		if labelSelector != nil {
+			ids, err := dbCluster.GetInstanceIDsWithLabels(ctx, tx.Tx(), labelSelector)
+			if err != nil {
+				return fmt.Errorf("Failed getting instances by label: %w", err)
+			}
+
+			labelledInstanceIDs = make(map[int64]bool, len(ids))
+			for _, id := range ids {
+				labelledInstanceIDs[int64(id)] = true
+			}
+		}
+
 		offlineThreshold := s.GlobalConfig.OfflineThreshold()
 
-		memberAddressInstances, err = tx.GetInstancesByMemberAddress(ctx, offlineThreshold, filteredProjects, instanceType)
+		dbInstances, memberAddressInstances, err = tx.GetInstancesPageByMemberAddress(ctx, offlineThreshold, filteredProjects, instanceType, dbPagination, func(inst db.Instance) bool {
+			if labelledInstanceIDs != nil && !labelledInstanceIDs[inst.ID] {
+				return false
+			}
+
+			return userHasPermission(entity.InstanceURL(inst.Project, inst.Name))
+		})
 		if err != nil {
 			return fmt.Errorf("Failed getting instances by member address: %w", err)
 		}
@@ -289,24 +391,13 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeInstance)
-	if err != nil {
-		return response.SmartError(err)
-	}
-
-	// Removes instances the user doesn't have access to.
-	for address, instances := range memberAddressInstances {
-		var filteredInstances []db.Instance
-
-		for _, inst := range instances {
-			if !userHasPermission(entity.InstanceURL(inst.Project, inst.Name)) {
-				continue
-			}
-
-			filteredInstances = append(filteredInstances, inst)
+	// Only fetch the instances of the page rather than all the instances of each member.
+	var pageInstances map[string]bool
+	if !filterLoaded && pagination.Paged() {
+		pageInstances = make(map[string]bool, len(dbInstances))
+		for _, inst := range dbInstances {
+			pageInstances[inst.Project+"/"+inst.Name] = true
 		}
-
-		memberAddressInstances[address] = filteredInstances
 	}
 
 	resultErrListAppend := func(inst db.Instance, err error) {
@@ -329,7 +420,7 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	resultFullListAppend := func(instFull *api.InstanceFull) {
-		if instFull != nil {
+		if instFull != nil && (pageInstances == nil || pageInstances[instFull.Project+"/"+instFull.Name]) {
 			resultMu.Lock()
 			resultFullList = append(resultFullList, instFull)
 			resultMu.Unlock()
@@ -370,6 +461,21 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 			go func(memberAddress string, instances []db.Instance) {
 				defer wg.Done()
 
+				// Only fetch the instances of the page rather than all the instances of the member.
+				if pageInstances != nil {
+					for _, inst := range instances {
+						instFull, err := doInstanceGetFromNode(inst, memberAddress, recursion, networkCert, s.ServerCert(), r)
+						if err != nil {
+							resultErrListAppend(inst, err)
+							continue
+						}
+
+						resultFullListAppend(instFull)
+					}
+
+					return
+				}
+
 				if recursion == 1 {
 					apiInsts, err := doContainersGetFromNode(filteredProjects, memberAddress, allProjects, networkCert, s.ServerCert(), r, instanceType)
 					if err != nil {
@@ -417,17 +523,33 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 
 			hostInterfaces, _ := net.Interfaces()
 
-			// Get the local instances.
+			// Get the local instances, only loading those of the page if one was selected.
 			localInstancesByID := make(map[int64]instance.Instance)
-			for _, projectName := range filteredProjects {
-				insts, err := instanceLoadNodeProjectAll(r.Context(), s, projectName, instanceType)
+			if pageInstances != nil {
+				ids := make([]int, 0, len(instances))
+				for _, inst := range instances {
+					ids = append(ids, int(inst.ID))
+				}
+
+				insts, err := instanceLoadByIDs(r.Context(), s, ids)
 				if err != nil {
-					return response.InternalError(fmt.Errorf("Failed loading instances for project %q: %w", projectName, err))
+					return response.InternalError(fmt.Errorf("Failed loading instances: %w", err))
 				}
 
 				for _, inst := range insts {
 					localInstancesByID[int64(inst.ID())] = inst
 				}
+			} else {
+				for _, projectName := range filteredProjects {
+					insts, err := instanceLoadNodeProjectAll(r.Context(), s, projectName, instanceType)
+					if err != nil {
+						return response.InternalError(fmt.Errorf("Failed loading instances for project %q: %w", projectName, err))
+					}
+
+					for _, inst := range insts {
+						localInstancesByID[int64(inst.ID())] = inst
+					}
+				}
 			}
 
 			queue := make(chan db.Instance, threads)
@@ -462,6 +584,7 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 						if err != nil {
 							resultErrListAppend(dbInst, err)
 						} else {
+							c.StateHistory = instanceStateRecentSamples(d, inst)
 							resultFullListAppend(c)
 						}
 					}
@@ -479,15 +602,39 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 	}
 	wg.Wait()
 
-	// Sort the result list by project and then instance name.
-	sort.SliceStable(resultFullList, func(i, j int) bool {
-		if resultFullList[i].Project == resultFullList[j].Project {
-			return resultFullList[i].Name < resultFullList[j].Name
-		}
+	// Sort the result list in the order of the database records.
+	instanceIndexes := make(map[string]int, len(dbInstances))
+	for i, inst := range dbInstances {
+		instanceIndexes[inst.Project+"/"+inst.Name] = i
+	}
 
-		return resultFullList[i].Project < resultFullList[j].Project
+	slices.SortStableFunc(resultFullList, func(a *api.InstanceFull, b *api.InstanceFull) int {
+		return cmp.Compare(instanceIndexes[a.Project+"/"+a.Name], instanceIndexes[b.Project+"/"+b.Name])
 	})
 
+	// Fill in the ownership of the instances before filtering, so that it can be filtered on.
+	if mustLoadObjects {
+		instanceIDs := make(map[string]int, len(dbInstances))
+		for _, inst := range dbInstances {
+			instanceIDs[inst.Project+"/"+inst.Name] = int(inst.ID)
+		}
+
+		ownedInstances := make(map[int]*api.Instance, len(resultFullList))
+		for _, inst := range resultFullList {
+			instanceID, ok := instanceIDs[inst.Project+"/"+inst.Name]
+			if ok {
+				ownedInstances[instanceID] = &inst.Instance
+			}
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return instancesOwnershipFill(ctx, tx, ownedInstances)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Filter result list if needed.
 	if clauses != nil && len(clauses.Clauses) > 0 {
 		resultFullList, err = instance.FilterFull(resultFullList, *clauses)
@@ -496,6 +643,11 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Select the page of instances if it couldn't be selected before loading them.
+	if filterLoaded {
+		resultFullList = request.Page(pagination, resultFullList)
+	}
+
 	if recursion == 0 {
 		resultList := make([]string, 0, len(resultFullList))
 		for i := range resultFullList {
@@ -534,10 +686,46 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 			resultList = append(resultList, &resultFullList[i].Instance)
 		}
 
-		return response.SyncResponse(true, resultList)
+		result, err := pagination.SelectFields(resultList)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, result)
+	}
+
+	result, err := pagination.SelectFields(resultFullList)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// doInstanceGetFromNode fetches a single instance from the given remote member.
+func doInstanceGetFromNode(inst db.Instance, node string, recursion int, networkCert *shared.CertInfo, serverCert *shared.CertInfo, r *http.Request) (*api.InstanceFull, error) {
+	client, err := cluster.Connect(r.Context(), node, networkCert, serverCert, true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to member %s: %w", node, err)
+	}
+
+	client = client.UseProject(inst.Project)
+
+	if recursion == 1 {
+		apiInst, _, err := client.GetInstance(inst.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get instance from member %s: %w", node, err)
+		}
+
+		return &api.InstanceFull{Instance: *apiInst}, nil
+	}
+
+	instFull, _, err := client.GetInstanceFull(inst.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get instance from member %s: %w", node, err)
 	}
 
-	return response.SyncResponse(true, resultFullList)
+	return instFull, nil
 }
 
 // Fetch information about the containers on the given remote node, using the
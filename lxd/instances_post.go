@@ -6,16 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
 	"slices"
 	"strconv"
+	"strings"
 
 	petname "github.com/dustinkirkland/golang-petname"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 
+	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/archive"
 	"github.com/canonical/lxd/lxd/backup"
 	"github.com/canonical/lxd/lxd/cluster"
@@ -24,20 +27,24 @@ import (
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/clone"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/instance/operationlock"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/resources"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/osarch"
 	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -100,6 +107,7 @@ func createFromImage(ctx context.Context, s *state.State, p api.Project, profile
 			Description: req.Description,
 			Devices:     deviceConfig.ApplyDeviceInitialValues(devices, profiles),
 			Ephemeral:   req.Ephemeral,
+			Labels:      req.Labels,
 			Name:        req.Name,
 			Profiles:    profiles,
 		}
@@ -110,7 +118,7 @@ func createFromImage(ctx context.Context, s *state.State, p api.Project, profile
 				return err
 			}
 		} else if img != nil {
-			err := ensureImageIsLocallyAvailable(ctx, s, img, args.Project)
+			err := ensureImageIsLocallyAvailable(ctx, s, img, sourceImageProject(args.Project, req.Source), args.Project)
 			if err != nil {
 				return err
 			}
@@ -166,6 +174,7 @@ func createFromNone(ctx context.Context, s *state.State, projectName string, pro
 		Description: req.Description,
 		Devices:     deviceConfig.ApplyDeviceInitialValues(devices, profiles),
 		Ephemeral:   req.Ephemeral,
+		Labels:      req.Labels,
 		Name:        req.Name,
 		Profiles:    profiles,
 	}
@@ -232,6 +241,11 @@ func createFromMigration(ctx context.Context, s *state.State, projectName string
 		return response.BadRequest(fmt.Errorf("Instance type not supported %q", req.Type))
 	}
 
+	err = instanceCopyRegenerateIdentity(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	storagePool, args, resp := setupInstanceArgs(s, dbType, projectName, profiles, req)
 	if resp != nil {
 		return resp
@@ -575,6 +589,11 @@ func createFromCopy(ctx context.Context, s *state.State, projectName string, pro
 		req.Devices[key] = value
 	}
 
+	err = instanceCopyRegenerateIdentity(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	if req.Stateful {
 		sourceName, _, _ := api.GetParentAndSnapshotName(source.Name())
 		if sourceName != req.Name {
@@ -605,6 +624,7 @@ func createFromCopy(ctx context.Context, s *state.State, projectName string, pro
 		Description:  req.Description,
 		Devices:      deviceConfig.NewDevices(req.Devices),
 		Ephemeral:    req.Ephemeral,
+		Labels:       req.Labels,
 		Name:         req.Name,
 		Profiles:     profiles,
 		Stateful:     req.Stateful,
@@ -824,7 +844,7 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	// Copy reverter so far so we can use it inside run after this function has finished.
 	runRevert := revert.Clone()
 
-	run := func(_ *operations.Operation) error {
+	run := func(op *operations.Operation) error {
 		defer func() { _ = backupFile.Close() }()
 		defer runRevert.Fail()
 
@@ -843,13 +863,19 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		// a post hook that can be run once the instance has been created in the database to run any
 		// storage layer finalisations, and a revert hook that can be run if the instance database load
 		// process fails that will remove anything created thus far.
-		postHook, revertHook, err := pool.CreateInstanceFromBackup(*bInfo, backupFile, nil)
+		// The unpacking stops once the cancellation of the operation is requested.
+		postHook, revertHook, err := pool.CreateInstanceFromBackup(*bInfo, op.CheckpointReadSeeker(backupFile), nil)
 		if err != nil {
 			return fmt.Errorf("Create instance from backup: %w", err)
 		}
 
 		runRevert.Add(revertHook)
 
+		err = op.Checkpoint()
+		if err != nil {
+			return err
+		}
+
 		err = internalImportFromBackup(context.TODO(), s, bInfo.Project, bInfo.Name, instanceName != "", devices)
 		if err != nil {
 			return fmt.Errorf("Failed importing backup: %w", err)
@@ -907,6 +933,7 @@ func setupInstanceArgs(s *state.State, instType instancetype.Type, projectName s
 		Devices:      deviceConfig.NewDevices(req.Devices),
 		Description:  req.Description,
 		Ephemeral:    req.Ephemeral,
+		Labels:       req.Labels,
 		Name:         req.Name,
 		Profiles:     profiles,
 		Stateful:     req.Stateful,
@@ -1079,6 +1106,20 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = admitRequest(r.Context(), s, targetProjectName, entity.TypeInstance, "create", req.Name, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The admission scriptlets may have removed the devices or config.
+	if req.Devices == nil {
+		req.Devices = map[string]map[string]string{}
+	}
+
+	if req.Config == nil {
+		req.Config = map[string]string{}
+	}
+
 	var targetProject *api.Project
 	var profiles []api.Profile
 	var sourceInst *dbCluster.Instance
@@ -1160,16 +1201,17 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 
 			// If image has an entry in the database then use its profiles if no override provided.
+			// The default profiles of the project take precedence over those of the image.
 			if sourceImage != nil && req.Profiles == nil {
 				req.Architecture = sourceImage.Architecture
-				req.Profiles = sourceImage.Profiles
+				req.Profiles = project.DefaultProfilesFromRecord(targetProject, sourceImage.Profiles)
 			}
 		}
 
-		// Use default profile if no profile list specified (not even an empty list).
-		// This mirrors the logic in instance.CreateInternal() that would occur anyway.
+		// Use the default profiles of the project if no profile list specified (not even an empty list).
+		// Without any set by the project, this mirrors the logic in instance.CreateInternal() that would occur anyway.
 		if req.Profiles == nil {
-			req.Profiles = []string{"default"}
+			req.Profiles = project.DefaultProfilesFromRecord(targetProject, nil)
 		}
 
 		// Initialise the profile info list (even if an empty list is provided so this isn't left as nil).
@@ -1270,12 +1312,65 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 				}
 			}
 
+			groupConstraints, err := clusterGroupConstraints(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			// Pick the image variant among the architectures allowed by the targeted cluster group.
+			for _, constraints := range groupConstraints {
+				if constraints.Group != targetGroupName || constraints.Architectures == nil {
+					continue
+				}
+
+				allowedArchitectures := constraints.AllowedArchitectures(architectures)
+				if len(allowedArchitectures) == 0 {
+					return api.StatusErrorf(http.StatusBadRequest, "Image isn't available for the architectures allowed by cluster group %q", targetGroupName)
+				}
+
+				architectures = allowedArchitectures
+			}
+
 			clusterGroupsAllowed := limits.GetRestrictedClusterGroups(targetProject)
 
 			candidateMembers, err = tx.GetCandidateMembers(ctx, allMembers, architectures, targetGroupName, clusterGroupsAllowed, s.GlobalConfig.OfflineThreshold())
 			if err != nil {
 				return err
 			}
+
+			instanceType, err := instancetype.New(string(req.Type))
+			if err != nil {
+				return err
+			}
+
+			candidateMembers, err = instanceGroupConstraintCandidates(groupConstraints, architectures, instanceType, candidateMembers)
+			if err != nil {
+				return err
+			}
+
+			expandedConfig := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), req.Config, profiles)
+			candidateMembers, err = instancePlacementCandidates(ctx, tx, targetProjectName, req.Name, expandedConfig, req.Labels, candidateMembers)
+			if err != nil {
+				return err
+			}
+		}
+
+		if s.ServerClustered && !clusterNotification && targetMemberInfo != nil {
+			// Check that the constraints of the cluster groups of the targeted member allow the instance.
+			groupConstraints, err := clusterGroupConstraints(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			instanceType, err := instancetype.New(string(req.Type))
+			if err != nil {
+				return err
+			}
+
+			err = clusterGroupConstraintsCheck(groupConstraints, nil, instanceType, *targetMemberInfo)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "Instance can't be placed on cluster member %q: %w", targetMemberInfo.Name, err)
+			}
 		}
 
 		if !clusterNotification {
@@ -1298,6 +1393,29 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if s.ServerClustered && !clusterNotification && targetMemberInfo == nil && req.Type == api.InstanceTypeVM {
+		// Only consider the members with enough capacity for the pooled mdev GPU devices.
+		devices := instancetype.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles)
+		candidateMembers, err = instanceMdevCandidateMembers(r.Context(), s, candidateMembers, devices)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	if s.ServerClustered && !clusterNotification && targetMemberInfo == nil {
+		// Only consider the members with enough capacity within their overcommit policy.
+		instanceType, err := instancetype.New(string(req.Type))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		expandedConfig := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), req.Config, profiles)
+		candidateMembers, err = instanceOvercommitCandidateMembers(r.Context(), s, candidateMembers, instanceType, expandedConfig)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	if s.ServerClustered && !clusterNotification && targetMemberInfo == nil {
 		// If no target member was selected yet, pick the member with the least number of instances.
 		if targetMemberInfo == nil {
@@ -1510,6 +1628,57 @@ func clusterCopyContainerInternal(ctx context.Context, s *state.State, source in
 	return createFromMigration(context.Background(), s, projectName, profiles, req, false)
 }
 
+// instanceCopyRegenerateIdentity prepares the creation request of an instance copy for the requested identity
+// regeneration. The MAC addresses of the network interfaces are cleared so that new ones get generated, while the
+// items that live in the guest filesystem are recorded to be regenerated on the first start of the new instance.
+func instanceCopyRegenerateIdentity(req *api.InstancesPost) error {
+	items := req.Source.RegenerateIdentity
+	if len(items) == 0 {
+		return nil
+	}
+
+	if req.Source.Refresh {
+		return errors.New("Identity regeneration can't be used when refreshing an instance")
+	}
+
+	for _, item := range items {
+		err := validate.IsOneOf(api.InstanceIdentityHWAddr, api.InstanceIdentityMachineID, api.InstanceIdentitySSHHostKeys, api.InstanceIdentityHostname)(item)
+		if err != nil {
+			return fmt.Errorf("Invalid identity item %q: %w", item, err)
+		}
+	}
+
+	if slices.Contains(items, api.InstanceIdentityHWAddr) {
+		for devName, dev := range req.Devices {
+			if !slices.Contains([]string{"nic", "infiniband"}, dev["type"]) || dev["hwaddr"] == "" {
+				continue
+			}
+
+			// Don't modify the device in place as it may be shared with the source instance.
+			dev = maps.Clone(dev)
+			delete(dev, "hwaddr")
+			req.Devices[devName] = dev
+		}
+
+		for key := range req.Config {
+			if strings.HasPrefix(key, instancetype.ConfigVolatilePrefix) && strings.HasSuffix(key, ".hwaddr") {
+				delete(req.Config, key)
+			}
+		}
+	}
+
+	guestItems := clone.GuestIdentityItems(items)
+	if len(guestItems) > 0 {
+		if req.Config == nil {
+			req.Config = make(map[string]string)
+		}
+
+		req.Config["volatile.regenerate_identity"] = strings.Join(guestItems, ",")
+	}
+
+	return nil
+}
+
 // instanceCreateFinish finalizes the creation process of an instance by starting it based on
 // the Start field of the request.
 func instanceCreateFinish(s *state.State, req *api.InstancesPost, args db.InstanceArgs) error {
@@ -1525,3 +1694,127 @@ func instanceCreateFinish(s *state.State, req *api.InstancesPost, args db.Instan
 
 	return inst.Start(false)
 }
+
+// instanceMdevCandidateMembers filters the candidate members down to those with enough available capacity for the
+// mdev GPU devices that aren't tied to a specific GPU.
+func instanceMdevCandidateMembers(ctx context.Context, s *state.State, candidateMembers []db.NodeInfo, devices deviceConfig.Devices) ([]db.NodeInfo, error) {
+	// Count the requested vGPUs per mdev profile.
+	required := map[string]uint64{}
+	for _, dev := range devices {
+		if dev["type"] != "gpu" || dev["gputype"] != "mdev" || dev["mdev"] == "" || dev["pci"] != "" || dev["id"] != "" {
+			continue
+		}
+
+		required[dev["mdev"]]++
+	}
+
+	if len(required) == 0 {
+		return candidateMembers, nil
+	}
+
+	members := make([]db.NodeInfo, 0, len(candidateMembers))
+	for _, member := range candidateMembers {
+		var gpus *api.ResourcesGPU
+		var err error
+
+		if member.Name == s.ServerName {
+			gpus, err = resources.GetGPU()
+		} else {
+			var client lxd.InstanceServer
+			client, err = cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+			if err == nil {
+				var res *api.Resources
+				res, err = client.GetServerResources()
+				if err == nil {
+					gpus = &res.GPU
+				}
+			}
+		}
+
+		if err != nil {
+			logger.Warn("Failed getting GPU resources of cluster member", logger.Ctx{"member": member.Name, "err": err})
+			continue
+		}
+
+		available := true
+		for profile, count := range required {
+			if gpus.MdevProfiles[profile].Available < count {
+				available = false
+				break
+			}
+		}
+
+		if available {
+			members = append(members, member)
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, api.StatusErrorf(http.StatusServiceUnavailable, "No cluster member has enough available capacity for the requested mdev profiles")
+	}
+
+	return members, nil
+}
+
+// instanceOvercommitCandidateMembers returns the candidate members that can accept an instance of the given type and
+// expanded configuration without exceeding their overcommit policy. Saturated members are excluded.
+func instanceOvercommitCandidateMembers(ctx context.Context, s *state.State, candidateMembers []db.NodeInfo, instanceType instancetype.Type, config map[string]string) ([]db.NodeInfo, error) {
+	members := make([]db.NodeInfo, 0, len(candidateMembers))
+	for _, member := range candidateMembers {
+		if !cluster.HasOvercommitPolicy(member.Config) {
+			members = append(members, member)
+			continue
+		}
+
+		var scheduler *api.ClusterMemberScheduler
+		var err error
+
+		if member.Name == s.ServerName {
+			scheduler, err = cluster.MemberScheduler(ctx, s)
+		} else {
+			var client lxd.InstanceServer
+			client, err = cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+			if err == nil {
+				var memberState *api.ClusterMemberState
+				memberState, _, err = client.GetClusterMemberState(member.Name)
+				if err == nil {
+					scheduler = memberState.Scheduler
+				}
+			}
+		}
+
+		if err != nil {
+			logger.Warn("Failed getting scheduler state of cluster member", logger.Ctx{"member": member.Name, "err": err})
+			continue
+		}
+
+		if scheduler == nil {
+			members = append(members, member)
+			continue
+		}
+
+		cpus, memory, err := cluster.InstanceAllocation(config, instanceType, scheduler.MemoryTotal)
+		if err != nil {
+			return nil, err
+		}
+
+		if scheduler.Saturated != "" {
+			logger.Debug("Skipping saturated cluster member", logger.Ctx{"member": member.Name, "reason": scheduler.Saturated})
+			continue
+		}
+
+		err = cluster.SchedulerFits(scheduler, cpus, memory)
+		if err != nil {
+			logger.Debug("Skipping cluster member without enough capacity", logger.Ctx{"member": member.Name, "reason": err})
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	if len(members) == 0 {
+		return nil, api.StatusErrorf(http.StatusServiceUnavailable, "No cluster member has enough capacity within its overcommit policy")
+	}
+
+	return members, nil
+}
@@ -47,7 +47,7 @@ func coalesceErrors(local bool, errs map[string]error) error {
 //
 //	Bulk instance state update
 //
-//	Changes the running state of all instances.
+//	Changes the running state of all instances, or only of those having all the labels given in the request.
 //
 //	---
 //	consumes:
@@ -98,6 +98,18 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 
 	action := instancetype.InstanceAction(req.State.Action)
 
+	// Restrict the change to the instances matching the label selector.
+	if len(req.Labels) > 0 {
+		selected := make([]instance.Instance, 0, len(c))
+		for _, inst := range c {
+			if instance.MatchLabels(inst, req.Labels) {
+				selected = append(selected, inst)
+			}
+		}
+
+		c = selected
+	}
+
 	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanUpdateState, entity.TypeInstance)
 	if err != nil {
 		return response.SmartError(err)
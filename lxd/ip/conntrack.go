@@ -0,0 +1,134 @@
+package ip
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ConntrackFlow represents a connection tracked by the kernel, with the addresses of its original and reply
+// directions.
+type ConntrackFlow struct {
+	OriginalSrc net.IP
+	OriginalDst net.IP
+	ReplySrc    net.IP
+	ReplyDst    net.IP
+}
+
+// IsNAT returns whether the source or destination address of the connection is translated.
+func (f ConntrackFlow) IsNAT() bool {
+	return !f.OriginalSrc.Equal(f.ReplyDst) || !f.OriginalDst.Equal(f.ReplySrc)
+}
+
+// ConntrackFlows returns the IPv4 and IPv6 connections tracked by the kernel.
+func ConntrackFlows() ([]ConntrackFlow, error) {
+	flows := []ConntrackFlow{}
+
+	for _, family := range []netlink.InetFamily{unix.AF_INET, unix.AF_INET6} {
+		entries, err := netlink.ConntrackTableList(netlink.ConntrackTable, family)
+		if err != nil && !errors.Is(err, netlink.ErrDumpInterrupted) {
+			return nil, fmt.Errorf("Failed listing conntrack entries: %w", err)
+		}
+
+		for _, entry := range entries {
+			flows = append(flows, ConntrackFlow{
+				OriginalSrc: entry.Forward.SrcIP,
+				OriginalDst: entry.Forward.DstIP,
+				ReplySrc:    entry.Reverse.SrcIP,
+				ReplyDst:    entry.Reverse.DstIP,
+			})
+		}
+	}
+
+	return flows, nil
+}
+
+// ConntrackStats represents the size and the drop counters of the conntrack table.
+type ConntrackStats struct {
+	Entries uint64
+	Limit   uint64
+
+	// Drops is the number of packets dropped by conntrack, keyed by reason ("drop", "early_drop" or
+	// "insert_failed").
+	Drops map[string]uint64
+}
+
+// GetConntrackStats returns the size and the drop counters of the conntrack table.
+func GetConntrackStats() (*ConntrackStats, error) {
+	stats := &ConntrackStats{}
+
+	var err error
+	stats.Entries, err = readConntrackSysctl("nf_conntrack_count")
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Limit, err = readConntrackSysctl("nf_conntrack_max")
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open("/proc/net/stat/nf_conntrack")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	stats.Drops, err = parseConntrackDrops(bufio.NewScanner(f))
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing conntrack statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+func readConntrackSysctl(name string) (uint64, error) {
+	content, err := os.ReadFile("/proc/sys/net/netfilter/" + name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// parseConntrackDrops sums the drop counters of all CPUs from the content of /proc/net/stat/nf_conntrack, which
+// has a header line with the names of the counters followed by a line of hexadecimal values per CPU.
+func parseConntrackDrops(scanner *bufio.Scanner) (map[string]uint64, error) {
+	if !scanner.Scan() {
+		return nil, errors.New("Missing header")
+	}
+
+	header := strings.Fields(scanner.Text())
+	drops := map[string]uint64{"drop": 0, "early_drop": 0, "insert_failed": 0}
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != len(header) {
+			return nil, fmt.Errorf("Unexpected number of fields %d, expected %d", len(fields), len(header))
+		}
+
+		for i, name := range header {
+			_, ok := drops[name]
+			if !ok {
+				continue
+			}
+
+			value, err := strconv.ParseUint(fields[i], 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value %q for %q: %w", fields[i], name, err)
+			}
+
+			drops[name] += value
+		}
+	}
+
+	return drops, scanner.Err()
+}
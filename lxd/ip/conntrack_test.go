@@ -0,0 +1,54 @@
+package ip
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConntrackFlow_IsNAT(t *testing.T) {
+	instance := net.ParseIP("10.0.0.2")
+	remote := net.ParseIP("198.51.100.1")
+	host := net.ParseIP("203.0.113.1")
+
+	// Connection which isn't translated.
+	flow := ConntrackFlow{OriginalSrc: instance, OriginalDst: remote, ReplySrc: remote, ReplyDst: instance}
+	assert.False(t, flow.IsNAT())
+
+	// Outgoing connection masqueraded behind the host address.
+	flow = ConntrackFlow{OriginalSrc: instance, OriginalDst: remote, ReplySrc: remote, ReplyDst: host}
+	assert.True(t, flow.IsNAT())
+
+	// Incoming connection to a forwarded port of the host.
+	flow = ConntrackFlow{OriginalSrc: remote, OriginalDst: host, ReplySrc: instance, ReplyDst: remote}
+	assert.True(t, flow.IsNAT())
+}
+
+func TestParseConntrackDrops(t *testing.T) {
+	content := `entries  clashres found     new      invalid  ignore   delete   chainlength insert   insert_failed drop     early_drop icmp_error expect_new expect_create expect_delete search_restart
+00000010  00000000 00000000 00000000 00000005 00000000 00000000 00000000    00000000 00000001      00000002 00000000   00000000   00000000   00000000      00000000      00000000
+00000010  00000000 00000000 00000000 00000003 00000000 00000000 00000000    00000000 0000000a      00000010 00000001   00000000   00000000   00000000      00000000      00000000
+`
+
+	drops, err := parseConntrackDrops(bufio.NewScanner(strings.NewReader(content)))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"drop": 18, "early_drop": 1, "insert_failed": 11}, drops)
+
+	// Counters missing from the header are reported as zero.
+	drops, err = parseConntrackDrops(bufio.NewScanner(strings.NewReader("entries drop\n00000001 00000002\n")))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"drop": 2, "early_drop": 0, "insert_failed": 0}, drops)
+
+	_, err = parseConntrackDrops(bufio.NewScanner(strings.NewReader("")))
+	assert.Error(t, err)
+
+	_, err = parseConntrackDrops(bufio.NewScanner(strings.NewReader("entries drop\n00000001\n")))
+	assert.Error(t, err)
+
+	_, err = parseConntrackDrops(bufio.NewScanner(strings.NewReader("entries drop\n00000001 zz\n")))
+	assert.Error(t, err)
+}
@@ -10,10 +10,14 @@ type ClusterMemberAction string
 
 // All supported lifecycle events for cluster members.
 const (
-	ClusterMemberAdded   = ClusterMemberAction(api.EventLifecycleClusterMemberAdded)
-	ClusterMemberRemoved = ClusterMemberAction(api.EventLifecycleClusterMemberRemoved)
-	ClusterMemberUpdated = ClusterMemberAction(api.EventLifecycleClusterMemberUpdated)
-	ClusterMemberRenamed = ClusterMemberAction(api.EventLifecycleClusterMemberRenamed)
+	ClusterMemberAdded       = ClusterMemberAction(api.EventLifecycleClusterMemberAdded)
+	ClusterMemberFenced      = ClusterMemberAction(api.EventLifecycleClusterMemberFenced)
+	ClusterMemberHealed      = ClusterMemberAction(api.EventLifecycleClusterMemberHealed)
+	ClusterMemberPartitioned = ClusterMemberAction(api.EventLifecycleClusterMemberPartitioned)
+	ClusterMemberRebalanced  = ClusterMemberAction(api.EventLifecycleClusterMemberRebalanced)
+	ClusterMemberRemoved     = ClusterMemberAction(api.EventLifecycleClusterMemberRemoved)
+	ClusterMemberUpdated     = ClusterMemberAction(api.EventLifecycleClusterMemberUpdated)
+	ClusterMemberRenamed     = ClusterMemberAction(api.EventLifecycleClusterMemberRenamed)
 )
 
 // Event creates the lifecycle event for an action on a cluster member.
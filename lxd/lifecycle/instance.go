@@ -23,11 +23,14 @@ const (
 	InstanceStopped          = InstanceAction(api.EventLifecycleInstanceStopped)
 	InstanceShutdown         = InstanceAction(api.EventLifecycleInstanceShutdown)
 	InstanceRestarted        = InstanceAction(api.EventLifecycleInstanceRestarted)
+	InstanceAutoRestarted    = InstanceAction(api.EventLifecycleInstanceAutoRestarted)
 	InstancePaused           = InstanceAction(api.EventLifecycleInstancePaused)
 	InstanceReady            = InstanceAction(api.EventLifecycleInstanceReady)
 	InstanceResumed          = InstanceAction(api.EventLifecycleInstanceResumed)
 	InstanceRestored         = InstanceAction(api.EventLifecycleInstanceRestored)
 	InstanceDeleted          = InstanceAction(api.EventLifecycleInstanceDeleted)
+	InstanceDeviceAttached   = InstanceAction(api.EventLifecycleInstanceDeviceAttached)
+	InstanceDeviceDetached   = InstanceAction(api.EventLifecycleInstanceDeviceDetached)
 	InstanceRenamed          = InstanceAction(api.EventLifecycleInstanceRenamed)
 	InstanceUpdated          = InstanceAction(api.EventLifecycleInstanceUpdated)
 	InstanceExec             = InstanceAction(api.EventLifecycleInstanceExec)
@@ -37,6 +40,7 @@ const (
 	InstanceFileRetrieved    = InstanceAction(api.EventLifecycleInstanceFileRetrieved)
 	InstanceFilePushed       = InstanceAction(api.EventLifecycleInstanceFilePushed)
 	InstanceFileDeleted      = InstanceAction(api.EventLifecycleInstanceFileDeleted)
+	InstanceWatchdog         = InstanceAction(api.EventLifecycleInstanceWatchdog)
 )
 
 // Event creates the lifecycle event for an action on an instance.
@@ -10,10 +10,13 @@ type ProjectAction string
 
 // All supported lifecycle events for projects.
 const (
-	ProjectCreated = ProjectAction(api.EventLifecycleProjectCreated)
-	ProjectDeleted = ProjectAction(api.EventLifecycleProjectDeleted)
-	ProjectUpdated = ProjectAction(api.EventLifecycleProjectUpdated)
-	ProjectRenamed = ProjectAction(api.EventLifecycleProjectRenamed)
+	ProjectCreated                  = ProjectAction(api.EventLifecycleProjectCreated)
+	ProjectDeleted                  = ProjectAction(api.EventLifecycleProjectDeleted)
+	ProjectUpdated                  = ProjectAction(api.EventLifecycleProjectUpdated)
+	ProjectRenamed                  = ProjectAction(api.EventLifecycleProjectRenamed)
+	ProjectDisabled                 = ProjectAction(api.EventLifecycleProjectDisabled)
+	ProjectEnabled                  = ProjectAction(api.EventLifecycleProjectEnabled)
+	ProjectDeleteProtectionUnlocked = ProjectAction(api.EventLifecycleProjectDeleteProtectionUnlocked)
 )
 
 // Event creates the lifecycle event for an action on a project.
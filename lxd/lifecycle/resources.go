@@ -0,0 +1,26 @@
+package lifecycle
+
+import (
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// ResourcesAction represents a lifecycle event action for the host resources.
+type ResourcesAction string
+
+// All supported lifecycle events for the host resources.
+const (
+	ResourcesDeviceAdded   = ResourcesAction(api.EventLifecycleResourcesDeviceAdded)
+	ResourcesDeviceRemoved = ResourcesAction(api.EventLifecycleResourcesDeviceRemoved)
+)
+
+// Event creates the lifecycle event for an action on the host resources.
+func (a ResourcesAction) Event(ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "resources")
+
+	return api.EventLifecycle{
+		Action:  string(a),
+		Source:  u.String(),
+		Context: ctx,
+	}
+}
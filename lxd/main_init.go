@@ -168,6 +168,9 @@ func (c *cmdInit) Run(cmd *cobra.Command, args []string) error {
 		// Set server name from join token
 		config.Cluster.ServerName = joinToken.ServerName
 
+		// Apply the configuration embedded in the join token.
+		initJoinTokenPreseed(config.Cluster, joinToken)
+
 		// Attempt to find a working cluster member to use for joining by retrieving the
 		// cluster certificate from each address in the join token until we succeed.
 		for _, clusterAddress := range joinToken.Addresses {
@@ -257,3 +260,29 @@ func (c *cmdInit) defaultHostname() string {
 	c.hostname = hostName
 	return hostName
 }
+
+// initJoinTokenPreseed applies the configuration embedded in the join token to the cluster preseed.
+// Values already set in the preseed take precedence over the ones from the token.
+func initJoinTokenPreseed(config *api.InitClusterPreseed, joinToken *api.ClusterMemberJoinToken) {
+	if joinToken.Preseed == nil {
+		return
+	}
+
+	for _, key := range joinToken.Preseed.MemberConfig {
+		found := false
+		for _, existing := range config.MemberConfig {
+			if existing.Entity == key.Entity && existing.Name == key.Name && existing.Key == key.Key {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			config.MemberConfig = append(config.MemberConfig, key)
+		}
+	}
+
+	if len(config.Roles) == 0 {
+		config.Roles = joinToken.Preseed.Roles
+	}
+}
@@ -269,6 +269,18 @@ func (c *cmdInit) askClustering(config *api.InitPreseed, server *api.Server) err
 			}
 
 			for i, config := range cluster.MemberConfig {
+				// Use the value from the join token if provided.
+				if joinToken.Preseed != nil {
+					idx := slices.IndexFunc(joinToken.Preseed.MemberConfig, func(key api.ClusterMemberConfigKey) bool {
+						return key.Entity == config.Entity && key.Name == config.Name && key.Key == config.Key
+					})
+
+					if idx >= 0 {
+						cluster.MemberConfig[i].Value = joinToken.Preseed.MemberConfig[idx].Value
+						continue
+					}
+				}
+
 				question := "Choose " + config.Description + ": "
 
 				// Allow for empty values.
@@ -281,6 +293,10 @@ func (c *cmdInit) askClustering(config *api.InitPreseed, server *api.Server) err
 			}
 
 			config.Cluster.MemberConfig = cluster.MemberConfig
+
+			if joinToken.Preseed != nil {
+				config.Cluster.Roles = joinToken.Preseed.Roles
+			}
 		} else {
 			// Ask for server name since no token is provided
 			err = askForServerName()
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_initJoinTokenPreseed(t *testing.T) {
+	joinToken := &api.ClusterMemberJoinToken{
+		Preseed: &api.ClusterMemberJoinPreseed{
+			MemberConfig: []api.ClusterMemberConfigKey{
+				{Entity: "storage-pool", Name: "data", Key: "source", Value: "/dev/sdb"},
+				{Entity: "network", Name: "lxdbr0", Key: "bridge.external_interfaces", Value: "eth1"},
+			},
+			Roles: []string{"event-hub"},
+		},
+	}
+
+	// The local preseed takes precedence over the token.
+	config := &api.InitClusterPreseed{}
+	config.MemberConfig = []api.ClusterMemberConfigKey{
+		{Entity: "storage-pool", Name: "data", Key: "source", Value: "/dev/sdc"},
+	}
+
+	config.Roles = []string{"ovn-chassis"}
+
+	initJoinTokenPreseed(config, joinToken)
+	assert.Equal(t, []api.ClusterMemberConfigKey{
+		{Entity: "storage-pool", Name: "data", Key: "source", Value: "/dev/sdc"},
+		{Entity: "network", Name: "lxdbr0", Key: "bridge.external_interfaces", Value: "eth1"},
+	}, config.MemberConfig)
+	assert.Equal(t, []string{"ovn-chassis"}, config.Roles)
+
+	// The token roles apply when none are set locally.
+	config = &api.InitClusterPreseed{}
+	initJoinTokenPreseed(config, joinToken)
+	assert.Equal(t, joinToken.Preseed.MemberConfig, config.MemberConfig)
+	assert.Equal(t, []string{"event-hub"}, config.Roles)
+
+	// Tokens without a preseed leave the configuration untouched.
+	config = &api.InitClusterPreseed{}
+	initJoinTokenPreseed(config, &api.ClusterMemberJoinToken{})
+	assert.Empty(t, config.MemberConfig)
+	assert.Empty(t, config.Roles)
+}
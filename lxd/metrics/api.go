@@ -62,6 +62,10 @@ type MemoryMetrics struct {
 	UnevictableBytes    uint64 `json:"memory_unevictable_bytes" yaml:"memory_unevictable_bytes"`
 	WritebackBytes      uint64 `json:"memory_writeback_bytes" yaml:"memory_writeback_bytes"`
 	OOMKills            uint64 `json:"memory_oom_kills" yaml:"memory_oom_kills"`
+
+	// Percentage of time in the last 10 seconds during which some or all tasks were stalled on memory.
+	PressureSomeAvg10 float64 `json:"memory_pressure_some_avg10" yaml:"memory_pressure_some_avg10"`
+	PressureFullAvg10 float64 `json:"memory_pressure_full_avg10" yaml:"memory_pressure_full_avg10"`
 }
 
 // NetworkMetrics represents network metrics for an instance.
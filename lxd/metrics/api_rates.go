@@ -4,9 +4,11 @@ import (
 	"net/http"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
 )
 
 // RequestResult represents a completed request status category.
@@ -39,14 +41,32 @@ type completedMetricsLabeling struct {
 var ongoingRequests map[entity.Type]*atomic.Int64
 var completedRequests map[completedMetricsLabeling]*atomic.Int64
 
+var slowRequests map[entity.Type]*atomic.Int64
+var requestsTooLarge map[entity.Type]*atomic.Int64
+
+// slowRequestThreshold is the duration above which completed requests are logged and counted as slow.
+// A zero threshold disables the tracking of slow requests.
+var slowRequestThreshold atomic.Int64
+
+// projectRequests counts the requests of each project since they were last taken for the usage accounting.
+var projectRequests = map[string]int64{}
+var projectRequestsMu sync.Mutex
+
+var requestDurations = newHistogramVec()
+var operationDurations = newHistogramVec()
+
 // InitAPIMetrics initializes maps with initial values for the API rates metrics.
 func InitAPIMetrics() {
 	relevantEntityTypes := entity.APIMetricsEntityTypes()
 	ongoingRequests = make(map[entity.Type]*atomic.Int64, len(relevantEntityTypes))
 	completedRequests = make(map[completedMetricsLabeling]*atomic.Int64, len(relevantEntityTypes)*len(requestResultNames))
+	slowRequests = make(map[entity.Type]*atomic.Int64, len(relevantEntityTypes))
+	requestsTooLarge = make(map[entity.Type]*atomic.Int64, len(relevantEntityTypes))
 
 	for _, entityType := range relevantEntityTypes {
 		ongoingRequests[entityType] = new(atomic.Int64)
+		slowRequests[entityType] = new(atomic.Int64)
+		requestsTooLarge[entityType] = new(atomic.Int64)
 		for result := range requestResultNames {
 			completedRequests[completedMetricsLabeling{entityType: entityType, result: result}] = new(atomic.Int64)
 		}
@@ -74,15 +94,142 @@ func GetCompletedRequests(entityType entity.Type, result RequestResult) int64 {
 	return completedRequests[completedMetricsLabeling{entityType: entityType, result: result}].Load()
 }
 
+// GetSlowRequests gets the number of slow requests filtered by entity type.
+func GetSlowRequests(entityType entity.Type) int64 {
+	return slowRequests[entityType].Load()
+}
+
+// TrackRequestTooLarge counts a request rejected for exceeding the maximum request size.
+func TrackRequestTooLarge(entityType entity.Type) {
+	counter, ok := requestsTooLarge[entityType]
+	if ok {
+		counter.Add(1)
+	}
+}
+
+// GetRequestsTooLarge gets the number of requests rejected for exceeding the maximum request size filtered by
+// entity type.
+func GetRequestsTooLarge(entityType entity.Type) int64 {
+	return requestsTooLarge[entityType].Load()
+}
+
+// SetSlowRequestThreshold sets the duration above which completed requests are logged and counted as slow.
+// A zero threshold disables the tracking of slow requests.
+func SetSlowRequestThreshold(threshold time.Duration) {
+	slowRequestThreshold.Store(int64(threshold))
+}
+
+// trackSlowRequest logs and counts the completed request if it exceeded the slow request threshold.
+func trackSlowRequest(r *http.Request, endpointType entity.Type, projectName string, result RequestResult, duration time.Duration) {
+	threshold := time.Duration(slowRequestThreshold.Load())
+	if threshold <= 0 || duration < threshold {
+		return
+	}
+
+	slowRequests[endpointType].Add(1)
+
+	ctx := logger.Ctx{"method": r.Method, "url": r.URL.RequestURI(), "entity_type": endpointType, "result": requestResultNames[result], "duration": duration}
+	if projectName != "" {
+		ctx["project"] = projectName
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err == nil {
+		ctx["username"] = requestor.CallerUsername()
+		ctx["protocol"] = requestor.CallerProtocol()
+		ctx["trace_id"] = requestor.TraceID()
+	}
+
+	logger.Warn("Slow API request", ctx)
+}
+
+// countProjectRequest counts the completed request against its project for the usage accounting.
+// Requests forwarded by other cluster members and cluster notifications are counted by the member which received
+// them from the client.
+func countProjectRequest(r *http.Request, projectName string) {
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil || requestor.IsForwarded() || requestor.IsClusterNotification() {
+		return
+	}
+
+	projectRequestsMu.Lock()
+	projectRequests[projectName]++
+	projectRequestsMu.Unlock()
+}
+
+// TakeProjectRequests returns the number of requests of each project since the last call, and resets the counts.
+func TakeProjectRequests() map[string]int64 {
+	projectRequestsMu.Lock()
+	defer projectRequestsMu.Unlock()
+
+	out := projectRequests
+	projectRequests = map[string]int64{}
+
+	return out
+}
+
+// GetRequestDurations gets the samples of the request duration histograms.
+func GetRequestDurations() []Sample {
+	return requestDurations.samples()
+}
+
+// GetOperationDurations gets the samples of the operation duration histograms.
+func GetOperationDurations() []Sample {
+	return operationDurations.samples()
+}
+
+// TrackCompletedOperation records the duration of a completed operation acting on the given entity type.
+// Operations which don't act on a specific entity type are recorded against the server.
+func TrackCompletedOperation(entityType entity.Type, projectName string, duration time.Duration) {
+	if entityType == "" {
+		entityType = entity.TypeServer
+	}
+
+	operationDurations.observe(entityType, projectName, duration.Seconds())
+}
+
+// metricsProject returns the project label of the metrics of a request acting on the given entity type.
+// It is empty for entity types that are not project specific.
+func metricsProject(r *http.Request, entityType entity.Type) string {
+	requiresProject, err := entityType.RequiresProject()
+	if err != nil || !requiresProject {
+		return ""
+	}
+
+	projectName, allProjects, err := request.ProjectParams(r)
+	if err != nil || allProjects {
+		return ""
+	}
+
+	return projectName
+}
+
 // TrackStartedRequest tracks the request as started for the API metrics and
 // injects a callback function to track the request as completed.
 func TrackStartedRequest(r *http.Request, endpointType entity.Type) {
+	startTime := time.Now()
+	projectName := metricsProject(r, endpointType)
+
 	// Set the callback function to track the request as completed.
 	// Use sync.Once to ensure it can be called at most once.
 	var once sync.Once
 	callbackFunc := func(result RequestResult) {
 		once.Do(func() {
 			countCompletedRequest(endpointType, result)
+
+			// Don't label client errors with the requested project, as it may not exist.
+			durationProject := projectName
+			if result == ErrorClient {
+				durationProject = ""
+			}
+
+			duration := time.Since(startTime)
+			requestDurations.observe(endpointType, durationProject, duration.Seconds())
+			trackSlowRequest(r, endpointType, projectName, result, duration)
+
+			if durationProject != "" {
+				countProjectRequest(r, durationProject)
+			}
 		})
 	}
 
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the buckets of the duration histograms.
+// They range from fast API requests to long running operations such as migrations.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600}
+
+type histogramLabeling struct {
+	entityType entity.Type
+	project    string
+}
+
+// histogram counts the observed values in buckets.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// histogramVec holds a histogram per entity type and project.
+type histogramVec struct {
+	mu         sync.Mutex
+	histograms map[histogramLabeling]*histogram
+}
+
+func newHistogramVec() *histogramVec {
+	return &histogramVec{histograms: map[histogramLabeling]*histogram{}}
+}
+
+// observe adds a value to the histogram of the given entity type and project.
+func (v *histogramVec) observe(entityType entity.Type, project string, value float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	labeling := histogramLabeling{entityType: entityType, project: project}
+	h, ok := v.histograms[labeling]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(durationBuckets))}
+		v.histograms[labeling] = h
+	}
+
+	for i, bound := range durationBuckets {
+		if value <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+
+	h.count++
+	h.sum += value
+}
+
+// samples returns the bucket, sum and count samples of all the histograms as specified by OpenMetrics.
+func (v *histogramVec) samples() []Sample {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	labelings := make([]histogramLabeling, 0, len(v.histograms))
+	for labeling := range v.histograms {
+		labelings = append(labelings, labeling)
+	}
+
+	sort.Slice(labelings, func(i, j int) bool {
+		if labelings[i].entityType != labelings[j].entityType {
+			return labelings[i].entityType < labelings[j].entityType
+		}
+
+		return labelings[i].project < labelings[j].project
+	})
+
+	samples := make([]Sample, 0, len(labelings)*(len(durationBuckets)+3))
+	for _, labeling := range labelings {
+		h := v.histograms[labeling]
+
+		labels := func() map[string]string {
+			labels := map[string]string{"entity_type": labeling.entityType.String()}
+			if labeling.project != "" {
+				labels["project"] = labeling.project
+			}
+
+			return labels
+		}
+
+		// Buckets are cumulative.
+		var cumulative uint64
+		for i, bound := range durationBuckets {
+			cumulative += h.counts[i]
+
+			bucketLabels := labels()
+			bucketLabels["le"] = strconv.FormatFloat(bound, 'g', -1, 64)
+			samples = append(samples, Sample{Suffix: "_bucket", Labels: bucketLabels, Value: float64(cumulative)})
+		}
+
+		bucketLabels := labels()
+		bucketLabels["le"] = "+Inf"
+		samples = append(samples,
+			Sample{Suffix: "_bucket", Labels: bucketLabels, Value: float64(h.count)},
+			Sample{Suffix: "_sum", Labels: labels(), Value: h.sum},
+			Sample{Suffix: "_count", Labels: labels(), Value: float64(h.count)},
+		)
+	}
+
+	return samples
+}
@@ -94,12 +94,29 @@ func (m *MetricSet) String() string {
 		GoHeapObjects,
 		Instances,
 		APIOngoingRequests,
+		ClusterRaftApplyLatencySeconds,
+		ClusterRaftRole,
+		ClusterRaftSnapshotAgeSeconds,
+		ConntrackEntries,
+		ConntrackEntriesLimit,
+		NetworkConntrackEntries,
+		NetworkNATTranslations,
+		Warnings,
+	}
+
+	histogramMetrics := []MetricType{
+		APIRequestDurationSeconds,
+		OperationDurationSeconds,
+		StoragePoolReadLatencySeconds,
+		StoragePoolWriteLatencySeconds,
 	}
 
 	for _, metricType := range metricTypes {
 		// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
 		metricTypeNameSuffix := " counter\n"
-		if slices.Contains(gaugeMetrics, metricType) || strings.HasSuffix(MetricNames[metricType], "_bytes") {
+		if slices.Contains(histogramMetrics, metricType) {
+			metricTypeNameSuffix = " histogram\n"
+		} else if slices.Contains(gaugeMetrics, metricType) || strings.HasSuffix(MetricNames[metricType], "_bytes") {
 			metricTypeNameSuffix = " gauge\n"
 		}
 
@@ -133,9 +150,9 @@ func (m *MetricSet) String() string {
 			valueStr := strconv.FormatFloat(sample.Value, 'g', -1, 64)
 
 			if labels != "" {
-				_, err = out.WriteString(MetricNames[metricType] + "{" + labels + "} " + valueStr + "\n")
+				_, err = out.WriteString(MetricNames[metricType] + sample.Suffix + "{" + labels + "} " + valueStr + "\n")
 			} else {
-				_, err = out.WriteString(MetricNames[metricType] + " " + valueStr + "\n")
+				_, err = out.WriteString(MetricNames[metricType] + sample.Suffix + " " + valueStr + "\n")
 			}
 
 			if err != nil {
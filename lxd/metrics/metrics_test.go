@@ -1,7 +1,10 @@
 package metrics
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -56,3 +59,48 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+func TestMetricSet_Histogram(t *testing.T) {
+	v := newHistogramVec()
+	v.observe(entity.TypeInstance, "default", 0.02)
+	v.observe(entity.TypeInstance, "default", 2)
+	v.observe(entity.TypeInstance, "default", 7200)
+	v.observe(entity.TypeServer, "", 0.001)
+
+	m := NewMetricSet(nil)
+	m.AddSamples(APIRequestDurationSeconds, v.samples()...)
+	out := m.String()
+
+	require.Contains(t, out, "# TYPE lxd_api_request_duration_seconds histogram\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_bucket{entity_type="instance",le="0.01",project="default"} 0`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_bucket{entity_type="instance",le="0.025",project="default"} 1`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_bucket{entity_type="instance",le="2.5",project="default"} 2`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_bucket{entity_type="instance",le="3600",project="default"} 2`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_bucket{entity_type="instance",le="+Inf",project="default"} 3`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_sum{entity_type="instance",project="default"} 7202.02`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_count{entity_type="instance",project="default"} 3`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_bucket{entity_type="server",le="0.005"} 1`+"\n")
+	require.Contains(t, out, `lxd_api_request_duration_seconds_count{entity_type="server"} 1`+"\n")
+}
+
+func TestTrackSlowRequest(t *testing.T) {
+	InitAPIMetrics()
+	defer SetSlowRequestThreshold(0)
+
+	r := httptest.NewRequest(http.MethodGet, "/1.0/instances", nil)
+
+	// Disabled threshold.
+	trackSlowRequest(r, entity.TypeInstance, "default", Success, time.Hour)
+	require.Equal(t, int64(0), GetSlowRequests(entity.TypeInstance))
+
+	SetSlowRequestThreshold(time.Second)
+
+	// Fast request.
+	trackSlowRequest(r, entity.TypeInstance, "default", Success, time.Millisecond)
+	require.Equal(t, int64(0), GetSlowRequests(entity.TypeInstance))
+
+	// Slow request.
+	trackSlowRequest(r, entity.TypeInstance, "default", Success, 2*time.Second)
+	require.Equal(t, int64(1), GetSlowRequests(entity.TypeInstance))
+	require.Equal(t, int64(0), GetSlowRequests(entity.TypeProject))
+}
@@ -4,6 +4,9 @@ package metrics
 type Sample struct {
 	Labels map[string]string
 	Value  float64
+
+	// Suffix is appended to the metric name, for the "_bucket", "_sum" and "_count" samples of histograms.
+	Suffix string
 }
 
 // MetricSet represents a set of metrics.
@@ -20,10 +23,34 @@ const (
 	APICompletedRequests MetricType = iota
 	// APIOngoingRequests represents the number of requests currently being handled.
 	APIOngoingRequests
+	// APIRequestDurationSeconds represents the histogram of the durations of the completed requests.
+	APIRequestDurationSeconds
+	// APIRequestsTooLargeTotal represents the total number of requests rejected for exceeding the maximum request size.
+	APIRequestsTooLargeTotal
+	// APISlowRequestsTotal represents the total number of requests which exceeded the slow request threshold.
+	APISlowRequestsTotal
+	// ClusterDialFailuresTotal represents the total number of failed dqlite and raft connections to other cluster members.
+	ClusterDialFailuresTotal
+	// ClusterRaftApplyLatencySeconds represents the time it took the leader to commit its last heartbeat round.
+	ClusterRaftApplyLatencySeconds
+	// ClusterRaftLeaderChangesTotal represents the total number of raft leader changes seen by the member.
+	ClusterRaftLeaderChangesTotal
+	// ClusterRaftRole represents the raft role of the database members.
+	ClusterRaftRole
+	// ClusterRaftSnapshotAgeSeconds represents the age of the most recent raft snapshot.
+	ClusterRaftSnapshotAgeSeconds
+	// ConntrackDropsTotal represents the total number of packets dropped by conntrack.
+	ConntrackDropsTotal
+	// ConntrackEntries represents the number of entries in the conntrack table.
+	ConntrackEntries
+	// ConntrackEntriesLimit represents the maximum number of entries in the conntrack table.
+	ConntrackEntriesLimit
 	// CPUs represents the total number of effective CPUs.
 	CPUs
 	// CPUSecondsTotal represents the total CPU seconds used.
 	CPUSecondsTotal
+	// DBSlowTransactionsTotal represents the total number of database transactions which exceeded the slow transaction threshold.
+	DBSlowTransactionsTotal
 	// DiskReadBytesTotal represents the read bytes for a disk.
 	DiskReadBytesTotal
 	// DiskReadsCompletedTotal represents the completed for a disk.
@@ -92,6 +119,10 @@ const (
 	MemoryActiveBytes
 	// MemoryActiveFileBytes represents the amount of file-backed memory on active LRU list.
 	MemoryActiveFileBytes
+	// MemoryBalloonResizesTotal represents the number of automatic memory balloon resizes.
+	MemoryBalloonResizesTotal
+	// MemoryBalloonTargetBytes represents the amount of memory left to the instance by the memory balloon.
+	MemoryBalloonTargetBytes
 	// MemoryCachedBytes represents the amount of cached memory.
 	MemoryCachedBytes
 	// MemoryDirtyBytes represents the amount of memory waiting to get written back to the disk.
@@ -126,6 +157,10 @@ const (
 	MemoryUnevictableBytes
 	// MemoryWritebackBytes represents the amount of memory queued for syncing to disk.
 	MemoryWritebackBytes
+	// NetworkConntrackEntries represents the number of conntrack entries of a network.
+	NetworkConntrackEntries
+	// NetworkNATTranslations represents the number of conntrack entries of a network whose addresses are translated.
+	NetworkNATTranslations
 	// NetworkReceiveBytesTotal represents the amount of received bytes on a given interface.
 	NetworkReceiveBytesTotal
 	// NetworkReceiveDropTotal represents the amount of received dropped bytes on a given interface.
@@ -142,154 +177,218 @@ const (
 	NetworkTransmitErrsTotal
 	// NetworkTransmitPacketsTotal represents the amount of transmitted packets on a given interface.
 	NetworkTransmitPacketsTotal
+	// OperationDurationSeconds represents the histogram of the durations of the completed operations.
+	OperationDurationSeconds
+	// OperationsQueued represents the number of operations waiting in a queue.
+	OperationsQueued
 	// OperationsTotal represents the number of running operations.
 	OperationsTotal
 	// ProcsTotal represents the number of running processes.
 	ProcsTotal
+	// StoragePoolIOErrorsTotal represents the number of I/O errors of a storage pool.
+	StoragePoolIOErrorsTotal
+	// StoragePoolReadLatencySeconds represents the histogram of the read latencies of a storage pool.
+	StoragePoolReadLatencySeconds
+	// StoragePoolSizeBytes represents the size in bytes of a storage pool.
+	StoragePoolSizeBytes
+	// StoragePoolUsedBytes represents the used bytes of a storage pool.
+	StoragePoolUsedBytes
+	// StoragePoolWriteLatencySeconds represents the histogram of the write latencies of a storage pool.
+	StoragePoolWriteLatencySeconds
 	// UptimeSeconds represents the daemon uptime in seconds.
 	UptimeSeconds
+	// Warnings represents the number of unresolved warnings by type, severity and status.
+	Warnings
 	// WarningsTotal represents the number of active warnings.
 	WarningsTotal
 )
 
 // MetricNames associates a metric type to its name.
 var MetricNames = map[MetricType]string{
-	APICompletedRequests:        "lxd_api_requests_completed_total",
-	APIOngoingRequests:          "lxd_api_requests_ongoing",
-	CPUSecondsTotal:             "lxd_cpu_seconds_total",
-	CPUs:                        "lxd_cpu_effective_total",
-	DiskReadBytesTotal:          "lxd_disk_read_bytes_total",
-	DiskReadsCompletedTotal:     "lxd_disk_reads_completed_total",
-	DiskWrittenBytesTotal:       "lxd_disk_written_bytes_total",
-	DiskWritesCompletedTotal:    "lxd_disk_writes_completed_total",
-	FilesystemAvailBytes:        "lxd_filesystem_avail_bytes",
-	FilesystemFreeBytes:         "lxd_filesystem_free_bytes",
-	FilesystemSizeBytes:         "lxd_filesystem_size_bytes",
-	GoAllocBytes:                "lxd_go_alloc_bytes",
-	GoAllocBytesTotal:           "lxd_go_alloc_bytes_total",
-	GoBuckHashSysBytes:          "lxd_go_buck_hash_sys_bytes",
-	GoFreesTotal:                "lxd_go_frees_total",
-	GoGCSysBytes:                "lxd_go_gc_sys_bytes",
-	GoGoroutines:                "lxd_go_goroutines",
-	GoHeapAllocBytes:            "lxd_go_heap_alloc_bytes",
-	GoHeapIdleBytes:             "lxd_go_heap_idle_bytes",
-	GoHeapInuseBytes:            "lxd_go_heap_inuse_bytes",
-	GoHeapObjects:               "lxd_go_heap_objects",
-	GoHeapReleasedBytes:         "lxd_go_heap_released_bytes",
-	GoHeapSysBytes:              "lxd_go_heap_sys_bytes",
-	GoLookupsTotal:              "lxd_go_lookups_total",
-	GoMallocsTotal:              "lxd_go_mallocs_total",
-	GoMCacheInuseBytes:          "lxd_go_mcache_inuse_bytes",
-	GoMCacheSysBytes:            "lxd_go_mcache_sys_bytes",
-	GoMSpanInuseBytes:           "lxd_go_mspan_inuse_bytes",
-	GoMSpanSysBytes:             "lxd_go_mspan_sys_bytes",
-	GoNextGCBytes:               "lxd_go_next_gc_bytes",
-	GoOtherSysBytes:             "lxd_go_other_sys_bytes",
-	GoStackInuseBytes:           "lxd_go_stack_inuse_bytes",
-	GoStackSysBytes:             "lxd_go_stack_sys_bytes",
-	GoSysBytes:                  "lxd_go_sys_bytes",
-	MemoryActiveAnonBytes:       "lxd_memory_Active_anon_bytes",
-	MemoryActiveFileBytes:       "lxd_memory_Active_file_bytes",
-	MemoryActiveBytes:           "lxd_memory_Active_bytes",
-	MemoryCachedBytes:           "lxd_memory_Cached_bytes",
-	MemoryDirtyBytes:            "lxd_memory_Dirty_bytes",
-	MemoryHugePagesFreeBytes:    "lxd_memory_HugepagesFree_bytes",
-	MemoryHugePagesTotalBytes:   "lxd_memory_HugepagesTotal_bytes",
-	MemoryInactiveAnonBytes:     "lxd_memory_Inactive_anon_bytes",
-	MemoryInactiveFileBytes:     "lxd_memory_Inactive_file_bytes",
-	MemoryInactiveBytes:         "lxd_memory_Inactive_bytes",
-	MemoryMappedBytes:           "lxd_memory_Mapped_bytes",
-	MemoryMemAvailableBytes:     "lxd_memory_MemAvailable_bytes",
-	MemoryMemFreeBytes:          "lxd_memory_MemFree_bytes",
-	MemoryMemTotalBytes:         "lxd_memory_MemTotal_bytes",
-	MemoryRSSBytes:              "lxd_memory_RSS_bytes",
-	MemoryShmemBytes:            "lxd_memory_Shmem_bytes",
-	MemorySwapBytes:             "lxd_memory_Swap_bytes",
-	MemoryUnevictableBytes:      "lxd_memory_Unevictable_bytes",
-	MemoryWritebackBytes:        "lxd_memory_Writeback_bytes",
-	MemoryOOMKillsTotal:         "lxd_memory_OOM_kills_total",
-	NetworkReceiveBytesTotal:    "lxd_network_receive_bytes_total",
-	NetworkReceiveDropTotal:     "lxd_network_receive_drop_total",
-	NetworkReceiveErrsTotal:     "lxd_network_receive_errs_total",
-	NetworkReceivePacketsTotal:  "lxd_network_receive_packets_total",
-	NetworkTransmitBytesTotal:   "lxd_network_transmit_bytes_total",
-	NetworkTransmitDropTotal:    "lxd_network_transmit_drop_total",
-	NetworkTransmitErrsTotal:    "lxd_network_transmit_errs_total",
-	NetworkTransmitPacketsTotal: "lxd_network_transmit_packets_total",
-	OperationsTotal:             "lxd_operations_total",
-	ProcsTotal:                  "lxd_procs_total",
-	UptimeSeconds:               "lxd_uptime_seconds",
-	WarningsTotal:               "lxd_warnings_total",
-	Instances:                   "lxd_instances",
+	APICompletedRequests:           "lxd_api_requests_completed_total",
+	APIOngoingRequests:             "lxd_api_requests_ongoing",
+	APIRequestDurationSeconds:      "lxd_api_request_duration_seconds",
+	APIRequestsTooLargeTotal:       "lxd_api_requests_too_large_total",
+	APISlowRequestsTotal:           "lxd_api_slow_requests_total",
+	ClusterDialFailuresTotal:       "lxd_cluster_dial_failures_total",
+	ClusterRaftApplyLatencySeconds: "lxd_cluster_raft_apply_latency_seconds",
+	ClusterRaftLeaderChangesTotal:  "lxd_cluster_raft_leader_changes_total",
+	ClusterRaftRole:                "lxd_cluster_raft_role",
+	ClusterRaftSnapshotAgeSeconds:  "lxd_cluster_raft_snapshot_age_seconds",
+	ConntrackDropsTotal:            "lxd_conntrack_drops_total",
+	ConntrackEntries:               "lxd_conntrack_entries",
+	ConntrackEntriesLimit:          "lxd_conntrack_entries_limit",
+	CPUSecondsTotal:                "lxd_cpu_seconds_total",
+	CPUs:                           "lxd_cpu_effective_total",
+	DBSlowTransactionsTotal:        "lxd_db_slow_transactions_total",
+	DiskReadBytesTotal:             "lxd_disk_read_bytes_total",
+	DiskReadsCompletedTotal:        "lxd_disk_reads_completed_total",
+	DiskWrittenBytesTotal:          "lxd_disk_written_bytes_total",
+	DiskWritesCompletedTotal:       "lxd_disk_writes_completed_total",
+	FilesystemAvailBytes:           "lxd_filesystem_avail_bytes",
+	FilesystemFreeBytes:            "lxd_filesystem_free_bytes",
+	FilesystemSizeBytes:            "lxd_filesystem_size_bytes",
+	GoAllocBytes:                   "lxd_go_alloc_bytes",
+	GoAllocBytesTotal:              "lxd_go_alloc_bytes_total",
+	GoBuckHashSysBytes:             "lxd_go_buck_hash_sys_bytes",
+	GoFreesTotal:                   "lxd_go_frees_total",
+	GoGCSysBytes:                   "lxd_go_gc_sys_bytes",
+	GoGoroutines:                   "lxd_go_goroutines",
+	GoHeapAllocBytes:               "lxd_go_heap_alloc_bytes",
+	GoHeapIdleBytes:                "lxd_go_heap_idle_bytes",
+	GoHeapInuseBytes:               "lxd_go_heap_inuse_bytes",
+	GoHeapObjects:                  "lxd_go_heap_objects",
+	GoHeapReleasedBytes:            "lxd_go_heap_released_bytes",
+	GoHeapSysBytes:                 "lxd_go_heap_sys_bytes",
+	GoLookupsTotal:                 "lxd_go_lookups_total",
+	GoMallocsTotal:                 "lxd_go_mallocs_total",
+	GoMCacheInuseBytes:             "lxd_go_mcache_inuse_bytes",
+	GoMCacheSysBytes:               "lxd_go_mcache_sys_bytes",
+	GoMSpanInuseBytes:              "lxd_go_mspan_inuse_bytes",
+	GoMSpanSysBytes:                "lxd_go_mspan_sys_bytes",
+	GoNextGCBytes:                  "lxd_go_next_gc_bytes",
+	GoOtherSysBytes:                "lxd_go_other_sys_bytes",
+	GoStackInuseBytes:              "lxd_go_stack_inuse_bytes",
+	GoStackSysBytes:                "lxd_go_stack_sys_bytes",
+	GoSysBytes:                     "lxd_go_sys_bytes",
+	MemoryActiveAnonBytes:          "lxd_memory_Active_anon_bytes",
+	MemoryActiveFileBytes:          "lxd_memory_Active_file_bytes",
+	MemoryActiveBytes:              "lxd_memory_Active_bytes",
+	MemoryBalloonResizesTotal:      "lxd_memory_balloon_resizes_total",
+	MemoryBalloonTargetBytes:       "lxd_memory_balloon_target_bytes",
+	MemoryCachedBytes:              "lxd_memory_Cached_bytes",
+	MemoryDirtyBytes:               "lxd_memory_Dirty_bytes",
+	MemoryHugePagesFreeBytes:       "lxd_memory_HugepagesFree_bytes",
+	MemoryHugePagesTotalBytes:      "lxd_memory_HugepagesTotal_bytes",
+	MemoryInactiveAnonBytes:        "lxd_memory_Inactive_anon_bytes",
+	MemoryInactiveFileBytes:        "lxd_memory_Inactive_file_bytes",
+	MemoryInactiveBytes:            "lxd_memory_Inactive_bytes",
+	MemoryMappedBytes:              "lxd_memory_Mapped_bytes",
+	MemoryMemAvailableBytes:        "lxd_memory_MemAvailable_bytes",
+	MemoryMemFreeBytes:             "lxd_memory_MemFree_bytes",
+	MemoryMemTotalBytes:            "lxd_memory_MemTotal_bytes",
+	MemoryRSSBytes:                 "lxd_memory_RSS_bytes",
+	MemoryShmemBytes:               "lxd_memory_Shmem_bytes",
+	MemorySwapBytes:                "lxd_memory_Swap_bytes",
+	MemoryUnevictableBytes:         "lxd_memory_Unevictable_bytes",
+	MemoryWritebackBytes:           "lxd_memory_Writeback_bytes",
+	MemoryOOMKillsTotal:            "lxd_memory_OOM_kills_total",
+	NetworkConntrackEntries:        "lxd_network_conntrack_entries",
+	NetworkNATTranslations:         "lxd_network_nat_translations",
+	NetworkReceiveBytesTotal:       "lxd_network_receive_bytes_total",
+	NetworkReceiveDropTotal:        "lxd_network_receive_drop_total",
+	NetworkReceiveErrsTotal:        "lxd_network_receive_errs_total",
+	NetworkReceivePacketsTotal:     "lxd_network_receive_packets_total",
+	NetworkTransmitBytesTotal:      "lxd_network_transmit_bytes_total",
+	NetworkTransmitDropTotal:       "lxd_network_transmit_drop_total",
+	NetworkTransmitErrsTotal:       "lxd_network_transmit_errs_total",
+	NetworkTransmitPacketsTotal:    "lxd_network_transmit_packets_total",
+	OperationDurationSeconds:       "lxd_operation_duration_seconds",
+	OperationsQueued:               "lxd_operations_queued",
+	OperationsTotal:                "lxd_operations_total",
+	ProcsTotal:                     "lxd_procs_total",
+	StoragePoolIOErrorsTotal:       "lxd_storage_pool_io_errors_total",
+	StoragePoolReadLatencySeconds:  "lxd_storage_pool_read_latency_seconds",
+	StoragePoolSizeBytes:           "lxd_storage_pool_size_bytes",
+	StoragePoolUsedBytes:           "lxd_storage_pool_used_bytes",
+	StoragePoolWriteLatencySeconds: "lxd_storage_pool_write_latency_seconds",
+	UptimeSeconds:                  "lxd_uptime_seconds",
+	Warnings:                       "lxd_warnings",
+	WarningsTotal:                  "lxd_warnings_total",
+	Instances:                      "lxd_instances",
 }
 
 // MetricHeaders represents the metric headers which contain help messages as specified by OpenMetrics.
 var MetricHeaders = map[MetricType]string{
-	APICompletedRequests:        "# HELP lxd_api_requests_completed_total The total number of completed API requests.",
-	APIOngoingRequests:          "# HELP lxd_api_requests_ongoing The number of API requests currently being handled.",
-	CPUSecondsTotal:             "# HELP lxd_cpu_seconds_total The total number of CPU time used in seconds.",
-	CPUs:                        "# HELP lxd_cpu_effective_total The total number of effective CPUs.",
-	DiskReadBytesTotal:          "# HELP lxd_disk_read_bytes_total The total number of bytes read.",
-	DiskReadsCompletedTotal:     "# HELP lxd_disk_reads_completed_total The total number of completed reads.",
-	DiskWrittenBytesTotal:       "# HELP lxd_disk_written_bytes_total The total number of bytes written.",
-	DiskWritesCompletedTotal:    "# HELP lxd_disk_writes_completed_total The total number of completed writes.",
-	FilesystemAvailBytes:        "# HELP lxd_filesystem_avail_bytes The number of available space in bytes.",
-	FilesystemFreeBytes:         "# HELP lxd_filesystem_free_bytes The number of free space in bytes.",
-	FilesystemSizeBytes:         "# HELP lxd_filesystem_size_bytes The size of the filesystem in bytes.",
-	GoAllocBytes:                "# HELP lxd_go_alloc_bytes Number of bytes allocated and still in use.",
-	GoAllocBytesTotal:           "# HELP lxd_go_alloc_bytes_total Total number of bytes allocated, even if freed.",
-	GoBuckHashSysBytes:          "# HELP lxd_go_buck_hash_sys_bytes Number of bytes used by the profiling bucket hash table.",
-	GoFreesTotal:                "# HELP lxd_go_frees_total Total number of frees.",
-	GoGCSysBytes:                "# HELP lxd_go_gc_sys_bytes Number of bytes used for garbage collection system metadata.",
-	GoGoroutines:                "# HELP lxd_go_goroutines Number of goroutines that currently exist.",
-	GoHeapAllocBytes:            "# HELP lxd_go_heap_alloc_bytes Number of heap bytes allocated and still in use.",
-	GoHeapIdleBytes:             "# HELP lxd_go_heap_idle_bytes Number of heap bytes waiting to be used.",
-	GoHeapInuseBytes:            "# HELP lxd_go_heap_inuse_bytes Number of heap bytes that are in use.",
-	GoHeapObjects:               "# HELP lxd_go_heap_objects Number of allocated objects.",
-	GoHeapReleasedBytes:         "# HELP lxd_go_heap_released_bytes Number of heap bytes released to OS.",
-	GoHeapSysBytes:              "# HELP lxd_go_heap_sys_bytes Number of heap bytes obtained from system.",
-	GoLookupsTotal:              "# HELP lxd_go_lookups_total Total number of pointer lookups.",
-	GoMallocsTotal:              "# HELP lxd_go_mallocs_total Total number of mallocs.",
-	GoMCacheInuseBytes:          "# HELP lxd_go_mcache_inuse_bytes Number of bytes in use by mcache structures.",
-	GoMCacheSysBytes:            "# HELP lxd_go_mcache_sys_bytes Number of bytes used for mcache structures obtained from system.",
-	GoMSpanInuseBytes:           "# HELP lxd_go_mspan_inuse_bytes Number of bytes in use by mspan structures.",
-	GoMSpanSysBytes:             "# HELP lxd_go_mspan_sys_bytes Number of bytes used for mspan structures obtained from system.",
-	GoNextGCBytes:               "# HELP lxd_go_next_gc_bytes Number of heap bytes when next garbage collection will take place.",
-	GoOtherSysBytes:             "# HELP lxd_go_other_sys_bytes Number of bytes used for other system allocations.",
-	GoStackInuseBytes:           "# HELP lxd_go_stack_inuse_bytes Number of bytes in use by the stack allocator.",
-	GoStackSysBytes:             "# HELP lxd_go_stack_sys_bytes Number of bytes obtained from system for stack allocator.",
-	GoSysBytes:                  "# HELP lxd_go_sys_bytes Number of bytes obtained from system.",
-	MemoryActiveAnonBytes:       "# HELP lxd_memory_Active_anon_bytes The amount of anonymous memory on active LRU list.",
-	MemoryActiveFileBytes:       "# HELP lxd_memory_Active_file_bytes The amount of file-backed memory on active LRU list.",
-	MemoryActiveBytes:           "# HELP lxd_memory_Active_bytes The amount of memory on active LRU list.",
-	MemoryCachedBytes:           "# HELP lxd_memory_Cached_bytes The amount of cached memory.",
-	MemoryDirtyBytes:            "# HELP lxd_memory_Dirty_bytes The amount of memory waiting to get written back to the disk.",
-	MemoryHugePagesFreeBytes:    "# HELP lxd_memory_HugepagesFree_bytes The amount of free memory for hugetlb.",
-	MemoryHugePagesTotalBytes:   "# HELP lxd_memory_HugepagesTotal_bytes The amount of used memory for hugetlb.",
-	MemoryInactiveAnonBytes:     "# HELP lxd_memory_Inactive_anon_bytes The amount of anonymous memory on inactive LRU list.",
-	MemoryInactiveFileBytes:     "# HELP lxd_memory_Inactive_file_bytes The amount of file-backed memory on inactive LRU list.",
-	MemoryInactiveBytes:         "# HELP lxd_memory_Inactive_bytes The amount of memory on inactive LRU list.",
-	MemoryMappedBytes:           "# HELP lxd_memory_Mapped_bytes The amount of mapped memory.",
-	MemoryMemAvailableBytes:     "# HELP lxd_memory_MemAvailable_bytes The amount of available memory.",
-	MemoryMemFreeBytes:          "# HELP lxd_memory_MemFree_bytes The amount of free memory.",
-	MemoryMemTotalBytes:         "# HELP lxd_memory_MemTotal_bytes The amount of used memory.",
-	MemoryRSSBytes:              "# HELP lxd_memory_RSS_bytes The amount of anonymous and swap cache memory.",
-	MemoryShmemBytes:            "# HELP lxd_memory_Shmem_bytes The amount of cached filesystem data that is swap-backed.",
-	MemorySwapBytes:             "# HELP lxd_memory_Swap_bytes The amount of used swap memory.",
-	MemoryUnevictableBytes:      "# HELP lxd_memory_Unevictable_bytes The amount of unevictable memory.",
-	MemoryWritebackBytes:        "# HELP lxd_memory_Writeback_bytes The amount of memory queued for syncing to disk.",
-	MemoryOOMKillsTotal:         "# HELP lxd_memory_OOM_kills_total The number of out of memory kills.",
-	NetworkReceiveBytesTotal:    "# HELP lxd_network_receive_bytes_total The amount of received bytes on a given interface.",
-	NetworkReceiveDropTotal:     "# HELP lxd_network_receive_drop_total The amount of received dropped bytes on a given interface.",
-	NetworkReceiveErrsTotal:     "# HELP lxd_network_receive_errs_total The amount of received errors on a given interface.",
-	NetworkReceivePacketsTotal:  "# HELP lxd_network_receive_packets_total The amount of received packets on a given interface.",
-	NetworkTransmitBytesTotal:   "# HELP lxd_network_transmit_bytes_total The amount of transmitted bytes on a given interface.",
-	NetworkTransmitDropTotal:    "# HELP lxd_network_transmit_drop_total The amount of transmitted dropped bytes on a given interface.",
-	NetworkTransmitErrsTotal:    "# HELP lxd_network_transmit_errs_total The amount of transmitted errors on a given interface.",
-	NetworkTransmitPacketsTotal: "# HELP lxd_network_transmit_packets_total The amount of transmitted packets on a given interface.",
-	OperationsTotal:             "# HELP lxd_operations_total The number of running operations",
-	ProcsTotal:                  "# HELP lxd_procs_total The number of running processes.",
-	UptimeSeconds:               "# HELP lxd_uptime_seconds The daemon uptime in seconds.",
-	WarningsTotal:               "# HELP lxd_warnings_total The number of active warnings.",
-	Instances:                   "# HELP lxd_instances The number of instances.",
+	APICompletedRequests:           "# HELP lxd_api_requests_completed_total The total number of completed API requests.",
+	APIOngoingRequests:             "# HELP lxd_api_requests_ongoing The number of API requests currently being handled.",
+	APIRequestDurationSeconds:      "# HELP lxd_api_request_duration_seconds The duration of the completed API requests in seconds.",
+	APIRequestsTooLargeTotal:       "# HELP lxd_api_requests_too_large_total The total number of API requests rejected for exceeding the maximum request size.",
+	APISlowRequestsTotal:           "# HELP lxd_api_slow_requests_total The total number of API requests which exceeded the slow request threshold.",
+	ClusterDialFailuresTotal:       "# HELP lxd_cluster_dial_failures_total The total number of failed dqlite and raft connections to other cluster members.",
+	ClusterRaftApplyLatencySeconds: "# HELP lxd_cluster_raft_apply_latency_seconds The time it took the leader to commit its last heartbeat round to the global database in seconds.",
+	ClusterRaftLeaderChangesTotal:  "# HELP lxd_cluster_raft_leader_changes_total The total number of raft leader changes seen by the cluster member.",
+	ClusterRaftRole:                "# HELP lxd_cluster_raft_role The raft role of the database cluster members.",
+	ClusterRaftSnapshotAgeSeconds:  "# HELP lxd_cluster_raft_snapshot_age_seconds The age of the most recent raft snapshot in seconds.",
+	ConntrackDropsTotal:            "# HELP lxd_conntrack_drops_total The total number of packets dropped by conntrack.",
+	ConntrackEntries:               "# HELP lxd_conntrack_entries The number of entries in the conntrack table.",
+	ConntrackEntriesLimit:          "# HELP lxd_conntrack_entries_limit The maximum number of entries in the conntrack table.",
+	CPUSecondsTotal:                "# HELP lxd_cpu_seconds_total The total number of CPU time used in seconds.",
+	CPUs:                           "# HELP lxd_cpu_effective_total The total number of effective CPUs.",
+	DBSlowTransactionsTotal:        "# HELP lxd_db_slow_transactions_total The total number of database transactions which exceeded the slow transaction threshold.",
+	DiskReadBytesTotal:             "# HELP lxd_disk_read_bytes_total The total number of bytes read.",
+	DiskReadsCompletedTotal:        "# HELP lxd_disk_reads_completed_total The total number of completed reads.",
+	DiskWrittenBytesTotal:          "# HELP lxd_disk_written_bytes_total The total number of bytes written.",
+	DiskWritesCompletedTotal:       "# HELP lxd_disk_writes_completed_total The total number of completed writes.",
+	FilesystemAvailBytes:           "# HELP lxd_filesystem_avail_bytes The number of available space in bytes.",
+	FilesystemFreeBytes:            "# HELP lxd_filesystem_free_bytes The number of free space in bytes.",
+	FilesystemSizeBytes:            "# HELP lxd_filesystem_size_bytes The size of the filesystem in bytes.",
+	GoAllocBytes:                   "# HELP lxd_go_alloc_bytes Number of bytes allocated and still in use.",
+	GoAllocBytesTotal:              "# HELP lxd_go_alloc_bytes_total Total number of bytes allocated, even if freed.",
+	GoBuckHashSysBytes:             "# HELP lxd_go_buck_hash_sys_bytes Number of bytes used by the profiling bucket hash table.",
+	GoFreesTotal:                   "# HELP lxd_go_frees_total Total number of frees.",
+	GoGCSysBytes:                   "# HELP lxd_go_gc_sys_bytes Number of bytes used for garbage collection system metadata.",
+	GoGoroutines:                   "# HELP lxd_go_goroutines Number of goroutines that currently exist.",
+	GoHeapAllocBytes:               "# HELP lxd_go_heap_alloc_bytes Number of heap bytes allocated and still in use.",
+	GoHeapIdleBytes:                "# HELP lxd_go_heap_idle_bytes Number of heap bytes waiting to be used.",
+	GoHeapInuseBytes:               "# HELP lxd_go_heap_inuse_bytes Number of heap bytes that are in use.",
+	GoHeapObjects:                  "# HELP lxd_go_heap_objects Number of allocated objects.",
+	GoHeapReleasedBytes:            "# HELP lxd_go_heap_released_bytes Number of heap bytes released to OS.",
+	GoHeapSysBytes:                 "# HELP lxd_go_heap_sys_bytes Number of heap bytes obtained from system.",
+	GoLookupsTotal:                 "# HELP lxd_go_lookups_total Total number of pointer lookups.",
+	GoMallocsTotal:                 "# HELP lxd_go_mallocs_total Total number of mallocs.",
+	GoMCacheInuseBytes:             "# HELP lxd_go_mcache_inuse_bytes Number of bytes in use by mcache structures.",
+	GoMCacheSysBytes:               "# HELP lxd_go_mcache_sys_bytes Number of bytes used for mcache structures obtained from system.",
+	GoMSpanInuseBytes:              "# HELP lxd_go_mspan_inuse_bytes Number of bytes in use by mspan structures.",
+	GoMSpanSysBytes:                "# HELP lxd_go_mspan_sys_bytes Number of bytes used for mspan structures obtained from system.",
+	GoNextGCBytes:                  "# HELP lxd_go_next_gc_bytes Number of heap bytes when next garbage collection will take place.",
+	GoOtherSysBytes:                "# HELP lxd_go_other_sys_bytes Number of bytes used for other system allocations.",
+	GoStackInuseBytes:              "# HELP lxd_go_stack_inuse_bytes Number of bytes in use by the stack allocator.",
+	GoStackSysBytes:                "# HELP lxd_go_stack_sys_bytes Number of bytes obtained from system for stack allocator.",
+	GoSysBytes:                     "# HELP lxd_go_sys_bytes Number of bytes obtained from system.",
+	MemoryActiveAnonBytes:          "# HELP lxd_memory_Active_anon_bytes The amount of anonymous memory on active LRU list.",
+	MemoryActiveFileBytes:          "# HELP lxd_memory_Active_file_bytes The amount of file-backed memory on active LRU list.",
+	MemoryActiveBytes:              "# HELP lxd_memory_Active_bytes The amount of memory on active LRU list.",
+	MemoryBalloonResizesTotal:      "# HELP lxd_memory_balloon_resizes_total The number of automatic memory balloon resizes.",
+	MemoryBalloonTargetBytes:       "# HELP lxd_memory_balloon_target_bytes The amount of memory left to the instance by the memory balloon.",
+	MemoryCachedBytes:              "# HELP lxd_memory_Cached_bytes The amount of cached memory.",
+	MemoryDirtyBytes:               "# HELP lxd_memory_Dirty_bytes The amount of memory waiting to get written back to the disk.",
+	MemoryHugePagesFreeBytes:       "# HELP lxd_memory_HugepagesFree_bytes The amount of free memory for hugetlb.",
+	MemoryHugePagesTotalBytes:      "# HELP lxd_memory_HugepagesTotal_bytes The amount of used memory for hugetlb.",
+	MemoryInactiveAnonBytes:        "# HELP lxd_memory_Inactive_anon_bytes The amount of anonymous memory on inactive LRU list.",
+	MemoryInactiveFileBytes:        "# HELP lxd_memory_Inactive_file_bytes The amount of file-backed memory on inactive LRU list.",
+	MemoryInactiveBytes:            "# HELP lxd_memory_Inactive_bytes The amount of memory on inactive LRU list.",
+	MemoryMappedBytes:              "# HELP lxd_memory_Mapped_bytes The amount of mapped memory.",
+	MemoryMemAvailableBytes:        "# HELP lxd_memory_MemAvailable_bytes The amount of available memory.",
+	MemoryMemFreeBytes:             "# HELP lxd_memory_MemFree_bytes The amount of free memory.",
+	MemoryMemTotalBytes:            "# HELP lxd_memory_MemTotal_bytes The amount of used memory.",
+	MemoryRSSBytes:                 "# HELP lxd_memory_RSS_bytes The amount of anonymous and swap cache memory.",
+	MemoryShmemBytes:               "# HELP lxd_memory_Shmem_bytes The amount of cached filesystem data that is swap-backed.",
+	MemorySwapBytes:                "# HELP lxd_memory_Swap_bytes The amount of used swap memory.",
+	MemoryUnevictableBytes:         "# HELP lxd_memory_Unevictable_bytes The amount of unevictable memory.",
+	MemoryWritebackBytes:           "# HELP lxd_memory_Writeback_bytes The amount of memory queued for syncing to disk.",
+	MemoryOOMKillsTotal:            "# HELP lxd_memory_OOM_kills_total The number of out of memory kills.",
+	NetworkConntrackEntries:        "# HELP lxd_network_conntrack_entries The number of conntrack entries of the network.",
+	NetworkNATTranslations:         "# HELP lxd_network_nat_translations The number of conntrack entries of the network whose addresses are translated.",
+	NetworkReceiveBytesTotal:       "# HELP lxd_network_receive_bytes_total The amount of received bytes on a given interface.",
+	NetworkReceiveDropTotal:        "# HELP lxd_network_receive_drop_total The amount of received dropped bytes on a given interface.",
+	NetworkReceiveErrsTotal:        "# HELP lxd_network_receive_errs_total The amount of received errors on a given interface.",
+	NetworkReceivePacketsTotal:     "# HELP lxd_network_receive_packets_total The amount of received packets on a given interface.",
+	NetworkTransmitBytesTotal:      "# HELP lxd_network_transmit_bytes_total The amount of transmitted bytes on a given interface.",
+	NetworkTransmitDropTotal:       "# HELP lxd_network_transmit_drop_total The amount of transmitted dropped bytes on a given interface.",
+	NetworkTransmitErrsTotal:       "# HELP lxd_network_transmit_errs_total The amount of transmitted errors on a given interface.",
+	NetworkTransmitPacketsTotal:    "# HELP lxd_network_transmit_packets_total The amount of transmitted packets on a given interface.",
+	OperationDurationSeconds:       "# HELP lxd_operation_duration_seconds The duration of the completed operations in seconds.",
+	OperationsQueued:               "# HELP lxd_operations_queued The number of operations waiting in a queue.",
+	OperationsTotal:                "# HELP lxd_operations_total The number of running operations",
+	ProcsTotal:                     "# HELP lxd_procs_total The number of running processes.",
+	StoragePoolIOErrorsTotal:       "# HELP lxd_storage_pool_io_errors_total The number of I/O errors of the storage pool.",
+	StoragePoolReadLatencySeconds:  "# HELP lxd_storage_pool_read_latency_seconds The latency of the read operations of the storage pool in seconds.",
+	StoragePoolSizeBytes:           "# HELP lxd_storage_pool_size_bytes The size of the storage pool in bytes.",
+	StoragePoolUsedBytes:           "# HELP lxd_storage_pool_used_bytes The used space of the storage pool in bytes.",
+	StoragePoolWriteLatencySeconds: "# HELP lxd_storage_pool_write_latency_seconds The latency of the write operations of the storage pool in seconds.",
+	UptimeSeconds:                  "# HELP lxd_uptime_seconds The daemon uptime in seconds.",
+	Warnings:                       "# HELP lxd_warnings The number of unresolved warnings by type, severity and status.",
+	WarningsTotal:                  "# HELP lxd_warnings_total The number of active warnings.",
+	Instances:                      "# HELP lxd_instances The number of instances.",
 }
@@ -137,6 +137,7 @@ type migrationSourceWs struct {
 	migrationFields
 
 	clusterMoveSourceName string
+	migrationConfig       map[string]string
 
 	pushCertificate  string
 	pushOperationURL string
@@ -13,11 +13,13 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/instance/operationlock"
+	"github.com/canonical/lxd/lxd/migration"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
 )
 
 func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool, allowInconsistent bool, clusterMoveSourceName string, pushTarget *api.InstancePostTarget) (*migrationSourceWs, error) {
@@ -84,7 +86,7 @@ func newMigrationSource(inst instance.Instance, stateful bool, instanceOnly bool
 func (s *migrationSourceWs) Do(state *state.State, migrateOp *operations.Operation) error {
 	l := logger.AddContext(logger.Ctx{"project": s.instance.Project().Name, "instance": s.instance.Name(), "live": s.live, "clusterMoveSourceName": s.clusterMoveSourceName, "push": s.pushOperationURL != ""})
 
-	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*10)
+	ctx, cancel := context.WithTimeout(migrateOp.Context(), time.Second*10)
 	defer cancel()
 
 	l.Info("Waiting for migration control connection on source")
@@ -99,6 +101,21 @@ func (s *migrationSourceWs) Do(state *state.State, migrateOp *operations.Operati
 	defer l.Info("Migration channels disconnected on source")
 	defer s.disconnect()
 
+	// Abort the migration on both sides once the cancellation of the operation is requested.
+	stopCancel := context.AfterFunc(migrateOp.Context(), func() { s.sendControl(operations.ErrCancelled) })
+	defer stopCancel()
+
+	// Throttle the transfers to the lowest of the bandwidth limits of the cluster member and of the request.
+	requestBandwidth, err := units.ParseByteSizeString(s.migrationConfig["migration.bandwidth"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing migration bandwidth: %w", err)
+	}
+
+	bandwidth := migration.BandwidthLimit(state.LocalConfig.MaxMigrationBandwidth(), requestBandwidth)
+	if bandwidth > 0 {
+		l.Info("Limiting migration bandwidth on source", logger.Ctx{"bandwidth": bandwidth})
+	}
+
 	stateConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
 		conn := s.conns[api.SecretNameState]
 		if conn == nil {
@@ -110,7 +127,7 @@ func (s *migrationSourceWs) Do(state *state.State, migrateOp *operations.Operati
 			return nil, fmt.Errorf("Failed getting migration source control connection: %w", err)
 		}
 
-		return wsConn, nil
+		return migration.NewBandwidthLimitedConn(wsConn, bandwidth), nil
 	}
 
 	filesystemConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
@@ -124,7 +141,7 @@ func (s *migrationSourceWs) Do(state *state.State, migrateOp *operations.Operati
 			return nil, fmt.Errorf("Failed getting migration source filesystem connection: %w", err)
 		}
 
-		return wsConn, nil
+		return migration.NewBandwidthLimitedConn(wsConn, bandwidth), nil
 	}
 
 	s.instance.SetOperation(migrateOp)
@@ -146,6 +163,7 @@ func (s *migrationSourceWs) Do(state *state.State, migrateOp *operations.Operati
 			ClusterMoveSourceName: s.clusterMoveSourceName,
 		},
 		AllowInconsistent: s.allowInconsistent,
+		MigrationConfig:   s.migrationConfig,
 	})
 	if err != nil {
 		l.Error("Failed migration on source", logger.Ctx{"err": err})
@@ -91,6 +91,10 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 	defer l.Info("Migration channels disconnected on source")
 	defer s.disconnect()
 
+	// Abort the migration on both sides once the cancellation of the operation is requested.
+	stopCancel := context.AfterFunc(migrateOp.Context(), func() { s.sendControl(operations.ErrCancelled) })
+	defer stopCancel()
+
 	var poolMigrationTypes []migration.Type
 
 	pool, err := storagePools.LoadByName(state, poolName)
@@ -190,7 +194,8 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 		return err
 	}
 
-	err = pool.MigrateCustomVolume(projectName, fsConn, volSourceArgs, migrateOp)
+	// Throttle the storage transfer to the bandwidth limit of the cluster member.
+	err = pool.MigrateCustomVolume(projectName, migration.NewBandwidthLimitedConn(fsConn, state.LocalConfig.MaxMigrationBandwidth()), volSourceArgs, migrateOp)
 	if err != nil {
 		s.sendControl(err)
 		return err
@@ -0,0 +1,77 @@
+package migration
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// bandwidthLimitedConn is a connection whose writes are throttled to a maximum bandwidth.
+type bandwidthLimitedConn struct {
+	io.ReadWriteCloser
+
+	limit int64 // Bytes per second.
+
+	mu      sync.Mutex
+	start   time.Time
+	written int64
+}
+
+// NewBandwidthLimitedConn returns a connection throttling the data written to conn to limit bytes per second.
+// The connection is returned as is if limit isn't positive.
+func NewBandwidthLimitedConn(conn io.ReadWriteCloser, limit int64) io.ReadWriteCloser {
+	if limit <= 0 {
+		return conn
+	}
+
+	return &bandwidthLimitedConn{ReadWriteCloser: conn, limit: limit}
+}
+
+// Write writes p to the underlying connection in chunks, sleeping between them to stay under the bandwidth limit.
+func (c *bandwidthLimitedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Start a new measurement period after idle times so that they don't allow bursts above the limit.
+	expected := time.Duration(float64(c.written) / float64(c.limit) * float64(time.Second))
+	if c.start.IsZero() || time.Since(c.start)-expected > time.Second {
+		c.start = time.Now()
+		c.written = 0
+	}
+
+	// Write at most a tenth of a second worth of data at once to keep the rate smooth.
+	chunkSize := max(int(c.limit/10), 1)
+
+	total := 0
+	for len(p) > 0 {
+		n, err := c.ReadWriteCloser.Write(p[:min(len(p), chunkSize)])
+		total += n
+		c.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+
+		p = p[n:]
+
+		// Wait until the average rate over the measurement period is back under the limit.
+		expected = time.Duration(float64(c.written) / float64(c.limit) * float64(time.Second))
+		elapsed := time.Since(c.start)
+		if expected > elapsed {
+			time.Sleep(expected - elapsed)
+		}
+	}
+
+	return total, nil
+}
+
+// BandwidthLimit returns the lowest of the given bandwidth limits, ignoring the ones that aren't positive (0 if none is).
+func BandwidthLimit(limits ...int64) int64 {
+	var lowest int64
+	for _, limit := range limits {
+		if limit > 0 && (lowest == 0 || limit < lowest) {
+			lowest = limit
+		}
+	}
+
+	return lowest
+}
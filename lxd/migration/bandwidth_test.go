@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func TestBandwidthLimit(t *testing.T) {
+	tests := []struct {
+		limits []int64
+		want   int64
+	}{
+		{limits: nil, want: 0},
+		{limits: []int64{0, 0}, want: 0},
+		{limits: []int64{0, 100}, want: 100},
+		{limits: []int64{300, 0, 100, 200}, want: 100},
+	}
+
+	for _, tt := range tests {
+		got := BandwidthLimit(tt.limits...)
+		if got != tt.want {
+			t.Errorf("BandwidthLimit(%v) = %d, want %d", tt.limits, got, tt.want)
+		}
+	}
+}
+
+func TestNewBandwidthLimitedConn(t *testing.T) {
+	conn := &nopCloser{}
+
+	// No limit returns the connection as is.
+	if NewBandwidthLimitedConn(conn, 0) != io.ReadWriteCloser(conn) {
+		t.Fatal("Expected the connection to be returned as is without limit")
+	}
+
+	// Writing 3KiB at 10KiB/s takes about 300ms.
+	limited := NewBandwidthLimitedConn(conn, 10*1024)
+	start := time.Now()
+	n, err := limited.Write(make([]byte, 3*1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elapsed := time.Since(start)
+	if n != 3*1024 || conn.Len() != 3*1024 {
+		t.Fatalf("Expected 3072 bytes to be written, got %d", n)
+	}
+
+	if elapsed < 250*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatalf("Unexpected write duration %v", elapsed)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"slices"
+	"strings"
 
 	backupConfig "github.com/canonical/lxd/lxd/backup/config"
 	"github.com/canonical/lxd/lxd/operations"
@@ -268,6 +269,7 @@ func progressWrapperRender(op *operations.Operation, key string, description str
 
 	if meta[key] != progress {
 		meta[key] = progress
+		meta[api.OperationProgressMetadataKey] = op.RecordProgress(strings.TrimSuffix(key, "_progress"), ioprogress.ProgressData{Name: description, TransferredBytes: progressInt, Speed: speedInt})
 		_ = op.UpdateMetadata(meta)
 	}
 }
@@ -112,6 +112,15 @@ func (n *common) init(state *state.State, id int64, projectName string, netInfo
 	n.status = netInfo.Status
 	n.managed = netInfo.Managed
 	n.nodes = netNodes
+
+	// Use the parent interface mapped to the local cluster member when it has no member specific parent.
+	if state != nil && netInfo.Config["parent"] == "" {
+		parent := mappedParent(netInfo.Config, state.ServerName)
+		if parent != "" {
+			n.config = maps.Clone(netInfo.Config)
+			n.config["parent"] = parent
+		}
+	}
 }
 
 // FillConfig fills requested config with any default values, by default this is a no-op.
@@ -510,9 +519,16 @@ func (n *common) update(applyNetwork api.NetworkPut, targetNode string, clientTy
 			}
 		}
 
+		// Don't store the parent interface mapped to the local cluster member as member specific config.
+		dbConfig := applyNetwork.Config
+		if dbConfig["parent"] != "" && dbConfig["parent"] == mappedParent(dbConfig, n.state.ServerName) {
+			dbConfig = maps.Clone(dbConfig)
+			delete(dbConfig, "parent")
+		}
+
 		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			// Update the database.
-			return tx.UpdateNetwork(ctx, n.project, n.name, applyNetwork.Description, applyNetwork.Config)
+			return tx.UpdateNetwork(ctx, n.project, n.name, applyNetwork.Description, dbConfig)
 		})
 		if err != nil {
 			return err
@@ -82,6 +82,15 @@ func (n *macvlan) Validate(config map[string]string) error {
 		//  shortdesc: Parent interface to create `macvlan` NICs on
 		//  scope: local
 		"parent": validate.Required(validate.IsNotEmpty, validate.IsInterfaceName),
+		// lxdmeta:generate(entities=network-macvlan; group=network-conf; key=parent.members)
+		// Comma-separated list of `<member>=<interface>` entries, for example `server1=eth0,server2=eth1`.
+		// Cluster members without a member-specific `parent` use the interface mapped to them.
+		// See {ref}`network-parent-members` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Parent interface of each cluster member
+		//  scope: global
+		"parent.members": validate.Optional(validParentMembers),
 		// lxdmeta:generate(entities=network-macvlan; group=network-conf; key=mtu)
 		//
 		// ---
@@ -41,6 +41,15 @@ func (n *physical) Validate(config map[string]string) error {
 		//  shortdesc: Existing interface to use for network
 		//  scope: local
 		"parent": validate.Required(validate.IsNotEmpty, validate.IsInterfaceName),
+		// lxdmeta:generate(entities=network-physical; group=network-conf; key=parent.members)
+		// Comma-separated list of `<member>=<interface>` entries, for example `server1=eth0,server2=eth1`.
+		// Cluster members without a member-specific `parent` use the interface mapped to them.
+		// See {ref}`network-parent-members` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Parent interface of each cluster member
+		//  scope: global
+		"parent.members": validate.Optional(validParentMembers),
 		// lxdmeta:generate(entities=network-physical; group=network-conf; key=mtu)
 		//
 		// ---
@@ -270,7 +279,7 @@ func (n *physical) checkParentUse(ourConfig map[string]string) error {
 			}
 
 			// Check if another network is using our parent.
-			if network.Config["parent"] == ourConfig["parent"] {
+			if memberParent(network.Config, n.state.ServerName) == ourConfig["parent"] {
 				// If either network doesn't specify a vlan, or both specify same vlan,
 				// then we can't use this parent.
 				if (network.Config["vlan"] == "" || ourConfig["vlan"] == "") || network.Config["vlan"] == ourConfig["vlan"] {
@@ -34,6 +34,7 @@ import (
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
@@ -158,6 +159,25 @@ func UsedBy(s *state.State, networkProjectName string, networkID int64, networkN
 		}
 	}
 
+	// If managed network being passed in, add the projects that it is shared with.
+	if networkID > 0 {
+		var sharedWith []string
+
+		err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			sharedWith, err = cluster.GetProjectSharesUsedBy(ctx, tx.Tx(), entity.TypeNetwork, int(networkID))
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting network shares: %w", err)
+		}
+
+		usedBy = append(usedBy, sharedWith...)
+		if firstOnly && len(usedBy) > 0 {
+			return usedBy, nil
+		}
+	}
+
 	// Only networks defined in the default project can be used by other networks. Cheapest to do.
 	if networkProjectName == api.ProjectDefaultName {
 		// Get all managed networks across all projects.
@@ -179,7 +199,7 @@ func UsedBy(s *state.State, networkProjectName string, networkID int64, networkN
 
 				// The network's config references the network we are searching for. Either by
 				// directly referencing our network or by referencing our interface as its parent.
-				if network.Config["network"] == networkName || network.Config["parent"] == networkName {
+				if network.Config["network"] == networkName || memberParent(network.Config, s.ServerName) == networkName {
 					usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "networks", network.Name).Project(projectName).String())
 
 					if firstOnly {
@@ -1508,6 +1528,79 @@ func AllowedUplinkNetworks(ctx context.Context, tx *db.ClusterTx, projectConfig
 	return allowedUplinkNetworkNames, nil
 }
 
+// ParseParentMembers parses the parent.members network config key, a comma separated list of
+// <member>=<interface> entries, into a map of cluster member names to parent interfaces.
+func ParseParentMembers(value string) (map[string]string, error) {
+	parents := map[string]string{}
+
+	for _, entry := range shared.SplitNTrimSpace(value, ",", -1, true) {
+		member, parent, ok := strings.Cut(entry, "=")
+		member = strings.TrimSpace(member)
+		parent = strings.TrimSpace(parent)
+		if !ok || member == "" || parent == "" {
+			return nil, fmt.Errorf("Invalid parent mapping %q, expected <member>=<interface>", entry)
+		}
+
+		err := validate.IsInterfaceName(parent)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid parent interface for member %q: %w", member, err)
+		}
+
+		_, found := parents[member]
+		if found {
+			return nil, fmt.Errorf("Duplicate parent mapping for member %q", member)
+		}
+
+		parents[member] = parent
+	}
+
+	return parents, nil
+}
+
+// validParentMembers validates the parent.members network config key.
+func validParentMembers(value string) error {
+	_, err := ParseParentMembers(value)
+	return err
+}
+
+// mappedParent returns the parent interface mapped to the cluster member by the parent.members network config key.
+func mappedParent(config map[string]string, memberName string) string {
+	parents, err := ParseParentMembers(config["parent.members"])
+	if err != nil {
+		return ""
+	}
+
+	return parents[memberName]
+}
+
+// memberParent returns the parent interface of the network on the cluster member, either from its member specific
+// parent config key or from the parent.members mapping.
+func memberParent(config map[string]string, memberName string) string {
+	if config["parent"] != "" {
+		return config["parent"]
+	}
+
+	return mappedParent(config, memberName)
+}
+
+// ApplyParentMembers sets the parent of the new network config from the parent.members mapping when it has no
+// parent, or when its parent comes from the parent.members mapping of the old network config, so that changes of the
+// mapping apply to the cluster member.
+func ApplyParentMembers(oldConfig map[string]string, newConfig map[string]string, memberName string) {
+	oldParent := oldConfig["parent"]
+	if newConfig["parent"] != "" && (newConfig["parent"] != oldParent || oldParent != mappedParent(oldConfig, memberName)) {
+		return
+	}
+
+	newParent := mappedParent(newConfig, memberName)
+	if newParent == "" {
+		delete(newConfig, "parent")
+		return
+	}
+
+	newConfig["parent"] = newParent
+}
+
 // complementRangesIP4 returns the complement of the provided IPv4 network ranges.
 // Accepts a slice of IPv4 ranges and its network's address as parameters.
 // It calculates the IPv4 ranges that are *not* covered by the input slice and
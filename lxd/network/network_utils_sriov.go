@@ -96,7 +96,7 @@ func SRIOVGetHostDevicesInUse(s *state.State) (map[string]struct{}, error) {
 	for _, networks := range projectNetworks {
 		for _, ni := range networks {
 			// If network references a parent host interface name, mark that as reserved.
-			parent := ni.Config["parent"]
+			parent := memberParent(ni.Config, s.ServerName)
 			if parent != "" {
 				reservedDevices[parent] = struct{}{}
 			}
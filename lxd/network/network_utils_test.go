@@ -253,3 +253,34 @@ func Example_complementRangesIP4() {
 	// Range2: 10.1.1.1-10.1.1.9, 10.1.1.101-10.1.1.199, 10.1.1.231-10.1.1.255
 	// Range3: 10.1.1.1-10.1.1.9, 10.1.1.26-10.1.1.255
 }
+
+func Test_ParseParentMembers(t *testing.T) {
+	parents, err := ParseParentMembers("server1=eth0, server2=eth1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"server1": "eth0", "server2": "eth1"}, parents)
+
+	_, err = ParseParentMembers("server1")
+	assert.Error(t, err)
+
+	_, err = ParseParentMembers("server1=eth0,server1=eth1")
+	assert.Error(t, err)
+}
+
+func Test_ApplyParentMembers(t *testing.T) {
+	oldConfig := map[string]string{"parent.members": "server1=eth0", "parent": "eth0"}
+
+	// The mapped parent follows the changes of the mapping.
+	newConfig := map[string]string{"parent.members": "server1=eth1", "parent": "eth0"}
+	ApplyParentMembers(oldConfig, newConfig, "server1")
+	assert.Equal(t, "eth1", newConfig["parent"])
+
+	// A member specific parent is kept.
+	newConfig = map[string]string{"parent.members": "server1=eth1", "parent": "eth2"}
+	ApplyParentMembers(oldConfig, newConfig, "server1")
+	assert.Equal(t, "eth2", newConfig["parent"])
+
+	// Removing the member specific parent falls back to the mapping.
+	newConfig = map[string]string{"parent.members": "server1=eth1"}
+	ApplyParentMembers(map[string]string{"parent.members": "server1=eth1", "parent": "eth2"}, newConfig, "server1")
+	assert.Equal(t, "eth1", newConfig["parent"])
+}
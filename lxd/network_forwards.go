@@ -581,18 +581,24 @@ func networkForwardPut(d *Daemon, r *http.Request) response.Response {
 	targetMember := request.QueryParam(r, "target")
 	memberSpecific := targetMember != ""
 
-	if r.Method == http.MethodPatch {
-		var forward *api.NetworkForward
+	var forward *api.NetworkForward
 
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-			_, forward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, forward, err = tx.GetNetworkForward(ctx, n.ID(), memberSpecific, listenAddress)
 
-			return err
-		})
-		if err != nil {
-			return response.SmartError(err)
-		}
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
 
+	// Validate the ETag.
+	err = util.EtagCheck(r, forward.Etag())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	if r.Method == http.MethodPatch {
 		// If config being updated via "patch" method, then merge all existing config with the keys that
 		// are present in the request config.
 		for k, v := range forward.Config {
@@ -582,18 +582,24 @@ func networkLoadBalancerPut(d *Daemon, r *http.Request) response.Response {
 	targetMember := request.QueryParam(r, "target")
 	memberSpecific := targetMember != ""
 
-	if r.Method == http.MethodPatch {
-		var loadBalancer *api.NetworkLoadBalancer
+	var loadBalancer *api.NetworkLoadBalancer
 
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-			_, loadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, loadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
 
-			return err
-		})
-		if err != nil {
-			return response.SmartError(err)
-		}
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
 
+	// Validate the ETag.
+	err = util.EtagCheck(r, loadBalancer.Etag())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	if r.Method == http.MethodPatch {
 		// If config being updated via "patch" method, then merge all existing config with the keys that
 		// are present in the request config.
 		for k, v := range loadBalancer.Config {
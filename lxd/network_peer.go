@@ -568,6 +568,23 @@ func networkPeerPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	var peer *api.NetworkPeer
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, peer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = util.EtagCheck(r, peer.Etag())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
 	err = n.PeerUpdate(peerName, req)
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed updating peer: %w", err))
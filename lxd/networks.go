@@ -158,6 +158,21 @@ func networkAccessHandler(entitlement auth.Entitlement) func(d *Daemon, r *http.
 //      description: Retrieve networks from all projects
 //      type: boolean
 //      example: true
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -name
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -217,6 +232,26 @@ func networkAccessHandler(entitlement auth.Entitlement) func(d *Daemon, r *http.
 //	    description: Retrieve networks from all projects
 //	    type: boolean
 //	    example: true
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return
+//	    type: integer
+//	    example: 100
+//	  - in: query
+//	    name: offset
+//	    description: Number of entries to skip
+//	    type: integer
+//	    example: 200
+//	  - in: query
+//	    name: sort
+//	    description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//	    type: string
+//	    example: -name
+//	  - in: query
+//	    name: fields
+//	    description: Comma-separated list of fields to return
+//	    type: string
+//	    example: name,type
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -278,6 +313,11 @@ func networksGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	pagination, err := request.PaginationParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// networks holds the network names of the managed and unmanaged networks. They are in two different slices so that
 	// we can perform access control checks differently.
 	var networks [2]map[string][]string
@@ -359,9 +399,7 @@ func networksGet(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(err)
 	}
 
-	resultString := []string{}
-	resultMap := []*api.Network{}
-	urlToNetwork := make(map[*api.URL]auth.EntitlementReporter)
+	var entries []networkListEntry
 	for kind, projectNetworks := range networks {
 		for projectName, networkNames := range projectNetworks {
 			for _, networkName := range networkNames {
@@ -370,32 +408,45 @@ func networksGet(d *Daemon, r *http.Request) response.Response {
 					continue
 				}
 
-				if !recursion {
-					resultString = append(resultString, api.NewURL().Path(version.APIVersion, "networks", networkName).String())
-				} else {
-					var projectConfig map[string]string
-					if allProjects {
-						projectConfig = projectConfigs[projectName]
-					} else {
-						projectConfig = reqProject.Config
-					}
-
-					net, err := doNetworkGet(s, r, s.ServerClustered, projectName, projectConfig, networkName)
-					if err != nil {
-						continue
-					}
-
-					resultMap = append(resultMap, &net)
-					urlToNetwork[entity.NetworkURL(projectName, networkName)] = &net
-				}
+				entries = append(entries, networkListEntry{Project: projectName, Name: networkName, Managed: kind == managed})
 			}
 		}
 	}
 
+	// Select the page of networks before loading them.
+	entries, err = request.Paginate(pagination, entries, networkListSortFields)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	if !recursion {
+		resultString := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			resultString = append(resultString, api.NewURL().Path(version.APIVersion, "networks", entry.Name).String())
+		}
+
 		return response.SyncResponse(true, resultString)
 	}
 
+	resultMap := []*api.Network{}
+	urlToNetwork := make(map[*api.URL]auth.EntitlementReporter)
+	for _, entry := range entries {
+		var projectConfig map[string]string
+		if allProjects {
+			projectConfig = projectConfigs[entry.Project]
+		} else {
+			projectConfig = reqProject.Config
+		}
+
+		net, err := doNetworkGet(s, r, s.ServerClustered, entry.Project, projectConfig, entry.Name)
+		if err != nil {
+			continue
+		}
+
+		resultMap = append(resultMap, &net)
+		urlToNetwork[entity.NetworkURL(entry.Project, entry.Name)] = &net
+	}
+
 	if len(withEntitlements) > 0 {
 		err = reportEntitlements(r.Context(), s.Authorizer, entity.TypeNetwork, withEntitlements, urlToNetwork)
 		if err != nil {
@@ -403,7 +454,34 @@ func networksGet(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	return response.SyncResponse(true, resultMap)
+	result, err := pagination.SelectFields(resultMap)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// networkListEntry is a network found when listing networks, before it is loaded.
+type networkListEntry struct {
+	Project string
+	Name    string
+	Managed bool
+}
+
+// networkListSortFields are the fields networks can be sorted by.
+var networkListSortFields = []request.SortField[networkListEntry]{
+	{Name: "project", Compare: func(a networkListEntry, b networkListEntry) int { return strings.Compare(a.Project, b.Project) }},
+	{Name: "name", Compare: func(a networkListEntry, b networkListEntry) int { return strings.Compare(a.Name, b.Name) }},
+	{Name: "managed", Compare: func(a networkListEntry, b networkListEntry) int {
+		if a.Managed == b.Managed {
+			return 0
+		} else if a.Managed {
+			return 1
+		}
+
+		return -1
+	}},
 }
 
 // swagger:operation POST /1.0/networks networks networks_post
@@ -463,6 +541,11 @@ func networksPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = admitRequest(r.Context(), s, projectName, entity.TypeNetwork, "create", req.Name, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Quick checks.
 	if req.Name == "" {
 		return response.BadRequest(errors.New("No name provided"))
@@ -602,8 +685,14 @@ func networksPost(d *Daemon, r *http.Request) response.Response {
 	// No targetNode was specified and we're clustered or there is an existing partially created single node
 	// network, either way finalize the config in the db and actually create the network on all cluster nodes.
 	if count > 1 || (netInfo != nil && netInfo.Status != api.NetworkStatusCreated) {
-		// Simulate adding pending node network config when the driver doesn't support per-node config.
-		if !netTypeInfo.NodeSpecificConfig && clientType != request.ClientTypeJoiner {
+		// Simulate adding pending node network config when the driver doesn't support per-node config, or
+		// when the parent interface of each member is mapped by the parent.members config key.
+		if (!netTypeInfo.NodeSpecificConfig || req.Config["parent.members"] != "") && clientType != request.ClientTypeJoiner {
+			parents, err := network.ParseParentMembers(req.Config["parent.members"])
+			if err != nil {
+				return response.BadRequest(err)
+			}
+
 			// Create pending entry for each node.
 			err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 				members, err := tx.GetNodes(ctx)
@@ -618,6 +707,11 @@ func networksPost(d *Daemon, r *http.Request) response.Response {
 					if err != nil && !api.StatusErrorCheck(err, http.StatusConflict) {
 						return fmt.Errorf("Failed creating pending network for member %q: %w", member.Name, err)
 					}
+
+					// Members already defined with --target may have their own parent.
+					if err == nil && netTypeInfo.NodeSpecificConfig && parents[member.Name] == "" {
+						return api.StatusErrorf(http.StatusBadRequest, "No parent interface mapped to cluster member %q in %q", member.Name, "parent.members")
+					}
 				}
 
 				return nil
@@ -1100,6 +1194,19 @@ func doNetworkGet(s *state.State, r *http.Request, allNodes bool, requestProject
 		}
 
 		apiNet.Locations = n.Locations()
+
+		var ownership dbCluster.EntityOwnership
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			ownership, err = dbCluster.GetEntityOwnership(ctx, tx.Tx(), entity.TypeNetwork, int(n.ID()))
+			return err
+		})
+		if err != nil {
+			return api.Network{}, err
+		}
+
+		apiNet.CreatedAt = ownership.CreatedAt
+		apiNet.CreatedBy = ownership.CreatedBy
+		apiNet.LastModifiedBy = ownership.LastModifiedBy
 	}
 
 	return apiNet, nil
@@ -1453,6 +1560,12 @@ func networkPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// For PATCH requests, the spec only holds the fields being changed.
+	err = admitRequest(r.Context(), s, effectiveProjectName, entity.TypeNetwork, "update", details.networkName, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor, err := request.GetRequestor(r.Context())
 	if err != nil {
 		return response.SmartError(err)
@@ -1480,7 +1593,7 @@ func networkPut(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	response := doNetworkUpdate(n, req, targetNode, requestor.ClientType(), r.Method, s.ServerClustered)
+	response := doNetworkUpdate(n, req, targetNode, requestor.ClientType(), r.Method, s.ServerClustered, s.ServerName)
 
 	s.Events.SendLifecycle(effectiveProjectName, lifecycle.NetworkUpdated.Event(n, requestor.EventLifecycleRequestor(), nil))
 
@@ -1532,7 +1645,7 @@ func networkPatch(d *Daemon, r *http.Request) response.Response {
 
 // doNetworkUpdate loads the current local network config, merges with the requested network config, validates
 // and applies the changes. Will also notify other cluster nodes of non-node specific config if needed.
-func doNetworkUpdate(n network.Network, req api.NetworkPut, targetNode string, clientType request.ClientType, httpMethod string, clustered bool) response.Response {
+func doNetworkUpdate(n network.Network, req api.NetworkPut, targetNode string, clientType request.ClientType, httpMethod string, clustered bool, serverName string) response.Response {
 	if req.Config == nil {
 		req.Config = map[string]string{}
 	}
@@ -1559,6 +1672,9 @@ func doNetworkUpdate(n network.Network, req api.NetworkPut, targetNode string, c
 		}
 	}
 
+	// Follow the parent.members mapping for the parent interface of the local cluster member.
+	network.ApplyParentMembers(n.Config(), req.Config, serverName)
+
 	// Validate the merged configuration.
 	err := n.Validate(req.Config)
 	if err != nil {
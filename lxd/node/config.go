@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"runtime"
 
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -47,6 +49,17 @@ func (c *Config) HTTPSAddress() string {
 	return networkAddress
 }
 
+// HTTPSQUICAddress returns the address and port this LXD node should expose
+// its API to over HTTP/3 (QUIC), if any.
+func (c *Config) HTTPSQUICAddress() string {
+	quicAddress := c.m.GetString("core.https_quic_address")
+	if quicAddress != "" {
+		return util.CanonicalNetworkAddress(quicAddress, shared.HTTPSDefaultPort)
+	}
+
+	return quicAddress
+}
+
 // BGPAddress returns the address and port to setup the BGP listener on.
 func (c *Config) BGPAddress() string {
 	return c.m.GetString("core.bgp_address")
@@ -68,6 +81,13 @@ func (c *Config) ClusterAddress() string {
 	return clusterAddress
 }
 
+// MaxMigrationBandwidth returns the maximum bandwidth in bytes per second used by each outgoing migration (0 if
+// unlimited).
+func (c *Config) MaxMigrationBandwidth() int64 {
+	bandwidth, _ := units.ParseByteSizeString(c.m.GetString("cluster.max_migration_bandwidth"))
+	return bandwidth
+}
+
 // DebugAddress returns the address and port to setup the pprof listener on.
 func (c *Config) DebugAddress() string {
 	debugAddress := c.m.GetString("core.debug_address")
@@ -93,6 +113,16 @@ func (c *Config) MetricsAddress() string {
 	return metricsAddress
 }
 
+// InstancesAutostartParallelism returns the maximum number of instances started concurrently when LXD starts.
+func (c *Config) InstancesAutostartParallelism() int {
+	parallelism := c.m.GetInt64("instances.autostart.parallelism")
+	if parallelism <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return int(parallelism)
+}
+
 // MAASMachine returns the MAAS machine this instance is associated with, if
 // any.
 func (c *Config) MAASMachine() string {
@@ -188,6 +218,18 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Address to bind for the remote API (HTTPS)
 	"core.https_address": {Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+	// Network address for the HTTP/3 (QUIC) listener
+
+	// lxdmeta:generate(entities=server; group=core; key=core.https_quic_address)
+	// The remote API is served over HTTP/3 on this UDP address, in addition to HTTPS.
+	// Clients of the HTTPS listener are told about it through the `Alt-Svc` response header.
+	// See {ref}`server-expose-http3`.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Address to bind for the remote API over HTTP/3 (QUIC)
+	"core.https_quic_address": {Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
+
 	// Network address for cluster communication
 
 	// lxdmeta:generate(entities=server; group=cluster; key=cluster.https_address)
@@ -198,6 +240,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Address to use for clustering traffic
 	"cluster.https_address": {Validator: validate.Optional(validate.IsListenAddress(true, false, false))},
 
+	// lxdmeta:generate(entities=server; group=cluster; key=cluster.max_migration_bandwidth)
+	// The limit applies to each migration sent by this cluster member (including the instances moved during evacuation),
+	// both to the transfer of the VM memory and to the storage transfer.
+	// See {ref}`cluster-migration-bandwidth`.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Maximum bandwidth used by each outgoing migration (bytes per second)
+	"cluster.max_migration_bandwidth": {Validator: validate.Optional(validate.IsSize)},
+
 	// Network address for the BGP server
 
 	// lxdmeta:generate(entities=server; group=core; key=core.bgp_address)
@@ -269,6 +321,17 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to enable the syslog unixgram socket listener
 	"core.syslog_socket": {Validator: validate.Optional(validate.IsBool), Type: config.Bool},
 
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.autostart.parallelism)
+	// Set to `1` to start the instances one after the other.
+	// When set to `0`, the number of CPU threads of the cluster member is used.
+	// See {ref}`instances-autostart` for more information.
+	// ---
+	//  type: integer
+	//  scope: local
+	//  defaultdesc: `0`
+	//  shortdesc: Maximum number of instances started concurrently when LXD starts
+	"instances.autostart.parallelism": {Validator: validate.IsUint32, Type: config.Int64, Default: "0"},
+
 	// MAAS machine this LXD instance is associated with
 
 	// lxdmeta:generate(entities=server; group=miscellaneous; key=maas.machine)
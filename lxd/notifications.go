@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/notifications"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// notificationsDefaultTypes are the notification types sent when a project doesn't set `notifications.types`.
+var notificationsDefaultTypes = []string{api.EventTypeLifecycle, api.EventTypeAlert, notifications.TypeWarning}
+
+// setupNotifications starts routing the events of this member to the notification targets of their projects.
+// The targets are reloaded whenever a project changes on any member.
+func (d *Daemon) setupNotifications(smtp notifications.SMTP) {
+	d.notifications = notifications.NewRouter(smtp)
+	d.notificationsReload()
+
+	d.internalListener.AddHandler("notifications", func(event api.Event) {
+		if event.Type == api.EventTypeLifecycle && notificationsProjectChanged(event) {
+			go d.notificationsReload()
+		}
+
+		// The events of the other members are forwarded by the members themselves.
+		if event.Location != d.serverName {
+			return
+		}
+
+		d.notifications.HandleEvent(event)
+	})
+}
+
+// notificationsProjectChanged returns whether the lifecycle event records a change to a project.
+func notificationsProjectChanged(event api.Event) bool {
+	lifecycleEvent := api.EventLifecycle{}
+	err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+	if err != nil {
+		return false
+	}
+
+	switch lifecycleEvent.Action {
+	case string(lifecycle.ProjectCreated), string(lifecycle.ProjectUpdated), string(lifecycle.ProjectRenamed), string(lifecycle.ProjectDeleted):
+		return true
+	}
+
+	return false
+}
+
+// notificationsReload loads the notification targets of all projects.
+func (d *Daemon) notificationsReload() {
+	var projectsConfig map[string]map[string]string
+	err := d.db.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		projectsConfig, err = dbCluster.GetAllProjectsConfig(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		logger.Warn("Failed loading project notifications", logger.Ctx{"err": err})
+		return
+	}
+
+	configs := make(map[string]notifications.Config, len(projectsConfig))
+	for projectName, config := range projectsConfig {
+		types := notificationsDefaultTypes
+		if config["notifications.types"] != "" {
+			types = shared.SplitNTrimSpace(config["notifications.types"], ",", -1, true)
+		}
+
+		configs[projectName] = notifications.Config{
+			WebhookURLs:   shared.SplitNTrimSpace(config["notifications.webhook.url"], ",", -1, true),
+			WebhookSecret: config["notifications.webhook.secret"],
+			EmailTo:       shared.SplitNTrimSpace(config["notifications.email.to"], ",", -1, true),
+			Types:         types,
+		}
+	}
+
+	err = d.notifications.SetProjects(configs)
+	if err != nil {
+		logger.Warn("Failed setting up project notifications", logger.Ctx{"err": err})
+	}
+}
+
+// notificationsWarningsTask sends the notifications of the warnings newly raised in the projects.
+// Warnings are stored in the cluster database, so only the leader sends them.
+func notificationsWarningsTask(d *Daemon) (task.Func, task.Schedule) {
+	since := time.Now()
+
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		now := time.Now()
+		defer func() { since = now }()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Warn("Failed getting cluster leader for warning notifications", logger.Ctx{"err": err})
+			return
+		}
+
+		if !leaderInfo.Leader {
+			return
+		}
+
+		var warnings []dbCluster.Warning
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			status := warningtype.StatusNew
+			warnings, err = dbCluster.GetWarnings(ctx, tx.Tx(), dbCluster.WarningFilter{Status: &status})
+			return err
+		})
+		if err != nil {
+			logger.Warn("Failed getting warnings for notifications", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, w := range warnings {
+			if w.Project == "" || !w.FirstSeenDate.After(since) || w.FirstSeenDate.After(now) {
+				continue
+			}
+
+			d.notifications.HandleWarning(w.ToAPI())
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
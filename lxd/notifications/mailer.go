@@ -0,0 +1,175 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/cancel"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// mailQueueSize is the maximum number of emails waiting to be sent to a project.
+// Emails are dropped if the SMTP server can't keep up.
+const mailQueueSize = 100
+
+// SMTP holds the settings of the SMTP server used to send emails.
+type SMTP struct {
+	// Address is the host and port of the SMTP server.
+	Address string
+
+	// Username and Password authenticate to the SMTP server, if set.
+	Username string
+	Password string
+
+	// Sender is the address the emails are sent from.
+	Sender string
+}
+
+// mailer sends the notifications of a project by email.
+type mailer struct {
+	smtp SMTP
+	to   []string
+
+	emails chan []byte
+	cancel cancel.Canceller
+	wg     sync.WaitGroup
+}
+
+// newMailer returns a mailer sending emails to the given addresses through the SMTP server.
+func newMailer(server SMTP, to []string) *mailer {
+	m := &mailer{
+		smtp:   server,
+		to:     to,
+		emails: make(chan []byte, mailQueueSize),
+		cancel: cancel.New(),
+	}
+
+	m.wg.Add(1)
+	go m.run()
+
+	return m
+}
+
+// stop the mailer. Emails which weren't sent yet are dropped.
+func (m *mailer) stop() {
+	m.cancel.Cancel()
+	m.wg.Wait()
+}
+
+// handleEvent queues the email of the event.
+//
+// Warn: This must not log, as it is called for logging events too.
+func (m *mailer) handleEvent(event api.Event) {
+	msg, err := m.message(event)
+	if err != nil {
+		return
+	}
+
+	select {
+	case m.emails <- msg:
+	default:
+	}
+}
+
+// run sends the queued emails until the mailer is stopped.
+func (m *mailer) run() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.cancel.Done():
+			return
+		case msg := <-m.emails:
+			err := m.send(msg)
+			if err != nil && m.cancel.Err() == nil {
+				logger.Warn("Failed sending notification email", logger.Ctx{"server": m.smtp.Address, "err": err})
+			}
+		}
+	}
+}
+
+// send sends a single email through the SMTP server.
+func (m *mailer) send(msg []byte) error {
+	var auth smtp.Auth
+	if m.smtp.Username != "" {
+		host, _, err := net.SplitHostPort(m.smtp.Address)
+		if err != nil {
+			return err
+		}
+
+		auth = smtp.PlainAuth("", m.smtp.Username, m.smtp.Password, host)
+	}
+
+	return smtp.SendMail(m.smtp.Address, auth, m.smtp.Sender, m.to, msg)
+}
+
+// message returns the email of the event, summarising the event in its subject and holding the event in its body.
+func (m *mailer) message(event api.Event) ([]byte, error) {
+	body, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := event.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.smtp.Sender)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.to, ", "))
+	fmt.Fprintf(&b, "Subject: [LXD] %s\r\n", Summary(event))
+	fmt.Fprintf(&b, "Date: %s\r\n", timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.Write(bytes.ReplaceAll(body, []byte("\n"), []byte("\r\n")))
+	b.WriteString("\r\n")
+
+	return b.Bytes(), nil
+}
+
+// Summary returns a single line describing the event.
+func Summary(event api.Event) string {
+	summary := fmt.Sprintf("%s event in project %q", event.Type, event.Project)
+
+	switch event.Type {
+	case api.EventTypeLifecycle:
+		lifecycle := api.EventLifecycle{}
+		err := json.Unmarshal(event.Metadata, &lifecycle)
+		if err == nil {
+			summary = fmt.Sprintf("%s %s in project %q", lifecycle.Source, lifecycle.Action, event.Project)
+		}
+
+	case api.EventTypeOperation:
+		op := api.Operation{}
+		err := json.Unmarshal(event.Metadata, &op)
+		if err == nil {
+			summary = fmt.Sprintf("%s: %s in project %q", op.Description, op.Status, event.Project)
+		}
+
+	case api.EventTypeAlert:
+		alert := api.EventAlert{}
+		err := json.Unmarshal(event.Metadata, &alert)
+		if err == nil {
+			summary = fmt.Sprintf("Alert %s: %s", alert.Status, alert.Description)
+		}
+
+	case TypeWarning:
+		warning := api.Warning{}
+		err := json.Unmarshal(event.Metadata, &warning)
+		if err == nil {
+			summary = fmt.Sprintf("Warning in project %q: %s", event.Project, warning.LastMessage)
+		}
+	}
+
+	// Header values can't span multiple lines.
+	return strings.Join(strings.Fields(summary), " ")
+}
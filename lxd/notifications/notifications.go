@@ -0,0 +1,205 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/webhook"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// TypeWarning is the type of the notifications sent for the warnings raised in a project. Unlike the other types,
+// it isn't an event type, as warnings are not sent as events.
+const TypeWarning = "warning"
+
+// Config is the notification configuration of a project.
+type Config struct {
+	// WebhookURLs are the webhooks to send the notifications to.
+	WebhookURLs []string
+
+	// WebhookSecret signs the requests sent to the webhooks.
+	WebhookSecret string
+
+	// EmailTo are the email addresses to send the notifications to.
+	EmailTo []string
+
+	// Types are the types of the events to send notifications for.
+	Types []string
+}
+
+// route holds the notification targets of a project.
+type route struct {
+	config  Config
+	webhook *webhook.Client
+	mailer  *mailer
+}
+
+// stop stops the delivery of the notifications of the route.
+func (r *route) stop() {
+	if r.webhook != nil {
+		r.webhook.Stop()
+	}
+
+	if r.mailer != nil {
+		r.mailer.stop()
+	}
+}
+
+// Router sends the events of each project to the notification targets configured by the project.
+// Only the events of a project are sent to its targets, and events that aren't project specific are never sent.
+type Router struct {
+	mu     sync.Mutex
+	smtp   SMTP
+	routes map[string]*route
+}
+
+// NewRouter returns a Router sending emails through the given SMTP server.
+func NewRouter(smtp SMTP) *Router {
+	return &Router{
+		smtp:   smtp,
+		routes: map[string]*route{},
+	}
+}
+
+// newRoute returns the route delivering notifications to the targets of the config.
+func (r *Router) newRoute(projectName string, config Config) (*route, error) {
+	rt := &route{config: config}
+
+	if len(config.WebhookURLs) > 0 && len(config.Types) > 0 {
+		client, err := webhook.NewClient(config.WebhookURLs, config.WebhookSecret, config.Types, []string{projectName})
+		if err != nil {
+			return nil, err
+		}
+
+		rt.webhook = client
+	}
+
+	if len(config.EmailTo) > 0 && len(config.Types) > 0 && r.smtp.Address != "" {
+		rt.mailer = newMailer(r.smtp, config.EmailTo)
+	}
+
+	return rt, nil
+}
+
+// SetSMTP changes the SMTP server used to send emails.
+func (r *Router) SetSMTP(smtp SMTP) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if smtp == r.smtp {
+		return nil
+	}
+
+	r.smtp = smtp
+
+	for projectName, rt := range r.routes {
+		newRoute, err := r.newRoute(projectName, rt.config)
+		if err != nil {
+			return fmt.Errorf("Failed setting up notifications of project %q: %w", projectName, err)
+		}
+
+		rt.stop()
+		r.routes[projectName] = newRoute
+	}
+
+	return nil
+}
+
+// SetProjects replaces the notification configuration of all projects. Projects without any target are skipped.
+// A project whose targets are invalid doesn't prevent the notifications of the other projects from being set up.
+func (r *Router) SetProjects(configs map[string]Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for projectName, rt := range r.routes {
+		config, ok := configs[projectName]
+		if ok && equalConfig(config, rt.config) {
+			continue
+		}
+
+		rt.stop()
+		delete(r.routes, projectName)
+	}
+
+	for _, projectName := range slices.Sorted(maps.Keys(configs)) {
+		config := configs[projectName]
+		_, ok := r.routes[projectName]
+		if ok || (len(config.WebhookURLs) == 0 && len(config.EmailTo) == 0) {
+			continue
+		}
+
+		rt, err := r.newRoute(projectName, config)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("Failed setting up notifications of project %q: %w", projectName, err)
+			}
+
+			continue
+		}
+
+		r.routes[projectName] = rt
+	}
+
+	return firstErr
+}
+
+// Stop stops the delivery of all notifications. Notifications which weren't delivered yet are dropped.
+func (r *Router) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for projectName, rt := range r.routes {
+		rt.stop()
+		delete(r.routes, projectName)
+	}
+}
+
+// HandleEvent queues the notifications of the event for delivery to the targets of its project.
+//
+// Warn: This must not log, as it is called for logging events too.
+func (r *Router) HandleEvent(event api.Event) {
+	if event.Project == "" {
+		return
+	}
+
+	r.mu.Lock()
+	rt, ok := r.routes[event.Project]
+	r.mu.Unlock()
+
+	if !ok || !slices.Contains(rt.config.Types, event.Type) {
+		return
+	}
+
+	if rt.webhook != nil {
+		rt.webhook.HandleEvent(event)
+	}
+
+	if rt.mailer != nil {
+		rt.mailer.handleEvent(event)
+	}
+}
+
+// HandleWarning queues the notifications of a warning raised in a project.
+func (r *Router) HandleWarning(warning api.Warning) {
+	metadata, err := json.Marshal(warning)
+	if err != nil {
+		return
+	}
+
+	r.HandleEvent(api.Event{
+		Type:      TypeWarning,
+		Timestamp: warning.LastSeenAt,
+		Metadata:  metadata,
+		Location:  warning.Location,
+		Project:   warning.Project,
+	})
+}
+
+// equalConfig returns whether the two configs have the same targets.
+func equalConfig(a Config, b Config) bool {
+	return slices.Equal(a.WebhookURLs, b.WebhookURLs) && a.WebhookSecret == b.WebhookSecret && slices.Equal(a.EmailTo, b.EmailTo) && slices.Equal(a.Types, b.Types)
+}
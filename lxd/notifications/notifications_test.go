@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/webhook"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestRouter_HandleEvent(t *testing.T) {
+	type received struct {
+		event     api.Event
+		eventType string
+	}
+
+	newServer := func() (*httptest.Server, chan received) {
+		receivedCh := make(chan received, 10)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			event := api.Event{}
+			_ = json.Unmarshal(body, &event)
+
+			receivedCh <- received{event: event, eventType: r.Header.Get(webhook.EventTypeHeader)}
+		}))
+
+		return server, receivedCh
+	}
+
+	fooServer, fooCh := newServer()
+	defer fooServer.Close()
+
+	barServer, barCh := newServer()
+	defer barServer.Close()
+
+	r := NewRouter(SMTP{})
+	defer r.Stop()
+
+	err := r.SetProjects(map[string]Config{
+		"foo": {WebhookURLs: []string{fooServer.URL}, Types: []string{api.EventTypeLifecycle, TypeWarning}},
+		"bar": {WebhookURLs: []string{barServer.URL}, Types: []string{api.EventTypeLifecycle}},
+		"baz": {Types: []string{api.EventTypeLifecycle}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Filtered out by type and project.
+	r.HandleEvent(api.Event{Type: api.EventTypeOperation, Project: "foo"})
+	r.HandleEvent(api.Event{Type: api.EventTypeLifecycle})
+	r.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Project: "baz"})
+	r.HandleWarning(api.Warning{Project: "bar", LastMessage: "Couldn't find the CGroup blkio"})
+
+	r.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Project: "foo", Metadata: json.RawMessage(`{"action":"instance-created"}`)})
+	r.HandleWarning(api.Warning{Project: "foo", LastMessage: "Couldn't find the CGroup blkio"})
+	r.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Project: "bar", Metadata: json.RawMessage(`{"action":"instance-deleted"}`)})
+
+	expect := func(ch chan received, eventType string) {
+		t.Helper()
+
+		select {
+		case r := <-ch:
+			if r.eventType != eventType {
+				t.Fatalf("Unexpected event delivered: %+v", r.event)
+			}
+
+		case <-time.After(10 * time.Second):
+			t.Fatal("Timed out waiting for the event")
+		}
+	}
+
+	// Each target receives the events of its own project only. The order of the deliveries to a target is kept.
+	expect(fooCh, api.EventTypeLifecycle)
+	expect(fooCh, TypeWarning)
+	expect(barCh, api.EventTypeLifecycle)
+
+	select {
+	case r := <-fooCh:
+		t.Fatalf("Unexpected event delivered: %+v", r.event)
+	case r := <-barCh:
+		t.Fatalf("Unexpected event delivered: %+v", r.event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		event api.Event
+		want  string
+	}{
+		{
+			event: api.Event{Type: api.EventTypeLifecycle, Project: "foo", Metadata: json.RawMessage(`{"action":"instance-started","source":"/1.0/instances/c1"}`)},
+			want:  `/1.0/instances/c1 instance-started in project "foo"`,
+		},
+		{
+			event: api.Event{Type: TypeWarning, Project: "foo", Metadata: json.RawMessage(`{"last_message":"Disk is\nfull"}`)},
+			want:  `Warning in project "foo": Disk is full`,
+		},
+		{
+			event: api.Event{Type: api.EventTypeAlert, Project: "foo", Metadata: json.RawMessage(`invalid`)},
+			want:  `alert event in project "foo"`,
+		},
+	}
+
+	for _, test := range tests {
+		got := Summary(test.event)
+		if got != test.want {
+			t.Errorf("Summary() = %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+// Package oci converts LXD images into OCI images.
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	yaml "go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/osarch"
+)
+
+// OCI media types.
+const (
+	MediaTypeImageIndex     = "application/vnd.oci.image.index.v1+json"
+	MediaTypeImageManifest  = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageConfig    = "application/vnd.oci.image.config.v1+json"
+	MediaTypeImageLayerGzip = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+// OCI annotations.
+const (
+	AnnotationRefName     = "org.opencontainers.image.ref.name"
+	AnnotationCreated     = "org.opencontainers.image.created"
+	AnnotationDescription = "org.opencontainers.image.description"
+)
+
+// defaultPath is the PATH set in the config of the exported images.
+const defaultPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// Descriptor describes the content of a blob.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *Platform         `json:"platform,omitempty"`
+}
+
+// Platform describes the platform an image runs on.
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Index is the entrypoint of an OCI image layout.
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// Manifest describes the config and the layers of an OCI image.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// ImageConfig is the configuration of an OCI image.
+type ImageConfig struct {
+	Created      time.Time      `json:"created"`
+	Architecture string         `json:"architecture"`
+	OS           string         `json:"os"`
+	Variant      string         `json:"variant,omitempty"`
+	Config       ExecConfig     `json:"config"`
+	RootFS       RootFS         `json:"rootfs"`
+	History      []ImageHistory `json:"history,omitempty"`
+}
+
+// ExecConfig is the default execution parameters of an OCI image.
+type ExecConfig struct {
+	Env    []string          `json:"Env,omitempty"`
+	Cmd    []string          `json:"Cmd,omitempty"`
+	Labels map[string]string `json:"Labels,omitempty"`
+}
+
+// RootFS references the layers of an OCI image by their uncompressed digest.
+type RootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// ImageHistory describes how a layer of an OCI image was created.
+type ImageHistory struct {
+	Created   time.Time `json:"created"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// architectures maps the LXD architectures to the OCI architectures and variants.
+var architectures = map[int]Platform{
+	osarch.ARCH_32BIT_INTEL_X86:             {Architecture: "386"},
+	osarch.ARCH_64BIT_INTEL_X86:             {Architecture: "amd64"},
+	osarch.ARCH_32BIT_ARMV6_LITTLE_ENDIAN:   {Architecture: "arm", Variant: "v6"},
+	osarch.ARCH_32BIT_ARMV7_LITTLE_ENDIAN:   {Architecture: "arm", Variant: "v7"},
+	osarch.ARCH_32BIT_ARMV8_LITTLE_ENDIAN:   {Architecture: "arm", Variant: "v8"},
+	osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN:   {Architecture: "arm64"},
+	osarch.ARCH_32BIT_POWERPC_BIG_ENDIAN:    {Architecture: "ppc"},
+	osarch.ARCH_64BIT_POWERPC_BIG_ENDIAN:    {Architecture: "ppc64"},
+	osarch.ARCH_64BIT_POWERPC_LITTLE_ENDIAN: {Architecture: "ppc64le"},
+	osarch.ARCH_64BIT_S390_BIG_ENDIAN:       {Architecture: "s390x"},
+	osarch.ARCH_32BIT_MIPS:                  {Architecture: "mipsle"},
+	osarch.ARCH_64BIT_MIPS:                  {Architecture: "mips64le"},
+	osarch.ARCH_32BIT_RISCV_LITTLE_ENDIAN:   {Architecture: "riscv"},
+	osarch.ARCH_64BIT_RISCV_LITTLE_ENDIAN:   {Architecture: "riscv64"},
+	osarch.ARCH_64BIT_LOONGARCH:             {Architecture: "loong64"},
+}
+
+// PlatformFromArchitecture returns the OCI platform of the given LXD architecture name.
+func PlatformFromArchitecture(architecture string) (*Platform, error) {
+	archID, err := osarch.ArchitectureId(architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	platform, ok := architectures[archID]
+	if !ok {
+		return nil, fmt.Errorf("Architecture %q isn't supported by OCI images", architecture)
+	}
+
+	platform.OS = "linux"
+
+	return &platform, nil
+}
+
+// WriteLayout converts the LXD image tarball (holding metadata.yaml and the rootfs directory) read from r into an
+// OCI image layout written to dir, with a single layer holding the root filesystem.
+// The image is tagged with the given tag in the index of the layout. The descriptor of its manifest is returned.
+func WriteLayout(r io.Reader, dir string, tag string) (*Descriptor, error) {
+	blobsDir := filepath.Join(dir, "blobs", "sha256")
+	err := os.MkdirAll(blobsDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	layer, diffID, meta, err := writeLayer(r, blobsDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing image layer: %w", err)
+	}
+
+	if meta == nil {
+		return nil, errors.New("The image doesn't have a metadata.yaml file")
+	}
+
+	platform, err := PlatformFromArchitecture(meta.Architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	created := time.Now().UTC()
+	if meta.CreationDate > 0 {
+		created = time.Unix(meta.CreationDate, 0).UTC()
+	}
+
+	config := ImageConfig{
+		Created:      created,
+		Architecture: platform.Architecture,
+		OS:           platform.OS,
+		Variant:      platform.Variant,
+		Config: ExecConfig{
+			Env:    []string{defaultPath},
+			Cmd:    []string{"/sbin/init"},
+			Labels: meta.Properties,
+		},
+		RootFS: RootFS{
+			Type:    "layers",
+			DiffIDs: []string{diffID},
+		},
+		History: []ImageHistory{{Created: created, CreatedBy: "LXD instance export"}},
+	}
+
+	configDesc, err := writeJSONBlob(blobsDir, MediaTypeImageConfig, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing image config: %w", err)
+	}
+
+	annotations := map[string]string{AnnotationCreated: created.Format(time.RFC3339)}
+	if meta.Properties["description"] != "" {
+		annotations[AnnotationDescription] = meta.Properties["description"]
+	}
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        *configDesc,
+		Layers:        []Descriptor{*layer},
+		Annotations:   annotations,
+	}
+
+	manifestDesc, err := writeJSONBlob(blobsDir, MediaTypeImageManifest, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing image manifest: %w", err)
+	}
+
+	manifestDesc.Platform = platform
+	manifestDesc.Annotations = map[string]string{AnnotationRefName: tag}
+
+	index := Index{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		Manifests:     []Descriptor{*manifestDesc},
+	}
+
+	err = writeJSONFile(filepath.Join(dir, "index.json"), index)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeJSONFile(filepath.Join(dir, "oci-layout"), map[string]string{"imageLayoutVersion": "1.0.0"})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifestDesc, nil
+}
+
+// WriteArchive writes the OCI image layout in dir to w as a tarball.
+func WriteArchive(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = filepath.ToSlash(name)
+		hdr.Uid = 0
+		hdr.Gid = 0
+		hdr.Uname = ""
+		hdr.Gname = ""
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// writeLayer writes the content of the rootfs directory of the LXD image tarball read from r as a gzip compressed
+// layer blob. It returns the descriptor of the layer, its uncompressed digest and the metadata of the image.
+func writeLayer(r io.Reader, blobsDir string) (*Descriptor, string, *api.ImageMetadata, error) {
+	f, err := os.CreateTemp(blobsDir, "layer_")
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	compressedHash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(f, compressedHash)}
+	gw := gzip.NewWriter(counter)
+	diffHash := sha256.New()
+	tw := tar.NewWriter(io.MultiWriter(gw, diffHash))
+
+	var meta *api.ImageMetadata
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, "", nil, err
+		}
+
+		name := strings.TrimPrefix(hdr.Name, "./")
+
+		if name == "metadata.yaml" {
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, "", nil, err
+			}
+
+			meta = &api.ImageMetadata{}
+			err = yaml.Unmarshal(content, meta)
+			if err != nil {
+				return nil, "", nil, fmt.Errorf("Failed parsing metadata.yaml: %w", err)
+			}
+
+			continue
+		}
+
+		// Only keep the content of the root filesystem, skipping the directory itself.
+		name, ok := strings.CutPrefix(name, "rootfs/")
+		if !ok || strings.TrimSuffix(name, "/") == "" {
+			continue
+		}
+
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname = strings.TrimPrefix(strings.TrimPrefix(hdr.Linkname, "./"), "rootfs/")
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = gw.Close()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	digest := hex.EncodeToString(compressedHash.Sum(nil))
+	err = os.Rename(f.Name(), filepath.Join(blobsDir, digest))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	layer := &Descriptor{
+		MediaType: MediaTypeImageLayerGzip,
+		Digest:    "sha256:" + digest,
+		Size:      counter.n,
+	}
+
+	return layer, digestString(diffHash), meta, nil
+}
+
+// writeJSONBlob writes the JSON encoding of v as a blob and returns its descriptor.
+func writeJSONBlob(blobsDir string, mediaType string, v any) (*Descriptor, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+
+	err = os.WriteFile(filepath.Join(blobsDir, digest), content, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Descriptor{MediaType: mediaType, Digest: "sha256:" + digest, Size: int64(len(content))}, nil
+}
+
+// writeJSONFile writes the JSON encoding of v to path.
+func writeJSONFile(path string, v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0600)
+}
+
+// digestString returns the OCI digest of the content hashed by h.
+func digestString(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes p to the underlying writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
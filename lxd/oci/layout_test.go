@@ -0,0 +1,144 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lxdImageTarball returns an LXD image tarball with the given files, keyed by path.
+func lxdImageTarball(t *testing.T, files map[string]string) io.Reader {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, name := range []string{"metadata.yaml", "rootfs", "rootfs/etc", "rootfs/etc/hostname", "templates", "templates/hostname.tpl"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(content, "/") {
+			hdr = &tar.Header{Name: name, Mode: 0755, Typeflag: tar.TypeDir}
+		}
+
+		require.NoError(t, tw.WriteHeader(hdr))
+
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, tw.Close())
+
+	return &buf
+}
+
+// readBlob reads the blob of the descriptor from the layout and checks its digest and size.
+func readBlob(t *testing.T, dir string, desc Descriptor) []byte {
+	digest, ok := strings.CutPrefix(desc.Digest, "sha256:")
+	require.True(t, ok)
+
+	content, err := os.ReadFile(filepath.Join(dir, "blobs", "sha256", digest))
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(content)
+	assert.Equal(t, digest, hex.EncodeToString(sum[:]))
+	assert.Equal(t, desc.Size, int64(len(content)))
+
+	return content
+}
+
+func TestWriteLayout(t *testing.T) {
+	dir := t.TempDir()
+
+	r := lxdImageTarball(t, map[string]string{
+		"metadata.yaml":          "architecture: aarch64\ncreation_date: 1700000000\nproperties:\n  description: Ubuntu noble\n  os: ubuntu\n",
+		"rootfs":                 "/",
+		"rootfs/etc":             "/",
+		"rootfs/etc/hostname":    "c1\n",
+		"templates":              "/",
+		"templates/hostname.tpl": "{{ container.name }}\n",
+	})
+
+	desc, err := WriteLayout(r, dir, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, MediaTypeImageManifest, desc.MediaType)
+	assert.Equal(t, &Platform{Architecture: "arm64", OS: "linux"}, desc.Platform)
+
+	// Check the layout.
+	layout, err := os.ReadFile(filepath.Join(dir, "oci-layout"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imageLayoutVersion": "1.0.0"}`, string(layout))
+
+	content, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	require.NoError(t, err)
+
+	index := Index{}
+	require.NoError(t, json.Unmarshal(content, &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "latest", index.Manifests[0].Annotations[AnnotationRefName])
+
+	manifest := Manifest{}
+	require.NoError(t, json.Unmarshal(readBlob(t, dir, index.Manifests[0]), &manifest))
+	assert.Equal(t, "Ubuntu noble", manifest.Annotations[AnnotationDescription])
+	require.Len(t, manifest.Layers, 1)
+
+	config := ImageConfig{}
+	require.NoError(t, json.Unmarshal(readBlob(t, dir, manifest.Config), &config))
+	assert.Equal(t, "arm64", config.Architecture)
+	assert.Equal(t, "ubuntu", config.Config.Labels["os"])
+	assert.Equal(t, int64(1700000000), config.Created.Unix())
+
+	// Check the layer only holds the root filesystem.
+	gr, err := gzip.NewReader(bytes.NewReader(readBlob(t, dir, manifest.Layers[0])))
+	require.NoError(t, err)
+
+	layer, err := io.ReadAll(gr)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(layer)
+	assert.Equal(t, []string{"sha256:" + hex.EncodeToString(sum[:])}, config.RootFS.DiffIDs)
+
+	names := []string{}
+	tr := tar.NewReader(bytes.NewReader(layer))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+
+	assert.Equal(t, []string{"etc", "etc/hostname"}, names)
+
+	// Check no temporary files are left behind.
+	blobs, err := os.ReadDir(filepath.Join(dir, "blobs", "sha256"))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 3)
+}
+
+func TestWriteLayoutNoMetadata(t *testing.T) {
+	r := lxdImageTarball(t, map[string]string{
+		"rootfs":              "/",
+		"rootfs/etc":          "/",
+		"rootfs/etc/hostname": "c1\n",
+	})
+
+	_, err := WriteLayout(r, t.TempDir(), "latest")
+	assert.ErrorContains(t, err, "metadata.yaml")
+}
@@ -401,6 +401,21 @@ func operationCancel(ctx context.Context, s *state.State, projectName string, op
 //      name: all-projects
 //      description: Retrieve operations from all projects
 //      type: boolean
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -457,6 +472,26 @@ func operationCancel(ctx context.Context, s *state.State, projectName string, op
 //	    name: all-projects
 //	    description: Retrieve operations from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return
+//	    type: integer
+//	    example: 100
+//	  - in: query
+//	    name: offset
+//	    description: Number of entries to skip
+//	    type: integer
+//	    example: 200
+//	  - in: query
+//	    name: sort
+//	    description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//	    type: string
+//	    example: -created_at
+//	  - in: query
+//	    name: fields
+//	    description: Comma-separated list of fields to return
+//	    type: string
+//	    example: id,status
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -498,6 +533,11 @@ func operationsGet(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(fmt.Errorf("Failed to get operation permission checker: %w", err))
 	}
 
+	pagination, err := request.PaginationParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	localOperationURLs := func() (shared.Jmap, error) {
 		// Get all the operations.
 		localOps := operations.Clone()
@@ -605,6 +645,11 @@ func operationsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If not clustered, then just return local operations.
 	if !s.ServerClustered {
+		md, err = paginateOperations(md, pagination)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		return response.SyncResponse(true, md)
 	}
 
@@ -705,9 +750,54 @@ func operationsGet(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	md, err = paginateOperations(md, pagination)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	return response.SyncResponse(true, md)
 }
 
+// operationSortFields are the fields operations can be sorted by.
+var operationSortFields = []request.SortField[*api.Operation]{
+	{Name: "created_at", Compare: func(a *api.Operation, b *api.Operation) int { return a.CreatedAt.Compare(b.CreatedAt) }},
+	{Name: "updated_at", Compare: func(a *api.Operation, b *api.Operation) int { return a.UpdatedAt.Compare(b.UpdatedAt) }},
+	{Name: "description", Compare: func(a *api.Operation, b *api.Operation) int { return strings.Compare(a.Description, b.Description) }},
+	{Name: "id", Compare: func(a *api.Operation, b *api.Operation) int { return strings.Compare(a.ID, b.ID) }},
+}
+
+// operationURLSortFields are the fields operation URLs can be sorted by.
+var operationURLSortFields = []request.SortField[string]{
+	{Name: "id", Compare: strings.Compare},
+}
+
+// paginateOperations sorts the operations of each status and selects their requested page and fields.
+func paginateOperations(md shared.Jmap, pagination *request.Pagination) (shared.Jmap, error) {
+	for status, entries := range md {
+		switch entries := entries.(type) {
+		case []*api.Operation:
+			ops, err := request.Paginate(pagination, entries, operationSortFields)
+			if err != nil {
+				return nil, err
+			}
+
+			md[status], err = pagination.SelectFields(ops)
+			if err != nil {
+				return nil, err
+			}
+		case []string:
+			urls, err := request.Paginate(pagination, entries, operationURLSortFields)
+			if err != nil {
+				return nil, err
+			}
+
+			md[status] = urls
+		}
+	}
+
+	return md, nil
+}
+
 // operationsGetByType gets all operations for a project and type.
 func operationsGetByType(ctx context.Context, s *state.State, projectName string, opType operationtype.Type) ([]*api.Operation, error) {
 	ops := make([]*api.Operation, 0)
@@ -1261,9 +1351,13 @@ func operationWaitHandler(d *Daemon, r *http.Request) response.Response {
 	}
 
 	run := func(op *operations.Operation) error {
-		// Just sleep for the duration.
-		time.Sleep(duration)
-		return nil
+		// Just sleep for the duration, stopping early if the operation is cancelled.
+		select {
+		case <-time.After(duration):
+			return nil
+		case <-op.Context().Done():
+			return op.Checkpoint()
+		}
 	}
 
 	var onConnect func(op *operations.Operation, r *http.Request, w http.ResponseWriter) error
@@ -1274,7 +1368,8 @@ func operationWaitHandler(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	op, err := operations.OperationCreate(r.Context(), d.State(), "", req.OpClass, req.OpType, resources, nil, run, nil, onConnect)
+	// The operation only belongs to a project if one is given, so that it's subject to the project's limits.
+	op, err := operations.OperationCreate(r.Context(), d.State(), request.QueryParam(r, "project"), req.OpClass, req.OpType, resources, nil, run, nil, onConnect)
 	if err != nil {
 		return response.InternalError(err)
 	}
@@ -0,0 +1,109 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrCancelled is returned by the cancellation checkpoints of an operation whose cancellation was requested.
+var ErrCancelled = errors.New("Operation cancelled")
+
+// Context returns a context which is cancelled once the cancellation of the operation is requested or the operation
+// has finished. It returns a background context if op is nil.
+func (op *Operation) Context() context.Context {
+	if op == nil || op.ctx == nil {
+		return context.Background()
+	}
+
+	return op.ctx
+}
+
+// Checkpoint returns ErrCancelled if the cancellation of the operation was requested, in which case the caller
+// should stop and revert its changes. It always returns nil if op is nil.
+func (op *Operation) Checkpoint() error {
+	if op == nil || op.ctx == nil {
+		return nil
+	}
+
+	if op.ctx.Err() != nil {
+		return ErrCancelled
+	}
+
+	return nil
+}
+
+// checkpointReader is a reader which stops at the cancellation of its operation.
+type checkpointReader struct {
+	io.Reader
+	op *Operation
+}
+
+func (r *checkpointReader) Read(p []byte) (int, error) {
+	err := r.op.Checkpoint()
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Reader.Read(p)
+}
+
+// checkpointReadSeeker is a read seeker which stops at the cancellation of its operation.
+type checkpointReadSeeker struct {
+	io.ReadSeeker
+	op *Operation
+}
+
+func (r *checkpointReadSeeker) Read(p []byte) (int, error) {
+	err := r.op.Checkpoint()
+	if err != nil {
+		return 0, err
+	}
+
+	return r.ReadSeeker.Read(p)
+}
+
+// checkpointWriter is a writer which stops at the cancellation of its operation.
+type checkpointWriter struct {
+	io.Writer
+	op *Operation
+}
+
+func (w *checkpointWriter) Write(p []byte) (int, error) {
+	err := w.op.Checkpoint()
+	if err != nil {
+		return 0, err
+	}
+
+	return w.Writer.Write(p)
+}
+
+// CheckpointReader returns a reader which fails with ErrCancelled once the cancellation of the operation is
+// requested. The reader is returned unchanged if op is nil.
+func (op *Operation) CheckpointReader(r io.Reader) io.Reader {
+	if op == nil {
+		return r
+	}
+
+	return &checkpointReader{Reader: r, op: op}
+}
+
+// CheckpointReadSeeker returns a read seeker which fails with ErrCancelled once the cancellation of the operation
+// is requested. The read seeker is returned unchanged if op is nil.
+func (op *Operation) CheckpointReadSeeker(r io.ReadSeeker) io.ReadSeeker {
+	if op == nil {
+		return r
+	}
+
+	return &checkpointReadSeeker{ReadSeeker: r, op: op}
+}
+
+// CheckpointWriter returns a writer which fails with ErrCancelled once the cancellation of the operation is
+// requested. The writer is returned unchanged if op is nil.
+func (op *Operation) CheckpointWriter(w io.Writer) io.Writer {
+	if op == nil {
+		return w
+	}
+
+	return &checkpointWriter{Writer: w, op: op}
+}
@@ -0,0 +1,64 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	var nilOp *Operation
+	assert.NoError(t, nilOp.Checkpoint())
+	assert.NoError(t, nilOp.Context().Err())
+
+	op := &Operation{}
+	op.ctx, op.ctxCancel = context.WithCancel(context.Background())
+
+	reader := op.CheckpointReader(strings.NewReader("abcdef"))
+	readSeeker := op.CheckpointReadSeeker(strings.NewReader("abcdef"))
+	var buf bytes.Buffer
+	writer := op.CheckpointWriter(&buf)
+
+	// The operation runs normally until its cancellation is requested.
+	assert.NoError(t, op.Checkpoint())
+
+	p := make([]byte, 3)
+	n, err := reader.Read(p)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(p[:n]))
+
+	n, err = readSeeker.Read(p)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(p[:n]))
+
+	_, err = writer.Write([]byte("abc"))
+	require.NoError(t, err)
+
+	op.ctxCancel()
+
+	assert.ErrorIs(t, op.Checkpoint(), ErrCancelled)
+	assert.Error(t, op.Context().Err())
+
+	_, err = reader.Read(p)
+	assert.ErrorIs(t, err, ErrCancelled)
+
+	_, err = readSeeker.Read(p)
+	assert.ErrorIs(t, err, ErrCancelled)
+
+	// Seeking doesn't transfer any data.
+	_, err = readSeeker.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+
+	_, err = writer.Write([]byte("def"))
+	assert.ErrorIs(t, err, ErrCancelled)
+	assert.Equal(t, "abc", buf.String())
+
+	// Nil operations leave the readers and writers unchanged.
+	r := strings.NewReader("abc")
+	assert.Same(t, r, nilOp.CheckpointReader(r))
+}
@@ -5,6 +5,7 @@ package operations
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
@@ -55,6 +56,54 @@ func removeDBOperation(op *Operation) error {
 	return err
 }
 
+// projectOperationLimits returns the maximum numbers of running and queued task operations of the operation's
+// project on this member. A zero concurrent value means that there is no limit, and a negative queued value means that
+// the queue isn't limited.
+func projectOperationLimits(op *Operation) (concurrent int, queued int, err error) {
+	if op.state == nil {
+		return 0, -1, nil
+	}
+
+	var config map[string]string
+	err = op.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		config, err = cluster.GetProjectConfig(ctx, tx.Tx(), op.projectName)
+		return err
+	})
+	if err != nil {
+		return 0, -1, fmt.Errorf("Failed loading operation limits of project %q: %w", op.projectName, err)
+	}
+
+	queued = -1
+	if config["limits.operations.queued"] != "" {
+		queued, err = strconv.Atoi(config["limits.operations.queued"])
+		if err != nil {
+			return 0, -1, fmt.Errorf("Invalid \"limits.operations.queued\" in project %q: %w", op.projectName, err)
+		}
+	}
+
+	if config["limits.operations.concurrent"] != "" {
+		concurrent, err = strconv.Atoi(config["limits.operations.concurrent"])
+		if err != nil {
+			return 0, -1, fmt.Errorf("Invalid \"limits.operations.concurrent\" in project %q: %w", op.projectName, err)
+		}
+	}
+
+	return concurrent, queued, nil
+}
+
+// serverOperationLimits returns the maximum numbers of running operations of the category on this member and of
+// operations of each project waiting for them. A zero concurrent value means that there is no limit, and a negative
+// queued value means that the queue isn't limited.
+func serverOperationLimits(op *Operation, category string) (concurrent int, queued int, err error) {
+	if op.state == nil || op.state.GlobalConfig == nil {
+		return 0, -1, nil
+	}
+
+	maxConcurrent, maxQueued := op.state.GlobalConfig.OperationLimits(category)
+
+	return int(maxConcurrent), int(maxQueued), nil
+}
+
 func (op *Operation) sendEvent(eventMessage any) {
 	if op.events == nil {
 		return
@@ -25,6 +25,14 @@ func removeDBOperation(op *Operation) error {
 	return nil
 }
 
+func projectOperationLimits(op *Operation) (concurrent int, queued int, err error) {
+	return 0, -1, nil
+}
+
+func serverOperationLimits(op *Operation, category string) (concurrent int, queued int, err error) {
+	return 0, -1, nil
+}
+
 func (op *Operation) sendEvent(eventMessage any) {
 	if op.events == nil {
 		return
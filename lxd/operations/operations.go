@@ -10,13 +10,16 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/events"
+	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/tracing"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/cancel"
@@ -110,6 +113,7 @@ type Operation struct {
 	dbOpType    operationtype.Type
 	requestor   *request.Requestor
 	logger      logger.Logger
+	traceCtx    context.Context
 
 	// Those functions are called at various points in the Operation lifecycle
 	onRun     func(*Operation) error
@@ -120,9 +124,28 @@ type Operation struct {
 	// Indicates if operation has finished.
 	finished cancel.Canceller
 
+	// Context of the run hook, cancelled once the cancellation of the operation is requested.
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	// Indicates if the run hook is in progress, in which case it settles the status of a cancelled operation
+	// and reports it on chanCancel once it returns.
+	running    bool
+	chanCancel chan error
+
+	// Indicates if operation holds a running slot of its project's operation queue.
+	projectSlot bool
+
+	// Category of expensive operations whose running slot the operation holds, if any.
+	category string
+
 	// Locking for concurent access to the Operation
 	lock sync.Mutex
 
+	// Structured progress of the operation, see UpdateProgress.
+	progress     *api.OperationProgress
+	progressLock sync.Mutex
+
 	state  *state.State
 	events *events.Server
 }
@@ -149,8 +172,10 @@ func OperationCreate(ctx context.Context, s *state.State, projectName string, op
 	op.url = "/" + version.APIVersion + "/operations/" + op.id
 	op.resources = opResources
 	op.finished = cancel.New()
+	op.ctx, op.ctxCancel = context.WithCancel(context.Background())
 	op.state = s
 	op.logger = logger.AddContext(logger.Ctx{"operation": op.id, "project": op.projectName, "class": op.class.String(), "description": op.description})
+	op.traceCtx = tracing.Detach(ctx)
 
 	if s != nil {
 		op.SetEventServer(s.Events)
@@ -218,6 +243,9 @@ func (op *Operation) SetEventServer(events *events.Server) {
 // SetRequestor sets a requestor for this operation from an http.Request.
 func (op *Operation) SetRequestor(ctx context.Context) {
 	op.requestor, _ = request.GetRequestor(ctx)
+	if op.requestor != nil {
+		op.logger = op.logger.AddContext(logger.Ctx{"trace": op.requestor.TraceID()})
+	}
 }
 
 // CheckRequestor checks that the requestor of a given HTTP request is equal to the requestor of the operation.
@@ -244,6 +272,23 @@ func (op *Operation) SetOnDone(f func(*Operation)) {
 	op.onDone = f
 }
 
+// TraceContext returns a context holding the span of the operation, so that the spans started while running the
+// operation are its children. It returns an empty context if op is nil.
+func (op *Operation) TraceContext() context.Context {
+	if op == nil {
+		return context.Background()
+	}
+
+	op.lock.Lock()
+	defer op.lock.Unlock()
+
+	if op.traceCtx == nil {
+		return context.Background()
+	}
+
+	return op.traceCtx
+}
+
 // Requestor returns the initial requestor for this operation.
 func (op *Operation) Requestor() *request.Requestor {
 	return op.requestor
@@ -274,8 +319,16 @@ func (op *Operation) done() {
 	op.onCancel = nil
 	op.onConnect = nil
 	op.finished.Cancel()
+	op.ctxCancel()
 	op.lock.Unlock()
 
+	op.releaseSlots()
+
+	// Token operations only wait for their token to be used.
+	if op.class != OperationClassToken {
+		metrics.TrackCompletedOperation(op.entityType, op.projectName, time.Since(op.createdAt))
+	}
+
 	go func() {
 		shutdownCtx := context.Background()
 		if op.state != nil {
@@ -313,6 +366,9 @@ func (op *Operation) done() {
 }
 
 // Start a pending operation. It returns an error if the operation cannot be started.
+// Task operations requested through the API are subject to the operation limits of their project, and remain
+// pending until the project has fewer running operations than its limit. Expensive task operations are then subject
+// to the server-wide limit of their category.
 func (op *Operation) Start() error {
 	op.lock.Lock()
 	if op.status != api.Pending {
@@ -320,11 +376,165 @@ func (op *Operation) Start() error {
 		return errors.New("Only pending operations can be started")
 	}
 
+	op.lock.Unlock()
+
+	if op.class != OperationClassTask || op.onRun == nil || op.requestor == nil || op.projectName == "" {
+		return op.run()
+	}
+
+	concurrent, queued, err := projectOperationLimits(op)
+	if err != nil {
+		return err
+	}
+
+	if concurrent <= 0 {
+		return op.admit()
+	}
+
+	run, err := enqueueOperation(op, concurrent, queued)
+	if err != nil {
+		op.failQueued(err)
+		return err
+	}
+
+	if !run {
+		op.logger.Debug("Queued operation")
+		return nil
+	}
+
+	op.lock.Lock()
+	op.projectSlot = true
+	op.lock.Unlock()
+
+	return op.admit()
+}
+
+// admit runs a pending task operation once the server-wide limit of its category allows it, queueing it otherwise.
+func (op *Operation) admit() error {
+	category := operationCategory(op)
+	if category == "" {
+		return op.runOrRelease()
+	}
+
+	concurrent, queued, err := serverOperationLimits(op, category)
+	if err != nil {
+		op.failQueued(err)
+		return err
+	}
+
+	if concurrent <= 0 {
+		return op.runOrRelease()
+	}
+
+	run, err := enqueueCategoryOperation(op, category, concurrent, queued)
+	if err != nil {
+		op.failQueued(err)
+		return err
+	}
+
+	if !run {
+		op.logger.Debug("Queued operation", logger.Ctx{"category": category})
+		return nil
+	}
+
+	op.lock.Lock()
+	op.category = category
+	op.lock.Unlock()
+
+	return op.runOrRelease()
+}
+
+// runOrRelease runs a pending operation, releasing the queue slots it holds if it can't be started.
+func (op *Operation) runOrRelease() error {
+	err := op.run()
+	if err != nil {
+		op.releaseSlots()
+		return err
+	}
+
+	return nil
+}
+
+// failQueued fails a pending operation which couldn't be queued.
+func (op *Operation) failQueued(err error) {
+	op.lock.Lock()
+	op.status = api.Failure
+	op.err = err
+	op.lock.Unlock()
+	op.done()
+
+	_, md, _ := op.Render()
+
+	op.lock.Lock()
+	op.sendEvent(md)
+	op.lock.Unlock()
+}
+
+// releaseSlots frees the running slots held by the operation in the queues of its project and category.
+func (op *Operation) releaseSlots() {
+	op.lock.Lock()
+	projectSlot := op.projectSlot
+	category := op.category
+	op.projectSlot = false
+	op.category = ""
+	op.lock.Unlock()
+
+	if category != "" {
+		releaseCategoryOperation(op, category)
+	}
+
+	if projectSlot {
+		releaseOperation(op)
+	}
+}
+
+// run runs a pending operation.
+func (op *Operation) run() error {
+	op.lock.Lock()
+	if op.status != api.Pending {
+		op.lock.Unlock()
+		return errors.New("Only pending operations can be started")
+	}
+
 	op.status = api.Running
 
 	if op.onRun != nil {
+		// The span of the operation is a child of the span of the request which created it.
+		ctx, span := tracing.Start(op.traceCtx, op.description, attribute.String("lxd.operation.id", op.id), attribute.String("lxd.operation.class", op.class.String()))
+		op.traceCtx = ctx
+		op.running = true
+
 		go func(op *Operation) {
 			err := op.onRun(op)
+			tracing.End(span, err)
+
+			op.lock.Lock()
+			op.running = false
+			cancelling := op.status == api.Cancelling
+			chanCancel := op.chanCancel
+			op.lock.Unlock()
+
+			if cancelling && err != nil {
+				// The run hook stopped following the request to cancel the operation.
+				op.lock.Lock()
+				op.status = api.Cancelled
+				op.lock.Unlock()
+				op.done()
+
+				if chanCancel != nil {
+					chanCancel <- nil
+				}
+
+				op.logger.Debug("Cancelled operation", logger.Ctx{"err": err})
+				_, md, _ := op.Render()
+
+				op.lock.Lock()
+				op.sendEvent(md)
+				op.lock.Unlock()
+
+				return
+			}
+
 			if err != nil {
 				op.lock.Lock()
 				op.status = api.Failure
@@ -347,6 +557,10 @@ func (op *Operation) Start() error {
 			op.lock.Unlock()
 			op.done()
 
+			if chanCancel != nil {
+				chanCancel <- errors.New("Operation completed before it could be cancelled")
+			}
+
 			op.logger.Debug("Success for operation")
 			_, md, _ := op.Render()
 
@@ -369,9 +583,28 @@ func (op *Operation) Start() error {
 }
 
 // Cancel cancels a running operation. If the operation cannot be cancelled, it
-// returns an error.
+// returns an error. The returned channel receives the outcome of the cancellation
+// once the run hook of the operation, if still in progress, has returned.
 func (op *Operation) Cancel() (chan error, error) {
 	op.lock.Lock()
+	if op.status == api.Pending && dequeueOperation(op) {
+		op.status = api.Cancelled
+		op.lock.Unlock()
+		op.done()
+
+		chanCancel := make(chan error, 1)
+		chanCancel <- nil
+
+		op.logger.Debug("Cancelled queued operation")
+		_, md, _ := op.Render()
+
+		op.lock.Lock()
+		op.sendEvent(md)
+		op.lock.Unlock()
+
+		return chanCancel, nil
+	}
+
 	if op.status != api.Running {
 		op.lock.Unlock()
 		return nil, errors.New("Only running operations can be cancelled")
@@ -409,18 +642,7 @@ func (op *Operation) Cancel() (chan error, error) {
 				return
 			}
 
-			op.lock.Lock()
-			op.status = api.Cancelled
-			op.lock.Unlock()
-			op.done()
-			chanCancel <- nil
-
-			op.logger.Debug("Cancelled operation")
-			_, md, _ := op.Render()
-
-			op.lock.Lock()
-			op.sendEvent(md)
-			op.lock.Unlock()
+			op.requestCancel(chanCancel)
 		}(op, oldStatus, chanCancel)
 	}
 
@@ -428,7 +650,7 @@ func (op *Operation) Cancel() (chan error, error) {
 	_, md, _ := op.Render()
 	op.sendEvent(md)
 
-	if op.canceler != nil {
+	if op.canceler != nil && op.canceler.Cancelable() {
 		err := op.canceler.Cancel()
 		if err != nil {
 			return nil, err
@@ -436,21 +658,53 @@ func (op *Operation) Cancel() (chan error, error) {
 	}
 
 	if !hasOnCancel {
-		op.lock.Lock()
-		op.status = api.Cancelled
+		op.requestCancel(chanCancel)
+	}
+
+	return chanCancel, nil
+}
+
+// requestCancel cancels the context of the run hook, which then stops at its next cancellation checkpoint and
+// reverts its changes. If the run hook isn't in progress, the operation is cancelled straight away.
+func (op *Operation) requestCancel(chanCancel chan error) {
+	op.lock.Lock()
+	op.ctxCancel()
+
+	if op.running {
+		// The run hook reports the outcome of the cancellation once it returns.
+		op.chanCancel = chanCancel
 		op.lock.Unlock()
-		op.done()
-		chanCancel <- nil
+
+		op.logger.Debug("Requested cancellation of operation")
+
+		return
+	}
+
+	if op.status != api.Cancelling {
+		// The run hook returned in the meantime and already settled the status of the operation.
+		status := op.status
+		op.lock.Unlock()
+
+		if status == api.Success {
+			chanCancel <- errors.New("Operation completed before it could be cancelled")
+		} else {
+			chanCancel <- nil
+		}
+
+		return
 	}
 
+	op.status = api.Cancelled
+	op.lock.Unlock()
+	op.done()
+	chanCancel <- nil
+
 	op.logger.Debug("Cancelled operation")
-	_, md, _ = op.Render()
+	_, md, _ := op.Render()
 
 	op.lock.Lock()
 	op.sendEvent(md)
 	op.lock.Unlock()
-
-	return chanCancel, nil
 }
 
 // Connect connects a websocket operation. If the operation is not a websocket
@@ -502,6 +756,11 @@ func (op *Operation) mayCancel() bool {
 		return true
 	}
 
+	// The run hook of these operations stops at its cancellation checkpoints.
+	if op.onRun != nil && op.dbOpType.Cancellable() {
+		return true
+	}
+
 	return false
 }
 
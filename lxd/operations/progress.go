@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"slices"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/ioprogress"
+)
+
+// RecordProgress records the progress of a task of the operation and returns the structured progress of the
+// operation, to be stored in its metadata under api.OperationProgressMetadataKey.
+// Tasks are identified by their stage and name, the name being empty for stages made of a single task.
+// The overall progress covers the tasks of the given stage, which becomes the current stage.
+func (op *Operation) RecordProgress(stage string, data ioprogress.ProgressData) api.OperationProgress {
+	op.progressLock.Lock()
+	defer op.progressLock.Unlock()
+
+	if op.progress == nil {
+		op.progress = &api.OperationProgress{}
+	}
+
+	task := api.OperationProgressTask{
+		Name:       data.Name,
+		Stage:      stage,
+		BytesDone:  data.TransferredBytes,
+		BytesTotal: data.TotalBytes,
+		Percent:    int64(data.Percentage),
+		Rate:       data.Speed,
+		ETA:        -1,
+	}
+
+	if task.BytesTotal > 0 {
+		task.Percent = min(task.BytesDone*100/task.BytesTotal, 100)
+
+		if task.Rate > 0 {
+			task.ETA = max(task.BytesTotal-task.BytesDone, 0) / task.Rate
+		}
+	}
+
+	i := slices.IndexFunc(op.progress.Tasks, func(t api.OperationProgressTask) bool {
+		return t.Stage == stage && t.Name == data.Name
+	})
+
+	if i >= 0 {
+		op.progress.Tasks[i] = task
+	} else {
+		op.progress.Tasks = append(op.progress.Tasks, task)
+	}
+
+	op.progress.Stage = stage
+	op.progress.Rate = task.Rate
+	op.progress.BytesDone = 0
+	op.progress.BytesTotal = 0
+
+	// The totals are only known if all the tasks of the stage know theirs.
+	totalKnown := true
+	stageTasks := 0
+	for _, t := range op.progress.Tasks {
+		if t.Stage != stage {
+			continue
+		}
+
+		stageTasks++
+		op.progress.BytesDone += t.BytesDone
+		op.progress.BytesTotal += t.BytesTotal
+		totalKnown = totalKnown && t.BytesTotal > 0
+	}
+
+	if !totalKnown {
+		op.progress.BytesTotal = 0
+	}
+
+	switch {
+	case op.progress.BytesTotal > 0:
+		op.progress.Percent = min(op.progress.BytesDone*100/op.progress.BytesTotal, 100)
+	case stageTasks == 1:
+		op.progress.Percent = task.Percent
+	default:
+		op.progress.Percent = 0
+	}
+
+	op.progress.ETA = -1
+	if op.progress.BytesTotal > 0 && op.progress.Rate > 0 {
+		op.progress.ETA = max(op.progress.BytesTotal-op.progress.BytesDone, 0) / op.progress.Rate
+	}
+
+	progress := *op.progress
+	progress.Tasks = slices.Clone(op.progress.Tasks)
+
+	return progress
+}
@@ -0,0 +1,52 @@
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/ioprogress"
+)
+
+func TestOperation_RecordProgress(t *testing.T) {
+	op := &Operation{}
+
+	// A single task with a known total.
+	progress := op.RecordProgress("download", ioprogress.ProgressData{TransferredBytes: 25, TotalBytes: 100, Speed: 5})
+	assert.Equal(t, "download", progress.Stage)
+	assert.Equal(t, int64(25), progress.BytesDone)
+	assert.Equal(t, int64(100), progress.BytesTotal)
+	assert.Equal(t, int64(25), progress.Percent)
+	assert.Equal(t, int64(15), progress.ETA)
+	assert.Len(t, progress.Tasks, 1)
+
+	// Updates of the same task replace it.
+	progress = op.RecordProgress("download", ioprogress.ProgressData{TransferredBytes: 50, TotalBytes: 100, Speed: 5})
+	assert.Equal(t, int64(50), progress.Percent)
+	assert.Equal(t, int64(10), progress.ETA)
+	assert.Len(t, progress.Tasks, 1)
+
+	// A new stage made of several named tasks, whose totals are summed.
+	op.RecordProgress("transfer", ioprogress.ProgressData{Name: "rootfs", TransferredBytes: 100, TotalBytes: 300, Speed: 10})
+	progress = op.RecordProgress("transfer", ioprogress.ProgressData{Name: "data", TransferredBytes: 0, TotalBytes: 100, Speed: 10})
+	assert.Equal(t, "transfer", progress.Stage)
+	assert.Equal(t, int64(100), progress.BytesDone)
+	assert.Equal(t, int64(400), progress.BytesTotal)
+	assert.Equal(t, int64(25), progress.Percent)
+	assert.Equal(t, int64(30), progress.ETA)
+	assert.Len(t, progress.Tasks, 3)
+
+	// The totals of the stage are unknown as soon as one of its tasks doesn't know its own.
+	progress = op.RecordProgress("transfer", ioprogress.ProgressData{Name: "logs", TransferredBytes: 50, Speed: 10})
+	assert.Equal(t, int64(150), progress.BytesDone)
+	assert.Equal(t, int64(0), progress.BytesTotal)
+	assert.Equal(t, int64(0), progress.Percent)
+	assert.Equal(t, int64(-1), progress.ETA)
+	assert.Len(t, progress.Tasks, 4)
+	assert.Equal(t, int64(-1), progress.Tasks[3].ETA)
+
+	// The returned progress doesn't share its tasks with the operation.
+	progress.Tasks[0].BytesDone = 0
+	progress = op.RecordProgress("transfer", ioprogress.ProgressData{Name: "logs", TransferredBytes: 60, Speed: 10})
+	assert.Equal(t, int64(50), progress.Tasks[0].BytesDone)
+}
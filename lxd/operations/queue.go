@@ -0,0 +1,310 @@
+package operations
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Categories of expensive operations whose number of running operations is limited server-wide.
+const (
+	CategoryImageImports = "image_imports"
+	CategoryBackups      = "backups"
+	CategoryMigrations   = "migrations"
+)
+
+// operationCategories associates the types of the expensive operations with their category.
+var operationCategories = map[operationtype.Type]string{
+	operationtype.ImageDownload:             CategoryImageImports,
+	operationtype.BackupCreate:              CategoryBackups,
+	operationtype.BackupRestore:             CategoryBackups,
+	operationtype.CustomVolumeBackupCreate:  CategoryBackups,
+	operationtype.CustomVolumeBackupRestore: CategoryBackups,
+	operationtype.InstanceExport:            CategoryBackups,
+	operationtype.InstanceMigrate:           CategoryMigrations,
+	operationtype.InstanceLiveMigrate:       CategoryMigrations,
+	operationtype.InstancesReplicate:        CategoryMigrations,
+	operationtype.VolumeCopy:                CategoryMigrations,
+	operationtype.VolumeMigrate:             CategoryMigrations,
+	operationtype.VolumeMove:                CategoryMigrations,
+}
+
+// projectQueue holds the number of running task operations of a project along with those waiting to run.
+type projectQueue struct {
+	concurrent int
+	running    int
+	waiting    []*Operation
+}
+
+var projectQueuesLock sync.Mutex
+var projectQueues = map[string]*projectQueue{}
+
+// categoryQueue holds the number of running operations of a category along with those waiting to run, by project.
+// The waiting operations are started in turn for each project, so that a busy project can't starve the others.
+type categoryQueue struct {
+	concurrent int
+	running    int
+	waiting    map[string][]*Operation
+	projects   []string
+}
+
+var categoryQueuesLock sync.Mutex
+var categoryQueues = map[string]*categoryQueue{}
+
+// QueueDepth is the number of operations of a project waiting in a queue.
+type QueueDepth struct {
+	// Queue is either "project" for the operation limits of the project, or the category of the operations.
+	Queue   string
+	Project string
+	Waiting int
+}
+
+// QueueDepths returns the number of operations waiting in each queue, by project.
+func QueueDepths() []QueueDepth {
+	var depths []QueueDepth
+
+	projectQueuesLock.Lock()
+	for projectName, queue := range projectQueues {
+		depths = append(depths, QueueDepth{Queue: "project", Project: projectName, Waiting: len(queue.waiting)})
+	}
+
+	projectQueuesLock.Unlock()
+
+	categoryQueuesLock.Lock()
+	for category, queue := range categoryQueues {
+		for projectName, waiting := range queue.waiting {
+			depths = append(depths, QueueDepth{Queue: category, Project: projectName, Waiting: len(waiting)})
+		}
+	}
+
+	categoryQueuesLock.Unlock()
+
+	sort.Slice(depths, func(i int, j int) bool {
+		if depths[i].Queue != depths[j].Queue {
+			return depths[i].Queue < depths[j].Queue
+		}
+
+		return depths[i].Project < depths[j].Project
+	})
+
+	return depths
+}
+
+// enqueueOperation takes a running slot of the operation's project if fewer than concurrent operations are running,
+// and otherwise queues the operation. A negative queued value means that the queue isn't limited.
+// It returns true if the operation can run straight away.
+func enqueueOperation(op *Operation, concurrent int, queued int) (bool, error) {
+	projectQueuesLock.Lock()
+	defer projectQueuesLock.Unlock()
+
+	queue, ok := projectQueues[op.projectName]
+	if !ok {
+		queue = &projectQueue{}
+		projectQueues[op.projectName] = queue
+	}
+
+	// Apply the latest limits to the operations already queued.
+	queue.concurrent = concurrent
+
+	if queue.running < queue.concurrent {
+		queue.running++
+		return true, nil
+	}
+
+	if queued >= 0 && len(queue.waiting) >= queued {
+		return false, api.StatusErrorf(http.StatusTooManyRequests, "Too many operations queued in project %q", op.projectName)
+	}
+
+	queue.waiting = append(queue.waiting, op)
+
+	return false, nil
+}
+
+// dequeueOperation removes a waiting operation from the queue of its project or category.
+// It returns false if the operation wasn't waiting.
+func dequeueOperation(op *Operation) bool {
+	projectQueuesLock.Lock()
+	queue, ok := projectQueues[op.projectName]
+	if ok {
+		i := slices.Index(queue.waiting, op)
+		if i >= 0 {
+			queue.waiting = slices.Delete(queue.waiting, i, i+1)
+			projectQueuesLock.Unlock()
+			return true
+		}
+	}
+
+	projectQueuesLock.Unlock()
+
+	categoryQueuesLock.Lock()
+	defer categoryQueuesLock.Unlock()
+
+	for _, queue := range categoryQueues {
+		waiting := queue.waiting[op.projectName]
+
+		i := slices.Index(waiting, op)
+		if i < 0 {
+			continue
+		}
+
+		waiting = slices.Delete(waiting, i, i+1)
+		if len(waiting) == 0 {
+			delete(queue.waiting, op.projectName)
+			queue.projects = slices.DeleteFunc(queue.projects, func(projectName string) bool { return projectName == op.projectName })
+		} else {
+			queue.waiting[op.projectName] = waiting
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// releaseOperation frees the running slot held by a completed operation and starts the next waiting operations
+// of its project.
+func releaseOperation(op *Operation) {
+	projectQueuesLock.Lock()
+
+	queue, ok := projectQueues[op.projectName]
+	if !ok {
+		projectQueuesLock.Unlock()
+		return
+	}
+
+	queue.running--
+
+	var next []*Operation
+	for queue.running < queue.concurrent && len(queue.waiting) > 0 {
+		next = append(next, queue.waiting[0])
+		queue.waiting = queue.waiting[1:]
+		queue.running++
+	}
+
+	if queue.running <= 0 && len(queue.waiting) == 0 {
+		delete(projectQueues, op.projectName)
+	}
+
+	projectQueuesLock.Unlock()
+
+	for _, nextOp := range next {
+		nextOp.lock.Lock()
+		nextOp.projectSlot = true
+		nextOp.lock.Unlock()
+
+		err := nextOp.admit()
+		if err != nil {
+			nextOp.logger.Warn("Failed starting queued operation", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// operationCategory returns the category of the operation if the number of running operations of its type is
+// limited server-wide. Operations requested by other cluster members are already limited where they were requested.
+// Only task operations reach this point: websocket operations, such as the source side of migrations, must run as
+// soon as they're created so that their peer can connect to them, and aren't limited.
+func operationCategory(op *Operation) string {
+	if op.requestor != nil && op.requestor.CallerProtocol() == request.ProtocolCluster {
+		return ""
+	}
+
+	return operationCategories[op.dbOpType]
+}
+
+// enqueueCategoryOperation takes a running slot of the operation's category if fewer than concurrent operations are
+// running, and otherwise queues the operation. A negative queued value means that the number of operations of each
+// project waiting in the queue isn't limited.
+// It returns true if the operation can run straight away.
+func enqueueCategoryOperation(op *Operation, category string, concurrent int, queued int) (bool, error) {
+	categoryQueuesLock.Lock()
+	defer categoryQueuesLock.Unlock()
+
+	queue, ok := categoryQueues[category]
+	if !ok {
+		queue = &categoryQueue{waiting: map[string][]*Operation{}}
+		categoryQueues[category] = queue
+	}
+
+	// Apply the latest limits to the operations already queued.
+	queue.concurrent = concurrent
+
+	if queue.running < queue.concurrent {
+		queue.running++
+		return true, nil
+	}
+
+	waiting := queue.waiting[op.projectName]
+	if queued >= 0 && len(waiting) >= queued {
+		return false, api.StatusErrorf(http.StatusTooManyRequests, "Too many %s operations queued in project %q", category, op.projectName)
+	}
+
+	if len(waiting) == 0 {
+		queue.projects = append(queue.projects, op.projectName)
+	}
+
+	queue.waiting[op.projectName] = append(waiting, op)
+
+	return false, nil
+}
+
+// next takes the operations that can start from the queue, from each project in turn, and counts them as running.
+func (q *categoryQueue) next() []*Operation {
+	var next []*Operation
+	for q.running < q.concurrent && len(q.projects) > 0 {
+		projectName := q.projects[0]
+		waiting := q.waiting[projectName]
+
+		next = append(next, waiting[0])
+		q.running++
+
+		// Move the project to the end of the line if it has more operations waiting.
+		q.projects = q.projects[1:]
+		if len(waiting) > 1 {
+			q.waiting[projectName] = waiting[1:]
+			q.projects = append(q.projects, projectName)
+		} else {
+			delete(q.waiting, projectName)
+		}
+	}
+
+	return next
+}
+
+// releaseCategoryOperation frees the running slot of the category held by a completed operation and starts the next
+// waiting operations of the category, taking them from each project in turn.
+func releaseCategoryOperation(op *Operation, category string) {
+	categoryQueuesLock.Lock()
+
+	queue, ok := categoryQueues[category]
+	if !ok {
+		categoryQueuesLock.Unlock()
+		return
+	}
+
+	queue.running--
+	next := queue.next()
+
+	if queue.running <= 0 && len(queue.projects) == 0 {
+		delete(categoryQueues, category)
+	}
+
+	categoryQueuesLock.Unlock()
+
+	for _, nextOp := range next {
+		nextOp.lock.Lock()
+		nextOp.category = category
+		nextOp.lock.Unlock()
+
+		err := nextOp.run()
+		if err != nil {
+			nextOp.logger.Warn("Failed starting queued operation", logger.Ctx{"err": err})
+			nextOp.releaseSlots()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package operations
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestEnqueueCategoryOperation(t *testing.T) {
+	t.Cleanup(func() { categoryQueues = map[string]*categoryQueue{} })
+
+	newOp := func(projectName string) *Operation {
+		return &Operation{projectName: projectName, dbOpType: operationtype.ImageDownload}
+	}
+
+	assert.Equal(t, CategoryImageImports, operationCategory(newOp("p1")))
+	assert.Empty(t, operationCategory(&Operation{dbOpType: operationtype.InstanceStart}))
+
+	// The operations run straight away until the limit is reached.
+	running := newOp("p1")
+	run, err := enqueueCategoryOperation(running, CategoryImageImports, 1, 2)
+	require.NoError(t, err)
+	assert.True(t, run)
+
+	// Further operations wait, up to the number of queued operations of each project.
+	p1a, p1b, p1c, p2a := newOp("p1"), newOp("p1"), newOp("p1"), newOp("p2")
+	for _, op := range []*Operation{p1a, p1b, p2a} {
+		run, err = enqueueCategoryOperation(op, CategoryImageImports, 1, 2)
+		require.NoError(t, err)
+		assert.False(t, run)
+	}
+
+	_, err = enqueueCategoryOperation(p1c, CategoryImageImports, 1, 2)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusTooManyRequests))
+
+	assert.Equal(t, []QueueDepth{
+		{Queue: CategoryImageImports, Project: "p1", Waiting: 2},
+		{Queue: CategoryImageImports, Project: "p2", Waiting: 1},
+	}, QueueDepths())
+
+	// The waiting operations start from each project in turn.
+	queue := categoryQueues[CategoryImageImports]
+	queue.running--
+	assert.Equal(t, []*Operation{p1a}, queue.next())
+	queue.running--
+	assert.Equal(t, []*Operation{p2a}, queue.next())
+
+	// Raising the limit starts more operations.
+	queue.concurrent = 3
+	assert.Equal(t, []*Operation{p1b}, queue.next())
+	assert.Empty(t, queue.next())
+	assert.Empty(t, QueueDepths())
+}
+
+func TestDequeueOperation(t *testing.T) {
+	t.Cleanup(func() { categoryQueues = map[string]*categoryQueue{} })
+
+	ops := []*Operation{{projectName: "p1"}, {projectName: "p1"}, {projectName: "p1"}, {projectName: "p2"}}
+	for _, op := range ops {
+		_, err := enqueueCategoryOperation(op, CategoryBackups, 1, -1)
+		require.NoError(t, err)
+	}
+
+	// Operations that aren't waiting can't be removed from the queues.
+	assert.False(t, dequeueOperation(ops[0]))
+
+	assert.True(t, dequeueOperation(ops[2]))
+	assert.True(t, dequeueOperation(ops[3]))
+	assert.False(t, dequeueOperation(ops[3]))
+
+	assert.Equal(t, []QueueDepth{{Queue: CategoryBackups, Project: "p1", Waiting: 1}}, QueueDepths())
+
+	// The projects without waiting operations are skipped.
+	queue := categoryQueues[CategoryBackups]
+	queue.running--
+	assert.Equal(t, []*Operation{ops[1]}, queue.next())
+}
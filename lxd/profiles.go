@@ -275,6 +275,11 @@ func profilesGet(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
+			ownerships, err := dbCluster.GetEntityOwnerships(ctx, tx.Tx(), entity.TypeProfile)
+			if err != nil {
+				return err
+			}
+
 			apiProfiles = make([]*api.Profile, 0, len(profiles))
 			for _, profile := range profiles {
 				if !userHasPermission(entity.ProfileURL(requestProjectName, profile.Name)) {
@@ -291,6 +296,11 @@ func profilesGet(d *Daemon, r *http.Request) response.Response {
 					return err
 				}
 
+				ownership := ownerships[profile.ID]
+				apiProfile.CreatedAt = ownership.CreatedAt
+				apiProfile.CreatedBy = ownership.CreatedBy
+				apiProfile.LastModifiedBy = ownership.LastModifiedBy
+
 				apiProfiles = append(apiProfiles, apiProfile)
 				urlToProfile[entity.ProfileURL(requestProjectName, profile.Name)] = apiProfile
 			}
@@ -541,6 +551,15 @@ func profileGet(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		ownership, err := dbCluster.GetEntityOwnership(ctx, tx.Tx(), entity.TypeProfile, profile.ID)
+		if err != nil {
+			return err
+		}
+
+		resp.CreatedAt = ownership.CreatedAt
+		resp.CreatedBy = ownership.CreatedBy
+		resp.LastModifiedBy = ownership.LastModifiedBy
+
 		return nil
 	})
 	if err != nil {
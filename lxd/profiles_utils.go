@@ -237,6 +237,7 @@ func doProfileUpdateInstance(ctx context.Context, s *state.State, args db.Instan
 		Description:  inst.Description(),
 		Devices:      inst.LocalDevices(),
 		Ephemeral:    inst.IsEphemeral(),
+		Labels:       inst.Labels(),
 		Profiles:     profiles, // Supply with new profile config.
 		Project:      inst.Project().Name,
 		Type:         inst.Type(),
@@ -0,0 +1,456 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	clusterConfig "github.com/canonical/lxd/lxd/cluster/config"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// projectParent is the project config key holding the name of the parent project.
+const projectParent string = "parent"
+
+// hierarchicalCountLimits is the list of count limits that also apply to the descendants of a project.
+// The limits affected by instance-level values are listed in allInstanceAggregateLimits.
+var hierarchicalCountLimits = []string{
+	"limits.containers",
+	"limits.instances",
+	"limits.networks",
+	"limits.virtual-machines",
+}
+
+// isHierarchicalLimit returns whether the given project limit applies to the sum of the usage of the
+// project and of its descendants.
+func isHierarchicalLimit(key string) bool {
+	return slices.Contains(hierarchicalCountLimits, key) || slices.Contains(allInstanceAggregateLimits, key) || strings.HasPrefix(key, projectLimitDiskPool)
+}
+
+// parseHierarchicalLimit parses the value of the given hierarchical limit.
+func parseHierarchicalLimit(key string, value string) (int64, error) {
+	if slices.Contains(hierarchicalCountLimits, key) {
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	keyName := key
+
+	// Handle pool-specific limits.
+	if strings.HasPrefix(key, projectLimitDiskPool) {
+		keyName = "limits.disk"
+	}
+
+	parser := aggregateLimitConfigValueParsers[keyName]
+	return parser(value)
+}
+
+// projectTree holds the parent/child relationships between all projects, along with the lazily
+// fetched information needed to compute their usage.
+type projectTree struct {
+	ctx          context.Context
+	tx           *db.ClusterTx
+	globalConfig map[string]string
+
+	configs  map[string]map[string]string
+	children map[string][]string
+	infos    map[string]*projectInfo
+}
+
+// loadProjectTree loads the hierarchy of all projects.
+func loadProjectTree(ctx context.Context, globalConfig *clusterConfig.Config, tx *db.ClusterTx) (*projectTree, error) {
+	dbProjects, err := cluster.GetProjects(ctx, tx.Tx())
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading projects: %w", err)
+	}
+
+	projectConfigs, err := cluster.GetConfig(ctx, tx.Tx(), "project")
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading project configs: %w", err)
+	}
+
+	t := &projectTree{
+		ctx:     ctx,
+		tx:      tx,
+		configs: make(map[string]map[string]string, len(dbProjects)),
+		infos:   map[string]*projectInfo{},
+	}
+
+	if globalConfig != nil {
+		t.globalConfig = globalConfig.Dump()
+	}
+
+	for _, dbProject := range dbProjects {
+		config := projectConfigs[dbProject.ID]
+		if config == nil {
+			config = map[string]string{}
+		}
+
+		t.configs[dbProject.Name] = config
+	}
+
+	t.indexChildren()
+
+	return t, nil
+}
+
+// indexChildren rebuilds the children of each project from the project configs.
+func (t *projectTree) indexChildren() {
+	t.children = map[string][]string{}
+	for name, config := range t.configs {
+		parent := config[projectParent]
+		if parent != "" {
+			t.children[parent] = append(t.children[parent], name)
+		}
+	}
+
+	for _, children := range t.children {
+		slices.Sort(children)
+	}
+}
+
+// override replaces the stored information of a project with the given pending one, so that usage
+// is computed as if the pending change was committed.
+func (t *projectTree) override(info *projectInfo) error {
+	instances, err := expandInstancesConfigAndDevices(t.globalConfig, info.Instances, info.Profiles)
+	if err != nil {
+		return err
+	}
+
+	pending := *info
+	pending.Instances = instances
+
+	t.infos[info.Project.Name] = &pending
+	t.configs[info.Project.Name] = info.Project.Config
+	t.indexChildren()
+
+	return nil
+}
+
+// ancestors returns the ancestors of the given project, starting from its parent.
+func (t *projectTree) ancestors(name string) []string {
+	ancestors := []string{}
+
+	for {
+		parent := t.configs[name][projectParent]
+		if parent == "" || parent == name || slices.Contains(ancestors, parent) {
+			return ancestors
+		}
+
+		ancestors = append(ancestors, parent)
+		name = parent
+	}
+}
+
+// info returns the information of the given project, with the instances expanded.
+func (t *projectTree) info(name string) (*projectInfo, error) {
+	info, ok := t.infos[name]
+	if ok {
+		return info, nil
+	}
+
+	info, err := fetchProject(t.ctx, t.tx, name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Instances, err = expandInstancesConfigAndDevices(t.globalConfig, info.Instances, info.Profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	t.infos[name] = info
+
+	return info, nil
+}
+
+// usage returns the usage of the given limit by the project itself, excluding its descendants.
+func (t *projectTree) usage(name string, key string, skipUnset bool) (int64, error) {
+	info, err := t.info(name)
+	if err != nil {
+		return -1, err
+	}
+
+	switch key {
+	case "limits.instances":
+		return int64(len(info.Instances)), nil
+	case "limits.containers", "limits.virtual-machines":
+		instanceType, err := instancetype.New(string(countConfigInstanceType[key]))
+		if err != nil {
+			return -1, err
+		}
+
+		count := int64(0)
+		for _, instance := range info.Instances {
+			if instance.Type == instanceType.String() {
+				count++
+			}
+		}
+
+		return count, nil
+	case "limits.networks":
+		return int64(len(info.Networks)), nil
+	}
+
+	totals, err := getTotalsAcrossProjectEntities(info, []string{key}, skipUnset)
+	if err != nil {
+		return -1, err
+	}
+
+	return totals[key], nil
+}
+
+// subtreeUsage returns the usage of the given limit by the project and all of its descendants.
+func (t *projectTree) subtreeUsage(name string, key string, skipUnset bool) (int64, error) {
+	total, err := t.usage(name, key, skipUnset)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, child := range t.children[name] {
+		usage, err := t.subtreeUsage(child, key, skipUnset)
+		if err != nil {
+			return -1, err
+		}
+
+		total += usage
+	}
+
+	return total, nil
+}
+
+// reserved returns the share of the given limit of its parent that the project holds: its own limit
+// when set, and otherwise the usage of the project and its descendants.
+func (t *projectTree) reserved(name string, key string) (int64, error) {
+	usage, err := t.subtreeUsage(name, key, false)
+	if err != nil {
+		return -1, err
+	}
+
+	value := t.configs[name][key]
+	if value == "" {
+		return usage, nil
+	}
+
+	limit, err := parseHierarchicalLimit(key, value)
+	if err != nil {
+		return -1, err
+	}
+
+	return max(limit, usage), nil
+}
+
+// allocated returns how much of the given limit of the project is used by the project itself and
+// reserved by its children, leaving out the skipChild child.
+func (t *projectTree) allocated(name string, key string, skipChild string) (int64, error) {
+	total, err := t.usage(name, key, false)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, child := range t.children[name] {
+		if child == skipChild {
+			continue
+		}
+
+		reserved, err := t.reserved(child, key)
+		if err != nil {
+			return -1, err
+		}
+
+		total += reserved
+	}
+
+	return total, nil
+}
+
+// checkSubtreeLimits checks that the usage of the project and its descendants doesn't exceed the
+// hierarchical limits of the project, when it has children, or of any of its ancestors.
+func (t *projectTree) checkSubtreeLimits(name string) error {
+	projects := t.ancestors(name)
+	if len(t.children[name]) > 0 {
+		projects = append([]string{name}, projects...)
+	}
+
+	for _, projectName := range projects {
+		for key, value := range t.configs[projectName] {
+			if value == "" || !isHierarchicalLimit(key) {
+				continue
+			}
+
+			limit, err := parseHierarchicalLimit(key, value)
+			if err != nil {
+				return err
+			}
+
+			usage, err := t.subtreeUsage(projectName, key, false)
+			if err != nil {
+				return fmt.Errorf("Failed getting usage of project %q and its child projects: %w", projectName, err)
+			}
+
+			if usage > limit {
+				return fmt.Errorf("Reached maximum aggregate value %q for %q in project %q and its child projects", value, key, projectName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkHierarchyLimits returns an error if the pending change of the given project would make it and
+// its descendants exceed the limits of the project or of one of its ancestors.
+func checkHierarchyLimits(ctx context.Context, globalConfig *clusterConfig.Config, tx *db.ClusterTx, info *projectInfo) error {
+	tree, err := loadProjectTree(ctx, globalConfig, tx)
+	if err != nil {
+		return err
+	}
+
+	if info.Project.Config[projectParent] == "" && len(tree.children[info.Project.Name]) == 0 {
+		return nil
+	}
+
+	err = tree.override(info)
+	if err != nil {
+		return err
+	}
+
+	return tree.checkSubtreeLimits(info.Project.Name)
+}
+
+// checkProjectHierarchy checks that the new config of the given project keeps the project hierarchy
+// valid: the parent must exist without creating a cycle, the limits of the project can't exceed the
+// remaining quota of its parent, and they can't go below what the project and its children hold.
+func checkProjectHierarchy(ctx context.Context, globalConfig *clusterConfig.Config, tx *db.ClusterTx, info *projectInfo, changed []string) error {
+	tree, err := loadProjectTree(ctx, globalConfig, tx)
+	if err != nil {
+		return err
+	}
+
+	name := info.Project.Name
+	parent := info.Project.Config[projectParent]
+	parentChanged := slices.Contains(changed, projectParent)
+
+	if parent == "" && len(tree.children[name]) == 0 {
+		return nil
+	}
+
+	if parent != "" && parentChanged {
+		if parent == name {
+			return fmt.Errorf("Project %q can't be its own parent", name)
+		}
+
+		_, ok := tree.configs[parent]
+		if !ok {
+			return fmt.Errorf("Parent project %q not found", parent)
+		}
+
+		if slices.Contains(tree.ancestors(parent), name) {
+			return fmt.Errorf("Project %q can't be a child of its descendant %q", name, parent)
+		}
+	}
+
+	err = tree.override(info)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range info.Project.Config {
+		if value == "" || !isHierarchicalLimit(key) {
+			continue
+		}
+
+		limit, err := parseHierarchicalLimit(key, value)
+		if err != nil {
+			return err
+		}
+
+		// Check that the limit fits in the remaining quota of the parent.
+		parentValue := tree.configs[parent][key]
+		if parent != "" && parentValue != "" && (parentChanged || slices.Contains(changed, key)) {
+			parentLimit, err := parseHierarchicalLimit(key, parentValue)
+			if err != nil {
+				return err
+			}
+
+			allocated, err := tree.allocated(parent, key, name)
+			if err != nil {
+				return fmt.Errorf("Failed getting usage of parent project %q: %w", parent, err)
+			}
+
+			if limit > parentLimit-allocated {
+				return fmt.Errorf("%q can't exceed the remaining %q quota of parent project %q (%d)", value, key, parent, max(parentLimit-allocated, 0))
+			}
+		}
+
+		// Check that the limit still covers what the project uses and its children hold.
+		if len(tree.children[name]) > 0 && slices.Contains(changed, key) {
+			allocated, err := tree.allocated(name, key, "")
+			if err != nil {
+				return fmt.Errorf("Failed getting usage of child projects: %w", err)
+			}
+
+			if limit < allocated {
+				return fmt.Errorf("%q is too low: %d is used by project %q or held by its child projects", key, allocated, name)
+			}
+		}
+	}
+
+	// A new parent also limits the current usage of the project and its descendants.
+	if parent != "" && parentChanged {
+		err = tree.checkSubtreeLimits(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addDescendantsUsage adds the usage of the descendants of the given project to its allocations.
+// The allocations are indexed by the name of the resource, such as "cpu" for "limits.cpu".
+func addDescendantsUsage(ctx context.Context, globalConfig map[string]string, tx *db.ClusterTx, projectName string, allocations map[string]api.ProjectStateResource) error {
+	tree, err := loadProjectTree(ctx, nil, tx)
+	if err != nil {
+		return err
+	}
+
+	tree.globalConfig = globalConfig
+
+	if len(tree.children[projectName]) == 0 {
+		return nil
+	}
+
+	for resource, allocation := range allocations {
+		key := "limits." + resource
+		if strings.HasPrefix(resource, "disk.") {
+			key = projectLimitDiskPool + strings.TrimPrefix(resource, "disk.")
+		}
+
+		for _, child := range tree.children[projectName] {
+			usage, err := tree.subtreeUsage(child, key, true)
+			if err != nil {
+				return err
+			}
+
+			allocation.Usage += usage
+		}
+
+		allocations[resource] = allocation
+	}
+
+	return nil
+}
+
+// ChildProjects returns the names of the direct child projects of the given project.
+func ChildProjects(ctx context.Context, tx *db.ClusterTx, projectName string) ([]string, error) {
+	tree, err := loadProjectTree(ctx, nil, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.children[projectName], nil
+}
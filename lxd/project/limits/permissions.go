@@ -121,6 +121,11 @@ func AllowInstanceCreation(ctx context.Context, globalConfig *clusterConfig.Conf
 		return fmt.Errorf("Failed checking if instance creation allowed: %w", err)
 	}
 
+	err = checkHierarchyLimits(ctx, globalConfig, tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if instance creation allowed: %w", err)
+	}
+
 	return nil
 }
 
@@ -273,8 +278,8 @@ func AllowVolumeCreation(ctx context.Context, globalConfig *clusterConfig.Config
 		return nil
 	}
 
-	// If "limits.disk" is not set, there's nothing to do.
-	if info.Project.Config["limits.disk"] == "" {
+	// If "limits.disk" is not set and there's no parent project, there's nothing to do.
+	if info.Project.Config["limits.disk"] == "" && info.Project.Config[projectParent] == "" {
 		return nil
 	}
 
@@ -290,6 +295,11 @@ func AllowVolumeCreation(ctx context.Context, globalConfig *clusterConfig.Config
 		return fmt.Errorf("Failed checking if volume creation allowed: %w", err)
 	}
 
+	err = checkHierarchyLimits(ctx, globalConfig, tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if volume creation allowed: %w", err)
+	}
+
 	return nil
 }
 
@@ -682,8 +692,8 @@ func checkInstanceRestrictions(proj api.Project, instances []api.Instance, profi
 					return nil
 				}
 
-				// Always allow the cloud-init config drive.
-				if device["path"] == "" && device["source"] == "cloud-init:config" {
+				// Always allow the cloud-init config drive and the Windows setup media.
+				if device["path"] == "" && (device["source"] == "cloud-init:config" || device["source"] == "windows:setup") {
 					return nil
 				}
 
@@ -893,7 +903,7 @@ func isContainerLowLevelOptionForbidden(key string) bool {
 		return true
 	}
 
-	if slices.Contains([]string{"boot.host_shutdown_timeout", "linux.kernel_modules", "linux.kernel_modules.load", "raw.apparmor", "raw.idmap", "raw.lxc", "raw.seccomp", "security.devlxd.images", "security.idmap.base", "security.idmap.size"}, key) {
+	if slices.Contains([]string{"boot.host_shutdown_timeout", "hooks.post_stop", "hooks.pre_start", "linux.kernel_modules", "linux.kernel_modules.load", "raw.apparmor", "raw.idmap", "raw.lxc", "raw.seccomp", "security.devlxd.images", "security.idmap.base", "security.idmap.size"}, key) {
 		return true
 	}
 
@@ -902,7 +912,7 @@ func isContainerLowLevelOptionForbidden(key string) bool {
 
 // Return true if a low-level VM option is forbidden.
 func isVMLowLevelOptionForbidden(key string) bool {
-	return slices.Contains([]string{"boot.host_shutdown_timeout", "limits.memory.hugepages", "raw.idmap", "raw.qemu"}, key)
+	return slices.Contains([]string{"boot.host_shutdown_timeout", "hooks.post_stop", "hooks.pre_start", "limits.memory.hugepages", "raw.idmap", "raw.qemu"}, key)
 }
 
 // AllowInstanceUpdate returns an error if any project-specific limit or
@@ -949,6 +959,11 @@ func AllowInstanceUpdate(ctx context.Context, globalConfig *clusterConfig.Config
 		return fmt.Errorf("Failed checking if instance update allowed: %w", err)
 	}
 
+	err = checkHierarchyLimits(ctx, globalConfig, tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if instance update allowed: %w", err)
+	}
+
 	return nil
 }
 
@@ -964,8 +979,8 @@ func AllowVolumeUpdate(ctx context.Context, globalConfig *clusterConfig.Config,
 		return nil
 	}
 
-	// If "limits.disk" is not set, there's nothing to do.
-	if info.Project.Config["limits.disk"] == "" {
+	// If "limits.disk" is not set and there's no parent project, there's nothing to do.
+	if info.Project.Config["limits.disk"] == "" && info.Project.Config[projectParent] == "" {
 		return nil
 	}
 
@@ -983,6 +998,11 @@ func AllowVolumeUpdate(ctx context.Context, globalConfig *clusterConfig.Config,
 		return fmt.Errorf("Failed checking if volume update allowed: %w", err)
 	}
 
+	err = checkHierarchyLimits(ctx, globalConfig, tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if volume update allowed: %w", err)
+	}
+
 	return nil
 }
 
@@ -1013,6 +1033,11 @@ func AllowProfileUpdate(ctx context.Context, globalConfig *clusterConfig.Config,
 		return fmt.Errorf("Failed checking if profile update allowed: %w", err)
 	}
 
+	err = checkHierarchyLimits(ctx, globalConfig, tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if profile update allowed: %w", err)
+	}
+
 	return nil
 }
 
@@ -1138,6 +1163,12 @@ func AllowProjectUpdate(ctx context.Context, globalConfig *clusterConfig.Config,
 		}
 	}
 
+	// Check the limits against the parent and child projects.
+	err = checkProjectHierarchy(ctx, globalConfig, tx, info, changed)
+	if err != nil {
+		return fmt.Errorf("Conflict detected when updating project %q: %w", projectName, err)
+	}
+
 	return nil
 }
 
@@ -1230,6 +1261,11 @@ func projectHasLimitsOrRestrictions(project api.Project) bool {
 		if k == "restricted" && shared.IsTrue(v) {
 			return true
 		}
+
+		// The limits of the ancestors also apply to the project.
+		if k == projectParent && v != "" {
+			return true
+		}
 	}
 
 	return false
@@ -1634,6 +1670,10 @@ func CheckTargetMember(p *api.Project, targetMemberName string, allMembers []db.
 				return nil, api.StatusErrorf(http.StatusForbidden, "%w", err)
 			}
 
+			if slices.Contains(potentialMember.Roles, db.ClusterRoleWitness) {
+				return nil, api.StatusErrorf(http.StatusBadRequest, "Cluster member %q is a witness and can't host instances or storage volumes", targetMemberName)
+			}
+
 			return &potentialMember, nil
 		}
 	}
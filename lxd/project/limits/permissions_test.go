@@ -159,6 +159,99 @@ func TestAllowInstanceCreation_AboveInstances(t *testing.T) {
 	assert.EqualError(t, err, `Reached maximum number of instances in project "p1"`)
 }
 
+// If the parent project has a limit and it matches the current number of
+// instances in the parent and its children, the check fails.
+func TestAllowInstanceCreation_AboveParent(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	id, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p1"})
+	require.NoError(t, err)
+
+	err = cluster.CreateProjectConfig(ctx, tx.Tx(), id, map[string]string{"limits.containers": "1"})
+	require.NoError(t, err)
+
+	id, err = cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p2"})
+	require.NoError(t, err)
+
+	err = cluster.CreateProjectConfig(ctx, tx.Tx(), id, map[string]string{"parent": "p1"})
+	require.NoError(t, err)
+
+	_, err = cluster.CreateInstance(ctx, tx.Tx(), cluster.Instance{
+		Project:      "p1",
+		Name:         "c1",
+		Type:         instancetype.Container,
+		Architecture: 1,
+		Node:         "none",
+	})
+	require.NoError(t, err)
+
+	req := api.InstancesPost{
+		Name: "c2",
+		Type: api.InstanceTypeContainer,
+	}
+
+	err = limits.AllowInstanceCreation(context.Background(), nil, tx, "p2", req)
+	assert.EqualError(t, err, `Failed checking if instance creation allowed: Reached maximum aggregate value "1" for "limits.containers" in project "p1" and its child projects`)
+}
+
+// The limits of a child project can't exceed the remaining quota of its
+// parent.
+func TestAllowProjectUpdate_AboveParentQuota(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	id, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p1"})
+	require.NoError(t, err)
+
+	err = cluster.CreateProjectConfig(ctx, tx.Tx(), id, map[string]string{"limits.instances": "3"})
+	require.NoError(t, err)
+
+	id, err = cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p2"})
+	require.NoError(t, err)
+
+	err = cluster.CreateProjectConfig(ctx, tx.Tx(), id, map[string]string{"parent": "p1"})
+	require.NoError(t, err)
+
+	_, err = cluster.CreateInstance(ctx, tx.Tx(), cluster.Instance{
+		Project:      "p1",
+		Name:         "c1",
+		Type:         instancetype.Container,
+		Architecture: 1,
+		Node:         "none",
+	})
+	require.NoError(t, err)
+
+	config := map[string]string{"parent": "p1", "limits.instances": "2"}
+	err = limits.AllowProjectUpdate(ctx, nil, tx, "p2", config, []string{"limits.instances"})
+	assert.NoError(t, err)
+
+	config["limits.instances"] = "3"
+	err = limits.AllowProjectUpdate(ctx, nil, tx, "p2", config, []string{"limits.instances"})
+	assert.EqualError(t, err, `Conflict detected when updating project "p2": "3" can't exceed the remaining "limits.instances" quota of parent project "p1" (2)`)
+}
+
+// A project can't be nested under one of its descendants.
+func TestAllowProjectUpdate_ParentCycle(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p1"})
+	require.NoError(t, err)
+
+	id, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p2"})
+	require.NoError(t, err)
+
+	err = cluster.CreateProjectConfig(ctx, tx.Tx(), id, map[string]string{"parent": "p1"})
+	require.NoError(t, err)
+
+	err = limits.AllowProjectUpdate(ctx, nil, tx, "p1", map[string]string{"parent": "p2"}, []string{"parent"})
+	assert.EqualError(t, err, `Conflict detected when updating project "p1": Project "p1" can't be a child of its descendant "p2"`)
+}
+
 // If a direct targeting is blocked, the check fails.
 func TestCheckClusterTargetRestriction_RestrictedTrue(t *testing.T) {
 	tx, cleanup := db.NewTestClusterTx(t)
@@ -289,3 +382,29 @@ func TestCheckClusterTargetRestriction_RestrictedFalse(t *testing.T) {
 	err = limits.CheckClusterTargetRestriction(req.Context(), authorizer, p, "n1")
 	assert.NoError(t, err)
 }
+
+// Witness members can't be targeted, and the other members are checked against the project restrictions.
+func TestCheckTargetMember(t *testing.T) {
+	allMembers := []db.NodeInfo{
+		{Name: "n1", Groups: []string{"default"}},
+		{Name: "n2", Groups: []string{"default"}, Roles: []db.ClusterRole{db.ClusterRoleWitness}},
+		{Name: "n3", Groups: []string{"gpu"}},
+	}
+
+	p := &api.Project{Name: "p1", Config: map[string]string{}}
+
+	member, err := limits.CheckTargetMember(p, "n1", allMembers)
+	require.NoError(t, err)
+	assert.Equal(t, "n1", member.Name)
+
+	_, err = limits.CheckTargetMember(p, "n2", allMembers)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+
+	_, err = limits.CheckTargetMember(p, "n4", allMembers)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	p.Config = map[string]string{"restricted": "true", "restricted.cluster.groups": "default"}
+
+	_, err = limits.CheckTargetMember(p, "n3", allMembers)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+}
@@ -110,5 +110,11 @@ func GetCurrentAllocations(ctx context.Context, globalConfig map[string]string,
 		Usage: int64(len(networks[projectName])),
 	}
 
+	// Include the usage of the child projects.
+	err = addDescendantsUsage(ctx, globalConfig, tx, projectName, result)
+	if err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
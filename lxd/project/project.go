@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
@@ -314,6 +315,44 @@ func ProfileProjectFromRecord(p *api.Project) string {
 	return api.ProjectDefaultName
 }
 
+// DefaultProfilesFromRecord returns the ordered list of profiles to apply to a new instance of the project which
+// doesn't specify any. The profiles set by the project take precedence over the given image profiles, which are
+// otherwise used unless nil. Without either, the `default` profile is used.
+// The profiles are looked up in the project returned by ProfileProjectFromRecord.
+func DefaultProfilesFromRecord(p *api.Project, imageProfiles []string) []string {
+	if p.Config["instances.default_profiles"] != "" {
+		return shared.SplitNTrimSpace(p.Config["instances.default_profiles"], ",", -1, true)
+	}
+
+	if imageProfiles != nil {
+		return imageProfiles
+	}
+
+	return []string{"default"}
+}
+
+// DeleteProtectedFromRecord returns whether an instance or custom volume with the given config is protected from
+// being deleted. The "security.protection.delete" key of the entity takes precedence, otherwise the value of the
+// same key in the supplied project applies unless the project is temporarily unlocked through
+// "security.protection.delete.unlocked_until".
+func DeleteProtectedFromRecord(p *api.Project, config map[string]string) bool {
+	value, ok := config["security.protection.delete"]
+	if ok && value != "" {
+		return shared.IsTrue(value)
+	}
+
+	if !shared.IsTrue(p.Config["security.protection.delete"]) {
+		return false
+	}
+
+	unlockedUntil, err := time.Parse(time.RFC3339, p.Config["security.protection.delete.unlocked_until"])
+	if err == nil && time.Now().Before(unlockedUntil) {
+		return false
+	}
+
+	return true
+}
+
 // NetworkZoneProject returns the effective project name to use for network zone based on the requested project.
 // If the requested project has the "features.networks.zones" flag enabled then the requested project's name is
 // returned, otherwise the default project name is returned.
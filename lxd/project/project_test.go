@@ -2,6 +2,7 @@ package project_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/shared/api"
@@ -45,3 +46,38 @@ func ExampleStorageVolume() {
 	// Output: default_test
 	// project_name_test1
 }
+
+func ExampleDeleteProtectedFromRecord() {
+	p := &api.Project{Name: "prod", Config: map[string]string{"security.protection.delete": "true"}}
+	fmt.Println(project.DeleteProtectedFromRecord(p, nil))
+	fmt.Println(project.DeleteProtectedFromRecord(p, map[string]string{"security.protection.delete": "false"}))
+
+	p.Config["security.protection.delete.unlocked_until"] = time.Now().Add(time.Hour).Format(time.RFC3339)
+	fmt.Println(project.DeleteProtectedFromRecord(p, nil))
+
+	p.Config["security.protection.delete.unlocked_until"] = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	fmt.Println(project.DeleteProtectedFromRecord(p, nil))
+
+	// Output: true
+	// false
+	// false
+	// true
+}
+
+func ExampleDefaultProfilesFromRecord() {
+	p := &api.Project{Name: "web", Config: map[string]string{}}
+	fmt.Println(project.DefaultProfilesFromRecord(p, nil))
+	fmt.Println(project.DefaultProfilesFromRecord(p, []string{"gpu"}))
+	fmt.Println(project.DefaultProfilesFromRecord(p, []string{}))
+
+	// The profiles set by the project take precedence over those of the image.
+	p.Config["instances.default_profiles"] = "default, web ,monitoring"
+	fmt.Println(project.DefaultProfilesFromRecord(p, nil))
+	fmt.Println(project.DefaultProfilesFromRecord(p, []string{"gpu"}))
+
+	// Output: [default]
+	// [gpu]
+	// []
+	// [default web monitoring]
+	// [default web monitoring]
+}
@@ -0,0 +1,193 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Header is the header of an asciicast (version 2) recording.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes a terminal session to a file in the asciicast (version 2) format.
+// A nil Recorder is valid and records nothing.
+type Recorder struct {
+	mu     sync.Mutex
+	f      *os.File
+	start  time.Time
+	failed bool
+}
+
+// New creates a new recording at the given path.
+// A width and height of 0 are replaced with the traditional 80x24 terminal size.
+func New(path string, header Header) (*Recorder, error) {
+	if header.Width <= 0 || header.Height <= 0 {
+		header.Width = 80
+		header.Height = 24
+	}
+
+	header.Version = 2
+
+	start := time.Now()
+	header.Timestamp = start.Unix()
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating session recording %q: %w", path, err)
+	}
+
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("Failed writing session recording %q: %w", path, err)
+	}
+
+	return &Recorder{f: f, start: start}, nil
+}
+
+// event appends an event to the recording.
+// Failures are logged once and stop the recording without interrupting the session.
+func (r *Recorder) event(code string, data string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failed || r.f == nil {
+		return
+	}
+
+	line, err := json.Marshal([]any{time.Since(r.start).Seconds(), code, data})
+	if err == nil {
+		_, err = r.f.Write(append(line, '\n'))
+	}
+
+	if err != nil {
+		logger.Warn("Failed writing session recording", logger.Ctx{"path": r.f.Name(), "err": err})
+		r.failed = true
+	}
+}
+
+// Output records data sent to the terminal.
+func (r *Recorder) Output(data []byte) {
+	r.event("o", string(data))
+}
+
+// Input records data typed in the terminal.
+func (r *Recorder) Input(data []byte) {
+	r.event("i", string(data))
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width int, height int) {
+	r.event("r", strconv.Itoa(width)+"x"+strconv.Itoa(height))
+}
+
+// Close finishes the recording.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+
+	err := r.f.Close()
+	r.f = nil
+
+	return err
+}
+
+// Reader returns a reader recording all the data read from rd as output.
+func (r *Recorder) Reader(rd io.Reader) io.Reader {
+	if r == nil {
+		return rd
+	}
+
+	return &reader{rec: r, rd: rd}
+}
+
+// Writer returns a writer recording all the data written to w as input.
+func (r *Recorder) Writer(w io.Writer) io.Writer {
+	if r == nil {
+		return w
+	}
+
+	return &writer{rec: r, w: w}
+}
+
+// ReadWriteCloser returns a wrapper around a terminal recording what is read from it as output and what is
+// written to it as input.
+func (r *Recorder) ReadWriteCloser(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	if r == nil {
+		return rwc
+	}
+
+	return &readWriteCloser{reader: reader{rec: r, rd: rwc}, writer: writer{rec: r, w: rwc}, closer: rwc}
+}
+
+type reader struct {
+	rec *Recorder
+	rd  io.Reader
+}
+
+// Read implements io.Reader.
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.rd.Read(p)
+	if n > 0 {
+		r.rec.Output(p[:n])
+	}
+
+	return n, err
+}
+
+type writer struct {
+	rec *Recorder
+	w   io.Writer
+}
+
+// Write implements io.Writer.
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n > 0 {
+		w.rec.Input(p[:n])
+	}
+
+	return n, err
+}
+
+type readWriteCloser struct {
+	reader
+	writer
+	closer io.Closer
+}
+
+// Close implements io.Closer.
+func (rwc *readWriteCloser) Close() error {
+	return rwc.closer.Close()
+}
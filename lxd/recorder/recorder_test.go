@@ -0,0 +1,86 @@
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+
+	rec, err := New(path, Header{Command: "bash", Title: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	_, err = io.Copy(&out, rec.Reader(bytes.NewBufferString("hello\r\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rec.Writer(io.Discard).Write([]byte("ls\r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec.Resize(120, 40)
+
+	err = rec.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "hello\r\n" {
+		t.Fatalf("Unexpected data passed through: %q", out.String())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("Missing header")
+	}
+
+	header := Header{}
+	err = json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 || header.Command != "bash" {
+		t.Fatalf("Unexpected header: %+v", header)
+	}
+
+	expected := [][2]string{{"o", "hello\r\n"}, {"i", "ls\r"}, {"r", "120x40"}}
+	for _, exp := range expected {
+		if !scanner.Scan() {
+			t.Fatalf("Missing event %v", exp)
+		}
+
+		event := []any{}
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(event) != 3 || event[1] != exp[0] || event[2] != exp[1] {
+			t.Fatalf("Unexpected event %v, expected %v", event, exp)
+		}
+	}
+
+	_, err = New(path, Header{})
+	if err == nil {
+		t.Fatal("Expected an error when overwriting an existing recording")
+	}
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var replicationCmd = APIEndpoint{
+	Path:        "replication",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: replicationGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanView)},
+}
+
+var replicationPromoteCmd = APIEndpoint{
+	Path:        "replication/promote",
+	MetricsType: entity.TypeServer,
+
+	Post: APIEndpointAction{Handler: replicationPromotePost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// replicationRunning tracks the instances being replicated, to avoid overlapping replications of an instance.
+var replicationRunning = sync.Map{}
+
+// replicationInstances returns the replicated instances of the cluster, both the primary instances with a
+// replication schedule and the standby replicas.
+func replicationInstances(ctx context.Context, s *state.State) ([]instance.Instance, error) {
+	var instances []instance.Instance
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			if inst.ExpandedConfig()["replication.schedule"] == "" && shared.IsFalseOrEmpty(inst.LocalConfig()["volatile.replication.standby"]) {
+				return nil
+			}
+
+			instances = append(instances, inst)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// swagger:operation GET /1.0/replication replication replication_get
+//
+//	Get the replication state
+//
+//	Returns the replication state of the instances replicated to the standby cluster, or of the replicas received
+//	from the primary cluster.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Replication state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of replicated instances
+//	          items:
+//	            $ref: "#/definitions/ReplicationInstance"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instances, err := replicationInstances(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	result := make([]api.ReplicationInstance, 0, len(instances))
+	for _, inst := range instances {
+		entry := api.ReplicationInstance{
+			Project:  inst.Project().Name,
+			Name:     inst.Name(),
+			Location: inst.Location(),
+			Role:     "primary",
+			Schedule: inst.ExpandedConfig()["replication.schedule"],
+			Error:    inst.LocalConfig()["volatile.replication.last_error"],
+			Lag:      -1,
+		}
+
+		if shared.IsTrue(inst.LocalConfig()["volatile.replication.standby"]) {
+			entry.Role = "standby"
+			entry.Schedule = ""
+		}
+
+		lastSync, err := time.Parse(time.RFC3339, inst.LocalConfig()["volatile.replication.last_sync"])
+		if err == nil {
+			entry.LastSync = lastSync
+			entry.Lag = int64(time.Since(lastSync).Seconds())
+		}
+
+		result = append(result, entry)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation POST /1.0/replication/promote replication replication_promote_post
+//
+//	Promote the standby replicas
+//
+//	Turns the replicas received from the primary cluster into regular instances, optionally starting them.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: promote
+//	    description: Replicas to promote
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ReplicationPromotePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationPromotePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ReplicationPromotePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for _, key := range req.Instances {
+		projectName, instName, ok := strings.Cut(key, "/")
+		if !ok || projectName == "" || instName == "" {
+			return response.BadRequest(fmt.Errorf("Invalid instance %q, must be in the form <project>/<instance>", key))
+		}
+	}
+
+	run := func(op *operations.Operation) error {
+		ctx := context.TODO()
+
+		instances, err := replicationInstances(ctx, s)
+		if err != nil {
+			return err
+		}
+
+		promoted := []instance.Instance{}
+		for _, inst := range instances {
+			if shared.IsFalseOrEmpty(inst.LocalConfig()["volatile.replication.standby"]) {
+				continue
+			}
+
+			if len(req.Instances) > 0 && !slices.Contains(req.Instances, inst.Project().Name+"/"+inst.Name()) {
+				continue
+			}
+
+			err = inst.VolatileSet(map[string]string{"volatile.replication.standby": ""})
+			if err != nil {
+				return fmt.Errorf("Failed promoting instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+			}
+
+			logger.Info("Promoted standby replica", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+			promoted = append(promoted, inst)
+		}
+
+		if !req.Start {
+			return nil
+		}
+
+		for _, inst := range promoted {
+			err = replicationStartInstance(ctx, s, inst)
+			if err != nil {
+				return fmt.Errorf("Failed starting promoted instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+			}
+		}
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(r.Context(), s, "", operations.OperationClassTask, operationtype.ReplicationPromote, nil, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// replicationStartInstance starts the instance, on the cluster member it is located on.
+func replicationStartInstance(ctx context.Context, s *state.State, inst instance.Instance) error {
+	client, err := cluster.ConnectIfInstanceIsRemote(ctx, s, inst.Project().Name, inst.Name(), instancetype.Any)
+	if err != nil {
+		return err
+	}
+
+	if client == nil {
+		return inst.Start(false)
+	}
+
+	op, err := client.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start", Timeout: -1}, "")
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// replicationStandbyPut returns the configuration of the standby replica of the instance. The volatile keys of
+// an existing replica are preserved.
+func replicationStandbyPut(put api.InstancePut, replicaConfig map[string]string) api.InstancePut {
+	config := make(map[string]string, len(put.Config))
+	for key, value := range put.Config {
+		if key == "replication.schedule" || (strings.HasPrefix(key, instancetype.ConfigVolatilePrefix) && key != "volatile.base_image") {
+			continue
+		}
+
+		config[key] = value
+	}
+
+	for key, value := range replicaConfig {
+		if strings.HasPrefix(key, instancetype.ConfigVolatilePrefix) {
+			config[key] = value
+		}
+	}
+
+	config["volatile.replication.standby"] = "true"
+	put.Config = config
+
+	return put
+}
+
+// replicateInstance sends the instance volumes and configuration to its replica on the standby cluster.
+// Existing replicas are refreshed, only sending the differences since the last replication when the storage
+// driver supports it.
+func replicateInstance(s *state.State, op *operations.Operation, client lxd.InstanceServer, remoteCert string, address string, inst instance.Instance) error {
+	render, _, err := inst.Render()
+	if err != nil {
+		return err
+	}
+
+	apiInst, ok := render.(*api.Instance)
+	if !ok {
+		return errors.New("Unexpected instance representation")
+	}
+
+	client = client.UseProject(inst.Project().Name)
+
+	// Never overwrite an instance of the standby cluster that isn't a replica, such as a promoted one.
+	replica, _, err := client.GetInstance(inst.Name())
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed getting replica from standby cluster: %w", err)
+	}
+
+	if replica != nil && shared.IsFalseOrEmpty(replica.Config["volatile.replication.standby"]) {
+		return fmt.Errorf("Instance %q of the standby cluster isn't a replica", inst.Name())
+	}
+
+	syncTime := time.Now().UTC()
+
+	instReq := api.InstancesPost{
+		Name:        inst.Name(),
+		InstancePut: replicationStandbyPut(apiInst.Writable(), nil),
+		Type:        api.InstanceType(apiInst.Type),
+		Source: api.InstanceSource{
+			Type:              api.SourceTypeMigration,
+			Mode:              "push",
+			BaseImage:         apiInst.Config["volatile.base_image"],
+			AllowInconsistent: true,
+			Refresh:           true,
+		},
+	}
+
+	err = instanceRemotePush(op.Context(), s, op, client, remoteCert, address, inst, instReq, nil)
+	if err != nil {
+		return err
+	}
+
+	// Refreshing an existing replica doesn't update its configuration, so do it now.
+	replica, etag, err := client.GetInstance(inst.Name())
+	if err != nil {
+		return fmt.Errorf("Failed getting replica from standby cluster: %w", err)
+	}
+
+	put := replicationStandbyPut(apiInst.Writable(), replica.Config)
+	put.Config["volatile.replication.last_sync"] = syncTime.Format(time.RFC3339)
+
+	updateOp, err := client.UpdateInstance(inst.Name(), put, etag)
+	if err != nil {
+		return fmt.Errorf("Failed updating replica on standby cluster: %w", err)
+	}
+
+	err = updateOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed updating replica on standby cluster: %w", err)
+	}
+
+	return inst.VolatileSet(map[string]string{
+		"volatile.replication.last_sync":  syncTime.Format(time.RFC3339),
+		"volatile.replication.last_error": "",
+	})
+}
+
+// replicateInstances replicates the instances to the standby cluster, recording the outcome in their volatile
+// configuration.
+func replicateInstances(ctx context.Context, s *state.State, op *operations.Operation, instances []instance.Instance) error {
+	address, certificate := s.GlobalConfig.ReplicationTarget()
+
+	var client lxd.InstanceServer
+	var remoteCert string
+	var err error
+
+	if certificate == "" {
+		err = errors.New("The certificate of the standby cluster isn't configured")
+	} else {
+		address = util.CanonicalNetworkAddress(address, shared.HTTPSDefaultPort)
+		client, remoteCert, address, err = instanceRemoteConnect(ctx, s, s.Endpoints.NetworkCert(), api.InstancePostRemote{Address: address, Certificate: certificate})
+	}
+
+	var errs []error
+	for _, inst := range instances {
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		_, loaded := replicationRunning.LoadOrStore(inst.ID(), struct{}{})
+		if loaded {
+			continue // Replication of this instance is already running, skip.
+		}
+
+		instErr := err
+		if instErr == nil {
+			instErr = replicateInstance(s, op, client, remoteCert, address, inst)
+		}
+
+		replicationRunning.Delete(inst.ID())
+
+		if instErr != nil {
+			l.Error("Failed replicating instance", logger.Ctx{"err": instErr})
+			_ = inst.VolatileSet(map[string]string{"volatile.replication.last_error": instErr.Error()})
+			errs = append(errs, fmt.Errorf("Failed replicating instance %q in project %q: %w", inst.Name(), inst.Project().Name, instErr))
+			continue
+		}
+
+		l.Debug("Replicated instance")
+	}
+
+	return errors.Join(errs...)
+}
+
+func replicationTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		address, _ := s.GlobalConfig.ReplicationTarget()
+		if address == "" {
+			return
+		}
+
+		// Get list of instances on the local member that are due to be replicated.
+		var instances []instance.Instance
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for replication task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				schedule := inst.ExpandedConfig()["replication.schedule"]
+				if schedule == "" || shared.IsTrue(inst.LocalConfig()["volatile.replication.standby"]) {
+					return nil
+				}
+
+				if !snapshotIsScheduledNow(schedule, int64(inst.ID())) {
+					return nil
+				}
+
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance replication schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return replicateInstances(ctx, s, op, instances)
+		}
+
+		op, err := operations.OperationCreate(context.Background(), s, "", operations.OperationClassTask, operationtype.InstancesReplicate, nil, nil, opRun, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Replicating instances to standby cluster")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed replicating instances to standby cluster", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done replicating instances to standby cluster")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
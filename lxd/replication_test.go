@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_replicationStandbyPut(t *testing.T) {
+	put := api.InstancePut{
+		Config: map[string]string{
+			"limits.cpu":           "2",
+			"replication.schedule": "@hourly",
+			"volatile.base_image":  "abcdef",
+			"volatile.eth0.hwaddr": "00:16:3e:00:00:01",
+			"volatile.idmap.next":  "[]",
+		},
+		Profiles: []string{"default"},
+	}
+
+	// The replication schedule and the volatile keys of the primary aren't replicated.
+	assert.Equal(t, api.InstancePut{
+		Config: map[string]string{
+			"limits.cpu":                   "2",
+			"volatile.base_image":          "abcdef",
+			"volatile.replication.standby": "true",
+		},
+		Profiles: []string{"default"},
+	}, replicationStandbyPut(put, nil))
+
+	// The volatile keys of an existing replica are preserved.
+	replicaConfig := map[string]string{
+		"limits.cpu":                     "1",
+		"volatile.eth0.hwaddr":           "00:16:3e:00:00:02",
+		"volatile.replication.last_sync": "2024-01-01T00:00:00Z",
+	}
+
+	assert.Equal(t, map[string]string{
+		"limits.cpu":                     "2",
+		"volatile.base_image":            "abcdef",
+		"volatile.eth0.hwaddr":           "00:16:3e:00:00:02",
+		"volatile.replication.last_sync": "2024-01-01T00:00:00Z",
+		"volatile.replication.standby":   "true",
+	}, replicationStandbyPut(put, replicaConfig).Config)
+
+	// The configuration of the primary isn't modified.
+	assert.Equal(t, "@hourly", put.Config["replication.schedule"])
+}
@@ -27,6 +27,9 @@ const (
 
 	// CtxOpenFGARequestCache is used to set a cache for the OpenFGA datastore to improve driver performance on a per request basis.
 	CtxOpenFGARequestCache CtxKey = "openfga_request_cache"
+
+	// CtxEtagRequired indicates whether the request must include an If-Match header when updating an entity.
+	CtxEtagRequired CtxKey = "etag_required"
 )
 
 // Headers.
@@ -43,6 +46,13 @@ const (
 	// headerForwardedIdentityProviderGroups is the forwarded identity provider groups field in request header.
 	// This will be a JSON marshalled []string.
 	headerForwardedIdentityProviderGroups = "X-LXD-forwarded-identity-provider-groups"
+
+	// headerForwardedAdmitted is set when the forwarded request was already admitted by the admission scriptlets.
+	headerForwardedAdmitted = "X-LXD-forwarded-admitted"
+
+	// headerTraceparent is the W3C trace context header holding the trace ID of the request.
+	// It is accepted from any client and propagated when requests are forwarded between cluster members.
+	headerTraceparent = "traceparent"
 )
 
 const (
@@ -0,0 +1,186 @@
+package request
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Pagination holds the pagination, sorting and field selection parameters of a request for a collection.
+type Pagination struct {
+	// Limit is the maximum number of entries to return, or zero for no limit.
+	Limit int
+
+	// Offset is the number of entries to skip.
+	Offset int
+
+	// Sort is the list of fields to sort the entries by, in order of precedence.
+	Sort []SortKey
+
+	// Fields is the list of fields to return for each entry, or empty for all fields.
+	Fields []string
+}
+
+// SortKey is a field to sort the entries of a collection by.
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// SortField is a field that the entries of a collection of type T can be sorted by.
+type SortField[T any] struct {
+	Name    string
+	Compare func(a T, b T) int
+}
+
+// PaginationParams returns the pagination parameters of the given request. The `limit` and `offset` parameters
+// select the page of entries to return, `sort` is a comma-separated list of fields to sort the entries by (prefixed
+// with `-` for descending order) and `fields` is a comma-separated list of the fields to return for each entry.
+// It returns an [api.StatusError] with [http.StatusBadRequest] if a parameter is invalid.
+func PaginationParams(r *http.Request) (*Pagination, error) {
+	p := &Pagination{}
+
+	var err error
+	p.Limit, err = paginationCount(r, "limit")
+	if err != nil {
+		return nil, err
+	}
+
+	p.Offset, err = paginationCount(r, "offset")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range shared.SplitNTrimSpace(QueryParam(r, "sort"), ",", -1, true) {
+		key := SortKey{Field: field}
+
+		if strings.HasPrefix(field, "-") {
+			key.Field = field[1:]
+			key.Descending = true
+		}
+
+		if key.Field == "" {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid sort field %q", field)
+		}
+
+		p.Sort = append(p.Sort, key)
+	}
+
+	p.Fields = shared.SplitNTrimSpace(QueryParam(r, "fields"), ",", -1, true)
+
+	return p, nil
+}
+
+// paginationMaxCount is the largest limit or offset, above which the values are clamped so that they can be added
+// without overflowing.
+const paginationMaxCount = math.MaxInt32
+
+// paginationCount returns the value of the given non-negative integer query parameter, or zero if it isn't set.
+// Values above paginationMaxCount are clamped.
+func paginationCount(r *http.Request, key string) (int, error) {
+	param := QueryParam(r, key)
+	if param == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(param)
+	if err != nil || value < 0 {
+		return 0, api.StatusErrorf(http.StatusBadRequest, "Invalid %s %q", key, param)
+	}
+
+	return min(value, paginationMaxCount), nil
+}
+
+// Paged returns whether only a page of the entries was requested.
+func (p *Pagination) Paged() bool {
+	return p.Limit > 0 || p.Offset > 0
+}
+
+// Paginate sorts the entries by the sort keys of the pagination, breaking ties using the given fields in order, and
+// returns the page of entries selected by its offset and limit. The entries are sorted in place.
+// It returns an [api.StatusError] with [http.StatusBadRequest] if a sort key isn't one of the given fields.
+func Paginate[T any](p *Pagination, entries []T, fields []SortField[T]) ([]T, error) {
+	compares := make([]func(a T, b T) int, 0, len(p.Sort)+len(fields))
+	for _, key := range p.Sort {
+		i := slices.IndexFunc(fields, func(field SortField[T]) bool { return field.Name == key.Field })
+		if i < 0 {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid sort field %q", key.Field)
+		}
+
+		compare := fields[i].Compare
+		if key.Descending {
+			compare = func(a T, b T) int { return fields[i].Compare(b, a) }
+		}
+
+		compares = append(compares, compare)
+	}
+
+	for _, field := range fields {
+		compares = append(compares, field.Compare)
+	}
+
+	slices.SortStableFunc(entries, func(a T, b T) int {
+		for _, compare := range compares {
+			result := compare(a, b)
+			if result != 0 {
+				return result
+			}
+		}
+
+		return 0
+	})
+
+	return Page(p, entries), nil
+}
+
+// Page returns the page of the given entries selected by the offset and limit of the pagination. The entries must
+// already be sorted.
+func Page[T any](p *Pagination, entries []T) []T {
+	start := min(p.Offset, len(entries))
+	end := len(entries)
+	if p.Limit > 0 && p.Limit < end-start {
+		end = start + p.Limit
+	}
+
+	return entries[start:end]
+}
+
+// SelectFields returns the given list of entries with only the requested fields, keyed by their JSON name. The list
+// is returned unchanged if no fields were requested.
+func (p *Pagination) SelectFields(entries any) (any, error) {
+	if len(p.Fields) == 0 {
+		return entries, nil
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []map[string]json.RawMessage
+	err = json.Unmarshal(data, &all)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]map[string]json.RawMessage, 0, len(all))
+	for _, entry := range all {
+		fields := make(map[string]json.RawMessage, len(p.Fields))
+		for _, field := range p.Fields {
+			value, ok := entry[field]
+			if ok {
+				fields[field] = value
+			}
+		}
+
+		selected = append(selected, fields)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,92 @@
+package request_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/request"
+)
+
+type paginationEntry struct {
+	Name  string `json:"name"`
+	Group string `json:"group"`
+}
+
+var paginationSortFields = []request.SortField[paginationEntry]{
+	{Name: "group", Compare: func(a paginationEntry, b paginationEntry) int { return strings.Compare(a.Group, b.Group) }},
+	{Name: "name", Compare: func(a paginationEntry, b paginationEntry) int { return strings.Compare(a.Name, b.Name) }},
+}
+
+func paginationEntries() []paginationEntry {
+	return []paginationEntry{{"c", "x"}, {"a", "y"}, {"b", "x"}, {"d", "y"}}
+}
+
+func TestPaginationParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/1.0/instances?limit=2&offset=1&sort=-name,group&fields=name", nil)
+
+	p, err := request.PaginationParams(r)
+	require.NoError(t, err)
+	assert.Equal(t, &request.Pagination{
+		Limit:  2,
+		Offset: 1,
+		Sort:   []request.SortKey{{Field: "name", Descending: true}, {Field: "group"}},
+		Fields: []string{"name"},
+	}, p)
+	assert.True(t, p.Paged())
+
+	for _, query := range []string{"limit=-1", "offset=foo", "sort=-"} {
+		_, err := request.PaginationParams(httptest.NewRequest("GET", "/1.0/instances?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	// Default order uses the sort fields in order.
+	entries, err := request.Paginate(&request.Pagination{}, paginationEntries(), paginationSortFields)
+	require.NoError(t, err)
+	assert.Equal(t, []paginationEntry{{"b", "x"}, {"c", "x"}, {"a", "y"}, {"d", "y"}}, entries)
+
+	// Requested sort keys take precedence and the page is selected after sorting.
+	p := &request.Pagination{Limit: 2, Offset: 1, Sort: []request.SortKey{{Field: "name", Descending: true}}}
+	entries, err = request.Paginate(p, paginationEntries(), paginationSortFields)
+	require.NoError(t, err)
+	assert.Equal(t, []paginationEntry{{"c", "x"}, {"b", "x"}}, entries)
+
+	// Offsets past the end return an empty page.
+	entries, err = request.Paginate(&request.Pagination{Offset: 10}, paginationEntries(), paginationSortFields)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = request.Paginate(&request.Pagination{Sort: []request.SortKey{{Field: "size"}}}, paginationEntries(), paginationSortFields)
+	assert.Error(t, err)
+}
+
+func TestPageLargeLimit(t *testing.T) {
+	// Limits that overflow when added to the offset return the rest of the entries.
+	entries := request.Page(&request.Pagination{Limit: math.MaxInt, Offset: 1}, paginationEntries())
+	assert.Equal(t, paginationEntries()[1:], entries)
+
+	// Oversized query parameters are clamped.
+	r := httptest.NewRequest("GET", "/1.0/instances?offset=1&limit=9223372036854775807", nil)
+	p, err := request.PaginationParams(r)
+	require.NoError(t, err)
+	assert.Equal(t, math.MaxInt32, p.Limit)
+	assert.Equal(t, paginationEntries()[1:], request.Page(p, paginationEntries()))
+}
+
+func TestPaginationSelectFields(t *testing.T) {
+	p := &request.Pagination{Fields: []string{"name", "unknown"}}
+
+	result, err := p.SelectFields(paginationEntries()[:1])
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "c"}]`, string(data))
+}
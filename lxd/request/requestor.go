@@ -46,9 +46,13 @@ type Requestor struct {
 	forwardedUsername               string
 	forwardedProtocol               string
 	forwardedIdentityProviderGroups []string
+	admitted                        bool
 	clientType                      ClientType
 	identity                        *identity.CacheEntry
 	identityType                    identity.Type
+	traceID                         string
+	spanID                          string
+	traceFlags                      string
 }
 
 // IsClusterNotification returns true if this an API request coming from a
@@ -117,6 +121,12 @@ func (r *Requestor) ClientType() ClientType {
 	return r.clientType
 }
 
+// TraceID returns the trace ID of the request. It is taken from the "traceparent" header of the request if valid,
+// and generated otherwise, so that the requests forwarded between cluster members can be correlated.
+func (r *Requestor) TraceID() string {
+	return r.traceID
+}
+
 // EventLifecycleRequestor returns an api.EventLifecycleRequestor representing the original caller.
 func (r *Requestor) EventLifecycleRequestor() *api.EventLifecycleRequestor {
 	return &api.EventLifecycleRequestor{
@@ -141,6 +151,7 @@ func (r *Requestor) OperationRequestor() *api.OperationRequestor {
 		Username: r.CallerUsername(),
 		Protocol: r.CallerProtocol(),
 		Address:  r.OriginAddress(),
+		TraceID:  r.TraceID(),
 	}
 }
 
@@ -159,6 +170,18 @@ func (r *Requestor) IsForwarded() bool {
 	return r.forwardedOriginAddress != ""
 }
 
+// IsAdmitted returns true if the request was already admitted by the admission scriptlets, either on this cluster
+// member or on the cluster member which forwarded it.
+func (r *Requestor) IsAdmitted() bool {
+	return r.admitted
+}
+
+// SetAdmitted records that the request was admitted by the admission scriptlets. This is propagated when the request
+// is forwarded to another cluster member.
+func (r *Requestor) SetAdmitted() {
+	r.admitted = true
+}
+
 // ForwardProxy returns a proxy function that adds the requestor details as headers to be inspected by the receiving cluster member.
 func (r *Requestor) ForwardProxy() func(req *http.Request) (*url.URL, error) {
 	return func(req *http.Request) (*url.URL, error) {
@@ -182,6 +205,14 @@ func (r *Requestor) ForwardProxy() func(req *http.Request) (*url.URL, error) {
 			}
 		}
 
+		if r.admitted {
+			req.Header.Set(headerForwardedAdmitted, "true")
+		}
+
+		if r.traceID != "" {
+			req.Header.Set(headerTraceparent, traceparent(r.traceID, r.spanID, r.traceFlags))
+		}
+
 		return shared.ProxyFromEnvironment(req)
 	}
 }
@@ -202,11 +233,12 @@ func (r *Requestor) setForwardingDetails(req *http.Request) error {
 	forwardedUsername := req.Header.Get(headerForwardedUsername)
 	forwardedProtocol := req.Header.Get(headerForwardedProtocol)
 	forwardedIdentityProviderGroupsJSON := req.Header.Get(headerForwardedIdentityProviderGroups)
+	forwardedAdmitted := req.Header.Get(headerForwardedAdmitted)
 
 	// Requests can only be forwarded from other cluster members.
 	if r.protocol != ProtocolCluster {
 		// No forwarding headers may be set if the protocol is not ProtocolCluster.
-		if forwardedAddress != "" || forwardedUsername != "" || forwardedProtocol != "" || forwardedIdentityProviderGroupsJSON != "" {
+		if forwardedAddress != "" || forwardedUsername != "" || forwardedProtocol != "" || forwardedIdentityProviderGroupsJSON != "" || forwardedAdmitted != "" {
 			return errors.New("Received forwarded request information from non-cluster member")
 		}
 
@@ -225,6 +257,7 @@ func (r *Requestor) setForwardingDetails(req *http.Request) error {
 	r.forwardedUsername = forwardedUsername
 	r.forwardedProtocol = forwardedProtocol
 	r.forwardedIdentityProviderGroups = forwardedIdentityProviderGroups
+	r.admitted = shared.IsTrue(forwardedAdmitted)
 	return nil
 }
 
@@ -306,6 +339,11 @@ func SetRequestor(req *http.Request, identityCache *identity.Cache, args Request
 		clientType:             clientType,
 	}
 
+	r.traceID, r.spanID, r.traceFlags = parseTraceparent(req.Header.Get(headerTraceparent))
+	if r.traceID == "" {
+		r.traceID = newTraceID()
+	}
+
 	err := r.setForwardingDetails(req)
 	if err != nil {
 		return err
@@ -0,0 +1,73 @@
+package request
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// traceIDZero is the invalid all-zero trace ID of the W3C trace context specification.
+const traceIDZero = "00000000000000000000000000000000"
+
+// isLowerHex returns whether s is made of lowercase hexadecimal characters only.
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseTraceparent returns the trace ID, parent ID and flags of a W3C "traceparent" header value
+// (version-traceid-parentid-flags), or empty strings if the value is invalid.
+func parseTraceparent(value string) (traceID string, parentID string, flags string) {
+	fields := strings.Split(strings.TrimSpace(value), "-")
+	if len(fields) < 4 || len(fields[0]) != 2 || len(fields[1]) != 32 || len(fields[2]) != 16 || len(fields[3]) != 2 {
+		return "", "", ""
+	}
+
+	// Version "ff" is forbidden and version "00" has exactly four fields.
+	if fields[0] == "ff" || (fields[0] == "00" && len(fields) != 4) {
+		return "", "", ""
+	}
+
+	for _, field := range fields[:4] {
+		if !isLowerHex(field) {
+			return "", "", ""
+		}
+	}
+
+	if fields[1] == traceIDZero || fields[2] == "0000000000000000" {
+		return "", "", ""
+	}
+
+	return fields[1], fields[2], fields[3]
+}
+
+// randomHex returns n random bytes encoded as lowercase hexadecimal.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newTraceID returns a new random trace ID.
+func newTraceID() string {
+	return randomHex(16)
+}
+
+// traceparent returns a W3C "traceparent" header value for the given trace ID, parent ID and flags. A new parent
+// ID identifying the outgoing request is used if none is given, and the trace is flagged as sampled by default.
+func traceparent(traceID string, parentID string, flags string) string {
+	if parentID == "" {
+		parentID = randomHex(8)
+	}
+
+	if flags == "" {
+		flags = "01"
+	}
+
+	return "00-" + traceID + "-" + parentID + "-" + flags
+}
@@ -0,0 +1,70 @@
+package request_test
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/request"
+)
+
+func TestRequestorTraceID(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		traceID     string
+	}{
+		{name: "Valid", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "Future version", traceparent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra", traceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "Missing"},
+		{name: "Forbidden version", traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "Extra field", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "Uppercase", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "Zero trace ID", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "Zero parent ID", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "Short trace ID", traceparent: "00-4bf92f3577b34da6-00f067aa0ba902b7-01"},
+	}
+
+	validTraceID := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/1.0", nil)
+			if tt.traceparent != "" {
+				r.Header.Set("traceparent", tt.traceparent)
+			}
+
+			require.NoError(t, request.SetRequestor(r, nil, request.RequestorArgs{}))
+
+			requestor, err := request.GetRequestor(r.Context())
+			require.NoError(t, err)
+
+			// A new trace ID is generated if the header is missing or invalid.
+			traceID := requestor.TraceID()
+			if tt.traceID != "" {
+				assert.Equal(t, tt.traceID, traceID)
+			} else {
+				assert.Regexp(t, validTraceID, traceID)
+				assert.False(t, strings.Contains(tt.traceparent, traceID))
+			}
+
+			assert.Equal(t, traceID, requestor.OperationRequestor().TraceID)
+
+			// The trace ID is propagated to forwarded requests.
+			forwarded := httptest.NewRequest("GET", "https://lxd02:8443/1.0", nil)
+			_, err = requestor.ForwardProxy()(forwarded)
+			require.NoError(t, err)
+
+			fields := strings.Split(forwarded.Header.Get("traceparent"), "-")
+			require.Len(t, fields, 4)
+			assert.Equal(t, "00", fields[0])
+			assert.Equal(t, traceID, fields[1])
+			assert.Regexp(t, `^[0-9a-f]{16}$`, fields[2])
+			assert.NotEqual(t, "0000000000000000", fields[2])
+		})
+	}
+}
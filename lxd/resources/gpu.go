@@ -272,6 +272,19 @@ func gpuAddDeviceInfo(devicePath string, nvidiaCards map[string]*api.ResourcesGP
 		}
 	}
 
+	// NVIDIA MIG profiles (only available when MIG is enabled on the GPU).
+	if card.Nvidia != nil {
+		gpu := card.Nvidia.UUID
+		if gpu == "" {
+			gpu = card.PCIAddress
+		}
+
+		mig, err := loadNvidiaMIG(gpu)
+		if err == nil {
+			card.Nvidia.MIG = mig
+		}
+	}
+
 	// DRM information
 	drmPath := filepath.Join(devicePath, "drm")
 	if pathExists(drmPath) {
@@ -589,5 +602,42 @@ func GetGPU() (*api.ResourcesGPU, error) {
 		gpu.Total++
 	}
 
+	// Summarize the mediated device utilization.
+	gpu.MdevProfiles = gpuMdevProfiles(gpu.Cards)
+
 	return &gpu, nil
 }
+
+// gpuMdevProfiles aggregates the mediated device profiles of the cards (and their virtual functions).
+func gpuMdevProfiles(cards []api.ResourcesGPUCard) map[string]api.ResourcesGPUMdevProfile {
+	profiles := map[string]api.ResourcesGPUMdevProfile{}
+
+	var addCard func(card api.ResourcesGPUCard)
+	addCard = func(card api.ResourcesGPUCard) {
+		for k, v := range card.Mdev {
+			profile := profiles[k]
+			profile.Name = v.Name
+			profile.Available += v.Available
+			profile.Used += uint64(len(v.Devices))
+			profile.Total = profile.Available + profile.Used
+			profile.Cards = append(profile.Cards, card.PCIAddress)
+			profiles[k] = profile
+		}
+
+		if card.SRIOV != nil {
+			for _, vf := range card.SRIOV.VFs {
+				addCard(vf)
+			}
+		}
+	}
+
+	for _, card := range cards {
+		addCard(card)
+	}
+
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	return profiles
+}
@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// nvidiaMIGProfileRegex matches the GPU instance profile lines of `nvidia-smi mig -lgip`, for example:
+// |   0  MIG 1g.5gb          19     7/7        4.75       No     14     0     0   |
+var nvidiaMIGProfileRegex = regexp.MustCompile(`^\|\s*\d+\s+MIG\s+(\S+)\s+(\d+)\s+(\d+)/(\d+)\s+([0-9.]+)\s`)
+
+// loadNvidiaMIG returns the MIG GPU instance profiles of the NVIDIA GPU identified by its UUID or PCI address.
+// An error is returned if MIG isn't supported or enabled on the GPU.
+func loadNvidiaMIG(gpu string) (*api.ResourcesGPUCardNvidiaMIG, error) {
+	_, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to locate nvidia-smi: %w", err)
+	}
+
+	output, err := shared.RunCommandCLocale("nvidia-smi", "mig", "-lgip", "-i", gpu)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNvidiaMIGProfiles(output)
+}
+
+// parseNvidiaMIGProfiles parses the output of `nvidia-smi mig -lgip` for a single GPU.
+func parseNvidiaMIGProfiles(output string) (*api.ResourcesGPUCardNvidiaMIG, error) {
+	mig := &api.ResourcesGPUCardNvidiaMIG{
+		Profiles: map[string]api.ResourcesGPUCardNvidiaMIGProfile{},
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := nvidiaMIGProfileRegex.FindStringSubmatch(scanner.Text())
+		if fields == nil {
+			continue
+		}
+
+		id, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing MIG profile ID %q: %w", fields[2], err)
+		}
+
+		available, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing MIG profile free instances %q: %w", fields[3], err)
+		}
+
+		total, err := strconv.ParseUint(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing MIG profile total instances %q: %w", fields[4], err)
+		}
+
+		memory, err := strconv.ParseFloat(fields[5], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing MIG profile memory %q: %w", fields[5], err)
+		}
+
+		mig.Profiles[fields[1]] = api.ResourcesGPUCardNvidiaMIGProfile{
+			ID:        id,
+			Available: available,
+			Total:     total,
+			Memory:    uint64(memory * 1024 * 1024 * 1024),
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mig.Profiles) == 0 {
+		return nil, errors.New("No MIG profiles found")
+	}
+
+	return mig, nil
+}
@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_parseNvidiaMIGProfiles(t *testing.T) {
+	output := `+-----------------------------------------------------------------------------+
+| GPU instance profiles:                                                      |
+| GPU   Name             ID    Instances   Memory     P2P    SM    DEC   ENC  |
+|                              Free/Total   GiB              CE    JPEG  OFA  |
+|=============================================================================|
+|   0  MIG 1g.5gb        19     5/7        4.75       No     14     0     0   |
+|                                                             1     0     0   |
++-----------------------------------------------------------------------------+
+|   0  MIG 2g.10gb       14     1/3        9.75       No     28     1     0   |
+|                                                             2     0     0   |
++-----------------------------------------------------------------------------+
+|   0  MIG 7g.40gb        0     0/1        39.50      No     98     5     0   |
+|                                                             7     1     1   |
++-----------------------------------------------------------------------------+
+`
+
+	mig, err := parseNvidiaMIGProfiles(output)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]api.ResourcesGPUCardNvidiaMIGProfile{
+		"1g.5gb":  {ID: 19, Available: 5, Total: 7, Memory: 5100273664},
+		"2g.10gb": {ID: 14, Available: 1, Total: 3, Memory: 10468982784},
+		"7g.40gb": {ID: 0, Available: 0, Total: 1, Memory: 42412802048},
+	}, mig.Profiles)
+
+	// MIG disabled on the GPU.
+	_, err = parseNvidiaMIGProfiles("No MIG-enabled devices found.\n")
+	assert.Error(t, err)
+}
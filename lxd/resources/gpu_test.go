@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func Test_gpuMdevProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    []api.ResourcesGPUCard
+		expected map[string]api.ResourcesGPUMdevProfile
+	}{
+		{
+			name:     "No mediated devices",
+			cards:    []api.ResourcesGPUCard{{PCIAddress: "0000:01:00.0"}},
+			expected: nil,
+		},
+		{
+			name: "Multiple cards",
+			cards: []api.ResourcesGPUCard{
+				{
+					PCIAddress: "0000:3b:00.0",
+					Mdev: map[string]api.ResourcesGPUCardMdev{
+						"nvidia-233": {Name: "GRID T4-2Q", Available: 6, Devices: []string{"uuid1", "uuid2"}},
+						"nvidia-234": {Name: "GRID T4-4Q", Available: 2},
+					},
+				},
+				{
+					PCIAddress: "0000:d8:00.0",
+					Mdev: map[string]api.ResourcesGPUCardMdev{
+						"nvidia-233": {Name: "GRID T4-2Q", Available: 8},
+					},
+				},
+			},
+			expected: map[string]api.ResourcesGPUMdevProfile{
+				"nvidia-233": {Name: "GRID T4-2Q", Available: 14, Used: 2, Total: 16, Cards: []string{"0000:3b:00.0", "0000:d8:00.0"}},
+				"nvidia-234": {Name: "GRID T4-4Q", Available: 2, Used: 0, Total: 2, Cards: []string{"0000:3b:00.0"}},
+			},
+		},
+		{
+			name: "Virtual functions",
+			cards: []api.ResourcesGPUCard{
+				{
+					PCIAddress: "0000:41:00.0",
+					SRIOV: &api.ResourcesGPUCardSRIOV{
+						VFs: []api.ResourcesGPUCard{
+							{
+								PCIAddress: "0000:41:00.4",
+								Mdev: map[string]api.ResourcesGPUCardMdev{
+									"nvidia-558": {Name: "NVIDIA A100-4C", Available: 0, Devices: []string{"uuid1"}},
+								},
+							},
+							{
+								PCIAddress: "0000:41:00.5",
+								Mdev: map[string]api.ResourcesGPUCardMdev{
+									"nvidia-558": {Name: "NVIDIA A100-4C", Available: 1},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: map[string]api.ResourcesGPUMdevProfile{
+				"nvidia-558": {Name: "NVIDIA A100-4C", Available: 1, Used: 1, Total: 2, Cards: []string{"0000:41:00.4", "0000:41:00.5"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, gpuMdevProfiles(tt.cards))
+		})
+	}
+}
@@ -194,6 +194,11 @@ func SyncResponseHeaders(success bool, metadata any, headers map[string]string)
 	return &syncResponse{success: success, metadata: metadata, headers: headers}
 }
 
+// SyncResponseCode returns a new syncResponse with the given HTTP status code.
+func SyncResponseCode(success bool, code int, metadata any) Response {
+	return &syncResponse{success: success, metadata: metadata, code: code}
+}
+
 // SyncResponsePlain return a new syncResponse with plaintext.
 func SyncResponsePlain(success bool, compress bool, metadata string) Response {
 	return &syncResponse{success: success, metadata: metadata, plaintext: true, compress: compress}
@@ -354,7 +359,13 @@ func NotImplemented(err error) Response {
 
 // PreconditionFailed returns a precondition failed response (412) with the
 // given error.
+// A missing If-Match header reported as a precondition required error (428) keeps its status code.
 func PreconditionFailed(err error) Response {
+	code, found := api.StatusErrorMatch(err, http.StatusPreconditionRequired)
+	if found {
+		return &errorResponse{code: code, err: err}
+	}
+
 	return &errorResponse{code: http.StatusPreconditionFailed, err: err}
 }
 
@@ -42,6 +42,15 @@ func (rc *responseCapture) WriteHeader(statusCode int) {
 	rc.statusCode = statusCode
 }
 
+// StatusCode returns the captured response status code.
+func (rc *responseCapture) StatusCode() int {
+	if rc.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return rc.statusCode
+}
+
 // ToAPIResponse decodes the captured response body into an api.Response
 // and retrieves the ETag from the header.
 func (rc *responseCapture) ToAPIResponse() (*api.Response, string, error) {
@@ -0,0 +1,137 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/util"
+)
+
+func TestPreconditionFailed(t *testing.T) {
+	data := map[string]string{"limits.cpu": "2"}
+
+	hash, err := util.EtagHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Handler updating an entity as the API handlers do.
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		err := util.EtagCheck(r, data)
+		if err != nil {
+			_ = PreconditionFailed(err).Render(w, r)
+			return
+		}
+
+		_ = EmptySyncResponse.Render(w, r)
+	}
+
+	tests := []struct {
+		name     string
+		ifMatch  string
+		required bool
+		code     int
+	}{
+		{name: "No If-Match header", code: http.StatusOK},
+		{name: "No If-Match header when required", required: true, code: http.StatusPreconditionRequired},
+		{name: "Matching If-Match header when required", ifMatch: `"` + hash + `"`, required: true, code: http.StatusOK},
+		{name: "Stale If-Match header", ifMatch: "stale", code: http.StatusPreconditionFailed},
+		{name: "Stale If-Match header when required", ifMatch: "stale", required: true, code: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/1.0/profiles/default", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			if tt.required {
+				request.SetContextValue(r, request.CtxEtagRequired, true)
+			}
+
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("Expected status code %d, got %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+// Single file responses, such as backup exports, honour Range requests so that interrupted downloads can be resumed.
+func TestFileResponseRange(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	err := os.WriteFile(path, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modified := time.Now().Add(-time.Hour).UTC()
+	err = os.Chtimes(path, modified, modified)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		rangeHeader  string
+		ifRange      string
+		code         int
+		contentRange string
+		body         []byte
+	}{
+		{name: "Whole file", code: http.StatusOK, body: data},
+		{name: "Open range", rangeHeader: "bytes=4000-", code: http.StatusPartialContent, contentRange: "bytes 4000-9999/10000", body: data[4000:]},
+		{name: "Matching If-Range", rangeHeader: "bytes=4000-", ifRange: modified.Format(http.TimeFormat), code: http.StatusPartialContent, contentRange: "bytes 4000-9999/10000", body: data[4000:]},
+		{name: "Stale If-Range", rangeHeader: "bytes=4000-", ifRange: modified.Add(-time.Hour).Format(http.TimeFormat), code: http.StatusOK, body: data},
+		{name: "Unsatisfiable range", rangeHeader: "bytes=20000-", code: http.StatusRequestedRangeNotSatisfiable, contentRange: "bytes */10000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/1.0/instances/c1/backups/b1/export", nil)
+			if tt.rangeHeader != "" {
+				r.Header.Set("Range", tt.rangeHeader)
+			}
+
+			if tt.ifRange != "" {
+				r.Header.Set("If-Range", tt.ifRange)
+			}
+
+			w := httptest.NewRecorder()
+			err := FileResponse([]FileResponseEntry{{Path: path}}, nil).Render(w, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if w.Code != tt.code {
+				t.Fatalf("Expected status code %d, got %d", tt.code, w.Code)
+			}
+
+			if w.Header().Get("Content-Range") != tt.contentRange {
+				t.Errorf("Expected Content-Range %q, got %q", tt.contentRange, w.Header().Get("Content-Range"))
+			}
+
+			if tt.body != nil {
+				if !bytes.Equal(w.Body.Bytes(), tt.body) {
+					t.Errorf("Unexpected body of %d bytes", w.Body.Len())
+				}
+
+				if w.Header().Get("Content-Length") != strconv.Itoa(len(tt.body)) {
+					t.Errorf("Expected Content-Length %d, got %q", len(tt.body), w.Header().Get("Content-Length"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package scriptlet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// admissionMaxSteps bounds the number of execution steps of an admission scriptlet so that a faulty scriptlet can't
+// hold up requests.
+const admissionMaxSteps = 1000000
+
+// AdmissionRequest describes a create or update request evaluated by an admission scriptlet.
+type AdmissionRequest struct {
+	// Operation is either "create" or "update".
+	Operation string
+
+	// EntityType is the type of the entity being created or updated.
+	EntityType string
+
+	// Project is the project of the request.
+	Project string
+
+	// Name is the name of the entity. It can be empty when creating an instance with a generated name.
+	Name string
+
+	// Username and Protocol identify the caller.
+	Username string
+	Protocol string
+}
+
+// admissionRejection is returned by the reject builtin to stop the scriptlet and reject the request.
+type admissionRejection struct {
+	reason string
+}
+
+// Error implements the error interface.
+func (e *admissionRejection) Error() string {
+	return e.reason
+}
+
+// admissionPredeclared returns the builtins available to admission scriptlets.
+func admissionPredeclared(l logger.Logger) starlark.StringDict {
+	logFunc := func(log func(msg string, args ...logger.Ctx)) func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		return func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var msg string
+			err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &msg)
+			if err != nil {
+				return nil, err
+			}
+
+			log("Admission scriptlet: " + msg)
+
+			return starlark.None, nil
+		}
+	}
+
+	reject := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var reason string
+		err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &reason)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &admissionRejection{reason: reason}
+	}
+
+	return starlark.StringDict{
+		"log_info":  starlark.NewBuiltin("log_info", logFunc(l.Info)),
+		"log_warn":  starlark.NewBuiltin("log_warn", logFunc(l.Warn)),
+		"log_error": starlark.NewBuiltin("log_error", logFunc(l.Error)),
+		"reject":    starlark.NewBuiltin("reject", reject),
+	}
+}
+
+// admissionCompile runs the top level of the scriptlet and returns its admit function.
+func admissionCompile(thread *starlark.Thread, name string, src string, predeclared starlark.StringDict) (*starlark.Function, error) {
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, name, src, predeclared)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := globals["admit"]
+	if !ok {
+		return nil, errors.New("Scriptlet missing admit function")
+	}
+
+	admit, ok := value.(*starlark.Function)
+	if !ok || admit.NumParams() != 1 {
+		return nil, errors.New("The admit function of the scriptlet must take a single request argument")
+	}
+
+	return admit, nil
+}
+
+// AdmissionValidate checks that the admission scriptlet compiles and defines its admit function.
+func AdmissionValidate(src string) error {
+	thread := &starlark.Thread{Name: "admission"}
+	thread.SetMaxExecutionSteps(admissionMaxSteps)
+
+	_, err := admissionCompile(thread, "admission.star", src, admissionPredeclared(logger.Log))
+	if err != nil {
+		return fmt.Errorf("Invalid admission scriptlet: %w", err)
+	}
+
+	return nil
+}
+
+// AdmissionRun evaluates the admit function of the admission scriptlet against the request. The function is given
+// the request along with its spec, converted from its JSON representation, and can reject it by calling reject().
+// It can also change the spec, either in place or by returning a new one, in which case the given spec is replaced.
+func AdmissionRun(ctx context.Context, l logger.Logger, name string, src string, req AdmissionRequest, spec any) error {
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(_ *starlark.Thread, msg string) { l.Debug("Admission scriptlet: " + msg) },
+	}
+
+	thread.SetMaxExecutionSteps(admissionMaxSteps)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
+
+	admit, err := admissionCompile(thread, name+".star", src, admissionPredeclared(l))
+	if err != nil {
+		return api.StatusErrorf(http.StatusInternalServerError, "Failed loading %s admission scriptlet: %w", name, err)
+	}
+
+	specValue, err := specToStarlark(spec)
+	if err != nil {
+		return fmt.Errorf("Failed converting request for admission scriptlet: %w", err)
+	}
+
+	request := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"operation":   starlark.String(req.Operation),
+		"entity_type": starlark.String(req.EntityType),
+		"project":     starlark.String(req.Project),
+		"name":        starlark.String(req.Name),
+		"username":    starlark.String(req.Username),
+		"protocol":    starlark.String(req.Protocol),
+		"spec":        specValue,
+	})
+
+	result, err := starlark.Call(thread, admit, starlark.Tuple{request}, nil)
+	if err != nil {
+		var rejection *admissionRejection
+		if errors.As(err, &rejection) {
+			return api.StatusErrorf(http.StatusBadRequest, "Request rejected by %s admission scriptlet: %s", name, rejection.reason)
+		}
+
+		return api.StatusErrorf(http.StatusInternalServerError, "Failed running %s admission scriptlet: %w", name, err)
+	}
+
+	// Changes made in place to the spec apply unless a new spec is returned.
+	if result != starlark.None {
+		specValue = result
+	}
+
+	_, ok := specValue.(*starlark.Dict)
+	if !ok {
+		return api.StatusErrorf(http.StatusInternalServerError, "The %s admission scriptlet must return a dictionary or None, got %s", name, specValue.Type())
+	}
+
+	newSpec, err := fromStarlark(specValue)
+	if err != nil {
+		return api.StatusErrorf(http.StatusInternalServerError, "Invalid spec returned by %s admission scriptlet: %w", name, err)
+	}
+
+	data, err := json.Marshal(newSpec)
+	if err != nil {
+		return err
+	}
+
+	// Reset the spec so that the fields removed by the scriptlet are cleared.
+	specPtr := reflect.ValueOf(spec)
+	if specPtr.Kind() != reflect.Pointer {
+		return errors.New("Admission spec must be a pointer")
+	}
+
+	specPtr.Elem().SetZero()
+
+	err = json.Unmarshal(data, spec)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid spec returned by %s admission scriptlet: %w", name, err)
+	}
+
+	return nil
+}
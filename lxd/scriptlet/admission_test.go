@@ -0,0 +1,58 @@
+package scriptlet_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/scriptlet"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+const testAdmissionScriptlet = `
+def admit(request):
+    if not request.name.startswith("web-"):
+        reject("Instance names must start with web-")
+
+    request.spec["config"]["user.owner"] = request.username
+    request.spec["devices"].pop("gpu", None)
+`
+
+func TestAdmissionValidate(t *testing.T) {
+	assert.NoError(t, scriptlet.AdmissionValidate(testAdmissionScriptlet))
+	assert.Error(t, scriptlet.AdmissionValidate("x = 1"))
+	assert.Error(t, scriptlet.AdmissionValidate("def admit():\n    pass\n"))
+	assert.Error(t, scriptlet.AdmissionValidate("def admit(request)"))
+}
+
+func TestAdmissionRun(t *testing.T) {
+	newRequest := func() *api.InstancesPost {
+		return &api.InstancesPost{
+			InstancePut: api.InstancePut{
+				Config:  map[string]string{"limits.cpu": "2"},
+				Devices: map[string]map[string]string{"gpu": {"type": "gpu"}},
+			},
+		}
+	}
+
+	req := newRequest()
+	err := scriptlet.AdmissionRun(context.Background(), logger.Log, "server", testAdmissionScriptlet, scriptlet.AdmissionRequest{Name: "web-1", Username: "alice"}, req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"limits.cpu": "2", "user.owner": "alice"}, req.Config)
+	assert.Empty(t, req.Devices)
+
+	req = newRequest()
+	err = scriptlet.AdmissionRun(context.Background(), logger.Log, "server", testAdmissionScriptlet, scriptlet.AdmissionRequest{Name: "db-1"}, req)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+	assert.Contains(t, req.Devices, "gpu")
+
+	req = newRequest()
+	err = scriptlet.AdmissionRun(context.Background(), logger.Log, "project", "def admit(request):\n    return {\"config\": {}}\n", scriptlet.AdmissionRequest{}, req)
+	require.NoError(t, err)
+	assert.Empty(t, req.Config)
+	assert.Nil(t, req.Devices)
+}
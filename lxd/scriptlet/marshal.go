@@ -0,0 +1,146 @@
+package scriptlet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.starlark.net/starlark"
+)
+
+// toStarlark converts a value decoded from JSON into a Starlark value.
+func toStarlark(value any) (starlark.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case string:
+		return starlark.String(v), nil
+	case json.Number:
+		i, err := v.Int64()
+		if err == nil {
+			return starlark.MakeInt64(i), nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+
+		return starlark.Float(f), nil
+	case []any:
+		elems := make([]starlark.Value, 0, len(v))
+		for _, elem := range v {
+			sv, err := toStarlark(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			elems = append(elems, sv)
+		}
+
+		return starlark.NewList(elems), nil
+	case map[string]any:
+		d := starlark.NewDict(len(v))
+		for key, elem := range v {
+			sv, err := toStarlark(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			err = d.SetKey(starlark.String(key), sv)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return d, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported value type %T", value)
+}
+
+// fromStarlark converts a Starlark value into a value that can be encoded to JSON.
+func fromStarlark(value starlark.Value) (any, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, errors.New("Integer out of range")
+		}
+
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case *starlark.List:
+		result := make([]any, 0, v.Len())
+		for i := range v.Len() {
+			elem, err := fromStarlark(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, elem)
+		}
+
+		return result, nil
+	case starlark.Tuple:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			elem, err := fromStarlark(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, elem)
+		}
+
+		return result, nil
+	case *starlark.Dict:
+		result := make(map[string]any, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("Dictionary keys must be strings, got %s", item[0].Type())
+			}
+
+			elem, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for key %q: %w", key, err)
+			}
+
+			result[key] = elem
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported value type %s", value.Type())
+}
+
+// specToStarlark converts the JSON representation of the given spec into a Starlark dictionary.
+func specToStarlark(spec any) (starlark.Value, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStarlark(value)
+}
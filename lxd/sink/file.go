@@ -0,0 +1,128 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// File writes events as JSON lines to a local file, rotating it once it reaches a maximum size.
+type File struct {
+	*queue
+
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+// NewFile returns a sink writing the events selected by the filter to the file at the given path.
+// The file is rotated once it reaches maxSize bytes, keeping at most maxFiles rotated files.
+func NewFile(path string, maxSize int64, maxFiles int, filter Filter) (*File, error) {
+	f := &File{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+
+	err := f.open()
+	if err != nil {
+		return nil, err
+	}
+
+	f.queue = newQueue("file", filter, f.send)
+
+	return f, nil
+}
+
+// Stop stops the sink and closes the file.
+func (f *File) Stop() {
+	f.queue.stop()
+
+	if f.file != nil {
+		_ = f.file.Close()
+	}
+}
+
+// open opens the file for appending.
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed opening %q: %w", f.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("Failed getting size of %q: %w", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// rotate renames the current file to "<path>.1", shifting the previously rotated files, and opens a new file.
+func (f *File) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return err
+	}
+
+	if f.maxFiles > 0 {
+		for i := f.maxFiles - 1; i > 0; i-- {
+			err = os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		err = os.Rename(f.path, f.path+".1")
+	} else {
+		err = os.Remove(f.path)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return f.open()
+}
+
+func (f *File) send(event api.Event) error {
+	// Reopen the file if a previous rotation failed.
+	if f.file == nil {
+		err := f.open()
+		if err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+
+	if f.size > 0 && f.size+int64(len(line)) > f.maxSize {
+		err = f.rotate()
+		if err != nil {
+			return fmt.Errorf("Failed rotating %q: %w", f.path, err)
+		}
+	}
+
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("Failed writing to %q: %w", f.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestFile_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	f := &File{path: path, maxSize: 200, maxFiles: 2}
+	err := f.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = f.file.Close() }()
+
+	// Each event is about 100 bytes, so the file is rotated every two events.
+	for range 7 {
+		err := f.send(api.Event{Type: api.EventTypeLifecycle, Location: "node1", Project: "default"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Size() > f.maxSize {
+			t.Errorf("%q is larger than the maximum size: %d", name, info.Size())
+		}
+	}
+
+	_, err = os.Stat(path + ".3")
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected only 2 rotated files, got %v", err)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	filter := Filter{Types: []string{api.EventTypeLifecycle}, Projects: []string{"foo"}}
+
+	tests := []struct {
+		event api.Event
+		match bool
+	}{
+		{api.Event{Type: api.EventTypeLifecycle, Project: "foo"}, true},
+		{api.Event{Type: api.EventTypeLifecycle, Project: "bar"}, false},
+		{api.Event{Type: api.EventTypeLifecycle}, false},
+		{api.Event{Type: api.EventTypeLogging, Project: "foo"}, false},
+	}
+
+	for _, test := range tests {
+		if filter.Match(test.event) != test.match {
+			t.Errorf("Unexpected match result for %+v", test.event)
+		}
+	}
+
+	filter.Projects = nil
+	if !filter.Match(api.Event{Type: api.EventTypeLifecycle}) {
+		t.Error("Expected events that aren't project specific to match without project filter")
+	}
+}
@@ -0,0 +1,86 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// kafkaContentType is the content type of JSON records in the Kafka REST proxy v2 API.
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// Kafka produces events as JSON records to a Kafka topic through a Kafka REST proxy.
+type Kafka struct {
+	*queue
+
+	url    string
+	client *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string    `json:"key"`
+	Value api.Event `json:"value"`
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+// NewKafka returns a sink producing the events selected by the filter to the topic through the Kafka REST proxy
+// listening on the given URL. The records are keyed by the location of the event.
+func NewKafka(proxyURL string, topic string, filter Filter) (*Kafka, error) {
+	u, err := url.ParseRequestURI(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Kafka REST proxy URL %q: %w", proxyURL, err)
+	}
+
+	k := &Kafka{
+		url:    u.JoinPath("topics", topic).String(),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	k.queue = newQueue("kafka", filter, k.send)
+
+	return k, nil
+}
+
+// Stop stops the sink.
+func (k *Kafka) Stop() {
+	k.queue.stop()
+}
+
+func (k *Kafka) send(event api.Event) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: event.Location, Value: event}}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(k.cancel, http.MethodPost, k.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected status code %d from Kafka REST proxy", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestKafka_Send(t *testing.T) {
+	var records []kafkaRecords
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/kafka/topics/lxd-events" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		if r.Header.Get("Content-Type") != kafkaContentType {
+			t.Errorf("Unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+
+		req := kafkaRecords{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			t.Error(err)
+		}
+
+		records = append(records, req)
+		w.WriteHeader(status)
+	}))
+
+	defer server.Close()
+
+	k, err := NewKafka(server.URL+"/kafka", "lxd-events", Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer k.Stop()
+
+	event := api.Event{Type: api.EventTypeLifecycle, Location: "node1", Project: "foo"}
+	err = k.send(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 || len(records[0].Records) != 1 {
+		t.Fatalf("Expected a single record, got %+v", records)
+	}
+
+	record := records[0].Records[0]
+	if record.Key != "node1" || record.Value.Type != event.Type || record.Value.Project != event.Project {
+		t.Errorf("Unexpected record %+v", record)
+	}
+
+	// Errors of the proxy are reported, and the following events are still sent.
+	status = http.StatusInternalServerError
+	err = k.send(event)
+	if err == nil {
+		t.Error("Expected an error when the proxy fails")
+	}
+
+	status = http.StatusOK
+	err = k.send(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(records))
+	}
+}
+
+func TestKafka_Stop(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+
+	defer server.Close()
+	defer close(block)
+
+	k, err := NewKafka(server.URL, "lxd-events", Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- k.send(api.Event{Type: api.EventTypeLifecycle})
+	}()
+
+	// Stopping the sink interrupts the pending request.
+	k.Stop()
+
+	err = <-errCh
+	if err == nil {
+		t.Error("Expected the pending request to fail")
+	}
+}
+
+func TestNewKafka(t *testing.T) {
+	_, err := NewKafka("kafka-rest", "lxd-events", Filter{})
+	if err == nil {
+		t.Error("Expected an error for an invalid URL")
+	}
+}
@@ -0,0 +1,252 @@
+package sink
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// mqttTimeout is the timeout for connecting to the MQTT broker and for publishing an event.
+const mqttTimeout = 10 * time.Second
+
+// MQTT control packet types, as defined by MQTT 3.1.1.
+const (
+	mqttConnect    = 0x10
+	mqttConnack    = 0x20
+	mqttPublish    = 0x30
+	mqttDisconnect = 0xe0
+)
+
+// mqttConnackErrors are the reasons for which the broker refuses a connection, keyed by CONNACK return code.
+var mqttConnackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// MQTT publishes events as JSON messages to an MQTT broker, using MQTT 3.1.1 with QoS 0.
+type MQTT struct {
+	*queue
+
+	address   string
+	tlsConfig *tls.Config
+	clientID  string
+	username  string
+	password  string
+	topic     string
+
+	conn net.Conn
+}
+
+// NewMQTT returns a sink publishing the events selected by the filter to the MQTT broker at the given URL, either
+// `mqtt://host[:port]` or `mqtts://host[:port]` for TLS. The events are published to the
+// `<topic>/<project>/<event type>` topic, or to `<topic>/<event type>` for events that aren't project specific.
+func NewMQTT(brokerURL string, username string, password string, topic string, clientID string, filter Filter) (*MQTT, error) {
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid MQTT broker URL %q: %w", brokerURL, err)
+	}
+
+	if strings.ContainsAny(topic, "+#") {
+		return nil, fmt.Errorf("Invalid MQTT topic %q: Wildcards aren't allowed", topic)
+	}
+
+	m := &MQTT{
+		clientID: clientID,
+		username: username,
+		password: password,
+		topic:    strings.TrimSuffix(topic, "/"),
+	}
+
+	port := u.Port()
+	switch u.Scheme {
+	case "mqtt":
+		if port == "" {
+			port = "1883"
+		}
+
+	case "mqtts":
+		if port == "" {
+			port = "8883"
+		}
+
+		m.tlsConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+
+	default:
+		return nil, fmt.Errorf("Invalid MQTT broker URL %q: Scheme must be mqtt or mqtts", brokerURL)
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("Invalid MQTT broker URL %q: Missing host", brokerURL)
+	}
+
+	m.address = net.JoinHostPort(u.Hostname(), port)
+	m.queue = newQueue("mqtt", filter, m.send)
+
+	return m, nil
+}
+
+// Stop stops the sink and disconnects from the broker.
+func (m *MQTT) Stop() {
+	m.queue.stop()
+
+	if m.conn != nil {
+		_ = m.conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+		_, _ = m.conn.Write([]byte{mqttDisconnect, 0})
+		_ = m.conn.Close()
+	}
+}
+
+// eventTopic returns the topic the event is published to.
+func (m *MQTT) eventTopic(event api.Event) string {
+	if event.Project == "" {
+		return m.topic + "/" + event.Type
+	}
+
+	return m.topic + "/" + event.Project + "/" + event.Type
+}
+
+func (m *MQTT) send(event api.Event) error {
+	// Connect lazily, so that an unreachable broker doesn't prevent the sink from being set up.
+	if m.conn == nil {
+		err := m.connect()
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	packet := &bytes.Buffer{}
+	mqttWriteString(packet, m.eventTopic(event))
+	packet.Write(payload)
+
+	_ = m.conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+	_, err = m.conn.Write(mqttPacket(mqttPublish, packet.Bytes()))
+	if err != nil {
+		// Reconnect on the next event.
+		_ = m.conn.Close()
+		m.conn = nil
+
+		return err
+	}
+
+	return nil
+}
+
+// connect opens a clean session with the broker.
+func (m *MQTT) connect() error {
+	dialer := &net.Dialer{Timeout: mqttTimeout}
+
+	var conn net.Conn
+	var err error
+	if m.tlsConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: m.tlsConfig}
+		conn, err = tlsDialer.DialContext(m.cancel, "tcp", m.address)
+	} else {
+		conn, err = dialer.DialContext(m.cancel, "tcp", m.address)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Keep alive is disabled as events may be published less often than any sensible interval.
+	flags := byte(0x02)
+	payload := &bytes.Buffer{}
+	mqttWriteString(payload, m.clientID)
+
+	if m.username != "" {
+		flags |= 0x80
+		mqttWriteString(payload, m.username)
+
+		if m.password != "" {
+			flags |= 0x40
+			mqttWriteString(payload, m.password)
+		}
+	}
+
+	packet := &bytes.Buffer{}
+	mqttWriteString(packet, "MQTT")
+	packet.Write([]byte{4, flags, 0, 0})
+	packet.Write(payload.Bytes())
+
+	_ = conn.SetDeadline(time.Now().Add(mqttTimeout))
+
+	_, err = conn.Write(mqttPacket(mqttConnect, packet.Bytes()))
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	connack := make([]byte, 4)
+	_, err = io.ReadFull(conn, connack)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("Failed reading MQTT connection acknowledgement: %w", err)
+	}
+
+	if connack[0] != mqttConnack || connack[1] != 2 {
+		_ = conn.Close()
+		return errors.New("Unexpected response from MQTT broker")
+	}
+
+	if connack[3] != 0 {
+		_ = conn.Close()
+
+		reason, ok := mqttConnackErrors[connack[3]]
+		if !ok {
+			reason = fmt.Sprintf("return code %d", connack[3])
+		}
+
+		return fmt.Errorf("MQTT broker refused the connection: %s", reason)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	m.conn = conn
+
+	return nil
+}
+
+// mqttPacket returns the control packet of the given type with the given variable header and payload.
+func mqttPacket(packetType byte, body []byte) []byte {
+	packet := []byte{packetType}
+
+	// The remaining length is encoded 7 bits at a time, the high bit indicating that more bytes follow.
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+
+		packet = append(packet, b)
+		if length == 0 {
+			break
+		}
+	}
+
+	return append(packet, body...)
+}
+
+// mqttWriteString writes a length prefixed UTF-8 string.
+func mqttWriteString(buf *bytes.Buffer, s string) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(s)))
+	buf.WriteString(s)
+}
@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// readMQTTPacket reads a control packet and returns its type and body.
+func readMQTTPacket(t *testing.T, r io.Reader) (byte, []byte) {
+	t.Helper()
+
+	header := make([]byte, 1)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	length := 0
+	for multiplier := 1; ; multiplier *= 128 {
+		b := make([]byte, 1)
+		_, err := io.ReadFull(r, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		length += int(b[0]&0x7f) * multiplier
+		if b[0]&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return header[0], body
+}
+
+func TestMQTT_Send(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = listener.Close() }()
+
+	m, err := NewMQTT("mqtt://"+listener.Addr().String(), "user", "secret", "lxd/", "lxd-node1", Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer m.Stop()
+
+	events := []api.Event{
+		{Type: api.EventTypeLifecycle, Location: "node1", Project: "foo", Metadata: json.RawMessage(`{"action":"instance-started"}`)},
+		{Type: api.EventTypeLogging, Location: "node1", Metadata: json.RawMessage(`{"message":"hello"}`)},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		for _, event := range events {
+			err := m.send(event)
+			if err != nil {
+				errCh <- err
+				return
+			}
+		}
+
+		errCh <- nil
+	}()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	packetType, body := readMQTTPacket(t, conn)
+	if packetType != mqttConnect {
+		t.Fatalf("Expected CONNECT packet, got %#x", packetType)
+	}
+
+	// Protocol name and level, flags (clean session, user name and password) and keep alive.
+	if !bytes.Equal(body[:10], []byte{0, 4, 'M', 'Q', 'T', 'T', 4, 0xc2, 0, 0}) {
+		t.Fatalf("Unexpected CONNECT variable header %v", body[:10])
+	}
+
+	if !bytes.Contains(body, []byte("lxd-node1")) || !bytes.Contains(body, []byte("secret")) {
+		t.Fatal("Missing client identifier or credentials in CONNECT packet")
+	}
+
+	_, err = conn.Write([]byte{mqttConnack, 2, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, topic := range []string{"lxd/foo/lifecycle", "lxd/logging"} {
+		packetType, body := readMQTTPacket(t, conn)
+		if packetType != mqttPublish {
+			t.Fatalf("Expected PUBLISH packet, got %#x", packetType)
+		}
+
+		topicLength := int(body[0])<<8 | int(body[1])
+		if string(body[2:2+topicLength]) != topic {
+			t.Errorf("Expected topic %q, got %q", topic, body[2:2+topicLength])
+		}
+
+		event := api.Event{}
+		err := json.Unmarshal(body[2+topicLength:], &event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if event.Type != events[i].Type || event.Project != events[i].Project {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+
+	err = <-errCh
+	if err != nil {
+		t.Fatal(err)
+	}
+}
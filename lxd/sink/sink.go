@@ -0,0 +1,120 @@
+package sink
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/cancel"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// queueSize is the maximum number of events waiting to be sent to a sink.
+// Events are dropped if the sink can't keep up.
+const queueSize = 1000
+
+// Sink is an external target that events are forwarded to.
+type Sink interface {
+	// HandleEvent handles the event received from the internal event listener.
+	//
+	// Warn: This must not log, as it is called for logging events too.
+	HandleEvent(event api.Event)
+
+	// Stop stops the sink. Events which weren't sent yet are dropped.
+	Stop()
+}
+
+// Filter selects the events forwarded to a sink.
+type Filter struct {
+	// Types are the event types to forward.
+	Types []string
+
+	// Projects are the projects to forward the events of. If empty, the events of all projects and the events
+	// that aren't project specific are forwarded.
+	Projects []string
+}
+
+// Match returns whether the event is selected by the filter.
+func (f Filter) Match(event api.Event) bool {
+	if !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+
+	if len(f.Projects) > 0 && !slices.Contains(f.Projects, event.Project) {
+		return false
+	}
+
+	return true
+}
+
+// queue forwards the events selected by a filter to a send function, from a dedicated go routine so that slow
+// targets don't hold the event listener up.
+type queue struct {
+	name   string
+	filter Filter
+	send   func(api.Event) error
+
+	events chan api.Event
+	cancel cancel.Canceller
+	wg     sync.WaitGroup
+
+	// failing is only accessed from the queue go routine.
+	failing bool
+}
+
+// newQueue starts a queue sending the events selected by the filter with the send function.
+func newQueue(name string, filter Filter, send func(api.Event) error) *queue {
+	q := &queue{
+		name:   name,
+		filter: filter,
+		send:   send,
+		events: make(chan api.Event, queueSize),
+		cancel: cancel.New(),
+	}
+
+	q.wg.Add(1)
+	go q.run()
+
+	return q
+}
+
+// HandleEvent queues the event if it is selected by the filter.
+func (q *queue) HandleEvent(event api.Event) {
+	if !q.filter.Match(event) {
+		return
+	}
+
+	select {
+	case q.events <- event:
+	default:
+	}
+}
+
+// stop stops the queue go routine and waits for it to exit.
+func (q *queue) stop() {
+	q.cancel.Cancel()
+	q.wg.Wait()
+}
+
+func (q *queue) run() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.cancel.Done():
+			return
+		case event := <-q.events:
+			err := q.send(event)
+
+			// Only log when the sink starts or stops failing. As the failure is itself a logging event which
+			// may be forwarded to the failing sink, logging each failure would loop.
+			if err != nil && !q.failing {
+				q.failing = true
+				logger.Warn("Failed sending events to sink", logger.Ctx{"sink": q.name, "err": err})
+			} else if err == nil && q.failing {
+				q.failing = false
+				logger.Info("Resumed sending events to sink", logger.Ctx{"sink": q.name})
+			}
+		}
+	}
+}
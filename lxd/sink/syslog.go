@@ -0,0 +1,91 @@
+package sink
+
+import (
+	"encoding/json"
+	"log/syslog"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// syslogTag is the tag of the messages sent to the syslog server.
+const syslogTag = "lxd"
+
+// Syslog sends events as JSON messages to a remote syslog server.
+type Syslog struct {
+	*queue
+
+	protocol string
+	address  string
+
+	writer *syslog.Writer
+}
+
+// NewSyslog returns a sink sending the events selected by the filter to the syslog server listening on the given
+// address, using the given protocol (`udp` or `tcp`).
+func NewSyslog(protocol string, address string, filter Filter) (*Syslog, error) {
+	s := &Syslog{
+		protocol: protocol,
+		address:  address,
+	}
+
+	s.queue = newQueue("syslog", filter, s.send)
+
+	return s, nil
+}
+
+// Stop stops the sink and closes the connection to the syslog server.
+func (s *Syslog) Stop() {
+	s.queue.stop()
+
+	if s.writer != nil {
+		_ = s.writer.Close()
+	}
+}
+
+func (s *Syslog) send(event api.Event) error {
+	// Connect lazily, so that an unreachable server doesn't prevent the sink from being set up.
+	if s.writer == nil {
+		writer, err := syslog.Dial(s.protocol, s.address, syslog.LOG_INFO|syslog.LOG_DAEMON, syslogTag)
+		if err != nil {
+			return err
+		}
+
+		s.writer = writer
+	}
+
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Use the severity of logging events.
+	if event.Type == api.EventTypeLogging {
+		logEvent := api.EventLogging{}
+
+		err := json.Unmarshal(event.Metadata, &logEvent)
+		if err == nil {
+			switch logEvent.Level {
+			case "debug", "trace":
+				return s.writer.Debug(string(msg))
+			case "warning":
+				return s.writer.Warning(string(msg))
+			case "error":
+				return s.writer.Err(string(msg))
+			case "fatal", "panic":
+				return s.writer.Crit(string(msg))
+			}
+		}
+	}
+
+	// Firing alerts are sent as warnings.
+	if event.Type == api.EventTypeAlert {
+		alert := api.EventAlert{}
+
+		err := json.Unmarshal(event.Metadata, &alert)
+		if err == nil && alert.Status == api.AlertStatusFiring {
+			return s.writer.Warning(string(msg))
+		}
+	}
+
+	return s.writer.Info(string(msg))
+}
@@ -0,0 +1,152 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestSyslog_Send(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = listener.Close() }()
+
+	s, err := NewSyslog("tcp", listener.Addr().String(), Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Stop()
+
+	// The messages use the daemon facility with the severity of the event.
+	tests := []struct {
+		event    api.Event
+		priority string
+	}{
+		{event: api.Event{Type: api.EventTypeLifecycle, Project: "foo", Metadata: json.RawMessage(`{"action":"instance-started"}`)}, priority: "<30>"},
+		{event: api.Event{Type: api.EventTypeLogging, Metadata: json.RawMessage(`{"message":"hello","level":"debug"}`)}, priority: "<31>"},
+		{event: api.Event{Type: api.EventTypeLogging, Metadata: json.RawMessage(`{"message":"hello","level":"warning"}`)}, priority: "<28>"},
+		{event: api.Event{Type: api.EventTypeLogging, Metadata: json.RawMessage(`{"message":"hello","level":"error"}`)}, priority: "<27>"},
+		{event: api.Event{Type: api.EventTypeLogging, Metadata: json.RawMessage(`{"message":"hello","level":"info"}`)}, priority: "<30>"},
+		{event: api.Event{Type: api.EventTypeAlert, Metadata: json.RawMessage(`{"rule":"disk","status":"firing"}`)}, priority: "<28>"},
+		{event: api.Event{Type: api.EventTypeAlert, Metadata: json.RawMessage(`{"rule":"disk","status":"resolved"}`)}, priority: "<30>"},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		for _, test := range tests {
+			err := s.send(test.event)
+			if err != nil {
+				errCh <- err
+				return
+			}
+		}
+
+		errCh <- nil
+	}()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	// Messages sent over TCP are separated by new lines.
+	reader := bufio.NewReader(conn)
+	for _, test := range tests {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(line, test.priority) {
+			t.Errorf("Expected priority %q, got message %q", test.priority, line)
+		}
+
+		_, msg, found := strings.Cut(line, "]: ")
+		if !found || !strings.Contains(line, " "+syslogTag+"[") {
+			t.Fatalf("Unexpected message framing %q", line)
+		}
+
+		event := api.Event{}
+		err = json.Unmarshal([]byte(msg), &event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if event.Type != test.event.Type {
+			t.Errorf("Expected event type %q, got %q", test.event.Type, event.Type)
+		}
+	}
+
+	err = <-errCh
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyslog_Reconnect(t *testing.T) {
+	// Find a free address and leave it unused.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	s, err := NewSyslog("tcp", address, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Stop()
+
+	// The sink is set up even though the server is unreachable, and sending fails.
+	err = s.send(api.Event{Type: api.EventTypeLifecycle})
+	if err == nil {
+		t.Fatal("Expected an error when the server is unreachable")
+	}
+
+	// Once the server is reachable, the following events are sent.
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("Address %q was reused: %v", address, err)
+	}
+
+	defer func() { _ = listener.Close() }()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.send(api.Event{Type: api.EventTypeLifecycle})
+	}()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(line, `"type":"lifecycle"`) {
+		t.Errorf("Unexpected message %q", line)
+	}
+
+	err = <-errCh
+	if err != nil {
+		t.Fatal(err)
+	}
+}
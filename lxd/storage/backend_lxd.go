@@ -24,6 +24,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.yaml.in/yaml/v2"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sys/unix"
@@ -55,6 +57,7 @@ import (
 	"github.com/canonical/lxd/lxd/storage/memorypipe"
 	"github.com/canonical/lxd/lxd/storage/s3"
 	"github.com/canonical/lxd/lxd/storage/s3/miniod"
+	"github.com/canonical/lxd/lxd/tracing"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -137,6 +140,12 @@ func (b *lxdBackend) LocalStatus() string {
 	return db.StoragePoolStateToAPIStatus(node.State)
 }
 
+// startSpan starts the span of a storage operation as a child of the span of op, if any.
+func (b *lxdBackend) startSpan(op *operations.Operation, name string) trace.Span {
+	_, span := tracing.Start(op.TraceContext(), "storage."+name, attribute.String("lxd.storage.pool", b.name), attribute.String("lxd.storage.driver", b.driver.Info().Name))
+	return span
+}
+
 // isStatusReady returns an error if pool is not ready for use on this server.
 func (b *lxdBackend) isStatusReady() error {
 	if b.Status() == api.StoragePoolStatusPending {
@@ -273,6 +282,11 @@ func (b *lxdBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// GetIOStats returns the I/O statistics of the pool.
+func (b *lxdBackend) GetIOStats() (*drivers.IOStats, error) {
+	return b.driver.GetIOStats()
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *lxdBackend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, cluster.StoragePoolVolumeTypeNameImage)
@@ -656,6 +670,9 @@ func (b *lxdBackend) CreateInstance(inst instance.Instance, op *operations.Opera
 	l.Debug("CreateInstance started")
 	defer l.Debug("CreateInstance finished")
 
+	span := b.startSpan(op, "CreateInstance")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -724,6 +741,9 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	l.Debug("CreateInstanceFromBackup started")
 	defer l.Debug("CreateInstanceFromBackup finished")
 
+	span := b.startSpan(op, "CreateInstanceFromBackup")
+	defer span.End()
+
 	// Validate the names in the backup.yaml file as these could be malicious.
 	err := instancetype.ValidName(srcBackup.Name, false)
 	if err != nil {
@@ -1035,6 +1055,9 @@ func (b *lxdBackend) CreateInstanceFromCopy(inst instance.Instance, src instance
 	l.Debug("CreateInstanceFromCopy started")
 	defer l.Debug("CreateInstanceFromCopy finished")
 
+	span := b.startSpan(op, "CreateInstanceFromCopy")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -1223,7 +1246,8 @@ func (b *lxdBackend) CreateInstanceFromCopy(inst instance.Instance, src instance
 			}
 		}
 
-		ctx, cancel := context.WithCancel(context.Background())
+		// The pipes are closed once the cancellation of the operation is requested, stopping both sides.
+		ctx, cancel := context.WithCancel(op.Context())
 		defer cancel()
 
 		// Run sender and receiver in separate go routines to prevent deadlocks.
@@ -1278,6 +1302,12 @@ func (b *lxdBackend) CreateInstanceFromCopy(inst instance.Instance, src instance
 		}
 	}
 
+	// Last chance to remove the copy if the operation was cancelled.
+	err = op.Checkpoint()
+	if err != nil {
+		return err
+	}
+
 	revert.Success()
 	return nil
 }
@@ -1290,6 +1320,9 @@ func (b *lxdBackend) RefreshCustomVolume(projectName string, srcProjectName stri
 	l.Debug("RefreshCustomVolume started")
 	defer l.Debug("RefreshCustomVolume finished")
 
+	span := b.startSpan(op, "RefreshCustomVolume")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -1506,7 +1539,8 @@ func (b *lxdBackend) RefreshCustomVolume(projectName string, srcProjectName stri
 			}
 		}
 
-		ctx, cancel := context.WithCancel(context.Background())
+		// The pipes are closed once the cancellation of the operation is requested, stopping both sides.
+		ctx, cancel := context.WithCancel(op.Context())
 
 		// Use in-memory pipe pair to simulate a connection between the sender and receiver.
 		aEnd, bEnd := memorypipe.NewPipePair(ctx)
@@ -1586,6 +1620,9 @@ func (b *lxdBackend) RefreshInstance(inst instance.Instance, src instance.Instan
 	l.Debug("RefreshInstance started")
 	defer l.Debug("RefreshInstance finished")
 
+	span := b.startSpan(op, "RefreshInstance")
+	defer span.End()
+
 	// This indicates whether or not it's a volume-only refresh.
 	snapshots := len(srcSnapshots) > 0
 
@@ -1766,7 +1803,8 @@ func (b *lxdBackend) RefreshInstance(inst instance.Instance, src instance.Instan
 			return fmt.Errorf("Failed to negotiate copy migration type: %w", err)
 		}
 
-		ctx, cancel := context.WithCancel(context.Background())
+		// The pipes are closed once the cancellation of the operation is requested, stopping both sides.
+		ctx, cancel := context.WithCancel(op.Context())
 		defer cancel()
 
 		// Run sender and receiver in separate go routines to prevent deadlocks.
@@ -1834,6 +1872,7 @@ func (b *lxdBackend) imageFiller(fingerprint string, op *operations.Operation, p
 			tracker = &ioprogress.ProgressTracker{
 				Handler: func(percent, speed int64) {
 					shared.SetProgressMetadata(metadata, "create_instance_from_image_unpack", "Unpacking image", percent, 0, speed)
+					metadata[api.OperationProgressMetadataKey] = op.RecordProgress("create_instance_from_image_unpack", ioprogress.ProgressData{Percentage: int(percent), Speed: speed})
 					_ = op.UpdateMetadata(metadata)
 				}}
 		}
@@ -1882,6 +1921,7 @@ func (b *lxdBackend) imageConversionFiller(imgPath string, imgFormat string, op
 				Handler: func(percent, speed int64) {
 					displayPrefix := "Converting image format from " + imgFormat + " to raw"
 					shared.SetProgressMetadata(metadata, "format_progress", displayPrefix, percent, 0, speed)
+					metadata[api.OperationProgressMetadataKey] = op.RecordProgress("format", ioprogress.ProgressData{Percentage: int(percent), Speed: speed})
 					_ = op.UpdateMetadata(metadata)
 				},
 			}
@@ -2024,6 +2064,9 @@ func (b *lxdBackend) CreateInstanceFromImage(inst instance.Instance, fingerprint
 	l.Debug("CreateInstanceFromImage started")
 	defer l.Debug("CreateInstanceFromImage finished")
 
+	span := b.startSpan(op, "CreateInstanceFromImage")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -2169,6 +2212,76 @@ func (b *lxdBackend) CreateInstanceFromImage(inst instance.Instance, fingerprint
 	return nil
 }
 
+// ReimageInstance replaces the content of the instance's existing root volume with the image's one.
+// Unlike re-creating the volume, this keeps the volume's snapshots.
+func (b *lxdBackend) ReimageInstance(inst instance.Instance, fingerprint string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "fingerprint": fingerprint})
+	l.Debug("ReimageInstance started")
+	defer l.Debug("ReimageInstance finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if inst.IsSnapshot() {
+		return errors.New("Instance must not be a snapshot")
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return err
+	}
+
+	fill := b.imageFiller(fingerprint, op, inst.Project().Name)
+
+	err = vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		// Remove the existing content (the root filesystem of containers or the config volume of VMs).
+		entries, err := os.ReadDir(mountPath)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			err = os.RemoveAll(filepath.Join(mountPath, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("Failed removing %q: %w", entry.Name(), err)
+			}
+		}
+
+		// VM images are converted onto the existing root block device.
+		rootBlockPath := ""
+		if vol.IsVMBlock() {
+			rootBlockPath, err = b.driver.GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = fill(vol, rootBlockPath, false)
+		return err
+	}, op)
+	if err != nil {
+		return fmt.Errorf("Failed replacing instance volume content: %w", err)
+	}
+
+	return inst.DeferTemplateApply(instance.TemplateTriggerCreate)
+}
+
 // CreateInstanceFromMigration receives an instance being migrated.
 // The args.Name and args.Config fields are ignored and, instance properties are used instead.
 func (b *lxdBackend) CreateInstanceFromMigration(inst instance.Instance, conn io.ReadWriteCloser, args migration.VolumeTargetArgs, op *operations.Operation) error {
@@ -2176,6 +2289,9 @@ func (b *lxdBackend) CreateInstanceFromMigration(inst instance.Instance, conn io
 	l.Debug("CreateInstanceFromMigration started")
 	defer l.Debug("CreateInstanceFromMigration finished")
 
+	span := b.startSpan(op, "CreateInstanceFromMigration")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -2496,6 +2612,9 @@ func (b *lxdBackend) CreateInstanceFromConversion(inst instance.Instance, conn i
 	l.Debug("CreateInstanceFromConversion started")
 	defer l.Debug("CreateInstanceFromConversion finished")
 
+	span := b.startSpan(op, "CreateInstanceFromConversion")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -2887,12 +3006,132 @@ func (b *lxdBackend) RenameInstance(inst instance.Instance, newName string, op *
 	return nil
 }
 
+// MoveInstanceProject moves the instance's root volume and its snapshots to another project.
+// The instance must be stopped. Returns a revert hook that moves the volume back to the instance's project.
+func (b *lxdBackend) MoveInstanceProject(inst instance.Instance, newProjectName string, op *operations.Operation) (revert.Hook, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "newProject": newProjectName})
+	l.Debug("MoveInstanceProject started")
+	defer l.Debug("MoveInstanceProject finished")
+
+	if inst.IsSnapshot() {
+		return nil, errors.New("Instance cannot be a snapshot")
+	}
+
+	if inst.IsRunning() {
+		return nil, errors.New("Instance must be stopped to be moved to another project")
+	}
+
+	// Check we can convert the instance to the volume types needed.
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	volDBType, err := VolumeTypeToDBType(volType)
+	if err != nil {
+		return nil, err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	volume, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []string
+
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Get any snapshots the instance has in the format <instance name>/<snapshot name>.
+		snapshots, err = tx.GetInstanceSnapshotsNames(ctx, inst.Project().Name, inst.Name())
+		if err != nil {
+			return err
+		}
+
+		// Move the volume DB record, its snapshot records follow it.
+		return tx.UpdateStoragePoolVolumeProject(ctx, inst.Project().Name, inst.Name(), volDBType, b.ID(), newProjectName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateStoragePoolVolumeProject(ctx, newProjectName, inst.Name(), volDBType, b.ID(), inst.Project().Name)
+		})
+	})
+
+	// Rename the volume and its snapshots on the storage device.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	newVolStorageName := project.Instance(newProjectName, inst.Name())
+	contentType := InstanceContentType(inst)
+
+	vol := b.GetVolume(volType, contentType, volStorageName, volume.Config)
+
+	err = b.driver.RenameVolume(vol, newVolStorageName, op)
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		// Renaming a volume doesn't change its UUID.
+		// Pass the same configuration as for the initial rename operation.
+		newVol := b.GetVolume(volType, contentType, newVolStorageName, volume.Config)
+		_ = b.driver.RenameVolume(newVol, volStorageName, op)
+	})
+
+	// Remove old instance symlink and create new one.
+	err = b.removeInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), drivers.GetVolumeMountPath(b.name, volType, volStorageName))
+	})
+
+	err = b.ensureInstanceSymlink(inst.Type(), newProjectName, inst.Name(), drivers.GetVolumeMountPath(b.name, volType, newVolStorageName))
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = b.removeInstanceSymlink(inst.Type(), newProjectName, inst.Name())
+	})
+
+	// Remove old instance snapshot symlink and create a new one if needed.
+	err = b.removeInstanceSnapshotSymlinkIfUnused(inst.Type(), inst.Project().Name, inst.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) > 0 {
+		revert.Add(func() {
+			_ = b.removeInstanceSnapshotSymlinkIfUnused(inst.Type(), newProjectName, inst.Name())
+			_ = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
+		})
+
+		err = b.ensureInstanceSnapshotSymlink(inst.Type(), newProjectName, inst.Name())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cleanup := revert.Clone().Fail
+	revert.Success()
+	return cleanup, nil
+}
+
 // DeleteInstance removes the instance's root volume (all snapshots need to be removed first).
 func (b *lxdBackend) DeleteInstance(inst instance.Instance, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 	l.Debug("DeleteInstance started")
 	defer l.Debug("DeleteInstance finished")
 
+	span := b.startSpan(op, "DeleteInstance")
+	defer span.End()
+
 	if inst.IsSnapshot() {
 		return errors.New("Instance must not be a snapshot")
 	}
@@ -3087,6 +3326,9 @@ func (b *lxdBackend) MigrateInstance(inst instance.Instance, conn io.ReadWriteCl
 	l.Debug("MigrateInstance started")
 	defer l.Debug("MigrateInstance finished")
 
+	span := b.startSpan(op, "MigrateInstance")
+	defer span.End()
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -3296,6 +3538,9 @@ func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancew
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	span := b.startSpan(op, "BackupInstance")
+	defer span.End()
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -3636,6 +3881,9 @@ func (b *lxdBackend) CreateInstanceSnapshot(inst instance.Instance, src instance
 	l.Debug("CreateInstanceSnapshot started")
 	defer l.Debug("CreateInstanceSnapshot finished")
 
+	span := b.startSpan(op, "CreateInstanceSnapshot")
+	defer span.End()
+
 	if inst.Type() != src.Type() {
 		return errors.New("Instance types must match")
 	}
@@ -3901,6 +4149,9 @@ func (b *lxdBackend) RestoreInstanceSnapshot(inst instance.Instance, src instanc
 	l.Debug("RestoreInstanceSnapshot started")
 	defer l.Debug("RestoreInstanceSnapshot finished")
 
+	span := b.startSpan(op, "RestoreInstanceSnapshot")
+	defer span.End()
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -4157,6 +4408,9 @@ func (b *lxdBackend) EnsureImage(fingerprint string, op *operations.Operation, p
 	l.Debug("EnsureImage started")
 	defer l.Debug("EnsureImage finished")
 
+	span := b.startSpan(op, "EnsureImage")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -5361,6 +5615,9 @@ func (b *lxdBackend) CreateCustomVolume(projectName string, volName string, desc
 	l.Debug("CreateCustomVolume started")
 	defer l.Debug("CreateCustomVolume finished")
 
+	span := b.startSpan(op, "CreateCustomVolume")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -5411,6 +5668,9 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectNa
 	l.Debug("CreateCustomVolumeFromCopy started")
 	defer l.Debug("CreateCustomVolumeFromCopy finished")
 
+	span := b.startSpan(op, "CreateCustomVolumeFromCopy")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -5600,7 +5860,8 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectNa
 		}
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// The pipes are closed once the cancellation of the operation is requested, stopping both sides.
+	ctx, cancel := context.WithCancel(op.Context())
 
 	// Use in-memory pipe pair to simulate a connection between the sender and receiver.
 	aEnd, bEnd := memorypipe.NewPipePair(ctx)
@@ -5782,6 +6043,9 @@ func (b *lxdBackend) MigrateCustomVolume(projectName string, conn io.ReadWriteCl
 	l.Debug("MigrateCustomVolume started")
 	defer l.Debug("MigrateCustomVolume finished")
 
+	span := b.startSpan(op, "MigrateCustomVolume")
+	defer span.End()
+
 	// Get the volume name on storage.
 	volStorageName := project.StorageVolume(projectName, args.Name)
 
@@ -5853,6 +6117,9 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(projectName string, conn io
 	l.Debug("CreateCustomVolumeFromMigration started")
 	defer l.Debug("CreateCustomVolumeFromMigration finished")
 
+	span := b.startSpan(op, "CreateCustomVolumeFromMigration")
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -6451,6 +6718,9 @@ func (b *lxdBackend) DeleteCustomVolume(projectName string, volName string, op *
 	l.Debug("DeleteCustomVolume started")
 	defer l.Debug("DeleteCustomVolume finished")
 
+	span := b.startSpan(op, "DeleteCustomVolume")
+	defer span.End()
+
 	if shared.IsSnapshot(volName) {
 		return errors.New("Volume name cannot be a snapshot")
 	}
@@ -8082,6 +8352,9 @@ func (b *lxdBackend) ImportInstance(inst instance.Instance, poolVol *backupConfi
 	l.Debug("ImportInstance started")
 	defer l.Debug("ImportInstance finished")
 
+	span := b.startSpan(op, "ImportInstance")
+	defer span.End()
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return nil, err
@@ -8341,6 +8614,9 @@ func (b *lxdBackend) CreateCustomVolumeFromISO(projectName string, volName strin
 	l.Debug("CreateCustomVolumeFromISO started")
 	defer l.Debug("CreateCustomVolumeFromISO finished")
 
+	span := b.startSpan(op, "CreateCustomVolumeFromISO")
+	defer span.End()
+
 	// Validate the name of the volume as this could be malicious.
 	err := drivers.ValidVolumeName(volName)
 	if err != nil {
@@ -8421,6 +8697,9 @@ func (b *lxdBackend) CreateCustomVolumeFromTarball(projectName string, volName s
 	l.Debug("CreateCustomVolumeFromTarball started")
 	defer l.Debug("CreateCustomVolumeFromTarball finished")
 
+	span := b.startSpan(op, "CreateCustomVolumeFromTarball")
+	defer span.End()
+
 	// Validate the name of the volume as this could be malicious.
 	err := drivers.ValidVolumeName(volName)
 	if err != nil {
@@ -8519,6 +8798,9 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData
 	l.Debug("CreateCustomVolumeFromBackup started")
 	defer l.Debug("CreateCustomVolumeFromBackup finished")
 
+	span := b.startSpan(op, "CreateCustomVolumeFromBackup")
+	defer span.End()
+
 	if srcBackup.Config == nil {
 		return errors.New("Valid volume config not found in index")
 	}
@@ -88,6 +88,11 @@ func (b *mockBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+// GetIOStats ...
+func (b *mockBackend) GetIOStats() (*drivers.IOStats, error) {
+	return nil, nil
+}
+
 // IsUsed ...
 func (b *mockBackend) IsUsed() (bool, error) {
 	return false, nil
@@ -148,6 +153,11 @@ func (b *mockBackend) CreateInstanceFromImage(inst instance.Instance, fingerprin
 	return nil
 }
 
+// ReimageInstance ...
+func (b *mockBackend) ReimageInstance(inst instance.Instance, fingerprint string, op *operations.Operation) error {
+	return nil
+}
+
 // CreateInstanceFromMigration ...
 func (b *mockBackend) CreateInstanceFromMigration(inst instance.Instance, conn io.ReadWriteCloser, args migration.VolumeTargetArgs, op *operations.Operation) error {
 	return nil
@@ -163,6 +173,11 @@ func (b *mockBackend) RenameInstance(inst instance.Instance, newName string, op
 	return nil
 }
 
+// MoveInstanceProject ...
+func (b *mockBackend) MoveInstanceProject(inst instance.Instance, newProjectName string, op *operations.Operation) (revert.Hook, error) {
+	return nil, nil
+}
+
 // DeleteInstance ...
 func (b *mockBackend) DeleteInstance(inst instance.Instance, op *operations.Operation) error {
 	return nil
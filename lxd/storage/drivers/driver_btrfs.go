@@ -17,6 +17,7 @@ import (
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
@@ -351,6 +352,22 @@ func (d *btrfs) Validate(config map[string]string) error {
 		//  shortdesc: Mount options for block devices
 		//  scope: global
 		"btrfs.mount_options": validate.IsAny,
+		// lxdmeta:generate(entities=storage-btrfs; group=pool-conf; key=btrfs.quotas)
+		// When enabled, quotas are turned on for the file system when the pool is mounted, so that
+		// the reported volume usage comes from the quota groups rather than only being available
+		// for volumes with a `size` limit.
+		// A background rescan is started whenever the quota accounting is found to be inconsistent,
+		// during which the volume usage is not reported.
+		//
+		// Setting this option to `false` turns off quotas for the file system, which is refused while any volume
+		// has a `size` limit. While it is `false`, size limits can't be set on file system volumes or container root disks.
+		// Unsetting it leaves quotas as they are.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to use quota groups for usage reporting
+		//  scope: global
+		"btrfs.quotas": validate.Optional(validate.IsBool),
 	}
 
 	return d.validatePool(config, rules, nil)
@@ -377,6 +394,22 @@ func (d *btrfs) Update(changedConfig map[string]string) error {
 		}
 	}
 
+	// Quotas are left as they are when the key gets unset, as they may still be used by size limits.
+	val, ok = changedConfig["btrfs.quotas"]
+	if ok && !d.state.OS.RunningInUserNS {
+		if shared.IsTrue(val) {
+			err := d.enableQuotas()
+			if err != nil {
+				return err
+			}
+		} else if shared.IsFalse(val) {
+			err := d.disableQuotas()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	size, ok := changedConfig["size"]
 	if ok {
 		// Figure out loop path
@@ -424,6 +457,24 @@ func (d *btrfs) Update(changedConfig map[string]string) error {
 
 // Mount mounts the storage pool.
 func (d *btrfs) Mount() (bool, error) {
+	ourMount, err := d.mount()
+	if err != nil {
+		return false, err
+	}
+
+	// Enable quota groups once the pool is mounted if requested.
+	if ourMount && d.quotasEnabled() {
+		err = d.enableQuotas()
+		if err != nil {
+			d.logger.Warn("Failed enabling quotas", logger.Ctx{"err": err})
+		}
+	}
+
+	return ourMount, nil
+}
+
+// mount mounts the storage pool's file system.
+func (d *btrfs) mount() (bool, error) {
 	// Check if already mounted.
 	if filesystem.IsMountPoint(GetPoolMountPath(d.name)) {
 		return false, nil
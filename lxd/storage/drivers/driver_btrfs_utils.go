@@ -14,6 +14,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/google/uuid"
@@ -36,6 +38,18 @@ var errBtrfsNoQGroup = errors.New("Unable to find quota group")
 // btrfsISOVolSuffix suffix used for iso content type volumes.
 const btrfsISOVolSuffix = ".iso"
 
+// btrfsQuotaRescanCheckInterval is how long the state of the qgroup rescan of a pool is cached for.
+const btrfsQuotaRescanCheckInterval = 10 * time.Second
+
+// btrfsQuotaRescan is the cached state of the qgroup rescan of a pool.
+type btrfsQuotaRescan struct {
+	checkedAt time.Time
+	running   bool
+}
+
+var btrfsQuotaRescans = map[string]btrfsQuotaRescan{}
+var btrfsQuotaRescansMu sync.Mutex
+
 // setReceivedUUID sets the "Received UUID" field on a subvolume with the given path using ioctl.
 func setReceivedUUID(path string, UUID string) error {
 	type btrfsIoctlReceivedSubvolArgs struct {
@@ -305,13 +319,28 @@ func (d *btrfs) deleteSubvolume(rootPath string, recursion bool) error {
 	return nil
 }
 
+// getQGroup returns the qgroup identifier of the subvolume at path along with its referenced usage.
+// The usage is -1 if the qgroup accounting isn't currently reliable, in which case a rescan is triggered.
 func (d *btrfs) getQGroup(path string) (string, int64, error) {
 	// Try to get the qgroup details.
-	output, err := shared.RunCommandContext(context.TODO(), "btrfs", "qgroup", "show", "-e", "-f", "--raw", path)
+	output, stderr, err := shared.RunCommandSplit(context.TODO(), nil, nil, "btrfs", "qgroup", "show", "-e", "-f", "--raw", path)
 	if err != nil {
 		return "", -1, errBtrfsNoQuota
 	}
 
+	// The accounting is stale until a rescan completes.
+	reliable := true
+	if strings.Contains(strings.ToLower(stderr), "inconsistent") {
+		reliable = false
+
+		err = d.quotaRescan()
+		if err != nil {
+			d.logger.Warn("Failed starting quota rescan", logger.Ctx{"err": err})
+		}
+	} else if d.quotaRescanRunning() {
+		reliable = false
+	}
+
 	// Parse to extract the qgroup identifier.
 	var qgroup string
 	usage := int64(-1)
@@ -330,7 +359,7 @@ func (d *btrfs) getQGroup(path string) (string, int64, error) {
 
 		qgroup = fields[0]
 		val, err := strconv.ParseInt(fields[2], 10, 64)
-		if err == nil {
+		if err == nil && reliable {
 			usage = val
 		}
 
@@ -344,6 +373,123 @@ func (d *btrfs) getQGroup(path string) (string, int64, error) {
 	return qgroup, usage, nil
 }
 
+// quotasEnabled returns whether qgroup based usage reporting is enabled for the pool.
+func (d *btrfs) quotasEnabled() bool {
+	return shared.IsTrue(d.config["btrfs.quotas"]) && !d.state.OS.RunningInUserNS
+}
+
+// quotasDisabled returns whether quotas were explicitly disabled for the pool, in which case no size limit can be applied.
+func (d *btrfs) quotasDisabled() bool {
+	return shared.IsFalse(d.config["btrfs.quotas"])
+}
+
+// enableQuotas enables quotas on the pool and starts a background rescan so that the usage of
+// existing subvolumes gets accounted for.
+func (d *btrfs) enableQuotas() error {
+	poolPath := GetPoolMountPath(d.name)
+
+	_, err := shared.RunCommandContext(context.TODO(), "btrfs", "quota", "enable", poolPath)
+	if err != nil {
+		return fmt.Errorf("Failed enabling quotas on %q: %w", poolPath, err)
+	}
+
+	return d.quotaRescan()
+}
+
+// disableQuotas disables quotas on the pool, removing all qgroups.
+// It fails if any qgroup has a size limit, as disabling quotas would silently lift it.
+func (d *btrfs) disableQuotas() error {
+	poolPath := GetPoolMountPath(d.name)
+
+	limited, err := d.quotaLimitsSet()
+	if err != nil {
+		return err
+	}
+
+	if limited {
+		return errors.New("Quotas can't be disabled while volumes have a size limit")
+	}
+
+	_, err = shared.RunCommandContext(context.TODO(), "btrfs", "quota", "disable", poolPath)
+	if err != nil {
+		return fmt.Errorf("Failed disabling quotas on %q: %w", poolPath, err)
+	}
+
+	return nil
+}
+
+// quotaRescan starts a qgroup rescan of the pool in the background.
+// The kernel performs the rescan asynchronously so this returns immediately.
+func (d *btrfs) quotaRescan() error {
+	// Don't start a new rescan while one is still running.
+	if d.quotaRescanRunning() {
+		return nil
+	}
+
+	_, err := shared.RunCommandContext(context.TODO(), "btrfs", "quota", "rescan", GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	btrfsQuotaRescansMu.Lock()
+	btrfsQuotaRescans[d.name] = btrfsQuotaRescan{checkedAt: time.Now(), running: true}
+	btrfsQuotaRescansMu.Unlock()
+
+	d.logger.Debug("Started quota rescan")
+	return nil
+}
+
+// quotaRescanRunning returns whether a qgroup rescan is in progress on the pool.
+// The state is cached for a while, as it's checked for each volume when reporting their usage.
+func (d *btrfs) quotaRescanRunning() bool {
+	btrfsQuotaRescansMu.Lock()
+	defer btrfsQuotaRescansMu.Unlock()
+
+	rescan, ok := btrfsQuotaRescans[d.name]
+	if ok && time.Since(rescan.checkedAt) < btrfsQuotaRescanCheckInterval {
+		return rescan.running
+	}
+
+	output, err := shared.RunCommandContext(context.TODO(), "btrfs", "quota", "rescan", "-s", GetPoolMountPath(d.name))
+	running := err == nil && strings.Contains(output, "operation running")
+
+	btrfsQuotaRescans[d.name] = btrfsQuotaRescan{checkedAt: time.Now(), running: running}
+
+	return running
+}
+
+// quotaLimitsSet returns whether any qgroup of the pool has a size limit.
+func (d *btrfs) quotaLimitsSet() (bool, error) {
+	output, err := shared.RunCommandContext(context.TODO(), "btrfs", "qgroup", "show", "-r", "--raw", GetPoolMountPath(d.name))
+	if err != nil {
+		// Quotas are already disabled.
+		return false, nil
+	}
+
+	return btrfsQGroupLimitsSet(output), nil
+}
+
+// btrfsQGroupLimitsSet returns whether the output of "btrfs qgroup show -r" has any qgroup with a size limit.
+func btrfsQGroupLimitsSet(output string) bool {
+	for line := range strings.SplitSeq(output, "\n") {
+		if line == "" || strings.HasPrefix(strings.ToLower(line), "qgroupid") || strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		// The fourth column is the referenced size limit.
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		if fields[3] != "none" && fields[3] != "0" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (d *btrfs) sendSubvolume(path string, parent string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker) error {
 	defer func() { _ = conn.Close() }()
 
@@ -0,0 +1,144 @@
+package drivers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/validate"
+)
+
+func Test_btrfs_Validate(t *testing.T) {
+	d := &btrfs{common: common{commonRules: &Validators{
+		PoolRules: func() map[string]func(string) error { return map[string]func(string) error{"source": validate.IsAny} },
+	}}}
+
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr bool
+	}{
+		{name: "Quotas unset", config: map[string]string{}},
+		{name: "Quotas enabled", config: map[string]string{"btrfs.quotas": "true"}},
+		{name: "Quotas disabled", config: map[string]string{"btrfs.quotas": "false"}},
+		{name: "Invalid quotas value", config: map[string]string{"btrfs.quotas": "sometimes"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.Validate(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_btrfs_ValidateVolume(t *testing.T) {
+	volumeRules := func(vol Volume) map[string]func(string) error {
+		return map[string]func(string) error{"size": validate.Optional(validate.IsSize)}
+	}
+
+	tests := []struct {
+		name       string
+		poolConfig map[string]string
+		vol        Volume
+		wantErr    bool
+	}{
+		{
+			name:       "Size with quotas unset",
+			poolConfig: map[string]string{},
+			vol:        Volume{volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{"size": "1GiB"}},
+		},
+		{
+			name:       "Size with quotas enabled",
+			poolConfig: map[string]string{"btrfs.quotas": "true"},
+			vol:        Volume{volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{"size": "1GiB"}},
+		},
+		{
+			name:       "Size with quotas disabled",
+			poolConfig: map[string]string{"btrfs.quotas": "false"},
+			vol:        Volume{volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{"size": "1GiB"}},
+			wantErr:    true,
+		},
+		{
+			name:       "No size with quotas disabled",
+			poolConfig: map[string]string{"btrfs.quotas": "false"},
+			vol:        Volume{volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{}},
+		},
+		{
+			name:       "Block volume size with quotas disabled",
+			poolConfig: map[string]string{"btrfs.quotas": "false"},
+			vol:        Volume{volType: VolumeTypeCustom, contentType: ContentTypeBlock, config: map[string]string{"size": "1GiB"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &btrfs{common: common{config: tt.poolConfig, commonRules: &Validators{VolumeRules: volumeRules}}}
+
+			err := d.ValidateVolume(tt.vol, false)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_btrfsQGroupLimitsSet(t *testing.T) {
+	header := "qgroupid         rfer         excl     max_rfer     max_excl\n--------         ----         ----     --------     --------\n"
+
+	tests := []struct {
+		name    string
+		output  string
+		limited bool
+	}{
+		{name: "No qgroups", output: header},
+		{name: "No limits", output: header + "0/256        16384        16384         none         none\n0/257        16384        16384         none         none\n"},
+		{name: "Zero limit", output: header + "0/256        16384        16384            0         none\n"},
+		{name: "Limit on one qgroup", output: header + "0/256        16384        16384         none         none\n0/257        16384        16384   1073741824         none\n", limited: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.limited, btrfsQGroupLimitsSet(tt.output))
+		})
+	}
+}
+
+func Test_btrfs_quotaRescanRunning(t *testing.T) {
+	d := &btrfs{common: common{name: "test-rescan"}}
+
+	t.Cleanup(func() {
+		btrfsQuotaRescansMu.Lock()
+		delete(btrfsQuotaRescans, d.name)
+		btrfsQuotaRescansMu.Unlock()
+	})
+
+	// A recently checked state is returned without querying the file system.
+	for _, running := range []bool{true, false} {
+		btrfsQuotaRescansMu.Lock()
+		btrfsQuotaRescans[d.name] = btrfsQuotaRescan{checkedAt: time.Now(), running: running}
+		btrfsQuotaRescansMu.Unlock()
+
+		assert.Equal(t, running, d.quotaRescanRunning())
+	}
+
+	// An outdated state is refreshed, and the rescan isn't reported as running when it can't be queried.
+	btrfsQuotaRescansMu.Lock()
+	btrfsQuotaRescans[d.name] = btrfsQuotaRescan{checkedAt: time.Now().Add(-btrfsQuotaRescanCheckInterval), running: true}
+	btrfsQuotaRescansMu.Unlock()
+
+	assert.False(t, d.quotaRescanRunning())
+
+	btrfsQuotaRescansMu.Lock()
+	rescan := btrfsQuotaRescans[d.name]
+	btrfsQuotaRescansMu.Unlock()
+
+	assert.WithinDuration(t, time.Now(), rescan.checkedAt, btrfsQuotaRescanCheckInterval)
+}
@@ -883,7 +883,17 @@ func (d *btrfs) HasVolume(vol Volume) (bool, error) {
 
 // ValidateVolume validates the supplied volume config.
 func (d *btrfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
-	return d.validateVolume(vol, nil, removeUnknownKeys)
+	err := d.validateVolume(vol, nil, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	// Size limits of filesystem volumes rely on quotas.
+	if vol.contentType == ContentTypeFS && vol.config["size"] != "" && d.quotasDisabled() {
+		return fmt.Errorf("Volume %q property can't be set when %q is disabled", "size", "btrfs.quotas")
+	}
+
+	return nil
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -911,6 +921,12 @@ func (d *btrfs) GetVolumeUsage(vol Volume) (int64, error) {
 		return -1, err
 	}
 
+	// The qgroup accounting is being rebuilt, usage will be available once the rescan completes.
+	if usage < 0 {
+		d.logger.Debug("Volume usage unavailable while quota rescan is in progress", logger.Ctx{"volName": vol.name})
+		return -1, ErrNotSupported
+	}
+
 	return usage, nil
 }
 
@@ -971,6 +987,15 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 				return nil
 			}
 
+			if d.quotasDisabled() {
+				// The filesystem volume of virtual machines is only limited on a best effort basis.
+				if vol.volType == VolumeTypeVM {
+					return nil
+				}
+
+				return fmt.Errorf("Size limits can't be applied when %q is disabled", "btrfs.quotas")
+			}
+
 			path := GetPoolMountPath(d.name)
 
 			_, err = shared.RunCommandContext(context.TODO(), "btrfs", "quota", "enable", path)
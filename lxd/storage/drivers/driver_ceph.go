@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"slices"
 	"strconv"
@@ -546,6 +547,40 @@ func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetIOStats returns the I/O statistics of the RBD devices of the OSD pool mapped on this server.
+// This is the latency seen by the instances, including the network round trips to the OSDs.
+func (d *ceph) GetIOStats() (*IOStats, error) {
+	files, err := os.ReadDir("/sys/devices/rbd")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var devPaths []string
+	for _, f := range files {
+		// Skip if not a device directory.
+		_, err := strconv.ParseUint(f.Name(), 10, 64)
+		if err != nil || !f.IsDir() {
+			continue
+		}
+
+		devPoolName, err := os.ReadFile(fmt.Sprintf("/sys/devices/rbd/%s/pool", f.Name()))
+		if err != nil {
+			// Skip if no pool file, the device may have been unmapped in the meantime.
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if strings.TrimSpace(string(devPoolName)) == d.config["ceph.osd.pool_name"] {
+			devPaths = append(devPaths, "/dev/rbd"+f.Name())
+		}
+	}
+
+	return blockDevicesIOStats(devPaths)
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
@@ -269,6 +269,11 @@ func (d *common) ApplyPatch(name string) error {
 	return patch()
 }
 
+// GetIOStats returns the I/O statistics of the storage pool.
+func (d *common) GetIOStats() (*IOStats, error) {
+	return nil, ErrNotSupported
+}
+
 // moveGPTAltHeader moves the GPT alternative header to the end of the disk device supplied.
 // If the device supplied is not detected as not being a GPT disk then no action is taken and nil is returned.
 // If the required sgdisk command is not available a warning is logged, but no error is returned, as really it is
@@ -793,6 +793,16 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetIOStats returns the I/O statistics of the physical volumes of the volume group.
+func (d *lvm) GetIOStats() (*IOStats, error) {
+	out, err := shared.RunCommandContext(d.state.ShutdownCtx, "pvs", "--noheadings", "-o", "pv_name", "--select", "vg_name="+d.config["lvm.vg_name"])
+	if err != nil {
+		return nil, err
+	}
+
+	return blockDevicesIOStats(strings.Fields(out))
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of the volume group extent size.
 func (d *lvm) roundVolumeBlockSizeBytes(vol Volume, sizeBytes int64) int64 {
@@ -0,0 +1,243 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// nfsDefaultMountOptions are the mount options used when nfs.mount_options isn't set.
+const nfsDefaultMountOptions = "vers=4,hard"
+
+// nfs stores volumes as plain directories on an NFS export shared by all cluster members.
+// Volume handling is inherited from the dir driver, only the pool handling differs.
+type nfs struct {
+	dir
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *nfs) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *nfs) isRemote() bool {
+	return true
+}
+
+// Info returns info about the driver and its environment.
+func (d *nfs) Info() Info {
+	return Info{
+		Name:                         "nfs",
+		Version:                      "1",
+		DefaultBlockSize:             d.defaultBlockVolumeSize(),
+		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeMultiNode:              true,
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      true,
+		MountedRoot:                  true,
+		Buckets:                      false,
+		PopulateParentVolumeUUID:     false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *nfs) FillConfig() error {
+	if d.config["nfs.mount_options"] == "" {
+		d.config["nfs.mount_options"] = nfsDefaultMountOptions
+	}
+
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *nfs) Create() error {
+	err := d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	if d.config["source"] == "" {
+		return errors.New(`Missing required "source" property, expected "<host>:<path>"`)
+	}
+
+	_, _, err = d.parseSource(d.config["source"])
+	if err != nil {
+		return err
+	}
+
+	// Create a temporary mountpoint.
+	mountPath, err := os.MkdirTemp("", "lxd_nfs_")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory under: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(mountPath) }()
+
+	err = os.Chmod(mountPath, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to chmod '%s': %w", mountPath, err)
+	}
+
+	// Mount the export.
+	err = d.mount(mountPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = forceUnmount(mountPath) }()
+
+	// Check that the export is empty.
+	ok, _ := shared.PathIsEmpty(mountPath)
+	if !ok {
+		return fmt.Errorf("Only empty NFS exports can be used as a LXD storage pool, %q isn't empty", d.config["source"])
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *nfs) Delete(op *operations.Operation) error {
+	// The export needs to be mounted to clear its content.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the export.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Unmount the export.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *nfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-nfs; group=pool-conf; key=nfs.mount_options)
+		// The options are passed to the kernel NFS client as is.
+		// File locking must remain enabled as the same export is used by all cluster members,
+		// so the `nolock` option isn't allowed.
+		// ---
+		//  type: string
+		//  defaultdesc: `vers=4,hard`
+		//  shortdesc: Mount options for the NFS export
+		//  scope: global
+		"nfs.mount_options": func(value string) error {
+			for option := range strings.SplitSeq(value, ",") {
+				if option == "nolock" || (strings.HasPrefix(option, "local_lock=") && option != "local_lock=none") {
+					return fmt.Errorf("Mount option %q isn't allowed as it disables locking on the NFS server", option)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return d.validatePool(config, rules, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *nfs) Update(changedConfig map[string]string) error {
+	_, changed := changedConfig["nfs.mount_options"]
+	if changed && filesystem.IsMountPoint(GetPoolMountPath(d.name)) {
+		return errors.New("Mount options can only be changed while the pool is unmounted")
+	}
+
+	return nil
+}
+
+// Mount mounts the storage pool.
+func (d *nfs) Mount() (bool, error) {
+	path := GetPoolMountPath(d.name)
+
+	// Check if already mounted.
+	if filesystem.IsMountPoint(path) {
+		return false, nil
+	}
+
+	err := d.mount(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount unmounts the storage pool.
+func (d *nfs) Unmount() (bool, error) {
+	return forceUnmount(GetPoolMountPath(d.name))
+}
+
+// GetResources returns the pool resource usage information.
+func (d *nfs) GetResources() (*api.ResourcesStoragePool, error) {
+	return genericVFSGetResources(d)
+}
+
+// mount mounts the pool's NFS export on the given path.
+func (d *nfs) mount(path string) error {
+	host, exportPath, err := d.parseSource(d.config["source"])
+	if err != nil {
+		return err
+	}
+
+	// The kernel NFS client expects the server address rather than its name.
+	addr, err := d.resolveHost(host)
+	if err != nil {
+		return err
+	}
+
+	options := d.config["nfs.mount_options"]
+	if options == "" {
+		options = nfsDefaultMountOptions
+	}
+
+	options = strings.Join(slices.DeleteFunc(strings.Split(options, ","), func(option string) bool {
+		return option == "" || strings.HasPrefix(option, "addr=")
+	}), ",")
+
+	// IPv6 addresses need to be enclosed in square brackets.
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	err = TryMount(context.TODO(), host+":"+filepath.Clean(exportPath), path, "nfs", 0, options+",addr="+addr)
+	if err != nil {
+		return fmt.Errorf("Failed mounting NFS export %q: %w", d.config["source"], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package drivers
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// parseSource splits an NFS source of the form "<host>:<path>" into its host and export path.
+// IPv6 addresses must be enclosed in square brackets.
+func (d *nfs) parseSource(source string) (string, string, error) {
+	host, exportPath, found := strings.Cut(source, ":/")
+	if !found || host == "" {
+		return "", "", fmt.Errorf("Invalid NFS source %q, expected \"<host>:<path>\"", source)
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), "/" + exportPath, nil
+}
+
+// resolveHost returns the address of the NFS server.
+func (d *nfs) resolveHost(host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return "", fmt.Errorf("Failed resolving NFS server %q: %w", host, err)
+	}
+
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("No address found for NFS server %q", host)
+	}
+
+	return addrs[0], nil
+}
@@ -0,0 +1,89 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/validate"
+)
+
+func Test_nfs_parseSource(t *testing.T) {
+	d := &nfs{}
+
+	tests := []struct {
+		name       string
+		source     string
+		host       string
+		exportPath string
+		wantErr    bool
+	}{
+		{name: "Host name", source: "nas:/export/lxd", host: "nas", exportPath: "/export/lxd"},
+		{name: "IPv4 address", source: "192.0.2.10:/export/lxd", host: "192.0.2.10", exportPath: "/export/lxd"},
+		{name: "IPv6 address", source: "[2001:db8::10]:/export/lxd", host: "2001:db8::10", exportPath: "/export/lxd"},
+		{name: "Root export", source: "nas:/", host: "nas", exportPath: "/"},
+		{name: "Missing path", source: "nas", wantErr: true},
+		{name: "Relative path", source: "nas:export/lxd", wantErr: true},
+		{name: "Missing host", source: ":/export/lxd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, exportPath, err := d.parseSource(tt.source)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.exportPath, exportPath)
+		})
+	}
+}
+
+func Test_nfs_resolveHost(t *testing.T) {
+	d := &nfs{}
+
+	// Addresses are used as is.
+	for _, host := range []string{"192.0.2.10", "2001:db8::10"} {
+		addr, err := d.resolveHost(host)
+		require.NoError(t, err)
+		assert.Equal(t, host, addr)
+	}
+
+	addr, err := d.resolveHost("localhost")
+	require.NoError(t, err)
+	assert.NotEmpty(t, addr)
+}
+
+func Test_nfs_Validate(t *testing.T) {
+	d := &nfs{dir: dir{common: common{commonRules: &Validators{
+		PoolRules: func() map[string]func(string) error { return map[string]func(string) error{"source": validate.IsAny} },
+	}}}}
+
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr bool
+	}{
+		{name: "Default mount options", config: map[string]string{"source": "nas:/export/lxd"}},
+		{name: "Custom mount options", config: map[string]string{"nfs.mount_options": "vers=4.2,soft,timeo=100"}},
+		{name: "Local locking disabled", config: map[string]string{"nfs.mount_options": "vers=3,local_lock=none"}},
+		{name: "Locking disabled", config: map[string]string{"nfs.mount_options": "vers=3,nolock"}, wantErr: true},
+		{name: "Local locking", config: map[string]string{"nfs.mount_options": "vers=3,local_lock=all"}, wantErr: true},
+		{name: "Unknown option", config: map[string]string{"nfs.foo": "bar"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.Validate(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
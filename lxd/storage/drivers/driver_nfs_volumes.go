@@ -0,0 +1,27 @@
+package drivers
+
+import (
+	"io"
+
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/operations"
+)
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *nfs) CreateVolumeFromMigration(vol VolumeCopy, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	// When performing a cluster member move the volume is already on the shared export.
+	if volTargetArgs.ClusterMoveSourceName != "" {
+		return vol.EnsureMountPath()
+	}
+
+	return d.dir.CreateVolumeFromMigration(vol, conn, volTargetArgs, preFiller, op)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *nfs) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	if volSrcArgs.ClusterMove {
+		return nil // When performing a cluster member move don't do anything on the source member.
+	}
+
+	return d.dir.MigrateVolume(vol, conn, volSrcArgs, op)
+}
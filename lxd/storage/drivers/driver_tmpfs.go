@@ -0,0 +1,201 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+type tmpfs struct {
+	common
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *tmpfs) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	return nil
+}
+
+// Info returns info about the driver and its environment.
+func (d *tmpfs) Info() Info {
+	return Info{
+		Name:                         "tmpfs",
+		Version:                      "1",
+		DefaultBlockSize:             d.defaultBlockVolumeSize(),
+		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     false,
+		IOUring:                      true,
+		MountedRoot:                  true,
+		Buckets:                      false,
+		PopulateParentVolumeUUID:     false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *tmpfs) FillConfig() error {
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *tmpfs) Create() error {
+	if d.config["source"] != "" {
+		return errors.New(`The "source" property isn't supported by the tmpfs driver`)
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *tmpfs) Delete(op *operations.Operation) error {
+	path := GetPoolMountPath(d.name)
+
+	// On delete, wipe everything in the directory.
+	err := wipeDirectory(path)
+	if err != nil {
+		return err
+	}
+
+	// Unmount the path (discarding its content).
+	_, err = forceUnmount(path)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *tmpfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-tmpfs; group=pool-conf; key=size)
+		// This limits the memory used by the pool itself (images, snapshots and backups).
+		// Each volume is backed by its own `tmpfs` that is limited by the volume's `size`.
+		//
+		// The default is half of the host's memory, as for any `tmpfs` mount.
+		// ---
+		//  type: string
+		//  defaultdesc: 50% of the host memory
+		//  shortdesc: Size of the storage pool
+		//  scope: local
+		"size": validate.Optional(validate.IsSize),
+	}
+
+	return d.validatePool(config, rules, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *tmpfs) Update(changedConfig map[string]string) error {
+	size, ok := changedConfig["size"]
+	if !ok {
+		return nil
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	// Resize the pool's tmpfs in place.
+	return d.remount(GetPoolMountPath(d.name), sizeBytes)
+}
+
+// Mount mounts the storage pool.
+// As the content of a tmpfs doesn't survive being unmounted, a pool that got lost (for example after a reboot)
+// comes back empty.
+func (d *tmpfs) Mount() (bool, error) {
+	path := GetPoolMountPath(d.name)
+
+	// Check if already mounted.
+	if filesystem.IsMountPoint(path) {
+		return false, nil
+	}
+
+	var sizeBytes int64
+	if d.config["size"] != "" {
+		var err error
+		sizeBytes, err = units.ParseByteSizeString(d.config["size"])
+		if err != nil {
+			return false, err
+		}
+	}
+
+	options, err := d.mountOptions(sizeBytes)
+	if err != nil {
+		return false, err
+	}
+
+	err = TryMount(context.TODO(), "tmpfs", path, "tmpfs", 0, options)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount is a no-op as unmounting the pool's tmpfs would discard its content.
+// The pool only gets unmounted when it is deleted.
+func (d *tmpfs) Unmount() (bool, error) {
+	return false, nil
+}
+
+// GetResources returns the pool resource usage information.
+// This accounts for the memory used by the pool itself as well as by each of its volumes.
+func (d *tmpfs) GetResources() (*api.ResourcesStoragePool, error) {
+	res, err := genericVFSGetResources(d)
+	if err != nil {
+		return nil, err
+	}
+
+	poolPath := GetPoolMountPath(d.name)
+	for _, volType := range d.Info().VolumeTypes {
+		volTypeDir := filepath.Join(poolPath, string(volType))
+
+		entries, err := os.ReadDir(volTypeDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			volPath := filepath.Join(volTypeDir, entry.Name())
+			if !entry.IsDir() || !filesystem.IsMountPoint(volPath) {
+				continue
+			}
+
+			st, err := filesystem.StatVFS(volPath)
+			if err != nil {
+				return nil, fmt.Errorf("Failed getting usage of %q: %w", volPath, err)
+			}
+
+			res.Space.Used += (st.Blocks - st.Bfree) * uint64(st.Bsize)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,162 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/lxd/storage/block"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// dir returns a dir driver for the same pool.
+// Once their tmpfs is mounted, volumes are plain directories so snapshots and restores are handled
+// the same way as for the dir driver.
+func (d *tmpfs) dir() *dir {
+	dirDriver := &dir{}
+	getVolID := func(volType VolumeType, volName string) (int64, error) { return volIDQuotaSkip, nil }
+	dirDriver.init(d.state, d.name, d.config, d.logger, getVolID, d.commonRules)
+	_ = dirDriver.load()
+
+	return dirDriver
+}
+
+// tmpfsDefaultSizeBytes returns the size limit the kernel applies to a tmpfs mounted without a size option,
+// which is half of the RAM.
+func tmpfsDefaultSizeBytes() (int64, error) {
+	var info unix.Sysinfo_t
+	err := unix.Sysinfo(&info)
+	if err != nil {
+		return -1, fmt.Errorf("Failed getting the size of the RAM: %w", err)
+	}
+
+	return int64(info.Totalram) * int64(info.Unit) / 2, nil
+}
+
+// sizeLimit returns the size limit in bytes of a tmpfs for the given size, falling back to the kernel default if no
+// size is set. A size of 0 means unlimited for tmpfs, so it's never passed to the kernel.
+func (d *tmpfs) sizeLimit(sizeBytes int64) (int64, error) {
+	if sizeBytes > 0 {
+		return sizeBytes, nil
+	}
+
+	return tmpfsDefaultSizeBytes()
+}
+
+// mountOptions returns the tmpfs mount options for the given size limit in bytes.
+func (d *tmpfs) mountOptions(sizeBytes int64) (string, error) {
+	limit, err := d.sizeLimit(sizeBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("mode=0711,size=%d", limit), nil
+}
+
+// remount changes the size limit of an existing tmpfs mount.
+// A size of 0 sets the limit back to the kernel default.
+func (d *tmpfs) remount(path string, sizeBytes int64) error {
+	limit, err := d.sizeLimit(sizeBytes)
+	if err != nil {
+		return err
+	}
+
+	err = unix.Mount("tmpfs", path, "tmpfs", unix.MS_REMOUNT, fmt.Sprintf("size=%d", limit))
+	if err != nil {
+		if errors.Is(err, unix.EINVAL) {
+			return fmt.Errorf("Failed resizing %q, the new size may be smaller than the data it holds: %w", path, err)
+		}
+
+		return fmt.Errorf("Failed resizing %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// volumeSize returns the size limit in bytes of the tmpfs backing the volume.
+func (d *tmpfs) volumeSize(vol Volume) (int64, error) {
+	// ISO volumes are sized by their content.
+	if vol.contentType == ContentTypeISO {
+		return 0, nil
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+	if err != nil {
+		return -1, err
+	}
+
+	// The tmpfs of a VM holds both its config filesystem and its root disk image.
+	if vol.IsVMBlock() {
+		fsSizeBytes, err := units.ParseByteSizeString(vol.NewVMBlockFilesystemVolume().ConfigSize())
+		if err != nil {
+			return -1, err
+		}
+
+		sizeBytes += fsSizeBytes
+	}
+
+	return sizeBytes, nil
+}
+
+// mountVolumeTmpfs mounts the tmpfs backing the volume.
+// The tmpfs stays mounted until the volume gets deleted as its content would otherwise be lost.
+func (d *tmpfs) mountVolumeTmpfs(vol Volume) error {
+	sizeBytes, err := d.volumeSize(vol)
+	if err != nil {
+		return err
+	}
+
+	options, err := d.mountOptions(sizeBytes)
+	if err != nil {
+		return err
+	}
+
+	return TryMount(context.TODO(), "tmpfs", vol.MountPath(), "tmpfs", 0, options)
+}
+
+// resizeBlockVolume resizes a VM or custom block volume's disk image along with its tmpfs.
+func (d *tmpfs) resizeBlockVolume(vol Volume, sizeBytes int64, allowUnsafeResize bool) (bool, error) {
+	rootBlockPath, err := d.GetVolumeDiskPath(vol)
+	if err != nil {
+		return false, err
+	}
+
+	oldSizeBytes, err := block.DiskSizeBytes(rootBlockPath)
+	if err != nil {
+		return false, err
+	}
+
+	// Work out the new size of the tmpfs.
+	newVol := vol.Clone()
+	newVol.SetConfigSize(strconv.FormatInt(sizeBytes, 10))
+	tmpfsSizeBytes, err := d.volumeSize(newVol)
+	if err != nil {
+		return false, err
+	}
+
+	// Grow the tmpfs ahead of the disk image so it has room for it.
+	if sizeBytes > oldSizeBytes {
+		err = d.remount(vol.MountPath(), tmpfsSizeBytes)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	resized, err := ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, allowUnsafeResize)
+	if err != nil {
+		return false, err
+	}
+
+	// Shrink the tmpfs once the disk image has been shrunk.
+	if sizeBytes < oldSizeBytes {
+		err = d.remount(vol.MountPath(), tmpfsSizeBytes)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return resized, nil
+}
@@ -0,0 +1,88 @@
+package drivers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tmpfs_sizeLimit(t *testing.T) {
+	d := &tmpfs{}
+
+	defaultSizeBytes, err := tmpfsDefaultSizeBytes()
+	require.NoError(t, err)
+	require.Positive(t, defaultSizeBytes)
+
+	tests := []struct {
+		name      string
+		sizeBytes int64
+		limit     int64
+	}{
+		{name: "Explicit size", sizeBytes: 1024 * 1024 * 1024, limit: 1024 * 1024 * 1024},
+		{name: "No size falls back to the kernel default", sizeBytes: 0, limit: defaultSizeBytes},
+		{name: "Negative size falls back to the kernel default", sizeBytes: -1, limit: defaultSizeBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := d.sizeLimit(tt.sizeBytes)
+			require.NoError(t, err)
+			assert.Equal(t, tt.limit, limit)
+
+			// The mount options always carry a bounded size.
+			options, err := d.mountOptions(tt.sizeBytes)
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("mode=0711,size=%d", tt.limit), options)
+		})
+	}
+}
+
+func Test_tmpfs_volumeSize(t *testing.T) {
+	d := &tmpfs{}
+
+	tests := []struct {
+		name      string
+		vol       Volume
+		sizeBytes int64
+	}{
+		{
+			name:      "Custom filesystem volume with a size",
+			vol:       Volume{driver: d, volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{"size": "1GiB"}},
+			sizeBytes: 1024 * 1024 * 1024,
+		},
+		{
+			name:      "Custom filesystem volume without a size",
+			vol:       Volume{driver: d, volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{}},
+			sizeBytes: 0,
+		},
+		{
+			name:      "Custom filesystem volume with the pool default size",
+			vol:       Volume{driver: d, volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{}, poolConfig: map[string]string{"volume.size": "2GiB"}},
+			sizeBytes: 2 * 1024 * 1024 * 1024,
+		},
+		{
+			name:      "ISO volume sized by its content",
+			vol:       Volume{driver: d, volType: VolumeTypeCustom, contentType: ContentTypeISO, config: map[string]string{"size": "1GiB"}},
+			sizeBytes: 0,
+		},
+		{
+			name:      "VM block volume including its config filesystem",
+			vol:       Volume{driver: d, volType: VolumeTypeVM, contentType: ContentTypeBlock, config: map[string]string{"size": "10GiB", "size.state": "1GiB"}},
+			sizeBytes: 11 * 1024 * 1024 * 1024,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizeBytes, err := d.volumeSize(tt.vol)
+			require.NoError(t, err)
+			assert.Equal(t, tt.sizeBytes, sizeBytes)
+		})
+	}
+
+	// Invalid sizes are reported.
+	_, err := d.volumeSize(Volume{driver: d, volType: VolumeTypeCustom, contentType: ContentTypeFS, config: map[string]string{"size": "invalid"}})
+	assert.Error(t, err)
+}
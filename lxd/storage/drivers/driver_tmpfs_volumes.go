@@ -0,0 +1,456 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/instancewriter"
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/storage/block"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied
+// filler function.
+func (d *tmpfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	volPath := vol.MountPath()
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	if shared.PathExists(volPath) {
+		return fmt.Errorf("Volume path %q already exists", volPath)
+	}
+
+	// Create the volume's mount point.
+	err := vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = os.RemoveAll(volPath) })
+
+	// Mount the tmpfs holding the volume, its size limit acts as the volume's quota.
+	err = d.mountVolumeTmpfs(vol)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _, _ = forceUnmount(volPath) })
+
+	// Apply the expected permissions to the root of the tmpfs.
+	err = vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	// Get path to disk volume if volume is block or iso.
+	rootBlockPath := ""
+	if IsContentBlock(vol.contentType) {
+		// We expect the filler to copy the VM image into this path.
+		rootBlockPath, err = d.GetVolumeDiskPath(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Run the volume filler function if supplied.
+	err = d.runFiller(vol, rootBlockPath, filler, false)
+	if err != nil {
+		return err
+	}
+
+	// If we are creating a block volume, resize it to the requested size or the default.
+	// For block volumes, we expect the filler function to have converted the qcow2 image to raw into the rootBlockPath.
+	// For ISOs the content will just be copied.
+	if IsContentBlock(vol.contentType) {
+		// Convert to bytes.
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return err
+		}
+
+		// Ignore ErrCannotBeShrunk when setting size this just means the filler run above has needed to
+		// increase the volume size beyond the default block volume size.
+		_, err = ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
+		if err != nil && !errors.Is(err, ErrCannotBeShrunk) {
+			return err
+		}
+
+		// Move the GPT alt header to end of disk if needed and if filler specified.
+		if vol.IsVMBlock() && filler != nil && filler.Fill != nil {
+			err = d.moveGPTAltHeader(rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	revert.Success()
+	return nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *tmpfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	// Run the generic backup unpacker
+	postHook, revertHook, err := genericVFSBackupUnpack(d, d.state, vol, srcBackup.Snapshots, srcData, op)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// genericVFSBackupUnpack returns a nil postHook when volume's type is VolumeTypeCustom which
+	// doesn't need any post hook processing after DB record creation.
+	if postHook != nil {
+		// Define a post hook function that can be run once the backup config has been restored.
+		// This will apply the size limit from the restored config.
+		postHookWrapper := func(vol Volume) error {
+			err := postHook(vol)
+			if err != nil {
+				return err
+			}
+
+			sizeBytes, err := d.volumeSize(vol)
+			if err != nil {
+				return err
+			}
+
+			return d.remount(vol.MountPath(), sizeBytes)
+		}
+
+		return postHookWrapper, revertHook, nil
+	}
+
+	return nil, revertHook, nil
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+func (d *tmpfs) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowInconsistent bool, op *operations.Operation) error {
+	var srcSnapshots []string
+
+	if len(vol.Snapshots) > 0 && !srcVol.IsSnapshot() {
+		// Get the list of snapshots from the source.
+		allSrcSnapshots, err := srcVol.Volume.Snapshots(op)
+		if err != nil {
+			return err
+		}
+
+		for _, srcSnapshot := range allSrcSnapshots {
+			_, snapshotName, _ := api.GetParentAndSnapshotName(srcSnapshot.name)
+			srcSnapshots = append(srcSnapshots, snapshotName)
+		}
+	}
+
+	// Run the generic copy.
+	_, err := genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	return err
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *tmpfs) CreateVolumeFromMigration(vol VolumeCopy, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	_, err := genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+	return err
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *tmpfs) RefreshVolume(vol VolumeCopy, srcVol VolumeCopy, refreshSnapshots []string, allowInconsistent bool, op *operations.Operation) error {
+	_, err := genericVFSCopyVolume(d, nil, vol, srcVol, refreshSnapshots, true, allowInconsistent, op)
+	return err
+}
+
+// DeleteVolume deletes a volume of the storage device. If any snapshots of the volume remain then
+// this function will return an error.
+func (d *tmpfs) DeleteVolume(vol Volume, op *operations.Operation) error {
+	snapshots, err := d.VolumeSnapshots(vol, op)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) > 0 {
+		return errors.New("Cannot remove a volume that has snapshots")
+	}
+
+	volPath := vol.MountPath()
+
+	// If the volume doesn't exist, then nothing more to do.
+	if !shared.PathExists(volPath) {
+		return nil
+	}
+
+	// Unmounting the tmpfs discards the volume's content.
+	_, err = forceUnmount(volPath)
+	if err != nil {
+		return err
+	}
+
+	// Remove the mount point.
+	err = forceRemoveAll(volPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to remove '%s': %w", volPath, err)
+	}
+
+	// Although the volume snapshot directory should already be removed, lets remove it here
+	// to just in case the top-level directory is left.
+	err = deleteParentSnapshotDirIfEmpty(d.name, vol.volType, vol.name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *tmpfs) HasVolume(vol Volume) (bool, error) {
+	return genericVFSHasVolume(vol)
+}
+
+// ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
+func (d *tmpfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	return d.validateVolume(vol, nil, removeUnknownKeys)
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *tmpfs) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeUsage returns the memory used by the volume.
+func (d *tmpfs) GetVolumeUsage(vol Volume) (int64, error) {
+	// Snapshot usage not supported for tmpfs.
+	if vol.IsSnapshot() {
+		return -1, ErrNotSupported
+	}
+
+	volPath := vol.MountPath()
+	if !filesystem.IsMountPoint(volPath) {
+		return -1, ErrNotSupported
+	}
+
+	st, err := filesystem.StatVFS(volPath)
+	if err != nil {
+		return -1, err
+	}
+
+	return int64((st.Blocks - st.Bfree) * uint64(st.Bsize)), nil
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// Does nothing if supplied with an empty/zero size for block volumes, and for filesystem volumes removes the limit.
+func (d *tmpfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	// Convert to bytes.
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	// For VM block files, resize the file and its tmpfs if needed.
+	if vol.contentType == ContentTypeBlock {
+		// Do nothing if size isn't specified.
+		if sizeBytes <= 0 {
+			return nil
+		}
+
+		resized, err := d.resizeBlockVolume(vol, sizeBytes, allowUnsafeResize)
+		if err != nil {
+			return err
+		}
+
+		// Move the GPT alt header to end of disk if needed and resize has taken place (not needed in
+		// unsafe resize mode as it is expected the caller will do all necessary post resize actions
+		// themselves).
+		if vol.IsVMBlock() && resized && !allowUnsafeResize {
+			rootBlockPath, err := d.GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
+
+			err = d.moveGPTAltHeader(rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Custom handling for filesystem volume associated with a VM.
+	volPath := vol.MountPath()
+	if sizeBytes > 0 && vol.volType == VolumeTypeVM && shared.PathExists(filepath.Join(volPath, genericVolumeDiskFile)) {
+		// Get the size of the VM image.
+		blockSize, err := block.DiskSizeBytes(filepath.Join(volPath, genericVolumeDiskFile))
+		if err != nil {
+			return err
+		}
+
+		// Add that to the requested filesystem size (to ignore it from the limit).
+		sizeBytes += blockSize
+		d.logger.Debug("Accounting for VM image file size", logger.Ctx{"sizeBytes": sizeBytes})
+	}
+
+	return d.remount(volPath, sizeBytes)
+}
+
+// GetVolumeDiskPath returns the location of a disk volume.
+func (d *tmpfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	return genericVFSGetVolumeDiskPath(vol)
+}
+
+// ListVolumes returns a list of LXD volumes in storage pool.
+func (d *tmpfs) ListVolumes() ([]Volume, error) {
+	return genericVFSListVolumes(d)
+}
+
+// MountVolume simulates mounting a volume.
+// The volume's tmpfs is kept mounted for as long as the volume exists so this only checks it is still there.
+func (d *tmpfs) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if !filesystem.IsMountPoint(vol.MountPath()) {
+		return fmt.Errorf("Volume %q is missing from tmpfs pool %q, its content is lost when the host restarts", vol.name, d.name)
+	}
+
+	// Don't attempt to modify the permission of an existing custom volume root.
+	// A user inside the instance may have modified this and we don't want to reset it on restart.
+	if vol.volType != VolumeTypeCustom {
+		err := vol.EnsureMountPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	return nil
+}
+
+// UnmountVolume simulates unmounting a volume.
+// As the volume's tmpfs must remain mounted it returns false indicating the volume was already unmounted.
+func (d *tmpfs) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	refCount := vol.MountRefCountDecrement()
+	if refCount > 0 {
+		d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+		return false, ErrInUse
+	}
+
+	return false, nil
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *tmpfs) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	srcVolumePath := vol.MountPath()
+	newVol := NewVolume(d, d.name, vol.volType, vol.contentType, newVolName, vol.config, vol.poolConfig)
+	dstVolumePath := newVol.MountPath()
+
+	// A mount point can't be renamed, so move the volume's tmpfs over to its new location instead.
+	if filesystem.IsMountPoint(srcVolumePath) {
+		err := newVol.EnsureMountPath()
+		if err != nil {
+			return err
+		}
+
+		revert.Add(func() { _ = os.Remove(dstVolumePath) })
+
+		err = unix.Mount(srcVolumePath, dstVolumePath, "", unix.MS_MOVE, "")
+		if err != nil {
+			return fmt.Errorf("Failed to move %q to %q: %w", srcVolumePath, dstVolumePath, err)
+		}
+
+		revert.Add(func() { _ = unix.Mount(dstVolumePath, srcVolumePath, "", unix.MS_MOVE, "") })
+
+		err = os.Remove(srcVolumePath)
+		if err != nil {
+			return fmt.Errorf("Failed to remove %q: %w", srcVolumePath, err)
+		}
+
+		revert.Add(func() { _ = os.Mkdir(srcVolumePath, 0711) })
+	}
+
+	// Rename the snapshots (the volume itself was moved above).
+	err := genericVFSRenameVolume(d, vol, newVolName, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *tmpfs) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
+// This driver does not support optimized backups.
+func (d *tmpfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume.
+// Snapshots are stored on the pool's own tmpfs.
+func (d *tmpfs) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return d.dir().CreateVolumeSnapshot(snapVol, op)
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device.
+func (d *tmpfs) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return d.dir().DeleteVolumeSnapshot(snapVol, op)
+}
+
+// MountVolumeSnapshot sets up a read-only mount on top of the snapshot to avoid accidental modifications.
+func (d *tmpfs) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	return d.dir().MountVolumeSnapshot(snapVol, op)
+}
+
+// UnmountVolumeSnapshot removes the read-only mount placed on top of a snapshot.
+func (d *tmpfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	return d.dir().UnmountVolumeSnapshot(snapVol, op)
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume (in no particular order).
+func (d *tmpfs) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	return genericVFSVolumeSnapshots(d, vol, op)
+}
+
+// RestoreVolume restores a volume from a snapshot.
+func (d *tmpfs) RestoreVolume(vol Volume, snapVol Volume, op *operations.Operation) error {
+	return d.dir().RestoreVolume(vol, snapVol, op)
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *tmpfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+}
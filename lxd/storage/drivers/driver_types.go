@@ -71,3 +71,33 @@ type VolumeFiller struct {
 
 	Fingerprint string // If the Filler will unpack an image, it should be this fingerprint.
 }
+
+// IOStats represents the I/O statistics of a storage pool on the local server.
+// The values are accumulated since the pool or its devices were set up.
+type IOStats struct {
+	// Latency histograms of the read and write operations.
+	ReadLatency  LatencyHistogram
+	WriteLatency LatencyHistogram
+
+	// Number of I/O errors by type ("read", "write" or "checksum").
+	Errors map[string]uint64
+}
+
+// LatencyHistogram represents the distribution of the latencies of I/O operations.
+type LatencyHistogram struct {
+	// Buckets holds the cumulative number of operations per upper bound in seconds, sorted by upper bound.
+	// Drivers which only know the total time spent doing I/O leave it empty.
+	Buckets []LatencyBucket
+
+	// Number of operations.
+	Count uint64
+
+	// Total time spent in the operations in seconds, negative if unknown.
+	Sum float64
+}
+
+// LatencyBucket represents a bucket of a latency histogram.
+type LatencyBucket struct {
+	UpperBound float64
+	Count      uint64
+}
@@ -668,6 +668,32 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetIOStats returns the latency histograms and the device errors of the zpool.
+// When using a dataset, the statistics are those of the whole zpool.
+func (d *zfs) GetIOStats() (*IOStats, error) {
+	poolName := strings.Split(d.config["zfs.pool_name"], "/")[0]
+
+	out, err := shared.RunCommandContext(d.state.ShutdownCtx, "zpool", "iostat", "-wHp", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &IOStats{}
+	stats.ReadLatency, stats.WriteLatency, err = parseZpoolLatencyHistograms(out)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = shared.RunCommandContext(d.state.ShutdownCtx, "zpool", "status", "-p", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Errors = parseZpoolStatusErrors(out)
+
+	return stats, nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing
 // migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
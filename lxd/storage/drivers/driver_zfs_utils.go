@@ -526,3 +526,93 @@ func (d *zfs) delegateDataset(vol Volume, pid int) error {
 func ZFSSupportsDelegation() bool {
 	return zfsDelegate
 }
+
+// parseZpoolLatencyHistograms parses the output of "zpool iostat -wHp" into the read and write latency histograms,
+// using the total wait time of the operations.
+func parseZpoolLatencyHistograms(output string) (LatencyHistogram, LatencyHistogram, error) {
+	read := LatencyHistogram{Sum: -1}
+	write := LatencyHistogram{Sum: -1}
+
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.Fields(line)
+
+		// Skip the lines which aren't histogram buckets, such as the pool name.
+		if len(fields) < 3 {
+			continue
+		}
+
+		upperBound, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		readCount, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return read, write, fmt.Errorf("Failed parsing read latency bucket %q: %w", line, err)
+		}
+
+		writeCount, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return read, write, fmt.Errorf("Failed parsing write latency bucket %q: %w", line, err)
+		}
+
+		// The buckets are in nanoseconds and not cumulative.
+		read.Count += readCount
+		read.Buckets = append(read.Buckets, LatencyBucket{UpperBound: float64(upperBound) / 1e9, Count: read.Count})
+		write.Count += writeCount
+		write.Buckets = append(write.Buckets, LatencyBucket{UpperBound: float64(upperBound) / 1e9, Count: write.Count})
+	}
+
+	return read, write, nil
+}
+
+// parseZpoolStatusErrors parses the output of "zpool status -p" and returns the read, write and checksum errors
+// summed over the leaf devices of the pool.
+func parseZpoolStatusErrors(output string) map[string]uint64 {
+	type row struct {
+		indent int
+		fields []string
+	}
+
+	var rows []row
+	inConfig := false
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.Fields(line)
+
+		if !inConfig {
+			inConfig = len(fields) >= 5 && fields[0] == "NAME" && fields[2] == "READ"
+			continue
+		}
+
+		// The device list ends with an empty line.
+		if len(fields) == 0 {
+			break
+		}
+
+		rows = append(rows, row{indent: len(line) - len(strings.TrimLeft(line, " \t")), fields: fields})
+	}
+
+	counts := map[string]uint64{"read": 0, "write": 0, "checksum": 0}
+	for i, r := range rows {
+		// Skip the devices having children, their errors are those of their children.
+		if i+1 < len(rows) && rows[i+1].indent > r.indent {
+			continue
+		}
+
+		// Skip the section headers such as "logs" and the spares which have no error counters.
+		if len(r.fields) < 5 {
+			continue
+		}
+
+		for j, errorType := range []string{"read", "write", "checksum"} {
+			count, err := strconv.ParseUint(r.fields[2+j], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			counts[errorType] += count
+		}
+	}
+
+	return counts
+}
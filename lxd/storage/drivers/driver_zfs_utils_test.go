@@ -0,0 +1,42 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test parseZpoolLatencyHistograms.
+func TestParseZpoolLatencyHistograms(t *testing.T) {
+	output := "tank\n1\t0\t0\t0\t0\n3\t2\t1\t0\t0\n7\t3\t0\t0\t0\n"
+
+	read, write, err := parseZpoolLatencyHistograms(output)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(5), read.Count)
+	assert.Equal(t, []LatencyBucket{{UpperBound: 1e-9, Count: 0}, {UpperBound: 3e-9, Count: 2}, {UpperBound: 7e-9, Count: 5}}, read.Buckets)
+	assert.Equal(t, uint64(1), write.Count)
+	assert.Equal(t, uint64(1), write.Buckets[2].Count)
+	assert.Negative(t, read.Sum)
+}
+
+// Test parseZpoolStatusErrors.
+func TestParseZpoolStatusErrors(t *testing.T) {
+	output := `  pool: tank
+ state: DEGRADED
+config:
+
+	NAME        STATE     READ WRITE CKSUM
+	tank        DEGRADED     0     0     0
+	  mirror-0  DEGRADED     3     0     0
+	    sda     ONLINE       0     0     2
+	    sdb     FAULTED      3     1     0
+	logs
+	  sdc       ONLINE       0     0     0
+
+errors: No known data errors
+`
+
+	counts := parseZpoolStatusErrors(output)
+	assert.Equal(t, map[string]uint64{"read": 3, "write": 1, "checksum": 2}, counts)
+}
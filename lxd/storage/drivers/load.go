@@ -11,7 +11,9 @@ var drivers = map[string]func() driver{
 	"cephfs":     func() driver { return &cephfs{} },
 	"cephobject": func() driver { return &cephobject{} },
 	"dir":        func() driver { return &dir{} },
+	"tmpfs":      func() driver { return &tmpfs{} },
 	"lvm":        func() driver { return &lvm{} },
+	"nfs":        func() driver { return &nfs{} },
 	"powerflex":  func() driver { return &powerflex{} },
 	"pure":       func() driver { return &pure{} },
 	"alletra":    func() driver { return &alletra{} },
@@ -988,3 +988,52 @@ func ValidVolumeName(volumeName string) error {
 
 	return nil
 }
+
+// blockDevicesIOStats returns the combined I/O statistics of the given block devices, as reported by the kernel.
+// The kernel only tracks the time spent doing I/O, so the latency histograms have no buckets.
+func blockDevicesIOStats(devPaths []string) (*IOStats, error) {
+	stats := &IOStats{}
+
+	for _, devPath := range devPaths {
+		// Resolve symlinks such as "/dev/disk/by-id/..." or "/dev/mapper/..." to the kernel device name.
+		realPath, err := filepath.EvalSymlinks(devPath)
+		if err != nil {
+			// Skip devices removed in the meantime.
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		content, err := os.ReadFile(filepath.Join("/sys/class/block", filepath.Base(realPath), "stat"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		// See https://www.kernel.org/doc/html/latest/block/stat.html for the meaning of the fields.
+		fields := strings.Fields(string(content))
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("Unexpected block device statistics for %q", devPath)
+		}
+
+		values := make([]uint64, 8)
+		for i := range values {
+			values[i], err = strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing block device statistics for %q: %w", devPath, err)
+			}
+		}
+
+		stats.ReadLatency.Count += values[0]
+		stats.ReadLatency.Sum += float64(values[3]) / 1000
+		stats.WriteLatency.Count += values[4]
+		stats.WriteLatency.Sum += float64(values[7]) / 1000
+	}
+
+	return stats, nil
+}
@@ -1172,6 +1172,33 @@ func storagePoolBucketKeyPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	targetMember := request.QueryParam(r, "target")
+	memberSpecific := targetMember != ""
+
+	var bucketKey *db.StorageBucketKey
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err := tx.GetStoragePoolBucket(ctx, details.pool.ID(), effectiveProjectName, memberSpecific, details.bucketName)
+		if err != nil {
+			return err
+		}
+
+		bucketKey, err = tx.GetStoragePoolBucketKey(ctx, bucket.ID, keyName)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = util.EtagCheck(r, bucketKey.Etag())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
 	err = details.pool.UpdateBucketKey(effectiveProjectName, details.bucketName, keyName, req, nil)
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed updating storage bucket key: %w", err))
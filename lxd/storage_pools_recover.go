@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var storagePoolScanCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/scan",
+	MetricsType: entity.TypeStoragePool,
+
+	Post: APIEndpointAction{Handler: storagePoolScanPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+var storagePoolRecoverCmd = APIEndpoint{
+	Path:        "storage-pools/{poolName}/recover",
+	MetricsType: entity.TypeStoragePool,
+
+	Post: APIEndpointAction{Handler: storagePoolRecoverPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// storagePoolRecoverPools returns the pool definition used to scan an existing storage pool for unknown volumes.
+func storagePoolRecoverPools(d *Daemon, r *http.Request) ([]api.StoragePoolsPost, error) {
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return nil, err
+	}
+
+	// Only pools that are known to the database can be scanned through the API.
+	_, err = storagePools.LoadByName(d.State(), poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return []api.StoragePoolsPost{{Name: poolName}}, nil
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/scan storage storage_pool_scan_post
+//
+//	Scan the storage pool for unknown volumes
+//
+//	Looks for instance, custom volume and bucket volumes on the storage pool that are missing from the database.
+//	Each discovered volume is returned along with the backup configuration it was found with.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "200":
+//	    description: Scan result
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StoragePoolScan"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolScanPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	pools, err := storagePoolRecoverPools(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	res, err := internalRecoverScan(r.Context(), s, pools, true, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	scan := api.StoragePoolScan{
+		UnknownVolumes:   make([]api.StoragePoolScanVolume, 0, len(res.UnknownVolumes)),
+		DependencyErrors: res.DependencyErrors,
+	}
+
+	for _, vol := range res.UnknownVolumes {
+		scan.UnknownVolumes = append(scan.UnknownVolumes, api.StoragePoolScanVolume{
+			Name:          vol.Name,
+			Type:          vol.Type,
+			Project:       vol.Project,
+			Pool:          vol.Pool,
+			SnapshotCount: vol.SnapshotCount,
+			Config:        vol.Config,
+		})
+	}
+
+	return response.SyncResponse(true, scan)
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/recover storage storage_pool_recover_post
+//
+//	Recover unknown volumes
+//
+//	Re-creates the database records of unknown volumes found on the storage pool.
+//	If no volumes are listed, all unknown volumes found by the scan are recovered.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: body
+//	    name: recover
+//	    description: Volumes to recover
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StoragePoolRecoverPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolRecoverPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseToNode(r.Context(), s, request.QueryParam(r, "target"))
+	if resp != nil {
+		return resp
+	}
+
+	pools, err := storagePoolRecoverPools(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.StoragePoolRecoverPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var selector func(vol internalRecoverValidateVolume) bool
+	if len(req.Volumes) > 0 {
+		selector = func(vol internalRecoverValidateVolume) bool {
+			for _, selected := range req.Volumes {
+				if selected.Name == vol.Name && selected.Type == vol.Type && selected.Project == vol.Project {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	run := func(op *operations.Operation) error {
+		res, err := internalRecoverScan(context.Background(), s, pools, false, selector)
+		if err != nil {
+			return err
+		}
+
+		if res != nil && len(res.DependencyErrors) > 0 {
+			return fmt.Errorf("Missing dependencies: %s", strings.Join(res.DependencyErrors, ", "))
+		}
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_pools"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", pools[0].Name)}
+
+	op, err := operations.OperationCreate(r.Context(), s, "", operations.OperationClassTask, operationtype.StoragePoolRecover, resources, nil, run, nil, nil)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
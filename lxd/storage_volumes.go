@@ -13,7 +13,6 @@ import (
 	"net/url"
 	"os"
 	"slices"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -176,6 +175,21 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -242,6 +256,26 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
+//    - in: query
+//      name: fields
+//      description: Comma-separated list of fields to return
+//      type: string
+//      example: name,type
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -296,6 +330,21 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //      description: Cluster member name
 //      type: string
 //      example: lxd01
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -355,6 +404,26 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //	    description: Cluster member name
 //	    type: string
 //	    example: lxd01
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return
+//	    type: integer
+//	    example: 100
+//	  - in: query
+//	    name: offset
+//	    description: Number of entries to skip
+//	    type: integer
+//	    example: 200
+//	  - in: query
+//	    name: sort
+//	    description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//	    type: string
+//	    example: -created_at
+//	  - in: query
+//	    name: fields
+//	    description: Comma-separated list of fields to return
+//	    type: string
+//	    example: name,type
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -414,6 +483,21 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -480,6 +564,26 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //      description: Collection filter
 //      type: string
 //      example: default
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
+//    - in: query
+//      name: fields
+//      description: Comma-separated list of fields to return
+//      type: string
+//      example: name,type
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -534,6 +638,21 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //      description: Cluster member name
 //      type: string
 //      example: lxd01
+//    - in: query
+//      name: limit
+//      description: Maximum number of entries to return
+//      type: integer
+//      example: 100
+//    - in: query
+//      name: offset
+//      description: Number of entries to skip
+//      type: integer
+//      example: 200
+//    - in: query
+//      name: sort
+//      description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//      type: string
+//      example: -created_at
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -593,6 +712,26 @@ func checkStoragePoolVolumeTypeAccess(s *state.State, r *http.Request, entityTyp
 //	    description: Cluster member name
 //	    type: string
 //	    example: lxd01
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of entries to return
+//	    type: integer
+//	    example: 100
+//	  - in: query
+//	    name: offset
+//	    description: Number of entries to skip
+//	    type: integer
+//	    example: 200
+//	  - in: query
+//	    name: sort
+//	    description: Comma-separated list of fields to sort by, prefixed with `-` for descending order
+//	    type: string
+//	    example: -created_at
+//	  - in: query
+//	    name: fields
+//	    description: Comma-separated list of fields to return
+//	    type: string
+//	    example: name,type
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -668,6 +807,11 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Invalid filter: %w", err))
 	}
 
+	pagination, err := request.PaginationParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	var poolID int64
 
 	if !allPools {
@@ -687,12 +831,11 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	var dbVolumes []*db.StorageVolume
 	var projectImages []string
 	var customVolProjectName string
 
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		if !allProjects {
+	if !allProjects {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 			dbProject, err := cluster.GetProject(ctx, tx.Tx(), requestProjectName)
 			if err != nil {
 				return err
@@ -711,8 +854,55 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 			if err != nil {
 				return err
 			}
+
+			return nil
+		})
+		if err != nil {
+			return response.SmartError(err)
 		}
 
+		// If we're requesting for just one project, set the effective project name of volumes in this project.
+		request.SetContextValue(r, request.CtxEffectiveProjectName, customVolProjectName)
+	}
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeStorageVolume)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The auth.PermissionChecker expects the url to contain the request project (not the effective project).
+	// So when getting networks in a single project, ensure we use the request project name.
+	authCheckProject := func(dbProject string) string {
+		if !allProjects {
+			return requestProjectName
+		}
+
+		return dbProject
+	}
+
+	// Select the page of volumes in the database so that only those are loaded, unless the volumes must be loaded
+	// to be filtered. Their page is then selected once they're filtered.
+	filterLoaded := clauses != nil && len(clauses.Clauses) > 0
+	dbPagination := pagination
+	if filterLoaded {
+		dbPagination = &request.Pagination{Sort: pagination.Sort}
+	}
+
+	// Filter out the volumes the caller doesn't have permission to view, and the image volumes not used by the
+	// project unless they're filtered once loaded.
+	volumeFilter := func(dbVol *db.StorageVolume) bool {
+		if !filterLoaded && dbVol.Type == cluster.StoragePoolVolumeTypeNameImage && !allProjects && !slices.Contains(projectImages, dbVol.Name) {
+			return false
+		}
+
+		volumeName, _, _ := api.GetParentAndSnapshotName(dbVol.Name)
+
+		return userHasPermission(entity.StorageVolumeURL(authCheckProject(dbVol.Project), dbVol.Location, dbVol.Pool, dbVol.Type, volumeName))
+	}
+
+	var dbVolumes []*db.StorageVolume
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		filters := make([]db.StorageVolumeFilter, 0)
 
 		for i := range supportedVolumeTypes {
@@ -755,32 +945,33 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		if allPools {
-			dbVolumes, err = tx.GetStorageVolumes(ctx, memberSpecific, filters...)
-			if err != nil {
-				return fmt.Errorf("Failed loading storage volumes: %w", err)
+		if !allPools {
+			for i := range filters {
+				filters[i].PoolID = &poolID
 			}
-
-			return err
-		}
-
-		for i := range filters {
-			filters[i].PoolID = &poolID
 		}
 
-		dbVolumes, err = tx.GetStorageVolumes(ctx, memberSpecific, filters...)
+		dbVolumes, err = tx.GetStorageVolumesPage(ctx, memberSpecific, dbPagination, volumeFilter, filters...)
 		if err != nil {
 			return fmt.Errorf("Failed loading storage volumes: %w", err)
 		}
 
-		return err
+		return nil
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
-	// Pre-fill UsedBy if using filtering.
-	if clauses != nil && len(clauses.Clauses) > 0 {
+	// Fill in the ownership of the volumes before filtering, so that it can be filtered on.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return storageVolumesOwnershipFill(ctx, tx, dbVolumes...)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if filterLoaded {
+		// Pre-fill UsedBy to filter on it.
 		for i, vol := range dbVolumes {
 			volumeUsedBy, err := storagePoolVolumeUsedByGet(s, requestProjectName, vol)
 			if err != nil {
@@ -789,44 +980,14 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 
 			dbVolumes[i].UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, volumeUsedBy)
 		}
-	}
-
-	// Filter the results.
-	dbVolumes, err = filterVolumes(dbVolumes, clauses, allProjects, projectImages)
-	if err != nil {
-		return response.SmartError(err)
-	}
 
-	// Sort by type then volume name.
-	sort.SliceStable(dbVolumes, func(i, j int) bool {
-		volA := dbVolumes[i]
-		volB := dbVolumes[j]
-
-		if volA.Type != volB.Type {
-			return dbVolumes[i].Type < dbVolumes[j].Type
-		}
-
-		return volA.Name < volB.Name
-	})
-
-	// If we're requesting for just one project, set the effective project name of volumes in this project.
-	if !allProjects {
-		request.SetContextValue(r, request.CtxEffectiveProjectName, customVolProjectName)
-	}
-
-	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeStorageVolume)
-	if err != nil {
-		return response.SmartError(err)
-	}
-
-	// The auth.PermissionChecker expects the url to contain the request project (not the effective project).
-	// So when getting networks in a single project, ensure we use the request project name.
-	authCheckProject := func(dbProject string) string {
-		if !allProjects {
-			return requestProjectName
+		// Filter the results and select their page.
+		dbVolumes, err = filterVolumes(dbVolumes, clauses, allProjects, projectImages)
+		if err != nil {
+			return response.SmartError(err)
 		}
 
-		return dbProject
+		dbVolumes = request.Page(pagination, dbVolumes)
 	}
 
 	if util.IsRecursionRequest(r) {
@@ -835,11 +996,6 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 		for _, dbVol := range dbVolumes {
 			vol := &dbVol.StorageVolume
 
-			volumeName, _, _ := api.GetParentAndSnapshotName(vol.Name)
-			if !userHasPermission(entity.StorageVolumeURL(authCheckProject(vol.Project), vol.Location, dbVol.Pool, dbVol.Type, volumeName)) {
-				continue
-			}
-
 			// Fill in UsedBy if we haven't previously done so.
 			if clauses == nil || len(clauses.Clauses) == 0 {
 				volumeUsedBy, err := storagePoolVolumeUsedByGet(s, requestProjectName, dbVol)
@@ -861,17 +1017,16 @@ func storagePoolVolumesGet(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		return response.SyncResponse(true, volumes)
+		result, err := pagination.SelectFields(volumes)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, result)
 	}
 
 	urls := make([]string, 0, len(dbVolumes))
 	for _, dbVol := range dbVolumes {
-		volumeName, _, _ := api.GetParentAndSnapshotName(dbVol.Name)
-
-		if !userHasPermission(entity.StorageVolumeURL(authCheckProject(dbVol.Project), dbVol.Location, dbVol.Pool, dbVol.Type, volumeName)) {
-			continue
-		}
-
 		urls = append(urls, dbVol.StorageVolume.URL(version.APIVersion).String())
 	}
 
@@ -1036,6 +1191,11 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = admitRequest(r.Context(), s, projectName, entity.TypeStorageVolume, "create", req.Name, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check new volume name is valid.
 	err = storageDrivers.ValidVolumeName(req.Name)
 	if err != nil {
@@ -2066,7 +2226,11 @@ func storagePoolVolumeGet(d *Daemon, r *http.Request) response.Response {
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Get the storage volume.
 		dbVolume, err = tx.GetStoragePoolVolume(ctx, details.pool.ID(), effectiveProjectName, details.volumeType, details.volumeName, true)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return storageVolumesOwnershipFill(ctx, tx, dbVolume)
 	})
 	if err != nil {
 		return response.SmartError(err)
@@ -2183,6 +2347,13 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if req.Restore == "" {
+		err = admitRequest(r.Context(), s, effectiveProjectName, entity.TypeStorageVolume, "update", details.volumeName, &req)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Use an empty operation for this sync response to pass the requestor
 	op := &operations.Operation{}
 	op.SetRequestor(r.Context())
@@ -2350,6 +2521,11 @@ func storagePoolVolumePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = admitRequest(r.Context(), s, effectiveProjectName, entity.TypeStorageVolume, "update", details.volumeName, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Use an empty operation for this sync response to pass the requestor
 	op := &operations.Operation{}
 	op.SetRequestor(r.Context())
@@ -2438,17 +2614,33 @@ func doStoragePoolVolumeDelete(ctx context.Context, s *state.State, name string,
 		return api.StatusErrorf(http.StatusBadRequest, "Storage volumes of type %q cannot be deleted directly", volType.String())
 	}
 
-	// Get the storage volume.
+	// Get the storage volume and the project it was requested from.
 	var dbVolume *db.StorageVolume
+	var requestProject *api.Project
 	var err error
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), effectiveProjectName, volType, name, true)
+		if err != nil {
+			return err
+		}
+
+		dbProject, err := cluster.GetProject(ctx, tx.Tx(), requestProjectName)
+		if err != nil {
+			return err
+		}
+
+		requestProject, err = dbProject.ToAPI(ctx, tx.Tx())
+
 		return err
 	})
 	if err != nil {
 		return err
 	}
 
+	if volType == cluster.StoragePoolVolumeTypeCustom && project.DeleteProtectedFromRecord(requestProject, nil) {
+		return api.NewStatusError(http.StatusBadRequest, "The storage volume is protected from being deleted")
+	}
+
 	volumeUsedBy, err := storagePoolVolumeUsedByGet(s, requestProjectName, dbVolume)
 	if err != nil {
 		return err
@@ -2749,8 +2941,8 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
 		}
 
-		// Dump tarball to storage.
-		err = pool.CreateCustomVolumeFromBackup(*bInfo, backupFile, nil)
+		// Dump tarball to storage, stopping once the cancellation of the operation is requested.
+		err = pool.CreateCustomVolumeFromBackup(*bInfo, op.CheckpointReadSeeker(backupFile), nil)
 		if err != nil {
 			return fmt.Errorf("Create custom volume from backup: %w", err)
 		}
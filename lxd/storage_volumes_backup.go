@@ -424,7 +424,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			CompressionAlgorithm: req.CompressionAlgorithm,
 		}
 
-		err := volumeBackupCreate(s, args, effectiveProjectName, details.pool.Name(), details.volumeName, req.Version)
+		err := volumeBackupCreate(s, args, effectiveProjectName, details.pool.Name(), details.volumeName, req.Version, op)
 		if err != nil {
 			return fmt.Errorf("Create volume backup: %w", err)
 		}
@@ -770,9 +770,16 @@ func storagePoolVolumeTypeCustomBackupDelete(d *Daemon, r *http.Request) respons
 //	    description: Cluster member name
 //	    type: string
 //	    example: lxd01
+//	  - in: header
+//	    name: Range
+//	    description: Byte range to retrieve (used to resume an interrupted download)
+//	    type: string
+//	    example: bytes=1048576-
 //	responses:
 //	  "200":
 //	    description: Raw backup data
+//	  "206":
+//	    description: Partial backup data
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
 //	  "500":
@@ -13,6 +13,7 @@ import (
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/version"
@@ -66,6 +67,7 @@ func storagePoolVolumeUpdateUsers(ctx context.Context, s *state.State, projectNa
 			Config:       inst.LocalConfig(),
 			Devices:      instancesNewDevices[i],
 			Ephemeral:    inst.IsEphemeral(),
+			Labels:       inst.Labels(),
 			Profiles:     inst.Profiles(),
 			Project:      inst.Project().Name,
 			Type:         inst.Type(),
@@ -187,6 +189,22 @@ func storagePoolVolumeUsedByGet(s *state.State, requestProjectName string, vol *
 		return []string{}, err
 	}
 
+	// Add the projects that custom volumes are shared with.
+	if vol.Type == cluster.StoragePoolVolumeTypeNameCustom {
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			sharedWith, err := cluster.GetProjectSharesUsedBy(ctx, tx.Tx(), entity.TypeStorageVolume, int(vol.ID))
+			if err != nil {
+				return err
+			}
+
+			volumeUsedBy = append(volumeUsedBy, sharedWith...)
+			return nil
+		})
+		if err != nil {
+			return []string{}, err
+		}
+	}
+
 	// Handle instance volumes.
 	if vol.Type == cluster.StoragePoolVolumeTypeNameVM {
 		volName, snapName, isSnap := api.GetParentAndSnapshotName(vol.Name)
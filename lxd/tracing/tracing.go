@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/canonical/lxd/shared/version"
+)
+
+// tracerName is the instrumentation scope of the spans emitted by LXD.
+const tracerName = "github.com/canonical/lxd"
+
+// shutdownTimeout is how long to wait for the pending spans to be exported when the exporter is stopped.
+const shutdownTimeout = 5 * time.Second
+
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+)
+
+// propagator handles the W3C trace context headers of the API requests.
+var propagator = propagation.TraceContext{}
+
+// Setup (re)configures the export of spans to the OTLP collector listening on the given gRPC endpoint.
+// Only the given percentage of the traces started locally is exported. An empty endpoint disables the export.
+func Setup(endpoint string, insecure bool, sampling int64, serverName string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Stop any existing exporter.
+	if provider != nil {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		stop(provider)
+		provider = nil
+	}
+
+	if endpoint == "" {
+		return nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	// The exporter connects lazily, so an unreachable collector doesn't prevent LXD from starting.
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("Failed creating OTLP trace exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "lxd"),
+		attribute.String("service.version", version.Version),
+		attribute.String("service.instance.id", serverName),
+	)
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(sampling)/100))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return nil
+}
+
+// Stop flushes the pending spans and stops the exporter, if any.
+func Stop() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if provider != nil {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		stop(provider)
+		provider = nil
+	}
+}
+
+// stop flushes the pending spans of the given provider and shuts it down.
+func stop(p *sdktrace.TracerProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	_ = p.Shutdown(ctx)
+}
+
+// Start starts a new span with the given name and attributes, as a child of the span held by ctx if any.
+// The returned span must be ended by the caller. This is a no-op if the export of spans is disabled.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Detach returns a new empty context holding the span of ctx, if any. This allows long running tasks spawned by a
+// request to be traced as part of it, without being cancelled alongside the request.
+func Detach(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// StartRequest starts the server span of an API request, as a child of the span identified by its "traceparent"
+// header if any. The header is then set to the new span, so that the trace ID of the request matches the exported
+// trace and requests forwarded to other cluster members are children of the new span.
+func StartRequest(r *http.Request, name string, attrs ...attribute.KeyValue) trace.Span {
+	ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+
+	if span.SpanContext().IsValid() {
+		propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	}
+
+	*r = *r.WithContext(ctx)
+
+	return span
+}
+
+// End records the given error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestStartRequestDisabled(t *testing.T) {
+	require.NoError(t, Setup("", false, 100, "node1"))
+
+	// The incoming trace context is kept as is.
+	r := httptest.NewRequest("GET", "/1.0", nil)
+	r.Header.Set("traceparent", testTraceparent)
+
+	span := StartRequest(r, "GET /1.0")
+	assert.False(t, span.IsRecording())
+	assert.Equal(t, testTraceparent, r.Header.Get("traceparent"))
+
+	_, span = Start(context.Background(), "test")
+	assert.False(t, span.IsRecording())
+}
+
+func TestStartRequest(t *testing.T) {
+	// The exporter connects lazily, so no collector is needed as long as no span is ended.
+	require.NoError(t, Setup("127.0.0.1:4317", true, 0, "node1"))
+	t.Cleanup(Stop)
+
+	// Sampled traces of the caller are continued.
+	r := httptest.NewRequest("GET", "/1.0", nil)
+	r.Header.Set("traceparent", testTraceparent)
+
+	span := StartRequest(r, "GET /1.0")
+	assert.True(t, span.IsRecording())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+
+	// The header now identifies the new span, for the requests forwarded to other cluster members.
+	fields := strings.Split(r.Header.Get("traceparent"), "-")
+	require.Len(t, fields, 4)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields[1])
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields[2])
+	assert.Equal(t, "01", fields[3])
+
+	// Spans started from the request context are children of the request span.
+	ctx, child := Start(r.Context(), "transaction")
+	assert.True(t, child.IsRecording())
+	assert.Equal(t, span.SpanContext().TraceID(), child.SpanContext().TraceID())
+
+	// Detached contexts keep the span but aren't cancelled alongside the request.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	detached := Detach(cancelCtx)
+	assert.NoError(t, detached.Err())
+
+	_, grandChild := Start(detached, "operation")
+	assert.Equal(t, span.SpanContext().TraceID(), grandChild.SpanContext().TraceID())
+
+	// New traces are only sampled according to the configured percentage.
+	r = httptest.NewRequest("GET", "/1.0", nil)
+	span = StartRequest(r, "GET /1.0")
+	assert.False(t, span.IsRecording())
+	assert.True(t, span.SpanContext().IsValid())
+	assert.True(t, strings.HasSuffix(r.Header.Get("traceparent"), "-00"))
+}
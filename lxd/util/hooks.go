@@ -0,0 +1,51 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared"
+)
+
+// HookPath returns the path of the host-side hook with the given name.
+// Only the executables placed by the operator in the hooks directory of LXD can be used as hooks, so the hook
+// must be a regular file owned by root and not writable by other users.
+func HookPath(name string) (string, error) {
+	err := instancetype.ValidHookName(name)
+	if err != nil {
+		return "", err
+	}
+
+	path := shared.VarPath("hooks", name)
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("Hook %q isn't registered in %q", name, shared.VarPath("hooks"))
+		}
+
+		return "", err
+	}
+
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("Hook %q isn't a regular file", name)
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Uid != 0 {
+		return "", fmt.Errorf("Hook %q isn't owned by root", name)
+	}
+
+	if info.Mode().Perm()&0022 != 0 {
+		return "", fmt.Errorf("Hook %q is writable by other users", name)
+	}
+
+	if info.Mode().Perm()&0100 == 0 {
+		return "", fmt.Errorf("Hook %q isn't executable", name)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,44 @@
+package util_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/util"
+)
+
+func Test_HookPath(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Hooks must be owned by root")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("LXD_DIR", dir)
+
+	hooksDir := filepath.Join(dir, "hooks")
+	require.NoError(t, os.Mkdir(hooksDir, 0700))
+
+	for name, mode := range map[string]os.FileMode{"valid": 0755, "writable": 0777, "data": 0644} {
+		require.NoError(t, os.WriteFile(filepath.Join(hooksDir, name), []byte("#!/bin/sh\n"), mode))
+		require.NoError(t, os.Chmod(filepath.Join(hooksDir, name), mode))
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(hooksDir, "dir"), 0755))
+	require.NoError(t, os.Symlink("valid", filepath.Join(hooksDir, "link")))
+
+	path, err := util.HookPath("valid")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(hooksDir, "valid"), path)
+
+	for _, name := range []string{"", "../valid", ".valid", "missing", "writable", "data", "dir", "link"} {
+		t.Run(fmt.Sprintf("%q", name), func(t *testing.T) {
+			_, err := util.HookPath(name)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"golang.org/x/sys/unix"
 
+	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
@@ -78,10 +79,16 @@ func EtagHash(data any) (string, error) {
 }
 
 // EtagCheck validates the hash of the current state with the hash
-// provided by the client.
+// provided by the client. A missing hash is only accepted if the request
+// doesn't require one.
 func EtagCheck(r *http.Request, data any) error {
 	match := r.Header.Get("If-Match")
 	if match == "" {
+		required, _ := request.GetContextValue[bool](r.Context(), request.CtxEtagRequired)
+		if required {
+			return api.StatusErrorf(http.StatusPreconditionRequired, "An If-Match header with the ETag of the current configuration is required. Please retrieve the configuration before changing it.")
+		}
+
 		return nil
 	}
 
@@ -2,6 +2,11 @@ package util
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func ExampleListenAddresses() {
@@ -38,3 +43,33 @@ func ExampleListenAddresses() {
 	// "foo:8000:9000": [] address foo:8000:9000: too many colons in address
 	// ":::8000": [] address :::8000: too many colons in address
 }
+
+func ExampleEtagCheck() {
+	data := []string{"foo"}
+	hash, _ := EtagHash(data)
+
+	for _, match := range []string{"", `"` + hash + `"`, "bad"} {
+		for _, required := range []bool{false, true} {
+			r := httptest.NewRequest(http.MethodPut, "/1.0/instances/c1", nil)
+			r.Header.Set("If-Match", match)
+			if required {
+				request.SetContextValue(r, request.CtxEtagRequired, true)
+			}
+
+			err := EtagCheck(r, data)
+			code := 0
+			if err != nil {
+				code, _ = api.StatusErrorMatch(err)
+			}
+
+			fmt.Printf("%t %t: %d\n", match != "", required, code)
+		}
+	}
+
+	// Output: false false: 0
+	// false true: 428
+	// true false: 0
+	// true true: 0
+	// true false: 412
+	// true true: 412
+}
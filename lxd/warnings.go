@@ -285,7 +285,12 @@ func warningGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	return response.SyncResponse(true, resp)
+	return response.SyncResponseETag(true, resp, warningEtag(resp))
+}
+
+// warningEtag returns the ETag data of the updatable status of the warning.
+func warningEtag(warning api.Warning) []any {
+	return []any{warning.Status, warning.AcknowledgedComment}
 }
 
 // swagger:operation PATCH /1.0/warnings/{uuid} warnings warning_patch
@@ -313,6 +318,8 @@ func warningGet(d *Daemon, r *http.Request) response.Response {
 //	    $ref: "#/responses/BadRequest"
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func warningPatch(d *Daemon, r *http.Request) response.Response {
@@ -324,6 +331,7 @@ func warningPatch(d *Daemon, r *http.Request) response.Response {
 //	Update the warning
 //
 //	Updates the warning status.
+//	When acknowledging the warning, the identity of the caller is recorded along with an optional comment.
 //
 //	---
 //	consumes:
@@ -344,6 +352,8 @@ func warningPatch(d *Daemon, r *http.Request) response.Response {
 //	    $ref: "#/responses/BadRequest"
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func warningPut(d *Daemon, r *http.Request) response.Response {
@@ -372,6 +382,10 @@ func warningPut(d *Daemon, r *http.Request) response.Response {
 		return response.Forbidden(errors.New(`Status may only be set to "acknowledge" or "new"`))
 	}
 
+	if req.Comment != "" && status != warningtype.StatusAcknowledged {
+		return response.BadRequest(errors.New("A comment may only be given when acknowledging a warning"))
+	}
+
 	var warning *cluster.Warning
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		warning, err = cluster.GetWarning(ctx, tx.Tx(), id)
@@ -379,12 +393,17 @@ func warningPut(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
-		err := tx.UpdateWarningStatus(id, status)
+		// Validate the ETag.
+		err = util.EtagCheck(r, warningEtag(warning.ToAPI()))
 		if err != nil {
 			return err
 		}
 
-		return nil
+		if status == warningtype.StatusAcknowledged {
+			return tx.AcknowledgeWarning(id, request.CreateRequestor(r.Context()).Username, req.Comment)
+		}
+
+		return tx.UpdateWarningStatus(id, status)
 	})
 	if err != nil {
 		return response.SmartError(err)
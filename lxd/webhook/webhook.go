@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/cancel"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+const (
+	// queueSize is the maximum number of events waiting to be delivered to a webhook.
+	// Events are dropped if the webhook can't keep up.
+	queueSize = 1000
+
+	// maxAttempts is the maximum number of delivery attempts of an event.
+	maxAttempts = 5
+
+	// retryDelay is the delay before the first retry. It doubles on each subsequent retry.
+	retryDelay = time.Second
+
+	// timeout is the timeout of a single delivery attempt.
+	timeout = 10 * time.Second
+)
+
+// SignatureHeader is the header holding the HMAC-SHA256 signature of the request body, if a secret is configured.
+const SignatureHeader = "X-LXD-Signature"
+
+// EventTypeHeader is the header holding the type of the delivered event.
+const EventTypeHeader = "X-LXD-Event-Type"
+
+// delivery is an event waiting to be delivered.
+type delivery struct {
+	eventType string
+	body      []byte
+}
+
+// target is a webhook URL along with its queue of events waiting to be delivered.
+type target struct {
+	url        string
+	deliveries chan delivery
+}
+
+// Client delivers events to webhooks.
+type Client struct {
+	secret   []byte
+	types    []string
+	projects []string
+
+	client  *http.Client
+	targets []*target
+	cancel  cancel.Canceller
+	wg      sync.WaitGroup
+}
+
+// NewClient returns a Client delivering the events of the given types to the given URLs.
+// Only the events of the given projects are delivered, unless no project is given.
+func NewClient(urls []string, secret string, types []string, projects []string) (*Client, error) {
+	c := &Client{
+		secret:   []byte(secret),
+		types:    types,
+		projects: projects,
+		client:   &http.Client{Timeout: timeout},
+		cancel:   cancel.New(),
+	}
+
+	for _, rawURL := range urls {
+		u, err := url.ParseRequestURI(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid webhook URL %q: %w", rawURL, err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("Invalid webhook URL %q: Unsupported scheme %q", rawURL, u.Scheme)
+		}
+
+		c.targets = append(c.targets, &target{url: u.String(), deliveries: make(chan delivery, queueSize)})
+	}
+
+	for _, t := range c.targets {
+		c.wg.Add(1)
+		go c.run(t)
+	}
+
+	return c, nil
+}
+
+// Stop the client. Events which weren't delivered yet are dropped.
+func (c *Client) Stop() {
+	c.cancel.Cancel()
+	c.wg.Wait()
+}
+
+// HandleEvent queues the event received from the internal event listener for delivery.
+//
+// Warn: This must not log, as it is called for logging events too.
+func (c *Client) HandleEvent(event api.Event) {
+	if !slices.Contains(c.types, event.Type) {
+		return
+	}
+
+	if len(c.projects) > 0 && !slices.Contains(c.projects, event.Project) {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	for _, t := range c.targets {
+		select {
+		case t.deliveries <- delivery{eventType: event.Type, body: body}:
+		default:
+		}
+	}
+}
+
+// run delivers the queued events to the target until the client is stopped.
+func (c *Client) run(t *target) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.cancel.Done():
+			return
+		case d := <-t.deliveries:
+			err := c.deliver(t.url, d)
+			if err != nil && c.cancel.Err() == nil {
+				logger.Warn("Failed delivering event to webhook", logger.Ctx{"url": t.url, "err": err})
+			}
+		}
+	}
+}
+
+// deliver posts the event to the URL, retrying with an exponential backoff on failure.
+func (c *Client) deliver(url string, d delivery) error {
+	var err error
+	var retry bool
+
+	delay := retryDelay
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		retry, err = c.post(url, d)
+		if err == nil || !retry || attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-c.cancel.Done():
+			return c.cancel.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+
+	return err
+}
+
+// post sends a single delivery attempt of the event. It returns whether a failed attempt should be retried.
+func (c *Client) post(url string, d delivery) (bool, error) {
+	req, err := http.NewRequestWithContext(c.cancel, http.MethodPost, url, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+	req.Header.Set(EventTypeHeader, d.eventType)
+
+	if len(c.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(c.secret, d.body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+
+	// Only retry on server errors and rate limiting, other client errors won't go away.
+	retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+
+	return retry, fmt.Errorf("Unexpected status code %d", resp.StatusCode)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body using the given secret.
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
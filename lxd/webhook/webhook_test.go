@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestClient_HandleEvent(t *testing.T) {
+	type received struct {
+		event     api.Event
+		eventType string
+		signature string
+		body      []byte
+	}
+
+	receivedCh := make(chan received, 10)
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to exercise the retry.
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		event := api.Event{}
+		_ = json.Unmarshal(body, &event)
+
+		receivedCh <- received{event: event, eventType: r.Header.Get(EventTypeHeader), signature: r.Header.Get(SignatureHeader), body: body}
+	}))
+
+	defer server.Close()
+
+	c, err := NewClient([]string{server.URL}, "secret", []string{api.EventTypeLifecycle}, []string{"foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer c.Stop()
+
+	// Filtered out by type and project.
+	c.HandleEvent(api.Event{Type: api.EventTypeLogging, Location: "node1", Project: "foo"})
+	c.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Location: "node1", Project: "bar"})
+
+	c.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Location: "node1", Project: "foo", Metadata: json.RawMessage(`{"action":"instance-created"}`)})
+
+	select {
+	case r := <-receivedCh:
+		if r.event.Project != "foo" || r.event.Location != "node1" {
+			t.Fatalf("Unexpected event delivered: %+v", r.event)
+		}
+
+		if r.eventType != api.EventTypeLifecycle {
+			t.Fatalf("Unexpected event type header %q", r.eventType)
+		}
+
+		if r.signature != "sha256="+Sign([]byte("secret"), r.body) {
+			t.Fatalf("Unexpected signature header %q", r.signature)
+		}
+
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the event")
+	}
+
+	select {
+	case r := <-receivedCh:
+		t.Fatalf("Unexpected event delivered: %+v", r.event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 const (
 	// AuthenticationMethodTLS is the default authentication method for interacting with LXD remotely.
 	AuthenticationMethodTLS = "tls"
@@ -83,20 +87,62 @@ type Identity struct {
 	// Example: Jane Doe
 	Name string `json:"name" yaml:"name"`
 
+	// DisplayName is the name chosen by the identity itself.
+	// Example: Jane
+	//
+	// API extension: auth_identity_current_patch.
+	DisplayName string `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+
 	// Groups is the list of groups for which the identity is a member.
 	// Example: ["foo", "bar"]
 	Groups []string `json:"groups" yaml:"groups"`
 
+	// GroupExpiries is a map of group name to the date at which the membership of the identity to the group expires.
+	// Memberships to groups that aren't in the map don't expire.
+	// Example: {"foo": "2025-01-01T00:00:00Z"}
+	//
+	// API extension: auth_expiring_grants.
+	GroupExpiries map[string]time.Time `json:"group_expiries,omitempty" yaml:"group_expiries,omitempty"`
+
 	// TLSCertificate is a PEM encoded x509 certificate. This is only set if the AuthenticationMethod is AuthenticationMethodTLS.
 	//
 	// API extension: access_management_tls.
 	TLSCertificate string `json:"tls_certificate" yaml:"tls_certificate"`
+
+	// Delegation is set for identities created by a project administrator and confined to their project.
+	//
+	// API extension: auth_delegated_identities.
+	Delegation *IdentityDelegation `json:"delegation,omitempty" yaml:"delegation,omitempty"`
+}
+
+// IdentityDelegation records how an identity confined to a project was created.
+//
+// swagger:model
+//
+// API extension: auth_delegated_identities.
+type IdentityDelegation struct {
+	// Parent is the URL of the identity that created the identity.
+	// Example: /1.0/auth/identities/tls/e1e06266e36f67431c996d5678e66d732dfd12fe5073c161e62e6360619fc226
+	Parent string `json:"parent" yaml:"parent"`
+
+	// Project is the project the identity is confined to.
+	// Example: foo
+	Project string `json:"project" yaml:"project"`
+
+	// Group is the group holding the permissions delegated to the identity.
+	// Example: delegation-5b9c0d5e-0b5f-4bd8-9e5c-5b1f6a8a9e36
+	Group string `json:"group" yaml:"group"`
+
+	// CreatedAt is the date at which the identity was created.
+	// Example: 2025-01-01T00:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
 }
 
 // Writable converts a Identity struct into a IdentityPut struct (filters read-only fields).
 func (i Identity) Writable() IdentityPut {
 	return IdentityPut{
 		Groups:         i.Groups,
+		GroupExpiries:  i.GroupExpiries,
 		TLSCertificate: i.TLSCertificate,
 	}
 }
@@ -104,6 +150,7 @@ func (i Identity) Writable() IdentityPut {
 // SetWritable sets applicable values from IdentityPut struct to Identity struct.
 func (i *Identity) SetWritable(put IdentityPut) {
 	i.Groups = put.Groups
+	i.GroupExpiries = put.GroupExpiries
 	i.TLSCertificate = put.TLSCertificate
 }
 
@@ -140,12 +187,38 @@ type IdentityPut struct {
 	// Example: ["foo", "bar"]
 	Groups []string `json:"groups" yaml:"groups"`
 
+	// GroupExpiries is a map of group name to the date at which the membership of the identity to the group expires.
+	// Memberships to groups that aren't in the map don't expire.
+	// Example: {"foo": "2025-01-01T00:00:00Z"}
+	//
+	// API extension: auth_expiring_grants.
+	GroupExpiries map[string]time.Time `json:"group_expiries,omitempty" yaml:"group_expiries,omitempty"`
+
 	// TLSCertificate is a base64 encoded x509 certificate. This can only be set if the authentication method of the identity is AuthenticationMethodTLS.
 	//
 	// API extension: access_management_tls.
 	TLSCertificate string `json:"tls_certificate" yaml:"tls_certificate"`
 }
 
+// IdentityCurrentPut contains the fields of the current identity that the identity can edit itself.
+//
+// swagger:model
+//
+// API extension: auth_identity_current_patch.
+type IdentityCurrentPut struct {
+	// DisplayName is the name chosen by the identity itself.
+	// Example: Jane
+	DisplayName string `json:"display_name" yaml:"display_name"`
+
+	// TLSCertificate is a PEM encoded x509 certificate replacing the current one. This can only be set if the
+	// authentication method of the identity is AuthenticationMethodTLS.
+	TLSCertificate string `json:"tls_certificate" yaml:"tls_certificate"`
+
+	// RevokeSessions revokes the sessions and tokens of the identity that were started before the request.
+	// Example: true
+	RevokeSessions bool `json:"revoke_sessions" yaml:"revoke_sessions"`
+}
+
 // IdentitiesTLSPost contains required information for the creation of a TLS identity.
 //
 // swagger:model
@@ -170,6 +243,19 @@ type IdentitiesTLSPost struct {
 	// Groups is the list of groups for which the identity is a member.
 	// Example: ["foo", "bar"]
 	Groups []string `json:"groups" yaml:"groups"`
+
+	// Project confines the identity to the given project. Only the permissions on entities of the project can be
+	// granted to it and the caller only needs the `can_create_identities` entitlement on the project.
+	// Example: foo
+	//
+	// API extension: auth_delegated_identities.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+
+	// Permissions are the permissions delegated to the identity confined to the project. The caller must hold each of
+	// them.
+	//
+	// API extension: auth_delegated_identities.
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 }
 
 // IdentitiesBearerPost contains required information for the creation of a token identity.
@@ -189,6 +275,19 @@ type IdentitiesBearerPost struct {
 	// Groups is the list of groups for which the identity is a member.
 	// Example: ["foo", "bar"]
 	Groups []string `json:"groups" yaml:"groups"`
+
+	// Project confines the identity to the given project. Only the permissions on entities of the project can be
+	// granted to it and the caller only needs the `can_create_identities` entitlement on the project.
+	// Example: foo
+	//
+	// API extension: auth_delegated_identities.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+
+	// Permissions are the permissions delegated to the identity confined to the project. The caller must hold each of
+	// them.
+	//
+	// API extension: auth_delegated_identities.
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 }
 
 // IdentityBearerToken contains a token issued for an identity whose authentication method is
@@ -236,6 +335,11 @@ type AuthGroup struct {
 	// includes this group.
 	// Example: ["sales", "operations"]
 	IdentityProviderGroups []string `json:"identity_provider_groups" yaml:"identity_provider_groups"`
+
+	// Roles are a list of roles granted to the group on entities.
+	//
+	// API extension: auth_roles
+	Roles []RoleGrant `json:"roles,omitempty" yaml:"roles,omitempty"`
 }
 
 // Writable converts a AuthGroup struct into a AuthGroupPut struct (filters read-only fields).
@@ -243,6 +347,7 @@ func (g AuthGroup) Writable() AuthGroupPut {
 	return AuthGroupPut{
 		Description: g.Description,
 		Permissions: g.Permissions,
+		Roles:       g.Roles,
 	}
 }
 
@@ -250,6 +355,7 @@ func (g AuthGroup) Writable() AuthGroupPut {
 func (g *AuthGroup) SetWritable(put AuthGroupPut) {
 	g.Description = put.Description
 	g.Permissions = put.Permissions
+	g.Roles = put.Roles
 }
 
 // AuthGroupsPost is used for creating a new group.
@@ -285,6 +391,118 @@ type AuthGroupPut struct {
 
 	// Permissions are a list of permissions.
 	Permissions []Permission `json:"permissions" yaml:"permissions"`
+
+	// Roles are a list of roles granted to the group on entities.
+	//
+	// API extension: auth_roles
+	Roles []RoleGrant `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// AuthRole is a named set of entitlements that can be granted to groups on entities.
+//
+// swagger:model
+//
+// API extension: auth_roles.
+type AuthRole struct {
+	// Name is the name of the role.
+	// Example: network-operator
+	Name string `json:"name" yaml:"name"`
+
+	// Description is a short description of the role.
+	// Example: Manages networks without deleting them.
+	Description string `json:"description" yaml:"description"`
+
+	// Entitlements are the entitlements given by the role on the entities it is granted on.
+	Entitlements []AuthRoleEntitlement `json:"entitlements" yaml:"entitlements"`
+
+	// UsedBy is a list of URLs of the groups that the role is granted to.
+	// Read only: true
+	// Example: ["/1.0/auth/groups/network-operators"]
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+}
+
+// Writable converts a AuthRole struct into a AuthRolePut struct (filters read-only fields).
+func (r AuthRole) Writable() AuthRolePut {
+	return AuthRolePut{
+		Description:  r.Description,
+		Entitlements: r.Entitlements,
+	}
+}
+
+// SetWritable sets applicable values from AuthRolePut struct to AuthRole struct.
+func (r *AuthRole) SetWritable(put AuthRolePut) {
+	r.Description = put.Description
+	r.Entitlements = put.Entitlements
+}
+
+// AuthRolesPost is used for creating a new role.
+//
+// swagger:model
+//
+// API extension: auth_roles.
+type AuthRolesPost struct {
+	AuthRolePost `yaml:",inline"`
+	AuthRolePut  `yaml:",inline"`
+}
+
+// AuthRolePost is used for renaming a role.
+//
+// swagger:model
+//
+// API extension: auth_roles.
+type AuthRolePost struct {
+	// Name is the name of the role.
+	// Example: network-operator
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthRolePut contains the editable fields of a role.
+//
+// swagger:model
+//
+// API extension: auth_roles.
+type AuthRolePut struct {
+	// Description is a short description of the role.
+	// Example: Manages networks without deleting them.
+	Description string `json:"description" yaml:"description"`
+
+	// Entitlements are the entitlements given by the role on the entities it is granted on.
+	Entitlements []AuthRoleEntitlement `json:"entitlements" yaml:"entitlements"`
+}
+
+// AuthRoleEntitlement is an entitlement given by a role on the entities of a type.
+//
+// swagger:model
+//
+// API extension: auth_roles.
+type AuthRoleEntitlement struct {
+	// EntityType is the string representation of the entity type.
+	// Example: network
+	EntityType string `json:"entity_type" yaml:"entity_type"`
+
+	// Entitlement is the entitlement given on the entities of the type.
+	// Example: can_edit
+	Entitlement string `json:"entitlement" yaml:"entitlement"`
+}
+
+// RoleGrant is a role granted to a group on an entity. The group is given the entitlements of the role which apply
+// to the type of the entity.
+//
+// swagger:model
+//
+// API extension: auth_roles.
+type RoleGrant struct {
+	// Role is the name of the role.
+	// Example: network-operator
+	Role string `json:"role" yaml:"role"`
+
+	// EntityType is the string representation of the entity type.
+	// Example: network
+	EntityType string `json:"entity_type" yaml:"entity_type"`
+
+	// EntityReference is the URL of the entity that the role is granted on.
+	// Example: /1.0/networks/lxdbr0?project=default
+	EntityReference string `json:"url" yaml:"url"`
 }
 
 // IdentityProviderGroup represents a mapping between LXD groups and groups defined by an identity provider.
@@ -353,6 +571,12 @@ type Permission struct {
 	// Entitlement is the entitlement define for the entity type.
 	// Example: can_view
 	Entitlement string `json:"entitlement" yaml:"entitlement"`
+
+	// ExpiresAt is the date at which the permission expires. Permissions without an expiry date don't expire.
+	// Example: 2025-01-01T00:00:00Z
+	//
+	// API extension: auth_expiring_grants.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
 }
 
 // PermissionInfo expands a Permission to include any groups that may have the specified Permission.
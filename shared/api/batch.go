@@ -0,0 +1,92 @@
+package api
+
+// BatchModeBestEffort executes all the requests of a batch, regardless of the failure of any of them.
+const BatchModeBestEffort = "best-effort"
+
+// BatchModeAllOrNothing stops a batch at the first failed request and reverts the requests that succeeded.
+const BatchModeAllOrNothing = "all-or-nothing"
+
+// BatchPost represents a batch of API requests.
+//
+// swagger:model
+//
+// API extension: batch.
+type BatchPost struct {
+	// How the requests are executed ("best-effort" or "all-or-nothing")
+	// Example: all-or-nothing
+	Mode string `json:"mode" yaml:"mode"`
+
+	// List of requests, executed in order
+	Requests []BatchRequest `json:"requests" yaml:"requests"`
+}
+
+// BatchRequest represents a single API request of a batch.
+//
+// swagger:model
+//
+// API extension: batch.
+type BatchRequest struct {
+	// HTTP method of the request
+	// Example: PATCH
+	Method string `json:"method" yaml:"method"`
+
+	// URL of the request, including its query string
+	// Example: /1.0/profiles/default?project=default
+	URL string `json:"url" yaml:"url"`
+
+	// ETag sent as the If-Match header of the request
+	// Example: 7ec17d6a0bb1c8bb8b4a5b0ec2b8a0bc6cc3e8b5df7fc8f5b11fc8c8a79b8a11
+	ETag string `json:"etag,omitempty" yaml:"etag,omitempty"`
+
+	// Body of the request
+	// Example: {"config": {"limits.cpu": "2"}}
+	Body any `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// Batch represents the results of a batch of API requests.
+//
+// swagger:model
+//
+// API extension: batch.
+type Batch struct {
+	// Whether all the requests succeeded
+	// Example: true
+	Success bool `json:"success" yaml:"success"`
+
+	// Result of each request, in the order of the requests
+	Results []BatchResult `json:"results" yaml:"results"`
+}
+
+// BatchResult represents the result of a single API request of a batch.
+//
+// swagger:model
+//
+// API extension: batch.
+type BatchResult struct {
+	// HTTP status code of the response, or 0 if the request wasn't executed
+	// Example: 200
+	StatusCode int `json:"status_code" yaml:"status_code"`
+
+	// Error message of a failed request
+	// Example: Profile not found
+	Error string `json:"error" yaml:"error"`
+
+	// URL of the background operation started by the request
+	// Example: /1.0/operations/66e83638-9dd7-4a26-aef2-5462814869a1
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
+
+	// Location of the entity created by the request
+	// Example: /1.0/profiles/web
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// Metadata of the response
+	Metadata any `json:"metadata" yaml:"metadata"`
+
+	// Whether the changes of the request were reverted
+	// Example: false
+	Reverted bool `json:"reverted" yaml:"reverted"`
+
+	// Error message of the failed revert of the changes of the request, which are then still applied
+	// Example: Failed to update profile: Profile is in use
+	RevertError string `json:"revert_error,omitempty" yaml:"revert_error,omitempty"`
+}
@@ -87,6 +87,12 @@ type ClusterPut struct {
 	//
 	// API extension: explicit_trust_token
 	ClusterToken string `json:"cluster_token" yaml:"cluster_token"`
+
+	// Roles of the member joining the cluster
+	// Example: ["witness"]
+	//
+	// API extension: clustering_witness
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
 }
 
 // ClusterMembersPost represents the fields required to request a join token to add a member to the cluster.
@@ -98,6 +104,26 @@ type ClusterMembersPost struct {
 	// The name of the new cluster member
 	// Example: lxd02
 	ServerName string `json:"server_name" yaml:"server_name"`
+
+	// Configuration to embed in the join token and apply automatically when the member joins
+	//
+	// API extension: clustering_join_preseed
+	Preseed *ClusterMemberJoinPreseed `json:"preseed,omitempty" yaml:"preseed,omitempty"`
+}
+
+// ClusterMemberJoinPreseed represents the configuration applied by a new cluster member when joining.
+//
+// swagger:model
+//
+// API extension: clustering_join_preseed.
+type ClusterMemberJoinPreseed struct {
+	// List of member configuration keys (used during join)
+	// Example: []
+	MemberConfig []ClusterMemberConfigKey `json:"member_config,omitempty" yaml:"member_config,omitempty"`
+
+	// Roles of the new cluster member
+	// Example: ["witness"]
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
 }
 
 // ClusterMemberJoinToken represents the fields contained within an encoded cluster member join token.
@@ -125,6 +151,11 @@ type ClusterMemberJoinToken struct {
 	// The token's expiry date.
 	// Example: 2021-03-23T17:38:37.753398689-04:00
 	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// Configuration applied automatically when joining
+	//
+	// API extension: clustering_join_preseed
+	Preseed *ClusterMemberJoinPreseed `json:"preseed,omitempty" yaml:"preseed,omitempty"`
 }
 
 // String encodes the cluster member join token as JSON and then base64.
@@ -274,6 +305,53 @@ type ClusterCertificatePut struct {
 	ClusterCertificateKey string `json:"cluster_certificate_key" yaml:"cluster_certificate_key"`
 }
 
+// ClusterCertificateRotatePost represents the fields required to rotate the certificate of all members in a LXD Cluster
+//
+// swagger:model
+//
+// API extension: clustering_certificate_rotation.
+type ClusterCertificateRotatePost struct {
+	// The new certificate (X509 PEM encoded) for the cluster, generated if empty
+	// Example: X509 PEM certificate
+	ClusterCertificate string `json:"cluster_certificate" yaml:"cluster_certificate"`
+
+	// The new certificate key (X509 PEM encoded) for the cluster, generated if empty
+	// Example: X509 PEM certificate key
+	ClusterCertificateKey string `json:"cluster_certificate_key" yaml:"cluster_certificate_key"`
+}
+
+// ClusterBackup represents the index of a cluster backup archive.
+// The archive also holds dumps of the databases and the certificates of the cluster member which created it.
+//
+// swagger:model
+//
+// API extension: cluster_backup.
+type ClusterBackup struct {
+	// When the backup was created
+	// Example: 2021-03-23T20:00:00-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Name of the cluster member which created the backup
+	// Example: lxd01
+	ServerName string `json:"server_name" yaml:"server_name"`
+
+	// Version of the LXD server which created the backup
+	// Example: 6.1
+	ServerVersion string `json:"server_version" yaml:"server_version"`
+
+	// Version of the global database schema
+	// Example: 75
+	SchemaVersion int `json:"schema_version" yaml:"schema_version"`
+
+	// Number of API extensions supported by the LXD server which created the backup
+	// Example: 420
+	APIExtensions int `json:"api_extensions" yaml:"api_extensions"`
+
+	// Addresses of the cluster members by name
+	// Example: {"lxd01": "10.0.0.30:8443"}
+	Members map[string]string `json:"members" yaml:"members"`
+}
+
 // ClusterMemberStatePost represents the fields required to evacuate a cluster member.
 //
 // swagger:model
@@ -285,7 +363,7 @@ type ClusterMemberStatePost struct {
 	Action string `json:"action" yaml:"action"`
 
 	// Override the configured evacuation mode.
-	// Valid modes for the "evacuate" action are "stop", "migrate", and "live-migrate".
+	// Valid modes for the "evacuate" action are "stop", "stateful-stop", "migrate", "live-migrate" and "skip".
 	// Valid modes for the "restore" action are "skip".
 	// Example: stop
 	//
@@ -293,6 +371,27 @@ type ClusterMemberStatePost struct {
 	Mode string `json:"mode" yaml:"mode"`
 }
 
+// ClusterMemberStatePut represents the fields required to put a cluster member in or out of maintenance mode.
+//
+// swagger:model
+//
+// API extension: clustering_maintenance.
+type ClusterMemberStatePut struct {
+	// Whether the cluster member should be in maintenance mode
+	// Example: true
+	Maintenance bool `json:"maintenance" yaml:"maintenance"`
+
+	// Evacuation policy applied to all instances instead of their cluster.evacuate setting.
+	// Valid policies are "auto", "stop", "stateful-stop", "migrate", "live-migrate" and "skip".
+	// When leaving maintenance mode, "skip" leaves the instances where they are.
+	// Example: live-migrate
+	Policy string `json:"policy" yaml:"policy"`
+
+	// Evacuation policies of specific instances, keyed by "<project>/<instance>"
+	// Example: {"default/db1": "stateful-stop"}
+	InstancePolicies map[string]string `json:"instance_policies" yaml:"instance_policies"`
+}
+
 // ClusterGroupsPost represents the fields available for a new cluster group.
 //
 // swagger:model
@@ -324,6 +423,12 @@ type ClusterGroup struct {
 	// Example: ["node1", "node3"]
 	Members []string `json:"members" yaml:"members"`
 
+	// Cluster group configuration map (refer to doc/explanation/clusters.md)
+	// Example: {"images.replication": "eager"}
+	//
+	// API extension: clustering_groups_image_replication
+	Config map[string]string `json:"config" yaml:"config"`
+
 	// UsedBy is a list or LXD entity URLs that reference the cluster group.
 	//
 	// API extension: clustering_groups_used_by
@@ -354,6 +459,12 @@ type ClusterGroupPut struct {
 	// List of members in this group
 	// Example: ["node1", "node3"]
 	Members []string `json:"members" yaml:"members"`
+
+	// Cluster group configuration map (refer to doc/explanation/clusters.md)
+	// Example: {"images.replication": "eager"}
+	//
+	// API extension: clustering_groups_image_replication
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // Writable converts a full ClusterGroup struct into a ClusterGroupPut struct (filters read-only fields).
@@ -361,6 +472,7 @@ func (c *ClusterGroup) Writable() ClusterGroupPut {
 	return ClusterGroupPut{
 		Description: c.Description,
 		Members:     c.Members,
+		Config:      c.Config,
 	}
 }
 
@@ -368,4 +480,5 @@ func (c *ClusterGroup) Writable() ClusterGroupPut {
 func (c *ClusterGroup) SetWritable(put ClusterGroupPut) {
 	c.Description = put.Description
 	c.Members = put.Members
+	c.Config = put.Config
 }
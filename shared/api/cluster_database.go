@@ -0,0 +1,48 @@
+package api
+
+// ClusterDatabaseMaintenancePost represents the fields required to run maintenance tasks on the cluster database.
+//
+// swagger:model
+//
+// API extension: clustering_database_maintenance.
+type ClusterDatabaseMaintenancePost struct {
+	// Maintenance tasks to run in order (one of "integrity-check", "vacuum" or "report")
+	// Example: ["integrity-check", "vacuum"]
+	Tasks []string `json:"tasks" yaml:"tasks"`
+}
+
+// ClusterDatabaseMaintenance represents the result of the maintenance tasks run on the cluster database.
+// It is returned in the "maintenance" field of the operation metadata.
+//
+// swagger:model
+//
+// API extension: clustering_database_maintenance.
+type ClusterDatabaseMaintenance struct {
+	// Problems found by the integrity check ("ok" if the database is consistent)
+	// Example: ["ok"]
+	Integrity []string `json:"integrity,omitempty" yaml:"integrity,omitempty"`
+
+	// Size of the database in bytes
+	// Example: 10485760
+	Size int64 `json:"size" yaml:"size"`
+
+	// Number of bytes reclaimed by the vacuum
+	// Example: 2097152
+	SizeReclaimed int64 `json:"size_reclaimed" yaml:"size_reclaimed"`
+
+	// Size of the database pages in bytes
+	// Example: 4096
+	PageSize int64 `json:"page_size" yaml:"page_size"`
+
+	// Number of pages in the database
+	// Example: 2560
+	PageCount int64 `json:"page_count" yaml:"page_count"`
+
+	// Number of unused pages in the database
+	// Example: 512
+	FreePages int64 `json:"free_pages" yaml:"free_pages"`
+
+	// Number of rows in each table (report only)
+	// Example: {"instances": 42}
+	Tables map[string]int64 `json:"tables,omitempty" yaml:"tables,omitempty"`
+}
@@ -0,0 +1,42 @@
+package api
+
+// ClusterDrift represents the inconsistencies between the effective state of the cluster members.
+//
+// swagger:model
+//
+// API extension: clustering_drift.
+type ClusterDrift struct {
+	// Cluster members that were compared
+	// Example: ["lxd01", "lxd02", "lxd03"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Cluster members that couldn't be reached
+	// Example: ["lxd04"]
+	UnreachableMembers []string `json:"unreachable_members" yaml:"unreachable_members"`
+
+	// Inconsistencies found between the cluster members
+	Inconsistencies []ClusterDriftInconsistency `json:"inconsistencies" yaml:"inconsistencies"`
+}
+
+// ClusterDriftInconsistency represents a difference of state between the cluster members.
+//
+// swagger:model
+//
+// API extension: clustering_drift.
+type ClusterDriftInconsistency struct {
+	// Category of the inconsistency ("kernel", "kernel_feature", "lxc_feature", "instance_driver", "storage_driver" or "network")
+	// Example: instance_driver
+	Category string `json:"category" yaml:"category"`
+
+	// Name of the inconsistent item within the category
+	// Example: qemu
+	Name string `json:"name" yaml:"name"`
+
+	// What the inconsistency can break ("migration" or "scheduling")
+	// Example: migration
+	Impact string `json:"impact" yaml:"impact"`
+
+	// Value of the item on each cluster member (empty if missing)
+	// Example: {"lxd01": "8.2.2", "lxd02": "9.0.0"}
+	Values map[string]string `json:"values" yaml:"values"`
+}
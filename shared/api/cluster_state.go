@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ClusterMemberSysInfo represents the sysinfo of a cluster member.
 //
 // swagger:model
@@ -26,4 +30,110 @@ type ClusterMemberSysInfo struct {
 type ClusterMemberState struct {
 	SysInfo      ClusterMemberSysInfo        `json:"sysinfo" yaml:"sysinfo"`
 	StoragePools map[string]StoragePoolState `json:"storage_pools" yaml:"storage_pools"`
+
+	// Progress of the last maintenance drain of the cluster member
+	//
+	// API extension: clustering_maintenance
+	Maintenance *ClusterMemberMaintenance `json:"maintenance,omitempty" yaml:"maintenance,omitempty"`
+
+	// Resource allocation of the cluster member against its overcommit policy
+	//
+	// API extension: clustering_overcommit
+	Scheduler *ClusterMemberScheduler `json:"scheduler,omitempty" yaml:"scheduler,omitempty"`
+}
+
+// ClusterMemberScheduler represents the resource allocation of a cluster member against its overcommit policy.
+//
+// swagger:model
+//
+// API extension: clustering_overcommit.
+type ClusterMemberScheduler struct {
+	// Number of CPUs allocated to the instances of the cluster member
+	// Example: 12
+	AllocatedCPUs uint64 `json:"allocated_cpus" yaml:"allocated_cpus"`
+
+	// Maximum number of CPUs that can be allocated (0 if unlimited)
+	// Example: 32
+	CPUsLimit uint64 `json:"cpus_limit" yaml:"cpus_limit"`
+
+	// Memory allocated to the instances of the cluster member (in bytes)
+	// Example: 17179869184
+	AllocatedMemory uint64 `json:"allocated_memory" yaml:"allocated_memory"`
+
+	// Maximum memory that can be allocated (in bytes, 0 if unlimited)
+	// Example: 68719476736
+	MemoryLimit uint64 `json:"memory_limit" yaml:"memory_limit"`
+
+	// Total memory of the cluster member (in bytes)
+	// Example: 34359738368
+	MemoryTotal uint64 `json:"memory_total" yaml:"memory_total"`
+
+	// Memory currently available on the cluster member (in bytes)
+	// Example: 8589934592
+	MemoryAvailable uint64 `json:"memory_available" yaml:"memory_available"`
+
+	// Memory that must be kept available on the cluster member (in bytes)
+	// Example: 4294967296
+	MemoryReserved uint64 `json:"memory_reserved" yaml:"memory_reserved"`
+
+	// Why the cluster member doesn't accept new instances (empty if it does)
+	// Example: Available memory is below the reserved headroom
+	Saturated string `json:"saturated" yaml:"saturated"`
+}
+
+// ClusterMemberMaintenance represents the progress of the maintenance drain of a cluster member.
+//
+// swagger:model
+//
+// API extension: clustering_maintenance.
+type ClusterMemberMaintenance struct {
+	// Status of the drain ("draining", "drained" or "failed")
+	// Example: draining
+	Status string `json:"status" yaml:"status"`
+
+	// Error that interrupted the drain
+	// Example: Failed to migrate instance "c1" in project "default"
+	Error string `json:"error" yaml:"error"`
+
+	// When the drain started
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// When the drain completed or failed
+	// Example: 2021-03-23T17:40:02.411258771-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Instances handled by the drain
+	Instances []ClusterMemberMaintenanceInstance `json:"instances" yaml:"instances"`
+}
+
+// ClusterMemberMaintenanceInstance represents an instance handled by the maintenance drain of a cluster member.
+//
+// swagger:model
+//
+// API extension: clustering_maintenance.
+type ClusterMemberMaintenanceInstance struct {
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Evacuation policy applied to the instance
+	// Example: live-migrate
+	Policy string `json:"policy" yaml:"policy"`
+
+	// What happened to the instance ("pending", "migrated", "stopped", "skipped" or "failed")
+	// Example: migrated
+	Status string `json:"status" yaml:"status"`
+
+	// Cluster member the instance was moved to
+	// Example: lxd02
+	Target string `json:"target" yaml:"target"`
+
+	// Error of the failed instance
+	// Example: Failed to stop instance "c1" in project "default"
+	Error string `json:"error" yaml:"error"`
 }
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"time"
+)
+
+// DebugRuntime represents the runtime diagnostics of the LXD daemon.
+//
+// swagger:model
+//
+// API extension: debug_api.
+type DebugRuntime struct {
+	// Version of Go the daemon was built with
+	// Example: go1.23.4
+	GoVersion string `json:"go_version" yaml:"go_version"`
+
+	// Maximum number of CPUs executing Go code simultaneously
+	// Example: 8
+	GOMAXPROCS int `json:"gomaxprocs" yaml:"gomaxprocs"`
+
+	// Number of goroutines
+	// Example: 312
+	Goroutines int `json:"goroutines" yaml:"goroutines"`
+
+	// Number of cgo calls made by the daemon
+	// Example: 1052
+	CgoCalls int64 `json:"cgo_calls" yaml:"cgo_calls"`
+
+	// Memory allocation statistics
+	Memory DebugMemory `json:"memory" yaml:"memory"`
+}
+
+// DebugMemory represents the memory allocation statistics of the LXD daemon.
+//
+// swagger:model
+//
+// API extension: debug_api.
+type DebugMemory struct {
+	// Bytes of allocated heap objects
+	// Example: 25165824
+	Alloc uint64 `json:"alloc" yaml:"alloc"`
+
+	// Cumulative bytes allocated for heap objects
+	// Example: 1073741824
+	TotalAlloc uint64 `json:"total_alloc" yaml:"total_alloc"`
+
+	// Total bytes of memory obtained from the OS
+	// Example: 67108864
+	Sys uint64 `json:"sys" yaml:"sys"`
+
+	// Bytes in in-use heap spans
+	// Example: 29360128
+	HeapInuse uint64 `json:"heap_inuse" yaml:"heap_inuse"`
+
+	// Bytes in idle heap spans
+	// Example: 8388608
+	HeapIdle uint64 `json:"heap_idle" yaml:"heap_idle"`
+
+	// Bytes of physical memory returned to the OS
+	// Example: 4194304
+	HeapReleased uint64 `json:"heap_released" yaml:"heap_released"`
+
+	// Number of allocated heap objects
+	// Example: 120000
+	HeapObjects uint64 `json:"heap_objects" yaml:"heap_objects"`
+
+	// Bytes in stack spans
+	// Example: 2097152
+	StackInuse uint64 `json:"stack_inuse" yaml:"stack_inuse"`
+
+	// Cumulative count of heap objects allocated
+	// Example: 9000000
+	Mallocs uint64 `json:"mallocs" yaml:"mallocs"`
+
+	// Cumulative count of heap objects freed
+	// Example: 8880000
+	Frees uint64 `json:"frees" yaml:"frees"`
+
+	// Number of completed garbage collection cycles
+	// Example: 150
+	GCCycles uint32 `json:"gc_cycles" yaml:"gc_cycles"`
+
+	// Cumulative time spent in garbage collection pauses, in nanoseconds
+	// Example: 35000000
+	GCPauseTotal uint64 `json:"gc_pause_total" yaml:"gc_pause_total"`
+
+	// Time of the last garbage collection
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastGC time.Time `json:"last_gc" yaml:"last_gc"`
+}
+
+// DebugProfile represents a profile written to the log directory of the LXD daemon.
+//
+// swagger:model
+//
+// API extension: debug_api.
+type DebugProfile struct {
+	// Name of the profile
+	// Example: heap
+	Name string `json:"name" yaml:"name"`
+
+	// Path of the file the profile was written to
+	// Example: /var/log/lxd/pprof-heap-20240101T120000Z.pb.gz
+	Path string `json:"path" yaml:"path"`
+}
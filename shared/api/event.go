@@ -12,6 +12,13 @@ const (
 	EventTypeLogging   = "logging"
 	EventTypeOperation = "operation"
 	EventTypeOVN       = "ovn"
+	EventTypeAlert     = "alert"
+)
+
+// Alert statuses.
+const (
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
 )
 
 // Event represents an event entry (over websocket)
@@ -41,6 +48,12 @@ type Event struct {
 	//
 	// API extension: event_project
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+
+	// Sequence number of the event on the cluster member dispatching it
+	// Example: 1234
+	//
+	// API extension: event_sequence
+	Sequence uint64 `yaml:"sequence,omitempty" json:"sequence,omitempty"`
 }
 
 // ToLogging creates log record for the event.
@@ -118,6 +131,31 @@ func (event *Event) ToLogging() (EventLogRecord, error) {
 			},
 		}
 
+		return record, nil
+	case EventTypeAlert:
+		e := &EventAlert{}
+		err := json.Unmarshal(event.Metadata, &e)
+		if err != nil {
+			return EventLogRecord{}, err
+		}
+
+		lvl := "warning"
+		if e.Status == AlertStatusResolved {
+			lvl = "info"
+		}
+
+		record := EventLogRecord{
+			Time: event.Timestamp,
+			Lvl:  lvl,
+			Msg:  "Alert " + e.Status + ": " + e.Description,
+			Ctx: []any{
+				"rule", e.Rule,
+				"source", e.Source,
+				"value", e.Value,
+				"threshold", e.Threshold,
+			},
+		}
+
 		return record, nil
 	}
 
@@ -168,3 +206,36 @@ type EventLifecycleRequestor struct {
 	// API extension: event_lifecycle_requestor_address
 	Address string `yaml:"address" json:"address"`
 }
+
+// EventAlert represents an alert type event entry, sent when an alerting rule starts or stops firing.
+//
+// API extension: alerts.
+type EventAlert struct {
+	// Name of the alerting rule
+	// Example: storage-pool-usage
+	Rule string `yaml:"rule" json:"rule"`
+
+	// Status of the alert (firing or resolved)
+	// Example: firing
+	Status string `yaml:"status" json:"status"`
+
+	// URL of the entity the alert relates to
+	// Example: /1.0/storage-pools/default
+	Source string `yaml:"source" json:"source"`
+
+	// Description of the condition
+	// Example: Storage pool "default" is 93% full
+	Description string `yaml:"description" json:"description"`
+
+	// Value that was evaluated against the threshold
+	// Example: 93
+	Value float64 `yaml:"value" json:"value"`
+
+	// Threshold of the alerting rule
+	// Example: 90
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+
+	// Time at which the alert started firing
+	// Example: 2021-02-24T19:00:45.452649098-05:00
+	FiringSince time.Time `yaml:"firing_since" json:"firing_since"`
+}
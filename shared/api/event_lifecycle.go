@@ -13,6 +13,10 @@ const (
 	EventLifecycleClusterGroupRenamed               = "cluster-group-renamed"
 	EventLifecycleClusterGroupUpdated               = "cluster-group-updated"
 	EventLifecycleClusterMemberAdded                = "cluster-member-added"
+	EventLifecycleClusterMemberFenced               = "cluster-member-fenced"
+	EventLifecycleClusterMemberHealed               = "cluster-member-healed"
+	EventLifecycleClusterMemberPartitioned          = "cluster-member-partitioned"
+	EventLifecycleClusterMemberRebalanced           = "cluster-member-rebalanced"
 	EventLifecycleClusterMemberRemoved              = "cluster-member-removed"
 	EventLifecycleClusterMemberRenamed              = "cluster-member-renamed"
 	EventLifecycleClusterMemberUpdated              = "cluster-member-updated"
@@ -28,6 +32,7 @@ const (
 	EventLifecycleImageRetrieved                    = "image-retrieved"
 	EventLifecycleImageSecretCreated                = "image-secret-created"
 	EventLifecycleImageUpdated                      = "image-updated"
+	EventLifecycleInstanceAutoRestarted             = "instance-auto-restarted"
 	EventLifecycleInstanceBackupCreated             = "instance-backup-created"
 	EventLifecycleInstanceBackupDeleted             = "instance-backup-deleted"
 	EventLifecycleInstanceBackupRenamed             = "instance-backup-renamed"
@@ -37,6 +42,8 @@ const (
 	EventLifecycleInstanceConsoleRetrieved          = "instance-console-retrieved"
 	EventLifecycleInstanceCreated                   = "instance-created"
 	EventLifecycleInstanceDeleted                   = "instance-deleted"
+	EventLifecycleInstanceDeviceAttached            = "instance-device-attached"
+	EventLifecycleInstanceDeviceDetached            = "instance-device-detached"
 	EventLifecycleInstanceExec                      = "instance-exec"
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
@@ -61,7 +68,12 @@ const (
 	EventLifecycleInstanceSnapshotUpdated           = "instance-snapshot-updated"
 	EventLifecycleInstanceStarted                   = "instance-started"
 	EventLifecycleInstanceStopped                   = "instance-stopped"
+	EventLifecycleInstanceTemplateCreated           = "instance-template-created"
+	EventLifecycleInstanceTemplateDeleted           = "instance-template-deleted"
+	EventLifecycleInstanceTemplateRenamed           = "instance-template-renamed"
+	EventLifecycleInstanceTemplateUpdated           = "instance-template-updated"
 	EventLifecycleInstanceUpdated                   = "instance-updated"
+	EventLifecycleInstanceWatchdog                  = "instance-watchdog"
 	EventLifecycleNetworkACLCreated                 = "network-acl-created"
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
 	EventLifecycleNetworkACLRenamed                 = "network-acl-renamed"
@@ -91,9 +103,14 @@ const (
 	EventLifecycleProfileRenamed                    = "profile-renamed"
 	EventLifecycleProfileUpdated                    = "profile-updated"
 	EventLifecycleProjectCreated                    = "project-created"
+	EventLifecycleProjectDeleteProtectionUnlocked   = "project-delete-protection-unlocked"
 	EventLifecycleProjectDeleted                    = "project-deleted"
+	EventLifecycleProjectDisabled                   = "project-disabled"
+	EventLifecycleProjectEnabled                    = "project-enabled"
 	EventLifecycleProjectRenamed                    = "project-renamed"
 	EventLifecycleProjectUpdated                    = "project-updated"
+	EventLifecycleResourcesDeviceAdded              = "resources-device-added"
+	EventLifecycleResourcesDeviceRemoved            = "resources-device-removed"
 	EventLifecycleStoragePoolCreated                = "storage-pool-created"
 	EventLifecycleStoragePoolDeleted                = "storage-pool-deleted"
 	EventLifecycleStoragePoolUpdated                = "storage-pool-updated"
@@ -126,6 +143,10 @@ const (
 	EventLifecycleAuthGroupUpdated                  = "auth-group-updated"
 	EventLifecycleAuthGroupRenamed                  = "auth-group-renamed"
 	EventLifecycleAuthGroupDeleted                  = "auth-group-deleted"
+	EventLifecycleAuthRoleCreated                   = "auth-role-created"
+	EventLifecycleAuthRoleUpdated                   = "auth-role-updated"
+	EventLifecycleAuthRoleRenamed                   = "auth-role-renamed"
+	EventLifecycleAuthRoleDeleted                   = "auth-role-deleted"
 	EventLifecycleIdentityProviderGroupCreated      = "identity-provider-group-created"
 	EventLifecycleIdentityProviderGroupUpdated      = "identity-provider-group-updated"
 	EventLifecycleIdentityProviderGroupRenamed      = "identity-provider-group-renamed"
@@ -0,0 +1,43 @@
+package api
+
+// HealthStatusOK indicates that the server or subsystem is healthy.
+const HealthStatusOK = "ok"
+
+// HealthStatusDegraded indicates that the server or subsystem works but needs attention.
+const HealthStatusDegraded = "degraded"
+
+// HealthStatusError indicates that the server or subsystem doesn't work.
+const HealthStatusError = "error"
+
+// Health represents the health of the LXD server.
+//
+// swagger:model
+//
+// API extension: health.
+type Health struct {
+	// Overall status, the worst status of the checks ("ok", "degraded" or "error")
+	// Example: ok
+	Status string `json:"status" yaml:"status"`
+
+	// Status of each subsystem (only for callers allowed to view the server resources)
+	Checks []HealthCheck `json:"checks,omitempty" yaml:"checks,omitempty"`
+}
+
+// HealthCheck represents the health of a subsystem of the LXD server.
+//
+// swagger:model
+//
+// API extension: health.
+type HealthCheck struct {
+	// Name of the subsystem ("database", "storage", "network", "events", "images" or "time")
+	// Example: database
+	Name string `json:"name" yaml:"name"`
+
+	// Status of the subsystem ("ok", "degraded" or "error")
+	// Example: ok
+	Status string `json:"status" yaml:"status"`
+
+	// Details about the status
+	// Example: 3 of 3 cluster members online
+	Message string `json:"message" yaml:"message"`
+}
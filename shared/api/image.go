@@ -374,3 +374,18 @@ type ImageMetadataTemplate struct {
 	// Example: {"foo": "bar"}
 	Properties map[string]string `json:"properties" yaml:"properties"`
 }
+
+// ImageLocations represents the cluster members holding a copy of an image.
+//
+// swagger:model
+//
+// API extension: clustering_groups_image_replication.
+type ImageLocations struct {
+	// Cluster members holding a copy of the image
+	// Example: ["lxd01", "lxd02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Cluster members still missing a copy of the image required by the cluster group replication policies
+	// Example: ["lxd03"]
+	Pending []string `json:"pending" yaml:"pending"`
+}
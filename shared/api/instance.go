@@ -39,6 +39,20 @@ const (
 	SourceTypeNone = "none"
 )
 
+const (
+	// InstanceIdentityHWAddr represents the MAC addresses of the network interfaces of an instance.
+	InstanceIdentityHWAddr = "hwaddr"
+
+	// InstanceIdentityMachineID represents the machine ID of an instance.
+	InstanceIdentityMachineID = "machine-id"
+
+	// InstanceIdentitySSHHostKeys represents the SSH host keys of an instance.
+	InstanceIdentitySSHHostKeys = "ssh-host-keys"
+
+	// InstanceIdentityHostname represents the hostname of an instance.
+	InstanceIdentityHostname = "hostname"
+)
+
 // InstancesPost represents the fields available for a new LXD instance.
 //
 // swagger:model
@@ -77,6 +91,12 @@ type InstancesPost struct {
 type InstancesPut struct {
 	// Desired runtime state
 	State *InstanceStatePut `json:"state" yaml:"state"`
+
+	// Only apply the change to the instances having all these labels
+	// Example: {"env": "prod"}
+	//
+	// API extension: instance_labels
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 // InstancePost represents the fields required to rename/move a LXD instance.
@@ -151,6 +171,55 @@ type InstancePost struct {
 	//
 	// API extension: override_snapshot_profiles_on_copy
 	OverrideSnapshotProfiles bool `json:"override_snapshot_profiles" yaml:"override_snapshot_profiles"`
+
+	// Live migration tunables overriding the instance's migration.* configuration (live migration only, except for
+	// migration.bandwidth that also limits the storage transfer)
+	// Example: {"migration.bandwidth": "100MiB", "migration.postcopy": "true"}
+	//
+	// API extension: instance_migration_tunables
+	MigrationConfig map[string]string `json:"migration_config" yaml:"migration_config"`
+
+	// Project to move the stopped instance to in place, keeping its volumes, snapshots and identity
+	// Example: foo
+	//
+	// API extension: instance_project_rehome
+	TargetProject string `json:"target_project,omitempty" yaml:"target_project,omitempty"`
+
+	// Remote cluster to migrate the instance to, directly from this server (migration only)
+	//
+	// API extension: instance_remote_migration
+	Remote *InstancePostRemote `json:"remote,omitempty" yaml:"remote,omitempty"`
+}
+
+// InstancePostRemote represents a remote cluster an instance is migrated to.
+//
+// swagger:model
+//
+// API extension: instance_remote_migration.
+type InstancePostRemote struct {
+	// Address of the remote cluster (taken from the token if not set)
+	// Example: 10.0.0.2:8443
+	Address string `json:"address" yaml:"address"`
+
+	// Trust token issued by the remote cluster to trust this server
+	// Example: eyJjbGllbnRfbmFtZSI6InRlc3QiLCJmaW5nZXJwcmludCI6IjAw...
+	Token string `json:"token" yaml:"token"`
+
+	// Certificate of the remote cluster, required when this server is already trusted and no token is given
+	// Example: X509 PEM certificate
+	Certificate string `json:"certificate" yaml:"certificate"`
+
+	// Project to create the instance in on the remote cluster
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Storage pool to create the instance in on the remote cluster
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Cluster member to create the instance on in the remote cluster
+	// Example: lxd02
+	Target string `json:"target" yaml:"target"`
 }
 
 // InstancePostTarget represents the migration target host and operation.
@@ -209,6 +278,12 @@ type InstancePut struct {
 	// Instance description
 	// Example: My test instance
 	Description string `json:"description" yaml:"description"`
+
+	// Instance labels
+	// Example: {"env": "prod", "tier": "web"}
+	//
+	// API extension: instance_labels
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 // InstanceRebuildPost indicates how to rebuild an instance.
@@ -219,6 +294,79 @@ type InstancePut struct {
 type InstanceRebuildPost struct {
 	// Rebuild source
 	Source InstanceSource `json:"source" yaml:"source"`
+
+	// Whether to reset the instance's local configuration and devices
+	// Example: true
+	//
+	// API extension: instance_rebuild_preserve
+	Reset bool `json:"reset" yaml:"reset"`
+
+	// Disk devices to keep when resetting the instance
+	// Example: ["data"]
+	//
+	// API extension: instance_rebuild_preserve
+	PreserveDevices []string `json:"preserve_devices" yaml:"preserve_devices"`
+
+	// Whether to keep the user.* configuration keys when resetting the instance
+	// Example: true
+	//
+	// API extension: instance_rebuild_preserve
+	PreserveUserConfig bool `json:"preserve_user_config" yaml:"preserve_user_config"`
+
+	// Whether to snapshot the instance before rebuilding it (the instance is restored from it if the rebuild fails)
+	// Example: true
+	//
+	// API extension: instance_rebuild_preserve
+	Snapshot bool `json:"snapshot" yaml:"snapshot"`
+}
+
+// InstanceRescuePost represents the fields required to boot a LXD virtual machine from rescue media.
+//
+// swagger:model
+//
+// API extension: instance_rescue.
+type InstanceRescuePost struct {
+	// Storage pool of the rescue media
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Name of the custom ISO volume to boot from
+	// Example: ubuntu-live
+	Volume string `json:"volume" yaml:"volume"`
+}
+
+// InstanceExportPost represents the fields of a request to export an instance to another image format.
+//
+// swagger:model
+//
+// API extension: instance_export_oci.
+type InstanceExportPost struct {
+	// Tag of the image in the exported OCI image layout
+	// Example: latest
+	Tag string `json:"tag" yaml:"tag"`
+
+	// Registry reference to push the image to instead of returning it
+	// Example: registry.example.com/lxd/c1:latest
+	Target string `json:"target" yaml:"target"`
+}
+
+// InstanceWindowsSetupPost represents the fields required to prepare the setup media of a Windows virtual machine.
+//
+// swagger:model
+//
+// API extension: instance_windows_setup.
+type InstanceWindowsSetupPost struct {
+	// Storage pool of the VirtIO drivers media
+	// Example: default
+	DriversPool string `json:"drivers_pool" yaml:"drivers_pool"`
+
+	// Name of the custom ISO volume holding the VirtIO drivers
+	// Example: virtio-win
+	DriversVolume string `json:"drivers_volume" yaml:"drivers_volume"`
+
+	// Content of the answer file used by Windows setup (Autounattend.xml)
+	// Example: <?xml version="1.0" encoding="utf-8"?><unattend xmlns="urn:schemas-microsoft-com:unattend">...</unattend>
+	Unattend string `json:"unattend" yaml:"unattend"`
 }
 
 // Instance represents a LXD instance.
@@ -253,6 +401,20 @@ type Instance struct {
 	// Example: 2021-03-23T20:00:00-04:00
 	LastUsedAt time.Time `json:"last_used_at" yaml:"last_used_at"`
 
+	// Identity that created the instance
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	CreatedBy string `json:"created_by" yaml:"created_by"`
+
+	// Identity that last modified the instance
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	LastModifiedBy string `json:"last_modified_by" yaml:"last_modified_by"`
+
 	// What cluster member this instance is located on
 	// Example: lxd01
 	Location string `json:"location" yaml:"location"`
@@ -291,6 +453,12 @@ type Instance struct {
 	// Example: {"root": {"type": "disk", "pool": "default", "path": "/"}}
 	Devices map[string]map[string]string `json:"devices" yaml:"devices"`
 
+	// Instance labels
+	// Example: {"env": "prod", "tier": "web"}
+	//
+	// API extension: instance_labels
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
 	// Expanded configuration (all profiles and local config merged)
 	// Example: {"security.nesting": "true"}
 	ExpandedConfig map[string]string `json:"expanded_config,omitempty" yaml:"expanded_config,omitempty"`
@@ -316,6 +484,11 @@ type InstanceFull struct {
 
 	// List of snapshots.
 	Snapshots []InstanceSnapshot `json:"snapshots" yaml:"snapshots"`
+
+	// Recent resource usage samples (only included with recursion=2)
+	//
+	// API extension: instance_recursion_state_history
+	StateHistory []InstanceStateSample `json:"state_history,omitempty" yaml:"state_history,omitempty"`
 }
 
 // Writable converts a full Instance struct into a InstancePut struct (filters read-only fields).
@@ -330,6 +503,7 @@ func (c *Instance) Writable() InstancePut {
 		Profiles:     c.Profiles,
 		Stateful:     c.Stateful,
 		Description:  c.Description,
+		Labels:       c.Labels,
 	}
 }
 
@@ -342,6 +516,7 @@ func (c *Instance) SetWritable(put InstancePut) {
 	c.Profiles = put.Profiles
 	c.Stateful = put.Stateful
 	c.Description = put.Description
+	c.Labels = put.Labels
 }
 
 // IsActive checks whether the instance state indicates the instance is active.
@@ -467,6 +642,13 @@ type InstanceSource struct {
 	//
 	// API extension: override_snapshot_profiles_on_copy
 	OverrideSnapshotProfiles bool `json:"override_snapshot_profiles" yaml:"override_snapshot_profiles"`
+
+	// Identity items to regenerate in the new instance (for copy)
+	// Possible values are hwaddr, machine-id, ssh-host-keys and hostname.
+	// Example: ["hwaddr", "machine-id", "ssh-host-keys", "hostname"]
+	//
+	// API extension: instance_copy_regenerate_identity
+	RegenerateIdentity []string `json:"regenerate_identity,omitempty" yaml:"regenerate_identity,omitempty"`
 }
 
 // InstanceUEFIVars represents the UEFI variables of a LXD virtual machine.
@@ -501,3 +683,34 @@ type InstanceUEFIVariable struct {
 	// UEFI variable digest (HEX-encoded)
 	Digest string `json:"digest" yaml:"digest"`
 }
+
+// InstanceAttestation represents the confidential computing attestation information of a LXD virtual machine.
+//
+// swagger:model
+//
+// API extension: instance_confidential_computing.
+type InstanceAttestation struct {
+	// Confidential computing technology in use (sev, sev-es, sev-snp or tdx)
+	// Example: sev-snp
+	Type string `json:"type" yaml:"type"`
+
+	// State of the confidential guest as reported by the firmware (SEV only)
+	// Example: running
+	State string `json:"state,omitempty" yaml:"state,omitempty"`
+
+	// Guest policy (hexadecimal, SEV only)
+	// Example: 0x30000
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+
+	// Launch measurement (base64-encoded, SEV and SEV-ES only)
+	// Example: sM1HaQm7mlfX6tJ/Ru8GMeL9AnSoxE+7TSyq3Flp8rnsgnzaCKGFdmyOZpLpmbuQ
+	Measurement string `json:"measurement,omitempty" yaml:"measurement,omitempty"`
+
+	// Provider of the attestation report within the guest
+	// Example: sev_guest
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+
+	// Attestation report generated within the guest for the requested nonce (base64-encoded)
+	// Example: AgAAAAAAAAAAAAMAAAAAAAEAAAAAAAAAAAAAAAAAAAAB...
+	Report string `json:"report,omitempty" yaml:"report,omitempty"`
+}
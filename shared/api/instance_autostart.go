@@ -0,0 +1,96 @@
+package api
+
+import (
+	"time"
+)
+
+// InstanceAutostartStatus represents the status of an instance in the startup sequence.
+type InstanceAutostartStatus string
+
+const (
+	// InstanceAutostartPending means the instance hasn't been considered yet.
+	InstanceAutostartPending InstanceAutostartStatus = "pending"
+
+	// InstanceAutostartWaiting means the instance is waiting for higher priority instances or its dependencies.
+	InstanceAutostartWaiting InstanceAutostartStatus = "waiting"
+
+	// InstanceAutostartStarting means the instance is being started.
+	InstanceAutostartStarting InstanceAutostartStatus = "starting"
+
+	// InstanceAutostartStarted means the instance was started.
+	InstanceAutostartStarted InstanceAutostartStatus = "started"
+
+	// InstanceAutostartRunning means the instance was already running.
+	InstanceAutostartRunning InstanceAutostartStatus = "running"
+
+	// InstanceAutostartSkipped means the instance isn't meant to be started, or can't be started yet.
+	InstanceAutostartSkipped InstanceAutostartStatus = "skipped"
+
+	// InstanceAutostartFailed means the instance failed to start.
+	InstanceAutostartFailed InstanceAutostartStatus = "failed"
+)
+
+// InstancesAutostart represents the last instance startup sequence of a cluster member.
+//
+// swagger:model
+//
+// API extension: instances_autostart_parallel.
+type InstancesAutostart struct {
+	// Maximum number of instances started concurrently
+	// Example: 8
+	Parallelism int `json:"parallelism" yaml:"parallelism"`
+
+	// When the startup sequence started
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// When the startup sequence completed (zero while in progress)
+	// Example: 2021-03-23T17:39:02.411258771-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Instances in start order
+	Instances []InstanceAutostart `json:"instances" yaml:"instances"`
+}
+
+// InstanceAutostart represents an instance in the startup sequence.
+//
+// swagger:model
+//
+// API extension: instances_autostart_parallel.
+type InstanceAutostart struct {
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: foo
+	Name string `json:"name" yaml:"name"`
+
+	// Start priority (from boot.autostart.priority)
+	// Example: 10
+	Priority int `json:"priority" yaml:"priority"`
+
+	// Instances started before this instance (from boot.depends_on)
+	// Example: ["db:ready"]
+	DependsOn []string `json:"depends_on" yaml:"depends_on"`
+
+	// Status of the instance in the startup sequence
+	// Example: started
+	Status InstanceAutostartStatus `json:"status" yaml:"status"`
+
+	// Number of start attempts
+	// Example: 1
+	Attempts int `json:"attempts" yaml:"attempts"`
+
+	// Error of the last start attempt
+	// Example: Failed to start device "eth0"
+	Error string `json:"error" yaml:"error"`
+
+	// When the instance started being started
+	// Example: 2021-03-23T17:38:38.753398689-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// When the instance was started (or failed to be started)
+	// Example: 2021-03-23T17:38:41.215398689-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+}
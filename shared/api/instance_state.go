@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // InstanceStatePut represents the modifiable fields of a LXD instance's state.
 //
 // swagger:model
@@ -56,6 +60,12 @@ type InstanceState struct {
 
 	// CPU usage information
 	CPU InstanceStateCPU `json:"cpu" yaml:"cpu"`
+
+	// Number of automatic restarts since the instance was last started through the API
+	// Example: 2
+	//
+	// API extension: instance_autorestart
+	AutoRestarts int64 `json:"auto_restarts" yaml:"auto_restarts"`
 }
 
 // InstanceStateDisk represents the disk information section of a LXD instance's state.
@@ -84,6 +94,24 @@ type InstanceStateCPU struct {
 	// CPU usage in nanoseconds
 	// Example: 3637691016
 	Usage int64 `json:"usage" yaml:"usage"`
+
+	// Number of vCPUs currently plugged into the instance (virtual machines only)
+	// Example: 4
+	//
+	// API extension: vm_cpu_hotplug_max
+	VCPUs int64 `json:"vcpus,omitempty" yaml:"vcpus,omitempty"`
+
+	// Maximum number of vCPUs that can be plugged into the running instance (virtual machines only)
+	// Example: 16
+	//
+	// API extension: vm_cpu_hotplug_max
+	VCPUsMax int64 `json:"vcpus_max,omitempty" yaml:"vcpus_max,omitempty"`
+
+	// Host NUMA nodes the instance is placed on (virtual machines only)
+	// Example: [0]
+	//
+	// API extension: instance_cpu_nodes_balanced
+	NUMANodes []uint64 `json:"numa_nodes,omitempty" yaml:"numa_nodes,omitempty"`
 }
 
 // InstanceStateMemory represents the memory information section of a LXD instance's state.
@@ -211,3 +239,120 @@ type InstanceStateNetworkCounters struct {
 	// Example: 179
 	PacketsDroppedInbound int64 `json:"packets_dropped_inbound" yaml:"packets_dropped_inbound"`
 }
+
+// InstanceCheckpointPost represents the fields required to checkpoint a LXD container.
+//
+// swagger:model
+//
+// API extension: container_checkpoint_export.
+type InstanceCheckpointPost struct {
+	// Whether to stop the container once its runtime state is saved
+	// Example: false
+	Stop bool `json:"stop" yaml:"stop"`
+}
+
+// InstanceStateHistory represents the resource usage history of a LXD instance.
+//
+// swagger:model
+//
+// API extension: instance_state_history.
+type InstanceStateHistory struct {
+	// Interval between samples (in seconds)
+	// Example: 300
+	Interval int64 `json:"interval" yaml:"interval"`
+
+	// Usage samples, oldest first
+	Samples []InstanceStateSample `json:"samples" yaml:"samples"`
+}
+
+// InstanceStateSample represents a resource usage sample of a LXD instance.
+//
+// swagger:model
+//
+// API extension: instance_state_history.
+type InstanceStateSample struct {
+	// Time of the sample
+	// Example: 2021-03-23T20:00:00-04:00
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// CPU time used since the instance started (in nanoseconds)
+	// Example: 3637691016
+	CPUUsage int64 `json:"cpu_usage" yaml:"cpu_usage"`
+
+	// Memory usage (in bytes)
+	// Example: 73248768
+	MemoryUsage int64 `json:"memory_usage" yaml:"memory_usage"`
+
+	// Root disk usage (in bytes)
+	// Example: 502239232
+	DiskUsage int64 `json:"disk_usage" yaml:"disk_usage"`
+
+	// Bytes received on all network interfaces since the instance started
+	// Example: 192021
+	NetworkBytesReceived int64 `json:"network_bytes_received" yaml:"network_bytes_received"`
+
+	// Bytes sent on all network interfaces since the instance started
+	// Example: 10888579
+	NetworkBytesSent int64 `json:"network_bytes_sent" yaml:"network_bytes_sent"`
+
+	// Number of processes
+	// Example: 50
+	Processes int64 `json:"processes" yaml:"processes"`
+}
+
+// InstanceAvailability represents the uptime and downtime of a LXD instance over a period of time.
+//
+// swagger:model
+//
+// API extension: instance_availability.
+type InstanceAvailability struct {
+	// Start of the period
+	// Example: 2021-03-22T20:00:00-04:00
+	Since time.Time `json:"since" yaml:"since"`
+
+	// End of the period
+	// Example: 2021-03-23T20:00:00-04:00
+	Until time.Time `json:"until" yaml:"until"`
+
+	// Time the instance was running (in seconds)
+	// Example: 82800
+	Uptime int64 `json:"uptime" yaml:"uptime"`
+
+	// Time the instance was stopped or frozen (in seconds)
+	// Example: 3600
+	Downtime int64 `json:"downtime" yaml:"downtime"`
+
+	// Time before the first recorded state change of the instance (in seconds)
+	// Example: 0
+	Untracked int64 `json:"untracked" yaml:"untracked"`
+
+	// Percentage of the tracked time the instance was running
+	// Example: 95.83
+	UptimePercentage float64 `json:"uptime_percentage" yaml:"uptime_percentage"`
+
+	// Percentage of the tracked time the instance was stopped or frozen
+	// Example: 4.17
+	DowntimePercentage float64 `json:"downtime_percentage" yaml:"downtime_percentage"`
+
+	// Last state changes within the period, newest first
+	Changes []InstanceStateChange `json:"changes" yaml:"changes"`
+}
+
+// InstanceStateChange represents a change of the status of a LXD instance.
+//
+// swagger:model
+//
+// API extension: instance_availability.
+type InstanceStateChange struct {
+	// Time of the change
+	// Example: 2021-03-23T19:00:00-04:00
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// New status of the instance
+	// Example: Running
+	Status string `json:"status" yaml:"status"`
+
+	// Lifecycle action which caused the change, empty if the change was detected by LXD
+	// Example: instance-started
+	Action string `json:"action" yaml:"action"`
+}
@@ -0,0 +1,131 @@
+package api
+
+// InstanceTemplatesPost represents the fields of a new LXD instance template
+//
+// swagger:model
+//
+// API extension: instance_templates.
+type InstanceTemplatesPost struct {
+	InstanceTemplatePut `yaml:",inline"`
+
+	// The name of the new instance template
+	// Example: web-server
+	Name string `json:"name" yaml:"name"`
+}
+
+// InstanceTemplatePost represents the fields required to rename a LXD instance template
+//
+// swagger:model
+//
+// API extension: instance_templates.
+type InstanceTemplatePost struct {
+	// The new name for the instance template
+	// Example: web-server-v2
+	Name string `json:"name" yaml:"name"`
+}
+
+// InstanceTemplatePut represents the modifiable fields of a LXD instance template
+//
+// swagger:model
+//
+// API extension: instance_templates.
+type InstanceTemplatePut struct {
+	// Description of the instance template
+	// Example: Web server with a data volume
+	Description string `json:"description" yaml:"description"`
+
+	// Parameters that can be set when instantiating the template
+	// Example: {"memory": {"description": "Memory limit", "default": "2GiB"}}
+	Parameters map[string]InstanceTemplateParameter `json:"parameters" yaml:"parameters"`
+
+	// Instance type (container or virtual-machine)
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+
+	// Creation source of the instances
+	Source InstanceSource `json:"source" yaml:"source"`
+
+	// List of profiles applied to the instances
+	// Example: ["default"]
+	Profiles []string `json:"profiles" yaml:"profiles"`
+
+	// Instance configuration map (refer to doc/instances.md)
+	// Example: {"limits.cpu": "4", "limits.memory": "{{ memory }}"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// Instance devices (refer to doc/instances.md)
+	// Example: {"root": {"type": "disk", "pool": "default", "path": "/", "size": "{{ disk_size }}"}}
+	Devices map[string]map[string]string `json:"devices" yaml:"devices"`
+}
+
+// InstanceTemplateParameter represents a parameter of a LXD instance template
+//
+// swagger:model
+//
+// API extension: instance_templates.
+type InstanceTemplateParameter struct {
+	// Description of the parameter
+	// Example: Memory limit
+	Description string `json:"description" yaml:"description"`
+
+	// Default value of the parameter, used when no value is given
+	// Example: 2GiB
+	Default string `json:"default" yaml:"default"`
+
+	// Whether a value must be given for the parameter when creating an instance
+	// Example: false
+	Required bool `json:"required" yaml:"required"`
+}
+
+// InstanceTemplate represents a LXD instance template
+//
+// swagger:model
+//
+// API extension: instance_templates.
+type InstanceTemplate struct {
+	InstanceTemplatePut `yaml:",inline"`
+
+	// The instance template name
+	// Read only: true
+	// Example: web-server
+	Name string `json:"name" yaml:"name"`
+
+	// Project name
+	// Read only: true
+	// Example: project1
+	Project string `json:"project" yaml:"project"`
+}
+
+// Writable converts a full InstanceTemplate struct into a InstanceTemplatePut struct (filters read-only fields).
+func (template *InstanceTemplate) Writable() InstanceTemplatePut {
+	return template.InstanceTemplatePut
+}
+
+// SetWritable sets applicable values from InstanceTemplatePut struct to InstanceTemplate struct.
+func (template *InstanceTemplate) SetWritable(put InstanceTemplatePut) {
+	template.InstanceTemplatePut = put
+}
+
+// URL returns the URL for the instance template.
+func (template *InstanceTemplate) URL(apiVersion string, projectName string) *URL {
+	return NewURL().Path(apiVersion, "instance-templates", template.Name).Project(projectName)
+}
+
+// InstanceTemplateInstancesPost represents the fields required to create an instance from a LXD instance template
+//
+// swagger:model
+//
+// API extension: instance_templates.
+type InstanceTemplateInstancesPost struct {
+	// Name of the new instance
+	// Example: web01
+	Name string `json:"name" yaml:"name"`
+
+	// Values of the template parameters
+	// Example: {"memory": "4GiB"}
+	Parameters map[string]string `json:"parameters" yaml:"parameters"`
+
+	// Whether to start the instance after creation
+	// Example: true
+	Start bool `json:"start" yaml:"start"`
+}
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworksPost represents the fields of a new LXD network
 //
 // swagger:model
@@ -117,6 +121,27 @@ type Network struct {
 	//
 	// API extension: networks_all_projects
 	Project string `json:"project" yaml:"project"`
+
+	// Network creation timestamp
+	// Read only: true
+	// Example: 2021-03-23T20:00:00-04:00
+	//
+	// API extension: entities_ownership
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Identity that created the network
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	CreatedBy string `json:"created_by" yaml:"created_by"`
+
+	// Identity that last modified the network
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	LastModifiedBy string `json:"last_modified_by" yaml:"last_modified_by"`
 }
 
 // Writable converts a full Network struct into a NetworkPut struct (filters read-only fields).
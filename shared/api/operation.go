@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -90,6 +91,109 @@ type OperationRequestor struct {
 	// Address is the origin address of the request.
 	// Example: 10.0.2.15
 	Address string `yaml:"address" json:"address"`
+
+	// TraceID is the trace ID of the request, as propagated between cluster members.
+	// Example: 4bf92f3577b34da6a3ce929d0e0e4736
+	//
+	// API extension: request_tracing
+	TraceID string `yaml:"trace_id,omitempty" json:"trace_id,omitempty"`
+}
+
+// OperationProgressMetadataKey is the key of the structured progress in the operation metadata.
+//
+// API extension: operation_progress_details.
+const OperationProgressMetadataKey = "progress_details"
+
+// OperationProgress represents the progress of a long running operation, such as a migration, an image download,
+// a backup or a storage transfer
+//
+// swagger:model
+//
+// API extension: operation_progress_details.
+type OperationProgress struct {
+	// Current stage of the operation
+	// Example: download
+	Stage string `json:"stage" yaml:"stage"`
+
+	// Number of bytes processed by the tasks of the current stage
+	// Example: 104857600
+	BytesDone int64 `json:"bytes_done" yaml:"bytes_done"`
+
+	// Total number of bytes to process in the current stage (0 if unknown)
+	// Example: 419430400
+	BytesTotal int64 `json:"bytes_total" yaml:"bytes_total"`
+
+	// Completion percentage of the current stage (0 if unknown)
+	// Example: 25
+	Percent int64 `json:"percent" yaml:"percent"`
+
+	// Processing rate in bytes per second
+	// Example: 10485760
+	Rate int64 `json:"rate" yaml:"rate"`
+
+	// Estimated remaining time of the current stage in seconds (-1 if unknown)
+	// Example: 30
+	ETA int64 `json:"eta" yaml:"eta"`
+
+	// Progress of the individual tasks of the operation, such as the volumes or files being transferred
+	Tasks []OperationProgressTask `json:"tasks" yaml:"tasks"`
+}
+
+// OperationProgressTask represents the progress of a task of a long running operation
+//
+// swagger:model
+//
+// API extension: operation_progress_details.
+type OperationProgressTask struct {
+	// Name of the task (empty for operations made of a single task)
+	// Example: rootfs
+	Name string `json:"name" yaml:"name"`
+
+	// Stage the task belongs to
+	// Example: download
+	Stage string `json:"stage" yaml:"stage"`
+
+	// Number of bytes processed
+	// Example: 104857600
+	BytesDone int64 `json:"bytes_done" yaml:"bytes_done"`
+
+	// Total number of bytes to process (0 if unknown)
+	// Example: 419430400
+	BytesTotal int64 `json:"bytes_total" yaml:"bytes_total"`
+
+	// Completion percentage (0 if unknown)
+	// Example: 25
+	Percent int64 `json:"percent" yaml:"percent"`
+
+	// Processing rate in bytes per second
+	// Example: 10485760
+	Rate int64 `json:"rate" yaml:"rate"`
+
+	// Estimated remaining time in seconds (-1 if unknown)
+	// Example: 30
+	ETA int64 `json:"eta" yaml:"eta"`
+}
+
+// ToProgress returns the structured progress from the operation metadata, or nil if the operation doesn't
+// report it.
+func (op *Operation) ToProgress() (*OperationProgress, error) {
+	value, ok := op.Metadata[OperationProgressMetadataKey]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := OperationProgress{}
+	err = json.Unmarshal(valueJSON, &progress)
+	if err != nil {
+		return nil, fmt.Errorf("Operation progress is invalid: %w", err)
+	}
+
+	return &progress, nil
 }
 
 // ToCertificateAddToken creates a certificate add token from the operation metadata.
@@ -197,5 +301,19 @@ func (op *Operation) ToClusterJoinToken() (*ClusterMemberJoinToken, error) {
 		joinToken.Addresses = append(joinToken.Addresses, addressString)
 	}
 
+	preseed, ok := op.Metadata["preseed"]
+	if ok && preseed != nil {
+		preseedJSON, err := json.Marshal(preseed)
+		if err != nil {
+			return nil, err
+		}
+
+		joinToken.Preseed = &ClusterMemberJoinPreseed{}
+		err = json.Unmarshal(preseedJSON, joinToken.Preseed)
+		if err != nil {
+			return nil, fmt.Errorf("Operation preseed is invalid: %w", err)
+		}
+	}
+
 	return &joinToken, nil
 }
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ProfilesPost represents the fields of a new LXD profile
 //
 // swagger:model
@@ -72,6 +76,27 @@ type Profile struct {
 	//
 	// API extension: profiles_all_projects
 	Project string `json:"project" yaml:"project"`
+
+	// Profile creation timestamp
+	// Read only: true
+	// Example: 2021-03-23T20:00:00-04:00
+	//
+	// API extension: entities_ownership
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Identity that created the profile
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	CreatedBy string `json:"created_by" yaml:"created_by"`
+
+	// Identity that last modified the profile
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	LastModifiedBy string `json:"last_modified_by" yaml:"last_modified_by"`
 }
 
 // Writable converts a full Profile struct into a ProfilePut struct (filters read-only fields).
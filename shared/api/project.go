@@ -1,8 +1,18 @@
 package api
 
+import (
+	"time"
+)
+
 // ProjectDefaultName is the name of the default project that can never be deleted.
 const ProjectDefaultName = "default"
 
+// ProjectStatusActive is the status of projects which can be used normally.
+const ProjectStatusActive = "active"
+
+// ProjectStatusDisabled is the status of projects whose instances are stopped and which reject any change.
+const ProjectStatusDisabled = "disabled"
+
 // ProjectsPost represents the fields of a new LXD project
 //
 // swagger:model
@@ -75,6 +85,13 @@ type Project struct {
 	// Read only: true
 	// Example: ["/1.0/images/0e60015346f06627f10580d56ac7fffd9ea775f6d4f25987217d5eed94910a20", "/1.0/instances/c1", "/1.0/networks/lxdbr0", "/1.0/profiles/default", "/1.0/storage-pools/default/volumes/custom/blah"]
 	UsedBy []string `json:"used_by" yaml:"used_by"`
+
+	// Status of the project (active or disabled)
+	// Read only: true
+	// Example: active
+	//
+	// API extension: projects_disable
+	Status string `json:"status" yaml:"status"`
 }
 
 // Writable converts a full Project struct into a ProjectPut struct (filters read-only fields)
@@ -108,6 +125,61 @@ type ProjectState struct {
 	// Read only: true
 	// Example: {"containers": {"limit": 10, "usage": 4}, "cpu": {"limit": 20, "usage": 16}}
 	Resources map[string]ProjectStateResource `json:"resources" yaml:"resources"`
+
+	// Status of the project (active or disabled)
+	// Read only: true
+	// Example: disabled
+	//
+	// API extension: projects_disable
+	Status string `json:"status" yaml:"status"`
+
+	// Changes of the status of the project, from the newest to the oldest
+	// Read only: true
+	//
+	// API extension: projects_disable
+	StatusChanges []ProjectStatusChange `json:"status_changes" yaml:"status_changes"`
+}
+
+// ProjectStatePut represents the fields required to disable or enable a LXD project
+//
+// swagger:model
+//
+// API extension: projects_disable.
+type ProjectStatePut struct {
+	// Action to perform on the project (disable or enable)
+	// Example: disable
+	Action string `json:"action" yaml:"action"`
+
+	// Reason for the action, recorded along with the new status
+	// Example: Tenant offboarded
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// ProjectStatusChange represents a change of the status of a LXD project
+//
+// swagger:model
+//
+// API extension: projects_disable.
+type ProjectStatusChange struct {
+	// New status of the project
+	// Example: disabled
+	Status string `json:"status" yaml:"status"`
+
+	// Reason given for the change
+	// Example: Tenant offboarded
+	Reason string `json:"reason" yaml:"reason"`
+
+	// Name of the user who changed the status
+	// Example: admin
+	Requestor string `json:"requestor" yaml:"requestor"`
+
+	// Authentication protocol of the user who changed the status
+	// Example: tls
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// When the status changed
+	// Example: 2024-01-01T10:00:00Z
+	Date time.Time `json:"date" yaml:"date"`
 }
 
 // ProjectStateResource represents the state of a particular resource in a LXD project
@@ -124,3 +196,73 @@ type ProjectStateResource struct {
 	// Example: 4
 	Usage int64
 }
+
+// ProjectUsage represents the resource usage of a LXD project over a period of time
+//
+// swagger:model
+//
+// API extension: projects_usage_accounting.
+type ProjectUsage struct {
+	// Start of the period
+	// Example: 2024-01-01T00:00:00Z
+	Start time.Time `json:"start" yaml:"start"`
+
+	// End of the period (excluded)
+	// Example: 2024-01-02T00:00:00Z
+	End time.Time `json:"end" yaml:"end"`
+
+	// Number of API calls made to the project
+	// Example: 1520
+	APICalls int64 `json:"api_calls" yaml:"api_calls"`
+
+	// Running time of the instances of the project, in hours
+	// Example: 72
+	InstanceHours float64 `json:"instance_hours" yaml:"instance_hours"`
+
+	// Storage allocated to the project over time, in GB-hours (1 GB = 10^9 bytes)
+	// Example: 480
+	StorageGBHours float64 `json:"storage_gb_hours" yaml:"storage_gb_hours"`
+
+	// Bytes sent and received by the instances of the project
+	// Example: 1073741824
+	NetworkBytes int64 `json:"network_bytes" yaml:"network_bytes"`
+}
+
+// ProjectShareAccessReadOnly allows the identities of the project that an entity is shared with to view it.
+const ProjectShareAccessReadOnly = "read-only"
+
+// ProjectShareAccessAttach additionally allows the instances of the project that an entity is shared with to use it.
+const ProjectShareAccessAttach = "attach"
+
+// ProjectShare represents an entity of a LXD project that is shared with another project
+//
+// swagger:model
+//
+// API extension: projects_shares.
+type ProjectShare struct {
+	// Type of the shared entity (image, network or storage_volume)
+	// Example: storage_volume
+	EntityType string `json:"entity_type" yaml:"entity_type"`
+
+	// URL of the shared entity
+	// Example: /1.0/storage-pools/default/volumes/custom/data?project=foo
+	EntityReference string `json:"entity_reference" yaml:"entity_reference"`
+
+	// Name of the project that the entity is shared with
+	// Example: bar
+	Project string `json:"project" yaml:"project"`
+
+	// Access given to the project (read-only or attach)
+	// Example: attach
+	Access string `json:"access" yaml:"access"`
+}
+
+// ProjectSharesPut represents the shares of the entities of a LXD project
+//
+// swagger:model
+//
+// API extension: projects_shares.
+type ProjectSharesPut struct {
+	// Shares of the entities of the project
+	Shares []ProjectShare `json:"shares" yaml:"shares"`
+}
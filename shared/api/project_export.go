@@ -0,0 +1,37 @@
+package api
+
+// ProjectExport represents the content of a project export archive.
+// It holds the configuration of a project and of the entities it owns, but no instance or volume data.
+//
+// swagger:model
+//
+// API extension: project_export.
+type ProjectExport struct {
+	// The project itself
+	Project ProjectsPost `json:"project" yaml:"project"`
+
+	// Profiles of the project (only when features.profiles is enabled)
+	Profiles []ProfilesPost `json:"profiles" yaml:"profiles"`
+
+	// Networks of the project (only when features.networks is enabled)
+	// Member specific configuration keys are left out.
+	Networks []NetworksPost `json:"networks" yaml:"networks"`
+
+	// Network ACLs of the project (only when features.networks is enabled)
+	NetworkACLs []NetworkACLsPost `json:"network_acls" yaml:"network_acls"`
+
+	// Network zones of the project along with their records (only when features.networks.zones is enabled)
+	NetworkZones []ProjectExportNetworkZone `json:"network_zones" yaml:"network_zones"`
+}
+
+// ProjectExportNetworkZone represents a network zone along with its records in a project export archive.
+//
+// swagger:model
+//
+// API extension: project_export.
+type ProjectExportNetworkZone struct {
+	NetworkZonesPost `yaml:",inline"`
+
+	// Records of the zone
+	Records []NetworkZoneRecordsPost `json:"records" yaml:"records"`
+}
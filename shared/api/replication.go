@@ -0,0 +1,59 @@
+package api
+
+import (
+	"time"
+)
+
+// ReplicationInstance represents the replication state of an instance.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type ReplicationInstance struct {
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Cluster member the instance is located on
+	// Example: lxd01
+	Location string `json:"location" yaml:"location"`
+
+	// Replication role of the instance ("primary" or "standby")
+	// Example: primary
+	Role string `json:"role" yaml:"role"`
+
+	// Replication schedule of the instance (primary only)
+	// Example: @hourly
+	Schedule string `json:"schedule" yaml:"schedule"`
+
+	// Time of the last successful replication
+	// Example: 2021-03-23T20:00:00-04:00
+	LastSync time.Time `json:"last_sync" yaml:"last_sync"`
+
+	// Number of seconds since the last successful replication (-1 if never replicated)
+	// Example: 120
+	Lag int64 `json:"lag" yaml:"lag"`
+
+	// Error of the last failed replication (primary only)
+	// Example: Failed connecting to standby cluster
+	Error string `json:"error" yaml:"error"`
+}
+
+// ReplicationPromotePost represents the fields used to promote standby replicas.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type ReplicationPromotePost struct {
+	// Replicas to promote in the form <project>/<instance> (all replicas if empty)
+	// Example: ["default/c1"]
+	Instances []string `json:"instances" yaml:"instances"`
+
+	// Whether to start the promoted instances
+	// Example: true
+	Start bool `json:"start" yaml:"start"`
+}
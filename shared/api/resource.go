@@ -184,6 +184,39 @@ type ResourcesGPU struct {
 	// Total number of GPUs
 	// Example: 1
 	Total uint64 `json:"total" yaml:"total"`
+
+	// Utilization of the mediated device profiles across all GPUs
+	// Example: {"nvidia-233": {"name": "GRID T4-2Q", "available": 6, "used": 2, "total": 8, "cards": ["0000:3b:00.0"]}}
+	//
+	// API extension: gpu_mdev_pooling
+	MdevProfiles map[string]ResourcesGPUMdevProfile `json:"mdev_profiles,omitempty" yaml:"mdev_profiles,omitempty"`
+}
+
+// ResourcesGPUMdevProfile represents the utilization of a mediated device profile across all GPUs
+//
+// swagger:model
+//
+// API extension: gpu_mdev_pooling.
+type ResourcesGPUMdevProfile struct {
+	// Profile name
+	// Example: GRID T4-2Q
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Number of devices of this profile that can still be created
+	// Example: 6
+	Available uint64 `json:"available" yaml:"available"`
+
+	// Number of active devices of this profile
+	// Example: 2
+	Used uint64 `json:"used" yaml:"used"`
+
+	// Current capacity for this profile (available and used devices)
+	// Example: 8
+	Total uint64 `json:"total" yaml:"total"`
+
+	// PCI addresses of the GPUs (or virtual functions) offering this profile
+	// Example: ["0000:3b:00.0"]
+	Cards []string `json:"cards" yaml:"cards"`
 }
 
 // ResourcesGPUCard represents a GPU card on the system
@@ -341,6 +374,45 @@ type ResourcesGPUCardNvidia struct {
 	//
 	// API extension: resources_v2
 	CardDevice string `json:"card_device" yaml:"card_device"`
+
+	// MIG (Multi-Instance GPU) configuration of the GPU, only set when MIG is enabled
+	//
+	// API extension: gpu_mig_profiles
+	MIG *ResourcesGPUCardNvidiaMIG `json:"mig,omitempty" yaml:"mig,omitempty"`
+}
+
+// ResourcesGPUCardNvidiaMIG represents the MIG (Multi-Instance GPU) configuration of a NVIDIA GPU
+//
+// swagger:model
+//
+// API extension: gpu_mig_profiles.
+type ResourcesGPUCardNvidiaMIG struct {
+	// Map of GPU instance profiles, keyed by name
+	// Example: {"1g.5gb": {"id": 19, "available": 6, "total": 7, "memory": 5100273664}}
+	Profiles map[string]ResourcesGPUCardNvidiaMIGProfile `json:"profiles" yaml:"profiles"`
+}
+
+// ResourcesGPUCardNvidiaMIGProfile represents a MIG GPU instance profile of a NVIDIA GPU
+//
+// swagger:model
+//
+// API extension: gpu_mig_profiles.
+type ResourcesGPUCardNvidiaMIGProfile struct {
+	// Profile ID
+	// Example: 19
+	ID uint64 `json:"id" yaml:"id"`
+
+	// Number of GPU instances of this profile that can still be created
+	// Example: 6
+	Available uint64 `json:"available" yaml:"available"`
+
+	// Total number of GPU instances of this profile supported by the GPU
+	// Example: 7
+	Total uint64 `json:"total" yaml:"total"`
+
+	// Memory of a GPU instance of this profile (in bytes)
+	// Example: 5100273664
+	Memory uint64 `json:"memory" yaml:"memory"`
 }
 
 // ResourcesGPUCardMdev represents the mediated devices configuration of the GPU
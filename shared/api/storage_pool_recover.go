@@ -0,0 +1,74 @@
+package api
+
+// StoragePoolScanVolume represents a volume found on a storage pool that is unknown to the database.
+//
+// swagger:model
+//
+// API extension: storage_pool_recover.
+type StoragePoolScanVolume struct {
+	// Name of the instance, custom volume or bucket
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Type of the volume (container, virtual-machine, volume or bucket)
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+
+	// Project the volume belongs to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Storage pool the volume belongs to
+	// Example: local
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Number of snapshots found for the volume
+	// Example: 2
+	SnapshotCount int `json:"snapshot_count" yaml:"snapshot_count"`
+
+	// Backup configuration (backup.yaml) the volume was discovered from
+	Config any `json:"config" yaml:"config"`
+}
+
+// StoragePoolScan represents the result of scanning a storage pool for unknown volumes.
+//
+// swagger:model
+//
+// API extension: storage_pool_recover.
+type StoragePoolScan struct {
+	// Volumes that can be recovered
+	UnknownVolumes []StoragePoolScanVolume `json:"unknown_volumes" yaml:"unknown_volumes"`
+
+	// Missing dependencies (projects, profiles or networks) that are preventing recovery
+	// Example: ["Profile \"gpu\" in project \"default\""]
+	DependencyErrors []string `json:"dependency_errors" yaml:"dependency_errors"`
+}
+
+// StoragePoolRecoverVolume identifies an unknown volume to recover.
+//
+// swagger:model
+//
+// API extension: storage_pool_recover.
+type StoragePoolRecoverVolume struct {
+	// Name of the instance, custom volume or bucket
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Type of the volume (container, virtual-machine, volume or bucket)
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+
+	// Project the volume belongs to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// StoragePoolRecoverPost represents the fields required to recover unknown volumes of a storage pool.
+//
+// swagger:model
+//
+// API extension: storage_pool_recover.
+type StoragePoolRecoverPost struct {
+	// Volumes to recover (all unknown volumes are recovered if empty)
+	Volumes []StoragePoolRecoverVolume `json:"volumes" yaml:"volumes"`
+}
@@ -147,6 +147,20 @@ type StorageVolume struct {
 	// API extension: storage_volumes_created_at
 	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
 
+	// Identity that created the volume
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	CreatedBy string `json:"created_by" yaml:"created_by"`
+
+	// Identity that last modified the volume
+	// Read only: true
+	// Example: jane.doe@example.com
+	//
+	// API extension: entities_ownership
+	LastModifiedBy string `json:"last_modified_by" yaml:"last_modified_by"`
+
 	// Storage volume configuration map (refer to doc/storage.md)
 	// Example: {"zfs.remove_snapshots": "true", "size": "50GiB"}
 	Config map[string]string `json:"config" yaml:"config"`
@@ -53,6 +53,18 @@ type Warning struct {
 	// The entity affected by this warning
 	// Example: /1.0/instances/c1?project=default
 	EntityURL string `json:"entity_url" yaml:"entity_url"`
+
+	// Who acknowledged the warning
+	// Example: jane@example.com
+	//
+	// API extension: warnings_acknowledgement.
+	AcknowledgedBy string `json:"acknowledged_by" yaml:"acknowledged_by"`
+
+	// Comment given when acknowledging the warning
+	// Example: Disk replacement scheduled
+	//
+	// API extension: warnings_acknowledgement.
+	AcknowledgedComment string `json:"acknowledged_comment" yaml:"acknowledged_comment"`
 }
 
 // WarningPut represents the modifiable fields of a warning.
@@ -64,4 +76,10 @@ type WarningPut struct {
 	// Status of the warning (new, acknowledged, or resolved)
 	// Example: new
 	Status string `json:"status" yaml:"status"`
+
+	// Comment recorded when acknowledging the warning
+	// Example: Disk replacement scheduled
+	//
+	// API extension: warnings_acknowledgement.
+	Comment string `json:"comment" yaml:"comment"`
 }
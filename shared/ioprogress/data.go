@@ -13,4 +13,10 @@ type ProgressData struct {
 
 	// Total number of bytes (for files)
 	TotalBytes int64
+
+	// Transfer speed in bytes per second
+	Speed int64
+
+	// Name of the item being transferred, if there are several (for example "rootfs")
+	Name string
 }
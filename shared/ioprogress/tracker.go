@@ -1,7 +1,10 @@
 package ioprogress
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/canonical/lxd/shared/units"
 )
 
 // ProgressTracker provides the stream information needed for tracking.
@@ -9,6 +12,9 @@ type ProgressTracker struct {
 	Length  int64
 	Handler func(int64, int64)
 
+	// DataHandler is called along with Handler with the detailed progress information.
+	DataHandler func(ProgressData)
+
 	percentage float64
 	total      int64
 	start      *time.Time
@@ -17,7 +23,7 @@ type ProgressTracker struct {
 
 func (pt *ProgressTracker) update(n int) {
 	// Skip the rest if no handler attached
-	if pt.Handler == nil {
+	if pt.Handler == nil && pt.DataHandler == nil {
 		return
 	}
 
@@ -70,5 +76,24 @@ func (pt *ProgressTracker) update(n int) {
 		pt.last = &cur
 	}
 
-	pt.Handler(progressInt, speedInt)
+	if pt.Handler != nil {
+		pt.Handler(progressInt, speedInt)
+	}
+
+	if pt.DataHandler != nil {
+		data := ProgressData{
+			TransferredBytes: pt.total,
+			TotalBytes:       pt.Length,
+			Speed:            speedInt,
+		}
+
+		if pt.Length > 0 {
+			data.Percentage = int(progressInt)
+			data.Text = fmt.Sprintf("%d%% (%s/s)", progressInt, units.GetByteSizeString(speedInt, 2))
+		} else {
+			data.Text = fmt.Sprintf("%s (%s/s)", units.GetByteSizeString(pt.total, 2), units.GetByteSizeString(speedInt, 2))
+		}
+
+		pt.DataHandler(data)
+	}
 }
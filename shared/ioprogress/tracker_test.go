@@ -0,0 +1,75 @@
+package ioprogress
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressTracker_DataHandler(t *testing.T) {
+	var updates []ProgressData
+	reader := &ProgressReader{
+		Reader: bytes.NewReader(make([]byte, 1000)),
+		Tracker: &ProgressTracker{
+			Length: 1000,
+			DataHandler: func(data ProgressData) {
+				updates = append(updates, data)
+			},
+		},
+	}
+
+	buf := make([]byte, 100)
+	for {
+		_, err := reader.Read(buf)
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+	}
+
+	require.Len(t, updates, 10)
+
+	for i, data := range updates {
+		assert.Equal(t, int64((i+1)*100), data.TransferredBytes)
+		assert.Equal(t, int64(1000), data.TotalBytes)
+		assert.Equal(t, min((i+1)*10+1, 100), data.Percentage)
+		assert.NotEmpty(t, data.Text)
+	}
+
+	assert.Equal(t, "100%", updates[9].Text[:4])
+}
+
+func TestProgressTracker_DataHandlerUnknownLength(t *testing.T) {
+	var updates []ProgressData
+	reader := &ProgressReader{
+		Reader: bytes.NewReader(make([]byte, 100)),
+		Tracker: &ProgressTracker{
+			DataHandler: func(data ProgressData) {
+				updates = append(updates, data)
+			},
+		},
+	}
+
+	buf := make([]byte, 10)
+	_, err := reader.Read(buf)
+	require.NoError(t, err)
+
+	// Updates are rate limited to one per second when the length is unknown.
+	assert.Empty(t, updates)
+
+	time.Sleep(time.Second)
+	_, err = reader.Read(buf)
+	require.NoError(t, err)
+
+	require.Len(t, updates, 1)
+	assert.Equal(t, int64(20), updates[0].TransferredBytes)
+	assert.Equal(t, int64(0), updates[0].TotalBytes)
+	assert.Equal(t, 0, updates[0].Percentage)
+	assert.Positive(t, updates[0].Speed)
+	assert.Equal(t, "20B (", updates[0].Text[:5])
+}
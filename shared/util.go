@@ -1240,12 +1240,13 @@ func DownloadFileHash(ctx context.Context, httpClient *http.Client, useragent st
 			ReadCloser: r.Body,
 			Tracker: &ioprogress.ProgressTracker{
 				Length: r.ContentLength,
-				Handler: func(percent int64, speed int64) {
+				DataHandler: func(data ioprogress.ProgressData) {
 					if filename != "" {
-						progress(ioprogress.ProgressData{Text: fmt.Sprintf("%s: %d%% (%s/s)", filename, percent, units.GetByteSizeString(speed, 2))})
-					} else {
-						progress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+						data.Name = filename
+						data.Text = filename + ": " + data.Text
 					}
+
+					progress(data)
 				},
 			},
 		}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/mail"
 	"net/url"
 	"os/exec"
 	"path/filepath"
@@ -779,6 +780,20 @@ func IsRequestURL(value string) error {
 	return nil
 }
 
+// IsEmailAddress checks value is a bare email address, such as `jane.doe@example.com`.
+func IsEmailAddress(value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("Invalid email address %q: %w", value, err)
+	}
+
+	if addr.Address != value {
+		return fmt.Errorf("Invalid email address %q: Must not include a name", value)
+	}
+
+	return nil
+}
+
 // IsCloudInitUserData checks value is valid cloud-init user data.
 func IsCloudInitUserData(value string) error {
 	if value == "#cloud-config" || strings.HasPrefix(value, "#cloud-config\n") {
@@ -733,3 +733,23 @@ func Test_IsNetworkPortRange(t *testing.T) {
 		}
 	}
 }
+
+func Test_IsEmailAddress(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"jane.doe@example.com", true},
+		{"ops+lxd@example.com", true},
+		{"Jane Doe <jane.doe@example.com>", false},
+		{"jane.doe", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		err := validate.IsEmailAddress(test.value)
+		if (err == nil) != test.expected {
+			t.Errorf("IsEmailAddress(%q) = %v, want %v", test.value, err == nil, test.expected)
+		}
+	}
+}
@@ -463,6 +463,105 @@ var APIExtensions = []string{
 	"ovn_dhcp_ranges",
 	"operation_requestor",
 	"import_custom_volume_tar",
+	"backup_export_resume",
+	"storage_pool_recover",
+	"storage_driver_tmpfs",
+	"storage_btrfs_quotas",
+	"storage_driver_nfs",
+	"project_export",
+	"instance_migration_tunables",
+	"vm_cpu_hotplug_max",
+	"instance_templates",
+	"instance_copy_regenerate_identity",
+	"gpu_mdev_pooling",
+	"instance_snapshots_stateful_incremental",
+	"instance_session_recording",
+	"instance_boot_depends_on",
+	"instance_autorestart",
+	"console_history",
+	"instance_confidential_computing",
+	"container_checkpoint_export",
+	"instance_state_history",
+	"instance_labels",
+	"instance_rebuild_preserve",
+	"instance_placement_rules",
+	"instance_rescue",
+	"instance_uefi_vars_management",
+	"usb_hotplug_events",
+	"instance_recursion_state_history",
+	"instance_files_tar",
+	"instance_files_disk",
+	"instance_windows_setup",
+	"cluster_migration_bandwidth",
+	"instance_stateful_compression",
+	"device_secret",
+	"instance_project_rehome",
+	"instance_tpm_state",
+	"gpu_mig_profiles",
+	"device_watchdog",
+	"instance_memory_balloon_auto",
+	"instance_cpu_nodes_balanced",
+	"instances_autostart_parallel",
+	"instance_hooks",
+	"instance_export_oci",
+	"clustering_maintenance",
+	"instance_remote_migration",
+	"clustering_witness",
+	"clustering_join_preseed",
+	"cluster_healing_fence",
+	"instance_replication",
+	"clustering_drift",
+	"clustering_overcommit",
+	"event_sequence",
+	"clustering_groups_image_replication",
+	"clustering_database_maintenance",
+	"clustering_rebalance",
+	"network_parent_members",
+	"clustering_certificate_rotation",
+	"clustering_partition_fencing",
+	"cluster_backup",
+	"request_tracing",
+	"clustering_groups_constraints",
+	"opentelemetry_tracing",
+	"metrics_api_durations",
+	"events_history",
+	"webhooks",
+	"event_sinks",
+	"health",
+	"metrics_storage_pools",
+	"operation_progress_details",
+	"event_selectors",
+	"metrics_vm_host_collected",
+	"alerts",
+	"slow_request_logging",
+	"metrics_cluster_database",
+	"debug_api",
+	"metrics_conntrack",
+	"projects_usage_accounting",
+	"instance_availability",
+	"event_sink_mqtt",
+	"warnings_acknowledgement",
+	"api_request_size_limits",
+	"projects_default_profiles",
+	"projects_limits_operations",
+	"projects_disable",
+	"images_allowed_servers",
+	"auth_expiring_grants",
+	"auth_delegated_identities",
+	"entities_ownership",
+	"auth_roles",
+	"admission_scriptlet",
+	"projects_shares",
+	"projects_notifications",
+	"projects_hierarchy",
+	"auth_identity_current_patch",
+	"projects_delete_protection",
+	"api_pagination",
+	"etag_required",
+	"batch",
+	"server_operation_limits",
+	"http3",
+	"operation_cancellation",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -407,9 +407,13 @@ if [ "${1:-"all"}" != "standalone" ]; then
     run_test test_clustering_failure_domains "clustering failure domains"
     run_test test_clustering_image_refresh "clustering image refresh"
     run_test test_clustering_evacuation "clustering evacuation"
+    run_test test_clustering_maintenance "clustering maintenance mode"
+    run_test test_clustering_healing "clustering automatic healing"
+    run_test test_clustering_partition "clustering partition fencing"
     run_test test_clustering_move "clustering move"
     run_test test_clustering_remove_members "clustering config remove members"
     run_test test_clustering_autotarget "clustering autotarget member"
+    run_test test_clustering_placement "clustering placement rules"
     run_test test_clustering_upgrade "clustering upgrade"
     run_test test_clustering_upgrade_large "clustering upgrade_large"
     run_test test_clustering_downgrade "clustering downgrade"
@@ -425,25 +429,38 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_concurrent_exec "concurrent exec"
     run_test test_database_restore "database restore"
     run_test test_database_no_disk_space "database out of disk space"
+    run_test test_database_maintenance "database maintenance"
     run_test test_sql "lxd sql"
     run_test test_tls_restrictions "TLS restrictions"
     run_test test_tls_version "TLS version"
     run_test test_completions "CLI completions"
     run_test test_oidc "OpenID Connect"
     run_test test_authorization "Authorization"
+    run_test test_authorization_expiry "Authorization expiry"
+    run_test test_authorization_delegation "Authorization delegation"
+    run_test test_authorization_current_identity "Authorization current identity"
     run_test test_certificate_edit "Certificate edit"
     run_test test_basic_usage "basic usage"
     run_test test_duplicate_detection "duplicate detection"
     run_test test_basic_version "basic version"
     run_test test_server_info "server info"
+    run_test test_instance_autorestart "instance automatic restart"
+    run_test test_instance_state_history "instance resource usage history"
+    run_test test_instances_autostart "instances startup sequence"
+    run_test test_instance_hooks "instance host-side hooks"
     run_test test_remote_url "remote url handling"
     run_test test_remote_url_with_token "remote token handling"
     run_test test_remote_admin "remote administration"
     run_test test_remote_usage "remote usage"
     run_test test_vm_empty "Empty VM"
+    run_test test_vm_rescue "VM rescue mode"
+    run_test test_vm_windows_setup "VM Windows setup media"
     run_test test_projects_default "default project"
     run_test test_projects_copy "copy/move between projects"
     run_test test_projects_crud "projects CRUD operations"
+    run_test test_projects_disable "projects disabling"
+    run_test test_projects_shares "projects shares"
+    run_test test_projects_notifications "projects notifications"
     run_test test_projects_containers "containers inside projects"
     run_test test_projects_snapshots "snapshots inside projects"
     run_test test_projects_backups "backups inside projects"
@@ -454,8 +471,11 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_projects_storage "projects and storage pools"
     run_test test_projects_network "projects and networks"
     run_test test_projects_limits "projects limits"
+    run_test test_projects_operation_limits "projects operation limits"
     run_test test_projects_usage "projects usage"
+    run_test test_projects_usage_accounting "projects usage accounting"
     run_test test_projects_yaml "projects with yaml initialization"
+    run_test test_projects_export "projects configuration export and import"
     run_test test_projects_before_init "project operations before init"
     run_test test_projects_restrictions "projects restrictions"
     run_test test_projects_images_volume "projects images volume"
@@ -478,6 +498,8 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_container_devices_proxy "container devices - proxy"
     run_test test_container_devices_gpu "container devices - gpu"
     run_test test_container_devices_unix "container devices - unix"
+    run_test test_container_devices_usb "container devices - usb"
+    run_test test_container_devices_secret "container devices - secret"
     run_test test_container_devices_tpm "container devices - tpm"
     run_test test_container_move "container server-side move"
     run_test test_container_syscall_interception "container syscall interception"
@@ -491,6 +513,7 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_image_import_dir "import image from directory"
     run_test test_image_import_existing_alias "import existing image from alias"
     run_test test_image_refresh "image refresh"
+    run_test test_image_allowed_servers "image allowed servers"
     run_test test_image_acl "image acl"
     run_test test_images_public "public images"
     run_test test_cloud_init "cloud-init"
@@ -511,8 +534,13 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_container_metadata "manage container metadata and templates"
     run_test test_container_snapshot_config "container snapshot configuration"
     run_test test_server_config "server configuration"
+    run_test test_events_history "events history"
+    run_test test_instance_availability "instance availability"
+    run_test test_entities_ownership "entities ownership"
+    run_test test_admission_scriptlet "admission scriptlet"
     run_test test_filemanip "file manipulations"
     run_test test_filemanip_req_content_type "request content-type header verification during file push"
+    run_test test_filemanip_tar "file transfers of directory trees as tar streams"
     run_test test_network "network management"
     run_test test_network_acl "network ACL management"
     run_test test_network_forward "network address forwards"
@@ -520,11 +548,13 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_network_ovn "OVN network management"
     run_test test_idmap "id mapping"
     run_test test_template "file templating"
+    run_test test_instance_templates "instance templates"
     run_test test_pki "PKI mode"
     run_test test_devlxd "/dev/lxd"
     run_test test_devlxd_volume_management "devLXD volume management"
     run_test test_fuidshift "fuidshift"
     run_test test_migration "migration"
+    run_test test_instance_replication "instance replication"
     run_test test_fdleak "fd leak"
     run_test test_storage "storage"
     run_test test_storage_volume_snapshots "storage volume snapshots"
@@ -536,6 +566,7 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_container_recover "container recover"
     run_test test_bucket_recover "bucket recover"
     run_test test_get_operations "test_get_operations"
+    run_test test_operation_cancellation "operation cancellation"
     run_test test_storage_volume_attach "attaching storage volumes"
     run_test test_storage_driver_btrfs "btrfs storage driver"
     run_test test_storage_driver_ceph "ceph storage driver"
@@ -570,8 +601,12 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_profiles_project_profiles "profiles in project with images disabled and profiles enabled"
     run_test test_filtering "API filtering"
     run_test test_warnings "Warnings"
+    run_test test_warnings_acknowledgement "Warnings acknowledgement"
+    run_test test_warnings_auto_resolve "Warnings auto-resolution"
     run_test test_metrics "Metrics"
+    run_test test_metrics_conntrack "Conntrack metrics"
     run_test test_storage_volume_recover "Recover storage volumes"
+    run_test test_storage_pool_recover_api "Recover storage volumes through the API"
     run_test test_storage_volume_recover_by_container "Recover storage volumes by container"
     run_test test_syslog_socket "Syslog socket"
     run_test test_lxd_user "lxd user"
@@ -0,0 +1,68 @@
+test_admission_scriptlet() {
+  ensure_import_testimage
+
+  cat > "${TEST_DIR}/admission.star" << EOF2
+def admit(request):
+    if request.entity_type != "instance":
+        return
+
+    if request.operation == "create" and not request.name.startswith("web-"):
+        reject("Instance names must start with web-")
+
+    config = request.spec.get("config") or {}
+    if "user.owner" not in config:
+        config["user.owner"] = request.username
+        request.spec["config"] = config
+EOF2
+
+  # Invalid scriptlets are rejected.
+  ! lxc config set admission.scriptlet="def foo(): pass" || false
+  ! lxc config set admission.scriptlet="def admit(request): return (" || false
+
+  lxc config set admission.scriptlet="$(cat "${TEST_DIR}/admission.star")"
+
+  # The scriptlet can reject requests.
+  ! lxc init testimage c1 2> "${TEST_DIR}/admission.err" || false
+  grep -F "Instance names must start with web-" "${TEST_DIR}/admission.err"
+  [ "$(lxc list -f csv -c n)" = "" ]
+
+  # The scriptlet can change requests.
+  lxc init testimage web-1
+  [ -n "$(lxc config get web-1 user.owner)" ]
+  lxc config set web-1 user.owner=alice
+  [ "$(lxc config get web-1 user.owner)" = "alice" ]
+  lxc config unset web-1 user.owner
+  [ -n "$(lxc config get web-1 user.owner)" ]
+
+  # The scriptlet only applies to the entity types it handles.
+  lxc profile create noop
+  lxc profile delete noop
+
+  # The project scriptlet runs after the server one.
+  lxc project create admission -c features.images=false -c features.profiles=false
+  lxc project set admission admission.scriptlet='def admit(request):
+    if request.entity_type == "storage_volume" and request.operation == "create":
+        reject("No volumes here")
+    if request.entity_type == "instance" and request.name == "web-2":
+        reject("Not this one")'
+  pool="$(lxc profile device get default root pool)"
+  ! lxc storage volume create "${pool}" vol1 --project admission || false
+  lxc storage volume create "${pool}" vol1
+  ! lxc init testimage web-2 --project admission || false
+  ! lxc init testimage c2 --project admission || false
+  lxc init testimage web-3 --project admission
+  [ -n "$(lxc config get web-3 user.owner --project admission)" ]
+
+  # Unsetting the scriptlets admits every request again.
+  lxc config unset admission.scriptlet
+  lxc project unset admission admission.scriptlet
+  lxc init testimage c2 --project admission
+  [ -z "$(lxc config get c2 user.owner --project admission)" ]
+
+  # Cleanup
+  lxc delete c2 web-3 --project admission
+  lxc project delete admission
+  lxc storage volume delete "${pool}" vol1
+  lxc delete web-1
+  rm "${TEST_DIR}/admission.star" "${TEST_DIR}/admission.err"
+}
@@ -352,6 +352,139 @@ fine_grained: true"
   lxc config unset oidc.client.id
 }
 
+test_authorization_expiry() {
+  lxc auth group create expiry-group
+
+  tls_identity_token="$(lxc auth identity create tls/expiry-user --quiet)"
+  LXD_CONF2=$(mktemp -d -p "${TEST_DIR}" XXX)
+  LXD_CONF="${LXD_CONF2}" gen_cert_and_key "client"
+  LXD_CONF="${LXD_CONF2}" lxc remote add expiry "${tls_identity_token}"
+  ! LXD_CONF="${LXD_CONF2}" lxc_remote info expiry: | grep -F 'core.https_address' || false
+
+  # Memberships grant the group permissions until they expire.
+  lxc auth group permission add expiry-group server admin
+  lxc auth identity group add tls/expiry-user expiry-group --expiry 5S
+  lxc query /1.0/auth/identities/tls/expiry-user | jq -e '.group_expiries["expiry-group"] != null'
+  LXD_CONF="${LXD_CONF2}" lxc_remote info expiry: | grep -F 'core.https_address'
+  sleep 6
+  ! LXD_CONF="${LXD_CONF2}" lxc_remote info expiry: | grep -F 'core.https_address' || false
+  lxc query /1.0/auth/identities/tls/expiry-user | jq -e '(.groups | length) == 0'
+
+  # Expiry dates must be in the future and only apply to the groups of the identity.
+  [ "$(my_curl -X PUT -H 'Content-Type: application/json' --data '{"groups":["expiry-group"],"group_expiries":{"expiry-group":"2000-01-01T00:00:00Z"}}' "https://${LXD_ADDR}/1.0/auth/identities/tls/expiry-user" | jq -r '.error_code')" = "400" ]
+  [ "$(my_curl -X PUT -H 'Content-Type: application/json' --data '{"groups":[],"group_expiries":{"expiry-group":"2100-01-01T00:00:00Z"}}' "https://${LXD_ADDR}/1.0/auth/identities/tls/expiry-user" | jq -r '.error_code')" = "400" ]
+
+  # Permissions are granted until they expire.
+  lxc auth identity group add tls/expiry-user expiry-group
+  lxc auth group permission remove expiry-group server admin
+  lxc auth group permission add expiry-group server admin --expiry 5S
+  lxc query /1.0/auth/groups/expiry-group | jq -e '.permissions[0].expires_at != null'
+  LXD_CONF="${LXD_CONF2}" lxc_remote info expiry: | grep -F 'core.https_address'
+  sleep 6
+  ! LXD_CONF="${LXD_CONF2}" lxc_remote info expiry: | grep -F 'core.https_address' || false
+  lxc query /1.0/auth/groups/expiry-group | jq -e '(.permissions | length) == 0'
+
+  # Permissions granted again don't expire unless told so.
+  lxc auth group permission add expiry-group server admin
+  lxc query /1.0/auth/groups/expiry-group | jq -e '.permissions[0].expires_at == null'
+  LXD_CONF="${LXD_CONF2}" lxc_remote info expiry: | grep -F 'core.https_address'
+
+  # Cleanup
+  lxc auth identity delete tls/expiry-user
+  lxc auth group delete expiry-group
+  rm -r "${LXD_CONF2}"
+}
+
+test_authorization_delegation() {
+  lxc project create deleg
+  lxc auth group create deleg-admins
+  lxc auth group permission add deleg-admins project deleg can_create_identities
+  lxc auth group permission add deleg-admins project deleg can_view
+
+  # Create a project administrator.
+  admin_token="$(lxc auth identity create tls/deleg-admin --quiet --group deleg-admins)"
+  LXD_CONF_ADMIN=$(mktemp -d -p "${TEST_DIR}" XXX)
+  LXD_CONF="${LXD_CONF_ADMIN}" gen_cert_and_key "client"
+  LXD_CONF="${LXD_CONF_ADMIN}" lxc remote add deleg "${admin_token}"
+
+  # Project administrators can only create identities confined to their project, without groups, and with a subset of
+  # their own permissions.
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/unconfined --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project deleg --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project default --permission "project default can_view" --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project deleg --permission "project default can_view" --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project deleg --permission "project deleg can_edit" --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project deleg --permission "server admin" --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project deleg --permission "project deleg can_view" --group deleg-admins --quiet || false
+  ! LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --permission "project deleg can_view" --quiet || false
+  [ "$(lxc auth identity list --format csv | grep -cF 'pending')" = 0 ]
+
+  ci_token="$(LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity create deleg:tls/ci --project deleg --permission "project deleg can_view" --quiet)"
+  LXD_CONF_CI=$(mktemp -d -p "${TEST_DIR}" XXX)
+  LXD_CONF="${LXD_CONF_CI}" gen_cert_and_key "client"
+  LXD_CONF="${LXD_CONF_CI}" lxc remote add ci "${ci_token}"
+
+  # The delegated identity only has the delegated permissions.
+  LXD_CONF="${LXD_CONF_CI}" lxc_remote project show ci:deleg
+  ! LXD_CONF="${LXD_CONF_CI}" lxc_remote project show ci:default || false
+  ! LXD_CONF="${LXD_CONF_CI}" lxc_remote project set ci:deleg user.foo bar || false
+
+  # The delegation records the parent identity, the project and the group holding the permissions.
+  admin_fingerprint="$(cert_fingerprint "${LXD_CONF_ADMIN}/client.crt")"
+  lxc query /1.0/auth/identities/tls/ci > "${TEST_DIR}/ci.json"
+  [ "$(jq -r '.delegation.parent' "${TEST_DIR}/ci.json")" = "/1.0/auth/identities/tls/${admin_fingerprint}" ]
+  [ "$(jq -r '.delegation.project' "${TEST_DIR}/ci.json")" = "deleg" ]
+  ci_group="$(jq -r '.delegation.group' "${TEST_DIR}/ci.json")"
+  [ "$(jq -r '.groups[0]' "${TEST_DIR}/ci.json")" = "${ci_group}" ]
+  rm "${TEST_DIR}/ci.json"
+  lxc query "/1.0/auth/groups/${ci_group}" | jq -e '.permissions == [{"entity_type": "project", "url": "/1.0/projects/deleg", "entitlement": "can_view"}]'
+
+  # The parent identity can view and delete the identity it created, which deletes its group.
+  [ "$(LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote query deleg:/1.0/auth/identities/tls/ci | jq -r '.delegation.project')" = "deleg" ]
+  LXD_CONF="${LXD_CONF_ADMIN}" lxc_remote auth identity delete deleg:tls/ci
+  ! lxc auth group show "${ci_group}" || false
+  [ "$(LXD_CONF="${LXD_CONF_CI}" lxc_remote query ci:/1.0 | jq -r '.auth')" = "untrusted" ]
+
+  # Cleanup
+  lxc auth identity delete tls/deleg-admin
+  lxc auth group delete deleg-admins
+  lxc project delete deleg
+  rm -r "${LXD_CONF_ADMIN}" "${LXD_CONF_CI}"
+}
+
+test_authorization_current_identity() {
+  self_token="$(lxc auth identity create tls/self-user --quiet)"
+  LXD_CONF_SELF=$(mktemp -d -p "${TEST_DIR}" XXX)
+  LXD_CONF="${LXD_CONF_SELF}" gen_cert_and_key "client"
+  LXD_CONF="${LXD_CONF_SELF}" lxc remote add self "${self_token}"
+
+  # The current identity can only be updated over HTTPS.
+  ! lxc query -X PATCH -d '{\"display_name\": \"root\"}' /1.0/auth/identities/current || false
+
+  # Identities without any permission can set their own display name.
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" my_curl -X PATCH -H 'Content-Type: application/json' --data '{"display_name": "Jane"}' "https://${LXD_ADDR}/1.0/auth/identities/current" | jq -r '.status_code')" = "200" ]
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" lxc_remote query self:/1.0/auth/identities/current | jq -r '.display_name')" = "Jane" ]
+  [ "$(lxc query /1.0/auth/identities/tls/self-user | jq -r '.display_name')" = "Jane" ]
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" my_curl -X PATCH -H 'Content-Type: application/json' --data "{\"display_name\": \"$(head -c 256 /dev/zero | tr '\0' a)\"}" "https://${LXD_ADDR}/1.0/auth/identities/current" | jq -r '.error_code')" = "400" ]
+
+  # TLS identities don't have sessions.
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" my_curl -X PATCH -H 'Content-Type: application/json' --data '{"revoke_sessions": true}' "https://${LXD_ADDR}/1.0/auth/identities/current" | jq -r '.error_code')" = "400" ]
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" my_curl -X PATCH -H 'Content-Type: application/json' --data '{"tls_certificate": "not a certificate"}' "https://${LXD_ADDR}/1.0/auth/identities/current" | jq -r '.error_code')" = "400" ]
+
+  # Identities can rotate their own certificate.
+  LXD_CONF_ROTATED=$(mktemp -d -p "${TEST_DIR}" XXX)
+  LXD_CONF="${LXD_CONF_ROTATED}" gen_cert_and_key "client"
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" my_curl -X PATCH -H 'Content-Type: application/json' --data "{\"tls_certificate\":\"$(awk '{printf "%s\\n", $0}' "${LXD_CONF_ROTATED}/client.crt")\"}" "https://${LXD_ADDR}/1.0/auth/identities/current" | jq -r '.status_code')" = "200" ]
+  [ "$(LXD_CONF="${LXD_CONF_SELF}" lxc_remote query self:/1.0 | jq -r '.auth')" = "untrusted" ]
+  LXD_CONF="${LXD_CONF_ROTATED}" lxc remote add self "${LXD_ADDR}" --accept-certificate --auth-type tls
+  [ "$(LXD_CONF="${LXD_CONF_ROTATED}" lxc_remote query self:/1.0/auth/identities/current | jq -r '.display_name')" = "Jane" ]
+  [ "$(lxc query /1.0/auth/identities/tls/self-user | jq -r '.id')" = "$(cert_fingerprint "${LXD_CONF_ROTATED}/client.crt")" ]
+
+  # Cleanup
+  lxc auth identity delete tls/self-user
+  rm -r "${LXD_CONF_SELF}" "${LXD_CONF_ROTATED}"
+}
+
 events_filtering() {
   monfile="${TEST_DIR}/monitor-out.jsonl"
 
@@ -69,6 +69,60 @@ EOF
   shutdown_lxd "${LXD_IMPORT_DIR}"
 }
 
+test_storage_pool_recover_api() {
+  LXD_IMPORT_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_IMPORT_DIR}" true
+
+  poolName=$(lxc profile device get default root pool)
+  poolDriver=$(lxc storage show "${poolName}" | awk '/^driver:/ {print $2}')
+
+  if [ "${poolDriver}" = "pure" ]; then
+    echo "==> SKIP: Storage driver does not support recovery"
+    return
+  fi
+
+  # Only pools known to the database can be scanned.
+  ! lxc query -X POST /1.0/storage-pools/missing/scan || false
+
+  # Nothing is found on a pool without unknown volumes.
+  [ "$(lxc query -X POST "/1.0/storage-pools/${poolName}/scan" | jq '.unknown_volumes | length')" = "0" ]
+
+  # Create custom volumes and delete their database entries.
+  lxc storage volume create "${poolName}" vol1 size=32MiB
+  lxc storage volume create "${poolName}" vol2 size=32MiB
+  lxc storage volume snapshot "${poolName}" vol1
+  lxd sql global "PRAGMA foreign_keys=ON; DELETE FROM storage_volumes WHERE name='vol1'"
+  lxd sql global "PRAGMA foreign_keys=ON; DELETE FROM storage_volumes WHERE name='vol2'"
+  ! lxc storage volume show "${poolName}" vol1 || false
+  ! lxc storage volume show "${poolName}" vol2 || false
+
+  # The scan reports the unknown volumes without recovering them.
+  lxc query -X POST "/1.0/storage-pools/${poolName}/scan" > scan.json
+  [ "$(jq '.unknown_volumes | length' scan.json)" = "2" ]
+  [ "$(jq -r '.unknown_volumes[] | select(.name == "vol1") | .type' scan.json)" = "volume" ]
+  [ "$(jq -r '.unknown_volumes[] | select(.name == "vol1") | .project' scan.json)" = "default" ]
+  [ "$(jq -r '.unknown_volumes[] | select(.name == "vol1") | .pool' scan.json)" = "${poolName}" ]
+  [ "$(jq -r '.unknown_volumes[] | select(.name == "vol1") | .snapshot_count' scan.json)" = "1" ]
+  rm scan.json
+  ! lxc storage volume show "${poolName}" vol1 || false
+
+  # Only the selected volumes are recovered.
+  lxc query -X POST --wait -d '{\"volumes\": [{\"name\": \"vol1\", \"type\": \"volume\", \"project\": \"default\"}]}' "/1.0/storage-pools/${poolName}/recover"
+  lxc storage volume show "${poolName}" vol1/snap0
+  ! lxc storage volume show "${poolName}" vol2 || false
+  [ "$(lxc query -X POST "/1.0/storage-pools/${poolName}/scan" | jq -r '.unknown_volumes[].name')" = "vol2" ]
+
+  # Without a selection, all the unknown volumes are recovered.
+  lxc query -X POST --wait -d '{}' "/1.0/storage-pools/${poolName}/recover"
+  lxc storage volume show "${poolName}" vol2
+  [ "$(lxc query -X POST "/1.0/storage-pools/${poolName}/scan" | jq '.unknown_volumes | length')" = "0" ]
+
+  # Cleanup
+  lxc storage volume delete "${poolName}" vol1
+  lxc storage volume delete "${poolName}" vol2
+  shutdown_lxd "${LXD_IMPORT_DIR}"
+}
+
 test_storage_volume_recover_by_container() {
   LXD_IMPORT_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
   spawn_lxd "${LXD_IMPORT_DIR}" true
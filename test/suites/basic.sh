@@ -729,6 +729,30 @@ test_basic_usage() {
   ! lxc config show c1 | grep -F 'image.' || false
   lxc delete c1 -f
 
+  # Test resetting the configuration on rebuild while preserving selected disks and user keys.
+  poolName=$(lxc profile device get default root pool)
+  lxc storage volume create "${poolName}" vol1
+  lxc init testimage c1 -c limits.memory=128MiB -c user.foo=bar
+  lxc config device add c1 data disk pool="${poolName}" source=vol1 path=/data
+  lxc config device add c1 nothing none
+  ! lxc rebuild testimage c1 --preserve-device data || false
+  ! lxc rebuild testimage c1 --reset --preserve-device nothing || false
+  ! lxc rebuild testimage c1 --reset --preserve-device missing || false
+  lxc rebuild testimage c1 --reset --preserve-device data --preserve-user-config
+  [ "$(lxc config get c1 limits.memory)" = "" ]
+  [ "$(lxc config get c1 user.foo)" = "bar" ]
+  [ "$(lxc config device get c1 data source)" = "vol1" ]
+  ! lxc config device get c1 nothing type || false
+  lxc rebuild testimage c1 --reset
+  [ "$(lxc config get c1 user.foo)" = "" ]
+  ! lxc config device get c1 data source || false
+
+  # Test snapshotting an instance before rebuilding it.
+  lxc rebuild testimage c1 --snapshot
+  [ "$(lxc query /1.0/instances/c1/snapshots | jq 'length')" = "1" ]
+  lxc delete c1
+  lxc storage volume delete "${poolName}" vol1
+
   # Test assigning an empty profile (with no root disk device) to an instance.
   lxc init testimage c1
   lxc profile create foo
@@ -931,3 +955,193 @@ test_duplicate_detection() {
   lxc storage volume delete foo foo
   lxc storage delete foo
 }
+
+test_instance_autorestart() {
+  ensure_import_testimage
+
+  echo "Reject invalid restart policies"
+  lxc init testimage c1
+  ! lxc config set c1 boot.autorestart=sometimes || false
+  ! lxc config set c1 boot.autorestart.max_retries=-1 || false
+  ! lxc config set c1 boot.autorestart.backoff=soon || false
+  lxc config set c1 boot.autorestart=on-failure boot.autorestart.max_retries=1 boot.autorestart.backoff=1
+
+  lxc start c1
+  [ "$(lxc query /1.0/instances/c1/state | jq -r '.auto_restarts')" = "0" ]
+
+  echo "Restart the container once it crashes"
+  kill -9 "$(lxc query /1.0/instances/c1/state | jq -r '.pid')"
+  for _ in $(seq 30); do
+    [ "$(lxc query /1.0/instances/c1/state | jq -r '.auto_restarts')" = "1" ] && [ "$(lxc list -c s -f csv c1)" = "RUNNING" ] && break
+    sleep 1
+  done
+
+  [ "$(lxc list -c s -f csv c1)" = "RUNNING" ]
+  [ "$(lxc config get c1 volatile.autorestart.count)" = "1" ]
+  lxc info c1 | grep -xF "Automatic restarts: 1"
+
+  echo "Stop restarting once the maximum number of restarts is reached"
+  kill -9 "$(lxc query /1.0/instances/c1/state | jq -r '.pid')"
+  sleep 5
+  [ "$(lxc list -c s -f csv c1)" = "STOPPED" ]
+
+  echo "Reset the counter when started through the API"
+  lxc start c1
+  [ "$(lxc config get c1 volatile.autorestart.count)" = "" ]
+  [ "$(lxc query /1.0/instances/c1/state | jq -r '.auto_restarts')" = "0" ]
+
+  echo "Don't restart the container when it is stopped through LXD"
+  lxc config set c1 boot.autorestart=always
+  lxc stop c1 --force
+  sleep 3
+  [ "$(lxc list -c s -f csv c1)" = "STOPPED" ]
+
+  echo "Don't restart the container without a restart policy"
+  lxc config unset c1 boot.autorestart
+  lxc start c1
+  kill -9 "$(lxc query /1.0/instances/c1/state | jq -r '.pid')"
+  sleep 3
+  [ "$(lxc list -c s -f csv c1)" = "STOPPED" ]
+
+  # Cleanup
+  lxc delete c1
+}
+
+test_instance_state_history() {
+  ensure_import_testimage
+
+  lxc launch testimage c1
+  lxc init testimage c2
+
+  echo "No samples are included while the history is disabled"
+  [ "$(lxc query '/1.0/instances/c1?recursion=2' | jq -r '.state_history')" = "null" ]
+
+  echo "Enabling the history samples the running instances"
+  lxc config set instances.state.history.interval=1
+  for _ in $(seq 20); do
+    [ "$(lxc query /1.0/instances/c1/state/history | jq '.samples | length')" -ge 1 ] && break
+    sleep 1
+  done
+
+  [ "$(lxc query /1.0/instances/c1/state/history | jq '.interval')" = "60" ]
+
+  echo "The samples are only included with recursion=2"
+  [ "$(lxc query '/1.0/instances/c1?recursion=2' | jq '.state_history | length')" -ge 1 ]
+  [ "$(lxc query '/1.0/instances/c1?recursion=1' | jq -r '.state_history')" = "null" ]
+  [ "$(lxc query '/1.0/instances?recursion=2' | jq '.[] | select(.name == "c1") | .state_history | length')" -ge 1 ]
+  [ "$(lxc query '/1.0/instances?recursion=2' | jq -r '.[] | select(.name == "c2") | .state_history')" = "null" ]
+  [ "$(lxc query '/1.0/instances?recursion=1' | jq -r '.[] | select(.name == "c1") | .state_history')" = "null" ]
+
+  # Cleanup
+  lxc config unset instances.state.history.interval
+  lxc delete -f c1 c2
+}
+
+test_instances_autostart() {
+  LXD_AUTOSTART_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD_AUTOSTART_DIR}" true
+  (
+    set -e
+    # shellcheck disable=SC2030
+    LXD_DIR=${LXD_AUTOSTART_DIR}
+    ensure_import_testimage
+
+    ! lxc config set instances.autostart.parallelism=-1 || false
+    lxc config set instances.autostart.parallelism=2
+
+    # The dependency is started first despite its lower priority.
+    lxc init testimage db -c boot.autostart=true -c boot.autostart.priority=10
+    lxc init testimage web -c boot.autostart=true -c boot.autostart.priority=20 -c boot.depends_on=db
+    lxc init testimage manual
+
+    # An instance whose source disappeared can't be started.
+    mkdir "${TEST_DIR}/autostart-source"
+    lxc init testimage broken -c boot.autostart=true
+    lxc config device add broken source disk source="${TEST_DIR}/autostart-source" path=/mnt
+    rmdir "${TEST_DIR}/autostart-source"
+
+    shutdown_lxd "${LXD_DIR}"
+    respawn_lxd "${LXD_DIR}" true
+
+    # Wait for the startup sequence to complete (the failing instance is retried).
+    for _ in $(seq 60); do
+      [ "$(lxc query /1.0/autostart | jq -r '.finished_at')" != "0001-01-01T00:00:00Z" ] && break
+      sleep 1
+    done
+
+    lxc query /1.0/autostart > "${TEST_DIR}/autostart.json"
+    [ "$(jq -r '.finished_at' "${TEST_DIR}/autostart.json")" != "0001-01-01T00:00:00Z" ]
+    [ "$(jq -r '.parallelism' "${TEST_DIR}/autostart.json")" = "2" ]
+    [ "$(jq '[.instances[].name] | (indices("db")[0] < indices("web")[0])' "${TEST_DIR}/autostart.json")" = "true" ]
+    [ "$(jq -r '.instances[] | select(.name == "db") | .status' "${TEST_DIR}/autostart.json")" = "started" ]
+    [ "$(jq -r '.instances[] | select(.name == "web") | .status' "${TEST_DIR}/autostart.json")" = "started" ]
+    [ "$(jq -r '.instances[] | select(.name == "web") | .depends_on | join(",")' "${TEST_DIR}/autostart.json")" = "db" ]
+    [ "$(jq -r '.instances[] | select(.name == "web") | .priority' "${TEST_DIR}/autostart.json")" = "20" ]
+    [ "$(jq -r '.instances[] | select(.name == "manual") | .status' "${TEST_DIR}/autostart.json")" = "skipped" ]
+    [ "$(jq -r '.instances[] | select(.name == "broken") | .status' "${TEST_DIR}/autostart.json")" = "failed" ]
+    [ "$(jq -r '.instances[] | select(.name == "broken") | .attempts' "${TEST_DIR}/autostart.json")" = "3" ]
+    [ -n "$(jq -r '.instances[] | select(.name == "broken") | .error' "${TEST_DIR}/autostart.json")" ]
+    rm "${TEST_DIR}/autostart.json"
+
+    [ "$(lxc list -f csv -c s db)" = "RUNNING" ]
+    [ "$(lxc list -f csv -c s web)" = "RUNNING" ]
+    [ "$(lxc list -f csv -c s manual)" = "STOPPED" ]
+
+    lxc delete -f db web manual broken
+  )
+  # shellcheck disable=SC2031,2269
+  LXD_DIR=${LXD_DIR}
+  kill_lxd "${LXD_AUTOSTART_DIR}"
+}
+
+test_instance_hooks() {
+  ensure_import_testimage
+
+  mkdir -p "${LXD_DIR}/hooks"
+  cat > "${LXD_DIR}/hooks/record" <<EOF
+#!/bin/sh
+echo "\${1} \${LXD_HOOK} \${LXD_INSTANCE_PROJECT}/\${LXD_INSTANCE_NAME} \${LXD_INSTANCE_TYPE}" >> "${TEST_DIR}/hooks.out"
+EOF
+  printf '#!/bin/sh\necho "hook failure"\nexit 1\n' > "${LXD_DIR}/hooks/fail"
+  chmod 0755 "${LXD_DIR}/hooks/record" "${LXD_DIR}/hooks/fail"
+
+  echo "==> Invalid hook names are rejected"
+  ! lxc init testimage c1 -c hooks.pre_start=../record || false
+  ! lxc init testimage c1 -c hooks.pre_start=.record || false
+
+  echo "==> Hooks run around the instance lifecycle"
+  lxc init testimage c1 -c hooks.pre_start=record -c hooks.post_stop=record
+  lxc start c1
+  [ "$(cat "${TEST_DIR}/hooks.out")" = "pre-start pre-start default/c1 container" ]
+  lxc stop -f c1
+  for _ in $(seq 10); do
+    [ "$(wc -l < "${TEST_DIR}/hooks.out")" = "2" ] && break
+    sleep 1
+  done
+
+  [ "$(tail -n1 "${TEST_DIR}/hooks.out")" = "post-stop post-stop default/c1 container" ]
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/c1/logs/hooks.log" | grep -F 'pre-start hook "record": Succeeded'
+
+  echo "==> A failing pre-start hook prevents the instance from starting"
+  lxc config set c1 hooks.pre_start=record,fail
+  ! lxc start c1 || false
+  [ "$(lxc list -f csv -c s c1)" = "STOPPED" ]
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/c1/logs/hooks.log" | grep -F "hook failure"
+
+  echo "==> Only registered hooks that can't be modified by other users are run"
+  lxc config set c1 hooks.pre_start=missing
+  ! lxc start c1 || false
+  chmod 0777 "${LXD_DIR}/hooks/record"
+  lxc config set c1 hooks.pre_start=record
+  ! lxc start c1 || false
+  chmod 0755 "${LXD_DIR}/hooks/record"
+  lxc start c1
+  lxc delete -f c1
+
+  echo "==> Hooks are low-level options"
+  lxc project create p1 -c features.images=false -c features.profiles=false -c restricted=true
+  ! lxc init testimage c1 --project p1 -c hooks.pre_start=record || false
+  lxc project delete p1
+
+  rm -rf "${LXD_DIR}/hooks" "${TEST_DIR}/hooks.out"
+}
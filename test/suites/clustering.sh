@@ -279,7 +279,25 @@ test_clustering_membership() {
 
   # Generate a join token for the sixth node.
   LXD_DIR="${LXD_ONE_DIR}" lxc cluster list
-  token="$(LXD_DIR="${LXD_ONE_DIR}" lxc cluster add --quiet node6)"
+  # Invalid preseed bundles are rejected.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{\"server_name\": \"node6\", \"preseed\": {\"roles\": [\"boss\"]}}' /1.0/cluster/members || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{\"server_name\": \"node6\", \"preseed\": {\"member_config\": [{\"entity\": \"instance\", \"name\": \"c1\", \"key\": \"limits.cpu\", \"value\": \"1\"}]}}' /1.0/cluster/members || false
+
+  # Embed the member configuration in the join token.
+  cat > "${TEST_DIR}/preseed.yaml" << EOF
+member_config:
+- entity: storage-pool
+  name: data
+  key: source
+  value: ""
+roles:
+- event-hub
+EOF
+
+  token="$(LXD_DIR="${LXD_ONE_DIR}" lxc cluster add --quiet node6 --preseed "${TEST_DIR}/preseed.yaml")"
+  rm "${TEST_DIR}/preseed.yaml"
+  [ "$(echo "${token}" | base64 -d | jq -r '.preseed.roles | join(",")')" = "event-hub" ]
+  [ "$(echo "${token}" | base64 -d | jq -r '.preseed.member_config[0].name')" = "data" ]
 
   # Check token is associated to correct name.
   LXD_DIR="${LXD_TWO_DIR}" lxc cluster list-tokens | grep node6 | grep "${token}"
@@ -296,6 +314,10 @@ test_clustering_membership() {
   LXD_DIR="${LXD_TWO_DIR}" lxc cluster list-tokens
   ! LXD_DIR="${LXD_TWO_DIR}" lxc cluster list-tokens | grep node6 || false
 
+  # The roles embedded in the token were applied on join.
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster show node6 | grep -xF -- "- event-hub"
+  LXD_DIR="${LXD_ONE_DIR}" lxc cluster role remove node6 event-hub
+
   # Generate a join token for a seventh node
   token="$(LXD_DIR="${LXD_ONE_DIR}" lxc cluster add --quiet node7)"
 
@@ -450,6 +472,15 @@ test_clustering_containers() {
   LXD_DIR="${LXD_ONE_DIR}" lxc delete foo/foo-bak-2
   ! LXD_DIR="${LXD_ONE_DIR}" lxc info foo | grep -wF foo-bak-2 || false
 
+  # The trace ID of a request is propagated to the cluster member it is forwarded to.
+  traceparent="00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+  curl --silent --fail --unix-socket "${LXD_ONE_DIR}/unix.socket" -H "traceparent: ${traceparent}" -X POST --data '{"name": "traced"}' "lxd/1.0/instances/foo/snapshots" > "${TEST_DIR}/op.json"
+  [ "$(jq -r '.metadata.location' "${TEST_DIR}/op.json")" = "node2" ]
+  [ "$(jq -r '.metadata.requestor.trace_id' "${TEST_DIR}/op.json")" = "4bf92f3577b34da6a3ce929d0e0e4736" ]
+  curl --silent --fail --unix-socket "${LXD_ONE_DIR}/unix.socket" "lxd$(jq -r '.operation' "${TEST_DIR}/op.json")/wait"
+  rm "${TEST_DIR}/op.json"
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete foo/traced
+
   # Export from node1 the image that was imported on node2
   LXD_DIR="${LXD_ONE_DIR}" lxc image export testimage "${TEST_DIR}/testimage"
   rm "${TEST_DIR}/testimage.tar.xz"
@@ -1741,6 +1772,32 @@ test_clustering_update_cert() {
   LXD_DIR="${LXD_ONE_DIR}" lxc info --target node2 | grep -F "server_name: node2"
   LXD_DIR="${LXD_TWO_DIR}" lxc info --target node1 | grep -F "server_name: node1"
 
+  # Invalid rotation requests are rejected.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{\"cluster_certificate\": \"foo\"}' /1.0/cluster/certificate/rotate || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc query -X POST -d '{\"cluster_certificate\": \"foo\", \"cluster_certificate_key\": \"bar\"}' /1.0/cluster/certificate/rotate || false
+  cmp -s "${LXD_ONE_DIR}/cluster.crt" "${cert_path}"
+
+  # Rotate the cluster certificate on all members in one operation.
+  fingerprint="$(LXD_DIR="${LXD_TWO_DIR}" lxc query --wait -X POST -d '{}' /1.0/cluster/certificate/rotate | jq -r '.metadata.fingerprint')"
+  ! cmp -s "${LXD_ONE_DIR}/cluster.crt" "${cert_path}" || false
+  cmp -s "${LXD_ONE_DIR}/cluster.crt" "${LXD_TWO_DIR}/cluster.crt"
+  cmp -s "${LXD_ONE_DIR}/cluster.key" "${LXD_TWO_DIR}/cluster.key"
+  LXD_DIR="${LXD_ONE_DIR}" lxc info | grep -xF "  certificate_fingerprint: ${fingerprint}"
+  LXD_DIR="${LXD_TWO_DIR}" lxc info | grep -xF "  certificate_fingerprint: ${fingerprint}"
+
+  # The members still communicate with each other.
+  LXD_DIR="${LXD_ONE_DIR}" lxc info --target node2 | grep -F "server_name: node2"
+  LXD_DIR="${LXD_TWO_DIR}" lxc info --target node1 | grep -F "server_name: node1"
+
+  # Rotate to a provided certificate.
+  jq -n --rawfile cert "${cert_path}" --rawfile key "${key_path}" '{cluster_certificate: $cert, cluster_certificate_key: $key}' > "${TEST_DIR}/rotate.json"
+  op="$(curl --silent --fail --unix-socket "${LXD_ONE_DIR}/unix.socket" -X POST --data "@${TEST_DIR}/rotate.json" "lxd/1.0/cluster/certificate/rotate" | jq -r '.operation')"
+  rm "${TEST_DIR}/rotate.json"
+  [ "$(curl --silent --fail --unix-socket "${LXD_ONE_DIR}/unix.socket" "lxd${op}/wait" | jq -r '.metadata.status')" = "Success" ]
+  cmp -s "${LXD_ONE_DIR}/cluster.crt" "${cert_path}"
+  cmp -s "${LXD_TWO_DIR}/cluster.crt" "${cert_path}"
+  LXD_DIR="${LXD_TWO_DIR}" lxc info --target node1 | grep -F "server_name: node1"
+
   LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
   LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
   sleep 0.5
@@ -2148,6 +2205,13 @@ test_clustering_metrics() {
   LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/metrics | grep -xF 'lxd_instances{project="default",type="container"} 2'
   LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/metrics | grep -xF 'lxd_instances{project="default",type="container"} 1'
 
+  # The dqlite and raft health is reported.
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/metrics | grep -xF 'lxd_cluster_raft_role{member="node1",role="voter"} 1'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/metrics | grep -xF 'lxd_cluster_raft_role{member="node2",role="stand-by"} 1'
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/metrics | grep -xF 'lxd_cluster_raft_leader_changes_total 0'
+  LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/metrics | grep -xF 'lxd_cluster_raft_leader_changes_total 0'
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/metrics | grep '^lxd_cluster_raft_apply_latency_seconds ' || false
+
   # Remove previously existing warnings so they don't interfere with tests.
   LXD_DIR="${LXD_ONE_DIR}" lxc warning delete --all
 
@@ -3634,6 +3698,290 @@ test_clustering_evacuation() {
   LXD_NETNS=
 }
 
+test_clustering_maintenance() {
+  local LXD_DIR
+
+  setup_clustering_bridge
+  prefix="lxd$$"
+  bridge="${prefix}"
+
+  # The random storage backend is not supported in clustering tests,
+  # since we need to have the same storage driver on all nodes, so use the driver chosen for the standalone pool.
+  poolDriver=$(lxc storage show "$(lxc profile device get default root pool)" | awk '/^driver:/ {print $2}')
+
+  # Spawn first node
+  setup_clustering_netns 1
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns1="${prefix}1"
+  spawn_lxd_and_bootstrap_cluster "${ns1}" "${bridge}" "${LXD_ONE_DIR}" "${poolDriver}"
+
+  # Add a newline at the end of each line. YAML has weird rules.
+  cert=$(sed ':a;N;$!ba;s/\n/\n\n/g' "${LXD_ONE_DIR}/cluster.crt")
+
+  # Spawn a second node
+  setup_clustering_netns 2
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns2="${prefix}2"
+  spawn_lxd_and_join_cluster "${ns2}" "${bridge}" "${cert}" 2 1 "${LXD_TWO_DIR}" "${LXD_ONE_DIR}" "${poolDriver}"
+
+  LXD_DIR="${LXD_ONE_DIR}" ensure_import_testimage
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c1 --target=node1 -c boot.host_shutdown_timeout=1
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c2 --target=node1 -c boot.host_shutdown_timeout=1
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch testimage c3 --target=node1 -c boot.host_shutdown_timeout=1
+
+  # Invalid maintenance requests
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc query -X PUT -d '{\"maintenance\": true, \"policy\": \"destroy\"}' /1.0/cluster/members/node1/state || false
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc query -X PUT -d '{\"maintenance\": true, \"instance_policies\": {\"c1\": \"stop\"}}' /1.0/cluster/members/node1/state || false
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc query -X PUT -d '{\"maintenance\": true, \"instance_policies\": {\"default/c1\": \"destroy\"}}' /1.0/cluster/members/node1/state || false
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc query -X PUT -d '{\"maintenance\": false, \"policy\": \"stop\"}' /1.0/cluster/members/node1/state || false
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc query -X PUT -d '{\"maintenance\": false, \"instance_policies\": {\"default/c1\": \"stop\"}}' /1.0/cluster/members/node1/state || false
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/cluster/members/node1/state | jq -r '.maintenance')" = "null" ]
+
+  # Drain the first node with a policy per instance
+  LXD_DIR="${LXD_TWO_DIR}" lxc query --wait -X PUT -d '{\"maintenance\": true, \"policy\": \"migrate\", \"instance_policies\": {\"default/c2\": \"stop\", \"default/c3\": \"skip\"}}' /1.0/cluster/members/node1/state
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster show node1 | grep -xF "status: Evacuated"
+
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc list -f csv -c sL c1)" = "RUNNING,node2" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc list -f csv -c sL c2)" = "STOPPED,node1" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc list -f csv -c sL c3)" = "RUNNING,node1" ]
+
+  # No new instances are placed on the node
+  ! LXD_DIR="${LXD_TWO_DIR}" lxc init --empty c4 --target=node1 || false
+
+  # The drain progress is reported in the member state
+  LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/cluster/members/node1/state > "${TEST_DIR}/maintenance.json"
+  [ "$(jq -r '.maintenance.status' "${TEST_DIR}/maintenance.json")" = "drained" ]
+  [ "$(jq -r '.maintenance.instances[] | select(.name == "c1") | [.policy, .status, .target] | join(",")' "${TEST_DIR}/maintenance.json")" = "migrate,migrated,node2" ]
+  [ "$(jq -r '.maintenance.instances[] | select(.name == "c2") | [.policy, .status] | join(",")' "${TEST_DIR}/maintenance.json")" = "stop,stopped" ]
+  [ "$(jq -r '.maintenance.instances[] | select(.name == "c3") | [.policy, .status] | join(",")' "${TEST_DIR}/maintenance.json")" = "skip,skipped" ]
+  rm "${TEST_DIR}/maintenance.json"
+
+  # Leave maintenance mode, restoring the instances
+  LXD_DIR="${LXD_TWO_DIR}" lxc query --wait -X PUT -d '{\"maintenance\": false}' /1.0/cluster/members/node1/state
+  LXD_DIR="${LXD_TWO_DIR}" lxc cluster show node1 | grep -xF "status: Online"
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc list -f csv -c sL c1)" = "RUNNING,node1" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc list -f csv -c sL c2)" = "RUNNING,node1" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc list -f csv -c sL c3)" = "RUNNING,node1" ]
+  [ "$(LXD_DIR="${LXD_TWO_DIR}" lxc query /1.0/cluster/members/node1/state | jq -r '.maintenance')" = "null" ]
+
+  # Clean up
+  LXD_DIR="${LXD_TWO_DIR}" lxc delete -f c1 c2 c3
+  LXD_DIR="${LXD_TWO_DIR}" lxc image delete testimage
+
+  printf 'config: {}\ndevices: {}' | LXD_DIR="${LXD_ONE_DIR}" lxc profile edit default
+  LXD_DIR="${LXD_ONE_DIR}" lxc storage delete data
+
+  # Shut down cluster
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  sleep 0.5
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+
+  # shellcheck disable=SC2034
+  LXD_NETNS=
+}
+
+test_clustering_healing() {
+  local LXD_DIR
+
+  setup_clustering_bridge
+  prefix="lxd$$"
+  bridge="${prefix}"
+
+  # The random storage backend is not supported in clustering tests,
+  # since we need to have the same storage driver on all nodes, so use the driver chosen for the standalone pool.
+  poolDriver=$(lxc storage show "$(lxc profile device get default root pool)" | awk '/^driver:/ {print $2}')
+
+  # Spawn first node
+  setup_clustering_netns 1
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns1="${prefix}1"
+  spawn_lxd_and_bootstrap_cluster "${ns1}" "${bridge}" "${LXD_ONE_DIR}" "${poolDriver}"
+
+  # Add a newline at the end of each line. YAML has weird rules.
+  cert=$(sed ':a;N;$!ba;s/\n/\n\n/g' "${LXD_ONE_DIR}/cluster.crt")
+
+  # Spawn a second node
+  setup_clustering_netns 2
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns2="${prefix}2"
+  spawn_lxd_and_join_cluster "${ns2}" "${bridge}" "${cert}" 2 1 "${LXD_TWO_DIR}" "${LXD_ONE_DIR}" "${poolDriver}"
+
+  # Spawn a third node
+  setup_clustering_netns 3
+  LXD_THREE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns3="${prefix}3"
+  spawn_lxd_and_join_cluster "${ns3}" "${bridge}" "${cert}" 3 1 "${LXD_THREE_DIR}" "${LXD_ONE_DIR}" "${poolDriver}"
+
+  # Install the fence hooks on the members which can become leader.
+  for dir in "${LXD_ONE_DIR}" "${LXD_TWO_DIR}"; do
+    mkdir -p "${dir}/hooks"
+    cat > "${dir}/hooks/fence" << EOF
+#!/bin/sh
+echo "\${LXD_MEMBER_NAME}" >> "${TEST_DIR}/fenced"
+EOF
+
+    cat > "${dir}/hooks/fail" << EOF
+#!/bin/sh
+echo "\${LXD_MEMBER_NAME}" >> "${TEST_DIR}/fence-attempts"
+exit 1
+EOF
+
+    chmod 0755 "${dir}/hooks/fence" "${dir}/hooks/fail"
+  done
+
+  # Invalid hook names are rejected.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.healing_fence_hook=../fence || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.healing_fence_hook=/bin/true || false
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.offline_threshold=11 cluster.healing_threshold=11 cluster.healing_fence_hook=fail
+
+  # The health of the cluster subsystems is reported.
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/health | jq -r '[.checks[].name] | join(",")')" = "database,storage,network,events,images,time" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/health | jq -r '.checks[] | select(.name == "database") | .message' | grep -xF "3 of 3 cluster members online"
+
+  # Take the third node offline.
+  LXD_DIR="${LXD_THREE_DIR}" lxd shutdown
+  sleep 0.5
+  rm -f "${LXD_THREE_DIR}/unix.socket"
+
+  # A failing fence hook prevents the member from being healed.
+  for _ in $(seq 120); do
+    [ -s "${TEST_DIR}/fence-attempts" ] && break
+    sleep 1
+  done
+
+  [ "$(head -n1 "${TEST_DIR}/fence-attempts")" = "node3" ]
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxd sql global --format csv "SELECT state FROM nodes WHERE name = 'node3'")" = "0" ]
+  [ ! -e "${TEST_DIR}/fenced" ]
+
+  # The cluster is reported as degraded, without failing the health probes.
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/health | jq -r '.status')" = "degraded" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc query /1.0/health | jq -r '.checks[] | select(.name == "database") | .message' | grep -xF "2 of 3 cluster members online"
+  [ "$(curl --silent --unix-socket "${LXD_ONE_DIR}/unix.socket" --output /dev/null --write-out "%{http_code}" "lxd/1.0/health")" = "200" ]
+
+  # The member is healed once fenced.
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.healing_fence_hook=fence
+  for _ in $(seq 120); do
+    [ "$(LXD_DIR="${LXD_ONE_DIR}" lxd sql global --format csv "SELECT state FROM nodes WHERE name = 'node3'")" = "2" ] && break
+    sleep 1
+  done
+
+  [ "$(cat "${TEST_DIR}/fenced")" = "node3" ]
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxd sql global --format csv "SELECT state FROM nodes WHERE name = 'node3'")" = "2" ]
+
+  # Evacuated members aren't fenced again.
+  sleep 70
+  [ "$(wc -l < "${TEST_DIR}/fenced")" = "1" ]
+
+  # Clean up
+  rm -f "${TEST_DIR}/fenced" "${TEST_DIR}/fence-attempts"
+  LXD_DIR="${LXD_ONE_DIR}" lxc config unset cluster.healing_threshold
+  LXD_DIR="${LXD_ONE_DIR}" lxc config unset cluster.healing_fence_hook
+
+  # Shut down cluster
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  sleep 0.5
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+  kill_lxd "${LXD_THREE_DIR}"
+
+  # shellcheck disable=SC2034
+  LXD_NETNS=
+}
+
+test_clustering_partition() {
+  local LXD_DIR
+
+  setup_clustering_bridge
+  prefix="lxd$$"
+  bridge="${prefix}"
+
+  # Spawn first node
+  setup_clustering_netns 1
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns1="${prefix}1"
+  spawn_lxd_and_bootstrap_cluster "${ns1}" "${bridge}" "${LXD_ONE_DIR}"
+
+  # Add a newline at the end of each line. YAML has weird rules.
+  cert=$(sed ':a;N;$!ba;s/\n/\n\n/g' "${LXD_ONE_DIR}/cluster.crt")
+
+  # Spawn a second node
+  setup_clustering_netns 2
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns2="${prefix}2"
+  spawn_lxd_and_join_cluster "${ns2}" "${bridge}" "${cert}" 2 1 "${LXD_TWO_DIR}" "${LXD_ONE_DIR}"
+
+  # Install the fence hook on the second node.
+  mkdir -p "${LXD_TWO_DIR}/hooks"
+  cat > "${LXD_TWO_DIR}/hooks/fence" << EOF
+#!/bin/sh
+echo "\${LXD_MEMBER_NAME},\${LXD_LAST_HEARTBEAT}" > "${TEST_DIR}/partition"
+EOF
+  chmod 0755 "${LXD_TWO_DIR}/hooks/fence"
+
+  # Invalid hook names are rejected.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.partition_fence_hook=../fence || false
+
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.offline_threshold=11 cluster.partition_fence_hook=fence
+
+  # Wait for the second node to be in contact with the leader.
+  sleep 15
+
+  # Isolate the second node by freezing the leader.
+  kill -STOP "$(< "${LXD_ONE_DIR}/lxd.pid")"
+
+  for _ in $(seq 60); do
+    [ -s "${TEST_DIR}/partition" ] && break
+    sleep 1
+  done
+
+  kill -CONT "$(< "${LXD_ONE_DIR}/lxd.pid")"
+
+  # The isolated node fenced itself.
+  [ "$(cut -d, -f1 "${TEST_DIR}/partition")" = "node2" ]
+  [ -n "$(cut -d, -f2 "${TEST_DIR}/partition")" ]
+
+  # A warning is recorded once the partition is resolved.
+  for _ in $(seq 60); do
+    LXD_DIR="${LXD_TWO_DIR}" lxc query "/1.0/warnings?recursion=1" | jq -e '.[] | select(.type == "Cluster member was isolated from the cluster")' && break
+    sleep 1
+  done
+
+  LXD_DIR="${LXD_TWO_DIR}" lxc query "/1.0/warnings?recursion=1" | jq -r '.[] | select(.type == "Cluster member was isolated from the cluster") | "\(.location),\(.last_message)"' | grep "^node2,.*fenced: true$"
+
+  # Clean up
+  rm -f "${TEST_DIR}/partition"
+  LXD_DIR="${LXD_ONE_DIR}" lxc config unset cluster.partition_fence_hook
+
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+  sleep 0.5
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+}
+
 test_clustering_edit_configuration() {
   local LXD_DIR
 
@@ -3960,6 +4308,73 @@ test_clustering_autotarget() {
   kill_lxd "${LXD_TWO_DIR}"
 }
 
+test_clustering_placement() {
+  local LXD_DIR
+
+  setup_clustering_bridge
+  prefix="lxd$$"
+  bridge="${prefix}"
+
+  setup_clustering_netns 1
+  LXD_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns1="${prefix}1"
+  spawn_lxd_and_bootstrap_cluster "${ns1}" "${bridge}" "${LXD_ONE_DIR}"
+
+  # Add a newline at the end of each line. YAML has weird rules.
+  cert=$(sed ':a;N;$!ba;s/\n/\n\n/g' "${LXD_ONE_DIR}/cluster.crt")
+
+  # Spawn a second node
+  setup_clustering_netns 2
+  LXD_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  ns2="${prefix}2"
+  spawn_lxd_and_join_cluster "${ns2}" "${bridge}" "${cert}" 2 1 "${LXD_TWO_DIR}" "${LXD_ONE_DIR}"
+
+  # Use node1 for all cluster actions.
+  LXD_DIR="${LXD_ONE_DIR}"
+
+  lxc profile create web
+  ! lxc profile set web placement.anti_affinity=app,, || false
+  lxc profile set web placement.anti_affinity=app
+
+  # Instances sharing the anti-affinity label are spread over the members.
+  lxc query --wait -X POST -d '{\"name\": \"c1\", \"source\": {\"type\": \"none\"}, \"profiles\": [\"default\", \"web\"], \"labels\": {\"app\": \"web\"}}' /1.0/instances
+  lxc query --wait -X POST -d '{\"name\": \"c2\", \"source\": {\"type\": \"none\"}, \"profiles\": [\"default\", \"web\"], \"labels\": {\"app\": \"web\"}}' /1.0/instances
+  [ "$(lxc list -f csv -c L c1)" != "$(lxc list -f csv -c L c2)" ]
+
+  # No member is left for a third instance, the error explains why.
+  ! lxc query --wait -X POST -d '{\"name\": \"c3\", \"source\": {\"type\": \"none\"}, \"profiles\": [\"default\", \"web\"], \"labels\": {\"app\": \"web\"}}' /1.0/instances 2> "${TEST_DIR}/placement.err" || false
+  grep -F "No suitable cluster member could be found" "${TEST_DIR}/placement.err"
+  grep -F "placement.anti_affinity" "${TEST_DIR}/placement.err"
+
+  # Instances with another label value aren't affected.
+  lxc query --wait -X POST -d '{\"name\": \"c3\", \"source\": {\"type\": \"none\"}, \"profiles\": [\"default\", \"web\"], \"labels\": {\"app\": \"db\"}}' /1.0/instances
+
+  # Members of the preferred group are picked first.
+  lxc cluster group create fast
+  lxc cluster group assign node2 default,fast
+  lxc init --empty c4 -c placement.preferred_group=fast
+  lxc init --empty c5 -c placement.preferred_group=fast
+  [ "$(lxc list -f csv -c L c4)" = "node2" ]
+  [ "$(lxc list -f csv -c L c5)" = "node2" ]
+
+  lxc delete c1 c2 c3 c4 c5
+  lxc profile delete web
+  lxc cluster group assign node2 default
+  lxc cluster group delete fast
+
+  shutdown_lxd "${LXD_ONE_DIR}"
+  shutdown_lxd "${LXD_TWO_DIR}"
+  sleep 0.5
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+}
+
 test_clustering_groups() {
   local LXD_DIR
 
@@ -145,28 +145,28 @@ test_completions() {
 
     # 'config'
     [ "$(complete config show '')" = 'c1,c2,localhost:' ]
-    [ "$(complete config set '')" = 'acme.,backups.,c1,c2,cluster.,core.,images.,instances.,localhost:,loki.,maas.,network.,oidc.,storage.,user.' ]
+    [ "$(complete config set '')" = 'acme.,admission.,alerts.,backups.,c1,c2,cluster.,core.,images.,instances.,localhost:,loki.,maas.,network.,oidc.,replication.,sinks.,smtp.,storage.,user.,webhook.' ]
     [ "$(complete config set n)" = 'network.' ]
     [ "$(complete config set c)" = 'c1,c2,cluster.,core.' ]
     [ "$(complete config set l)" = 'localhost:,loki.' ]
     [ "$(complete config set localhost: m)" = 'maas.' ]
     [ "$(complete config set localhost: maas.)" = 'maas.api.,maas.machine=' ]
     [ "$(complete config set localhost: maas.api.)" = 'maas.api.key=,maas.api.url=' ]
-    [ "$(complete config set c1 '')" = 'boot.,cloud-init.,cluster.,environment.,limits.,linux.,migration.,nvidia.,raw.,security.,snapshots.,ubuntu_pro.,user.' ]
+    [ "$(complete config set c1 '')" = 'boot.,cloud-init.,cluster.,environment.,hooks.,limits.,linux.,migration.,nvidia.,placement.,raw.,replication.,security.,snapshots.,ubuntu_pro.,user.' ]
     [ "$(complete config set c1 l)" = 'limits.,linux.' ]
-    [ "$(complete config set localhost:c1 '')" = 'boot.,cloud-init.,cluster.,environment.,limits.,linux.,migration.,nvidia.,raw.,security.,snapshots.,ubuntu_pro.,user.' ]
+    [ "$(complete config set localhost:c1 '')" = 'boot.,cloud-init.,cluster.,environment.,hooks.,limits.,linux.,migration.,nvidia.,placement.,raw.,replication.,security.,snapshots.,ubuntu_pro.,user.' ]
     [ "$(complete config set c1 limits.)" = 'limits.cpu.,limits.cpu=,limits.disk.,limits.hugepages.,limits.kernel.,limits.memory.,limits.memory=,limits.processes=' ]
     [ "$(complete config set c1 migration.)" = 'migration.incremental.' ] # No .stateful because c1 is not a VM.
-    [ "$(complete config get '')" = 'acme.,backups.,c1,c2,cluster.,core.,images.,instances.,localhost:,loki.,maas.,network.,oidc.,storage.,user.' ]
+    [ "$(complete config get '')" = 'acme.,admission.,alerts.,backups.,c1,c2,cluster.,core.,images.,instances.,localhost:,loki.,maas.,network.,oidc.,replication.,sinks.,smtp.,storage.,user.,webhook.' ]
     [ "$(complete config get n)" = 'network.' ]
     [ "$(complete config get c)" = 'c1,c2,cluster.,core.' ]
     [ "$(complete config get l)" = 'localhost:,loki.' ]
     [ "$(complete config get localhost: m)" = 'maas.' ]
     [ "$(complete config get localhost: maas.)" = 'maas.api.,maas.machine' ]
     [ "$(complete config get localhost: maas.api.)" = 'maas.api.key,maas.api.url' ]
-    [ "$(complete config get c1 '')" = 'boot.,cloud-init.,cluster.,environment.,limits.,linux.,migration.,nvidia.,raw.,security.,snapshots.,ubuntu_pro.,user.' ]
+    [ "$(complete config get c1 '')" = 'boot.,cloud-init.,cluster.,environment.,hooks.,limits.,linux.,migration.,nvidia.,placement.,raw.,replication.,security.,snapshots.,ubuntu_pro.,user.' ]
     [ "$(complete config get c1 l)" = 'limits.,linux.' ]
-    [ "$(complete config get localhost:c1 '')" = 'boot.,cloud-init.,cluster.,environment.,limits.,linux.,migration.,nvidia.,raw.,security.,snapshots.,ubuntu_pro.,user.' ]
+    [ "$(complete config get localhost:c1 '')" = 'boot.,cloud-init.,cluster.,environment.,hooks.,limits.,linux.,migration.,nvidia.,placement.,raw.,replication.,security.,snapshots.,ubuntu_pro.,user.' ]
     [ "$(complete config get c1 limits.)" = 'limits.cpu,limits.cpu.,limits.disk.,limits.hugepages.,limits.kernel.,limits.memory,limits.memory.,limits.processes' ]
     lxc config set user.foo=bar
     [ "$(complete config unset '')" = 'c1,c2,core.https_address,localhost:,user.foo' ]
@@ -187,7 +187,7 @@ test_completions() {
     [ "$(complete config device add c)" = 'c1,c2' ]
     [ "$(complete config device add l)" = 'localhost:' ]
     [ "$(complete config device add localhost:)" = 'localhost:c1,localhost:c2' ]
-    [ "$(complete config device add c1 devname '')" = 'disk,gpu,infiniband,nic,pci,proxy,tpm,unix-block,unix-char,unix-hotplug,usb' ]
+    [ "$(complete config device add c1 devname '')" = 'disk,gpu,infiniband,nic,pci,proxy,secret,tpm,unix-block,unix-char,unix-hotplug,usb,watchdog' ]
     [ "$(complete config device add c1 devname u)" = 'unix-block,unix-char,unix-hotplug,usb' ]
     [ "$(complete config device add c1 devname disk '')" = 'boot.,ceph.,initial.,io.,limits.,path=,pool=,propagation=,raw.,readonly=,recursive=,required=,shift=,size.,size=,source.,source=' ]
     [ "$(complete config device add c1 devname gpu '')" = 'gputype=' ]
@@ -204,7 +204,7 @@ test_completions() {
     [ "$(complete config device add c1 devname unix-block '')" = 'gid=,major=,minor=,mode=,path=,required=,source=,uid=' ]
     [ "$(complete config device add c1 devname unix-char '')" = 'gid=,major=,minor=,mode=,path=,required=,source=,uid=' ]
     [ "$(complete config device add c1 devname unix-hotplug '')" = 'gid=,mode=,ownership.,productid=,required=,subsystem=,uid=,vendorid=' ]
-    [ "$(complete config device add c1 devname usb '')" = 'busnum=,devnum=,gid=,mode=,productid=,required=,serial=,uid=,vendorid=' ]
+    [ "$(complete config device add c1 devname usb '')" = 'busnum=,devnum=,gid=,hotplug=,mode=,productid=,required=,serial=,uid=,vendorid=' ]
     [ "$(complete config device add c1 devname nic '')" = 'network=,nictype=' ]
     [ "$(complete config device add c1 devname nic network=)" = "$(lxc query /1.0/networks?recursion=1 | jq -r '["network="+.[].name] | sort | @csv | sub("\"";"";"g")')" ]
     [ "$(complete config device add c1 devname nic network=lxdbr0 '' || echo fail)" = '' ]
@@ -213,7 +213,7 @@ test_completions() {
     [ "$(complete config device add c1 devname nic nictype=bridged '')" = 'boot.,host_name=,hwaddr=,ipv4.,ipv6.,limits.,maas.,mtu=,name=,network=,parent=,queue.,security.,vlan.,vlan=' ]
     [ "$(complete config device add c1 devname nic nictype=ipvlan '')" = 'gvrp=,hwaddr=,ipv4.,ipv6.,mode=,mtu=,name=,parent=,vlan=' ]
     [ "$(complete config device add c1 devname nic nictype=macvlan '')" = 'boot.,gvrp=,hwaddr=,maas.,mtu=,name=,network=,parent=,vlan=' ]
-    [ "$(complete config device add c1 devname nic nictype=ovn '')" = 'acceleration=,boot.,host_name=,hwaddr=,ipv4.,ipv6.,name=,nested=,network=,security.,vlan=' ]
+    [ "$(complete config device add c1 devname nic nictype=ovn '')" = 'acceleration=,boot.,host_name=,hwaddr=,ipv4.,ipv6.,name=,nested=,network.,network=,security.,vlan=' ]
     [ "$(complete config device add c1 devname nic nictype=p2p '')" = 'boot.,host_name=,hwaddr=,ipv4.,ipv6.,limits.,mtu=,name=,queue.' ]
     [ "$(complete config device add c1 devname nic nictype=physical '')" = 'boot.,gvrp=,hwaddr=,maas.,mtu=,name=,network=,parent=,vlan=' ]
     [ "$(complete config device add c1 devname nic nictype=routed '')" = 'gvrp=,host_name=,hwaddr=,ipv4.,ipv6.,limits.,mtu=,name=,parent=,queue.,vlan=' ]
@@ -222,7 +222,7 @@ test_completions() {
     [ "$(complete config device override c)" = 'c1,c2' ]
     [ "$(complete config device override l)" = 'localhost:' ]
     [ "$(complete config device override localhost:)" = 'localhost:c1,localhost:c2' ]
-    [ "$(complete config device override c1 devname '')" = 'disk,gpu,infiniband,nic,pci,proxy,tpm,unix-block,unix-char,unix-hotplug,usb' ]
+    [ "$(complete config device override c1 devname '')" = 'disk,gpu,infiniband,nic,pci,proxy,secret,tpm,unix-block,unix-char,unix-hotplug,usb,watchdog' ]
     [ "$(complete config device override c1 devname u)" = 'unix-block,unix-char,unix-hotplug,usb' ]
     [ "$(complete config device override c1 devname disk '')" = 'boot.,ceph.,initial.,io.,limits.,path=,pool=,propagation=,raw.,readonly=,recursive=,required=,shift=,size.,size=,source.,source=' ]
     [ "$(complete config device override c1 devname gpu '')" = 'gputype=' ]
@@ -239,7 +239,7 @@ test_completions() {
     [ "$(complete config device override c1 devname unix-block '')" = 'gid=,major=,minor=,mode=,path=,required=,source=,uid=' ]
     [ "$(complete config device override c1 devname unix-char '')" = 'gid=,major=,minor=,mode=,path=,required=,source=,uid=' ]
     [ "$(complete config device override c1 devname unix-hotplug '')" = 'gid=,mode=,ownership.,productid=,required=,subsystem=,uid=,vendorid=' ]
-    [ "$(complete config device override c1 devname usb '')" = 'busnum=,devnum=,gid=,mode=,productid=,required=,serial=,uid=,vendorid=' ]
+    [ "$(complete config device override c1 devname usb '')" = 'busnum=,devnum=,gid=,hotplug=,mode=,productid=,required=,serial=,uid=,vendorid=' ]
     [ "$(complete config device override c1 devname nic '')" = 'network=,nictype=' ]
     [ "$(complete config device override c1 devname nic network=)" = "$(lxc query /1.0/networks?recursion=1 | jq -r '["network="+.[].name] | sort | @csv | sub("\"";"";"g")')" ]
     [ "$(complete config device override c1 devname nic network=lxdbr0 '' || echo fail)" = '' ]
@@ -248,7 +248,7 @@ test_completions() {
     [ "$(complete config device override c1 devname nic nictype=bridged '')" = 'boot.,host_name=,hwaddr=,ipv4.,ipv6.,limits.,maas.,mtu=,name=,network=,parent=,queue.,security.,vlan.,vlan=' ]
     [ "$(complete config device override c1 devname nic nictype=ipvlan '')" = 'gvrp=,hwaddr=,ipv4.,ipv6.,mode=,mtu=,name=,parent=,vlan=' ]
     [ "$(complete config device override c1 devname nic nictype=macvlan '')" = 'boot.,gvrp=,hwaddr=,maas.,mtu=,name=,network=,parent=,vlan=' ]
-    [ "$(complete config device override c1 devname nic nictype=ovn '')" = 'acceleration=,boot.,host_name=,hwaddr=,ipv4.,ipv6.,name=,nested=,network=,security.,vlan=' ]
+    [ "$(complete config device override c1 devname nic nictype=ovn '')" = 'acceleration=,boot.,host_name=,hwaddr=,ipv4.,ipv6.,name=,nested=,network.,network=,security.,vlan=' ]
     [ "$(complete config device override c1 devname nic nictype=p2p '')" = 'boot.,host_name=,hwaddr=,ipv4.,ipv6.,limits.,mtu=,name=,queue.' ]
     [ "$(complete config device override c1 devname nic nictype=physical '')" = 'boot.,gvrp=,hwaddr=,maas.,mtu=,name=,network=,parent=,vlan=' ]
     [ "$(complete config device override c1 devname nic nictype=routed '')" = 'gvrp=,host_name=,hwaddr=,ipv4.,ipv6.,limits.,mtu=,name=,parent=,queue.,vlan=' ]
@@ -0,0 +1,39 @@
+test_container_devices_secret() {
+  ensure_import_testimage
+  ctName="ct$$"
+  lxc project set default secret.db_password=s3cr3t
+  lxc launch testimage "${ctName}"
+
+  # Check the config validation.
+  ! lxc config device add "${ctName}" secret1 secret path=/run/db_password || false
+  ! lxc config device add "${ctName}" secret1 secret source=db_password || false
+  ! lxc config device add "${ctName}" secret1 secret source=db_password path=run/db_password || false
+  ! lxc config device add "${ctName}" secret1 secret source=db_password path=/ || false
+  ! lxc config device add "${ctName}" secret1 secret source=db_password path=/run/db_password mode=999 || false
+
+  # Hotplug the secret and check its content, ownership and mode.
+  lxc config device add "${ctName}" secret1 secret source=db_password path=/run/db_password uid=1000 gid=1000 mode=0440
+  [ "$(lxc exec "${ctName}" -- cat /run/db_password)" = "s3cr3t" ]
+  [ "$(lxc exec "${ctName}" -- stat -c '%u:%g:%a' /run/db_password)" = "1000:1000:440" ]
+  ! lxc exec "${ctName}" -- sh -c 'echo changed > /run/db_password' || false
+
+  # The secret isn't left on the host.
+  ! ls "${LXD_DIR}/devices/${ctName}/secret."* || false
+
+  # Check the secret survives a restart and picks up the new value.
+  lxc project set default secret.db_password=rotated
+  lxc restart -f "${ctName}"
+  [ "$(lxc exec "${ctName}" -- cat /run/db_password)" = "rotated" ]
+
+  # Removing the device removes the secret.
+  lxc config device remove "${ctName}" secret1
+  ! lxc exec "${ctName}" -- cat /run/db_password || false
+
+  # Unknown secrets prevent the instance from starting.
+  lxc stop -f "${ctName}"
+  lxc config device add "${ctName}" secret2 secret source=missing path=/run/missing
+  ! lxc start "${ctName}" || false
+
+  lxc delete -f "${ctName}"
+  lxc project unset default secret.db_password
+}
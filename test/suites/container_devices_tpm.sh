@@ -35,6 +35,25 @@ test_container_devices_tpm() {
   lxc exec "${ctName}" -- stat /dev/tpm0
   lxc exec "${ctName}" -- stat /dev/tpmrm0
 
+  # The TPM state can only be accessed while the instance is stopped
+  [ "$(curl --silent --output /dev/null --write-out "%{http_code}" --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${ctName}/tpm/test-dev1")" = "400" ]
+  lxc stop -f "${ctName}"
+  [ "$(curl --silent --output /dev/null --write-out "%{http_code}" --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${ctName}/tpm/missing")" = "404" ]
+
+  # Export, reset and import the TPM state
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${ctName}/tpm/test-dev1" > "${TEST_DIR}/tpm.tar.gz"
+  tar -tzf "${TEST_DIR}/tpm.tar.gz" | grep -xF tpm2-00.permall
+  lxc query -X DELETE "/1.0/instances/${ctName}/tpm/test-dev1"
+  [ "$(curl --silent --output /dev/null --write-out "%{http_code}" --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${ctName}/tpm/test-dev1")" = "404" ]
+  ! curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" -X PUT --data-binary "not a tarball" "lxd/1.0/instances/${ctName}/tpm/test-dev1" || false
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" -X PUT --data-binary "@${TEST_DIR}/tpm.tar.gz" "lxd/1.0/instances/${ctName}/tpm/test-dev1"
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${ctName}/tpm/test-dev1" | tar -tz | grep -xF tpm2-00.permall
+  rm "${TEST_DIR}/tpm.tar.gz"
+
+  # The imported state is used by the TPM
+  lxc start "${ctName}"
+  lxc exec "${ctName}" -- stat /dev/tpm0
+
   # Remove device
   lxc config device rm "${ctName}" test-dev1
   ! lxc exec "${ctName}" -- stat /dev/tpm0 || false
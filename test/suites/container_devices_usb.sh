@@ -0,0 +1,21 @@
+test_container_devices_usb() {
+  ensure_import_testimage
+  ctName="ct$$"
+  lxc init testimage "${ctName}"
+
+  # Check the hotplug policy validation.
+  ! lxc config device add "${ctName}" usb1 usb vendorid=1234 hotplug=maybe || false
+  lxc config device add "${ctName}" usb1 usb vendorid=1234 serial=ABC123 required=false hotplug=false
+  [ "$(lxc config device get "${ctName}" usb1 hotplug)" = "false" ]
+  ! lxc config device set "${ctName}" usb1 hotplug=maybe || false
+
+  # Start without any matching device, without following the host devices.
+  lxc start "${ctName}"
+  lxc stop -f "${ctName}"
+
+  # Start without any matching device, following the host devices.
+  lxc config device set "${ctName}" usb1 hotplug=true
+  lxc start "${ctName}"
+
+  lxc delete -f "${ctName}"
+}
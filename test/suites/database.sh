@@ -89,3 +89,32 @@ test_database_no_disk_space() {
   umount "${GLOBAL_DB_DIR}"
   kill_lxd "${LXD_NOSPACE_DIR}"
 }
+
+test_database_maintenance() {
+  # Invalid tasks are rejected.
+  ! lxc query -X POST -d '{\"tasks\": [\"drop\"]}' /1.0/cluster/database/maintenance || false
+
+  # The usage is reported by default.
+  lxc query --wait -X POST -d '{}' /1.0/cluster/database/maintenance > "${TEST_DIR}/maintenance.json"
+  [ "$(jq -r '.status' "${TEST_DIR}/maintenance.json")" = "Success" ]
+  [ "$(jq -r '.metadata.maintenance.tables.profiles' "${TEST_DIR}/maintenance.json")" -ge 1 ]
+  [ "$(jq -r '.metadata.maintenance.page_size * .metadata.maintenance.page_count == .metadata.maintenance.size' "${TEST_DIR}/maintenance.json")" = "true" ]
+  [ "$(jq -r '.metadata.maintenance.integrity' "${TEST_DIR}/maintenance.json")" = "null" ]
+
+  # Create and delete some data to have pages to reclaim.
+  for i in $(seq 20); do
+    lxc profile create "p${i}"
+    lxc profile set "p${i}" user.data="$(head -c 2048 /dev/zero | tr '\0' 'x')"
+  done
+
+  for i in $(seq 20); do
+    lxc profile delete "p${i}"
+  done
+
+  lxc query --wait -X POST -d '{\"tasks\": [\"integrity-check\", \"vacuum\"]}' /1.0/cluster/database/maintenance > "${TEST_DIR}/maintenance.json"
+  [ "$(jq -r '.metadata.maintenance.integrity | join(",")' "${TEST_DIR}/maintenance.json")" = "ok" ]
+  [ "$(jq -r '.metadata.maintenance.free_pages' "${TEST_DIR}/maintenance.json")" = "0" ]
+  [ "$(jq -r '.metadata.maintenance.size_reclaimed' "${TEST_DIR}/maintenance.json")" -ge 0 ]
+  [ "$(jq -r '.metadata.maintenance.tables' "${TEST_DIR}/maintenance.json")" = "null" ]
+  rm "${TEST_DIR}/maintenance.json"
+}
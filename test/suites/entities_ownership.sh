@@ -0,0 +1,51 @@
+test_entities_ownership() {
+  ensure_import_testimage
+
+  pool="$(lxc profile device get default root pool)"
+  ownerships="$(lxd sql global --format csv "SELECT count(*) FROM entities_ownership")"
+
+  lxc init testimage c1
+  lxc profile create p1
+  lxc network create n1 ipv4.address=none ipv6.address=none
+  lxc storage volume create "${pool}" vol1
+
+  # The creations are recorded in the background, from the lifecycle events.
+  for _ in $(seq 10); do
+    [ -n "$(lxc query /1.0/storage-pools/"${pool}"/volumes/custom/vol1 | jq -r '.created_by')" ] && break
+    sleep 1
+  done
+
+  owner="$(lxc query /1.0/instances/c1 | jq -r '.created_by')"
+  [ -n "${owner}" ]
+  [ "$(lxc query /1.0/instances/c1 | jq -r '.last_modified_by')" = "${owner}" ]
+  [ "$(lxc query /1.0/profiles/p1 | jq -r '.created_by')" = "${owner}" ]
+  [ "$(lxc query /1.0/networks/n1 | jq -r '.created_by')" = "${owner}" ]
+  [ "$(lxc query /1.0/storage-pools/"${pool}"/volumes/custom/vol1 | jq -r '.created_by')" = "${owner}" ]
+  lxc query /1.0/profiles/p1 | jq -e '.created_at | startswith("0001") | not'
+
+  # The lists can be filtered on the ownership.
+  [ "$(lxc query "/1.0/instances?filter=created_by+eq+${owner}" | jq -r '.[]')" = "/1.0/instances/c1" ]
+  [ "$(lxc query "/1.0/instances?filter=created_by+eq+nobody" | jq -r 'length')" = "0" ]
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes?filter=created_by+eq+${owner}" | jq -r '.[]')" = "/1.0/storage-pools/${pool}/volumes/custom/vol1" ]
+
+  # The modifications by other identities are recorded without changing the creator.
+  fingerprint="$(cert_fingerprint "${LXD_CONF}/client.crt")"
+  my_curl -X PATCH -H 'Content-Type: application/json' --data '{"config": {"user.foo": "bar"}}' "https://${LXD_ADDR}/1.0/instances/c1" | jq -e '.status_code == 200'
+  my_curl -X PATCH -H 'Content-Type: application/json' --data '{"config": {"user.foo": "bar"}}' "https://${LXD_ADDR}/1.0/profiles/p1" | jq -e '.status_code == 200'
+  for _ in $(seq 10); do
+    [ "$(lxc query /1.0/profiles/p1 | jq -r '.last_modified_by')" = "${fingerprint}" ] && break
+    sleep 1
+  done
+
+  [ "$(lxc query /1.0/instances/c1 | jq -r '.last_modified_by')" = "${fingerprint}" ]
+  [ "$(lxc query /1.0/instances/c1 | jq -r '.created_by')" = "${owner}" ]
+  [ "$(lxc query /1.0/profiles/p1 | jq -r '.last_modified_by')" = "${fingerprint}" ]
+  [ "$(lxc query "/1.0/instances?filter=last_modified_by+eq+${fingerprint}" | jq -r '.[]')" = "/1.0/instances/c1" ]
+
+  # The ownership is removed along with the entities.
+  lxc delete c1
+  lxc profile delete p1
+  lxc network delete n1
+  lxc storage volume delete "${pool}" vol1
+  [ "$(lxd sql global --format csv "SELECT count(*) FROM entities_ownership")" = "${ownerships}" ]
+}
@@ -0,0 +1,35 @@
+test_events_history() {
+  # Invalid retention values are rejected.
+  ! lxc config set core.events_history_retention=-1 || false
+  ! lxc config set core.events_history_retention=9000 || false
+
+  lxc project create evhist -c features.profiles=true
+  lxc config set core.events_history_retention=24
+
+  lxc profile create p1 --project evhist
+  lxc profile delete p1 --project evhist
+
+  # The lifecycle events are persisted in the background.
+  for _ in $(seq 10); do
+    [ "$(lxc query "/1.0/events/history?project=evhist&type=lifecycle" | jq -r '[.[] | select(.project == "evhist") | .metadata.action] | join(",")')" = "profile-created,profile-deleted" ] && break
+    sleep 1
+  done
+
+  [ "$(lxc query "/1.0/events/history?project=evhist&type=lifecycle" | jq -r '[.[] | select(.project == "evhist") | .metadata.action] | join(",")')" = "profile-created,profile-deleted" ]
+
+  # Filter by type and time.
+  [ "$(lxc query "/1.0/events/history?project=evhist&type=operation" | jq -r '[.[] | select(.type != "operation")] | length')" = "0" ]
+  [ "$(lxc query "/1.0/events/history?project=evhist&since=$(date -u -d '+1 hour' +%Y-%m-%dT%H:%M:%SZ)" | jq -r 'length')" = "0" ]
+  ! lxc query "/1.0/events/history?since=foo" || false
+  ! lxc query "/1.0/events/history?type=foo" || false
+
+  # Events aren't persisted once the retention is unset.
+  lxc config unset core.events_history_retention
+  lxc profile create p2 --project evhist
+  sleep 2
+  [ "$(lxc query "/1.0/events/history?project=evhist&type=lifecycle" | jq -r '[.[] | select(.project == "evhist")] | length')" = "2" ]
+
+  # Clean up
+  lxc profile delete p2 --project evhist
+  lxc project delete evhist
+}
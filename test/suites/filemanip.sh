@@ -193,3 +193,47 @@ test_filemanip_req_content_type() {
 
   lxc delete "${inst}" --force
 }
+
+test_filemanip_tar() {
+  ensure_import_testimage
+
+  inst="c-file-tar"
+  lxc launch testimage "${inst}"
+
+  lxc exec "${inst}" -- mkdir -p /root/tree/sub /root/tree/logs
+  lxc exec "${inst}" -- sh -c 'echo foo > /root/tree/a.conf && echo bar > /root/tree/sub/b.conf && echo baz > /root/tree/sub/c.txt && echo log > /root/tree/logs/x.log'
+
+  echo "==> Download a directory tree as a tar stream"
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${inst}/files?path=/root/tree&format=tar" > "${TEST_DIR}/tree.tar"
+  [ "$(tar -tf "${TEST_DIR}/tree.tar" | sort | paste -sd,)" = "a.conf,logs/,logs/x.log,sub/,sub/b.conf,sub/c.txt" ]
+  [ "$(tar -xOf "${TEST_DIR}/tree.tar" sub/b.conf)" = "bar" ]
+
+  echo "==> Download a filtered directory tree"
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${inst}/files?path=/root/tree&format=tar&include=*.conf&exclude=logs" > "${TEST_DIR}/tree.tar"
+  [ "$(tar -tf "${TEST_DIR}/tree.tar" | sort | paste -sd,)" = "a.conf,sub/,sub/b.conf" ]
+
+  echo "==> Invalid patterns and missing paths are rejected"
+  [ "$(curl --silent --output /dev/null --write-out "%{http_code}" --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${inst}/files?path=/root/tree&format=tar&exclude=%5B")" = "400" ]
+  [ "$(curl --silent --output /dev/null --write-out "%{http_code}" --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/instances/${inst}/files?path=/root/missing&format=tar")" = "404" ]
+
+  echo "==> Upload a directory tree as a tar stream"
+  mkdir -p "${TEST_DIR}/upload/etc" "${TEST_DIR}/upload/skip"
+  echo "hello" > "${TEST_DIR}/upload/etc/hello.conf"
+  echo "ignored" > "${TEST_DIR}/upload/skip/ignored.conf"
+  ln -s hello.conf "${TEST_DIR}/upload/etc/link.conf"
+  tar -C "${TEST_DIR}/upload" -cf - . | curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" -X POST -H "Content-Type: application/x-tar" --data-binary @- "lxd/1.0/instances/${inst}/files?path=/root/upload&format=tar&exclude=skip"
+  [ "$(lxc exec "${inst}" -- cat /root/upload/etc/hello.conf)" = "hello" ]
+  [ "$(lxc exec "${inst}" -- readlink /root/upload/etc/link.conf)" = "hello.conf" ]
+  ! lxc exec "${inst}" -- test -e /root/upload/skip || false
+
+  echo "==> Tar streams can't be extracted over a file"
+  [ "$(tar -C "${TEST_DIR}/upload" -cf - . | curl --silent --output /dev/null --write-out "%{http_code}" --unix-socket "${LXD_DIR}/unix.socket" -X POST -H "Content-Type: application/x-tar" --data-binary @- "lxd/1.0/instances/${inst}/files?path=/root/tree/a.conf&format=tar")" = "400" ]
+
+  echo "==> Entries escaping the target directory are rejected"
+  tar -C "${TEST_DIR}/upload/etc" -P --transform 's|^|../|' -cf "${TEST_DIR}/escape.tar" hello.conf
+  ! curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" -X POST -H "Content-Type: application/x-tar" --data-binary "@${TEST_DIR}/escape.tar" "lxd/1.0/instances/${inst}/files?path=/root/upload&format=tar" || false
+  ! lxc exec "${inst}" -- test -e /root/hello.conf || false
+
+  rm -rf "${TEST_DIR}/upload" "${TEST_DIR}/tree.tar" "${TEST_DIR}/escape.tar"
+  lxc delete "${inst}" --force
+}
@@ -384,3 +384,47 @@ run_images_public() {
   lxc project delete foo
   lxc image delete "${fingerprint}"
 }
+
+test_image_allowed_servers() {
+  local LXD2_DIR LXD2_ADDR
+  LXD2_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD2_DIR}" true
+  LXD2_ADDR=$(< "${LXD2_DIR}/lxd.addr")
+
+  ensure_import_testimage
+
+  token="$(LXD_DIR=${LXD2_DIR} lxc config trust add --name foo -q)"
+  lxc_remote remote add l2 "${LXD2_ADDR}" --token "${token}"
+  lxc image copy testimage l2: --alias testimage --public
+  lxc image delete testimage
+
+  ! lxc config set images.allowed_servers "not-a-url" || false
+
+  # Only the listed servers can be downloaded from.
+  lxc config set images.allowed_servers "https://images.example.com"
+  ! lxc image copy l2:testimage local: || false
+  ! lxc image import https://127.0.0.1:1/image.tar.xz || false
+  [ "$(lxc image list -f csv | wc -l)" = "0" ]
+
+  lxc config set images.allowed_servers "https://images.example.com,https://${LXD2_ADDR}/"
+  lxc image copy l2:testimage local: --alias testimage
+  lxc image delete testimage
+
+  # Projects can override the server list.
+  lxc project create foo -c features.images=true -c images.allowed_servers="https://images.example.com"
+  ! lxc image copy l2:testimage local: --target-project foo || false
+  [ "$(lxc image list -f csv --project foo | wc -l)" = "0" ]
+  lxc config unset images.allowed_servers
+  ! lxc image copy l2:testimage local: --target-project foo || false
+  lxc image copy l2:testimage local: --alias testimage
+
+  lxc project set foo images.allowed_servers "https://${LXD2_ADDR}"
+  lxc image copy l2:testimage local: --target-project foo
+  [ "$(lxc image list -f csv --project foo | wc -l)" = "1" ]
+
+  # Cleanup
+  lxc image delete "$(lxc image list -f csv -c f --project foo)" --project foo
+  lxc project delete foo
+  lxc remote rm l2
+  kill_lxd "${LXD2_DIR}"
+}
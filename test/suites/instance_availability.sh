@@ -0,0 +1,37 @@
+test_instance_availability() {
+  ensure_import_testimage
+
+  lxc init testimage c1
+
+  # Nothing is known about the instance before its first state change.
+  [ "$(lxc query /1.0/instances/c1/availability | jq -r '.changes | length')" = "0" ]
+  lxc query /1.0/instances/c1/availability | jq -e '.uptime == 0 and .downtime == 0 and .untracked >= 86399'
+
+  lxc start c1
+  lxc pause c1
+  lxc resume c1
+  lxc stop c1 --force
+
+  # The state changes are recorded in the background.
+  for _ in $(seq 10); do
+    [ "$(lxc query /1.0/instances/c1/availability | jq -r '[.changes[].action] | join(",")')" = "instance-stopped,instance-resumed,instance-paused,instance-started" ] && break
+    sleep 1
+  done
+
+  [ "$(lxc query /1.0/instances/c1/availability | jq -r '[.changes[].action] | join(",")')" = "instance-stopped,instance-resumed,instance-paused,instance-started" ]
+  [ "$(lxc query /1.0/instances/c1/availability | jq -r '[.changes[].status] | join(",")')" = "Stopped,Running,Frozen,Running" ]
+  [ "$(lxc query "/1.0/instances/c1/availability?changes=2" | jq -r '[.changes[].action] | join(",")')" = "instance-stopped,instance-resumed" ]
+  lxc query "/1.0/instances/c1/availability?period=1h" | jq -e '.untracked > 0 and .uptime_percentage + .downtime_percentage > 99.9'
+
+  # The availability is available for the instance type only.
+  lxc query /1.0/containers/c1/availability
+  ! lxc query /1.0/virtual-machines/c1/availability || false
+
+  # Invalid requests.
+  ! lxc query "/1.0/instances/c1/availability?period=foo" || false
+  ! lxc query "/1.0/instances/c1/availability?period=-1h" || false
+  ! lxc query "/1.0/instances/c1/availability?changes=-1" || false
+  ! lxc query /1.0/instances/missing/availability || false
+
+  lxc delete c1
+}
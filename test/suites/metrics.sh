@@ -163,3 +163,35 @@ test_metrics() {
   lxc project rm foo
   lxc project rm foo2
 }
+
+test_metrics_conntrack() {
+  ensure_import_testimage
+
+  netName="lxdt$$"
+  lxc network create "${netName}" ipv4.address=192.0.2.1/24 ipv4.nat=true ipv4.dhcp=false ipv6.address=none
+
+  lxc init testimage c1
+  lxc config device add c1 eth0 nic network="${netName}"
+  lxc start c1
+  lxc exec c1 -- ip link set eth0 up
+  lxc exec c1 -- ip addr add 192.0.2.2/24 dev eth0
+
+  echo "==> The NAT rules of the network enable the connection tracking"
+  lxc query /1.0/metrics | grep -E '^lxd_conntrack_entries [0-9]+$'
+  lxc query /1.0/metrics | grep -E '^lxd_conntrack_entries_limit [1-9][0-9]*$'
+  lxc query /1.0/metrics | grep -E '^lxd_conntrack_drops_total\{reason="drop"\} [0-9]+$'
+  lxc query /1.0/metrics | grep -E '^lxd_conntrack_drops_total\{reason="early_drop"\} [0-9]+$'
+  lxc query /1.0/metrics | grep -E '^lxd_conntrack_drops_total\{reason="insert_failed"\} [0-9]+$'
+
+  echo "==> Connections of the instances are counted on their network"
+  lxc exec c1 -- ping -c1 -W1 192.0.2.1
+  lxc query /1.0/metrics | grep -E "^lxd_network_conntrack_entries\{network=\"${netName}\",project=\"default\"\} [1-9][0-9]*$"
+  lxc query /1.0/metrics | grep -E "^lxd_network_nat_translations\{network=\"${netName}\",project=\"default\"\} [0-9]+$"
+
+  echo "==> Networks without addresses are skipped"
+  lxc network set "${netName}" ipv4.address=none
+  ! lxc query /1.0/metrics | grep -F "network=\"${netName}\"" || false
+
+  lxc delete -f c1
+  lxc network delete "${netName}"
+}
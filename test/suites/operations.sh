@@ -73,3 +73,22 @@ test_get_operations() {
     lxc delete c2 --force --project "${proj2}"
   )
 }
+
+test_operation_cancellation() {
+  wait_op() {
+    lxc query -X POST -d "{\\\"duration\\\": \\\"${1}\\\", \\\"op_class\\\": 1, \\\"op_type\\\": ${2}}" "/internal/testing/operation-wait" | jq -r '.id'
+  }
+
+  # Backups stop at their cancellation checkpoints.
+  op="$(wait_op 60s 3)"
+  [ "$(lxc query "/1.0/operations/${op}" | jq -r '.may_cancel')" = "true" ]
+  lxc operation delete "${op}"
+  [ "$(lxc query "/1.0/operations/${op}/wait?timeout=10" | jq -r '.status')" = "Cancelled" ]
+
+  # Instance starts can't be cancelled.
+  op="$(wait_op 5s 16)"
+  [ "$(lxc query "/1.0/operations/${op}" | jq -r '.may_cancel')" = "false" ]
+  ! lxc operation delete "${op}" || false
+  [ "$(lxc query "/1.0/operations/${op}" | jq -r '.status')" = "Running" ]
+  [ "$(lxc query "/1.0/operations/${op}/wait?timeout=10" | jq -r '.status')" = "Success" ]
+}
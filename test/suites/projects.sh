@@ -153,6 +153,166 @@ test_projects_containers() {
   lxc delete --force c1
 }
 
+# Disabling a project stops its instances and rejects changes in it.
+test_projects_disable() {
+  ensure_import_testimage
+
+  lxc project create disabled -c features.images=false -c features.profiles=false
+  lxc init testimage c1 -d "${SMALL_ROOT_DISK}" --project disabled
+  lxc start c1 --project disabled
+  [ "$(lxc query /1.0/projects/disabled | jq -r '.status')" = "active" ]
+
+  # The default project can't be disabled.
+  ! lxc project disable default || false
+  ! lxc query -X PUT -d '{\"action\": \"archive\"}' /1.0/projects/disabled/state || false
+
+  lxc project disable disabled --reason "Tenant offboarded"
+  [ "$(lxc query /1.0/projects/disabled | jq -r '.status')" = "disabled" ]
+  [ "$(lxc list -f csv -c s c1 --project disabled)" = "STOPPED" ]
+
+  # The change is recorded along with who made it and why.
+  lxc query /1.0/projects/disabled/state > "${TEST_DIR}/state.json"
+  [ "$(jq -r '.status' "${TEST_DIR}/state.json")" = "disabled" ]
+  [ "$(jq -r '.status_changes | length' "${TEST_DIR}/state.json")" = "1" ]
+  [ "$(jq -r '.status_changes[0].status' "${TEST_DIR}/state.json")" = "disabled" ]
+  [ "$(jq -r '.status_changes[0].reason' "${TEST_DIR}/state.json")" = "Tenant offboarded" ]
+  [ "$(jq -r '.status_changes[0].protocol' "${TEST_DIR}/state.json")" = "unix" ]
+
+  # Changes are rejected while reading is still allowed.
+  ! lxc start c1 --project disabled || false
+  ! lxc config set c1 user.foo=bar --project disabled || false
+  ! lxc init testimage c2 --project disabled || false
+  ! lxc project set disabled user.foo bar || false
+  lxc config show c1 --project disabled
+  lxc list --project disabled | grep -F c1
+
+  # Disabling the project again doesn't record another change.
+  lxc project disable disabled
+  [ "$(lxc query /1.0/projects/disabled/state | jq -r '.status_changes | length')" = "1" ]
+
+  lxc project enable disabled --reason "Tenant back"
+  lxc query /1.0/projects/disabled/state > "${TEST_DIR}/state.json"
+  [ "$(jq -r '.status' "${TEST_DIR}/state.json")" = "active" ]
+  [ "$(jq -r '.status_changes | length' "${TEST_DIR}/state.json")" = "2" ]
+  [ "$(jq -r '.status_changes[0].status' "${TEST_DIR}/state.json")" = "active" ]
+  [ "$(jq -r '.status_changes[0].reason' "${TEST_DIR}/state.json")" = "Tenant back" ]
+  rm "${TEST_DIR}/state.json"
+
+  # The instances were kept and can be used again.
+  lxc start c1 --project disabled
+  [ "$(lxc list -f csv -c s c1 --project disabled)" = "RUNNING" ]
+  lxc delete --force c1 --project disabled
+
+  # Disabled projects can still be deleted.
+  lxc project disable disabled
+  lxc project delete disabled
+}
+
+# Sharing entities with other projects.
+test_projects_shares() {
+  ensure_import_testimage
+  pool="$(lxc profile device get default root pool)"
+
+  lxc project create p1 -c features.images=true
+  lxc project create p2 -c features.images=true -c features.profiles=false
+  lxc project create p3 -c features.images=true -c features.profiles=false
+  lxc image copy testimage local: --target-project p1 --alias testimage
+  lxc storage volume create "${pool}" data --project p1
+
+  # Entities can only be shared with other existing projects, with a known access.
+  ! lxc project share add p1 storage_volume data p1 pool="${pool}" || false
+  ! lxc project share add p1 storage_volume data missing pool="${pool}" || false
+  ! lxc project share add p1 storage_volume data p2 pool="${pool}" --access=write || false
+  ! lxc project share add p1 profile default p2 || false
+
+  lxc project share add p1 storage_volume data p2 pool="${pool}" --access=attach
+  lxc project share add p1 storage_volume data p3 pool="${pool}"
+  lxc project share add p1 image testimage p2 --access=attach
+  lxc project share add p1 image testimage p3 --access=attach
+
+  # Adding a share again replaces its access.
+  lxc project share add p1 image testimage p3
+
+  [ "$(lxc query /1.0/projects/p1/shares | jq -r 'length')" = "4" ]
+  [ "$(lxc query "/1.0/projects/p2/shares?received=true" | jq -r '[.[].access] | join(",")')" = "attach,attach" ]
+  [ "$(lxc query "/1.0/projects/p3/shares?received=true" | jq -r '[.[].access] | join(",")')" = "read-only,read-only" ]
+  lxc project share list p1
+
+  # The projects that an entity is shared with use it, and it can't be deleted while shared.
+  lxc query "/1.0/storage-pools/${pool}/volumes/custom/data?project=p1" | jq -e '.used_by | index("/1.0/projects/p2") != null'
+  ! lxc storage volume delete "${pool}" data --project p1 || false
+
+  # Instances can be created from images shared for attaching only.
+  lxc query --wait -X POST -d '{\"name\": \"c1\", \"source\": {\"type\": \"image\", \"alias\": \"testimage\", \"project\": \"p1\"}}' "/1.0/instances?project=p2"
+  ! lxc query --wait -X POST -d '{\"name\": \"c3\", \"source\": {\"type\": \"image\", \"alias\": \"testimage\", \"project\": \"p1\"}}' "/1.0/instances?project=p3" || false
+  lxc init --empty c3 --project p3
+
+  # Volumes can be attached when shared for attaching only.
+  lxc config device add c1 data disk pool="${pool}" source=data source.project=p1 path=/mnt --project p2
+  ! lxc config device add c3 data disk pool="${pool}" source=data source.project=p1 path=/mnt --project p3 || false
+  ! lxc config device add c3 data disk pool="${pool}" source=data source.project=p1 path=/ --project p3 || false
+  lxc config device remove c1 data --project p2
+
+  # Shares can be removed, and are removed along with the projects they are granted to.
+  lxc project share remove p1 storage_volume data p3 pool="${pool}"
+  [ "$(lxc query /1.0/projects/p1/shares | jq -r 'length')" = "3" ]
+  lxc delete c3 --project p3
+  lxc project delete p3
+  [ "$(lxc query /1.0/projects/p1/shares | jq -r 'length')" = "2" ]
+
+  lxc project share remove p1 storage_volume data p2 pool="${pool}"
+  lxc storage volume delete "${pool}" data --project p1
+  [ "$(lxc query /1.0/projects/p1/shares | jq -r '.[].entity_type')" = "image" ]
+
+  # Cleanup
+  lxc delete c1 --project p2
+  lxc project delete p2
+  lxc image delete testimage --project p1
+  lxc project delete p1
+}
+
+# Routing the notifications of projects to their own webhooks.
+test_projects_notifications() {
+  lxc project create notify -c features.profiles=false
+
+  # Invalid targets and notification types are rejected.
+  ! lxc project set notify notifications.webhook.url=not-a-url || false
+  ! lxc project set notify notifications.email.to=not-an-email || false
+  ! lxc project set notify notifications.types=lifecycle,bogus || false
+  ! lxc config set smtp.sender=not-an-email || false
+  lxc config set smtp.address=127.0.0.1:25 smtp.sender=lxd@example.com
+  lxc project set notify notifications.email.to=tenant@example.com
+  lxc project unset notify notifications.email.to
+  lxc config unset smtp.address
+  lxc config unset smtp.sender
+
+  port="$(local_tcp_port)"
+  nc -l 127.0.0.1 "${port}" < /dev/null > "${TEST_DIR}/notifications.log" &
+  nc_pid=$!
+
+  lxc project set notify notifications.webhook.url="http://127.0.0.1:${port}" notifications.types=lifecycle
+
+  # Only the events of the project are sent to its webhooks.
+  lxc init --empty c-default
+  lxc init --empty c-notify --project notify
+  for _ in $(seq 20); do
+    grep -F c-notify "${TEST_DIR}/notifications.log" && break
+    sleep 0.5
+  done
+
+  kill "${nc_pid}" || true
+  grep -F "POST / HTTP/1.1" "${TEST_DIR}/notifications.log"
+  grep -F '"project":"notify"' "${TEST_DIR}/notifications.log"
+  grep -F "instance-created" "${TEST_DIR}/notifications.log"
+  ! grep -F c-default "${TEST_DIR}/notifications.log" || false
+  rm "${TEST_DIR}/notifications.log"
+
+  # Cleanup
+  lxc delete c-default
+  lxc delete c-notify --project notify
+  lxc project delete notify
+}
+
 # Copy/move between projects
 test_projects_copy() {
   ensure_import_testimage
@@ -1236,6 +1396,66 @@ test_projects_usage() {
   lxc project delete test-usage
 }
 
+test_projects_usage_accounting() {
+  lxc project create test-accounting
+  lxc config set core.projects_usage_retention=30
+  ! lxc config set core.projects_usage_retention=-1 || false
+
+  # No usage has been recorded yet.
+  [ "$(lxc query /1.0/projects/test-accounting/usage)" = "[]" ]
+  [ "$(lxc query "/1.0/projects/test-accounting/usage?period=month&since=2026-01-01T00:00:00Z&until=2026-02-01T00:00:00Z")" = "[]" ]
+
+  # The usage can be exported as CSV.
+  [ "$(curl --silent --unix-socket "${LXD_DIR}/unix.socket" "lxd/1.0/projects/test-accounting/usage?format=csv")" = "start,end,api_calls,instance_hours,storage_gb_hours,network_bytes" ]
+
+  # Invalid requests.
+  ! lxc query "/1.0/projects/test-accounting/usage?period=week" || false
+  ! lxc query "/1.0/projects/test-accounting/usage?format=xml" || false
+  ! lxc query "/1.0/projects/test-accounting/usage?since=yesterday" || false
+  ! lxc query "/1.0/projects/test-accounting/usage?since=2026-02-01T00:00:00Z&until=2026-01-01T00:00:00Z" || false
+
+  lxc config unset core.projects_usage_retention
+  lxc project delete test-accounting
+}
+
+test_projects_operation_limits() {
+  lxc project create oplimits -c limits.operations.concurrent=1 -c limits.operations.queued=1
+  ! lxc project set oplimits limits.operations.concurrent=-1 || false
+
+  wait_op() {
+    lxc query -X POST -d "{\\\"duration\\\": \\\"${1}\\\", \\\"op_class\\\": 1, \\\"op_type\\\": 48}" "/internal/testing/operation-wait?project=${2}" | jq -r '.id'
+  }
+
+  # The first operation runs straight away while the second one waits for it.
+  op1="$(wait_op 5s oplimits)"
+  op2="$(wait_op 1s oplimits)"
+  [ "$(lxc query "/1.0/operations/${op1}" | jq -r '.status')" = "Running" ]
+  [ "$(lxc query "/1.0/operations/${op2}" | jq -r '.status')" = "Pending" ]
+
+  # Operations beyond the queue limit are rejected.
+  ! wait_op 1s oplimits || false
+
+  # Other projects aren't affected.
+  op3="$(wait_op 1s default)"
+  [ "$(lxc query "/1.0/operations/${op3}" | jq -r '.status')" = "Running" ]
+
+  # The waiting operation runs once the running one completes.
+  [ "$(lxc query "/1.0/operations/${op1}/wait" | jq -r '.status')" = "Success" ]
+  [ "$(lxc query "/1.0/operations/${op2}/wait" | jq -r '.status')" = "Success" ]
+
+  # Waiting operations can be cancelled.
+  op1="$(wait_op 5s oplimits)"
+  op2="$(wait_op 1s oplimits)"
+  lxc query -X DELETE "/1.0/operations/${op2}"
+  [ "$(lxc query "/1.0/operations/${op2}" | jq -r '.status')" = "Cancelled" ]
+  op3="$(wait_op 1s oplimits)"
+  [ "$(lxc query "/1.0/operations/${op3}" | jq -r '.status')" = "Pending" ]
+  [ "$(lxc query "/1.0/operations/${op3}/wait" | jq -r '.status')" = "Success" ]
+  [ "$(lxc query "/1.0/operations/${op1}" | jq -r '.status')" = "Success" ]
+
+  lxc project delete oplimits
+}
+
 test_projects_yaml() {
   lxc project create test-project-yaml <<EOF
 config:
@@ -1263,6 +1483,51 @@ EOF
   lxc project delete test-project-yaml
 }
 
+# Export a project configuration and re-create it under another name.
+test_projects_export() {
+  lxc project create foo -c features.profiles=true -c features.networks=true -c limits.containers=5 -c user.foo=bar
+  lxc profile create p1 --project foo
+  lxc profile set p1 limits.cpu=2 --project foo
+  lxc profile set default user.foo=bar --project foo
+  lxc network acl create acl1 --project foo
+  lxc network acl create acl2 --project foo
+  lxc network acl rule add acl2 ingress action=allow source=acl1 --project foo
+
+  # Exporting a missing project fails.
+  ! lxc project export missing "${TEST_DIR}/project-missing.tar.gz" || false
+  [ ! -e "${TEST_DIR}/project-missing.tar.gz" ]
+
+  # The archive holds the configuration of the project and of its entities.
+  lxc project export foo "${TEST_DIR}/project-foo.tar.gz"
+  tar -xzf "${TEST_DIR}/project-foo.tar.gz" -O index.yaml > "${TEST_DIR}/index.yaml"
+  grep -xF '  name: foo' "${TEST_DIR}/index.yaml"
+  grep -xF '    limits.containers: "5"' "${TEST_DIR}/index.yaml"
+  grep -xF '  name: p1' "${TEST_DIR}/index.yaml"
+  grep -xF -- '- name: acl2' "${TEST_DIR}/index.yaml"
+  rm "${TEST_DIR}/index.yaml"
+
+  # Importing under an existing name fails.
+  ! lxc project import "${TEST_DIR}/project-foo.tar.gz" || false
+
+  # The project is re-created along with its profiles and ACLs.
+  lxc project import "${TEST_DIR}/project-foo.tar.gz" bar
+  [ "$(lxc project get bar limits.containers)" = "5" ]
+  [ "$(lxc project get bar user.foo)" = "bar" ]
+  [ "$(lxc profile get p1 limits.cpu --project bar)" = "2" ]
+  [ "$(lxc profile get default user.foo --project bar)" = "bar" ]
+  lxc network acl show acl1 --project bar
+  lxc network acl show acl2 --project bar | grep -xF '  source: acl1'
+
+  # Cleanup.
+  rm "${TEST_DIR}/project-foo.tar.gz"
+  for project in foo bar; do
+    lxc network acl delete acl2 --project "${project}"
+    lxc network acl delete acl1 --project "${project}"
+    lxc profile delete p1 --project "${project}"
+    lxc project delete "${project}"
+  done
+}
+
 # Test project operations with an uninitialized LXD.
 test_projects_before_init() {
   LXD_INIT_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
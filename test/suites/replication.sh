@@ -0,0 +1,67 @@
+test_instance_replication() {
+  # setup a standby LXD
+  local LXD2_DIR LXD2_ADDR
+  LXD2_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  spawn_lxd "${LXD2_DIR}" true
+  LXD2_ADDR=$(< "${LXD2_DIR}/lxd.addr")
+
+  ensure_import_testimage
+
+  # Invalid replication configuration is rejected.
+  ! lxc config set replication.target.address=foo:bar:baz || false
+  ! lxc config set replication.target.certificate=foo || false
+  ! lxc init testimage c1 -c replication.schedule=invalid || false
+
+  # The standby trusts the certificate of the primary.
+  LXD_DIR="${LXD2_DIR}" lxc config trust add-certificate "${LXD_DIR}/server.crt"
+  lxc config set replication.target.address="${LXD2_ADDR}" replication.target.certificate="$(cat "${LXD2_DIR}/server.crt")"
+
+  lxc init testimage c1 -c replication.schedule="* * * * *" -c user.foo=bar
+  lxc init testimage c2
+  [ "$(lxc query /1.0/replication | jq -r '.[] | "\(.name),\(.role),\(.lag)"')" = "c1,primary,-1" ]
+
+  # Wait for the scheduled replication.
+  for _ in $(seq 150); do
+    [ -n "$(lxc config get c1 volatile.replication.last_sync)" ] && break
+    sleep 1
+  done
+
+  [ "$(lxc config get c1 volatile.replication.last_error)" = "" ]
+  [ "$(lxc query /1.0/replication | jq -r '.[] | "\(.name),\(.role),\(.error)"')" = "c1,primary," ]
+  [ "$(lxc query /1.0/replication | jq -r '.[0].lag')" -ge 0 ]
+
+  # The standby received the replica only, without the replication schedule.
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc list -f csv -c n)" = "c1" ]
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc config get c1 volatile.replication.standby)" = "true" ]
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc config get c1 user.foo)" = "bar" ]
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc config get c1 replication.schedule)" = "" ]
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc query /1.0/replication | jq -r '.[] | "\(.name),\(.role),\(.schedule)"')" = "c1,standby," ]
+
+  # Replicas can't be started until promoted.
+  ! LXD_DIR="${LXD2_DIR}" lxc start c1 || false
+
+  # Invalid promotion requests are rejected.
+  ! LXD_DIR="${LXD2_DIR}" lxc query -X POST -d '{\"instances\": [\"c1\"]}' /1.0/replication/promote || false
+
+  # Promote the replica and start it.
+  LXD_DIR="${LXD2_DIR}" lxc query --wait -X POST -d '{\"instances\": [\"default/c1\"], \"start\": true}' /1.0/replication/promote
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc config get c1 volatile.replication.standby)" = "" ]
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc list -f csv -c ns c1)" = "c1,RUNNING" ]
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc query /1.0/replication | jq 'length')" = "0" ]
+
+  # Promoted instances are never overwritten by the replication.
+  for _ in $(seq 150); do
+    [ -n "$(lxc config get c1 volatile.replication.last_error)" ] && break
+    sleep 1
+  done
+
+  lxc config get c1 volatile.replication.last_error | grep -F "isn't a replica"
+  [ "$(LXD_DIR="${LXD2_DIR}" lxc list -f csv -c ns c1)" = "c1,RUNNING" ]
+
+  # Clean up
+  lxc delete c1 c2
+  lxc config unset replication.target.address
+  lxc config unset replication.target.certificate
+  LXD_DIR="${LXD2_DIR}" lxc delete -f c1
+  kill_lxd "${LXD2_DIR}"
+}
@@ -8,6 +8,10 @@ test_server_config() {
   _server_config_auth_secret
   _server_config_cluster_uuid
   _server_config_user_microcloud
+  _server_config_tracing
+  _server_config_health
+  _server_config_debug
+  _server_config_request_size
 
   kill_lxd "${LXD_SERVERCONFIG_DIR}"
 }
@@ -202,3 +206,106 @@ _server_config_user_microcloud() {
   [ "$(curl "https://${LXD_ADDR}/1.0" --insecure | jq '.metadata.config["user.microcloud"]')" = 'null' ]
   [ "$(curl "https://${LXD_ADDR}/1.0" --insecure | jq '.metadata.config["user.foo"]')" = 'null' ]
 }
+
+_server_config_tracing() {
+  # Invalid tracing configuration is rejected.
+  ! lxc config set core.tracing.endpoint=foo:bar:baz || false
+  ! lxc config set core.tracing.sampling=101 || false
+  ! lxc config set core.tracing.sampling=-1 || false
+
+  # An unreachable collector doesn't prevent LXD from handling requests.
+  lxc config set core.tracing.endpoint=127.0.0.1:4317 core.tracing.insecure=true core.tracing.sampling=50
+  lxc info | grep -F 'core.tracing.endpoint: 127.0.0.1:4317'
+
+  # The trace of the caller is continued.
+  op="$(curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" -H "traceparent: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" -X POST --data '{"name": "c1", "source": {"type": "none"}}' "lxd/1.0/instances")"
+  [ "$(echo "${op}" | jq -r '.metadata.requestor.trace_id')" = "4bf92f3577b34da6a3ce929d0e0e4736" ]
+  curl --silent --fail --unix-socket "${LXD_DIR}/unix.socket" "lxd$(echo "${op}" | jq -r '.operation')/wait"
+  lxc delete c1
+
+  lxc config unset core.tracing.endpoint
+  lxc config unset core.tracing.insecure
+  lxc config unset core.tracing.sampling
+}
+
+_server_config_health() {
+  # Untrusted clients only get the overall status.
+  [ "$(curl --silent --insecure "https://${LXD_ADDR}/1.0/health" | jq -r '.metadata | "\(.status),\(.checks)"')" = "ok,null" ]
+
+  # Trusted clients get the status of each subsystem.
+  [ "$(lxc query /1.0/health | jq -r '.status')" = "ok" ]
+  [ "$(lxc query /1.0/health | jq -r '[.checks[] | "\(.name)=\(.status)"] | join(",")')" = "database=ok,storage=ok,network=ok" ]
+  lxc query /1.0/health | jq -r '.checks[] | select(.name == "database") | .message' | grep -xF "Database available"
+}
+
+_server_config_debug() {
+  # The diagnostics are restricted to administrators.
+  [ "$(curl --silent --insecure "https://${LXD_ADDR}/1.0/debug/runtime" | jq -r '.error_code')" = "403" ]
+  [ "$(curl --silent --insecure "https://${LXD_ADDR}/1.0/debug/pprof/heap" | jq -r '.error_code')" = "403" ]
+
+  # Runtime information and memory allocation statistics.
+  lxc query /1.0/debug/runtime | jq -e '.goroutines > 0 and .gomaxprocs > 0 and .memory.alloc > 0 and .memory.sys > 0'
+  lxc query /1.0/debug/runtime | jq -r '.go_version' | grep -q '^go[0-9]'
+
+  # Available profiles.
+  lxc query /1.0/debug/pprof | jq -r '.[]' | grep -xF /1.0/debug/pprof/cpu
+  lxc query /1.0/debug/pprof | jq -r '.[]' | grep -xF /1.0/debug/pprof/goroutine
+  lxc query /1.0/debug/pprof | jq -r '.[]' | grep -xF /1.0/debug/pprof/heap
+
+  # Profiles are returned in the pprof format (gzip compressed) unless a text format is requested.
+  curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output "${TEST_DIR}/heap.pprof" "lxd/1.0/debug/pprof/heap"
+  gzip --test "${TEST_DIR}/heap.pprof"
+  curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output "${TEST_DIR}/cpu.pprof" "lxd/1.0/debug/pprof/cpu?seconds=1"
+  gzip --test "${TEST_DIR}/cpu.pprof"
+  curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output "${TEST_DIR}/goroutine.txt" "lxd/1.0/debug/pprof/goroutine?debug=2"
+  grep -q '^goroutine [0-9]\+ \[' "${TEST_DIR}/goroutine.txt"
+  rm "${TEST_DIR}/heap.pprof" "${TEST_DIR}/cpu.pprof" "${TEST_DIR}/goroutine.txt"
+
+  # Invalid requests.
+  [ "$(curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output /dev/null --write-out "%{http_code}" "lxd/1.0/debug/pprof/foo")" = "404" ]
+  [ "$(curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output /dev/null --write-out "%{http_code}" "lxd/1.0/debug/pprof/cpu?seconds=0")" = "400" ]
+  [ "$(curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output /dev/null --write-out "%{http_code}" "lxd/1.0/debug/pprof/cpu?debug=1")" = "400" ]
+
+  # Profiles can be written to the log directory.
+  path="$(lxc query -X POST "/1.0/debug/pprof/goroutine?debug=1" | jq -r '.path')"
+  [ "$(dirname "${path}")" = "${LXD_DIR}/logs" ]
+  grep -q '^goroutine profile: total [0-9]\+$' "${path}"
+  rm "${path}"
+}
+
+_server_config_request_size() {
+  ! lxc config set core.max_request_size=foo || false
+  ! lxc config set core.request_size_overrides=images || false
+  ! lxc config set core.request_size_overrides==1MiB || false
+
+  # Requests whose size is known to exceed the limit are rejected upfront.
+  too_large() {
+    [ "$(curl --silent --unix-socket "${LXD_DIR}/unix.socket" --output /dev/null --write-out "%{http_code}" -X POST -H "Content-Type: ${1}" --data-binary @"${2}" "lxd/1.0/images")" = "413" ]
+  }
+
+  printf '{"properties":{"description":"%s"}}' "$(head -c 8192 /dev/zero | tr '\0' 'x')" > "${TEST_DIR}/large.json"
+  head -c 8192 /dev/zero > "${TEST_DIR}/large.bin"
+
+  previous="$(lxc query /1.0/metrics | awk '/^lxd_api_requests_too_large_total\{entity_type="image"\}/ {print $2}')"
+
+  lxc config set core.max_request_size=4KiB
+  too_large application/json "${TEST_DIR}/large.json"
+  [ "$(lxc query /1.0/metrics | awk '/^lxd_api_requests_too_large_total\{entity_type="image"\}/ {print $2}')" -eq $((previous+1)) ]
+
+  # Uploads have their own limit.
+  ! too_large application/octet-stream "${TEST_DIR}/large.bin" || false
+  lxc config set core.max_upload_size=4KiB
+  too_large application/octet-stream "${TEST_DIR}/large.bin"
+
+  # Overrides apply to the endpoints below the given path.
+  lxc config set core.request_size_overrides=images/aliases=1MiB
+  too_large application/json "${TEST_DIR}/large.json"
+  lxc config set core.request_size_overrides=images=1MiB
+  ! too_large application/json "${TEST_DIR}/large.json" || false
+  ! too_large application/octet-stream "${TEST_DIR}/large.bin" || false
+
+  lxc config unset core.request_size_overrides
+  lxc config unset core.max_upload_size
+  lxc config unset core.max_request_size
+  rm "${TEST_DIR}/large.json" "${TEST_DIR}/large.bin"
+}
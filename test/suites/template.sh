@@ -100,3 +100,60 @@ test_template() {
   lxc image delete template-test
   lxc delete template template1 --force
 }
+
+test_instance_templates() {
+  ensure_import_testimage
+
+  echo "Create an instance template"
+  lxc template create web << EOF
+description: Web server
+parameters:
+  memory:
+    default: 256MiB
+  image:
+    required: true
+type: container
+source:
+  type: image
+  alias: "{{ image }}"
+profiles:
+- default
+config:
+  limits.memory: "{{ memory }}"
+  user.fqdn: "{{ name }}.example.com"
+EOF
+  lxc template list | grep -wF web
+  lxc template show web | grep -xF "  limits.memory: '{{ memory }}'"
+
+  echo "Reject invalid templates"
+  ! lxc query -X POST -d '{\"name\": \"bad\", \"parameters\": {\"name\": {}}}' /1.0/instance-templates || false
+  ! lxc query -X POST -d '{\"name\": \"bad\", \"parameters\": {\"image\": {\"required\": true, \"default\": \"testimage\"}}}' /1.0/instance-templates || false
+  ! lxc query -X POST -d '{\"name\": \"web\"}' /1.0/instance-templates || false
+
+  echo "Reject missing and unknown parameters"
+  ! lxc template launch web c1 --no-start || false
+  ! lxc template launch web c1 --no-start image=testimage foo=bar || false
+  ! lxc info c1 || false
+
+  echo "Create an instance from the template"
+  lxc template launch web c1 --no-start image=testimage
+  [ "$(lxc config get c1 limits.memory)" = "256MiB" ]
+  [ "$(lxc config get c1 user.fqdn)" = "c1.example.com" ]
+  [ "$(lxc list -c s -f csv c1)" = "STOPPED" ]
+
+  lxc template launch web c2 --no-start image=testimage memory=512MiB
+  [ "$(lxc config get c2 limits.memory)" = "512MiB" ]
+
+  echo "Reject an instance name already in use"
+  ! lxc template launch web c2 --no-start image=testimage || false
+
+  echo "Rename the template"
+  lxc template rename web web2
+  ! lxc template show web || false
+  lxc template show web2
+
+  # Cleanup
+  lxc delete c1 c2
+  lxc template delete web2
+  ! lxc template list | grep -wF web2 || false
+}
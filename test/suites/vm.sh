@@ -60,3 +60,101 @@ test_vm_empty() {
   ! lxc start v1 || false
   lxc delete v1
 }
+
+test_vm_rescue() {
+  if [ "${LXD_VM_TESTS:-0}" = "0" ]; then
+    echo "==> SKIP: VM tests are disabled"
+    return
+  fi
+
+  if [ "${LXD_TMPFS:-0}" = "1" ] && ! runsMinimumKernel 6.6; then
+    echo "==> SKIP: QEMU requires direct-io support which requires a kernel >= 6.6 for tmpfs support (LXD_TMPFS=${LXD_TMPFS})"
+    return
+  fi
+
+  local pool
+  pool="$(lxc profile device get default root pool)"
+
+  lxc storage volume create "${pool}" rescue --type=block size=8MiB
+  lxc init --vm --empty v1 -c limits.memory=128MiB -d "${SMALL_ROOT_DISK}"
+  lxc init --empty c1
+
+  echo "==> Invalid rescue requests"
+  ! lxc query -X POST -d '{}' /1.0/instances/v1/rescue || false
+  ! lxc query -X POST -d '{\"pool\": \"'"${pool}"'\", \"volume\": \"rescue\"}' /1.0/instances/c1/rescue || false
+  ! lxc query -X DELETE /1.0/instances/v1/rescue || false
+
+  echo "==> Rescue a running VM"
+  lxc start v1
+  lxc query --wait -X POST -d '{\"pool\": \"'"${pool}"'\", \"volume\": \"rescue\"}' /1.0/instances/v1/rescue
+  [ "$(lxc list -f csv -c s v1)" = "RUNNING" ]
+  [ "$(lxc config get v1 volatile.rescue.device)" = "rescue" ]
+  [ "$(lxc config device get v1 rescue source)" = "rescue" ]
+  [ "$(lxc config device get v1 rescue boot.priority)" = "1" ]
+  ! lxc query --wait -X POST -d '{\"pool\": \"'"${pool}"'\", \"volume\": \"rescue\"}' /1.0/instances/v1/rescue || false
+
+  echo "==> Leave rescue mode"
+  lxc query --wait -X DELETE /1.0/instances/v1/rescue
+  [ "$(lxc list -f csv -c s v1)" = "RUNNING" ]
+  [ "$(lxc config get v1 volatile.rescue.device)" = "" ]
+  ! lxc config device get v1 rescue source || false
+
+  echo "==> Leave rescue mode of a stopped VM"
+  lxc query --wait -X POST -d '{\"pool\": \"'"${pool}"'\", \"volume\": \"rescue\"}' /1.0/instances/v1/rescue
+  lxc stop -f v1
+  lxc query --wait -X DELETE /1.0/instances/v1/rescue
+  [ "$(lxc list -f csv -c s v1)" = "STOPPED" ]
+  ! lxc config device get v1 rescue source || false
+
+  lxc delete v1 c1
+  lxc storage volume delete "${pool}" rescue
+}
+
+test_vm_windows_setup() {
+  if [ "${LXD_VM_TESTS:-0}" = "0" ]; then
+    echo "==> SKIP: VM tests are disabled"
+    return
+  fi
+
+  local pool
+  pool="$(lxc profile device get default root pool)"
+
+  dd if=/dev/urandom of="${TEST_DIR}/virtio-win.iso" bs=1M count=1
+  lxc storage volume import "${pool}" "${TEST_DIR}/virtio-win.iso" virtio-win
+  rm "${TEST_DIR}/virtio-win.iso"
+
+  lxc init --vm --empty v1 -c limits.memory=128MiB -d "${SMALL_ROOT_DISK}"
+  lxc init --empty c1
+
+  echo "==> Invalid setup requests"
+  ! lxc query -X POST -d '{\"drivers_pool\": \"'"${pool}"'\"}' /1.0/instances/v1/windows-setup || false
+  ! lxc query -X POST -d '{\"drivers_volume\": \"virtio-win\"}' /1.0/instances/v1/windows-setup || false
+  ! lxc query -X POST -d '{}' /1.0/instances/c1/windows-setup || false
+  ! lxc query -X DELETE /1.0/instances/v1/windows-setup || false
+
+  echo "==> Attach the setup media"
+  lxc config device add v1 windows-setup disk source=cloud-init:config
+  lxc query -X POST -d '{\"drivers_pool\": \"'"${pool}"'\", \"drivers_volume\": \"virtio-win\", \"unattend\": \"<unattend/>\"}' /1.0/instances/v1/windows-setup
+  [ "$(lxc config get v1 volatile.windows_setup.devices)" = "windows-setup1,windows-drivers" ]
+  [ "$(lxc config get v1 windows.unattend)" = "<unattend/>" ]
+  [ "$(lxc config device get v1 windows-setup1 source)" = "windows:setup" ]
+  [ "$(lxc config device get v1 windows-drivers pool)" = "${pool}" ]
+  [ "$(lxc config device get v1 windows-drivers source)" = "virtio-win" ]
+  [ "$(lxc config device get v1 windows-setup source)" = "cloud-init:config" ]
+  ! lxc query -X POST -d '{}' /1.0/instances/v1/windows-setup || false
+
+  echo "==> Detach the setup media"
+  lxc query -X DELETE /1.0/instances/v1/windows-setup
+  [ "$(lxc config get v1 volatile.windows_setup.devices)" = "" ]
+  [ "$(lxc config get v1 windows.unattend)" = "<unattend/>" ]
+  ! lxc config device get v1 windows-setup1 source || false
+  ! lxc config device get v1 windows-drivers source || false
+  [ "$(lxc config device get v1 windows-setup source)" = "cloud-init:config" ]
+
+  echo "==> The setup drive is only supported by VMs"
+  lxc config device add c1 windows-setup disk source=windows:setup
+  ! lxc start c1 || false
+
+  lxc delete v1 c1
+  lxc storage volume delete "${pool}" virtio-win
+}
@@ -62,3 +62,70 @@ test_warnings() {
     lxc warning delete --all
     [ -z "$(lxc warning ls --format csv)" ]
 }
+
+test_warnings_acknowledgement() {
+    lxc warning delete --all
+
+    lxc query --wait -X POST -d '{\"type_code\": 0, \"message\": \"global warning\"}' /internal/testing/warnings
+    uuid=$(lxc warning list --format json | jq -r '.[] | select(.last_message=="global warning") | .uuid')
+
+    # Unresolved warnings are exported as gauges.
+    lxc query /1.0/metrics | grep -xF 'lxd_warnings{severity="low",status="new",type="Undefined warning"} 1'
+    lxc query /1.0/metrics | grep -xF 'lxd_warnings_total 1'
+
+    # A comment can only be given when acknowledging a warning.
+    ! lxc query -X PATCH -d '{\"status\": \"new\", \"comment\": \"foo\"}' "/1.0/warnings/${uuid}" || false
+
+    # The acknowledgement is recorded with the identity and the comment.
+    lxc warning ack "${uuid}" --comment "Disk replacement scheduled"
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.status')" = "acknowledged" ]
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.acknowledged_comment')" = "Disk replacement scheduled" ]
+    [ -n "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.acknowledged_by')" ]
+
+    # Acknowledged warnings are still exported, but not counted as active.
+    lxc query /1.0/metrics | grep -xF 'lxd_warnings{severity="low",status="acknowledged",type="Undefined warning"} 1'
+    lxc query /1.0/metrics | grep -xF 'lxd_warnings_total 0'
+
+    # The acknowledgement is dropped once the warning reoccurs after being resolved.
+    lxc query -X PATCH -d '{\"status\": \"resolved\"}' "/1.0/warnings/${uuid}"
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.acknowledged_comment')" = "Disk replacement scheduled" ]
+    ! lxc query /1.0/metrics | grep '^lxd_warnings{' || false
+
+    lxc query --wait -X POST -d '{\"type_code\": 0, \"message\": \"global warning\"}' /internal/testing/warnings
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.status')" = "new" ]
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.acknowledged_comment')" = "" ]
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.acknowledged_by')" = "" ]
+
+    lxc warning delete --all
+}
+
+test_warnings_auto_resolve() {
+    lxc warning delete --all
+
+    pool="lxdtest-$(basename "${LXD_DIR}")-alerts"
+    lxc storage create "${pool}" dir
+
+    # A warning is raised while the storage pool usage is above the alerting threshold.
+    lxc config set alerts.storage_pool_usage=1
+    for _ in $(seq 90); do
+        lxc warning list --format json | jq -e --arg url "/1.0/storage-pools/${pool}" '.[] | select(.type == "Storage pool almost full" and .entity_url == $url)' && break
+        sleep 1
+    done
+
+    uuid="$(lxc warning list --format json | jq -r --arg url "/1.0/storage-pools/${pool}" '.[] | select(.type == "Storage pool almost full" and .entity_url == $url) | .uuid')"
+    [ -n "${uuid}" ]
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.severity')" = "moderate" ]
+
+    # The warning resolves itself once the usage is back below the threshold.
+    lxc config set alerts.storage_pool_usage=100
+    for _ in $(seq 90); do
+        [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.status')" = "resolved" ] && break
+        sleep 1
+    done
+
+    [ "$(lxc query "/1.0/warnings/${uuid}" | jq -r '.status')" = "resolved" ]
+
+    lxc config unset alerts.storage_pool_usage
+    lxc storage delete "${pool}"
+    lxc warning delete --all
+}